@@ -33,7 +33,8 @@ func (b *BridgeService) Startup(ctx context.Context) {
 
 // Shutdown is called when the app terminates
 func (b *BridgeService) Shutdown(ctx context.Context) {
-	// Cleanup operations if needed
+	// A recording or stream left running would never be finished
+	b.mcpServer.StopCaptures()
 }
 
 // OpenFileDialog opens a native file picker dialog for selecting video/audio files
@@ -0,0 +1,285 @@
+// Package apiclient retries, paces and budgets calls to hosted AI APIs
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Providers with their own rate limit and budget
+const (
+	ProviderOpenAI     = "openai"     // Whisper, chat, TTS and image generation
+	ProviderVision     = "vision"     // GPT-4 Vision frame analysis
+	ProviderElevenLabs = "elevenlabs" // Voice cloning and speech
+	ProviderStability  = "stability"  // Stable Diffusion image generation
+	ProviderPexels     = "pexels"     // Stock photo and video search
+	ProviderPixabay    = "pixabay"    // Stock photo and video search
+	ProviderYouTube    = "youtube"    // YouTube uploads and OAuth
+)
+
+// ErrBudgetExceeded is returned once a provider has used its request budget
+var ErrBudgetExceeded = errors.New("API request budget exceeded")
+
+// Policy controls retries, pacing and budgets for API calls
+type Policy struct {
+	MaxRetries int                // Retries after a 429, 5xx or network error
+	BaseDelay  time.Duration      // First backoff, doubled per retry
+	MaxDelay   time.Duration      // Longest single wait, including Retry-After
+	RateLimits map[string]float64 // Requests per minute by provider (zero: unlimited)
+	Budgets    map[string]int     // Requests per session by provider (zero: unlimited)
+}
+
+// DefaultPolicy retries three times starting at one second, with no rate
+// limits or budgets
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxRetries: 3,
+		BaseDelay:  time.Second,
+		MaxDelay:   time.Minute,
+	}
+}
+
+// providerState tracks pacing and budget use for one provider
+type providerState struct {
+	next time.Time // Earliest start for the next request
+	used int       // Requests charged against the budget
+}
+
+var (
+	mu        sync.Mutex
+	policy    = DefaultPolicy()
+	providers = map[string]*providerState{}
+)
+
+// Configure replaces the policy for all clients. Budget use so far carries
+// over, so lowering a budget takes effect immediately.
+func Configure(p Policy) {
+	mu.Lock()
+	defer mu.Unlock()
+	policy = p
+}
+
+// Used returns how many requests a provider has charged to its budget
+func Used(provider string) int {
+	mu.Lock()
+	defer mu.Unlock()
+	if state, ok := providers[provider]; ok {
+		return state.used
+	}
+	return 0
+}
+
+func currentPolicy() Policy {
+	mu.Lock()
+	defer mu.Unlock()
+	return policy
+}
+
+// acquire waits for the provider's next rate limit slot. New calls are
+// charged to the budget; retries are not.
+func acquire(ctx context.Context, provider string, charge bool) error {
+	mu.Lock()
+	state, ok := providers[provider]
+	if !ok {
+		state = &providerState{}
+		providers[provider] = state
+	}
+	if charge {
+		if budget := policy.Budgets[provider]; budget > 0 && state.used >= budget {
+			mu.Unlock()
+			return fmt.Errorf("%w: %s is limited to %d requests per session (raise apiBudgets.%s in config)",
+				ErrBudgetExceeded, provider, budget, provider)
+		}
+		state.used++
+	}
+	var wait time.Duration
+	if rpm := policy.RateLimits[provider]; rpm > 0 {
+		now := time.Now()
+		start := now
+		if state.next.After(now) {
+			start = state.next
+		}
+		state.next = start.Add(time.Duration(float64(time.Minute) / rpm))
+		wait = start.Sub(now)
+	}
+	mu.Unlock()
+	return sleep(ctx, wait)
+}
+
+// backoff returns the wait before retry number attempt+1: exponential with
+// jitter, or the server's Retry-After when that is longer
+func backoff(p Policy, attempt int, retryAfter time.Duration) time.Duration {
+	delay := p.BaseDelay << attempt
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if delay > 0 {
+		delay = delay/2 + rand.N(delay/2+1)
+	}
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// parseRetryAfter reads a Retry-After header in seconds or as an HTTP date
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// statusPattern finds a retryable HTTP status in errors from SDKs that only
+// report it as text
+var statusPattern = regexp.MustCompile(`\b(429|500|502|503|504)\b`)
+
+// retryableError reports whether a failed call is worth repeating
+func retryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrBudgetExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return statusPattern.MatchString(err.Error())
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Do runs fn under the provider's policy, retrying transient failures. It
+// is for SDKs that do not accept an HTTP client.
+func Do(ctx context.Context, provider string, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		if err := acquire(ctx, provider, attempt == 0); err != nil {
+			return err
+		}
+		err := fn()
+		p := currentPolicy()
+		if attempt >= p.MaxRetries || !retryableError(err) {
+			return err
+		}
+		if err := sleep(ctx, backoff(p, attempt, 0)); err != nil {
+			return err
+		}
+	}
+}
+
+// transport applies the policy to every request for one provider
+type transport struct {
+	provider string
+	base     http.RoundTripper
+}
+
+// NewTransport wraps base (http.DefaultTransport when nil) with the
+// provider's retries, rate limit and budget
+func NewTransport(provider string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{provider: provider, base: base}
+}
+
+// RoundTrip sends the request, repeating it after 429 and 5xx responses and
+// network errors. Requests whose body cannot be replayed are sent once.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	for attempt := 0; ; attempt++ {
+		if err := acquire(ctx, t.provider, attempt == 0); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, err
+		}
+
+		try := req
+		if attempt > 0 {
+			try = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				try.Body = body
+			}
+		}
+
+		resp, err := t.base.RoundTrip(try)
+		p := currentPolicy()
+		if !replayable || attempt >= p.MaxRetries {
+			return resp, err
+		}
+		var retryAfter time.Duration
+		if err != nil {
+			if !retryableError(err) {
+				return resp, err
+			}
+		} else {
+			if !retryableStatus(resp.StatusCode) {
+				return resp, nil
+			}
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, backoff(p, attempt, retryAfter)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// HTTPClient returns a client that applies the provider's policy
+func HTTPClient(provider string) *http.Client {
+	return &http.Client{Transport: NewTransport(provider, nil)}
+}
+
+// OpenAIConfig returns a go-openai configuration whose requests go through
+// the provider's policy
+func OpenAIConfig(apiKey, provider string) openai.ClientConfig {
+	cfg := openai.DefaultConfig(apiKey)
+	cfg.HTTPClient = HTTPClient(provider)
+	return cfg
+}
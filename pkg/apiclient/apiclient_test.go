@@ -0,0 +1,116 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransportRetries(t *testing.T) {
+	Configure(Policy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})
+	defer Configure(DefaultPolicy())
+
+	var calls int
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		switch calls {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	client := HTTPClient("test-retries")
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Errorf("Expected success on the third call, got %d after %d calls", resp.StatusCode, calls)
+	}
+	for i, body := range bodies {
+		if body != "payload" {
+			t.Errorf("Attempt %d sent body %q, want the replayed payload", i+1, body)
+		}
+	}
+	// Retries are not charged to the budget
+	if used := Used("test-retries"); used != 1 {
+		t.Errorf("Used() = %d, want 1", used)
+	}
+
+	// Client errors are returned as is
+	calls = 0
+	badRequest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer badRequest.Close()
+	resp, err = client.Get(badRequest.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest || calls != 1 {
+		t.Errorf("Expected one 400 response, got %d after %d calls", resp.StatusCode, calls)
+	}
+}
+
+func TestBudgetAndDo(t *testing.T) {
+	Configure(Policy{MaxRetries: 2, BaseDelay: time.Millisecond, Budgets: map[string]int{"test-budget": 2}})
+	defer Configure(DefaultPolicy())
+
+	var calls int
+	err := Do(context.Background(), "test-budget", func() error {
+		calls++
+		if calls == 1 {
+			return errors.New(`unexpected HTTP status "503 Service Unavailable" returned from server`)
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("Do() = %v after %d calls, want success after 2", err, calls)
+	}
+
+	calls = 0
+	err = Do(context.Background(), "test-budget", func() error {
+		calls++
+		return errors.New("voice not found (404)")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("Expected a non-retryable error after one call, got %v after %d calls", err, calls)
+	}
+
+	err = Do(context.Background(), "test-budget", func() error { return nil })
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected ErrBudgetExceeded once the budget is used, got %v", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := Policy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		if got := backoff(p, attempt, 0); got < want/2 || got > want {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", attempt, got, want/2, want)
+		}
+	}
+	if got := backoff(p, 0, 3*time.Second); got != 3*time.Second {
+		t.Errorf("backoff with Retry-After = %v, want 3s", got)
+	}
+	if got := backoff(p, 0, time.Hour); got != 5*time.Second {
+		t.Errorf("backoff with long Retry-After = %v, want the 5s cap", got)
+	}
+}
@@ -0,0 +1,197 @@
+package audio
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+)
+
+const (
+	analysisRate = 11025 // Sample rate audio is decoded at for analysis
+	onsetHop     = 256   // Samples between onset envelope frames (~23ms)
+	minTempo     = 60.0  // BPM search range
+	maxTempo     = 180.0
+)
+
+// BeatAnalysis describes the beat grid of a music track
+type BeatAnalysis struct {
+	Tempo    float64   `json:"tempo"`  // Beats per minute
+	Offset   float64   `json:"offset"` // Time of the first beat in seconds
+	Beats    []float64 `json:"beats"`  // Beat times in seconds
+	Duration float64   `json:"duration"`
+}
+
+// Period returns the time between beats in seconds
+func (b *BeatAnalysis) Period() float64 {
+	return 60 / b.Tempo
+}
+
+// DetectBeats estimates the tempo and beat positions of a track. It assumes
+// a steady tempo, which suits most music used under edits.
+func (o *Operations) DetectBeats(ctx context.Context, input string) (*BeatAnalysis, error) {
+	samples, err := o.decodeMono(ctx, input, analysisRate, 0)
+	if err != nil {
+		return nil, err
+	}
+	return detectBeats(samples, analysisRate)
+}
+
+// detectBeats finds the beat grid in mono samples at rate Hz
+func detectBeats(samples []float32, rate int) (*BeatAnalysis, error) {
+	duration := float64(len(samples)) / float64(rate)
+	envelope := onsetEnvelope(samples, onsetHop)
+	frameRate := float64(rate) / onsetHop
+	if len(envelope) < int(frameRate*60/minTempo)*4 {
+		return nil, fmt.Errorf("audio too short to detect beats (%.1fs)", duration)
+	}
+
+	period := estimatePeriod(envelope, frameRate)
+	if period <= 0 {
+		return nil, fmt.Errorf("no steady beat found")
+	}
+	period, phase := fitBeatGrid(envelope, period)
+
+	result := &BeatAnalysis{
+		Tempo:    60 * frameRate / period,
+		Offset:   phase / frameRate,
+		Duration: duration,
+	}
+	for t := result.Offset; t < duration; t += period / frameRate {
+		result.Beats = append(result.Beats, t)
+	}
+	return result, nil
+}
+
+// onsetEnvelope returns the rise in log energy per hop, which peaks where
+// notes and drum hits start
+func onsetEnvelope(samples []float32, hop int) []float64 {
+	frames := len(samples) / hop
+	envelope := make([]float64, frames)
+	previous := 0.0
+	for i := 0; i < frames; i++ {
+		energy := 0.0
+		for _, s := range samples[i*hop : (i+1)*hop] {
+			energy += float64(s) * float64(s)
+		}
+		level := math.Log1p(1000 * energy / float64(hop))
+		if i > 0 {
+			envelope[i] = math.Max(0, level-previous)
+		}
+		previous = level
+	}
+	return envelope
+}
+
+// estimatePeriod returns the beat period in envelope frames from the
+// autocorrelation of the envelope, weighted towards 120 BPM so half and
+// double tempos do not win on ties
+func estimatePeriod(envelope []float64, frameRate float64) float64 {
+	// Spread each onset over neighbouring frames so beats that fall between
+	// frames still line up at integer lags
+	envelope = smooth(smooth(envelope))
+	minLag := int(math.Floor(frameRate * 60 / maxTempo))
+	maxLag := int(math.Ceil(frameRate * 60 / minTempo))
+	scores := make([]float64, maxLag+2)
+	best := 0
+	for lag := minLag; lag <= maxLag+1 && lag < len(envelope); lag++ {
+		sum := 0.0
+		for i := lag; i < len(envelope); i++ {
+			sum += envelope[i] * envelope[i-lag]
+		}
+		bpm := 60 * frameRate / float64(lag)
+		weight := math.Exp(-0.5 * math.Pow(math.Log2(bpm/120), 2))
+		scores[lag] = sum / float64(len(envelope)-lag) * weight
+		if lag <= maxLag && (best == 0 || scores[lag] > scores[best]) {
+			best = lag
+		}
+	}
+	if best == 0 || scores[best] <= 0 {
+		return 0
+	}
+
+	// Parabolic interpolation for a fractional lag
+	if best > minLag && best < maxLag {
+		a, b, c := scores[best-1], scores[best], scores[best+1]
+		if d := a - 2*b + c; d < 0 {
+			return float64(best) + 0.5*(a-c)/d
+		}
+	}
+	return float64(best)
+}
+
+// smooth applies a 3-tap triangular filter
+func smooth(values []float64) []float64 {
+	out := make([]float64, len(values))
+	for i := range values {
+		out[i] = values[i] / 2
+		if i > 0 {
+			out[i] += values[i-1] / 4
+		}
+		if i < len(values)-1 {
+			out[i] += values[i+1] / 4
+		}
+	}
+	return out
+}
+
+// fitBeatGrid refines the period within ±3% and picks the phase whose
+// evenly spaced beats land on the most onset energy. Both are returned in
+// envelope frames.
+func fitBeatGrid(envelope []float64, period float64) (float64, float64) {
+	bestPeriod, bestPhase, bestScore := period, 0.0, -1.0
+	for step := -30; step <= 30; step++ {
+		p := period * (1 + float64(step)*0.001)
+		for phase := 0.0; phase < p; phase++ {
+			score := 0.0
+			for t := phase; ; t += p {
+				i := int(math.Round(t))
+				if i >= len(envelope) {
+					break
+				}
+				score += envelope[i]
+			}
+			if score > bestScore {
+				bestPeriod, bestPhase, bestScore = p, phase, score
+			}
+		}
+	}
+	return bestPeriod, bestPhase
+}
+
+// decodeMono decodes the first audio stream of a file to mono float samples,
+// stopping after limit seconds when limit > 0
+func (o *Operations) decodeMono(ctx context.Context, input string, rate int, limit float64) ([]float32, error) {
+	tempDir, cleanup, err := scratch.Dir("audio-analysis")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	rawPath := filepath.Join(tempDir, "audio.f32")
+	args := []string{"-i", input, "-vn", "-ac", "1", "-ar", fmt.Sprintf("%d", rate)}
+	if limit > 0 {
+		args = append(args, "-t", fmt.Sprintf("%.3f", limit))
+	}
+	args = append(args, "-f", "f32le", "-y", rawPath)
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, fmt.Errorf("failed to decode audio: %w", err)
+	}
+
+	data, err := os.ReadFile(rawPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read decoded audio: %w", err)
+	}
+	if len(data) < 4 {
+		return nil, fmt.Errorf("no audio decoded from %s", input)
+	}
+	samples := make([]float32, len(data)/4)
+	for i := range samples {
+		samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return samples, nil
+}
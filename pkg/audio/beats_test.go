@@ -0,0 +1,63 @@
+package audio
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// clickTrack synthesizes decaying clicks at bpm starting at offset seconds,
+// with quieter off-beat hits and background noise
+func clickTrack(bpm, offset, seconds float64, rate int) []float32 {
+	samples := make([]float32, int(seconds*float64(rate)))
+	rng := rand.New(rand.NewSource(1))
+	for i := range samples {
+		samples[i] = float32(rng.NormFloat64() * 0.01)
+	}
+	period := 60 / bpm
+	addClick := func(at, gain float64) {
+		start := int(at * float64(rate))
+		for i := 0; i < rate/20 && start+i < len(samples); i++ {
+			decay := math.Exp(-float64(i) / float64(rate) * 60)
+			samples[start+i] += float32(gain * decay * math.Sin(2*math.Pi*150*float64(i)/float64(rate)))
+		}
+	}
+	for t := offset; t < seconds; t += period {
+		addClick(t, 0.8)
+		addClick(t+period/2, 0.2)
+	}
+	return samples
+}
+
+func TestDetectBeats(t *testing.T) {
+	for _, tc := range []struct {
+		bpm, offset float64
+	}{
+		{120, 0.25},
+		{95, 0.6},
+		{150, 0.1},
+	} {
+		result, err := detectBeats(clickTrack(tc.bpm, tc.offset, 30, analysisRate), analysisRate)
+		if err != nil {
+			t.Fatalf("%.0f BPM: unexpected error: %v", tc.bpm, err)
+		}
+		if math.Abs(result.Tempo-tc.bpm) > 1 {
+			t.Errorf("Expected tempo %.0f, got %.2f", tc.bpm, result.Tempo)
+		}
+		if math.Abs(result.Offset-tc.offset) > 0.03 {
+			t.Errorf("%.0f BPM: expected first beat at %.2fs, got %.3f", tc.bpm, tc.offset, result.Offset)
+		}
+		// The grid must not drift by the end of the track
+		last := result.Beats[len(result.Beats)-1]
+		beats := math.Round((last - tc.offset) * tc.bpm / 60)
+		if want := tc.offset + beats*60/tc.bpm; math.Abs(last-want) > 0.05 {
+			t.Errorf("%.0f BPM: last beat at %.3fs, expected %.3f", tc.bpm, last, want)
+		}
+	}
+}
+
+func TestDetectBeatsTooShort(t *testing.T) {
+	if _, err := detectBeats(clickTrack(120, 0, 1, analysisRate), analysisRate); err == nil {
+		t.Error("Expected error for a 1s clip")
+	}
+}
@@ -0,0 +1,80 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Clean voice intensities
+const (
+	IntensityLight  = "light"
+	IntensityMedium = "medium"
+	IntensityStrong = "strong"
+)
+
+// CleanVoiceOptions contains parameters for cleaning up a voice recording
+type CleanVoiceOptions struct {
+	Input      string
+	Output     string
+	Intensity  string  // light, medium (default) or strong
+	TargetLUFS float64 // Integrated loudness target (default: -16)
+}
+
+// voiceChain holds the filter settings for one intensity
+type voiceChain struct {
+	highpass  int     // Hz; also takes out plosive thumps
+	deess     float64 // deesser intensity, 0-1
+	threshold int     // Compressor threshold in dB
+	ratio     float64
+}
+
+var voiceChains = map[string]voiceChain{
+	IntensityLight:  {highpass: 70, deess: 0.2, threshold: -18, ratio: 2},
+	IntensityMedium: {highpass: 80, deess: 0.4, threshold: -20, ratio: 3},
+	IntensityStrong: {highpass: 100, deess: 0.6, threshold: -24, ratio: 4},
+}
+
+// CleanVoice fixes a voiceover in one pass: a high-pass filter removes
+// rumble and plosive thumps, a de-esser tames sibilance, gentle compression
+// evens out the level and loudness normalization sets the final level.
+// Video is copied unchanged.
+func (o *Operations) CleanVoice(ctx context.Context, opts CleanVoiceOptions) error {
+	filter, err := cleanVoiceFilter(opts.Intensity, opts.TargetLUFS)
+	if err != nil {
+		return err
+	}
+
+	args := []string{
+		"-i", opts.Input,
+		"-af", filter,
+		"-c:v", "copy",
+		"-y", opts.Output,
+	}
+	return o.ffmpeg.Execute(ctx, args...)
+}
+
+// cleanVoiceFilter builds the filter chain for an intensity
+func cleanVoiceFilter(intensity string, targetLUFS float64) (string, error) {
+	if intensity == "" {
+		intensity = IntensityMedium
+	}
+	chain, ok := voiceChains[intensity]
+	if !ok {
+		return "", fmt.Errorf("unknown intensity: %s (use light, medium or strong)", intensity)
+	}
+	if targetLUFS == 0 {
+		targetLUFS = -16
+	}
+	if targetLUFS < -70 || targetLUFS > -5 {
+		return "", fmt.Errorf("targetLufs must be between -70 and -5")
+	}
+
+	filters := []string{
+		fmt.Sprintf("highpass=f=%d:poles=2", chain.highpass),
+		fmt.Sprintf("deesser=i=%.2f:m=0.5:f=0.5:s=o", chain.deess),
+		fmt.Sprintf("acompressor=threshold=%ddB:ratio=%g:attack=10:release=150:makeup=2", chain.threshold, chain.ratio),
+		fmt.Sprintf("loudnorm=I=%g:TP=-1.5:LRA=11", targetLUFS),
+	}
+	return strings.Join(filters, ","), nil
+}
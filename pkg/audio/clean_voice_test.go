@@ -0,0 +1,59 @@
+package audio
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCleanVoiceFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		intensity  string
+		targetLUFS float64
+		want       []string
+		wantErr    string
+	}{
+		{
+			name: "default is medium at -16 LUFS",
+			want: []string{"highpass=f=80:poles=2", "deesser=i=0.40", "ratio=3", "loudnorm=I=-16:"},
+		},
+		{
+			name:      "light",
+			intensity: "light",
+			want:      []string{"highpass=f=70:poles=2", "deesser=i=0.20", "threshold=-18dB", "ratio=2"},
+		},
+		{
+			name:       "strong with broadcast target",
+			intensity:  "strong",
+			targetLUFS: -23,
+			want:       []string{"highpass=f=100:poles=2", "deesser=i=0.60", "threshold=-24dB", "loudnorm=I=-23:"},
+		},
+		{name: "unknown intensity", intensity: "extreme", wantErr: "unknown intensity"},
+		{name: "target out of range", targetLUFS: 3, wantErr: "between -70 and -5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cleanVoiceFilter(tt.intensity, tt.targetLUFS)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("cleanVoiceFilter() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("cleanVoiceFilter() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("filter %q missing %q", got, want)
+				}
+			}
+			// Loudness normalization comes last so compression cannot push
+			// the level off target
+			if !strings.HasPrefix(got[strings.LastIndex(got, ",")+1:], "loudnorm=") {
+				t.Errorf("filter %q should end with loudnorm", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+)
+
+// DialogueLine is one line of a dialogue script
+type DialogueLine struct {
+	Speaker string  `json:"speaker"`
+	Text    string  `json:"text"`
+	Pause   float64 `json:"pause,omitempty"` // Silence before this line in seconds, instead of the gap
+}
+
+// DialogueOptions contains parameters for generating a multi-voice dialogue
+type DialogueOptions struct {
+	Script   string            // "SPEAKER: line" per line; used when Lines is empty
+	Lines    []DialogueLine    // Lines in order
+	Voices   map[string]string // Speaker -> voice ID for the provider
+	Provider string            // TTS provider for every line (default: as generate_speech)
+	ModelID  string
+	Markup   string  // Markup in line text (see SpeechOptions)
+	Gap      float64 // Silence between lines in seconds (default: 0.35)
+	Output   string  // Mixed track
+	StemsDir string  // Per-speaker stems (default: next to Output)
+}
+
+// DialogueTiming is where a line landed in the mix
+type DialogueTiming struct {
+	Speaker string  `json:"speaker"`
+	Text    string  `json:"text"`
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+}
+
+// DialogueResult describes a generated dialogue
+type DialogueResult struct {
+	Output   string            `json:"output"`
+	Duration float64           `json:"duration"`
+	Stems    map[string]string `json:"stems"` // Speaker -> stem path
+	Lines    []DialogueTiming  `json:"lines"`
+}
+
+// GenerateDialogue synthesizes each line with its speaker's voice, lays
+// the lines out with gaps and writes a mixed track plus one stem per
+// speaker. Stems are the same length as the mix so they line up.
+func (t *TTSOperations) GenerateDialogue(ctx context.Context, opts DialogueOptions) (*DialogueResult, error) {
+	if t.ffmpeg == nil {
+		return nil, fmt.Errorf("FFmpeg is required to mix dialogue")
+	}
+	lines := opts.Lines
+	if len(lines) == 0 {
+		var err error
+		if lines, err = parseDialogueScript(opts.Script); err != nil {
+			return nil, err
+		}
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("dialogue has no lines")
+	}
+	voices, speakers, err := dialogueVoices(lines, opts.Voices)
+	if err != nil {
+		return nil, err
+	}
+	gap := opts.Gap
+	if gap == 0 {
+		gap = 0.35
+	}
+	if gap < 0 {
+		return nil, fmt.Errorf("gap must not be negative")
+	}
+
+	tempDir, cleanup, err := scratch.Dir("dialogue")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	// Synthesize every line
+	files := make([]string, len(lines))
+	durations := make([]float64, len(lines))
+	for i, line := range lines {
+		files[i] = filepath.Join(tempDir, fmt.Sprintf("line_%03d.wav", i))
+		err := t.GenerateSpeech(ctx, SpeechOptions{
+			Text:     line.Text,
+			Provider: opts.Provider,
+			VoiceID:  voices[strings.ToLower(line.Speaker)],
+			ModelID:  opts.ModelID,
+			Markup:   opts.Markup,
+		}, files[i])
+		if err != nil {
+			return nil, fmt.Errorf("line %d (%s): %w", i+1, line.Speaker, err)
+		}
+		if durations[i], err = t.audioDuration(ctx, files[i]); err != nil {
+			return nil, fmt.Errorf("line %d (%s): %w", i+1, line.Speaker, err)
+		}
+	}
+
+	timings, total := dialogueTimeline(lines, durations, gap)
+	result := &DialogueResult{Output: opts.Output, Duration: total, Stems: map[string]string{}, Lines: timings}
+
+	// Mixed track
+	all := make([]int, len(lines))
+	for i := range all {
+		all[i] = i
+	}
+	if err := t.mixDialogue(ctx, files, timings, all, total, opts.Output); err != nil {
+		return nil, fmt.Errorf("failed to mix dialogue: %w", err)
+	}
+
+	// Per-speaker stems
+	stemsDir := opts.StemsDir
+	if stemsDir == "" {
+		stemsDir = filepath.Dir(opts.Output)
+	}
+	if err := os.MkdirAll(stemsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create stems dir: %w", err)
+	}
+	base := strings.TrimSuffix(filepath.Base(opts.Output), filepath.Ext(opts.Output))
+	for _, speaker := range speakers {
+		var indexes []int
+		for i, line := range lines {
+			if strings.EqualFold(line.Speaker, speaker) {
+				indexes = append(indexes, i)
+			}
+		}
+		stem := filepath.Join(stemsDir, fmt.Sprintf("%s_%s%s", base, stemFileName(speaker), filepath.Ext(opts.Output)))
+		if err := t.mixDialogue(ctx, files, timings, indexes, total, stem); err != nil {
+			return nil, fmt.Errorf("failed to write stem for %s: %w", speaker, err)
+		}
+		result.Stems[speaker] = stem
+	}
+	return result, nil
+}
+
+// mixDialogue places the given lines at their start times and pads the
+// result to the full dialogue length
+func (t *TTSOperations) mixDialogue(ctx context.Context, files []string, timings []DialogueTiming, indexes []int, total float64, output string) error {
+	var args []string
+	starts := make([]float64, len(indexes))
+	for i, index := range indexes {
+		args = append(args, "-i", files[index])
+		starts[i] = timings[index].Start
+	}
+	args = append(args, "-filter_complex", dialogueMixFilter(starts, total), "-map", "[out]", "-y", output)
+	return t.ffmpeg.Execute(ctx, args...)
+}
+
+// audioDuration returns the duration of an audio file in seconds
+func (t *TTSOperations) audioDuration(ctx context.Context, path string) (float64, error) {
+	output, err := t.ffmpeg.Probe(ctx,
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		path,
+	)
+	if err != nil {
+		return 0, err
+	}
+	var duration float64
+	if _, err := fmt.Sscanf(output, "%f", &duration); err != nil {
+		return 0, fmt.Errorf("failed to parse duration: %w", err)
+	}
+	return duration, nil
+}
+
+var (
+	dialogueSpeaker = regexp.MustCompile(`^([^:\[\]]{1,40}?)\s*:\s*(.*)$`)
+	dialoguePause   = regexp.MustCompile(`(?i)^\[(?:pause|gap)\s+([\d.]+)\s*(ms|s)?\]$`)
+)
+
+// parseDialogueScript reads "SPEAKER: line" lines. Unlabelled lines
+// continue the previous line, a [pause 2s] line sets the silence before the
+// next line, and blank lines and # comments are skipped.
+func parseDialogueScript(script string) ([]DialogueLine, error) {
+	var lines []DialogueLine
+	var pause float64
+	for n, raw := range strings.Split(script, "\n") {
+		text := strings.TrimSpace(raw)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if m := dialoguePause.FindStringSubmatch(text); m != nil {
+			seconds, err := strconv.ParseFloat(m[1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid pause: %s", n+1, text)
+			}
+			if strings.EqualFold(m[2], "ms") {
+				seconds /= 1000
+			}
+			pause += seconds
+			continue
+		}
+		if m := dialogueSpeaker.FindStringSubmatch(text); m != nil && m[2] != "" {
+			lines = append(lines, DialogueLine{Speaker: strings.TrimSpace(m[1]), Text: m[2], Pause: pause})
+			pause = 0
+			continue
+		}
+		if len(lines) == 0 {
+			return nil, fmt.Errorf("line %d: expected \"SPEAKER: text\", got %q", n+1, text)
+		}
+		lines[len(lines)-1].Text += " " + text
+	}
+	return lines, nil
+}
+
+// dialogueVoices matches speakers to voices case-insensitively and returns
+// the voices keyed by lower-case speaker, plus the speakers in order of
+// first appearance
+func dialogueVoices(lines []DialogueLine, voices map[string]string) (map[string]string, []string, error) {
+	byName := map[string]string{}
+	for speaker, voice := range voices {
+		byName[strings.ToLower(strings.TrimSpace(speaker))] = voice
+	}
+
+	var speakers, missing []string
+	seen := map[string]bool{}
+	for _, line := range lines {
+		key := strings.ToLower(line.Speaker)
+		if line.Speaker == "" {
+			return nil, nil, fmt.Errorf("every line needs a speaker")
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		speakers = append(speakers, line.Speaker)
+		if byName[key] == "" {
+			missing = append(missing, line.Speaker)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("no voice for speaker(s): %s", strings.Join(missing, ", "))
+	}
+	return byName, speakers, nil
+}
+
+// dialogueTimeline places lines one after another with gap seconds
+// between them, or the line's own pause where it has one
+func dialogueTimeline(lines []DialogueLine, durations []float64, gap float64) ([]DialogueTiming, float64) {
+	timings := make([]DialogueTiming, len(lines))
+	cursor := 0.0
+	for i, line := range lines {
+		switch {
+		case line.Pause > 0:
+			cursor += line.Pause
+		case i > 0:
+			cursor += gap
+		}
+		timings[i] = DialogueTiming{Speaker: line.Speaker, Text: line.Text, Start: cursor, End: cursor + durations[i]}
+		cursor += durations[i]
+	}
+	return timings, cursor
+}
+
+// dialogueMixFilter delays each input to its start time, mixes them and
+// pads to total seconds
+func dialogueMixFilter(starts []float64, total float64) string {
+	var filters []string
+	var labels strings.Builder
+	for i, start := range starts {
+		filters = append(filters, fmt.Sprintf("[%d:a]aresample=%d,aformat=channel_layouts=mono,adelay=%d:all=1[l%d]",
+			i, speechSampleRate, int(start*1000+0.5), i))
+		labels.WriteString(fmt.Sprintf("[l%d]", i))
+	}
+	filters = append(filters, fmt.Sprintf("%samix=inputs=%d:duration=longest:dropout_transition=0:normalize=0,apad=whole_dur=%.3f[out]",
+		labels.String(), len(starts), total))
+	return strings.Join(filters, ";")
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// stemFileName turns a speaker name into a file name part
+func stemFileName(speaker string) string {
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(strings.ToLower(speaker), "_"), "_")
+	if name == "" {
+		return "speaker"
+	}
+	return name
+}
@@ -0,0 +1,83 @@
+package audio
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDialogueScript(t *testing.T) {
+	script := `# Episode 3 cold open
+ALICE: Did you hear that?
+BOB: Hear what?
+  It's three in the morning.
+
+[pause 1.5s]
+Dr. Alice: Exactly.`
+
+	lines, err := parseDialogueScript(script)
+	if err != nil {
+		t.Fatalf("parseDialogueScript() error = %v", err)
+	}
+	want := []DialogueLine{
+		{Speaker: "ALICE", Text: "Did you hear that?"},
+		{Speaker: "BOB", Text: "Hear what? It's three in the morning."},
+		{Speaker: "Dr. Alice", Text: "Exactly.", Pause: 1.5},
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("parseDialogueScript() =\n%+v\nwant\n%+v", lines, want)
+	}
+
+	if _, err := parseDialogueScript("No speaker here"); err == nil || !strings.Contains(err.Error(), "SPEAKER") {
+		t.Errorf("expected missing speaker error, got %v", err)
+	}
+}
+
+func TestDialogueVoices(t *testing.T) {
+	lines := []DialogueLine{{Speaker: "Alice", Text: "Hi"}, {Speaker: "BOB", Text: "Hey"}, {Speaker: "alice", Text: "Bye"}}
+
+	voices, speakers, err := dialogueVoices(lines, map[string]string{"alice": "nova", "Bob ": "onyx"})
+	if err != nil {
+		t.Fatalf("dialogueVoices() error = %v", err)
+	}
+	if !reflect.DeepEqual(speakers, []string{"Alice", "BOB"}) {
+		t.Errorf("speakers = %v, want [Alice BOB]", speakers)
+	}
+	if voices["alice"] != "nova" || voices["bob"] != "onyx" {
+		t.Errorf("voices = %v", voices)
+	}
+
+	if _, _, err := dialogueVoices(lines, map[string]string{"alice": "nova"}); err == nil || !strings.Contains(err.Error(), "BOB") {
+		t.Errorf("expected missing voice error naming BOB, got %v", err)
+	}
+}
+
+func TestDialogueTimeline(t *testing.T) {
+	lines := []DialogueLine{
+		{Speaker: "A", Text: "one"},
+		{Speaker: "B", Text: "two"},
+		{Speaker: "A", Text: "three", Pause: 2},
+	}
+	timings, total := dialogueTimeline(lines, []float64{1.5, 1, 0.5}, 0.25)
+	starts := []float64{timings[0].Start, timings[1].Start, timings[2].Start}
+	if !reflect.DeepEqual(starts, []float64{0, 1.75, 4.75}) || total != 5.25 {
+		t.Errorf("starts = %v, total = %v; want [0 1.75 4.75], 5.25", starts, total)
+	}
+	if timings[1].End != 2.75 || timings[2].Speaker != "A" {
+		t.Errorf("timings = %+v", timings)
+	}
+}
+
+func TestDialogueMixFilter(t *testing.T) {
+	got := dialogueMixFilter([]float64{0, 1.75}, 5.25)
+	want := "[0:a]aresample=44100,aformat=channel_layouts=mono,adelay=0:all=1[l0];" +
+		"[1:a]aresample=44100,aformat=channel_layouts=mono,adelay=1750:all=1[l1];" +
+		"[l0][l1]amix=inputs=2:duration=longest:dropout_transition=0:normalize=0,apad=whole_dur=5.250[out]"
+	if got != want {
+		t.Errorf("dialogueMixFilter() =\n%s\nwant\n%s", got, want)
+	}
+
+	if name := stemFileName("Dr. Alice"); name != "dr_alice" {
+		t.Errorf("stemFileName() = %q, want dr_alice", name)
+	}
+}
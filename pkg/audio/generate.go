@@ -0,0 +1,121 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// ToneWaveforms lists the waveforms GenerateTone can produce
+var ToneWaveforms = []string{"sine", "white", "pink", "brown"}
+
+// SilenceOptions contains parameters for generating silence
+type SilenceOptions struct {
+	Output     string
+	Duration   float64 // seconds
+	SampleRate int     // default: 48000
+	Channels   int     // 1 or 2 (default: 2)
+}
+
+// ToneOptions contains parameters for generating a test tone or noise
+type ToneOptions struct {
+	Output     string
+	Duration   float64 // seconds
+	Waveform   string  // sine (default), white, pink or brown noise
+	Frequency  float64 // sine frequency in Hz (default: 1000, the usual sync beep)
+	Amplitude  float64 // 0-1 (default: 0.5)
+	SampleRate int     // default: 48000
+	Channels   int     // 1 or 2 (default: 2)
+}
+
+// GenerateSilence writes a silent audio file, e.g. to fill a gap
+func (o *Operations) GenerateSilence(ctx context.Context, opts SilenceOptions) error {
+	rate, layout, err := audioFormat(opts.SampleRate, opts.Channels)
+	if err != nil {
+		return err
+	}
+	if opts.Duration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+
+	args := []string{
+		"-f", "lavfi",
+		"-i", fmt.Sprintf("anullsrc=r=%d:cl=%s", rate, layout),
+		"-t", fmt.Sprintf("%.3f", opts.Duration),
+		"-y", opts.Output,
+	}
+	return o.ffmpeg.Execute(ctx, args...)
+}
+
+// GenerateTone writes a sine tone or noise, e.g. a sync beep or a test signal
+func (o *Operations) GenerateTone(ctx context.Context, opts ToneOptions) error {
+	source, err := toneSource(opts)
+	if err != nil {
+		return err
+	}
+
+	args := []string{
+		"-f", "lavfi",
+		"-i", source,
+		"-t", fmt.Sprintf("%.3f", opts.Duration),
+		"-y", opts.Output,
+	}
+	return o.ffmpeg.Execute(ctx, args...)
+}
+
+// toneSource builds the lavfi source for a tone
+func toneSource(opts ToneOptions) (string, error) {
+	rate, layout, err := audioFormat(opts.SampleRate, opts.Channels)
+	if err != nil {
+		return "", err
+	}
+	if opts.Duration <= 0 {
+		return "", fmt.Errorf("duration must be positive")
+	}
+	waveform := opts.Waveform
+	if waveform == "" {
+		waveform = "sine"
+	}
+	if !slices.Contains(ToneWaveforms, waveform) {
+		return "", fmt.Errorf("unknown waveform: %s (use sine, white, pink or brown)", waveform)
+	}
+	amplitude := opts.Amplitude
+	if amplitude == 0 {
+		amplitude = 0.5
+	}
+	if amplitude < 0 || amplitude > 1 {
+		return "", fmt.Errorf("amplitude must be between 0 and 1")
+	}
+
+	if waveform != "sine" {
+		return fmt.Sprintf("anoisesrc=r=%d:c=%s:a=%g,aformat=channel_layouts=%s", rate, waveform, amplitude, layout), nil
+	}
+	frequency := opts.Frequency
+	if frequency == 0 {
+		frequency = 1000
+	}
+	if frequency < 1 || frequency > float64(rate)/2 {
+		return "", fmt.Errorf("frequency must be between 1 and %d Hz", rate/2)
+	}
+	// sine is always mono at full scale
+	return fmt.Sprintf("sine=f=%g:r=%d,volume=%g,aformat=channel_layouts=%s", frequency, rate, amplitude, layout), nil
+}
+
+// audioFormat applies defaults to a sample rate and channel count and
+// returns the rate and FFmpeg channel layout
+func audioFormat(sampleRate, channels int) (int, string, error) {
+	if sampleRate == 0 {
+		sampleRate = 48000
+	}
+	if sampleRate < 8000 || sampleRate > 192000 {
+		return 0, "", fmt.Errorf("sample rate must be between 8000 and 192000")
+	}
+	switch channels {
+	case 0, 2:
+		return sampleRate, "stereo", nil
+	case 1:
+		return sampleRate, "mono", nil
+	default:
+		return 0, "", fmt.Errorf("channels must be 1 or 2")
+	}
+}
@@ -0,0 +1,54 @@
+package audio
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToneSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ToneOptions
+		want    string
+		wantErr string
+	}{
+		{
+			name: "default sync beep",
+			opts: ToneOptions{Duration: 1},
+			want: "sine=f=1000:r=48000,volume=0.5,aformat=channel_layouts=stereo",
+		},
+		{
+			name: "mono 440Hz",
+			opts: ToneOptions{Duration: 1, Frequency: 440, Amplitude: 0.25, SampleRate: 44100, Channels: 1},
+			want: "sine=f=440:r=44100,volume=0.25,aformat=channel_layouts=mono",
+		},
+		{
+			name: "pink noise",
+			opts: ToneOptions{Duration: 5, Waveform: "pink"},
+			want: "anoisesrc=r=48000:c=pink:a=0.5,aformat=channel_layouts=stereo",
+		},
+		{name: "no duration", opts: ToneOptions{}, wantErr: "duration"},
+		{name: "unknown waveform", opts: ToneOptions{Duration: 1, Waveform: "square"}, wantErr: "unknown waveform"},
+		{name: "above nyquist", opts: ToneOptions{Duration: 1, Frequency: 30000}, wantErr: "frequency"},
+		{name: "too loud", opts: ToneOptions{Duration: 1, Amplitude: 2}, wantErr: "amplitude"},
+		{name: "surround", opts: ToneOptions{Duration: 1, Channels: 6}, wantErr: "channels"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toneSource(tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("toneSource() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("toneSource() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("toneSource() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
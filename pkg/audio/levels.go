@@ -0,0 +1,37 @@
+package audio
+
+import (
+	"context"
+	"math"
+)
+
+// levelFloor is the level reported for silent windows, in dBFS
+const levelFloor = -100.0
+
+// LevelEnvelope returns the RMS level in dBFS of each window seconds of a
+// file's audio, reading at most limit seconds when limit > 0
+func (o *Operations) LevelEnvelope(ctx context.Context, input string, window, limit float64) ([]float64, error) {
+	samples, err := o.decodeMono(ctx, input, syncRate, limit)
+	if err != nil {
+		return nil, err
+	}
+	return levelEnvelope(samples, max(int(window*syncRate), 1)), nil
+}
+
+// levelEnvelope returns the RMS level in dBFS of each size-sample window
+func levelEnvelope(samples []float32, size int) []float64 {
+	levels := make([]float64, 0, len(samples)/size+1)
+	for start := 0; start < len(samples); start += size {
+		chunk := samples[start:min(start+size, len(samples))]
+		sum := 0.0
+		for _, s := range chunk {
+			sum += float64(s) * float64(s)
+		}
+		level := levelFloor
+		if sum > 0 {
+			level = math.Max(levelFloor, 10*math.Log10(sum/float64(len(chunk))))
+		}
+		levels = append(levels, level)
+	}
+	return levels
+}
@@ -0,0 +1,176 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LoudnessStats contains loudness and signal measurements for an audio stream
+type LoudnessStats struct {
+	Duration          float64 `json:"duration"`
+	IntegratedLUFS    float64 `json:"integratedLufs"`
+	LoudnessRange     float64 `json:"loudnessRange"` // LRA in LU
+	TruePeak          float64 `json:"truePeak"`      // dBTP
+	PeakLevel         float64 `json:"peakLevel"`     // Sample peak in dBFS
+	RMSLevel          float64 `json:"rmsLevel"`      // dBFS
+	CrestFactor       float64 `json:"crestFactor"`   // Peak to RMS ratio in dB
+	NoiseFloor        float64 `json:"noiseFloor"`    // dBFS
+	DCOffset          float64 `json:"dcOffset"`
+	PeakCount         int     `json:"peakCount"`  // Samples at the peak level
+	FlatFactor        float64 `json:"flatFactor"` // Consecutive samples at peak (flat tops)
+	Clipping          bool    `json:"clipping"`
+	SilenceDuration   float64 `json:"silenceDuration"`
+	SilencePercentage float64 `json:"silencePercentage"`
+}
+
+const (
+	clippingThresholdDB = -0.1  // Sample peaks at or above this are treated as full scale
+	statsSilenceNoiseDB = -50.0 // Level below which audio counts as silence
+	statsSilenceMinDur  = 0.5   // Minimum silence length in seconds
+)
+
+// AnalyzeLoudness measures integrated loudness, true peak, loudness range,
+// clipping, DC offset and silence in a single pass using ebur128, astats
+// and silencedetect
+func (o *Operations) AnalyzeLoudness(ctx context.Context, input string) (*LoudnessStats, error) {
+	filter := fmt.Sprintf("ebur128=peak=true:framelog=verbose,astats=metadata=0,silencedetect=noise=%.0fdB:d=%.1f",
+		statsSilenceNoiseDB, statsSilenceMinDur)
+
+	output, err := o.ffmpeg.ExecuteWithOutput(ctx,
+		"-hide_banner",
+		"-nostats",
+		"-i", input,
+		"-vn",
+		"-af", filter,
+		"-f", "null", "-",
+	)
+	if err != nil {
+		if strings.Contains(output, "does not contain any stream") || strings.Contains(output, "matches no streams") {
+			return nil, fmt.Errorf("no audio stream found in %s", input)
+		}
+		return nil, fmt.Errorf("failed to analyze audio: %w", err)
+	}
+
+	return parseLoudnessOutput(output), nil
+}
+
+var (
+	durationPattern     = regexp.MustCompile(`Duration: (\d+):(\d+):(\d+(?:\.\d+)?)`)
+	integratedPattern   = regexp.MustCompile(`^\s*I:\s+(-?[\d.]+|-inf) LUFS`)
+	lraPattern          = regexp.MustCompile(`^\s*LRA:\s+(-?[\d.]+) LU`)
+	truePeakPattern     = regexp.MustCompile(`^\s*Peak:\s+(-?[\d.]+|-inf) dBFS`)
+	astatsPattern       = regexp.MustCompile(`\] ([A-Za-z ]+?): (-?[\d.]+|-?inf|nan)\s*$`)
+	silenceStartPattern = regexp.MustCompile(`silence_start: (-?[\d.]+)`)
+	silenceEndPattern   = regexp.MustCompile(`silence_end: (-?[\d.]+)`)
+)
+
+// parseLoudnessOutput extracts measurements from FFmpeg's filter log output
+func parseLoudnessOutput(output string) *LoudnessStats {
+	stats := &LoudnessStats{
+		IntegratedLUFS: math.Inf(-1),
+		TruePeak:       math.Inf(-1),
+		PeakLevel:      math.Inf(-1),
+		RMSLevel:       math.Inf(-1),
+	}
+
+	if m := durationPattern.FindStringSubmatch(output); m != nil {
+		h, _ := strconv.ParseFloat(m[1], 64)
+		min, _ := strconv.ParseFloat(m[2], 64)
+		sec, _ := strconv.ParseFloat(m[3], 64)
+		stats.Duration = h*3600 + min*60 + sec
+	}
+
+	inOverall := false
+	silenceStart := -1.0
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case integratedPattern.MatchString(line):
+			stats.IntegratedLUFS = parseLevel(integratedPattern.FindStringSubmatch(line)[1])
+			continue
+		case lraPattern.MatchString(line):
+			stats.LoudnessRange = parseLevel(lraPattern.FindStringSubmatch(line)[1])
+			continue
+		case truePeakPattern.MatchString(line):
+			stats.TruePeak = parseLevel(truePeakPattern.FindStringSubmatch(line)[1])
+			continue
+		}
+
+		if m := silenceStartPattern.FindStringSubmatch(line); m != nil {
+			silenceStart, _ = strconv.ParseFloat(m[1], 64)
+			continue
+		}
+		if m := silenceEndPattern.FindStringSubmatch(line); m != nil {
+			end, _ := strconv.ParseFloat(m[1], 64)
+			if silenceStart >= 0 {
+				stats.SilenceDuration += end - math.Max(0, silenceStart)
+			}
+			silenceStart = -1
+			continue
+		}
+
+		// astats reports per channel first, then an Overall section
+		if strings.HasSuffix(strings.TrimSpace(line), "Overall") {
+			inOverall = true
+			continue
+		}
+		if !inOverall {
+			continue
+		}
+		m := astatsPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		value := parseLevel(m[2])
+		switch m[1] {
+		case "DC offset":
+			stats.DCOffset = value
+		case "Peak level dB":
+			stats.PeakLevel = value
+		case "RMS level dB":
+			stats.RMSLevel = value
+		case "Noise floor dB":
+			stats.NoiseFloor = value
+		case "Flat factor":
+			stats.FlatFactor = value
+		case "Peak count":
+			stats.PeakCount = int(value)
+		}
+	}
+
+	// Silence that runs to the end of the file has no silence_end
+	if silenceStart >= 0 && stats.Duration > silenceStart {
+		stats.SilenceDuration += stats.Duration - silenceStart
+	}
+	if stats.Duration > 0 {
+		stats.SilencePercentage = math.Min(100, stats.SilenceDuration/stats.Duration*100)
+	}
+
+	if !math.IsInf(stats.PeakLevel, -1) && !math.IsInf(stats.RMSLevel, -1) {
+		stats.CrestFactor = stats.PeakLevel - stats.RMSLevel
+	}
+	stats.Clipping = stats.PeakLevel >= clippingThresholdDB && (stats.FlatFactor > 0 || stats.PeakCount > 2)
+
+	return stats
+}
+
+func parseLevel(s string) float64 {
+	switch s {
+	case "-inf":
+		return math.Inf(-1)
+	case "inf":
+		return math.Inf(1)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
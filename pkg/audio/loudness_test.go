@@ -0,0 +1,61 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+const sampleLoudnessOutput = `Input #0, wav, from 'clip.wav':
+  Duration: 00:00:10.00, bitrate: 1536 kb/s
+[silencedetect @ 0x1] silence_start: 2
+[silencedetect @ 0x1] silence_end: 3.5 | silence_duration: 1.5
+[silencedetect @ 0x1] silence_start: 9
+[Parsed_ebur128_0 @ 0x2] Summary:
+
+  Integrated loudness:
+    I:         -16.4 LUFS
+    Threshold: -26.7 LUFS
+
+  Loudness range:
+    LRA:         6.3 LU
+    Threshold:  -36.8 LUFS
+    LRA low:    -20.1 LUFS
+    LRA high:   -13.8 LUFS
+
+  True peak:
+    Peak:        0.4 dBFS
+[Parsed_astats_1 @ 0x3] Channel: 1
+[Parsed_astats_1 @ 0x3] DC offset: 0.020000
+[Parsed_astats_1 @ 0x3] Peak level dB: -3.000000
+[Parsed_astats_1 @ 0x3] Overall
+[Parsed_astats_1 @ 0x3] DC offset: 0.010000
+[Parsed_astats_1 @ 0x3] Peak level dB: 0.000000
+[Parsed_astats_1 @ 0x3] RMS level dB: -18.500000
+[Parsed_astats_1 @ 0x3] Flat factor: 4.200000
+[Parsed_astats_1 @ 0x3] Peak count: 148.000000
+[Parsed_astats_1 @ 0x3] Noise floor dB: -70.200000
+`
+
+func TestParseLoudnessOutput(t *testing.T) {
+	stats := parseLoudnessOutput(sampleLoudnessOutput)
+
+	if stats.Duration != 10 {
+		t.Errorf("Expected duration 10, got %.2f", stats.Duration)
+	}
+	if stats.IntegratedLUFS != -16.4 || stats.LoudnessRange != 6.3 || stats.TruePeak != 0.4 {
+		t.Errorf("Unexpected ebur128 values: %+v", stats)
+	}
+	if stats.DCOffset != 0.01 || stats.PeakLevel != 0 || stats.PeakCount != 148 {
+		t.Errorf("Expected overall astats values, got %+v", stats)
+	}
+	if math.Abs(stats.CrestFactor-18.5) > 0.001 {
+		t.Errorf("Expected crest factor 18.5 dB, got %.2f", stats.CrestFactor)
+	}
+	if !stats.Clipping {
+		t.Error("Expected clipping to be detected")
+	}
+	// 1.5s + trailing 1s of silence over 10s
+	if math.Abs(stats.SilencePercentage-25) > 0.001 {
+		t.Errorf("Expected 25%% silence, got %.2f", stats.SilencePercentage)
+	}
+}
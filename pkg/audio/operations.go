@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
 )
 
 // Operations handles standalone audio editing operations
@@ -39,8 +42,24 @@ type TrimOptions struct {
 
 // ConcatenateOptions contains parameters for joining audio files
 type ConcatenateOptions struct {
-	Inputs []string
-	Output string
+	Inputs    []string
+	Output    string
+	Crossfade float64 // optional crossfade between files in seconds
+}
+
+// CrossfadeOptions contains parameters for crossfading audio files
+type CrossfadeOptions struct {
+	Inputs   []string
+	Output   string
+	Duration float64 // crossfade length in seconds (default: 2)
+	Curve    string  // fade curve for both sides (default: tri)
+	InCurve  string  // optional fade-in curve for the incoming file
+}
+
+// CrossfadeCurves lists the fade curves supported by acrossfade
+var CrossfadeCurves = []string{
+	"tri", "qsin", "esin", "hsin", "log", "ipar", "qua", "cub", "squ", "cbr",
+	"par", "exp", "iqsin", "ihsin", "dese", "desi", "losi", "sinc", "isinc", "nofade",
 }
 
 // VolumeOptions contains parameters for volume adjustment
@@ -126,12 +145,20 @@ func (o *Operations) TrimAudio(ctx context.Context, opts TrimOptions) error {
 
 // ConcatenateAudio joins multiple audio files
 func (o *Operations) ConcatenateAudio(ctx context.Context, opts ConcatenateOptions) error {
+	if opts.Crossfade > 0 {
+		return o.CrossfadeAudio(ctx, CrossfadeOptions{
+			Inputs:   opts.Inputs,
+			Output:   opts.Output,
+			Duration: opts.Crossfade,
+		})
+	}
+
 	// Create concat file
-	tempDir, err := os.MkdirTemp("", "audio-concat-*")
+	tempDir, cleanup, err := scratch.Dir("audio-concat")
 	if err != nil {
 		return err
 	}
-	defer os.RemoveAll(tempDir)
+	defer cleanup()
 
 	concatFile := filepath.Join(tempDir, "concat.txt")
 	var content string
@@ -155,6 +182,64 @@ func (o *Operations) ConcatenateAudio(ctx context.Context, opts ConcatenateOptio
 	return o.ffmpeg.Execute(ctx, args...)
 }
 
+// CrossfadeAudio joins audio files so each one fades out while the next
+// fades in, overlapping by the crossfade duration
+func (o *Operations) CrossfadeAudio(ctx context.Context, opts CrossfadeOptions) error {
+	if len(opts.Inputs) < 2 {
+		return fmt.Errorf("at least 2 inputs are required")
+	}
+	filter, err := crossfadeFilter(len(opts.Inputs), opts.Duration, opts.Curve, opts.InCurve)
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	for _, input := range opts.Inputs {
+		args = append(args, "-i", input)
+	}
+	args = append(args,
+		"-filter_complex", filter,
+		"-map", "[out]",
+		"-y", opts.Output,
+	)
+
+	return o.ffmpeg.Execute(ctx, args...)
+}
+
+// crossfadeFilter chains acrossfade over n inputs, labelling the result [out]
+func crossfadeFilter(n int, duration float64, curve, inCurve string) (string, error) {
+	if duration == 0 {
+		duration = 2
+	}
+	if duration < 0 {
+		return "", fmt.Errorf("duration must be positive")
+	}
+	if curve == "" {
+		curve = "tri"
+	}
+	if inCurve == "" {
+		inCurve = curve
+	}
+	for _, c := range []string{curve, inCurve} {
+		if !slices.Contains(CrossfadeCurves, c) {
+			return "", fmt.Errorf("unknown curve: %s (use one of %s)", c, strings.Join(CrossfadeCurves, ", "))
+		}
+	}
+
+	var parts []string
+	previous := "0:a"
+	for i := 1; i < n; i++ {
+		label := fmt.Sprintf("x%d", i)
+		if i == n-1 {
+			label = "out"
+		}
+		parts = append(parts, fmt.Sprintf("[%s][%d:a]acrossfade=d=%.3f:c1=%s:c2=%s[%s]",
+			previous, i, duration, curve, inCurve, label))
+		previous = label
+	}
+	return strings.Join(parts, ";"), nil
+}
+
 // AdjustVolume changes audio volume
 func (o *Operations) AdjustVolume(ctx context.Context, opts VolumeOptions) error {
 	args := []string{
@@ -289,35 +374,16 @@ func (o *Operations) ConvertAudio(ctx context.Context, opts ConvertOptions) erro
 
 // AdjustSpeed changes audio playback speed
 func (o *Operations) AdjustSpeed(ctx context.Context, opts SpeedOptions) error {
-	// Use atempo filter (supports 0.5 to 2.0 range)
-	// Chain multiple atempo filters for values outside this range
-	var atempoFilters []string
-	remaining := opts.Speed
-
-	for remaining > 2.0 {
-		atempoFilters = append(atempoFilters, "atempo=2.0")
-		remaining /= 2.0
-	}
-	for remaining < 0.5 {
-		atempoFilters = append(atempoFilters, "atempo=0.5")
-		remaining /= 0.5
+	if opts.Speed <= 0 {
+		return fmt.Errorf("speed must be positive, got: %.2f", opts.Speed)
 	}
-	if remaining != 1.0 {
-		atempoFilters = append(atempoFilters, fmt.Sprintf("atempo=%.4f", remaining))
-	}
-
-	if len(atempoFilters) == 0 {
+	if opts.Speed == 1 {
 		return fmt.Errorf("no speed adjustment needed")
 	}
 
-	filterStr := atempoFilters[0]
-	for i := 1; i < len(atempoFilters); i++ {
-		filterStr += "," + atempoFilters[i]
-	}
-
 	args := []string{
 		"-i", opts.Input,
-		"-af", filterStr,
+		"-af", ffmpeg.AtempoChain(opts.Speed),
 		"-y", opts.Output,
 	}
 
@@ -326,11 +392,11 @@ func (o *Operations) AdjustSpeed(ctx context.Context, opts SpeedOptions) error {
 
 // RemoveAudioSection removes a section of audio
 func (o *Operations) RemoveAudioSection(ctx context.Context, input, output string, startTime, endTime float64) error {
-	tempDir, err := os.MkdirTemp("", "audio-remove-*")
+	tempDir, cleanup, err := scratch.Dir("audio-remove")
 	if err != nil {
 		return err
 	}
-	defer os.RemoveAll(tempDir)
+	defer cleanup()
 
 	// Extract parts before and after the section to remove
 	beforePath := filepath.Join(tempDir, "before.mp3")
@@ -333,3 +333,57 @@ func TestMixAudio(t *testing.T) {
 		t.Error("Output file was not created")
 	}
 }
+
+func TestCrossfadeAudio(t *testing.T) {
+	ops, testDir := setupTest(t)
+	defer cleanup(testDir)
+
+	// Create three test audio files
+	var inputs []string
+	for i := 1; i <= 3; i++ {
+		path := filepath.Join(testDir, fmt.Sprintf("test%d.mp3", i))
+		createTestAudio(t, path, 3.0)
+		inputs = append(inputs, path)
+	}
+
+	outputPath := filepath.Join(testDir, "crossfaded.mp3")
+	ctx := context.Background()
+
+	err := ops.CrossfadeAudio(ctx, CrossfadeOptions{
+		Inputs:   inputs,
+		Output:   outputPath,
+		Duration: 1.0,
+		Curve:    "qsin",
+	})
+
+	if err != nil {
+		t.Fatalf("CrossfadeAudio failed: %v", err)
+	}
+
+	if _, err := os.Stat(outputPath); os.IsNotExist(err) {
+		t.Error("Output file was not created")
+	}
+}
+
+func TestCrossfadeFilter(t *testing.T) {
+	got, err := crossfadeFilter(3, 1.5, "log", "exp")
+	if err != nil {
+		t.Fatalf("crossfadeFilter failed: %v", err)
+	}
+	want := "[0:a][1:a]acrossfade=d=1.500:c1=log:c2=exp[x1];[x1][2:a]acrossfade=d=1.500:c1=log:c2=exp[out]"
+	if got != want {
+		t.Errorf("crossfadeFilter() = %q, want %q", got, want)
+	}
+
+	got, err = crossfadeFilter(2, 0, "", "")
+	if err != nil {
+		t.Fatalf("crossfadeFilter failed: %v", err)
+	}
+	if want := "[0:a][1:a]acrossfade=d=2.000:c1=tri:c2=tri[out]"; got != want {
+		t.Errorf("crossfadeFilter() defaults = %q, want %q", got, want)
+	}
+
+	if _, err := crossfadeFilter(2, 1, "wobble", ""); err == nil {
+		t.Error("Expected error for unknown curve")
+	}
+}
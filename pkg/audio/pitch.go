@@ -0,0 +1,123 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+)
+
+// PitchOptions contains parameters for pitch shifting
+type PitchOptions struct {
+	Input     string
+	Output    string
+	Semitones float64 // -24 to 24; fractions shift by cents
+
+	// ChangeTempo lets the tempo follow the pitch like a sped-up or slowed
+	// tape, instead of keeping the original duration
+	ChangeTempo bool
+
+	// PreserveFormants keeps the character of voices when shifting, so they
+	// do not sound chipmunk-like. Needs FFmpeg built with rubberband.
+	PreserveFormants bool
+}
+
+// PitchResult describes a pitch shift
+type PitchResult struct {
+	Engine   string   `json:"engine"` // rubberband or resample
+	Ratio    float64  `json:"ratio"`  // Frequency ratio applied
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// PitchShift shifts audio by a number of semitones. FFmpeg's rubberband
+// filter is used when available; otherwise the audio is resampled and, to
+// keep the tempo, time-stretched back with atempo.
+func (o *Operations) PitchShift(ctx context.Context, opts PitchOptions) (*PitchResult, error) {
+	if opts.Semitones < -24 || opts.Semitones > 24 {
+		return nil, fmt.Errorf("semitones must be between -24 and 24")
+	}
+	if opts.Semitones == 0 {
+		return nil, fmt.Errorf("no pitch shift needed")
+	}
+
+	result := &PitchResult{Ratio: semitoneRatio(opts.Semitones)}
+	var filter string
+	if o.ffmpeg.HasFilter("rubberband") {
+		result.Engine = "rubberband"
+		filter = rubberbandFilter(result.Ratio, opts.ChangeTempo, opts.PreserveFormants)
+	} else {
+		result.Engine = "resample"
+		rate, err := o.sampleRate(ctx, opts.Input)
+		if err != nil {
+			return nil, err
+		}
+		filter = resamplePitchFilter(result.Ratio, rate, opts.ChangeTempo)
+		if opts.PreserveFormants {
+			result.Warnings = append(result.Warnings, "formants were not preserved: this FFmpeg build has no rubberband filter")
+		}
+	}
+
+	args := []string{
+		"-i", opts.Input,
+		"-af", filter,
+		"-c:v", "copy",
+		"-y", opts.Output,
+	}
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// semitoneRatio returns the frequency ratio for a shift in semitones
+func semitoneRatio(semitones float64) float64 {
+	return math.Pow(2, semitones/12)
+}
+
+// rubberbandFilter shifts pitch by ratio, optionally changing tempo with it
+func rubberbandFilter(ratio float64, changeTempo, preserveFormants bool) string {
+	filter := fmt.Sprintf("rubberband=pitch=%.6f", ratio)
+	if changeTempo {
+		filter += fmt.Sprintf(":tempo=%.6f", ratio)
+	}
+	if preserveFormants {
+		filter += ":formant=preserved"
+	}
+	return filter + ":pitchq=quality"
+}
+
+// resamplePitchFilter plays the audio back at ratio times its sample rate,
+// which shifts pitch and tempo together, then stretches it back to the
+// original duration unless changeTempo is set
+func resamplePitchFilter(ratio float64, rate int, changeTempo bool) string {
+	filters := []string{
+		fmt.Sprintf("asetrate=%d", int(math.Round(float64(rate)*ratio))),
+		fmt.Sprintf("aresample=%d", rate),
+	}
+	if !changeTempo {
+		filters = append(filters, ffmpeg.AtempoChain(1/ratio))
+	}
+	return strings.Join(filters, ",")
+}
+
+// sampleRate returns the sample rate of a file's first audio stream
+func (o *Operations) sampleRate(ctx context.Context, input string) (int, error) {
+	output, err := o.ffmpeg.Probe(ctx,
+		"-v", "error",
+		"-select_streams", "a:0",
+		"-show_entries", "stream=sample_rate",
+		"-of", "csv=p=0",
+		input,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to probe audio: %w", err)
+	}
+	rate, err := strconv.Atoi(strings.TrimSpace(output))
+	if err != nil || rate <= 0 {
+		return 0, fmt.Errorf("no audio stream found in %s", input)
+	}
+	return rate, nil
+}
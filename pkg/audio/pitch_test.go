@@ -0,0 +1,60 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSemitoneRatio(t *testing.T) {
+	tests := map[float64]float64{
+		12:  2,
+		-12: 0.5,
+		7:   1.498307,
+		0.5: 1.029302,
+	}
+	for semitones, want := range tests {
+		if got := semitoneRatio(semitones); math.Abs(got-want) > 1e-5 {
+			t.Errorf("semitoneRatio(%v) = %v, want %v", semitones, got, want)
+		}
+	}
+}
+
+func TestRubberbandFilter(t *testing.T) {
+	tests := []struct {
+		name             string
+		changeTempo      bool
+		preserveFormants bool
+		want             string
+	}{
+		{name: "pitch only", want: "rubberband=pitch=1.500000:pitchq=quality"},
+		{name: "with tempo", changeTempo: true, want: "rubberband=pitch=1.500000:tempo=1.500000:pitchq=quality"},
+		{name: "formants", preserveFormants: true, want: "rubberband=pitch=1.500000:formant=preserved:pitchq=quality"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rubberbandFilter(1.5, tt.changeTempo, tt.preserveFormants); got != tt.want {
+				t.Errorf("rubberbandFilter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResamplePitchFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		ratio       float64
+		changeTempo bool
+		want        string
+	}{
+		{name: "octave up keeps tempo", ratio: 2, want: "asetrate=96000,aresample=48000,atempo=0.5000"},
+		{name: "two octaves down keeps tempo", ratio: 0.25, want: "asetrate=12000,aresample=48000,atempo=2.0,atempo=2.0000"},
+		{name: "tape style", ratio: 2, changeTempo: true, want: "asetrate=96000,aresample=48000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resamplePitchFilter(tt.ratio, 48000, tt.changeTempo); got != tt.want {
+				t.Errorf("resamplePitchFilter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,12 +1,15 @@
 package audio
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
 
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
 )
@@ -29,6 +32,35 @@ type ReplaceOptions struct {
 	VoiceID         string // optional, reuse existing voice
 	MatchIndex      int    // which match to replace (-1 for all)
 	OutputPath      string
+	PreviewPadding  float64 // seconds of context around each match in a preview (default: 3)
+}
+
+// ReplacementPreview is a short render of each replacement for approval
+// before the full render
+type ReplacementPreview struct {
+	VoiceID        string        `json:"voiceId"`        // Pass back to skip re-cloning
+	TranscriptPath string        `json:"transcriptPath"` // Pass back to skip re-transcribing
+	Clips          []PreviewClip `json:"clips"`
+}
+
+// PreviewClip is the region around one replaced match
+type PreviewClip struct {
+	MatchIndex int     `json:"matchIndex"`
+	Text       string  `json:"text"`  // Matched text
+	Start      float64 `json:"start"` // Match start in the input
+	End        float64 `json:"end"`   // Match end in the input
+	ClipStart  float64 `json:"clipStart"`
+	ClipEnd    float64 `json:"clipEnd"`
+	Path       string  `json:"path"`
+}
+
+// replacementPlan is what a preview and a full render share: the
+// transcript, the matches to replace and the voice to speak them in
+type replacementPlan struct {
+	transcript *transcript.Transcript
+	indexes    []int // Match numbers, for reporting
+	matches    []transcript.Match
+	voiceID    string
 }
 
 // NewReplacementOperations creates a new word replacement orchestrator
@@ -41,89 +73,144 @@ func NewReplacementOperations(tts *TTSOperations, splice *SpliceOperations, tran
 	}
 }
 
-// ReplaceWord is the main entry point for word replacement
-func (r *ReplacementOperations) ReplaceWord(ctx context.Context, opts ReplaceOptions) error {
+// plan finds the matches to replace and the voice to use
+func (r *ReplacementOperations) plan(ctx context.Context, opts ReplaceOptions) (*replacementPlan, error) {
 	// Step 1: Get or generate transcript with word-level timestamps
-	var trans *transcript.Transcript
-	var err error
-
-	if opts.TranscriptPath != "" {
-		trans, err = r.trans.LoadTranscript(opts.TranscriptPath)
-		if err != nil {
-			return fmt.Errorf("failed to load transcript: %w", err)
-		}
-	} else {
-		trans, err = r.trans.ExtractTranscript(ctx, opts.VideoPath, "")
-		if err != nil {
-			return fmt.Errorf("failed to extract transcript: %w", err)
-		}
+	trans, err := r.loadTranscript(ctx, opts.VideoPath, opts.TranscriptPath)
+	if err != nil {
+		return nil, err
 	}
 
 	// Step 2: Find word/phrase in transcript
 	matches := r.trans.FindInTranscript(trans, opts.SearchText)
 	if len(matches) == 0 {
-		return fmt.Errorf("word/phrase '%s' not found in transcript", opts.SearchText)
+		return nil, fmt.Errorf("word/phrase '%s' not found in transcript", opts.SearchText)
 	}
 
 	// Select which matches to replace
-	var selectedMatches []transcript.Match
-	if opts.MatchIndex == -1 {
-		// Replace all occurrences
-		selectedMatches = matches
-	} else if opts.MatchIndex >= 0 && opts.MatchIndex < len(matches) {
-		// Replace specific occurrence
-		selectedMatches = []transcript.Match{matches[opts.MatchIndex]}
-	} else {
-		return fmt.Errorf("match index %d out of range (found %d matches)", opts.MatchIndex, len(matches))
+	plan := &replacementPlan{transcript: trans}
+	if plan.indexes, err = selectMatches(len(matches), opts.MatchIndex); err != nil {
+		return nil, err
+	}
+	for _, i := range plan.indexes {
+		plan.matches = append(plan.matches, matches[i])
 	}
 
 	// Step 3: Get voice ID for TTS
-	voiceID := opts.VoiceID
-	if voiceID == "" {
-		voiceID, err = r.getVoiceIDFromVideo(ctx, opts.VideoPath, opts.VoiceSamplePath, selectedMatches[0])
+	plan.voiceID = opts.VoiceID
+	if plan.voiceID == "" {
+		plan.voiceID, err = r.getVoiceIDFromVideo(ctx, opts.VideoPath, opts.VoiceSamplePath, plan.matches[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get voice ID: %w", err)
+		}
+	}
+	return plan, nil
+}
+
+// loadTranscript loads the given transcript or transcribes the input
+func (r *ReplacementOperations) loadTranscript(ctx context.Context, videoPath, transcriptPath string) (*transcript.Transcript, error) {
+	if transcriptPath != "" {
+		trans, err := r.trans.LoadTranscript(transcriptPath)
 		if err != nil {
-			return fmt.Errorf("failed to get voice ID: %w", err)
+			return nil, fmt.Errorf("failed to load transcript: %w", err)
 		}
+		return trans, nil
 	}
+	trans, err := r.trans.ExtractTranscript(ctx, videoPath, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract transcript: %w", err)
+	}
+	return trans, nil
+}
+
+// selectMatches returns the match numbers to replace: all of them for
+// index -1, otherwise just the one
+func selectMatches(count, index int) ([]int, error) {
+	if index == -1 {
+		indexes := make([]int, count)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes, nil
+	}
+	if index < 0 || index >= count {
+		return nil, fmt.Errorf("match index %d out of range (found %d matches)", index, count)
+	}
+	return []int{index}, nil
+}
+
+// ReplaceWord is the main entry point for word replacement
+func (r *ReplacementOperations) ReplaceWord(ctx context.Context, opts ReplaceOptions) error {
+	plan, err := r.plan(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	var replacements []plannedReplacement
+	for _, match := range plan.matches {
+		replacements = append(replacements, plannedReplacement{Search: opts.SearchText, Replace: opts.ReplacementText, Match: match})
+	}
+	return r.render(ctx, opts.VideoPath, opts.OutputPath, plan.voiceID, replacements)
+}
 
+// plannedReplacement is one match and the text spoken in its place
+type plannedReplacement struct {
+	Search  string
+	Replace string
+	Match   transcript.Match
+}
+
+// render splices every replacement into the input's audio and writes the
+// output, re-muxing with the original picture for video
+func (r *ReplacementOperations) render(ctx context.Context, input, output, voiceID string, replacements []plannedReplacement) error {
 	// Step 4: Create temporary directory for processing
-	tempDir, err := os.MkdirTemp("", "word-replacement-*")
+	tempDir, cleanup, err := scratch.Dir("word-replacement")
 	if err != nil {
-		return fmt.Errorf("failed to create temp directory: %w", err)
+		return err
 	}
-	defer os.RemoveAll(tempDir)
+	defer cleanup()
 
 	// Step 5: Extract audio from video
 	audioPath := filepath.Join(tempDir, "original_audio.mp3")
 	err = r.videoOps.ExtractAudio(ctx, video.ExtractAudioOptions{
-		Input:  opts.VideoPath,
+		Input:  input,
 		Output: audioPath,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to extract audio: %w", err)
 	}
 
-	// Step 6: Replace each selected match
-	currentAudioPath := audioPath
-	for i, match := range selectedMatches {
-		// Generate TTS for replacement text
-		ttsPath := filepath.Join(tempDir, fmt.Sprintf("tts_%d.mp3", i))
+	// Step 6: Generate TTS once per replacement text
+	ttsPaths := map[string]string{}
+	for _, replacement := range replacements {
+		if _, ok := ttsPaths[replacement.Replace]; ok {
+			continue
+		}
+		ttsPath := filepath.Join(tempDir, fmt.Sprintf("tts_%d.mp3", len(ttsPaths)))
 		err = r.tts.GenerateSpeech(ctx, SpeechOptions{
-			Text:    opts.ReplacementText,
-			VoiceID: voiceID,
+			Text:     replacement.Replace,
+			Provider: TTSProviderElevenLabs, // voiceID is an ElevenLabs clone
+			VoiceID:  voiceID,
 		}, ttsPath)
 		if err != nil {
-			return fmt.Errorf("failed to generate TTS: %w", err)
+			return fmt.Errorf("failed to generate TTS for '%s': %w", replacement.Replace, err)
 		}
+		ttsPaths[replacement.Replace] = ttsPath
+	}
 
-		// Replace the audio segment
+	// Step 7: Replace each match, latest first, so a replacement that is
+	// longer or shorter than the original never shifts the ones still to do
+	ordered := slices.Clone(replacements)
+	slices.SortFunc(ordered, func(a, b plannedReplacement) int { return cmp.Compare(b.Match.Start, a.Match.Start) })
+	currentAudioPath := audioPath
+	for i, replacement := range ordered {
 		nextAudioPath := filepath.Join(tempDir, fmt.Sprintf("replaced_%d.mp3", i))
 		err = r.splice.ReplaceSegment(ctx, SpliceOptions{
 			InputAudio:      currentAudioPath,
 			OutputAudio:     nextAudioPath,
-			ReplacementPath: ttsPath,
-			StartTime:       match.Start,
-			EndTime:         match.End,
+			ReplacementPath: ttsPaths[replacement.Replace],
+			StartTime:       replacement.Match.Start,
+			EndTime:         replacement.Match.End,
 			CrossfadeDur:    0.05, // 50ms
 		})
 		if err != nil {
@@ -133,22 +220,22 @@ func (r *ReplacementOperations) ReplaceWord(ctx context.Context, opts ReplaceOpt
 		currentAudioPath = nextAudioPath
 	}
 
-	// Step 7: Determine if input is video or audio
-	isVideo, err := r.isVideoFile(ctx, opts.VideoPath)
+	// Step 8: Determine if input is video or audio
+	isVideo, err := r.isVideoFile(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to determine file type: %w", err)
 	}
 
-	// Step 8: Handle output based on file type
+	// Step 9: Handle output based on file type
 	if isVideo {
 		// Re-mux replaced audio with original video
-		err = r.remuxVideoWithAudio(ctx, opts.VideoPath, currentAudioPath, opts.OutputPath)
+		err = r.remuxVideoWithAudio(ctx, input, currentAudioPath, output)
 		if err != nil {
 			return fmt.Errorf("failed to remux video: %w", err)
 		}
 	} else {
 		// Just copy the replaced audio
-		err = r.copyFile(currentAudioPath, opts.OutputPath)
+		err = r.copyFile(currentAudioPath, output)
 		if err != nil {
 			return fmt.Errorf("failed to copy output: %w", err)
 		}
@@ -157,6 +244,141 @@ func (r *ReplacementOperations) ReplaceWord(ctx context.Context, opts ReplaceOpt
 	return nil
 }
 
+// PreviewReplacement renders only the region around each match, so the
+// result can be checked before ReplaceWord renders the whole file. Clips
+// are left in a scratch directory along with the transcript, which
+// cleanup_temp removes once they are old enough.
+func (r *ReplacementOperations) PreviewReplacement(ctx context.Context, opts ReplaceOptions) (preview *ReplacementPreview, err error) {
+	plan, err := r.plan(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	padding := opts.PreviewPadding
+	if padding == 0 {
+		padding = 3
+	}
+
+	previewDir, cleanup, err := scratch.Dir("word-replacement-preview")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			cleanup()
+		} else {
+			scratch.Release(previewDir)
+		}
+	}()
+	workDir := filepath.Join(previewDir, "work")
+	if err := os.Mkdir(workDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create preview directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	// Keep the transcript so the approved render skips transcription
+	preview = &ReplacementPreview{VoiceID: plan.voiceID, TranscriptPath: opts.TranscriptPath}
+	if preview.TranscriptPath == "" {
+		preview.TranscriptPath = filepath.Join(previewDir, "transcript.json")
+		if err := r.trans.SaveTranscript(plan.transcript, preview.TranscriptPath); err != nil {
+			return nil, fmt.Errorf("failed to save transcript: %w", err)
+		}
+	}
+
+	info, err := r.videoOps.GetVideoInfo(ctx, opts.VideoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine file type: %w", err)
+	}
+	isVideo := info.Width > 0 && info.Height > 0
+	ffmpegMgr := r.videoOps.GetFFmpegManager()
+
+	// Every clip speaks the same text, so it is generated once
+	ttsPath := filepath.Join(workDir, "tts.mp3")
+	err = r.tts.GenerateSpeech(ctx, SpeechOptions{
+		Text:     opts.ReplacementText,
+		Provider: TTSProviderElevenLabs, // voiceID is an ElevenLabs clone
+		VoiceID:  plan.voiceID,
+	}, ttsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate TTS: %w", err)
+	}
+
+	for i, match := range plan.matches {
+		clip := previewClip(match, plan.indexes[i], padding, info.Duration)
+
+		// Cut the original audio around the match
+		regionPath := filepath.Join(workDir, fmt.Sprintf("region_%d.mp3", i))
+		err = ffmpegMgr.Execute(ctx,
+			"-ss", fmt.Sprintf("%.3f", clip.ClipStart),
+			"-to", fmt.Sprintf("%.3f", clip.ClipEnd),
+			"-i", opts.VideoPath,
+			"-vn",
+			"-c:a", "libmp3lame",
+			"-y", regionPath,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract preview audio: %w", err)
+		}
+
+		// Splice at the match's position within the region
+		splicedPath := filepath.Join(workDir, fmt.Sprintf("spliced_%d.mp3", i))
+		err = r.splice.ReplaceSegment(ctx, SpliceOptions{
+			InputAudio:      regionPath,
+			OutputAudio:     splicedPath,
+			ReplacementPath: ttsPath,
+			StartTime:       match.Start - clip.ClipStart,
+			EndTime:         match.End - clip.ClipStart,
+			CrossfadeDur:    0.05,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to splice audio: %w", err)
+		}
+
+		if isVideo {
+			clip.Path = filepath.Join(previewDir, fmt.Sprintf("preview_%d.mp4", clip.MatchIndex))
+			err = ffmpegMgr.Execute(ctx,
+				"-ss", fmt.Sprintf("%.3f", clip.ClipStart),
+				"-to", fmt.Sprintf("%.3f", clip.ClipEnd),
+				"-i", opts.VideoPath,
+				"-i", splicedPath,
+				"-map", "0:v:0",
+				"-map", "1:a:0",
+				"-c:v", "libx264",
+				"-preset", "veryfast",
+				"-pix_fmt", "yuv420p",
+				"-c:a", "aac",
+				"-shortest",
+				"-y", clip.Path,
+			)
+		} else {
+			clip.Path = filepath.Join(previewDir, fmt.Sprintf("preview_%d.mp3", clip.MatchIndex))
+			err = r.copyFile(splicedPath, clip.Path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to render preview: %w", err)
+		}
+		preview.Clips = append(preview.Clips, clip)
+	}
+
+	return preview, nil
+}
+
+// previewClip is the region padding seconds either side of a match,
+// clamped to the start and, when it is known, the end of the input
+func previewClip(match transcript.Match, index int, padding, duration float64) PreviewClip {
+	clip := PreviewClip{
+		MatchIndex: index,
+		Text:       match.Text,
+		Start:      match.Start,
+		End:        match.End,
+		ClipStart:  math.Max(0, match.Start-padding),
+		ClipEnd:    match.End + padding,
+	}
+	if duration > 0 {
+		clip.ClipEnd = math.Min(duration, clip.ClipEnd)
+	}
+	return clip
+}
+
 // getVoiceIDFromVideo extracts voice sample and clones voice
 func (r *ReplacementOperations) getVoiceIDFromVideo(ctx context.Context, videoPath string, voiceSamplePath string, match transcript.Match) (string, error) {
 	// If voice sample path is provided, use it
@@ -165,11 +387,11 @@ func (r *ReplacementOperations) getVoiceIDFromVideo(ctx context.Context, videoPa
 	}
 
 	// Extract audio sample from video (30-60 seconds around the target word)
-	tempDir, err := os.MkdirTemp("", "voice-sample-*")
+	tempDir, cleanup, err := scratch.Dir("voice-sample")
 	if err != nil {
 		return "", err
 	}
-	defer os.RemoveAll(tempDir)
+	defer cleanup()
 
 	// Get video info to determine duration
 	info, err := r.videoOps.GetVideoInfo(ctx, videoPath)
@@ -0,0 +1,133 @@
+package audio
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+)
+
+// WordReplacement is one find/replace pair
+type WordReplacement struct {
+	Search     string `json:"search"`
+	Replace    string `json:"replace"`
+	MatchIndex *int   `json:"matchIndex,omitempty"` // Which occurrence to replace (default: all)
+}
+
+// BatchReplaceOptions contains parameters for replacing several words in one pass
+type BatchReplaceOptions struct {
+	VideoPath       string
+	TranscriptPath  string // optional, will generate if not provided
+	Replacements    []WordReplacement
+	VoiceSamplePath string // optional, will extract from video
+	VoiceID         string // optional, reuse existing voice
+	SkipMissing     bool   // Report pairs that are not found instead of failing
+	OutputPath      string
+}
+
+// ReplacedMatch is one spoken match that was replaced
+type ReplacedMatch struct {
+	Search  string  `json:"search"`
+	Replace string  `json:"replace"`
+	Text    string  `json:"text"` // Matched text
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+}
+
+// BatchReplaceResult summarizes a batch replacement
+type BatchReplaceResult struct {
+	VoiceID  string          `json:"voiceId"`
+	Replaced []ReplacedMatch `json:"replaced"`
+	Missing  []string        `json:"missing,omitempty"` // Searches not found, with SkipMissing
+}
+
+// ReplaceWords replaces several words or phrases with one transcript, one
+// cloned voice and one render, rather than running ReplaceWord per pair
+func (r *ReplacementOperations) ReplaceWords(ctx context.Context, opts BatchReplaceOptions) (*BatchReplaceResult, error) {
+	if len(opts.Replacements) == 0 {
+		return nil, fmt.Errorf("no replacements given")
+	}
+
+	trans, err := r.loadTranscript(ctx, opts.VideoPath, opts.TranscriptPath)
+	if err != nil {
+		return nil, err
+	}
+	find := func(search string) []transcript.Match {
+		return r.trans.FindInTranscript(trans, search)
+	}
+	replacements, missing, err := planBatchReplacements(opts.Replacements, find, opts.SkipMissing)
+	if err != nil {
+		return nil, err
+	}
+	if len(replacements) == 0 {
+		return nil, fmt.Errorf("none of the words were found in the transcript")
+	}
+
+	voiceID := opts.VoiceID
+	if voiceID == "" {
+		voiceID, err = r.getVoiceIDFromVideo(ctx, opts.VideoPath, opts.VoiceSamplePath, replacements[0].Match)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get voice ID: %w", err)
+		}
+	}
+
+	if err := r.render(ctx, opts.VideoPath, opts.OutputPath, voiceID, replacements); err != nil {
+		return nil, err
+	}
+
+	result := &BatchReplaceResult{VoiceID: voiceID, Missing: missing}
+	for _, replacement := range replacements {
+		result.Replaced = append(result.Replaced, ReplacedMatch{
+			Search:  replacement.Search,
+			Replace: replacement.Replace,
+			Text:    replacement.Match.Text,
+			Start:   replacement.Match.Start,
+			End:     replacement.Match.End,
+		})
+	}
+	return result, nil
+}
+
+// planBatchReplacements finds the matches for every pair, in time order.
+// Matches from different pairs may not overlap.
+func planBatchReplacements(pairs []WordReplacement, find func(string) []transcript.Match, skipMissing bool) ([]plannedReplacement, []string, error) {
+	var replacements []plannedReplacement
+	var missing []string
+	for _, pair := range pairs {
+		if pair.Search == "" || pair.Replace == "" {
+			return nil, nil, fmt.Errorf("each replacement needs search and replace text")
+		}
+		matches := find(pair.Search)
+		if len(matches) == 0 {
+			if !skipMissing {
+				return nil, nil, fmt.Errorf("word/phrase '%s' not found in transcript", pair.Search)
+			}
+			missing = append(missing, pair.Search)
+			continue
+		}
+
+		index := -1
+		if pair.MatchIndex != nil {
+			index = *pair.MatchIndex
+		}
+		indexes, err := selectMatches(len(matches), index)
+		if err != nil {
+			return nil, nil, fmt.Errorf("'%s': %w", pair.Search, err)
+		}
+		for _, i := range indexes {
+			replacements = append(replacements, plannedReplacement{Search: pair.Search, Replace: pair.Replace, Match: matches[i]})
+		}
+	}
+
+	slices.SortFunc(replacements, func(a, b plannedReplacement) int { return cmp.Compare(a.Match.Start, b.Match.Start) })
+	for i := 1; i < len(replacements); i++ {
+		prev, next := replacements[i-1], replacements[i]
+		if next.Match.Start < prev.Match.End {
+			return nil, nil, fmt.Errorf("'%s' and '%s' overlap at %.2fs; drop one of them or pick a matchIndex",
+				prev.Search, next.Search, next.Match.Start)
+		}
+	}
+	return replacements, missing, nil
+}
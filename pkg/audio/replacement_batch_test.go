@@ -0,0 +1,62 @@
+package audio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+)
+
+func TestPlanBatchReplacements(t *testing.T) {
+	found := map[string][]transcript.Match{
+		"Acme":     {{Text: "Acme", Start: 4, End: 4.5}, {Text: "acme", Start: 20, End: 20.4}},
+		"Tuesday":  {{Text: "Tuesday", Start: 10, End: 10.6}},
+		"New York": {{Text: "New York", Start: 30, End: 30.8}},
+		"York":     {{Text: "York", Start: 30.4, End: 30.8}},
+	}
+	find := func(search string) []transcript.Match { return found[search] }
+	second := 1
+
+	replacements, missing, err := planBatchReplacements([]WordReplacement{
+		{Search: "Acme", Replace: "Globex"},
+		{Search: "Tuesday", Replace: "Thursday"},
+	}, find, false)
+	if err != nil {
+		t.Fatalf("planBatchReplacements() error = %v", err)
+	}
+	if len(replacements) != 3 || len(missing) != 0 {
+		t.Fatalf("Expected 3 replacements, got %+v (missing %v)", replacements, missing)
+	}
+	// Sorted by time across pairs
+	if replacements[0].Match.Start != 4 || replacements[1].Replace != "Thursday" || replacements[2].Match.Start != 20 {
+		t.Errorf("Unexpected order: %+v", replacements)
+	}
+
+	replacements, missing, err = planBatchReplacements([]WordReplacement{
+		{Search: "Acme", Replace: "Initech", MatchIndex: &second},
+		{Search: "Berlin", Replace: "Paris"},
+	}, find, true)
+	if err != nil {
+		t.Fatalf("planBatchReplacements() error = %v", err)
+	}
+	if len(replacements) != 1 || replacements[0].Match.Start != 20 {
+		t.Errorf("Expected only the second Acme, got %+v", replacements)
+	}
+	if len(missing) != 1 || missing[0] != "Berlin" {
+		t.Errorf("Expected Berlin reported missing, got %v", missing)
+	}
+
+	for name, tt := range map[string]struct {
+		pairs   []WordReplacement
+		wantErr string
+	}{
+		"missing":     {[]WordReplacement{{Search: "Berlin", Replace: "Paris"}}, "not found"},
+		"overlap":     {[]WordReplacement{{Search: "New York", Replace: "Boston"}, {Search: "York", Replace: "Jork"}}, "overlap"},
+		"empty":       {[]WordReplacement{{Search: "Acme"}}, "search and replace"},
+		"index range": {[]WordReplacement{{Search: "Tuesday", Replace: "Friday", MatchIndex: &second}}, "out of range"},
+	} {
+		if _, _, err := planBatchReplacements(tt.pairs, find, false); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", name, err, tt.wantErr)
+		}
+	}
+}
@@ -177,3 +177,35 @@ func TestReplaceWord(t *testing.T) {
 	t.Log("Word replacement workflow components verified successfully")
 	t.Log("Note: Full end-to-end test with real speech would require a test video with spoken words")
 }
+
+func TestSelectMatches(t *testing.T) {
+	all, err := selectMatches(3, -1)
+	if err != nil || len(all) != 3 || all[0] != 0 || all[2] != 2 {
+		t.Errorf("selectMatches(3, -1) = %v, %v", all, err)
+	}
+	one, err := selectMatches(3, 1)
+	if err != nil || len(one) != 1 || one[0] != 1 {
+		t.Errorf("selectMatches(3, 1) = %v, %v", one, err)
+	}
+	for _, index := range []int{3, -2} {
+		if _, err := selectMatches(3, index); err == nil {
+			t.Errorf("selectMatches(3, %d) should fail", index)
+		}
+	}
+}
+
+func TestPreviewClip(t *testing.T) {
+	match := transcript.Match{Text: "Acme", Start: 1, End: 1.5}
+	clip := previewClip(match, 2, 3, 60)
+	if clip.MatchIndex != 2 || clip.Text != "Acme" || clip.ClipStart != 0 || clip.ClipEnd != 4.5 {
+		t.Errorf("clip near the start = %+v", clip)
+	}
+
+	match = transcript.Match{Start: 58, End: 59}
+	if clip := previewClip(match, 0, 3, 60); clip.ClipStart != 55 || clip.ClipEnd != 60 {
+		t.Errorf("clip near the end = %+v", clip)
+	}
+	if clip := previewClip(match, 0, 3, 0); clip.ClipEnd != 62 {
+		t.Errorf("clip with an unknown duration = %+v", clip)
+	}
+}
@@ -0,0 +1,330 @@
+package audio
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Speech markup formats
+const (
+	MarkupNone   = "none"   // Plain text
+	MarkupSimple = "simple" // [pause 500ms], *emphasis*, {word|respelling} and {word|/ipa/}
+	MarkupSSML   = "ssml"   // <break>, <emphasis>, <phoneme> and <sub>
+)
+
+// speechSpan is a piece of a speech script: text with optional delivery
+// hints, or a pause
+type speechSpan struct {
+	Text     string
+	Pause    float64 // Seconds of silence; Text is empty
+	Emphasis bool
+	Say      string // Respelling spoken in place of Text
+	IPA      string // IPA pronunciation of Text
+}
+
+// parseSpeechMarkup splits text into spans. An empty format reads SSML when
+// the text starts with <speak> and plain text otherwise.
+func parseSpeechMarkup(text, format string) ([]speechSpan, error) {
+	if format == "" {
+		format = MarkupNone
+		if strings.HasPrefix(strings.TrimSpace(text), "<speak") {
+			format = MarkupSSML
+		}
+	}
+	switch format {
+	case MarkupNone:
+		return []speechSpan{{Text: text}}, nil
+	case MarkupSimple:
+		return parseSimpleMarkup(text)
+	case MarkupSSML:
+		return parseSSML(text)
+	default:
+		return nil, fmt.Errorf("unknown markup: %s (use none, simple or ssml)", format)
+	}
+}
+
+var simpleMarkup = regexp.MustCompile(`(?i)\[pause\s+([\d.]+)\s*(ms|s)?\]|\*([^*\n]+)\*|\{([^|{}]+)\|([^{}]+)\}`)
+
+// parseSimpleMarkup reads [pause 500ms] or [pause 1.5s] pauses, *emphasis*
+// and {word|respelling} or {word|/ipa/} pronunciations
+func parseSimpleMarkup(text string) ([]speechSpan, error) {
+	var spans []speechSpan
+	last := 0
+	for _, m := range simpleMarkup.FindAllStringSubmatchIndex(text, -1) {
+		if m[0] > last {
+			spans = append(spans, speechSpan{Text: text[last:m[0]]})
+		}
+		group := func(i int) string {
+			if m[2*i] < 0 {
+				return ""
+			}
+			return text[m[2*i]:m[2*i+1]]
+		}
+		switch {
+		case m[2] >= 0:
+			seconds, err := strconv.ParseFloat(group(1), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pause: %s", text[m[0]:m[1]])
+			}
+			if strings.EqualFold(group(2), "ms") {
+				seconds /= 1000
+			}
+			spans = append(spans, speechSpan{Pause: seconds})
+		case m[6] >= 0:
+			spans = append(spans, speechSpan{Text: group(3), Emphasis: true})
+		default:
+			spans = append(spans, pronounced(group(4), strings.TrimSpace(group(5))))
+		}
+		last = m[1]
+	}
+	if last < len(text) {
+		spans = append(spans, speechSpan{Text: text[last:]})
+	}
+	return spans, nil
+}
+
+// pronounced returns a span for text said as pronunciation, which is IPA
+// when wrapped in slashes and a respelling otherwise
+func pronounced(text, pronunciation string) speechSpan {
+	if len(pronunciation) > 2 && strings.HasPrefix(pronunciation, "/") && strings.HasSuffix(pronunciation, "/") {
+		return speechSpan{Text: text, IPA: pronunciation[1 : len(pronunciation)-1]}
+	}
+	return speechSpan{Text: text, Say: pronunciation}
+}
+
+// breakStrengths are SSML break strengths in seconds
+var breakStrengths = map[string]float64{
+	"none": 0, "x-weak": 0.1, "weak": 0.25, "medium": 0.5, "strong": 0.75, "x-strong": 1,
+}
+
+// parseSSML reads the SSML elements that matter for delivery. Other
+// elements are kept as plain text.
+func parseSSML(text string) ([]speechSpan, error) {
+	decoder := xml.NewDecoder(strings.NewReader(text))
+	var spans []speechSpan
+	var emphasis int
+	var current *speechSpan // Open <phoneme> or <sub>
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid SSML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "break":
+				pause, err := ssmlBreak(t)
+				if err != nil {
+					return nil, err
+				}
+				spans = append(spans, speechSpan{Pause: pause})
+			case "emphasis":
+				if attr(t, "level") != "none" && attr(t, "level") != "reduced" {
+					emphasis++
+				}
+			case "phoneme":
+				current = &speechSpan{Emphasis: emphasis > 0}
+				if alphabet := attr(t, "alphabet"); alphabet == "" || alphabet == "ipa" {
+					current.IPA = attr(t, "ph")
+				}
+			case "sub":
+				current = &speechSpan{Emphasis: emphasis > 0, Say: attr(t, "alias")}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "emphasis":
+				if emphasis > 0 {
+					emphasis--
+				}
+			case "phoneme", "sub":
+				if current != nil {
+					current.Text = strings.TrimSpace(current.Text)
+					spans = append(spans, *current)
+					current = nil
+				}
+			case "p", "s":
+				spans = append(spans, speechSpan{Text: " "})
+			}
+		case xml.CharData:
+			if current != nil {
+				current.Text += string(t)
+			} else if s := collapseSpace(string(t)); s != "" {
+				spans = append(spans, speechSpan{Text: s, Emphasis: emphasis > 0})
+			}
+		}
+	}
+	return spans, nil
+}
+
+// ssmlBreak returns the length of a <break> in seconds
+func ssmlBreak(t xml.StartElement) (float64, error) {
+	if value := attr(t, "time"); value != "" {
+		unit := 1.0
+		switch {
+		case strings.HasSuffix(value, "ms"):
+			value, unit = strings.TrimSuffix(value, "ms"), 0.001
+		case strings.HasSuffix(value, "s"):
+			value = strings.TrimSuffix(value, "s")
+		}
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil || seconds < 0 {
+			return 0, fmt.Errorf("invalid break time: %s", attr(t, "time"))
+		}
+		return seconds * unit, nil
+	}
+	if strength, ok := breakStrengths[attr(t, "strength")]; ok {
+		return strength, nil
+	}
+	return breakStrengths["medium"], nil
+}
+
+// attr returns an attribute of an element
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+var spaceRun = regexp.MustCompile(`\s+`)
+
+// collapseSpace turns runs of whitespace into single spaces
+func collapseSpace(s string) string {
+	if strings.TrimSpace(s) == "" {
+		if s == "" {
+			return ""
+		}
+		return " "
+	}
+	return spaceRun.ReplaceAllString(s, " ")
+}
+
+// applyPronunciations replaces dictionary words in plain spans with
+// pronounced spans. Entries are word -> respelling, or word -> /ipa/.
+func applyPronunciations(spans []speechSpan, dictionary map[string]string) []speechSpan {
+	if len(dictionary) == 0 {
+		return spans
+	}
+	words := make([]string, 0, len(dictionary))
+	lookup := map[string]string{}
+	for word, pronunciation := range dictionary {
+		words = append(words, regexp.QuoteMeta(word))
+		lookup[strings.ToLower(word)] = pronunciation
+	}
+	// Longest first so phrases win over the words inside them
+	sort.Slice(words, func(i, j int) bool { return len(words[i]) > len(words[j]) })
+	pattern := regexp.MustCompile(`(?i)\b(?:` + strings.Join(words, "|") + `)\b`)
+
+	var out []speechSpan
+	for _, span := range spans {
+		if span.Pause > 0 || span.Say != "" || span.IPA != "" {
+			out = append(out, span)
+			continue
+		}
+		last := 0
+		for _, m := range pattern.FindAllStringIndex(span.Text, -1) {
+			if m[0] > last {
+				out = append(out, speechSpan{Text: span.Text[last:m[0]], Emphasis: span.Emphasis})
+			}
+			word := span.Text[m[0]:m[1]]
+			p := pronounced(word, lookup[strings.ToLower(word)])
+			p.Emphasis = span.Emphasis
+			out = append(out, p)
+			last = m[1]
+		}
+		if last < len(span.Text) {
+			out = append(out, speechSpan{Text: span.Text[last:], Emphasis: span.Emphasis})
+		}
+	}
+	return out
+}
+
+// elevenLabsMaxBreak is the longest pause ElevenLabs honours in one tag
+const elevenLabsMaxBreak = 3.0
+
+// renderSpeech turns spans into text for a provider, using the provider's
+// own syntax where it has one. Pauses are only rendered inline for
+// ElevenLabs; callers split on them for other providers. It also returns
+// the emphasized words, for providers that take delivery instructions.
+func renderSpeech(spans []speechSpan, provider string) (string, []string) {
+	var b strings.Builder
+	var emphasized []string
+	for _, span := range spans {
+		if span.Pause > 0 {
+			if provider == TTSProviderElevenLabs {
+				for remaining := span.Pause; remaining > 0.001; remaining -= elevenLabsMaxBreak {
+					b.WriteString(fmt.Sprintf(`<break time="%.2fs" />`, min(remaining, elevenLabsMaxBreak)))
+				}
+			} else {
+				b.WriteString("... ")
+			}
+			continue
+		}
+
+		text := span.Text
+		switch {
+		case span.IPA != "" && provider == TTSProviderElevenLabs:
+			text = fmt.Sprintf(`<phoneme alphabet="ipa" ph="%s">%s</phoneme>`, xmlEscape(span.IPA), xmlEscape(span.Text))
+		case span.IPA != "" && provider == TTSProviderPiper:
+			text = fmt.Sprintf("[[ %s ]]", span.IPA)
+		case span.Say != "":
+			text = span.Say
+		}
+		if span.Emphasis && strings.TrimSpace(text) != "" {
+			emphasized = append(emphasized, strings.TrimSpace(text))
+		}
+		b.WriteString(text)
+	}
+	return b.String(), emphasized
+}
+
+// splitPauses groups spans into runs of speech separated by pauses. Each
+// entry is either speech spans or a single pause span.
+func splitPauses(spans []speechSpan) [][]speechSpan {
+	var groups [][]speechSpan
+	var run []speechSpan
+	for _, span := range spans {
+		if span.Pause > 0 {
+			if len(run) > 0 {
+				groups = append(groups, run)
+				run = nil
+			}
+			groups = append(groups, []speechSpan{span})
+			continue
+		}
+		run = append(run, span)
+	}
+	if len(run) > 0 {
+		groups = append(groups, run)
+	}
+	return groups
+}
+
+// hasPauses reports whether any span is a pause
+func hasPauses(spans []speechSpan) bool {
+	for _, span := range spans {
+		if span.Pause > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// xmlEscape escapes text for an XML attribute or element
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,147 @@
+package audio
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSimpleMarkup(t *testing.T) {
+	spans, err := parseSpeechMarkup("Hello [pause 500ms] *world*, I run {nginx|engine x} on {Qi|/tʃiː/}.[Pause 1.5s]", MarkupSimple)
+	if err != nil {
+		t.Fatalf("parseSpeechMarkup() error = %v", err)
+	}
+	want := []speechSpan{
+		{Text: "Hello "},
+		{Pause: 0.5},
+		{Text: " "},
+		{Text: "world", Emphasis: true},
+		{Text: ", I run "},
+		{Text: "nginx", Say: "engine x"},
+		{Text: " on "},
+		{Text: "Qi", IPA: "tʃiː"},
+		{Text: "."},
+		{Pause: 1.5},
+	}
+	if !reflect.DeepEqual(spans, want) {
+		t.Errorf("parseSpeechMarkup() =\n%+v\nwant\n%+v", spans, want)
+	}
+}
+
+func TestParseSSML(t *testing.T) {
+	ssml := `<speak>
+  Welcome to <emphasis level="strong">the show</emphasis>.
+  <break time="750ms"/>
+  I'm <phoneme alphabet="ipa" ph="ˈsiːən">Sian</phoneme>, from the <sub alias="World Wide Web">WWW</sub>.
+  <break strength="x-strong"/>
+</speak>`
+
+	// Markup is detected from the <speak> root
+	spans, err := parseSpeechMarkup(ssml, "")
+	if err != nil {
+		t.Fatalf("parseSpeechMarkup() error = %v", err)
+	}
+	want := []speechSpan{
+		{Text: " Welcome to "},
+		{Text: "the show", Emphasis: true},
+		{Text: ". "},
+		{Pause: 0.75},
+		{Text: " I'm "},
+		{Text: "Sian", IPA: "ˈsiːən"},
+		{Text: ", from the "},
+		{Text: "WWW", Say: "World Wide Web"},
+		{Text: ". "},
+		{Pause: 1},
+		{Text: " "},
+	}
+	if !reflect.DeepEqual(spans, want) {
+		t.Errorf("parseSpeechMarkup() =\n%+v\nwant\n%+v", spans, want)
+	}
+
+	if _, err := parseSpeechMarkup(`<speak><break time="soon"/></speak>`, MarkupSSML); err == nil || !strings.Contains(err.Error(), "break time") {
+		t.Errorf("expected break time error, got %v", err)
+	}
+	if _, err := parseSpeechMarkup(`<speak>unclosed`, MarkupSSML); err == nil {
+		t.Error("expected error for malformed SSML")
+	}
+	if _, err := parseSpeechMarkup("hi", "markdown"); err == nil {
+		t.Error("expected error for unknown markup")
+	}
+}
+
+func TestApplyPronunciations(t *testing.T) {
+	dictionary := map[string]string{
+		"nginx":    "engine x",
+		"SQL":      "sequel",
+		"SQL Lite": "sequel light",
+		"Qi":       "/tʃiː/",
+	}
+	spans := []speechSpan{
+		{Text: "Use sql lite or SQL with nginx"},
+		{Pause: 1},
+		{Text: "Qi", Say: "chee"}, // Explicit markup wins
+		{Text: " on qis", Emphasis: true},
+	}
+	want := []speechSpan{
+		{Text: "Use "},
+		{Text: "sql lite", Say: "sequel light"},
+		{Text: " or "},
+		{Text: "SQL", Say: "sequel"},
+		{Text: " with "},
+		{Text: "nginx", Say: "engine x"},
+		{Pause: 1},
+		{Text: "Qi", Say: "chee"},
+		{Text: " on qis", Emphasis: true},
+	}
+	if got := applyPronunciations(spans, dictionary); !reflect.DeepEqual(got, want) {
+		t.Errorf("applyPronunciations() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestRenderSpeech(t *testing.T) {
+	spans := []speechSpan{
+		{Text: "Hello "},
+		{Text: "Qi", IPA: "tʃiː"},
+		{Pause: 4},
+		{Text: " meet "},
+		{Text: "nginx", Say: "engine x", Emphasis: true},
+	}
+
+	tests := map[string]string{
+		TTSProviderElevenLabs: `Hello <phoneme alphabet="ipa" ph="tʃiː">Qi</phoneme><break time="3.00s" /><break time="1.00s" /> meet engine x`,
+		TTSProviderOpenAI:     "Hello Qi...  meet engine x",
+		TTSProviderPiper:      "Hello [[ tʃiː ]]...  meet engine x",
+	}
+	for provider, want := range tests {
+		text, emphasized := renderSpeech(spans, provider)
+		if text != want {
+			t.Errorf("renderSpeech(%s) = %q, want %q", provider, text, want)
+		}
+		if !reflect.DeepEqual(emphasized, []string{"engine x"}) {
+			t.Errorf("renderSpeech(%s) emphasized = %v, want [engine x]", provider, emphasized)
+		}
+	}
+
+	opts := renderOptions(SpeechOptions{Instructions: "Calm."}, spans[3:], TTSProviderOpenAI)
+	if opts.Text != "meet engine x" || opts.Instructions != "Calm. Stress these words: engine x." {
+		t.Errorf("renderOptions() = %q / %q", opts.Text, opts.Instructions)
+	}
+}
+
+func TestSplitPausesAndJoin(t *testing.T) {
+	spans := []speechSpan{{Pause: 0.25}, {Text: "One "}, {Text: "two"}, {Pause: 1}, {Text: "three"}}
+	groups := splitPauses(spans)
+	if len(groups) != 4 || len(groups[1]) != 2 || groups[2][0].Pause != 1 {
+		t.Fatalf("splitPauses() = %+v", groups)
+	}
+
+	filter := joinPiecesFilter([]string{"pause:0.250", "a.wav", "pause:1.000", "b.wav"})
+	want := "anullsrc=r=44100:cl=mono,atrim=duration=0.250[p0];" +
+		"[0:a]aresample=44100,aformat=channel_layouts=mono[p1];" +
+		"anullsrc=r=44100:cl=mono,atrim=duration=1.000[p2];" +
+		"[1:a]aresample=44100,aformat=channel_layouts=mono[p3];" +
+		"[p0][p1][p2][p3]concat=n=4:v=0:a=1[out]"
+	if filter != want {
+		t.Errorf("joinPiecesFilter() =\n%s\nwant\n%s", filter, want)
+	}
+}
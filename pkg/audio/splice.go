@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
 )
 
 // SpliceOperations handles precise audio splicing with FFmpeg
@@ -39,11 +40,11 @@ func (s *SpliceOperations) ReplaceSegment(ctx context.Context, opts SpliceOption
 	}
 
 	// Create temporary directory for intermediate files
-	tempDir, err := os.MkdirTemp("", "audio-splice-*")
+	tempDir, cleanup, err := scratch.Dir("audio-splice")
 	if err != nil {
-		return fmt.Errorf("failed to create temp directory: %w", err)
+		return err
 	}
-	defer os.RemoveAll(tempDir)
+	defer cleanup()
 
 	// Paths for intermediate files
 	beforePath := filepath.Join(tempDir, "before.mp3")
@@ -0,0 +1,326 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+)
+
+// Stem separation engines
+const (
+	StemEngineAuto     = "auto"     // Demucs, then Spleeter, then FFmpeg
+	StemEngineDemucs   = "demucs"   // Demucs source separation model
+	StemEngineSpleeter = "spleeter" // Spleeter source separation model
+	StemEngineFFmpeg   = "ffmpeg"   // Filters only: voice isolation and center-channel removal
+)
+
+// Stem names
+const (
+	StemVocals        = "vocals"
+	StemAccompaniment = "accompaniment" // Everything but the vocals
+	StemDrums         = "drums"
+	StemBass          = "bass"
+	StemOther         = "other"
+)
+
+// StemOptions contains parameters for separating audio into stems
+type StemOptions struct {
+	Input     string
+	OutputDir string
+	Engine    string // auto (default), demucs, spleeter or ffmpeg
+	Stems     int    // 2 for vocals/accompaniment (default) or 4 for vocals/drums/bass/other
+	Model     string // Demucs model name, or an RNNoise .rnnn model file for ffmpeg
+
+	// Keep and Output rebuild the input with only some stems, e.g. Keep
+	// vocals to remove background music from a clip. Video is copied.
+	Keep   []string
+	Output string
+}
+
+// StemResult describes separated stems
+type StemResult struct {
+	Engine   string            `json:"engine"`
+	Stems    map[string]string `json:"stems"` // Stem name to file
+	Output   string            `json:"output,omitempty"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+// SeparateStems splits the audio of a file into stems such as vocals and
+// accompaniment. Demucs or Spleeter is used when installed; otherwise FFmpeg
+// filters isolate the voice and remove center-panned vocals from the music,
+// which is rougher and only offers two stems.
+func (o *Operations) SeparateStems(ctx context.Context, opts StemOptions) (*StemResult, error) {
+	if opts.Stems == 0 {
+		opts.Stems = 2
+	}
+	if opts.Stems != 2 && opts.Stems != 4 {
+		return nil, fmt.Errorf("stems must be 2 or 4")
+	}
+	if opts.OutputDir == "" {
+		opts.OutputDir = strings.TrimSuffix(opts.Input, filepath.Ext(opts.Input)) + "_stems"
+	}
+	if len(opts.Keep) > 0 && opts.Output == "" {
+		return nil, fmt.Errorf("output is required when keeping stems")
+	}
+	engine, err := resolveStemEngine(opts.Engine, opts.Stems, exec.LookPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	result := &StemResult{Engine: engine}
+	if engine == StemEngineFFmpeg {
+		result.Stems, result.Warnings, err = o.separateWithFilters(ctx, opts)
+	} else {
+		result.Stems, err = o.separateWithModel(ctx, engine, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Keep) > 0 {
+		var keep []string
+		for _, name := range opts.Keep {
+			path, ok := result.Stems[name]
+			if !ok {
+				return nil, fmt.Errorf("stem %s was not produced (have: %s)", name, strings.Join(stemNames(result.Stems), ", "))
+			}
+			keep = append(keep, path)
+		}
+		if err := o.remuxStems(ctx, opts.Input, keep, opts.Output); err != nil {
+			return nil, err
+		}
+		result.Output = opts.Output
+	}
+	return result, nil
+}
+
+// resolveStemEngine picks the engine to use, checking that external tools are
+// installed. lookPath is exec.LookPath outside tests.
+func resolveStemEngine(engine string, stems int, lookPath func(string) (string, error)) (string, error) {
+	switch engine {
+	case "", StemEngineAuto:
+		for _, candidate := range []string{StemEngineDemucs, StemEngineSpleeter} {
+			if _, err := lookPath(candidate); err == nil {
+				return candidate, nil
+			}
+		}
+		if stems != 2 {
+			return "", fmt.Errorf("4 stems need demucs or spleeter installed")
+		}
+		return StemEngineFFmpeg, nil
+	case StemEngineDemucs, StemEngineSpleeter:
+		if _, err := lookPath(engine); err != nil {
+			return "", fmt.Errorf("%s is not installed or not on PATH", engine)
+		}
+		return engine, nil
+	case StemEngineFFmpeg:
+		if stems != 2 {
+			return "", fmt.Errorf("the ffmpeg engine only produces 2 stems")
+		}
+		return engine, nil
+	default:
+		return "", fmt.Errorf("unknown engine: %s (use auto, demucs, spleeter or ffmpeg)", engine)
+	}
+}
+
+// separateWithModel decodes the input to WAV and runs Demucs or Spleeter on it
+func (o *Operations) separateWithModel(ctx context.Context, engine string, opts StemOptions) (map[string]string, error) {
+	tempDir, cleanup, err := scratch.Dir("stems")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	// Both tools read WAV reliably, whatever the input container
+	wavPath := filepath.Join(tempDir, "mix.wav")
+	if err := o.ffmpeg.Execute(ctx, "-i", opts.Input, "-vn", "-ac", "2", "-ar", "44100", "-y", wavPath); err != nil {
+		return nil, fmt.Errorf("failed to extract audio: %w", err)
+	}
+
+	name, args := stemCommand(engine, opts.Stems, opts.Model, wavPath, tempDir)
+	cmd := exec.CommandContext(ctx, name, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%s failed: %w\n%s", engine, err, tailLines(string(output), 10))
+	}
+
+	stems := map[string]string{}
+	for stem, produced := range stemFiles(engine, opts.Stems, opts.Model, "mix", tempDir) {
+		path := filepath.Join(opts.OutputDir, stem+".wav")
+		if err := os.Rename(produced, path); err != nil {
+			// Temp dirs may be on another device
+			if err := o.copyFile(produced, path); err != nil {
+				return nil, fmt.Errorf("failed to save %s stem: %w", stem, err)
+			}
+		}
+		stems[stem] = path
+	}
+	return stems, nil
+}
+
+// stemCommand returns the command line that separates input into outDir
+func stemCommand(engine string, stems int, model, input, outDir string) (string, []string) {
+	if engine == StemEngineSpleeter {
+		return "spleeter", []string{"separate", "-p", fmt.Sprintf("spleeter:%dstems", stems), "-o", outDir, input}
+	}
+	args := []string{"-n", demucsModel(model), "-o", outDir}
+	if stems == 2 {
+		args = append(args, "--two-stems", StemVocals)
+	}
+	return "demucs", append(args, input)
+}
+
+// stemFiles returns where each stem is written for an input named base
+func stemFiles(engine string, stems int, model, base, outDir string) map[string]string {
+	dir := filepath.Join(outDir, base)
+	accompaniment := "accompaniment.wav"
+	if engine == StemEngineDemucs {
+		dir = filepath.Join(outDir, demucsModel(model), base)
+		accompaniment = "no_vocals.wav"
+	}
+	if stems == 2 {
+		return map[string]string{
+			StemVocals:        filepath.Join(dir, "vocals.wav"),
+			StemAccompaniment: filepath.Join(dir, accompaniment),
+		}
+	}
+	files := map[string]string{}
+	for _, stem := range []string{StemVocals, StemDrums, StemBass, StemOther} {
+		files[stem] = filepath.Join(dir, stem+".wav")
+	}
+	return files
+}
+
+// demucsModel returns the Demucs model name, defaulting to htdemucs
+func demucsModel(model string) string {
+	if model == "" {
+		return "htdemucs"
+	}
+	return model
+}
+
+// separateWithFilters isolates the voice and removes center-panned vocals
+// with FFmpeg filters. The accompaniment needs stereo input.
+func (o *Operations) separateWithFilters(ctx context.Context, opts StemOptions) (map[string]string, []string, error) {
+	channels, err := o.audioChannels(ctx, opts.Input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stems := map[string]string{StemVocals: filepath.Join(opts.OutputDir, "vocals.wav")}
+	args := []string{"-i", opts.Input, "-vn", "-af", voiceIsolationFilter(opts.Model), "-y", stems[StemVocals]}
+	var warnings []string
+	if channels >= 2 {
+		stems[StemAccompaniment] = filepath.Join(opts.OutputDir, "accompaniment.wav")
+		args = append(args, "-vn", "-af", centerRemovalFilter, "-y", stems[StemAccompaniment])
+	} else {
+		warnings = append(warnings, "input is mono, so no accompaniment stem could be made without demucs or spleeter")
+	}
+	if opts.Model == "" {
+		warnings = append(warnings, "no RNNoise model given; vocals were isolated with band-limiting and spectral denoise only")
+	}
+
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, nil, fmt.Errorf("failed to separate audio: %w", err)
+	}
+	return stems, warnings, nil
+}
+
+// centerRemovalFilter cancels what is identical in both channels, which is
+// usually the lead vocal, leaving side-panned instruments and reverb
+const centerRemovalFilter = "pan=stereo|c0=0.5*c0-0.5*c1|c1=0.5*c1-0.5*c0"
+
+// voiceIsolationFilter keeps the speech band and suppresses everything
+// else, using an RNNoise model when one is given
+func voiceIsolationFilter(model string) string {
+	filters := []string{"highpass=f=80", "lowpass=f=12000"}
+	if model != "" {
+		filters = append(filters, fmt.Sprintf("arnndn=m='%s'", ffmpeg.EscapeFilterPath(model)))
+	} else {
+		filters = append(filters, "afftdn=nr=24:nf=-40:tn=1")
+	}
+	return strings.Join(filters, ",")
+}
+
+// remuxStems mixes stems back together and puts them in place of the
+// input's audio, copying any video
+func (o *Operations) remuxStems(ctx context.Context, input string, stems []string, output string) error {
+	args := []string{"-i", input}
+	for _, stem := range stems {
+		args = append(args, "-i", stem)
+	}
+	audio := "1:a"
+	if len(stems) > 1 {
+		var labels strings.Builder
+		for i := range stems {
+			labels.WriteString(fmt.Sprintf("[%d:a]", i+1))
+		}
+		args = append(args, "-filter_complex",
+			fmt.Sprintf("%samix=inputs=%d:duration=longest:normalize=0[mixed]", labels.String(), len(stems)))
+		audio = "[mixed]"
+	}
+	codec := o.getCodecForFormat(strings.ToLower(strings.TrimPrefix(filepath.Ext(output), ".")))
+	if codec == "" {
+		codec = "aac"
+	}
+	args = append(args,
+		"-map", "0:v?",
+		"-map", audio,
+		"-c:v", "copy",
+		"-c:a", codec,
+		"-shortest",
+		"-y", output,
+	)
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return fmt.Errorf("failed to rebuild audio from stems: %w", err)
+	}
+	return nil
+}
+
+// audioChannels returns the channel count of a file's first audio stream
+func (o *Operations) audioChannels(ctx context.Context, input string) (int, error) {
+	output, err := o.ffmpeg.Probe(ctx,
+		"-v", "error",
+		"-select_streams", "a:0",
+		"-show_entries", "stream=channels",
+		"-of", "csv=p=0",
+		input,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to probe audio: %w", err)
+	}
+	channels, err := strconv.Atoi(strings.TrimSpace(output))
+	if err != nil || channels <= 0 {
+		return 0, fmt.Errorf("no audio stream found in %s", input)
+	}
+	return channels, nil
+}
+
+// stemNames lists stem names in a fixed order
+func stemNames(stems map[string]string) []string {
+	var names []string
+	for _, name := range []string{StemVocals, StemAccompaniment, StemDrums, StemBass, StemOther} {
+		if _, ok := stems[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// tailLines returns the last n lines of s
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,100 @@
+package audio
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveStemEngine(t *testing.T) {
+	installed := func(tools ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, tool := range tools {
+				if tool == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	tests := []struct {
+		name    string
+		engine  string
+		stems   int
+		tools   []string
+		want    string
+		wantErr string
+	}{
+		{name: "auto prefers demucs", engine: "auto", stems: 2, tools: []string{"spleeter", "demucs"}, want: StemEngineDemucs},
+		{name: "auto falls back to spleeter", stems: 4, tools: []string{"spleeter"}, want: StemEngineSpleeter},
+		{name: "auto falls back to ffmpeg", stems: 2, want: StemEngineFFmpeg},
+		{name: "auto without models cannot do 4 stems", stems: 4, wantErr: "need demucs or spleeter"},
+		{name: "explicit engine must be installed", engine: "demucs", stems: 2, tools: []string{"spleeter"}, wantErr: "not installed"},
+		{name: "ffmpeg is 2 stems only", engine: "ffmpeg", stems: 4, wantErr: "only produces 2 stems"},
+		{name: "unknown engine", engine: "magic", stems: 2, wantErr: "unknown engine"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveStemEngine(tt.engine, tt.stems, installed(tt.tools...))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveStemEngine() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveStemEngine() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveStemEngine() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStemCommand(t *testing.T) {
+	name, args := stemCommand(StemEngineDemucs, 2, "", "in.wav", "/tmp/out")
+	if want := []string{"-n", "htdemucs", "-o", "/tmp/out", "--two-stems", "vocals", "in.wav"}; name != "demucs" || !reflect.DeepEqual(args, want) {
+		t.Errorf("demucs 2 stems = %s %v, want demucs %v", name, args, want)
+	}
+
+	name, args = stemCommand(StemEngineDemucs, 4, "mdx_extra", "in.wav", "/tmp/out")
+	if want := []string{"-n", "mdx_extra", "-o", "/tmp/out", "in.wav"}; name != "demucs" || !reflect.DeepEqual(args, want) {
+		t.Errorf("demucs 4 stems = %s %v, want demucs %v", name, args, want)
+	}
+
+	name, args = stemCommand(StemEngineSpleeter, 4, "", "in.wav", "/tmp/out")
+	if want := []string{"separate", "-p", "spleeter:4stems", "-o", "/tmp/out", "in.wav"}; name != "spleeter" || !reflect.DeepEqual(args, want) {
+		t.Errorf("spleeter 4 stems = %s %v, want spleeter %v", name, args, want)
+	}
+}
+
+func TestStemFiles(t *testing.T) {
+	got := stemFiles(StemEngineDemucs, 2, "", "mix", "/tmp/out")
+	want := map[string]string{
+		StemVocals:        filepath.Join("/tmp/out", "htdemucs", "mix", "vocals.wav"),
+		StemAccompaniment: filepath.Join("/tmp/out", "htdemucs", "mix", "no_vocals.wav"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("demucs files = %v, want %v", got, want)
+	}
+
+	got = stemFiles(StemEngineSpleeter, 4, "", "mix", "/tmp/out")
+	if len(got) != 4 || got[StemBass] != filepath.Join("/tmp/out", "mix", "bass.wav") {
+		t.Errorf("spleeter files = %v", got)
+	}
+}
+
+func TestVoiceIsolationFilter(t *testing.T) {
+	if got := voiceIsolationFilter(""); !strings.Contains(got, "afftdn") || strings.Contains(got, "arnndn") {
+		t.Errorf("without model = %q, want afftdn only", got)
+	}
+	got := voiceIsolationFilter("/models/it's.rnnn")
+	if want := `arnndn=m='/models/it\'\''s.rnnn'`; !strings.Contains(got, want) {
+		t.Errorf("with model = %q, want it to contain %q", got, want)
+	}
+}
@@ -0,0 +1,212 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+const (
+	syncRate         = 8000  // Sample rate audio is decoded at for alignment
+	syncEnvelopeRate = 200   // Envelope frames per second for the coarse search
+	syncRefineWindow = 20.0  // Seconds of samples compared when refining
+	defaultMaxOffset = 300.0 // Seconds either way searched by default
+	syncAnalysis     = 240.0 // Seconds of overlap analysed beyond the offset range
+)
+
+// OffsetResult describes how a recording lines up with a reference
+type OffsetResult struct {
+	// Offset is the time in the reference at which the recording starts:
+	// positive when the recording started later, negative when it started
+	// earlier and its first -Offset seconds have no counterpart
+	Offset float64 `json:"offset"`
+	// Confidence is the correlation of the two loudness envelopes at the
+	// offset, from 0 (unrelated) to 1 (identical)
+	Confidence float64 `json:"confidence"`
+}
+
+// DetectOffset finds where a separately recorded track (e.g. an external
+// mic) lines up with reference audio (e.g. the camera's scratch track) by
+// cross-correlating their loudness envelopes and refining the match on the
+// waveforms. maxOffset bounds the search in seconds (default: 300).
+func (o *Operations) DetectOffset(ctx context.Context, reference, recording string, maxOffset float64) (*OffsetResult, error) {
+	if maxOffset <= 0 {
+		maxOffset = defaultMaxOffset
+	}
+	limit := maxOffset + syncAnalysis
+	ref, err := o.decodeMono(ctx, reference, syncRate, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reference audio: %w", err)
+	}
+	rec, err := o.decodeMono(ctx, recording, syncRate, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	return findOffset(ref, rec, syncRate, maxOffset)
+}
+
+// findOffset aligns rec against ref, both mono at rate Hz
+func findOffset(ref, rec []float32, rate int, maxOffset float64) (*OffsetResult, error) {
+	// Onsets are smoothed so a match that falls between envelope frames
+	// still correlates strongly
+	hop := rate / syncEnvelopeRate
+	refEnv := normalizeEnvelope(smooth(smooth(onsetEnvelope(ref, hop))))
+	recEnv := normalizeEnvelope(smooth(smooth(onsetEnvelope(rec, hop))))
+	if len(refEnv) < syncEnvelopeRate || len(recEnv) < syncEnvelopeRate {
+		return nil, fmt.Errorf("audio too short to align (need at least 1s)")
+	}
+
+	// Coarse search over envelope frames
+	corr := crossCorrelate(refEnv, recEnv)
+	maxLag := int(maxOffset * syncEnvelopeRate)
+	bestLag, bestScore := 0, math.Inf(-1)
+	for k, v := range corr {
+		lag := k
+		if k >= len(corr)/2 {
+			lag = k - len(corr)
+		}
+		if lag < -maxLag || lag > maxLag || lag <= -len(recEnv) || lag >= len(refEnv) {
+			continue
+		}
+		if v > bestScore {
+			bestLag, bestScore = lag, v
+		}
+	}
+	confidence := math.Max(0, pearsonAt(refEnv, recEnv, bestLag))
+
+	// Refine to the sample within two envelope frames. Microphones can be
+	// wired with opposite polarity, so the strongest match either way wins.
+	center := bestLag * hop
+	start, end := overlap(len(ref), len(rec), center)
+	end = min(end, start+int(syncRefineWindow*float64(rate)))
+	best, bestAbs := center, -1.0
+	for lag := center - 2*hop; lag <= center+2*hop; lag++ {
+		sum := 0.0
+		for t := start; t < end; t++ {
+			if i := t + lag; i >= 0 && i < len(ref) {
+				sum += float64(ref[i]) * float64(rec[t])
+			}
+		}
+		if math.Abs(sum) > bestAbs {
+			best, bestAbs = lag, math.Abs(sum)
+		}
+	}
+
+	return &OffsetResult{
+		Offset:     float64(best) / float64(rate),
+		Confidence: math.Round(confidence*1000) / 1000,
+	}, nil
+}
+
+// normalizeEnvelope scales an envelope to zero mean and unit variance so
+// differences in mic gain and noise floor do not matter
+func normalizeEnvelope(env []float64) []float64 {
+	if len(env) == 0 {
+		return env
+	}
+	mean := 0.0
+	for _, v := range env {
+		mean += v
+	}
+	mean /= float64(len(env))
+	variance := 0.0
+	for _, v := range env {
+		variance += (v - mean) * (v - mean)
+	}
+	std := math.Sqrt(variance / float64(len(env)))
+	if std == 0 {
+		std = 1
+	}
+	out := make([]float64, len(env))
+	for i, v := range env {
+		out[i] = (v - mean) / std
+	}
+	return out
+}
+
+// crossCorrelate returns c[k] = sum a[t+k]*b[t] for every lag k, computed
+// with an FFT. Negative lags wrap around to the end of the result.
+func crossCorrelate(a, b []float64) []float64 {
+	n := 1
+	for n < len(a)+len(b) {
+		n <<= 1
+	}
+	fa := make([]complex128, n)
+	fb := make([]complex128, n)
+	for i, v := range a {
+		fa[i] = complex(v, 0)
+	}
+	for i, v := range b {
+		fb[i] = complex(v, 0)
+	}
+	fft(fa, false)
+	fft(fb, false)
+	for i := range fa {
+		fa[i] *= cmplx.Conj(fb[i])
+	}
+	fft(fa, true)
+
+	out := make([]float64, n)
+	for i, v := range fa {
+		out[i] = real(v) / float64(n)
+	}
+	return out
+}
+
+// fft is an in-place radix-2 FFT; len(x) must be a power of two
+func fft(x []complex128, inverse bool) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, sign*2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even, odd := x[start+k], x[start+k+size/2]*w
+				x[start+k], x[start+k+size/2] = even+odd, even-odd
+				w *= step
+			}
+		}
+	}
+}
+
+// pearsonAt returns the correlation coefficient of a[t+lag] and b[t] over
+// their overlap
+func pearsonAt(a, b []float64, lag int) float64 {
+	start, end := overlap(len(a), len(b), lag)
+	n := float64(end - start)
+	if n < 2 {
+		return 0
+	}
+	var sa, sb, saa, sbb, sab float64
+	for t := start; t < end; t++ {
+		x, y := a[t+lag], b[t]
+		sa, sb = sa+x, sb+y
+		saa, sbb, sab = saa+x*x, sbb+y*y, sab+x*y
+	}
+	cov := sab - sa*sb/n
+	den := math.Sqrt((saa - sa*sa/n) * (sbb - sb*sb/n))
+	if den == 0 {
+		return 0
+	}
+	return cov / den
+}
+
+// overlap returns the range of t in b for which a[t+lag] exists
+func overlap(lenA, lenB, lag int) (int, int) {
+	return max(0, -lag), min(lenB, lenA-lag)
+}
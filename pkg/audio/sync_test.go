@@ -0,0 +1,91 @@
+package audio
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// speechLike synthesizes bursts of tone and noise with random gaps
+func speechLike(seconds float64, rate int, seed int64) []float32 {
+	rng := rand.New(rand.NewSource(seed))
+	samples := make([]float32, int(seconds*float64(rate)))
+	for i := 0; i < len(samples); {
+		burst := int((0.1 + rng.Float64()*0.4) * float64(rate))
+		gap := int((0.05 + rng.Float64()*0.3) * float64(rate))
+		freq := 100 + rng.Float64()*200
+		for j := 0; j < burst && i+j < len(samples); j++ {
+			env := math.Sin(math.Pi * float64(j) / float64(burst))
+			samples[i+j] = float32(env * (0.5*math.Sin(2*math.Pi*freq*float64(j)/float64(rate)) + 0.2*rng.NormFloat64()))
+		}
+		i += burst + gap
+	}
+	return samples
+}
+
+// rerecord shifts src so it starts at offset seconds in the reference
+// timeline, changes gain and polarity, low-passes it and adds room noise
+func rerecord(src []float32, offset float64, rate int) []float32 {
+	rng := rand.New(rand.NewSource(99))
+	shift := int(offset * float64(rate))
+	out := make([]float32, len(src))
+	prev := 0.0
+	for t := range out {
+		v := 0.0
+		if i := t + shift; i >= 0 && i < len(src) {
+			v = float64(src[i])
+		}
+		prev = 0.6*prev + 0.4*v
+		out[t] = float32(-0.3*prev + 0.02*rng.NormFloat64())
+	}
+	return out
+}
+
+func TestFindOffset(t *testing.T) {
+	ref := speechLike(40, syncRate, 1)
+	for _, offset := range []float64{2.5, -3.25, 0.0125} {
+		result, err := findOffset(ref, rerecord(ref, offset, syncRate), syncRate, 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if math.Abs(result.Offset-offset) > 0.001 {
+			t.Errorf("Expected offset %.4f, got %.4f", offset, result.Offset)
+		}
+		if result.Confidence < 0.5 {
+			t.Errorf("Expected high confidence for offset %.4f, got %.3f", offset, result.Confidence)
+		}
+	}
+}
+
+func TestFindOffsetUnrelated(t *testing.T) {
+	result, err := findOffset(speechLike(20, syncRate, 1), speechLike(20, syncRate, 2), syncRate, 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Confidence > 0.3 {
+		t.Errorf("Expected low confidence for unrelated audio, got %.3f", result.Confidence)
+	}
+}
+
+func TestCrossCorrelate(t *testing.T) {
+	a := []float64{0, 0, 1, 2, 0}
+	b := []float64{1, 2}
+	corr := crossCorrelate(a, b)
+	if math.Abs(corr[2]-5) > 1e-9 {
+		t.Errorf("Expected peak of 5 at lag 2, got %v", corr)
+	}
+	if math.Abs(corr[len(corr)-1]) > 1e-9 {
+		t.Errorf("Expected nothing at lag -1, got %v", corr)
+	}
+}
+
+func TestLevelEnvelope(t *testing.T) {
+	samples := make([]float32, 3*syncRate)
+	for i := syncRate; i < 2*syncRate; i++ {
+		samples[i] = 0.5
+	}
+	levels := levelEnvelope(samples, syncRate)
+	if len(levels) != 3 || levels[0] != levelFloor || math.Abs(levels[1]-(-6.02)) > 0.01 {
+		t.Errorf("Unexpected levels: %v", levels)
+	}
+}
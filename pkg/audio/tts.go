@@ -7,17 +7,27 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/chandler-mayo/mcp-video-editor/pkg/apiclient"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/usage"
 	elevenlabs "github.com/haguro/elevenlabs-go"
+	openai "github.com/sashabaranov/go-openai"
 )
 
-// TTSOperations handles ElevenLabs TTS and voice cloning
+// TTSOperations handles text-to-speech across providers and ElevenLabs
+// voice cloning
 type TTSOperations struct {
 	apiKey string
 	client *elevenlabs.Client
 	config *config.Config
+	ffmpeg *ffmpeg.Manager // Converts local engines' WAV output (optional)
 }
 
 // VoiceCloneOptions contains parameters for voice cloning
@@ -30,10 +40,16 @@ type VoiceCloneOptions struct {
 // SpeechOptions contains parameters for TTS generation
 type SpeechOptions struct {
 	Text       string
-	VoiceID    string
-	ModelID    string  // defaults to "eleven_multilingual_v2"
-	Stability  float64 // 0.0-1.0, default 0.5
-	Similarity float64 // 0.0-1.0, default 0.75
+	Provider   string  // elevenlabs, openai, piper or coqui (default: config, then whichever is set up)
+	VoiceID    string  // provider voice: ElevenLabs voice ID, OpenAI voice name or local speaker
+	ModelID    string  // provider model; defaults to "eleven_multilingual_v2" for ElevenLabs
+	Stability  float64 // ElevenLabs only: 0.0-1.0, default 0.5
+	Similarity float64 // ElevenLabs only: 0.0-1.0, default 0.75
+	Speed      float64 // OpenAI and Piper: speaking rate multiplier (default: 1)
+	Markup     string  // none, simple or ssml (default: ssml when Text starts with <speak>, else none)
+	// Instructions steers delivery on OpenAI gpt-4o models; emphasized
+	// words from markup are added to it
+	Instructions string
 }
 
 // NewTTSOperations creates a new TTS operations handler
@@ -64,10 +80,15 @@ func (t *TTSOperations) CloneVoice(ctx context.Context, opts VoiceCloneOptions)
 	}
 
 	// Call ElevenLabs API
-	voiceID, err := t.client.AddVoice(req)
+	var voiceID string
+	err := apiclient.Do(ctx, apiclient.ProviderElevenLabs, func() (err error) {
+		voiceID, err = t.client.AddVoice(req)
+		return err
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to clone voice: %w", err)
 	}
+	usage.Record(usage.Entry{Provider: usage.ProviderElevenLabs, Feature: usage.FeatureVoiceClone})
 
 	// Cache the voice ID
 	audioHash, err := t.hashAudioFile(opts.AudioPath)
@@ -76,55 +97,138 @@ func (t *TTSOperations) CloneVoice(ctx context.Context, opts VoiceCloneOptions)
 			t.config.ElevenLabsVoices = make(map[string]string)
 		}
 		t.config.ElevenLabsVoices[audioHash] = voiceID
+		t.config.SetVoiceMeta(audioHash, config.VoiceMeta{
+			Name:        opts.Name,
+			Description: opts.Description,
+			Source:      opts.AudioPath,
+			CreatedAt:   time.Now(),
+		})
 		t.config.Save()
 	}
 
 	return voiceID, nil
 }
 
-// GenerateSpeech generates TTS audio and saves to file
+// SetFFmpeg lets local TTS engines convert their WAV output to other formats
+func (t *TTSOperations) SetFFmpeg(mgr *ffmpeg.Manager) {
+	t.ffmpeg = mgr
+}
+
+// GenerateSpeech generates TTS audio and saves to file. Markup and the
+// configured pronunciation dictionary are rendered for the provider first.
 func (t *TTSOperations) GenerateSpeech(ctx context.Context, opts SpeechOptions, outputPath string) error {
-	if t.client == nil {
-		return fmt.Errorf("ElevenLabs API key not configured")
+	provider, err := t.Provider(opts.Provider)
+	if err != nil {
+		return err
 	}
-
-	// Set defaults
-	if opts.ModelID == "" {
-		opts.ModelID = "eleven_multilingual_v2"
+	spans, err := parseSpeechMarkup(opts.Text, opts.Markup)
+	if err != nil {
+		return err
 	}
-	if opts.Stability == 0 {
-		opts.Stability = 0.5
+	if t.config != nil {
+		spans = applyPronunciations(spans, t.config.Pronunciations)
 	}
-	if opts.Similarity == 0 {
-		opts.Similarity = 0.75
+
+	// ElevenLabs takes pauses as inline breaks; other providers are
+	// synthesized in pieces joined with silence
+	if provider.Name() == TTSProviderElevenLabs || !hasPauses(spans) || t.ffmpeg == nil {
+		return provider.Synthesize(ctx, renderOptions(opts, spans, provider.Name()), outputPath)
 	}
+	return t.synthesizeWithPauses(ctx, provider, opts, spans, outputPath)
+}
 
-	// Create TTS request
-	ttsReq := elevenlabs.TextToSpeechRequest{
-		Text:    opts.Text,
-		ModelID: opts.ModelID,
+// renderOptions returns opts with the text rendered for a provider
+func renderOptions(opts SpeechOptions, spans []speechSpan, provider string) SpeechOptions {
+	text, emphasized := renderSpeech(spans, provider)
+	opts.Text = strings.TrimSpace(text)
+	if len(emphasized) > 0 && provider == TTSProviderOpenAI {
+		emphasis := fmt.Sprintf("Stress these words: %s.", strings.Join(emphasized, ", "))
+		opts.Instructions = strings.TrimSpace(opts.Instructions + " " + emphasis)
 	}
+	return opts
+}
 
-	// Set voice settings
-	if opts.Stability > 0 || opts.Similarity > 0 {
-		ttsReq.VoiceSettings = &elevenlabs.VoiceSettings{
-			Stability:       float32(opts.Stability),
-			SimilarityBoost: float32(opts.Similarity),
+// synthesizeWithPauses synthesizes each run of speech separately and joins
+// the pieces with silence for the pauses between them
+func (t *TTSOperations) synthesizeWithPauses(ctx context.Context, provider TTSProvider, opts SpeechOptions, spans []speechSpan, outputPath string) error {
+	tempDir, cleanup, err := scratch.Dir("tts")
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	var args []string
+	var pieces []string
+	for i, group := range splitPauses(spans) {
+		if group[0].Pause > 0 {
+			pieces = append(pieces, fmt.Sprintf("pause:%.3f", group[0].Pause))
+			continue
+		}
+		pieceOpts := renderOptions(opts, group, provider.Name())
+		if pieceOpts.Text == "" {
+			continue
 		}
+		piece := filepath.Join(tempDir, fmt.Sprintf("piece_%03d.wav", i))
+		if err := provider.Synthesize(ctx, pieceOpts, piece); err != nil {
+			return err
+		}
+		args = append(args, "-i", piece)
+		pieces = append(pieces, piece)
 	}
 
-	// Generate speech
-	audioData, err := t.client.TextToSpeech(opts.VoiceID, ttsReq)
-	if err != nil {
-		return fmt.Errorf("failed to generate speech: %w", err)
+	args = append(args, "-filter_complex", joinPiecesFilter(pieces), "-map", "[out]", "-y", outputPath)
+	return t.ffmpeg.Execute(ctx, args...)
+}
+
+// speechSampleRate is the rate speech pieces and pauses are joined at
+const speechSampleRate = 44100
+
+// joinPiecesFilter concatenates speech inputs and pauses, given as
+// "pause:<seconds>", into [out]. Inputs are numbered in order.
+func joinPiecesFilter(pieces []string) string {
+	var filters, labels []string
+	input := 0
+	for i, piece := range pieces {
+		label := fmt.Sprintf("[p%d]", i)
+		if seconds, ok := strings.CutPrefix(piece, "pause:"); ok {
+			filters = append(filters, fmt.Sprintf("anullsrc=r=%d:cl=mono,atrim=duration=%s%s", speechSampleRate, seconds, label))
+		} else {
+			filters = append(filters, fmt.Sprintf("[%d:a]aresample=%d,aformat=channel_layouts=mono%s", input, speechSampleRate, label))
+			input++
+		}
+		labels = append(labels, label)
 	}
+	filters = append(filters, fmt.Sprintf("%sconcat=n=%d:v=0:a=1[out]", strings.Join(labels, ""), len(labels)))
+	return strings.Join(filters, ";")
+}
 
-	// Save to file
-	if err := os.WriteFile(outputPath, audioData, 0644); err != nil {
-		return fmt.Errorf("failed to write audio file: %w", err)
+// Provider returns the named TTS provider, or the configured default when
+// name is empty
+func (t *TTSOperations) Provider(name string) (TTSProvider, error) {
+	var configured, openAIKey, piperModel string
+	if t.config != nil {
+		configured, openAIKey, piperModel = t.config.TTSProvider, t.config.OpenAIKey, t.config.PiperModel
+	}
+	resolved, err := resolveTTSProvider(name, configured, t.client != nil, openAIKey != "", exec.LookPath)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	switch resolved {
+	case TTSProviderElevenLabs:
+		return &elevenLabsProvider{client: t.client}, nil
+	case TTSProviderOpenAI:
+		return &openAIProvider{client: openai.NewClientWithConfig(apiclient.OpenAIConfig(openAIKey, apiclient.ProviderOpenAI))}, nil
+	default:
+		path, _ := exec.LookPath(localTTSCommands[resolved])
+		provider := &localProvider{name: resolved, path: path, piperModel: piperModel}
+		if t.ffmpeg != nil {
+			provider.convert = func(ctx context.Context, input, output string) error {
+				return t.ffmpeg.Execute(ctx, "-i", input, "-y", output)
+			}
+		}
+		return provider, nil
+	}
 }
 
 // GetOrCreateVoiceID checks cache for existing voice ID or creates a new clone
@@ -138,7 +242,7 @@ func (t *TTSOperations) GetOrCreateVoiceID(ctx context.Context, audioPath string
 				return voiceID, nil
 			}
 			// Voice doesn't exist anymore, remove from cache
-			delete(t.config.ElevenLabsVoices, audioHash)
+			t.config.RemoveVoice(audioHash)
 			t.config.Save()
 		}
 	}
@@ -173,7 +277,10 @@ func (t *TTSOperations) verifyVoiceExists(voiceID string) bool {
 		return false
 	}
 
-	_, err := t.client.GetVoice(voiceID)
+	err := apiclient.Do(context.Background(), apiclient.ProviderElevenLabs, func() error {
+		_, err := t.client.GetVoice(voiceID)
+		return err
+	})
 	return err == nil
 }
 
@@ -195,15 +302,23 @@ func (t *TTSOperations) ListCachedVoices(ctx context.Context) ([]CachedVoiceInfo
 	}
 
 	for hash, voiceID := range t.config.ElevenLabsVoices {
+		meta := t.config.VoiceInfo[hash]
 		info := CachedVoiceInfo{
-			AudioHash: hash,
-			VoiceID:   voiceID,
-			IsValid:   false,
+			AudioHash:   hash,
+			VoiceID:     voiceID,
+			Name:        meta.Name,
+			Description: meta.Description,
+			IsValid:     false,
 		}
 
 		// Try to get voice details from ElevenLabs
 		if t.client != nil {
-			if voice, err := t.client.GetVoice(voiceID); err == nil {
+			var voice elevenlabs.Voice
+			err := apiclient.Do(ctx, apiclient.ProviderElevenLabs, func() (err error) {
+				voice, err = t.client.GetVoice(voiceID)
+				return err
+			})
+			if err == nil {
 				info.Name = voice.Name
 				info.Description = voice.Description
 				info.IsValid = true
@@ -222,13 +337,14 @@ func (t *TTSOperations) ClearCachedVoice(audioHash string) error {
 		return nil
 	}
 
-	delete(t.config.ElevenLabsVoices, audioHash)
+	t.config.RemoveVoice(audioHash)
 	return t.config.Save()
 }
 
 // ClearAllCachedVoices removes all cached voices
 func (t *TTSOperations) ClearAllCachedVoices() error {
 	t.config.ElevenLabsVoices = make(map[string]string)
+	t.config.VoiceInfo = nil
 	return t.config.Save()
 }
 
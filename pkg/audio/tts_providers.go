@@ -0,0 +1,296 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/apiclient"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/usage"
+	elevenlabs "github.com/haguro/elevenlabs-go"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// TTS providers
+const (
+	TTSProviderElevenLabs = "elevenlabs"
+	TTSProviderOpenAI     = "openai"
+	TTSProviderPiper      = "piper" // Local Piper engine
+	TTSProviderCoqui      = "coqui" // Local Coqui TTS engine
+)
+
+// TTSProviders lists the supported TTS providers
+var TTSProviders = []string{TTSProviderElevenLabs, TTSProviderOpenAI, TTSProviderPiper, TTSProviderCoqui}
+
+// TTSProvider synthesizes speech to an audio file
+type TTSProvider interface {
+	Name() string
+	Synthesize(ctx context.Context, opts SpeechOptions, outputPath string) error
+}
+
+// elevenLabsProvider synthesizes speech with the ElevenLabs API
+type elevenLabsProvider struct {
+	client *elevenlabs.Client
+}
+
+func (p *elevenLabsProvider) Name() string {
+	return TTSProviderElevenLabs
+}
+
+func (p *elevenLabsProvider) Synthesize(ctx context.Context, opts SpeechOptions, outputPath string) error {
+	// Set defaults
+	if opts.ModelID == "" {
+		opts.ModelID = "eleven_multilingual_v2"
+	}
+	if opts.Stability == 0 {
+		opts.Stability = 0.5
+	}
+	if opts.Similarity == 0 {
+		opts.Similarity = 0.75
+	}
+	if opts.VoiceID == "" {
+		return fmt.Errorf("voice ID is required for ElevenLabs")
+	}
+
+	// Create TTS request
+	ttsReq := elevenlabs.TextToSpeechRequest{
+		Text:    opts.Text,
+		ModelID: opts.ModelID,
+		VoiceSettings: &elevenlabs.VoiceSettings{
+			Stability:       float32(opts.Stability),
+			SimilarityBoost: float32(opts.Similarity),
+		},
+	}
+
+	// Generate speech
+	var audioData []byte
+	err := apiclient.Do(ctx, apiclient.ProviderElevenLabs, func() (err error) {
+		audioData, err = p.client.TextToSpeech(opts.VoiceID, ttsReq)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate speech: %w", err)
+	}
+	usage.Record(usage.Entry{
+		Provider:   usage.ProviderElevenLabs,
+		Feature:    usage.FeatureSpeech,
+		Model:      opts.ModelID,
+		Characters: utf8.RuneCountInString(opts.Text),
+	})
+
+	// Save to file
+	if err := os.WriteFile(outputPath, audioData, 0644); err != nil {
+		return fmt.Errorf("failed to write audio file: %w", err)
+	}
+	return nil
+}
+
+// openAIProvider synthesizes speech with the OpenAI speech API
+type openAIProvider struct {
+	client *openai.Client
+}
+
+// OpenAIVoices lists the built-in OpenAI TTS voices
+var OpenAIVoices = []string{"alloy", "ash", "ballad", "coral", "echo", "fable", "onyx", "nova", "sage", "shimmer", "verse"}
+
+func (p *openAIProvider) Name() string {
+	return TTSProviderOpenAI
+}
+
+func (p *openAIProvider) Synthesize(ctx context.Context, opts SpeechOptions, outputPath string) error {
+	req := openai.CreateSpeechRequest{
+		Model:          openai.SpeechModel(opts.ModelID),
+		Input:          opts.Text,
+		Voice:          openai.SpeechVoice(strings.ToLower(opts.VoiceID)),
+		ResponseFormat: openAISpeechFormat(outputPath),
+		Speed:          opts.Speed,
+		Instructions:   opts.Instructions,
+	}
+	if req.Model == "" {
+		req.Model = openai.TTSModelGPT4oMini
+	}
+	if req.Voice == "" {
+		req.Voice = openai.VoiceAlloy
+	}
+
+	resp, err := p.client.CreateSpeech(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to generate speech: %w", err)
+	}
+	usage.Record(usage.Entry{
+		Provider:   usage.ProviderOpenAI,
+		Feature:    usage.FeatureSpeech,
+		Model:      string(req.Model),
+		Characters: utf8.RuneCountInString(req.Input),
+	})
+	defer resp.Close()
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to write audio file: %w", err)
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, resp); err != nil {
+		return fmt.Errorf("failed to write audio file: %w", err)
+	}
+	return nil
+}
+
+// openAISpeechFormat picks the response format matching the output file
+func openAISpeechFormat(outputPath string) openai.SpeechResponseFormat {
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".wav":
+		return openai.SpeechResponseFormatWav
+	case ".flac":
+		return openai.SpeechResponseFormatFlac
+	case ".aac", ".m4a":
+		return openai.SpeechResponseFormatAac
+	case ".opus", ".ogg":
+		return openai.SpeechResponseFormatOpus
+	default:
+		return openai.SpeechResponseFormatMp3
+	}
+}
+
+// localProvider synthesizes speech with a locally installed engine. Both
+// engines write WAV, which is converted when another format is asked for.
+type localProvider struct {
+	name       string
+	path       string
+	piperModel string // Default Piper voice model from config
+	convert    func(ctx context.Context, input, output string) error
+}
+
+func (p *localProvider) Name() string {
+	return p.name
+}
+
+func (p *localProvider) Synthesize(ctx context.Context, opts SpeechOptions, outputPath string) error {
+	wavPath := outputPath
+	if !strings.EqualFold(filepath.Ext(outputPath), ".wav") {
+		if p.convert == nil {
+			return fmt.Errorf("%s writes WAV; use a .wav output", p.name)
+		}
+		tempDir, cleanup, err := scratch.Dir("tts")
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		wavPath = filepath.Join(tempDir, "speech.wav")
+	}
+
+	args, stdin, err := localTTSArgs(p.name, opts, p.piperModel, wavPath)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, p.path, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w\n%s", p.name, err, tailLines(output.String(), 10))
+	}
+
+	if wavPath != outputPath {
+		if err := p.convert(ctx, wavPath, outputPath); err != nil {
+			return fmt.Errorf("failed to convert speech: %w", err)
+		}
+	}
+	return nil
+}
+
+// localTTSArgs returns the command line and standard input for a local
+// engine. For Piper, ModelID is the .onnx voice model and a numeric VoiceID
+// picks a speaker; for Coqui, ModelID is the model name and VoiceID the speaker.
+func localTTSArgs(engine string, opts SpeechOptions, piperModel, wavPath string) ([]string, string, error) {
+	switch engine {
+	case TTSProviderPiper:
+		model := opts.ModelID
+		if model == "" {
+			model = piperModel
+		}
+		if model == "" {
+			return nil, "", fmt.Errorf("piper needs a voice model: pass modelId or set piperModel in config")
+		}
+		args := []string{"--model", model, "--output_file", wavPath}
+		if opts.VoiceID != "" {
+			if _, err := strconv.Atoi(opts.VoiceID); err == nil {
+				args = append(args, "--speaker", opts.VoiceID)
+			}
+		}
+		if opts.Speed > 0 {
+			// Piper's length scale is the inverse of speed
+			args = append(args, "--length_scale", fmt.Sprintf("%.3f", 1/opts.Speed))
+		}
+		return args, opts.Text, nil
+	case TTSProviderCoqui:
+		args := []string{"--text", opts.Text, "--out_path", wavPath}
+		if opts.ModelID != "" {
+			args = append(args, "--model_name", opts.ModelID)
+		}
+		if opts.VoiceID != "" {
+			args = append(args, "--speaker_idx", opts.VoiceID)
+		}
+		return args, "", nil
+	default:
+		return nil, "", fmt.Errorf("unknown local TTS engine: %s", engine)
+	}
+}
+
+// localTTSCommands maps local providers to their executables
+var localTTSCommands = map[string]string{
+	TTSProviderPiper: "piper",
+	TTSProviderCoqui: "tts",
+}
+
+// resolveTTSProvider picks a provider: the requested one, then the
+// configured one, then the first one that is set up, preferring ElevenLabs
+// so existing setups keep their voices
+func resolveTTSProvider(requested, configured string, hasElevenLabs, hasOpenAI bool, lookPath func(string) (string, error)) (string, error) {
+	name := strings.ToLower(requested)
+	if name == "" {
+		name = strings.ToLower(configured)
+	}
+
+	switch name {
+	case TTSProviderElevenLabs:
+		if !hasElevenLabs {
+			return "", fmt.Errorf("ElevenLabs API key not configured. Set ELEVENLABS_API_KEY environment variable or update config")
+		}
+	case TTSProviderOpenAI:
+		if !hasOpenAI {
+			return "", fmt.Errorf("OpenAI API key not configured. Set OPENAI_API_KEY environment variable or update config")
+		}
+	case TTSProviderPiper, TTSProviderCoqui:
+		if _, err := lookPath(localTTSCommands[name]); err != nil {
+			return "", fmt.Errorf("%s is not installed (%s not found on PATH)", name, localTTSCommands[name])
+		}
+	case "":
+		switch {
+		case hasElevenLabs:
+			return TTSProviderElevenLabs, nil
+		case hasOpenAI:
+			return TTSProviderOpenAI, nil
+		}
+		for _, local := range []string{TTSProviderPiper, TTSProviderCoqui} {
+			if _, err := lookPath(localTTSCommands[local]); err == nil {
+				return local, nil
+			}
+		}
+		return "", fmt.Errorf("no TTS provider available. Set an ElevenLabs or OpenAI API key, or install piper or coqui tts")
+	default:
+		return "", fmt.Errorf("unknown TTS provider: %s (use %s)", name, strings.Join(TTSProviders, ", "))
+	}
+	return name, nil
+}
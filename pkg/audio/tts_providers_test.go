@@ -0,0 +1,107 @@
+package audio
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+func TestResolveTTSProvider(t *testing.T) {
+	lookPath := func(installed ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, tool := range installed {
+				if tool == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	tests := []struct {
+		name       string
+		requested  string
+		configured string
+		elevenLabs bool
+		openAI     bool
+		installed  []string
+		want       string
+		wantErr    string
+	}{
+		{name: "ElevenLabs preferred when set up", elevenLabs: true, openAI: true, want: TTSProviderElevenLabs},
+		{name: "OpenAI without ElevenLabs", openAI: true, want: TTSProviderOpenAI},
+		{name: "local engine without keys", installed: []string{"tts"}, want: TTSProviderCoqui},
+		{name: "nothing set up", wantErr: "no TTS provider available"},
+		{name: "config overrides the fallback order", configured: "openai", elevenLabs: true, openAI: true, want: TTSProviderOpenAI},
+		{name: "request overrides config", requested: "Piper", configured: "openai", openAI: true, installed: []string{"piper"}, want: TTSProviderPiper},
+		{name: "requested provider needs its key", requested: "elevenlabs", openAI: true, wantErr: "ElevenLabs API key not configured"},
+		{name: "requested engine must be installed", requested: "coqui", wantErr: "not installed"},
+		{name: "unknown provider", requested: "polly", wantErr: "unknown TTS provider"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveTTSProvider(tt.requested, tt.configured, tt.elevenLabs, tt.openAI, lookPath(tt.installed...))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveTTSProvider() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveTTSProvider() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveTTSProvider() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalTTSArgs(t *testing.T) {
+	args, stdin, err := localTTSArgs(TTSProviderPiper, SpeechOptions{Text: "Hello", VoiceID: "3", Speed: 1.25}, "en_US-amy.onnx", "out.wav")
+	if err != nil {
+		t.Fatalf("piper: %v", err)
+	}
+	want := []string{"--model", "en_US-amy.onnx", "--output_file", "out.wav", "--speaker", "3", "--length_scale", "0.800"}
+	if !reflect.DeepEqual(args, want) || stdin != "Hello" {
+		t.Errorf("piper = %v (stdin %q), want %v (stdin Hello)", args, stdin, want)
+	}
+
+	// ElevenLabs-style voice IDs are not Piper speakers
+	args, _, _ = localTTSArgs(TTSProviderPiper, SpeechOptions{Text: "Hi", VoiceID: "21m00Tcm4TlvDq8ikWAM", ModelID: "voice.onnx"}, "", "out.wav")
+	if want := []string{"--model", "voice.onnx", "--output_file", "out.wav"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("piper with model = %v, want %v", args, want)
+	}
+
+	if _, _, err := localTTSArgs(TTSProviderPiper, SpeechOptions{Text: "Hi"}, "", "out.wav"); err == nil {
+		t.Error("expected error for piper without a voice model")
+	}
+
+	args, stdin, err = localTTSArgs(TTSProviderCoqui, SpeechOptions{Text: "Hello", ModelID: "tts_models/en/vctk/vits", VoiceID: "p225"}, "", "out.wav")
+	if err != nil {
+		t.Fatalf("coqui: %v", err)
+	}
+	want = []string{"--text", "Hello", "--out_path", "out.wav", "--model_name", "tts_models/en/vctk/vits", "--speaker_idx", "p225"}
+	if !reflect.DeepEqual(args, want) || stdin != "" {
+		t.Errorf("coqui = %v (stdin %q), want %v", args, stdin, want)
+	}
+}
+
+func TestOpenAISpeechFormat(t *testing.T) {
+	tests := map[string]openai.SpeechResponseFormat{
+		"out.mp3":  openai.SpeechResponseFormatMp3,
+		"out.WAV":  openai.SpeechResponseFormatWav,
+		"out.m4a":  openai.SpeechResponseFormatAac,
+		"out.opus": openai.SpeechResponseFormatOpus,
+		"out":      openai.SpeechResponseFormatMp3,
+	}
+	for path, want := range tests {
+		if got := openAISpeechFormat(path); got != want {
+			t.Errorf("openAISpeechFormat(%q) = %s, want %s", path, got, want)
+		}
+	}
+}
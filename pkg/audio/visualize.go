@@ -0,0 +1,177 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+)
+
+// WaveformImageOptions contains parameters for rendering a waveform image
+type WaveformImageOptions struct {
+	Input         string
+	Output        string // Image path (png recommended for transparency)
+	Width         int    // Image width in pixels (default: 1920)
+	Height        int    // Image height in pixels (default: 240)
+	Color         string // Waveform color (default: "white")
+	Background    string // Background color (default: transparent)
+	SplitChannels bool   // Draw each channel separately instead of a mono mix
+	Scale         string // Amplitude scale: lin, log, sqrt, cbrt (default: sqrt)
+}
+
+// AudiogramOptions contains parameters for rendering an audio visualization video
+type AudiogramOptions struct {
+	Input      string
+	Output     string
+	Style      string // "waves" (default), "bars", or "spectrum"
+	Width      int    // Video width (default: 1080)
+	Height     int    // Video height (default: 1080)
+	FPS        int    // Frame rate (default: 30)
+	Color      string // Visualization color (default: "white")
+	Background string // Background color when no cover art is used (default: "black")
+	CoverImage string // Optional cover art used as the background
+	Subtitles  string // Optional SRT/ASS file burned in as captions
+	Start      float64
+	Duration   float64 // Clip length in seconds (0 = to end)
+}
+
+// GenerateWaveformImage renders the audio waveform of a file as a single image
+func (o *Operations) GenerateWaveformImage(ctx context.Context, opts WaveformImageOptions) error {
+	args := []string{
+		"-i", opts.Input,
+		"-filter_complex", buildWaveformImageFilter(opts),
+		"-map", "[out]",
+		"-frames:v", "1",
+		"-y", opts.Output,
+	}
+
+	return o.ffmpeg.Execute(ctx, args...)
+}
+
+// CreateAudiogram renders audio as a shareable video with an animated
+// waveform or spectrum over a solid color or cover art, with optional captions
+func (o *Operations) CreateAudiogram(ctx context.Context, opts AudiogramOptions) error {
+	width, height, fps := audiogramDimensions(opts)
+
+	var args []string
+	if opts.Start > 0 {
+		args = append(args, "-ss", fmt.Sprintf("%.3f", opts.Start))
+	}
+	if opts.Duration > 0 {
+		args = append(args, "-t", fmt.Sprintf("%.3f", opts.Duration))
+	}
+	args = append(args, "-i", opts.Input)
+
+	if opts.CoverImage != "" {
+		args = append(args, "-loop", "1", "-framerate", fmt.Sprintf("%d", fps), "-i", opts.CoverImage)
+	} else {
+		background := opts.Background
+		if background == "" {
+			background = "black"
+		}
+		args = append(args, "-f", "lavfi", "-i", fmt.Sprintf("color=c=%s:s=%dx%d:r=%d", background, width, height, fps))
+	}
+
+	args = append(args,
+		"-filter_complex", buildAudiogramFilter(opts),
+		"-map", "[out]",
+		"-map", "0:a",
+		"-c:v", "libx264",
+		"-pix_fmt", "yuv420p",
+		"-c:a", "aac",
+		"-b:a", "192k",
+		"-shortest",
+		"-y", opts.Output,
+	)
+
+	return o.ffmpeg.Execute(ctx, args...)
+}
+
+// buildWaveformImageFilter builds the showwavespic filter graph
+func buildWaveformImageFilter(opts WaveformImageOptions) string {
+	width := opts.Width
+	if width <= 0 {
+		width = 1920
+	}
+	height := opts.Height
+	if height <= 0 {
+		height = 240
+	}
+	color := opts.Color
+	if color == "" {
+		color = "white"
+	}
+	scale := opts.Scale
+	if scale == "" {
+		scale = "sqrt"
+	}
+
+	source := "[0:a]aformat=channel_layouts=mono,"
+	split := 0
+	if opts.SplitChannels {
+		source = "[0:a]"
+		split = 1
+	}
+
+	wave := fmt.Sprintf("%sshowwavespic=s=%dx%d:colors=%s:scale=%s:split_channels=%d", source, width, height, color, scale, split)
+	if opts.Background == "" {
+		return wave + "[out]"
+	}
+
+	return fmt.Sprintf("%s[wave];color=c=%s:s=%dx%d[bg];[bg][wave]overlay=format=auto[out]",
+		wave, opts.Background, width, height)
+}
+
+// buildAudiogramFilter builds the visualization, background and caption graph.
+// Input 0 is the audio, input 1 is the background (cover art or color source).
+func buildAudiogramFilter(opts AudiogramOptions) string {
+	width, height, fps := audiogramDimensions(opts)
+	color := opts.Color
+	if color == "" {
+		color = "white"
+	}
+
+	// The visualization occupies the lower third of the frame
+	vizHeight := (height / 3) &^ 1
+	var viz string
+	switch opts.Style {
+	case "spectrum":
+		viz = fmt.Sprintf("[0:a]showspectrum=s=%dx%d:mode=combined:slide=scroll:color=intensity:scale=cbrt,fps=%d,format=rgba,colorchannelmixer=aa=0.85[viz]",
+			width, vizHeight, fps)
+	case "bars":
+		viz = fmt.Sprintf("[0:a]showfreqs=s=%dx%d:mode=bar:ascale=sqrt:fscale=log:colors=%s,fps=%d,format=rgba[viz]",
+			width, vizHeight, color, fps)
+	default:
+		viz = fmt.Sprintf("[0:a]showwaves=s=%dx%d:mode=cline:rate=%d:colors=%s:scale=sqrt,format=rgba[viz]",
+			width, vizHeight, fps, color)
+	}
+
+	bg := fmt.Sprintf("[1:v]scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d,setsar=1[bg]",
+		width, height, width, height)
+
+	composite := fmt.Sprintf("[bg][viz]overlay=0:H-h-%d:shortest=1", height/12)
+	if opts.Subtitles != "" {
+		composite += fmt.Sprintf(",subtitles='%s':force_style='Alignment=8,MarginV=%d'", ffmpeg.EscapeFilterPath(opts.Subtitles), height/10)
+	}
+	composite += "[out]"
+
+	return strings.Join([]string{viz, bg, composite}, ";")
+}
+
+func audiogramDimensions(opts AudiogramOptions) (int, int, int) {
+	width := opts.Width
+	if width <= 0 {
+		width = 1080
+	}
+	height := opts.Height
+	if height <= 0 {
+		height = 1080
+	}
+	fps := opts.FPS
+	if fps <= 0 {
+		fps = 30
+	}
+	// libx264 with yuv420p requires even dimensions
+	return width &^ 1, height &^ 1, fps
+}
@@ -0,0 +1,36 @@
+package audio
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildWaveformImageFilter(t *testing.T) {
+	filter := buildWaveformImageFilter(WaveformImageOptions{})
+	if !strings.Contains(filter, "showwavespic=s=1920x240:colors=white") {
+		t.Errorf("Expected default size and color, got %s", filter)
+	}
+	if !strings.HasSuffix(filter, "[out]") || strings.Contains(filter, "overlay") {
+		t.Errorf("Expected transparent waveform without overlay, got %s", filter)
+	}
+
+	filter = buildWaveformImageFilter(WaveformImageOptions{Background: "navy", SplitChannels: true})
+	if !strings.Contains(filter, "split_channels=1") || !strings.Contains(filter, "color=c=navy") {
+		t.Errorf("Expected split channels over a background, got %s", filter)
+	}
+}
+
+func TestBuildAudiogramFilter(t *testing.T) {
+	filter := buildAudiogramFilter(AudiogramOptions{Width: 1081, Height: 1920, Subtitles: "C:\\clips\\captions.srt"})
+	if !strings.Contains(filter, "showwaves=s=1080x640") {
+		t.Errorf("Expected even dimensions for the waveform, got %s", filter)
+	}
+	if !strings.Contains(filter, "subtitles='C\\:/clips/captions.srt'") {
+		t.Errorf("Expected escaped subtitles path, got %s", filter)
+	}
+
+	filter = buildAudiogramFilter(AudiogramOptions{Style: "spectrum"})
+	if !strings.Contains(filter, "showspectrum") {
+		t.Errorf("Expected spectrum visualization, got %s", filter)
+	}
+}
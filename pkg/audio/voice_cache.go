@@ -0,0 +1,61 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+)
+
+// VoiceImportResult summarizes a voice cache import
+type VoiceImportResult struct {
+	Added   int      `json:"added"`
+	Updated int      `json:"updated"`
+	Skipped int      `json:"skipped"`           // Already cached, or incomplete entries
+	Missing []string `json:"missing,omitempty"` // Voice IDs not in this ElevenLabs account, dropped when verifying
+}
+
+// ExportVoiceCache writes the cached voices, with their hashes and
+// metadata, to a file another machine can import. It returns the number of
+// voices written.
+func (t *TTSOperations) ExportVoiceCache(path string) (int, error) {
+	registry := t.config.VoiceRegistry()
+	if err := config.WriteVoiceRegistry(path, registry); err != nil {
+		return 0, fmt.Errorf("failed to write voice cache: %w", err)
+	}
+	return len(registry.Voices), nil
+}
+
+// ImportVoiceCache merges an exported voice cache into this one. Voice IDs
+// belong to an ElevenLabs account, so verify drops voices the configured
+// account cannot see.
+func (t *TTSOperations) ImportVoiceCache(ctx context.Context, path string, overwrite, verify bool) (*VoiceImportResult, error) {
+	registry, err := config.ReadVoiceRegistry(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read voice cache: %w", err)
+	}
+
+	result := &VoiceImportResult{}
+	voices := registry.Voices
+	if verify {
+		if t.client == nil {
+			return nil, fmt.Errorf("ElevenLabs API key not configured; import without verify or set ELEVENLABS_API_KEY")
+		}
+		voices = nil
+		for _, voice := range registry.Voices {
+			if t.verifyVoiceExists(voice.VoiceID) {
+				voices = append(voices, voice)
+			} else {
+				result.Missing = append(result.Missing, voice.VoiceID)
+			}
+		}
+	}
+
+	result.Added, result.Updated, result.Skipped = t.config.MergeVoices(voices, overwrite)
+	if result.Added+result.Updated > 0 {
+		if err := t.config.Save(); err != nil {
+			return nil, fmt.Errorf("failed to save voice cache: %w", err)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,295 @@
+package captions
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/subtitles"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+)
+
+// Caption levels
+const (
+	LevelWord    = "word"    // Short chunks of a few words that follow speech closely
+	LevelSegment = "segment" // One caption per transcript segment
+)
+
+// Karaoke highlight animations
+const (
+	AnimationFill    = "fill" // Highlight sweeps across each word as it is spoken
+	AnimationInstant = "none" // Each word switches color when it starts
+	AnimationPop     = "pop"  // Each word switches color and briefly grows
+)
+
+// Style describes how captions look when burned in
+type Style struct {
+	FontName       string
+	FontSize       int    // At 1080p; scaled to the video
+	TextColor      string // Color of unspoken or static text
+	HighlightColor string // Karaoke color for spoken words
+	OutlineColor   string
+	Outline        float64
+	Shadow         float64
+	BoxColor       string // Opaque box behind text when set
+	Bold           bool
+	Uppercase      bool
+	Alignment      int // Numpad layout: 2 bottom-center, 1 bottom-left, 5 middle-center
+	MarginV        int // Vertical margin at 1080p
+	MarginL        int
+	Karaoke        bool   // Highlight each word as it is spoken
+	Animation      string // Karaoke highlight animation (default: fill)
+	PopScale       int    // Peak size in percent for the pop animation (default: 125)
+	MaxWords       int    // Words per caption at word level
+	DefaultLevel   string
+}
+
+// Presets are the built-in caption styles
+var Presets = map[string]Style{
+	"karaoke": {
+		FontName: "Arial Black", FontSize: 84, TextColor: "white", HighlightColor: "yellow",
+		OutlineColor: "black", Outline: 5, Shadow: 2, Bold: true, Uppercase: true,
+		Alignment: 2, MarginV: 300, Karaoke: true, MaxWords: 3, DefaultLevel: LevelWord,
+	},
+	"classic": {
+		FontName: "Arial", FontSize: 54, TextColor: "white",
+		OutlineColor: "black", Outline: 3, Shadow: 1,
+		Alignment: 2, MarginV: 60, MaxWords: 7, DefaultLevel: LevelSegment,
+	},
+	"lower-third": {
+		FontName: "Arial", FontSize: 48, TextColor: "white", BoxColor: "#1E1E1E",
+		Outline: 12, Bold: true, Alignment: 1, MarginV: 180, MarginL: 120, MaxWords: 8,
+		DefaultLevel: LevelSegment,
+	},
+}
+
+// PresetNames lists preset names in display order
+var PresetNames = []string{"karaoke", "classic", "lower-third"}
+
+// Caption is one timed caption with the words it contains
+type Caption struct {
+	Start float64
+	End   float64
+	Words []transcript.Word // Empty for segment captions without word timing
+	Text  string
+}
+
+const (
+	maxWordGap     = 0.8 // Seconds of silence that start a new caption
+	minCaptionTime = 0.3 // Shortest caption duration in seconds
+	popRiseMs      = 80  // Time for a word to grow to PopScale
+	popFallMs      = 120 // Time for a word to settle back to normal size
+)
+
+// BuildCaptions splits transcript segments into captions. At word level,
+// captions hold up to maxWords words and break at pauses and sentence ends;
+// segments without word timing are kept whole.
+func BuildCaptions(segments []transcript.Segment, level string, maxWords int) []Caption {
+	if maxWords <= 0 {
+		maxWords = 3
+	}
+
+	var captions []Caption
+	for _, seg := range segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+		if level != LevelWord || len(seg.Words) == 0 {
+			captions = append(captions, Caption{Start: seg.Start, End: seg.End, Words: cleanWords(seg.Words), Text: text})
+			continue
+		}
+
+		var chunk []transcript.Word
+		flush := func() {
+			if len(chunk) == 0 {
+				return
+			}
+			captions = append(captions, Caption{
+				Start: chunk[0].Start,
+				End:   chunk[len(chunk)-1].End,
+				Words: chunk,
+				Text:  joinWords(chunk),
+			})
+			chunk = nil
+		}
+		for _, w := range cleanWords(seg.Words) {
+			if len(chunk) > 0 && (len(chunk) >= maxWords || w.Start-chunk[len(chunk)-1].End > maxWordGap) {
+				flush()
+			}
+			chunk = append(chunk, w)
+			if strings.ContainsAny(w.Word[len(w.Word)-1:], ".?!") {
+				flush()
+			}
+		}
+		flush()
+	}
+
+	// Keep very short captions readable without overlapping the next one
+	for i := range captions {
+		if captions[i].End-captions[i].Start >= minCaptionTime {
+			continue
+		}
+		end := captions[i].Start + minCaptionTime
+		if i+1 < len(captions) && end > captions[i+1].Start {
+			end = captions[i+1].Start
+		}
+		captions[i].End = math.Max(captions[i].End, end)
+	}
+
+	return captions
+}
+
+// cleanWords trims whitespace and drops empty words
+func cleanWords(words []transcript.Word) []transcript.Word {
+	cleaned := make([]transcript.Word, 0, len(words))
+	for _, w := range words {
+		w.Word = strings.TrimSpace(w.Word)
+		if w.Word != "" {
+			cleaned = append(cleaned, w)
+		}
+	}
+	return cleaned
+}
+
+func joinWords(words []transcript.Word) string {
+	parts := make([]string, len(words))
+	for i, w := range words {
+		parts[i] = w.Word
+	}
+	return strings.Join(parts, " ")
+}
+
+// ToDocument converts captions to a plain subtitle document for soft tracks
+func ToDocument(captions []Caption, uppercase bool) *subtitles.Document {
+	doc := &subtitles.Document{Format: subtitles.FormatSRT}
+	for _, c := range captions {
+		text := c.Text
+		if uppercase {
+			text = strings.ToUpper(text)
+		}
+		doc.Cues = append(doc.Cues, subtitles.Cue{Start: c.Start, End: c.End, Text: text})
+	}
+	return doc
+}
+
+// BuildASS renders captions as an ASS script sized for a width x height video
+func BuildASS(captions []Caption, style Style, width, height int) string {
+	if width <= 0 || height <= 0 {
+		width, height = 1920, 1080
+	}
+	// Sizes are defined for 1080p and scaled by the short side so vertical video matches
+	scale := float64(min(width, height)) / 1080
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Script Info]\nScriptType: v4.00+\nPlayResX: %d\nPlayResY: %d\nWrapStyle: 0\nScaledBorderAndShadow: yes\n\n", width, height)
+
+	primary, secondary := assColor(style.TextColor, 0), assColor(style.TextColor, 0)
+	if style.Karaoke {
+		// Karaoke fills from SecondaryColour to PrimaryColour as each word is spoken
+		primary = assColor(style.HighlightColor, 0)
+	}
+	borderStyle, outlineColor, backColor := 1, assColor(style.OutlineColor, 0), assColor("black", 0x80)
+	if style.BoxColor != "" {
+		borderStyle, outlineColor, backColor = 3, assColor(style.BoxColor, 0x20), assColor(style.BoxColor, 0x20)
+	}
+	bold := 0
+	if style.Bold {
+		bold = -1
+	}
+
+	b.WriteString("[V4+ Styles]\n")
+	b.WriteString("Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n")
+	fmt.Fprintf(&b, "Style: Default,%s,%d,%s,%s,%s,%s,%d,0,0,0,100,100,0,0,%d,%.1f,%.1f,%d,%d,%d,%d,1\n\n",
+		style.FontName, scaled(style.FontSize, scale), primary, secondary, outlineColor, backColor, bold,
+		borderStyle, style.Outline*scale, style.Shadow*scale, style.Alignment,
+		scaled(max(style.MarginL, 40), scale), scaled(40, scale), scaled(style.MarginV, scale))
+
+	b.WriteString("[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+	for _, c := range captions {
+		text := captionText(c, style)
+		fmt.Fprintf(&b, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n", assTime(c.Start), assTime(c.End), text)
+	}
+
+	return b.String()
+}
+
+// captionText builds the ASS event text, with karaoke timing tags when enabled
+func captionText(c Caption, style Style) string {
+	transform := func(s string) string {
+		s = strings.NewReplacer("{", "(", "}", ")", "\n", `\N`).Replace(s)
+		if style.Uppercase {
+			s = strings.ToUpper(s)
+		}
+		return s
+	}
+
+	if !style.Karaoke || len(c.Words) == 0 {
+		return transform(c.Text)
+	}
+
+	popScale := style.PopScale
+	if popScale <= 0 {
+		popScale = 125
+	}
+
+	var b strings.Builder
+	cursor := c.Start
+	for i, w := range c.Words {
+		if gap := centiseconds(w.Start - cursor); gap > 0 {
+			fmt.Fprintf(&b, `{\k%d}`, gap)
+		}
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		duration := max(centiseconds(w.End-w.Start), 1)
+		switch style.Animation {
+		case AnimationInstant:
+			fmt.Fprintf(&b, `{\k%d}`, duration)
+		case AnimationPop:
+			// Tags carry over to later words, so reset the size before each
+			// word's own transform; \t times are relative to the line start
+			t0 := int(math.Round((w.Start - c.Start) * 1000))
+			fmt.Fprintf(&b, `{\k%d\fscx100\fscy100\t(%d,%d,\fscx%d\fscy%d)\t(%d,%d,\fscx100\fscy100)}`,
+				duration, t0, t0+popRiseMs, popScale, popScale, t0+popRiseMs, t0+popRiseMs+popFallMs)
+		default:
+			fmt.Fprintf(&b, `{\kf%d}`, duration)
+		}
+		b.WriteString(transform(w.Word))
+		cursor = math.Max(w.End, cursor)
+	}
+	return b.String()
+}
+
+func centiseconds(seconds float64) int {
+	return int(math.Round(seconds * 100))
+}
+
+func scaled(v int, scale float64) int {
+	return int(math.Round(float64(v) * scale))
+}
+
+func assTime(seconds float64) string {
+	cs := int64(math.Round(math.Max(0, seconds) * 100))
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
+
+var namedColors = map[string]string{
+	"white": "FFFFFF", "black": "000000", "yellow": "FFFF00", "red": "FF0000", "green": "00FF00",
+	"blue": "0000FF", "cyan": "00FFFF", "magenta": "FF00FF", "orange": "FFA500", "pink": "FF69B4",
+	"purple": "800080", "gray": "808080", "lime": "32CD32",
+}
+
+// assColor converts a color name or #RRGGBB to ASS &HAABBGGRR with the given alpha (0 = opaque)
+func assColor(color string, alpha int) string {
+	hex := strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(color)), "#"), "0x")
+	if named, ok := namedColors[hex]; ok {
+		hex = named
+	}
+	if _, err := strconv.ParseUint(hex, 16, 32); err != nil || len(hex) != 6 {
+		hex = "FFFFFF"
+	}
+	hex = strings.ToUpper(hex)
+	return fmt.Sprintf("&H%02X%s%s%s", alpha, hex[4:6], hex[2:4], hex[0:2])
+}
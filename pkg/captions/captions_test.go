@@ -0,0 +1,70 @@
+package captions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+)
+
+var testSegments = []transcript.Segment{
+	{
+		Text:  " Hello there everyone. Welcome back",
+		Start: 0,
+		End:   4,
+		Words: []transcript.Word{
+			{Word: "Hello", Start: 0.0, End: 0.4},
+			{Word: "there", Start: 0.5, End: 0.8},
+			{Word: "everyone.", Start: 0.9, End: 1.4},
+			{Word: "Welcome", Start: 2.8, End: 3.2},
+			{Word: "back", Start: 3.3, End: 3.6},
+		},
+	},
+}
+
+func TestBuildCaptionsWordLevel(t *testing.T) {
+	captions := BuildCaptions(testSegments, LevelWord, 2)
+	var texts []string
+	for _, c := range captions {
+		texts = append(texts, c.Text)
+	}
+	want := []string{"Hello there", "everyone.", "Welcome back"}
+	if strings.Join(texts, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected captions %v, got %v", want, texts)
+	}
+	if captions[2].Start != 2.8 || captions[2].End != 3.6 {
+		t.Errorf("Unexpected caption timing: %+v", captions[2])
+	}
+}
+
+func TestBuildCaptionsSegmentLevel(t *testing.T) {
+	captions := BuildCaptions(testSegments, LevelSegment, 0)
+	if len(captions) != 1 || captions[0].Text != "Hello there everyone. Welcome back" || captions[0].End != 4 {
+		t.Errorf("Expected one caption per segment, got %+v", captions)
+	}
+}
+
+func TestBuildASSKaraoke(t *testing.T) {
+	captions := BuildCaptions(testSegments, LevelWord, 3)
+	ass := BuildASS(captions, Presets["karaoke"], 1080, 1920)
+
+	if !strings.Contains(ass, "PlayResX: 1080\nPlayResY: 1920") || !strings.Contains(ass, "Style: Default,Arial Black,84,") {
+		t.Error("Expected script sized to the vertical video")
+	}
+	// Yellow highlight as primary, white unspoken text as secondary
+	if !strings.Contains(ass, "&H0000FFFF,&H00FFFFFF") {
+		t.Errorf("Expected karaoke colors in style:\n%s", ass)
+	}
+	if !strings.Contains(ass, `{\kf40}HELLO{\k10} {\kf30}THERE{\k10} {\kf50}EVERYONE.`) {
+		t.Errorf("Expected karaoke timing tags:\n%s", ass)
+	}
+}
+
+func TestAssColor(t *testing.T) {
+	tests := map[string]string{"white": "&H00FFFFFF", "#FF8000": "&H000080FF", "yellow": "&H0000FFFF", "bogus": "&H00FFFFFF"}
+	for input, want := range tests {
+		if got := assColor(input, 0); got != want {
+			t.Errorf("assColor(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
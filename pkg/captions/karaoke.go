@@ -0,0 +1,126 @@
+package captions
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Karaoke caption positions
+var karaokePositions = map[string]struct{ alignment, marginV int }{
+	"bottom": {2, 300},
+	"center": {5, 0},
+	"top":    {8, 200},
+}
+
+// KaraokeOptions contains options for word-by-word karaoke captions.
+// Zero values fall back to the karaoke preset.
+type KaraokeOptions struct {
+	Input          string
+	Output         string
+	FontName       string
+	FontSize       int // At 1080p; scaled to the video
+	TextColor      string
+	HighlightColor string
+	OutlineColor   string
+	Animation      string // fill, none or pop (default: pop)
+	PopScale       int    // Peak size in percent for pop (default: 125)
+	WordsPerLine   int
+	Position       string // bottom (default), center or top
+	Uppercase      *bool  // Default: true
+	Language       string
+	TranscriptPath string
+}
+
+// KaraokeStyle builds the caption style for the given options
+func KaraokeStyle(opts KaraokeOptions) (Style, error) {
+	style := Presets["karaoke"]
+	style.Animation = AnimationPop
+
+	if opts.FontName != "" {
+		style.FontName = opts.FontName
+	}
+	if opts.FontSize > 0 {
+		style.FontSize = opts.FontSize
+	}
+	if opts.TextColor != "" {
+		style.TextColor = opts.TextColor
+	}
+	if opts.HighlightColor != "" {
+		style.HighlightColor = opts.HighlightColor
+	}
+	if opts.OutlineColor != "" {
+		style.OutlineColor = opts.OutlineColor
+	}
+	if opts.Animation != "" {
+		switch opts.Animation {
+		case AnimationFill, AnimationInstant, AnimationPop:
+			style.Animation = opts.Animation
+		default:
+			return Style{}, fmt.Errorf("unknown animation: %s (use pop, fill or none)", opts.Animation)
+		}
+	}
+	if opts.PopScale > 0 {
+		style.PopScale = opts.PopScale
+	}
+	if opts.WordsPerLine > 0 {
+		style.MaxWords = opts.WordsPerLine
+	}
+	if opts.Position != "" {
+		pos, ok := karaokePositions[opts.Position]
+		if !ok {
+			return Style{}, fmt.Errorf("unknown position: %s (use bottom, center or top)", opts.Position)
+		}
+		style.Alignment, style.MarginV = pos.alignment, pos.marginV
+	}
+	if opts.Uppercase != nil {
+		style.Uppercase = *opts.Uppercase
+	}
+	return style, nil
+}
+
+// Karaoke burns word-by-word captions that highlight each word as it is
+// spoken. Word timings come from the transcript, so one is extracted with
+// Whisper unless TranscriptPath is given.
+func (p *Pipeline) Karaoke(ctx context.Context, opts KaraokeOptions) (*Result, error) {
+	style, err := KaraokeStyle(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := p.videoOps.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+
+	base := strings.TrimSuffix(opts.Output, filepath.Ext(opts.Output))
+	result := &Result{
+		Output:       opts.Output,
+		Preset:       "karaoke",
+		Level:        LevelWord,
+		Mode:         ModeBurn,
+		CaptionsFile: base + ".karaoke.ass",
+	}
+
+	trans, transcriptFile, err := p.loadTranscript(ctx, opts.Input, opts.TranscriptPath, opts.Language, base, info.HasAudio)
+	if err != nil {
+		return nil, err
+	}
+	result.TranscriptFile = transcriptFile
+	result.Language = trans.Language
+
+	if !hasWordTimings(trans.Segments) {
+		return nil, fmt.Errorf("transcript has no word timings to highlight")
+	}
+	captions := BuildCaptions(trans.Segments, LevelWord, style.MaxWords)
+	if len(captions) == 0 {
+		return nil, fmt.Errorf("no speech found to caption")
+	}
+	result.Captions = len(captions)
+
+	if err := p.burn(ctx, opts.Input, opts.Output, result.CaptionsFile, BuildASS(captions, style, info.Width, info.Height)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
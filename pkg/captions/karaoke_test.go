@@ -0,0 +1,44 @@
+package captions
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKaraokeStyle(t *testing.T) {
+	style, err := KaraokeStyle(KaraokeOptions{HighlightColor: "#00FF00", Position: "top", WordsPerLine: 4})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if style.Animation != AnimationPop || style.HighlightColor != "#00FF00" || style.Alignment != 8 || style.MaxWords != 4 {
+		t.Errorf("Unexpected style: %+v", style)
+	}
+	if !style.Uppercase || style.FontName != "Arial Black" {
+		t.Errorf("Expected karaoke preset defaults, got %+v", style)
+	}
+
+	if _, err := KaraokeStyle(KaraokeOptions{Animation: "spin"}); err == nil {
+		t.Error("Expected error for unknown animation")
+	}
+	if _, err := KaraokeStyle(KaraokeOptions{Position: "left"}); err == nil {
+		t.Error("Expected error for unknown position")
+	}
+}
+
+func TestCaptionTextAnimations(t *testing.T) {
+	caption := BuildCaptions(testSegments, LevelWord, 2)[0]
+
+	style := Presets["karaoke"]
+	style.Animation = AnimationInstant
+	if got := captionText(caption, style); got != `{\k40}HELLO{\k10} {\k30}THERE` {
+		t.Errorf("Unexpected instant text: %s", got)
+	}
+
+	style.Animation = AnimationPop
+	style.PopScale = 130
+	got := captionText(caption, style)
+	want := `{\k30\fscx100\fscy100\t(500,580,\fscx130\fscy130)\t(580,700,\fscx100\fscy100)}THERE`
+	if !strings.Contains(got, want) {
+		t.Errorf("Expected pop tags %s in %s", want, got)
+	}
+}
@@ -0,0 +1,216 @@
+package captions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/text"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/workspace"
+)
+
+// Caption delivery modes
+const (
+	ModeBurn   = "burn"   // Render captions into the picture with the preset style
+	ModeAttach = "attach" // Add a toggleable soft subtitle track
+)
+
+// Options contains options for the auto-caption pipeline
+type Options struct {
+	Input          string
+	Output         string
+	Mode           string // burn (default) or attach
+	Preset         string // karaoke, classic (default) or lower-third
+	Level          string // word or segment (default: the preset's level)
+	MaxWords       int    // Words per caption at word level (default: the preset's)
+	Language       string // Transcription language hint (optional)
+	TranscriptPath string // Reuse a saved transcript JSON instead of transcribing
+}
+
+// Result describes the files produced by the pipeline
+type Result struct {
+	Output         string   `json:"output"`
+	CaptionsFile   string   `json:"captionsFile"`
+	TranscriptFile string   `json:"transcriptFile"`
+	Preset         string   `json:"preset"`
+	Level          string   `json:"level"`
+	Mode           string   `json:"mode"`
+	Captions       int      `json:"captions"`
+	Language       string   `json:"language,omitempty"`
+	Warnings       []string `json:"warnings,omitempty"`
+}
+
+// Pipeline transcribes a video, formats captions and burns or attaches them
+type Pipeline struct {
+	videoOps      *video.Operations
+	textOps       *text.Operations
+	transcriptOps *transcript.Operations
+}
+
+// NewPipeline creates a new auto-caption pipeline
+func NewPipeline(videoOps *video.Operations, textOps *text.Operations, transcriptOps *transcript.Operations) *Pipeline {
+	return &Pipeline{
+		videoOps:      videoOps,
+		textOps:       textOps,
+		transcriptOps: transcriptOps,
+	}
+}
+
+// AutoCaption runs transcription, caption formatting and rendering in one call.
+// The transcript and caption file are saved next to the output for reuse,
+// numbered rather than overwriting files already there.
+func (p *Pipeline) AutoCaption(ctx context.Context, opts Options) (*Result, error) {
+	presetName := opts.Preset
+	if presetName == "" {
+		presetName = "classic"
+	}
+	style, ok := Presets[presetName]
+	if !ok {
+		return nil, fmt.Errorf("unknown caption preset: %s (use %s)", presetName, strings.Join(PresetNames, ", "))
+	}
+
+	mode := opts.Mode
+	if mode == "" {
+		mode = ModeBurn
+	}
+	if mode != ModeBurn && mode != ModeAttach {
+		return nil, fmt.Errorf("unknown mode: %s (use burn or attach)", mode)
+	}
+
+	level := opts.Level
+	if level == "" {
+		level = style.DefaultLevel
+	}
+	if level != LevelWord && level != LevelSegment {
+		return nil, fmt.Errorf("unknown caption level: %s (use word or segment)", level)
+	}
+	maxWords := opts.MaxWords
+	if maxWords <= 0 {
+		maxWords = style.MaxWords
+	}
+
+	info, err := p.videoOps.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+
+	base := strings.TrimSuffix(opts.Output, filepath.Ext(opts.Output))
+	result := &Result{
+		Output: opts.Output,
+		Preset: presetName,
+		Level:  level,
+		Mode:   mode,
+	}
+
+	// Step 1: transcript
+	trans, transcriptFile, err := p.loadTranscript(ctx, opts.Input, opts.TranscriptPath, opts.Language, base, info.HasAudio)
+	if err != nil {
+		return nil, err
+	}
+	result.TranscriptFile = transcriptFile
+	result.Language = trans.Language
+
+	// Step 2: captions
+	if level == LevelWord && !hasWordTimings(trans.Segments) {
+		result.Warnings = append(result.Warnings, "transcript has no word timings; using segment captions")
+		level = LevelSegment
+		result.Level = level
+	}
+	captions := BuildCaptions(trans.Segments, level, maxWords)
+	if len(captions) == 0 {
+		return nil, fmt.Errorf("no speech found to caption")
+	}
+	result.Captions = len(captions)
+
+	// Step 3: burn or attach
+	if mode == ModeBurn {
+		if result.CaptionsFile, err = workspace.ReservePath(base + ".captions.ass"); err != nil {
+			return nil, err
+		}
+		if err := p.burn(ctx, opts.Input, opts.Output, result.CaptionsFile, BuildASS(captions, style, info.Width, info.Height)); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	if style.Karaoke {
+		result.Warnings = append(result.Warnings, "soft subtitle tracks cannot highlight words; karaoke styling applies only when burning")
+	}
+	if result.CaptionsFile, err = workspace.ReservePath(base + ".captions.srt"); err != nil {
+		return nil, err
+	}
+	if err := ToDocument(captions, style.Uppercase).WriteFile(result.CaptionsFile); err != nil {
+		return nil, fmt.Errorf("failed to write captions: %w", err)
+	}
+	if err := p.videoOps.AttachSubtitles(ctx, video.AttachSubtitlesOptions{
+		Input:  opts.Input,
+		Output: opts.Output,
+		Tracks: []video.SubtitleTrack{{
+			Path:     result.CaptionsFile,
+			Language: trans.Language,
+			Title:    "Captions",
+			Default:  true,
+		}},
+		KeepExisting: true,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to attach captions: %w", err)
+	}
+
+	return result, nil
+}
+
+// loadTranscript loads a saved transcript, or transcribes the input and saves
+// the result to base.transcript.json. It returns the transcript file used.
+func (p *Pipeline) loadTranscript(ctx context.Context, input, transcriptPath, language, base string, hasAudio bool) (*transcript.Transcript, string, error) {
+	if transcriptPath != "" {
+		trans, err := p.transcriptOps.LoadTranscript(transcriptPath)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load transcript: %w", err)
+		}
+		return trans, transcriptPath, nil
+	}
+
+	if !hasAudio {
+		return nil, "", fmt.Errorf("video has no audio track to transcribe")
+	}
+	trans, err := p.transcriptOps.ExtractTranscript(ctx, input, language)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to transcribe: %w", err)
+	}
+	transcriptFile, err := workspace.ReservePath(base + ".transcript.json")
+	if err != nil {
+		return nil, "", err
+	}
+	if err := p.transcriptOps.SaveTranscript(trans, transcriptFile); err != nil {
+		return nil, "", fmt.Errorf("failed to save transcript: %w", err)
+	}
+	return trans, transcriptFile, nil
+}
+
+// burn writes an ASS script and renders it into the video
+func (p *Pipeline) burn(ctx context.Context, input, output, captionsFile, script string) error {
+	if err := os.WriteFile(captionsFile, []byte(script), 0644); err != nil {
+		return fmt.Errorf("failed to write captions: %w", err)
+	}
+	if err := p.textOps.BurnSubtitles(ctx, text.SubtitleOptions{
+		Input:        input,
+		Output:       output,
+		SubtitleFile: captionsFile,
+	}); err != nil {
+		return fmt.Errorf("failed to burn captions: %w", err)
+	}
+	return nil
+}
+
+func hasWordTimings(segments []transcript.Segment) bool {
+	for _, seg := range segments {
+		if len(seg.Words) > 0 {
+			return true
+		}
+	}
+	return false
+}
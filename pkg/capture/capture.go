@@ -0,0 +1,396 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/google/uuid"
+)
+
+const (
+	// startupCheck is how long Start waits for FFmpeg to fail on a missing
+	// device or a denied permission before reporting the recording started
+	startupCheck = 1500 * time.Millisecond
+
+	// stopGrace is how long FFmpeg gets to finish the file after being
+	// asked to quit
+	stopGrace = 15 * time.Second
+
+	// pipMargin is the webcam's distance from the frame edges, in pixels
+	pipMargin = 20
+)
+
+// Region is the part of the screen to record, in screen pixels
+type Region struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"width"`
+	H int `json:"height"`
+}
+
+// Options contains options for a screen recording
+type Options struct {
+	Output         string
+	FPS            int     // Capture frame rate (default: 30)
+	Region         *Region // Part of the screen to record (default: all of it)
+	Display        string  // Screen to record: index on macOS, X display on Linux, "desktop" or "title=<window>" on Windows
+	Microphone     string  // "default" or a device to record from ("" = no audio)
+	Webcam         string  // "default" or a device to show picture-in-picture ("" = none)
+	WebcamPosition string  // top-left, top-right, bottom-left or bottom-right (default)
+	WebcamWidth    float64 // Webcam width as a fraction of the frame width (default: 0.25)
+	MaxDuration    float64 // Stop on its own after this many seconds (0 = until stopped)
+}
+
+// Session is a recording in progress or finished
+type Session struct {
+	ID      string    `json:"id"`
+	Output  string    `json:"output"`
+	Started time.Time `json:"started"`
+	Running bool      `json:"running"`
+
+	process *ffmpeg.Process
+}
+
+// Result describes a finished recording
+type Result struct {
+	ID       string  `json:"id"`
+	Output   string  `json:"output"`
+	Duration float64 `json:"duration"`
+}
+
+// Recorder runs screen recordings in the background
+type Recorder struct {
+	ffmpeg   *ffmpeg.Manager
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+// NewRecorder creates a new recorder
+func NewRecorder(ffmpegMgr *ffmpeg.Manager) *Recorder {
+	return &Recorder{
+		ffmpeg:   ffmpegMgr,
+		sessions: make(map[string]*Session),
+	}
+}
+
+// Start begins recording the screen and returns once FFmpeg is capturing.
+// The recording runs until Stop is called or MaxDuration passes.
+func (r *Recorder) Start(opts Options) (*Session, error) {
+	if opts.Output == "" {
+		return nil, fmt.Errorf("output path is required")
+	}
+	args, err := buildCaptureArgs(runtime.GOOS, opts, os.Getenv("DISPLAY"))
+	if err != nil {
+		return nil, err
+	}
+	process, err := r.ffmpeg.Start(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	// Devices that are missing or not permitted fail right away
+	select {
+	case <-process.Done():
+		if err := process.Err(); err != nil {
+			return nil, fmt.Errorf("recording failed to start: %w\nOutput: %s", err, lastLines(process.Output(), 10))
+		}
+		return nil, fmt.Errorf("recording ended as soon as it started\nOutput: %s", lastLines(process.Output(), 10))
+	case <-time.After(startupCheck):
+	}
+
+	session := &Session{
+		ID:      uuid.New().String(),
+		Output:  opts.Output,
+		Started: time.Now(),
+		process: process,
+	}
+	r.mu.Lock()
+	r.sessions[session.ID] = session
+	r.mu.Unlock()
+	return session.snapshot(), nil
+}
+
+// Stop ends a recording, letting FFmpeg finish writing the file, and
+// forgets the session. A recording that already ended on its own is
+// reported the same way.
+func (r *Recorder) Stop(id string) (*Result, error) {
+	r.mu.Lock()
+	session, ok := r.sessions[id]
+	if ok {
+		delete(r.sessions, id)
+	}
+	r.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no recording with id %s", id)
+	}
+
+	duration := session.process.Elapsed().Seconds()
+	if err := session.process.Stop(stopGrace); err != nil {
+		if info, statErr := os.Stat(session.Output); statErr != nil || info.Size() == 0 {
+			return nil, fmt.Errorf("%w\nOutput: %s", err, lastLines(session.process.Output(), 10))
+		}
+		// FFmpeg exits non-zero when a device disappears mid-recording, but
+		// what it wrote up to then is still usable
+	}
+	return &Result{
+		ID:       session.ID,
+		Output:   session.Output,
+		Duration: duration,
+	}, nil
+}
+
+// List returns the recordings that haven't been stopped, oldest first
+func (r *Recorder) List() []*Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sessions := make([]*Session, 0, len(r.sessions))
+	for _, session := range r.sessions {
+		sessions = append(sessions, session.snapshot())
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Started.Before(sessions[j].Started) })
+	return sessions
+}
+
+// StopAll stops every recording, so none is left running when the server
+// shuts down
+func (r *Recorder) StopAll() {
+	r.mu.Lock()
+	ids := make([]string, 0, len(r.sessions))
+	for id := range r.sessions {
+		ids = append(ids, id)
+	}
+	r.mu.Unlock()
+	for _, id := range ids {
+		r.Stop(id)
+	}
+}
+
+// snapshot copies the session with its current running state
+func (s *Session) snapshot() *Session {
+	copied := *s
+	select {
+	case <-s.process.Done():
+		copied.Running = false
+	default:
+		copied.Running = true
+	}
+	return &copied
+}
+
+// buildCaptureArgs returns the FFmpeg arguments that record the screen on
+// goos, with the webcam overlaid and the microphone mixed in if asked for.
+// display is the X display to fall back to on Linux.
+func buildCaptureArgs(goos string, opts Options, display string) ([]string, error) {
+	args, mic, err := captureSource(goos, opts, display)
+	if err != nil {
+		return nil, err
+	}
+	// Fast enough to keep up in real time; re-encode later to shrink it
+	args = append(args,
+		"-c:v", "libx264",
+		"-preset", "ultrafast",
+		"-crf", "23",
+		"-pix_fmt", "yuv420p",
+		"-r", strconv.Itoa(captureFPS(opts)),
+	)
+	if mic >= 0 {
+		args = append(args, "-map", fmt.Sprintf("%d:a", mic), "-c:a", "aac", "-b:a", "160k")
+	}
+	if opts.MaxDuration > 0 {
+		args = append(args, "-t", fmt.Sprintf("%.3f", opts.MaxDuration))
+	}
+	return append(args, "-y", opts.Output), nil
+}
+
+// captureFPS returns the capture frame rate, 30 unless set
+func captureFPS(opts Options) int {
+	if opts.FPS == 0 {
+		return 30
+	}
+	return opts.FPS
+}
+
+// captureSource returns the input options for the screen, webcam and
+// microphone and the video mapping, ending in [vout] or 0:v, and the input
+// index of the microphone (-1 for none), for the caller to encode
+func captureSource(goos string, opts Options, display string) ([]string, int, error) {
+	fps := captureFPS(opts)
+	if fps < 1 || fps > 120 {
+		return nil, -1, fmt.Errorf("fps must be between 1 and 120")
+	}
+	if r := opts.Region; r != nil && (r.W < 16 || r.H < 16 || r.X < 0 || r.Y < 0) {
+		return nil, -1, fmt.Errorf("region must be at least 16x16 pixels at a non-negative position")
+	}
+	rate := strconv.Itoa(fps)
+
+	// Live inputs need a deep queue so a slow encoder doesn't drop frames
+	args := []string{"-thread_queue_size", "1024"}
+	crop := ""
+	switch goos {
+	case "darwin":
+		screen := opts.Display
+		if screen == "" {
+			screen = "0"
+		}
+		args = append(args, "-f", "avfoundation", "-capture_cursor", "1", "-framerate", rate, "-i", "Capture screen "+screen)
+		if r := opts.Region; r != nil {
+			crop = fmt.Sprintf("crop=%d:%d:%d:%d", r.W/2*2, r.H/2*2, r.X, r.Y)
+		}
+	case "linux":
+		screen := opts.Display
+		if screen == "" {
+			screen = display
+		}
+		if screen == "" {
+			screen = ":0.0"
+		}
+		args = append(args, "-f", "x11grab", "-framerate", rate, "-draw_mouse", "1")
+		if r := opts.Region; r != nil {
+			args = append(args, "-video_size", fmt.Sprintf("%dx%d", r.W/2*2, r.H/2*2))
+			screen = fmt.Sprintf("%s+%d,%d", screen, r.X, r.Y)
+		}
+		args = append(args, "-i", screen)
+	case "windows":
+		screen := opts.Display
+		if screen == "" {
+			screen = "desktop"
+		}
+		args = append(args, "-f", "gdigrab", "-framerate", rate, "-draw_mouse", "1")
+		if r := opts.Region; r != nil {
+			args = append(args,
+				"-offset_x", strconv.Itoa(r.X),
+				"-offset_y", strconv.Itoa(r.Y),
+				"-video_size", fmt.Sprintf("%dx%d", r.W/2*2, r.H/2*2),
+			)
+		}
+		args = append(args, "-i", screen)
+	default:
+		return nil, -1, fmt.Errorf("screen recording isn't supported on %s", goos)
+	}
+
+	inputs := 1
+	webcam := -1
+	if opts.Webcam != "" {
+		device, err := webcamInput(goos, opts.Webcam)
+		if err != nil {
+			return nil, -1, err
+		}
+		args = append(args, "-thread_queue_size", "1024")
+		args = append(args, device...)
+		webcam = inputs
+		inputs++
+	}
+	mic := -1
+	if opts.Microphone != "" {
+		device, err := microphoneInput(goos, opts.Microphone)
+		if err != nil {
+			return nil, -1, err
+		}
+		args = append(args, "-thread_queue_size", "1024")
+		args = append(args, device...)
+		mic = inputs
+	}
+
+	if webcam >= 0 || crop != "" {
+		filter, err := buildCaptureFilter(opts, crop, webcam)
+		if err != nil {
+			return nil, -1, err
+		}
+		args = append(args, "-filter_complex", filter, "-map", "[vout]")
+	} else {
+		args = append(args, "-map", "0:v", "-vf", "scale=trunc(iw/2)*2:trunc(ih/2)*2")
+	}
+	return args, mic, nil
+}
+
+// webcamInput returns the input options for a camera, "default" being the
+// first one where the platform has a sensible first camera
+func webcamInput(goos, device string) ([]string, error) {
+	switch goos {
+	case "darwin":
+		if device == "default" {
+			device = "0"
+		}
+		return []string{"-f", "avfoundation", "-framerate", "30", "-i", device}, nil
+	case "linux":
+		if device == "default" {
+			device = "/dev/video0"
+		}
+		return []string{"-f", "v4l2", "-i", device}, nil
+	default:
+		if device == "default" {
+			return nil, fmt.Errorf("name the webcam on Windows (list them with: ffmpeg -list_devices true -f dshow -i dummy)")
+		}
+		return []string{"-f", "dshow", "-i", "video=" + device}, nil
+	}
+}
+
+// microphoneInput returns the input options for a microphone, "default"
+// being the system's default input where the platform has one
+func microphoneInput(goos, device string) ([]string, error) {
+	switch goos {
+	case "darwin":
+		if device == "default" {
+			device = "0"
+		}
+		return []string{"-f", "avfoundation", "-i", ":" + strings.TrimPrefix(device, ":")}, nil
+	case "linux":
+		return []string{"-f", "pulse", "-i", device}, nil
+	default:
+		if device == "default" {
+			return nil, fmt.Errorf("name the microphone on Windows (list them with: ffmpeg -list_devices true -f dshow -i dummy)")
+		}
+		return []string{"-f", "dshow", "-i", "audio=" + device}, nil
+	}
+}
+
+// buildCaptureFilter returns the graph, ending in [vout], that crops the
+// screen and overlays the webcam in a corner
+func buildCaptureFilter(opts Options, crop string, webcam int) (string, error) {
+	screen := "scale=trunc(iw/2)*2:trunc(ih/2)*2"
+	if crop != "" {
+		screen = crop
+	}
+	if webcam < 0 {
+		return fmt.Sprintf("[0:v]%s[vout]", screen), nil
+	}
+
+	width := opts.WebcamWidth
+	if width == 0 {
+		width = 0.25
+	}
+	if width <= 0 || width > 0.5 {
+		return "", fmt.Errorf("webcam width must be a fraction of the frame up to 0.5")
+	}
+	var x, y string
+	switch opts.WebcamPosition {
+	case "top-left":
+		x, y = strconv.Itoa(pipMargin), strconv.Itoa(pipMargin)
+	case "top-right":
+		x, y = fmt.Sprintf("W-w-%d", pipMargin), strconv.Itoa(pipMargin)
+	case "bottom-left":
+		x, y = strconv.Itoa(pipMargin), fmt.Sprintf("H-h-%d", pipMargin)
+	case "", "bottom-right":
+		x, y = fmt.Sprintf("W-w-%d", pipMargin), fmt.Sprintf("H-h-%d", pipMargin)
+	default:
+		return "", fmt.Errorf("unknown webcam position %q (use top-left, top-right, bottom-left or bottom-right)", opts.WebcamPosition)
+	}
+	return fmt.Sprintf("[0:v]%s[screen];[%d:v][screen]scale2ref=w='trunc(main_w*%.3f/2)*2':h='trunc(ow/a/2)*2'[cam][base];[base][cam]overlay=x=%s:y=%s:shortest=1[vout]",
+		screen, webcam, width, x, y), nil
+}
+
+// lastLines returns the end of FFmpeg's log, where the error is
+func lastLines(output string, n int) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,77 @@
+package capture
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildCaptureArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		opts Options
+		want []string
+	}{
+		{
+			name: "macOS region with microphone",
+			goos: "darwin",
+			opts: Options{Output: "out.mp4", Region: &Region{X: 10, Y: 20, W: 801, H: 600}, Microphone: "default"},
+			want: []string{
+				"-f avfoundation -capture_cursor 1 -framerate 30 -i Capture screen 0",
+				"-f avfoundation -i :0",
+				"[0:v]crop=800:600:10:20[vout]",
+				"-map 1:a -c:a aac",
+			},
+		},
+		{
+			name: "Linux region",
+			goos: "linux",
+			opts: Options{Output: "out.mp4", FPS: 60, Region: &Region{X: 100, Y: 50, W: 1280, H: 720}},
+			want: []string{
+				"-f x11grab -framerate 60 -draw_mouse 1 -video_size 1280x720 -i :1+100,50",
+				"-map 0:v -vf scale=trunc(iw/2)*2:trunc(ih/2)*2",
+			},
+		},
+		{
+			name: "Windows webcam and microphone",
+			goos: "windows",
+			opts: Options{Output: "out.mp4", Webcam: "HD Webcam", Microphone: "Mic", WebcamPosition: "top-left", MaxDuration: 90},
+			want: []string{
+				"-f gdigrab -framerate 30 -draw_mouse 1 -i desktop",
+				"-f dshow -i video=HD Webcam",
+				"-f dshow -i audio=Mic",
+				"[1:v][screen]scale2ref=w='trunc(main_w*0.250/2)*2'",
+				"overlay=x=20:y=20:shortest=1[vout]",
+				"-map 2:a",
+				"-t 90.000 -y out.mp4",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := buildCaptureArgs(tt.goos, tt.opts, ":1")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			joined := strings.Join(args, " ")
+			for _, want := range tt.want {
+				if !strings.Contains(joined, want) {
+					t.Errorf("Arguments %q missing %q", joined, want)
+				}
+			}
+		})
+	}
+
+	for _, opts := range []Options{
+		{Output: "out.mp4", Webcam: "default"},
+		{Output: "out.mp4", FPS: 500},
+		{Output: "out.mp4", Region: &Region{W: 8, H: 8}},
+	} {
+		if _, err := buildCaptureArgs("windows", opts, ""); err == nil {
+			t.Errorf("Expected an error for %+v", opts)
+		}
+	}
+	if _, err := buildCaptureArgs("plan9", Options{Output: "out.mp4"}, ""); err == nil {
+		t.Error("Expected an error for an unsupported platform")
+	}
+}
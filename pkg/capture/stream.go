@@ -0,0 +1,362 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultStreamRetries is how many times a dropped stream reconnects
+	defaultStreamRetries = 5
+
+	// stableStream is how long a connection must last for its drop to be
+	// treated as a new failure rather than another of the same
+	stableStream = time.Minute
+)
+
+// streamPreset holds an ingest endpoint and its recommended video bitrate
+type streamPreset struct {
+	url     string // Ingest URL the stream key is appended to
+	bitrate int    // Video bitrate in kbit/s
+}
+
+// streamPresets are the platforms whose ingest settings are built in. Both
+// want H.264 with a keyframe every 2 seconds and AAC audio over RTMP.
+var streamPresets = map[string]streamPreset{
+	"youtube": {url: "rtmp://a.rtmp.youtube.com/live2/", bitrate: 6000},
+	"twitch":  {url: "rtmp://live.twitch.tv/app/", bitrate: 6000},
+}
+
+// StreamOptions contains options for a live stream
+type StreamOptions struct {
+	Input      string   // File to stream ("" to stream a screen capture)
+	Capture    *Options // Screen capture to stream when there is no Input (its Output is ignored)
+	URL        string   // rtmp://, rtmps:// or srt:// endpoint, for a custom destination
+	Platform   string   // youtube or twitch, instead of a URL
+	StreamKey  string   // Stream key for the platform
+	Bitrate    int      // Video bitrate in kbit/s (default: the platform's, or 4500)
+	Loop       bool     // Repeat the file until stopped
+	MaxRetries int      // Reconnects after the connection drops (default: 5; -1 = none)
+}
+
+// Stream is a live stream in progress or finished
+type Stream struct {
+	ID         string    `json:"id"`
+	Source     string    `json:"source"`
+	Target     string    `json:"target"` // Endpoint without the stream key
+	Started    time.Time `json:"started"`
+	Running    bool      `json:"running"`
+	Reconnects int       `json:"reconnects"`
+	LastError  string    `json:"lastError,omitempty"`
+
+	mu      sync.Mutex
+	process *ffmpeg.Process
+	stopped bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// Streamer runs live streams in the background and reconnects them when
+// the connection drops
+type Streamer struct {
+	ffmpeg  *ffmpeg.Manager
+	mu      sync.Mutex
+	streams map[string]*Stream
+}
+
+// NewStreamer creates a new streamer
+func NewStreamer(ffmpegMgr *ffmpeg.Manager) *Streamer {
+	return &Streamer{
+		ffmpeg:  ffmpegMgr,
+		streams: make(map[string]*Stream),
+	}
+}
+
+// Start begins streaming and returns once FFmpeg is sending. A file is sent
+// at its own pace; when the connection drops, the stream reconnects and a
+// file resumes about where it left off.
+func (s *Streamer) Start(opts StreamOptions) (*Stream, error) {
+	target, err := streamTarget(opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Input == "" && opts.Capture == nil {
+		return nil, fmt.Errorf("input file or capture is required")
+	}
+	args, err := buildStreamArgs(runtime.GOOS, opts, target, 0, os.Getenv("DISPLAY"))
+	if err != nil {
+		return nil, err
+	}
+
+	process, err := s.ffmpeg.Start(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+	// A wrong key or an unreachable server usually fails right away
+	select {
+	case <-process.Done():
+		if err := process.Err(); err != nil {
+			return nil, fmt.Errorf("stream failed to start: %w\nOutput: %s", err, redactKey(lastLines(process.Output(), 10), opts.StreamKey))
+		}
+		return nil, fmt.Errorf("stream ended as soon as it started\nOutput: %s", redactKey(lastLines(process.Output(), 10), opts.StreamKey))
+	case <-time.After(startupCheck):
+	}
+
+	source := opts.Input
+	if source == "" {
+		source = "screen capture"
+	}
+	stream := &Stream{
+		ID:      uuid.New().String(),
+		Source:  source,
+		Target:  redactKey(target, opts.StreamKey),
+		Started: time.Now(),
+		process: process,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.supervise(stream, opts, target)
+
+	s.mu.Lock()
+	s.streams[stream.ID] = stream
+	s.mu.Unlock()
+	return stream.snapshot(), nil
+}
+
+// supervise restarts the stream after the connection drops, backing off
+// between attempts, until it ends cleanly, is stopped or runs out of
+// retries
+func (s *Streamer) supervise(stream *Stream, opts StreamOptions, target string) {
+	defer close(stream.done)
+	retries := opts.MaxRetries
+	if retries == 0 {
+		retries = defaultStreamRetries
+	}
+	offset := 0.0
+	failures := 0
+	for {
+		stream.mu.Lock()
+		process := stream.process
+		stream.mu.Unlock()
+		<-process.Done()
+
+		stream.mu.Lock()
+		if stream.stopped || process.Err() == nil {
+			stream.mu.Unlock()
+			return
+		}
+		stream.LastError = redactKey(strings.TrimSpace(lastLines(process.Output(), 1)), opts.StreamKey)
+		if process.Elapsed() >= stableStream {
+			failures = 0
+		}
+		failures++
+		if failures > retries {
+			stream.mu.Unlock()
+			return
+		}
+		stream.mu.Unlock()
+
+		// Files are sent in real time, so the time sent is the position
+		offset += process.Elapsed().Seconds()
+		select {
+		case <-time.After(min(time.Duration(failures)*2*time.Second, 30*time.Second)):
+		case <-stream.stop:
+			return
+		}
+
+		// The options were checked when the stream started
+		args, _ := buildStreamArgs(runtime.GOOS, opts, target, offset, os.Getenv("DISPLAY"))
+		stream.mu.Lock()
+		if stream.stopped {
+			stream.mu.Unlock()
+			return
+		}
+		next, err := s.ffmpeg.Start(args...)
+		if err != nil {
+			stream.LastError = err.Error()
+			stream.mu.Unlock()
+			return
+		}
+		stream.process = next
+		stream.Reconnects++
+		stream.mu.Unlock()
+	}
+}
+
+// Stop ends a stream and forgets it
+func (s *Streamer) Stop(id string) (*Stream, error) {
+	s.mu.Lock()
+	stream, ok := s.streams[id]
+	if ok {
+		delete(s.streams, id)
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no stream with id %s", id)
+	}
+
+	stream.mu.Lock()
+	stream.stopped = true
+	process := stream.process
+	stream.mu.Unlock()
+	close(stream.stop)
+	process.Stop(stopGrace)
+	<-stream.done
+	return stream.snapshot(), nil
+}
+
+// List returns the streams that haven't been stopped, oldest first
+func (s *Streamer) List() []*Stream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	streams := make([]*Stream, 0, len(s.streams))
+	for _, stream := range s.streams {
+		streams = append(streams, stream.snapshot())
+	}
+	sort.Slice(streams, func(i, j int) bool { return streams[i].Started.Before(streams[j].Started) })
+	return streams
+}
+
+// StopAll stops every stream, so none is left running when the server
+// shuts down
+func (s *Streamer) StopAll() {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.streams))
+	for id := range s.streams {
+		ids = append(ids, id)
+	}
+	s.mu.Unlock()
+	for _, id := range ids {
+		s.Stop(id)
+	}
+}
+
+// snapshot copies the stream's exported state
+func (s *Stream) snapshot() *Stream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	running := true
+	select {
+	case <-s.done:
+		running = false
+	default:
+	}
+	return &Stream{
+		ID:         s.ID,
+		Source:     s.Source,
+		Target:     s.Target,
+		Started:    s.Started,
+		Running:    running,
+		Reconnects: s.Reconnects,
+		LastError:  s.LastError,
+	}
+}
+
+// streamTarget returns the endpoint to send to: the platform's ingest URL
+// with the stream key, or the custom URL
+func streamTarget(opts StreamOptions) (string, error) {
+	if opts.Platform != "" {
+		preset, ok := streamPresets[strings.ToLower(opts.Platform)]
+		if !ok {
+			return "", fmt.Errorf("unknown platform %q (use youtube or twitch, or a url)", opts.Platform)
+		}
+		if opts.StreamKey == "" {
+			return "", fmt.Errorf("streamKey is required for %s", opts.Platform)
+		}
+		return preset.url + opts.StreamKey, nil
+	}
+	if opts.URL == "" {
+		return "", fmt.Errorf("url or platform is required")
+	}
+	if streamFormat(opts.URL) == "" {
+		return "", fmt.Errorf("url must start with rtmp://, rtmps:// or srt://")
+	}
+	if opts.StreamKey != "" {
+		return strings.TrimRight(opts.URL, "/") + "/" + opts.StreamKey, nil
+	}
+	return opts.URL, nil
+}
+
+// streamFormat returns the container for an endpoint: FLV over RTMP,
+// MPEG-TS over SRT
+func streamFormat(url string) string {
+	switch {
+	case strings.HasPrefix(url, "rtmp://"), strings.HasPrefix(url, "rtmps://"):
+		return "flv"
+	case strings.HasPrefix(url, "srt://"):
+		return "mpegts"
+	}
+	return ""
+}
+
+// redactKey hides the stream key in text shown to the user, such as the
+// endpoint or FFmpeg's log
+func redactKey(text, key string) string {
+	if key == "" {
+		return text
+	}
+	return strings.ReplaceAll(text, key, "<stream key>")
+}
+
+// buildStreamArgs returns the FFmpeg arguments that send a file, from
+// offset seconds in, or a screen capture to target, encoded the way live
+// platforms expect: constant bitrate H.264 with 2 second keyframes and
+// 48 kHz stereo AAC
+func buildStreamArgs(goos string, opts StreamOptions, target string, offset float64, display string) ([]string, error) {
+	bitrate := opts.Bitrate
+	if bitrate == 0 {
+		bitrate = 4500
+		if preset, ok := streamPresets[strings.ToLower(opts.Platform)]; ok {
+			bitrate = preset.bitrate
+		}
+	}
+	if bitrate < 300 || bitrate > 50000 {
+		return nil, fmt.Errorf("bitrate must be between 300 and 50000 kbit/s")
+	}
+
+	var args []string
+	audio := "0:a:0?"
+	if opts.Input != "" {
+		if offset > 0 {
+			args = append(args, "-ss", fmt.Sprintf("%.3f", offset))
+		}
+		if opts.Loop {
+			args = append(args, "-stream_loop", "-1")
+		}
+		// Read at the file's own pace, as a live source would arrive
+		args = append(args, "-re", "-i", opts.Input, "-map", "0:v:0", "-vf", "scale=trunc(iw/2)*2:trunc(ih/2)*2")
+	} else {
+		source, mic, err := captureSource(goos, *opts.Capture, display)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, source...)
+		audio = ""
+		if mic >= 0 {
+			audio = fmt.Sprintf("%d:a", mic)
+		}
+	}
+
+	rate := fmt.Sprintf("%dk", bitrate)
+	args = append(args,
+		"-c:v", "libx264",
+		"-preset", "veryfast",
+		"-b:v", rate,
+		"-maxrate", rate,
+		"-bufsize", fmt.Sprintf("%dk", bitrate*2),
+		"-pix_fmt", "yuv420p",
+		"-force_key_frames", "expr:gte(t,n_forced*2)",
+	)
+	if audio != "" {
+		args = append(args, "-map", audio, "-c:a", "aac", "-b:a", "160k", "-ar", "48000", "-ac", "2")
+	}
+	return append(args, "-f", streamFormat(target), target), nil
+}
@@ -0,0 +1,80 @@
+package capture
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStreamTarget(t *testing.T) {
+	tests := []struct {
+		opts    StreamOptions
+		want    string
+		wantErr bool
+	}{
+		{StreamOptions{Platform: "YouTube", StreamKey: "abcd"}, "rtmp://a.rtmp.youtube.com/live2/abcd", false},
+		{StreamOptions{Platform: "twitch", StreamKey: "live_1"}, "rtmp://live.twitch.tv/app/live_1", false},
+		{StreamOptions{URL: "rtmp://example.com/live/", StreamKey: "k"}, "rtmp://example.com/live/k", false},
+		{StreamOptions{URL: "srt://example.com:9000?streamid=x"}, "srt://example.com:9000?streamid=x", false},
+		{StreamOptions{Platform: "twitch"}, "", true},
+		{StreamOptions{Platform: "myspace", StreamKey: "k"}, "", true},
+		{StreamOptions{URL: "http://example.com/live"}, "", true},
+		{StreamOptions{}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := streamTarget(tt.opts)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("streamTarget(%+v) error = %v, wantErr %v", tt.opts, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("streamTarget(%+v) = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+	if got := redactKey("rtmp://live.twitch.tv/app/live_1: I/O error", "live_1"); got != "rtmp://live.twitch.tv/app/<stream key>: I/O error" {
+		t.Errorf("redactKey = %q", got)
+	}
+}
+
+func TestBuildStreamArgs(t *testing.T) {
+	opts := StreamOptions{Input: "talk.mp4", Platform: "twitch", StreamKey: "k", Loop: true}
+	args, err := buildStreamArgs("linux", opts, "rtmp://live.twitch.tv/app/k", 42.5, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	for _, want := range []string{
+		"-ss 42.500 -stream_loop -1 -re -i talk.mp4",
+		"-b:v 6000k -maxrate 6000k -bufsize 12000k",
+		"-force_key_frames expr:gte(t,n_forced*2)",
+		"-map 0:a:0? -c:a aac",
+		"-f flv rtmp://live.twitch.tv/app/k",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Arguments %q missing %q", joined, want)
+		}
+	}
+
+	opts = StreamOptions{Capture: &Options{Microphone: "default"}, URL: "srt://host:9000", Bitrate: 2500}
+	args, err = buildStreamArgs("darwin", opts, opts.URL, 0, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	joined = strings.Join(args, " ")
+	for _, want := range []string{
+		"-i Capture screen 0",
+		"-map 1:a -c:a aac",
+		"-b:v 2500k",
+		"-f mpegts srt://host:9000",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Arguments %q missing %q", joined, want)
+		}
+	}
+	if strings.Contains(joined, "-ss") || strings.Contains(joined, "-re") {
+		t.Errorf("Capture arguments %q should not seek or pace", joined)
+	}
+
+	if _, err := buildStreamArgs("linux", StreamOptions{Input: "a.mp4", Bitrate: 50}, "rtmp://h/k", 0, ""); err == nil {
+		t.Error("Expected an error for a tiny bitrate")
+	}
+}
@@ -0,0 +1,532 @@
+// Package chapters builds chapter markers from scene cuts, transcript topics
+// and frame descriptions
+package chapters
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/llm"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/usage"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/vision"
+)
+
+// Shot types a scene is classified as
+const (
+	ShotTalkingHead     = "talking head"
+	ShotScreenRecording = "screen recording"
+	ShotSlides          = "slides"
+	ShotBRoll           = "b-roll"
+	ShotTitleCard       = "title card"
+	ShotOther           = "other"
+)
+
+var shotTypes = []string{ShotTalkingHead, ShotScreenRecording, ShotSlides, ShotBRoll, ShotTitleCard, ShotOther}
+
+const (
+	// maxDescribedScenes caps vision calls on videos with many cuts; the
+	// longest scenes are described
+	maxDescribedScenes = 30
+	// snapWindow is how far a chapter start moves to meet a scene cut
+	snapWindow = 2.0
+)
+
+// Options contains parameters for generating chapters
+type Options struct {
+	Input            string
+	OutputDir        string  // Default: <input>_chapters next to the input
+	Language         string  // Transcription language hint (optional)
+	SceneThreshold   float64 // Scene change sensitivity, 0-1 (default: 0.3)
+	MinChapterLength float64 // Seconds (default: 10, YouTube's minimum)
+	MaxChapters      int     // Default: 15
+	SkipTranscript   bool    // Don't transcribe; use scenes and frames only
+	SkipVision       bool    // Don't describe frames
+	Embed            bool    // Write a copy of the input with chapters embedded
+	EmbedOutput      string  // Default: <outputDir>/<name>_chapters<ext>
+}
+
+// Scene is a run of frames between two cuts
+type Scene struct {
+	Start       float64 `json:"start"`
+	End         float64 `json:"end"`
+	Shot        string  `json:"shot,omitempty"`
+	Description string  `json:"description,omitempty"`
+}
+
+// Chapter is a titled section of the video
+type Chapter struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Title string  `json:"title"`
+	Shot  string  `json:"shot,omitempty"` // Shot type of the opening scene
+}
+
+// Result describes the generated chapters and files
+type Result struct {
+	Input          string    `json:"input"`
+	Duration       float64   `json:"duration"`
+	Chapters       []Chapter `json:"chapters"`
+	Scenes         []Scene   `json:"scenes"`
+	Sources        []string  `json:"sources"` // Signals used: scenes, transcript, vision
+	YouTubeFile    string    `json:"youtubeFile"`
+	MetadataFile   string    `json:"metadataFile"`
+	JSONFile       string    `json:"jsonFile"`
+	EmbeddedOutput string    `json:"embeddedOutput,omitempty"`
+	Warnings       []string  `json:"warnings,omitempty"`
+}
+
+// Generator combines scene detection, transcription, frame analysis and the
+// configured language model into chapters
+type Generator struct {
+	ffmpeg        *ffmpeg.Manager
+	videoOps      *video.Operations
+	transcriptOps *transcript.Operations
+	vision        *vision.Analyzer
+	llm           *llm.Client
+}
+
+// NewGenerator creates a chapter generator
+func NewGenerator(cfg *config.Config, mgr *ffmpeg.Manager, videoOps *video.Operations, transcriptOps *transcript.Operations, visionAnalyzer *vision.Analyzer) *Generator {
+	return &Generator{
+		ffmpeg:        mgr,
+		videoOps:      videoOps,
+		transcriptOps: transcriptOps,
+		vision:        visionAnalyzer,
+		llm:           llm.NewClient(cfg),
+	}
+}
+
+// Generate detects chapters and writes YouTube chapter text, an FFmetadata
+// file and JSON to opts.OutputDir. Signals that are unavailable, such as a
+// transcript without an OpenAI key, are skipped with a warning.
+func (g *Generator) Generate(ctx context.Context, opts Options) (*Result, error) {
+	if opts.SceneThreshold <= 0 {
+		opts.SceneThreshold = 0.3
+	}
+	if opts.MinChapterLength <= 0 {
+		opts.MinChapterLength = 10
+	}
+	if opts.MaxChapters <= 0 {
+		opts.MaxChapters = 15
+	}
+	if opts.OutputDir == "" {
+		opts.OutputDir = strings.TrimSuffix(opts.Input, filepath.Ext(opts.Input)) + "_chapters"
+	}
+
+	info, err := g.videoOps.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media info: %w", err)
+	}
+	if info.Duration <= 0 {
+		return nil, fmt.Errorf("could not determine duration of %s", opts.Input)
+	}
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	result := &Result{Input: opts.Input, Duration: info.Duration}
+
+	// Step 1: scene cuts
+	var cuts []float64
+	if info.Width > 0 {
+		cuts, err = g.detectScenes(ctx, opts.Input, opts.SceneThreshold)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("scene detection failed: %v", err))
+		} else {
+			result.Sources = append(result.Sources, "scenes")
+		}
+	}
+	result.Scenes = buildScenes(cuts, info.Duration)
+
+	// Step 2: transcript topics
+	var trans *transcript.Transcript
+	if !opts.SkipTranscript && info.HasAudio {
+		trans, err = g.transcriptOps.ExtractTranscript(ctx, opts.Input, opts.Language)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("transcript unavailable: %v", err))
+			trans = nil
+		} else {
+			result.Sources = append(result.Sources, "transcript")
+		}
+	}
+
+	// Step 3: shot type and description of each scene
+	if !opts.SkipVision && info.Width > 0 {
+		if err := g.describeScenes(ctx, opts.Input, result.Scenes); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("frame descriptions unavailable: %v", err))
+		} else {
+			result.Sources = append(result.Sources, "vision")
+		}
+	}
+
+	// Step 4: chapter titles from the language model, or scene cuts alone
+	var chapters []Chapter
+	if trans != nil || hasDescriptions(result.Scenes) {
+		chapters, err = g.proposeChapters(ctx, result.Scenes, trans, info.Duration, opts)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("chapter titles unavailable, using scene cuts: %v", err))
+			chapters = nil
+		}
+	}
+	if chapters == nil {
+		chapters = sceneChapters(result.Scenes)
+	}
+	result.Chapters = normalizeChapters(chapters, result.Scenes, info.Duration, opts.MinChapterLength, opts.MaxChapters)
+	if len(result.Chapters) < 3 {
+		result.Warnings = append(result.Warnings, "YouTube only shows chapters when there are at least 3")
+	}
+
+	// Step 5: write outputs
+	if err := writeFormats(result, opts.OutputDir); err != nil {
+		return nil, err
+	}
+
+	if opts.Embed {
+		output := opts.EmbedOutput
+		if output == "" {
+			ext := filepath.Ext(opts.Input)
+			output = filepath.Join(opts.OutputDir, strings.TrimSuffix(filepath.Base(opts.Input), ext)+"_chapters"+ext)
+		}
+		if err := g.embed(ctx, opts.Input, result.MetadataFile, output); err != nil {
+			return nil, fmt.Errorf("failed to embed chapters: %w", err)
+		}
+		result.EmbeddedOutput = output
+	}
+
+	if err := writeJSON(result, opts.OutputDir); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+var ptsTimePattern = regexp.MustCompile(`pts_time:\s*([0-9.]+)`)
+
+// detectScenes returns the times of scene cuts using FFmpeg's scene score
+func (g *Generator) detectScenes(ctx context.Context, input string, threshold float64) ([]float64, error) {
+	output, err := g.ffmpeg.ExecuteWithOutput(ctx,
+		"-i", input,
+		"-map", "0:v:0",
+		"-vf", fmt.Sprintf("select='gt(scene,%.3f)',showinfo", threshold),
+		"-f", "null",
+		"-",
+	)
+	if err != nil {
+		return nil, err
+	}
+	return parseSceneCuts(output), nil
+}
+
+// parseSceneCuts reads cut times from showinfo output
+func parseSceneCuts(output string) []float64 {
+	var cuts []float64
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.Contains(line, "Parsed_showinfo") {
+			continue
+		}
+		if m := ptsTimePattern.FindStringSubmatch(line); m != nil {
+			if t, err := strconv.ParseFloat(m[1], 64); err == nil {
+				cuts = append(cuts, t)
+			}
+		}
+	}
+	return cuts
+}
+
+// buildScenes splits the video at cuts. Cuts within a second of another are
+// treated as one.
+func buildScenes(cuts []float64, duration float64) []Scene {
+	scenes := []Scene{{Start: 0}}
+	for _, cut := range cuts {
+		if cut-scenes[len(scenes)-1].Start < 1 || cut >= duration {
+			continue
+		}
+		scenes[len(scenes)-1].End = cut
+		scenes = append(scenes, Scene{Start: cut})
+	}
+	scenes[len(scenes)-1].End = duration
+	return scenes
+}
+
+// shotPrompt asks for a shot type and a one-line description
+var shotPrompt = fmt.Sprintf(`Classify this video frame as one of: %s.
+Then describe what it shows in one short sentence, including any visible title or heading.
+Reply on one line as "<shot type>: <description>".`, strings.Join(shotTypes, ", "))
+
+// describeScenes classifies the longest scenes from a frame shortly after
+// each one starts
+func (g *Generator) describeScenes(ctx context.Context, input string, scenes []Scene) error {
+	indexes := make([]int, len(scenes))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	if len(indexes) > maxDescribedScenes {
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return scenes[indexes[a]].End-scenes[indexes[a]].Start > scenes[indexes[b]].End-scenes[indexes[b]].Start
+		})
+		indexes = indexes[:maxDescribedScenes]
+		sort.Ints(indexes)
+	}
+
+	timestamps := make([]float64, len(indexes))
+	for i, index := range indexes {
+		scene := scenes[index]
+		timestamps[i] = scene.Start + math.Min(1, (scene.End-scene.Start)/2)
+	}
+	answers, err := g.vision.DescribeFrames(ctx, input, timestamps, shotPrompt)
+	if err != nil {
+		return err
+	}
+	for i, index := range indexes {
+		scenes[index].Shot, scenes[index].Description = parseShot(answers[i])
+	}
+	return nil
+}
+
+// parseShot splits a "<shot type>: <description>" answer. Unknown shot
+// types are reported as other.
+func parseShot(answer string) (string, string) {
+	answer = strings.TrimSpace(strings.Trim(strings.TrimSpace(answer), `"`))
+	label, description, found := strings.Cut(answer, ":")
+	if !found {
+		return ShotOther, answer
+	}
+	label = strings.ToLower(strings.Trim(strings.TrimSpace(label), "*<>"))
+	for _, shot := range shotTypes {
+		if label == shot {
+			return shot, strings.TrimSpace(description)
+		}
+	}
+	if strings.Contains(label, "b roll") || strings.Contains(label, "broll") {
+		return ShotBRoll, strings.TrimSpace(description)
+	}
+	return ShotOther, answer
+}
+
+func hasDescriptions(scenes []Scene) bool {
+	for _, scene := range scenes {
+		if scene.Description != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// proposeChapters asks the language model for chapter starts and titles
+func (g *Generator) proposeChapters(ctx context.Context, scenes []Scene, trans *transcript.Transcript, duration float64, opts Options) ([]Chapter, error) {
+	content, err := g.llm.Complete(ctx, usage.FeatureChapters, buildPrompt(scenes, trans, duration, opts))
+	if err != nil {
+		return nil, err
+	}
+	return parseChapters(content)
+}
+
+// buildPrompt lists scenes and transcript lines with their times
+func buildPrompt(scenes []Scene, trans *transcript.Transcript, duration float64, opts Options) string {
+	var sceneLines []string
+	for _, scene := range scenes {
+		line := fmt.Sprintf("(%.1f-%.1f)", scene.Start, scene.End)
+		if scene.Description != "" {
+			line += fmt.Sprintf(" %s: %s", scene.Shot, scene.Description)
+		}
+		sceneLines = append(sceneLines, line)
+	}
+	var transcriptLines []string
+	if trans != nil {
+		for _, seg := range trans.Segments {
+			transcriptLines = append(transcriptLines, fmt.Sprintf("(%.1f) %s", seg.Start, strings.TrimSpace(seg.Text)))
+		}
+	}
+	if len(transcriptLines) == 0 {
+		transcriptLines = []string{"(no transcript)"}
+	}
+
+	return fmt.Sprintf(`You are writing YouTube chapters for a video that is %.0f seconds long.
+Below are its scenes (start-end seconds, shot type and what is on screen) and its transcript (start seconds and text).
+
+Choose chapters where the topic or section changes, preferring scene boundaries. The first chapter must start at 0. Use at most %d chapters, each at least %.0f seconds long. Titles are 2-6 words, specific to the content, in title case, without timestamps or numbering.
+
+Return only a JSON object of the form {"chapters": [{"start": seconds, "title": "..."}]}.
+
+Scenes:
+%s
+
+Transcript:
+%s`, duration, opts.MaxChapters, opts.MinChapterLength, strings.Join(sceneLines, "\n"), strings.Join(transcriptLines, "\n"))
+}
+
+// parseChapters extracts the chapters array from a model response,
+// tolerating surrounding prose or code fences
+func parseChapters(content string) ([]Chapter, error) {
+	var resp struct {
+		Chapters []Chapter `json:"chapters"`
+	}
+	if err := llm.DecodeJSON(content, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse chapter response: %w", err)
+	}
+	var chapters []Chapter
+	for _, ch := range resp.Chapters {
+		if title := strings.TrimSpace(ch.Title); title != "" {
+			chapters = append(chapters, Chapter{Start: ch.Start, Title: title})
+		}
+	}
+	if len(chapters) == 0 {
+		return nil, fmt.Errorf("no chapters returned")
+	}
+	return chapters, nil
+}
+
+// sceneChapters starts an untitled chapter at every scene
+func sceneChapters(scenes []Scene) []Chapter {
+	chapters := make([]Chapter, len(scenes))
+	for i, scene := range scenes {
+		chapters[i] = Chapter{Start: scene.Start}
+	}
+	return chapters
+}
+
+// normalizeChapters snaps starts to nearby scene cuts, starts the first
+// chapter at 0, drops chapters shorter than minLength and keeps at most
+// maxChapters by merging the shortest into the one before. Untitled
+// chapters are named "Part N".
+func normalizeChapters(chapters []Chapter, scenes []Scene, duration, minLength float64, maxChapters int) []Chapter {
+	var valid []Chapter
+	for _, ch := range chapters {
+		if ch.Start < 0 || ch.Start >= duration {
+			continue
+		}
+		ch.Start = snapToScene(ch.Start, scenes)
+		valid = append(valid, ch)
+	}
+	sort.SliceStable(valid, func(i, j int) bool { return valid[i].Start < valid[j].Start })
+	if len(valid) == 0 {
+		valid = []Chapter{{Title: "Introduction"}}
+	}
+	valid[0].Start = 0
+
+	// Keep the first of chapters that start too close together
+	spaced := []Chapter{valid[0]}
+	for _, ch := range valid[1:] {
+		if ch.Start-spaced[len(spaced)-1].Start >= minLength {
+			spaced = append(spaced, ch)
+		}
+	}
+	if len(spaced) > 1 && duration-spaced[len(spaced)-1].Start < minLength {
+		spaced = spaced[:len(spaced)-1]
+	}
+
+	for len(spaced) > maxChapters {
+		shortest := 1
+		for i := 1; i < len(spaced); i++ {
+			if chapterLength(spaced, i, duration) < chapterLength(spaced, shortest, duration) {
+				shortest = i
+			}
+		}
+		spaced = append(spaced[:shortest], spaced[shortest+1:]...)
+	}
+
+	for i := range spaced {
+		spaced[i].End = duration
+		if i+1 < len(spaced) {
+			spaced[i].End = spaced[i+1].Start
+		}
+		spaced[i].Shot = shotAt(spaced[i].Start, scenes)
+		if spaced[i].Title == "" {
+			spaced[i].Title = fmt.Sprintf("Part %d", i+1)
+		}
+	}
+	return spaced
+}
+
+func chapterLength(chapters []Chapter, i int, duration float64) float64 {
+	if i+1 < len(chapters) {
+		return chapters[i+1].Start - chapters[i].Start
+	}
+	return duration - chapters[i].Start
+}
+
+// snapToScene moves t to the nearest scene start within snapWindow
+func snapToScene(t float64, scenes []Scene) float64 {
+	best, bestDistance := t, snapWindow
+	for _, scene := range scenes {
+		if d := math.Abs(scene.Start - t); d <= bestDistance {
+			best, bestDistance = scene.Start, d
+		}
+	}
+	return best
+}
+
+// shotAt returns the shot type of the scene playing at t
+func shotAt(t float64, scenes []Scene) string {
+	shot := ""
+	for _, scene := range scenes {
+		if scene.Start > t {
+			break
+		}
+		shot = scene.Shot
+	}
+	return shot
+}
+
+// embed copies input with the chapters from metadataFile, without re-encoding
+func (g *Generator) embed(ctx context.Context, input, metadataFile, output string) error {
+	return g.ffmpeg.Execute(ctx,
+		"-i", input,
+		"-f", "ffmetadata",
+		"-i", metadataFile,
+		"-map", "0",
+		"-map_metadata", "0",
+		"-map_chapters", "1",
+		"-codec", "copy",
+		"-y",
+		output,
+	)
+}
+
+// FormatYouTube formats chapters as YouTube description timestamps, e.g.
+// "0:00 Intro". Hours are shown when the video is an hour or longer.
+func FormatYouTube(chapters []Chapter, duration float64) string {
+	var lines []string
+	for _, ch := range chapters {
+		lines = append(lines, fmt.Sprintf("%s %s", youTubeTimestamp(ch.Start, duration >= 3600), ch.Title))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func youTubeTimestamp(seconds float64, hours bool) string {
+	total := int(seconds)
+	if hours {
+		return fmt.Sprintf("%d:%02d:%02d", total/3600, (total/60)%60, total%60)
+	}
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
+
+// FormatFFMetadata formats chapters as an FFmpeg metadata file, which
+// -map_chapters embeds in MP4 and MKV files
+func FormatFFMetadata(chapters []Chapter) string {
+	var b strings.Builder
+	b.WriteString(";FFMETADATA1\n")
+	for _, ch := range chapters {
+		b.WriteString("\n[CHAPTER]\nTIMEBASE=1/1000\n")
+		b.WriteString(fmt.Sprintf("START=%d\n", int64(math.Round(ch.Start*1000))))
+		b.WriteString(fmt.Sprintf("END=%d\n", int64(math.Round(ch.End*1000))))
+		b.WriteString(fmt.Sprintf("title=%s\n", escapeMetadata(ch.Title)))
+	}
+	return b.String()
+}
+
+// escapeMetadata escapes the characters FFmetadata treats as special
+func escapeMetadata(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "=", `\=`, ";", `\;`, "#", `\#`, "\n", "\\\n").Replace(s)
+}
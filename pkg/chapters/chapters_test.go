@@ -0,0 +1,112 @@
+package chapters
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSceneCuts(t *testing.T) {
+	output := `frame=  100 fps=0.0 q=-0.0 size=N/A time=00:00:04.00
+[Parsed_showinfo_1 @ 0x7f] n:   0 pts:  15360 pts_time:12.5    duration:512 fmt:yuv420p
+[Parsed_showinfo_1 @ 0x7f] color_range:tv color_space:bt709
+[Parsed_showinfo_1 @ 0x7f] n:   1 pts:  46080 pts_time:37.04   duration:512
+[mp4 @ 0x7f] pts_time:99 is not a showinfo line`
+
+	if got, want := parseSceneCuts(output), []float64{12.5, 37.04}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSceneCuts() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildScenes(t *testing.T) {
+	scenes := buildScenes([]float64{0.2, 12.5, 12.9, 37, 60}, 60)
+	want := []Scene{{Start: 0, End: 12.5}, {Start: 12.5, End: 37}, {Start: 37, End: 60}}
+	if !reflect.DeepEqual(scenes, want) {
+		t.Errorf("buildScenes() = %+v, want %+v", scenes, want)
+	}
+}
+
+func TestParseShot(t *testing.T) {
+	tests := []struct {
+		answer, shot, description string
+	}{
+		{"Slides: A slide titled Q3 Revenue.", ShotSlides, "A slide titled Q3 Revenue."},
+		{`"talking head: A woman speaks to camera."`, ShotTalkingHead, "A woman speaks to camera."},
+		{"**B Roll**: Aerial city shot.", ShotBRoll, "Aerial city shot."},
+		{"A dog runs on a beach.", ShotOther, "A dog runs on a beach."},
+		{"Note: unclear frame", ShotOther, "Note: unclear frame"},
+	}
+	for _, tt := range tests {
+		shot, description := parseShot(tt.answer)
+		if shot != tt.shot || description != tt.description {
+			t.Errorf("parseShot(%q) = %q, %q; want %q, %q", tt.answer, shot, description, tt.shot, tt.description)
+		}
+	}
+}
+
+func TestParseChapters(t *testing.T) {
+	got, err := parseChapters("Here you go:\n```json\n{\"chapters\": [{\"start\": 0, \"title\": \" Intro \"}, {\"start\": 40, \"title\": \"\"}, {\"start\": 95, \"title\": \"Q3 Revenue\"}]}\n```")
+	want := []Chapter{{Start: 0, Title: "Intro"}, {Start: 95, Title: "Q3 Revenue"}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("parseChapters() = %+v, %v; want %+v", got, err, want)
+	}
+	if _, err := parseChapters(`{"chapters": []}`); err == nil {
+		t.Error("Expected an error when no chapters are returned")
+	}
+}
+
+func TestNormalizeChapters(t *testing.T) {
+	scenes := []Scene{
+		{Start: 0, End: 31, Shot: ShotTitleCard},
+		{Start: 31, End: 90, Shot: ShotTalkingHead},
+		{Start: 90, End: 200, Shot: ShotSlides},
+	}
+	chapters := []Chapter{
+		{Start: 89, Title: "Revenue"},
+		{Start: 2, Title: "Welcome"},
+		{Start: 30, Title: "Agenda"},
+		{Start: 35, Title: "Too Soon"},
+		{Start: 195, Title: "Too Short"},
+		{Start: 250, Title: "Past The End"},
+	}
+
+	got := normalizeChapters(chapters, scenes, 200, 10, 15)
+	want := []Chapter{
+		{Start: 0, End: 31, Title: "Welcome", Shot: ShotTitleCard},
+		{Start: 31, End: 90, Title: "Agenda", Shot: ShotTalkingHead},
+		{Start: 90, End: 200, Title: "Revenue", Shot: ShotSlides},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeChapters() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// Capping merges the shortest chapter into the one before
+	got = normalizeChapters(chapters, scenes, 200, 10, 2)
+	if len(got) != 2 || got[0].End != 90 || got[1].Title != "Revenue" {
+		t.Errorf("Expected the shortest chapter dropped, got %+v", got)
+	}
+
+	got = normalizeChapters(sceneChapters(scenes), scenes, 200, 10, 15)
+	if len(got) != 3 || got[0].Title != "Part 1" || got[2].Title != "Part 3" {
+		t.Errorf("Expected untitled scene chapters to be numbered, got %+v", got)
+	}
+}
+
+func TestFormatYouTube(t *testing.T) {
+	chapters := []Chapter{{Start: 0, Title: "Intro"}, {Start: 75.9, Title: "Setup"}, {Start: 3725, Title: "Wrap Up"}}
+	if got := FormatYouTube(chapters, 3800); got != "0:00:00 Intro\n0:01:15 Setup\n1:02:05 Wrap Up\n" {
+		t.Errorf("FormatYouTube() = %q", got)
+	}
+	if got := FormatYouTube(chapters[:2], 600); got != "0:00 Intro\n1:15 Setup\n" {
+		t.Errorf("FormatYouTube() = %q", got)
+	}
+}
+
+func TestFormatFFMetadata(t *testing.T) {
+	got := FormatFFMetadata([]Chapter{{Start: 0, End: 12.5, Title: "Q&A; a=b #1"}})
+	for _, want := range []string{";FFMETADATA1\n", "[CHAPTER]\nTIMEBASE=1/1000\nSTART=0\nEND=12500\n", `title=Q&A\; a\=b \#1`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected metadata to contain %q:\n%s", want, got)
+		}
+	}
+}
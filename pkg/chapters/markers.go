@@ -0,0 +1,197 @@
+package chapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+)
+
+// youTubeLine matches a YouTube description chapter line such as
+// "0:00 Intro", "- 1:02:03 - Q&A" or "(12:30) Demo"
+var youTubeLine = regexp.MustCompile(`^\s*(?:[-*•]\s*)?\(?((?:\d+:)?\d{1,2}:\d{2}(?:\.\d+)?)\)?\s*(?:[-–—:|]\s*)?(.*\S)\s*$`)
+
+// Parse reads chapters from JSON, either a list of chapters or
+// generate_chapters' chapters.json, or from YouTube-style text with one
+// "timestamp title" line per chapter. Other lines of a description are
+// ignored.
+func Parse(text string) ([]Chapter, error) {
+	trimmed := strings.TrimSpace(text)
+	switch {
+	case trimmed == "":
+		return nil, fmt.Errorf("no chapters given")
+	case strings.HasPrefix(trimmed, "["):
+		var chapters []Chapter
+		if err := json.Unmarshal([]byte(trimmed), &chapters); err != nil {
+			return nil, fmt.Errorf("failed to parse chapters: %w", err)
+		}
+		return chapters, nil
+	case strings.HasPrefix(trimmed, "{"):
+		var result Result
+		if err := json.Unmarshal([]byte(trimmed), &result); err != nil {
+			return nil, fmt.Errorf("failed to parse chapters: %w", err)
+		}
+		if len(result.Chapters) == 0 {
+			return nil, fmt.Errorf("no chapters in JSON object")
+		}
+		return result.Chapters, nil
+	}
+	return ParseYouTube(text)
+}
+
+// ParseYouTube reads chapters from YouTube description timestamps
+func ParseYouTube(text string) ([]Chapter, error) {
+	var chapters []Chapter
+	for _, line := range strings.Split(text, "\n") {
+		m := youTubeLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		start, err := parseTimestamp(m[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q: %w", m[1], err)
+		}
+		chapters = append(chapters, Chapter{Start: start, Title: m[2]})
+	}
+	if len(chapters) == 0 {
+		return nil, fmt.Errorf("no chapter timestamps found (expected lines like \"0:00 Intro\")")
+	}
+	return chapters, nil
+}
+
+// parseTimestamp parses [h:]mm:ss[.fff]
+func parseTimestamp(ts string) (float64, error) {
+	parts := strings.Split(ts, ":")
+	var seconds float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, err
+		}
+		if i > 0 && v >= 60 {
+			return 0, fmt.Errorf("%s is not below 60", part)
+		}
+		seconds = seconds*60 + v
+	}
+	return seconds, nil
+}
+
+// Markers sorts chapters and fills in missing ends, so each chapter runs to
+// the next one and the last to the end of the video
+func Markers(chapters []Chapter, duration float64) ([]Chapter, error) {
+	if len(chapters) == 0 {
+		return nil, fmt.Errorf("no chapters given")
+	}
+	sorted := make([]Chapter, len(chapters))
+	copy(sorted, chapters)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	for i := range sorted {
+		ch := &sorted[i]
+		if ch.Start < 0 || (duration > 0 && ch.Start >= duration) {
+			return nil, fmt.Errorf("chapter %q starts at %.3fs, outside the video (%.3fs)", ch.Title, ch.Start, duration)
+		}
+		next := duration
+		if i+1 < len(sorted) {
+			next = sorted[i+1].Start
+			if next == ch.Start {
+				return nil, fmt.Errorf("chapters %q and %q both start at %.3fs", ch.Title, sorted[i+1].Title, ch.Start)
+			}
+		}
+		if ch.End <= ch.Start || (next > 0 && ch.End > next) {
+			ch.End = next
+		}
+		if strings.TrimSpace(ch.Title) == "" {
+			ch.Title = fmt.Sprintf("Chapter %d", i+1)
+		}
+	}
+	return sorted, nil
+}
+
+// Embed writes a copy of input with the given chapters, replacing any it
+// has, without re-encoding
+func (g *Generator) Embed(ctx context.Context, input, output string, chapters []Chapter) ([]Chapter, error) {
+	info, err := g.videoOps.GetVideoInfo(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media info: %w", err)
+	}
+	chapters, err = Markers(chapters, info.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, cleanup, err := scratch.Dir("chapters")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	metadataFile := filepath.Join(dir, "chapters.ffmetadata")
+	if err := os.WriteFile(metadataFile, []byte(FormatFFMetadata(chapters)), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write chapter metadata: %w", err)
+	}
+	if err := g.embed(ctx, input, metadataFile, output); err != nil {
+		return nil, err
+	}
+	return chapters, nil
+}
+
+// Extract reads the chapters embedded in a file. With an output directory,
+// it also writes chapters.txt, chapters.ffmetadata and chapters.json as
+// generate_chapters does, so they can be edited and embedded again.
+func (g *Generator) Extract(ctx context.Context, input, outputDir string) (*Result, error) {
+	meta, err := g.videoOps.GetMetadata(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata: %w", err)
+	}
+	result := &Result{Input: input, Duration: meta.Duration, Chapters: []Chapter{}}
+	for _, ch := range meta.Chapters {
+		result.Chapters = append(result.Chapters, Chapter{Start: ch.Start, End: ch.End, Title: ch.Title})
+	}
+	if outputDir == "" || len(result.Chapters) == 0 {
+		return result, nil
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := writeFormats(result, outputDir); err != nil {
+		return nil, err
+	}
+	if err := writeJSON(result, outputDir); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// writeFormats writes the YouTube chapter text and FFmetadata file
+func writeFormats(result *Result, dir string) error {
+	result.YouTubeFile = filepath.Join(dir, "chapters.txt")
+	if err := os.WriteFile(result.YouTubeFile, []byte(FormatYouTube(result.Chapters, result.Duration)), 0644); err != nil {
+		return fmt.Errorf("failed to write chapters: %w", err)
+	}
+	result.MetadataFile = filepath.Join(dir, "chapters.ffmetadata")
+	if err := os.WriteFile(result.MetadataFile, []byte(FormatFFMetadata(result.Chapters)), 0644); err != nil {
+		return fmt.Errorf("failed to write chapter metadata: %w", err)
+	}
+	return nil
+}
+
+// writeJSON writes the result as chapters.json
+func writeJSON(result *Result, dir string) error {
+	result.JSONFile = filepath.Join(dir, "chapters.json")
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal chapters: %w", err)
+	}
+	if err := os.WriteFile(result.JSONFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write chapters: %w", err)
+	}
+	return nil
+}
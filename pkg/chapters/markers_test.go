@@ -0,0 +1,89 @@
+package chapters
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYouTube(t *testing.T) {
+	description := `Everything we shipped this quarter.
+
+0:00 Intro
+- 1:30 - Roadmap
+(12:05.5) Demo: the new editor
+1:02:03 | Q&A
+
+Follow us for more!`
+
+	chapters, err := Parse(description)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []Chapter{
+		{Start: 0, Title: "Intro"},
+		{Start: 90, Title: "Roadmap"},
+		{Start: 725.5, Title: "Demo: the new editor"},
+		{Start: 3723, Title: "Q&A"},
+	}
+	if !reflect.DeepEqual(chapters, want) {
+		t.Errorf("Parse() = %+v, want %+v", chapters, want)
+	}
+
+	if _, err := Parse("No timestamps here"); err == nil {
+		t.Error("Expected an error for text without chapters")
+	}
+	if _, err := Parse("1:75 Broken"); err == nil {
+		t.Error("Expected an error for 75 seconds")
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	list, err := Parse(`[{"start": 0, "title": "Intro"}, {"start": 30.5, "end": 60, "title": "Setup"}]`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(list) != 2 || list[1].Start != 30.5 || list[1].End != 60 {
+		t.Errorf("Parse(list) = %+v", list)
+	}
+
+	// generate_chapters' chapters.json
+	result, err := Parse(`{"input": "talk.mp4", "duration": 90, "chapters": [{"start": 0, "end": 45, "title": "Intro", "shot": "talking head"}]}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result) != 1 || result[0].Shot != "talking head" {
+		t.Errorf("Parse(result) = %+v", result)
+	}
+	if _, err := Parse(`{"input": "talk.mp4"}`); err == nil {
+		t.Error("Expected an error for JSON without chapters")
+	}
+}
+
+func TestMarkers(t *testing.T) {
+	got, err := Markers([]Chapter{
+		{Start: 60, Title: "Demo"},
+		{Start: 0, End: 20, Title: "Intro"},
+		{Start: 20, End: 90, Title: ""},
+	}, 120)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []Chapter{
+		{Start: 0, End: 20, Title: "Intro"},
+		{Start: 20, End: 60, Title: "Chapter 2"},
+		{Start: 60, End: 120, Title: "Demo"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Markers() = %+v, want %+v", got, want)
+	}
+
+	for _, chapters := range [][]Chapter{
+		nil,
+		{{Start: 0, Title: "A"}, {Start: 0, Title: "B"}},
+		{{Start: 130, Title: "Late"}},
+	} {
+		if _, err := Markers(chapters, 120); err == nil {
+			t.Errorf("Expected an error for %+v", chapters)
+		}
+	}
+}
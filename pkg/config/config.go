@@ -2,23 +2,91 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 )
 
 // Config holds all configuration for the MCP video editor
 type Config struct {
-	OpenAIKey        string            `json:"openaiApiKey"`
-	ClaudeAPIKey     string            `json:"claudeApiKey,omitempty"`
-	ElevenLabsKey    string            `json:"elevenLabsApiKey,omitempty"`
-	ElevenLabsVoices map[string]string `json:"elevenLabsVoices,omitempty"`
-	FFmpegPath       string            `json:"ffmpegPath,omitempty"`
-	FFprobePath      string            `json:"ffprobePath,omitempty"`
-	DefaultQuality   string            `json:"defaultQuality,omitempty"`
-	TempDir          string            `json:"tempDir,omitempty"`
-	AgentProvider    string            `json:"agentProvider,omitempty"` // "claude" or "openai"
-	AgentModel       string            `json:"agentModel,omitempty"`    // Model to use
-	LastProjectDir   string            `json:"lastProjectDir,omitempty"` // Remember last project directory
+	OpenAIKey        string               `json:"openaiApiKey"`
+	ClaudeAPIKey     string               `json:"claudeApiKey,omitempty"`
+	ElevenLabsKey    string               `json:"elevenLabsApiKey,omitempty"`
+	GeminiAPIKey     string               `json:"geminiApiKey,omitempty"`
+	StabilityAPIKey  string               `json:"stabilityApiKey,omitempty"` // Stability AI key for Stable Diffusion images
+	PexelsAPIKey     string               `json:"pexelsApiKey,omitempty"`    // Pexels key for stock media search
+	PixabayAPIKey    string               `json:"pixabayApiKey,omitempty"`   // Pixabay key for stock media search
+	ElevenLabsVoices map[string]string    `json:"elevenLabsVoices,omitempty"`
+	VoiceInfo        map[string]VoiceMeta `json:"voiceInfo,omitempty"`     // Metadata for cached voices, by audio hash
+	VoiceCacheDir    string               `json:"voiceCacheDir,omitempty"` // Keep the voice cache in <dir>/voices.json instead of this file
+	FFmpegPath       string               `json:"ffmpegPath,omitempty"`
+	FFprobePath      string               `json:"ffprobePath,omitempty"`
+	DefaultQuality   string               `json:"defaultQuality,omitempty"`
+	TempDir          string               `json:"tempDir,omitempty"`
+	AgentProvider    string               `json:"agentProvider,omitempty"`   // "claude" or "openai"
+	AgentModel       string               `json:"agentModel,omitempty"`      // Model to use
+	LastProjectDir   string               `json:"lastProjectDir,omitempty"`  // Remember last project directory
+	ImageProvider    string               `json:"imageProvider,omitempty"`   // Image generation provider: openai or stability
+	ImageModel       string               `json:"imageModel,omitempty"`      // Image generation model
+	ImageAPIBaseURL  string               `json:"imageApiBaseUrl,omitempty"` // OpenAI-compatible image endpoint override
+	AssetDir         string               `json:"assetDir,omitempty"`        // Asset library directory
+	TTSProvider      string               `json:"ttsProvider,omitempty"`     // Default TTS provider: elevenlabs, openai, piper or coqui
+	PiperModel       string               `json:"piperModel,omitempty"`      // Default Piper voice model (.onnx)
+	Pronunciations   map[string]string    `json:"pronunciations,omitempty"`  // TTS pronunciation dictionary: word -> respelling or /ipa/
+	VisionProvider   string               `json:"visionProvider,omitempty"`  // Frame analysis provider: openai, claude, gemini or ollama
+	VisionModel      string               `json:"visionModel,omitempty"`     // Model for the vision provider
+	OllamaURL        string               `json:"ollamaUrl,omitempty"`       // Ollama server (default: http://localhost:11434)
+	VisionWorkers    int                  `json:"visionWorkers,omitempty"`   // Frames analyzed at once (default: 4)
+
+	// Static FFmpeg download when ffmpeg isn't on PATH (off by default)
+	FFmpegAutoDownload bool              `json:"ffmpegAutoDownload,omitempty"`
+	FFmpegDownloadDir  string            `json:"ffmpegDownloadDir,omitempty"` // Install directory (default: <user cache>/mcp-video-editor/ffmpeg)
+	FFmpegChecksums    map[string]string `json:"ffmpegChecksums,omitempty"`   // SHA-256 by archive name, added to the pinned ones
+
+	// Resource guardrails for FFmpeg jobs (zero disables a limit)
+	MaxCPUPercent       float64 `json:"maxCpuPercent,omitempty"`
+	MaxMemoryPercent    float64 `json:"maxMemoryPercent,omitempty"`
+	MaxGPUPercent       float64 `json:"maxGpuPercent,omitempty"`
+	MaxGPUMemoryPercent float64 `json:"maxGpuMemoryPercent,omitempty"`
+	MaxDiskBusyPercent  float64 `json:"maxDiskBusyPercent,omitempty"`
+	MinFreeDiskMB       float64 `json:"minFreeDiskMb,omitempty"`
+	MaxConcurrentJobs   int     `json:"maxConcurrentJobs,omitempty"`
+	LowPriorityJobs     bool    `json:"lowPriorityJobs,omitempty"` // Run FFmpeg at reduced CPU priority
+	JobWaitTimeout      float64 `json:"jobWaitTimeout,omitempty"`  // Seconds a job may be deferred (default: 300)
+	JobTimeout          float64 `json:"jobTimeout,omitempty"`      // Seconds one FFmpeg run may take (default: 21600, -1 disables)
+	JobStallTimeout     float64 `json:"jobStallTimeout,omitempty"` // Seconds FFmpeg may make no progress (default: 600, -1 disables)
+
+	// Retries and limits for OpenAI, vision and ElevenLabs calls
+	APIMaxRetries int                `json:"apiMaxRetries,omitempty"` // Retries after 429/5xx (default: 3, -1 disables)
+	APIRetryDelay float64            `json:"apiRetryDelay,omitempty"` // First backoff in seconds, doubled per retry (default: 1)
+	APIRateLimits map[string]float64 `json:"apiRateLimits,omitempty"` // Requests per minute by provider: openai, vision, elevenlabs, stability, pexels, pixabay, youtube
+	APIBudgets    map[string]int     `json:"apiBudgets,omitempty"`    // Most requests per session by provider
+
+	// Remote inputs and outputs (http(s)://, s3:// and gs:// paths)
+	RemoteCacheDir    string  `json:"remoteCacheDir,omitempty"` // Download cache (default: <tempDir>/mcp-video-remote)
+	RemoteCacheMB     float64 `json:"remoteCacheMb,omitempty"`  // Download cache limit (default: 10240)
+	S3AccessKeyID     string  `json:"s3AccessKeyId,omitempty"`  // Unset for public buckets
+	S3SecretAccessKey string  `json:"s3SecretAccessKey,omitempty"`
+	S3SessionToken    string  `json:"s3SessionToken,omitempty"` // For temporary credentials
+	S3Region          string  `json:"s3Region,omitempty"`       // Bucket region (default: us-east-1)
+	S3Endpoint        string  `json:"s3Endpoint,omitempty"`     // S3-compatible endpoint such as MinIO or R2
+	GCSAccessKeyID    string  `json:"gcsAccessKeyId,omitempty"` // GCS HMAC key for the XML API
+	GCSSecret         string  `json:"gcsSecret,omitempty"`
+
+	// OAuth credentials for upload_to_youtube
+	YouTubeClientID     string `json:"youtubeClientId,omitempty"`
+	YouTubeClientSecret string `json:"youtubeClientSecret,omitempty"`
+	YouTubeRefreshToken string `json:"youtubeRefreshToken,omitempty"` // Refresh token with the youtube.upload scope
+
+	// Workspace projects
+	Projects      map[string]string `json:"projects,omitempty"`      // Project name -> root directory
+	ActiveProject string            `json:"activeProject,omitempty"` // Root of the active project
+	ProjectsDir   string            `json:"projectsDir,omitempty"`   // Where new projects are created (default: working directory)
+
+	// Folders of the active project. They take precedence over assetDir and
+	// tempDir and are never saved.
+	ProjectAssetDir string `json:"-"`
+	ProjectTempDir  string `json:"-"`
 }
 
 // Load reads configuration from ~/.mcp-video-config.json
@@ -50,6 +118,42 @@ func Load() (*Config, error) {
 	if key := os.Getenv("ELEVENLABS_API_KEY"); key != "" {
 		cfg.ElevenLabsKey = key
 	}
+	if key := os.Getenv("GEMINI_API_KEY"); key != "" {
+		cfg.GeminiAPIKey = key
+	}
+	if key := os.Getenv("STABILITY_API_KEY"); key != "" {
+		cfg.StabilityAPIKey = key
+	}
+	if key := os.Getenv("PEXELS_API_KEY"); key != "" {
+		cfg.PexelsAPIKey = key
+	}
+	if key := os.Getenv("PIXABAY_API_KEY"); key != "" {
+		cfg.PixabayAPIKey = key
+	}
+	if id := os.Getenv("YOUTUBE_CLIENT_ID"); id != "" {
+		cfg.YouTubeClientID = id
+		cfg.YouTubeClientSecret = os.Getenv("YOUTUBE_CLIENT_SECRET")
+	}
+	if token := os.Getenv("YOUTUBE_REFRESH_TOKEN"); token != "" {
+		cfg.YouTubeRefreshToken = token
+	}
+	if key := os.Getenv("AWS_ACCESS_KEY_ID"); key != "" {
+		cfg.S3AccessKeyID = key
+		cfg.S3SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		cfg.S3SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		cfg.S3Region = region
+	} else if region := os.Getenv("AWS_DEFAULT_REGION"); region != "" {
+		cfg.S3Region = region
+	}
+	if endpoint := os.Getenv("AWS_ENDPOINT_URL_S3"); endpoint != "" {
+		cfg.S3Endpoint = endpoint
+	}
+	if key := os.Getenv("GCS_HMAC_ACCESS_ID"); key != "" {
+		cfg.GCSAccessKeyID = key
+		cfg.GCSSecret = os.Getenv("GCS_HMAC_SECRET")
+	}
 	if path := os.Getenv("FFMPEG_PATH"); path != "" {
 		cfg.FFmpegPath = path
 	}
@@ -57,6 +161,11 @@ func Load() (*Config, error) {
 		cfg.FFprobePath = path
 	}
 
+	// Voices kept in a cache directory
+	if err := cfg.loadVoiceCache(); err != nil {
+		return nil, err
+	}
+
 	// Set default agent provider if not set
 	if cfg.AgentProvider == "" {
 		// Default to Claude if key is available, otherwise OpenAI
@@ -77,8 +186,18 @@ func (c *Config) Save() error {
 		return err
 	}
 
+	// Voices go to the cache directory when one is set
+	stored := *c
+	if path := c.VoiceCachePath(); path != "" {
+		if err := WriteVoiceRegistry(path, c.VoiceRegistry()); err != nil {
+			return fmt.Errorf("failed to write voice cache: %w", err)
+		}
+		stored.ElevenLabsVoices = nil
+		stored.VoiceInfo = nil
+	}
+
 	configPath := filepath.Join(home, ".mcp-video-config.json")
-	data, err := json.MarshalIndent(c, "", "  ")
+	data, err := json.MarshalIndent(stored, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -102,6 +221,34 @@ func (c *Config) Update(updates map[string]interface{}) error {
 			if v, ok := value.(string); ok {
 				c.ElevenLabsKey = v
 			}
+		case "geminiKey", "geminiApiKey":
+			if v, ok := value.(string); ok {
+				c.GeminiAPIKey = v
+			}
+		case "stabilityKey", "stabilityApiKey":
+			if v, ok := value.(string); ok {
+				c.StabilityAPIKey = v
+			}
+		case "pexelsKey", "pexelsApiKey":
+			if v, ok := value.(string); ok {
+				c.PexelsAPIKey = v
+			}
+		case "pixabayKey", "pixabayApiKey":
+			if v, ok := value.(string); ok {
+				c.PixabayAPIKey = v
+			}
+		case "youtubeClientId":
+			if v, ok := value.(string); ok {
+				c.YouTubeClientID = v
+			}
+		case "youtubeClientSecret":
+			if v, ok := value.(string); ok {
+				c.YouTubeClientSecret = v
+			}
+		case "youtubeRefreshToken":
+			if v, ok := value.(string); ok {
+				c.YouTubeRefreshToken = v
+			}
 		case "ffmpegPath":
 			if v, ok := value.(string); ok {
 				c.FFmpegPath = v
@@ -130,6 +277,186 @@ func (c *Config) Update(updates map[string]interface{}) error {
 			if v, ok := value.(string); ok {
 				c.LastProjectDir = v
 			}
+		case "imageProvider":
+			if v, ok := value.(string); ok {
+				c.ImageProvider = v
+			}
+		case "imageModel":
+			if v, ok := value.(string); ok {
+				c.ImageModel = v
+			}
+		case "imageApiBaseUrl":
+			if v, ok := value.(string); ok {
+				c.ImageAPIBaseURL = v
+			}
+		case "assetDir":
+			if v, ok := value.(string); ok {
+				c.AssetDir = v
+			}
+		case "ttsProvider":
+			if v, ok := value.(string); ok {
+				c.TTSProvider = v
+			}
+		case "voiceCacheDir":
+			if v, ok := value.(string); ok {
+				c.VoiceCacheDir = v
+			}
+		case "piperModel":
+			if v, ok := value.(string); ok {
+				c.PiperModel = v
+			}
+		case "pronunciations":
+			if v, ok := value.(map[string]interface{}); ok {
+				c.Pronunciations = make(map[string]string, len(v))
+				for word, pronunciation := range v {
+					if p, ok := pronunciation.(string); ok {
+						c.Pronunciations[word] = p
+					}
+				}
+			}
+		case "visionProvider":
+			if v, ok := value.(string); ok {
+				c.VisionProvider = v
+			}
+		case "visionModel":
+			if v, ok := value.(string); ok {
+				c.VisionModel = v
+			}
+		case "ollamaUrl":
+			if v, ok := value.(string); ok {
+				c.OllamaURL = v
+			}
+		case "visionWorkers":
+			if v, ok := value.(float64); ok {
+				c.VisionWorkers = int(v)
+			}
+		case "ffmpegAutoDownload":
+			if v, ok := value.(bool); ok {
+				c.FFmpegAutoDownload = v
+			}
+		case "ffmpegDownloadDir":
+			if v, ok := value.(string); ok {
+				c.FFmpegDownloadDir = v
+			}
+		case "ffmpegChecksums":
+			if v, ok := value.(map[string]interface{}); ok {
+				c.FFmpegChecksums = make(map[string]string, len(v))
+				for archive, sum := range v {
+					if s, ok := sum.(string); ok {
+						c.FFmpegChecksums[archive] = s
+					}
+				}
+			}
+		case "maxCpuPercent":
+			if v, ok := value.(float64); ok {
+				c.MaxCPUPercent = v
+			}
+		case "maxMemoryPercent":
+			if v, ok := value.(float64); ok {
+				c.MaxMemoryPercent = v
+			}
+		case "maxGpuPercent":
+			if v, ok := value.(float64); ok {
+				c.MaxGPUPercent = v
+			}
+		case "maxGpuMemoryPercent":
+			if v, ok := value.(float64); ok {
+				c.MaxGPUMemoryPercent = v
+			}
+		case "maxDiskBusyPercent":
+			if v, ok := value.(float64); ok {
+				c.MaxDiskBusyPercent = v
+			}
+		case "minFreeDiskMb":
+			if v, ok := value.(float64); ok {
+				c.MinFreeDiskMB = v
+			}
+		case "maxConcurrentJobs":
+			if v, ok := value.(float64); ok {
+				c.MaxConcurrentJobs = int(v)
+			}
+		case "lowPriorityJobs":
+			if v, ok := value.(bool); ok {
+				c.LowPriorityJobs = v
+			}
+		case "jobWaitTimeout":
+			if v, ok := value.(float64); ok {
+				c.JobWaitTimeout = v
+			}
+		case "jobTimeout":
+			if v, ok := value.(float64); ok {
+				c.JobTimeout = v
+			}
+		case "jobStallTimeout":
+			if v, ok := value.(float64); ok {
+				c.JobStallTimeout = v
+			}
+		case "apiMaxRetries":
+			if v, ok := value.(float64); ok {
+				c.APIMaxRetries = int(v)
+			}
+		case "apiRetryDelay":
+			if v, ok := value.(float64); ok {
+				c.APIRetryDelay = v
+			}
+		case "apiRateLimits":
+			if v, ok := value.(map[string]interface{}); ok {
+				c.APIRateLimits = make(map[string]float64, len(v))
+				for provider, limit := range v {
+					if l, ok := limit.(float64); ok {
+						c.APIRateLimits[provider] = l
+					}
+				}
+			}
+		case "apiBudgets":
+			if v, ok := value.(map[string]interface{}); ok {
+				c.APIBudgets = make(map[string]int, len(v))
+				for provider, budget := range v {
+					if b, ok := budget.(float64); ok {
+						c.APIBudgets[provider] = int(b)
+					}
+				}
+			}
+		case "projectsDir":
+			if v, ok := value.(string); ok {
+				c.ProjectsDir = v
+			}
+		case "remoteCacheDir":
+			if v, ok := value.(string); ok {
+				c.RemoteCacheDir = v
+			}
+		case "remoteCacheMb":
+			if v, ok := value.(float64); ok {
+				c.RemoteCacheMB = v
+			}
+		case "s3AccessKeyId":
+			if v, ok := value.(string); ok {
+				c.S3AccessKeyID = v
+			}
+		case "s3SecretAccessKey":
+			if v, ok := value.(string); ok {
+				c.S3SecretAccessKey = v
+			}
+		case "s3SessionToken":
+			if v, ok := value.(string); ok {
+				c.S3SessionToken = v
+			}
+		case "s3Region":
+			if v, ok := value.(string); ok {
+				c.S3Region = v
+			}
+		case "s3Endpoint":
+			if v, ok := value.(string); ok {
+				c.S3Endpoint = v
+			}
+		case "gcsAccessKeyId":
+			if v, ok := value.(string); ok {
+				c.GCSAccessKeyID = v
+			}
+		case "gcsSecret":
+			if v, ok := value.(string); ok {
+				c.GCSSecret = v
+			}
 		}
 	}
 	return c.Save()
@@ -140,7 +467,16 @@ func (c *Config) Reset() error {
 	c.OpenAIKey = ""
 	c.ClaudeAPIKey = ""
 	c.ElevenLabsKey = ""
+	c.GeminiAPIKey = ""
+	c.StabilityAPIKey = ""
+	c.PexelsAPIKey = ""
+	c.PixabayAPIKey = ""
+	c.YouTubeClientID = ""
+	c.YouTubeClientSecret = ""
+	c.YouTubeRefreshToken = ""
 	c.ElevenLabsVoices = nil
+	c.VoiceInfo = nil
+	c.VoiceCacheDir = "" // Before Save, so a shared cache directory is left alone
 	c.FFmpegPath = ""
 	c.FFprobePath = ""
 	c.DefaultQuality = "high"
@@ -148,24 +484,150 @@ func (c *Config) Reset() error {
 	c.AgentProvider = ""
 	c.AgentModel = ""
 	c.LastProjectDir = ""
+	c.ImageProvider = ""
+	c.ImageModel = ""
+	c.ImageAPIBaseURL = ""
+	c.AssetDir = ""
+	c.TTSProvider = ""
+	c.PiperModel = ""
+	c.Pronunciations = nil
+	c.VisionProvider = ""
+	c.VisionModel = ""
+	c.OllamaURL = ""
+	c.VisionWorkers = 0
+	c.FFmpegAutoDownload = false
+	c.FFmpegDownloadDir = ""
+	c.FFmpegChecksums = nil
+	c.MaxCPUPercent = 0
+	c.MaxMemoryPercent = 0
+	c.MaxGPUPercent = 0
+	c.MaxGPUMemoryPercent = 0
+	c.MaxDiskBusyPercent = 0
+	c.MinFreeDiskMB = 0
+	c.MaxConcurrentJobs = 0
+	c.LowPriorityJobs = false
+	c.JobWaitTimeout = 0
+	c.JobTimeout = 0
+	c.JobStallTimeout = 0
+	c.APIMaxRetries = 0
+	c.APIRetryDelay = 0
+	c.APIRateLimits = nil
+	c.APIBudgets = nil
+	c.RemoteCacheDir = ""
+	c.RemoteCacheMB = 0
+	c.S3AccessKeyID = ""
+	c.S3SecretAccessKey = ""
+	c.S3SessionToken = ""
+	c.S3Region = ""
+	c.S3Endpoint = ""
+	c.GCSAccessKeyID = ""
+	c.GCSSecret = ""
+	c.Projects = nil
+	c.ActiveProject = ""
+	c.ProjectsDir = ""
+	c.ProjectAssetDir = ""
+	c.ProjectTempDir = ""
 	return c.Save()
 }
 
 // ToMap converts config to map for JSON output
 func (c *Config) ToMap() map[string]interface{} {
 	return map[string]interface{}{
-		"openaiKey":        maskAPIKey(c.OpenAIKey),
-		"claudeKey":        maskAPIKey(c.ClaudeAPIKey),
-		"elevenLabsKey":    maskAPIKey(c.ElevenLabsKey),
-		"elevenLabsVoices": c.ElevenLabsVoices,
-		"ffmpegPath":       c.FFmpegPath,
-		"ffprobePath":      c.FFprobePath,
-		"defaultQuality":   c.DefaultQuality,
-		"tempDir":          c.TempDir,
-		"agentProvider":    c.AgentProvider,
-		"agentModel":       c.AgentModel,
-		"lastProjectDir":   c.LastProjectDir,
+		"openaiKey":           maskAPIKey(c.OpenAIKey),
+		"claudeKey":           maskAPIKey(c.ClaudeAPIKey),
+		"elevenLabsKey":       maskAPIKey(c.ElevenLabsKey),
+		"geminiKey":           maskAPIKey(c.GeminiAPIKey),
+		"stabilityKey":        maskAPIKey(c.StabilityAPIKey),
+		"pexelsKey":           maskAPIKey(c.PexelsAPIKey),
+		"pixabayKey":          maskAPIKey(c.PixabayAPIKey),
+		"youtubeClientId":     c.YouTubeClientID,
+		"youtubeClientSecret": maskAPIKey(c.YouTubeClientSecret),
+		"youtubeRefreshToken": maskAPIKey(c.YouTubeRefreshToken),
+		"elevenLabsVoices":    c.ElevenLabsVoices,
+		"voiceCacheDir":       c.VoiceCacheDir,
+		"ffmpegPath":          c.FFmpegPath,
+		"ffprobePath":         c.FFprobePath,
+		"ffmpegAutoDownload":  c.FFmpegAutoDownload,
+		"ffmpegDownloadDir":   c.FFmpegDownloadPath(),
+		"ffmpegChecksums":     c.FFmpegChecksums,
+		"defaultQuality":      c.DefaultQuality,
+		"tempDir":             c.TempDir,
+		"agentProvider":       c.AgentProvider,
+		"agentModel":          c.AgentModel,
+		"lastProjectDir":      c.LastProjectDir,
+		"imageProvider":       c.ImageProvider,
+		"imageModel":          c.ImageModel,
+		"imageApiBaseUrl":     c.ImageAPIBaseURL,
+		"assetDir":            c.AssetDir,
+		"ttsProvider":         c.TTSProvider,
+		"piperModel":          c.PiperModel,
+		"pronunciations":      c.Pronunciations,
+		"visionProvider":      c.VisionProvider,
+		"visionModel":         c.VisionModel,
+		"ollamaUrl":           c.OllamaURL,
+		"visionWorkers":       c.VisionWorkers,
+		"maxCpuPercent":       c.MaxCPUPercent,
+		"maxMemoryPercent":    c.MaxMemoryPercent,
+		"maxGpuPercent":       c.MaxGPUPercent,
+		"maxGpuMemoryPercent": c.MaxGPUMemoryPercent,
+		"maxDiskBusyPercent":  c.MaxDiskBusyPercent,
+		"minFreeDiskMb":       c.MinFreeDiskMB,
+		"maxConcurrentJobs":   c.MaxConcurrentJobs,
+		"lowPriorityJobs":     c.LowPriorityJobs,
+		"jobWaitTimeout":      c.JobWaitTimeout,
+		"jobTimeout":          c.JobTimeout,
+		"jobStallTimeout":     c.JobStallTimeout,
+		"apiMaxRetries":       c.APIMaxRetries,
+		"apiRetryDelay":       c.APIRetryDelay,
+		"apiRateLimits":       c.APIRateLimits,
+		"apiBudgets":          c.APIBudgets,
+		"remoteCacheDir":      c.RemoteCacheDir,
+		"remoteCacheMb":       c.RemoteCacheMB,
+		"s3AccessKeyId":       c.S3AccessKeyID,
+		"s3SecretAccessKey":   maskAPIKey(c.S3SecretAccessKey),
+		"s3SessionToken":      maskAPIKey(c.S3SessionToken),
+		"s3Region":            c.S3Region,
+		"s3Endpoint":          c.S3Endpoint,
+		"gcsAccessKeyId":      c.GCSAccessKeyID,
+		"gcsSecret":           maskAPIKey(c.GCSSecret),
+		"projects":            c.Projects,
+		"activeProject":       c.ActiveProject,
+		"projectsDir":         c.ProjectsDir,
+	}
+}
+
+// AssetPath returns the asset library directory: the active project's
+// assets folder, then assetDir. Empty means the caller's default.
+func (c *Config) AssetPath() string {
+	if c.ProjectAssetDir != "" {
+		return c.ProjectAssetDir
+	}
+	return c.AssetDir
+}
+
+// TempPath returns the directory for temporary files: the active project's
+// temp folder, then tempDir, then the system default
+func (c *Config) TempPath() string {
+	if c.ProjectTempDir != "" {
+		return c.ProjectTempDir
+	}
+	if c.TempDir != "" {
+		return c.TempDir
+	}
+	return os.TempDir()
+}
+
+// FFmpegDownloadPath returns where a downloaded FFmpeg is installed:
+// ffmpegDownloadDir, then the user cache directory
+func (c *Config) FFmpegDownloadPath() string {
+	if c.FFmpegDownloadDir != "" {
+		return c.FFmpegDownloadDir
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
 	}
+	return filepath.Join(base, "mcp-video-editor", "ffmpeg")
 }
 
 func maskAPIKey(key string) string {
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// voiceCacheFile is the voice registry's file name inside VoiceCacheDir
+const voiceCacheFile = "voices.json"
+
+// voiceRegistryVersion is the current registry file format
+const voiceRegistryVersion = 1
+
+// VoiceMeta describes a cloned voice
+type VoiceMeta struct {
+	Name        string    `json:"name,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Source      string    `json:"source,omitempty"` // Audio the voice was cloned from
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+}
+
+// VoiceEntry is one cached voice clone
+type VoiceEntry struct {
+	AudioHash string `json:"audioHash"` // SHA256 of the sample audio
+	VoiceID   string `json:"voiceId"`
+	VoiceMeta
+}
+
+// VoiceRegistry is the portable form of the voice cache, used for the
+// cache directory and for export/import
+type VoiceRegistry struct {
+	Version int          `json:"version"`
+	Voices  []VoiceEntry `json:"voices"`
+}
+
+// VoiceRegistry returns the cached voices, sorted by hash
+func (c *Config) VoiceRegistry() VoiceRegistry {
+	registry := VoiceRegistry{Version: voiceRegistryVersion, Voices: []VoiceEntry{}}
+	for hash, voiceID := range c.ElevenLabsVoices {
+		registry.Voices = append(registry.Voices, VoiceEntry{AudioHash: hash, VoiceID: voiceID, VoiceMeta: c.VoiceInfo[hash]})
+	}
+	sort.Slice(registry.Voices, func(i, j int) bool { return registry.Voices[i].AudioHash < registry.Voices[j].AudioHash })
+	return registry
+}
+
+// MergeVoices adds voices to the cache. Voices already cached under the
+// same hash are kept unless overwrite is set. It returns how many entries
+// were added, updated and skipped.
+func (c *Config) MergeVoices(voices []VoiceEntry, overwrite bool) (added, updated, skipped int) {
+	if c.ElevenLabsVoices == nil {
+		c.ElevenLabsVoices = make(map[string]string)
+	}
+	for _, voice := range voices {
+		if voice.AudioHash == "" || voice.VoiceID == "" {
+			skipped++
+			continue
+		}
+		existing, ok := c.ElevenLabsVoices[voice.AudioHash]
+		switch {
+		case !ok:
+			added++
+		case existing == voice.VoiceID && !overwrite:
+			// Same voice; pick up metadata the cache lacks
+			if _, has := c.VoiceInfo[voice.AudioHash]; has || voice.VoiceMeta == (VoiceMeta{}) {
+				skipped++
+				continue
+			}
+			updated++
+		case overwrite:
+			updated++
+		default:
+			skipped++
+			continue
+		}
+		c.ElevenLabsVoices[voice.AudioHash] = voice.VoiceID
+		if voice.VoiceMeta != (VoiceMeta{}) {
+			c.SetVoiceMeta(voice.AudioHash, voice.VoiceMeta)
+		}
+	}
+	return added, updated, skipped
+}
+
+// SetVoiceMeta records metadata for a cached voice
+func (c *Config) SetVoiceMeta(audioHash string, meta VoiceMeta) {
+	if c.VoiceInfo == nil {
+		c.VoiceInfo = make(map[string]VoiceMeta)
+	}
+	c.VoiceInfo[audioHash] = meta
+}
+
+// RemoveVoice drops a voice and its metadata from the cache
+func (c *Config) RemoveVoice(audioHash string) {
+	delete(c.ElevenLabsVoices, audioHash)
+	delete(c.VoiceInfo, audioHash)
+}
+
+// ReadVoiceRegistry reads a voice registry file
+func ReadVoiceRegistry(path string) (VoiceRegistry, error) {
+	var registry VoiceRegistry
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return registry, err
+	}
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return registry, fmt.Errorf("invalid voice registry %s: %w", path, err)
+	}
+	if registry.Version > voiceRegistryVersion {
+		return registry, fmt.Errorf("voice registry %s is version %d; this build reads up to %d", path, registry.Version, voiceRegistryVersion)
+	}
+	return registry, nil
+}
+
+// WriteVoiceRegistry writes a voice registry file, creating its directory
+func WriteVoiceRegistry(path string, registry VoiceRegistry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// VoiceCachePath returns the voice registry file when voices are kept in
+// VoiceCacheDir, or "" when they live in the config file
+func (c *Config) VoiceCachePath() string {
+	if c.VoiceCacheDir == "" {
+		return ""
+	}
+	dir := c.VoiceCacheDir
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, rest)
+		}
+	}
+	return filepath.Join(dir, voiceCacheFile)
+}
+
+// loadVoiceCache merges the registry in VoiceCacheDir into the cache
+func (c *Config) loadVoiceCache() error {
+	path := c.VoiceCachePath()
+	if path == "" {
+		return nil
+	}
+	registry, err := ReadVoiceRegistry(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.MergeVoices(registry.Voices, true)
+	return nil
+}
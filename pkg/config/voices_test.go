@@ -0,0 +1,84 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMergeVoices(t *testing.T) {
+	cfg := &Config{ElevenLabsVoices: map[string]string{"aaa": "voice-1", "bbb": "voice-2"}}
+
+	added, updated, skipped := cfg.MergeVoices([]VoiceEntry{
+		{AudioHash: "aaa", VoiceID: "voice-9"},                                   // Conflict, kept
+		{AudioHash: "bbb", VoiceID: "voice-2", VoiceMeta: VoiceMeta{Name: "Bo"}}, // Same voice, gains metadata
+		{AudioHash: "ccc", VoiceID: "voice-3"},                                   // New
+		{AudioHash: "ddd"},                                                       // Incomplete
+	}, false)
+	if added != 1 || updated != 1 || skipped != 2 {
+		t.Errorf("MergeVoices() = %d added, %d updated, %d skipped; want 1, 1, 2", added, updated, skipped)
+	}
+	if cfg.ElevenLabsVoices["aaa"] != "voice-1" || cfg.ElevenLabsVoices["ccc"] != "voice-3" || cfg.VoiceInfo["bbb"].Name != "Bo" {
+		t.Errorf("Unexpected cache after merge: %v %v", cfg.ElevenLabsVoices, cfg.VoiceInfo)
+	}
+
+	added, updated, _ = cfg.MergeVoices([]VoiceEntry{{AudioHash: "aaa", VoiceID: "voice-9"}}, true)
+	if added != 0 || updated != 1 || cfg.ElevenLabsVoices["aaa"] != "voice-9" {
+		t.Errorf("Expected overwrite to replace aaa, got %v", cfg.ElevenLabsVoices)
+	}
+
+	cfg.RemoveVoice("bbb")
+	registry := cfg.VoiceRegistry()
+	if len(registry.Voices) != 2 || registry.Voices[0].AudioHash != "aaa" || registry.Voices[1].AudioHash != "ccc" {
+		t.Errorf("VoiceRegistry() = %+v", registry.Voices)
+	}
+}
+
+func TestVoiceCacheDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ELEVENLABS_API_KEY", "")
+
+	cfg := &Config{
+		VoiceCacheDir:    "~/shared/voices",
+		ElevenLabsVoices: map[string]string{"aaa": "voice-1"},
+		VoiceInfo:        map[string]VoiceMeta{"aaa": {Name: "Narrator"}},
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Voices live in the cache directory, not the config file
+	registry, err := ReadVoiceRegistry(filepath.Join(home, "shared", "voices", "voices.json"))
+	if err != nil {
+		t.Fatalf("ReadVoiceRegistry() error = %v", err)
+	}
+	if len(registry.Voices) != 1 || registry.Voices[0].VoiceID != "voice-1" || registry.Voices[0].Name != "Narrator" {
+		t.Errorf("Unexpected registry: %+v", registry)
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".mcp-video-config.json"))
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if strings.Contains(string(data), "voice-1") {
+		t.Errorf("Config file should not hold cached voices:\n%s", data)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.ElevenLabsVoices["aaa"] != "voice-1" || loaded.VoiceInfo["aaa"].Name != "Narrator" {
+		t.Errorf("Load() did not read the cache directory: %v %v", loaded.ElevenLabsVoices, loaded.VoiceInfo)
+	}
+
+	// A newer format is refused rather than misread
+	future, _ := json.Marshal(VoiceRegistry{Version: 99})
+	path := filepath.Join(home, "future.json")
+	os.WriteFile(path, future, 0600)
+	if _, err := ReadVoiceRegistry(path); err == nil {
+		t.Error("Expected error for a newer registry version")
+	}
+}
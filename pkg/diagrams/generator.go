@@ -8,6 +8,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
 )
 
 // TimelineEvent represents an event in a timeline
@@ -90,15 +92,11 @@ type DiagramStyle struct {
 }
 
 // Generator handles diagram generation
-type Generator struct {
-	tempDir string
-}
+type Generator struct{}
 
 // NewGenerator creates a new diagram generator
 func NewGenerator() *Generator {
-	tempDir := filepath.Join(os.TempDir(), ".mcp-diagram-temp")
-	os.MkdirAll(tempDir, 0755)
-	return &Generator{tempDir: tempDir}
+	return &Generator{}
 }
 
 // DefaultStyle returns default styling
@@ -576,11 +574,15 @@ func cosApprox(rad float64) float64 {
 // saveSVGAsPNG converts SVG to PNG using ImageMagick or rsvg-convert
 func (g *Generator) saveSVGAsPNG(ctx context.Context, svg string, outputPath string, width, height int) error {
 	// Save SVG to temp file
-	svgPath := filepath.Join(g.tempDir, "temp-diagram.svg")
+	tempDir, cleanup, err := scratch.Dir("diagram")
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	svgPath := filepath.Join(tempDir, "diagram.svg")
 	if err := os.WriteFile(svgPath, []byte(svg), 0644); err != nil {
 		return fmt.Errorf("failed to write SVG file: %w", err)
 	}
-	defer os.Remove(svgPath)
 
 	// Try rsvg-convert first (most reliable)
 	cmd := exec.CommandContext(ctx, "rsvg-convert", "-w", fmt.Sprintf("%d", width), "-h", fmt.Sprintf("%d", height), "-o", outputPath, svgPath)
@@ -603,11 +605,6 @@ func (g *Generator) saveSVGAsPNG(ctx context.Context, svg string, outputPath str
 	return fmt.Errorf("failed to convert SVG to PNG: neither rsvg-convert nor ImageMagick are available")
 }
 
-// Cleanup removes temporary files
-func (g *Generator) Cleanup() error {
-	return os.RemoveAll(g.tempDir)
-}
-
 // Helper functions
 
 func truncate(s string, maxLen int) string {
@@ -0,0 +1,360 @@
+package dubbing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/audio"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/translate"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+)
+
+const (
+	defaultMaxSpeedup = 1.25 // Fastest a dubbed line is played before it is cut
+	voiceSampleLength = 60   // Seconds of source speech used to clone a voice
+	trimTolerance     = 0.05 // Overrun in seconds that is cut without a warning
+)
+
+// Options contains options for dubbing a video into another language
+type Options struct {
+	Input            string
+	Output           string
+	TargetLanguage   string  // Language name or code, e.g. "Spanish" or "es"
+	SourceLanguage   string  // Transcription and translation hint (optional)
+	TranscriptPath   string  // Reuse a source transcript instead of transcribing
+	TranslationPath  string  // Reuse a translated transcript instead of translating
+	Notes            string  // Glossary or tone guidance for the translator
+	VoiceID          string  // ElevenLabs voice; cloned from the input when empty
+	VoiceSamplePath  string  // Audio to clone the voice from instead of the input
+	ModelID          string  // ElevenLabs model (default: eleven_multilingual_v2)
+	MaxSpeedup       float64 // Fastest playback used to fit a line in its slot (default: 1.25)
+	BackgroundVolume float64 // Keep the original audio under the dub at this volume (0-1, default: 0)
+	KeepOriginal     bool    // Keep the original audio as a second, non-default track
+}
+
+// Result describes the files produced by the pipeline
+type Result struct {
+	Output          string   `json:"output"`
+	TranscriptFile  string   `json:"transcriptFile"`
+	TranslationFile string   `json:"translationFile"`
+	SubtitlesFile   string   `json:"subtitlesFile"`
+	VoiceID         string   `json:"voiceId"`
+	Segments        int      `json:"segments"`
+	SpedUp          int      `json:"spedUp"`
+	Trimmed         int      `json:"trimmed"`
+	Warnings        []string `json:"warnings,omitempty"`
+}
+
+// Pipeline chains translation, per-segment TTS, time fitting and audio
+// replacement to produce a localized version of a video
+type Pipeline struct {
+	ffmpeg        *ffmpeg.Manager
+	videoOps      *video.Operations
+	transcriptOps *transcript.Operations
+	translator    *translate.Translator
+	tts           *audio.TTSOperations
+}
+
+// NewPipeline creates a new dubbing pipeline
+func NewPipeline(mgr *ffmpeg.Manager, videoOps *video.Operations, transcriptOps *transcript.Operations, translator *translate.Translator, tts *audio.TTSOperations) *Pipeline {
+	return &Pipeline{
+		ffmpeg:        mgr,
+		videoOps:      videoOps,
+		transcriptOps: transcriptOps,
+		translator:    translator,
+		tts:           tts,
+	}
+}
+
+// Dub translates the input's speech and replaces its audio with synthesized
+// speech in the target language. The source transcript, translation and
+// subtitles are saved next to the output for reuse.
+func (p *Pipeline) Dub(ctx context.Context, opts Options) (*Result, error) {
+	if opts.TargetLanguage == "" {
+		return nil, fmt.Errorf("target language is required")
+	}
+	if opts.BackgroundVolume < 0 || opts.BackgroundVolume > 1 {
+		return nil, fmt.Errorf("background volume must be between 0 and 1")
+	}
+	maxSpeedup := opts.MaxSpeedup
+	if maxSpeedup <= 0 {
+		maxSpeedup = defaultMaxSpeedup
+	}
+	// A single atempo filter covers 0.5-2.0
+	maxSpeedup = min(max(maxSpeedup, 1), 2)
+
+	info, err := p.videoOps.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+
+	base := strings.TrimSuffix(opts.Output, filepath.Ext(opts.Output))
+	lang := languageSlug(opts.TargetLanguage)
+	result := &Result{
+		Output:          opts.Output,
+		TranslationFile: fmt.Sprintf("%s.%s.json", base, lang),
+		SubtitlesFile:   fmt.Sprintf("%s.%s.srt", base, lang),
+	}
+
+	// Step 1: source transcript
+	var source *transcript.Transcript
+	if opts.TranscriptPath != "" {
+		if source, err = p.transcriptOps.LoadTranscript(opts.TranscriptPath); err != nil {
+			return nil, fmt.Errorf("failed to load transcript: %w", err)
+		}
+		result.TranscriptFile = opts.TranscriptPath
+	} else if opts.TranslationPath == "" {
+		if !info.HasAudio {
+			return nil, fmt.Errorf("video has no audio track to transcribe")
+		}
+		if source, err = p.transcriptOps.ExtractTranscript(ctx, opts.Input, opts.SourceLanguage); err != nil {
+			return nil, fmt.Errorf("failed to transcribe: %w", err)
+		}
+		result.TranscriptFile = base + ".transcript.json"
+		if err := p.transcriptOps.SaveTranscript(source, result.TranscriptFile); err != nil {
+			return nil, fmt.Errorf("failed to save transcript: %w", err)
+		}
+	}
+
+	// Step 2: translation
+	var translated *transcript.Transcript
+	if opts.TranslationPath != "" {
+		if translated, err = p.transcriptOps.LoadTranscript(opts.TranslationPath); err != nil {
+			return nil, fmt.Errorf("failed to load translation: %w", err)
+		}
+		result.TranslationFile = opts.TranslationPath
+	} else {
+		if translated, err = p.translator.TranslateTranscript(ctx, source, translate.Options{
+			TargetLanguage: opts.TargetLanguage,
+			SourceLanguage: opts.SourceLanguage,
+			Notes:          opts.Notes,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to translate: %w", err)
+		}
+		if err := p.transcriptOps.SaveTranscript(translated, result.TranslationFile); err != nil {
+			return nil, fmt.Errorf("failed to save translation: %w", err)
+		}
+	}
+	if err := os.WriteFile(result.SubtitlesFile, []byte(p.transcriptOps.FormatAsSRT(translated)), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write subtitles: %w", err)
+	}
+	if len(translated.Segments) == 0 {
+		return nil, fmt.Errorf("no speech found to dub")
+	}
+
+	tempDir, cleanup, err := scratch.Dir("dub-video")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	// Step 3: voice
+	result.VoiceID = opts.VoiceID
+	if result.VoiceID == "" {
+		if result.VoiceID, err = p.cloneVoice(ctx, opts, translated.Segments[0].Start, tempDir); err != nil {
+			return nil, fmt.Errorf("failed to get voice: %w", err)
+		}
+	}
+
+	// Step 4: speech per segment, fitted to the time before the next segment
+	var pieces []string
+	if lead := translated.Segments[0].Start; lead > 0 {
+		path := filepath.Join(tempDir, "lead.wav")
+		if err := p.ffmpeg.Execute(ctx, silenceArgs(lead, path)...); err != nil {
+			return nil, fmt.Errorf("failed to create silence: %w", err)
+		}
+		pieces = append(pieces, path)
+	}
+
+	for i, seg := range translated.Segments {
+		end := info.Duration
+		if i+1 < len(translated.Segments) {
+			end = translated.Segments[i+1].Start
+		}
+		slot := max(end-seg.Start, seg.End-seg.Start, 0.1)
+		piece := filepath.Join(tempDir, fmt.Sprintf("piece_%04d.wav", i))
+
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			if err := p.ffmpeg.Execute(ctx, silenceArgs(slot, piece)...); err != nil {
+				return nil, fmt.Errorf("failed to create silence: %w", err)
+			}
+			pieces = append(pieces, piece)
+			continue
+		}
+
+		speech := filepath.Join(tempDir, fmt.Sprintf("speech_%04d.mp3", i))
+		// Dubs use a cloned voice, which only ElevenLabs has
+		if err := p.tts.GenerateSpeech(ctx, audio.SpeechOptions{
+			Text:     text,
+			Provider: audio.TTSProviderElevenLabs,
+			VoiceID:  result.VoiceID,
+			ModelID:  opts.ModelID,
+		}, speech); err != nil {
+			return nil, fmt.Errorf("segment %d: %w", i, err)
+		}
+		speechInfo, err := p.videoOps.GetVideoInfo(ctx, speech)
+		if err != nil {
+			return nil, fmt.Errorf("segment %d: failed to measure speech: %w", i, err)
+		}
+
+		tempo, trimmed := fitToSlot(speechInfo.Duration, slot, maxSpeedup)
+		if tempo > 1 {
+			result.SpedUp++
+		}
+		if trimmed {
+			result.Trimmed++
+			result.Warnings = append(result.Warnings, fmt.Sprintf("segment %d at %.1fs was cut to fit %.1fs even at %.2fx speed", i, seg.Start, slot, maxSpeedup))
+		}
+		if err := p.ffmpeg.Execute(ctx, fitArgs(speech, piece, tempo, slot)...); err != nil {
+			return nil, fmt.Errorf("segment %d: failed to fit speech: %w", i, err)
+		}
+		pieces = append(pieces, piece)
+	}
+	result.Segments = len(translated.Segments)
+
+	dubTrack := filepath.Join(tempDir, "dub.wav")
+	if err := p.concat(ctx, pieces, dubTrack, tempDir); err != nil {
+		return nil, fmt.Errorf("failed to assemble dub track: %w", err)
+	}
+
+	// Step 5: optional original audio bed under the dub
+	if opts.BackgroundVolume > 0 && info.HasAudio {
+		mixed := filepath.Join(tempDir, "dub_mixed.wav")
+		if err := p.ffmpeg.Execute(ctx, mixArgs(opts.Input, dubTrack, mixed, opts.BackgroundVolume)...); err != nil {
+			return nil, fmt.Errorf("failed to mix background audio: %w", err)
+		}
+		dubTrack = mixed
+	}
+
+	// Step 6: replace or add the audio track
+	if opts.KeepOriginal && info.HasAudio {
+		originalLanguage := opts.SourceLanguage
+		if originalLanguage == "" && source != nil {
+			originalLanguage = source.Language
+		}
+		err = p.videoOps.MuxAudioTracks(ctx, video.MuxAudioTracksOptions{
+			Input:  opts.Input,
+			Output: opts.Output,
+			Tracks: []video.AudioTrack{{
+				Path:     dubTrack,
+				Language: opts.TargetLanguage,
+				Title:    opts.TargetLanguage + " (dub)",
+				Default:  true,
+			}},
+			KeepOriginal:     true,
+			OriginalLanguage: originalLanguage,
+		})
+	} else {
+		err = p.videoOps.ReplaceAudio(ctx, video.ReplaceAudioOptions{
+			Input:  opts.Input,
+			Audio:  dubTrack,
+			Output: opts.Output,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to add dubbed audio: %w", err)
+	}
+
+	return result, nil
+}
+
+// cloneVoice clones the speaker's voice from the sample file or from the
+// first minute of speech in the input
+func (p *Pipeline) cloneVoice(ctx context.Context, opts Options, speechStart float64, tempDir string) (string, error) {
+	if opts.VoiceSamplePath != "" {
+		return p.tts.GetOrCreateVoiceID(ctx, opts.VoiceSamplePath, "Dub voice")
+	}
+
+	sample := filepath.Join(tempDir, "voice_sample.mp3")
+	if err := p.ffmpeg.Execute(ctx,
+		"-ss", fmt.Sprintf("%.3f", speechStart),
+		"-t", fmt.Sprintf("%d", voiceSampleLength),
+		"-i", opts.Input,
+		"-vn",
+		"-ac", "1",
+		"-c:a", "libmp3lame",
+		"-y", sample,
+	); err != nil {
+		return "", fmt.Errorf("failed to extract voice sample: %w", err)
+	}
+	return p.tts.GetOrCreateVoiceID(ctx, sample, "Dub voice")
+}
+
+// concat joins the fitted pieces with the concat demuxer
+func (p *Pipeline) concat(ctx context.Context, pieces []string, output, tempDir string) error {
+	var list strings.Builder
+	for _, piece := range pieces {
+		list.WriteString(fmt.Sprintf("file '%s'\n", strings.ReplaceAll(piece, "'", `'\''`)))
+	}
+	listFile := filepath.Join(tempDir, "pieces.txt")
+	if err := os.WriteFile(listFile, []byte(list.String()), 0644); err != nil {
+		return err
+	}
+	return p.ffmpeg.Execute(ctx, "-f", "concat", "-safe", "0", "-i", listFile, "-c", "copy", "-y", output)
+}
+
+// fitToSlot returns the playback speed needed to fit speech into slot
+// seconds, capped at maxSpeedup, and whether the speech must still be cut
+func fitToSlot(speech, slot, maxSpeedup float64) (tempo float64, trimmed bool) {
+	if speech <= slot || slot <= 0 {
+		return 1, false
+	}
+	tempo = min(speech/slot, maxSpeedup)
+	return tempo, speech/tempo > slot+trimTolerance
+}
+
+// fitArgs speeds up, cuts and pads speech so it fills exactly slot seconds
+func fitArgs(input, output string, tempo, slot float64) []string {
+	var filters []string
+	if tempo > 1 {
+		filters = append(filters, fmt.Sprintf("atempo=%.4f", tempo))
+	}
+	filters = append(filters,
+		fmt.Sprintf("atrim=end=%.3f", slot),
+		fmt.Sprintf("apad=whole_dur=%.3f", slot),
+	)
+	return []string{
+		"-i", input,
+		"-af", strings.Join(filters, ","),
+		"-ar", "48000",
+		"-ac", "1",
+		"-c:a", "pcm_s16le",
+		"-y", output,
+	}
+}
+
+// silenceArgs renders seconds of silence in the same format as fitted speech
+func silenceArgs(seconds float64, output string) []string {
+	return []string{
+		"-f", "lavfi",
+		"-i", "anullsrc=r=48000:cl=mono",
+		"-t", fmt.Sprintf("%.3f", seconds),
+		"-c:a", "pcm_s16le",
+		"-y", output,
+	}
+}
+
+// mixArgs lays the original audio under the dub at the given volume
+func mixArgs(input, dub, output string, volume float64) []string {
+	return []string{
+		"-i", input,
+		"-i", dub,
+		"-filter_complex", fmt.Sprintf("[0:a:0]volume=%.2f[bg];[bg][1:a]amix=inputs=2:duration=longest:dropout_transition=0:normalize=0[a]", volume),
+		"-map", "[a]",
+		"-ar", "48000",
+		"-c:a", "pcm_s16le",
+		"-y", output,
+	}
+}
+
+// languageSlug turns a language name or code into a file name part
+func languageSlug(language string) string {
+	return strings.Join(strings.Fields(strings.ToLower(language)), "-")
+}
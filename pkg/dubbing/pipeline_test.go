@@ -0,0 +1,47 @@
+package dubbing
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFitToSlot(t *testing.T) {
+	tests := []struct {
+		name        string
+		speech      float64
+		slot        float64
+		wantTempo   float64
+		wantTrimmed bool
+	}{
+		{"fits", 2.0, 3.0, 1, false},
+		{"sped up", 3.3, 3.0, 1.1, false},
+		{"capped and cut", 5.0, 3.0, 1.25, true},
+		{"within tolerance", 3.79, 3.0, 1.25, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempo, trimmed := fitToSlot(tt.speech, tt.slot, 1.25)
+			if diff := tempo - tt.wantTempo; diff > 1e-9 || diff < -1e-9 || trimmed != tt.wantTrimmed {
+				t.Errorf("fitToSlot(%v, %v) = %v, %v; want %v, %v", tt.speech, tt.slot, tempo, trimmed, tt.wantTempo, tt.wantTrimmed)
+			}
+		})
+	}
+}
+
+func TestFitArgs(t *testing.T) {
+	args := strings.Join(fitArgs("speech.mp3", "piece.wav", 1.2, 2.5), " ")
+	if !strings.Contains(args, "-af atempo=1.2000,atrim=end=2.500,apad=whole_dur=2.500") {
+		t.Errorf("Unexpected fit filters: %s", args)
+	}
+
+	args = strings.Join(fitArgs("speech.mp3", "piece.wav", 1, 2.5), " ")
+	if strings.Contains(args, "atempo") {
+		t.Errorf("Expected no atempo at normal speed: %s", args)
+	}
+}
+
+func TestLanguageSlug(t *testing.T) {
+	if got := languageSlug(" Brazilian Portuguese "); got != "brazilian-portuguese" {
+		t.Errorf("languageSlug() = %q", got)
+	}
+}
@@ -0,0 +1,112 @@
+package elements
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// animatedExtensions are overlay files read as animations rather than
+// stills. PNGs are animated when they carry an APNG animation chunk.
+var animatedExtensions = map[string]bool{
+	".gif":  true,
+	".apng": true,
+	".webm": true,
+	".mov":  true,
+	".mkv":  true,
+	".mp4":  true,
+}
+
+// isAnimatedOverlay reports whether an overlay file should be played as an
+// animation: a GIF, APNG or video (with or without alpha)
+func isAnimatedOverlay(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".png" {
+		return isAPNG(path)
+	}
+	return animatedExtensions[ext]
+}
+
+// isAPNG reports whether a PNG has an animation control chunk, which APNG
+// requires before the first image data
+func isAPNG(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(f, header); err != nil || !bytes.Equal(header, []byte("\x89PNG\r\n\x1a\n")) {
+		return false
+	}
+	chunk := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(f, chunk); err != nil {
+			return false
+		}
+		switch string(chunk[4:]) {
+		case "acTL":
+			return true
+		case "IDAT", "IEND":
+			return false
+		}
+		// Skip the chunk data and its CRC
+		if _, err := f.Seek(int64(binary.BigEndian.Uint32(chunk[:4]))+4, io.SeekCurrent); err != nil {
+			return false
+		}
+	}
+}
+
+// overlayInputArgs returns the input options for the overlay file and
+// whether it is played as an animation. Animations loop per opts.Loop, and
+// WebM uses the libvpx decoders, which unlike the native ones keep alpha.
+func (o *Operations) overlayInputArgs(ctx context.Context, opts ImageOverlayOptions) ([]string, bool, error) {
+	animated := isAnimatedOverlay(opts.Image)
+	if opts.Animated != nil {
+		animated = *opts.Animated
+	}
+	if !animated {
+		return []string{"-i", opts.Image}, false, nil
+	}
+
+	loop := overlayLoop(opts)
+	if loop < -1 {
+		return nil, false, fmt.Errorf("loop must be -1 (forever) or a number of extra plays")
+	}
+	args := []string{"-stream_loop", strconv.Itoa(loop)}
+	if strings.EqualFold(filepath.Ext(opts.Image), ".webm") {
+		codec, err := o.ffmpeg.Probe(ctx,
+			"-v", "error",
+			"-select_streams", "v:0",
+			"-show_entries", "stream=codec_name",
+			"-of", "csv=p=0",
+			opts.Image,
+		)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to probe overlay codec: %w", err)
+		}
+		switch strings.TrimSpace(codec) {
+		case "vp8":
+			args = append(args, "-c:v", "libvpx")
+		case "vp9":
+			args = append(args, "-c:v", "libvpx-vp9")
+		}
+	}
+	return append(args, "-i", opts.Image), true, nil
+}
+
+// overlayLoop returns how many extra times an animation plays, -1 for
+// forever (the default)
+func overlayLoop(opts ImageOverlayOptions) int {
+	if opts.Loop == nil {
+		return -1
+	}
+	return *opts.Loop
+}
@@ -29,6 +29,11 @@ type ImageOverlayOptions struct {
 	Rotation  *float64 // Rotation in degrees
 	StartTime *float64 // Start time in seconds
 	Duration  *float64 // Duration in seconds
+
+	// Animation: GIF, APNG or video (WebM/MOV with alpha) stickers play
+	// from StartTime instead of showing their first frame
+	Animated *bool // Play Image as an animation (default: detected from the file)
+	Loop     *int  // Extra plays after the first; -1 loops for the whole video (default: -1)
 }
 
 // ShapeOptions contains options for drawing shapes
@@ -60,6 +65,27 @@ type ShapeOptions struct {
 	// Timing
 	StartTime *float64 // Start time in seconds
 	Duration  *float64 // Duration in seconds
+
+	// Follow moves a rectangle along a motion path, such as a tracked
+	// object, replacing X, Y, Width and Height
+	Follow []FollowKeyframe
+
+	// Callout styling and animation. Shapes other than plain rectangles
+	// are rendered as an antialiased image and overlaid.
+	CornerRadius int             // Corner radius for rounded rectangles
+	Dash         int             // Dash length in pixels for outlines, lines and arrow shafts (0 = solid)
+	HeadSize     int             // Arrowhead length in pixels (default: 4x the line width, at least 16)
+	Motion       []ShapeKeyframe // Offset and scale over time
+}
+
+// FollowKeyframe is a rectangle's position and size at a point in time, as
+// fractions of the frame width and height from the top-left corner
+type FollowKeyframe struct {
+	Time float64
+	X    float64
+	Y    float64
+	W    float64
+	H    float64
 }
 
 // Point represents a 2D point
@@ -80,25 +106,36 @@ func NewOperations(mgr *ffmpeg.Manager) *Operations {
 
 // AddImageOverlay overlays an image on video
 func (o *Operations) AddImageOverlay(ctx context.Context, opts ImageOverlayOptions) error {
+	imageArgs, animated, err := o.overlayInputArgs(ctx, opts)
+	if err != nil {
+		return err
+	}
+
 	// Build filter for image overlay
-	filter := o.buildImageOverlayFilter(opts)
+	filter := o.buildImageOverlayFilter(opts, animated)
 
-	args := []string{
-		"-i", opts.Input,
-		"-i", opts.Image,
+	args := append([]string{"-i", opts.Input}, imageArgs...)
+	args = append(args,
 		"-filter_complex", filter,
 		"-map", "[v]",
 		"-map", "0:a?", // Copy audio if it exists
 		"-c:a", "copy",
 		"-y",
 		opts.Output,
-	}
+	)
 
 	return o.ffmpeg.Execute(ctx, args...)
 }
 
 // DrawShape draws a shape on video
 func (o *Operations) DrawShape(ctx context.Context, opts ShapeOptions) error {
+	if len(opts.Follow) > 0 && (opts.CornerRadius > 0 || opts.Dash > 0 || len(opts.Motion) > 0) {
+		return fmt.Errorf("a rectangle following a track can't also be rounded, dashed or keyframed")
+	}
+	if len(opts.Follow) == 0 && isRenderedShape(opts) {
+		return o.drawRenderedShape(ctx, opts)
+	}
+
 	filter := o.buildShapeFilter(opts)
 
 	args := []string{
@@ -112,14 +149,22 @@ func (o *Operations) DrawShape(ctx context.Context, opts ShapeOptions) error {
 	return o.ffmpeg.Execute(ctx, args...)
 }
 
-// buildImageOverlayFilter builds the filter for image overlay
-func (o *Operations) buildImageOverlayFilter(opts ImageOverlayOptions) string {
+// buildImageOverlayFilter builds the filter for image overlay. An animated
+// overlay starts playing at StartTime and either loops until the video ends
+// or disappears when it finishes.
+func (o *Operations) buildImageOverlayFilter(opts ImageOverlayOptions, animated bool) string {
 	filters := []string{}
 
+	source := "[1:v]"
+	if animated && opts.StartTime != nil && *opts.StartTime > 0 {
+		filters = append(filters, fmt.Sprintf("[1:v]setpts=PTS-STARTPTS+%.3f/TB[anim]", *opts.StartTime))
+		source = "[anim]"
+	}
+
 	// Scale overlay if needed
 	scaleFilter := ""
 	if opts.Scale != nil {
-		scaleFilter = fmt.Sprintf("[1:v]scale=iw*%.2f:ih*%.2f", *opts.Scale, *opts.Scale)
+		scaleFilter = fmt.Sprintf("%sscale=iw*%.2f:ih*%.2f", source, *opts.Scale, *opts.Scale)
 	} else if opts.Width != nil || opts.Height != nil {
 		w := -1
 		h := -1
@@ -129,10 +174,10 @@ func (o *Operations) buildImageOverlayFilter(opts ImageOverlayOptions) string {
 		if opts.Height != nil {
 			h = *opts.Height
 		}
-		scaleFilter = fmt.Sprintf("[1:v]scale=%d:%d", w, h)
+		scaleFilter = fmt.Sprintf("%sscale=%d:%d", source, w, h)
 	}
 
-	overlayInput := "[1:v]"
+	overlayInput := source
 	if scaleFilter != "" {
 		filters = append(filters, scaleFilter+"[scaled]")
 		overlayInput = "[scaled]"
@@ -154,6 +199,16 @@ func (o *Operations) buildImageOverlayFilter(opts ImageOverlayOptions) string {
 		overlayOpts += fmt.Sprintf(":format=auto:alpha=%.2f", *opts.Opacity)
 	}
 
+	// A looping animation would never end, so the video's length decides;
+	// one that plays a set number of times is removed when it finishes
+	if animated {
+		if overlayLoop(opts) < 0 {
+			overlayOpts += ":shortest=1"
+		} else {
+			overlayOpts += ":eof_action=pass"
+		}
+	}
+
 	// Add timing
 	if opts.StartTime != nil || opts.Duration != nil {
 		enable := buildEnableExpression(opts.StartTime, opts.Duration)
@@ -166,7 +221,22 @@ func (o *Operations) buildImageOverlayFilter(opts ImageOverlayOptions) string {
 	return strings.Join(filters, ";")
 }
 
-// buildShapeFilter builds the filter for drawing shapes
+// followBoxParams returns drawbox x, y, w and h expressions that move
+// linearly between keyframes
+func followBoxParams(keyframes []FollowKeyframe) string {
+	times := make([]float64, len(keyframes))
+	xs := make([]float64, len(keyframes))
+	ys := make([]float64, len(keyframes))
+	ws := make([]float64, len(keyframes))
+	hs := make([]float64, len(keyframes))
+	for i, k := range keyframes {
+		times[i], xs[i], ys[i], ws[i], hs[i] = k.Time, k.X, k.Y, k.W, k.H
+	}
+	return fmt.Sprintf("x='iw*(%s)':y='ih*(%s)':w='iw*(%s)':h='ih*(%s)'",
+		ffmpeg.LinearExpr(times, xs), ffmpeg.LinearExpr(times, ys), ffmpeg.LinearExpr(times, ws), ffmpeg.LinearExpr(times, hs))
+}
+
+// buildShapeFilter builds the drawbox filter for plain rectangles
 func (o *Operations) buildShapeFilter(opts ShapeOptions) string {
 	color := opts.Color
 	if color == "" {
@@ -196,6 +266,9 @@ func (o *Operations) buildShapeFilter(opts ShapeOptions) string {
 
 		// drawbox filter
 		params := fmt.Sprintf("x=%d:y=%d:w=%d:h=%d:color=%s", opts.X, opts.Y, width, height, colorWithAlpha)
+		if len(opts.Follow) > 0 {
+			params = fmt.Sprintf("%s:color=%s", followBoxParams(opts.Follow), colorWithAlpha)
+		}
 
 		if opts.BorderWidth > 0 {
 			params += fmt.Sprintf(":t=%d", opts.BorderWidth)
@@ -205,69 +278,6 @@ func (o *Operations) buildShapeFilter(opts ShapeOptions) string {
 
 		filter = "drawbox=" + params
 
-	case "circle":
-		radius := 50
-		if opts.Radius != nil {
-			radius = *opts.Radius
-		}
-
-		// Approximate circle with drawtext or use multiple drawbox
-		// FFmpeg doesn't have a native circle drawing, so we'll use a workaround
-		// Draw using a text character or approximate with polygon
-		filter = fmt.Sprintf("drawtext=text='●':fontsize=%d:fontcolor=%s:x=%d:y=%d",
-			radius*2, colorWithAlpha, opts.X-radius, opts.Y-radius)
-
-	case "line":
-		if opts.X2 == nil || opts.Y2 == nil {
-			filter = fmt.Sprintf("drawbox=x=%d:y=%d:w=1:h=1:color=%s:t=1", opts.X, opts.Y, colorWithAlpha)
-		} else {
-			// Draw line using drawbox (approximate as thin rectangle)
-			x1, y1 := opts.X, opts.Y
-			x2, y2 := *opts.X2, *opts.Y2
-
-			thickness := opts.BorderWidth
-			if thickness == 0 {
-				thickness = 2
-			}
-
-			// Simple horizontal or vertical lines
-			if y1 == y2 {
-				// Horizontal line
-				w := x2 - x1
-				if w < 0 {
-					w = -w
-					x1 = x2
-				}
-				filter = fmt.Sprintf("drawbox=x=%d:y=%d:w=%d:h=%d:color=%s:t=fill",
-					x1, y1, w, thickness, colorWithAlpha)
-			} else if x1 == x2 {
-				// Vertical line
-				h := y2 - y1
-				if h < 0 {
-					h = -h
-					y1 = y2
-				}
-				filter = fmt.Sprintf("drawbox=x=%d:y=%d:w=%d:h=%d:color=%s:t=fill",
-					x1, y1, thickness, h, colorWithAlpha)
-			} else {
-				// Diagonal line - approximate with small boxes along the line
-				filter = fmt.Sprintf("drawbox=x=%d:y=%d:w=%d:h=%d:color=%s:t=fill",
-					x1, y1, 4, 4, colorWithAlpha)
-			}
-		}
-
-	case "arrow":
-		// Draw arrow as line + triangle head
-		// This is simplified - just draw as a line for now
-		if opts.X2 != nil && opts.Y2 != nil {
-			thickness := opts.BorderWidth
-			if thickness == 0 {
-				thickness = 2
-			}
-			filter = fmt.Sprintf("drawbox=x=%d:y=%d:w=%d:h=%d:color=%s:t=fill",
-				opts.X, opts.Y, *opts.X2-opts.X, thickness, colorWithAlpha)
-		}
-
 	default:
 		// Default to rectangle
 		filter = fmt.Sprintf("drawbox=x=%d:y=%d:w=100:h=100:color=%s:t=fill", opts.X, opts.Y, colorWithAlpha)
@@ -0,0 +1,80 @@
+package elements
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildShapeFilterFollow(t *testing.T) {
+	o := &Operations{}
+	start := 1.0
+	filter := o.buildShapeFilter(ShapeOptions{
+		Shape:       "rectangle",
+		Color:       "red",
+		BorderWidth: 4,
+		StartTime:   &start,
+		Follow: []FollowKeyframe{
+			{Time: 1, X: 0.1, Y: 0.2, W: 0.2, H: 0.2},
+			{Time: 3, X: 0.3, Y: 0.2, W: 0.2, H: 0.2},
+		},
+	})
+	want := "drawbox=x='iw*(if(lt(t,3.000),0.1000+(0.2000)*(t-1.000)/2.000,0.3000))':y='ih*(if(lt(t,3.000),0.2000,0.2000))':w='iw*(if(lt(t,3.000),0.2000,0.2000))':h='ih*(if(lt(t,3.000),0.2000,0.2000))':color=red@1.00:t=4:enable='gte(t,1.00)'"
+	if filter != want {
+		t.Errorf("filter = %s\nwant %s", filter, want)
+	}
+}
+
+func TestBuildImageOverlayFilterAnimated(t *testing.T) {
+	o := &Operations{}
+	start, scale, once := 2.0, 0.5, 0
+	opts := ImageOverlayOptions{Position: "top-right", Scale: &scale, StartTime: &start}
+
+	filter := o.buildImageOverlayFilter(opts, true)
+	want := "[1:v]setpts=PTS-STARTPTS+2.000/TB[anim];[anim]scale=iw*0.50:ih*0.50[scaled];[0:v][scaled]overlay=x=W-w-10:y=10:shortest=1:enable='gte(t,2.00)'[v]"
+	if filter != want {
+		t.Errorf("filter = %s\nwant %s", filter, want)
+	}
+
+	opts.Loop = &once
+	if filter := o.buildImageOverlayFilter(opts, true); !strings.Contains(filter, ":eof_action=pass") {
+		t.Errorf("Play-once filter %q should pass the video through after the animation", filter)
+	}
+	if filter := o.buildImageOverlayFilter(opts, false); strings.Contains(filter, "setpts") || strings.Contains(filter, "eof_action") {
+		t.Errorf("Still image filter %q should not be retimed", filter)
+	}
+}
+
+func TestIsAnimatedOverlay(t *testing.T) {
+	dir := t.TempDir()
+	chunk := func(kind string, size int) []byte {
+		b := make([]byte, 12+size)
+		binary.BigEndian.PutUint32(b, uint32(size))
+		copy(b[4:], kind)
+		return b
+	}
+	png := []byte("\x89PNG\r\n\x1a\n")
+	png = append(png, chunk("IHDR", 13)...)
+	still := append(append([]byte{}, png...), chunk("IDAT", 4)...)
+	apng := append(append(append([]byte{}, png...), chunk("acTL", 8)...), chunk("IDAT", 4)...)
+
+	for name, data := range map[string][]byte{"still.png": still, "sticker.png": apng} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := map[string]bool{
+		"still.png":      false,
+		"sticker.png":    true,
+		"logo.jpg":       false,
+		"wave.GIF":       true,
+		"subscribe.webm": true,
+	}
+	for name, want := range tests {
+		if got := isAnimatedOverlay(filepath.Join(dir, name)); got != want {
+			t.Errorf("isAnimatedOverlay(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package elements
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// barLength is the length of the generated bar source. It only needs to cover
+// the longest frame edge; the overlay slides it in from off-screen.
+const barLength = 8192
+
+// ProgressBarOptions contains options for an animated progress bar
+type ProgressBarOptions struct {
+	Input  string
+	Output string
+	Edge   string // top, bottom (default), left or right
+
+	// Styling
+	Color        string  // Bar color (default: white)
+	TrackColor   string  // Unfilled track color; empty draws no track
+	TrackOpacity float64 // 0-1 (default: 0.3)
+	Thickness    int     // Pixels (default: 8)
+
+	// Timing
+	StartTime *float64 // Bar is empty at this time (default: 0)
+	EndTime   *float64 // Bar is full at this time (default: end of video)
+}
+
+// AddProgressBar draws a bar along one edge that fills over a time range
+func (o *Operations) AddProgressBar(ctx context.Context, opts ProgressBarOptions) error {
+	start, end := 0.0, 0.0
+	if opts.StartTime != nil {
+		start = *opts.StartTime
+	}
+	if opts.EndTime != nil {
+		end = *opts.EndTime
+	} else {
+		duration, err := o.ffmpeg.ProbeDuration(ctx, opts.Input)
+		if err != nil {
+			return err
+		}
+		end = duration
+	}
+
+	filter, err := buildProgressBarFilter(opts, start, end)
+	if err != nil {
+		return err
+	}
+
+	return o.ffmpeg.Execute(ctx,
+		"-i", opts.Input,
+		"-filter_complex", filter,
+		"-map", "[v]",
+		"-map", "0:a?",
+		"-c:a", "copy",
+		"-y",
+		opts.Output,
+	)
+}
+
+// buildProgressBarFilter builds the filter graph for a progress bar filling
+// from start to end. The result is labelled [v].
+func buildProgressBarFilter(opts ProgressBarOptions, start, end float64) (string, error) {
+	if end <= start {
+		return "", fmt.Errorf("endTime must be after startTime")
+	}
+	thickness := opts.Thickness
+	if thickness <= 0 {
+		thickness = 8
+	}
+	color := opts.Color
+	if color == "" {
+		color = "white"
+	}
+	trackOpacity := opts.TrackOpacity
+	if trackOpacity <= 0 || trackOpacity > 1 {
+		trackOpacity = 0.3
+	}
+
+	// Fraction of the range elapsed, held at 0 before and 1 after
+	progress := fmt.Sprintf("clip((t-%.3f)/%.3f,0,1)", start, end-start)
+	t := strconv.Itoa(thickness)
+
+	var size, x, y, track string
+	switch opts.Edge {
+	case "", "bottom":
+		size = fmt.Sprintf("%dx%d", barLength, thickness)
+		x, y = fmt.Sprintf("-%d+main_w*%s", barLength, progress), "main_h-"+t
+		track = "x=0:y=ih-" + t + ":w=iw:h=" + t
+	case "top":
+		size = fmt.Sprintf("%dx%d", barLength, thickness)
+		x, y = fmt.Sprintf("-%d+main_w*%s", barLength, progress), "0"
+		track = "x=0:y=0:w=iw:h=" + t
+	case "left":
+		// Vertical bars fill from the bottom up
+		size = fmt.Sprintf("%dx%d", thickness, barLength)
+		x, y = "0", "main_h-main_h*"+progress
+		track = "x=0:y=0:w=" + t + ":h=ih"
+	case "right":
+		size = fmt.Sprintf("%dx%d", thickness, barLength)
+		x, y = "main_w-"+t, "main_h-main_h*"+progress
+		track = "x=iw-" + t + ":y=0:w=" + t + ":h=ih"
+	default:
+		return "", fmt.Errorf("unknown edge: %s (use top, bottom, left or right)", opts.Edge)
+	}
+
+	base := "[0:v]"
+	var parts []string
+	if opts.TrackColor != "" {
+		parts = append(parts, fmt.Sprintf("[0:v]drawbox=%s:color=%s@%.2f:t=fill[track]", track, opts.TrackColor, trackOpacity))
+		base = "[track]"
+	}
+	parts = append(parts,
+		fmt.Sprintf("color=c=%s:s=%s:r=30,format=rgba[bar]", color, size),
+		fmt.Sprintf("%s[bar]overlay=x='%s':y='%s':shortest=1[v]", base, x, y),
+	)
+
+	return strings.Join(parts, ";"), nil
+}
@@ -0,0 +1,41 @@
+package elements
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildProgressBarFilter(t *testing.T) {
+	filter, err := buildProgressBarFilter(ProgressBarOptions{TrackColor: "gray"}, 2, 12)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"[0:v]drawbox=x=0:y=ih-8:w=iw:h=8:color=gray@0.30:t=fill[track]",
+		"color=c=white:s=8192x8:r=30",
+		"[track][bar]overlay=x='-8192+main_w*clip((t-2.000)/10.000,0,1)':y='main_h-8'",
+		"[v]",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q:\n%s", want, filter)
+		}
+	}
+
+	filter, err = buildProgressBarFilter(ProgressBarOptions{Edge: "right", Thickness: 12}, 0, 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(filter, "drawbox") {
+		t.Error("Expected no track without a track color")
+	}
+	if !strings.Contains(filter, "x='main_w-12':y='main_h-main_h*clip((t-0.000)/5.000,0,1)'") {
+		t.Errorf("Unexpected vertical bar position:\n%s", filter)
+	}
+
+	if _, err := buildProgressBarFilter(ProgressBarOptions{}, 5, 5); err == nil {
+		t.Error("Expected error for empty range")
+	}
+	if _, err := buildProgressBarFilter(ProgressBarOptions{Edge: "middle"}, 0, 5); err == nil {
+		t.Error("Expected error for unknown edge")
+	}
+}
@@ -0,0 +1,528 @@
+package elements
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+)
+
+// ShapeKeyframe moves and resizes a drawn shape over time. Values are
+// interpolated linearly between keyframes and held before the first and
+// after the last.
+type ShapeKeyframe struct {
+	Time  float64
+	X     float64  // Horizontal offset in pixels from where the shape is drawn
+	Y     float64  // Vertical offset in pixels
+	Scale *float64 // Size relative to as drawn, about the shape's center (default: 1)
+}
+
+// shapeColors are the color names shapes accept besides hex, with FFmpeg's
+// values for them
+var shapeColors = map[string]string{
+	"white":   "FFFFFF",
+	"black":   "000000",
+	"red":     "FF0000",
+	"green":   "008000",
+	"lime":    "00FF00",
+	"blue":    "0000FF",
+	"yellow":  "FFFF00",
+	"orange":  "FFA500",
+	"purple":  "800080",
+	"pink":    "FFC0CB",
+	"cyan":    "00FFFF",
+	"magenta": "FF00FF",
+	"gray":    "808080",
+	"grey":    "808080",
+	"brown":   "A52A2A",
+}
+
+// maxShapeSize bounds the rendered shape image on each side
+const maxShapeSize = 8192
+
+// vec is a point or direction in pixels
+type vec struct{ x, y float64 }
+
+func (a vec) add(b vec) vec             { return vec{a.x + b.x, a.y + b.y} }
+func (a vec) sub(b vec) vec             { return vec{a.x - b.x, a.y - b.y} }
+func (a vec) mul(k float64) vec         { return vec{a.x * k, a.y * k} }
+func (a vec) length() float64           { return math.Hypot(a.x, a.y) }
+func (a vec) normal() vec               { return vec{-a.y, a.x} }
+func (a vec) unit() vec                 { return a.mul(1 / a.length()) }
+func (a vec) lerp(b vec, t float64) vec { return a.add(b.sub(a).mul(t)) }
+
+// shapeLayer is a set of polygons painted in one color
+type shapeLayer struct {
+	polygons [][]vec
+	color    color.NRGBA
+}
+
+// isRenderedShape reports whether a shape is drawn as an image and
+// overlaid, rather than with drawbox: everything but plain rectangles
+func isRenderedShape(opts ShapeOptions) bool {
+	switch strings.ToLower(opts.Shape) {
+	case "circle", "line", "arrow", "polygon":
+		return true
+	case "rectangle", "rect", "box":
+		outlined := opts.BorderWidth > 0 && opts.BorderColor != nil && *opts.BorderColor != ""
+		return opts.CornerRadius > 0 || opts.Dash > 0 || len(opts.Motion) > 0 || outlined
+	}
+	return false
+}
+
+// drawRenderedShape renders the shape to a transparent PNG the size of its
+// bounds and overlays it, following opts.Motion if set
+func (o *Operations) drawRenderedShape(ctx context.Context, opts ShapeOptions) error {
+	layers, err := buildShapeLayers(opts)
+	if err != nil {
+		return err
+	}
+	img, origin := rasterizeShape(layers)
+	if img == nil {
+		return fmt.Errorf("shape is too large to draw")
+	}
+
+	dir, cleanup, err := scratch.Dir("shape")
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	shapePath := filepath.Join(dir, "shape.png")
+	f, err := os.Create(shapePath)
+	if err != nil {
+		return fmt.Errorf("failed to write shape image: %w", err)
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write shape image: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write shape image: %w", err)
+	}
+
+	args := []string{"-i", opts.Input}
+	if len(opts.Motion) > 0 {
+		// The still is looped so the per-frame scale sees every frame
+		args = append(args, "-loop", "1")
+	}
+	args = append(args,
+		"-i", shapePath,
+		"-filter_complex", buildShapeOverlayFilter(opts, origin, img.Bounds().Dx(), img.Bounds().Dy()),
+		"-map", "[v]",
+		"-map", "0:a?",
+		"-c:a", "copy",
+		"-y",
+		opts.Output,
+	)
+	return o.ffmpeg.Execute(ctx, args...)
+}
+
+// buildShapeOverlayFilter overlays a width x height shape image whose
+// top-left corner belongs at origin, moving and scaling it about its
+// center when the shape has motion keyframes
+func buildShapeOverlayFilter(opts ShapeOptions, origin image.Point, width, height int) string {
+	enable := ""
+	if opts.StartTime != nil || opts.Duration != nil {
+		enable = fmt.Sprintf(":enable='%s'", buildEnableExpression(opts.StartTime, opts.Duration))
+	}
+	if len(opts.Motion) == 0 {
+		return fmt.Sprintf("[1:v]format=rgba[shape];[0:v][shape]overlay=x=%d:y=%d%s[v]", origin.X, origin.Y, enable)
+	}
+
+	keyframes := slices.Clone(opts.Motion)
+	slices.SortFunc(keyframes, func(a, b ShapeKeyframe) int { return cmp.Compare(a.Time, b.Time) })
+	times := make([]float64, len(keyframes))
+	xs, ys, scales := make([]float64, len(keyframes)), make([]float64, len(keyframes)), make([]float64, len(keyframes))
+	for i, k := range keyframes {
+		scale := 1.0
+		if k.Scale != nil {
+			scale = math.Max(0, *k.Scale)
+		}
+		times[i], xs[i], ys[i], scales[i] = k.Time, k.X, k.Y, scale
+	}
+	scale := ffmpeg.LinearExpr(times, scales)
+	return fmt.Sprintf("[1:v]format=rgba,scale=w='max(1,trunc(%d*(%s)))':h='max(1,trunc(%d*(%s)))':eval=frame[shape];"+
+		"[0:v][shape]overlay=x='%.1f+(%s)-w/2':y='%.1f+(%s)-h/2':shortest=1%s[v]",
+		width, scale, height, scale,
+		float64(origin.X)+float64(width)/2, ffmpeg.LinearExpr(times, xs),
+		float64(origin.Y)+float64(height)/2, ffmpeg.LinearExpr(times, ys), enable)
+}
+
+// buildShapeLayers turns the shape into polygons in frame pixels. Closed
+// shapes are filled with Color, or outlined in Color when BorderWidth is
+// set; with a BorderColor too they are filled with Color and outlined in
+// BorderColor. Lines and arrows are strokes in Color.
+func buildShapeLayers(opts ShapeOptions) ([]shapeLayer, error) {
+	opacity := opts.Opacity
+	if opacity == 0 {
+		opacity = 1.0
+	}
+	fill, err := parseShapeColor(opts.Color, opacity)
+	if err != nil {
+		return nil, err
+	}
+	stroke := fill
+	if opts.BorderColor != nil && *opts.BorderColor != "" {
+		if stroke, err = parseShapeColor(*opts.BorderColor, opacity); err != nil {
+			return nil, err
+		}
+	}
+	width := float64(opts.BorderWidth)
+	dash := float64(opts.Dash)
+	origin := vec{float64(opts.X), float64(opts.Y)}
+
+	var outline []vec
+	switch strings.ToLower(opts.Shape) {
+	case "rectangle", "rect", "box":
+		w, h := 100, 100
+		if opts.Width != nil {
+			w = *opts.Width
+		}
+		if opts.Height != nil {
+			h = *opts.Height
+		}
+		if w <= 0 || h <= 0 {
+			return nil, fmt.Errorf("rectangle needs a positive width and height")
+		}
+		outline = roundedRectPath(origin, float64(w), float64(h), float64(opts.CornerRadius))
+	case "circle":
+		radius := 50
+		if opts.Radius != nil {
+			radius = *opts.Radius
+		}
+		if radius <= 0 {
+			return nil, fmt.Errorf("circle needs a positive radius")
+		}
+		outline = arcPath(origin, float64(radius), 0, 2*math.Pi, 96)
+	case "polygon":
+		if len(opts.Points) < 3 {
+			return nil, fmt.Errorf("polygon needs at least 3 points")
+		}
+		for _, p := range opts.Points {
+			outline = append(outline, vec{float64(p.X), float64(p.Y)})
+		}
+	case "line", "arrow":
+		if opts.X2 == nil || opts.Y2 == nil {
+			return nil, fmt.Errorf("%s needs x2 and y2", strings.ToLower(opts.Shape))
+		}
+		end := vec{float64(*opts.X2), float64(*opts.Y2)}
+		if end.sub(origin).length() < 1 {
+			return nil, fmt.Errorf("%s start and end are the same point", strings.ToLower(opts.Shape))
+		}
+		if width == 0 {
+			width = 2
+			if strings.EqualFold(opts.Shape, "arrow") {
+				width = 4
+			}
+		}
+		if strings.EqualFold(opts.Shape, "line") {
+			return []shapeLayer{{strokePath([]vec{origin, end}, false, width, dash), fill}}, nil
+		}
+		return arrowLayers(origin, end, width, float64(opts.HeadSize), dash, fill)
+	default:
+		return nil, fmt.Errorf("unknown shape %q", opts.Shape)
+	}
+
+	if width == 0 {
+		if dash > 0 {
+			return nil, fmt.Errorf("dashed outlines need a borderWidth")
+		}
+		return []shapeLayer{{[][]vec{outline}, fill}}, nil
+	}
+	layers := []shapeLayer{{strokePath(outline, true, width, dash), stroke}}
+	if opts.BorderColor != nil && *opts.BorderColor != "" {
+		layers = append([]shapeLayer{{[][]vec{outline}, fill}}, layers...)
+	}
+	return layers, nil
+}
+
+// arrowLayers draws a shaft from start toward end and a filled triangular
+// head whose tip is at end
+func arrowLayers(start, end vec, width, headSize, dash float64, c color.NRGBA) ([]shapeLayer, error) {
+	if headSize <= 0 {
+		headSize = math.Max(16, 4*width)
+	}
+	dir := end.sub(start).unit()
+	if end.sub(start).length() <= headSize {
+		return nil, fmt.Errorf("arrow is shorter than its %.0fpx head", headSize)
+	}
+	base := end.sub(dir.mul(headSize))
+	side := dir.normal().mul(headSize * 0.6)
+	head := []vec{end, base.add(side), base.sub(side)}
+
+	// A solid shaft runs into the head so no seam shows where they meet
+	shaftEnd := base
+	if dash == 0 {
+		shaftEnd = base.add(dir.mul(headSize / 2))
+	}
+	polygons := append(strokePath([]vec{start, shaftEnd}, false, width, dash), head)
+	return []shapeLayer{{polygons, c}}, nil
+}
+
+// roundedRectPath returns a rectangle's outline with corners rounded to
+// radius, clamped to half the shorter side
+func roundedRectPath(topLeft vec, w, h, radius float64) []vec {
+	radius = math.Min(math.Max(0, radius), math.Min(w, h)/2)
+	if radius == 0 {
+		return []vec{topLeft, {topLeft.x + w, topLeft.y}, {topLeft.x + w, topLeft.y + h}, {topLeft.x, topLeft.y + h}}
+	}
+	segments := max(4, int(radius/2))
+	var path []vec
+	for i, corner := range []vec{
+		{topLeft.x + w - radius, topLeft.y + radius},
+		{topLeft.x + w - radius, topLeft.y + h - radius},
+		{topLeft.x + radius, topLeft.y + h - radius},
+		{topLeft.x + radius, topLeft.y + radius},
+	} {
+		from := -math.Pi/2 + float64(i)*math.Pi/2
+		path = append(path, arcPath(corner, radius, from, from+math.Pi/2, segments)...)
+	}
+	return path
+}
+
+// arcPath returns points on a circle from one angle to another, inclusive
+// of the start and, for partial arcs, of the end
+func arcPath(center vec, radius, from, to float64, segments int) []vec {
+	full := to-from >= 2*math.Pi
+	n := segments
+	if !full {
+		n++
+	}
+	path := make([]vec, 0, n)
+	for i := 0; i < n; i++ {
+		a := from + (to-from)*float64(i)/float64(segments)
+		path = append(path, vec{center.x + radius*math.Cos(a), center.y + radius*math.Sin(a)})
+	}
+	return path
+}
+
+// strokePath returns polygons covering a path drawn width pixels wide, in
+// dashes of dash pixels separated by equal gaps when dash is set. Segments
+// are joined with round joins; the ends are flat.
+func strokePath(path []vec, closed bool, width, dash float64) [][]vec {
+	if closed {
+		path = append(slices.Clone(path), path[0])
+	}
+	pieces := [][]vec{path}
+	if dash > 0 {
+		pieces = dashPath(path, dash)
+	}
+
+	var polygons [][]vec
+	for _, piece := range pieces {
+		for i := 1; i < len(piece); i++ {
+			a, b := piece[i-1], piece[i]
+			if b.sub(a).length() == 0 {
+				continue
+			}
+			n := b.sub(a).unit().normal().mul(width / 2)
+			polygons = append(polygons, []vec{a.add(n), b.add(n), b.sub(n), a.sub(n)})
+		}
+		joins := piece[1 : len(piece)-1]
+		if closed && dash == 0 {
+			joins = piece[:len(piece)-1]
+		}
+		for _, p := range joins {
+			polygons = append(polygons, arcPath(p, width/2, 0, 2*math.Pi, 16))
+		}
+	}
+	return polygons
+}
+
+// dashPath splits a path into dashes of length dash with equal gaps
+func dashPath(path []vec, dash float64) [][]vec {
+	var pieces [][]vec
+	var current []vec
+	on, left := true, dash
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		length := b.sub(a).length()
+		if on && current == nil {
+			current = []vec{a}
+		}
+		pos := 0.0
+		for length-pos > left {
+			pos += left
+			p := a.lerp(b, pos/length)
+			if on {
+				pieces = append(pieces, append(current, p))
+				current = nil
+			} else {
+				current = []vec{p}
+			}
+			on, left = !on, dash
+		}
+		left -= length - pos
+		if on {
+			current = append(current, b)
+		}
+	}
+	if on && len(current) > 1 {
+		pieces = append(pieces, current)
+	}
+	return pieces
+}
+
+// rasterizeShape paints the layers into an image just big enough to hold
+// them and returns it with the frame position of its top-left corner, or
+// nil if it would be unreasonably large
+func rasterizeShape(layers []shapeLayer) (*image.RGBA, image.Point) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, layer := range layers {
+		for _, polygon := range layer.polygons {
+			for _, p := range polygon {
+				minX, minY = math.Min(minX, p.x), math.Min(minY, p.y)
+				maxX, maxY = math.Max(maxX, p.x), math.Max(maxY, p.y)
+			}
+		}
+	}
+	origin := image.Pt(int(math.Floor(minX))-1, int(math.Floor(minY))-1)
+	width := int(math.Ceil(maxX)) + 1 - origin.X
+	height := int(math.Ceil(maxY)) + 1 - origin.Y
+	if width > maxShapeSize || height > maxShapeSize {
+		return nil, origin
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	offset := vec{float64(origin.X), float64(origin.Y)}
+	for _, layer := range layers {
+		coverage := polygonCoverage(layer.polygons, width, height, offset)
+		for i, c := range coverage {
+			a := c * float64(layer.color.A) / 255
+			if a <= 0 {
+				continue
+			}
+			px := img.Pix[i*4 : i*4+4]
+			for ch, v := range []uint8{layer.color.R, layer.color.G, layer.color.B, 255} {
+				px[ch] = uint8(math.Round(float64(v)*a + float64(px[ch])*(1-a)))
+			}
+		}
+	}
+	return img, origin
+}
+
+// polygonCoverage returns how much of each pixel of a width x height image
+// placed at offset the polygons cover, from 0 to 1. Overlapping polygons
+// are unioned (nonzero winding, after orienting them all the same way).
+// Edges are antialiased exactly across and with four samples down.
+func polygonCoverage(polygons [][]vec, width, height int, offset vec) []float64 {
+	const samples = 4
+	oriented := make([][]vec, 0, len(polygons))
+	for _, polygon := range polygons {
+		if signedArea(polygon) < 0 {
+			polygon = slices.Clone(polygon)
+			slices.Reverse(polygon)
+		}
+		oriented = append(oriented, polygon)
+	}
+
+	type crossing struct {
+		x       float64
+		winding int
+	}
+	coverage := make([]float64, width*height)
+	var crossings []crossing
+	for row := 0; row < height; row++ {
+		for s := 0; s < samples; s++ {
+			y := offset.y + float64(row) + (float64(s)+0.5)/samples
+			crossings = crossings[:0]
+			for _, polygon := range oriented {
+				for i := range polygon {
+					a, b := polygon[i], polygon[(i+1)%len(polygon)]
+					winding := 0
+					switch {
+					case a.y <= y && b.y > y:
+						winding = 1
+					case b.y <= y && a.y > y:
+						winding = -1
+					default:
+						continue
+					}
+					x := a.x + (y-a.y)*(b.x-a.x)/(b.y-a.y) - offset.x
+					crossings = append(crossings, crossing{x, winding})
+				}
+			}
+			slices.SortFunc(crossings, func(a, b crossing) int { return cmp.Compare(a.x, b.x) })
+
+			winding, start := 0, 0.0
+			for _, c := range crossings {
+				if winding == 0 {
+					start = c.x
+				}
+				winding += c.winding
+				if winding == 0 {
+					addSpan(coverage[row*width:(row+1)*width], start, c.x, 1.0/samples)
+				}
+			}
+		}
+	}
+	for i, c := range coverage {
+		coverage[i] = math.Min(1, c)
+	}
+	return coverage
+}
+
+// addSpan adds weight times the covered fraction of each pixel between x0
+// and x1 to a row
+func addSpan(row []float64, x0, x1, weight float64) {
+	x0, x1 = math.Max(0, x0), math.Min(float64(len(row)), x1)
+	for px := int(x0); float64(px) < x1; px++ {
+		overlap := math.Min(x1, float64(px+1)) - math.Max(x0, float64(px))
+		if overlap > 0 {
+			row[px] += overlap * weight
+		}
+	}
+}
+
+// signedArea is positive for polygons wound clockwise on screen
+func signedArea(polygon []vec) float64 {
+	area := 0.0
+	for i := range polygon {
+		a, b := polygon[i], polygon[(i+1)%len(polygon)]
+		area += a.x*b.y - b.x*a.y
+	}
+	return area / 2
+}
+
+// parseShapeColor reads a color name, #RRGGBB or 0xRRGGBB, optionally with
+// an FFmpeg-style @alpha suffix, and applies opacity
+func parseShapeColor(s string, opacity float64) (color.NRGBA, error) {
+	name, alphaText, hasAlpha := strings.Cut(strings.TrimSpace(s), "@")
+	if name == "" {
+		name = "white"
+	}
+	if hasAlpha {
+		alpha, err := strconv.ParseFloat(alphaText, 64)
+		if err != nil || alpha < 0 || alpha > 1 {
+			return color.NRGBA{}, fmt.Errorf("invalid color alpha in %q", s)
+		}
+		opacity *= alpha
+	}
+
+	hex, ok := shapeColors[strings.ToLower(name)]
+	if !ok {
+		hex = strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(name), "#"), "0x")
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return color.NRGBA{}, fmt.Errorf("unknown color %q (use #RRGGBB or a basic color name)", s)
+	}
+	return color.NRGBA{
+		R: uint8(value >> 16),
+		G: uint8(value >> 8),
+		B: uint8(value),
+		A: uint8(math.Round(math.Max(0, math.Min(1, opacity)) * 255)),
+	}, nil
+}
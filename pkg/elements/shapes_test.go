@@ -0,0 +1,126 @@
+package elements
+
+import (
+	"image"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestIsRenderedShape(t *testing.T) {
+	tests := []struct {
+		opts ShapeOptions
+		want bool
+	}{
+		{ShapeOptions{Shape: "rectangle"}, false},
+		{ShapeOptions{Shape: "rectangle", CornerRadius: 8}, true},
+		{ShapeOptions{Shape: "box", Dash: 6}, true},
+		{ShapeOptions{Shape: "arrow"}, true},
+		{ShapeOptions{Shape: "polygon"}, true},
+	}
+	for _, tt := range tests {
+		if got := isRenderedShape(tt.opts); got != tt.want {
+			t.Errorf("isRenderedShape(%+v) = %v, want %v", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestRasterizeShapeFill(t *testing.T) {
+	width, height := 20, 10
+	layers, err := buildShapeLayers(ShapeOptions{Shape: "rectangle", X: 10, Y: 20, Width: &width, Height: &height, Color: "red", Opacity: 0.5})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	img, origin := rasterizeShape(layers)
+	if origin != image.Pt(9, 19) || img.Bounds().Dx() != 22 || img.Bounds().Dy() != 12 {
+		t.Fatalf("Shape image %v at %v", img.Bounds(), origin)
+	}
+	// Inside is red at half opacity (premultiplied), the padding is clear
+	if got := img.RGBAAt(10, 5); got.R != 128 || got.G != 0 || got.A != 128 {
+		t.Errorf("Inside pixel = %v", got)
+	}
+	if got := img.RGBAAt(0, 0); got.A != 0 {
+		t.Errorf("Padding pixel = %v", got)
+	}
+}
+
+func TestPolygonCoverageAntialiased(t *testing.T) {
+	// A square covering half of its edge pixels
+	square := []vec{{0.5, 0.5}, {3.5, 0.5}, {3.5, 3.5}, {0.5, 3.5}}
+	coverage := polygonCoverage([][]vec{square}, 4, 4, vec{})
+	if c := coverage[1*4+1]; c != 1 {
+		t.Errorf("Inner pixel coverage = %v, want 1", c)
+	}
+	if c := coverage[1*4+0]; math.Abs(c-0.5) > 0.01 {
+		t.Errorf("Edge pixel coverage = %v, want 0.5", c)
+	}
+	// Overlapping polygons wound either way are unioned, not cancelled
+	reversed := []vec{square[3], square[2], square[1], square[0]}
+	coverage = polygonCoverage([][]vec{square, reversed}, 4, 4, vec{})
+	if c := coverage[1*4+1]; c != 1 {
+		t.Errorf("Overlap coverage = %v, want 1", c)
+	}
+}
+
+func TestDashPath(t *testing.T) {
+	pieces := dashPath([]vec{{0, 0}, {25, 0}}, 5)
+	if len(pieces) != 3 {
+		t.Fatalf("Got %d dashes, want 3: %v", len(pieces), pieces)
+	}
+	if pieces[1][0] != (vec{10, 0}) || pieces[1][len(pieces[1])-1] != (vec{15, 0}) {
+		t.Errorf("Second dash = %v", pieces[1])
+	}
+	// Dashes carry on around corners
+	pieces = dashPath([]vec{{0, 0}, {3, 0}, {3, 10}}, 5)
+	if len(pieces[0]) != 3 || pieces[0][2] != (vec{3, 2}) {
+		t.Errorf("Corner dash = %v", pieces[0])
+	}
+}
+
+func TestArrowLayers(t *testing.T) {
+	x2, y2 := 100, 0
+	layers, err := buildShapeLayers(ShapeOptions{Shape: "arrow", X2: &x2, Y2: &y2, HeadSize: 20})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	polygons := layers[0].polygons
+	head := polygons[len(polygons)-1]
+	if head[0] != (vec{100, 0}) || head[1] != (vec{80, 12}) || head[2] != (vec{80, -12}) {
+		t.Errorf("Arrowhead = %v", head)
+	}
+
+	x2 = 10
+	if _, err := buildShapeLayers(ShapeOptions{Shape: "arrow", X2: &x2, Y2: &y2, HeadSize: 20}); err == nil {
+		t.Error("Expected an error for an arrow shorter than its head")
+	}
+}
+
+func TestBuildShapeOverlayFilterMotion(t *testing.T) {
+	half := 0.5
+	start := 1.0
+	filter := buildShapeOverlayFilter(ShapeOptions{
+		StartTime: &start,
+		Motion:    []ShapeKeyframe{{Time: 3, X: 200}, {Time: 1, Scale: &half}},
+	}, image.Pt(10, 20), 40, 30)
+	for _, want := range []string{
+		"scale=w='max(1,trunc(40*(if(lt(t,3.000),0.5000+(0.5000)*(t-1.000)/2.000,1.0000))))'",
+		"eval=frame",
+		"overlay=x='30.0+(if(lt(t,3.000),0.0000+(200.0000)*(t-1.000)/2.000,200.0000))-w/2'",
+		"y='35.0+(",
+		":shortest=1:enable='gte(t,1.00)'[v]",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Filter %q missing %q", filter, want)
+		}
+	}
+}
+
+func TestParseShapeColor(t *testing.T) {
+	c, err := parseShapeColor("#00ff80@0.5", 1)
+	if err != nil || c.R != 0 || c.G != 255 || c.B != 128 || c.A != 128 {
+		t.Errorf("parseShapeColor = %v, %v", c, err)
+	}
+	if _, err := parseShapeColor("chartreuse-ish", 1); err == nil {
+		t.Error("Expected an error for an unknown color")
+	}
+}
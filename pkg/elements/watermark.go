@@ -0,0 +1,195 @@
+package elements
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Watermark modes
+const (
+	WatermarkSingle   = "single"   // One mark at a position preset
+	WatermarkTile     = "tile"     // Repeated in a grid over the frame
+	WatermarkDiagonal = "diagonal" // Repeated in a grid rotated across the frame
+)
+
+// WatermarkOpacity maps opacity presets to alpha values
+var WatermarkOpacity = map[string]float64{
+	"subtle": 0.15,
+	"light":  0.3,
+	"medium": 0.5,
+	"strong": 0.8,
+}
+
+// WatermarkOptions contains options for watermarking a video with an image
+type WatermarkOptions struct {
+	Input  string
+	Output string
+	Image  string
+
+	Mode     string // single (default), tile or diagonal
+	Position string // Single mode: top-left, top-right, bottom-left, bottom-right (default), center, ...
+
+	// Appearance
+	OpacityPreset string   // subtle, light (default), medium or strong
+	Opacity       *float64 // 0-1, overrides the preset
+
+	// Sizes relative to the frame, so the result looks the same at any resolution
+	Scale   float64 // Watermark width as a fraction of frame width (default: 0.15, or 0.1 when tiled)
+	Margin  float64 // Single mode: distance from the edges as a fraction of the shorter side (default: 0.03)
+	Spacing float64 // Tiled modes: gap between marks as a fraction of their width (default: 1)
+	Angle   float64 // Diagonal mode: rotation in degrees (default: 30)
+
+	// Timing
+	StartTime *float64
+	Duration  *float64
+}
+
+// AddWatermark overlays an image watermark, scaled and placed relative to
+// the frame size, either once or repeated across the frame
+func (o *Operations) AddWatermark(ctx context.Context, opts WatermarkOptions) error {
+	width, height, err := o.ffmpeg.ProbeDimensions(ctx, opts.Input)
+	if err != nil {
+		return err
+	}
+	markW, markH, err := o.ffmpeg.ProbeDimensions(ctx, opts.Image)
+	if err != nil {
+		return err
+	}
+	filter, err := buildWatermarkFilter(opts, width, height, float64(markH)/float64(markW))
+	if err != nil {
+		return err
+	}
+
+	return o.ffmpeg.Execute(ctx,
+		"-i", opts.Input,
+		"-i", opts.Image,
+		"-filter_complex", filter,
+		"-map", "[v]",
+		"-map", "0:a?",
+		"-c:a", "copy",
+		"-y",
+		opts.Output,
+	)
+}
+
+// buildWatermarkFilter builds the filter graph for a watermark on a width x
+// height video. aspect is the watermark's height divided by its width. The
+// result is labelled [v].
+func buildWatermarkFilter(opts WatermarkOptions, width, height int, aspect float64) (string, error) {
+	mode := opts.Mode
+	if mode == "" {
+		mode = WatermarkSingle
+	}
+	if mode != WatermarkSingle && mode != WatermarkTile && mode != WatermarkDiagonal {
+		return "", fmt.Errorf("unknown watermark mode: %s (use single, tile or diagonal)", mode)
+	}
+
+	opacity := WatermarkOpacity["light"]
+	if opts.OpacityPreset != "" {
+		preset, ok := WatermarkOpacity[opts.OpacityPreset]
+		if !ok {
+			return "", fmt.Errorf("unknown opacity preset: %s (use subtle, light, medium or strong)", opts.OpacityPreset)
+		}
+		opacity = preset
+	}
+	if opts.Opacity != nil {
+		opacity = math.Max(0, math.Min(1, *opts.Opacity))
+	}
+
+	scale := opts.Scale
+	if scale <= 0 {
+		scale = 0.15
+		if mode != WatermarkSingle {
+			scale = 0.1
+		}
+	}
+	markW := max(int(math.Round(float64(width)*scale)), 2)
+	markH := max(int(math.Round(float64(markW)*aspect)), 2)
+
+	mark := fmt.Sprintf("[1:v]scale=%d:%d,format=rgba,colorchannelmixer=aa=%.2f", markW, markH, opacity)
+	x, y := "", ""
+
+	if mode == WatermarkSingle {
+		margin := opts.Margin
+		if margin <= 0 {
+			margin = 0.03
+		}
+		m := int(math.Round(float64(min(width, height)) * margin))
+		var err error
+		if x, y, err = watermarkPosition(opts.Position, m); err != nil {
+			return "", err
+		}
+	} else {
+		spacing := opts.Spacing
+		if spacing <= 0 {
+			spacing = 1
+		}
+		gap := int(math.Round(float64(markW) * spacing))
+		cellW, cellH := markW+gap, markH+gap
+
+		// Cover the frame, or its diagonal when the grid will be rotated
+		coverW, coverH := float64(width), float64(height)
+		if mode == WatermarkDiagonal {
+			diagonal := math.Hypot(coverW, coverH)
+			coverW, coverH = diagonal, diagonal
+		}
+		cols := int(math.Ceil(coverW / float64(cellW)))
+		rows := int(math.Ceil(coverH / float64(cellH)))
+
+		// Pad the mark into a transparent cell, repeat the single frame to
+		// fill the grid and tile it into one image
+		mark += fmt.Sprintf(",pad=%d:%d:%d:%d:color=black@0,loop=loop=%d:size=1:start=0,tile=%dx%d",
+			cellW, cellH, gap/2, gap/2, cols*rows-1, cols, rows)
+		if mode == WatermarkDiagonal {
+			angle := opts.Angle
+			if angle == 0 {
+				angle = 30
+			}
+			mark += fmt.Sprintf(",rotate=%.4f:ow=%d:oh=%d:c=none", -angle*math.Pi/180, width, height)
+			x, y = "0", "0"
+		} else {
+			// Center the grid so partial marks are split evenly between edges
+			x = fmt.Sprintf("%d", (width-cols*cellW)/2)
+			y = fmt.Sprintf("%d", (height-rows*cellH)/2)
+		}
+	}
+
+	overlay := fmt.Sprintf("[0:v][wm]overlay=x=%s:y=%s", x, y)
+	if opts.StartTime != nil || opts.Duration != nil {
+		overlay += fmt.Sprintf(":enable='%s'", buildEnableExpression(opts.StartTime, opts.Duration))
+	}
+	return strings.Join([]string{mark + "[wm]", overlay + "[v]"}, ";"), nil
+}
+
+// watermarkPosition returns overlay coordinates for a position preset with
+// an m pixel margin
+func watermarkPosition(position string, m int) (string, string, error) {
+	left, right := fmt.Sprintf("%d", m), fmt.Sprintf("W-w-%d", m)
+	top, bottom := fmt.Sprintf("%d", m), fmt.Sprintf("H-h-%d", m)
+	centerX, centerY := "(W-w)/2", "(H-h)/2"
+
+	switch position {
+	case "top-left":
+		return left, top, nil
+	case "top-center":
+		return centerX, top, nil
+	case "top-right":
+		return right, top, nil
+	case "center-left":
+		return left, centerY, nil
+	case "center":
+		return centerX, centerY, nil
+	case "center-right":
+		return right, centerY, nil
+	case "bottom-left":
+		return left, bottom, nil
+	case "bottom-center":
+		return centerX, bottom, nil
+	case "", "bottom-right":
+		return right, bottom, nil
+	default:
+		return "", "", fmt.Errorf("unknown position: %s", position)
+	}
+}
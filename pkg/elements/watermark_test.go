@@ -0,0 +1,62 @@
+package elements
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildWatermarkFilterSingle(t *testing.T) {
+	start := 5.0
+	filter, err := buildWatermarkFilter(WatermarkOptions{OpacityPreset: "subtle", StartTime: &start}, 1920, 1080, 0.5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "[1:v]scale=288:144,format=rgba,colorchannelmixer=aa=0.15[wm];[0:v][wm]overlay=x=W-w-32:y=H-h-32:enable='gte(t,5.00)'[v]"
+	if filter != want {
+		t.Errorf("filter = %s\nwant %s", filter, want)
+	}
+
+	// Margin and size follow the resolution
+	filter, _ = buildWatermarkFilter(WatermarkOptions{Position: "top-left"}, 1280, 720, 0.5)
+	if !strings.Contains(filter, "scale=192:96") || !strings.Contains(filter, "overlay=x=22:y=22") {
+		t.Errorf("Unexpected 720p filter: %s", filter)
+	}
+}
+
+func TestBuildWatermarkFilterTiled(t *testing.T) {
+	opacity := 0.4
+	filter, err := buildWatermarkFilter(WatermarkOptions{Mode: WatermarkTile, Opacity: &opacity}, 1000, 500, 0.5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// 100x50 marks in 200x150 cells: 5 columns, 4 rows
+	for _, want := range []string{
+		"colorchannelmixer=aa=0.40",
+		"pad=200:150:50:50:color=black@0,loop=loop=19:size=1:start=0,tile=5x4",
+		"overlay=x=0:y=-50",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q:\n%s", want, filter)
+		}
+	}
+
+	filter, err = buildWatermarkFilter(WatermarkOptions{Mode: WatermarkDiagonal}, 1000, 500, 0.5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(filter, "tile=6x8,rotate=-0.5236:ow=1000:oh=500:c=none") {
+		t.Errorf("Unexpected diagonal filter: %s", filter)
+	}
+}
+
+func TestBuildWatermarkFilterErrors(t *testing.T) {
+	for _, opts := range []WatermarkOptions{
+		{Mode: "scatter"},
+		{OpacityPreset: "invisible"},
+		{Position: "middle"},
+	} {
+		if _, err := buildWatermarkFilter(opts, 1920, 1080, 1); err == nil {
+			t.Errorf("Expected error for %+v", opts)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Codec is an encoder or decoder in this FFmpeg build
+type Codec struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // video, audio or subtitle
+	Description string `json:"description"`
+}
+
+// Capabilities describes what this FFmpeg build can do
+type Capabilities struct {
+	Version       string   `json:"version"`
+	Configuration []string `json:"configuration"` // ./configure flags, e.g. --enable-libx265
+	Encoders      []Codec  `json:"encoders"`
+	Decoders      []Codec  `json:"decoders"`
+	Filters       []string `json:"filters"`
+	HWAccels      []string `json:"hwaccels"` // Hardware decoding methods, e.g. cuda, videotoolbox
+}
+
+// HasEncoder reports whether the build includes an encoder
+func (c *Capabilities) HasEncoder(name string) bool {
+	return hasCodec(c.Encoders, name)
+}
+
+// HasDecoder reports whether the build includes a decoder
+func (c *Capabilities) HasDecoder(name string) bool {
+	return hasCodec(c.Decoders, name)
+}
+
+// HasFilter reports whether the build includes a filter
+func (c *Capabilities) HasFilter(name string) bool {
+	i := sort.SearchStrings(c.Filters, name)
+	return i < len(c.Filters) && c.Filters[i] == name
+}
+
+// HasHWAccel reports whether the build supports a hardware decoding method
+func (c *Capabilities) HasHWAccel(name string) bool {
+	for _, h := range c.HWAccels {
+		if h == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCodec(codecs []Codec, name string) bool {
+	for _, c := range codecs {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Capabilities lists the build's version, encoders, decoders, filters and
+// hardware acceleration methods. The result is read once and reused.
+func (m *Manager) Capabilities() (*Capabilities, error) {
+	m.capsOnce.Do(func() {
+		m.caps, m.capsErr = m.readCapabilities()
+	})
+	return m.caps, m.capsErr
+}
+
+func (m *Manager) readCapabilities() (*Capabilities, error) {
+	query := func(flag string) (string, error) {
+		output, err := exec.Command(m.ffmpegPath, "-hide_banner", flag).Output()
+		if err != nil {
+			return "", fmt.Errorf("ffmpeg %s failed: %w", flag, err)
+		}
+		return string(output), nil
+	}
+
+	version, err := exec.Command(m.ffmpegPath, "-version").Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg -version failed: %w", err)
+	}
+	caps := &Capabilities{}
+	caps.Version, caps.Configuration = parseVersion(string(version))
+
+	output, err := query("-encoders")
+	if err != nil {
+		return nil, err
+	}
+	caps.Encoders = parseCodecList(output)
+	if output, err = query("-decoders"); err != nil {
+		return nil, err
+	}
+	caps.Decoders = parseCodecList(output)
+	if output, err = query("-filters"); err != nil {
+		return nil, err
+	}
+	for name := range parseFilterList(output) {
+		caps.Filters = append(caps.Filters, name)
+	}
+	sort.Strings(caps.Filters)
+	// Older builds without -hwaccels simply report none
+	if output, err = query("-hwaccels"); err == nil {
+		caps.HWAccels = parseHWAccels(output)
+	}
+	return caps, nil
+}
+
+// parseVersion reads the version and configure flags from ffmpeg -version
+func parseVersion(output string) (string, []string) {
+	version := "unknown"
+	var configuration []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if fields := strings.Fields(line); len(fields) >= 3 && fields[0] == "ffmpeg" && fields[1] == "version" {
+			version = fields[2]
+		}
+		if rest, ok := strings.CutPrefix(line, "configuration:"); ok {
+			configuration = strings.Fields(rest)
+		}
+	}
+	return version, configuration
+}
+
+// codecTypes maps the first flag of ffmpeg -encoders and -decoders lines
+var codecTypes = map[byte]string{'V': "video", 'A': "audio", 'S': "subtitle"}
+
+// parseCodecList reads codecs from the output of ffmpeg -encoders or
+// -decoders, skipping the legend before the ------ line
+func parseCodecList(output string) []Codec {
+	var codecs []Codec
+	listing := false
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if strings.HasPrefix(fields[0], "---") {
+			listing = true
+			continue
+		}
+		if !listing || len(fields) < 2 || len(fields[0]) != 6 {
+			continue
+		}
+		codecType, ok := codecTypes[fields[0][0]]
+		if !ok {
+			continue
+		}
+		codecs = append(codecs, Codec{Name: fields[1], Type: codecType, Description: strings.Join(fields[2:], " ")})
+	}
+	return codecs
+}
+
+// parseHWAccels reads method names from the output of ffmpeg -hwaccels
+func parseHWAccels(output string) []string {
+	var methods []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasSuffix(line, ":") {
+			continue
+		}
+		methods = append(methods, line)
+	}
+	return methods
+}
@@ -0,0 +1,58 @@
+package ffmpeg
+
+import "testing"
+
+func TestParseCodecList(t *testing.T) {
+	output := `Encoders:
+ V..... = Video
+ A..... = Audio
+ S..... = Subtitle
+ .F.... = Frame-level multithreading
+ ------
+ V....D libx264              libx264 H.264 / AVC / MPEG-4 AVC (codec h264)
+ V..... hevc_nvenc           NVIDIA NVENC hevc encoder (codec hevc)
+ A....D aac                  AAC (Advanced Audio Coding)
+ S..... ass                  ASS (Advanced SubStation Alpha) subtitle
+`
+	codecs := parseCodecList(output)
+	if len(codecs) != 4 {
+		t.Fatalf("parsed %d codecs, want 4: %+v", len(codecs), codecs)
+	}
+	want := []Codec{
+		{"libx264", "video", "libx264 H.264 / AVC / MPEG-4 AVC (codec h264)"},
+		{"hevc_nvenc", "video", "NVIDIA NVENC hevc encoder (codec hevc)"},
+		{"aac", "audio", "AAC (Advanced Audio Coding)"},
+		{"ass", "subtitle", "ASS (Advanced SubStation Alpha) subtitle"},
+	}
+	for i, c := range codecs {
+		if c != want[i] {
+			t.Errorf("codec %d = %+v, want %+v", i, c, want[i])
+		}
+	}
+	caps := &Capabilities{Encoders: codecs, Filters: []string{"drawtext", "scale"}, HWAccels: []string{"cuda"}}
+	if !caps.HasEncoder("libx264") || caps.HasEncoder("libx265") || !caps.HasFilter("scale") || caps.HasFilter("libvmaf") || !caps.HasHWAccel("cuda") {
+		t.Errorf("lookups disagree with %+v", caps)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	output := `ffmpeg version 6.1.1-3ubuntu5 Copyright (c) 2000-2023 the FFmpeg developers
+built with gcc 13 (Ubuntu 13.2.0-23ubuntu3)
+configuration: --prefix=/usr --enable-gpl --enable-libx265 --enable-libvmaf
+libavutil      58. 29.100 / 58. 29.100
+`
+	version, configuration := parseVersion(output)
+	if version != "6.1.1-3ubuntu5" {
+		t.Errorf("version = %q", version)
+	}
+	if len(configuration) != 4 || configuration[2] != "--enable-libx265" {
+		t.Errorf("configuration = %v", configuration)
+	}
+}
+
+func TestParseHWAccels(t *testing.T) {
+	methods := parseHWAccels("Hardware acceleration methods:\nvdpau\ncuda\nvaapi\n\n")
+	if len(methods) != 3 || methods[0] != "vdpau" || methods[2] != "vaapi" {
+		t.Errorf("methods = %v", methods)
+	}
+}
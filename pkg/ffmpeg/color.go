@@ -0,0 +1,115 @@
+package ffmpeg
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// colorOptions are the output options that set color signaling
+var colorOptions = map[string]string{
+	"color_primaries": "-color_primaries",
+	"color_transfer":  "-color_trc",
+	"color_space":     "-colorspace",
+}
+
+// hdrTransfers are the PQ (HDR10) and HLG transfer characteristics
+var hdrTransfers = map[string]bool{"smpte2084": true, "arib-std-b67": true}
+
+// withColorTags carries the first input's HDR color signaling into each
+// output. Encoders otherwise write untagged video, which players show as
+// BT.709 and an HDR clip comes out washed out. Outputs whose options set
+// color themselves or copy or drop the video are left alone, as are all
+// outputs of an SDR input.
+func (m *Manager) withColorTags(ctx context.Context, args []string) []string {
+	input, outputs := colorOutputs(args)
+	if input == "" || len(outputs) == 0 || m.ffprobePath == "" {
+		return args
+	}
+	if info, err := os.Stat(input); err != nil || !info.Mode().IsRegular() {
+		return args
+	}
+
+	probe, err := m.Probe(ctx,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=color_primaries,color_transfer,color_space",
+		"-of", "default=noprint_wrappers=1",
+		input,
+	)
+	if err != nil {
+		return args
+	}
+	tags := parseColorTags(probe)
+	if !hdrTransfers[tags["color_transfer"]] {
+		return args
+	}
+	var options []string
+	for _, key := range []string{"color_primaries", "color_transfer", "color_space"} {
+		if value := tags[key]; value != "" && value != "unknown" {
+			options = append(options, colorOptions[key], value)
+		}
+	}
+
+	tagged := make([]string, 0, len(args)+len(outputs)*len(options))
+	last := 0
+	for _, i := range outputs {
+		tagged = append(tagged, args[last:i]...)
+		tagged = append(tagged, options...)
+		last = i
+	}
+	return append(tagged, args[last:]...)
+}
+
+// colorOutputs walks FFmpeg arguments and returns the first input and the
+// indexes of the output files to tag: local files whose options don't set
+// color, drop the video or, last, copy it (-c copy, -codec:v copy, -vcodec
+// copy)
+func colorOutputs(args []string) (string, []int) {
+	input := ""
+	var outputs []int
+	var colored, dropped, copied bool
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			// An output file ends its options
+			if !colored && !dropped && !copied && outputPath([]string{arg}) != "" {
+				outputs = append(outputs, i)
+			}
+			colored, dropped, copied = false, false, false
+			continue
+		}
+		base, stream, _ := strings.Cut(strings.TrimPrefix(arg, "-"), ":")
+		if base == "y" || base == "n" || flagOptions[base] {
+			dropped = dropped || base == "vn"
+			continue
+		}
+		if i+1 >= len(args) {
+			break
+		}
+		i++
+		switch value := args[i]; {
+		case base == "i":
+			if input == "" {
+				input = value
+			}
+			colored, dropped, copied = false, false, false // Those were input options
+		case base == "color_primaries" || base == "color_trc" || base == "colorspace":
+			colored = true
+		case base == "vcodec" || ((base == "c" || base == "codec") && (stream == "" || strings.HasPrefix(stream, "v"))):
+			copied = value == "copy"
+		}
+	}
+	return input, outputs
+}
+
+// parseColorTags reads key=value lines from ffprobe
+func parseColorTags(output string) map[string]string {
+	tags := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			tags[key] = value
+		}
+	}
+	return tags
+}
@@ -0,0 +1,83 @@
+//go:build linux || darwin
+
+package ffmpeg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithColorTags(t *testing.T) {
+	dir := t.TempDir()
+	probe := filepath.Join(dir, "ffprobe")
+	script := "#!/bin/sh\nprintf 'color_space=bt2020nc\\ncolor_transfer=smpte2084\\ncolor_primaries=bt2020\\n'\n"
+	if err := os.WriteFile(probe, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	input := filepath.Join(dir, "in.mov")
+	if err := os.WriteFile(input, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := &Manager{ffprobePath: probe, monitor: NewResourceMonitor(ResourceLimits{}), probes: newProbeCache()}
+	ctx := context.Background()
+
+	got := strings.Join(m.withColorTags(ctx, []string{"-i", input, "-c:v", "libx264", "-y", "out.mp4"}), " ")
+	want := "-i " + input + " -c:v libx264 -y -color_primaries bt2020 -color_trc smpte2084 -colorspace bt2020nc out.mp4"
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	// Each output is tagged, except one that copies the video
+	got = strings.Join(m.withColorTags(ctx, []string{"-y", "-i", input, "-c", "copy", "-c:v", "libx265", "a.mp4", "-s", "1280x720", "b.mp4", "-codec", "copy", "c.mkv"}), " ")
+	tags := "-color_primaries bt2020 -color_trc smpte2084 -colorspace bt2020nc"
+	want = "-y -i " + input + " -c copy -c:v libx265 " + tags + " a.mp4 -s 1280x720 " + tags + " b.mp4 -codec copy c.mkv"
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	for _, args := range [][]string{
+		{"-i", input, "-c:v", "copy", "out.mp4"},
+		{"-i", input, "-codec", "copy", "out.mp4"},
+		{"-i", input, "-codec:v:0", "copy", "-c:a", "aac", "out.mp4"},
+		{"-i", input, "-vn", "out.wav"},
+		{"-i", input, "-colorspace", "bt709", "out.mp4"},
+		{"-i", input, "-f", "null", "-"},
+		{"-i", "missing.mov", "out.mp4"},
+	} {
+		if got := m.withColorTags(ctx, args); len(got) != len(args) {
+			t.Errorf("expected %v unchanged, got %v", args, got)
+		}
+	}
+}
+
+func TestRunPassthroughKeepsColor(t *testing.T) {
+	dir := t.TempDir()
+	probe := filepath.Join(dir, "ffprobe")
+	if err := os.WriteFile(probe, []byte("#!/bin/sh\nprintf 'color_transfer=smpte2084\\n'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	ffmpeg := filepath.Join(dir, "ffmpeg")
+	if err := os.WriteFile(ffmpeg, []byte("#!/bin/sh\necho \"$@\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	input := filepath.Join(dir, "in.mov")
+	if err := os.WriteFile(input, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := &Manager{ffmpegPath: ffmpeg, ffprobePath: probe, monitor: NewResourceMonitor(ResourceLimits{}), probes: newProbeCache()}
+
+	log, err := m.RunPassthrough(context.Background(), PassthroughOptions{
+		Inputs: []string{input},
+		Args:   []string{"-c:v", "libx264"},
+		Output: filepath.Join(dir, "out.mp4"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(log, "-color_trc") {
+		t.Errorf("run_ffmpeg command was rewritten: %s", log)
+	}
+}
@@ -0,0 +1,196 @@
+package ffmpeg
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PinnedVersion is the FFmpeg release Download installs
+const PinnedVersion = "6.1"
+
+// pinnedRelease hosts static FFmpeg and ffprobe builds, one zip per
+// binary and platform
+const pinnedRelease = "https://github.com/ffbinaries/ffbinaries-prebuilt/releases/download/v" + PinnedVersion + "/"
+
+// pinnedPlatforms maps GOOS/GOARCH to the release's platform names. Apple
+// silicon runs the Intel build under Rosetta.
+var pinnedPlatforms = map[string]string{
+	"linux/amd64":   "linux-64",
+	"linux/arm64":   "linux-arm-64",
+	"darwin/amd64":  "macos-64",
+	"darwin/arm64":  "macos-64",
+	"windows/amd64": "win-64",
+}
+
+// PinnedChecksums are the SHA-256 sums of the release's archives, by file
+// name. An archive without one is refused; checksums passed to Download
+// add to or replace these. The table must cover ffmpeg and ffprobe for
+// every platform in pinnedPlatforms; PinnedArchives lists the names, and
+// each sum comes from the downloaded archive:
+//
+//	curl -sL <pinnedRelease><archive> | sha256sum
+//
+// It is empty until the release archives have been hashed.
+var PinnedChecksums = map[string]string{}
+
+// PinnedArchives returns the archive names Download may fetch, sorted
+func PinnedArchives() []string {
+	seen := map[string]bool{}
+	var archives []string
+	for _, platform := range pinnedPlatforms {
+		for _, tool := range []string{"ffmpeg", "ffprobe"} {
+			archive := pinnedArchive(tool, platform)
+			if !seen[archive] {
+				seen[archive] = true
+				archives = append(archives, archive)
+			}
+		}
+	}
+	sort.Strings(archives)
+	return archives
+}
+
+// pinnedArchive is the release's file name for a binary and platform
+func pinnedArchive(tool, platform string) string {
+	return fmt.Sprintf("%s-%s-%s.zip", tool, PinnedVersion, platform)
+}
+
+// Binaries are the paths of an installed ffmpeg and ffprobe
+type Binaries struct {
+	FFmpeg  string
+	FFprobe string
+}
+
+// downloadTimeout bounds each archive download
+const downloadTimeout = 10 * time.Minute
+
+// OnPath reports whether ffmpeg can be found without a configured path
+func OnPath() bool {
+	_, err := exec.LookPath("ffmpeg")
+	return err == nil
+}
+
+// Download installs the pinned static ffmpeg and ffprobe for this platform
+// into dir/<version>, verifying each archive's SHA-256 before extracting
+// it. Binaries already installed there are reused.
+func Download(ctx context.Context, dir string, checksums map[string]string) (*Binaries, error) {
+	platform, ok := pinnedPlatforms[runtime.GOOS+"/"+runtime.GOARCH]
+	if !ok {
+		return nil, fmt.Errorf("no FFmpeg download for %s/%s; install FFmpeg and set ffmpegPath", runtime.GOOS, runtime.GOARCH)
+	}
+	installDir := filepath.Join(dir, PinnedVersion)
+	if err := os.MkdirAll(installDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", installDir, err)
+	}
+
+	bins := &Binaries{}
+	for _, tool := range []struct {
+		name string
+		path *string
+	}{{"ffmpeg", &bins.FFmpeg}, {"ffprobe", &bins.FFprobe}} {
+		binary := tool.name
+		if runtime.GOOS == "windows" {
+			binary += ".exe"
+		}
+		target := filepath.Join(installDir, binary)
+		if _, err := os.Stat(target); err != nil {
+			archive := pinnedArchive(tool.name, platform)
+			sum := checksums[archive]
+			if sum == "" {
+				sum = PinnedChecksums[archive]
+			}
+			if sum == "" {
+				return nil, fmt.Errorf("no SHA-256 pinned for %s; add it to ffmpegChecksums", archive)
+			}
+			if err := install(ctx, pinnedRelease+archive, sum, binary, target); err != nil {
+				return nil, fmt.Errorf("failed to install %s: %w", tool.name, err)
+			}
+		}
+		*tool.path = target
+	}
+	return bins, nil
+}
+
+// install downloads a zip, checks its SHA-256 and extracts binary to target
+func install(ctx context.Context, url, checksum, binary, target string) error {
+	ctx, cancel := context.WithTimeout(ctx, downloadTimeout)
+	defer cancel()
+
+	archive, err := os.CreateTemp(filepath.Dir(target), "download-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(archive, hash), resp.Body)
+	if err != nil {
+		return fmt.Errorf("download interrupted: %w", err)
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(got, checksum) {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", path.Base(url), got, checksum)
+	}
+
+	return extract(archive, size, binary, target)
+}
+
+// extract copies the file named binary out of a zip to target, through a
+// temporary name so an interrupted copy is never mistaken for an install
+func extract(archive io.ReaderAt, size int64, binary, target string) error {
+	zr, err := zip.NewReader(archive, size)
+	if err != nil {
+		return fmt.Errorf("invalid archive: %w", err)
+	}
+	for _, f := range zr.File {
+		if path.Base(f.Name) != binary || f.FileInfo().IsDir() {
+			continue
+		}
+		src, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		partial := target + ".partial"
+		dst, err := os.OpenFile(partial, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(dst, src); err != nil {
+			dst.Close()
+			os.Remove(partial)
+			return err
+		}
+		if err := dst.Close(); err != nil {
+			os.Remove(partial)
+			return err
+		}
+		return os.Rename(partial, target)
+	}
+	return fmt.Errorf("archive has no %s", binary)
+}
@@ -0,0 +1,77 @@
+package ffmpeg
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestInstall(t *testing.T) {
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	w, _ := zw.Create("ffmpeg-6.1/ffprobe")
+	w.Write([]byte("#!/bin/sh\necho ffprobe\n"))
+	zw.Close()
+	sum := sha256.Sum256(archive.Bytes())
+	checksum := hex.EncodeToString(sum[:])
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive.Bytes())
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "ffprobe")
+	if err := install(context.Background(), srv.URL+"/ffprobe.zip", strings.ToUpper(checksum), "ffprobe", target); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if info, err := os.Stat(target); err != nil || info.Mode()&0100 == 0 {
+		t.Errorf("ffprobe not installed as an executable: %v", err)
+	}
+
+	other := filepath.Join(dir, "other")
+	err := install(context.Background(), srv.URL+"/ffprobe.zip", strings.Repeat("0", 64), "ffprobe", other)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("expected a checksum mismatch, got %v", err)
+	}
+	if _, err := os.Stat(other); !os.IsNotExist(err) {
+		t.Error("a binary was installed despite the checksum mismatch")
+	}
+	if err := install(context.Background(), srv.URL+"/ffprobe.zip", checksum, "ffmpeg", other); err == nil {
+		t.Error("expected an error for an archive without the binary")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("downloads left behind: %v", entries)
+	}
+}
+
+func TestPinnedChecksums(t *testing.T) {
+	archives := PinnedArchives()
+	for _, want := range []string{"ffmpeg-6.1-linux-64.zip", "ffprobe-6.1-linux-64.zip", "ffprobe-6.1-win-64.zip"} {
+		if !slices.Contains(archives, want) {
+			t.Errorf("Expected %s in %v", want, archives)
+		}
+	}
+	for archive, sum := range PinnedChecksums {
+		if _, err := hex.DecodeString(sum); err != nil || len(sum) != 64 {
+			t.Errorf("%s: %q is not a SHA-256", archive, sum)
+		}
+	}
+	if len(PinnedChecksums) == 0 {
+		t.Skip("PinnedChecksums is empty; downloads fail closed until the release archives are hashed")
+	}
+	for _, archive := range archives {
+		if PinnedChecksums[archive] == "" {
+			t.Errorf("No SHA-256 pinned for %s", archive)
+		}
+	}
+}
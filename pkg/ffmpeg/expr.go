@@ -0,0 +1,50 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LinearExpr builds a filter expression in t that moves linearly between
+// values at the given times and holds the first and last values outside
+// them. Filters that evaluate per frame (crop, overlay, drawbox) can use it
+// to follow a motion path.
+func LinearExpr(times, values []float64) string {
+	return LinearExprIn("t", times, values)
+}
+
+// LinearExprIn is LinearExpr with a different time variable, such as T in
+// geq expressions
+func LinearExprIn(variable string, times, values []float64) string {
+	if len(values) == 0 {
+		return "0"
+	}
+	expr := fmt.Sprintf("%.4f", values[len(values)-1])
+	for i := len(values) - 2; i >= 0; i-- {
+		v0, v1 := values[i], values[i+1]
+		t0, t1 := times[i], times[i+1]
+		segment := fmt.Sprintf("%.4f", v0)
+		if v0 != v1 && t1 > t0 {
+			segment = fmt.Sprintf("%.4f+(%.4f)*(%s-%.3f)/%.3f", v0, v1-v0, variable, t0, t1-t0)
+		}
+		expr = fmt.Sprintf("if(lt(%s,%.3f),%s,%s)", variable, t1, segment, expr)
+	}
+	return strings.ReplaceAll(expr, "+(-", "-(")
+}
+
+// AtempoChain builds an audio filter that changes tempo by speed. One
+// atempo filter covers 0.5 to 2.0, so other speeds chain several.
+func AtempoChain(speed float64) string {
+	var filters []string
+	remaining := speed
+	for remaining > 2.0 {
+		filters = append(filters, "atempo=2.0")
+		remaining /= 2.0
+	}
+	for remaining > 0 && remaining < 0.5 {
+		filters = append(filters, "atempo=0.5")
+		remaining /= 0.5
+	}
+	filters = append(filters, fmt.Sprintf("atempo=%.4f", remaining))
+	return strings.Join(filters, ",")
+}
@@ -0,0 +1,35 @@
+package ffmpeg
+
+import "testing"
+
+func TestLinearExpr(t *testing.T) {
+	if got := LinearExpr([]float64{0, 2, 4}, []float64{0.1, 0.3, 0.3}); got != "if(lt(t,2.000),0.1000+(0.2000)*(t-0.000)/2.000,if(lt(t,4.000),0.3000,0.3000))" {
+		t.Errorf("LinearExpr() = %q", got)
+	}
+	if got := LinearExpr([]float64{0, 1}, []float64{0.5, 0.2}); got != "if(lt(t,1.000),0.5000-(0.3000)*(t-0.000)/1.000,0.2000)" {
+		t.Errorf("LinearExpr() moving left = %q", got)
+	}
+	if got := LinearExpr([]float64{3}, []float64{0.4}); got != "0.4000" {
+		t.Errorf("LinearExpr() with one keyframe = %q", got)
+	}
+}
+
+func TestLinearExprIn(t *testing.T) {
+	if got := LinearExprIn("T", []float64{0, 1}, []float64{0, 1}); got != "if(lt(T,1.000),0.0000+(1.0000)*(T-0.000)/1.000,1.0000)" {
+		t.Errorf("LinearExprIn() = %q", got)
+	}
+}
+
+func TestAtempoChain(t *testing.T) {
+	for speed, want := range map[float64]string{
+		1:    "atempo=1.0000",
+		1.5:  "atempo=1.5000",
+		4:    "atempo=2.0,atempo=2.0000",
+		5:    "atempo=2.0,atempo=2.0,atempo=1.2500",
+		0.25: "atempo=0.5,atempo=0.5000",
+	} {
+		if got := AtempoChain(speed); got != want {
+			t.Errorf("AtempoChain(%v) = %q, want %q", speed, got, want)
+		}
+	}
+}
@@ -2,16 +2,28 @@ package ffmpeg
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // Manager handles FFmpeg operations
 type Manager struct {
 	ffmpegPath  string
 	ffprobePath string
+	monitor     *ResourceMonitor
+	probes      *probeCache
+
+	filtersOnce sync.Once
+	filters     map[string]bool
+
+	capsOnce sync.Once
+	caps     *Capabilities
+	capsErr  error
 }
 
 // NewManager creates a new FFmpeg manager
@@ -19,13 +31,15 @@ func NewManager(ffmpegPath, ffprobePath string) (*Manager, error) {
 	m := &Manager{
 		ffmpegPath:  ffmpegPath,
 		ffprobePath: ffprobePath,
+		monitor:     NewResourceMonitor(ResourceLimits{}),
+		probes:      newProbeCache(),
 	}
 
 	// Find FFmpeg if not specified
 	if m.ffmpegPath == "" {
 		path, err := exec.LookPath("ffmpeg")
 		if err != nil {
-			return nil, fmt.Errorf("ffmpeg not found in PATH: %w", err)
+			return nil, fmt.Errorf("ffmpeg not found in PATH (install it, set ffmpegPath, or enable ffmpegAutoDownload): %w", err)
 		}
 		m.ffmpegPath = path
 	}
@@ -71,38 +85,121 @@ func (m *Manager) verifyFFmpeg() error {
 
 // Execute runs an FFmpeg command
 func (m *Manager) Execute(ctx context.Context, args ...string) error {
-	cmd := exec.CommandContext(ctx, m.ffmpegPath, args...)
-	output, err := cmd.CombinedOutput()
+	output, err := m.run(ctx, args)
 	if err != nil {
-		return fmt.Errorf("ffmpeg command failed: %w\nOutput: %s", err, string(output))
+		return fmt.Errorf("ffmpeg command failed: %w\nOutput: %s", err, output)
 	}
 	return nil
 }
 
 // ExecuteWithOutput runs an FFmpeg command and returns output
 func (m *Manager) ExecuteWithOutput(ctx context.Context, args ...string) (string, error) {
+	output, err := m.run(ctx, args)
+	if err != nil {
+		return output, fmt.Errorf("ffmpeg command failed: %w", err)
+	}
+	return output, nil
+}
+
+// run runs an FFmpeg command built by this package's callers, keeping the
+// HDR color signaling of the first input in its outputs
+func (m *Manager) run(ctx context.Context, args []string) (string, error) {
+	return m.execute(ctx, m.withColorTags(ctx, args))
+}
+
+// execute waits for the resource monitor to admit the job, then runs
+// FFmpeg as given and returns its combined output. Unless the caller set a
+// deadline, the run is killed after the job timeout, and in any case after
+// the stall timeout without progress. A new output file is removed if
+// FFmpeg fails.
+func (m *Manager) execute(ctx context.Context, args []string) (string, error) {
+	release, err := m.monitor.Acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	limits := m.monitor.Limits()
+	jobTimeout, stallTimeout := limits.jobTimeouts()
+	ctx, stop := context.WithCancelCause(ctx)
+	defer stop(nil)
+	if _, ok := ctx.Deadline(); !ok && jobTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, jobTimeout, &TimeoutError{Limit: jobTimeout})
+		defer cancel()
+	}
+
+	outPath := outputPath(args)
+	_, statErr := os.Stat(outPath)
+	existed := outPath == "" || statErr == nil
+
+	output := newProgressLog()
 	cmd := exec.CommandContext(ctx, m.ffmpegPath, args...)
-	output, err := cmd.CombinedOutput()
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.WaitDelay = killGrace
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+	if limits.LowPriority {
+		setLowPriority(cmd.Process.Pid)
+	}
+
+	done := make(chan struct{})
+	if stallTimeout > 0 {
+		go watchStall(output, outPath, stallTimeout, func() {
+			stop(&TimeoutError{Limit: stallTimeout, Stalled: true})
+		}, done)
+	}
+	err = cmd.Wait()
+	close(done)
+
 	if err != nil {
-		return string(output), fmt.Errorf("ffmpeg command failed: %w", err)
+		if !existed {
+			os.Remove(outPath)
+		}
+		var timeout *TimeoutError
+		if errors.As(context.Cause(ctx), &timeout) {
+			err = timeout
+		}
 	}
-	return string(output), nil
+	return output.String(), err
 }
 
-// Probe runs ffprobe on a file
+// Probe runs ffprobe on a file, the last argument. Results for a local
+// file are reused until its size or modification time changes.
 func (m *Manager) Probe(ctx context.Context, args ...string) (string, error) {
 	if m.ffprobePath == "" {
 		return "", fmt.Errorf("ffprobe not available")
 	}
+	cached, hit, cacheable := m.probes.get(args)
+	if hit {
+		return cached, nil
+	}
 
 	cmd := exec.CommandContext(ctx, m.ffprobePath, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return string(output), fmt.Errorf("ffprobe command failed: %w", err)
 	}
+	if cacheable {
+		m.probes.put(args, string(output))
+	}
 	return string(output), nil
 }
 
+// ProbeCacheStats reports the reuse of cached ffprobe results
+func (m *Manager) ProbeCacheStats() ProbeCacheStats {
+	return m.probes.snapshot()
+}
+
+// ClearProbeCache drops every cached ffprobe result and returns how many
+// there were
+func (m *Manager) ClearProbeCache() int {
+	return m.probes.clear()
+}
+
 // GetVersion returns FFmpeg version
 func (m *Manager) GetVersion() (string, error) {
 	cmd := exec.Command(m.ffmpegPath, "-version")
@@ -123,7 +220,46 @@ func (m *Manager) GetVersion() (string, error) {
 	return "unknown", nil
 }
 
+// HasFilter reports whether this FFmpeg build includes a filter, e.g.
+// rubberband, which is only present in some builds
+func (m *Manager) HasFilter(name string) bool {
+	m.filtersOnce.Do(func() {
+		output, err := exec.Command(m.ffmpegPath, "-hide_banner", "-filters").Output()
+		if err != nil {
+			m.filters = map[string]bool{}
+			return
+		}
+		m.filters = parseFilterList(string(output))
+	})
+	return m.filters[name]
+}
+
+// parseFilterList reads filter names from the output of ffmpeg -filters
+func parseFilterList(output string) map[string]bool {
+	filters := map[string]bool{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && strings.Contains(fields[2], "->") {
+			filters[fields[1]] = true
+		}
+	}
+	return filters
+}
+
 // GetPath returns the FFmpeg binary path
 func (m *Manager) GetPath() string {
 	return m.ffmpegPath
 }
+
+// SetResourceLimits updates the limits used to defer new FFmpeg jobs
+func (m *Manager) SetResourceLimits(limits ResourceLimits) {
+	m.monitor.SetLimits(limits)
+}
+
+// SystemStatus returns current system utilization and FFmpeg job counts
+func (m *Manager) SystemStatus() SystemStatus {
+	status := m.monitor.Status()
+	stats := m.probes.snapshot()
+	status.ProbeCache = &stats
+	return status
+}
@@ -0,0 +1,30 @@
+package ffmpeg
+
+import "testing"
+
+func TestParseFilterList(t *testing.T) {
+	output := `Filters:
+  T.. = Timeline support
+  .S. = Slice threading
+  ..C = Command support
+  A = Audio input/output
+  V = Video input/output
+  N = Dynamic number and/or type of input/output
+  | = Source or sink filter
+ ... abench            A->A       Benchmark part of a filtergraph.
+ TSC afftdn            A->A       Denoise audio samples using FFT.
+ ..C rubberband        A->A       Apply time-stretching and pitch-shifting.
+ ... amovie            |->N       Read audio from a movie source.
+`
+	filters := parseFilterList(output)
+	for _, name := range []string{"abench", "afftdn", "rubberband", "amovie"} {
+		if !filters[name] {
+			t.Errorf("expected filter %s", name)
+		}
+	}
+	for _, name := range []string{"T..", "=", "A", "Filters:"} {
+		if filters[name] {
+			t.Errorf("legend entry %q parsed as a filter", name)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Limits of a passthrough run
+const (
+	DefaultPassthroughTimeout = 10 * time.Minute
+	MaxPassthroughTimeout     = 2 * time.Hour
+)
+
+// PassthroughOptions describes a raw FFmpeg command:
+// ffmpeg [InputArgs] -i Inputs[0] -i Inputs[1] ... [Args] Output
+type PassthroughOptions struct {
+	Inputs    []string
+	InputArgs []string // Options for the first input, e.g. -ss 10
+	Args      []string // Output options: codecs, filters, maps
+	Output    string
+	Overwrite bool          // Replace an existing output
+	Timeout   time.Duration // Kill FFmpeg after this long (default: 10 minutes)
+}
+
+// flagOptions take no value, so the next argument is another option
+var flagOptions = map[string]bool{
+	"an": true, "vn": true, "sn": true, "dn": true, "shortest": true, "re": true,
+	"copyts": true, "start_at_zero": true, "accurate_seek": true, "noaccurate_seek": true,
+	"autorotate": true, "noautorotate": true, "ignore_unknown": true, "copy_unknown": true,
+	"stats": true, "nostats": true, "hide_banner": true, "benchmark": true, "xerror": true,
+	"nostdin": true, "dump": true, "hex": true, "bitexact": true, "benchmark_all": true,
+	"debug_ts": true, "fix_sub_duration": true, "autoscale": true, "find_stream_info": true,
+	"display_hflip": true, "display_vflip": true, "psnr": true, "qphist": true,
+}
+
+// valueOptions take a value. Options in neither list are refused, since
+// guessing whether an unknown option takes a value would let the next
+// argument slip past as an extra output or a denied option.
+var valueOptions = map[string]bool{
+	// Main options
+	"c": true, "codec": true, "vcodec": true, "acodec": true, "scodec": true, "dcodec": true,
+	"f": true, "t": true, "to": true, "ss": true, "sseof": true, "itsoffset": true, "itsscale": true,
+	"fs": true, "map": true, "map_metadata": true, "map_chapters": true, "metadata": true,
+	"disposition": true, "program": true, "streamid": true, "timestamp": true, "target": true,
+	"frames": true, "vframes": true, "aframes": true, "dframes": true, "tag": true, "vtag": true,
+	"atag": true, "stag": true, "threads": true, "filter_threads": true, "filter_complex_threads": true,
+	"loglevel": true, "v": true, "stream_loop": true, "readrate": true, "readrate_initial_burst": true,
+	"stats_period": true, "discard": true, "bsf": true, "absf": true, "vbsf": true,
+	"max_muxing_queue_size": true, "muxing_queue_data_threshold": true, "muxdelay": true,
+	"muxpreload": true, "copytb": true, "enc_time_base": true, "time_base": true,
+	"shortest_buf_duration": true, "max_error_rate": true, "abort_on": true, "display_rotation": true,
+	// Filters
+	"vf": true, "af": true, "filter": true, "filter_complex": true, "lavfi": true,
+	// Video
+	"r": true, "fpsmax": true, "s": true, "aspect": true, "pix_fmt": true, "fps_mode": true,
+	"vsync": true, "force_key_frames": true, "frame_drop_threshold": true, "top": true,
+	"hwaccel": true, "hwaccel_device": true, "hwaccel_output_format": true, "sws_flags": true,
+	"colorspace": true, "color_primaries": true, "color_trc": true, "color_range": true,
+	"field_order": true, "framerate": true, "video_size": true, "pixel_format": true,
+	// Audio
+	"ar": true, "ac": true, "aq": true, "sample_fmt": true, "channel_layout": true, "ch_layout": true,
+	"async": true, "apad": true, "sample_rate": true, "channels": true, "cutoff": true,
+	// Encoder settings
+	"b": true, "ab": true, "q": true, "qscale": true, "crf": true, "cq": true, "qp": true,
+	"qmin": true, "qmax": true, "preset": true, "tune": true, "profile": true, "level": true,
+	"tier": true, "g": true, "keyint_min": true, "bf": true, "refs": true, "sc_threshold": true,
+	"maxrate": true, "minrate": true, "bufsize": true, "rc": true, "row-mt": true,
+	"tile-columns": true, "tile-rows": true, "cpu-used": true, "deadline": true, "quality": true,
+	"speed": true, "lag-in-frames": true, "auto-alt-ref": true, "compression_level": true,
+	"vbr": true, "application": true, "frame_duration": true, "strict": true, "flags": true,
+	// Demuxer and muxer settings
+	"fflags": true, "avoid_negative_ts": true, "movflags": true, "brand": true, "probesize": true,
+	"analyzeduration": true, "err_detect": true, "max_delay": true, "max_interleave_delta": true,
+	"id3v2_version": true, "write_id3v1": true, "write_xing": true, "loop": true,
+	"start_number": true, "update": true, "timecode": true, "rtbufsize": true,
+}
+
+// deniedOptions read or write files the sandbox can't check, or talk to
+// other processes
+var deniedOptions = map[string]bool{
+	"i": true, "filter_script": true, "filter_complex_script": true, "attach": true,
+	"dump_attachment": true, "report": true, "progress": true, "vstats": true, "vstats_file": true,
+	"sdp_file": true, "pass": true, "passlogfile": true, "fpre": true, "vpre": true, "apre": true,
+	"spre": true, "init_hw_device": true, "filter_hw_device": true,
+}
+
+// encoderParams are encoder parameter strings such as -x264-params and
+// -x264opts, whose keys (stats=, csv=, qpfile=) read and write any file
+var encoderParams = regexp.MustCompile(`(-params|opts)$`)
+
+// deniedFormats write to places other than the output path, or (concat)
+// read files listed inside an input
+var deniedFormats = map[string]bool{"tee": true, "segment": true, "stream_segment": true, "ssegment": true, "hls": true, "dash": true, "concat": true}
+
+// deniedFilters open files or listen for commands
+var deniedFilters = regexp.MustCompile(`(?:^|[,;\]\s])(movie|amovie|sendcmd|asendcmd|zmq|azmq)(?:=|@|[,;\[\s]|$)`)
+
+// filterFiles finds file options in a filter graph: readers must name an
+// existing file, writers a file beside the output
+var filterFiles = regexp.MustCompile(`(?:^|[=:,;\]\s])(filename|file|textfile|fontfile|fontsdir|input|stats_file|log_path|logfile|result|subtitles|ass|lut3d|haldclut)=('[^']*'|[^:,;\[\]\s]+)`)
+
+// writerKeys are the filter options in filterFiles that write a file;
+// file is a writer when it doesn't exist yet
+var writerKeys = map[string]bool{"stats_file": true, "log_path": true, "logfile": true, "result": true}
+
+// protocols are FFmpeg URL schemes that reach beyond local files
+var protocols = regexp.MustCompile(`(?i)^(https?|ftp|rtmps?|rtsp|srt|tcp|udp|tls|unix|pipe|file|concat|concatf|subfile|data|crypto|async|cache|gopher|fd|zmq):`)
+
+// Validate checks a passthrough command against the sandbox: inputs are
+// existing local files, the output is a new file (or Overwrite) that isn't
+// an input, and options may not read or write anything else
+func (opts *PassthroughOptions) Validate() error {
+	if len(opts.Inputs) == 0 {
+		return fmt.Errorf("at least one input is required")
+	}
+	if opts.Output == "" {
+		return fmt.Errorf("output is required")
+	}
+	if opts.Timeout < 0 || opts.Timeout > MaxPassthroughTimeout {
+		return fmt.Errorf("timeout must be between 0 and %s", MaxPassthroughTimeout)
+	}
+
+	output, err := filepath.Abs(opts.Output)
+	if err != nil {
+		return err
+	}
+	if protocols.MatchString(opts.Output) {
+		return fmt.Errorf("output must be a local file: %s", opts.Output)
+	}
+	for _, input := range opts.Inputs {
+		if protocols.MatchString(input) {
+			return fmt.Errorf("input must be a local file: %s", input)
+		}
+		info, err := os.Stat(input)
+		if err != nil {
+			return fmt.Errorf("input not found: %s", input)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("input is not a file: %s", input)
+		}
+		if abs, _ := filepath.Abs(input); abs == output {
+			return fmt.Errorf("output path cannot be the same as input path: %s", opts.Output)
+		}
+	}
+	if info, err := os.Stat(filepath.Dir(output)); err != nil || !info.IsDir() {
+		return fmt.Errorf("output directory does not exist: %s", filepath.Dir(output))
+	}
+	if _, err := os.Stat(output); err == nil && !opts.Overwrite {
+		return fmt.Errorf("output already exists: %s (set overwrite to replace it)", opts.Output)
+	}
+
+	for _, args := range [][]string{opts.InputArgs, opts.Args} {
+		if err := validateArgs(args, filepath.Dir(output)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateArgs walks option/value pairs, refusing extra outputs (a value
+// with no option), unknown and denied options, formats and filters,
+// protocols and filter files outside the sandbox
+func validateArgs(args []string, outputDir string) error {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return fmt.Errorf("unexpected argument %q: extra inputs and outputs are not allowed, use inputs and output", arg)
+		}
+		name := strings.TrimPrefix(arg, "-")
+		base, _, _ := strings.Cut(name, ":") // -c:v, -filter:a
+		base = strings.TrimPrefix(base, "/") // -/filter loads the value from a file
+		if deniedOptions[base] || encoderParams.MatchString(base) || strings.HasPrefix(name, "/") {
+			return fmt.Errorf("option %s is not allowed", arg)
+		}
+		if base == "y" || base == "n" {
+			return fmt.Errorf("option %s is not allowed; set overwrite instead", arg)
+		}
+		if flagOptions[base] || flagOptions[strings.TrimPrefix(base, "no")] { // -noautorotate
+			continue
+		}
+		if !valueOptions[base] {
+			return fmt.Errorf("option %s is not supported", arg)
+		}
+		if i+1 >= len(args) {
+			return fmt.Errorf("option %s needs a value", arg)
+		}
+		i++
+		value := args[i]
+		if protocols.MatchString(value) {
+			return fmt.Errorf("option %s may not use a network or pipe protocol: %s", arg, value)
+		}
+		switch base {
+		case "f":
+			if deniedFormats[value] {
+				return fmt.Errorf("format %s is not allowed", value)
+			}
+		case "vf", "af", "filter", "filter_complex", "lavfi":
+			if err := validateFilters(value, outputDir); err != nil {
+				return fmt.Errorf("%s: %w", arg, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateFilterGraph checks a filter graph from a user the way
+// RunPassthrough does: no filters that read other inputs or take commands,
+// and the files it names must exist or, for logs, be written in outputDir
+func ValidateFilterGraph(graph, outputDir string) error {
+	return validateFilters(graph, outputDir)
+}
+
+// EscapeFilterPath escapes a file path for a filter option written inside
+// single quotes, as in subtitles='%s'. Backslashes become slashes, which
+// FFmpeg accepts on Windows too. The option parser sees ' and : escaped,
+// and the quotes are closed and reopened around each ' so the filter graph
+// parser passes it through.
+func EscapeFilterPath(path string) string {
+	path = strings.ReplaceAll(path, `\`, "/")
+	path = strings.NewReplacer(`'`, `\'`, `:`, `\:`).Replace(path)
+	return strings.ReplaceAll(path, `'`, `'\''`)
+}
+
+// validateFilters checks the files a filter graph names
+func validateFilters(graph, outputDir string) error {
+	if m := deniedFilters.FindStringSubmatch(graph); m != nil {
+		return fmt.Errorf("filter %s is not allowed", m[1])
+	}
+	for _, m := range filterFiles.FindAllStringSubmatch(graph, -1) {
+		key, path := m[1], strings.Trim(m[2], "'")
+		if protocols.MatchString(path) {
+			return fmt.Errorf("%s may not use a network or pipe protocol: %s", key, path)
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		_, statErr := os.Stat(abs)
+		if writerKeys[key] || (key == "file" && statErr != nil) { // metadata's file= writes
+			if filepath.Dir(abs) != outputDir {
+				return fmt.Errorf("%s must be written beside the output, in %s", key, outputDir)
+			}
+			continue
+		}
+		if info, err := os.Stat(abs); err != nil || info.IsDir() == (key != "fontsdir") {
+			return fmt.Errorf("%s not found: %s", key, path)
+		}
+	}
+	return nil
+}
+
+// RunPassthrough validates and runs a raw FFmpeg command as written, with
+// no shell and no added options, killing it after the timeout. A new
+// output is removed if FFmpeg fails. It returns FFmpeg's log.
+func (m *Manager) RunPassthrough(ctx context.Context, opts PassthroughOptions) (string, error) {
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultPassthroughTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	args := []string{"-hide_banner", "-nostdin", "-n"}
+	if opts.Overwrite {
+		args[2] = "-y"
+	}
+	args = append(args, opts.InputArgs...)
+	for _, input := range opts.Inputs {
+		args = append(args, "-i", input)
+	}
+	args = append(args, opts.Args...)
+	args = append(args, opts.Output)
+
+	output, err := m.execute(ctx, args)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return output, fmt.Errorf("ffmpeg timed out after %s", timeout)
+		}
+		return output, fmt.Errorf("ffmpeg command failed: %w", err)
+	}
+	return output, nil
+}
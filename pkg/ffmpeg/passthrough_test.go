@@ -0,0 +1,79 @@
+package ffmpeg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPassthroughValidate(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.mp4")
+	font := filepath.Join(dir, "font.ttf")
+	existing := filepath.Join(dir, "existing.mp4")
+	for _, path := range []string{input, font, existing} {
+		os.WriteFile(path, nil, 0644)
+	}
+	output := filepath.Join(dir, "out.mp4")
+
+	valid := []PassthroughOptions{
+		{Inputs: []string{input}, Output: output},
+		{Inputs: []string{input}, InputArgs: []string{"-ss", "10"}, Args: []string{"-vf", "hqdn3d=4:3:6:4.5,scale=1280:-2", "-c:v", "libx264", "-an", "-crf", "20"}, Output: output},
+		{Inputs: []string{input}, Args: []string{"-vf", "drawtext=fontfile='" + font + "':text=Hi"}, Output: output},
+		{Inputs: []string{input}, Args: []string{"-lavfi", "ametadata=mode=print:file=" + filepath.Join(dir, "levels.txt")}, Output: output},
+		{Inputs: []string{input}, Output: existing, Overwrite: true},
+		{Inputs: []string{input}, InputArgs: []string{"-noautorotate"}, Args: []string{"-map", "0:v", "-dn", "-c:v:0", "libx265", "-b:v", "4M", "-tag:v", "hvc1"}, Output: output},
+	}
+	for i, opts := range valid {
+		if err := opts.Validate(); err != nil {
+			t.Errorf("valid case %d: %v", i, err)
+		}
+	}
+
+	invalid := map[string]PassthroughOptions{
+		"not found":           {Inputs: []string{filepath.Join(dir, "missing.mp4")}, Output: output},
+		"same as input":       {Inputs: []string{input}, Output: input, Overwrite: true},
+		"already exists":      {Inputs: []string{input}, Output: existing},
+		"local file":          {Inputs: []string{"http://example.com/a.mp4"}, Output: output},
+		"extra inputs":        {Inputs: []string{input}, Args: []string{"-c", "copy", "/tmp/second.mp4"}, Output: output},
+		"option -i":           {Inputs: []string{input}, Args: []string{"-i", "/etc/passwd"}, Output: output},
+		"set overwrite":       {Inputs: []string{input}, Args: []string{"-y"}, Output: output},
+		"format tee":          {Inputs: []string{input}, Args: []string{"-f", "tee", "-map", "0"}, Output: output},
+		"filter movie":        {Inputs: []string{input}, Args: []string{"-filter_complex", "movie=/etc/passwd[a];[0][a]overlay"}, Output: output},
+		"protocol":            {Inputs: []string{input}, Args: []string{"-metadata", "pipe:1"}, Output: output},
+		"beside the output":   {Inputs: []string{input}, Args: []string{"-vf", "vidstabdetect=result=/tmp/transforms.trf"}, Output: output},
+		"textfile not found":  {Inputs: []string{input}, Args: []string{"-vf", "drawtext=textfile=/nonexistent.txt"}, Output: output},
+		"option -/vf":         {Inputs: []string{input}, Args: []string{"-/vf", "graph.txt"}, Output: output},
+		"timeout":             {Inputs: []string{input}, Output: output, Timeout: 3 * MaxPassthroughTimeout},
+		"option -x264-params": {Inputs: []string{input}, Args: []string{"-x264-params", "stats=/tmp/x264.log"}, Output: output},
+		"option -x265-params": {Inputs: []string{input}, Args: []string{"-x265-params", "csv=/tmp/x265.csv"}, Output: output},
+		"option -x264opts":    {Inputs: []string{input}, Args: []string{"-x264opts", "stats=/tmp/x264.log"}, Output: output},
+		"option -pass":        {Inputs: []string{input}, Args: []string{"-c:v", "libx264", "-pass", "1"}, Output: output},
+		// An unknown flag must not take /tmp/second.mp4 as its value
+		"option -frobnicate": {Inputs: []string{input}, Args: []string{"-frobnicate", "/tmp/second.mp4"}, Output: output},
+		"option -somebool":   {Inputs: []string{input}, Args: []string{"-somebool", "-i", "/etc/passwd"}, Output: output},
+	}
+	for want, opts := range invalid {
+		err := opts.Validate()
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got %v", want, err)
+		}
+	}
+}
+
+func TestEscapeFilterPath(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		{"/tmp/captions.ass", "/tmp/captions.ass"},
+		{"/media/10:30 take.srt", `/media/10\:30 take.srt`},
+		{"/media/it's.srt", `/media/it\'\''s.srt`},
+		{`C:\clips\captions.srt`, `C\:/clips/captions.srt`},
+	}
+	for _, tt := range tests {
+		if got := EscapeFilterPath(tt.path); got != tt.want {
+			t.Errorf("EscapeFilterPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ProbeDimensions returns the width and height of the first video stream
+// (or image) in a file
+func (m *Manager) ProbeDimensions(ctx context.Context, input string) (int, int, error) {
+	output, err := m.Probe(ctx,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height",
+		"-of", "csv=s=x:p=0",
+		input,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to probe dimensions: %w", err)
+	}
+
+	var width, height int
+	if _, err := fmt.Sscanf(strings.TrimSpace(output), "%dx%d", &width, &height); err != nil || width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("could not determine dimensions for %s", input)
+	}
+	return width, height, nil
+}
+
+// ProbeDuration returns the duration of a media file in seconds
+func (m *Manager) ProbeDuration(ctx context.Context, input string) (float64, error) {
+	output, err := m.Probe(ctx,
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "csv=p=0",
+		input,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to probe duration: %w", err)
+	}
+
+	duration, err := strconv.ParseFloat(strings.TrimSpace(output), 64)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("could not determine duration for %s", input)
+	}
+	return duration, nil
+}
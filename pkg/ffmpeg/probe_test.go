@@ -0,0 +1,40 @@
+//go:build linux || darwin
+
+package ffmpeg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeFFprobe returns a manager whose ffprobe prints output
+func fakeFFprobe(t *testing.T, output string) *Manager {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "ffprobe")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho '"+output+"'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return &Manager{ffprobePath: script, probes: newProbeCache()}
+}
+
+func TestProbeDimensions(t *testing.T) {
+	width, height, err := fakeFFprobe(t, "1920x1080").ProbeDimensions(context.Background(), "in.mp4")
+	if err != nil || width != 1920 || height != 1080 {
+		t.Errorf("ProbeDimensions() = %d, %d, %v; want 1920, 1080", width, height, err)
+	}
+	if _, _, err := fakeFFprobe(t, "").ProbeDimensions(context.Background(), "audio.wav"); err == nil {
+		t.Error("Expected an error for a file without a video stream")
+	}
+}
+
+func TestProbeDuration(t *testing.T) {
+	duration, err := fakeFFprobe(t, "12.480000").ProbeDuration(context.Background(), "in.mp4")
+	if err != nil || duration != 12.48 {
+		t.Errorf("ProbeDuration() = %v, %v; want 12.48", duration, err)
+	}
+	if _, err := fakeFFprobe(t, "N/A").ProbeDuration(context.Background(), "live.ts"); err == nil {
+		t.Error("Expected an error for an unknown duration")
+	}
+}
@@ -0,0 +1,123 @@
+package ffmpeg
+
+import (
+	"container/list"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxProbeEntries = 1000       // Results kept before the least recently used is dropped
+	maxProbeOutput  = 256 * 1024 // Larger results (e.g. packet dumps) are not kept
+)
+
+// ProbeCacheStats reports how well cached ffprobe results are reused
+type ProbeCacheStats struct {
+	Entries       int   `json:"entries"`
+	Hits          int64 `json:"hits"`
+	Misses        int64 `json:"misses"`
+	Invalidations int64 `json:"invalidations"` // Results dropped because the file changed
+}
+
+// probeCache keeps ffprobe output per set of arguments for files that
+// haven't changed since they were probed, least recently used first out
+type probeCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // Most recently used at the front
+	stats   ProbeCacheStats
+}
+
+type probeEntry struct {
+	key     string
+	size    int64
+	modTime time.Time
+	output  string
+}
+
+func newProbeCache() *probeCache {
+	return &probeCache{entries: map[string]*list.Element{}, order: list.New()}
+}
+
+// probeFile returns the file a probe reads, its last argument, if it is a
+// local regular file
+func probeFile(args []string) (os.FileInfo, bool) {
+	if len(args) == 0 {
+		return nil, false
+	}
+	info, err := os.Stat(args[len(args)-1])
+	if err != nil || !info.Mode().IsRegular() {
+		return nil, false
+	}
+	return info, true
+}
+
+// get returns the cached output for args if the file is unchanged. ok is
+// false for probes that can't be cached.
+func (c *probeCache) get(args []string) (output string, hit, ok bool) {
+	info, ok := probeFile(args)
+	if !ok {
+		return "", false, false
+	}
+	key := strings.Join(args, "\x00")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, found := c.entries[key]; found {
+		e := el.Value.(*probeEntry)
+		if e.size == info.Size() && e.modTime.Equal(info.ModTime()) {
+			c.order.MoveToFront(el)
+			c.stats.Hits++
+			return e.output, true, true
+		}
+		c.order.Remove(el)
+		delete(c.entries, key)
+		c.stats.Invalidations++
+	}
+	c.stats.Misses++
+	return "", false, true
+}
+
+// put stores the output of a successful probe
+func (c *probeCache) put(args []string, output string) {
+	info, ok := probeFile(args)
+	if !ok || len(output) > maxProbeOutput {
+		return
+	}
+	key := strings.Join(args, "\x00")
+	e := &probeEntry{key: key, size: info.Size(), modTime: info.ModTime(), output: output}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, found := c.entries[key]; found {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(e)
+	for c.order.Len() > maxProbeEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*probeEntry).key)
+	}
+}
+
+// clear drops every cached result and returns how many there were
+func (c *probeCache) clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := c.order.Len()
+	c.entries = map[string]*list.Element{}
+	c.order.Init()
+	return n
+}
+
+func (c *probeCache) snapshot() ProbeCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	return stats
+}
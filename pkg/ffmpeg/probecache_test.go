@@ -0,0 +1,61 @@
+package ffmpeg
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestProbeCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clip.mp4")
+	if err := os.WriteFile(path, []byte("frames"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := newProbeCache()
+	args := []string{"-show_format", path}
+
+	if _, hit, ok := c.get(args); hit || !ok {
+		t.Fatalf("get on empty cache: hit=%v ok=%v", hit, ok)
+	}
+	c.put(args, `{"format":{}}`)
+	if output, hit, _ := c.get(args); !hit || output != `{"format":{}}` {
+		t.Errorf("get = %q, %v; want cached output", output, hit)
+	}
+	if _, hit, _ := c.get([]string{"-show_streams", path}); hit {
+		t.Error("different arguments shared a result")
+	}
+
+	// Rewriting the file invalidates its results
+	later := time.Now().Add(time.Minute)
+	os.WriteFile(path, []byte("new frames"), 0644)
+	os.Chtimes(path, later, later)
+	if _, hit, _ := c.get(args); hit {
+		t.Error("stale result returned after the file changed")
+	}
+
+	if _, _, ok := c.get([]string{"-i", "https://example.com/clip.mp4"}); ok {
+		t.Error("a remote input was treated as cacheable")
+	}
+
+	stats := c.snapshot()
+	if stats.Hits != 1 || stats.Misses != 3 || stats.Invalidations != 1 || stats.Entries != 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestProbeCacheEviction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clip.mp4")
+	os.WriteFile(path, nil, 0644)
+	c := newProbeCache()
+	for i := 0; i <= maxProbeEntries; i++ {
+		c.put([]string{"-read_intervals", strconv.Itoa(i), path}, "out")
+	}
+	if n := c.snapshot().Entries; n != maxProbeEntries {
+		t.Errorf("Entries = %d, want %d", n, maxProbeEntries)
+	}
+	if _, hit, _ := c.get([]string{"-read_intervals", "0", path}); hit {
+		t.Error("least recently used result was not evicted")
+	}
+}
@@ -0,0 +1,113 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+)
+
+// Process is an FFmpeg run that ends when the caller says so, such as a
+// live capture
+type Process struct {
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	output  *progressLog
+	cancel  context.CancelFunc
+	started time.Time
+	exited  time.Time // Set before done is closed
+	done    chan struct{}
+	err     error
+}
+
+// Start launches FFmpeg in the background and returns once it is running.
+// Unlike Execute it takes no job slot and has no timeouts: the process
+// runs until it exits on its own or is stopped.
+func (m *Manager) Start(args ...string) (*Process, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	output := newProgressLog()
+	cmd := exec.CommandContext(ctx, m.ffmpegPath, args...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.WaitDelay = killGrace
+	setProcessGroup(cmd)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	p := &Process{
+		cmd:     cmd,
+		stdin:   stdin,
+		output:  output,
+		cancel:  cancel,
+		started: time.Now(),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		p.err = cmd.Wait()
+		p.exited = time.Now()
+		cancel()
+		close(p.done)
+	}()
+	return p, nil
+}
+
+// Done is closed when the process has exited
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err returns how the process exited; it is only meaningful after Done
+func (p *Process) Err() error {
+	select {
+	case <-p.done:
+		return p.err
+	default:
+		return nil
+	}
+}
+
+// Output returns what FFmpeg has logged so far
+func (p *Process) Output() string {
+	return p.output.String()
+}
+
+// Elapsed returns how long the process has been running, or how long it
+// ran once it has exited
+func (p *Process) Elapsed() time.Duration {
+	select {
+	case <-p.done:
+		return p.exited.Sub(p.started)
+	default:
+		return time.Since(p.started)
+	}
+}
+
+// Stop asks FFmpeg to quit, which lets it finish writing the output file,
+// and kills it if it hasn't exited within grace. It returns the exit error,
+// if any.
+func (p *Process) Stop(grace time.Duration) error {
+	select {
+	case <-p.done:
+		return p.err
+	default:
+	}
+	if _, err := io.WriteString(p.stdin, "q"); err == nil {
+		p.stdin.Close()
+	}
+	select {
+	case <-p.done:
+		return p.err
+	case <-time.After(grace):
+	}
+	p.cancel()
+	<-p.done
+	return fmt.Errorf("ffmpeg did not stop within %s and was killed", grace)
+}
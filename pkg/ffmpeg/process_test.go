@@ -0,0 +1,58 @@
+//go:build linux || darwin
+
+package ffmpeg
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProcessStop(t *testing.T) {
+	// Quits cleanly when "q" arrives on stdin, like FFmpeg
+	m := fakeFFmpeg(t, "read -r key; echo \"quit on $key\"", ResourceLimits{})
+	p, err := m.Start(filepath.Join(t.TempDir(), "out.mp4"))
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := p.Stop(5 * time.Second); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if !strings.Contains(p.Output(), "quit on q") {
+		t.Errorf("Output = %q", p.Output())
+	}
+
+	// Ignores stdin, so it has to be killed
+	m = fakeFFmpeg(t, "exec sleep 30", ResourceLimits{})
+	p, err = m.Start(filepath.Join(t.TempDir(), "out.mp4"))
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	start := time.Now()
+	if err := p.Stop(200 * time.Millisecond); err == nil {
+		t.Error("Expected an error for a killed process")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Stop took %s", elapsed)
+	}
+	select {
+	case <-p.Done():
+	default:
+		t.Error("Done not closed after Stop")
+	}
+}
+
+func TestProcessElapsedStopsAtExit(t *testing.T) {
+	m := fakeFFmpeg(t, "exit 0", ResourceLimits{})
+	p, err := m.Start(filepath.Join(t.TempDir(), "out.mp4"))
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	<-p.Done()
+	ran := p.Elapsed()
+	time.Sleep(100 * time.Millisecond)
+	if got := p.Elapsed(); got != ran {
+		t.Errorf("Elapsed grew from %s to %s after the process exited", ran, got)
+	}
+}
@@ -0,0 +1,345 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResourceLimits controls when new FFmpeg jobs are deferred. Zero values disable a check.
+type ResourceLimits struct {
+	MaxCPUPercent       float64       // Defer jobs while system CPU usage is above this
+	MaxMemoryPercent    float64       // Defer jobs while memory usage is above this
+	MaxGPUPercent       float64       // Defer jobs while any GPU is busier than this
+	MaxGPUMemoryPercent float64       // Defer jobs while any GPU's memory usage is above this
+	MaxDiskBusyPercent  float64       // Defer jobs while disk I/O utilization is above this
+	MinFreeDiskMB       float64       // Refuse jobs when free space in the temp dir drops below this
+	MaxConcurrentJobs   int           // Maximum FFmpeg jobs running at once
+	LowPriority         bool          // Run FFmpeg at reduced CPU scheduling priority
+	WaitTimeout         time.Duration // How long a job may be deferred (default: 5 minutes)
+	JobTimeout          time.Duration // How long one run may take (default: 6 hours, negative disables)
+	StallTimeout        time.Duration // How long a run may make no progress (default: 10 minutes, negative disables)
+	DiskPath            string        // Path used for free space checks (default: temp dir)
+}
+
+// GPUStatus reports utilization for one GPU
+type GPUStatus struct {
+	Name          string  `json:"name"`
+	Utilization   float64 `json:"utilization"`
+	MemoryUsedMB  float64 `json:"memoryUsedMb"`
+	MemoryTotalMB float64 `json:"memoryTotalMb"`
+}
+
+// MemoryPercent returns GPU memory usage as a percentage
+func (g GPUStatus) MemoryPercent() float64 {
+	if g.MemoryTotalMB <= 0 {
+		return 0
+	}
+	return g.MemoryUsedMB / g.MemoryTotalMB * 100
+}
+
+// SystemStatus reports current utilization. Values that cannot be measured
+// on this platform are -1.
+type SystemStatus struct {
+	CPUPercent      float64          `json:"cpuPercent"`
+	MemoryPercent   float64          `json:"memoryPercent"`
+	MemoryUsedMB    float64          `json:"memoryUsedMb"`
+	MemoryTotalMB   float64          `json:"memoryTotalMb"`
+	DiskBusyPercent float64          `json:"diskBusyPercent"`
+	DiskFreeMB      float64          `json:"diskFreeMb"`
+	GPUs            []GPUStatus      `json:"gpus,omitempty"`
+	ActiveJobs      int              `json:"activeJobs"`
+	QueuedJobs      int              `json:"queuedJobs"`
+	Limits          ResourceLimits   `json:"limits"`
+	ProbeCache      *ProbeCacheStats `json:"probeCache,omitempty"`
+}
+
+// ResourceMonitor gates FFmpeg jobs on system load
+type ResourceMonitor struct {
+	mu     sync.Mutex
+	limits ResourceLimits
+	active int
+	queued int
+}
+
+const resourcePollInterval = 2 * time.Second
+
+// NewResourceMonitor creates a monitor with the given limits
+func NewResourceMonitor(limits ResourceLimits) *ResourceMonitor {
+	return &ResourceMonitor{limits: limits}
+}
+
+// SetLimits replaces the current limits; queued jobs pick them up on their next check
+func (r *ResourceMonitor) SetLimits(limits ResourceLimits) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.limits = limits
+}
+
+// Limits returns the current limits
+func (r *ResourceMonitor) Limits() ResourceLimits {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.limits
+}
+
+// Acquire waits until a job may start and returns a release function.
+// Jobs are deferred while the concurrency limit is reached or the system is
+// over a configured limit, and fail once the wait timeout expires.
+func (r *ResourceMonitor) Acquire(ctx context.Context) (func(), error) {
+	r.mu.Lock()
+	r.queued++
+	r.mu.Unlock()
+
+	dequeue := func() {
+		r.mu.Lock()
+		r.queued--
+		r.mu.Unlock()
+	}
+
+	limits := r.Limits()
+	timeout := limits.WaitTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		reason := r.blockedReason()
+		if reason == "" {
+			// Another job may have started while the system was sampled
+			if reason = r.tryAdmit(); reason == "" {
+				return r.release, nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			dequeue()
+			return nil, fmt.Errorf("job deferred for %s and system is still busy: %s", timeout, reason)
+		}
+
+		select {
+		case <-ctx.Done():
+			dequeue()
+			return nil, ctx.Err()
+		case <-time.After(min(resourcePollInterval, time.Until(deadline))):
+		}
+	}
+}
+
+func (r *ResourceMonitor) release() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.active > 0 {
+		r.active--
+	}
+}
+
+// tryAdmit starts a queued job unless the concurrency limit is reached, and
+// returns why it can't start otherwise. The check and the count change share
+// one lock so concurrent callers can't both take the last slot.
+func (r *ResourceMonitor) tryAdmit() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if reason := concurrencyReason(r.active, r.limits); reason != "" {
+		return reason
+	}
+	r.queued--
+	r.active++
+	return ""
+}
+
+// concurrencyReason returns why the concurrency limit blocks a new job, or "" if it doesn't
+func concurrencyReason(active int, limits ResourceLimits) string {
+	if limits.MaxConcurrentJobs > 0 && active >= limits.MaxConcurrentJobs {
+		return fmt.Sprintf("%d of %d concurrent jobs running", active, limits.MaxConcurrentJobs)
+	}
+	return ""
+}
+
+// blockedReason returns why a new job cannot start yet, or "" if it can.
+// System usage is sampled without holding the lock; tryAdmit makes the
+// final concurrency check.
+func (r *ResourceMonitor) blockedReason() string {
+	r.mu.Lock()
+	limits, active := r.limits, r.active
+	r.mu.Unlock()
+	if reason := concurrencyReason(active, limits); reason != "" {
+		return reason
+	}
+
+	// Skip sampling entirely when no system limits are configured
+	if limits.MaxCPUPercent <= 0 && limits.MaxMemoryPercent <= 0 && limits.MaxGPUPercent <= 0 &&
+		limits.MaxGPUMemoryPercent <= 0 && limits.MaxDiskBusyPercent <= 0 && limits.MinFreeDiskMB <= 0 {
+		return ""
+	}
+
+	return checkLimits(r.sample(limits), limits)
+}
+
+// checkLimits compares a status snapshot against limits
+func checkLimits(status SystemStatus, limits ResourceLimits) string {
+	if limits.MaxCPUPercent > 0 && status.CPUPercent > limits.MaxCPUPercent {
+		return fmt.Sprintf("CPU at %.0f%% (limit %.0f%%)", status.CPUPercent, limits.MaxCPUPercent)
+	}
+	if limits.MaxMemoryPercent > 0 && status.MemoryPercent > limits.MaxMemoryPercent {
+		return fmt.Sprintf("memory at %.0f%% (limit %.0f%%)", status.MemoryPercent, limits.MaxMemoryPercent)
+	}
+	if limits.MaxDiskBusyPercent > 0 && status.DiskBusyPercent > limits.MaxDiskBusyPercent {
+		return fmt.Sprintf("disk I/O at %.0f%% (limit %.0f%%)", status.DiskBusyPercent, limits.MaxDiskBusyPercent)
+	}
+	if limits.MinFreeDiskMB > 0 && status.DiskFreeMB >= 0 && status.DiskFreeMB < limits.MinFreeDiskMB {
+		return fmt.Sprintf("only %.0f MB free disk space (minimum %.0f MB)", status.DiskFreeMB, limits.MinFreeDiskMB)
+	}
+	for _, gpu := range status.GPUs {
+		if limits.MaxGPUPercent > 0 && gpu.Utilization > limits.MaxGPUPercent {
+			return fmt.Sprintf("GPU %s at %.0f%% (limit %.0f%%)", gpu.Name, gpu.Utilization, limits.MaxGPUPercent)
+		}
+		if limits.MaxGPUMemoryPercent > 0 && gpu.MemoryPercent() > limits.MaxGPUMemoryPercent {
+			return fmt.Sprintf("GPU %s memory at %.0f%% (limit %.0f%%)", gpu.Name, gpu.MemoryPercent(), limits.MaxGPUMemoryPercent)
+		}
+	}
+	return ""
+}
+
+// Status samples current utilization and job counts
+func (r *ResourceMonitor) Status() SystemStatus {
+	limits := r.Limits()
+	status := r.sample(limits)
+
+	r.mu.Lock()
+	status.ActiveJobs = r.active
+	status.QueuedJobs = r.queued
+	r.mu.Unlock()
+	status.Limits = limits
+
+	return status
+}
+
+// sample measures CPU and disk activity over a short window plus point-in-time memory, disk and GPU usage
+func (r *ResourceMonitor) sample(limits ResourceLimits) SystemStatus {
+	status := SystemStatus{
+		CPUPercent:      -1,
+		MemoryPercent:   -1,
+		MemoryUsedMB:    -1,
+		MemoryTotalMB:   -1,
+		DiskBusyPercent: -1,
+		DiskFreeMB:      -1,
+	}
+
+	const window = 250 * time.Millisecond
+	cpuBefore, cpuOK := readCPUTimes()
+	ioBefore, ioOK := readDiskIOTicks()
+	time.Sleep(window)
+	if cpuAfter, ok := readCPUTimes(); ok && cpuOK {
+		status.CPUPercent = cpuBefore.percentUntil(cpuAfter)
+	} else if percent, ok := readCPUPercent(); ok {
+		status.CPUPercent = percent
+	}
+	if ioAfter, ok := readDiskIOTicks(); ok && ioOK {
+		status.DiskBusyPercent = diskBusyPercent(ioBefore, ioAfter, window)
+	}
+
+	if used, total, ok := readMemory(); ok && total > 0 {
+		status.MemoryUsedMB = used / (1024 * 1024)
+		status.MemoryTotalMB = total / (1024 * 1024)
+		status.MemoryPercent = used / total * 100
+	}
+
+	diskPath := limits.DiskPath
+	if diskPath == "" {
+		diskPath = defaultDiskPath()
+	}
+	if free, ok := diskFreeBytes(diskPath); ok {
+		status.DiskFreeMB = free / (1024 * 1024)
+	}
+
+	status.GPUs = readNvidiaGPUs()
+	return status
+}
+
+// cpuTimes holds cumulative busy and total CPU ticks
+type cpuTimes struct {
+	busy  float64
+	total float64
+}
+
+func (c cpuTimes) percentUntil(later cpuTimes) float64 {
+	total := later.total - c.total
+	if total <= 0 {
+		return 0
+	}
+	return (later.busy - c.busy) / total * 100
+}
+
+// diskBusyPercent returns the utilization of the busiest device between two
+// io_ticks samples (milliseconds spent doing I/O per device)
+func diskBusyPercent(before, after map[string]uint64, window time.Duration) float64 {
+	busiest := 0.0
+	for device, ticks := range after {
+		prev, ok := before[device]
+		if !ok || ticks < prev {
+			continue
+		}
+		busy := float64(ticks-prev) / float64(window.Milliseconds()) * 100
+		if busy > busiest {
+			busiest = busy
+		}
+	}
+	if busiest > 100 {
+		busiest = 100
+	}
+	return busiest
+}
+
+func defaultDiskPath() string {
+	return os.TempDir()
+}
+
+// readNvidiaGPUs queries NVIDIA GPUs through nvidia-smi when it is installed
+func readNvidiaGPUs() []GPUStatus {
+	path, err := exec.LookPath("nvidia-smi")
+	if err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, path,
+		"--query-gpu=name,utilization.gpu,memory.used,memory.total",
+		"--format=csv,noheader,nounits",
+	).Output()
+	if err != nil {
+		return nil
+	}
+	return parseNvidiaSMI(string(output))
+}
+
+// parseNvidiaSMI parses nvidia-smi CSV output
+func parseNvidiaSMI(output string) []GPUStatus {
+	var gpus []GPUStatus
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != 4 {
+			continue
+		}
+		util, err1 := strconv.ParseFloat(strings.TrimSpace(fields[1]), 64)
+		used, err2 := strconv.ParseFloat(strings.TrimSpace(fields[2]), 64)
+		total, err3 := strconv.ParseFloat(strings.TrimSpace(fields[3]), 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		gpus = append(gpus, GPUStatus{
+			Name:          strings.TrimSpace(fields[0]),
+			Utilization:   util,
+			MemoryUsedMB:  used,
+			MemoryTotalMB: total,
+		})
+	}
+	return gpus
+}
@@ -0,0 +1,68 @@
+package ffmpeg
+
+import (
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// readCPUTimes is not available on macOS; readCPUPercent is used instead
+func readCPUTimes() (cpuTimes, bool) {
+	return cpuTimes{}, false
+}
+
+// readCPUPercent sums per-process CPU usage from ps across all cores
+func readCPUPercent() (float64, bool) {
+	output, err := exec.Command("ps", "-A", "-o", "%cpu=").Output()
+	if err != nil {
+		return 0, false
+	}
+	total := 0.0
+	for _, field := range strings.Fields(string(output)) {
+		if v, err := strconv.ParseFloat(field, 64); err == nil {
+			total += v
+		}
+	}
+	percent := total / float64(runtime.NumCPU())
+	if percent > 100 {
+		percent = 100
+	}
+	return percent, true
+}
+
+var vmStatPattern = regexp.MustCompile(`Pages (free|inactive|speculative|purgeable):\s+(\d+)`)
+
+// readMemory returns used and total memory in bytes from sysctl and vm_stat
+func readMemory() (float64, float64, bool) {
+	memsize, err := exec.Command("sysctl", "-n", "hw.memsize").Output()
+	if err != nil {
+		return 0, 0, false
+	}
+	total, err := strconv.ParseFloat(strings.TrimSpace(string(memsize)), 64)
+	if err != nil || total <= 0 {
+		return 0, 0, false
+	}
+
+	vmStat, err := exec.Command("vm_stat").Output()
+	if err != nil {
+		return 0, 0, false
+	}
+	pageSize := 4096.0
+	if m := regexp.MustCompile(`page size of (\d+) bytes`).FindSubmatch(vmStat); m != nil {
+		pageSize, _ = strconv.ParseFloat(string(m[1]), 64)
+	}
+	available := 0.0
+	for _, m := range vmStatPattern.FindAllSubmatch(vmStat, -1) {
+		pages, _ := strconv.ParseFloat(string(m[2]), 64)
+		available += pages * pageSize
+	}
+
+	return total - available, total, true
+}
+
+// readDiskIOTicks is not available on macOS
+func readDiskIOTicks() (map[string]uint64, bool) {
+	return nil, false
+}
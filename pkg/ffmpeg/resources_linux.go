@@ -0,0 +1,96 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readCPUTimes reads aggregate CPU ticks from /proc/stat
+func readCPUTimes() (cpuTimes, bool) {
+	data, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return cpuTimes{}, false
+	}
+	line := strings.SplitN(string(data), "\n", 2)[0]
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return cpuTimes{}, false
+	}
+
+	var times cpuTimes
+	for i, field := range fields[1:] {
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			continue
+		}
+		times.total += v
+		// idle (3) and iowait (4) are not busy time
+		if i != 3 && i != 4 {
+			times.busy += v
+		}
+	}
+	return times, true
+}
+
+// readCPUPercent is unused on Linux where tick counters are available
+func readCPUPercent() (float64, bool) {
+	return 0, false
+}
+
+// readMemory returns used and total memory in bytes from /proc/meminfo
+func readMemory() (float64, float64, bool) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, 0, false
+	}
+	defer f.Close()
+
+	var total, available float64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		v, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total = v * 1024
+		case "MemAvailable:":
+			available = v * 1024
+		}
+	}
+	if total == 0 {
+		return 0, 0, false
+	}
+	return total - available, total, true
+}
+
+// readDiskIOTicks returns milliseconds spent doing I/O per block device from /proc/diskstats
+func readDiskIOTicks() (map[string]uint64, bool) {
+	data, err := os.ReadFile("/proc/diskstats")
+	if err != nil {
+		return nil, false
+	}
+
+	ticks := make(map[string]uint64)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 13 {
+			continue
+		}
+		name := fields[2]
+		if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[12], 10, 64); err == nil {
+			ticks[name] = v
+		}
+	}
+	return ticks, len(ticks) > 0
+}
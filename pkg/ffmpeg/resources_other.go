@@ -0,0 +1,33 @@
+//go:build !linux && !darwin
+
+package ffmpeg
+
+import "os/exec"
+
+// System sampling is not implemented on this platform; resource limits
+// other than MaxConcurrentJobs are skipped, and a timed out FFmpeg is killed
+// without its child processes.
+
+func readCPUTimes() (cpuTimes, bool) {
+	return cpuTimes{}, false
+}
+
+func readCPUPercent() (float64, bool) {
+	return 0, false
+}
+
+func readMemory() (float64, float64, bool) {
+	return 0, 0, false
+}
+
+func readDiskIOTicks() (map[string]uint64, bool) {
+	return nil, false
+}
+
+func diskFreeBytes(path string) (float64, bool) {
+	return 0, false
+}
+
+func setLowPriority(pid int) {}
+
+func setProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,108 @@
+package ffmpeg
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCheckLimits(t *testing.T) {
+	status := SystemStatus{
+		CPUPercent:      95,
+		MemoryPercent:   40,
+		DiskBusyPercent: -1,
+		DiskFreeMB:      -1,
+		GPUs:            []GPUStatus{{Name: "RTX", Utilization: 20, MemoryUsedMB: 7000, MemoryTotalMB: 8000}},
+	}
+
+	if reason := checkLimits(status, ResourceLimits{}); reason != "" {
+		t.Errorf("Expected no limits to pass, got %q", reason)
+	}
+	if reason := checkLimits(status, ResourceLimits{MaxCPUPercent: 90}); !strings.Contains(reason, "CPU") {
+		t.Errorf("Expected CPU limit to block, got %q", reason)
+	}
+	if reason := checkLimits(status, ResourceLimits{MaxMemoryPercent: 80, MaxGPUPercent: 50}); reason != "" {
+		t.Errorf("Expected memory and GPU limits to pass, got %q", reason)
+	}
+	if reason := checkLimits(status, ResourceLimits{MaxGPUMemoryPercent: 80}); !strings.Contains(reason, "GPU RTX memory") {
+		t.Errorf("Expected GPU memory limit to block, got %q", reason)
+	}
+	// Unmeasurable values never block
+	if reason := checkLimits(status, ResourceLimits{MaxDiskBusyPercent: 50, MinFreeDiskMB: 1024}); reason != "" {
+		t.Errorf("Expected unavailable disk stats to pass, got %q", reason)
+	}
+}
+
+func TestParseNvidiaSMI(t *testing.T) {
+	output := "NVIDIA GeForce RTX 3080, 45, 2048, 10240\nNVIDIA A100, [N/A], 0, 40960\n"
+	gpus := parseNvidiaSMI(output)
+	if len(gpus) != 1 {
+		t.Fatalf("Expected 1 parsed GPU, got %d", len(gpus))
+	}
+	if gpus[0].Name != "NVIDIA GeForce RTX 3080" || gpus[0].Utilization != 45 {
+		t.Errorf("Unexpected GPU: %+v", gpus[0])
+	}
+	if percent := gpus[0].MemoryPercent(); percent != 20 {
+		t.Errorf("Expected 20%% GPU memory, got %.1f", percent)
+	}
+}
+
+func TestDiskBusyPercent(t *testing.T) {
+	before := map[string]uint64{"sda": 1000, "nvme0n1": 500}
+	after := map[string]uint64{"sda": 1100, "nvme0n1": 700, "sdb": 50}
+	if busy := diskBusyPercent(before, after, 250*time.Millisecond); busy != 80 {
+		t.Errorf("Expected busiest device at 80%%, got %.1f", busy)
+	}
+}
+
+func TestAcquireConcurrencyLimit(t *testing.T) {
+	monitor := NewResourceMonitor(ResourceLimits{MaxConcurrentJobs: 1, WaitTimeout: time.Millisecond})
+
+	release, err := monitor.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("First job should start: %v", err)
+	}
+
+	if _, err := monitor.Acquire(context.Background()); err == nil || !strings.Contains(err.Error(), "concurrent jobs") {
+		t.Errorf("Expected second job to be deferred, got %v", err)
+	}
+
+	release()
+	release2, err := monitor.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Job should start after release: %v", err)
+	}
+	release2()
+
+	status := monitor.Status()
+	if status.ActiveJobs != 0 || status.QueuedJobs != 0 {
+		t.Errorf("Expected no active or queued jobs, got %d/%d", status.ActiveJobs, status.QueuedJobs)
+	}
+}
+
+func TestAcquireConcurrencyLimitUnderContention(t *testing.T) {
+	monitor := NewResourceMonitor(ResourceLimits{MaxConcurrentJobs: 2, WaitTimeout: time.Millisecond})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	started := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Jobs hold their slot, so only two may ever start
+			if _, err := monitor.Acquire(context.Background()); err == nil {
+				mu.Lock()
+				started++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if started != 2 {
+		t.Errorf("Expected 2 jobs to start with a limit of 2, got %d", started)
+	}
+}
@@ -0,0 +1,31 @@
+//go:build linux || darwin
+
+package ffmpeg
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// diskFreeBytes returns the space available to unprivileged users at path
+func diskFreeBytes(path string) (float64, bool) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, false
+	}
+	return float64(stat.Bavail) * float64(stat.Bsize), true
+}
+
+// setLowPriority lowers the scheduling priority of a running process
+func setLowPriority(pid int) {
+	_ = syscall.Setpriority(syscall.PRIO_PROCESS, pid, 10)
+}
+
+// setProcessGroup starts FFmpeg in its own process group and makes
+// cancellation kill the whole group, including any helpers it spawned
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
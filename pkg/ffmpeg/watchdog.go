@@ -0,0 +1,122 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultJobTimeout caps one FFmpeg run when no timeout is configured.
+	// It is deliberately generous: long renders must finish, hung ones must not
+	// hold a job slot forever.
+	DefaultJobTimeout = 6 * time.Hour
+
+	// DefaultStallTimeout is how long FFmpeg may go without logging progress
+	// or growing its output before it is considered hung
+	DefaultStallTimeout = 10 * time.Minute
+
+	// killGrace is how long Wait keeps reading output after FFmpeg is killed
+	killGrace = 5 * time.Second
+)
+
+// TimeoutError reports an FFmpeg run the watchdog killed
+type TimeoutError struct {
+	Limit   time.Duration
+	Stalled bool // No progress for Limit, rather than running longer than Limit
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Stalled {
+		return fmt.Sprintf("ffmpeg made no progress for %s and was stopped", e.Limit)
+	}
+	return fmt.Sprintf("ffmpeg ran longer than %s and was stopped", e.Limit)
+}
+
+// jobTimeouts returns the run and stall limits; zero means the default and a
+// negative value disables the limit
+func (l ResourceLimits) jobTimeouts() (time.Duration, time.Duration) {
+	run, stall := l.JobTimeout, l.StallTimeout
+	if run == 0 {
+		run = DefaultJobTimeout
+	}
+	if stall == 0 {
+		stall = DefaultStallTimeout
+	}
+	return max(run, 0), max(stall, 0)
+}
+
+// progressLog collects FFmpeg's output and remembers when it last wrote
+type progressLog struct {
+	mu   sync.Mutex
+	buf  bytes.Buffer
+	last time.Time
+}
+
+func newProgressLog() *progressLog {
+	return &progressLog{last: time.Now()}
+}
+
+func (p *progressLog) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.last = time.Now()
+	return p.buf.Write(b)
+}
+
+func (p *progressLog) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.buf.String()
+}
+
+func (p *progressLog) lastWrite() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.last
+}
+
+// watchStall calls stop once neither the log nor the output file has changed
+// for limit. It returns when done is closed.
+func watchStall(log *progressLog, output string, limit time.Duration, stop func(), done <-chan struct{}) {
+	interval := min(5*time.Second, limit/4)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastSize := int64(-1)
+	lastGrowth := time.Now()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			if output != "" {
+				if info, err := os.Stat(output); err == nil && info.Size() != lastSize {
+					lastSize = info.Size()
+					lastGrowth = now
+				}
+			}
+			if now.Sub(log.lastWrite()) > limit && now.Sub(lastGrowth) > limit {
+				stop()
+				return
+			}
+		}
+	}
+}
+
+// outputPath returns the file FFmpeg writes, taken as the last argument, or
+// "" when that is not a plain local path
+func outputPath(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	path := args[len(args)-1]
+	if strings.HasPrefix(path, "-") || strings.HasPrefix(path, "pipe:") ||
+		strings.Contains(path, "://") || strings.Contains(path, "%") {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,74 @@
+//go:build linux || darwin
+
+package ffmpeg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeFFmpeg returns a manager whose ffmpeg is a shell script that creates
+// its last argument and then runs body
+func fakeFFmpeg(t *testing.T, body string, limits ResourceLimits) *Manager {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "ffmpeg")
+	content := "#!/bin/sh\nfor a; do out=$a; done\n: > \"$out\"\n" + body + "\n"
+	if err := os.WriteFile(script, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return &Manager{ffmpegPath: script, monitor: NewResourceMonitor(limits), probes: newProbeCache()}
+}
+
+func TestRunWatchdog(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		limits  ResourceLimits
+		stalled bool
+	}{
+		// The child sleep keeps the output pipe open, so this only returns
+		// promptly if the whole process group is killed
+		{"stall", "sleep 30", ResourceLimits{StallTimeout: 200 * time.Millisecond, JobTimeout: -1}, true},
+		{"timeout", "while true; do echo frame; sleep 0.05; done", ResourceLimits{JobTimeout: 300 * time.Millisecond}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := fakeFFmpeg(t, tt.body, tt.limits)
+			output := filepath.Join(t.TempDir(), "out.mp4")
+
+			start := time.Now()
+			_, err := m.run(context.Background(), []string{"-i", "in.mp4", output})
+			if elapsed := time.Since(start); elapsed > 4*time.Second {
+				t.Errorf("run took %s after the limit", elapsed)
+			}
+			var timeout *TimeoutError
+			if !errors.As(err, &timeout) {
+				t.Fatalf("expected a TimeoutError, got %v", err)
+			}
+			if timeout.Stalled != tt.stalled {
+				t.Errorf("Stalled = %v, want %v", timeout.Stalled, tt.stalled)
+			}
+			if _, err := os.Stat(output); !os.IsNotExist(err) {
+				t.Error("partial output was not removed")
+			}
+		})
+	}
+}
+
+func TestRunKeepsExistingOutput(t *testing.T) {
+	m := fakeFFmpeg(t, "exit 1", ResourceLimits{})
+	output := filepath.Join(t.TempDir(), "out.mp4")
+	if err := os.WriteFile(output, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.run(context.Background(), []string{"-y", "-i", "in.mp4", output}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Error("existing output was removed")
+	}
+}
@@ -0,0 +1,299 @@
+package fonts
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:embed bundled/DejaVuSans.ttf
+var bundledFont []byte
+
+// BundledFamily is the family of the font shipped with the server. It is
+// always available, so text renders even when no system fonts are installed.
+const BundledFamily = "DejaVu Sans"
+
+// fallbackFamilies are tried in order for text the chosen font cannot draw,
+// covering CJK scripts and emoji on common Linux, macOS and Windows installs
+var fallbackFamilies = []string{
+	"Noto Sans",
+	"Noto Sans CJK SC",
+	"Noto Sans CJK JP",
+	"Noto Sans CJK KR",
+	"Source Han Sans",
+	"PingFang SC",
+	"Hiragino Sans",
+	"Apple SD Gothic Neo",
+	"Microsoft YaHei",
+	"Yu Gothic",
+	"Malgun Gothic",
+	"Arial Unicode MS",
+	"Noto Emoji",
+	"Noto Color Emoji",
+	"Apple Color Emoji",
+	"Segoe UI Emoji",
+	"Segoe UI Symbol",
+	"Segoe UI",
+	"Arial",
+	"DejaVu Sans",
+}
+
+// Info describes an installed font
+type Info struct {
+	Family  string `json:"family"`
+	Style   string `json:"style"`
+	Path    string `json:"path"`
+	Bundled bool   `json:"bundled,omitempty"`
+}
+
+// Name returns the family and style, e.g. "Open Sans Bold"
+func (i Info) Name() string {
+	if i.Style == "" || isRegular(i.Style) {
+		return i.Family
+	}
+	return i.Family + " " + i.Style
+}
+
+var (
+	listMu sync.Mutex
+	listed []Info
+)
+
+// List returns the installed fonts followed by the bundled fallback, sorted
+// by family and style. Results are cached for the life of the process.
+func List(ctx context.Context) ([]Info, error) {
+	listMu.Lock()
+	defer listMu.Unlock()
+	if listed != nil {
+		return listed, nil
+	}
+
+	fonts := systemFonts(ctx)
+	sort.SliceStable(fonts, func(i, j int) bool {
+		if !strings.EqualFold(fonts[i].Family, fonts[j].Family) {
+			return strings.ToLower(fonts[i].Family) < strings.ToLower(fonts[j].Family)
+		}
+		return styleRank(fonts[i].Style) < styleRank(fonts[j].Style)
+	})
+
+	path, err := BundledPath()
+	if err != nil {
+		return nil, err
+	}
+	listed = append(dedupe(fonts), Info{Family: BundledFamily, Style: "Book", Path: path, Bundled: true})
+	return listed, nil
+}
+
+// Resolve returns the file for a font given as a path, a family name
+// ("Open Sans") or a family and style ("Open Sans Bold Italic")
+func Resolve(ctx context.Context, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("font name is required")
+	}
+	if _, err := os.Stat(name); err == nil {
+		return name, nil
+	}
+
+	fonts, err := List(ctx)
+	if err != nil {
+		return "", err
+	}
+	want := normalize(name)
+	// Exact family and style first, then family alone (regular style first
+	// thanks to the sort), then the file name
+	for _, f := range fonts {
+		if normalize(f.Family+f.Style) == want || normalize(f.Name()) == want {
+			return f.Path, nil
+		}
+	}
+	for _, f := range fonts {
+		if normalize(f.Family) == want {
+			return f.Path, nil
+		}
+	}
+	for _, f := range fonts {
+		base := filepath.Base(f.Path)
+		if normalize(strings.TrimSuffix(base, filepath.Ext(base))) == want {
+			return f.Path, nil
+		}
+	}
+	return "", fmt.Errorf("font not found: %s (use list_fonts to see installed fonts)", name)
+}
+
+// ForText returns a font able to draw every character in text. The preferred
+// font is kept when it covers the text; otherwise the fallback families and
+// then the bundled font are tried. When nothing covers all of it, the
+// candidate missing the fewest characters is returned.
+func ForText(ctx context.Context, text, preferred string) (string, error) {
+	needed := drawnRunes(text)
+	if len(needed) == 0 {
+		return preferred, nil
+	}
+
+	var candidates []string
+	if preferred != "" {
+		candidates = append(candidates, preferred)
+	}
+	for _, family := range fallbackFamilies {
+		if path, err := Resolve(ctx, family); err == nil {
+			candidates = append(candidates, path)
+		}
+	}
+	bundled, err := BundledPath()
+	if err != nil {
+		return "", err
+	}
+	candidates = append(candidates, bundled)
+
+	best, bestMissing := preferred, -1
+	for _, path := range candidates {
+		m, err := LoadMetrics(path)
+		if err != nil {
+			continue
+		}
+		missing := 0
+		for _, r := range needed {
+			if !m.HasGlyph(r) {
+				missing++
+			}
+		}
+		if missing == 0 {
+			return path, nil
+		}
+		if bestMissing < 0 || missing < bestMissing {
+			best, bestMissing = path, missing
+		}
+	}
+	return best, nil
+}
+
+// NeedsFallback reports whether text has characters outside ASCII, which
+// FFmpeg's default font may not be able to draw
+func NeedsFallback(text string) bool {
+	for _, r := range text {
+		if r > unicode.MaxASCII {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	bundledOnce sync.Once
+	bundledPath string
+	bundledErr  error
+)
+
+// BundledPath writes the bundled font to the temp directory on first use and
+// returns its path
+func BundledPath() (string, error) {
+	bundledOnce.Do(func() {
+		dir := filepath.Join(os.TempDir(), ".mcp-video-fonts")
+		bundledPath = filepath.Join(dir, "DejaVuSans.ttf")
+		if info, err := os.Stat(bundledPath); err == nil && info.Size() == int64(len(bundledFont)) {
+			return
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			bundledErr = fmt.Errorf("failed to create font directory: %w", err)
+			return
+		}
+		if err := os.WriteFile(bundledPath, bundledFont, 0644); err != nil {
+			bundledErr = fmt.Errorf("failed to write bundled font: %w", err)
+		}
+	})
+	return bundledPath, bundledErr
+}
+
+// scanDirs lists font files under dirs, reading names from each file
+func scanDirs(dirs []string) []Info {
+	var fonts []Info
+	for _, dir := range dirs {
+		filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !isFontFile(path) {
+				return nil
+			}
+			if info, ok := readInfo(path); ok {
+				fonts = append(fonts, info)
+			}
+			return nil
+		})
+	}
+	return fonts
+}
+
+// readInfo reads the family and style of a font file
+func readInfo(path string) (Info, bool) {
+	family, style, err := readNames(path)
+	if err != nil || family == "" {
+		return Info{}, false
+	}
+	return Info{Family: family, Style: style, Path: path}, true
+}
+
+func isFontFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ttf", ".otf", ".ttc":
+		return true
+	}
+	return false
+}
+
+// drawnRunes returns the distinct visible characters in text
+func drawnRunes(text string) []rune {
+	seen := map[rune]bool{}
+	var runes []rune
+	for _, r := range text {
+		if unicode.IsSpace(r) || unicode.Is(unicode.Variation_Selector, r) || r == '\u200d' || seen[r] {
+			continue
+		}
+		seen[r] = true
+		runes = append(runes, r)
+	}
+	return runes
+}
+
+func dedupe(fonts []Info) []Info {
+	seen := map[string]bool{}
+	var out []Info
+	for _, f := range fonts {
+		key := f.Path + "\x00" + f.Family + "\x00" + f.Style
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// normalize lowercases a font name and drops spaces, hyphens and underscores
+func normalize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '_' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
+
+func isRegular(style string) bool {
+	switch strings.ToLower(style) {
+	case "regular", "book", "normal", "roman":
+		return true
+	}
+	return false
+}
+
+// styleRank sorts regular styles ahead of bold and italic variants
+func styleRank(style string) int {
+	if style == "" || isRegular(style) {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,47 @@
+package fonts
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestInfoName(t *testing.T) {
+	if got := (Info{Family: "Open Sans", Style: "Regular"}).Name(); got != "Open Sans" {
+		t.Errorf("Name() = %q, want Open Sans", got)
+	}
+	if got := (Info{Family: "Open Sans", Style: "Bold Italic"}).Name(); got != "Open Sans Bold Italic" {
+		t.Errorf("Name() = %q, want Open Sans Bold Italic", got)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if normalize("Open Sans-Bold") != normalize("opensans_bold") {
+		t.Error("Expected names to match ignoring case, spaces, hyphens and underscores")
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	path, err := BundledPath()
+	if err != nil {
+		t.Fatalf("BundledPath() error: %v", err)
+	}
+	got, err := Resolve(context.Background(), path)
+	if err != nil || got != path {
+		t.Errorf("Resolve(%q) = %q, %v", path, got, err)
+	}
+	if _, err := Resolve(context.Background(), "No Such Font Family"); err == nil {
+		t.Error("Expected error for unknown font")
+	}
+}
+
+func TestDrawnRunes(t *testing.T) {
+	got := drawnRunes("a b a❤️")
+	want := []rune{'a', 'b', '❤'}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("drawnRunes() = %q, want %q", got, want)
+	}
+	if NeedsFallback("plain ASCII") || !NeedsFallback("日本") {
+		t.Error("NeedsFallback() should only flag non-ASCII text")
+	}
+}
@@ -0,0 +1,353 @@
+package fonts
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Metrics holds the names and horizontal metrics of a TrueType or OpenType
+// font, enough to measure text before drawtext renders it
+type Metrics struct {
+	Family string
+	Style  string
+
+	unitsPerEm float64
+	advances   []uint16        // Advance width per glyph, in font units
+	glyphs     map[rune]uint16 // Character to glyph index
+}
+
+var (
+	fontCacheMu sync.Mutex
+	fontCache   = map[string]*Metrics{}
+)
+
+// LoadMetrics reads a .ttf, .otf or .ttc font (the first face of a
+// collection). Fonts are cached by path.
+func LoadMetrics(path string) (*Metrics, error) {
+	fontCacheMu.Lock()
+	defer fontCacheMu.Unlock()
+	if f, ok := fontCache[path]; ok {
+		return f, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font: %w", err)
+	}
+	f, err := parseFont(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font %s: %w", path, err)
+	}
+	fontCache[path] = f
+	return f, nil
+}
+
+// Width returns the advance width of text in pixels at the given font size
+func (f *Metrics) Width(text string, fontSize int) float64 {
+	units := 0.0
+	for _, r := range text {
+		glyph := f.glyphs[r] // Missing characters render as glyph 0 (.notdef)
+		units += float64(f.advance(glyph))
+	}
+	return units * float64(fontSize) / f.unitsPerEm
+}
+
+// HasGlyph reports whether the font has a glyph for r
+func (f *Metrics) HasGlyph(r rune) bool {
+	_, ok := f.glyphs[r]
+	return ok
+}
+
+func (f *Metrics) advance(glyph uint16) uint16 {
+	if len(f.advances) == 0 {
+		return 0
+	}
+	// Glyphs past the last metric share its advance
+	return f.advances[min(int(glyph), len(f.advances)-1)]
+}
+
+// fontData reads big-endian values with bounds checks
+type fontData []byte
+
+func (d fontData) u16(off int) (uint16, error) {
+	if off < 0 || off+2 > len(d) {
+		return 0, fmt.Errorf("unexpected end of font data")
+	}
+	return binary.BigEndian.Uint16(d[off:]), nil
+}
+
+func (d fontData) u32(off int) (uint32, error) {
+	if off < 0 || off+4 > len(d) {
+		return 0, fmt.Errorf("unexpected end of font data")
+	}
+	return binary.BigEndian.Uint32(d[off:]), nil
+}
+
+// parseFont reads the head, hhea, hmtx, cmap and name tables
+func parseFont(data []byte) (*Metrics, error) {
+	tables, err := parseTables(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "cmap"} {
+		if tables[tag] == nil {
+			return nil, fmt.Errorf("missing %s table", tag)
+		}
+	}
+
+	unitsPerEm, err := tables["head"].u16(18)
+	if err != nil {
+		return nil, err
+	}
+	if unitsPerEm == 0 {
+		return nil, fmt.Errorf("invalid unitsPerEm")
+	}
+	numMetrics, err := tables["hhea"].u16(34)
+	if err != nil {
+		return nil, err
+	}
+	hmtx := tables["hmtx"]
+	advances := make([]uint16, 0, numMetrics)
+	for i := 0; i < int(numMetrics); i++ {
+		adv, err := hmtx.u16(4 * i)
+		if err != nil {
+			return nil, err
+		}
+		advances = append(advances, adv)
+	}
+
+	glyphs, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Metrics{unitsPerEm: float64(unitsPerEm), advances: advances, glyphs: glyphs}
+	if name := tables["name"]; name != nil {
+		m.Family, m.Style = parseNames(name)
+	}
+	return m, nil
+}
+
+// parseCmap reads the best Unicode subtable: format 12 (full Unicode) when
+// present, otherwise format 4 (BMP)
+func parseCmap(cmap fontData) (map[rune]uint16, error) {
+	numTables, err := cmap.u16(2)
+	if err != nil {
+		return nil, err
+	}
+	best, bestFormat := -1, uint16(0)
+	for i := 0; i < int(numTables); i++ {
+		platform, _ := cmap.u16(4 + 8*i)
+		encoding, _ := cmap.u16(6 + 8*i)
+		off, err := cmap.u32(8 + 8*i)
+		if err != nil {
+			return nil, err
+		}
+		unicode := platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))
+		if !unicode {
+			continue
+		}
+		format, err := cmap.u16(int(off))
+		if err != nil {
+			return nil, err
+		}
+		if format == 12 || (format == 4 && bestFormat != 12) {
+			best, bestFormat = int(off), format
+		}
+	}
+
+	switch bestFormat {
+	case 12:
+		return parseCmap12(cmap[best:])
+	case 4:
+		return parseCmap4(cmap[best:])
+	default:
+		return nil, fmt.Errorf("no supported Unicode cmap subtable")
+	}
+}
+
+func parseCmap4(t fontData) (map[rune]uint16, error) {
+	segX2, err := t.u16(6)
+	if err != nil {
+		return nil, err
+	}
+	seg := int(segX2)
+	ends, starts, deltas, ranges := 14, 16+seg, 16+2*seg, 16+3*seg
+
+	glyphs := map[rune]uint16{}
+	for i := 0; i < seg/2; i++ {
+		end, err1 := t.u16(ends + 2*i)
+		start, err2 := t.u16(starts + 2*i)
+		delta, err3 := t.u16(deltas + 2*i)
+		rangeOffset, err4 := t.u16(ranges + 2*i)
+		if err := firstError(err1, err2, err3, err4); err != nil {
+			return nil, err
+		}
+		for c := int(start); c <= int(end) && c < 0xFFFF; c++ {
+			var glyph uint16
+			if rangeOffset == 0 {
+				glyph = uint16(c) + delta
+			} else {
+				g, err := t.u16(ranges + 2*i + int(rangeOffset) + 2*(c-int(start)))
+				if err != nil {
+					return nil, err
+				}
+				if g == 0 {
+					continue
+				}
+				glyph = g + delta
+			}
+			if glyph != 0 {
+				glyphs[rune(c)] = glyph
+			}
+		}
+	}
+	return glyphs, nil
+}
+
+func parseCmap12(t fontData) (map[rune]uint16, error) {
+	groups, err := t.u32(12)
+	if err != nil {
+		return nil, err
+	}
+	glyphs := map[rune]uint16{}
+	for i := 0; i < int(groups); i++ {
+		start, err1 := t.u32(16 + 12*i)
+		end, err2 := t.u32(20 + 12*i)
+		glyph, err3 := t.u32(24 + 12*i)
+		if err := firstError(err1, err2, err3); err != nil {
+			return nil, err
+		}
+		if end > utf8.MaxRune || end < start {
+			continue
+		}
+		for c := start; c <= end; c++ {
+			g := glyph + (c - start)
+			if g > math.MaxUint16 {
+				break
+			}
+			glyphs[rune(c)] = uint16(g)
+		}
+	}
+	return glyphs, nil
+}
+
+// readNames reads only the family and style of a font file, which is much
+// cheaper than loading its metrics
+func readNames(path string) (string, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	tables, err := parseTables(data)
+	if err != nil {
+		return "", "", err
+	}
+	if tables["name"] == nil {
+		return "", "", fmt.Errorf("missing name table")
+	}
+	family, style := parseNames(tables["name"])
+	return family, style, nil
+}
+
+// parseTables reads the table directory of the font, or of the first font in
+// a collection
+func parseTables(data []byte) (map[string]fontData, error) {
+	d := fontData(data)
+	base := 0
+	if len(d) >= 4 && string(d[:4]) == "ttcf" {
+		off, err := d.u32(12)
+		if err != nil {
+			return nil, err
+		}
+		base = int(off)
+	}
+
+	numTables, err := d.u16(base + 4)
+	if err != nil {
+		return nil, err
+	}
+	tables := map[string]fontData{}
+	for i := 0; i < int(numTables); i++ {
+		rec := base + 12 + 16*i
+		if rec+16 > len(d) {
+			return nil, fmt.Errorf("truncated table directory")
+		}
+		off, _ := d.u32(rec + 8)
+		length, _ := d.u32(rec + 12)
+		if int(off)+int(length) > len(d) {
+			return nil, fmt.Errorf("table %s out of range", d[rec:rec+4])
+		}
+		tables[string(d[rec:rec+4])] = d[off : off+length]
+	}
+	return tables, nil
+}
+
+// parseNames returns the family and style from the name table, preferring
+// the typographic names (IDs 16 and 17) over the legacy ones (1 and 2)
+func parseNames(name fontData) (string, string) {
+	count, err := name.u16(2)
+	if err != nil {
+		return "", ""
+	}
+	storage, _ := name.u16(4)
+	names := map[uint16]string{}
+	for i := 0; i < int(count); i++ {
+		rec := 6 + 12*i
+		platform, err1 := name.u16(rec)
+		language, err2 := name.u16(rec + 4)
+		id, err3 := name.u16(rec + 6)
+		length, err4 := name.u16(rec + 8)
+		offset, err5 := name.u16(rec + 10)
+		if firstError(err1, err2, err3, err4, err5) != nil {
+			break
+		}
+		start := int(storage) + int(offset)
+		if start+int(length) > len(name) {
+			continue
+		}
+		raw := name[start : start+int(length)]
+
+		var value string
+		switch {
+		case platform == 3 && language == 0x409, platform == 0:
+			units := make([]uint16, len(raw)/2)
+			for j := range units {
+				units[j] = binary.BigEndian.Uint16(raw[2*j:])
+			}
+			value = string(utf16.Decode(units))
+		case platform == 1 && language == 0:
+			if _, ok := names[id]; ok {
+				continue // Prefer Unicode names
+			}
+			value = string(raw)
+		default:
+			continue
+		}
+		names[id] = value
+	}
+
+	family, style := names[16], names[17]
+	if family == "" {
+		family = names[1]
+	}
+	if style == "" {
+		style = names[2]
+	}
+	return family, style
+}
+
+// firstError returns the first non-nil error
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
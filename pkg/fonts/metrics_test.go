@@ -0,0 +1,76 @@
+package fonts
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestParseFont(t *testing.T) {
+	f, err := parseFont(testFont())
+	if err != nil {
+		t.Fatalf("parseFont() error: %v", err)
+	}
+	if !f.HasGlyph('A') || f.HasGlyph('Z') {
+		t.Error("Expected a glyph for A only")
+	}
+	// A is 600 units, B is past the last metric so shares it, and unmapped C
+	// falls back to glyph 0 at 400; 1000 units per em
+	if got := f.Width("ABC", 50); got != (600+600+400)*50/1000.0 {
+		t.Errorf("Width() = %v", got)
+	}
+}
+
+// testFont builds a minimal font with glyphs for A and B (glyphs 1 and 2)
+func testFont() []byte {
+	u16 := func(b []byte, v ...uint16) []byte {
+		for _, x := range v {
+			b = binary.BigEndian.AppendUint16(b, x)
+		}
+		return b
+	}
+
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint16(head[18:], 1000)
+	hhea := make([]byte, 36)
+	binary.BigEndian.PutUint16(hhea[34:], 2)
+	hmtx := u16(nil, 400, 0, 600, 0)
+
+	// Format 4 subtable with segments A-B and the 0xFFFF terminator
+	sub := u16(nil, 4, 32, 0, 4, 4, 1, 0)
+	sub = u16(sub, 'B', 0xFFFF, 0, 'A', 0xFFFF)
+	sub = u16(sub, 0xFFC0, 1, 0, 0) // Delta -64 maps A to glyph 1
+	cmap := u16(nil, 0, 1, 3, 1)
+	cmap = binary.BigEndian.AppendUint32(cmap, 12)
+	cmap = append(cmap, sub...)
+
+	tables := []struct {
+		tag  string
+		data []byte
+	}{{"cmap", cmap}, {"head", head}, {"hhea", hhea}, {"hmtx", hmtx}}
+	font := u16([]byte{0, 1, 0, 0}, uint16(len(tables)), 0, 0, 0)
+	offset := 12 + 16*len(tables)
+	for _, tbl := range tables {
+		font = append(font, tbl.tag...)
+		font = binary.BigEndian.AppendUint32(font, 0)
+		font = binary.BigEndian.AppendUint32(font, uint32(offset))
+		font = binary.BigEndian.AppendUint32(font, uint32(len(tbl.data)))
+		offset += len(tbl.data)
+	}
+	for _, tbl := range tables {
+		font = append(font, tbl.data...)
+	}
+	return font
+}
+
+func TestParseNames(t *testing.T) {
+	m, err := parseFont(bundledFont)
+	if err != nil {
+		t.Fatalf("parseFont() error: %v", err)
+	}
+	if m.Family != BundledFamily || m.Style != "Book" {
+		t.Errorf("names = %q %q, want %q Book", m.Family, m.Style, BundledFamily)
+	}
+	if !m.HasGlyph('Ж') {
+		t.Error("Expected the bundled font to cover Cyrillic")
+	}
+}
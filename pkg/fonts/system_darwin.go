@@ -0,0 +1,17 @@
+package fonts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+)
+
+// systemFonts scans the system, local and user font directories
+func systemFonts(ctx context.Context) []Info {
+	home, _ := os.UserHomeDir()
+	return scanDirs([]string{
+		"/System/Library/Fonts",
+		"/Library/Fonts",
+		filepath.Join(home, "Library/Fonts"),
+	})
+}
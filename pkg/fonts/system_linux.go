@@ -0,0 +1,34 @@
+package fonts
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// systemFonts lists fonts known to fontconfig, falling back to scanning the
+// standard font directories when fc-list is not installed
+func systemFonts(ctx context.Context) []Info {
+	out, err := exec.CommandContext(ctx, "fc-list", "--format", "%{family[0]}\t%{style[0]}\t%{file}\n").Output()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		return scanDirs([]string{
+			"/usr/share/fonts",
+			"/usr/local/share/fonts",
+			filepath.Join(home, ".local/share/fonts"),
+			filepath.Join(home, ".fonts"),
+		})
+	}
+
+	var fonts []Info
+	for _, line := range strings.Split(string(out), "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) != 3 || parts[0] == "" || !isFontFile(parts[2]) {
+			continue
+		}
+		fonts = append(fonts, Info{Family: parts[0], Style: parts[1], Path: parts[2]})
+	}
+	return fonts
+}
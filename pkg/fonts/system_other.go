@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !windows
+
+package fonts
+
+import "context"
+
+// Font discovery is not implemented on this platform; only the bundled font
+// and explicit font paths are available.
+func systemFonts(ctx context.Context) []Info {
+	return nil
+}
@@ -0,0 +1,53 @@
+package fonts
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// fontKeys are the registry keys listing machine-wide and per-user fonts
+var fontKeys = []string{
+	`HKLM\SOFTWARE\Microsoft\Windows NT\CurrentVersion\Fonts`,
+	`HKCU\SOFTWARE\Microsoft\Windows NT\CurrentVersion\Fonts`,
+}
+
+// systemFonts reads the installed fonts from the registry, falling back to
+// scanning the font directories when it cannot be queried
+func systemFonts(ctx context.Context) []Info {
+	fontDir := filepath.Join(os.Getenv("WINDIR"), "Fonts")
+	userDir := filepath.Join(os.Getenv("LOCALAPPDATA"), "Microsoft", "Windows", "Fonts")
+
+	var fonts []Info
+	queried := false
+	for _, key := range fontKeys {
+		out, err := exec.CommandContext(ctx, "reg", "query", key).Output()
+		if err != nil {
+			continue
+		}
+		queried = true
+		for _, line := range strings.Split(string(out), "\n") {
+			// Value lines look like: "    Arial (TrueType)    REG_SZ    arial.ttf"
+			_, file, ok := strings.Cut(line, "REG_SZ")
+			if !ok {
+				continue
+			}
+			file = strings.TrimSpace(file)
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(fontDir, file)
+			}
+			if !isFontFile(file) {
+				continue
+			}
+			if info, ok := readInfo(file); ok {
+				fonts = append(fonts, info)
+			}
+		}
+	}
+	if !queried {
+		return scanDirs([]string{fontDir, userDir})
+	}
+	return fonts
+}
@@ -0,0 +1,592 @@
+// Package highlights scores a video second by second for short-form clips
+package highlights
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/llm"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/usage"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+)
+
+// segmentsPerRequest keeps each scoring request well inside the model's output limit
+const segmentsPerRequest = 80
+
+// visualSampleRate is how many frames per second are scored for motion
+const visualSampleRate = 4
+
+// Weights sets how much each signal counts toward a highlight's score.
+// Signals that are unavailable are left out and the rest rescaled.
+type Weights struct {
+	Audio  float64 `json:"audio"`
+	Speech float64 `json:"speech"`
+	Visual float64 `json:"visual"`
+}
+
+// DefaultWeights favors what is said and how loudly
+var DefaultWeights = Weights{Audio: 0.35, Speech: 0.4, Visual: 0.25}
+
+// Options contains parameters for finding highlights
+type Options struct {
+	Input          string
+	Language       string   // Transcription language hint (optional)
+	Keywords       []string // Words or phrases that make a moment more interesting
+	ClipLength     float64  // Target clip length in seconds (default: 30)
+	MinClipLength  float64  // Default: 15
+	MaxClipLength  float64  // Default: 60
+	MaxClips       int      // Default: 5
+	SkipTranscript bool     // Score audio and visuals only
+	SkipSentiment  bool     // Don't ask the language model to rate segments
+	Weights        *Weights // Default: DefaultWeights
+	Output         string   // Optional JSON file for the result
+}
+
+// Signals are a clip's average scores, each from 0 to 1
+type Signals struct {
+	Audio  float64 `json:"audio"`
+	Speech float64 `json:"speech"`
+	Visual float64 `json:"visual"`
+}
+
+// Highlight is a candidate clip with suggested in and out points
+type Highlight struct {
+	Rank     int      `json:"rank"`
+	Start    float64  `json:"start"`
+	End      float64  `json:"end"`
+	Score    float64  `json:"score"` // Weighted signal average, 0 to 1
+	Signals  Signals  `json:"signals"`
+	Keywords []string `json:"keywords,omitempty"` // Keywords spoken in the clip
+	Reason   string   `json:"reason"`
+	Text     string   `json:"text,omitempty"` // What is said in the clip
+}
+
+// Result lists ranked highlights
+type Result struct {
+	Input      string      `json:"input"`
+	Duration   float64     `json:"duration"`
+	Highlights []Highlight `json:"highlights"`
+	Sources    []string    `json:"sources"` // Signals used: audio, speech, visual
+	Warnings   []string    `json:"warnings,omitempty"`
+}
+
+// Finder scores media for highlights
+type Finder struct {
+	ffmpeg        *ffmpeg.Manager
+	videoOps      *video.Operations
+	transcriptOps *transcript.Operations
+	llm           *llm.Client
+}
+
+// NewFinder creates a highlight finder
+func NewFinder(cfg *config.Config, mgr *ffmpeg.Manager, videoOps *video.Operations, transcriptOps *transcript.Operations) *Finder {
+	return &Finder{
+		ffmpeg:        mgr,
+		videoOps:      videoOps,
+		transcriptOps: transcriptOps,
+		llm:           llm.NewClient(cfg),
+	}
+}
+
+// timeline holds per-second scores for each signal; nil when unavailable
+type timeline struct {
+	audio, speech, visual []float64
+}
+
+// spokenSegment is a transcript segment with its speech score
+type spokenSegment struct {
+	start, end float64
+	text       string
+	keywords   []string
+	interest   float64 // Language model rating, 0 to 1, or -1 when unrated
+	reason     string
+}
+
+// Find scores every second of the input and returns the best
+// non-overlapping clips, highest score first
+func (f *Finder) Find(ctx context.Context, opts Options) (*Result, error) {
+	if opts.ClipLength <= 0 {
+		opts.ClipLength = 30
+	}
+	if opts.MinClipLength <= 0 {
+		opts.MinClipLength = math.Min(15, opts.ClipLength)
+	}
+	if opts.MaxClipLength <= 0 {
+		opts.MaxClipLength = math.Max(60, opts.ClipLength)
+	}
+	if opts.MinClipLength > opts.MaxClipLength {
+		return nil, fmt.Errorf("minClipLength must not exceed maxClipLength")
+	}
+	opts.ClipLength = math.Max(opts.MinClipLength, math.Min(opts.ClipLength, opts.MaxClipLength))
+	if opts.MaxClips <= 0 {
+		opts.MaxClips = 5
+	}
+	weights := DefaultWeights
+	if opts.Weights != nil {
+		weights = *opts.Weights
+	}
+
+	info, err := f.videoOps.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media info: %w", err)
+	}
+	if info.Duration <= 0 {
+		return nil, fmt.Errorf("could not determine duration of %s", opts.Input)
+	}
+	bins := int(math.Ceil(info.Duration))
+	result := &Result{Input: opts.Input, Duration: info.Duration, Highlights: []Highlight{}}
+	var tl timeline
+
+	// Audio energy
+	if info.HasAudio {
+		tl.audio, err = f.audioEnergy(ctx, opts.Input, bins)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("audio energy unavailable: %v", err))
+		} else {
+			result.Sources = append(result.Sources, "audio")
+		}
+	}
+
+	// Speech interest, keywords and pace
+	var segments []spokenSegment
+	if info.HasAudio && !opts.SkipTranscript {
+		trans, err := f.transcriptOps.ExtractTranscript(ctx, opts.Input, opts.Language)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("transcript unavailable: %v", err))
+		} else {
+			segments = toSpokenSegments(trans, opts.Keywords)
+			if !opts.SkipSentiment && len(segments) > 0 {
+				if err := f.rateSegments(ctx, segments, opts.Keywords); err != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("speech ratings unavailable, using keywords and pace: %v", err))
+				}
+			}
+			tl.speech = speechScores(segments, bins)
+			result.Sources = append(result.Sources, "speech")
+		}
+	}
+
+	// Visual activity
+	if info.Width > 0 {
+		tl.visual, err = f.visualActivity(ctx, opts.Input, bins)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("visual activity unavailable: %v", err))
+		} else {
+			result.Sources = append(result.Sources, "visual")
+		}
+	}
+
+	if tl.audio == nil && tl.speech == nil && tl.visual == nil {
+		return nil, fmt.Errorf("no signals could be measured for %s", opts.Input)
+	}
+
+	scores := combine(tl, weights, bins)
+	// Inputs shorter than a clip yield one clip of the whole input
+	windows := pickWindows(scores, min(int(math.Round(opts.ClipLength)), bins), opts.MaxClips)
+	for _, w := range windows {
+		start, end := snapToSpeech(float64(w[0]), float64(w[1]), segments, opts.MinClipLength, opts.MaxClipLength, info.Duration)
+		result.Highlights = append(result.Highlights, describe(start, end, tl, weights, segments))
+	}
+	sort.SliceStable(result.Highlights, func(i, j int) bool { return result.Highlights[i].Score > result.Highlights[j].Score })
+	for i := range result.Highlights {
+		result.Highlights[i].Rank = i + 1
+	}
+
+	if opts.Output != "" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal highlights: %w", err)
+		}
+		if err := os.WriteFile(opts.Output, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write highlights: %w", err)
+		}
+	}
+	return result, nil
+}
+
+var (
+	momentaryPattern = regexp.MustCompile(`t:\s*([\d.]+)\s+.*?\bM:\s*(-?[\d.]+|-inf|nan)`)
+	metadataPtsTime  = regexp.MustCompile(`pts_time:([\d.]+)`)
+	sceneScore       = regexp.MustCompile(`lavfi\.scene_score=([\d.]+)`)
+)
+
+// audioEnergy returns the peak momentary loudness of each second, scaled
+// between the recording's quiet and loud levels
+func (f *Finder) audioEnergy(ctx context.Context, input string, bins int) ([]float64, error) {
+	output, err := f.ffmpeg.ExecuteWithOutput(ctx,
+		"-hide_banner",
+		"-nostats",
+		"-i", input,
+		"-vn",
+		"-af", "ebur128=framelog=verbose",
+		"-f", "null", "-",
+	)
+	if err != nil {
+		return nil, err
+	}
+	return parseLoudness(output, bins), nil
+}
+
+// parseLoudness reads ebur128 frame log lines into per-second scores
+func parseLoudness(output string, bins int) []float64 {
+	levels := make([]float64, bins)
+	for i := range levels {
+		levels[i] = math.Inf(-1)
+	}
+	for _, line := range strings.Split(output, "\n") {
+		m := momentaryPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		t, _ := strconv.ParseFloat(m[1], 64)
+		level, err := strconv.ParseFloat(m[2], 64)
+		if err != nil || math.IsNaN(level) {
+			continue
+		}
+		// The momentary window is 400ms, so its level describes slightly earlier audio
+		if i := int(math.Max(0, t-0.2)); i < bins && level > levels[i] {
+			levels[i] = level
+		}
+	}
+	return scaleBetweenPercentiles(levels, 0.2, 0.95)
+}
+
+// visualActivity returns the average scene-change score of each second
+func (f *Finder) visualActivity(ctx context.Context, input string, bins int) ([]float64, error) {
+	output, err := f.ffmpeg.ExecuteWithOutput(ctx,
+		"-hide_banner",
+		"-nostats",
+		"-i", input,
+		"-an",
+		"-vf", fmt.Sprintf("fps=%d,select='gte(scene,0)',metadata=print:key=lavfi.scene_score", visualSampleRate),
+		"-f", "null", "-",
+	)
+	if err != nil {
+		return nil, err
+	}
+	return parseSceneScores(output, bins), nil
+}
+
+// parseSceneScores pairs metadata frame times with their scene scores
+func parseSceneScores(output string, bins int) []float64 {
+	sums := make([]float64, bins)
+	counts := make([]int, bins)
+	current := -1
+	for _, line := range strings.Split(output, "\n") {
+		if m := metadataPtsTime.FindStringSubmatch(line); m != nil {
+			t, _ := strconv.ParseFloat(m[1], 64)
+			current = int(t)
+			continue
+		}
+		if m := sceneScore.FindStringSubmatch(line); m != nil && current >= 0 && current < bins {
+			v, _ := strconv.ParseFloat(m[1], 64)
+			sums[current] += v
+			counts[current]++
+		}
+	}
+	for i := range sums {
+		if counts[i] > 0 {
+			sums[i] /= float64(counts[i])
+		}
+	}
+	return scaleBetweenPercentiles(sums, 0, 0.95)
+}
+
+// scaleBetweenPercentiles maps values so the low percentile is 0 and the
+// high percentile is 1, clamping outside. -Inf counts as the minimum.
+func scaleBetweenPercentiles(values []float64, low, high float64) []float64 {
+	var finite []float64
+	for _, v := range values {
+		if !math.IsInf(v, 0) {
+			finite = append(finite, v)
+		}
+	}
+	scaled := make([]float64, len(values))
+	if len(finite) == 0 {
+		return scaled
+	}
+	sort.Float64s(finite)
+	lo := finite[int(math.Round(low*float64(len(finite)-1)))]
+	hi := finite[int(math.Round(high*float64(len(finite)-1)))]
+	for i, v := range values {
+		switch {
+		case math.IsInf(v, -1) || v <= lo:
+			scaled[i] = 0
+		case hi <= lo || v >= hi:
+			scaled[i] = 1
+		default:
+			scaled[i] = (v - lo) / (hi - lo)
+		}
+	}
+	return scaled
+}
+
+// toSpokenSegments finds keywords in each transcript segment
+func toSpokenSegments(trans *transcript.Transcript, keywords []string) []spokenSegment {
+	var segments []spokenSegment
+	for _, seg := range trans.Segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" || seg.End <= seg.Start {
+			continue
+		}
+		lower := strings.ToLower(text)
+		var found []string
+		for _, keyword := range keywords {
+			if k := strings.ToLower(strings.TrimSpace(keyword)); k != "" && strings.Contains(lower, k) {
+				found = append(found, keyword)
+			}
+		}
+		segments = append(segments, spokenSegment{start: seg.Start, end: seg.End, text: text, keywords: found, interest: -1})
+	}
+	return segments
+}
+
+// rateSegments asks the language model how well each segment would work as
+// a standalone short clip
+func (f *Finder) rateSegments(ctx context.Context, segments []spokenSegment, keywords []string) error {
+	for start := 0; start < len(segments); start += segmentsPerRequest {
+		batch := segments[start:min(start+segmentsPerRequest, len(segments))]
+		content, err := f.llm.Complete(ctx, usage.FeatureHighlights, buildRatingPrompt(batch, keywords))
+		if err != nil {
+			return err
+		}
+		ratings, err := parseRatings(content, len(batch))
+		if err != nil {
+			return err
+		}
+		for i, r := range ratings {
+			batch[i].interest = math.Max(0, math.Min(10, r.Score)) / 10
+			batch[i].reason = strings.TrimSpace(r.Reason)
+		}
+	}
+	return nil
+}
+
+type rating struct {
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+func buildRatingPrompt(segments []spokenSegment, keywords []string) string {
+	var lines []string
+	for i, seg := range segments {
+		lines = append(lines, fmt.Sprintf("[%d] %s", i, seg.text))
+	}
+	focus := ""
+	if len(keywords) > 0 {
+		focus = "\nThe viewer is especially interested in: " + strings.Join(keywords, ", ") + "\n"
+	}
+	return fmt.Sprintf(`Rate each numbered transcript segment from 0 to 10 for how well it would work in a short-form highlight clip: strong emotion (excitement, humor, surprise, frustration), a bold claim, a punchline, a memorable quote or a key insight score high; filler, logistics and small talk score low.
+%s
+Return only a JSON object of the form {"ratings": [{"score": 0-10, "reason": "a few words"}]} with exactly %d entries, one per segment, in order.
+
+Segments:
+%s`, focus, len(segments), strings.Join(lines, "\n"))
+}
+
+// parseRatings extracts the ratings array from a model response,
+// tolerating surrounding prose or code fences
+func parseRatings(content string, want int) ([]rating, error) {
+	var resp struct {
+		Ratings []rating `json:"ratings"`
+	}
+	if err := llm.DecodeJSON(content, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse rating response: %w", err)
+	}
+	if len(resp.Ratings) != want {
+		return nil, fmt.Errorf("expected %d ratings, got %d", want, len(resp.Ratings))
+	}
+	return resp.Ratings, nil
+}
+
+// speechScores spreads each segment's score over the seconds it covers.
+// A segment scores from its rating, keyword hits and speaking pace.
+func speechScores(segments []spokenSegment, bins int) []float64 {
+	scores := make([]float64, bins)
+	for _, seg := range segments {
+		// Around 3.5 words per second is fast, animated speech
+		pace := math.Min(1, float64(len(strings.Fields(seg.text)))/(seg.end-seg.start)/3.5)
+		keywords := 0.0
+		if len(seg.keywords) > 0 {
+			keywords = 1
+		}
+		score := 0.6*keywords + 0.4*pace
+		if seg.interest >= 0 {
+			score = 0.6*seg.interest + 0.25*keywords + 0.15*pace
+		}
+		for i := int(seg.start); i < bins && float64(i) < seg.end; i++ {
+			scores[i] = math.Max(scores[i], score)
+		}
+	}
+	return scores
+}
+
+// combine weights the available signals for each second
+func combine(tl timeline, w Weights, bins int) []float64 {
+	scores := make([]float64, bins)
+	for i := range scores {
+		scores[i] = weighted(at(tl.audio, i), at(tl.speech, i), at(tl.visual, i), w)
+	}
+	return scores
+}
+
+// at returns the score at i, or -1 when the signal is unavailable
+func at(values []float64, i int) float64 {
+	if values == nil {
+		return -1
+	}
+	return values[i]
+}
+
+// weighted averages the available (non-negative) signals
+func weighted(audio, speech, visual float64, w Weights) float64 {
+	total, weight := 0.0, 0.0
+	for _, s := range []struct{ value, weight float64 }{{audio, w.Audio}, {speech, w.Speech}, {visual, w.Visual}} {
+		if s.value >= 0 && s.weight > 0 {
+			total += s.value * s.weight
+			weight += s.weight
+		}
+	}
+	if weight == 0 {
+		return 0
+	}
+	return total / weight
+}
+
+// pickWindows returns up to maxClips non-overlapping [start, end) windows
+// of length seconds with the highest average scores
+func pickWindows(scores []float64, length, maxClips int) [][2]int {
+	if length <= 0 || length > len(scores) {
+		return nil
+	}
+	prefix := make([]float64, len(scores)+1)
+	for i, s := range scores {
+		prefix[i+1] = prefix[i] + s
+	}
+	starts := make([]int, len(scores)-length+1)
+	for i := range starts {
+		starts[i] = i
+	}
+	mean := func(start int) float64 { return (prefix[start+length] - prefix[start]) / float64(length) }
+	sort.SliceStable(starts, func(a, b int) bool { return mean(starts[a]) > mean(starts[b]) })
+
+	var picked [][2]int
+	for _, start := range starts {
+		if len(picked) >= maxClips {
+			break
+		}
+		overlaps := false
+		for _, p := range picked {
+			if start < p[1] && start+length > p[0] {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			picked = append(picked, [2]int{start, start + length})
+		}
+	}
+	return picked
+}
+
+// snapToSpeech widens a window to whole transcript segments so clips don't
+// start or end mid-sentence, within the clip length limits
+func snapToSpeech(start, end float64, segments []spokenSegment, minLength, maxLength, duration float64) (float64, float64) {
+	snappedStart, snappedEnd := start, end
+	for _, seg := range segments {
+		if seg.start < start && seg.end > start {
+			snappedStart = seg.start
+		}
+		if seg.start < end && seg.end > end {
+			snappedEnd = seg.end
+		}
+	}
+	if snappedEnd-snappedStart <= maxLength {
+		start, end = snappedStart, snappedEnd
+	} else if snappedEnd-start <= maxLength {
+		end = snappedEnd
+	} else if end-snappedStart <= maxLength {
+		start = snappedStart
+	}
+	if end-start < minLength {
+		end = start + minLength
+	}
+	if end > duration {
+		start, end = math.Max(0, start-(end-duration)), duration
+	}
+	return start, end
+}
+
+// describe scores a clip and explains what makes it stand out
+func describe(start, end float64, tl timeline, w Weights, segments []spokenSegment) Highlight {
+	h := Highlight{Start: start, End: end}
+	mean := func(values []float64) float64 {
+		if values == nil {
+			return -1
+		}
+		sum, n := 0.0, 0
+		for i := int(start); i < len(values) && float64(i) < end; i++ {
+			sum += values[i]
+			n++
+		}
+		if n == 0 {
+			return 0
+		}
+		return sum / float64(n)
+	}
+	audio, speech, visual := mean(tl.audio), mean(tl.speech), mean(tl.visual)
+	h.Score = round2(weighted(audio, speech, visual, w))
+	h.Signals = Signals{Audio: round2(math.Max(0, audio)), Speech: round2(math.Max(0, speech)), Visual: round2(math.Max(0, visual))}
+
+	var texts, reasons []string
+	best := -1.0
+	bestReason := ""
+	seen := map[string]bool{}
+	for _, seg := range segments {
+		if seg.end <= start || seg.start >= end {
+			continue
+		}
+		texts = append(texts, seg.text)
+		for _, k := range seg.keywords {
+			if !seen[k] {
+				seen[k] = true
+				h.Keywords = append(h.Keywords, k)
+			}
+		}
+		if seg.interest > best && seg.reason != "" {
+			best, bestReason = seg.interest, seg.reason
+		}
+	}
+	h.Text = strings.Join(texts, " ")
+
+	if bestReason != "" {
+		reasons = append(reasons, bestReason)
+	}
+	if len(h.Keywords) > 0 {
+		reasons = append(reasons, "mentions "+strings.Join(h.Keywords, ", "))
+	}
+	if audio >= 0.7 {
+		reasons = append(reasons, "high audio energy")
+	}
+	if visual >= 0.5 {
+		reasons = append(reasons, "lots of visual activity")
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, "highest combined score in this part of the video")
+	}
+	h.Reason = strings.Join(reasons, "; ")
+	return h
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
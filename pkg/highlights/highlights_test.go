@@ -0,0 +1,126 @@
+package highlights
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+)
+
+func TestParseLoudness(t *testing.T) {
+	output := `[Parsed_ebur128_0 @ 0x1] t: 0.1      TARGET:-23 LUFS    M:-120.7 S:-120.7     I: -70.0 LUFS       LRA:   0.0 LU
+[Parsed_ebur128_0 @ 0x1] t: 0.6      TARGET:-23 LUFS    M: -30.0 S:-120.7     I: -30.0 LUFS       LRA:   0.0 LU
+[Parsed_ebur128_0 @ 0x1] t: 1.5      TARGET:-23 LUFS    M: -20.0 S: -25.0     I: -24.0 LUFS       LRA:   0.0 LU
+[Parsed_ebur128_0 @ 0x1] t: 2.5      TARGET:-23 LUFS    M: -10.0 S: -15.0     I: -18.0 LUFS       LRA:   0.0 LU
+[Parsed_ebur128_0 @ 0x1] t: 3.5      TARGET:-23 LUFS    M:  -inf S:  -inf     I: -18.0 LUFS       LRA:   0.0 LU`
+
+	got := parseLoudness(output, 4)
+	if got[0] != 0 || got[3] != 0 || got[2] != 1 || got[1] <= 0 || got[1] >= 1 {
+		t.Errorf("parseLoudness() = %v, want quiet ends and the loudest second at 1", got)
+	}
+}
+
+func TestParseSceneScores(t *testing.T) {
+	output := `[Parsed_metadata_2 @ 0x1] frame:0    pts:0       pts_time:0
+[Parsed_metadata_2 @ 0x1] lavfi.scene_score=0.010000
+[Parsed_metadata_2 @ 0x1] frame:1    pts:1       pts_time:0.25
+[Parsed_metadata_2 @ 0x1] lavfi.scene_score=0.030000
+[Parsed_metadata_2 @ 0x1] frame:4    pts:4       pts_time:1
+[Parsed_metadata_2 @ 0x1] lavfi.scene_score=0.400000
+[Parsed_metadata_2 @ 0x1] frame:8    pts:8       pts_time:2
+[Parsed_metadata_2 @ 0x1] lavfi.scene_score=0.000000`
+
+	got := parseSceneScores(output, 3)
+	if got[1] != 1 || got[2] != 0 || got[0] <= 0 || got[0] >= 1 {
+		t.Errorf("parseSceneScores() = %v, want the cut second at 1", got)
+	}
+}
+
+func TestSpeechScores(t *testing.T) {
+	trans := &transcript.Transcript{Segments: []transcript.Segment{
+		{Start: 0, End: 2, Text: "um so"},
+		{Start: 2, End: 4, Text: "Our new Pricing plan launches today for everyone"},
+		{Start: 4, End: 5, Text: " "},
+	}}
+	segments := toSpokenSegments(trans, []string{"pricing", "launch"})
+	if len(segments) != 2 || !reflect.DeepEqual(segments[1].keywords, []string{"pricing", "launch"}) {
+		t.Fatalf("toSpokenSegments() = %+v", segments)
+	}
+
+	scores := speechScores(segments, 5)
+	if scores[2] <= scores[0] || scores[4] != 0 {
+		t.Errorf("Expected the keyword segment to outscore filler, got %v", scores)
+	}
+
+	segments[0].interest = 1
+	rated := speechScores(segments, 5)
+	if rated[0] <= scores[0] {
+		t.Errorf("Expected a high rating to raise the score, got %v", rated)
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	w := Weights{Audio: 1, Speech: 2, Visual: 1}
+	if got := weighted(1, 0.5, 0, w); got != 0.5 {
+		t.Errorf("weighted() = %v, want 0.5", got)
+	}
+	// Unavailable signals are left out
+	if got := weighted(-1, 0.5, -1, w); got != 0.5 {
+		t.Errorf("weighted() without audio and visual = %v, want 0.5", got)
+	}
+}
+
+func TestPickWindows(t *testing.T) {
+	scores := []float64{0, 0, 1, 1, 0, 0, 0, 0.9, 0.9, 0}
+	got := pickWindows(scores, 2, 3)
+	want := [][2]int{{2, 4}, {7, 9}, {0, 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pickWindows() = %v, want %v", got, want)
+	}
+	if pickWindows(scores, 20, 3) != nil {
+		t.Error("Expected no windows longer than the input")
+	}
+}
+
+func TestSnapToSpeech(t *testing.T) {
+	segments := []spokenSegment{{start: 8, end: 13}, {start: 13, end: 29}, {start: 29, end: 44}}
+
+	start, end := snapToSpeech(10, 40, segments, 15, 60, 100)
+	if start != 8 || end != 44 {
+		t.Errorf("snapToSpeech() = %v-%v, want 8-44", start, end)
+	}
+	// Widening both sides would exceed the maximum, so only the end moves
+	start, end = snapToSpeech(10, 40, segments, 15, 34, 100)
+	if start != 10 || end != 44 {
+		t.Errorf("snapToSpeech() = %v-%v, want 10-44", start, end)
+	}
+	start, end = snapToSpeech(90, 95, nil, 15, 60, 100)
+	if start != 85 || end != 100 {
+		t.Errorf("snapToSpeech() = %v-%v, want 85-100 at the end of the input", start, end)
+	}
+}
+
+func TestParseRatings(t *testing.T) {
+	ratings, err := parseRatings("```json\n{\"ratings\": [{\"score\": 8, \"reason\": \"bold claim\"}, {\"score\": 2}]}\n```", 2)
+	if err != nil || len(ratings) != 2 || ratings[0].Score != 8 || ratings[0].Reason != "bold claim" {
+		t.Errorf("parseRatings() = %+v, %v", ratings, err)
+	}
+	if _, err := parseRatings(`{"ratings": [{"score": 8}]}`, 2); err == nil || !strings.Contains(err.Error(), "expected 2") {
+		t.Errorf("Expected a count mismatch error, got %v", err)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tl := timeline{audio: []float64{0.9, 0.9, 0.8, 0.1}, visual: []float64{0, 0, 0, 0}}
+	segments := []spokenSegment{{start: 0, end: 3, text: "This changes everything.", keywords: []string{"launch"}, interest: 0.9, reason: "bold claim"}}
+
+	h := describe(0, 3, tl, DefaultWeights, segments)
+	if h.Signals.Speech != 0 || h.Signals.Audio != 0.87 || math.Abs(h.Score-0.51) > 0.01 {
+		t.Errorf("describe() = %+v", h)
+	}
+	if h.Reason != "bold claim; mentions launch; high audio energy" || h.Text != "This changes everything." {
+		t.Errorf("describe() reason = %q, text = %q", h.Reason, h.Text)
+	}
+}
@@ -0,0 +1,110 @@
+package imagegen
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// B-roll looks
+const (
+	LookPhoto        = "photo"        // Natural documentary photography (default)
+	LookCinematic    = "cinematic"    // Film still with dramatic light
+	LookIllustration = "illustration" // Clean digital illustration
+	LookNone         = "none"         // The prompt as given
+)
+
+// BrollLooks lists the looks GenerateBroll accepts
+var BrollLooks = []string{LookPhoto, LookCinematic, LookIllustration, LookNone}
+
+// BrollAspects maps b-roll aspect ratios to image sizes and the video
+// resolution they animate to
+var BrollAspects = map[string]struct {
+	Size          string
+	Width, Height int
+}{
+	"16:9": {"landscape", 1920, 1080},
+	"9:16": {"portrait", 1080, 1920},
+	"1:1":  {"square", 1080, 1080},
+}
+
+// brollLooks holds each look's prompt suffix and the style it maps to for
+// OpenAI (vivid or natural) and Stability (style preset)
+var brollLooks = map[string]struct {
+	suffix, openai, stability string
+}{
+	LookPhoto:        {"realistic documentary photograph, natural light, shallow depth of field", "natural", "photographic"},
+	LookCinematic:    {"cinematic film still, dramatic lighting, anamorphic, color graded", "vivid", "cinematic"},
+	LookIllustration: {"clean modern digital illustration, soft colors", "vivid", "digital-art"},
+	LookNone:         {},
+}
+
+// BrollOptions contains options for generating a b-roll still
+type BrollOptions struct {
+	Prompt   string
+	Look     string // photo (default), cinematic, illustration or none
+	Aspect   string // 16:9 (default), 9:16 or 1:1
+	Output   string // Explicit output path (optional, defaults to the asset library)
+	Quality  string
+	Provider string // openai or stability (default: the configured imageProvider)
+}
+
+// GenerateBroll creates a still for cutaway footage: a wide shot of the
+// subject in the chosen look, without text, with room around the subject so
+// it survives a Ken Burns zoom and pan
+func (g *Generator) GenerateBroll(ctx context.Context, opts BrollOptions) (*Asset, error) {
+	if opts.Aspect == "" {
+		opts.Aspect = "16:9"
+	}
+	aspect, ok := BrollAspects[opts.Aspect]
+	if !ok {
+		return nil, fmt.Errorf("unsupported aspect ratio: %s (use 16:9, 9:16 or 1:1)", opts.Aspect)
+	}
+	prompt, style, err := brollPrompt(opts.Prompt, opts.Look, opts.Provider, g.config.ImageProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := g.Generate(ctx, GenerateOptions{
+		Prompt:   prompt,
+		Output:   opts.Output,
+		Name:     "broll-" + slugify(opts.Prompt),
+		Size:     aspect.Size,
+		Quality:  opts.Quality,
+		Style:    style,
+		Provider: opts.Provider,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &assets[0], nil
+}
+
+// brollPrompt dresses the subject in the look and returns the provider's
+// style for it
+func brollPrompt(subject, look, provider, configured string) (string, string, error) {
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return "", "", fmt.Errorf("prompt is required")
+	}
+	if look == "" {
+		look = LookPhoto
+	}
+	l, ok := brollLooks[look]
+	if !ok {
+		return "", "", fmt.Errorf("unknown look: %s (use photo, cinematic, illustration or none)", look)
+	}
+	if look == LookNone {
+		return subject, "", nil
+	}
+
+	if provider == "" {
+		provider = configured
+	}
+	style := l.openai
+	if strings.EqualFold(provider, "stability") {
+		style = l.stability
+	}
+	prompt := fmt.Sprintf("%s. Wide establishing shot with space around the subject, %s. No text, captions, logos or watermarks.", strings.TrimRight(subject, ". "), l.suffix)
+	return prompt, style, nil
+}
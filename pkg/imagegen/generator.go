@@ -0,0 +1,320 @@
+package imagegen
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/apiclient"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Request describes an image to generate
+type Request struct {
+	Prompt  string
+	Size    string // Explicit size (e.g. "1024x1024") or aspect: square, landscape, portrait
+	Quality string // Provider-specific quality (e.g. standard, hd, high)
+	Style   string // Provider-specific style (e.g. vivid, natural)
+}
+
+// Provider generates images from text prompts
+type Provider interface {
+	Name() string
+	Model() string
+	Generate(ctx context.Context, req Request) ([]byte, error)
+}
+
+// OpenAIProvider generates images with the OpenAI Images API or any
+// OpenAI-compatible endpoint
+type OpenAIProvider struct {
+	client *openai.Client
+	model  string
+}
+
+// NewOpenAIProvider creates a provider for OpenAI or an OpenAI-compatible base URL
+func NewOpenAIProvider(apiKey, baseURL, model string) *OpenAIProvider {
+	clientConfig := apiclient.OpenAIConfig(apiKey, apiclient.ProviderOpenAI)
+	if baseURL != "" {
+		clientConfig.BaseURL = baseURL
+	}
+	if model == "" {
+		model = openai.CreateImageModelDallE3
+	}
+	return &OpenAIProvider{
+		client: openai.NewClientWithConfig(clientConfig),
+		model:  model,
+	}
+}
+
+// Name returns the provider name
+func (p *OpenAIProvider) Name() string {
+	return "openai"
+}
+
+// Model returns the model used for generation
+func (p *OpenAIProvider) Model() string {
+	return p.model
+}
+
+// Generate creates a single image and returns its encoded bytes
+func (p *OpenAIProvider) Generate(ctx context.Context, req Request) ([]byte, error) {
+	imageReq := openai.ImageRequest{
+		Prompt:  req.Prompt,
+		Model:   p.model,
+		N:       1,
+		Size:    resolveSize(p.model, req.Size),
+		Quality: req.Quality,
+		Style:   req.Style,
+	}
+	// gpt-image models always return base64 and reject response_format
+	if !strings.HasPrefix(p.model, "gpt-image") {
+		imageReq.ResponseFormat = openai.CreateImageResponseFormatB64JSON
+	}
+
+	resp, err := p.client.CreateImage(ctx, imageReq)
+	if err != nil {
+		return nil, fmt.Errorf("image generation failed: %w", err)
+	}
+	if len(resp.Data) == 0 {
+		return nil, fmt.Errorf("image generation returned no images")
+	}
+
+	if resp.Data[0].B64JSON != "" {
+		data, err := base64.StdEncoding.DecodeString(resp.Data[0].B64JSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode image: %w", err)
+		}
+		return data, nil
+	}
+
+	if resp.Data[0].URL != "" {
+		return downloadImage(ctx, resp.Data[0].URL)
+	}
+
+	return nil, fmt.Errorf("image generation returned an empty image")
+}
+
+// GenerateOptions contains options for generating images into the asset library
+type GenerateOptions struct {
+	Prompt   string
+	Output   string // Explicit output path (optional, defaults to the asset library)
+	Name     string // Base file name inside the asset library (optional)
+	Size     string
+	Quality  string
+	Style    string
+	Count    int    // Number of images to generate (default: 1)
+	Provider string // openai or stability (default: the configured imageProvider)
+}
+
+// Asset describes a generated image stored on disk
+type Asset struct {
+	Path     string    `json:"path"`
+	Prompt   string    `json:"prompt"`
+	Provider string    `json:"provider"`
+	Model    string    `json:"model"`
+	Size     string    `json:"size,omitempty"`
+	Created  time.Time `json:"created"`
+}
+
+// Generator writes generated images into the asset library
+type Generator struct {
+	config *config.Config
+}
+
+// NewGenerator creates an image generator. The provider and asset directory
+// are resolved from config on each call so set_config changes apply immediately.
+func NewGenerator(cfg *config.Config) *Generator {
+	return &Generator{config: cfg}
+}
+
+// ImagesDir returns the asset library directory for generated images
+func (g *Generator) ImagesDir() string {
+	assetDir := g.config.AssetPath()
+	if assetDir == "" {
+		cwd, _ := os.Getwd()
+		assetDir = filepath.Join(cwd, ".mcp-video-assets")
+	}
+	return filepath.Join(assetDir, "images")
+}
+
+// provider returns the named image provider, or the configured one. The
+// configured model and base URL only apply to the configured provider.
+func (g *Generator) provider(name string) (Provider, error) {
+	configured := strings.ToLower(g.config.ImageProvider)
+	if configured == "" {
+		configured = "openai"
+	}
+	name = strings.ToLower(name)
+	if name == "" {
+		name = configured
+	}
+	var model, baseURL string
+	if name == configured {
+		model, baseURL = g.config.ImageModel, g.config.ImageAPIBaseURL
+	}
+
+	switch name {
+	case "openai":
+		if g.config.OpenAIKey == "" && baseURL == "" {
+			return nil, fmt.Errorf("image generation not configured. Set an OpenAI API key or imageApiBaseUrl in config")
+		}
+		return NewOpenAIProvider(g.config.OpenAIKey, baseURL, model), nil
+	case "stability":
+		if g.config.StabilityAPIKey == "" {
+			return nil, fmt.Errorf("no Stability AI key configured. Set STABILITY_API_KEY or stabilityKey in config")
+		}
+		return NewStabilityProvider(g.config.StabilityAPIKey, baseURL, model), nil
+	default:
+		return nil, fmt.Errorf("unsupported image provider: %s (use openai or stability)", name)
+	}
+}
+
+// Generate creates one or more images and stores them with metadata sidecars
+func (g *Generator) Generate(ctx context.Context, opts GenerateOptions) ([]Asset, error) {
+	provider, err := g.provider(opts.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(opts.Prompt) == "" {
+		return nil, fmt.Errorf("prompt is required")
+	}
+
+	count := opts.Count
+	if count <= 0 {
+		count = 1
+	}
+	if count > 1 && opts.Output != "" {
+		return nil, fmt.Errorf("output path can only be used when generating a single image")
+	}
+
+	var assets []Asset
+	for i := 0; i < count; i++ {
+		data, err := provider.Generate(ctx, Request{
+			Prompt:  opts.Prompt,
+			Size:    opts.Size,
+			Quality: opts.Quality,
+			Style:   opts.Style,
+		})
+		if err != nil {
+			return assets, err
+		}
+
+		path := opts.Output
+		if path == "" {
+			if err := os.MkdirAll(g.ImagesDir(), 0755); err != nil {
+				return assets, fmt.Errorf("failed to create asset directory: %w", err)
+			}
+			name := opts.Name
+			if name == "" {
+				name = slugify(opts.Prompt)
+			}
+			path = filepath.Join(g.ImagesDir(), fmt.Sprintf("%s-%s-%d%s", name, time.Now().Format("20060102-150405"), i+1, detectExtension(data)))
+		} else if dir := filepath.Dir(path); dir != "" {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return assets, fmt.Errorf("failed to create output directory: %w", err)
+			}
+		}
+
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return assets, fmt.Errorf("failed to write image: %w", err)
+		}
+
+		asset := Asset{
+			Path:     path,
+			Prompt:   opts.Prompt,
+			Provider: provider.Name(),
+			Model:    provider.Model(),
+			Size:     opts.Size,
+			Created:  time.Now(),
+		}
+
+		// Sidecar metadata keeps the prompt alongside the asset for reuse
+		if meta, err := json.MarshalIndent(asset, "", "  "); err == nil {
+			_ = os.WriteFile(path+".json", meta, 0644)
+		}
+
+		assets = append(assets, asset)
+	}
+
+	return assets, nil
+}
+
+// resolveSize maps aspect names to sizes supported by the model
+func resolveSize(model, size string) string {
+	gptImage := strings.HasPrefix(model, "gpt-image")
+
+	switch strings.ToLower(size) {
+	case "", "square":
+		return openai.CreateImageSize1024x1024
+	case "landscape":
+		if gptImage {
+			return openai.CreateImageSize1536x1024
+		}
+		return openai.CreateImageSize1792x1024
+	case "portrait":
+		if gptImage {
+			return openai.CreateImageSize1024x1536
+		}
+		return openai.CreateImageSize1024x1792
+	default:
+		return size
+	}
+}
+
+// detectExtension picks a file extension from image magic bytes
+func detectExtension(data []byte) string {
+	switch {
+	case len(data) >= 8 && string(data[1:4]) == "PNG":
+		return ".png"
+	case len(data) >= 3 && data[0] == 0xFF && data[1] == 0xD8 && data[2] == 0xFF:
+		return ".jpg"
+	case len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return ".webp"
+	default:
+		return ".png"
+	}
+}
+
+// slugify turns a prompt into a short file-name-safe string
+func slugify(text string) string {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) > 6 {
+		words = words[:6]
+	}
+	slug := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return -1
+	}, strings.Join(words, "-"))
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		return "image"
+	}
+	return slug
+}
+
+func downloadImage(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := apiclient.HTTPClient(apiclient.ProviderOpenAI).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download image: HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
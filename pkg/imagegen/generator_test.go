@@ -0,0 +1,116 @@
+package imagegen
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResolveSize(t *testing.T) {
+	tests := []struct {
+		model string
+		size  string
+		want  string
+	}{
+		{"dall-e-3", "", "1024x1024"},
+		{"dall-e-3", "landscape", "1792x1024"},
+		{"gpt-image-1", "landscape", "1536x1024"},
+		{"gpt-image-1", "portrait", "1024x1536"},
+		{"dall-e-3", "512x512", "512x512"},
+	}
+
+	for _, tt := range tests {
+		if got := resolveSize(tt.model, tt.size); got != tt.want {
+			t.Errorf("resolveSize(%q, %q) = %q, want %q", tt.model, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	if got := slugify("A Sunset over the Ocean, cinematic & moody lighting"); got != "a-sunset-over-the-ocean-cinematic" {
+		t.Errorf("Unexpected slug: %q", got)
+	}
+	if got := slugify("!!!"); got != "image" {
+		t.Errorf("Expected fallback slug 'image', got %q", got)
+	}
+}
+
+func TestDetectExtension(t *testing.T) {
+	if ext := detectExtension([]byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A}); ext != ".png" {
+		t.Errorf("Expected .png, got %s", ext)
+	}
+	if ext := detectExtension([]byte{0xFF, 0xD8, 0xFF, 0xE0}); ext != ".jpg" {
+		t.Errorf("Expected .jpg, got %s", ext)
+	}
+}
+
+func TestResolveAspect(t *testing.T) {
+	tests := map[string]string{
+		"":          "1:1",
+		"landscape": "16:9",
+		"portrait":  "9:16",
+		"1792x1024": "16:9",
+		"1024x1536": "2:3",
+		"4:5":       "4:5",
+		"2560x1080": "21:9",
+		"wide":      "1:1",
+	}
+	for size, want := range tests {
+		if got := resolveAspect(size); got != want {
+			t.Errorf("resolveAspect(%q) = %q, want %q", size, got, want)
+		}
+	}
+}
+
+func TestBrollPrompt(t *testing.T) {
+	prompt, style, err := brollPrompt("A busy coffee shop.", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(prompt, "A busy coffee shop. Wide establishing shot") || !strings.HasSuffix(prompt, "No text, captions, logos or watermarks.") {
+		t.Errorf("Unexpected prompt: %q", prompt)
+	}
+	if style != "natural" {
+		t.Errorf("Expected the natural OpenAI style for photos, got %q", style)
+	}
+
+	if _, style, _ := brollPrompt("city at night", LookCinematic, "", "stability"); style != "cinematic" {
+		t.Errorf("Expected the cinematic Stability preset, got %q", style)
+	}
+	if prompt, style, _ := brollPrompt("exact prompt", LookNone, "stability", ""); prompt != "exact prompt" || style != "" {
+		t.Errorf("Expected the prompt unchanged, got %q, %q", prompt, style)
+	}
+	if _, _, err := brollPrompt("x", "anime", "", ""); err == nil {
+		t.Error("Expected an error for an unknown look")
+	}
+}
+
+func TestStabilityProviderGenerate(t *testing.T) {
+	png := []byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"name": "unauthorized", "errors": ["bad key"]}`))
+			return
+		}
+		if r.URL.Path != "/sd3" || r.FormValue("model") != "sd3.5-large" || r.FormValue("aspect_ratio") != "16:9" || r.FormValue("style_preset") != "cinematic" {
+			t.Errorf("Unexpected request: %s %v", r.URL.Path, r.MultipartForm.Value)
+		}
+		w.Write(png)
+	}))
+	defer server.Close()
+
+	provider := NewStabilityProvider("key", server.URL, "sd3.5-large")
+	data, err := provider.Generate(context.Background(), Request{Prompt: "harbor at dawn", Size: "landscape", Style: "cinematic"})
+	if err != nil || !bytes.Equal(data, png) {
+		t.Fatalf("Generate() = %v, %v", data, err)
+	}
+
+	_, err = NewStabilityProvider("wrong", server.URL, "").Generate(context.Background(), Request{Prompt: "x"})
+	if err == nil || !strings.Contains(err.Error(), "bad key") {
+		t.Errorf("Expected the API error message, got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package imagegen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/apiclient"
+)
+
+// stabilityBaseURL is the Stability AI image generation API
+const stabilityBaseURL = "https://api.stability.ai/v2beta/stable-image/generate"
+
+// stabilityAspects are the aspect ratios the Stability API accepts
+var stabilityAspects = []string{"21:9", "16:9", "3:2", "5:4", "1:1", "4:5", "2:3", "9:16", "9:21"}
+
+// StabilityProvider generates images with Stable Diffusion through the
+// Stability AI REST API
+type StabilityProvider struct {
+	apiKey  string
+	baseURL string
+	model   string // core, ultra or an sd3 model such as sd3.5-large
+	client  *http.Client
+}
+
+// NewStabilityProvider creates a Stable Diffusion provider. The model
+// defaults to core; baseURL overrides the Stability endpoint.
+func NewStabilityProvider(apiKey, baseURL, model string) *StabilityProvider {
+	if baseURL == "" {
+		baseURL = stabilityBaseURL
+	}
+	if model == "" {
+		model = "core"
+	}
+	return &StabilityProvider{
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   model,
+		client:  apiclient.HTTPClient(apiclient.ProviderStability),
+	}
+}
+
+// Name returns the provider name
+func (p *StabilityProvider) Name() string {
+	return "stability"
+}
+
+// Model returns the model used for generation
+func (p *StabilityProvider) Model() string {
+	return p.model
+}
+
+// Generate creates a single PNG image and returns its bytes. Style is sent
+// as the style preset (e.g. photographic, cinematic, digital-art).
+func (p *StabilityProvider) Generate(ctx context.Context, req Request) ([]byte, error) {
+	endpoint := p.model
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	fields := map[string]string{
+		"prompt":        req.Prompt,
+		"aspect_ratio":  resolveAspect(req.Size),
+		"output_format": "png",
+	}
+	if strings.HasPrefix(p.model, "sd3") {
+		endpoint = "sd3"
+		fields["model"] = p.model
+	}
+	if req.Style != "" {
+		fields["style_preset"] = req.Style
+	}
+	for key, value := range fields {
+		if err := form.WriteField(key, value); err != nil {
+			return nil, err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/"+endpoint, &body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	httpReq.Header.Set("Content-Type", form.FormDataContentType())
+	httpReq.Header.Set("Accept", "image/*")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("image generation failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Errors []string `json:"errors"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && len(apiErr.Errors) > 0 {
+			return nil, fmt.Errorf("image generation failed: HTTP %d: %s", resp.StatusCode, strings.Join(apiErr.Errors, "; "))
+		}
+		return nil, fmt.Errorf("image generation failed: HTTP %d", resp.StatusCode)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("image generation returned an empty image")
+	}
+	return data, nil
+}
+
+// resolveAspect maps a size or aspect name to the nearest aspect ratio the
+// Stability API accepts
+func resolveAspect(size string) string {
+	switch strings.ToLower(size) {
+	case "", "square":
+		return "1:1"
+	case "landscape":
+		return "16:9"
+	case "portrait":
+		return "9:16"
+	}
+
+	sep := "x"
+	if strings.Contains(size, ":") {
+		sep = ":"
+	}
+	parts := strings.Split(strings.ToLower(size), sep)
+	if len(parts) != 2 {
+		return "1:1"
+	}
+	w, errW := strconv.ParseFloat(parts[0], 64)
+	h, errH := strconv.ParseFloat(parts[1], 64)
+	if errW != nil || errH != nil || w <= 0 || h <= 0 {
+		return "1:1"
+	}
+
+	best, bestDiff := "1:1", math.Inf(1)
+	for _, aspect := range stabilityAspects {
+		var aw, ah float64
+		fmt.Sscanf(aspect, "%f:%f", &aw, &ah)
+		if diff := math.Abs(math.Log(w/h) - math.Log(aw/ah)); diff < bestDiff {
+			best, bestDiff = aspect, diff
+		}
+	}
+	return best
+}
@@ -0,0 +1,353 @@
+// Package library catalogs a folder of source media with probe metadata
+// and, optionally, transcripts and frame descriptions, so the right clip
+// can be found by its properties, what is said in it or what it shows
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/vision"
+)
+
+// Media kinds
+const (
+	KindVideo = "video"
+	KindAudio = "audio"
+	KindImage = "image"
+)
+
+// catalogFile holds a folder's catalog in the folder itself
+const catalogFile = ".mcp-video-library.json"
+
+// describePrompt asks for a short, searchable description of a frame
+const describePrompt = "Describe this frame in one or two sentences for a searchable media catalog: the setting, people, objects, actions, on-screen text and shot type (e.g. close-up, wide, screen recording)."
+
+// describeSamples is how many frames of each video are described
+const describeSamples = 3
+
+var mediaKinds = map[string]string{
+	".mp4": KindVideo, ".mov": KindVideo, ".mkv": KindVideo, ".avi": KindVideo, ".webm": KindVideo, ".m4v": KindVideo,
+	".flv": KindVideo, ".wmv": KindVideo, ".mts": KindVideo, ".m2ts": KindVideo, ".ts": KindVideo, ".mpg": KindVideo, ".mpeg": KindVideo,
+	".mp3": KindAudio, ".wav": KindAudio, ".m4a": KindAudio, ".aac": KindAudio, ".flac": KindAudio, ".ogg": KindAudio, ".opus": KindAudio,
+	".jpg": KindImage, ".jpeg": KindImage, ".png": KindImage, ".webp": KindImage, ".gif": KindImage, ".bmp": KindImage, ".tiff": KindImage,
+}
+
+// Segment is timed text: a line of speech or a frame description
+type Segment struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end,omitempty"`
+	Text  string  `json:"text"`
+}
+
+// Entry is one cataloged file. Path is relative to the catalog's folder.
+type Entry struct {
+	Path         string    `json:"path"`
+	Kind         string    `json:"kind"`
+	Size         int64     `json:"size"`
+	Modified     time.Time `json:"modified"`
+	Format       string    `json:"format,omitempty"`
+	Duration     float64   `json:"duration,omitempty"`
+	Width        int       `json:"width,omitempty"`
+	Height       int       `json:"height,omitempty"`
+	FPS          float64   `json:"fps,omitempty"`
+	VideoCodec   string    `json:"videoCodec,omitempty"`
+	AudioCodec   string    `json:"audioCodec,omitempty"`
+	Bitrate      int       `json:"bitrate,omitempty"`
+	HasAudio     bool      `json:"hasAudio,omitempty"`
+	Transcript   []Segment `json:"transcript,omitempty"`
+	Descriptions []Segment `json:"descriptions,omitempty"`
+}
+
+// Catalog is the index of one folder
+type Catalog struct {
+	Directory string    `json:"-"`
+	Updated   time.Time `json:"updated"`
+	Entries   []Entry   `json:"entries"`
+}
+
+// IndexOptions contains parameters for indexing a folder
+type IndexOptions struct {
+	Directory  string
+	Recursive  bool
+	Transcribe bool   // Transcribe speech with Whisper (OpenAI API key)
+	Describe   bool   // Describe sampled frames with the vision model
+	Language   string // Transcription language hint (optional)
+	Force      bool   // Re-probe and re-analyze unchanged files
+}
+
+// IndexResult summarizes an indexing run
+type IndexResult struct {
+	Catalog     *Catalog
+	Path        string // Catalog file
+	Added       int
+	Updated     int
+	Unchanged   int
+	Removed     int
+	Transcribed int
+	Described   int
+	Warnings    []string
+}
+
+// Indexer probes and analyzes media into catalogs
+type Indexer struct {
+	videoOps      *video.Operations
+	transcriptOps *transcript.Operations
+	vision        *vision.Analyzer
+}
+
+// NewIndexer creates an indexer
+func NewIndexer(videoOps *video.Operations, transcriptOps *transcript.Operations, visionAnalyzer *vision.Analyzer) *Indexer {
+	return &Indexer{
+		videoOps:      videoOps,
+		transcriptOps: transcriptOps,
+		vision:        visionAnalyzer,
+	}
+}
+
+// Index scans the folder and updates its catalog. Files whose size and
+// modification time are unchanged keep their entry, so re-indexing only
+// probes new and edited files, and transcripts and descriptions are added
+// only where missing. A file that can't be probed or analyzed is reported
+// as a warning.
+func (x *Indexer) Index(ctx context.Context, opts IndexOptions) (*IndexResult, error) {
+	dir, err := filepath.Abs(opts.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("invalid directory: %w", err)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", opts.Directory)
+	}
+
+	previous, err := Load(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	known := map[string]Entry{}
+	if previous != nil {
+		for _, e := range previous.Entries {
+			known[e.Path] = e
+		}
+	}
+
+	files, err := listMedia(dir, opts.Recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &IndexResult{Catalog: &Catalog{Directory: dir}}
+	transcribeFailed, describeFailed := 0, 0
+	for _, rel := range files {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		path := filepath.Join(dir, rel)
+		stat, err := os.Stat(path)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", rel, err))
+			continue
+		}
+
+		entry, ok := known[rel]
+		delete(known, rel)
+		switch {
+		case ok && !opts.Force && entry.Size == stat.Size() && entry.Modified.Equal(stat.ModTime().UTC()):
+			result.Unchanged++
+		default:
+			probed, err := x.probe(ctx, path, rel, stat)
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", rel, err))
+				continue
+			}
+			if ok {
+				result.Updated++
+			} else {
+				result.Added++
+			}
+			entry = *probed
+		}
+
+		if opts.Transcribe && entry.HasAudio && entry.Kind != KindImage && entry.Transcript == nil {
+			segments, err := x.transcribe(ctx, path, opts.Language)
+			if err != nil {
+				if transcribeFailed++; transcribeFailed <= 3 {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("%s: transcription failed: %v", rel, err))
+				}
+			} else {
+				entry.Transcript = segments
+				result.Transcribed++
+			}
+		}
+		if opts.Describe && entry.Kind != KindAudio && entry.Descriptions == nil {
+			segments, err := x.describe(ctx, path, entry)
+			if err != nil {
+				if describeFailed++; describeFailed <= 3 {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("%s: description failed: %v", rel, err))
+				}
+			} else {
+				entry.Descriptions = segments
+				result.Described++
+			}
+		}
+		result.Catalog.Entries = append(result.Catalog.Entries, entry)
+	}
+	result.Removed = len(known)
+	if extra := transcribeFailed - 3; extra > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%d more file(s) could not be transcribed", extra))
+	}
+	if extra := describeFailed - 3; extra > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%d more file(s) could not be described", extra))
+	}
+
+	result.Catalog.Updated = time.Now().UTC().Truncate(time.Second)
+	if result.Path, err = result.Catalog.Save(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// probe reads a file's metadata
+func (x *Indexer) probe(ctx context.Context, path, rel string, stat os.FileInfo) (*Entry, error) {
+	info, err := x.videoOps.GetVideoInfo(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe: %w", err)
+	}
+	kind := mediaKinds[strings.ToLower(filepath.Ext(rel))]
+	if kind == KindVideo && info.Width == 0 {
+		kind = KindAudio
+	}
+	return &Entry{
+		Path:       rel,
+		Kind:       kind,
+		Size:       stat.Size(),
+		Modified:   stat.ModTime().UTC(),
+		Format:     info.Format,
+		Duration:   info.Duration,
+		Width:      info.Width,
+		Height:     info.Height,
+		FPS:        info.FPS,
+		VideoCodec: info.VideoCodec,
+		AudioCodec: info.AudioCodec,
+		Bitrate:    info.Bitrate,
+		HasAudio:   info.HasAudio || kind == KindAudio,
+	}, nil
+}
+
+// transcribe returns a file's speech as timed segments. Silence gives an
+// empty, non-nil list so the file isn't transcribed again.
+func (x *Indexer) transcribe(ctx context.Context, path, language string) ([]Segment, error) {
+	trans, err := x.transcriptOps.ExtractTranscript(ctx, path, language)
+	if err != nil {
+		return nil, err
+	}
+	segments := make([]Segment, 0, len(trans.Segments))
+	for _, s := range trans.Segments {
+		if text := strings.TrimSpace(s.Text); text != "" {
+			segments = append(segments, Segment{Start: s.Start, End: s.End, Text: text})
+		}
+	}
+	return segments, nil
+}
+
+// describe describes an image, or frames spread through a video
+func (x *Indexer) describe(ctx context.Context, path string, entry Entry) ([]Segment, error) {
+	if entry.Kind == KindImage {
+		description, err := x.vision.AnalyzeFrame(ctx, path, describePrompt)
+		if err != nil {
+			return nil, err
+		}
+		return []Segment{{Text: strings.TrimSpace(description)}}, nil
+	}
+
+	timestamps := sampleTimes(entry.Duration, describeSamples)
+	descriptions, err := x.vision.DescribeFrames(ctx, path, timestamps, describePrompt)
+	if err != nil {
+		return nil, err
+	}
+	segments := make([]Segment, len(descriptions))
+	for i, d := range descriptions {
+		segments[i] = Segment{Start: timestamps[i], Text: strings.TrimSpace(d)}
+	}
+	return segments, nil
+}
+
+// sampleTimes spreads n timestamps evenly through a duration, away from
+// the very start and end where fades and slates sit
+func sampleTimes(duration float64, n int) []float64 {
+	if duration <= 0 {
+		return []float64{0}
+	}
+	times := make([]float64, n)
+	for i := range times {
+		times[i] = duration * float64(i+1) / float64(n+1)
+	}
+	return times
+}
+
+// listMedia returns the media files in dir relative to it, skipping hidden
+// files and folders
+func listMedia(dir string, recursive bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		hidden := strings.HasPrefix(d.Name(), ".") && path != dir
+		if d.IsDir() {
+			if path != dir && (!recursive || hidden) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if hidden || mediaKinds[strings.ToLower(filepath.Ext(path))] == "" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Load reads the catalog of a folder. A folder that was never indexed
+// returns an error satisfying os.IsNotExist.
+func Load(dir string) (*Catalog, error) {
+	data, err := os.ReadFile(filepath.Join(dir, catalogFile))
+	if err != nil {
+		return nil, err
+	}
+	var catalog Catalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("invalid catalog in %s: %w", dir, err)
+	}
+	catalog.Directory = dir
+	return &catalog, nil
+}
+
+// Save writes the catalog into its folder and returns the file's path
+func (c *Catalog) Save() (string, error) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(c.Directory, catalogFile)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write catalog: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,90 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestListMedia(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.mp4", "b.WAV", "notes.txt", ".hidden.mp4", "sub/c.png", ".cache/d.mov"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := listMedia(dir, true)
+	if err != nil {
+		t.Fatalf("listMedia failed: %v", err)
+	}
+	if want := []string{"a.mp4", "b.WAV", filepath.Join("sub", "c.png")}; !reflect.DeepEqual(files, want) {
+		t.Errorf("recursive = %v, want %v", files, want)
+	}
+	files, _ = listMedia(dir, false)
+	if want := []string{"a.mp4", "b.WAV"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("flat = %v, want %v", files, want)
+	}
+}
+
+func TestCatalogSearch(t *testing.T) {
+	catalog := &Catalog{Directory: "/media", Entries: []Entry{
+		{Path: "interview.mov", Kind: KindVideo, Duration: 600, Width: 3840, Height: 2160, VideoCodec: "prores", AudioCodec: "pcm_s16le", HasAudio: true,
+			Transcript:   []Segment{{Start: 12, End: 15, Text: "Our pricing changed last year"}, {Start: 40, End: 44, Text: "The new pricing model is simpler"}},
+			Descriptions: []Segment{{Start: 150, Text: "A woman in a blue shirt talking in an office"}}},
+		{Path: "broll/city.mp4", Kind: KindVideo, Duration: 12, Width: 1080, Height: 1920, VideoCodec: "h264",
+			Descriptions: []Segment{{Start: 6, Text: "Aerial shot of a city skyline at night"}}},
+		{Path: "music/pricing-theme.mp3", Kind: KindAudio, Duration: 95, AudioCodec: "mp3", HasAudio: true},
+	}}
+
+	hits, err := catalog.Search(Query{Text: "pricing"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	// A file name match weighs as much as two passages; ties sort by path
+	if len(hits) != 2 || hits[0].Path != "interview.mov" || hits[1].Path != "music/pricing-theme.mp3" {
+		t.Fatalf("text hits = %+v", hits)
+	}
+	if hits[0].File != filepath.Join("/media", "interview.mov") || len(hits[0].Matches) != 2 || hits[0].Matches[0].Start != 12 {
+		t.Errorf("interview hit = %+v", hits[0])
+	}
+
+	if hits, _ := catalog.Search(Query{Spoken: "pricing model"}); len(hits) != 1 || hits[0].Matches[0].Start != 40 {
+		t.Errorf("spoken hits = %+v", hits)
+	}
+	if hits, _ := catalog.Search(Query{Visual: "skyline", Orientation: OrientationPortrait}); len(hits) != 1 || hits[0].Path != "broll/city.mp4" {
+		t.Errorf("visual hits = %+v", hits)
+	}
+	if hits, _ := catalog.Search(Query{Kind: KindVideo, MinWidth: 3840, Codec: "ProRes"}); len(hits) != 1 || hits[0].Path != "interview.mov" {
+		t.Errorf("filter hits = %+v", hits)
+	}
+	if hits, _ := catalog.Search(Query{MaxDuration: 100, Text: "skyline night"}); len(hits) != 1 {
+		t.Errorf("duration hits = %+v", hits)
+	}
+	if hits, _ := catalog.Search(Query{Text: "pricing skyline"}); len(hits) != 0 {
+		t.Errorf("every word should be required: %+v", hits)
+	}
+	if _, err := catalog.Search(Query{Orientation: "diagonal"}); err == nil {
+		t.Error("expected an error for an unknown orientation")
+	}
+}
+
+func TestCatalogSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Load(dir); !os.IsNotExist(err) {
+		t.Errorf("Load of an unindexed folder = %v", err)
+	}
+	catalog := &Catalog{Directory: dir, Entries: []Entry{{Path: "a.mp4", Kind: KindVideo, Duration: 3}}}
+	if _, err := catalog.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load(dir)
+	if err != nil || loaded.Directory != dir || !reflect.DeepEqual(loaded.Entries, catalog.Entries) {
+		t.Errorf("Load = %+v, %v", loaded, err)
+	}
+}
@@ -0,0 +1,211 @@
+package library
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Orientations
+const (
+	OrientationLandscape = "landscape"
+	OrientationPortrait  = "portrait"
+	OrientationSquare    = "square"
+)
+
+// Query filters and ranks catalog entries. Empty fields match everything.
+type Query struct {
+	Text        string  // Words matched against file names, speech and descriptions
+	Spoken      string  // Words matched against speech only
+	Visual      string  // Words matched against frame descriptions only
+	Kind        string  // video, audio or image
+	MinDuration float64 // Seconds
+	MaxDuration float64
+	MinWidth    int
+	MinHeight   int
+	Orientation string // landscape, portrait or square
+	Codec       string // Video or audio codec name, e.g. h264, prores, aac
+	Limit       int    // Maximum results (default: 20)
+}
+
+// Match is a timed passage that matched the query
+type Match struct {
+	Source string  `json:"source"` // speech or visual
+	Start  float64 `json:"start"`
+	End    float64 `json:"end,omitempty"`
+	Text   string  `json:"text"`
+}
+
+// Hit is an entry that satisfies the query, with its absolute path
+type Hit struct {
+	Entry
+	File    string  `json:"file"`
+	Score   float64 `json:"score"`
+	Matches []Match `json:"matches,omitempty"`
+}
+
+// Search returns the entries that pass the filters, best first. With
+// words to match, an entry must contain every one of them in the searched
+// fields; passages that contain the most words are returned as matches.
+func (c *Catalog) Search(q Query) ([]Hit, error) {
+	switch q.Orientation {
+	case "", OrientationLandscape, OrientationPortrait, OrientationSquare:
+	default:
+		return nil, fmt.Errorf("unknown orientation: %s (use landscape, portrait or square)", q.Orientation)
+	}
+	switch q.Kind {
+	case "", KindVideo, KindAudio, KindImage:
+	default:
+		return nil, fmt.Errorf("unknown kind: %s (use video, audio or image)", q.Kind)
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var hits []Hit
+	for _, e := range c.Entries {
+		if !q.matchesFilters(e) {
+			continue
+		}
+		hit := Hit{Entry: e, File: filepath.Join(c.Directory, e.Path), Score: 1}
+		ok := true
+		for _, part := range []struct {
+			words                []string
+			name, speech, visual bool
+		}{
+			{terms(q.Text), true, true, true},
+			{terms(q.Spoken), false, true, false},
+			{terms(q.Visual), false, false, true},
+		} {
+			if len(part.words) == 0 {
+				continue
+			}
+			score, matches, found := matchWords(e, part.words, part.name, part.speech, part.visual)
+			if !found {
+				ok = false
+				break
+			}
+			hit.Score += score
+			hit.Matches = append(hit.Matches, matches...)
+		}
+		if ok {
+			hits = append(hits, hit)
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Path < hits[j].Path
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// matchesFilters checks the metadata filters
+func (q Query) matchesFilters(e Entry) bool {
+	if q.Kind != "" && e.Kind != q.Kind {
+		return false
+	}
+	if q.MinDuration > 0 && e.Duration < q.MinDuration {
+		return false
+	}
+	if q.MaxDuration > 0 && e.Duration > q.MaxDuration {
+		return false
+	}
+	if e.Width < q.MinWidth || e.Height < q.MinHeight {
+		return false
+	}
+	if q.Orientation != "" && orientation(e.Width, e.Height) != q.Orientation {
+		return false
+	}
+	if q.Codec != "" && !strings.EqualFold(e.VideoCodec, q.Codec) && !strings.EqualFold(e.AudioCodec, q.Codec) {
+		return false
+	}
+	return true
+}
+
+// orientation names the shape of a frame; within 5% of square is square
+func orientation(width, height int) string {
+	switch {
+	case width == 0 || height == 0:
+		return ""
+	case float64(width) > float64(height)*1.05:
+		return OrientationLandscape
+	case float64(height) > float64(width)*1.05:
+		return OrientationPortrait
+	}
+	return OrientationSquare
+}
+
+// matchWords reports whether every word appears somewhere in the chosen
+// fields, scoring by how many fields and passages contain them, and
+// returns the passages that contain the most words
+func matchWords(e Entry, words []string, name, speech, visual bool) (float64, []Match, bool) {
+	var haystack strings.Builder
+	if name {
+		haystack.WriteString(strings.ToLower(e.Path) + "\n")
+	}
+	type passage struct {
+		source string
+		seg    Segment
+		hits   int
+	}
+	var passages []passage
+	add := func(source string, segments []Segment) {
+		for _, s := range segments {
+			lower := strings.ToLower(s.Text)
+			haystack.WriteString(lower + "\n")
+			hits := 0
+			for _, w := range words {
+				if strings.Contains(lower, w) {
+					hits++
+				}
+			}
+			if hits > 0 {
+				passages = append(passages, passage{source, s, hits})
+			}
+		}
+	}
+	if speech {
+		add("speech", e.Transcript)
+	}
+	if visual {
+		add("visual", e.Descriptions)
+	}
+
+	all := haystack.String()
+	for _, w := range words {
+		if !strings.Contains(all, w) {
+			return 0, nil, false
+		}
+	}
+
+	score := 0.0
+	if name {
+		for _, w := range words {
+			if strings.Contains(strings.ToLower(e.Path), w) {
+				score += 2
+			}
+		}
+	}
+	sort.SliceStable(passages, func(i, j int) bool { return passages[i].hits > passages[j].hits })
+	var matches []Match
+	for i, p := range passages {
+		score += float64(p.hits) / float64(len(words))
+		if i < 3 {
+			matches = append(matches, Match{Source: p.source, Start: p.seg.Start, End: p.seg.End, Text: p.seg.Text})
+		}
+	}
+	return score, matches, true
+}
+
+// terms splits a query into lowercase words
+func terms(s string) []string {
+	return strings.Fields(strings.ToLower(s))
+}
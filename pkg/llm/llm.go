@@ -0,0 +1,126 @@
+// Package llm sends prompts to the configured language model, Claude or
+// OpenAI
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/apiclient"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/usage"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Client completes prompts with the configured provider and model
+type Client struct {
+	config *config.Config
+}
+
+// NewClient creates a client. The provider and model are read from the
+// config on each call so set_config changes apply immediately.
+func NewClient(cfg *config.Config) *Client {
+	return &Client{config: cfg}
+}
+
+// Provider returns the LLM provider in use ("claude" or "openai")
+func (c *Client) Provider() string {
+	switch {
+	case c.config.AgentProvider != "":
+		return c.config.AgentProvider
+	case c.config.ClaudeAPIKey != "":
+		return "claude"
+	default:
+		return "openai"
+	}
+}
+
+// Complete sends a single prompt to the configured provider and returns the
+// reply text. OpenAI is asked for a JSON object, so prompts should request
+// JSON. Usage is recorded under feature.
+func (c *Client) Complete(ctx context.Context, feature, prompt string) (string, error) {
+	if c.Provider() == "openai" {
+		if c.config.OpenAIKey == "" {
+			return "", fmt.Errorf("OpenAI API key not configured")
+		}
+		model := c.config.AgentModel
+		if model == "" {
+			model = openai.GPT4o
+		}
+		resp, err := openai.NewClientWithConfig(apiclient.OpenAIConfig(c.config.OpenAIKey, apiclient.ProviderOpenAI)).CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+			Model: model,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: prompt,
+				},
+			},
+			ResponseFormat: &openai.ChatCompletionResponseFormat{
+				Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+		usage.Record(usage.Entry{
+			Provider:     usage.ProviderOpenAI,
+			Feature:      feature,
+			Model:        model,
+			InputTokens:  resp.Usage.PromptTokens,
+			OutputTokens: resp.Usage.CompletionTokens,
+		})
+		if len(resp.Choices) == 0 {
+			return "", fmt.Errorf("no response returned")
+		}
+		return resp.Choices[0].Message.Content, nil
+	}
+
+	if c.config.ClaudeAPIKey == "" {
+		return "", fmt.Errorf("Claude API key not configured")
+	}
+	model := c.config.AgentModel
+	if model == "" {
+		model = "claude-opus-4-6"
+	}
+	client := anthropic.NewClient(option.WithAPIKey(c.config.ClaudeAPIKey))
+	resp, err := client.Messages.New(ctx, anthropic.MessageNewParams{
+		Model:     anthropic.Model(model),
+		MaxTokens: 8192,
+		Messages:  []anthropic.MessageParam{anthropic.NewUserMessage(anthropic.NewTextBlock(prompt))},
+	})
+	if err != nil {
+		return "", err
+	}
+	usage.Record(usage.Entry{
+		Provider:     usage.ProviderAnthropic,
+		Feature:      feature,
+		Model:        model,
+		InputTokens:  int(resp.Usage.InputTokens),
+		OutputTokens: int(resp.Usage.OutputTokens),
+	})
+	var out strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			out.WriteString(block.Text)
+		}
+	}
+	if out.Len() == 0 {
+		return "", fmt.Errorf("no response returned")
+	}
+	return out.String(), nil
+}
+
+// DecodeJSON unmarshals the JSON object in a model reply into v. Models
+// often wrap it in a code fence or a sentence, so everything outside the
+// outermost braces is ignored.
+func DecodeJSON(reply string, v interface{}) error {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return fmt.Errorf("reply did not contain JSON")
+	}
+	return json.Unmarshal([]byte(reply[start:end+1]), v)
+}
@@ -0,0 +1,24 @@
+package llm
+
+import "testing"
+
+func TestDecodeJSON(t *testing.T) {
+	var got struct {
+		Items []string `json:"items"`
+	}
+	for _, reply := range []string{
+		`{"items": ["a", "b"]}`,
+		"```json\n{\"items\": [\"a\", \"b\"]}\n```",
+		`Here you go: {"items": ["a", "b"]} Let me know if you need more.`,
+	} {
+		got.Items = nil
+		if err := DecodeJSON(reply, &got); err != nil || len(got.Items) != 2 || got.Items[1] != "b" {
+			t.Errorf("DecodeJSON(%q) = %v, %v", reply, got.Items, err)
+		}
+	}
+	for _, reply := range []string{"", "no JSON here", "} backwards {", `{"items": [}`} {
+		if err := DecodeJSON(reply, &got); err == nil {
+			t.Errorf("DecodeJSON(%q) should fail", reply)
+		}
+	}
+}
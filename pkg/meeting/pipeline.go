@@ -0,0 +1,499 @@
+package meeting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/apiclient"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/usage"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Options contains options for processing a meeting or lecture recording
+type Options struct {
+	Input                 string
+	OutputDir             string
+	Language              string  // Transcription language hint (optional)
+	SkipAudioCleanup      bool    // Transcribe the original audio instead of the cleaned track
+	SkipHighlights        bool    // Don't render a highlights clip
+	MaxHighlights         int     // Maximum number of highlight moments (default: 5)
+	MaxHighlightsDuration float64 // Maximum total highlights length in seconds (default: 90)
+}
+
+// SpeakerSegment is a transcript segment attributed to a speaker
+type SpeakerSegment struct {
+	Speaker string  `json:"speaker"`
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Text    string  `json:"text"`
+}
+
+// Chapter marks the start of a topic within the recording
+type Chapter struct {
+	Start float64 `json:"start"`
+	Title string  `json:"title"`
+}
+
+// Highlight is a notable moment selected for the highlights clip
+type Highlight struct {
+	Start  float64 `json:"start"`
+	End    float64 `json:"end"`
+	Reason string  `json:"reason"`
+}
+
+// Result describes everything produced by the pipeline
+type Result struct {
+	Input          string           `json:"input"`
+	Duration       float64          `json:"duration"`
+	CleanedAudio   string           `json:"cleanedAudio,omitempty"`
+	TranscriptJSON string           `json:"transcriptJson"`
+	TranscriptText string           `json:"transcriptText"`
+	ChaptersFile   string           `json:"chaptersFile"`
+	SummaryFile    string           `json:"summaryFile"`
+	HighlightsClip string           `json:"highlightsClip,omitempty"`
+	ResultFile     string           `json:"resultFile"`
+	Speakers       []string         `json:"speakers"`
+	Segments       []SpeakerSegment `json:"segments"`
+	Chapters       []Chapter        `json:"chapters"`
+	Summary        string           `json:"summary"`
+	KeyPoints      []string         `json:"keyPoints"`
+	ActionItems    []string         `json:"actionItems"`
+	Decisions      []string         `json:"decisions"`
+	Highlights     []Highlight      `json:"highlights"`
+	Warnings       []string         `json:"warnings,omitempty"`
+}
+
+// analysis is the structured response expected from the language model
+type analysis struct {
+	SegmentSpeakers []string    `json:"segmentSpeakers"`
+	Chapters        []Chapter   `json:"chapters"`
+	Summary         string      `json:"summary"`
+	KeyPoints       []string    `json:"keyPoints"`
+	ActionItems     []string    `json:"actionItems"`
+	Decisions       []string    `json:"decisions"`
+	Highlights      []Highlight `json:"highlights"`
+}
+
+// Pipeline orchestrates audio cleanup, transcription, analysis and highlight
+// rendering for meeting and lecture recordings
+type Pipeline struct {
+	client        *openai.Client
+	ffmpeg        *ffmpeg.Manager
+	videoOps      *video.Operations
+	transcriptOps *transcript.Operations
+}
+
+// NewPipeline creates a new meeting pipeline
+func NewPipeline(apiKey string, mgr *ffmpeg.Manager, videoOps *video.Operations, transcriptOps *transcript.Operations) *Pipeline {
+	var client *openai.Client
+	if apiKey != "" {
+		client = openai.NewClientWithConfig(apiclient.OpenAIConfig(apiKey, apiclient.ProviderOpenAI))
+	}
+	return &Pipeline{
+		client:        client,
+		ffmpeg:        mgr,
+		videoOps:      videoOps,
+		transcriptOps: transcriptOps,
+	}
+}
+
+// Process runs the full pipeline and writes all outputs to opts.OutputDir
+func (p *Pipeline) Process(ctx context.Context, opts Options) (*Result, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("OpenAI API key not configured")
+	}
+	if opts.OutputDir == "" {
+		return nil, fmt.Errorf("output directory is required")
+	}
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	maxHighlights := opts.MaxHighlights
+	if maxHighlights <= 0 {
+		maxHighlights = 5
+	}
+	maxHighlightsDuration := opts.MaxHighlightsDuration
+	if maxHighlightsDuration <= 0 {
+		maxHighlightsDuration = 90
+	}
+
+	info, err := p.videoOps.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media info: %w", err)
+	}
+	if !info.HasAudio {
+		return nil, fmt.Errorf("recording has no audio track")
+	}
+
+	result := &Result{
+		Input:    opts.Input,
+		Duration: info.Duration,
+	}
+
+	// Step 1: cleaned audio
+	transcriptSource := opts.Input
+	if !opts.SkipAudioCleanup {
+		cleaned := filepath.Join(opts.OutputDir, "cleaned_audio.m4a")
+		if err := p.cleanAudio(ctx, opts.Input, cleaned); err != nil {
+			return nil, fmt.Errorf("failed to clean audio: %w", err)
+		}
+		result.CleanedAudio = cleaned
+		transcriptSource = cleaned
+	}
+
+	// Step 2: transcript
+	trans, err := p.transcriptOps.ExtractTranscript(ctx, transcriptSource, opts.Language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transcribe: %w", err)
+	}
+	if len(trans.Segments) == 0 {
+		return nil, fmt.Errorf("no speech found in recording")
+	}
+
+	// Step 3: speakers, chapters, summary and highlights
+	a, err := p.analyze(ctx, trans, maxHighlights)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze transcript: %w", err)
+	}
+
+	result.Segments = buildSpeakerSegments(trans, a.SegmentSpeakers)
+	result.Speakers = uniqueSpeakers(result.Segments)
+	result.Chapters = normalizeChapters(a.Chapters, info.Duration)
+	result.Summary = a.Summary
+	result.KeyPoints = a.KeyPoints
+	result.ActionItems = a.ActionItems
+	result.Decisions = a.Decisions
+	result.Highlights = limitHighlights(a.Highlights, info.Duration, maxHighlights, maxHighlightsDuration)
+
+	// Step 4: write documents
+	result.TranscriptJSON = filepath.Join(opts.OutputDir, "transcript.json")
+	if err := writeJSON(result.TranscriptJSON, result.Segments); err != nil {
+		return nil, fmt.Errorf("failed to write transcript: %w", err)
+	}
+
+	result.TranscriptText = filepath.Join(opts.OutputDir, "transcript.txt")
+	if err := os.WriteFile(result.TranscriptText, []byte(FormatSpeakerTranscript(result.Segments)), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write transcript: %w", err)
+	}
+
+	result.ChaptersFile = filepath.Join(opts.OutputDir, "chapters.txt")
+	if err := os.WriteFile(result.ChaptersFile, []byte(FormatChapters(result.Chapters)), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write chapters: %w", err)
+	}
+
+	result.SummaryFile = filepath.Join(opts.OutputDir, "summary.md")
+	if err := os.WriteFile(result.SummaryFile, []byte(FormatSummaryMarkdown(result)), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write summary: %w", err)
+	}
+
+	// Step 5: highlights clip
+	if !opts.SkipHighlights {
+		if len(result.Highlights) == 0 {
+			result.Warnings = append(result.Warnings, "no highlights selected; highlights clip skipped")
+		} else {
+			ext := filepath.Ext(opts.Input)
+			if ext == "" {
+				ext = ".mp4"
+			}
+			clip := filepath.Join(opts.OutputDir, "highlights"+ext)
+			if err := p.renderHighlights(ctx, opts.Input, clip, result.Highlights); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("failed to render highlights: %v", err))
+			} else {
+				result.HighlightsClip = clip
+			}
+		}
+	}
+
+	result.ResultFile = filepath.Join(opts.OutputDir, "meeting.json")
+	if err := writeJSON(result.ResultFile, result); err != nil {
+		return nil, fmt.Errorf("failed to write result: %w", err)
+	}
+
+	return result, nil
+}
+
+// cleanAudio removes rumble and background noise and normalizes loudness for speech
+func (p *Pipeline) cleanAudio(ctx context.Context, input, output string) error {
+	return p.ffmpeg.Execute(ctx,
+		"-i", input,
+		"-vn",
+		"-af", "highpass=f=80,lowpass=f=12000,afftdn=nf=-25,loudnorm=I=-16:TP=-1.5:LRA=11",
+		"-c:a", "aac",
+		"-b:a", "128k",
+		"-y",
+		output,
+	)
+}
+
+// analyze asks the language model for speakers, chapters, summary and highlights
+func (p *Pipeline) analyze(ctx context.Context, trans *transcript.Transcript, maxHighlights int) (*analysis, error) {
+	var lines []string
+	for i, seg := range trans.Segments {
+		lines = append(lines, fmt.Sprintf("[%d] (%.1f-%.1f) %s", i, seg.Start, seg.End, strings.TrimSpace(seg.Text)))
+	}
+
+	prompt := fmt.Sprintf(`You are processing the transcript of a meeting or lecture recording.
+Each line is "[segment index] (start-end seconds) text".
+
+Return a JSON object with these fields:
+- "segmentSpeakers": array with exactly one speaker label per segment, in order. Infer speaker changes from context (names, turn-taking, questions and answers). Use real names when stated, otherwise "Speaker 1", "Speaker 2", ...
+- "chapters": array of {"start": seconds, "title": string} marking topic changes. The first chapter must start at 0.
+- "summary": a concise paragraph summarizing the recording.
+- "keyPoints": array of the most important points.
+- "actionItems": array of action items with owners when mentioned.
+- "decisions": array of decisions that were made.
+- "highlights": up to %d of the most engaging or important moments as {"start": seconds, "end": seconds, "reason": string}, each 5-30 seconds long and aligned to segment boundaries.
+
+Transcript:
+%s`, maxHighlights, strings.Join(lines, "\n"))
+
+	resp, err := p.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model: openai.GPT4o,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: prompt,
+			},
+		},
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	usage.Record(usage.Entry{
+		Provider:     usage.ProviderOpenAI,
+		Feature:      usage.FeatureMeeting,
+		Model:        openai.GPT4o,
+		InputTokens:  resp.Usage.PromptTokens,
+		OutputTokens: resp.Usage.CompletionTokens,
+	})
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no analysis returned")
+	}
+
+	var a analysis
+	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &a); err != nil {
+		return nil, fmt.Errorf("failed to parse analysis: %w", err)
+	}
+
+	return &a, nil
+}
+
+// renderHighlights trims each highlight and joins them into a single clip
+func (p *Pipeline) renderHighlights(ctx context.Context, input, output string, highlights []Highlight) error {
+	if len(highlights) == 1 {
+		duration := highlights[0].End - highlights[0].Start
+		return p.videoOps.Trim(ctx, video.TrimOptions{
+			Input:     input,
+			Output:    output,
+			StartTime: highlights[0].Start,
+			Duration:  &duration,
+		})
+	}
+
+	tempDir, cleanup, err := scratch.Dir("meeting-highlights")
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	ext := filepath.Ext(output)
+	var segmentPaths []string
+	for i, h := range highlights {
+		segmentPath := filepath.Join(tempDir, fmt.Sprintf("highlight_%d%s", i, ext))
+		duration := h.End - h.Start
+		if err := p.videoOps.Trim(ctx, video.TrimOptions{
+			Input:     input,
+			Output:    segmentPath,
+			StartTime: h.Start,
+			Duration:  &duration,
+		}); err != nil {
+			return fmt.Errorf("failed to trim highlight %d: %w", i, err)
+		}
+		segmentPaths = append(segmentPaths, segmentPath)
+	}
+
+	return p.videoOps.Concatenate(ctx, video.ConcatenateOptions{
+		Inputs: segmentPaths,
+		Output: output,
+	})
+}
+
+// buildSpeakerSegments attaches speaker labels to transcript segments
+func buildSpeakerSegments(trans *transcript.Transcript, speakers []string) []SpeakerSegment {
+	segments := make([]SpeakerSegment, len(trans.Segments))
+	last := "Speaker 1"
+	for i, seg := range trans.Segments {
+		speaker := last
+		if i < len(speakers) && strings.TrimSpace(speakers[i]) != "" {
+			speaker = strings.TrimSpace(speakers[i])
+		}
+		last = speaker
+		segments[i] = SpeakerSegment{
+			Speaker: speaker,
+			Start:   seg.Start,
+			End:     seg.End,
+			Text:    strings.TrimSpace(seg.Text),
+		}
+	}
+	return segments
+}
+
+// uniqueSpeakers lists speakers in order of first appearance
+func uniqueSpeakers(segments []SpeakerSegment) []string {
+	seen := make(map[string]bool)
+	var speakers []string
+	for _, seg := range segments {
+		if !seen[seg.Speaker] {
+			seen[seg.Speaker] = true
+			speakers = append(speakers, seg.Speaker)
+		}
+	}
+	return speakers
+}
+
+// normalizeChapters sorts chapters, drops out-of-range entries and ensures one starts at 0
+func normalizeChapters(chapters []Chapter, duration float64) []Chapter {
+	var valid []Chapter
+	for _, ch := range chapters {
+		if ch.Start < 0 || (duration > 0 && ch.Start >= duration) || strings.TrimSpace(ch.Title) == "" {
+			continue
+		}
+		valid = append(valid, Chapter{Start: ch.Start, Title: strings.TrimSpace(ch.Title)})
+	}
+	sort.Slice(valid, func(i, j int) bool { return valid[i].Start < valid[j].Start })
+
+	if len(valid) == 0 {
+		return []Chapter{{Start: 0, Title: "Introduction"}}
+	}
+	valid[0].Start = 0
+	return valid
+}
+
+// limitHighlights clamps highlights to the recording and caps their count and total length
+func limitHighlights(highlights []Highlight, duration float64, maxCount int, maxTotal float64) []Highlight {
+	var valid []Highlight
+	total := 0.0
+	for _, h := range highlights {
+		if len(valid) >= maxCount {
+			break
+		}
+		start := h.Start
+		end := h.End
+		if start < 0 {
+			start = 0
+		}
+		if duration > 0 && end > duration {
+			end = duration
+		}
+		if end-start < 1 {
+			continue
+		}
+		if total+(end-start) > maxTotal {
+			end = start + (maxTotal - total)
+			if end-start < 1 {
+				break
+			}
+		}
+		total += end - start
+		valid = append(valid, Highlight{Start: start, End: end, Reason: h.Reason})
+	}
+	sort.Slice(valid, func(i, j int) bool { return valid[i].Start < valid[j].Start })
+	return valid
+}
+
+// FormatSpeakerTranscript formats speaker segments as readable text, merging consecutive lines by the same speaker
+func FormatSpeakerTranscript(segments []SpeakerSegment) string {
+	var b strings.Builder
+	var current string
+	for _, seg := range segments {
+		if seg.Speaker != current {
+			if current != "" {
+				b.WriteString("\n\n")
+			}
+			b.WriteString(fmt.Sprintf("[%s] %s: ", formatTimestamp(seg.Start), seg.Speaker))
+			current = seg.Speaker
+		} else {
+			b.WriteString(" ")
+		}
+		b.WriteString(seg.Text)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// FormatChapters formats chapters in the "HH:MM:SS Title" style used by video platforms
+func FormatChapters(chapters []Chapter) string {
+	var lines []string
+	for _, ch := range chapters {
+		lines = append(lines, fmt.Sprintf("%s %s", formatTimestamp(ch.Start), ch.Title))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// FormatSummaryMarkdown renders the summary document
+func FormatSummaryMarkdown(r *Result) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Summary: %s\n\n", filepath.Base(r.Input)))
+	b.WriteString(fmt.Sprintf("**Duration:** %s  \n", formatTimestamp(r.Duration)))
+	if len(r.Speakers) > 0 {
+		b.WriteString(fmt.Sprintf("**Speakers:** %s\n", strings.Join(r.Speakers, ", ")))
+	}
+	b.WriteString("\n## Overview\n\n")
+	b.WriteString(strings.TrimSpace(r.Summary))
+	b.WriteString("\n")
+
+	writeList := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		b.WriteString(fmt.Sprintf("\n## %s\n\n", title))
+		for _, item := range items {
+			b.WriteString(fmt.Sprintf("- %s\n", item))
+		}
+	}
+	writeList("Key Points", r.KeyPoints)
+	writeList("Decisions", r.Decisions)
+	writeList("Action Items", r.ActionItems)
+
+	if len(r.Chapters) > 0 {
+		b.WriteString("\n## Chapters\n\n")
+		for _, ch := range r.Chapters {
+			b.WriteString(fmt.Sprintf("- %s %s\n", formatTimestamp(ch.Start), ch.Title))
+		}
+	}
+
+	if len(r.Highlights) > 0 {
+		b.WriteString("\n## Highlights\n\n")
+		for _, h := range r.Highlights {
+			b.WriteString(fmt.Sprintf("- %s-%s %s\n", formatTimestamp(h.Start), formatTimestamp(h.End), h.Reason))
+		}
+	}
+
+	return b.String()
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func formatTimestamp(seconds float64) string {
+	total := int(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, (total/60)%60, total%60)
+}
@@ -0,0 +1,71 @@
+package meeting
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+)
+
+func TestBuildSpeakerSegments(t *testing.T) {
+	trans := &transcript.Transcript{
+		Segments: []transcript.Segment{
+			{Text: " Welcome everyone.", Start: 0, End: 2},
+			{Text: " Thanks for having me.", Start: 2, End: 4},
+			{Text: " Let's begin.", Start: 4, End: 5},
+		},
+	}
+
+	// Missing labels inherit the previous speaker
+	segments := buildSpeakerSegments(trans, []string{"Alice", "Bob"})
+	if len(segments) != 3 {
+		t.Fatalf("Expected 3 segments, got %d", len(segments))
+	}
+	if segments[2].Speaker != "Bob" {
+		t.Errorf("Expected unlabeled segment to inherit 'Bob', got %q", segments[2].Speaker)
+	}
+
+	speakers := uniqueSpeakers(segments)
+	if len(speakers) != 2 || speakers[0] != "Alice" || speakers[1] != "Bob" {
+		t.Errorf("Unexpected speakers: %v", speakers)
+	}
+
+	text := FormatSpeakerTranscript(segments)
+	if !strings.Contains(text, "[00:00:02] Bob: Thanks for having me. Let's begin.") {
+		t.Errorf("Consecutive segments were not merged:\n%s", text)
+	}
+}
+
+func TestNormalizeChapters(t *testing.T) {
+	chapters := normalizeChapters([]Chapter{
+		{Start: 120, Title: "Q&A"},
+		{Start: 5, Title: "Intro"},
+		{Start: 999, Title: "Out of range"},
+	}, 300)
+
+	if len(chapters) != 2 {
+		t.Fatalf("Expected 2 chapters, got %d", len(chapters))
+	}
+	if chapters[0].Start != 0 || chapters[0].Title != "Intro" {
+		t.Errorf("Expected first chapter to start at 0, got %+v", chapters[0])
+	}
+}
+
+func TestLimitHighlights(t *testing.T) {
+	highlights := limitHighlights([]Highlight{
+		{Start: 50, End: 80},
+		{Start: 10, End: 30},
+		{Start: 100, End: 140},
+	}, 120, 5, 60)
+
+	total := 0.0
+	for _, h := range highlights {
+		total += h.End - h.Start
+	}
+	if total > 60 {
+		t.Errorf("Expected highlights capped at 60s, got %.1fs", total)
+	}
+	if highlights[0].Start != 10 {
+		t.Errorf("Expected highlights sorted by start, got %+v", highlights)
+	}
+}
@@ -0,0 +1,300 @@
+// Package moderation screens video for content that needs review before
+// publishing: nudity, violence and brand logos on screen and profanity in
+// speech
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/vision"
+)
+
+// Flag categories
+const (
+	CategoryNudity    = "nudity"
+	CategoryViolence  = "violence"
+	CategoryProfanity = "profanity"
+	CategoryLogo      = "logo"
+)
+
+// Categories lists every category in report order
+var Categories = []string{CategoryNudity, CategoryViolence, CategoryProfanity, CategoryLogo}
+
+// Severities, from least to most serious
+const (
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+)
+
+var severityRank = map[string]int{SeverityLow: 1, SeverityMedium: 2, SeverityHigh: 3}
+
+// Where a flag was found
+const (
+	SourceFrames     = "frames"
+	SourceTranscript = "transcript"
+)
+
+// Options contains parameters for screening a video
+type Options struct {
+	Input          string
+	Categories     []string // Categories to check (default: all)
+	FailOn         string   // Lowest severity that fails the check (default: medium)
+	Interval       float64  // Seconds between sampled frames (default: duration/120, at least 2)
+	Language       string   // Transcription language hint (optional)
+	TranscriptPath string   // Reuse a transcript JSON instead of transcribing
+	SkipVision     bool     // Check the transcript only
+	SkipTranscript bool     // Check frames only
+	Output         string   // JSON report (default: <input>_moderation.json)
+}
+
+// Flag is content that needs review during [Start, End]
+type Flag struct {
+	Category string  `json:"category"`
+	Severity string  `json:"severity"`
+	Start    float64 `json:"start"`
+	End      float64 `json:"end"`
+	Source   string  `json:"source"` // frames or transcript
+	Detail   string  `json:"detail"`
+	Brand    string  `json:"brand,omitempty"`  // Logos only
+	Frames   int     `json:"frames,omitempty"` // Sampled frames flagged within the range
+}
+
+// CategorySummary counts a category's flags and their worst severity
+type CategorySummary struct {
+	Flags       int    `json:"flags"`
+	MaxSeverity string `json:"maxSeverity,omitempty"`
+}
+
+// Report is the machine-readable result of a pre-publish check
+type Report struct {
+	Input         string                     `json:"input"`
+	Duration      float64                    `json:"duration"`
+	Passed        bool                       `json:"passed"` // No flag at or above FailOn
+	FailOn        string                     `json:"failOn"`
+	Categories    map[string]CategorySummary `json:"categories"`
+	Flags         []Flag                     `json:"flags"`
+	SampledFrames int                        `json:"sampledFrames"`
+	CachedFrames  int                        `json:"cachedFrames"`
+	Sources       []string                   `json:"sources"` // Checks that ran: frames, transcript
+	Warnings      []string                   `json:"warnings,omitempty"`
+	Output        string                     `json:"-"`
+}
+
+// Moderator screens video
+type Moderator struct {
+	videoOps      *video.Operations
+	transcriptOps *transcript.Operations
+	vision        *vision.Analyzer
+}
+
+// NewModerator creates a moderator
+func NewModerator(videoOps *video.Operations, transcriptOps *transcript.Operations, visionAnalyzer *vision.Analyzer) *Moderator {
+	return &Moderator{
+		videoOps:      videoOps,
+		transcriptOps: transcriptOps,
+		vision:        visionAnalyzer,
+	}
+}
+
+// Moderate screens sampled frames with the vision model and the transcript
+// against a word list, and writes the report as JSON. A check that cannot
+// run is reported as a warning; it fails only when neither can run.
+func (m *Moderator) Moderate(ctx context.Context, opts Options) (*Report, error) {
+	if opts.FailOn == "" {
+		opts.FailOn = SeverityMedium
+	}
+	if severityRank[opts.FailOn] == 0 {
+		return nil, fmt.Errorf("unknown severity: %s (use low, medium or high)", opts.FailOn)
+	}
+	categories, err := selectCategories(opts.Categories)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Output == "" {
+		opts.Output = strings.TrimSuffix(opts.Input, filepath.Ext(opts.Input)) + "_moderation.json"
+	}
+
+	info, err := m.videoOps.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+	report := &Report{Input: opts.Input, Duration: info.Duration, FailOn: opts.FailOn, Flags: []Flag{}, Output: opts.Output}
+	var flags []Flag
+
+	visual := categories[CategoryNudity] || categories[CategoryViolence] || categories[CategoryLogo]
+	if visual && !opts.SkipVision && info.Width > 0 {
+		interval := opts.Interval
+		if interval <= 0 {
+			interval = math.Max(2, info.Duration/120)
+		}
+		var timestamps []float64
+		for t := 0.0; t < info.Duration; t += interval {
+			timestamps = append(timestamps, t)
+		}
+		scan, err := m.vision.ModerateFrames(ctx, opts.Input, timestamps)
+		if err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("frames not checked: %v", err))
+		} else {
+			report.Sources = append(report.Sources, SourceFrames)
+			report.SampledFrames, report.CachedFrames = len(scan.Frames), scan.CachedFrames
+			if scan.FailedFrames > 0 {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("%d frame(s) could not be analyzed", scan.FailedFrames))
+			}
+			flags = append(flags, frameFlags(scan.Frames, interval, info.Duration)...)
+		}
+	}
+
+	if categories[CategoryProfanity] && !opts.SkipTranscript {
+		trans, err := m.loadTranscript(ctx, opts, info.HasAudio)
+		if err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("speech not checked: %v", err))
+		} else {
+			report.Sources = append(report.Sources, SourceTranscript)
+			flags = append(flags, profanityFlags(trans)...)
+		}
+	}
+
+	if len(report.Sources) == 0 {
+		return nil, fmt.Errorf("no checks could run: %s", strings.Join(report.Warnings, "; "))
+	}
+
+	report.Categories = map[string]CategorySummary{}
+	for _, c := range Categories {
+		if categories[c] {
+			report.Categories[c] = CategorySummary{}
+		}
+	}
+	for _, f := range flags {
+		if !categories[f.Category] {
+			continue
+		}
+		report.Flags = append(report.Flags, f)
+		summary := report.Categories[f.Category]
+		summary.Flags++
+		if severityRank[f.Severity] > severityRank[summary.MaxSeverity] {
+			summary.MaxSeverity = f.Severity
+		}
+		report.Categories[f.Category] = summary
+	}
+	sort.SliceStable(report.Flags, func(i, j int) bool { return report.Flags[i].Start < report.Flags[j].Start })
+	report.Passed = passes(report.Flags, opts.FailOn)
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(opts.Output, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write report: %w", err)
+	}
+	return report, nil
+}
+
+// loadTranscript reads the given transcript or transcribes the input
+func (m *Moderator) loadTranscript(ctx context.Context, opts Options, hasAudio bool) (*transcript.Transcript, error) {
+	if opts.TranscriptPath != "" {
+		trans, err := m.transcriptOps.LoadTranscript(opts.TranscriptPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load transcript: %w", err)
+		}
+		return trans, nil
+	}
+	if !hasAudio {
+		return nil, fmt.Errorf("video has no audio track to transcribe")
+	}
+	return m.transcriptOps.ExtractTranscript(ctx, opts.Input, opts.Language)
+}
+
+// selectCategories returns the requested categories as a set, or all of
+// them when none are given
+func selectCategories(requested []string) (map[string]bool, error) {
+	selected := map[string]bool{}
+	if len(requested) == 0 {
+		requested = Categories
+	}
+	for _, c := range requested {
+		c = strings.ToLower(strings.TrimSpace(c))
+		switch c {
+		case CategoryNudity, CategoryViolence, CategoryProfanity, CategoryLogo:
+			selected[c] = true
+		default:
+			return nil, fmt.Errorf("unknown category: %s (use nudity, violence, profanity or logo)", c)
+		}
+	}
+	return selected, nil
+}
+
+// frameFlags joins flags of the same category (and brand, for logos) on
+// adjacent samples into one range with the worst severity seen. Each range
+// reaches half an interval past its first and last sample.
+func frameFlags(frames []vision.FrameModeration, interval, duration float64) []Flag {
+	type open struct {
+		flag Flag
+		last float64
+	}
+	active := map[string]*open{}
+	var flags []Flag
+	closeFlag := func(o *open) {
+		o.flag.Start = math.Max(0, o.flag.Start-interval/2)
+		o.flag.End = o.last + interval/2
+		if duration > 0 {
+			o.flag.End = math.Min(duration, o.flag.End)
+		}
+		flags = append(flags, o.flag)
+	}
+
+	sorted := append([]vision.FrameModeration(nil), frames...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp < sorted[j].Timestamp })
+	for _, frame := range sorted {
+		for _, cf := range frame.Flags {
+			key := cf.Category + "|" + strings.ToLower(cf.Brand)
+			o := active[key]
+			if o != nil && frame.Timestamp-o.last > 1.5*interval {
+				closeFlag(o)
+				o = nil
+			}
+			if o == nil {
+				o = &open{flag: Flag{Category: cf.Category, Severity: cf.Severity, Start: frame.Timestamp, Source: SourceFrames, Detail: cf.Detail, Brand: cf.Brand}}
+				active[key] = o
+			} else if o.last != frame.Timestamp {
+				if severityRank[cf.Severity] > severityRank[o.flag.Severity] {
+					o.flag.Severity, o.flag.Detail = cf.Severity, cf.Detail
+				}
+			} else {
+				continue
+			}
+			o.last = frame.Timestamp
+			o.flag.Frames++
+		}
+	}
+
+	keys := make([]string, 0, len(active))
+	for k := range active {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		closeFlag(active[k])
+	}
+	sort.SliceStable(flags, func(i, j int) bool { return flags[i].Start < flags[j].Start })
+	return flags
+}
+
+// passes reports whether no flag is at or above failOn
+func passes(flags []Flag, failOn string) bool {
+	for _, f := range flags {
+		if severityRank[f.Severity] >= severityRank[failOn] {
+			return false
+		}
+	}
+	return true
+}
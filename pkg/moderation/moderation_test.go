@@ -0,0 +1,82 @@
+package moderation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/vision"
+)
+
+func TestProfanitySeverity(t *testing.T) {
+	tests := map[string]string{
+		"Fucking,": SeverityHigh,
+		"bullshit": SeverityMedium,
+		"damn!":    SeverityLow,
+		"hello":    "",
+		"class":    "",
+		"Hell.":    SeverityLow,
+		"":         "",
+	}
+	for word, want := range tests {
+		if got := profanitySeverity(word); got != want {
+			t.Errorf("profanitySeverity(%q) = %q, want %q", word, got, want)
+		}
+	}
+	if got := mask("Shit!"); got != "s***" {
+		t.Errorf("mask() = %q", got)
+	}
+}
+
+func TestProfanityFlags(t *testing.T) {
+	trans := &transcript.Transcript{Segments: []transcript.Segment{
+		{Text: "Well damn, that worked.", Start: 0, End: 2, Words: []transcript.Word{
+			{Word: "Well", Start: 0, End: 0.3}, {Word: "damn,", Start: 0.3, End: 0.7}, {Word: "that", Start: 0.8, End: 1}, {Word: "worked.", Start: 1, End: 1.5},
+		}},
+		{Text: "What the fuck", Start: 3, End: 4},
+	}}
+	want := []Flag{
+		{Category: CategoryProfanity, Severity: SeverityLow, Start: 0.3, End: 0.7, Source: SourceTranscript, Detail: `said "d***"`},
+		{Category: CategoryProfanity, Severity: SeverityHigh, Start: 3, End: 4, Source: SourceTranscript, Detail: `said "f***"`},
+	}
+	if got := profanityFlags(trans); !reflect.DeepEqual(got, want) {
+		t.Errorf("profanityFlags() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFrameFlags(t *testing.T) {
+	logo := vision.ContentFlag{Category: CategoryLogo, Severity: SeverityLow, Detail: "mug", Brand: "Acme"}
+	frames := []vision.FrameModeration{
+		{Timestamp: 2, Flags: []vision.ContentFlag{logo, {Category: CategoryViolence, Severity: SeverityHigh, Detail: "fight"}}},
+		{Timestamp: 0, Flags: []vision.ContentFlag{logo}},
+		{Timestamp: 4},
+		// Two samples later the logo starts a new range
+		{Timestamp: 6, Flags: []vision.ContentFlag{{Category: CategoryLogo, Severity: SeverityMedium, Detail: "shirt", Brand: "acme"}}},
+	}
+	want := []Flag{
+		{Category: CategoryLogo, Severity: SeverityLow, Start: 0, End: 3, Source: SourceFrames, Detail: "mug", Brand: "Acme", Frames: 2},
+		{Category: CategoryViolence, Severity: SeverityHigh, Start: 1, End: 3, Source: SourceFrames, Detail: "fight", Frames: 1},
+		{Category: CategoryLogo, Severity: SeverityMedium, Start: 5, End: 6.5, Source: SourceFrames, Detail: "shirt", Brand: "acme", Frames: 1},
+	}
+	if got := frameFlags(frames, 2, 6.5); !reflect.DeepEqual(got, want) {
+		t.Errorf("frameFlags() = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestPassesAndCategories(t *testing.T) {
+	flags := []Flag{{Severity: SeverityLow}, {Severity: SeverityMedium}}
+	if !passes(flags, SeverityHigh) || passes(flags, SeverityMedium) {
+		t.Error("Expected medium flags to fail only at or below medium")
+	}
+
+	selected, err := selectCategories([]string{" Logo", "profanity"})
+	if err != nil || !reflect.DeepEqual(selected, map[string]bool{CategoryLogo: true, CategoryProfanity: true}) {
+		t.Errorf("selectCategories() = %v, %v", selected, err)
+	}
+	if all, _ := selectCategories(nil); len(all) != 4 {
+		t.Errorf("Expected every category by default, got %v", all)
+	}
+	if _, err := selectCategories([]string{"drugs"}); err == nil {
+		t.Error("Expected an error for an unknown category")
+	}
+}
@@ -0,0 +1,96 @@
+package moderation
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+)
+
+// profanity maps whole words to their severity. Short or ambiguous words
+// are only matched exactly so that "hello" or "class" are not flagged.
+var profanity = map[string]string{
+	"damn": SeverityLow, "damned": SeverityLow, "dammit": SeverityLow, "hell": SeverityLow,
+	"crap": SeverityLow, "crappy": SeverityLow, "bloody": SeverityLow, "ass": SeverityLow,
+	"piss": SeverityMedium, "pissed": SeverityMedium, "dick": SeverityMedium, "dicks": SeverityMedium,
+	"prick": SeverityMedium, "bastard": SeverityMedium, "bastards": SeverityMedium, "cock": SeverityMedium,
+	"slut": SeverityHigh, "whore": SeverityHigh, "cunt": SeverityHigh, "cunts": SeverityHigh,
+}
+
+// profanityStems flags words starting with a stem, such as "fucking", or
+// containing it when anywhere is set, such as "bullshit"
+var profanityStems = []struct {
+	stem, severity string
+	anywhere       bool // Also match inside compounds
+}{
+	{"fuck", SeverityHigh, true},
+	{"motherf", SeverityHigh, false},
+	{"shit", SeverityMedium, true},
+	{"bitch", SeverityMedium, false},
+	{"asshole", SeverityMedium, true},
+	{"dickhead", SeverityMedium, false},
+}
+
+// profanitySeverity returns the severity of a spoken word, or "" when it is
+// clean. Case and surrounding punctuation are ignored.
+func profanitySeverity(word string) string {
+	word = strings.ToLower(strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }))
+	if word == "" {
+		return ""
+	}
+	if severity, ok := profanity[word]; ok {
+		return severity
+	}
+	for _, p := range profanityStems {
+		if strings.HasPrefix(word, p.stem) || (p.anywhere && strings.Contains(word, p.stem)) {
+			return p.severity
+		}
+	}
+	return ""
+}
+
+// mask hides all but the first letter of a word
+func mask(word string) string {
+	word = strings.ToLower(strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }))
+	runes := []rune(word)
+	if len(runes) <= 1 {
+		return word
+	}
+	return string(runes[0]) + strings.Repeat("*", len(runes)-1)
+}
+
+// profanityFlags finds profane words in the transcript. Word timestamps are
+// used when present; otherwise a flag covers the whole segment.
+func profanityFlags(trans *transcript.Transcript) []Flag {
+	var flags []Flag
+	for _, seg := range trans.Segments {
+		if len(seg.Words) > 0 {
+			for _, w := range seg.Words {
+				if severity := profanitySeverity(w.Word); severity != "" {
+					flags = append(flags, Flag{
+						Category: CategoryProfanity,
+						Severity: severity,
+						Start:    w.Start,
+						End:      w.End,
+						Source:   SourceTranscript,
+						Detail:   "said \"" + mask(w.Word) + "\"",
+					})
+				}
+			}
+			continue
+		}
+		for _, word := range strings.Fields(seg.Text) {
+			if severity := profanitySeverity(word); severity != "" {
+				flags = append(flags, Flag{
+					Category: CategoryProfanity,
+					Severity: severity,
+					Start:    seg.Start,
+					End:      seg.End,
+					Source:   SourceTranscript,
+					Detail:   "said \"" + mask(word) + "\"",
+				})
+			}
+		}
+	}
+	return flags
+}
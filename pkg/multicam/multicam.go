@@ -0,0 +1,110 @@
+package multicam
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/audio"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+)
+
+// Operations aligns multicam angles and renders cuts between them
+type Operations struct {
+	ffmpeg *ffmpeg.Manager
+	audio  *audio.Operations
+}
+
+// NewOperations creates a new multicam operations handler
+func NewOperations(mgr *ffmpeg.Manager, audioOps *audio.Operations) *Operations {
+	return &Operations{ffmpeg: mgr, audio: audioOps}
+}
+
+// Angle is one camera in a multicam shoot
+type Angle struct {
+	Path       string  `json:"path"`
+	Offset     float64 `json:"offset"` // Time on the shared timeline at which this angle starts
+	Duration   float64 `json:"duration"`
+	Confidence float64 `json:"confidence"` // Audio match confidence, 1 for the reference
+}
+
+// Alignment places every angle on a shared timeline, which is the
+// reference angle's own timeline
+type Alignment struct {
+	Reference int     `json:"reference"`
+	Angles    []Angle `json:"angles"`
+	Start     float64 `json:"start"` // Span covered by every angle
+	End       float64 `json:"end"`
+}
+
+// AlignOptions contains options for aligning camera angles
+type AlignOptions struct {
+	Angles    []string
+	Reference int     // Index of the angle whose timeline is shared (default: 0)
+	MaxOffset float64 // Largest offset searched, in seconds (default: 300)
+}
+
+// AlignCameras syncs angles by cross-correlating each one's audio with the
+// reference angle's audio
+func (o *Operations) AlignCameras(ctx context.Context, opts AlignOptions) (*Alignment, error) {
+	if len(opts.Angles) < 2 {
+		return nil, fmt.Errorf("at least 2 angles are required")
+	}
+	if opts.Reference < 0 || opts.Reference >= len(opts.Angles) {
+		return nil, fmt.Errorf("reference must be between 0 and %d", len(opts.Angles)-1)
+	}
+
+	alignment := &Alignment{Reference: opts.Reference}
+	reference := opts.Angles[opts.Reference]
+	for i, path := range opts.Angles {
+		duration, err := o.ffmpeg.ProbeDuration(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		angle := Angle{Path: path, Duration: duration, Confidence: 1}
+		if i != opts.Reference {
+			result, err := o.audio.DetectOffset(ctx, reference, path, opts.MaxOffset)
+			if err != nil {
+				return nil, fmt.Errorf("failed to align %s: %w", path, err)
+			}
+			angle.Offset, angle.Confidence = result.Offset, result.Confidence
+		}
+		alignment.Angles = append(alignment.Angles, angle)
+	}
+
+	if err := alignment.span(); err != nil {
+		return nil, err
+	}
+	return alignment, nil
+}
+
+// span sets Start and End to the part of the timeline every angle covers
+func (a *Alignment) span() error {
+	for i, angle := range a.Angles {
+		if i == 0 || angle.Offset > a.Start {
+			a.Start = angle.Offset
+		}
+		if end := angle.Offset + angle.Duration; i == 0 || end < a.End {
+			a.End = end
+		}
+	}
+	if a.End <= a.Start {
+		return fmt.Errorf("the angles do not overlap in time")
+	}
+	return nil
+}
+
+// Validate checks that an alignment loaded from elsewhere is usable
+func (a *Alignment) Validate() error {
+	if len(a.Angles) < 2 {
+		return fmt.Errorf("alignment needs at least 2 angles")
+	}
+	for i, angle := range a.Angles {
+		if angle.Path == "" || angle.Duration <= 0 {
+			return fmt.Errorf("angle %d needs a path and duration", i)
+		}
+	}
+	if a.Reference < 0 || a.Reference >= len(a.Angles) {
+		return fmt.Errorf("reference must be between 0 and %d", len(a.Angles)-1)
+	}
+	return a.span()
+}
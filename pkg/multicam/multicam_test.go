@@ -0,0 +1,102 @@
+package multicam
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAlignmentSpan(t *testing.T) {
+	tests := []struct {
+		name      string
+		angles    []Angle
+		wantStart float64
+		wantEnd   float64
+		wantErr   string
+	}{
+		{
+			name: "later angle bounds the start",
+			angles: []Angle{
+				{Path: "a.mp4", Offset: 0, Duration: 60},
+				{Path: "b.mp4", Offset: 5, Duration: 60},
+			},
+			wantStart: 5,
+			wantEnd:   60,
+		},
+		{
+			name: "earlier angle bounds nothing before the reference",
+			angles: []Angle{
+				{Path: "a.mp4", Offset: 0, Duration: 60},
+				{Path: "b.mp4", Offset: -10, Duration: 40},
+			},
+			wantStart: 0,
+			wantEnd:   30,
+		},
+		{
+			name: "three angles",
+			angles: []Angle{
+				{Path: "a.mp4", Offset: 0, Duration: 100},
+				{Path: "b.mp4", Offset: 2, Duration: 90},
+				{Path: "c.mp4", Offset: -3, Duration: 80},
+			},
+			wantStart: 2,
+			wantEnd:   77,
+		},
+		{
+			name: "no overlap",
+			angles: []Angle{
+				{Path: "a.mp4", Offset: 0, Duration: 10},
+				{Path: "b.mp4", Offset: 20, Duration: 10},
+			},
+			wantErr: "do not overlap",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Alignment{Angles: tt.angles}
+			err := a.span()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("span() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("span() error = %v", err)
+			}
+			if a.Start != tt.wantStart || a.End != tt.wantEnd {
+				t.Errorf("span = %.2f-%.2f, want %.2f-%.2f", a.Start, a.End, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestAlignmentValidate(t *testing.T) {
+	angles := []Angle{{Path: "a.mp4", Duration: 10}, {Path: "b.mp4", Duration: 10}}
+
+	tests := []struct {
+		name    string
+		a       Alignment
+		wantErr string
+	}{
+		{name: "valid", a: Alignment{Angles: angles}},
+		{name: "one angle", a: Alignment{Angles: angles[:1]}, wantErr: "at least 2"},
+		{name: "missing path", a: Alignment{Angles: []Angle{{Duration: 10}, angles[1]}}, wantErr: "path and duration"},
+		{name: "bad reference", a: Alignment{Reference: 2, Angles: angles}, wantErr: "reference"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.a.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
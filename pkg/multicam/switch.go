@@ -0,0 +1,419 @@
+package multicam
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Switching modes
+const (
+	SwitchManual   = "manual"   // Follow a switch list
+	SwitchAudio    = "audio"    // Cut to the angle whose mic is loudest above its own floor
+	SwitchSpeakers = "speakers" // Cut to each speaker's angle from speaker-labelled turns
+)
+
+const (
+	levelWindow     = 0.5  // Seconds per level measurement in audio mode
+	activeThreshold = 10.0 // dB above an angle's floor that counts as speech
+	crosstalkMargin = 3.0  // dB within which two angles count as talking together
+)
+
+// Cut shows one angle over a span of the shared timeline
+type Cut struct {
+	Angle int     `json:"angle"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end,omitempty"` // default: the next cut's start
+}
+
+// Turn is a span of speech by one speaker, e.g. from a meeting transcript
+type Turn struct {
+	Speaker string  `json:"speaker"`
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+}
+
+// SwitchOptions contains options for rendering a multicam edit
+type SwitchOptions struct {
+	Alignment *Alignment
+	Output    string
+	Mode      string // manual (default when Cuts are given), audio or speakers
+
+	Cuts          []Cut          // Manual switch list on the shared timeline
+	Turns         []Turn         // Speaker turns on the shared timeline (speakers mode)
+	SpeakerAngles map[string]int // Angle for each speaker (speakers mode)
+
+	WideAngle  *int    // Angle used for silence, crosstalk and unmapped speakers
+	AudioAngle *int    // Angle whose audio is used (default: the reference)
+	MinShot    float64 // Shortest shot in seconds for automatic modes (default: 2)
+}
+
+// SwitchResult describes a rendered multicam edit
+type SwitchResult struct {
+	Mode     string  `json:"mode"`
+	Duration float64 `json:"duration"`
+	Cuts     []Cut   `json:"cuts"`
+}
+
+// SwitchCameras renders a cut between aligned angles, from a switch list or
+// by picking the active speaker's angle automatically
+func (o *Operations) SwitchCameras(ctx context.Context, opts SwitchOptions) (*SwitchResult, error) {
+	if opts.Alignment == nil {
+		return nil, fmt.Errorf("alignment is required")
+	}
+	a := opts.Alignment
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	mode := opts.Mode
+	if mode == "" {
+		mode = SwitchAudio
+		if len(opts.Cuts) > 0 {
+			mode = SwitchManual
+		}
+	}
+	for _, index := range []*int{opts.WideAngle, opts.AudioAngle} {
+		if index != nil && (*index < 0 || *index >= len(a.Angles)) {
+			return nil, fmt.Errorf("angle %d does not exist", *index)
+		}
+	}
+	minShot := opts.MinShot
+	if minShot <= 0 {
+		minShot = 2
+	}
+
+	var cuts []Cut
+	var err error
+	switch mode {
+	case SwitchManual:
+		cuts, err = manualCuts(a, opts.Cuts)
+	case SwitchAudio:
+		levels := make([][]float64, len(a.Angles))
+		for i, angle := range a.Angles {
+			if levels[i], err = o.audio.LevelEnvelope(ctx, angle.Path, levelWindow, 0); err != nil {
+				return nil, fmt.Errorf("failed to measure audio for %s: %w", angle.Path, err)
+			}
+		}
+		cuts = mergeShortCuts(choicesToCuts(chooseByLevel(a, levels, opts.WideAngle), a.Start, a.End), minShot)
+	case SwitchSpeakers:
+		cuts, err = speakerCuts(a, opts.Turns, opts.SpeakerAngles, opts.WideAngle)
+		cuts = mergeShortCuts(cuts, minShot)
+	default:
+		return nil, fmt.Errorf("unknown mode: %s (use manual, audio or speakers)", mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	audioAngle := a.Reference
+	if opts.AudioAngle != nil {
+		audioAngle = *opts.AudioAngle
+	}
+	width, height, fps, err := o.probeVideo(ctx, a.Angles[a.Reference].Path)
+	if err != nil {
+		return nil, err
+	}
+	filter, inputs := buildSwitchFilter(a, cuts, audioAngle, width, height, fps)
+
+	var args []string
+	for _, input := range inputs {
+		args = append(args, "-i", input)
+	}
+	args = append(args,
+		"-filter_complex", filter,
+		"-map", "[v]",
+		"-map", "[a]",
+		"-c:v", "libx264",
+		"-crf", "18",
+		"-pix_fmt", "yuv420p",
+		"-c:a", "aac",
+		"-b:a", "192k",
+		"-y", opts.Output,
+	)
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, err
+	}
+
+	return &SwitchResult{Mode: mode, Duration: cuts[len(cuts)-1].End - cuts[0].Start, Cuts: cuts}, nil
+}
+
+// manualCuts sorts a switch list, fills in open ends and clips it to the
+// span every angle covers
+func manualCuts(a *Alignment, cuts []Cut) ([]Cut, error) {
+	if len(cuts) == 0 {
+		return nil, fmt.Errorf("manual mode needs a switch list")
+	}
+	sorted := append([]Cut(nil), cuts...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var out []Cut
+	for i, cut := range sorted {
+		if cut.Angle < 0 || cut.Angle >= len(a.Angles) {
+			return nil, fmt.Errorf("cut %d uses angle %d, which does not exist", i+1, cut.Angle)
+		}
+		end := cut.End
+		if end <= 0 {
+			end = a.End
+			if i < len(sorted)-1 {
+				end = sorted[i+1].Start
+			}
+		}
+		cut.Start, cut.End = math.Max(cut.Start, a.Start), math.Min(end, a.End)
+		if cut.End > cut.Start {
+			out = append(out, cut)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no cuts fall within %.2f-%.2fs, where all angles overlap", a.Start, a.End)
+	}
+	return out, nil
+}
+
+// chooseByLevel picks an angle for each level window from the alignment's
+// start. Each angle's level is measured against its own noise floor so mic
+// gain does not matter; silence and crosstalk go to the wide angle when
+// there is one, and otherwise hold the previous choice.
+func chooseByLevel(a *Alignment, levels [][]float64, wide *int) []int {
+	floors := make([]float64, len(levels))
+	for i, l := range levels {
+		floors[i] = percentile(l, 0.2)
+	}
+
+	windows := int(math.Ceil((a.End - a.Start) / levelWindow))
+	choices := make([]int, windows)
+	previous := a.Reference
+	if wide != nil {
+		previous = *wide
+	}
+	for w := range choices {
+		t := a.Start + (float64(w)+0.5)*levelWindow
+		best, second := -1, -1
+		scores := make([]float64, len(levels))
+		for i, l := range levels {
+			index := int((t - a.Angles[i].Offset) / levelWindow)
+			scores[i] = math.Inf(-1)
+			if index >= 0 && index < len(l) {
+				scores[i] = l[index] - floors[i]
+			}
+			switch {
+			case best < 0 || scores[i] > scores[best]:
+				best, second = i, best
+			case second < 0 || scores[i] > scores[second]:
+				second = i
+			}
+		}
+
+		quiet := scores[best] < activeThreshold
+		crosstalk := second >= 0 && scores[best]-scores[second] < crosstalkMargin && scores[second] >= activeThreshold
+		switch {
+		case (quiet || crosstalk) && wide != nil:
+			choices[w] = *wide
+		case quiet || crosstalk:
+			choices[w] = previous
+		default:
+			choices[w] = best
+		}
+		previous = choices[w]
+	}
+	return choices
+}
+
+// choicesToCuts turns per-window angle choices into cuts between start and end
+func choicesToCuts(choices []int, start, end float64) []Cut {
+	var cuts []Cut
+	for w, angle := range choices {
+		t := start + float64(w)*levelWindow
+		if len(cuts) > 0 && cuts[len(cuts)-1].Angle == angle {
+			continue
+		}
+		if len(cuts) > 0 {
+			cuts[len(cuts)-1].End = t
+		}
+		cuts = append(cuts, Cut{Angle: angle, Start: t})
+	}
+	if len(cuts) > 0 {
+		cuts[len(cuts)-1].End = end
+	}
+	return cuts
+}
+
+// speakerCuts cuts to each speaker's angle when their turn starts and holds
+// it through gaps until the next turn
+func speakerCuts(a *Alignment, turns []Turn, speakerAngles map[string]int, wide *int) ([]Cut, error) {
+	if len(turns) == 0 {
+		return nil, fmt.Errorf("speakers mode needs speaker turns")
+	}
+	if len(speakerAngles) == 0 {
+		return nil, fmt.Errorf("speakers mode needs a speaker-to-angle mapping")
+	}
+	for speaker, angle := range speakerAngles {
+		if angle < 0 || angle >= len(a.Angles) {
+			return nil, fmt.Errorf("speaker %s is mapped to angle %d, which does not exist", speaker, angle)
+		}
+	}
+	sorted := append([]Turn(nil), turns...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	fallback := a.Reference
+	if wide != nil {
+		fallback = *wide
+	}
+	cuts := []Cut{{Angle: fallback, Start: a.Start}}
+	for _, turn := range sorted {
+		if turn.Start >= a.End {
+			break
+		}
+		angle, ok := speakerAngles[turn.Speaker]
+		if !ok {
+			angle = fallback
+		}
+		last := &cuts[len(cuts)-1]
+		switch {
+		case angle == last.Angle:
+		case turn.Start <= last.Start:
+			// Turns before the overlap, or at its very start, set the opening angle
+			last.Angle = angle
+		default:
+			last.End = turn.Start
+			cuts = append(cuts, Cut{Angle: angle, Start: turn.Start})
+		}
+	}
+	cuts[len(cuts)-1].End = a.End
+	return cuts, nil
+}
+
+// mergeShortCuts folds shots shorter than minShot into the shot before them
+// (or after, for the first) and joins neighbours that end up on one angle
+func mergeShortCuts(cuts []Cut, minShot float64) []Cut {
+	cuts = append([]Cut(nil), cuts...)
+	for len(cuts) > 1 {
+		shortest := -1
+		for i, c := range cuts {
+			if c.End-c.Start < minShot && (shortest < 0 || c.End-c.Start < cuts[shortest].End-cuts[shortest].Start) {
+				shortest = i
+			}
+		}
+		if shortest < 0 {
+			break
+		}
+		if shortest > 0 {
+			cuts[shortest-1].End = cuts[shortest].End
+		} else {
+			cuts[1].Start = cuts[0].Start
+		}
+		cuts = append(cuts[:shortest], cuts[shortest+1:]...)
+
+		// Join neighbours on the same angle
+		joined := cuts[:1]
+		for _, c := range cuts[1:] {
+			if last := &joined[len(joined)-1]; last.Angle == c.Angle {
+				last.End = c.End
+			} else {
+				joined = append(joined, c)
+			}
+		}
+		cuts = joined
+	}
+	return cuts
+}
+
+// buildSwitchFilter trims each cut from its angle, conforms it to width x
+// height at fps and concatenates the cuts, with audio from one angle over
+// the same span. It returns the filter and the input files in order. The
+// result is labelled [v] and [a].
+func buildSwitchFilter(a *Alignment, cuts []Cut, audioAngle, width, height int, fps string) (string, []string) {
+	// Only angles that are shown or heard become inputs
+	inputIndex := map[int]int{}
+	var inputs []string
+	uses := map[int]int{}
+	addInput := func(angle int) {
+		if _, ok := inputIndex[angle]; !ok {
+			inputIndex[angle] = len(inputs)
+			inputs = append(inputs, a.Angles[angle].Path)
+		}
+	}
+	for _, c := range cuts {
+		addInput(c.Angle)
+		uses[c.Angle]++
+	}
+	addInput(audioAngle)
+
+	var parts []string
+	angles := make([]int, 0, len(uses))
+	for angle := range uses {
+		angles = append(angles, angle)
+	}
+	sort.Ints(angles)
+	for _, angle := range angles {
+		in := inputIndex[angle]
+		if uses[angle] == 1 {
+			parts = append(parts, fmt.Sprintf("[%d:v]null[v%d_0]", in, angle))
+			continue
+		}
+		labels := ""
+		for j := 0; j < uses[angle]; j++ {
+			labels += fmt.Sprintf("[v%d_%d]", angle, j)
+		}
+		parts = append(parts, fmt.Sprintf("[%d:v]split=%d%s", in, uses[angle], labels))
+	}
+
+	used := map[int]int{}
+	var concat strings.Builder
+	for k, c := range cuts {
+		offset := a.Angles[c.Angle].Offset
+		parts = append(parts, fmt.Sprintf(
+			"[v%d_%d]trim=start=%.3f:end=%.3f,setpts=PTS-STARTPTS,scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1,fps=%s,format=yuv420p[c%d]",
+			c.Angle, used[c.Angle], c.Start-offset, c.End-offset, width, height, width, height, fps, k))
+		used[c.Angle]++
+		concat.WriteString(fmt.Sprintf("[c%d]", k))
+	}
+	parts = append(parts, fmt.Sprintf("%sconcat=n=%d:v=1:a=0[v]", concat.String(), len(cuts)))
+
+	offset := a.Angles[audioAngle].Offset
+	parts = append(parts, fmt.Sprintf("[%d:a]atrim=start=%.3f:end=%.3f,asetpts=PTS-STARTPTS[a]",
+		inputIndex[audioAngle], cuts[0].Start-offset, cuts[len(cuts)-1].End-offset))
+
+	return strings.Join(parts, ";"), inputs
+}
+
+// percentile returns the p-th quantile (0-1) of values
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return sorted[int(p*float64(len(sorted)-1))]
+}
+
+// probeVideo returns the width, height and frame rate of a file's first
+// video stream. The frame rate is kept as FFmpeg's fraction.
+func (o *Operations) probeVideo(ctx context.Context, input string) (int, int, string, error) {
+	output, err := o.ffmpeg.Probe(ctx,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height,r_frame_rate",
+		"-of", "csv=p=0",
+		input,
+	)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("failed to probe video: %w", err)
+	}
+	fields := strings.Split(strings.TrimSpace(output), ",")
+	if len(fields) < 3 {
+		return 0, 0, "", fmt.Errorf("could not read video stream of %s", input)
+	}
+	width, err1 := strconv.Atoi(fields[0])
+	height, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil || width <= 0 || height <= 0 {
+		return 0, 0, "", fmt.Errorf("could not determine dimensions of %s", input)
+	}
+	fps := fields[2]
+	if fps == "" || strings.HasPrefix(fps, "0/") {
+		fps = "30"
+	}
+	return width, height, fps, nil
+}
@@ -0,0 +1,200 @@
+package multicam
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func twoAngles() *Alignment {
+	return &Alignment{
+		Angles: []Angle{
+			{Path: "wide.mp4", Offset: 0, Duration: 20},
+			{Path: "close.mp4", Offset: 2, Duration: 20},
+		},
+		Start: 2,
+		End:   20,
+	}
+}
+
+func TestManualCuts(t *testing.T) {
+	got, err := manualCuts(twoAngles(), []Cut{
+		{Angle: 1, Start: 10},
+		{Angle: 0, Start: 0},
+		{Angle: 0, Start: 15, End: 30},
+	})
+	if err != nil {
+		t.Fatalf("manualCuts() error = %v", err)
+	}
+	want := []Cut{
+		{Angle: 0, Start: 2, End: 10},
+		{Angle: 1, Start: 10, End: 15},
+		{Angle: 0, Start: 15, End: 20},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("manualCuts() = %+v, want %+v", got, want)
+	}
+
+	if _, err := manualCuts(twoAngles(), []Cut{{Angle: 3}}); err == nil {
+		t.Error("expected error for unknown angle")
+	}
+	if _, err := manualCuts(twoAngles(), []Cut{{Angle: 0, Start: 25}}); err == nil {
+		t.Error("expected error for cuts outside the overlap")
+	}
+}
+
+func TestChooseByLevel(t *testing.T) {
+	a := &Alignment{
+		Angles: []Angle{
+			{Path: "wide.mp4", Duration: 4},
+			{Path: "a.mp4", Duration: 4},
+			{Path: "b.mp4", Duration: 4},
+		},
+		Start: 0,
+		End:   4,
+	}
+	// 8 windows: A talks, B talks, both talk, then silence
+	quiet := -60.0
+	levels := [][]float64{
+		{-40, -40, -40, -40, -40, -40, -40, -40},
+		{-20, -20, quiet, quiet, -20, -20, quiet, quiet},
+		{quiet, quiet, -20, -20, -21, -21, quiet, quiet},
+	}
+	// Floors need quiet windows to be measured against
+	for i := range levels[1:] {
+		levels[i+1] = append(levels[i+1], quiet, quiet, quiet, quiet)
+	}
+
+	wide := 0
+	got := chooseByLevel(a, levels, &wide)
+	want := []int{1, 1, 2, 2, 0, 0, 0, 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("with wide angle = %v, want %v", got, want)
+	}
+
+	got = chooseByLevel(a, levels, nil)
+	want = []int{1, 1, 2, 2, 2, 2, 2, 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("without wide angle = %v, want %v", got, want)
+	}
+}
+
+func TestChoicesToCuts(t *testing.T) {
+	got := choicesToCuts([]int{1, 1, 2, 2, 2, 1}, 10, 13)
+	want := []Cut{
+		{Angle: 1, Start: 10, End: 11},
+		{Angle: 2, Start: 11, End: 12.5},
+		{Angle: 1, Start: 12.5, End: 13},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("choicesToCuts() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSpeakerCuts(t *testing.T) {
+	a := twoAngles()
+	turns := []Turn{
+		{Speaker: "SPEAKER_01", Start: 6, End: 9},
+		{Speaker: "SPEAKER_00", Start: 0, End: 5},
+		{Speaker: "SPEAKER_02", Start: 12, End: 14},
+		{Speaker: "SPEAKER_00", Start: 16, End: 25},
+	}
+	speakers := map[string]int{"SPEAKER_00": 0, "SPEAKER_01": 1}
+
+	got, err := speakerCuts(a, turns, speakers, nil)
+	if err != nil {
+		t.Fatalf("speakerCuts() error = %v", err)
+	}
+	want := []Cut{
+		{Angle: 0, Start: 2, End: 6},
+		{Angle: 1, Start: 6, End: 12},
+		{Angle: 0, Start: 12, End: 20},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("speakerCuts() = %+v, want %+v", got, want)
+	}
+
+	if _, err := speakerCuts(a, turns, map[string]int{"SPEAKER_00": 5}, nil); err == nil {
+		t.Error("expected error for unknown angle")
+	}
+}
+
+func TestMergeShortCuts(t *testing.T) {
+	tests := []struct {
+		name string
+		cuts []Cut
+		want []Cut
+	}{
+		{
+			name: "short shot folds into the previous one",
+			cuts: []Cut{{0, 0, 5}, {1, 5, 6}, {2, 6, 10}},
+			want: []Cut{{0, 0, 6}, {2, 6, 10}},
+		},
+		{
+			name: "short first shot folds into the next one",
+			cuts: []Cut{{0, 0, 1}, {1, 1, 6}},
+			want: []Cut{{1, 0, 6}},
+		},
+		{
+			name: "flicker back to the same angle joins up",
+			cuts: []Cut{{0, 0, 5}, {1, 5, 5.5}, {0, 5.5, 10}},
+			want: []Cut{{0, 0, 10}},
+		},
+		{
+			name: "long shots are kept",
+			cuts: []Cut{{0, 0, 3}, {1, 3, 6}},
+			want: []Cut{{0, 0, 3}, {1, 3, 6}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeShortCuts(tt.cuts, 2); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeShortCuts() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSwitchFilter(t *testing.T) {
+	a := &Alignment{
+		Angles: []Angle{
+			{Path: "wide.mp4", Offset: 0, Duration: 30},
+			{Path: "close.mp4", Offset: 2, Duration: 30},
+			{Path: "unused.mp4", Offset: 1, Duration: 30},
+		},
+		Start: 2,
+		End:   30,
+	}
+	cuts := []Cut{{0, 2, 10}, {1, 10, 15}, {0, 15, 20}}
+
+	filter, inputs := buildSwitchFilter(a, cuts, 0, 1920, 1080, "30000/1001")
+
+	if want := []string{"wide.mp4", "close.mp4"}; !reflect.DeepEqual(inputs, want) {
+		t.Errorf("inputs = %v, want %v", inputs, want)
+	}
+	for _, want := range []string{
+		"[0:v]split=2[v0_0][v0_1]",
+		"[1:v]null[v1_0]",
+		"[v0_0]trim=start=2.000:end=10.000",
+		"[v1_0]trim=start=8.000:end=13.000",
+		"[v0_1]trim=start=15.000:end=20.000",
+		"scale=1920:1080:force_original_aspect_ratio=decrease",
+		"fps=30000/1001",
+		"[c0][c1][c2]concat=n=3:v=1:a=0[v]",
+		"[0:a]atrim=start=2.000:end=20.000,asetpts=PTS-STARTPTS[a]",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("filter missing %q:\n%s", want, filter)
+		}
+	}
+
+	// Audio from an angle that is never shown still becomes an input
+	filter, inputs = buildSwitchFilter(a, cuts, 2, 1280, 720, "25")
+	if len(inputs) != 3 || inputs[2] != "unused.mp4" {
+		t.Errorf("inputs = %v, want unused.mp4 added for audio", inputs)
+	}
+	if want := "[2:a]atrim=start=1.000:end=19.000"; !strings.Contains(filter, want) {
+		t.Errorf("filter missing %q:\n%s", want, filter)
+	}
+}
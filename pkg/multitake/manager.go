@@ -76,6 +76,12 @@ func NewManager(baseDir string) *Manager {
 	return &Manager{baseDir: baseDir}
 }
 
+// SetBaseDir moves later reads and writes to baseDir, e.g. when another
+// workspace project becomes active. Empty means the default directory.
+func (m *Manager) SetBaseDir(baseDir string) {
+	*m = *NewManager(baseDir)
+}
+
 // Initialize creates the projects directory
 func (m *Manager) Initialize() error {
 	return os.MkdirAll(m.baseDir, 0755)
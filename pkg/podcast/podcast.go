@@ -0,0 +1,276 @@
+// Package podcast exports the audio of a recording as a feed-ready podcast
+// episode: loudness normalized, encoded and tagged with artwork and chapters
+package podcast
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/chapters"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+)
+
+const (
+	// truePeak leaves headroom for the lossy encoder, as Apple recommends
+	truePeak = -1.5
+	// artworkSize is the embedded cover size; feeds link the full 3000px art
+	artworkSize = 1400
+)
+
+// codecs maps episode formats to the audio encoder
+var codecs = map[string]string{
+	"mp3": "libmp3lame",
+	"m4a": "aac",
+}
+
+// Options contains options for exporting a podcast episode
+type Options struct {
+	Input       string
+	Output      string  // .mp3 or .m4a
+	TargetLUFS  float64 // Integrated loudness (default: -16, the podcast standard)
+	Bitrate     int     // kbps (default: 128, or 64 for mono)
+	Mono        bool    // Downmix, e.g. for a single voice
+	Title       string
+	Artist      string // Host or author
+	Show        string // Podcast name, stored as the album
+	Episode     int    // Stored as the track number
+	Date        string // Release year or date
+	Description string
+	Genre       string // Default: Podcast
+	Artwork     string // Cover image, cropped square
+	Chapters    []chapters.Chapter
+}
+
+// Result describes the exported episode
+type Result struct {
+	Output     string             `json:"output"`
+	Duration   float64            `json:"duration"`
+	InputLUFS  float64            `json:"inputLufs"`
+	InputPeak  float64            `json:"inputPeak"` // dBTP
+	TargetLUFS float64            `json:"targetLufs"`
+	Bitrate    int                `json:"bitrate"`
+	Bytes      int64              `json:"bytes"`
+	Chapters   []chapters.Chapter `json:"chapters,omitempty"`
+	HasArtwork bool               `json:"hasArtwork"`
+}
+
+// loudness holds the first loudnorm pass's measurements, as FFmpeg prints them
+type loudness struct {
+	InputI       string `json:"input_i"`
+	InputTP      string `json:"input_tp"`
+	InputLRA     string `json:"input_lra"`
+	InputThresh  string `json:"input_thresh"`
+	TargetOffset string `json:"target_offset"`
+}
+
+// Exporter exports podcast episodes
+type Exporter struct {
+	ffmpeg   *ffmpeg.Manager
+	videoOps *video.Operations
+}
+
+// NewExporter creates a podcast exporter
+func NewExporter(mgr *ffmpeg.Manager, videoOps *video.Operations) *Exporter {
+	return &Exporter{ffmpeg: mgr, videoOps: videoOps}
+}
+
+// Export measures the input's loudness, then encodes its first audio track
+// normalized to the target in a second, linear loudnorm pass, so the level
+// is exact without the pumping of single-pass normalization
+func (e *Exporter) Export(ctx context.Context, opts Options) (*Result, error) {
+	if opts.TargetLUFS == 0 {
+		opts.TargetLUFS = -16
+	}
+	if opts.TargetLUFS < -30 || opts.TargetLUFS > -10 {
+		return nil, fmt.Errorf("targetLufs must be between -30 and -10")
+	}
+	info, err := e.videoOps.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media info: %w", err)
+	}
+	if !info.HasAudio {
+		return nil, fmt.Errorf("no audio stream found in %s", opts.Input)
+	}
+	if len(opts.Chapters) > 0 {
+		if opts.Chapters, err = chapters.Markers(opts.Chapters, info.Duration); err != nil {
+			return nil, err
+		}
+	}
+
+	measured, err := e.measure(ctx, opts.Input, opts.TargetLUFS)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, cleanup, err := scratch.Dir("podcast")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	metadataFile := ""
+	if len(opts.Chapters) > 0 {
+		metadataFile = filepath.Join(dir, "chapters.ffmetadata")
+		if err := os.WriteFile(metadataFile, []byte(chapters.FormatFFMetadata(opts.Chapters)), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write chapter metadata: %w", err)
+		}
+	}
+
+	args, bitrate, err := buildExportArgs(opts, measured, metadataFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Output:     opts.Output,
+		Duration:   info.Duration,
+		TargetLUFS: opts.TargetLUFS,
+		Bitrate:    bitrate,
+		Chapters:   opts.Chapters,
+		HasArtwork: opts.Artwork != "",
+	}
+	result.InputLUFS, _ = strconv.ParseFloat(measured.InputI, 64)
+	result.InputPeak, _ = strconv.ParseFloat(measured.InputTP, 64)
+	if stat, err := os.Stat(opts.Output); err == nil {
+		result.Bytes = stat.Size()
+	}
+	return result, nil
+}
+
+// measure runs loudnorm's analysis pass over the first audio track
+func (e *Exporter) measure(ctx context.Context, input string, target float64) (*loudness, error) {
+	output, err := e.ffmpeg.ExecuteWithOutput(ctx,
+		"-hide_banner",
+		"-nostats",
+		"-i", input,
+		"-map", "0:a:0",
+		"-af", fmt.Sprintf("loudnorm=I=%g:TP=%g:LRA=11:print_format=json", target, truePeak),
+		"-f", "null", "-",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure loudness: %w", err)
+	}
+	return parseLoudness(output)
+}
+
+// parseLoudness reads the JSON block loudnorm prints at the end of its log
+func parseLoudness(output string) (*loudness, error) {
+	start := strings.LastIndex(output, "{")
+	end := strings.LastIndex(output, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("loudness measurements not found in FFmpeg output")
+	}
+	var measured loudness
+	if err := json.Unmarshal([]byte(output[start:end+1]), &measured); err != nil {
+		return nil, fmt.Errorf("failed to parse loudness measurements: %w", err)
+	}
+	if measured.InputI == "" || strings.Contains(measured.InputI, "inf") {
+		return nil, fmt.Errorf("the audio is silent")
+	}
+	return &measured, nil
+}
+
+// buildExportArgs returns the FFmpeg arguments for the encoding pass and the
+// bitrate used. Inputs are the source, then the artwork and chapter
+// metadata when given.
+func buildExportArgs(opts Options, measured *loudness, metadataFile string) ([]string, int, error) {
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(opts.Output), "."))
+	codec, ok := codecs[format]
+	if !ok {
+		return nil, 0, fmt.Errorf("unsupported podcast format %q (use .mp3 or .m4a)", filepath.Ext(opts.Output))
+	}
+	bitrate := opts.Bitrate
+	if bitrate == 0 {
+		bitrate = 128
+		if opts.Mono {
+			bitrate = 64
+		}
+	}
+	if bitrate < 32 || bitrate > 320 {
+		return nil, 0, fmt.Errorf("bitrate must be between 32 and 320 kbps")
+	}
+	channels := "2"
+	if opts.Mono {
+		channels = "1"
+	}
+
+	args := []string{"-i", opts.Input}
+	artIndex, chapterIndex := -1, -1
+	if opts.Artwork != "" {
+		artIndex = 1
+		args = append(args, "-i", opts.Artwork)
+	}
+	if metadataFile != "" {
+		chapterIndex = 1 + max(artIndex, 0)
+		args = append(args, "-f", "ffmetadata", "-i", metadataFile)
+	}
+
+	// loudnorm works at 192 kHz internally, so the output is resampled
+	args = append(args,
+		"-map", "0:a:0",
+		"-af", fmt.Sprintf("loudnorm=I=%g:TP=%g:LRA=11:measured_I=%s:measured_TP=%s:measured_LRA=%s:measured_thresh=%s:offset=%s:linear=true",
+			opts.TargetLUFS, truePeak, measured.InputI, measured.InputTP, measured.InputLRA, measured.InputThresh, measured.TargetOffset),
+		"-ar", "44100",
+		"-ac", channels,
+		"-c:a", codec,
+		"-b:a", fmt.Sprintf("%dk", bitrate),
+	)
+	if artIndex > 0 {
+		args = append(args,
+			"-map", fmt.Sprintf("%d:v:0", artIndex),
+			"-c:v", "mjpeg",
+			"-vf", fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d,format=yuvj420p", artworkSize, artworkSize, artworkSize, artworkSize),
+			"-frames:v", "1",
+			"-disposition:v", "attached_pic",
+			"-metadata:s:v", "title=Album cover",
+			"-metadata:s:v", "comment=Cover (front)",
+		)
+	}
+
+	// Source tags such as a camera's are dropped; chapters come only from
+	// the chapter list
+	args = append(args, "-map_metadata", "-1")
+	if chapterIndex > 0 {
+		args = append(args, "-map_chapters", strconv.Itoa(chapterIndex))
+	} else {
+		args = append(args, "-map_chapters", "-1")
+	}
+
+	genre := opts.Genre
+	if genre == "" {
+		genre = "Podcast"
+	}
+	tags := [][2]string{
+		{"title", opts.Title},
+		{"artist", opts.Artist},
+		{"album", opts.Show},
+		{"genre", genre},
+		{"date", opts.Date},
+		{"comment", opts.Description},
+	}
+	if opts.Episode > 0 {
+		tags = append(tags, [2]string{"track", strconv.Itoa(opts.Episode)})
+	}
+	for _, tag := range tags {
+		if tag[1] != "" {
+			args = append(args, "-metadata", tag[0]+"="+tag[1])
+		}
+	}
+
+	if format == "mp3" {
+		// ID3v2.3 is what podcast apps read most reliably, chapters included
+		args = append(args, "-id3v2_version", "3", "-write_id3v1", "1")
+	} else {
+		args = append(args, "-movflags", "+faststart")
+	}
+	return append(args, "-y", opts.Output), bitrate, nil
+}
@@ -0,0 +1,94 @@
+package podcast
+
+import (
+	"strings"
+	"testing"
+)
+
+const loudnormLog = `[Parsed_loudnorm_0 @ 0x55d]
+{
+	"input_i" : "-23.41",
+	"input_tp" : "-4.20",
+	"input_lra" : "7.10",
+	"input_thresh" : "-33.80",
+	"output_i" : "-16.02",
+	"output_tp" : "-1.50",
+	"output_lra" : "6.20",
+	"output_thresh" : "-26.40",
+	"normalization_type" : "dynamic",
+	"target_offset" : "0.02"
+}
+size=N/A time=00:30:00.00 bitrate=N/A speed= 412x`
+
+func TestParseLoudness(t *testing.T) {
+	measured, err := parseLoudness(loudnormLog)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if measured.InputI != "-23.41" || measured.InputThresh != "-33.80" || measured.TargetOffset != "0.02" {
+		t.Errorf("Measured %+v", measured)
+	}
+	if _, err := parseLoudness(`{"input_i" : "-inf", "input_tp" : "-inf"}`); err == nil {
+		t.Error("Expected an error for silent audio")
+	}
+	if _, err := parseLoudness("Conversion failed!"); err == nil {
+		t.Error("Expected an error without measurements")
+	}
+}
+
+func TestBuildExportArgs(t *testing.T) {
+	measured, err := parseLoudness(loudnormLog)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	args, bitrate, err := buildExportArgs(Options{
+		Input:      "episode.mp4",
+		Output:     "episode.mp3",
+		TargetLUFS: -16,
+		Mono:       true,
+		Title:      "Shipping on Fridays",
+		Show:       "Release Notes",
+		Episode:    42,
+		Artwork:    "cover.png",
+	}, measured, "chapters.ffmetadata")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bitrate != 64 {
+		t.Errorf("Mono bitrate = %d, want 64", bitrate)
+	}
+	joined := strings.Join(args, " ")
+	for _, want := range []string{
+		"-i episode.mp4 -i cover.png -f ffmetadata -i chapters.ffmetadata -map 0:a:0",
+		"loudnorm=I=-16:TP=-1.5:LRA=11:measured_I=-23.41:measured_TP=-4.20:measured_LRA=7.10:measured_thresh=-33.80:offset=0.02:linear=true",
+		"-ar 44100 -ac 1 -c:a libmp3lame -b:a 64k",
+		"-map 1:v:0 -c:v mjpeg",
+		"-disposition:v attached_pic",
+		"-map_metadata -1 -map_chapters 2",
+		"-metadata title=Shipping on Fridays -metadata album=Release Notes -metadata genre=Podcast -metadata track=42",
+		"-id3v2_version 3 -write_id3v1 1 -y episode.mp3",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Arguments %q missing %q", joined, want)
+		}
+	}
+
+	args, bitrate, err = buildExportArgs(Options{Input: "episode.wav", Output: "episode.m4a", TargetLUFS: -16}, measured, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	joined = strings.Join(args, " ")
+	if bitrate != 128 || !strings.Contains(joined, "-ac 2 -c:a aac -b:a 128k") || !strings.Contains(joined, "-map_chapters -1") ||
+		!strings.Contains(joined, "-movflags +faststart") || strings.Contains(joined, "attached_pic") {
+		t.Errorf("M4A arguments %q", joined)
+	}
+
+	for _, opts := range []Options{
+		{Output: "episode.wav"},
+		{Output: "episode.mp3", Bitrate: 16},
+	} {
+		if _, _, err := buildExportArgs(opts, measured, ""); err == nil {
+			t.Errorf("Expected an error for %+v", opts)
+		}
+	}
+}
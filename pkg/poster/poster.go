@@ -0,0 +1,409 @@
+// Package poster designs thumbnails and posters from a video frame with
+// text, shapes, a logo and a border
+package poster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/elements"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/text"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+)
+
+// Size is a thumbnail size and the largest file the platform accepts
+type Size struct {
+	Width    int
+	Height   int
+	MaxBytes int64 // 0 for no limit
+}
+
+// Sizes maps platform names to thumbnail sizes
+var Sizes = map[string]Size{
+	"youtube":            {Width: 1280, Height: 720, MaxBytes: 2 << 20},
+	"instagram":          {Width: 1080, Height: 1080},
+	"instagram_portrait": {Width: 1080, Height: 1350},
+	"story":              {Width: 1080, Height: 1920}, // Shorts, Reels and TikTok covers
+	"twitter":            {Width: 1600, Height: 900, MaxBytes: 5 << 20},
+	"facebook":           {Width: 1200, Height: 630},
+	"linkedin":           {Width: 1200, Height: 627},
+}
+
+// SizeNames returns the size preset names in sorted order
+func SizeNames() []string {
+	names := make([]string, 0, len(Sizes))
+	for name := range Sizes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BorderNames are the border presets, thinnest first
+var BorderNames = []string{"none", "thin", "thick", "double"}
+
+// Text is a line or block of text on the thumbnail
+type Text struct {
+	Text         string
+	Position     string // top-left, top-center, top-right, center-left, center, center-right, bottom-left, bottom-center or bottom-right (default: center)
+	FontFile     string // Font name or file
+	FontSize     int    // Pixels (default: an eighth of the height)
+	Color        string // Default: white
+	OutlineColor string // Default: black
+	OutlineWidth *int   // Default: a fifteenth of the font size
+	Box          bool   // Draw a box behind the text
+	BoxColor     string // Default: black
+}
+
+// Logo is an image placed in a corner of the thumbnail
+type Logo struct {
+	Image    string  `json:"image"`
+	Position string  `json:"position"` // As for Text (default: bottom-right)
+	Width    float64 `json:"width"`    // Fraction of the thumbnail width (default: 0.15)
+}
+
+// Options contains options for designing a thumbnail
+type Options struct {
+	Input       string
+	Output      string
+	Timestamp   float64                 // Frame to start from, in seconds
+	Size        string                  // One of SizeNames (default: youtube)
+	Filter      string                  // Look applied to the frame, such as the video's filter chain
+	Texts       []Text                  // Drawn last, over everything but the border
+	Shapes      []elements.ShapeOptions // In thumbnail pixels; Input and Output are ignored
+	Logo        *Logo
+	Border      string // One of BorderNames (default: none)
+	BorderColor string // Default: white
+	Quality     int    // 1-100 for jpg and webp (default: 92, lowered to fit the platform's limit)
+}
+
+// Result describes a designed thumbnail
+type Result struct {
+	Output  string `json:"output"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Bytes   int64  `json:"bytes"`
+	Quality int    `json:"quality,omitempty"`
+}
+
+// Designer builds thumbnails with the video, text and elements operations
+type Designer struct {
+	videoOps    *video.Operations
+	textOps     *text.Operations
+	elementsOps *elements.Operations
+}
+
+// NewDesigner creates a new thumbnail designer
+func NewDesigner(videoOps *video.Operations, textOps *text.Operations, elementsOps *elements.Operations) *Designer {
+	return &Designer{
+		videoOps:    videoOps,
+		textOps:     textOps,
+		elementsOps: elementsOps,
+	}
+}
+
+// Create grabs the frame, fills it to the platform's size and adds the
+// shapes, logo, text and border in that order, each as a lossless layer,
+// before encoding the result
+func (d *Designer) Create(ctx context.Context, opts Options) (*Result, error) {
+	size, ok := Sizes[sizeName(opts.Size)]
+	if !ok {
+		return nil, fmt.Errorf("unknown size %q (use %s)", opts.Size, strings.Join(SizeNames(), ", "))
+	}
+	borders, err := borderShapes(opts.Border, opts.BorderColor, size)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range opts.Texts {
+		if strings.TrimSpace(t.Text) == "" {
+			return nil, fmt.Errorf("text elements need text")
+		}
+	}
+
+	dir, cleanup, err := scratch.Dir("poster")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	step := 0
+	next := func() string {
+		step++
+		return filepath.Join(dir, fmt.Sprintf("layer_%02d.png", step))
+	}
+
+	filters := []string{}
+	if opts.Filter != "" {
+		filters = append(filters, opts.Filter)
+	}
+	filters = append(filters, fillFilter(size))
+	frame := next()
+	if err := d.videoOps.ExportStill(ctx, video.StillOptions{
+		Input:     opts.Input,
+		Output:    frame,
+		Timestamp: opts.Timestamp,
+		Filter:    strings.Join(filters, ","),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to grab frame: %w", err)
+	}
+	current := frame
+
+	for i, shape := range opts.Shapes {
+		shape.Input, shape.Output = current, next()
+		if err := d.elementsOps.DrawShape(ctx, shape); err != nil {
+			return nil, fmt.Errorf("failed to draw shape %d: %w", i+1, err)
+		}
+		current = shape.Output
+	}
+
+	if opts.Logo != nil {
+		overlay, err := logoOverlay(*opts.Logo, size)
+		if err != nil {
+			return nil, err
+		}
+		overlay.Input, overlay.Output = current, next()
+		if err := d.elementsOps.AddImageOverlay(ctx, overlay); err != nil {
+			return nil, fmt.Errorf("failed to add logo: %w", err)
+		}
+		current = overlay.Output
+	}
+
+	for i, t := range opts.Texts {
+		overlay, err := textOverlay(t, size)
+		if err != nil {
+			return nil, err
+		}
+		overlay.Input, overlay.Output = current, next()
+		if err := d.textOps.AddTextOverlay(ctx, overlay); err != nil {
+			return nil, fmt.Errorf("failed to add text %d: %w", i+1, err)
+		}
+		current = overlay.Output
+	}
+
+	for _, border := range borders {
+		border.Input, border.Output = current, next()
+		if err := d.elementsOps.DrawShape(ctx, border); err != nil {
+			return nil, fmt.Errorf("failed to draw border: %w", err)
+		}
+		current = border.Output
+	}
+
+	return d.encode(ctx, current, opts, size)
+}
+
+// encode writes the finished layer to the output, lowering the quality of
+// a lossy format step by step until it fits the platform's size limit
+func (d *Designer) encode(ctx context.Context, layer string, opts Options, size Size) (*Result, error) {
+	quality := opts.Quality
+	if quality == 0 {
+		quality = 92
+	}
+	ext := strings.ToLower(filepath.Ext(opts.Output))
+	lossy := ext == ".jpg" || ext == ".jpeg" || ext == ".webp"
+	for {
+		if err := d.videoOps.ExportStill(ctx, video.StillOptions{
+			Input:   layer,
+			Output:  opts.Output,
+			Quality: quality,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to write thumbnail: %w", err)
+		}
+		info, err := os.Stat(opts.Output)
+		if err != nil {
+			return nil, err
+		}
+		result := &Result{Output: opts.Output, Width: size.Width, Height: size.Height, Bytes: info.Size()}
+		if lossy {
+			result.Quality = quality
+		}
+		if size.MaxBytes == 0 || info.Size() <= size.MaxBytes {
+			return result, nil
+		}
+		if !lossy || quality <= 50 {
+			return nil, fmt.Errorf("thumbnail is %.1f MB, over the %s limit of %.0f MB; use jpg or a lower quality",
+				float64(info.Size())/(1<<20), sizeName(opts.Size), float64(size.MaxBytes)/(1<<20))
+		}
+		quality = max(quality-10, 50)
+	}
+}
+
+// sizeName returns the size preset name, youtube unless set
+func sizeName(name string) string {
+	if name == "" {
+		return "youtube"
+	}
+	return strings.ToLower(name)
+}
+
+// fillFilter scales the frame to cover the thumbnail and crops the overflow
+// evenly, so nothing is letterboxed
+func fillFilter(size Size) string {
+	return fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=increase:flags=lanczos,crop=%d:%d,setsar=1",
+		size.Width, size.Height, size.Width, size.Height)
+}
+
+// place returns x and y expressions that put an object of size objW x objH
+// at a named position, margin pixels in from the edges of a frame of size
+// frameW x frameH
+func place(position string, margin int, frameW, frameH, objW, objH string) (string, string, error) {
+	parts := strings.SplitN(position, "-", 2)
+	vertical, horizontal := parts[0], "center"
+	if len(parts) == 2 {
+		horizontal = parts[1]
+	}
+	if position == "center" {
+		vertical = "center"
+	}
+
+	var x, y string
+	switch horizontal {
+	case "left":
+		x = fmt.Sprintf("%d", margin)
+	case "center":
+		x = fmt.Sprintf("(%s-%s)/2", frameW, objW)
+	case "right":
+		x = fmt.Sprintf("%s-%s-%d", frameW, objW, margin)
+	default:
+		return "", "", fmt.Errorf("unknown position %q", position)
+	}
+	switch vertical {
+	case "top":
+		y = fmt.Sprintf("%d", margin)
+	case "center":
+		y = fmt.Sprintf("(%s-%s)/2", frameH, objH)
+	case "bottom":
+		y = fmt.Sprintf("%s-%s-%d", frameH, objH, margin)
+	default:
+		return "", "", fmt.Errorf("unknown position %q", position)
+	}
+	return x, y, nil
+}
+
+// textOverlay returns the drawtext options for a text element: bold
+// outlined lettering wrapped to the safe area, as thumbnails need to read
+// at a small size
+func textOverlay(t Text, size Size) (text.TextOverlayOptions, error) {
+	position := t.Position
+	if position == "" {
+		position = "center"
+	}
+	margin := size.Height / 20
+	x, y, err := place(position, margin, "w", "h", "text_w", "text_h")
+	if err != nil {
+		return text.TextOverlayOptions{}, err
+	}
+	fontSize := t.FontSize
+	if fontSize == 0 {
+		fontSize = size.Height / 8
+	}
+	outline := fontSize / 15
+	if t.OutlineWidth != nil {
+		outline = *t.OutlineWidth
+	}
+	align := text.AlignCenter
+	if strings.HasSuffix(position, "left") {
+		align = text.AlignLeft
+	} else if strings.HasSuffix(position, "right") {
+		align = text.AlignRight
+	}
+	shadow := max(fontSize/20, 2)
+
+	return text.TextOverlayOptions{
+		Text:           t.Text,
+		X:              x,
+		Y:              y,
+		FontFile:       t.FontFile,
+		FontSize:       fontSize,
+		FontColor:      colorOr(t.Color, "white"),
+		BorderWidth:    outline,
+		BorderColor:    colorOr(t.OutlineColor, "black"),
+		ShadowX:        &shadow,
+		ShadowY:        &shadow,
+		ShadowColor:    "black@0.6",
+		Box:            t.Box,
+		BoxColor:       colorOr(t.BoxColor, "black"),
+		BoxOpacity:     0.7,
+		BoxBorderWidth: fontSize / 4,
+		MaxWidth:       size.Width - 2*margin,
+		Align:          align,
+	}, nil
+}
+
+// logoOverlay returns the overlay options for the logo
+func logoOverlay(logo Logo, size Size) (elements.ImageOverlayOptions, error) {
+	if logo.Image == "" {
+		return elements.ImageOverlayOptions{}, fmt.Errorf("logo needs an image")
+	}
+	fraction := logo.Width
+	if fraction == 0 {
+		fraction = 0.15
+	}
+	if fraction <= 0 || fraction > 1 {
+		return elements.ImageOverlayOptions{}, fmt.Errorf("logo width must be a fraction of the thumbnail width up to 1")
+	}
+	position := logo.Position
+	if position == "" {
+		position = "bottom-right"
+	}
+	x, y, err := place(position, size.Height/24, "W", "H", "w", "h")
+	if err != nil {
+		return elements.ImageOverlayOptions{}, err
+	}
+	width := int(fraction*float64(size.Width)) / 2 * 2
+	height := -1
+	animated := false
+	return elements.ImageOverlayOptions{
+		Image:    logo.Image,
+		X:        &x,
+		Y:        &y,
+		Width:    &width,
+		Height:   &height,
+		Animated: &animated,
+	}, nil
+}
+
+// borderShapes returns the outlines that draw a border preset around the
+// thumbnail, scaled to its height
+func borderShapes(preset, color string, size Size) ([]elements.ShapeOptions, error) {
+	frame := func(inset, thickness int, color string) elements.ShapeOptions {
+		w, h := size.Width-2*inset, size.Height-2*inset
+		return elements.ShapeOptions{
+			Shape:       "rectangle",
+			X:           inset,
+			Y:           inset,
+			Width:       &w,
+			Height:      &h,
+			Color:       color,
+			BorderWidth: thickness,
+		}
+	}
+	color = colorOr(color, "white")
+	unit := size.Height
+	switch preset {
+	case "", "none":
+		return nil, nil
+	case "thin":
+		return []elements.ShapeOptions{frame(0, max(unit/90, 2), color)}, nil
+	case "thick":
+		return []elements.ShapeOptions{frame(0, max(unit/36, 4), color)}, nil
+	case "double":
+		outer := max(unit/48, 3)
+		return []elements.ShapeOptions{
+			frame(0, outer, color),
+			frame(outer+max(unit/120, 2), max(unit/240, 1), color),
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown border %q (use %s)", preset, strings.Join(BorderNames, ", "))
+}
+
+// colorOr returns color, or fallback when it is empty
+func colorOr(color, fallback string) string {
+	if color == "" {
+		return fallback
+	}
+	return color
+}
@@ -0,0 +1,77 @@
+package poster
+
+import (
+	"testing"
+)
+
+func TestPlace(t *testing.T) {
+	tests := []struct {
+		position string
+		x, y     string
+	}{
+		{"top-left", "36", "36"},
+		{"center", "(w-text_w)/2", "(h-text_h)/2"},
+		{"bottom-right", "w-text_w-36", "h-text_h-36"},
+		{"center-left", "36", "(h-text_h)/2"},
+		{"top-center", "(w-text_w)/2", "36"},
+	}
+	for _, tt := range tests {
+		x, y, err := place(tt.position, 36, "w", "h", "text_w", "text_h")
+		if err != nil {
+			t.Errorf("place(%q) error: %v", tt.position, err)
+			continue
+		}
+		if x != tt.x || y != tt.y {
+			t.Errorf("place(%q) = %q, %q, want %q, %q", tt.position, x, y, tt.x, tt.y)
+		}
+	}
+	for _, position := range []string{"middle", "top-middle", "left-top"} {
+		if _, _, err := place(position, 36, "w", "h", "text_w", "text_h"); err == nil {
+			t.Errorf("Expected an error for position %q", position)
+		}
+	}
+}
+
+func TestTextOverlay(t *testing.T) {
+	overlay, err := textOverlay(Text{Text: "HOW I SHIP", Position: "top-left"}, Sizes["youtube"])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if overlay.FontSize != 90 || overlay.BorderWidth != 6 || overlay.FontColor != "white" {
+		t.Errorf("Defaults: size %d, outline %d, color %s", overlay.FontSize, overlay.BorderWidth, overlay.FontColor)
+	}
+	if overlay.MaxWidth != 1280-72 || overlay.Align != "left" || overlay.X != "36" {
+		t.Errorf("Layout: max width %d, align %s, x %s", overlay.MaxWidth, overlay.Align, overlay.X)
+	}
+}
+
+func TestBorderShapes(t *testing.T) {
+	size := Sizes["youtube"]
+	shapes, err := borderShapes("double", "", size)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(shapes) != 2 {
+		t.Fatalf("Double border has %d outlines", len(shapes))
+	}
+	outer, inner := shapes[0], shapes[1]
+	if outer.X != 0 || *outer.Width != 1280 || outer.BorderWidth != 15 || outer.Color != "white" {
+		t.Errorf("Outer outline %+v", outer)
+	}
+	if inner.X != 21 || *inner.Width != 1280-42 || *inner.Height != 720-42 || inner.BorderWidth != 3 {
+		t.Errorf("Inner outline at %d, %dx%d, %d thick", inner.X, *inner.Width, *inner.Height, inner.BorderWidth)
+	}
+	if shapes, _ := borderShapes("none", "red", size); len(shapes) != 0 {
+		t.Errorf("No border drew %d outlines", len(shapes))
+	}
+	if _, err := borderShapes("neon", "", size); err == nil {
+		t.Error("Expected an error for an unknown border")
+	}
+}
+
+func TestFillFilter(t *testing.T) {
+	want := "scale=1080:1920:force_original_aspect_ratio=increase:flags=lanczos,crop=1080:1920,setsar=1"
+	if got := fillFilter(Sizes["story"]); got != want {
+		t.Errorf("fillFilter = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,325 @@
+// Package reframe converts landscape video to portrait or square by moving
+// a crop window with the main subject
+package reframe
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/vision"
+)
+
+// Subject tracking engines
+const (
+	EngineAuto   = "auto"   // Vision when a provider is configured, otherwise motion
+	EngineVision = "vision" // Face and person positions from the vision model
+	EngineMotion = "motion" // Edge detail and motion, no API calls
+	EngineFollow = "follow" // A supplied box track, reported when Options.Follow is set
+)
+
+// Aspects maps supported aspect ratios to output resolutions
+var Aspects = map[string][2]int{
+	"9:16": {1080, 1920},
+	"1:1":  {1080, 1080},
+}
+
+// Options contains parameters for reframing a video
+type Options struct {
+	Input    string
+	Output   string  // Default: <input>_9x16.mp4 or <input>_1x1.mp4
+	Aspect   string  // 9:16 (default) or 1:1
+	Engine   string  // auto (default), vision or motion
+	Interval float64 // Seconds between sampled frames (default: duration/120, at least 1)
+	Quality  string  // high, medium, low (default: high)
+
+	// Follow keeps the crop on a tracked object, such as a track from
+	// TrackObject, instead of finding the speaker
+	Follow *vision.BoxTrack
+}
+
+// TrackOptions selects what to track for a crop of the given aspect ratio
+type TrackOptions struct {
+	Aspect   float64 // Crop width divided by height
+	Engine   string  // auto (default), vision or motion
+	Interval float64 // Seconds between sampled frames (default: length/120, at least 1)
+	Start    float64 // Section of the input to track (default: all of it)
+	End      float64 // 0 for the end of the input
+}
+
+// Track is a camera path that keeps the subject inside the crop window
+type Track struct {
+	Engine   string                  `json:"engine"`
+	Path     []video.ReframeKeyframe `json:"path"`               // Times are relative to the tracked section's start
+	Samples  int                     `json:"samples,omitempty"`  // Frames sent to the vision model
+	Detected int                     `json:"detected,omitempty"` // Frames where the vision model found a subject
+	Warnings []string                `json:"warnings,omitempty"`
+}
+
+// Result describes a reframed video
+type Result struct {
+	Output    string                  `json:"output"`
+	Aspect    string                  `json:"aspect"`
+	Width     int                     `json:"width"`
+	Height    int                     `json:"height"`
+	CropWidth int                     `json:"cropWidth"` // In source pixels
+	Engine    string                  `json:"engine"`
+	Samples   int                     `json:"samples,omitempty"`
+	Detected  int                     `json:"detected,omitempty"`
+	Path      []video.ReframeKeyframe `json:"path"`
+	Warnings  []string                `json:"warnings,omitempty"`
+}
+
+// Reframer tracks subjects and renders cropped video
+type Reframer struct {
+	ffmpeg   *ffmpeg.Manager
+	videoOps *video.Operations
+	vision   *vision.Analyzer
+}
+
+// NewReframer creates a reframer
+func NewReframer(mgr *ffmpeg.Manager, videoOps *video.Operations, visionAnalyzer *vision.Analyzer) *Reframer {
+	return &Reframer{
+		ffmpeg:   mgr,
+		videoOps: videoOps,
+		vision:   visionAnalyzer,
+	}
+}
+
+// Reframe tracks the main subject and renders the input cropped to the
+// target aspect ratio, following the subject with an interpolated crop
+func (r *Reframer) Reframe(ctx context.Context, opts Options) (*Result, error) {
+	if opts.Aspect == "" {
+		opts.Aspect = "9:16"
+	}
+	target, ok := Aspects[opts.Aspect]
+	if !ok {
+		return nil, fmt.Errorf("unsupported aspect ratio: %s (use 9:16 or 1:1)", opts.Aspect)
+	}
+	if opts.Output == "" {
+		opts.Output = fmt.Sprintf("%s_%s.mp4", strings.TrimSuffix(opts.Input, filepath.Ext(opts.Input)), strings.ReplaceAll(opts.Aspect, ":", "x"))
+	}
+
+	info, err := r.videoOps.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+	if info.Width <= 0 || info.Height <= 0 {
+		return nil, fmt.Errorf("could not determine video dimensions")
+	}
+	targetAspect := float64(target[0]) / float64(target[1])
+	if float64(info.Width)/float64(info.Height) <= targetAspect+0.01 {
+		return nil, fmt.Errorf("video is already %s or narrower (%dx%d)", opts.Aspect, info.Width, info.Height)
+	}
+
+	var track *Track
+	if opts.Follow != nil {
+		if len(opts.Follow.Keyframes) == 0 {
+			return nil, fmt.Errorf("track %d has no keyframes to follow", opts.Follow.ID)
+		}
+		track = &Track{Engine: EngineFollow, Path: followPath(opts.Follow.Keyframes)}
+	} else {
+		track, err = r.Track(ctx, opts.Input, TrackOptions{Aspect: targetAspect, Engine: opts.Engine, Interval: opts.Interval})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	filter, cropWidth := CropFilter(track.Path, info.Width, info.Height, target[0], target[1])
+	if err := r.ffmpeg.Execute(ctx,
+		"-i", opts.Input,
+		"-vf", filter,
+		"-map", "0:v:0", "-map", "0:a?",
+		"-c:v", "libx264",
+		"-crf", fmt.Sprintf("%d", qualityCRF(opts.Quality)),
+		"-preset", "medium",
+		"-pix_fmt", "yuv420p",
+		"-c:a", "aac",
+		"-b:a", "192k",
+		"-movflags", "+faststart",
+		"-y", opts.Output,
+	); err != nil {
+		return nil, fmt.Errorf("failed to render: %w", err)
+	}
+
+	return &Result{
+		Output:    opts.Output,
+		Aspect:    opts.Aspect,
+		Width:     target[0],
+		Height:    target[1],
+		CropWidth: cropWidth,
+		Engine:    track.Engine,
+		Samples:   track.Samples,
+		Detected:  track.Detected,
+		Path:      track.Path,
+		Warnings:  track.Warnings,
+	}, nil
+}
+
+// Track finds a camera path for a crop of opts.Aspect. With the auto engine,
+// vision tracking falls back to motion when no provider is configured or no
+// subject is found.
+func (r *Reframer) Track(ctx context.Context, input string, opts TrackOptions) (*Track, error) {
+	_, providerErr := r.vision.Provider()
+	engine, err := resolveEngine(opts.Engine, providerErr == nil)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := r.videoOps.GetVideoInfo(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+	if info.Width <= 0 || info.Height <= 0 || info.Duration <= 0 {
+		return nil, fmt.Errorf("could not determine video dimensions or duration")
+	}
+	end := opts.End
+	if end <= 0 || end > info.Duration {
+		end = info.Duration
+	}
+	if opts.Start < 0 || opts.Start >= end {
+		return nil, fmt.Errorf("invalid range: %.2fs to %.2fs", opts.Start, end)
+	}
+
+	track := &Track{Engine: engine}
+	if engine == EngineVision {
+		interval := opts.Interval
+		if interval <= 0 {
+			interval = math.Max(1, (end-opts.Start)/120)
+		}
+		var timestamps []float64
+		for t := 0.0; opts.Start+t < end; t += interval {
+			timestamps = append(timestamps, opts.Start+t)
+		}
+
+		positions, err := r.vision.LocateSubject(ctx, input, timestamps)
+		if err == nil {
+			cropFraction := opts.Aspect * float64(info.Height) / float64(info.Width)
+			centers, detected := subjectCenters(positions, cropFraction)
+			track.Samples, track.Detected = len(timestamps), detected
+			if detected > 0 {
+				track.Path = video.BuildReframePath(centers, interval)
+				if detected < len(timestamps) {
+					track.Warnings = append(track.Warnings, fmt.Sprintf("no subject found in %d of %d frames; held the last position", len(timestamps)-detected, len(timestamps)))
+				}
+				return track, nil
+			}
+			err = fmt.Errorf("no subject found in %d frames", len(timestamps))
+		}
+		if strings.EqualFold(opts.Engine, EngineVision) {
+			return nil, fmt.Errorf("vision tracking failed: %w", err)
+		}
+		track.Warnings = append(track.Warnings, fmt.Sprintf("vision tracking failed, using motion: %v", err))
+		track.Engine, track.Samples, track.Detected = EngineMotion, 0, 0
+	}
+
+	path, err := r.videoOps.AnalyzeReframeRange(ctx, input, opts.Aspect, opts.Start, end)
+	if err != nil {
+		return nil, fmt.Errorf("motion tracking failed: %w", err)
+	}
+	track.Path = path
+	return track, nil
+}
+
+// CropFilter returns the filter that crops a source frame to follow path
+// and scales it to width x height, plus the crop width in source pixels
+func CropFilter(path []video.ReframeKeyframe, sourceWidth, sourceHeight, width, height int) (string, int) {
+	cropWidth := int(math.Round(float64(sourceHeight)*float64(width)/float64(height)/2)) * 2
+	return fmt.Sprintf("crop=%d:%d:x='%s':y=0,scale=%d:%d,setsar=1",
+		cropWidth, sourceHeight, video.BuildReframeCropX(path, sourceWidth, cropWidth), width, height), cropWidth
+}
+
+// resolveEngine picks the tracking engine for the request
+func resolveEngine(engine string, hasProvider bool) (string, error) {
+	switch strings.ToLower(engine) {
+	case "", EngineAuto:
+		if hasProvider {
+			return EngineVision, nil
+		}
+		return EngineMotion, nil
+	case EngineVision:
+		if !hasProvider {
+			return "", fmt.Errorf("vision tracking needs a configured vision provider")
+		}
+		return EngineVision, nil
+	case EngineMotion:
+		return EngineMotion, nil
+	default:
+		return "", fmt.Errorf("unknown engine: %s (use auto, vision or motion)", engine)
+	}
+}
+
+// subjectCenters turns subject positions into crop centers, as fractions of
+// the source width. The crop is centered on the face, then shifted to keep
+// the head and shoulders inside it when they fit, and kept inside the frame.
+// Frames without a subject hold the nearest earlier position (or the first
+// found, before any). It also returns how many frames had a subject.
+func subjectCenters(positions []vision.SubjectPosition, cropFraction float64) ([]float64, int) {
+	half := math.Min(0.5, cropFraction/2)
+	centers := make([]float64, len(positions))
+	found := make([]bool, len(positions))
+	detected := 0
+	for i, p := range positions {
+		if !p.Found {
+			continue
+		}
+		center := p.Face
+		if p.Right-p.Left <= 2*half {
+			if p.Left < center-half {
+				center = p.Left + half
+			}
+			if p.Right > center+half {
+				center = p.Right - half
+			}
+		}
+		centers[i] = math.Max(half, math.Min(1-half, center))
+		found[i] = true
+		detected++
+	}
+	if detected == 0 {
+		return nil, 0
+	}
+
+	first := -1
+	for i := range centers {
+		if found[i] {
+			if first < 0 {
+				first = i
+			}
+			continue
+		}
+		if first >= 0 {
+			centers[i] = centers[i-1]
+		}
+	}
+	for i := 0; i < first; i++ {
+		centers[i] = centers[first]
+	}
+	return centers, detected
+}
+
+// followPath centers the crop on each keyframe's box. The crop filter keeps
+// the window inside the frame and holds the ends of the path.
+func followPath(keyframes []vision.BoxKeyframe) []video.ReframeKeyframe {
+	path := make([]video.ReframeKeyframe, len(keyframes))
+	for i, k := range keyframes {
+		path[i] = video.ReframeKeyframe{Time: k.Time, Center: k.X + k.W/2}
+	}
+	return path
+}
+
+// qualityCRF maps a quality name to an x264 CRF
+func qualityCRF(quality string) int {
+	switch quality {
+	case "medium":
+		return 23
+	case "low":
+		return 28
+	default:
+		return 18
+	}
+}
@@ -0,0 +1,90 @@
+package reframe
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/vision"
+)
+
+func TestResolveEngine(t *testing.T) {
+	tests := []struct {
+		engine      string
+		hasProvider bool
+		want        string
+		wantErr     string
+	}{
+		{engine: "", hasProvider: true, want: EngineVision},
+		{engine: "auto", hasProvider: false, want: EngineMotion},
+		{engine: "Vision", hasProvider: true, want: EngineVision},
+		{engine: "vision", hasProvider: false, wantErr: "needs a configured vision provider"},
+		{engine: "motion", hasProvider: true, want: EngineMotion},
+		{engine: "yolo", hasProvider: true, wantErr: "unknown engine"},
+	}
+	for _, tt := range tests {
+		got, err := resolveEngine(tt.engine, tt.hasProvider)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("resolveEngine(%q) error = %v, want %q", tt.engine, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("resolveEngine(%q, %v) = %q, %v; want %q", tt.engine, tt.hasProvider, got, err, tt.want)
+		}
+	}
+}
+
+func TestSubjectCenters(t *testing.T) {
+	positions := []vision.SubjectPosition{
+		{},
+		{Found: true, Face: 0.5, Left: 0.45, Right: 0.55},
+		// Shoulders reach past the right edge of a crop centered on the face
+		{Found: true, Face: 0.6, Left: 0.5, Right: 0.8},
+		{},
+		// Near the frame edge the crop stops at the border
+		{Found: true, Face: 0.05, Left: 0, Right: 0.1},
+		// Wider than the crop: stay on the face
+		{Found: true, Face: 0.4, Left: 0.1, Right: 0.9},
+	}
+
+	centers, detected := subjectCenters(positions, 0.4)
+	want := []float64{0.5, 0.5, 0.6, 0.6, 0.2, 0.4}
+	if detected != 4 || len(centers) != len(want) {
+		t.Fatalf("subjectCenters() = %v, %d", centers, detected)
+	}
+	for i := range want {
+		if math.Abs(centers[i]-want[i]) > 1e-9 {
+			t.Errorf("centers[%d] = %v, want %v", i, centers[i], want[i])
+		}
+	}
+
+	if centers, detected := subjectCenters([]vision.SubjectPosition{{}, {}}, 0.4); centers != nil || detected != 0 {
+		t.Errorf("Expected no centers without a subject, got %v, %d", centers, detected)
+	}
+}
+
+func TestCropFilter(t *testing.T) {
+	filter, cropWidth := CropFilter([]video.ReframeKeyframe{{Time: 0, Center: 0.5}}, 1920, 1080, 1080, 1920)
+	if cropWidth != 608 {
+		t.Errorf("cropWidth = %d, want 608", cropWidth)
+	}
+	if filter != "crop=608:1080:x='656.0000':y=0,scale=1080:1920,setsar=1" {
+		t.Errorf("CropFilter() = %q", filter)
+	}
+}
+
+func TestFollowPath(t *testing.T) {
+	path := followPath([]vision.BoxKeyframe{
+		{Time: 2, Box: vision.Box{X: 0.1, Y: 0.4, W: 0.2, H: 0.2}},
+		{Time: 4, Box: vision.Box{X: 0.6, Y: 0.4, W: 0.1, H: 0.1}},
+	})
+	want := []video.ReframeKeyframe{{Time: 2, Center: 0.2}, {Time: 4, Center: 0.65}}
+	for i := range want {
+		if path[i].Time != want[i].Time || math.Abs(path[i].Center-want[i].Center) > 1e-9 {
+			t.Errorf("path[%d] = %+v, want %+v", i, path[i], want[i])
+		}
+	}
+}
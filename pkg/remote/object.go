@@ -0,0 +1,210 @@
+package remote
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty body
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// target is the HTTP location of a remote URI and how to sign requests to it
+type target struct {
+	url         *url.URL
+	key         string       // Object key or URL path
+	credentials *credentials // nil for unsigned requests
+}
+
+// credentials sign requests with AWS Signature Version 4, which S3 and
+// S3-compatible stores (including GCS with HMAC keys) accept
+type credentials struct {
+	accessKey string
+	secret    string
+	token     string
+	region    string
+	service   string
+}
+
+// resolve maps a URI to the URL to request. S3 uses virtual-hosted
+// addressing on AWS and path-style addressing on a custom endpoint; GCS uses
+// its XML API. Without credentials, requests are unsigned so public objects
+// and presigned URLs still work.
+func (s *Store) resolve(uri string) (*target, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URI %s: %w", uri, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid URI %s: missing host or bucket", uri)
+	}
+
+	switch u.Scheme {
+	case SchemeHTTP, SchemeHTTPS:
+		return &target{url: u, key: u.Path}, nil
+
+	case SchemeS3:
+		bucket, key := u.Host, strings.TrimPrefix(u.Path, "/")
+		region := s.config.S3Region
+		if region == "" {
+			region = "us-east-1"
+		}
+		var endpoint *url.URL
+		if s.config.S3Endpoint != "" {
+			if endpoint, err = url.Parse(s.config.S3Endpoint); err != nil {
+				return nil, fmt.Errorf("invalid s3Endpoint: %w", err)
+			}
+			endpoint = objectURL(endpoint, "/"+bucket+"/"+key)
+		} else if strings.Contains(bucket, ".") {
+			// Dotted bucket names don't match the wildcard certificate
+			endpoint = objectURL(&url.URL{Scheme: "https", Host: "s3." + region + ".amazonaws.com"}, "/"+bucket+"/"+key)
+		} else {
+			endpoint = objectURL(&url.URL{Scheme: "https", Host: bucket + ".s3." + region + ".amazonaws.com"}, "/"+key)
+		}
+		t := &target{url: endpoint, key: key}
+		if s.config.S3AccessKeyID != "" && s.config.S3SecretAccessKey != "" {
+			t.credentials = &credentials{
+				accessKey: s.config.S3AccessKeyID,
+				secret:    s.config.S3SecretAccessKey,
+				token:     s.config.S3SessionToken,
+				region:    region,
+				service:   "s3",
+			}
+		}
+		return t, nil
+
+	case SchemeGCS:
+		bucket, key := u.Host, strings.TrimPrefix(u.Path, "/")
+		base, err := url.Parse(s.gcsEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		t := &target{url: objectURL(base, "/"+bucket+"/"+key), key: key}
+		if s.config.GCSAccessKeyID != "" && s.config.GCSSecret != "" {
+			t.credentials = &credentials{
+				accessKey: s.config.GCSAccessKeyID,
+				secret:    s.config.GCSSecret,
+				region:    "auto",
+				service:   "s3",
+			}
+		}
+		return t, nil
+	}
+	return nil, fmt.Errorf("unsupported URI scheme: %s (use http, https, s3 or gs)", u.Scheme)
+}
+
+// objectURL appends an object path to an endpoint, escaped as SigV4 expects
+func objectURL(endpoint *url.URL, objectPath string) *url.URL {
+	u := *endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + objectPath
+	u.RawPath = escapePath(u.Path)
+	return &u
+}
+
+// sign adds SigV4 headers to req. Unsigned targets are left alone.
+func (t *target) sign(req *http.Request, payloadHash string, now time.Time) {
+	if t.credentials != nil {
+		t.credentials.sign(req, payloadHash, now)
+	}
+}
+
+// sign adds the X-Amz-* and Authorization headers, signing the host and
+// every header already set on req
+func (c *credentials) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if c.token != "" {
+		req.Header.Set("X-Amz-Security-Token", c.token)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + c.region + "/" + c.service + "/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+c.secret), date)
+	key = hmacSHA256(key, c.region)
+	key = hmacSHA256(key, c.service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery sorts and escapes query parameters
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		vs := append([]string(nil), values[k]...)
+		sort.Strings(vs)
+		for _, v := range vs {
+			parts = append(parts, escape(k, true)+"="+escape(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// escapePath escapes an object path, keeping the slashes
+func escapePath(p string) string {
+	return escape(p, false)
+}
+
+// escape percent-encodes everything except RFC 3986 unreserved characters,
+// and slashes unless encodeSlash is set
+func escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
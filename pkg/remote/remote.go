@@ -0,0 +1,332 @@
+// Package remote lets tools read inputs from and write outputs to
+// http(s)://, s3:// and gs:// URIs. Inputs are downloaded into a managed
+// cache and revalidated by ETag; outputs are rendered locally and uploaded.
+package remote
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+)
+
+// Supported URI schemes
+const (
+	SchemeHTTP  = "http"
+	SchemeHTTPS = "https"
+	SchemeS3    = "s3"
+	SchemeGCS   = "gs"
+)
+
+// defaultCacheMB caps the download cache when remoteCacheMb is unset
+const defaultCacheMB = 10240
+
+// metaFile records where a cache entry came from
+const metaFile = ".remote.json"
+
+// IsRemote reports whether path is an s3:// or gs:// URI, or an http(s)
+// URL whose path names a file
+func IsRemote(path string) bool {
+	u, err := url.Parse(path)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	switch u.Scheme {
+	case SchemeS3, SchemeGCS:
+		return true
+	case SchemeHTTP, SchemeHTTPS:
+		// Bare sites such as https://example.com are text, not media
+		return pathExt(u.Path) != ""
+	}
+	return false
+}
+
+// Store downloads remote inputs into a cache and uploads outputs
+type Store struct {
+	config      *config.Config
+	client      *http.Client
+	gcsEndpoint string
+	now         func() time.Time
+	locks       sync.Map // Cache entry -> *sync.Mutex
+}
+
+// NewStore creates a store. Credentials and the cache location are read
+// from cfg on each call so set_config changes apply immediately.
+func NewStore(cfg *config.Config) *Store {
+	return &Store{
+		config:      cfg,
+		client:      &http.Client{},
+		gcsEndpoint: "https://storage.googleapis.com",
+		now:         time.Now,
+	}
+}
+
+// CacheDir returns the download cache directory
+func (s *Store) CacheDir() string {
+	if s.config.RemoteCacheDir != "" {
+		return s.config.RemoteCacheDir
+	}
+	return filepath.Join(s.config.TempPath(), "mcp-video-remote")
+}
+
+// cacheMeta is the metadata kept next to a cached download
+type cacheMeta struct {
+	URI          string `json:"uri"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Size         int64  `json:"size"`
+	Fetched      string `json:"fetched"`
+}
+
+// Fetch downloads uri into the cache and returns the local path, keeping
+// the remote file name. A cached copy is revalidated with its ETag or
+// Last-Modified date and reused when unchanged.
+func (s *Store) Fetch(ctx context.Context, uri string) (string, error) {
+	target, err := s.resolve(uri)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(uri))
+	entry := filepath.Join(s.CacheDir(), hex.EncodeToString(sum[:8]))
+	lock, _ := s.locks.LoadOrStore(entry, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	name := path.Base(target.key)
+	if name == "." || name == "/" || name == "" {
+		name = "download"
+	}
+	local := filepath.Join(entry, name)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.url.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	var meta cacheMeta
+	if _, err := os.Stat(local); err == nil {
+		if data, err := os.ReadFile(filepath.Join(entry, metaFile)); err == nil && json.Unmarshal(data, &meta) == nil {
+			if meta.ETag != "" {
+				req.Header.Set("If-None-Match", meta.ETag)
+			}
+			if meta.LastModified != "" {
+				req.Header.Set("If-Modified-Since", meta.LastModified)
+			}
+		}
+	}
+	target.sign(req, emptyPayloadHash, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", uri, err)
+	}
+	defer resp.Body.Close()
+
+	now := s.now()
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		os.Chtimes(entry, now, now)
+		return local, nil
+	case http.StatusOK:
+	default:
+		return "", fmt.Errorf("failed to download %s: %s", uri, responseError(resp))
+	}
+
+	if err := os.MkdirAll(entry, 0755); err != nil {
+		return "", fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	// Write to a temporary name so an interrupted download is not reused
+	tmp := local + ".part"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return "", err
+	}
+	size, err := io.Copy(file, resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to download %s: %w", uri, err)
+	}
+	if err := os.Rename(tmp, local); err != nil {
+		return "", err
+	}
+
+	meta = cacheMeta{
+		URI:          uri,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Size:         size,
+		Fetched:      now.UTC().Format(time.RFC3339),
+	}
+	if data, err := json.MarshalIndent(meta, "", "  "); err == nil {
+		os.WriteFile(filepath.Join(entry, metaFile), data, 0644)
+	}
+	os.Chtimes(entry, now, now)
+	s.prune(entry)
+	return local, nil
+}
+
+// Upload writes a local file to uri
+func (s *Store) Upload(ctx context.Context, localPath, uri string) error {
+	target, err := s.resolve(uri)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	payloadHash := emptyPayloadHash
+	if target.credentials != nil {
+		hash := sha256.New()
+		if _, err := io.Copy(hash, file); err != nil {
+			return err
+		}
+		payloadHash = hex.EncodeToString(hash.Sum(nil))
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.url.String(), file)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	if contentType := mime.TypeByExtension(filepath.Ext(localPath)); contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	target.sign(req, payloadHash, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload to %s: %w", uri, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to upload to %s: %s", uri, responseError(resp))
+	}
+	return nil
+}
+
+// UploadDir uploads every file under dir to uri as a prefix and returns the
+// URIs written
+func (s *Store) UploadDir(ctx context.Context, dir, uri string) ([]string, error) {
+	var uploaded []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		dest := JoinURI(uri, filepath.ToSlash(rel))
+		if err := s.Upload(ctx, p, dest); err != nil {
+			return err
+		}
+		uploaded = append(uploaded, dest)
+		return nil
+	})
+	return uploaded, err
+}
+
+// JoinURI appends a relative path to a URI prefix
+func JoinURI(uri, rel string) string {
+	return strings.TrimSuffix(uri, "/") + "/" + strings.TrimPrefix(rel, "/")
+}
+
+// prune removes the least recently used cache entries until the cache fits
+// in remoteCacheMb, never removing keep
+func (s *Store) prune(keep string) {
+	limit := s.config.RemoteCacheMB
+	if limit <= 0 {
+		limit = defaultCacheMB
+	}
+	entries, err := os.ReadDir(s.CacheDir())
+	if err != nil {
+		return
+	}
+
+	type cached struct {
+		path string
+		used time.Time
+		size int64
+	}
+	var all []cached
+	var total int64
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		c := cached{path: filepath.Join(s.CacheDir(), e.Name()), used: info.ModTime()}
+		filepath.WalkDir(c.path, func(_ string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				if fi, err := d.Info(); err == nil {
+					c.size += fi.Size()
+				}
+			}
+			return nil
+		})
+		total += c.size
+		all = append(all, c)
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].used.Before(all[j].used) })
+	for _, c := range all {
+		if float64(total) <= limit*1024*1024 {
+			break
+		}
+		if c.path == keep {
+			continue
+		}
+		if os.RemoveAll(c.path) == nil {
+			total -= c.size
+		}
+	}
+}
+
+// responseError summarizes a failed response, including the provider's
+// error message when it is short
+func responseError(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 300))
+	if msg := strings.TrimSpace(string(body)); msg != "" {
+		return fmt.Sprintf("HTTP %d: %s", resp.StatusCode, msg)
+	}
+	return fmt.Sprintf("HTTP %d", resp.StatusCode)
+}
+
+// pathExt returns the extension of a URL path's last element
+func pathExt(p string) string {
+	if ext := path.Ext(path.Base(p)); ext != "." {
+		return ext
+	}
+	return ""
+}
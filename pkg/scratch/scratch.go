@@ -0,0 +1,184 @@
+// Package scratch gives each operation its own temporary directory under
+// one root, so concurrent tool calls never share a file name and leftovers
+// from failed or interrupted runs are easy to find and remove
+package scratch
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// rootName is the folder holding every scratch directory
+const rootName = "mcp-video-editor-tmp"
+
+// StaleAge is how old a scratch directory must be before the server removes
+// it at startup: no operation runs that long, so it was left by a crash
+const StaleAge = 24 * time.Hour
+
+// legacyEntries are fixed names earlier versions left in the system temp
+// directory
+var legacyEntries = []string{"concat_list.txt", ".mcp-diagram-temp"}
+
+var (
+	mu     sync.Mutex
+	base   string              // Parent of the root; empty means os.TempDir()
+	active = map[string]bool{} // Directories of running operations
+)
+
+// SetBase moves the root into dir (empty: the system temp directory).
+// Directories already handed out keep working.
+func SetBase(dir string) {
+	mu.Lock()
+	defer mu.Unlock()
+	base = dir
+}
+
+// Root returns the directory that holds every scratch directory
+func Root() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return root()
+}
+
+func root() string {
+	dir := base
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, rootName)
+}
+
+// Dir creates a uniquely named directory for one operation, named after
+// prefix. Defer the returned function: it removes the directory whether
+// the operation succeeded or failed.
+func Dir(prefix string) (string, func(), error) {
+	mu.Lock()
+	parent := root()
+	mu.Unlock()
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return "", nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	dir, err := os.MkdirTemp(parent, prefix+"-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	mu.Lock()
+	active[dir] = true
+	mu.Unlock()
+	return dir, func() {
+		os.RemoveAll(dir)
+		mu.Lock()
+		delete(active, dir)
+		mu.Unlock()
+	}, nil
+}
+
+// Release marks a directory from Dir as no longer in use without removing
+// it, for results handed back to the caller such as previews. Clean then
+// removes it like any other once it is old enough.
+func Release(dir string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(active, dir)
+}
+
+// Entry is a scratch directory, or a leftover of an earlier version
+type Entry struct {
+	Path     string
+	Size     int64
+	Modified time.Time
+	Active   bool // In use by a running operation
+}
+
+// List returns the scratch directories and leftovers, oldest first
+func List() ([]Entry, error) {
+	mu.Lock()
+	parent := root()
+	mu.Unlock()
+
+	var paths []string
+	items, err := os.ReadDir(parent)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", parent, err)
+	}
+	for _, item := range items {
+		paths = append(paths, filepath.Join(parent, item.Name()))
+	}
+	for _, name := range legacyEntries {
+		paths = append(paths, filepath.Join(os.TempDir(), name))
+	}
+
+	var entries []Entry
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		mu.Lock()
+		inUse := active[path]
+		mu.Unlock()
+		entries = append(entries, Entry{Path: path, Size: size(path), Modified: info.ModTime(), Active: inUse})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Modified.Before(entries[j].Modified) })
+	return entries, nil
+}
+
+// CleanResult lists what Clean removed and what it left
+type CleanResult struct {
+	Removed []Entry
+	Kept    []Entry // In use, or newer than the age limit
+	Bytes   int64   // Space freed
+	Errors  []string
+}
+
+// Clean removes scratch directories and leftovers last modified more than
+// maxAge ago. Directories of running operations are always kept; a maxAge
+// of zero removes everything else. With dryRun nothing is removed, but the
+// result is the same.
+func Clean(maxAge time.Duration, dryRun bool) (*CleanResult, error) {
+	entries, err := List()
+	if err != nil {
+		return nil, err
+	}
+	cutoff := time.Now().Add(-maxAge)
+	result := &CleanResult{}
+	for _, e := range entries {
+		if e.Active || e.Modified.After(cutoff) {
+			result.Kept = append(result.Kept, e)
+			continue
+		}
+		if dryRun {
+			result.Removed = append(result.Removed, e)
+			result.Bytes += e.Size
+			continue
+		}
+		if err := os.RemoveAll(e.Path); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", e.Path, err))
+			continue
+		}
+		result.Removed = append(result.Removed, e)
+		result.Bytes += e.Size
+	}
+	return result, nil
+}
+
+// size returns the total size of the files at path
+func size(path string) int64 {
+	var total int64
+	filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && !d.IsDir() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
@@ -0,0 +1,145 @@
+package scratch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDir(t *testing.T) {
+	SetBase(t.TempDir())
+	defer SetBase("")
+
+	first, cleanup, err := Dir("concat")
+	if err != nil {
+		t.Fatalf("Dir failed: %v", err)
+	}
+	second, cleanupSecond, err := Dir("concat")
+	if err != nil {
+		t.Fatalf("Dir failed: %v", err)
+	}
+	defer cleanupSecond()
+	if first == second {
+		t.Fatalf("Dir returned %s twice", first)
+	}
+	if filepath.Dir(first) != Root() {
+		t.Errorf("Dir = %s, want it under %s", first, Root())
+	}
+	os.WriteFile(filepath.Join(first, "list.txt"), []byte("file 'a.mp4'"), 0644)
+
+	cleanup()
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Errorf("cleanup left %s", first)
+	}
+}
+
+func TestClean(t *testing.T) {
+	SetBase(t.TempDir())
+	defer SetBase("")
+
+	inUse, cleanup, err := Dir("render")
+	if err != nil {
+		t.Fatalf("Dir failed: %v", err)
+	}
+	defer cleanup()
+	stale := filepath.Join(Root(), "segments-crashed")
+	os.MkdirAll(stale, 0755)
+	os.WriteFile(filepath.Join(stale, "segment_0.mp4"), make([]byte, 100), 0644)
+	recent := filepath.Join(Root(), "frames-recent")
+	os.MkdirAll(recent, 0755)
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, path := range []string{inUse, stale} {
+		os.Chtimes(path, old, old)
+	}
+
+	if preview, _ := Clean(time.Hour, true); len(preview.Removed) != 1 {
+		t.Errorf("dry run would remove %+v, want only %s", preview.Removed, stale)
+	}
+	if _, err := os.Stat(stale); err != nil {
+		t.Fatal("dry run removed files")
+	}
+
+	result, err := Clean(time.Hour, false)
+	if err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+	if len(result.Removed) != 1 || result.Removed[0].Path != stale || result.Bytes != 100 {
+		t.Errorf("Removed = %+v (%d bytes), want only %s", result.Removed, result.Bytes, stale)
+	}
+	for _, path := range []string{inUse, recent} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Clean removed %s", path)
+		}
+	}
+
+	// Everything not in use goes with no age limit
+	if _, err := Clean(0, false); err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+	entries, _ := List()
+	for _, e := range entries {
+		if e.Path != inUse && filepath.Dir(e.Path) == Root() {
+			t.Errorf("Clean(0, false) left %s", e.Path)
+		}
+	}
+}
+
+func TestDirConcurrent(t *testing.T) {
+	SetBase(t.TempDir())
+	defer SetBase("")
+
+	dirs := make(chan string, 20)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(dirs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dir, cleanup, err := Dir("concat")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer cleanup()
+			os.WriteFile(filepath.Join(dir, "concat_list.txt"), []byte(dir), 0644)
+			dirs <- dir
+		}()
+	}
+	wg.Wait()
+	close(dirs)
+
+	seen := map[string]bool{}
+	for dir := range dirs {
+		if seen[dir] {
+			t.Errorf("%s handed out twice", dir)
+		}
+		seen[dir] = true
+	}
+	if entries, _ := List(); len(entries) != 0 {
+		t.Errorf("%d directories left after cleanup", len(entries))
+	}
+}
+
+func TestRelease(t *testing.T) {
+	SetBase(t.TempDir())
+	defer SetBase("")
+
+	dir, cleanup, err := Dir("preview")
+	if err != nil {
+		t.Fatalf("Dir failed: %v", err)
+	}
+	defer cleanup()
+	if result, _ := Clean(0, false); len(result.Removed) != 0 {
+		t.Fatalf("Clean removed %s while it was in use", dir)
+	}
+
+	Release(dir)
+	if _, err := os.Stat(dir); err != nil {
+		t.Fatalf("Release removed %s", dir)
+	}
+	if result, _ := Clean(0, false); len(result.Removed) != 1 || result.Removed[0].Path != dir {
+		t.Errorf("Clean after Release removed %+v", result.Removed)
+	}
+}
@@ -4,14 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/chandler-mayo/mcp-video-editor/pkg/apiclient"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/diagrams"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/elements"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/text"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/usage"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/vision"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/visual"
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -56,6 +64,9 @@ func (s *MCPServer) handleGetVideoInfo(arguments map[string]interface{}) (*mcp.C
 		float64(info.Bitrate)/1000,
 		info.HasAudio,
 	)
+	if color, err := s.videoOps.ProbeColor(context.Background(), args.FilePath); err == nil {
+		result += "\n- Color: " + describeColor(*color)
+	}
 
 	return mcp.NewToolResultText(result), nil
 }
@@ -170,17 +181,29 @@ func (s *MCPServer) handleExtractAudio(arguments map[string]interface{}) (*mcp.C
 
 func (s *MCPServer) handleTranscodeVideo(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
 	var args struct {
-		Input   string  `json:"input"`
-		Output  string  `json:"output"`
-		Quality *string `json:"quality"`
+		Input        string  `json:"input"`
+		Output       string  `json:"output"`
+		Quality      *string `json:"quality"`
+		RateControl  string  `json:"rateControl"`
+		Bitrate      int     `json:"bitrate"`
+		MaxBitrate   int     `json:"maxBitrate"`
+		BufferSize   int     `json:"bufferSize"`
+		AudioBitrate int     `json:"audioBitrate"`
+		TwoPass      bool    `json:"twoPass"`
 	}
 	if err := unmarshalArgs(arguments, &args); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
 	}
 
 	opts := video.TranscodeOptions{
-		Input:  args.Input,
-		Output: args.Output,
+		Input:        args.Input,
+		Output:       args.Output,
+		RateControl:  args.RateControl,
+		Bitrate:      args.Bitrate,
+		MaxBitrate:   args.MaxBitrate,
+		BufferSize:   args.BufferSize,
+		AudioBitrate: args.AudioBitrate,
+		TwoPass:      args.TwoPass,
 	}
 
 	if args.Quality != nil {
@@ -196,10 +219,11 @@ func (s *MCPServer) handleTranscodeVideo(arguments map[string]interface{}) (*mcp
 
 func (s *MCPServer) handleApplyBlur(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
 	var args struct {
-		Input    string   `json:"input"`
-		Output   string   `json:"output"`
-		Type     *string  `json:"type"`
-		Strength *float64 `json:"strength"`
+		Input    string          `json:"input"`
+		Output   string          `json:"output"`
+		Type     *string         `json:"type"`
+		Strength *float64        `json:"strength"`
+		Mask     *effectMaskArgs `json:"mask"`
 	}
 	if err := unmarshalArgs(arguments, &args); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
@@ -208,6 +232,7 @@ func (s *MCPServer) handleApplyBlur(arguments map[string]interface{}) (*mcp.Call
 	opts := visual.BlurOptions{
 		Input:  args.Input,
 		Output: args.Output,
+		Mask:   args.Mask.toEffectMask(),
 	}
 
 	if args.Type != nil {
@@ -226,15 +251,16 @@ func (s *MCPServer) handleApplyBlur(arguments map[string]interface{}) (*mcp.Call
 
 func (s *MCPServer) handleApplyColorGrade(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
 	var args struct {
-		Input       string   `json:"input"`
-		Output      string   `json:"output"`
-		Brightness  *float64 `json:"brightness"`
-		Contrast    *float64 `json:"contrast"`
-		Saturation  *float64 `json:"saturation"`
-		Gamma       *float64 `json:"gamma"`
-		Hue         *float64 `json:"hue"`
-		Temperature *float64 `json:"temperature"`
-		Tint        *float64 `json:"tint"`
+		Input       string          `json:"input"`
+		Output      string          `json:"output"`
+		Brightness  *float64        `json:"brightness"`
+		Contrast    *float64        `json:"contrast"`
+		Saturation  *float64        `json:"saturation"`
+		Gamma       *float64        `json:"gamma"`
+		Hue         *float64        `json:"hue"`
+		Temperature *float64        `json:"temperature"`
+		Tint        *float64        `json:"tint"`
+		Mask        *effectMaskArgs `json:"mask"`
 	}
 	if err := unmarshalArgs(arguments, &args); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
@@ -250,6 +276,7 @@ func (s *MCPServer) handleApplyColorGrade(arguments map[string]interface{}) (*mc
 		Hue:         args.Hue,
 		Temperature: args.Temperature,
 		Tint:        args.Tint,
+		Mask:        args.Mask.toEffectMask(),
 	}
 
 	if err := s.visualFx.ApplyColorGrade(context.Background(), opts); err != nil {
@@ -261,19 +288,31 @@ func (s *MCPServer) handleApplyColorGrade(arguments map[string]interface{}) (*mc
 
 func (s *MCPServer) handleApplyChromaKey(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
 	var args struct {
-		Input      string   `json:"input"`
-		Output     string   `json:"output"`
-		KeyColor   *string  `json:"keyColor"`
-		Similarity *float64 `json:"similarity"`
-		Blend      *float64 `json:"blend"`
+		Input           string   `json:"input"`
+		Output          string   `json:"output"`
+		KeyColor        *string  `json:"keyColor"`
+		Similarity      *float64 `json:"similarity"`
+		Blend           *float64 `json:"blend"`
+		Despill         bool     `json:"despill"`
+		Choke           int      `json:"choke"`
+		Feather         float64  `json:"feather"`
+		Background      *string  `json:"background"`
+		BackgroundColor *string  `json:"backgroundColor"`
+		Transparent     bool     `json:"transparent"`
 	}
 	if err := unmarshalArgs(arguments, &args); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
 	}
 
 	opts := visual.ChromaKeyOptions{
-		Input:  args.Input,
-		Output: args.Output,
+		Input:           args.Input,
+		Output:          args.Output,
+		BackgroundImage: args.Background,
+		BackgroundColor: args.BackgroundColor,
+		Despill:         args.Despill,
+		Choke:           args.Choke,
+		Feather:         args.Feather,
+		Transparent:     args.Transparent,
 	}
 
 	if args.KeyColor != nil {
@@ -293,11 +332,60 @@ func (s *MCPServer) handleApplyChromaKey(arguments map[string]interface{}) (*mcp
 	return mcp.NewToolResultText(fmt.Sprintf("Successfully applied chroma key to: %s", args.Output)), nil
 }
 
+func (s *MCPServer) handleReplaceBackground(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input          string   `json:"input"`
+		Background     string   `json:"background"`
+		Output         string   `json:"output"`
+		KeyColor       *string  `json:"keyColor"`
+		Similarity     *float64 `json:"similarity"`
+		Blend          *float64 `json:"blend"`
+		Fit            string   `json:"fit"`
+		BackgroundBlur float64  `json:"backgroundBlur"`
+		LightWrap      float64  `json:"lightWrap"`
+		Despill        bool     `json:"despill"`
+		Choke          int      `json:"choke"`
+		Feather        float64  `json:"feather"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	opts := visual.ReplaceBackgroundOptions{
+		Input:          args.Input,
+		Background:     args.Background,
+		Output:         args.Output,
+		Fit:            args.Fit,
+		BackgroundBlur: args.BackgroundBlur,
+		LightWrap:      args.LightWrap,
+		Despill:        args.Despill,
+		Choke:          args.Choke,
+		Feather:        args.Feather,
+	}
+
+	if args.KeyColor != nil {
+		opts.KeyColor = *args.KeyColor
+	}
+	if args.Similarity != nil {
+		opts.Similarity = *args.Similarity
+	}
+	if args.Blend != nil {
+		opts.Blend = *args.Blend
+	}
+
+	if err := s.visualFx.ReplaceBackground(context.Background(), opts); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to replace background: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully replaced background: %s", args.Output)), nil
+}
+
 func (s *MCPServer) handleApplyVignette(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
 	var args struct {
-		Input     string   `json:"input"`
-		Output    string   `json:"output"`
-		Intensity *float64 `json:"intensity"`
+		Input     string          `json:"input"`
+		Output    string          `json:"output"`
+		Intensity *float64        `json:"intensity"`
+		Mask      *effectMaskArgs `json:"mask"`
 	}
 	if err := unmarshalArgs(arguments, &args); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
@@ -306,6 +394,7 @@ func (s *MCPServer) handleApplyVignette(arguments map[string]interface{}) (*mcp.
 	opts := visual.VignetteOptions{
 		Input:  args.Input,
 		Output: args.Output,
+		Mask:   args.Mask.toEffectMask(),
 	}
 
 	if args.Intensity != nil {
@@ -347,19 +436,41 @@ func (s *MCPServer) handleApplySharpen(arguments map[string]interface{}) (*mcp.C
 
 func (s *MCPServer) handleCreatePictureInPicture(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
 	var args struct {
-		MainVideo string  `json:"mainVideo"`
-		PipVideo  string  `json:"pipVideo"`
-		Output    string  `json:"output"`
-		Position  *string `json:"position"`
+		MainVideo   string  `json:"mainVideo"`
+		PipVideo    string  `json:"pipVideo"`
+		Output      string  `json:"output"`
+		Position    *string `json:"position"`
+		Width       *int    `json:"width"`
+		Height      *int    `json:"height"`
+		Margin      int     `json:"margin"`
+		BorderWidth int     `json:"borderWidth"`
+		BorderColor string  `json:"borderColor"`
+		Shadow      bool    `json:"shadow"`
+		Keyframes   []struct {
+			Time    float64  `json:"time"`
+			X       float64  `json:"x"`
+			Y       float64  `json:"y"`
+			Scale   float64  `json:"scale"`
+			Opacity *float64 `json:"opacity"`
+		} `json:"keyframes"`
 	}
 	if err := unmarshalArgs(arguments, &args); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
 	}
 
 	opts := visual.PictureInPictureOptions{
-		MainVideo: args.MainVideo,
-		PipVideo:  args.PipVideo,
-		Output:    args.Output,
+		MainVideo:   args.MainVideo,
+		PipVideo:    args.PipVideo,
+		Output:      args.Output,
+		Width:       args.Width,
+		Height:      args.Height,
+		Margin:      args.Margin,
+		BorderWidth: args.BorderWidth,
+		BorderColor: args.BorderColor,
+		Shadow:      args.Shadow,
+	}
+	for _, k := range args.Keyframes {
+		opts.Keyframes = append(opts.Keyframes, visual.PipKeyframe{Time: k.Time, X: k.X, Y: k.Y, Scale: k.Scale, Opacity: k.Opacity})
 	}
 
 	if args.Position != nil {
@@ -375,18 +486,30 @@ func (s *MCPServer) handleCreatePictureInPicture(arguments map[string]interface{
 
 func (s *MCPServer) handleCreateSplitScreen(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
 	var args struct {
-		Videos []string `json:"videos"`
-		Output string   `json:"output"`
-		Layout string   `json:"layout"`
+		Videos      []string `json:"videos"`
+		Output      string   `json:"output"`
+		Layout      string   `json:"layout"`
+		Rows        int      `json:"rows"`
+		Columns     int      `json:"columns"`
+		Gap         int      `json:"gap"`
+		BorderColor string   `json:"borderColor"`
+		Labels      []string `json:"labels"`
+		Audio       string   `json:"audio"`
 	}
 	if err := unmarshalArgs(arguments, &args); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
 	}
 
 	opts := visual.SplitScreenOptions{
-		Videos: args.Videos,
-		Output: args.Output,
-		Layout: args.Layout,
+		Videos:      args.Videos,
+		Output:      args.Output,
+		Layout:      args.Layout,
+		Rows:        args.Rows,
+		Columns:     args.Columns,
+		BorderWidth: args.Gap,
+		BorderColor: args.BorderColor,
+		Labels:      args.Labels,
+		Audio:       args.Audio,
 	}
 
 	if err := s.composite.CreateSplitScreen(context.Background(), opts); err != nil {
@@ -469,15 +592,23 @@ func (s *MCPServer) handleAddTextOverlay(arguments map[string]interface{}) (*mcp
 		BorderWidth *int     `json:"borderWidth"`
 		StartTime   *float64 `json:"startTime"`
 		Duration    *float64 `json:"duration"`
+		FontFile    string   `json:"fontFile"`
+		MaxWidth    int      `json:"maxWidth"`
+		LineSpacing int      `json:"lineSpacing"`
+		Align       string   `json:"align"`
 	}
 	if err := unmarshalArgs(arguments, &args); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
 	}
 
 	opts := text.TextOverlayOptions{
-		Input:  args.Input,
-		Output: args.Output,
-		Text:   args.Text,
+		Input:       args.Input,
+		Output:      args.Output,
+		Text:        args.Text,
+		FontFile:    args.FontFile,
+		MaxWidth:    args.MaxWidth,
+		LineSpacing: args.LineSpacing,
+		Align:       text.TextAlign(args.Align),
 	}
 
 	if args.Position != nil {
@@ -521,6 +652,10 @@ func (s *MCPServer) handleAddAnimatedText(arguments map[string]interface{}) (*mc
 		AnimationDuration *float64 `json:"animationDuration"`
 		FontSize          *int     `json:"fontSize"`
 		FontColor         *string  `json:"fontColor"`
+		FontFile          string   `json:"fontFile"`
+		MaxWidth          int      `json:"maxWidth"`
+		LineSpacing       int      `json:"lineSpacing"`
+		Align             string   `json:"align"`
 	}
 	if err := unmarshalArgs(arguments, &args); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
@@ -528,9 +663,13 @@ func (s *MCPServer) handleAddAnimatedText(arguments map[string]interface{}) (*mc
 
 	opts := text.AnimatedTextOptions{
 		TextOverlayOptions: text.TextOverlayOptions{
-			Input:  args.Input,
-			Output: args.Output,
-			Text:   args.Text,
+			Input:       args.Input,
+			Output:      args.Output,
+			Text:        args.Text,
+			FontFile:    args.FontFile,
+			MaxWidth:    args.MaxWidth,
+			LineSpacing: args.LineSpacing,
+			Align:       text.TextAlign(args.Align),
 		},
 		Animation: text.AnimationType(args.Animation),
 	}
@@ -588,36 +727,49 @@ func (s *MCPServer) handleBurnSubtitles(arguments map[string]interface{}) (*mcp.
 
 func (s *MCPServer) handleExtractFrames(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
 	var args struct {
-		Input      string   `json:"input"`
-		OutputDir  string   `json:"outputDir"`
-		FPS        *float64 `json:"fps"`
-		Format     *string  `json:"format"`
-		StartTime  *float64 `json:"startTime"`
-		Duration   *float64 `json:"duration"`
-		FrameCount *int     `json:"frameCount"`
+		Input       string   `json:"input"`
+		OutputDir   string   `json:"outputDir"`
+		FPS         *float64 `json:"fps"`
+		Format      *string  `json:"format"`
+		StartTime   *float64 `json:"startTime"`
+		Duration    *float64 `json:"duration"`
+		FrameCount  *int     `json:"frameCount"`
+		NamePattern string   `json:"namePattern"`
+		StartNumber *int     `json:"startNumber"`
+		Alpha       bool     `json:"alpha"`
+		Manifest    bool     `json:"manifest"`
 	}
 	if err := unmarshalArgs(arguments, &args); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
 	}
 
 	opts := video.ExtractFramesOptions{
-		Input:      args.Input,
-		OutputDir:  args.OutputDir,
-		FPS:        args.FPS,
-		StartTime:  args.StartTime,
-		Duration:   args.Duration,
-		FrameCount: args.FrameCount,
+		Input:       args.Input,
+		OutputDir:   args.OutputDir,
+		FPS:         args.FPS,
+		StartTime:   args.StartTime,
+		Duration:    args.Duration,
+		FrameCount:  args.FrameCount,
+		NamePattern: args.NamePattern,
+		StartNumber: args.StartNumber,
+		Alpha:       args.Alpha,
+		Manifest:    args.Manifest,
 	}
 
 	if args.Format != nil {
 		opts.Format = *args.Format
 	}
 
-	if err := s.videoOps.ExtractFrames(context.Background(), opts); err != nil {
+	manifest, err := s.videoOps.ExtractFrames(context.Background(), opts)
+	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to extract frames: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Successfully extracted frames to: %s", args.OutputDir)), nil
+	result := fmt.Sprintf("Successfully extracted %d frames to: %s (%s)", len(manifest.Frames), args.OutputDir, manifest.Pattern)
+	if manifest.File != "" {
+		result += fmt.Sprintf("\nManifest: %s", manifest.File)
+	}
+	return mcp.NewToolResultText(result), nil
 }
 
 func (s *MCPServer) handleAdjustSpeed(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -660,6 +812,7 @@ func (s *MCPServer) handleConvertVideo(arguments map[string]interface{}) (*mcp.C
 		Quality      *string `json:"quality"`
 		Bitrate      *int    `json:"bitrate"`
 		AudioBitrate *int    `json:"audioBitrate"`
+		CodecPreset  string  `json:"codecPreset"`
 	}
 	if err := unmarshalArgs(arguments, &args); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
@@ -670,6 +823,7 @@ func (s *MCPServer) handleConvertVideo(arguments map[string]interface{}) (*mcp.C
 		Output:       args.Output,
 		Bitrate:      args.Bitrate,
 		AudioBitrate: args.AudioBitrate,
+		CodecPreset:  args.CodecPreset,
 	}
 
 	if args.Format != nil {
@@ -689,6 +843,11 @@ func (s *MCPServer) handleConvertVideo(arguments map[string]interface{}) (*mcp.C
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to convert video: %v", err)), nil
 	}
 
+	preset, _ := video.GetCodecPreset(opts.CodecPreset)
+	if preset.Archival || opts.Quality == "archival" && opts.CodecPreset == "" {
+		return mcp.NewToolResultText(fmt.Sprintf("Successfully converted video to lossless master: %s\n\nChecksums:\n- %s",
+			args.Output, strings.Join(video.ArchiveChecksumFiles(args.Output), "\n- "))), nil
+	}
 	return mcp.NewToolResultText(fmt.Sprintf("Successfully converted video to: %s", args.Output)), nil
 }
 
@@ -756,6 +915,9 @@ func (s *MCPServer) handleSetConfig(arguments map[string]interface{}) (*mcp.Call
 	if err := s.config.Update(args.Updates); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update config: %v", err)), nil
 	}
+	s.ffmpeg.SetResourceLimits(resourceLimitsFromConfig(s.config))
+	apiclient.Configure(apiPolicyFromConfig(s.config))
+	scratch.SetBase(s.config.TempPath())
 
 	return mcp.NewToolResultText("Successfully updated configuration"), nil
 }
@@ -764,10 +926,163 @@ func (s *MCPServer) handleResetConfig(arguments map[string]interface{}) (*mcp.Ca
 	if err := s.config.Reset(); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to reset config: %v", err)), nil
 	}
+	s.ffmpeg.SetResourceLimits(resourceLimitsFromConfig(s.config))
+	apiclient.Configure(apiPolicyFromConfig(s.config))
+	s.applyProject(nil)
 
 	return mcp.NewToolResultText("Successfully reset configuration to defaults"), nil
 }
 
+func (s *MCPServer) handleGetSystemStatus(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	status := s.ffmpeg.SystemStatus()
+
+	formatPercent := func(v float64) string {
+		if v < 0 {
+			return "unavailable"
+		}
+		return fmt.Sprintf("%.1f%%", v)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("System Status:\n")
+	sb.WriteString(fmt.Sprintf("  CPU: %s\n", formatPercent(status.CPUPercent)))
+	if status.MemoryTotalMB > 0 {
+		sb.WriteString(fmt.Sprintf("  Memory: %s (%.0f / %.0f MB)\n", formatPercent(status.MemoryPercent), status.MemoryUsedMB, status.MemoryTotalMB))
+	} else {
+		sb.WriteString("  Memory: unavailable\n")
+	}
+	sb.WriteString(fmt.Sprintf("  Disk I/O: %s\n", formatPercent(status.DiskBusyPercent)))
+	if status.DiskFreeMB >= 0 {
+		sb.WriteString(fmt.Sprintf("  Free disk: %.0f MB\n", status.DiskFreeMB))
+	}
+	if len(status.GPUs) == 0 {
+		sb.WriteString("  GPU: none detected\n")
+	}
+	for _, gpu := range status.GPUs {
+		sb.WriteString(fmt.Sprintf("  GPU %s: %.0f%% (memory %.0f / %.0f MB)\n", gpu.Name, gpu.Utilization, gpu.MemoryUsedMB, gpu.MemoryTotalMB))
+	}
+	sb.WriteString(fmt.Sprintf("\nFFmpeg jobs: %d running, %d waiting\n", status.ActiveJobs, status.QueuedJobs))
+	if probes := status.ProbeCache; probes != nil {
+		sb.WriteString(fmt.Sprintf("Probe cache: %d file result(s), %d hit(s), %d miss(es), %d invalidated by file changes\n", probes.Entries, probes.Hits, probes.Misses, probes.Invalidations))
+	}
+
+	statusJSON, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to format status: %v", err)), nil
+	}
+	sb.WriteString(fmt.Sprintf("\n%s", string(statusJSON)))
+
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+func (s *MCPServer) handleGetAIUsage(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		TimelineID string `json:"timelineId"`
+		ProjectID  string `json:"projectId"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	var report usage.Report
+	var title string
+	switch {
+	case args.TimelineID != "" && args.ProjectID != "":
+		return mcp.NewToolResultError("Pass timelineId or projectId, not both"), nil
+	case args.TimelineID != "" || args.ProjectID != "":
+		scope := usage.TimelineScope(args.TimelineID)
+		title = fmt.Sprintf("AI usage for timeline %s", args.TimelineID)
+		if args.ProjectID != "" {
+			scope = usage.ProjectScope(args.ProjectID)
+			title = fmt.Sprintf("AI usage for project %s", args.ProjectID)
+		}
+		var err error
+		report, err = s.usage.ScopeReport(scope)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read AI usage: %v", err)), nil
+		}
+	default:
+		report = s.usage.Session()
+		title = "AI usage this session"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %d calls, estimated $%.4f\n", title, report.Calls, report.Cost))
+	if !report.Since.IsZero() {
+		sb.WriteString(fmt.Sprintf("Since: %s\n", report.Since.Format("2006-01-02 15:04:05")))
+	}
+	if scope := s.usage.Scope(); scope != "" {
+		sb.WriteString(fmt.Sprintf("New calls are recorded to: %s\n", scope))
+	}
+	for _, total := range report.Totals {
+		var amounts []string
+		if total.InputTokens > 0 || total.OutputTokens > 0 {
+			amounts = append(amounts, fmt.Sprintf("%d in / %d out tokens", total.InputTokens, total.OutputTokens))
+		}
+		if total.Characters > 0 {
+			amounts = append(amounts, fmt.Sprintf("%d characters", total.Characters))
+		}
+		if total.AudioSeconds > 0 {
+			amounts = append(amounts, fmt.Sprintf("%.1f audio minutes", total.AudioSeconds/60))
+		}
+		name := total.Provider + " " + total.Feature
+		if total.Model != "" {
+			name += " (" + total.Model + ")"
+		}
+		sb.WriteString(fmt.Sprintf("  %s: %d calls", name, total.Calls))
+		if len(amounts) > 0 {
+			sb.WriteString(", " + strings.Join(amounts, ", "))
+		}
+		sb.WriteString(fmt.Sprintf(", $%.4f\n", total.Cost))
+	}
+	if err := s.usage.Err(); err != nil {
+		sb.WriteString(fmt.Sprintf("\nWarning: usage could not be saved: %v\n", err))
+	}
+
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to format usage: %v", err)), nil
+	}
+	sb.WriteString(fmt.Sprintf("\n%s", string(reportJSON)))
+
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+// resourceLimitsFromConfig converts the guardrail settings into FFmpeg job limits
+func resourceLimitsFromConfig(cfg *config.Config) ffmpeg.ResourceLimits {
+	return ffmpeg.ResourceLimits{
+		MaxCPUPercent:       cfg.MaxCPUPercent,
+		MaxMemoryPercent:    cfg.MaxMemoryPercent,
+		MaxGPUPercent:       cfg.MaxGPUPercent,
+		MaxGPUMemoryPercent: cfg.MaxGPUMemoryPercent,
+		MaxDiskBusyPercent:  cfg.MaxDiskBusyPercent,
+		MinFreeDiskMB:       cfg.MinFreeDiskMB,
+		MaxConcurrentJobs:   cfg.MaxConcurrentJobs,
+		LowPriority:         cfg.LowPriorityJobs,
+		WaitTimeout:         time.Duration(cfg.JobWaitTimeout * float64(time.Second)),
+		JobTimeout:          time.Duration(cfg.JobTimeout * float64(time.Second)),
+		StallTimeout:        time.Duration(cfg.JobStallTimeout * float64(time.Second)),
+		DiskPath:            cfg.TempDir,
+	}
+}
+
+// apiPolicyFromConfig converts the API settings into a retry and rate limit policy
+func apiPolicyFromConfig(cfg *config.Config) apiclient.Policy {
+	policy := apiclient.DefaultPolicy()
+	switch {
+	case cfg.APIMaxRetries < 0:
+		policy.MaxRetries = 0
+	case cfg.APIMaxRetries > 0:
+		policy.MaxRetries = cfg.APIMaxRetries
+	}
+	if cfg.APIRetryDelay > 0 {
+		policy.BaseDelay = time.Duration(cfg.APIRetryDelay * float64(time.Second))
+	}
+	policy.RateLimits = cfg.APIRateLimits
+	policy.Budgets = cfg.APIBudgets
+	return policy
+}
+
 // Ken Burns effect handler
 
 func (s *MCPServer) handleApplyKenBurns(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -849,6 +1164,8 @@ func (s *MCPServer) handleAddImageOverlay(arguments map[string]interface{}) (*mc
 		Rotation  *float64 `json:"rotation"`
 		StartTime *float64 `json:"startTime"`
 		Duration  *float64 `json:"duration"`
+		Animated  *bool    `json:"animated"`
+		Loop      *int     `json:"loop"`
 	}
 	if err := unmarshalArgs(arguments, &args); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
@@ -867,6 +1184,8 @@ func (s *MCPServer) handleAddImageOverlay(arguments map[string]interface{}) (*mc
 		Rotation:  args.Rotation,
 		StartTime: args.StartTime,
 		Duration:  args.Duration,
+		Animated:  args.Animated,
+		Loop:      args.Loop,
 	}
 
 	if args.Position != nil {
@@ -882,43 +1201,56 @@ func (s *MCPServer) handleAddImageOverlay(arguments map[string]interface{}) (*mc
 
 func (s *MCPServer) handleAddShape(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
 	var args struct {
-		Input       string              `json:"input"`
-		Output      string              `json:"output"`
-		Shape       string              `json:"shape"`
-		X           int                 `json:"x"`
-		Y           int                 `json:"y"`
-		Width       *int                `json:"width"`
-		Height      *int                `json:"height"`
-		Radius      *int                `json:"radius"`
-		X2          *int                `json:"x2"`
-		Y2          *int                `json:"y2"`
-		Points      []elements.Point    `json:"points"`
-		Color       *string             `json:"color"`
-		BorderColor *string             `json:"borderColor"`
-		BorderWidth *int                `json:"borderWidth"`
-		Opacity     *float64            `json:"opacity"`
-		StartTime   *float64            `json:"startTime"`
-		Duration    *float64            `json:"duration"`
+		Input        string                   `json:"input"`
+		Output       string                   `json:"output"`
+		Shape        string                   `json:"shape"`
+		X            int                      `json:"x"`
+		Y            int                      `json:"y"`
+		Width        *int                     `json:"width"`
+		Height       *int                     `json:"height"`
+		Radius       *int                     `json:"radius"`
+		X2           *int                     `json:"x2"`
+		Y2           *int                     `json:"y2"`
+		Points       []elements.Point         `json:"points"`
+		Color        *string                  `json:"color"`
+		BorderColor  *string                  `json:"borderColor"`
+		BorderWidth  *int                     `json:"borderWidth"`
+		Opacity      *float64                 `json:"opacity"`
+		StartTime    *float64                 `json:"startTime"`
+		Duration     *float64                 `json:"duration"`
+		TrackFile    string                   `json:"trackFile"`
+		TrackID      *int                     `json:"trackId"`
+		CornerRadius int                      `json:"cornerRadius"`
+		Dash         int                      `json:"dash"`
+		HeadSize     int                      `json:"headSize"`
+		Motion       []elements.ShapeKeyframe `json:"motion"`
 	}
 	if err := unmarshalArgs(arguments, &args); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
 	}
+	if args.TrackFile == "" && !strings.EqualFold(args.Shape, "polygon") && (arguments["x"] == nil || arguments["y"] == nil) {
+		return mcp.NewToolResultError("x and y are required unless drawing a polygon or following a trackFile"), nil
+	}
 
 	opts := elements.ShapeOptions{
-		Input:       args.Input,
-		Output:      args.Output,
-		Shape:       args.Shape,
-		X:           args.X,
-		Y:           args.Y,
-		Width:       args.Width,
-		Height:      args.Height,
-		Radius:      args.Radius,
-		X2:          args.X2,
-		Y2:          args.Y2,
-		Points:      args.Points,
-		BorderColor: args.BorderColor,
-		StartTime:   args.StartTime,
-		Duration:    args.Duration,
+		Input:        args.Input,
+		Output:       args.Output,
+		Shape:        args.Shape,
+		X:            args.X,
+		Y:            args.Y,
+		Width:        args.Width,
+		Height:       args.Height,
+		Radius:       args.Radius,
+		X2:           args.X2,
+		Y2:           args.Y2,
+		Points:       args.Points,
+		BorderColor:  args.BorderColor,
+		StartTime:    args.StartTime,
+		Duration:     args.Duration,
+		CornerRadius: args.CornerRadius,
+		Dash:         args.Dash,
+		HeadSize:     args.HeadSize,
+		Motion:       args.Motion,
 	}
 
 	// Set color with default
@@ -942,6 +1274,28 @@ func (s *MCPServer) handleAddShape(arguments map[string]interface{}) (*mcp.CallT
 		opts.Opacity = 1.0
 	}
 
+	// Follow a tracked object, shown while it is tracked unless timed explicitly
+	if args.TrackFile != "" {
+		if shape := strings.ToLower(args.Shape); shape != "rectangle" && shape != "rect" && shape != "box" {
+			return mcp.NewToolResultError("Only rectangles can follow a track"), nil
+		}
+		id := 1
+		if args.TrackID != nil {
+			id = *args.TrackID
+		}
+		tracks, err := loadTracks(args.TrackFile, []int{id})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		for _, k := range tracks[0].Keyframes {
+			opts.Follow = append(opts.Follow, elements.FollowKeyframe{Time: k.Time, X: k.X, Y: k.Y, W: k.W, H: k.H})
+		}
+		if opts.StartTime == nil && opts.Duration == nil {
+			start, duration := tracks[0].Start, tracks[0].End-tracks[0].Start
+			opts.StartTime, opts.Duration = &start, &duration
+		}
+	}
+
 	if err := s.elements.DrawShape(context.Background(), opts); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to add shape: %v", err)), nil
 	}
@@ -1078,10 +1432,15 @@ func (s *MCPServer) handleRemoveByTranscript(arguments map[string]interface{}) (
 	// This requires multiple FFmpeg operations
 
 	// For now, we'll use the video operations to trim and concatenate
-	// Create temp files for each segment
+	// Create temp files for each segment; they are removed even on failure
+	tempDir, cleanup, err := scratch.Dir("segments")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer cleanup()
 	var segmentPaths []string
 	for i, seg := range toKeep {
-		segmentPath := fmt.Sprintf("%s_segment_%d.mp4", args.Output[:len(args.Output)-4], i)
+		segmentPath := filepath.Join(tempDir, fmt.Sprintf("segment_%d%s", i, filepath.Ext(args.Input)))
 		duration := seg.End - seg.Start
 
 		trimOpts := video.TrimOptions{
@@ -1108,11 +1467,6 @@ func (s *MCPServer) handleRemoveByTranscript(arguments map[string]interface{}) (
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to concatenate segments: %v", err)), nil
 	}
 
-	// Clean up temp files
-	for _, path := range segmentPaths {
-		_ = os.Remove(path)
-	}
-
 	return mcp.NewToolResultText(fmt.Sprintf("Successfully removed text from video. Removed %d segment(s). Output: %s", len(toRemove), args.Output)), nil
 }
 
@@ -1141,9 +1495,14 @@ func (s *MCPServer) handleTrimToScript(arguments map[string]interface{}) (*mcp.C
 	}
 
 	// Trim and concatenate segments
+	tempDir, cleanup, err := scratch.Dir("segments")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer cleanup()
 	var segmentPaths []string
 	for i, seg := range toKeep {
-		segmentPath := fmt.Sprintf("%s_segment_%d.mp4", args.Output[:len(args.Output)-4], i)
+		segmentPath := filepath.Join(tempDir, fmt.Sprintf("segment_%d%s", i, filepath.Ext(args.Input)))
 		duration := seg.End - seg.Start
 
 		trimOpts := video.TrimOptions{
@@ -1170,11 +1529,6 @@ func (s *MCPServer) handleTrimToScript(arguments map[string]interface{}) (*mcp.C
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to concatenate segments: %v", err)), nil
 	}
 
-	// Clean up temp files
-	for _, path := range segmentPaths {
-		_ = os.Remove(path)
-	}
-
 	return mcp.NewToolResultText(fmt.Sprintf("Successfully trimmed video to script. Kept %d segment(s). Output: %s", len(toKeep), args.Output)), nil
 }
 
@@ -1193,6 +1547,7 @@ func (s *MCPServer) handleCreateTimeline(arguments map[string]interface{}) (*mcp
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create timeline: %v", err)), nil
 	}
+	s.usage.SetScope(usage.TimelineScope(timeline.ID))
 
 	result := fmt.Sprintf("Successfully created timeline:\n- ID: %s\n- Name: %s\n- Created: %s",
 		timeline.ID,
@@ -1235,6 +1590,7 @@ func (s *MCPServer) handleAddToTimeline(arguments map[string]interface{}) (*mcp.
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to add operation: %v", err)), nil
 	}
+	s.usage.SetScope(usage.TimelineScope(timeline.ID))
 
 	result := fmt.Sprintf("Successfully added operation to timeline:\n- Operation: %s\n- Description: %s\n- Timeline position: %d/%d\n- Can undo: %t\n- Can redo: %t",
 		args.Operation,
@@ -1276,6 +1632,7 @@ func (s *MCPServer) handleJumpToTimelinePoint(arguments map[string]interface{})
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to jump to timeline point: %v", err)), nil
 	}
+	s.usage.SetScope(usage.TimelineScope(timeline.ID))
 
 	result := fmt.Sprintf("Jumped to timeline position %d/%d",
 		timeline.CurrentIndex+1,
@@ -1306,6 +1663,7 @@ func (s *MCPServer) handleUndo(arguments map[string]interface{}) (*mcp.CallToolR
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to undo: %v", err)), nil
 	}
+	s.usage.SetScope(usage.TimelineScope(timeline.ID))
 
 	if timeline.CurrentIndex < 0 {
 		result := "Already at the beginning of the timeline"
@@ -1342,6 +1700,7 @@ func (s *MCPServer) handleRedo(arguments map[string]interface{}) (*mcp.CallToolR
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to redo: %v", err)), nil
 	}
+	s.usage.SetScope(usage.TimelineScope(timeline.ID))
 
 	if timeline.CurrentIndex >= len(timeline.Operations)-1 && nextOutput == nil {
 		return mcp.NewToolResultText("Already at the end of the timeline. Nothing to redo."), nil
@@ -1377,6 +1736,7 @@ func (s *MCPServer) handleCreateMultiTakeProject(arguments map[string]interface{
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create project: %v", err)), nil
 	}
+	s.usage.SetScope(usage.ProjectScope(project.ID))
 
 	result := fmt.Sprintf("Multi-take project created successfully!\nProject ID: %s\nName: %s\nScript sections: %d\nStatus: %s",
 		project.ID,
@@ -1400,6 +1760,7 @@ func (s *MCPServer) handleAddTakesToProject(arguments map[string]interface{}) (*
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to load project: %v", err)), nil
 	}
+	s.usage.SetScope(usage.ProjectScope(project.ID))
 
 	added, err := s.multitake.AddTakes(project, args.TakePaths, true)
 	if err != nil {
@@ -1431,6 +1792,7 @@ func (s *MCPServer) handleAnalyzeTakes(arguments map[string]interface{}) (*mcp.C
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to load project: %v", err)), nil
 	}
+	s.usage.SetScope(usage.ProjectScope(project.ID))
 
 	if err := s.multitake.AnalyzeTakes(project); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to analyze takes: %v", err)), nil
@@ -1459,6 +1821,7 @@ func (s *MCPServer) handleSelectBestTakes(arguments map[string]interface{}) (*mc
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to load project: %v", err)), nil
 	}
+	s.usage.SetScope(usage.ProjectScope(project.ID))
 
 	if err := s.multitake.SelectBestTakes(project); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to select best takes: %v", err)), nil
@@ -1489,6 +1852,7 @@ func (s *MCPServer) handleAssembleBestTakes(arguments map[string]interface{}) (*
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to load project: %v", err)), nil
 	}
+	s.usage.SetScope(usage.ProjectScope(project.ID))
 
 	if err := s.multitake.AssembleFinal(project, args.Output); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to assemble video: %v", err)), nil
@@ -1627,6 +1991,7 @@ func (s *MCPServer) handleExportFinalVideo(arguments map[string]interface{}) (*m
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to load project: %v", err)), nil
 	}
+	s.usage.SetScope(usage.ProjectScope(project.ID))
 
 	// Find assembled video in output directory
 	assembledPath := filepath.Join(project.Directories.Output, project.Name+"_assembled.mp4")
@@ -1710,7 +2075,11 @@ func (s *MCPServer) handleAnalyzeVideoContent(arguments map[string]interface{})
 	result.WriteString(strings.Repeat("=", 80))
 	result.WriteString("\n\n")
 	result.WriteString(fmt.Sprintf("Duration: %.2fs\n", analysis.Duration))
-	result.WriteString(fmt.Sprintf("Frames analyzed: %d\n\n", len(analysis.Frames)))
+	result.WriteString(fmt.Sprintf("Frames analyzed: %d\n", len(analysis.Frames)))
+	if analysis.CachedFrames > 0 {
+		result.WriteString(fmt.Sprintf("From cache: %d\n", analysis.CachedFrames))
+	}
+	result.WriteString("\n")
 	
 	result.WriteString("SUMMARY:\n")
 	result.WriteString(analysis.Summary)
@@ -1762,27 +2131,12 @@ func (s *MCPServer) handleDescribeScene(arguments map[string]interface{}) (*mcp.
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
 	}
 
-	// Extract frame at timestamp using FFmpeg directly
-	tempFrame := filepath.Join(os.TempDir(), "scene-frame.jpg")
-	ffmpegArgs := []string{
-		"-ss", fmt.Sprintf("%.3f", args.Timestamp),
-		"-i", args.Input,
-		"-frames:v", "1",
-		"-q:v", "2",
-		"-y",
-		tempFrame,
-	}
-	if err := s.ffmpeg.Execute(context.Background(), ffmpegArgs...); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to extract frame: %v", err)), nil
-	}
-	defer os.Remove(tempFrame)
-
 	prompt := ""
 	if args.Prompt != nil {
 		prompt = *args.Prompt
 	}
 
-	description, err := s.visionAnalyzer.AnalyzeFrame(context.Background(), tempFrame, prompt)
+	description, err := s.visionAnalyzer.DescribeScene(context.Background(), args.Input, args.Timestamp, prompt)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to analyze scene: %v", err)), nil
 	}
@@ -1795,6 +2149,31 @@ func (s *MCPServer) handleDescribeScene(arguments map[string]interface{}) (*mcp.
 	return mcp.NewToolResultText(result), nil
 }
 
+func (s *MCPServer) handleClearVisionCache(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input string `json:"input"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	stats, err := s.visionAnalyzer.ClearCache(args.Input)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to clear vision cache: %v", err)), nil
+	}
+
+	target := "all videos"
+	if args.Input != "" {
+		target = args.Input
+	}
+	result := fmt.Sprintf("Cleared vision cache for %s: %d file(s), %.1f MB",
+		target,
+		stats.Files,
+		float64(stats.Bytes)/(1024*1024))
+
+	return mcp.NewToolResultText(result), nil
+}
+
 func (s *MCPServer) handleFindObjectsInVideo(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
 	var args struct {
 		Input    string   `json:"input"`
@@ -1829,6 +2208,11 @@ func (s *MCPServer) handleFindObjectsInVideo(arguments map[string]interface{}) (
 				i+1, match.Timestamp, match.Confidence*100))
 			result.WriteString(fmt.Sprintf("   %s\n\n", match.Description))
 		}
+		result.WriteString("Visible:\n")
+		for _, r := range searchResult.Ranges {
+			result.WriteString(fmt.Sprintf("- %.2fs - %.2fs (%d frame(s), up to %.0f%%)\n", r.Start, r.End, r.Matches, r.Confidence*100))
+		}
+		result.WriteString("\nUse track_object for a motion path to blur, highlight or follow.")
 	}
 
 	return mcp.NewToolResultText(result.String()), nil
@@ -1867,6 +2251,75 @@ func (s *MCPServer) handleSearchVisualContent(arguments map[string]interface{})
 			result.WriteString(fmt.Sprintf("• %.2fs (confidence: %.0f%%): %s\n",
 				match.Timestamp, match.Confidence*100, match.Description))
 		}
+		result.WriteString("\nTime ranges:\n")
+		for _, r := range searchResult.Ranges {
+			result.WriteString(fmt.Sprintf("• %.2fs - %.2fs (%d frame(s))\n", r.Start, r.End, r.Matches))
+		}
+	}
+	if searchResult.CachedFrames > 0 {
+		result.WriteString(fmt.Sprintf("\n(%d frame(s) answered from the vision cache)", searchResult.CachedFrames))
+	}
+
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+func (s *MCPServer) handleExtractOnscreenText(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input      string    `json:"input"`
+		Interval   float64   `json:"interval"`
+		Timestamps []float64 `json:"timestamps"`
+		Query      string    `json:"query"`
+		Engine     string    `json:"engine"`
+		Language   string    `json:"language"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	textResult, err := s.visionAnalyzer.ExtractText(context.Background(), vision.OCROptions{
+		Input:      args.Input,
+		Interval:   args.Interval,
+		Timestamps: args.Timestamps,
+		Engine:     args.Engine,
+		Language:   args.Language,
+		Query:      args.Query,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to extract text: %v", err)), nil
+	}
+
+	var result strings.Builder
+	if args.Query != "" {
+		result.WriteString(fmt.Sprintf("ON-SCREEN TEXT MATCHING \"%s\"\n", args.Query))
+	} else {
+		result.WriteString("ON-SCREEN TEXT\n")
+	}
+	result.WriteString(strings.Repeat("=", 80))
+	result.WriteString("\n\n")
+	result.WriteString(fmt.Sprintf("Engine: %s\n", textResult.Engine))
+	result.WriteString(fmt.Sprintf("Frames scanned: %d", textResult.FramesScanned))
+	if textResult.CachedFrames > 0 {
+		result.WriteString(fmt.Sprintf(" (%d from cache)", textResult.CachedFrames))
+	}
+	result.WriteString("\n\n")
+
+	if len(textResult.Segments) == 0 {
+		if args.Query != "" {
+			result.WriteString("No on-screen text matches the query.")
+		} else {
+			result.WriteString("No on-screen text found.")
+		}
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	for _, segment := range textResult.Segments {
+		if segment.End > segment.Start {
+			result.WriteString(fmt.Sprintf("[%.2fs - %.2fs]\n", segment.Start, segment.End))
+		} else {
+			result.WriteString(fmt.Sprintf("[%.2fs]\n", segment.Start))
+		}
+		result.WriteString(segment.Text)
+		result.WriteString("\n\n")
 	}
 
 	return mcp.NewToolResultText(result.String()), nil
@@ -2066,29 +2519,34 @@ func countMindMapNodes(node diagrams.MindMapNode) int {
 
 func (s *MCPServer) handleCreateSideBySide(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
 	var args struct {
-		Input1 string `json:"input1"`
-		Input2 string `json:"input2"`
-		Output string `json:"output"`
+		Input1   string  `json:"input1"`
+		Input2   string  `json:"input2"`
+		Output   string  `json:"output"`
+		Offset1  float64 `json:"offset1"`
+		Offset2  float64 `json:"offset2"`
+		Vertical bool    `json:"vertical"`
+		Height   int     `json:"height"`
+		Width    int     `json:"width"`
+		Audio    string  `json:"audio"`
 	}
 
 	if err := unmarshalArgs(arguments, &args); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
 	}
 
-	// Use FFmpeg directly to create side-by-side video
-	ffmpegArgs := []string{
-		"-i", args.Input1,
-		"-i", args.Input2,
-		"-filter_complex", "[0:v][1:v]hstack=inputs=2[v]",
-		"-map", "[v]",
-		"-c:v", "libx264",
-		"-preset", "medium",
-		"-crf", "23",
-		"-y",
-		args.Output,
+	opts := visual.SideBySideOptions{
+		Input1:   args.Input1,
+		Input2:   args.Input2,
+		Output:   args.Output,
+		Offset1:  args.Offset1,
+		Offset2:  args.Offset2,
+		Vertical: args.Vertical,
+		Height:   args.Height,
+		Width:    args.Width,
+		Audio:    args.Audio,
 	}
 
-	if err := s.ffmpeg.Execute(context.Background(), ffmpegArgs...); err != nil {
+	if err := s.composite.CreateSideBySide(context.Background(), opts); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create side-by-side: %v", err)), nil
 	}
 
@@ -2097,9 +2555,15 @@ func (s *MCPServer) handleCreateSideBySide(arguments map[string]interface{}) (*m
 
 func (s *MCPServer) handleCreateVideoFromImages(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
 	var args struct {
-		ImagePattern string `json:"imagePattern"`
-		Output       string `json:"output"`
-		FPS          *int   `json:"fps"`
+		Images             []video.ImageClip `json:"images"`
+		ImagePattern       string            `json:"imagePattern"`
+		Output             string            `json:"output"`
+		FPS                *int              `json:"fps"`
+		Duration           float64           `json:"duration"`
+		Transition         string            `json:"transition"`
+		TransitionDuration float64           `json:"transitionDuration"`
+		Width              int               `json:"width"`
+		Height             int               `json:"height"`
 	}
 
 	if err := unmarshalArgs(arguments, &args); err != nil {
@@ -2111,24 +2575,26 @@ func (s *MCPServer) handleCreateVideoFromImages(arguments map[string]interface{}
 		fps = *args.FPS
 	}
 
-	// Use FFmpeg directly to create video from images
-	ffmpegArgs := []string{
-		"-framerate", fmt.Sprintf("%d", fps),
-		"-pattern_type", "glob",
-		"-i", args.ImagePattern,
-		"-c:v", "libx264",
-		"-preset", "medium",
-		"-crf", "23",
-		"-pix_fmt", "yuv420p",
-		"-y",
-		args.Output,
-	}
-
-	if err := s.ffmpeg.Execute(context.Background(), ffmpegArgs...); err != nil {
+	result, err := s.videoOps.CreateVideoFromImages(context.Background(), video.ImagesToVideoOptions{
+		Images:             args.Images,
+		Pattern:            args.ImagePattern,
+		Output:             args.Output,
+		FPS:                fps,
+		Duration:           args.Duration,
+		Transition:         args.Transition,
+		TransitionDuration: args.TransitionDuration,
+		Width:              args.Width,
+		Height:             args.Height,
+	})
+	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create video from images: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Successfully created video from images: %s (FPS: %d)", args.Output, fps)), nil
+	if result.Images == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("Successfully created video from images: %s (FPS: %d)", args.Output, fps)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully created video from %d images: %s (%dx%d, %.2fs, FPS: %d)",
+		result.Images, args.Output, result.Width, result.Height, result.Duration, fps)), nil
 }
 
 func (s *MCPServer) handleGetAudioStats(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
@@ -2154,13 +2620,58 @@ func (s *MCPServer) handleGetAudioStats(arguments map[string]interface{}) (*mcp.
 	result.WriteString(fmt.Sprintf("Duration: %.2f seconds\n", info.Duration))
 	result.WriteString(fmt.Sprintf("Has Audio: %t\n", info.HasAudio))
 
-	if info.HasAudio {
-		result.WriteString(fmt.Sprintf("Audio Codec: %s\n", info.AudioCodec))
-		if info.Bitrate > 0 {
-			result.WriteString(fmt.Sprintf("Bitrate: %d kbps\n", info.Bitrate/1000))
-		}
-	} else {
+	if !info.HasAudio {
 		result.WriteString("\nNo audio found in file.")
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	result.WriteString(fmt.Sprintf("Audio Codec: %s\n", info.AudioCodec))
+	if info.Bitrate > 0 {
+		result.WriteString(fmt.Sprintf("Bitrate: %d kbps\n", info.Bitrate/1000))
+	}
+
+	stats, err := s.audioOps.AnalyzeLoudness(context.Background(), args.Input)
+	if err != nil {
+		result.WriteString(fmt.Sprintf("\nLoudness analysis failed: %v\n", err))
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	result.WriteString("\nLOUDNESS:\n")
+	result.WriteString(fmt.Sprintf("Integrated Loudness: %.1f LUFS\n", stats.IntegratedLUFS))
+	result.WriteString(fmt.Sprintf("True Peak: %.1f dBTP\n", stats.TruePeak))
+	result.WriteString(fmt.Sprintf("Loudness Range (LRA): %.1f LU\n", stats.LoudnessRange))
+
+	result.WriteString("\nSIGNAL:\n")
+	result.WriteString(fmt.Sprintf("Peak Level: %.1f dBFS\n", stats.PeakLevel))
+	result.WriteString(fmt.Sprintf("RMS Level: %.1f dBFS\n", stats.RMSLevel))
+	result.WriteString(fmt.Sprintf("Crest Factor (dynamic range): %.1f dB\n", stats.CrestFactor))
+	result.WriteString(fmt.Sprintf("Noise Floor: %.1f dBFS\n", stats.NoiseFloor))
+	result.WriteString(fmt.Sprintf("DC Offset: %.6f\n", stats.DCOffset))
+	result.WriteString(fmt.Sprintf("Clipping: %t", stats.Clipping))
+	if stats.Clipping {
+		result.WriteString(fmt.Sprintf(" (%d samples at full scale)", stats.PeakCount))
+	}
+	result.WriteString("\n")
+	result.WriteString(fmt.Sprintf("Silence: %.1f%% (%.2fs)\n", stats.SilencePercentage, stats.SilenceDuration))
+
+	var warnings []string
+	if stats.Clipping {
+		warnings = append(warnings, "Audio is clipping; reduce gain at the source or apply a limiter")
+	}
+	if stats.TruePeak > -1 {
+		warnings = append(warnings, "True peak above -1 dBTP may distort after lossy encoding")
+	}
+	if math.Abs(stats.DCOffset) > 0.01 {
+		warnings = append(warnings, "Significant DC offset; apply a high-pass filter")
+	}
+	if stats.IntegratedLUFS < -24 || stats.IntegratedLUFS > -9 {
+		warnings = append(warnings, "Loudness is outside typical delivery targets (-14 LUFS streaming, -16 podcasts, -23 broadcast); consider normalize_audio")
+	}
+	if len(warnings) > 0 {
+		result.WriteString("\nWARNINGS:\n")
+		for _, w := range warnings {
+			result.WriteString(fmt.Sprintf("- %s\n", w))
+		}
 	}
 
 	return mcp.NewToolResultText(result.String()), nil
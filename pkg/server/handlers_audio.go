@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/chandler-mayo/mcp-video-editor/pkg/audio"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -11,7 +12,7 @@ import (
 
 // registerReplaceSpokenWord registers the replace_spoken_word MCP tool
 func (s *MCPServer) registerReplaceSpokenWord() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "replace_spoken_word",
 		Description: "Replace a spoken word or phrase in audio/video with voice-matched TTS audio. Uses ElevenLabs for voice cloning and seamless audio splicing.",
 		InputSchema: mcp.ToolInputSchema{
@@ -23,7 +24,7 @@ func (s *MCPServer) registerReplaceSpokenWord() {
 				},
 				"output": map[string]interface{}{
 					"type":        "string",
-					"description": "Output video or audio file path",
+					"description": "Output video or audio file path (not needed with preview)",
 				},
 				"searchText": map[string]interface{}{
 					"type":        "string",
@@ -49,8 +50,16 @@ func (s *MCPServer) registerReplaceSpokenWord() {
 					"type":        "number",
 					"description": "Which occurrence to replace: 0-based index, or -1 for all occurrences (default: 0)",
 				},
+				"preview": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Render only the region around each match to temp files for approval instead of the whole file. Returns the timestamps, voice ID and transcript path to pass to the full render (default: false)",
+				},
+				"previewPadding": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds of context before and after each match in a preview (default: 3)",
+				},
 			},
-			Required: []string{"input", "output", "searchText", "replacementText"},
+			Required: []string{"input", "searchText", "replacementText"},
 		},
 	}, s.handleReplaceSpokenWord)
 }
@@ -69,6 +78,8 @@ func (s *MCPServer) handleReplaceSpokenWord(arguments map[string]interface{}) (*
 	if idx, ok := arguments["matchIndex"].(float64); ok {
 		matchIndex = int(idx)
 	}
+	preview, _ := arguments["preview"].(bool)
+	previewPadding, _ := arguments["previewPadding"].(float64)
 
 	// Build options
 	opts := audio.ReplaceOptions{
@@ -80,6 +91,27 @@ func (s *MCPServer) handleReplaceSpokenWord(arguments map[string]interface{}) (*
 		VoiceSamplePath: voiceSamplePath,
 		VoiceID:         voiceID,
 		MatchIndex:      matchIndex,
+		PreviewPadding:  previewPadding,
+	}
+
+	if preview {
+		result, err := s.audioReplacement.PreviewReplacement(context.Background(), opts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to preview replacement: %v", err)), nil
+		}
+
+		var b strings.Builder
+		b.WriteString(fmt.Sprintf("Preview of replacing '%s' with '%s' (%d clip(s)):\n", searchText, replacementText, len(result.Clips)))
+		for _, clip := range result.Clips {
+			b.WriteString(fmt.Sprintf("- Match %d: '%s' at %.2fs-%.2fs, clip %.2fs-%.2fs: %s\n",
+				clip.MatchIndex, clip.Text, clip.Start, clip.End, clip.ClipStart, clip.ClipEnd, clip.Path))
+		}
+		b.WriteString(fmt.Sprintf("\nTo apply, call replace_spoken_word again without preview, with an output path, voiceID %q and transcriptPath %q.",
+			result.VoiceID, result.TranscriptPath))
+		return mcp.NewToolResultText(b.String()), nil
+	}
+	if output == "" {
+		return mcp.NewToolResultError("output is required unless preview is set"), nil
 	}
 
 	// Execute replacement
@@ -91,9 +123,99 @@ func (s *MCPServer) handleReplaceSpokenWord(arguments map[string]interface{}) (*
 		searchText, replacementText, input, output)), nil
 }
 
+// registerReplaceSpokenWords registers the replace_spoken_words MCP tool
+func (s *MCPServer) registerReplaceSpokenWords() {
+	s.addTool(mcp.Tool{
+		Name:        "replace_spoken_words",
+		Description: "Replace several spoken words or phrases in one pass from a find/replace list. Transcribes once, clones the voice once and renders once, instead of calling replace_spoken_word per word.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video or audio file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video or audio file path",
+				},
+				"replacements": map[string]interface{}{
+					"type":        "array",
+					"description": "Find/replace pairs, e.g. [{\"search\": \"Tuesday\", \"replace\": \"Thursday\"}]",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"search":     map[string]interface{}{"type": "string", "description": "Word or phrase to find"},
+							"replace":    map[string]interface{}{"type": "string", "description": "Replacement spoken in the matching voice"},
+							"matchIndex": map[string]interface{}{"type": "number", "description": "Which occurrence to replace, 0-based (default: all)"},
+						},
+						"required": []string{"search", "replace"},
+					},
+				},
+				"transcriptPath": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: Path to existing transcript JSON (will auto-generate if not provided)",
+				},
+				"voiceSamplePath": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: Path to audio sample for voice cloning (will extract from video if not provided)",
+				},
+				"voiceID": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional: Existing ElevenLabs voice ID to reuse",
+				},
+				"skipMissing": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Report words that are not in the transcript instead of failing (default: false)",
+				},
+			},
+			Required: []string{"input", "output", "replacements"},
+		},
+	}, s.handleReplaceSpokenWords)
+}
+
+func (s *MCPServer) handleReplaceSpokenWords(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input           string                  `json:"input"`
+		Output          string                  `json:"output"`
+		Replacements    []audio.WordReplacement `json:"replacements"`
+		TranscriptPath  string                  `json:"transcriptPath"`
+		VoiceSamplePath string                  `json:"voiceSamplePath"`
+		VoiceID         string                  `json:"voiceID"`
+		SkipMissing     bool                    `json:"skipMissing"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.audioReplacement.ReplaceWords(context.Background(), audio.BatchReplaceOptions{
+		VideoPath:       args.Input,
+		TranscriptPath:  args.TranscriptPath,
+		Replacements:    args.Replacements,
+		VoiceSamplePath: args.VoiceSamplePath,
+		VoiceID:         args.VoiceID,
+		SkipMissing:     args.SkipMissing,
+		OutputPath:      args.Output,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to replace words: %v", err)), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Successfully replaced %d match(es) in %s. Output saved to: %s\n\n", len(result.Replaced), args.Input, args.Output))
+	for _, match := range result.Replaced {
+		b.WriteString(fmt.Sprintf("- %.2fs-%.2fs: '%s' -> '%s'\n", match.Start, match.End, match.Text, match.Replace))
+	}
+	if len(result.Missing) > 0 {
+		b.WriteString(fmt.Sprintf("\nNot found: %s\n", strings.Join(result.Missing, ", ")))
+	}
+	b.WriteString(fmt.Sprintf("\nVoice ID: %s", result.VoiceID))
+	return mcp.NewToolResultText(b.String()), nil
+}
+
 // registerCloneVoiceFromAudio registers the clone_voice_from_audio MCP tool
 func (s *MCPServer) registerCloneVoiceFromAudio() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "clone_voice_from_audio",
 		Description: "Clone a voice from an audio sample using ElevenLabs and save the voice ID for reuse. Requires 30-60 seconds of clear speech.",
 		InputSchema: mcp.ToolInputSchema{
@@ -139,34 +261,56 @@ func (s *MCPServer) handleCloneVoiceFromAudio(arguments map[string]interface{})
 
 // registerGenerateSpeech registers the generate_speech MCP tool
 func (s *MCPServer) registerGenerateSpeech() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "generate_speech",
-		Description: "Generate text-to-speech audio using ElevenLabs with a specified voice ID. Creates natural-sounding speech from text.",
+		Description: "Generate text-to-speech audio with ElevenLabs, OpenAI, or a local Piper/Coqui engine. Creates natural-sounding speech from text.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
 				"text": map[string]interface{}{
 					"type":        "string",
-					"description": "Text to convert to speech",
+					"description": "Text to convert to speech. With markup 'simple': [pause 500ms], *emphasis*, {word|respelling} or {word|/ipa/}. With markup 'ssml': <break>, <emphasis>, <phoneme alphabet=\"ipa\"> and <sub alias>",
 				},
 				"output": map[string]interface{}{
 					"type":        "string",
 					"description": "Output audio file path",
 				},
+				"markup": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{audio.MarkupNone, audio.MarkupSimple, audio.MarkupSSML},
+					"description": "How to read text (default: ssml when text starts with <speak>, else none). The pronunciations dictionary from config applies either way",
+				},
+				"instructions": map[string]interface{}{
+					"type":        "string",
+					"description": "Delivery instructions for OpenAI gpt-4o voices, e.g. 'calm and warm'",
+				},
+				"provider": map[string]interface{}{
+					"type":        "string",
+					"enum":        audio.TTSProviders,
+					"description": "TTS provider (default: ttsProvider from config, then ElevenLabs, OpenAI or a local engine, whichever is set up)",
+				},
 				"voiceID": map[string]interface{}{
 					"type":        "string",
-					"description": "ElevenLabs voice ID (from clone_voice_from_audio or ElevenLabs dashboard)",
+					"description": "Voice: ElevenLabs voice ID (from clone_voice_from_audio or the ElevenLabs dashboard), OpenAI voice name (alloy, nova, onyx, ...) or local speaker ID",
+				},
+				"modelId": map[string]interface{}{
+					"type":        "string",
+					"description": "Provider model: ElevenLabs model, OpenAI model (default: gpt-4o-mini-tts), Piper .onnx voice or Coqui model name",
 				},
 				"stability": map[string]interface{}{
 					"type":        "number",
-					"description": "Voice stability 0.0-1.0 (default: 0.5, higher = more stable/monotone)",
+					"description": "ElevenLabs voice stability 0.0-1.0 (default: 0.5, higher = more stable/monotone)",
 				},
 				"similarity": map[string]interface{}{
 					"type":        "number",
-					"description": "Voice similarity boost 0.0-1.0 (default: 0.75, higher = closer to original)",
+					"description": "ElevenLabs voice similarity boost 0.0-1.0 (default: 0.75, higher = closer to original)",
+				},
+				"speed": map[string]interface{}{
+					"type":        "number",
+					"description": "Speaking rate for OpenAI and Piper (default: 1.0)",
 				},
 			},
-			Required: []string{"text", "output", "voiceID"},
+			Required: []string{"text", "output"},
 		},
 	}, s.handleGenerateSpeech)
 }
@@ -176,7 +320,12 @@ func (s *MCPServer) handleGenerateSpeech(arguments map[string]interface{}) (*mcp
 	// Parse arguments
 	text, _ := arguments["text"].(string)
 	output, _ := arguments["output"].(string)
+	provider, _ := arguments["provider"].(string)
 	voiceID, _ := arguments["voiceID"].(string)
+	modelID, _ := arguments["modelId"].(string)
+	speed, _ := arguments["speed"].(float64)
+	markup, _ := arguments["markup"].(string)
+	instructions, _ := arguments["instructions"].(string)
 	stability := 0.5
 	if s, ok := arguments["stability"].(float64); ok {
 		stability = s
@@ -186,23 +335,146 @@ func (s *MCPServer) handleGenerateSpeech(arguments map[string]interface{}) (*mcp
 		similarity = s
 	}
 
+	tts, err := s.ttsOps.Provider(provider)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to generate speech: %v", err)), nil
+	}
+
 	// Generate speech
-	err := s.ttsOps.GenerateSpeech(context.Background(), audio.SpeechOptions{
-		Text:       text,
-		VoiceID:    voiceID,
-		Stability:  stability,
-		Similarity: similarity,
+	err = s.ttsOps.GenerateSpeech(context.Background(), audio.SpeechOptions{
+		Text:         text,
+		Provider:     tts.Name(),
+		VoiceID:      voiceID,
+		ModelID:      modelID,
+		Stability:    stability,
+		Similarity:   similarity,
+		Speed:        speed,
+		Markup:       markup,
+		Instructions: instructions,
 	}, output)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to generate speech: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Speech generated successfully. Audio saved to: %s", output)), nil
+	return mcp.NewToolResultText(fmt.Sprintf("Speech generated successfully with %s. Audio saved to: %s", tts.Name(), output)), nil
+}
+
+// registerGenerateDialogue registers the generate_dialogue MCP tool
+func (s *MCPServer) registerGenerateDialogue() {
+	s.addTool(mcp.Tool{
+		Name:        "generate_dialogue",
+		Description: "Generate a multi-voice dialogue from a script with speaker labels. Synthesizes each line with its speaker's voice, spaces lines with gaps, and writes a mixed track plus one aligned stem per speaker.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"script": map[string]interface{}{
+					"type":        "string",
+					"description": "One 'SPEAKER: text' per line. Unlabelled lines continue the previous line; a '[pause 2s]' line sets the silence before the next line",
+				},
+				"lines": map[string]interface{}{
+					"type":        "array",
+					"description": "Lines as objects instead of a script",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"speaker": map[string]interface{}{"type": "string"},
+							"text":    map[string]interface{}{"type": "string"},
+							"pause":   map[string]interface{}{"type": "number", "description": "Silence before this line in seconds, instead of the gap"},
+						},
+						"required": []string{"speaker", "text"},
+					},
+				},
+				"voices": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": map[string]interface{}{"type": "string"},
+					"description":          "Voice per speaker, e.g. {\"ALICE\": \"nova\", \"BOB\": \"onyx\"}. Speaker names match case-insensitively",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Mixed dialogue audio file path",
+				},
+				"stemsDir": map[string]interface{}{
+					"type":        "string",
+					"description": "Directory for per-speaker stems named <output>_<speaker> (default: next to output)",
+				},
+				"provider": map[string]interface{}{
+					"type":        "string",
+					"enum":        audio.TTSProviders,
+					"description": "TTS provider for all lines (default: as generate_speech)",
+				},
+				"modelId": map[string]interface{}{
+					"type":        "string",
+					"description": "Provider model for all lines",
+				},
+				"markup": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{audio.MarkupNone, audio.MarkupSimple, audio.MarkupSSML},
+					"description": "Markup used in line text (default: none)",
+				},
+				"gap": map[string]interface{}{
+					"type":        "number",
+					"description": "Silence between lines in seconds (default: 0.35)",
+				},
+			},
+			Required: []string{"voices", "output"},
+		},
+	}, s.handleGenerateDialogue)
+}
+
+func (s *MCPServer) handleGenerateDialogue(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Script   string               `json:"script"`
+		Lines    []audio.DialogueLine `json:"lines"`
+		Voices   map[string]string    `json:"voices"`
+		Output   string               `json:"output"`
+		StemsDir string               `json:"stemsDir"`
+		Provider string               `json:"provider"`
+		ModelID  string               `json:"modelId"`
+		Markup   string               `json:"markup"`
+		Gap      float64              `json:"gap"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+	if args.Script == "" && len(args.Lines) == 0 {
+		return mcp.NewToolResultError("Either script or lines is required"), nil
+	}
+
+	result, err := s.ttsOps.GenerateDialogue(context.Background(), audio.DialogueOptions{
+		Script:   args.Script,
+		Lines:    args.Lines,
+		Voices:   args.Voices,
+		Provider: args.Provider,
+		ModelID:  args.ModelID,
+		Markup:   args.Markup,
+		Gap:      args.Gap,
+		Output:   args.Output,
+		StemsDir: args.StemsDir,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to generate dialogue: %v", err)), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Successfully generated %d-line dialogue (%.2fs): %s\n", len(result.Lines), result.Duration, result.Output))
+	b.WriteString("\nStems:\n")
+	listed := map[string]bool{}
+	for _, line := range result.Lines {
+		if stem, ok := result.Stems[line.Speaker]; ok && !listed[stem] {
+			listed[stem] = true
+			b.WriteString(fmt.Sprintf("- %s: %s\n", line.Speaker, stem))
+		}
+	}
+	b.WriteString("\nLines:\n")
+	for _, line := range result.Lines {
+		b.WriteString(fmt.Sprintf("- %.2fs-%.2fs %s: %s\n", line.Start, line.End, line.Speaker, line.Text))
+	}
+	return mcp.NewToolResultText(b.String()), nil
 }
 
 // registerGetWordTimestamps registers the get_word_timestamps MCP tool
 func (s *MCPServer) registerGetWordTimestamps() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "get_word_timestamps",
 		Description: "Extract transcript with word-level timestamps from video/audio using Whisper. Shows precise timing for each spoken word.",
 		InputSchema: mcp.ToolInputSchema{
@@ -279,7 +551,7 @@ func (s *MCPServer) handleGetWordTimestamps(arguments map[string]interface{}) (*
 
 // registerListCachedVoices registers the list_cached_voices MCP tool
 func (s *MCPServer) registerListCachedVoices() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "list_cached_voices",
 		Description: "List all cached voice clones. Shows voice IDs, names, and validation status. Cached voices can be reused across projects without re-cloning.",
 		InputSchema: mcp.ToolInputSchema{
@@ -291,7 +563,7 @@ func (s *MCPServer) registerListCachedVoices() {
 
 // registerClearCachedVoice registers the clear_cached_voice MCP tool
 func (s *MCPServer) registerClearCachedVoice() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "clear_cached_voice",
 		Description: "Remove a specific voice from the cache by its audio hash. Use list_cached_voices to see available hashes.",
 		InputSchema: mcp.ToolInputSchema{
@@ -309,7 +581,7 @@ func (s *MCPServer) registerClearCachedVoice() {
 
 // registerClearAllCachedVoices registers the clear_all_cached_voices MCP tool
 func (s *MCPServer) registerClearAllCachedVoices() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "clear_all_cached_voices",
 		Description: "Clear all cached voice clones. This will require re-cloning voices if needed again.",
 		InputSchema: mcp.ToolInputSchema{
@@ -377,3 +649,87 @@ func (s *MCPServer) handleClearAllCachedVoices(arguments map[string]interface{})
 
 	return mcp.NewToolResultText(fmt.Sprintf("Successfully cleared %d cached voice(s)", count)), nil
 }
+
+// registerExportVoiceCache registers the export_voice_cache MCP tool
+func (s *MCPServer) registerExportVoiceCache() {
+	s.addTool(mcp.Tool{
+		Name:        "export_voice_cache",
+		Description: "Export the cached voice clones (audio hashes, voice IDs, names and sources) to a JSON file that import_voice_cache can load on another machine.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Path of the JSON file to write",
+				},
+			},
+			Required: []string{"output"},
+		},
+	}, s.handleExportVoiceCache)
+}
+
+func (s *MCPServer) handleExportVoiceCache(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Output string `json:"output"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	count, err := s.ttsOps.ExportVoiceCache(args.Output)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to export voice cache: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully exported %d cached voice(s) to %s", count, args.Output)), nil
+}
+
+// registerImportVoiceCache registers the import_voice_cache MCP tool
+func (s *MCPServer) registerImportVoiceCache() {
+	s.addTool(mcp.Tool{
+		Name:        "import_voice_cache",
+		Description: "Import cached voice clones exported by export_voice_cache, so word replacement and dubbing reuse them instead of cloning again. Voice IDs only work with the ElevenLabs account that created them.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Exported voice cache JSON file",
+				},
+				"overwrite": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Replace voices already cached for the same audio (default: false, keep existing)",
+				},
+				"verify": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Check each voice exists in the configured ElevenLabs account and skip those that don't (default: false)",
+				},
+			},
+			Required: []string{"input"},
+		},
+	}, s.handleImportVoiceCache)
+}
+
+func (s *MCPServer) handleImportVoiceCache(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input     string `json:"input"`
+		Overwrite bool   `json:"overwrite"`
+		Verify    bool   `json:"verify"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.ttsOps.ImportVoiceCache(context.Background(), args.Input, args.Overwrite, args.Verify)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to import voice cache: %v", err)), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Successfully imported voice cache from %s: %d added, %d updated, %d skipped",
+		args.Input, result.Added, result.Updated, result.Skipped))
+	if len(result.Missing) > 0 {
+		b.WriteString(fmt.Sprintf("\n\nNot in this ElevenLabs account (not imported): %s", strings.Join(result.Missing, ", ")))
+	}
+	return mcp.NewToolResultText(b.String()), nil
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/chandler-mayo/mcp-video-editor/pkg/audio"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -10,7 +11,7 @@ import (
 
 // registerTrimAudio registers the trim_audio MCP tool
 func (s *MCPServer) registerTrimAudio() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "trim_audio",
 		Description: "Trim audio file to specified time range. Cut out a segment from start to end time.",
 		InputSchema: mcp.ToolInputSchema{
@@ -62,9 +63,9 @@ func (s *MCPServer) handleTrimAudio(arguments map[string]interface{}) (*mcp.Call
 
 // registerConcatenateAudio registers the concatenate_audio MCP tool
 func (s *MCPServer) registerConcatenateAudio() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "concatenate_audio",
-		Description: "Join multiple audio files together into one continuous audio file. Files will be joined in the order provided.",
+		Description: "Join multiple audio files together into one continuous audio file. Files will be joined in the order provided, optionally crossfading between them.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -79,6 +80,10 @@ func (s *MCPServer) registerConcatenateAudio() {
 					"type":        "string",
 					"description": "Output audio file path",
 				},
+				"crossfade": map[string]interface{}{
+					"type":        "number",
+					"description": "Crossfade between files in seconds (default: 0, joined back to back without re-encoding)",
+				},
 			},
 			Required: []string{"inputs", "output"},
 		},
@@ -96,9 +101,12 @@ func (s *MCPServer) handleConcatenateAudio(arguments map[string]interface{}) (*m
 		}
 	}
 
+	crossfade, _ := arguments["crossfade"].(float64)
+
 	if err := s.audioOps.ConcatenateAudio(context.Background(), audio.ConcatenateOptions{
-		Inputs: inputs,
-		Output: output,
+		Inputs:    inputs,
+		Output:    output,
+		Crossfade: crossfade,
 	}); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to concatenate audio: %v", err)), nil
 	}
@@ -106,9 +114,71 @@ func (s *MCPServer) handleConcatenateAudio(arguments map[string]interface{}) (*m
 	return mcp.NewToolResultText(fmt.Sprintf("Successfully concatenated %d audio files. Output: %s", len(inputs), output)), nil
 }
 
+// registerCrossfadeAudio registers the crossfade_audio MCP tool
+func (s *MCPServer) registerCrossfadeAudio() {
+	s.addTool(mcp.Tool{
+		Name:        "crossfade_audio",
+		Description: "Join audio files so each one fades out while the next fades in. Choose the fade curve, e.g. qsin for a smooth music transition or exp for a quick duck.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"inputs": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Audio files in order (at least 2)",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output audio file path",
+				},
+				"duration": map[string]interface{}{
+					"type":        "number",
+					"description": "Crossfade length in seconds (default: 2)",
+				},
+				"curve": map[string]interface{}{
+					"type":        "string",
+					"enum":        audio.CrossfadeCurves,
+					"description": "Fade curve (default: tri, linear)",
+				},
+				"inCurve": map[string]interface{}{
+					"type":        "string",
+					"enum":        audio.CrossfadeCurves,
+					"description": "Separate fade-in curve for the incoming file (default: same as curve)",
+				},
+			},
+			Required: []string{"inputs", "output"},
+		},
+	}, s.handleCrossfadeAudio)
+}
+
+func (s *MCPServer) handleCrossfadeAudio(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Inputs   []string `json:"inputs"`
+		Output   string   `json:"output"`
+		Duration float64  `json:"duration"`
+		Curve    string   `json:"curve"`
+		InCurve  string   `json:"inCurve"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	if err := s.audioOps.CrossfadeAudio(context.Background(), audio.CrossfadeOptions{
+		Inputs:   args.Inputs,
+		Output:   args.Output,
+		Duration: args.Duration,
+		Curve:    args.Curve,
+		InCurve:  args.InCurve,
+	}); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to crossfade audio: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully crossfaded %d audio files. Output: %s", len(args.Inputs), args.Output)), nil
+}
+
 // registerAdjustAudioVolume registers the adjust_audio_volume MCP tool
 func (s *MCPServer) registerAdjustAudioVolume() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "adjust_audio_volume",
 		Description: "Adjust the volume of an audio file. Use multiplier: 0.5 for 50%, 1.0 for 100%, 2.0 for 200%.",
 		InputSchema: mcp.ToolInputSchema{
@@ -151,7 +221,7 @@ func (s *MCPServer) handleAdjustAudioVolume(arguments map[string]interface{}) (*
 
 // registerNormalizeAudio registers the normalize_audio MCP tool
 func (s *MCPServer) registerNormalizeAudio() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "normalize_audio",
 		Description: "Normalize audio levels to a consistent volume. Useful for evening out quiet and loud sections.",
 		InputSchema: mcp.ToolInputSchema{
@@ -184,7 +254,7 @@ func (s *MCPServer) handleNormalizeAudio(arguments map[string]interface{}) (*mcp
 
 // registerFadeAudio registers the fade_audio MCP tool
 func (s *MCPServer) registerFadeAudio() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "fade_audio",
 		Description: "Apply fade in and/or fade out effects to audio. Smoothly increase volume at start and/or decrease at end.",
 		InputSchema: mcp.ToolInputSchema{
@@ -232,7 +302,7 @@ func (s *MCPServer) handleFadeAudio(arguments map[string]interface{}) (*mcp.Call
 
 // registerMixAudio registers the mix_audio MCP tool
 func (s *MCPServer) registerMixAudio() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "mix_audio",
 		Description: "Mix multiple audio tracks together into one. Combines all inputs into a single audio file. Optionally adjust volume for each track.",
 		InputSchema: mcp.ToolInputSchema{
@@ -295,7 +365,7 @@ func (s *MCPServer) handleMixAudio(arguments map[string]interface{}) (*mcp.CallT
 
 // registerConvertAudio registers the convert_audio MCP tool
 func (s *MCPServer) registerConvertAudio() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "convert_audio",
 		Description: "Convert audio to different format, bitrate, sample rate, or channel configuration. Supports mp3, aac, wav, flac, opus, ogg.",
 		InputSchema: mcp.ToolInputSchema{
@@ -361,7 +431,7 @@ func (s *MCPServer) handleConvertAudio(arguments map[string]interface{}) (*mcp.C
 
 // registerAdjustAudioSpeed registers the adjust_audio_speed MCP tool
 func (s *MCPServer) registerAdjustAudioSpeed() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "adjust_audio_speed",
 		Description: "Change audio playback speed without changing pitch. 0.5 = half speed, 1.0 = normal, 2.0 = double speed.",
 		InputSchema: mcp.ToolInputSchema{
@@ -403,7 +473,7 @@ func (s *MCPServer) handleAdjustAudioSpeed(arguments map[string]interface{}) (*m
 
 // registerRemoveAudioSection registers the remove_audio_section MCP tool
 func (s *MCPServer) registerRemoveAudioSection() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "remove_audio_section",
 		Description: "Remove a section of audio between start and end times. Keeps everything before and after the specified range.",
 		InputSchema: mcp.ToolInputSchema{
@@ -447,7 +517,7 @@ func (s *MCPServer) handleRemoveAudioSection(arguments map[string]interface{}) (
 
 // registerSplitAudio registers the split_audio MCP tool
 func (s *MCPServer) registerSplitAudio() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "split_audio",
 		Description: "Split audio file into multiple segments of specified duration. Useful for breaking long audio into chapters.",
 		InputSchema: mcp.ToolInputSchema{
@@ -485,7 +555,7 @@ func (s *MCPServer) handleSplitAudio(arguments map[string]interface{}) (*mcp.Cal
 
 // registerReverseAudio registers the reverse_audio MCP tool
 func (s *MCPServer) registerReverseAudio() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "reverse_audio",
 		Description: "Reverse audio playback (play backwards). Creates interesting effects or reverses accidentally reversed audio.",
 		InputSchema: mcp.ToolInputSchema{
@@ -518,7 +588,7 @@ func (s *MCPServer) handleReverseAudio(arguments map[string]interface{}) (*mcp.C
 
 // registerExtractAudioChannel registers the extract_audio_channel MCP tool
 func (s *MCPServer) registerExtractAudioChannel() {
-	s.server.AddTool(mcp.Tool{
+	s.addTool(mcp.Tool{
 		Name:        "extract_audio_channel",
 		Description: "Extract a specific channel from stereo audio (left or right). Converts stereo to mono by selecting one channel.",
 		InputSchema: mcp.ToolInputSchema{
@@ -554,3 +624,506 @@ func (s *MCPServer) handleExtractAudioChannel(arguments map[string]interface{})
 
 	return mcp.NewToolResultText(fmt.Sprintf("Extracted %s channel successfully. Output: %s", channel, output)), nil
 }
+
+// registerGenerateWaveformImage registers the generate_waveform_image MCP tool
+func (s *MCPServer) registerGenerateWaveformImage() {
+	s.addTool(mcp.Tool{
+		Name:        "generate_waveform_image",
+		Description: "Render the waveform of an audio or video file as a single image. Useful for podcast artwork, thumbnails, and visual overviews of a recording.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input audio or video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output image path (png keeps a transparent background)",
+				},
+				"width": map[string]interface{}{
+					"type":        "number",
+					"description": "Image width in pixels (default: 1920)",
+				},
+				"height": map[string]interface{}{
+					"type":        "number",
+					"description": "Image height in pixels (default: 240)",
+				},
+				"color": map[string]interface{}{
+					"type":        "string",
+					"description": "Waveform color name or hex like 0x3b82f6 (default: white)",
+				},
+				"background": map[string]interface{}{
+					"type":        "string",
+					"description": "Background color (default: transparent)",
+				},
+				"splitChannels": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Draw each channel separately (default: false)",
+				},
+				"scale": map[string]interface{}{
+					"type":        "string",
+					"description": "Amplitude scale (default: sqrt)",
+					"enum":        []string{"lin", "log", "sqrt", "cbrt"},
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleGenerateWaveformImage)
+}
+
+func (s *MCPServer) handleGenerateWaveformImage(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	input, _ := arguments["input"].(string)
+	output, _ := arguments["output"].(string)
+
+	opts := audio.WaveformImageOptions{
+		Input:  input,
+		Output: output,
+	}
+	if width, ok := arguments["width"].(float64); ok {
+		opts.Width = int(width)
+	}
+	if height, ok := arguments["height"].(float64); ok {
+		opts.Height = int(height)
+	}
+	opts.Color, _ = arguments["color"].(string)
+	opts.Background, _ = arguments["background"].(string)
+	opts.SplitChannels, _ = arguments["splitChannels"].(bool)
+	opts.Scale, _ = arguments["scale"].(string)
+
+	if err := s.audioOps.GenerateWaveformImage(context.Background(), opts); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to generate waveform image: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Waveform image generated successfully. Output: %s", output)), nil
+}
+
+// registerCreateAudiogram registers the create_audiogram MCP tool
+func (s *MCPServer) registerCreateAudiogram() {
+	s.addTool(mcp.Tool{
+		Name:        "create_audiogram",
+		Description: "Turn audio (e.g. a podcast clip) into a shareable video with an animated waveform, frequency bars, or spectrum over cover art or a solid color, with optional burned-in captions.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input audio or video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"style": map[string]interface{}{
+					"type":        "string",
+					"description": "Visualization style (default: waves)",
+					"enum":        []string{"waves", "bars", "spectrum"},
+				},
+				"width": map[string]interface{}{
+					"type":        "number",
+					"description": "Video width (default: 1080)",
+				},
+				"height": map[string]interface{}{
+					"type":        "number",
+					"description": "Video height (default: 1080)",
+				},
+				"fps": map[string]interface{}{
+					"type":        "number",
+					"description": "Frame rate (default: 30)",
+				},
+				"color": map[string]interface{}{
+					"type":        "string",
+					"description": "Visualization color (default: white)",
+				},
+				"background": map[string]interface{}{
+					"type":        "string",
+					"description": "Background color when no cover image is given (default: black)",
+				},
+				"coverImage": map[string]interface{}{
+					"type":        "string",
+					"description": "Cover art image used as the background (optional)",
+				},
+				"subtitles": map[string]interface{}{
+					"type":        "string",
+					"description": "SRT or ASS captions file to burn in (optional)",
+				},
+				"start": map[string]interface{}{
+					"type":        "number",
+					"description": "Start time of the clip in seconds (default: 0)",
+				},
+				"duration": map[string]interface{}{
+					"type":        "number",
+					"description": "Clip length in seconds (default: to end)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleCreateAudiogram)
+}
+
+func (s *MCPServer) handleCreateAudiogram(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	input, _ := arguments["input"].(string)
+	output, _ := arguments["output"].(string)
+
+	opts := audio.AudiogramOptions{
+		Input:  input,
+		Output: output,
+	}
+	opts.Style, _ = arguments["style"].(string)
+	if width, ok := arguments["width"].(float64); ok {
+		opts.Width = int(width)
+	}
+	if height, ok := arguments["height"].(float64); ok {
+		opts.Height = int(height)
+	}
+	if fps, ok := arguments["fps"].(float64); ok {
+		opts.FPS = int(fps)
+	}
+	opts.Color, _ = arguments["color"].(string)
+	opts.Background, _ = arguments["background"].(string)
+	opts.CoverImage, _ = arguments["coverImage"].(string)
+	opts.Subtitles, _ = arguments["subtitles"].(string)
+	opts.Start, _ = arguments["start"].(float64)
+	opts.Duration, _ = arguments["duration"].(float64)
+
+	if err := s.audioOps.CreateAudiogram(context.Background(), opts); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create audiogram: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Audiogram created successfully. Output: %s", output)), nil
+}
+
+// registerSeparateStems registers the separate_stems MCP tool
+func (s *MCPServer) registerSeparateStems() {
+	s.addTool(mcp.Tool{
+		Name:        "separate_stems",
+		Description: "Split audio into stems (vocals and accompaniment, or vocals/drums/bass/other) with Demucs or Spleeter when installed, falling back to FFmpeg voice isolation. Set keep and output to rebuild the clip with only some stems, e.g. keep vocals to remove background music.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input audio or video file path",
+				},
+				"outputDir": map[string]interface{}{
+					"type":        "string",
+					"description": "Directory for the stem WAV files (default: <input>_stems)",
+				},
+				"engine": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{audio.StemEngineAuto, audio.StemEngineDemucs, audio.StemEngineSpleeter, audio.StemEngineFFmpeg},
+					"description": "Separation engine (default: auto, which tries demucs, then spleeter, then ffmpeg)",
+				},
+				"stems": map[string]interface{}{
+					"type":        "number",
+					"enum":        []int{2, 4},
+					"description": "2 for vocals/accompaniment, 4 for vocals/drums/bass/other (default: 2; 4 needs demucs or spleeter)",
+				},
+				"model": map[string]interface{}{
+					"type":        "string",
+					"description": "Demucs model name (default: htdemucs), or an RNNoise .rnnn model file for the ffmpeg engine",
+				},
+				"keep": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Stems to keep in a rebuilt copy of the input, e.g. [\"vocals\"] (requires output)",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output file for the rebuilt copy; video is copied unchanged",
+				},
+			},
+			Required: []string{"input"},
+		},
+	}, s.handleSeparateStems)
+}
+
+func (s *MCPServer) handleSeparateStems(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input     string   `json:"input"`
+		OutputDir string   `json:"outputDir"`
+		Engine    string   `json:"engine"`
+		Stems     int      `json:"stems"`
+		Model     string   `json:"model"`
+		Keep      []string `json:"keep"`
+		Output    string   `json:"output"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.audioOps.SeparateStems(context.Background(), audio.StemOptions{
+		Input:     args.Input,
+		OutputDir: args.OutputDir,
+		Engine:    args.Engine,
+		Stems:     args.Stems,
+		Model:     args.Model,
+		Keep:      args.Keep,
+		Output:    args.Output,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to separate stems: %v", err)), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Successfully separated stems with %s\n", result.Engine))
+	for _, name := range []string{audio.StemVocals, audio.StemAccompaniment, audio.StemDrums, audio.StemBass, audio.StemOther} {
+		if path, ok := result.Stems[name]; ok {
+			b.WriteString(fmt.Sprintf("  %s: %s\n", name, path))
+		}
+	}
+	if result.Output != "" {
+		b.WriteString(fmt.Sprintf("Rebuilt with %s: %s\n", strings.Join(args.Keep, ", "), result.Output))
+	}
+	for _, warning := range result.Warnings {
+		b.WriteString(fmt.Sprintf("Warning: %s\n", warning))
+	}
+
+	return mcp.NewToolResultText(b.String()), nil
+}
+
+// registerPitchShift registers the pitch_shift MCP tool
+func (s *MCPServer) registerPitchShift() {
+	s.addTool(mcp.Tool{
+		Name:        "pitch_shift",
+		Description: "Shift the pitch of audio (or a video's audio) by semitones, keeping the tempo or letting it change like tape. Uses FFmpeg's rubberband filter when available, which can also preserve voice formants.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input audio or video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output file path",
+				},
+				"semitones": map[string]interface{}{
+					"type":        "number",
+					"description": "Semitones to shift, -24 to 24 (e.g. 12 for an octave up, -0.5 for a quarter tone down)",
+				},
+				"changeTempo": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Let the tempo change with the pitch, like speeding up a tape (default: false, duration is kept)",
+				},
+				"preserveFormants": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Keep voices natural instead of chipmunk-like; needs rubberband (default: false)",
+				},
+			},
+			Required: []string{"input", "output", "semitones"},
+		},
+	}, s.handlePitchShift)
+}
+
+func (s *MCPServer) handlePitchShift(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input            string  `json:"input"`
+		Output           string  `json:"output"`
+		Semitones        float64 `json:"semitones"`
+		ChangeTempo      bool    `json:"changeTempo"`
+		PreserveFormants bool    `json:"preserveFormants"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.audioOps.PitchShift(context.Background(), audio.PitchOptions{
+		Input:            args.Input,
+		Output:           args.Output,
+		Semitones:        args.Semitones,
+		ChangeTempo:      args.ChangeTempo,
+		PreserveFormants: args.PreserveFormants,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to shift pitch: %v", err)), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Successfully shifted pitch by %+g semitones (ratio %.4f) with %s: %s\n",
+		args.Semitones, result.Ratio, result.Engine, args.Output))
+	for _, warning := range result.Warnings {
+		b.WriteString(fmt.Sprintf("Warning: %s\n", warning))
+	}
+
+	return mcp.NewToolResultText(b.String()), nil
+}
+
+// registerCleanVoice registers the clean_voice MCP tool
+func (s *MCPServer) registerCleanVoice() {
+	s.addTool(mcp.Tool{
+		Name:        "clean_voice",
+		Description: "Fix a voiceover in one call: high-pass filter for rumble and plosives, de-esser for harsh sibilance, gentle compression and loudness normalization. Works on audio or a video's audio (video is copied).",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input audio or video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output file path",
+				},
+				"intensity": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{audio.IntensityLight, audio.IntensityMedium, audio.IntensityStrong},
+					"description": "How hard to process (default: medium)",
+				},
+				"targetLufs": map[string]interface{}{
+					"type":        "number",
+					"description": "Integrated loudness target (default: -16; -14 for streaming platforms, -23 for broadcast)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleCleanVoice)
+}
+
+func (s *MCPServer) handleCleanVoice(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input      string  `json:"input"`
+		Output     string  `json:"output"`
+		Intensity  string  `json:"intensity"`
+		TargetLUFS float64 `json:"targetLufs"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	if err := s.audioOps.CleanVoice(context.Background(), audio.CleanVoiceOptions{
+		Input:      args.Input,
+		Output:     args.Output,
+		Intensity:  args.Intensity,
+		TargetLUFS: args.TargetLUFS,
+	}); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to clean voice: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully cleaned voice: %s", args.Output)), nil
+}
+
+// registerGenerateSilence registers the generate_silence MCP tool
+func (s *MCPServer) registerGenerateSilence() {
+	s.addTool(mcp.Tool{
+		Name:        "generate_silence",
+		Description: "Create a silent audio file of a given length, e.g. to fill a gap or pad a track.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output audio file path",
+				},
+				"duration": map[string]interface{}{
+					"type":        "number",
+					"description": "Length in seconds",
+				},
+				"sampleRate": map[string]interface{}{
+					"type":        "number",
+					"description": "Sample rate in Hz (default: 48000)",
+				},
+				"channels": map[string]interface{}{
+					"type":        "number",
+					"description": "1 for mono, 2 for stereo (default: 2)",
+				},
+			},
+			Required: []string{"output", "duration"},
+		},
+	}, s.handleGenerateSilence)
+}
+
+func (s *MCPServer) handleGenerateSilence(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Output     string  `json:"output"`
+		Duration   float64 `json:"duration"`
+		SampleRate int     `json:"sampleRate"`
+		Channels   int     `json:"channels"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	if err := s.audioOps.GenerateSilence(context.Background(), audio.SilenceOptions{
+		Output:     args.Output,
+		Duration:   args.Duration,
+		SampleRate: args.SampleRate,
+		Channels:   args.Channels,
+	}); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to generate silence: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully generated %.2fs of silence: %s", args.Duration, args.Output)), nil
+}
+
+// registerGenerateTone registers the generate_tone MCP tool
+func (s *MCPServer) registerGenerateTone() {
+	s.addTool(mcp.Tool{
+		Name:        "generate_tone",
+		Description: "Create a sine tone or white/pink/brown noise, e.g. a 1kHz sync beep, a test signal or a censor bleep.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output audio file path",
+				},
+				"duration": map[string]interface{}{
+					"type":        "number",
+					"description": "Length in seconds (e.g. 0.042 for a one-frame 2-pop at 24fps)",
+				},
+				"waveform": map[string]interface{}{
+					"type":        "string",
+					"enum":        audio.ToneWaveforms,
+					"description": "sine tone or noise color (default: sine)",
+				},
+				"frequency": map[string]interface{}{
+					"type":        "number",
+					"description": "Sine frequency in Hz (default: 1000)",
+				},
+				"amplitude": map[string]interface{}{
+					"type":        "number",
+					"description": "Level from 0 to 1 (default: 0.5)",
+				},
+				"sampleRate": map[string]interface{}{
+					"type":        "number",
+					"description": "Sample rate in Hz (default: 48000)",
+				},
+				"channels": map[string]interface{}{
+					"type":        "number",
+					"description": "1 for mono, 2 for stereo (default: 2)",
+				},
+			},
+			Required: []string{"output", "duration"},
+		},
+	}, s.handleGenerateTone)
+}
+
+func (s *MCPServer) handleGenerateTone(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Output     string  `json:"output"`
+		Duration   float64 `json:"duration"`
+		Waveform   string  `json:"waveform"`
+		Frequency  float64 `json:"frequency"`
+		Amplitude  float64 `json:"amplitude"`
+		SampleRate int     `json:"sampleRate"`
+		Channels   int     `json:"channels"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	if err := s.audioOps.GenerateTone(context.Background(), audio.ToneOptions{
+		Output:     args.Output,
+		Duration:   args.Duration,
+		Waveform:   args.Waveform,
+		Frequency:  args.Frequency,
+		Amplitude:  args.Amplitude,
+		SampleRate: args.SampleRate,
+		Channels:   args.Channels,
+	}); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to generate tone: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully generated %.2fs tone: %s", args.Duration, args.Output)), nil
+}
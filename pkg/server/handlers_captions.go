@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/captions"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerAutoCaption registers the auto_caption MCP tool
+func (s *MCPServer) registerAutoCaption() {
+	s.addTool(mcp.Tool{
+		Name:        "auto_caption",
+		Description: "Caption a video in one call: transcribe with Whisper, split into word- or segment-level captions, then burn them in with a style preset or attach them as a soft subtitle track. Requires OpenAI API key unless transcriptPath is given.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path. The transcript and caption file are saved next to it.",
+				},
+				"mode": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{captions.ModeBurn, captions.ModeAttach},
+					"description": "burn renders captions into the picture; attach adds a toggleable subtitle track (default: burn)",
+				},
+				"preset": map[string]interface{}{
+					"type":        "string",
+					"enum":        captions.PresetNames,
+					"description": "Caption style: karaoke (large words highlighted as spoken), classic (bottom subtitles) or lower-third (boxed text at lower left) (default: classic)",
+				},
+				"level": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{captions.LevelWord, captions.LevelSegment},
+					"description": "word shows a few words at a time; segment shows whole sentences (default: the preset's level)",
+				},
+				"maxWords": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum words per caption at word level (default: the preset's)",
+				},
+				"language": map[string]interface{}{
+					"type":        "string",
+					"description": "Language code for transcription (e.g. 'en', optional)",
+				},
+				"transcriptPath": map[string]interface{}{
+					"type":        "string",
+					"description": "Reuse a transcript JSON saved by extract_transcript or a previous run instead of transcribing",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleAutoCaption)
+}
+
+func (s *MCPServer) handleAutoCaption(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input          string `json:"input"`
+		Output         string `json:"output"`
+		Mode           string `json:"mode"`
+		Preset         string `json:"preset"`
+		Level          string `json:"level"`
+		MaxWords       int    `json:"maxWords"`
+		Language       string `json:"language"`
+		TranscriptPath string `json:"transcriptPath"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.captionPipeline.AutoCaption(context.Background(), captions.Options{
+		Input:          args.Input,
+		Output:         args.Output,
+		Mode:           args.Mode,
+		Preset:         args.Preset,
+		Level:          args.Level,
+		MaxWords:       args.MaxWords,
+		Language:       args.Language,
+		TranscriptPath: args.TranscriptPath,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to caption video: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("CAPTIONS ADDED: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Mode: %s\n", result.Mode))
+	out.WriteString(fmt.Sprintf("Preset: %s\n", result.Preset))
+	out.WriteString(fmt.Sprintf("Level: %s\n", result.Level))
+	out.WriteString(fmt.Sprintf("Captions: %d\n", result.Captions))
+	if result.Language != "" {
+		out.WriteString(fmt.Sprintf("Language: %s\n", result.Language))
+	}
+
+	out.WriteString("\nOUTPUTS:\n")
+	out.WriteString(fmt.Sprintf("- Video: %s\n", result.Output))
+	out.WriteString(fmt.Sprintf("- Captions: %s\n", result.CaptionsFile))
+	out.WriteString(fmt.Sprintf("- Transcript: %s\n", result.TranscriptFile))
+
+	if len(result.Warnings) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range result.Warnings {
+			out.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// registerKaraokeCaptions registers the karaoke_captions MCP tool
+func (s *MCPServer) registerKaraokeCaptions() {
+	s.addTool(mcp.Tool{
+		Name:        "karaoke_captions",
+		Description: "Burn word-by-word karaoke captions that highlight each word as it's spoken, using Whisper word timestamps. Requires OpenAI API key unless transcriptPath is given.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path. The transcript and ASS script are saved next to it.",
+				},
+				"fontName": map[string]interface{}{
+					"type":        "string",
+					"description": "Font family (default: Arial Black)",
+				},
+				"fontSize": map[string]interface{}{
+					"type":        "number",
+					"description": "Font size at 1080p, scaled to the video (default: 84)",
+				},
+				"textColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Color of words not yet spoken, as a name or #RRGGBB (default: white)",
+				},
+				"highlightColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Color of spoken words (default: yellow)",
+				},
+				"outlineColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Text outline color (default: black)",
+				},
+				"animation": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{captions.AnimationPop, captions.AnimationFill, captions.AnimationInstant},
+					"description": "pop grows each word briefly as it's spoken, fill sweeps the highlight across the word, none switches color instantly (default: pop)",
+				},
+				"popScale": map[string]interface{}{
+					"type":        "number",
+					"description": "Peak word size in percent for the pop animation (default: 125)",
+				},
+				"wordsPerLine": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum words shown at once (default: 3)",
+				},
+				"position": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"bottom", "center", "top"},
+					"description": "Caption position (default: bottom)",
+				},
+				"uppercase": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Show captions in capitals (default: true)",
+				},
+				"language": map[string]interface{}{
+					"type":        "string",
+					"description": "Language code for transcription (e.g. 'en', optional)",
+				},
+				"transcriptPath": map[string]interface{}{
+					"type":        "string",
+					"description": "Reuse a transcript JSON with word timestamps instead of transcribing",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleKaraokeCaptions)
+}
+
+func (s *MCPServer) handleKaraokeCaptions(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input          string `json:"input"`
+		Output         string `json:"output"`
+		FontName       string `json:"fontName"`
+		FontSize       int    `json:"fontSize"`
+		TextColor      string `json:"textColor"`
+		HighlightColor string `json:"highlightColor"`
+		OutlineColor   string `json:"outlineColor"`
+		Animation      string `json:"animation"`
+		PopScale       int    `json:"popScale"`
+		WordsPerLine   int    `json:"wordsPerLine"`
+		Position       string `json:"position"`
+		Uppercase      *bool  `json:"uppercase"`
+		Language       string `json:"language"`
+		TranscriptPath string `json:"transcriptPath"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.captionPipeline.Karaoke(context.Background(), captions.KaraokeOptions{
+		Input:          args.Input,
+		Output:         args.Output,
+		FontName:       args.FontName,
+		FontSize:       args.FontSize,
+		TextColor:      args.TextColor,
+		HighlightColor: args.HighlightColor,
+		OutlineColor:   args.OutlineColor,
+		Animation:      args.Animation,
+		PopScale:       args.PopScale,
+		WordsPerLine:   args.WordsPerLine,
+		Position:       args.Position,
+		Uppercase:      args.Uppercase,
+		Language:       args.Language,
+		TranscriptPath: args.TranscriptPath,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to add karaoke captions: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("Karaoke captions added: %s\n", result.Output))
+	out.WriteString(fmt.Sprintf("Captions: %d\n", result.Captions))
+	out.WriteString(fmt.Sprintf("ASS script: %s\n", result.CaptionsFile))
+	out.WriteString(fmt.Sprintf("Transcript: %s\n", result.TranscriptFile))
+
+	return mcp.NewToolResultText(out.String()), nil
+}
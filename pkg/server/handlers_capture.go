@@ -0,0 +1,331 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/capture"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerCaptureTools registers the start_recording, stop_recording,
+// list_recordings, stream_video and stop_stream MCP tools
+func (s *MCPServer) registerCaptureTools() {
+	s.addTool(mcp.Tool{
+		Name:        "start_recording",
+		Description: "Start recording the screen in the background, optionally with the microphone and a webcam picture-in-picture, and return a recording id for stop_recording. Uses avfoundation on macOS (grant the app Screen Recording, Camera and Microphone permission), x11grab and PulseAudio on Linux, and gdigrab and DirectShow on Windows. The file is encoded fast for real time; re-encode it to shrink it.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: withCaptureProperties(map[string]interface{}{
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path (mp4 or mkv; mkv survives a crash mid-recording). Must be local: the file is still being written when the tool returns",
+				},
+				"maxDuration": map[string]interface{}{
+					"type":        "number",
+					"description": "Stop on its own after this many seconds (default: record until stop_recording)",
+				},
+			}),
+			Required: []string{"output"},
+		},
+	}, s.handleStartRecording)
+
+	s.addTool(mcp.Tool{
+		Name:        "stop_recording",
+		Description: "Stop a recording started with start_recording and finish writing its file",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"id": map[string]interface{}{
+					"type":        "string",
+					"description": "Recording id returned by start_recording",
+				},
+			},
+			Required: []string{"id"},
+		},
+	}, s.handleStopRecording)
+
+	s.addTool(mcp.Tool{
+		Name:        "list_recordings",
+		Description: "List recordings that haven't been stopped, with their ids and outputs",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+			Required:   []string{},
+		},
+	}, s.handleListRecordings)
+
+	s.addTool(mcp.Tool{
+		Name:        "stream_video",
+		Description: "Stream a video file or a live screen capture to an RTMP or SRT endpoint in the background, such as YouTube Live or Twitch, and return a stream id for stop_stream. Encodes constant bitrate H.264 with 2 second keyframes and AAC, as live platforms expect. When the connection drops, the stream reconnects with increasing delays and a file resumes about where it left off. Capture arguments are as for start_recording and apply when no input is given.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: withCaptureProperties(map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Video file to stream (omit to stream a screen capture)",
+				},
+				"platform": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"youtube", "twitch"},
+					"description": "Stream to the platform's ingest server with its recommended settings (needs streamKey)",
+				},
+				"url": map[string]interface{}{
+					"type":        "string",
+					"description": "Custom rtmp://, rtmps:// or srt:// endpoint, instead of a platform",
+				},
+				"streamKey": map[string]interface{}{
+					"type":        "string",
+					"description": "Stream key, appended to the platform's or custom RTMP URL; it is never shown in results",
+				},
+				"bitrate": map[string]interface{}{
+					"type":        "number",
+					"description": "Video bitrate in kbit/s (default: 6000 for YouTube and Twitch, otherwise 4500)",
+				},
+				"loop": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Repeat the file until stop_stream (default: false)",
+				},
+				"maxRetries": map[string]interface{}{
+					"type":        "number",
+					"description": "Reconnect attempts after the connection drops, -1 for none (default: 5)",
+				},
+			}),
+			Required: []string{},
+		},
+	}, s.handleStreamVideo)
+
+	s.addTool(mcp.Tool{
+		Name:        "stop_stream",
+		Description: "Stop a stream started with stream_video, or list the streams when no id is given",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"id": map[string]interface{}{
+					"type":        "string",
+					"description": "Stream id returned by stream_video (omit to list streams)",
+				},
+			},
+			Required: []string{},
+		},
+	}, s.handleStopStream)
+}
+
+// withCaptureProperties adds the screen, webcam and microphone arguments
+// shared by start_recording and stream_video to a tool's properties
+func withCaptureProperties(properties map[string]interface{}) map[string]interface{} {
+	for name, property := range map[string]interface{}{
+		"fps": map[string]interface{}{
+			"type":        "number",
+			"description": "Capture frame rate (default: 30)",
+		},
+		"region": map[string]interface{}{
+			"type":        "object",
+			"description": "Part of the screen to record, in screen pixels (default: the whole screen)",
+			"properties": map[string]interface{}{
+				"x":      map[string]interface{}{"type": "number"},
+				"y":      map[string]interface{}{"type": "number"},
+				"width":  map[string]interface{}{"type": "number"},
+				"height": map[string]interface{}{"type": "number"},
+			},
+		},
+		"display": map[string]interface{}{
+			"type":        "string",
+			"description": "Screen to record: screen index on macOS (default: 0), X display on Linux (default: $DISPLAY), \"desktop\" or \"title=<window title>\" on Windows (default: desktop)",
+		},
+		"microphone": map[string]interface{}{
+			"type":        "string",
+			"description": "Record audio from \"default\" or a named device: avfoundation index on macOS, PulseAudio source on Linux, DirectShow name on Windows (required there). Omit for no audio.",
+		},
+		"webcam": map[string]interface{}{
+			"type":        "string",
+			"description": "Show \"default\" or a named camera picture-in-picture: avfoundation index on macOS, /dev/videoN on Linux, DirectShow name on Windows (required there). Omit for none.",
+		},
+		"webcamPosition": map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{"top-left", "top-right", "bottom-left", "bottom-right"},
+			"description": "Webcam corner (default: bottom-right)",
+		},
+		"webcamWidth": map[string]interface{}{
+			"type":        "number",
+			"description": "Webcam width as a fraction of the frame width, up to 0.5 (default: 0.25)",
+		},
+	} {
+		properties[name] = property
+	}
+	return properties
+}
+
+func (s *MCPServer) handleStartRecording(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Output         string          `json:"output"`
+		FPS            int             `json:"fps"`
+		Region         *capture.Region `json:"region"`
+		Display        string          `json:"display"`
+		Microphone     string          `json:"microphone"`
+		Webcam         string          `json:"webcam"`
+		WebcamPosition string          `json:"webcamPosition"`
+		WebcamWidth    float64         `json:"webcamWidth"`
+		MaxDuration    float64         `json:"maxDuration"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	session, err := s.recorder.Start(capture.Options{
+		Output:         args.Output,
+		FPS:            args.FPS,
+		Region:         args.Region,
+		Display:        args.Display,
+		Microphone:     args.Microphone,
+		Webcam:         args.Webcam,
+		WebcamPosition: args.WebcamPosition,
+		WebcamWidth:    args.WebcamWidth,
+		MaxDuration:    args.MaxDuration,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to start recording: %v", err)), nil
+	}
+
+	result := fmt.Sprintf("Successfully started recording to %s\nRecording id: %s\n", session.Output, session.ID)
+	if args.MaxDuration > 0 {
+		result += fmt.Sprintf("Stops on its own after %.0fs; call stop_recording to finish it sooner.\n", args.MaxDuration)
+	} else {
+		result += "Call stop_recording with this id to finish it.\n"
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+func (s *MCPServer) handleStopRecording(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		ID string `json:"id"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.recorder.Stop(args.ID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to stop recording: %v", err)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully stopped recording after %.1fs: %s", result.Duration, result.Output)), nil
+}
+
+func (s *MCPServer) handleListRecordings(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	sessions := s.recorder.List()
+	if len(sessions) == 0 {
+		return mcp.NewToolResultText("No recordings in progress"), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("RECORDINGS (%d)\n", len(sessions)))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	for _, session := range sessions {
+		state := "recording"
+		if !session.Running {
+			state = "ended, call stop_recording to finish"
+		}
+		out.WriteString(fmt.Sprintf("- %s: %s (%s, started %s ago)\n", session.ID, session.Output, state, time.Since(session.Started).Round(time.Second)))
+	}
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+func (s *MCPServer) handleStreamVideo(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input          string          `json:"input"`
+		Platform       string          `json:"platform"`
+		URL            string          `json:"url"`
+		StreamKey      string          `json:"streamKey"`
+		Bitrate        int             `json:"bitrate"`
+		Loop           bool            `json:"loop"`
+		MaxRetries     int             `json:"maxRetries"`
+		FPS            int             `json:"fps"`
+		Region         *capture.Region `json:"region"`
+		Display        string          `json:"display"`
+		Microphone     string          `json:"microphone"`
+		Webcam         string          `json:"webcam"`
+		WebcamPosition string          `json:"webcamPosition"`
+		WebcamWidth    float64         `json:"webcamWidth"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	opts := capture.StreamOptions{
+		Input:      args.Input,
+		URL:        args.URL,
+		Platform:   args.Platform,
+		StreamKey:  args.StreamKey,
+		Bitrate:    args.Bitrate,
+		Loop:       args.Loop,
+		MaxRetries: args.MaxRetries,
+	}
+	if args.Input == "" {
+		opts.Capture = &capture.Options{
+			FPS:            args.FPS,
+			Region:         args.Region,
+			Display:        args.Display,
+			Microphone:     args.Microphone,
+			Webcam:         args.Webcam,
+			WebcamPosition: args.WebcamPosition,
+			WebcamWidth:    args.WebcamWidth,
+		}
+	}
+	stream, err := s.streamer.Start(opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to start stream: %v", err)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully started streaming %s to %s\nStream id: %s\nCall stop_stream with this id to end it.\n",
+		stream.Source, stream.Target, stream.ID)), nil
+}
+
+func (s *MCPServer) handleStopStream(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		ID string `json:"id"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	if args.ID == "" {
+		streams := s.streamer.List()
+		if len(streams) == 0 {
+			return mcp.NewToolResultText("No streams in progress"), nil
+		}
+		var out strings.Builder
+		out.WriteString(fmt.Sprintf("STREAMS (%d)\n", len(streams)))
+		out.WriteString(strings.Repeat("=", 80))
+		out.WriteString("\n\n")
+		for _, stream := range streams {
+			state := "live"
+			if !stream.Running {
+				state = "ended"
+			}
+			out.WriteString(fmt.Sprintf("- %s: %s -> %s (%s, started %s ago, %d reconnects)\n",
+				stream.ID, stream.Source, stream.Target, state, time.Since(stream.Started).Round(time.Second), stream.Reconnects))
+			if stream.LastError != "" {
+				out.WriteString(fmt.Sprintf("  Last error: %s\n", stream.LastError))
+			}
+		}
+		return mcp.NewToolResultText(out.String()), nil
+	}
+
+	stream, err := s.streamer.Stop(args.ID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to stop stream: %v", err)), nil
+	}
+	result := fmt.Sprintf("Successfully stopped streaming %s after %s", stream.Source, time.Since(stream.Started).Round(time.Second))
+	if stream.Reconnects > 0 {
+		result += fmt.Sprintf(" (%d reconnects)", stream.Reconnects)
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// StopCaptures stops any recordings and streams still running, for when
+// the app shuts down
+func (s *MCPServer) StopCaptures() {
+	s.recorder.StopAll()
+	s.streamer.StopAll()
+}
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/chapters"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerGenerateChapters registers the generate_chapters MCP tool
+func (s *MCPServer) registerGenerateChapters() {
+	s.addTool(mcp.Tool{
+		Name:        "generate_chapters",
+		Description: "Generate chapter markers from scene cuts, transcript topics and frame descriptions (each scene is classified as talking head, screen recording, slides, b-roll or title card). Writes YouTube chapter text, an FFmetadata file and JSON, and can embed the chapters in a copy of the video. Transcript needs an OpenAI key, frame descriptions a vision provider, and titles the configured LLM; missing ones are skipped.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"outputDir": map[string]interface{}{
+					"type":        "string",
+					"description": "Directory for chapters.txt, chapters.ffmetadata and chapters.json (default: <input>_chapters)",
+				},
+				"language": map[string]interface{}{
+					"type":        "string",
+					"description": "Language code for transcription (e.g. 'en', optional)",
+				},
+				"sceneThreshold": map[string]interface{}{
+					"type":        "number",
+					"description": "Scene change sensitivity from 0 to 1; lower finds more cuts (default: 0.3)",
+				},
+				"minChapterLength": map[string]interface{}{
+					"type":        "number",
+					"description": "Minimum chapter length in seconds (default: 10, YouTube's minimum)",
+				},
+				"maxChapters": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of chapters (default: 15)",
+				},
+				"skipTranscript": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Don't transcribe; use scenes and frames only (default: false)",
+				},
+				"skipVision": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Don't describe frames with the vision provider (default: false)",
+				},
+				"embed": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Write a copy of the video with the chapters embedded, without re-encoding (default: true)",
+				},
+				"embedOutput": map[string]interface{}{
+					"type":        "string",
+					"description": "Path for the video with embedded chapters (default: <outputDir>/<name>_chapters.<ext>)",
+				},
+			},
+			Required: []string{"input"},
+		},
+	}, s.handleGenerateChapters)
+}
+
+func (s *MCPServer) handleGenerateChapters(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input            string  `json:"input"`
+		OutputDir        string  `json:"outputDir"`
+		Language         string  `json:"language"`
+		SceneThreshold   float64 `json:"sceneThreshold"`
+		MinChapterLength float64 `json:"minChapterLength"`
+		MaxChapters      int     `json:"maxChapters"`
+		SkipTranscript   bool    `json:"skipTranscript"`
+		SkipVision       bool    `json:"skipVision"`
+		Embed            *bool   `json:"embed"`
+		EmbedOutput      string  `json:"embedOutput"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.chapterGen.Generate(context.Background(), chapters.Options{
+		Input:            args.Input,
+		OutputDir:        args.OutputDir,
+		Language:         args.Language,
+		SceneThreshold:   args.SceneThreshold,
+		MinChapterLength: args.MinChapterLength,
+		MaxChapters:      args.MaxChapters,
+		SkipTranscript:   args.SkipTranscript,
+		SkipVision:       args.SkipVision,
+		Embed:            args.Embed == nil || *args.Embed,
+		EmbedOutput:      args.EmbedOutput,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to generate chapters: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("CHAPTERS: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Duration: %.2fs\n", result.Duration))
+	out.WriteString(fmt.Sprintf("Scenes: %d\n", len(result.Scenes)))
+	out.WriteString(fmt.Sprintf("Sources: %s\n\n", strings.Join(result.Sources, ", ")))
+
+	out.WriteString("YOUTUBE CHAPTERS:\n")
+	out.WriteString(chapters.FormatYouTube(result.Chapters, result.Duration))
+	out.WriteString("\n")
+
+	out.WriteString("SHOTS:\n")
+	for _, ch := range result.Chapters {
+		if ch.Shot != "" {
+			out.WriteString(fmt.Sprintf("- [%.2fs - %.2fs] %s: %s\n", ch.Start, ch.End, ch.Title, ch.Shot))
+		}
+	}
+	out.WriteString("\n")
+
+	out.WriteString("OUTPUTS:\n")
+	out.WriteString(fmt.Sprintf("- YouTube chapters: %s\n", result.YouTubeFile))
+	out.WriteString(fmt.Sprintf("- FFmetadata: %s\n", result.MetadataFile))
+	out.WriteString(fmt.Sprintf("- JSON: %s\n", result.JSONFile))
+	if result.EmbeddedOutput != "" {
+		out.WriteString(fmt.Sprintf("- Video with chapters: %s\n", result.EmbeddedOutput))
+	}
+
+	if len(result.Warnings) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range result.Warnings {
+			out.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// registerEmbedChapters registers the embed_chapters MCP tool
+func (s *MCPServer) registerEmbedChapters() {
+	s.addTool(mcp.Tool{
+		Name:        "embed_chapters",
+		Description: "Embed chapter markers in a copy of a video without re-encoding, replacing any it has. Chapters come from a list, from YouTube-style text (\"0:00 Intro\" lines, e.g. a video description) or from a file such as generate_chapters' chapters.json or chapters.txt. Missing end times run to the next chapter.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path (MP4, MOV or MKV)",
+				},
+				"chapters": map[string]interface{}{
+					"type":        "array",
+					"description": "Chapters as {start, end, title} in seconds; end is optional",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"start": map[string]interface{}{"type": "number"},
+							"end":   map[string]interface{}{"type": "number"},
+							"title": map[string]interface{}{"type": "string"},
+						},
+						"required": []string{"start", "title"},
+					},
+				},
+				"text": map[string]interface{}{
+					"type":        "string",
+					"description": "YouTube-style chapter text or JSON, instead of chapters",
+				},
+				"file": map[string]interface{}{
+					"type":        "string",
+					"description": "File with YouTube-style text or JSON, instead of chapters",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleEmbedChapters)
+}
+
+func (s *MCPServer) handleEmbedChapters(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input    string             `json:"input"`
+		Output   string             `json:"output"`
+		Chapters []chapters.Chapter `json:"chapters"`
+		Text     string             `json:"text"`
+		File     string             `json:"file"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	list := args.Chapters
+	if len(list) == 0 {
+		text := args.Text
+		if args.File != "" {
+			data, err := os.ReadFile(args.File)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to read chapters: %v", err)), nil
+			}
+			text = string(data)
+		}
+		var err error
+		list, err = chapters.Parse(text)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid chapters: %v", err)), nil
+		}
+	}
+
+	embedded, err := s.chapterGen.Embed(context.Background(), args.Input, args.Output, list)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to embed chapters: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("Successfully embedded %d chapters: %s\n\n", len(embedded), args.Output))
+	for _, ch := range embedded {
+		out.WriteString(fmt.Sprintf("- [%.2fs - %.2fs] %s\n", ch.Start, ch.End, ch.Title))
+	}
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// registerExtractChapters registers the extract_chapters MCP tool
+func (s *MCPServer) registerExtractChapters() {
+	s.addTool(mcp.Tool{
+		Name:        "extract_chapters",
+		Description: "Read the chapter markers embedded in a video, as YouTube chapter text. With outputDir, also writes chapters.txt, chapters.ffmetadata and chapters.json like generate_chapters, ready to edit and pass back to embed_chapters.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"outputDir": map[string]interface{}{
+					"type":        "string",
+					"description": "Directory to write the chapter files to (optional)",
+				},
+			},
+			Required: []string{"input"},
+		},
+	}, s.handleExtractChapters)
+}
+
+func (s *MCPServer) handleExtractChapters(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input     string `json:"input"`
+		OutputDir string `json:"outputDir"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.chapterGen.Extract(context.Background(), args.Input, args.OutputDir)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to extract chapters: %v", err)), nil
+	}
+	if len(result.Chapters) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No chapters in %s", args.Input)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("CHAPTERS: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString("YOUTUBE CHAPTERS:\n")
+	out.WriteString(chapters.FormatYouTube(result.Chapters, result.Duration))
+	out.WriteString("\n")
+
+	out.WriteString("MARKERS:\n")
+	for _, ch := range result.Chapters {
+		out.WriteString(fmt.Sprintf("- [%.2fs - %.2fs] %s\n", ch.Start, ch.End, ch.Title))
+	}
+
+	if result.JSONFile != "" {
+		out.WriteString("\nOUTPUTS:\n")
+		out.WriteString(fmt.Sprintf("- YouTube chapters: %s\n", result.YouTubeFile))
+		out.WriteString(fmt.Sprintf("- FFmetadata: %s\n", result.MetadataFile))
+		out.WriteString(fmt.Sprintf("- JSON: %s\n", result.JSONFile))
+	}
+	return mcp.NewToolResultText(out.String()), nil
+}
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/dubbing"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/translate"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerTranslateTranscript registers the translate_transcript MCP tool
+func (s *MCPServer) registerTranslateTranscript() {
+	s.addTool(mcp.Tool{
+		Name:        "translate_transcript",
+		Description: "Translate a transcript into another language with the configured LLM (agentProvider: Claude or OpenAI), keeping segment timing. Writes a target-language SRT and the translated transcript JSON next to it. Transcribes the video with Whisper first when no transcriptPath is given.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"transcriptPath": map[string]interface{}{
+					"type":        "string",
+					"description": "Transcript JSON from extract_transcript",
+				},
+				"videoPath": map[string]interface{}{
+					"type":        "string",
+					"description": "Video to transcribe when no transcriptPath is given",
+				},
+				"targetLanguage": map[string]interface{}{
+					"type":        "string",
+					"description": "Language to translate into (e.g. 'Spanish', 'ja')",
+				},
+				"sourceLanguage": map[string]interface{}{
+					"type":        "string",
+					"description": "Source language (default: the transcript's language)",
+				},
+				"outputPath": map[string]interface{}{
+					"type":        "string",
+					"description": "Path for the translated SRT file",
+				},
+				"notes": map[string]interface{}{
+					"type":        "string",
+					"description": "Glossary, tone or audience guidance for the translation (optional)",
+				},
+			},
+			Required: []string{"targetLanguage", "outputPath"},
+		},
+	}, s.handleTranslateTranscript)
+}
+
+func (s *MCPServer) handleTranslateTranscript(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		TranscriptPath string `json:"transcriptPath"`
+		VideoPath      string `json:"videoPath"`
+		TargetLanguage string `json:"targetLanguage"`
+		SourceLanguage string `json:"sourceLanguage"`
+		OutputPath     string `json:"outputPath"`
+		Notes          string `json:"notes"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+	if !strings.EqualFold(filepath.Ext(args.OutputPath), ".srt") {
+		return mcp.NewToolResultError("outputPath must be an .srt file"), nil
+	}
+
+	ctx := context.Background()
+	var trans *transcript.Transcript
+	var err error
+	switch {
+	case args.TranscriptPath != "":
+		trans, err = s.transcriptOps.LoadTranscript(args.TranscriptPath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to load transcript: %v", err)), nil
+		}
+	case args.VideoPath != "":
+		trans, err = s.transcriptOps.ExtractTranscript(ctx, args.VideoPath, args.SourceLanguage)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to extract transcript: %v", err)), nil
+		}
+	default:
+		return mcp.NewToolResultError("transcriptPath or videoPath is required"), nil
+	}
+
+	translated, err := s.translator.TranslateTranscript(ctx, trans, translate.Options{
+		TargetLanguage: args.TargetLanguage,
+		SourceLanguage: args.SourceLanguage,
+		Notes:          args.Notes,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to translate transcript: %v", err)), nil
+	}
+
+	if err := os.WriteFile(args.OutputPath, []byte(s.transcriptOps.FormatAsSRT(translated)), 0644); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to write subtitles: %v", err)), nil
+	}
+	jsonPath := strings.TrimSuffix(args.OutputPath, filepath.Ext(args.OutputPath)) + ".json"
+	if err := s.transcriptOps.SaveTranscript(translated, jsonPath); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to save translated transcript: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Translated %d segments to %s using %s\n- Subtitles: %s\n- Transcript: %s\n\n%s",
+		len(translated.Segments), args.TargetLanguage, s.translator.Provider(), args.OutputPath, jsonPath,
+		s.transcriptOps.FormatAsText(translated))), nil
+}
+
+// registerDubVideo registers the dub_video MCP tool
+func (s *MCPServer) registerDubVideo() {
+	s.addTool(mcp.Tool{
+		Name:        "dub_video",
+		Description: "Create a dubbed version of a video: transcribe, translate with the configured LLM, generate ElevenLabs speech per segment in a cloned or chosen voice, fit each line to its original timing, and replace the audio. Requires ElevenLabs and LLM API keys, plus OpenAI for transcription unless transcriptPath is given.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path. The transcript, translation and SRT are saved next to it.",
+				},
+				"targetLanguage": map[string]interface{}{
+					"type":        "string",
+					"description": "Language to dub into (e.g. 'Spanish', 'de')",
+				},
+				"sourceLanguage": map[string]interface{}{
+					"type":        "string",
+					"description": "Spoken language of the input (optional)",
+				},
+				"transcriptPath": map[string]interface{}{
+					"type":        "string",
+					"description": "Reuse a source transcript JSON instead of transcribing",
+				},
+				"translationPath": map[string]interface{}{
+					"type":        "string",
+					"description": "Reuse a translated transcript JSON (e.g. from translate_transcript, after review) instead of translating",
+				},
+				"notes": map[string]interface{}{
+					"type":        "string",
+					"description": "Glossary or tone guidance for the translation (optional)",
+				},
+				"voiceId": map[string]interface{}{
+					"type":        "string",
+					"description": "ElevenLabs voice ID (default: clone the speaker's voice)",
+				},
+				"voiceSamplePath": map[string]interface{}{
+					"type":        "string",
+					"description": "Audio to clone the voice from (default: the first minute of speech in the input)",
+				},
+				"modelId": map[string]interface{}{
+					"type":        "string",
+					"description": "ElevenLabs model (default: eleven_multilingual_v2)",
+				},
+				"maxSpeedup": map[string]interface{}{
+					"type":        "number",
+					"description": "Fastest playback speed used to fit a line into its original timing before it is cut (1.0-2.0, default: 1.25)",
+				},
+				"backgroundVolume": map[string]interface{}{
+					"type":        "number",
+					"description": "Keep the original audio under the dub at this volume (0-1, default: 0)",
+				},
+				"keepOriginal": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Keep the original audio as a second track instead of replacing it; output must be .mp4, .mov or .mkv (default: false)",
+				},
+			},
+			Required: []string{"input", "output", "targetLanguage"},
+		},
+	}, s.handleDubVideo)
+}
+
+func (s *MCPServer) handleDubVideo(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input            string  `json:"input"`
+		Output           string  `json:"output"`
+		TargetLanguage   string  `json:"targetLanguage"`
+		SourceLanguage   string  `json:"sourceLanguage"`
+		TranscriptPath   string  `json:"transcriptPath"`
+		TranslationPath  string  `json:"translationPath"`
+		Notes            string  `json:"notes"`
+		VoiceID          string  `json:"voiceId"`
+		VoiceSamplePath  string  `json:"voiceSamplePath"`
+		ModelID          string  `json:"modelId"`
+		MaxSpeedup       float64 `json:"maxSpeedup"`
+		BackgroundVolume float64 `json:"backgroundVolume"`
+		KeepOriginal     bool    `json:"keepOriginal"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.dubPipeline.Dub(context.Background(), dubbing.Options{
+		Input:            args.Input,
+		Output:           args.Output,
+		TargetLanguage:   args.TargetLanguage,
+		SourceLanguage:   args.SourceLanguage,
+		TranscriptPath:   args.TranscriptPath,
+		TranslationPath:  args.TranslationPath,
+		Notes:            args.Notes,
+		VoiceID:          args.VoiceID,
+		VoiceSamplePath:  args.VoiceSamplePath,
+		ModelID:          args.ModelID,
+		MaxSpeedup:       args.MaxSpeedup,
+		BackgroundVolume: args.BackgroundVolume,
+		KeepOriginal:     args.KeepOriginal,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to dub video: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("VIDEO DUBBED: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Language: %s\n", args.TargetLanguage))
+	out.WriteString(fmt.Sprintf("Voice: %s\n", result.VoiceID))
+	out.WriteString(fmt.Sprintf("Segments: %d (%d sped up, %d cut)\n", result.Segments, result.SpedUp, result.Trimmed))
+
+	out.WriteString("\nOUTPUTS:\n")
+	out.WriteString(fmt.Sprintf("- Video: %s\n", result.Output))
+	if result.TranscriptFile != "" {
+		out.WriteString(fmt.Sprintf("- Source transcript: %s\n", result.TranscriptFile))
+	}
+	out.WriteString(fmt.Sprintf("- Translation: %s\n", result.TranslationFile))
+	out.WriteString(fmt.Sprintf("- Subtitles: %s\n", result.SubtitlesFile))
+
+	if len(result.Warnings) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range result.Warnings {
+			out.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/vision"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/visual"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerFaceTools registers the detect_faces and blur_faces MCP tools
+func (s *MCPServer) registerFaceTools() {
+	s.addTool(mcp.Tool{
+		Name:        "detect_faces",
+		Description: "Detect faces in sampled frames with the vision model. Returns bounding boxes per timestamp (as fractions of the frame) and the same faces linked into tracks over time, which blur_faces uses. Requires a vision provider.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"interval": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds between sampled frames (default: 1)",
+				},
+				"timestamps": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "number"},
+					"description": "Specific timestamps to check instead of sampling at an interval",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional JSON file to save the boxes and tracks to",
+				},
+			},
+			Required: []string{"input"},
+		},
+	}, s.handleDetectFaces)
+
+	s.addTool(mcp.Tool{
+		Name:        "blur_faces",
+		Description: "Blur or black out every face for privacy redaction. Faces are detected on sampled frames with the vision model, linked into tracks, and each track gets a blur region that moves with the face, interpolated between samples and held slightly before and after so faces stay covered. Use keepTracks with IDs from detect_faces to leave someone visible.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"interval": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds between sampled frames; lower catches faces that move quickly (default: 1)",
+				},
+				"mode": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{visual.RedactBlur, visual.RedactBlack},
+					"description": "blur or solid black boxes (default: blur)",
+				},
+				"strength": map[string]interface{}{
+					"type":        "number",
+					"description": "Blur strength 1-10 (default: 8)",
+				},
+				"padding": map[string]interface{}{
+					"type":        "number",
+					"description": "Extra area around each face as a fraction of its size (default: 0.2)",
+				},
+				"keepTracks": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "number"},
+					"description": "Track IDs from detect_faces (same interval) to leave unblurred",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleBlurFaces)
+}
+
+func (s *MCPServer) handleDetectFaces(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input      string    `json:"input"`
+		Interval   float64   `json:"interval"`
+		Timestamps []float64 `json:"timestamps"`
+		Output     string    `json:"output"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	detection, err := s.visionAnalyzer.DetectFaces(context.Background(), args.Input, args.Interval, args.Timestamps)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to detect faces: %v", err)), nil
+	}
+	if args.Output != "" {
+		data, err := json.MarshalIndent(detection, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to encode detections: %v", err)), nil
+		}
+		if err := os.WriteFile(args.Output, data, 0644); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to write detections: %v", err)), nil
+		}
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("FACE DETECTION: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	faces := 0
+	for _, frame := range detection.Frames {
+		faces += len(frame.Boxes)
+	}
+	out.WriteString(fmt.Sprintf("Frames: %d (%d from cache)\n", len(detection.Frames), detection.CachedFrames))
+	out.WriteString(fmt.Sprintf("Faces: %d in %d track(s)\n\n", faces, len(detection.Tracks)))
+
+	out.WriteString("TRACKS:\n")
+	for _, track := range detection.Tracks {
+		first := track.Keyframes[0]
+		out.WriteString(fmt.Sprintf("%d. [%.2fs - %.2fs] %d keyframe(s), first at x=%.2f y=%.2f w=%.2f h=%.2f\n",
+			track.ID, track.Start, track.End, len(track.Keyframes), first.X, first.Y, first.W, first.H))
+	}
+
+	out.WriteString("\nFRAMES:\n")
+	for i, frame := range detection.Frames {
+		if i == 50 {
+			out.WriteString(fmt.Sprintf("... %d more\n", len(detection.Frames)-i))
+			break
+		}
+		if len(frame.Boxes) == 0 {
+			continue
+		}
+		boxes := make([]string, len(frame.Boxes))
+		for j, b := range frame.Boxes {
+			boxes[j] = fmt.Sprintf("(%.2f, %.2f, %.2f×%.2f)", b.X, b.Y, b.W, b.H)
+		}
+		out.WriteString(fmt.Sprintf("- %.2fs: %s\n", frame.Timestamp, strings.Join(boxes, " ")))
+	}
+	if args.Output != "" {
+		out.WriteString(fmt.Sprintf("\nSaved to: %s\n", args.Output))
+	}
+	if detection.FailedFrames > 0 {
+		out.WriteString(fmt.Sprintf("\nWARNINGS:\n- %d frame(s) could not be analyzed\n", detection.FailedFrames))
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+func (s *MCPServer) handleBlurFaces(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input      string   `json:"input"`
+		Output     string   `json:"output"`
+		Interval   float64  `json:"interval"`
+		Mode       string   `json:"mode"`
+		Strength   float64  `json:"strength"`
+		Padding    *float64 `json:"padding"`
+		KeepTracks []int    `json:"keepTracks"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	ctx := context.Background()
+	detection, err := s.visionAnalyzer.DetectFaces(ctx, args.Input, args.Interval, nil)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to detect faces: %v", err)), nil
+	}
+
+	keep := map[int]bool{}
+	for _, id := range args.KeepTracks {
+		keep[id] = true
+	}
+	regions := tracksToRegions(detection.Tracks, keep)
+	if len(regions) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No faces to blur in %s (%d track(s) found, %d kept). No output written.", args.Input, len(detection.Tracks), len(keep))), nil
+	}
+
+	if err := s.visualFx.BlurRegions(ctx, visual.BlurRegionsOptions{
+		Input:    args.Input,
+		Output:   args.Output,
+		Regions:  regions,
+		Mode:     args.Mode,
+		Strength: args.Strength,
+		Padding:  args.Padding,
+	}); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to blur faces: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("FACE BLUR: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Output: %s\n", args.Output))
+	out.WriteString(fmt.Sprintf("Frames checked: %d (%d from cache)\n", len(detection.Frames), detection.CachedFrames))
+	out.WriteString(fmt.Sprintf("Faces redacted: %d track(s)\n", len(regions)))
+	if len(keep) > 0 {
+		out.WriteString(fmt.Sprintf("Left visible: %d track(s)\n", len(detection.Tracks)-len(regions)))
+	}
+	if detection.FailedFrames > 0 {
+		out.WriteString(fmt.Sprintf("\nWARNINGS:\n- %d frame(s) could not be analyzed; faces there may not be covered\n", detection.FailedFrames))
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// tracksToRegions turns box tracks into regions for BlurRegions, skipping
+// the track IDs in skip
+func tracksToRegions(tracks []vision.BoxTrack, skip map[int]bool) []visual.MovingRegion {
+	var regions []visual.MovingRegion
+	for _, track := range tracks {
+		if skip[track.ID] {
+			continue
+		}
+		region := visual.MovingRegion{Start: track.Start, End: track.End}
+		for _, k := range track.Keyframes {
+			region.Keyframes = append(region.Keyframes, visual.RegionKeyframe{Time: k.Time, X: k.X, Y: k.Y, W: k.W, H: k.H})
+		}
+		regions = append(regions, region)
+	}
+	return regions
+}
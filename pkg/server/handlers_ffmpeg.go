@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// capabilityCheck is a component worth knowing about before a render
+type capabilityCheck struct {
+	kind  string // encoder or filter
+	name  string
+	label string
+}
+
+// keyCapabilities are the optional components tools rely on, which vary
+// between FFmpeg builds
+var keyCapabilities = []capabilityCheck{
+	{"encoder", "libx264", "H.264 (software)"},
+	{"encoder", "libx265", "HEVC/H.265 (software)"},
+	{"encoder", "libsvtav1", "AV1 (SVT-AV1)"},
+	{"encoder", "libaom-av1", "AV1 (libaom)"},
+	{"encoder", "libvpx-vp9", "VP9"},
+	{"encoder", "prores_ks", "ProRes"},
+	{"encoder", "h264_nvenc", "H.264 (NVIDIA)"},
+	{"encoder", "hevc_nvenc", "HEVC (NVIDIA)"},
+	{"encoder", "h264_videotoolbox", "H.264 (Apple VideoToolbox)"},
+	{"encoder", "hevc_videotoolbox", "HEVC (Apple VideoToolbox)"},
+	{"encoder", "h264_qsv", "H.264 (Intel Quick Sync)"},
+	{"encoder", "h264_vaapi", "H.264 (VA-API)"},
+	{"encoder", "aac", "AAC"},
+	{"encoder", "libmp3lame", "MP3"},
+	{"encoder", "libopus", "Opus"},
+	{"filter", "drawtext", "Text overlays (drawtext)"},
+	{"filter", "subtitles", "Burned-in subtitles (libass)"},
+	{"filter", "libvmaf", "VMAF quality scores"},
+	{"filter", "rubberband", "Formant-preserving pitch shift"},
+	{"filter", "zscale", "HDR tone mapping (zimg)"},
+	{"filter", "vidstabdetect", "Stabilization (vid.stab)"},
+}
+
+// registerGetFFmpegCapabilities registers the get_ffmpeg_capabilities MCP tool
+func (s *MCPServer) registerGetFFmpegCapabilities() {
+	s.addTool(mcp.Tool{
+		Name:        "get_ffmpeg_capabilities",
+		Description: "Report what the local FFmpeg build supports: version, configure flags, encoders, decoders, filters and hardware acceleration methods, with a summary of optional components tools rely on (libx265, SVT-AV1, NVENC/VideoToolbox/QSV, drawtext, libass, libvmaf, rubberband, zscale). Check here before choosing a codec or filter so a render doesn't fail midway.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"check": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Encoder, decoder, filter or hardware acceleration names to look up, e.g. [\"libx265\", \"libvmaf\", \"cuda\"]",
+				},
+				"verbose": map[string]interface{}{
+					"type":        "boolean",
+					"description": "List every encoder, decoder and filter (default: false, counts only)",
+				},
+			},
+			Required: []string{},
+		},
+	}, s.handleGetFFmpegCapabilities)
+}
+
+func (s *MCPServer) handleGetFFmpegCapabilities(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Check   []string `json:"check"`
+		Verbose bool     `json:"verbose"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+	caps, err := s.ffmpeg.Capabilities()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to read FFmpeg capabilities: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("FFMPEG CAPABILITIES: %s\n", caps.Version))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Binary: %s\n", s.ffmpeg.GetPath()))
+	out.WriteString(fmt.Sprintf("Encoders: %s\n", codecCounts(caps.Encoders)))
+	out.WriteString(fmt.Sprintf("Decoders: %s\n", codecCounts(caps.Decoders)))
+	out.WriteString(fmt.Sprintf("Filters: %d\n", len(caps.Filters)))
+	if len(caps.HWAccels) > 0 {
+		out.WriteString(fmt.Sprintf("Hardware acceleration: %s\n", strings.Join(caps.HWAccels, ", ")))
+	} else {
+		out.WriteString("Hardware acceleration: none\n")
+	}
+
+	out.WriteString("\nKEY COMPONENTS:\n")
+	for _, c := range keyCapabilities {
+		available := caps.HasFilter(c.name)
+		if c.kind == "encoder" {
+			available = caps.HasEncoder(c.name)
+		}
+		mark := "no"
+		if available {
+			mark = "yes"
+		}
+		out.WriteString(fmt.Sprintf("- %-32s %-18s %s\n", c.label, c.name, mark))
+	}
+
+	if len(args.Check) > 0 {
+		out.WriteString("\nCHECKED:\n")
+		for _, name := range args.Check {
+			var found []string
+			if caps.HasEncoder(name) {
+				found = append(found, "encoder")
+			}
+			if caps.HasDecoder(name) {
+				found = append(found, "decoder")
+			}
+			if caps.HasFilter(name) {
+				found = append(found, "filter")
+			}
+			if caps.HasHWAccel(name) {
+				found = append(found, "hardware acceleration")
+			}
+			if len(found) == 0 {
+				out.WriteString(fmt.Sprintf("- %s: not available\n", name))
+			} else {
+				out.WriteString(fmt.Sprintf("- %s: available (%s)\n", name, strings.Join(found, ", ")))
+			}
+		}
+	}
+
+	if args.Verbose {
+		out.WriteString(fmt.Sprintf("\nCONFIGURATION:\n%s\n", strings.Join(caps.Configuration, " ")))
+		for _, list := range []struct {
+			title  string
+			codecs []ffmpeg.Codec
+		}{{"ENCODERS", caps.Encoders}, {"DECODERS", caps.Decoders}} {
+			out.WriteString(fmt.Sprintf("\n%s:\n", list.title))
+			for _, c := range list.codecs {
+				out.WriteString(fmt.Sprintf("- %s (%s): %s\n", c.Name, c.Type, c.Description))
+			}
+		}
+		out.WriteString(fmt.Sprintf("\nFILTERS:\n%s\n", strings.Join(caps.Filters, ", ")))
+	}
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// codecCounts summarizes codecs by type, e.g. "312 (198 video, 102 audio, 12 subtitle)"
+func codecCounts(codecs []ffmpeg.Codec) string {
+	counts := map[string]int{}
+	for _, c := range codecs {
+		counts[c.Type]++
+	}
+	return fmt.Sprintf("%d (%d video, %d audio, %d subtitle)", len(codecs), counts["video"], counts["audio"], counts["subtitle"])
+}
+
+// registerRunFFmpeg registers the run_ffmpeg MCP tool
+func (s *MCPServer) registerRunFFmpeg() {
+	s.addTool(mcp.Tool{
+		Name:        "run_ffmpeg",
+		Description: "Run FFmpeg with raw options for filters and settings no other tool covers, as: ffmpeg [inputArgs] -i <input>... [args] <output>. FFmpeg runs without a shell and is killed after the timeout. Inputs must be existing local files and the output a new file (set overwrite to replace one) that isn't an input; args may not add inputs or outputs, use unrecognized options, network or pipe protocols, the tee/segment/hls/dash/concat formats, the movie/sendcmd/zmq filters, two-pass logs, encoder parameter strings (-x264-params, -x265-params) or option files, and files named in filters must exist (or, for logs and stats, be written beside the output). A failed run removes its output.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"inputs": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Input files, passed as -i in order",
+				},
+				"inputArgs": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Options placed before the first input, e.g. [\"-ss\", \"10\"]",
+				},
+				"args": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Options placed before the output, one argument per item, e.g. [\"-vf\", \"hqdn3d=4:3:6:4.5\", \"-c:v\", \"libx264\", \"-crf\", \"20\"]",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output file; its extension picks the container",
+				},
+				"overwrite": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Replace the output if it exists (default: false)",
+				},
+				"timeout": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Seconds before FFmpeg is stopped (default: %.0f, max: %.0f)", ffmpeg.DefaultPassthroughTimeout.Seconds(), ffmpeg.MaxPassthroughTimeout.Seconds()),
+				},
+			},
+			Required: []string{"inputs", "output"},
+		},
+	}, s.handleRunFFmpeg)
+}
+
+func (s *MCPServer) handleRunFFmpeg(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Inputs    []string `json:"inputs"`
+		InputArgs []string `json:"inputArgs"`
+		Args      []string `json:"args"`
+		Output    string   `json:"output"`
+		Overwrite bool     `json:"overwrite"`
+		Timeout   float64  `json:"timeout"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	log, err := s.ffmpeg.RunPassthrough(context.Background(), ffmpeg.PassthroughOptions{
+		Inputs:    args.Inputs,
+		InputArgs: args.InputArgs,
+		Args:      args.Args,
+		Output:    args.Output,
+		Overwrite: args.Overwrite,
+		Timeout:   time.Duration(args.Timeout * float64(time.Second)),
+	})
+	if err != nil {
+		if log != "" {
+			return mcp.NewToolResultError(fmt.Sprintf("%v\n\nFFmpeg output:\n%s", err, logTail(log, 20))), nil
+		}
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("FFmpeg finished. Output: %s\n\nFFmpeg output:\n%s", args.Output, logTail(log, 20))), nil
+}
+
+// logTail returns the last n lines of an FFmpeg log, where errors and the
+// final statistics are
+func logTail(log string, n int) string {
+	lines := strings.Split(strings.TrimRight(log, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
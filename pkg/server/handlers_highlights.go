@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/highlights"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerFindHighlights registers the find_highlights MCP tool
+func (s *MCPServer) registerFindHighlights() {
+	s.addTool(mcp.Tool{
+		Name:        "find_highlights",
+		Description: "Find the best moments for short clips. Scores every second on audio energy, speech (language model ratings of emotion and quotability, keywords, pace) and visual activity, then returns ranked, non-overlapping candidate clips with in/out points snapped to sentence boundaries. Speech scoring needs an OpenAI key for transcription; missing signals are skipped.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video or audio file path",
+				},
+				"keywords": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Words or phrases that make a moment more interesting (e.g. product names, topics)",
+				},
+				"clipLength": map[string]interface{}{
+					"type":        "number",
+					"description": "Target clip length in seconds (default: 30)",
+				},
+				"minClipLength": map[string]interface{}{
+					"type":        "number",
+					"description": "Minimum clip length in seconds after snapping (default: 15)",
+				},
+				"maxClipLength": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum clip length in seconds after snapping (default: 60)",
+				},
+				"maxClips": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of candidate clips (default: 5)",
+				},
+				"language": map[string]interface{}{
+					"type":        "string",
+					"description": "Language code for transcription (e.g. 'en', optional)",
+				},
+				"skipTranscript": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Score audio and visuals only, without transcribing (default: false)",
+				},
+				"skipSentiment": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Don't ask the language model to rate segments; use keywords and pace (default: false)",
+				},
+				"weights": map[string]interface{}{
+					"type":        "object",
+					"description": "Relative weight of each signal (default: audio 0.35, speech 0.4, visual 0.25)",
+					"properties": map[string]interface{}{
+						"audio":  map[string]interface{}{"type": "number"},
+						"speech": map[string]interface{}{"type": "number"},
+						"visual": map[string]interface{}{"type": "number"},
+					},
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional JSON file to save the ranked clips to",
+				},
+			},
+			Required: []string{"input"},
+		},
+	}, s.handleFindHighlights)
+}
+
+func (s *MCPServer) handleFindHighlights(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input          string              `json:"input"`
+		Keywords       []string            `json:"keywords"`
+		ClipLength     float64             `json:"clipLength"`
+		MinClipLength  float64             `json:"minClipLength"`
+		MaxClipLength  float64             `json:"maxClipLength"`
+		MaxClips       int                 `json:"maxClips"`
+		Language       string              `json:"language"`
+		SkipTranscript bool                `json:"skipTranscript"`
+		SkipSentiment  bool                `json:"skipSentiment"`
+		Weights        *highlights.Weights `json:"weights"`
+		Output         string              `json:"output"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.highlightFinder.Find(context.Background(), highlights.Options{
+		Input:          args.Input,
+		Language:       args.Language,
+		Keywords:       args.Keywords,
+		ClipLength:     args.ClipLength,
+		MinClipLength:  args.MinClipLength,
+		MaxClipLength:  args.MaxClipLength,
+		MaxClips:       args.MaxClips,
+		SkipTranscript: args.SkipTranscript,
+		SkipSentiment:  args.SkipSentiment,
+		Weights:        args.Weights,
+		Output:         args.Output,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to find highlights: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("HIGHLIGHTS: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Duration: %.2fs\n", result.Duration))
+	out.WriteString(fmt.Sprintf("Signals: %s\n\n", strings.Join(result.Sources, ", ")))
+
+	for _, h := range result.Highlights {
+		out.WriteString(fmt.Sprintf("%d. [%.2fs - %.2fs] (%.1fs) score %.2f\n", h.Rank, h.Start, h.End, h.End-h.Start, h.Score))
+		out.WriteString(fmt.Sprintf("   audio %.2f, speech %.2f, visual %.2f\n", h.Signals.Audio, h.Signals.Speech, h.Signals.Visual))
+		out.WriteString(fmt.Sprintf("   Why: %s\n", h.Reason))
+		if h.Text != "" {
+			text := h.Text
+			if runes := []rune(text); len(runes) > 200 {
+				text = string(runes[:197]) + "..."
+			}
+			out.WriteString(fmt.Sprintf("   \"%s\"\n", text))
+		}
+		out.WriteString("\n")
+	}
+	if args.Output != "" {
+		out.WriteString(fmt.Sprintf("Saved to: %s\n", args.Output))
+	}
+
+	if len(result.Warnings) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range result.Warnings {
+			out.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
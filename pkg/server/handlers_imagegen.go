@@ -0,0 +1,234 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/imagegen"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/visual"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerGenerateImage registers the generate_image MCP tool
+func (s *MCPServer) registerGenerateImage() {
+	s.addTool(mcp.Tool{
+		Name:        "generate_image",
+		Description: "Generate an image from a text prompt (title backgrounds, placeholder B-roll) and save it to the asset library. Uses OpenAI Images by default or Stable Diffusion through Stability AI; configure imageProvider, imageModel, imageApiBaseUrl, stabilityKey and assetDir with set_config.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"prompt": map[string]interface{}{
+					"type":        "string",
+					"description": "Description of the image to generate",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output image path (optional, defaults to the asset library)",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Base file name for the asset library (optional, derived from prompt)",
+				},
+				"size": map[string]interface{}{
+					"type":        "string",
+					"description": "Image size: 'square', 'landscape', 'portrait' or explicit WxH supported by the model (default: square)",
+				},
+				"quality": map[string]interface{}{
+					"type":        "string",
+					"description": "Provider quality setting (e.g. 'standard', 'hd' for dall-e-3; 'low', 'medium', 'high' for gpt-image-1)",
+				},
+				"style": map[string]interface{}{
+					"type":        "string",
+					"description": "Provider style setting (e.g. 'vivid' or 'natural' for dall-e-3)",
+				},
+				"count": map[string]interface{}{
+					"type":        "number",
+					"description": "Number of images to generate (default: 1)",
+				},
+				"provider": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"openai", "stability"},
+					"description": "Image provider: openai (DALL·E, gpt-image) or stability (Stable Diffusion) (default: the configured imageProvider)",
+				},
+			},
+			Required: []string{"prompt"},
+		},
+	}, s.handleGenerateImage)
+
+	s.addTool(mcp.Tool{
+		Name:        "generate_broll_image",
+		Description: "Generate a b-roll still for a narration section from a prompt with DALL·E or Stable Diffusion, styled as a wide, text-free shot with room to zoom, and animate it into a Ken Burns clip ready to cut in. Set animate to false for the still only, which apply_ken_burns or create_slideshow can animate later.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"prompt": map[string]interface{}{
+					"type":        "string",
+					"description": "What the b-roll shows (e.g. 'a farmer checking soil moisture at sunrise')",
+				},
+				"look": map[string]interface{}{
+					"type":        "string",
+					"enum":        imagegen.BrollLooks,
+					"description": "Visual style added to the prompt; none sends the prompt as given (default: photo)",
+				},
+				"aspect": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"16:9", "9:16", "1:1"},
+					"description": "Aspect ratio of the image and clip (default: 16:9)",
+				},
+				"provider": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"openai", "stability"},
+					"description": "Image provider: openai (DALL·E, gpt-image) or stability (Stable Diffusion) (default: the configured imageProvider)",
+				},
+				"quality": map[string]interface{}{
+					"type":        "string",
+					"description": "Provider quality setting (e.g. 'hd' for dall-e-3)",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output image path (optional, defaults to the asset library)",
+				},
+				"animate": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Render a Ken Burns clip from the image (default: true)",
+				},
+				"videoOutput": map[string]interface{}{
+					"type":        "string",
+					"description": "Output clip path (default: the image path with .mp4)",
+				},
+				"duration": map[string]interface{}{
+					"type":        "number",
+					"description": "Clip length in seconds; match the narration section (default: 5)",
+				},
+				"motion": map[string]interface{}{
+					"type":        "string",
+					"enum":        visual.SlideshowMotions,
+					"description": "Ken Burns motion (default: zoom-in)",
+				},
+				"fps": map[string]interface{}{
+					"type":        "number",
+					"description": "Clip frame rate (default: 30)",
+				},
+			},
+			Required: []string{"prompt"},
+		},
+	}, s.handleGenerateBrollImage)
+}
+
+func (s *MCPServer) handleGenerateImage(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Prompt   string `json:"prompt"`
+		Output   string `json:"output"`
+		Name     string `json:"name"`
+		Size     string `json:"size"`
+		Quality  string `json:"quality"`
+		Style    string `json:"style"`
+		Count    int    `json:"count"`
+		Provider string `json:"provider"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	assets, err := s.imageGen.Generate(context.Background(), imagegen.GenerateOptions{
+		Prompt:   args.Prompt,
+		Output:   args.Output,
+		Name:     args.Name,
+		Size:     args.Size,
+		Quality:  args.Quality,
+		Style:    args.Style,
+		Count:    args.Count,
+		Provider: args.Provider,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to generate image: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("Generated %d image(s) with %s (%s):\n", len(assets), assets[0].Provider, assets[0].Model))
+	for _, asset := range assets {
+		out.WriteString(fmt.Sprintf("- %s\n", asset.Path))
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+func (s *MCPServer) handleGenerateBrollImage(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Prompt      string  `json:"prompt"`
+		Look        string  `json:"look"`
+		Aspect      string  `json:"aspect"`
+		Provider    string  `json:"provider"`
+		Quality     string  `json:"quality"`
+		Output      string  `json:"output"`
+		Animate     *bool   `json:"animate"`
+		VideoOutput string  `json:"videoOutput"`
+		Duration    float64 `json:"duration"`
+		Motion      string  `json:"motion"`
+		FPS         int     `json:"fps"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+	if args.Aspect == "" {
+		args.Aspect = "16:9"
+	}
+	if args.Motion == "" {
+		args.Motion = visual.MotionZoomIn
+	}
+	if args.Duration <= 0 {
+		args.Duration = 5
+	}
+	// Check the motion before paying for an image
+	if !slices.Contains(visual.SlideshowMotions, args.Motion) {
+		return mcp.NewToolResultError(fmt.Sprintf("Unknown motion: %s (use %s)", args.Motion, strings.Join(visual.SlideshowMotions, ", "))), nil
+	}
+
+	ctx := context.Background()
+	asset, err := s.imageGen.GenerateBroll(ctx, imagegen.BrollOptions{
+		Prompt:   args.Prompt,
+		Look:     args.Look,
+		Aspect:   args.Aspect,
+		Output:   args.Output,
+		Quality:  args.Quality,
+		Provider: args.Provider,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to generate b-roll image: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("B-ROLL: %s\n", args.Prompt))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Image: %s (%s, %s)\n", asset.Path, asset.Provider, asset.Model))
+
+	if args.Animate == nil || *args.Animate {
+		videoOutput := args.VideoOutput
+		if videoOutput == "" {
+			videoOutput = strings.TrimSuffix(asset.Path, filepath.Ext(asset.Path)) + ".mp4"
+		}
+		size := imagegen.BrollAspects[args.Aspect]
+		result, err := s.visualFx.CreateSlideshow(ctx, visual.SlideshowOptions{
+			Images:        []string{asset.Path},
+			Output:        videoOutput,
+			Width:         size.Width,
+			Height:        size.Height,
+			FPS:           args.FPS,
+			SlideDuration: args.Duration,
+			Motion:        args.Motion,
+		})
+		if err != nil {
+			out.WriteString(fmt.Sprintf("\nWARNINGS:\n- Image saved but the clip failed to render: %v\n", err))
+			return mcp.NewToolResultText(out.String()), nil
+		}
+		out.WriteString(fmt.Sprintf("Clip: %s (%dx%d, %.1fs, %s)\n", videoOutput, size.Width, size.Height, result.Duration, result.Slides[0].Motion))
+	}
+
+	out.WriteString("\nPrompt sent:\n")
+	out.WriteString(asset.Prompt + "\n")
+	return mcp.NewToolResultText(out.String()), nil
+}
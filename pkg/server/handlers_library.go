@@ -0,0 +1,270 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/library"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerMediaLibrary registers the index_media and search_media MCP tools
+func (s *MCPServer) registerMediaLibrary() {
+	s.addTool(mcp.Tool{
+		Name:        "index_media",
+		Description: "Catalog a folder of source media for search_media: every video, audio and image file is probed for duration, resolution, frame rate and codecs, and optionally transcribed (Whisper, OpenAI API key) and described from sampled frames (vision provider). The catalog is saved in the folder; re-indexing only probes new or changed files and fills in missing transcripts and descriptions.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"directory": map[string]interface{}{
+					"type":        "string",
+					"description": "Folder to index (default: the active project's assets or assetDir)",
+				},
+				"recursive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Include subfolders (default: true)",
+				},
+				"transcribe": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Transcribe speech so search_media can find what is said (default: false; uses the Whisper API)",
+				},
+				"describe": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Describe 3 frames of each video and every image so search_media can find what is shown (default: false; uses the vision provider)",
+				},
+				"language": map[string]interface{}{
+					"type":        "string",
+					"description": "Transcription language code (optional)",
+				},
+				"force": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Re-probe unchanged files and redo their transcripts and descriptions (default: false)",
+				},
+			},
+			Required: []string{},
+		},
+	}, s.handleIndexMedia)
+
+	s.addTool(mcp.Tool{
+		Name:        "search_media",
+		Description: "Find source clips in a folder cataloged by index_media, by duration, resolution, orientation, codec, spoken words and visual content. Word searches require every word and return the timestamps where they occur.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"directory": map[string]interface{}{
+					"type":        "string",
+					"description": "Indexed folder (default: the active project's assets or assetDir)",
+				},
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "Words to find in file names, speech or frame descriptions",
+				},
+				"spoken": map[string]interface{}{
+					"type":        "string",
+					"description": "Words that must be said in the clip (needs transcribe)",
+				},
+				"visual": map[string]interface{}{
+					"type":        "string",
+					"description": "Words that must describe what the clip shows (needs describe)",
+				},
+				"kind": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{library.KindVideo, library.KindAudio, library.KindImage},
+					"description": "Type of media",
+				},
+				"minDuration": map[string]interface{}{
+					"type":        "number",
+					"description": "Minimum duration in seconds",
+				},
+				"maxDuration": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum duration in seconds",
+				},
+				"minWidth": map[string]interface{}{
+					"type":        "number",
+					"description": "Minimum width in pixels (e.g. 3840 for 4K)",
+				},
+				"minHeight": map[string]interface{}{
+					"type":        "number",
+					"description": "Minimum height in pixels",
+				},
+				"orientation": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{library.OrientationLandscape, library.OrientationPortrait, library.OrientationSquare},
+					"description": "Frame orientation",
+				},
+				"codec": map[string]interface{}{
+					"type":        "string",
+					"description": "Video or audio codec, e.g. h264, hevc, prores, aac",
+				},
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum results (default: 20)",
+				},
+			},
+			Required: []string{},
+		},
+	}, s.handleSearchMedia)
+}
+
+func (s *MCPServer) handleIndexMedia(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Directory  string `json:"directory"`
+		Recursive  *bool  `json:"recursive"`
+		Transcribe bool   `json:"transcribe"`
+		Describe   bool   `json:"describe"`
+		Language   string `json:"language"`
+		Force      bool   `json:"force"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+	dir, err := s.libraryDir(args.Directory)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result, err := s.mediaIndexer.Index(context.Background(), library.IndexOptions{
+		Directory:  dir,
+		Recursive:  args.Recursive == nil || *args.Recursive,
+		Transcribe: args.Transcribe,
+		Describe:   args.Describe,
+		Language:   args.Language,
+		Force:      args.Force,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to index media: %v", err)), nil
+	}
+
+	counts := map[string]int{}
+	var duration float64
+	for _, e := range result.Catalog.Entries {
+		counts[e.Kind]++
+		duration += e.Duration
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("MEDIA INDEX: %s\n", result.Catalog.Directory))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Files: %d (%d video, %d audio, %d image)\n", len(result.Catalog.Entries), counts[library.KindVideo], counts[library.KindAudio], counts[library.KindImage]))
+	out.WriteString(fmt.Sprintf("Total duration: %s\n", formatDuration(duration)))
+	out.WriteString(fmt.Sprintf("Added: %d, updated: %d, unchanged: %d, removed: %d\n", result.Added, result.Updated, result.Unchanged, result.Removed))
+	if args.Transcribe {
+		out.WriteString(fmt.Sprintf("Transcribed: %d\n", result.Transcribed))
+	}
+	if args.Describe {
+		out.WriteString(fmt.Sprintf("Described: %d\n", result.Described))
+	}
+	out.WriteString(fmt.Sprintf("Catalog: %s\n", result.Path))
+
+	if len(result.Warnings) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range result.Warnings {
+			out.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+func (s *MCPServer) handleSearchMedia(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Directory   string  `json:"directory"`
+		Query       string  `json:"query"`
+		Spoken      string  `json:"spoken"`
+		Visual      string  `json:"visual"`
+		Kind        string  `json:"kind"`
+		MinDuration float64 `json:"minDuration"`
+		MaxDuration float64 `json:"maxDuration"`
+		MinWidth    int     `json:"minWidth"`
+		MinHeight   int     `json:"minHeight"`
+		Orientation string  `json:"orientation"`
+		Codec       string  `json:"codec"`
+		Limit       int     `json:"limit"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+	dir, err := s.libraryDir(args.Directory)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	catalog, err := library.Load(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return mcp.NewToolResultError(fmt.Sprintf("%s has not been indexed; run index_media first", dir)), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to load catalog: %v", err)), nil
+	}
+	hits, err := catalog.Search(library.Query{
+		Text:        args.Query,
+		Spoken:      args.Spoken,
+		Visual:      args.Visual,
+		Kind:        args.Kind,
+		MinDuration: args.MinDuration,
+		MaxDuration: args.MaxDuration,
+		MinWidth:    args.MinWidth,
+		MinHeight:   args.MinHeight,
+		Orientation: args.Orientation,
+		Codec:       args.Codec,
+		Limit:       args.Limit,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if len(hits) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No media in %s matches (%d file(s) cataloged).", dir, len(catalog.Entries))), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("MEDIA SEARCH: %s\n", dir))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("%d result(s) from %d file(s)\n\n", len(hits), len(catalog.Entries)))
+	for i, h := range hits {
+		out.WriteString(fmt.Sprintf("%d. %s\n", i+1, h.File))
+		details := []string{h.Kind}
+		if h.Duration > 0 && h.Kind != library.KindImage {
+			details = append(details, formatDuration(h.Duration))
+		}
+		if h.Width > 0 {
+			details = append(details, fmt.Sprintf("%dx%d", h.Width, h.Height))
+		}
+		if h.FPS > 0 && h.Kind == library.KindVideo {
+			details = append(details, fmt.Sprintf("%.3g fps", h.FPS))
+		}
+		for _, codec := range []string{h.VideoCodec, h.AudioCodec} {
+			if codec != "" {
+				details = append(details, codec)
+			}
+		}
+		out.WriteString(fmt.Sprintf("   %s\n", strings.Join(details, ", ")))
+		for _, m := range h.Matches {
+			out.WriteString(fmt.Sprintf("   [%s %s] %s\n", m.Source, formatDuration(m.Start), m.Text))
+		}
+	}
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// libraryDir returns the folder to index or search
+func (s *MCPServer) libraryDir(dir string) (string, error) {
+	if dir == "" {
+		dir = s.config.AssetPath()
+	}
+	if dir == "" {
+		return "", fmt.Errorf("directory is required when no project is active and assetDir is not set")
+	}
+	return dir, nil
+}
+
+// formatDuration formats seconds as m:ss, or h:mm:ss from an hour up
+func formatDuration(seconds float64) string {
+	total := int(seconds + 0.5)
+	if total >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", total/3600, total%3600/60, total%60)
+	}
+	return fmt.Sprintf("%d:%02d", total/60, total%60)
+}
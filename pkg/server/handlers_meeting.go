@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/meeting"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerProcessMeetingRecording registers the process_meeting_recording MCP tool
+func (s *MCPServer) registerProcessMeetingRecording() {
+	s.addTool(mcp.Tool{
+		Name:        "process_meeting_recording",
+		Description: "Process a meeting or lecture recording in one call: cleaned audio, transcript with speaker labels, chapters, a summary document with action items, and a highlights clip. Requires OpenAI API key.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video or audio recording path",
+				},
+				"outputDir": map[string]interface{}{
+					"type":        "string",
+					"description": "Directory where all outputs are written",
+				},
+				"language": map[string]interface{}{
+					"type":        "string",
+					"description": "Language code for transcription (e.g. 'en', optional)",
+				},
+				"skipAudioCleanup": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Transcribe original audio instead of producing a cleaned track (default: false)",
+				},
+				"skipHighlights": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Don't render a highlights clip (default: false)",
+				},
+				"maxHighlights": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of highlight moments (default: 5)",
+				},
+				"maxHighlightsDuration": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum total length of the highlights clip in seconds (default: 90)",
+				},
+			},
+			Required: []string{"input", "outputDir"},
+		},
+	}, s.handleProcessMeetingRecording)
+}
+
+func (s *MCPServer) handleProcessMeetingRecording(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input                 string   `json:"input"`
+		OutputDir             string   `json:"outputDir"`
+		Language              string   `json:"language"`
+		SkipAudioCleanup      bool     `json:"skipAudioCleanup"`
+		SkipHighlights        bool     `json:"skipHighlights"`
+		MaxHighlights         *int     `json:"maxHighlights"`
+		MaxHighlightsDuration *float64 `json:"maxHighlightsDuration"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	opts := meeting.Options{
+		Input:            args.Input,
+		OutputDir:        args.OutputDir,
+		Language:         args.Language,
+		SkipAudioCleanup: args.SkipAudioCleanup,
+		SkipHighlights:   args.SkipHighlights,
+	}
+	if args.MaxHighlights != nil {
+		opts.MaxHighlights = *args.MaxHighlights
+	}
+	if args.MaxHighlightsDuration != nil {
+		opts.MaxHighlightsDuration = *args.MaxHighlightsDuration
+	}
+
+	result, err := s.meetingPipeline.Process(context.Background(), opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to process meeting recording: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("MEETING PROCESSED: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Duration: %.2fs\n", result.Duration))
+	out.WriteString(fmt.Sprintf("Speakers: %s\n", strings.Join(result.Speakers, ", ")))
+	out.WriteString(fmt.Sprintf("Chapters: %d\n", len(result.Chapters)))
+	out.WriteString(fmt.Sprintf("Action items: %d\n", len(result.ActionItems)))
+	out.WriteString(fmt.Sprintf("Highlights: %d\n\n", len(result.Highlights)))
+
+	out.WriteString("OUTPUTS:\n")
+	if result.CleanedAudio != "" {
+		out.WriteString(fmt.Sprintf("- Cleaned audio: %s\n", result.CleanedAudio))
+	}
+	out.WriteString(fmt.Sprintf("- Transcript (JSON): %s\n", result.TranscriptJSON))
+	out.WriteString(fmt.Sprintf("- Transcript (text): %s\n", result.TranscriptText))
+	out.WriteString(fmt.Sprintf("- Chapters: %s\n", result.ChaptersFile))
+	out.WriteString(fmt.Sprintf("- Summary: %s\n", result.SummaryFile))
+	if result.HighlightsClip != "" {
+		out.WriteString(fmt.Sprintf("- Highlights clip: %s\n", result.HighlightsClip))
+	}
+	out.WriteString(fmt.Sprintf("- Structured result: %s\n\n", result.ResultFile))
+
+	out.WriteString("SUMMARY:\n")
+	out.WriteString(result.Summary)
+	out.WriteString("\n")
+
+	if len(result.Warnings) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range result.Warnings {
+			out.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
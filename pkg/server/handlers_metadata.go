@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerGetMetadata registers the get_metadata MCP tool
+func (s *MCPServer) registerGetMetadata() {
+	s.addTool(mcp.Tool{
+		Name:        "get_metadata",
+		Description: "Read a file's metadata: container tags (title, comment, creation time...), per-stream tags, the display rotation and chapters. Lists the location, device and telemetry data that set_metadata's stripSensitive removes.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"filePath": map[string]interface{}{
+					"type":        "string",
+					"description": "Path to the media file",
+				},
+				"json": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Return the full metadata as JSON (default: false)",
+				},
+			},
+			Required: []string{"filePath"},
+		},
+	}, s.handleGetMetadata)
+}
+
+func (s *MCPServer) handleGetMetadata(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		FilePath string `json:"filePath"`
+		JSON     bool   `json:"json"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	meta, err := s.videoOps.GetMetadata(context.Background(), args.FilePath)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get metadata: %v", err)), nil
+	}
+	if args.JSON {
+		data, err := json.MarshalIndent(meta, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to encode metadata: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString("METADATA\n")
+	out.WriteString(strings.Repeat("=", 80) + "\n")
+	fmt.Fprintf(&out, "Format: %s, %.2f seconds\n", meta.Format, meta.Duration)
+	if meta.CreationTime != "" {
+		fmt.Fprintf(&out, "Created: %s\n", meta.CreationTime)
+	}
+	fmt.Fprintf(&out, "Rotation: %d°\n", meta.Rotation)
+
+	out.WriteString("\nTags:\n")
+	if len(meta.Tags) == 0 {
+		out.WriteString("  (none)\n")
+	}
+	for _, key := range slices.Sorted(maps.Keys(meta.Tags)) {
+		fmt.Fprintf(&out, "  • %s: %s\n", key, meta.Tags[key])
+	}
+
+	out.WriteString("\nStreams:\n")
+	for _, stream := range meta.Streams {
+		fmt.Fprintf(&out, "  • #%d %s (%s)", stream.Index, stream.Type, stream.Codec)
+		if lang := stream.Tags["language"]; lang != "" {
+			fmt.Fprintf(&out, ", language %s", lang)
+		}
+		out.WriteString("\n")
+	}
+
+	if len(meta.Chapters) > 0 {
+		out.WriteString("\nChapters:\n")
+		for _, ch := range meta.Chapters {
+			fmt.Fprintf(&out, "  • %.2fs - %.2fs: %s\n", ch.Start, ch.End, ch.Title)
+		}
+	}
+
+	if len(meta.Sensitive) > 0 {
+		out.WriteString("\nSensitive (removed by set_metadata with stripSensitive):\n")
+		for _, item := range meta.Sensitive {
+			fmt.Fprintf(&out, "  • %s\n", item)
+		}
+	}
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// registerSetMetadata registers the set_metadata MCP tool
+func (s *MCPServer) registerSetMetadata() {
+	s.addTool(mcp.Tool{
+		Name:        "set_metadata",
+		Description: "Write a copy of a file with new container tags, display rotation or creation time, without re-encoding. stripSensitive removes GPS location, phone and camera make/model/software tags and telemetry tracks before publishing. MP4 and MOV only store standard tags (title, artist, album, comment, description, copyright, date, genre...); use MKV for custom ones.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input media file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output file path, same container as the input",
+				},
+				"tags": map[string]interface{}{
+					"type":                 "object",
+					"description":          "Container tags to set, e.g. {\"title\": \"Launch\", \"comment\": \"\"}; an empty value removes the tag",
+					"additionalProperties": map[string]interface{}{"type": "string"},
+				},
+				"rotation": map[string]interface{}{
+					"type":        "number",
+					"enum":        []int{0, 90, 180, 270},
+					"description": "Clockwise display rotation flag, replacing any existing one; the pixels are not re-encoded",
+				},
+				"creationTime": map[string]interface{}{
+					"type":        "string",
+					"description": "Creation time as RFC 3339 (2026-06-01T12:00:00+02:00) or a date (2026-06-01), or \"now\"",
+				},
+				"stripSensitive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Remove location, device tags and data tracks (GPS/sensor telemetry), keeping title, language and other harmless tags (default: false)",
+				},
+				"removeChapters": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Remove all chapters (default: false)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleSetMetadata)
+}
+
+func (s *MCPServer) handleSetMetadata(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input          string            `json:"input"`
+		Output         string            `json:"output"`
+		Tags           map[string]string `json:"tags"`
+		Rotation       *int              `json:"rotation"`
+		CreationTime   string            `json:"creationTime"`
+		StripSensitive bool              `json:"stripSensitive"`
+		RemoveChapters bool              `json:"removeChapters"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	opts := video.MetadataOptions{
+		Input:          args.Input,
+		Output:         args.Output,
+		Tags:           args.Tags,
+		Rotation:       args.Rotation,
+		StripSensitive: args.StripSensitive,
+		RemoveChapters: args.RemoveChapters,
+	}
+	if args.CreationTime != "" {
+		created, err := parseCreationTime(args.CreationTime)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid creationTime: %v", err)), nil
+		}
+		opts.CreationTime = &created
+	}
+
+	if err := s.videoOps.SetMetadata(context.Background(), opts); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to set metadata: %v", err)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully wrote metadata: %s", args.Output)), nil
+}
+
+// parseCreationTime accepts RFC 3339 times, plain dates and "now"
+func parseCreationTime(value string) (time.Time, error) {
+	if value == "now" {
+		return time.Now(), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
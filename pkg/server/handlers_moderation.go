@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/moderation"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerModerateVideo registers the moderate_video MCP tool
+func (s *MCPServer) registerModerateVideo() {
+	s.addTool(mcp.Tool{
+		Name:        "moderate_video",
+		Description: "Pre-publish brand-safety check. Screens sampled frames with the vision model for nudity, violence and brand logos, and the transcript for profanity, then returns each flag with a time range and severity (low, medium, high) and a pass/fail verdict. A JSON report is always written for automated checks. Frames need a vision provider; transcription needs an OpenAI key unless transcriptPath is given. A check that cannot run is skipped with a warning.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "JSON report path (default: <input>_moderation.json)",
+				},
+				"categories": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string", "enum": moderation.Categories},
+					"description": "Categories to check (default: all)",
+				},
+				"failOn": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{moderation.SeverityLow, moderation.SeverityMedium, moderation.SeverityHigh},
+					"description": "Lowest severity that fails the check (default: medium)",
+				},
+				"interval": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds between sampled frames (default: duration/120, at least 2)",
+				},
+				"language": map[string]interface{}{
+					"type":        "string",
+					"description": "Language code for transcription (e.g. 'en', optional)",
+				},
+				"transcriptPath": map[string]interface{}{
+					"type":        "string",
+					"description": "Reuse a transcript JSON instead of transcribing",
+				},
+				"skipVision": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Check the transcript only (default: false)",
+				},
+				"skipTranscript": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Check frames only (default: false)",
+				},
+			},
+			Required: []string{"input"},
+		},
+	}, s.handleModerateVideo)
+}
+
+func (s *MCPServer) handleModerateVideo(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input          string   `json:"input"`
+		Output         string   `json:"output"`
+		Categories     []string `json:"categories"`
+		FailOn         string   `json:"failOn"`
+		Interval       float64  `json:"interval"`
+		Language       string   `json:"language"`
+		TranscriptPath string   `json:"transcriptPath"`
+		SkipVision     bool     `json:"skipVision"`
+		SkipTranscript bool     `json:"skipTranscript"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	report, err := s.moderator.Moderate(context.Background(), moderation.Options{
+		Input:          args.Input,
+		Categories:     args.Categories,
+		FailOn:         args.FailOn,
+		Interval:       args.Interval,
+		Language:       args.Language,
+		TranscriptPath: args.TranscriptPath,
+		SkipVision:     args.SkipVision,
+		SkipTranscript: args.SkipTranscript,
+		Output:         args.Output,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to moderate video: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("MODERATION: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	verdict := "PASS"
+	if !report.Passed {
+		verdict = "FAIL"
+	}
+	out.WriteString(fmt.Sprintf("Result: %s (fails on %s or higher)\n", verdict, report.FailOn))
+	out.WriteString(fmt.Sprintf("Checked: %s", strings.Join(report.Sources, ", ")))
+	if report.SampledFrames > 0 {
+		out.WriteString(fmt.Sprintf(" (%d frames, %d from cache)", report.SampledFrames, report.CachedFrames))
+	}
+	out.WriteString("\n\nCATEGORIES:\n")
+	for _, c := range moderation.Categories {
+		summary, ok := report.Categories[c]
+		if !ok {
+			continue
+		}
+		if summary.Flags == 0 {
+			out.WriteString(fmt.Sprintf("- %s: clear\n", c))
+			continue
+		}
+		out.WriteString(fmt.Sprintf("- %s: %d flag(s), worst %s\n", c, summary.Flags, summary.MaxSeverity))
+	}
+
+	if len(report.Flags) > 0 {
+		out.WriteString("\nFLAGS:\n")
+		for i, f := range report.Flags {
+			if i == 50 {
+				out.WriteString(fmt.Sprintf("... %d more in the report\n", len(report.Flags)-i))
+				break
+			}
+			detail := f.Detail
+			if f.Brand != "" {
+				detail = fmt.Sprintf("%s: %s", f.Brand, detail)
+			}
+			out.WriteString(fmt.Sprintf("- [%.2fs - %.2fs] %s (%s): %s\n", f.Start, f.End, f.Category, f.Severity, detail))
+		}
+	}
+	out.WriteString(fmt.Sprintf("\nReport: %s\n", report.Output))
+
+	if len(report.Warnings) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range report.Warnings {
+			out.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
@@ -0,0 +1,289 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/multicam"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerAlignCameras registers the align_cameras MCP tool
+func (s *MCPServer) registerAlignCameras() {
+	s.addTool(mcp.Tool{
+		Name:        "align_cameras",
+		Description: "Sync two or more camera angles of the same event by cross-correlating their audio. Reports each angle's offset on the reference angle's timeline and the span every angle covers; save the alignment to reuse it with switch_cameras.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"angles": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Video files, one per camera angle",
+				},
+				"reference": map[string]interface{}{
+					"type":        "number",
+					"description": "Index of the angle whose timeline the others are placed on (default: 0)",
+				},
+				"maxOffset": map[string]interface{}{
+					"type":        "number",
+					"description": "Largest offset to search in seconds, either way (default: 300)",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "JSON file to save the alignment to (optional)",
+				},
+			},
+			Required: []string{"angles"},
+		},
+	}, s.handleAlignCameras)
+}
+
+func (s *MCPServer) handleAlignCameras(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Angles    []string `json:"angles"`
+		Reference int      `json:"reference"`
+		MaxOffset float64  `json:"maxOffset"`
+		Output    string   `json:"output"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	alignment, err := s.multicamOps.AlignCameras(context.Background(), multicam.AlignOptions{
+		Angles:    args.Angles,
+		Reference: args.Reference,
+		MaxOffset: args.MaxOffset,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to align cameras: %v", err)), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Successfully aligned %d angles\n", len(alignment.Angles)))
+	b.WriteString(fmt.Sprintf("Shared span: %.3fs - %.3fs (%.1fs)\n", alignment.Start, alignment.End, alignment.End-alignment.Start))
+	for i, angle := range alignment.Angles {
+		note := ""
+		switch {
+		case i == alignment.Reference:
+			note = " (reference)"
+		case angle.Confidence < 0.2:
+			note = " - low confidence, check this angle"
+		}
+		b.WriteString(fmt.Sprintf("  [%d] %s: offset %.3fs, confidence %.2f%s\n", i, angle.Path, angle.Offset, angle.Confidence, note))
+	}
+
+	if args.Output != "" {
+		data, err := json.MarshalIndent(alignment, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to encode alignment: %v", err)), nil
+		}
+		if err := os.WriteFile(args.Output, data, 0644); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to save alignment: %v", err)), nil
+		}
+		b.WriteString(fmt.Sprintf("Alignment saved to: %s\n", args.Output))
+	}
+
+	return mcp.NewToolResultText(b.String()), nil
+}
+
+// registerSwitchCameras registers the switch_cameras MCP tool
+func (s *MCPServer) registerSwitchCameras() {
+	s.addTool(mcp.Tool{
+		Name:        "switch_cameras",
+		Description: "Render a multicam edit that cuts between aligned angles. Follow a switch list, or switch automatically: 'audio' cuts to the angle whose mic is loudest above its own noise floor, 'speakers' cuts to each speaker's angle from speaker-labelled turns (e.g. process_meeting_recording's meeting.json). Silence and crosstalk go to the wide angle when one is given.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"angles": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Video files, one per angle; aligned by audio first (or use alignment)",
+				},
+				"alignment": map[string]interface{}{
+					"type":        "string",
+					"description": "Alignment JSON saved by align_cameras (or use angles)",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"mode": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{multicam.SwitchManual, multicam.SwitchAudio, multicam.SwitchSpeakers},
+					"description": "How to pick angles (default: manual when cuts are given, otherwise audio)",
+				},
+				"cuts": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"angle": map[string]interface{}{"type": "number"},
+							"start": map[string]interface{}{"type": "number"},
+							"end":   map[string]interface{}{"type": "number"},
+						},
+					},
+					"description": "Switch list on the reference angle's timeline: [{angle, start, end}]; end defaults to the next cut's start",
+				},
+				"turns": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"speaker": map[string]interface{}{"type": "string"},
+							"start":   map[string]interface{}{"type": "number"},
+							"end":     map[string]interface{}{"type": "number"},
+						},
+					},
+					"description": "Speaker turns for speakers mode: [{speaker, start, end}] (or use turnsFile)",
+				},
+				"turnsFile": map[string]interface{}{
+					"type":        "string",
+					"description": "JSON file with speaker turns: a meeting.json from process_meeting_recording or an array of {speaker, start, end}",
+				},
+				"turnsAngle": map[string]interface{}{
+					"type":        "number",
+					"description": "Angle whose timeline the turns were timed on (default: the reference)",
+				},
+				"speakerAngles": map[string]interface{}{
+					"type":        "object",
+					"description": "Angle index for each speaker, e.g. {\"Alice\": 1, \"Bob\": 2}",
+				},
+				"wideAngle": map[string]interface{}{
+					"type":        "number",
+					"description": "Angle used for silence, crosstalk and unmapped speakers (optional)",
+				},
+				"audioAngle": map[string]interface{}{
+					"type":        "number",
+					"description": "Angle whose audio is used throughout (default: the reference)",
+				},
+				"minShot": map[string]interface{}{
+					"type":        "number",
+					"description": "Shortest shot in seconds when switching automatically (default: 2)",
+				},
+				"maxOffset": map[string]interface{}{
+					"type":        "number",
+					"description": "Largest offset to search when aligning angles (default: 300)",
+				},
+			},
+			Required: []string{"output"},
+		},
+	}, s.handleSwitchCameras)
+}
+
+func (s *MCPServer) handleSwitchCameras(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Angles        []string        `json:"angles"`
+		Alignment     string          `json:"alignment"`
+		Output        string          `json:"output"`
+		Mode          string          `json:"mode"`
+		Cuts          []multicam.Cut  `json:"cuts"`
+		Turns         []multicam.Turn `json:"turns"`
+		TurnsFile     string          `json:"turnsFile"`
+		TurnsAngle    *int            `json:"turnsAngle"`
+		SpeakerAngles map[string]int  `json:"speakerAngles"`
+		WideAngle     *int            `json:"wideAngle"`
+		AudioAngle    *int            `json:"audioAngle"`
+		MinShot       float64         `json:"minShot"`
+		MaxOffset     float64         `json:"maxOffset"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	ctx := context.Background()
+	var alignment *multicam.Alignment
+	switch {
+	case args.Alignment != "":
+		data, err := os.ReadFile(args.Alignment)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read alignment: %v", err)), nil
+		}
+		if err := json.Unmarshal(data, &alignment); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to parse alignment: %v", err)), nil
+		}
+		if alignment == nil {
+			return mcp.NewToolResultError("Failed to parse alignment: file is empty"), nil
+		}
+	case len(args.Angles) > 0:
+		var err error
+		alignment, err = s.multicamOps.AlignCameras(ctx, multicam.AlignOptions{
+			Angles:    args.Angles,
+			MaxOffset: args.MaxOffset,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to align cameras: %v", err)), nil
+		}
+	default:
+		return mcp.NewToolResultError("Either angles or alignment is required"), nil
+	}
+
+	turns := args.Turns
+	if args.TurnsFile != "" {
+		loaded, err := loadSpeakerTurns(args.TurnsFile)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to load speaker turns: %v", err)), nil
+		}
+		turns = loaded
+	}
+	if args.TurnsAngle != nil {
+		if *args.TurnsAngle < 0 || *args.TurnsAngle >= len(alignment.Angles) {
+			return mcp.NewToolResultError(fmt.Sprintf("turnsAngle %d does not exist", *args.TurnsAngle)), nil
+		}
+		offset := alignment.Angles[*args.TurnsAngle].Offset
+		for i := range turns {
+			turns[i].Start += offset
+			turns[i].End += offset
+		}
+	}
+
+	result, err := s.multicamOps.SwitchCameras(ctx, multicam.SwitchOptions{
+		Alignment:     alignment,
+		Output:        args.Output,
+		Mode:          args.Mode,
+		Cuts:          args.Cuts,
+		Turns:         turns,
+		SpeakerAngles: args.SpeakerAngles,
+		WideAngle:     args.WideAngle,
+		AudioAngle:    args.AudioAngle,
+		MinShot:       args.MinShot,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to switch cameras: %v", err)), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Successfully rendered multicam edit: %s\n", args.Output))
+	b.WriteString(fmt.Sprintf("Mode: %s, %d shots, %.1fs\n", result.Mode, len(result.Cuts), result.Duration))
+	for _, cut := range result.Cuts {
+		b.WriteString(fmt.Sprintf("  %8.2fs - %8.2fs  angle %d (%s)\n", cut.Start, cut.End, cut.Angle, alignment.Angles[cut.Angle].Path))
+	}
+
+	return mcp.NewToolResultText(b.String()), nil
+}
+
+// loadSpeakerTurns reads speaker turns from a meeting result or a plain
+// array of turns
+func loadSpeakerTurns(path string) ([]multicam.Turn, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var turns []multicam.Turn
+	if err := json.Unmarshal(data, &turns); err == nil {
+		return turns, nil
+	}
+	var meeting struct {
+		Segments []multicam.Turn `json:"segments"`
+	}
+	if err := json.Unmarshal(data, &meeting); err != nil {
+		return nil, err
+	}
+	if len(meeting.Segments) == 0 {
+		return nil, fmt.Errorf("no speaker segments found in %s", path)
+	}
+	return meeting.Segments, nil
+}
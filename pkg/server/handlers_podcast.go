@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/chapters"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/podcast"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerExportPodcast registers the export_podcast MCP tool
+func (s *MCPServer) registerExportPodcast() {
+	s.addTool(mcp.Tool{
+		Name:        "export_podcast",
+		Description: "Export the audio of a video or recording as a feed-ready podcast episode: two-pass loudness normalization to -16 LUFS (-1.5 dBTP), MP3 or AAC (.m4a) encoding at 44.1 kHz, and tags for title, host, show, episode number, cover artwork and chapters. Chapters take a list, YouTube-style text or a generate_chapters/extract_chapters file.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video or audio file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output episode file path (.mp3 or .m4a)",
+				},
+				"targetLufs": map[string]interface{}{
+					"type":        "number",
+					"description": "Integrated loudness target (default: -16, the podcast standard; -19 for mono per AES)",
+				},
+				"bitrate": map[string]interface{}{
+					"type":        "number",
+					"description": "Audio bitrate in kbps (default: 128 stereo, 64 mono)",
+				},
+				"mono": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Downmix to mono, e.g. for a single voice (default: false)",
+				},
+				"title": map[string]interface{}{
+					"type":        "string",
+					"description": "Episode title",
+				},
+				"artist": map[string]interface{}{
+					"type":        "string",
+					"description": "Host or author",
+				},
+				"show": map[string]interface{}{
+					"type":        "string",
+					"description": "Podcast name, stored as the album",
+				},
+				"episode": map[string]interface{}{
+					"type":        "number",
+					"description": "Episode number, stored as the track number",
+				},
+				"date": map[string]interface{}{
+					"type":        "string",
+					"description": "Release year or date",
+				},
+				"description": map[string]interface{}{
+					"type":        "string",
+					"description": "Episode description, stored as the comment",
+				},
+				"genre": map[string]interface{}{
+					"type":        "string",
+					"description": "Genre (default: Podcast)",
+				},
+				"artwork": map[string]interface{}{
+					"type":        "string",
+					"description": "Cover image path, cropped square and embedded at 1400x1400",
+				},
+				"chapters": map[string]interface{}{
+					"type":        "array",
+					"description": "Chapters as {start, end, title} in seconds; end is optional",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"start": map[string]interface{}{"type": "number"},
+							"end":   map[string]interface{}{"type": "number"},
+							"title": map[string]interface{}{"type": "string"},
+						},
+						"required": []string{"start", "title"},
+					},
+				},
+				"chaptersText": map[string]interface{}{
+					"type":        "string",
+					"description": "YouTube-style chapter text or JSON, instead of chapters",
+				},
+				"chaptersFile": map[string]interface{}{
+					"type":        "string",
+					"description": "File with YouTube-style text or JSON, e.g. generate_chapters' chapters.json, instead of chapters",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleExportPodcast)
+}
+
+func (s *MCPServer) handleExportPodcast(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input        string             `json:"input"`
+		Output       string             `json:"output"`
+		TargetLUFS   float64            `json:"targetLufs"`
+		Bitrate      int                `json:"bitrate"`
+		Mono         bool               `json:"mono"`
+		Title        string             `json:"title"`
+		Artist       string             `json:"artist"`
+		Show         string             `json:"show"`
+		Episode      int                `json:"episode"`
+		Date         string             `json:"date"`
+		Description  string             `json:"description"`
+		Genre        string             `json:"genre"`
+		Artwork      string             `json:"artwork"`
+		Chapters     []chapters.Chapter `json:"chapters"`
+		ChaptersText string             `json:"chaptersText"`
+		ChaptersFile string             `json:"chaptersFile"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	list := args.Chapters
+	if len(list) == 0 && (args.ChaptersText != "" || args.ChaptersFile != "") {
+		text := args.ChaptersText
+		if args.ChaptersFile != "" {
+			data, err := os.ReadFile(args.ChaptersFile)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to read chapters: %v", err)), nil
+			}
+			text = string(data)
+		}
+		var err error
+		list, err = chapters.Parse(text)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid chapters: %v", err)), nil
+		}
+	}
+
+	result, err := s.podcastExporter.Export(context.Background(), podcast.Options{
+		Input:       args.Input,
+		Output:      args.Output,
+		TargetLUFS:  args.TargetLUFS,
+		Bitrate:     args.Bitrate,
+		Mono:        args.Mono,
+		Title:       args.Title,
+		Artist:      args.Artist,
+		Show:        args.Show,
+		Episode:     args.Episode,
+		Date:        args.Date,
+		Description: args.Description,
+		Genre:       args.Genre,
+		Artwork:     args.Artwork,
+		Chapters:    list,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to export podcast: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("Successfully exported podcast episode: %s\n\n", result.Output))
+	out.WriteString(fmt.Sprintf("- Duration: %.2fs\n", result.Duration))
+	out.WriteString(fmt.Sprintf("- Loudness: %.1f LUFS (%.1f dBTP) -> %.1f LUFS\n", result.InputLUFS, result.InputPeak, result.TargetLUFS))
+	out.WriteString(fmt.Sprintf("- Bitrate: %d kbps\n", result.Bitrate))
+	out.WriteString(fmt.Sprintf("- Size: %.1f MB\n", float64(result.Bytes)/(1<<20)))
+	out.WriteString(fmt.Sprintf("- Artwork: %t\n", result.HasArtwork))
+	if len(result.Chapters) > 0 {
+		out.WriteString(fmt.Sprintf("\nCHAPTERS (%d):\n", len(result.Chapters)))
+		out.WriteString(chapters.FormatYouTube(result.Chapters, result.Duration))
+	}
+	return mcp.NewToolResultText(out.String()), nil
+}
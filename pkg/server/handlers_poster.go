@@ -0,0 +1,220 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/elements"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/poster"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/visual"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerCreateThumbnail registers the create_thumbnail MCP tool
+func (s *MCPServer) registerCreateThumbnail() {
+	s.addTool(mcp.Tool{
+		Name:        "create_thumbnail",
+		Description: "Design a thumbnail or poster from a video frame: fills the platform's size (YouTube 1280x720 by default), then adds shapes, a logo, bold outlined text and a border preset. Shapes take the same options as add_shape in thumbnail pixels. YouTube thumbnails are kept under 2 MB by lowering the JPEG quality if needed.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output image file path (jpg, png, webp)",
+				},
+				"timestamp": map[string]interface{}{
+					"type":        "number",
+					"description": "Time of the frame in seconds (use find_highlights or generate_thumbnail to pick one)",
+				},
+				"size": map[string]interface{}{
+					"type":        "string",
+					"enum":        poster.SizeNames(),
+					"description": "Platform size: youtube 1280x720, instagram 1080x1080, instagram_portrait 1080x1350, story 1080x1920, twitter 1600x900, facebook 1200x630, linkedin 1200x627 (default: youtube)",
+				},
+				"stylePreset": map[string]interface{}{
+					"type":        "string",
+					"enum":        visual.StylePresetNames(),
+					"description": "Style preset look for the frame, as with apply_style_preset (optional)",
+				},
+				"filter": map[string]interface{}{
+					"type":        "string",
+					"description": "FFmpeg filter chain for the frame, e.g. the video's color grade (optional)",
+				},
+				"texts": map[string]interface{}{
+					"type":        "array",
+					"description": "Text blocks, drawn over the frame, shapes and logo; long text wraps to the frame",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"text":         map[string]interface{}{"type": "string"},
+							"position":     map[string]interface{}{"type": "string", "description": "top-left, top-center, top-right, center-left, center, center-right, bottom-left, bottom-center or bottom-right (default: center)"},
+							"font":         map[string]interface{}{"type": "string", "description": "Font name or file (see list_fonts)"},
+							"fontSize":     map[string]interface{}{"type": "number", "description": "Pixels (default: an eighth of the height)"},
+							"color":        map[string]interface{}{"type": "string", "description": "Default: white"},
+							"outlineColor": map[string]interface{}{"type": "string", "description": "Default: black"},
+							"outlineWidth": map[string]interface{}{"type": "number", "description": "Default: a fifteenth of the font size"},
+							"box":          map[string]interface{}{"type": "boolean", "description": "Draw a box behind the text"},
+							"boxColor":     map[string]interface{}{"type": "string", "description": "Default: black"},
+						},
+						"required": []string{"text"},
+					},
+				},
+				"shapes": map[string]interface{}{
+					"type":        "array",
+					"description": "Shapes drawn over the frame, as for add_shape: shape (rectangle, circle, line, arrow, polygon), x, y, width, height, radius, x2, y2, points, color, borderColor, borderWidth, opacity, cornerRadius, dash, headSize",
+					"items":       map[string]interface{}{"type": "object"},
+				},
+				"logo": map[string]interface{}{
+					"type":        "object",
+					"description": "Logo image to place on the thumbnail",
+					"properties": map[string]interface{}{
+						"image":    map[string]interface{}{"type": "string", "description": "Image file path (PNG with transparency works best)"},
+						"position": map[string]interface{}{"type": "string", "description": "As for texts (default: bottom-right)"},
+						"width":    map[string]interface{}{"type": "number", "description": "Fraction of the thumbnail width (default: 0.15)"},
+					},
+				},
+				"border": map[string]interface{}{
+					"type":        "string",
+					"enum":        poster.BorderNames,
+					"description": "Border preset (default: none)",
+				},
+				"borderColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Border color (default: white)",
+				},
+				"quality": map[string]interface{}{
+					"type":        "number",
+					"description": "Quality 1-100 for jpg and webp (default: 92)",
+				},
+			},
+			Required: []string{"input", "output", "timestamp"},
+		},
+	}, s.handleCreateThumbnail)
+}
+
+func (s *MCPServer) handleCreateThumbnail(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input       string  `json:"input"`
+		Output      string  `json:"output"`
+		Timestamp   float64 `json:"timestamp"`
+		Size        string  `json:"size"`
+		StylePreset string  `json:"stylePreset"`
+		Filter      string  `json:"filter"`
+		Texts       []struct {
+			Text         string `json:"text"`
+			Position     string `json:"position"`
+			Font         string `json:"font"`
+			FontSize     int    `json:"fontSize"`
+			Color        string `json:"color"`
+			OutlineColor string `json:"outlineColor"`
+			OutlineWidth *int   `json:"outlineWidth"`
+			Box          bool   `json:"box"`
+			BoxColor     string `json:"boxColor"`
+		} `json:"texts"`
+		Shapes []struct {
+			Shape        string           `json:"shape"`
+			X            int              `json:"x"`
+			Y            int              `json:"y"`
+			Width        *int             `json:"width"`
+			Height       *int             `json:"height"`
+			Radius       *int             `json:"radius"`
+			X2           *int             `json:"x2"`
+			Y2           *int             `json:"y2"`
+			Points       []elements.Point `json:"points"`
+			Color        string           `json:"color"`
+			BorderColor  *string          `json:"borderColor"`
+			BorderWidth  int              `json:"borderWidth"`
+			Opacity      float64          `json:"opacity"`
+			CornerRadius int              `json:"cornerRadius"`
+			Dash         int              `json:"dash"`
+			HeadSize     int              `json:"headSize"`
+		} `json:"shapes"`
+		Logo        *poster.Logo `json:"logo"`
+		Border      string       `json:"border"`
+		BorderColor string       `json:"borderColor"`
+		Quality     int          `json:"quality"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	var filters []string
+	if args.StylePreset != "" {
+		preset, ok := visual.StylePresets[args.StylePreset]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("Unknown style preset %q (use %s)", args.StylePreset, strings.Join(visual.StylePresetNames(), ", "))), nil
+		}
+		filters = append(filters, preset.Filter)
+	}
+	if args.Filter != "" {
+		filters = append(filters, args.Filter)
+	}
+
+	opts := poster.Options{
+		Input:       args.Input,
+		Output:      args.Output,
+		Timestamp:   args.Timestamp,
+		Size:        args.Size,
+		Filter:      strings.Join(filters, ","),
+		Logo:        args.Logo,
+		Border:      args.Border,
+		BorderColor: args.BorderColor,
+		Quality:     args.Quality,
+	}
+	for _, t := range args.Texts {
+		opts.Texts = append(opts.Texts, poster.Text{
+			Text:         t.Text,
+			Position:     t.Position,
+			FontFile:     t.Font,
+			FontSize:     t.FontSize,
+			Color:        t.Color,
+			OutlineColor: t.OutlineColor,
+			OutlineWidth: t.OutlineWidth,
+			Box:          t.Box,
+			BoxColor:     t.BoxColor,
+		})
+	}
+	for _, sh := range args.Shapes {
+		shape := elements.ShapeOptions{
+			Shape:        sh.Shape,
+			X:            sh.X,
+			Y:            sh.Y,
+			Width:        sh.Width,
+			Height:       sh.Height,
+			Radius:       sh.Radius,
+			X2:           sh.X2,
+			Y2:           sh.Y2,
+			Points:       sh.Points,
+			Color:        sh.Color,
+			BorderColor:  sh.BorderColor,
+			BorderWidth:  sh.BorderWidth,
+			Opacity:      sh.Opacity,
+			CornerRadius: sh.CornerRadius,
+			Dash:         sh.Dash,
+			HeadSize:     sh.HeadSize,
+		}
+		if shape.Color == "" {
+			shape.Color = "white"
+		}
+		if shape.Opacity == 0 {
+			shape.Opacity = 1.0
+		}
+		opts.Shapes = append(opts.Shapes, shape)
+	}
+
+	result, err := s.posterDesigner.Create(context.Background(), opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create thumbnail: %v", err)), nil
+	}
+
+	summary := fmt.Sprintf("Successfully created %dx%d thumbnail (%.0f KB", result.Width, result.Height, float64(result.Bytes)/1024)
+	if result.Quality > 0 {
+		summary += fmt.Sprintf(", quality %d", result.Quality)
+	}
+	return mcp.NewToolResultText(summary + "): " + result.Output), nil
+}
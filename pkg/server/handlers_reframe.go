@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/reframe"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerReframeVideo registers the reframe_video MCP tool
+func (s *MCPServer) registerReframeVideo() {
+	s.addTool(mcp.Tool{
+		Name:        "reframe_video",
+		Description: "Convert landscape video to 9:16 or 1:1 with a crop window that follows the main subject, so the speaker doesn't drift out of frame. Sampled frames are checked for the speaker's face and shoulders with the vision model (or tracked by motion without one), and the crop is smoothed and interpolated between keyframes. Pass a trackFile from track_object to follow an object instead.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path (default: <input>_9x16.mp4 or <input>_1x1.mp4)",
+				},
+				"aspect": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"9:16", "1:1"},
+					"description": "Target aspect ratio (default: 9:16)",
+				},
+				"engine": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{reframe.EngineAuto, reframe.EngineVision, reframe.EngineMotion},
+					"description": "vision locates the speaker's face with the vision model, motion follows edge detail and movement without API calls, auto uses vision when a provider is configured and falls back to motion (default: auto)",
+				},
+				"interval": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds between sampled frames (default: duration/120, at least 1)",
+				},
+				"trackFile": map[string]interface{}{
+					"type":        "string",
+					"description": "JSON file saved by track_object or detect_faces; the crop follows a track instead of the speaker",
+				},
+				"trackId": map[string]interface{}{
+					"type":        "number",
+					"description": "Track ID in trackFile to follow (default: 1)",
+				},
+				"quality": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"high", "medium", "low"},
+					"description": "Encoding quality (default: high)",
+				},
+			},
+			Required: []string{"input"},
+		},
+	}, s.handleReframeVideo)
+}
+
+func (s *MCPServer) handleReframeVideo(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input     string  `json:"input"`
+		Output    string  `json:"output"`
+		Aspect    string  `json:"aspect"`
+		Engine    string  `json:"engine"`
+		Interval  float64 `json:"interval"`
+		Quality   string  `json:"quality"`
+		TrackFile string  `json:"trackFile"`
+		TrackID   *int    `json:"trackId"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	opts := reframe.Options{
+		Input:    args.Input,
+		Output:   args.Output,
+		Aspect:   args.Aspect,
+		Engine:   args.Engine,
+		Interval: args.Interval,
+		Quality:  args.Quality,
+	}
+	if args.TrackFile != "" {
+		id := 1
+		if args.TrackID != nil {
+			id = *args.TrackID
+		}
+		tracks, err := loadTracks(args.TrackFile, []int{id})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		opts.Follow = &tracks[0]
+	}
+
+	result, err := s.reframer.Reframe(context.Background(), opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to reframe video: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("REFRAME: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Output: %s (%s, %dx%d)\n", result.Output, result.Aspect, result.Width, result.Height))
+	out.WriteString(fmt.Sprintf("Crop width: %dpx\n", result.CropWidth))
+	out.WriteString(fmt.Sprintf("Tracking: %s\n", result.Engine))
+	if result.Samples > 0 {
+		out.WriteString(fmt.Sprintf("Subject found in %d of %d frames\n", result.Detected, result.Samples))
+	}
+
+	out.WriteString(fmt.Sprintf("\nCAMERA PATH (%d keyframes, center as %% of width):\n", len(result.Path)))
+	for i, k := range result.Path {
+		if i == 20 {
+			out.WriteString(fmt.Sprintf("... %d more\n", len(result.Path)-i))
+			break
+		}
+		out.WriteString(fmt.Sprintf("- %.2fs: %.0f%%\n", k.Time, k.Center*100))
+	}
+
+	if len(result.Warnings) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range result.Warnings {
+			out.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
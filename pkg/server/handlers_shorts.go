@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/captions"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/shorts"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerCreateShort registers the create_short MCP tool
+func (s *MCPServer) registerCreateShort() {
+	s.addTool(mcp.Tool{
+		Name:        "create_short",
+		Description: "Turn a long video into a vertical short about a topic in one call. Finds the segment that matches the query in the transcript and in sampled frames, cuts it at sentence boundaries, crops to 9:16 following the subject, burns karaoke captions and exports with the platform preset. Transcription needs an OpenAI key unless transcriptPath is given; visual search needs a vision provider. Missing signals are skipped with a warning.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "Topic, phrase or visual the short should be about (e.g. 'the new pricing plan')",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path (default: <input>_<platform>.mp4). The transcript and ASS script are saved next to it.",
+				},
+				"platform": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"shorts", "tiktok", "reels"},
+					"description": "Export preset; also caps the length at 60s for shorts, 180s for tiktok and 90s for reels (default: shorts)",
+				},
+				"minLength": map[string]interface{}{
+					"type":        "number",
+					"description": "Minimum short length in seconds (default: 15)",
+				},
+				"maxLength": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum short length in seconds (default: 60)",
+				},
+				"language": map[string]interface{}{
+					"type":        "string",
+					"description": "Language code for transcription (e.g. 'en', optional)",
+				},
+				"transcriptPath": map[string]interface{}{
+					"type":        "string",
+					"description": "Reuse a transcript JSON with word timestamps instead of transcribing",
+				},
+				"skipVision": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Search the transcript only (default: false)",
+				},
+				"visionInterval": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds between frames searched with the vision model (default: duration/120, at least 5)",
+				},
+				"centerCrop": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Use a fixed center crop instead of following the subject (default: false)",
+				},
+				"tracking": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"auto", "vision", "motion"},
+					"description": "Subject tracking: vision finds the speaker's face with the vision model, motion follows edge detail and movement without API calls, auto uses vision when a provider is configured (default: auto)",
+				},
+				"captions": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Burn karaoke captions (default: true)",
+				},
+				"highlightColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Caption color of spoken words (default: yellow)",
+				},
+				"captionPosition": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"bottom", "center", "top"},
+					"description": "Caption position (default: bottom)",
+				},
+				"wordsPerLine": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum caption words shown at once (default: 3)",
+				},
+			},
+			Required: []string{"input", "query"},
+		},
+	}, s.handleCreateShort)
+}
+
+func (s *MCPServer) handleCreateShort(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input           string  `json:"input"`
+		Query           string  `json:"query"`
+		Output          string  `json:"output"`
+		Platform        string  `json:"platform"`
+		MinLength       float64 `json:"minLength"`
+		MaxLength       float64 `json:"maxLength"`
+		Language        string  `json:"language"`
+		TranscriptPath  string  `json:"transcriptPath"`
+		SkipVision      bool    `json:"skipVision"`
+		VisionInterval  float64 `json:"visionInterval"`
+		CenterCrop      bool    `json:"centerCrop"`
+		Tracking        string  `json:"tracking"`
+		Captions        *bool   `json:"captions"`
+		HighlightColor  string  `json:"highlightColor"`
+		CaptionPosition string  `json:"captionPosition"`
+		WordsPerLine    int     `json:"wordsPerLine"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.shortCreator.Create(context.Background(), shorts.Options{
+		Input:          args.Input,
+		Query:          args.Query,
+		Output:         args.Output,
+		Platform:       args.Platform,
+		MinLength:      args.MinLength,
+		MaxLength:      args.MaxLength,
+		Language:       args.Language,
+		TranscriptPath: args.TranscriptPath,
+		SkipVision:     args.SkipVision,
+		VisionInterval: args.VisionInterval,
+		CenterCrop:     args.CenterCrop,
+		Tracking:       args.Tracking,
+		SkipCaptions:   args.Captions != nil && !*args.Captions,
+		Captions: captions.KaraokeOptions{
+			HighlightColor: args.HighlightColor,
+			Position:       args.CaptionPosition,
+			WordsPerLine:   args.WordsPerLine,
+		},
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create short: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("SHORT: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Query: %s\n", args.Query))
+	out.WriteString(fmt.Sprintf("Segment: %.2fs - %.2fs (%.1fs), relevance %.2f\n", result.Start, result.End, result.Duration, result.Score))
+	out.WriteString(fmt.Sprintf("Signals: %s\n", strings.Join(result.Sources, ", ")))
+	if len(result.Matched) > 0 {
+		out.WriteString(fmt.Sprintf("Matched words: %s\n", strings.Join(result.Matched, ", ")))
+	}
+	if result.VisualMatches > 0 {
+		out.WriteString(fmt.Sprintf("Matching frames: %d\n", result.VisualMatches))
+	}
+	framing := result.Framing
+	if result.Keyframes > 0 {
+		framing = fmt.Sprintf("%s subject tracking (%d keyframes)", framing, result.Keyframes)
+	}
+	out.WriteString(fmt.Sprintf("Framing: %s\n", framing))
+	if result.Text != "" {
+		text := result.Text
+		if runes := []rune(text); len(runes) > 300 {
+			text = string(runes[:297]) + "..."
+		}
+		out.WriteString(fmt.Sprintf("\n\"%s\"\n", text))
+	}
+
+	out.WriteString("\nOUTPUTS:\n")
+	out.WriteString(fmt.Sprintf("- Video (%s, 1080x1920): %s\n", result.Platform, result.Output))
+	if result.CaptionsFile != "" {
+		out.WriteString(fmt.Sprintf("- Captions (%d): %s\n", result.Captions, result.CaptionsFile))
+	}
+	if result.TranscriptFile != "" {
+		out.WriteString(fmt.Sprintf("- Transcript: %s\n", result.TranscriptFile))
+	}
+
+	if len(result.Warnings) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range result.Warnings {
+			out.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
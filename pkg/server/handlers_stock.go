@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/stock"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerSearchStockMedia registers the search_stock_media MCP tool
+func (s *MCPServer) registerSearchStockMedia() {
+	s.addTool(mcp.Tool{
+		Name:        "search_stock_media",
+		Description: "Search Pexels and Pixabay for free stock clips or photos and download them into the project's asset folder for add_image_overlay, create_picture_in_picture, create_slideshow and composites. Each download gets a JSON sidecar with its license, author and credit line. Requires pexelsKey or pixabayKey (set_config, PEXELS_API_KEY or PIXABAY_API_KEY).",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "What to search for, e.g. 'city traffic at night'",
+				},
+				"type": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{stock.TypeVideo, stock.TypeImage},
+					"description": "video clips or still images (default: video)",
+				},
+				"provider": map[string]interface{}{
+					"type":        "string",
+					"enum":        stock.Providers,
+					"description": "Search one provider (default: every provider with a key)",
+				},
+				"orientation": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{stock.OrientationLandscape, stock.OrientationPortrait, stock.OrientationSquare},
+					"description": "Only return this orientation (optional)",
+				},
+				"count": map[string]interface{}{
+					"type":        "number",
+					"description": "Results per provider, at most 20 (default: 5)",
+				},
+				"minDuration": map[string]interface{}{
+					"type":        "number",
+					"description": "Shortest clip in seconds (optional)",
+				},
+				"maxDuration": map[string]interface{}{
+					"type":        "number",
+					"description": "Longest clip in seconds (optional)",
+				},
+				"maxWidth": map[string]interface{}{
+					"type":        "number",
+					"description": "Widest video rendition to download in pixels (default: 1920)",
+				},
+				"download": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Download results into the asset folder (default: true); false only lists them",
+				},
+				"folder": map[string]interface{}{
+					"type":        "string",
+					"description": "Folder to download into (default: <assetDir>/stock)",
+				},
+			},
+			Required: []string{"query"},
+		},
+	}, s.handleSearchStockMedia)
+}
+
+func (s *MCPServer) handleSearchStockMedia(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Query       string  `json:"query"`
+		Type        string  `json:"type"`
+		Provider    string  `json:"provider"`
+		Orientation string  `json:"orientation"`
+		Count       int     `json:"count"`
+		MinDuration float64 `json:"minDuration"`
+		MaxDuration float64 `json:"maxDuration"`
+		MaxWidth    int     `json:"maxWidth"`
+		Download    *bool   `json:"download"`
+		Folder      string  `json:"folder"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.stockClient.Search(context.Background(), stock.SearchOptions{
+		Query:       args.Query,
+		Type:        args.Type,
+		Provider:    args.Provider,
+		Orientation: args.Orientation,
+		Count:       args.Count,
+		MinDuration: args.MinDuration,
+		MaxDuration: args.MaxDuration,
+		MaxWidth:    args.MaxWidth,
+		Download:    args.Download == nil || *args.Download,
+		Folder:      args.Folder,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to search stock media: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("STOCK MEDIA: %s\n", result.Query))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Results: %d\n", len(result.Items)))
+	if result.Folder != "" {
+		out.WriteString(fmt.Sprintf("Folder: %s\n", result.Folder))
+	}
+
+	for i, item := range result.Items {
+		size := fmt.Sprintf("%dx%d", item.Width, item.Height)
+		if item.Type == stock.TypeVideo {
+			size += fmt.Sprintf(", %.0fs", item.Duration)
+		}
+		out.WriteString(fmt.Sprintf("\n%d. %s %s %s (%s)\n", i+1, item.Provider, item.Type, item.ID, size))
+		if item.Description != "" {
+			out.WriteString(fmt.Sprintf("   %s\n", item.Description))
+		} else if len(item.Tags) > 0 {
+			out.WriteString(fmt.Sprintf("   Tags: %s\n", strings.Join(item.Tags, ", ")))
+		}
+		if item.Path != "" {
+			out.WriteString(fmt.Sprintf("   File: %s\n", item.Path))
+		} else {
+			out.WriteString(fmt.Sprintf("   Download: %s\n", item.DownloadURL))
+		}
+		out.WriteString(fmt.Sprintf("   Credit: %s\n", item.Attribution))
+		out.WriteString(fmt.Sprintf("   License: %s (%s)\n", item.License.Name, item.License.URL))
+		out.WriteString(fmt.Sprintf("   Source: %s\n", item.PageURL))
+	}
+
+	if len(result.Items) > 0 {
+		out.WriteString("\nLicenses allow free commercial use and editing; credit is appreciated but not required. Downloaded files keep their license in <file>.json.\n")
+	}
+	if len(result.Warnings) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range result.Warnings {
+			out.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
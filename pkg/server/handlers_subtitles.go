@@ -0,0 +1,138 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/subtitles"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerConvertSubtitles registers the convert_subtitles MCP tool
+func (s *MCPServer) registerConvertSubtitles() {
+	s.addTool(mcp.Tool{
+		Name:        "convert_subtitles",
+		Description: "Convert subtitles between SRT, WebVTT and ASS. The format is taken from the file extensions; italic/bold/underline formatting is carried over.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input subtitle file (.srt, .vtt or .ass)",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output subtitle file (.srt, .vtt or .ass)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleConvertSubtitles)
+}
+
+func (s *MCPServer) handleConvertSubtitles(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input  string `json:"input"`
+		Output string `json:"output"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	count, err := subtitles.ConvertFile(args.Input, args.Output)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to convert subtitles: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully converted %d cues to: %s", count, args.Output)), nil
+}
+
+// registerShiftSubtitles registers the shift_subtitles MCP tool
+func (s *MCPServer) registerShiftSubtitles() {
+	s.addTool(mcp.Tool{
+		Name:        "shift_subtitles",
+		Description: "Shift all subtitle timings earlier or later by a number of seconds. Cues pushed before 0 are trimmed or dropped; ASS styling is preserved.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input subtitle file (.srt, .vtt or .ass)",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output subtitle file in the same format",
+				},
+				"offset": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds to shift by; positive delays subtitles, negative makes them appear earlier",
+				},
+			},
+			Required: []string{"input", "output", "offset"},
+		},
+	}, s.handleShiftSubtitles)
+}
+
+func (s *MCPServer) handleShiftSubtitles(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input  string  `json:"input"`
+		Output string  `json:"output"`
+		Offset float64 `json:"offset"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	count, err := subtitles.RetimeFile(args.Input, args.Output, subtitles.Shift(args.Offset))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to shift subtitles: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully shifted %d cues by %+.3fs to: %s", count, args.Offset, args.Output)), nil
+}
+
+// registerScaleSubtitles registers the scale_subtitles MCP tool
+func (s *MCPServer) registerScaleSubtitles() {
+	s.addTool(mcp.Tool{
+		Name:        "scale_subtitles",
+		Description: "Retime subtitles after a speed change (e.g. after adjust_speed) so they stay in sync. ASS styling is preserved.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input subtitle file (.srt, .vtt or .ass)",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output subtitle file in the same format",
+				},
+				"speed": map[string]interface{}{
+					"type":        "number",
+					"description": "Speed factor applied to the video (2.0 = twice as fast, 0.5 = half speed)",
+				},
+			},
+			Required: []string{"input", "output", "speed"},
+		},
+	}, s.handleScaleSubtitles)
+}
+
+func (s *MCPServer) handleScaleSubtitles(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input  string  `json:"input"`
+		Output string  `json:"output"`
+		Speed  float64 `json:"speed"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+	if args.Speed <= 0 {
+		return mcp.NewToolResultError("speed must be greater than 0"), nil
+	}
+
+	count, err := subtitles.RetimeFile(args.Input, args.Output, subtitles.Scale(args.Speed))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to scale subtitles: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully retimed %d cues for %.2fx speed to: %s", count, args.Speed, args.Output)), nil
+}
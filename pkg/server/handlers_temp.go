@@ -0,0 +1,89 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultTempAge is how old temp files must be before cleanup_temp removes
+// them, so another server's running operations aren't disturbed
+const defaultTempAge = 60
+
+// registerCleanupTemp registers the cleanup_temp MCP tool
+func (s *MCPServer) registerCleanupTemp() {
+	s.addTool(mcp.Tool{
+		Name:        "cleanup_temp",
+		Description: "Remove temporary files (segments, concat lists, extracted frames, rendered diagrams) left by interrupted or crashed operations. Each operation works in its own directory under <tempDir>/mcp-video-editor-tmp (the active project's temp/ folder while one is active) and removes it when it finishes or fails; directories of running operations are never removed.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"olderThanMinutes": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Only remove files last modified at least this many minutes ago (default: %d; 0 removes everything not in use)", defaultTempAge),
+				},
+				"dryRun": map[string]interface{}{
+					"type":        "boolean",
+					"description": "List what would be removed without removing it (default: false)",
+				},
+			},
+			Required: []string{},
+		},
+	}, s.handleCleanupTemp)
+}
+
+func (s *MCPServer) handleCleanupTemp(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		OlderThanMinutes *float64 `json:"olderThanMinutes"`
+		DryRun           bool     `json:"dryRun"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+	minutes := float64(defaultTempAge)
+	if args.OlderThanMinutes != nil {
+		if *args.OlderThanMinutes < 0 {
+			return mcp.NewToolResultError("olderThanMinutes must not be negative"), nil
+		}
+		minutes = *args.OlderThanMinutes
+	}
+
+	result, err := scratch.Clean(time.Duration(minutes*float64(time.Minute)), args.DryRun)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to clean up temp files: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("TEMP CLEANUP: %s\n", scratch.Root()))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	verb := "Removed"
+	if args.DryRun {
+		verb = "Would remove"
+	}
+	out.WriteString(fmt.Sprintf("%s: %d item(s), %.1f MB\n", verb, len(result.Removed), float64(result.Bytes)/(1024*1024)))
+	for _, e := range result.Removed {
+		out.WriteString(fmt.Sprintf("- %s (%.1f MB, %s)\n", e.Path, float64(e.Size)/(1024*1024), e.Modified.Format("2006-01-02 15:04")))
+	}
+	if len(result.Kept) > 0 {
+		out.WriteString(fmt.Sprintf("\nKept: %d item(s)\n", len(result.Kept)))
+		for _, e := range result.Kept {
+			reason := "newer than the age limit"
+			if e.Active {
+				reason = "in use"
+			}
+			out.WriteString(fmt.Sprintf("- %s (%s)\n", e.Path, reason))
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range result.Errors {
+			out.WriteString(fmt.Sprintf("- Could not remove %s\n", w))
+		}
+	}
+	return mcp.NewToolResultText(out.String()), nil
+}
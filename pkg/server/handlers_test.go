@@ -2,12 +2,24 @@ package server
 
 import (
 	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/remote"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/workspace"
+	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func setupServerTest(t *testing.T) (*MCPServer, string) {
@@ -305,3 +317,280 @@ func TestHandleAddTransition(t *testing.T) {
 		t.Fatal("Expected result, got nil")
 	}
 }
+
+func TestLoadTracks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tracks.json")
+	data := `{"videoPath": "a.mp4", "query": "red car", "tracks": [{"id": 1, "start": 0, "end": 3, "keyframes": [{"time": 1, "x": 0.1, "y": 0.2, "w": 0.3, "h": 0.2}]}, {"id": 2, "start": 4, "end": 6, "keyframes": []}]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tracks, err := loadTracks(path, nil)
+	if err != nil || len(tracks) != 2 {
+		t.Fatalf("loadTracks() = %+v, %v", tracks, err)
+	}
+	if k := tracks[0].Keyframes[0]; k.Time != 1 || k.X != 0.1 || k.W != 0.3 {
+		t.Errorf("Unexpected keyframe: %+v", k)
+	}
+
+	tracks, err = loadTracks(path, []int{2})
+	if err != nil || len(tracks) != 1 || tracks[0].ID != 2 {
+		t.Errorf("loadTracks(ids=[2]) = %+v, %v", tracks, err)
+	}
+	if _, err := loadTracks(path, []int{5}); err == nil {
+		t.Error("Expected an error for a missing track ID")
+	}
+}
+
+func TestWithRemotePaths(t *testing.T) {
+	var mu sync.Mutex
+	uploads := map[string]string{}
+	store := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte("source"))
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			uploads[r.URL.Path] = string(body)
+			mu.Unlock()
+		}
+	}))
+	defer store.Close()
+
+	cfg := &config.Config{TempDir: t.TempDir(), RemoteCacheDir: t.TempDir(), S3Endpoint: store.URL}
+	s := &MCPServer{config: cfg, remoteStore: remote.NewStore(cfg)}
+
+	var gotInput, gotOutput, gotText string
+	handler := s.withRemotePaths("trim_video", func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		gotInput, gotOutput, gotText = arguments["input"].(string), arguments["output"].(string), arguments["text"].(string)
+		data, err := os.ReadFile(gotInput)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if err := os.WriteFile(gotOutput, append(data, " edited"...), 0644); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText("Wrote " + gotOutput + " from " + gotInput), nil
+	})
+
+	arguments := map[string]interface{}{
+		"input":  "s3://media/in.mp4",
+		"output": "s3://media/out/final.mp4",
+		"text":   "https://example.com/watch.html",
+	}
+	result, err := handler(arguments)
+	if err != nil || result.IsError {
+		t.Fatalf("Handler failed: %+v, %v", result, err)
+	}
+	if gotText != "https://example.com/watch.html" {
+		t.Errorf("Text argument was staged: %s", gotText)
+	}
+	if arguments["input"] != "s3://media/in.mp4" {
+		t.Error("Caller's arguments were modified")
+	}
+	if uploads["/media/out/final.mp4"] != "source edited" {
+		t.Errorf("Unexpected uploads: %v", uploads)
+	}
+	if _, err := os.Stat(gotOutput); !os.IsNotExist(err) {
+		t.Error("Expected the staged output to be removed")
+	}
+
+	text, _ := mcp.AsTextContent(result.Content[0])
+	if !strings.HasPrefix(text.Text, "Wrote s3://media/out/final.mp4 from s3://media/in.mp4") || !strings.Contains(text.Text, "UPLOADED:") {
+		t.Errorf("Unexpected result text: %s", text.Text)
+	}
+
+	recording := s.withRemotePaths("start_recording", func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		t.Error("start_recording ran with a remote output")
+		return mcp.NewToolResultText("Recording started"), nil
+	})
+	result, err = recording(map[string]interface{}{"output": "s3://media/screen.mkv"})
+	if err != nil || !result.IsError {
+		t.Errorf("Expected a remote recording output to be refused, got %+v, %v", result, err)
+	}
+}
+
+func TestWithRemotePathsSidecars(t *testing.T) {
+	var mu sync.Mutex
+	uploads := map[string]string{}
+	store := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		uploads[r.URL.Path] = string(body)
+		mu.Unlock()
+	}))
+	defer store.Close()
+
+	cfg := &config.Config{TempDir: t.TempDir(), RemoteCacheDir: t.TempDir(), S3Endpoint: store.URL}
+	s := &MCPServer{config: cfg, remoteStore: remote.NewStore(cfg)}
+
+	// An archival master with its checksum sidecars
+	handler := s.withRemotePaths("convert_video", func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		output := arguments["output"].(string)
+		for _, f := range append([]string{output}, video.ArchiveChecksumFiles(output)...) {
+			if err := os.WriteFile(f, []byte(filepath.Ext(f)), 0644); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		return mcp.NewToolResultText("Checksums:\n- " + strings.Join(video.ArchiveChecksumFiles(output), "\n- ")), nil
+	})
+	result, err := handler(map[string]interface{}{"input": "/media/in.mov", "output": "s3://archive/master.mkv"})
+	if err != nil || result.IsError {
+		t.Fatalf("Handler failed: %+v, %v", result, err)
+	}
+	for path, body := range map[string]string{
+		"/archive/master.mkv":          ".mkv",
+		"/archive/master.mkv.framemd5": ".framemd5",
+		"/archive/master.mkv.sha256":   ".sha256",
+	} {
+		if uploads[path] != body {
+			t.Errorf("Expected %s to be uploaded, got %v", path, uploads)
+		}
+	}
+	text, _ := mcp.AsTextContent(result.Content[0])
+	if !strings.Contains(text.Text, "- s3://archive/master.mkv.sha256") || strings.Contains(text.Text, cfg.TempDir) {
+		t.Errorf("Unexpected result text: %s", text.Text)
+	}
+}
+
+func TestProjectArguments(t *testing.T) {
+	p := &workspace.Project{Name: "demo", Root: t.TempDir(), Naming: workspace.DefaultNaming}
+	if err := os.MkdirAll(p.Dir(workspace.DirAssets), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(p.Dir(workspace.DirAssets), "clip.mov"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := projectArguments(p, "trim_video", map[string]interface{}{"input": "clip.mov", "output": "cuts/short.mp4", "startTime": 1.0, "aspect": "9:16"})
+	if got["input"] != filepath.Join(p.Dir(workspace.DirAssets), "clip.mov") {
+		t.Errorf("input = %v", got["input"])
+	}
+	if got["output"] != filepath.Join(p.Dir(workspace.DirOutputs), "cuts", "short.mp4") {
+		t.Errorf("output = %v", got["output"])
+	}
+	if _, err := os.Stat(filepath.Join(p.Dir(workspace.DirOutputs), "cuts")); err != nil {
+		t.Error("output directory was not created")
+	}
+	if got["aspect"] != "9:16" || got["startTime"] != 1.0 {
+		t.Errorf("non-path arguments changed: %v", got)
+	}
+
+	got = projectArguments(p, "extract_subtitles", map[string]interface{}{"input": "/abs/in.mp4", "output": "talk.srt"})
+	if got["input"] != "/abs/in.mp4" || got["output"] != filepath.Join(p.Dir(workspace.DirTranscripts), "talk.srt") {
+		t.Errorf("explicit paths = %v", got)
+	}
+}
+
+func TestOptionalOutputs(t *testing.T) {
+	tool, outputs := optionalOutputs(mcp.Tool{Name: "trim_video", InputSchema: mcp.ToolInputSchema{
+		Properties: map[string]interface{}{"output": map[string]interface{}{"type": "string", "description": "Output video file path"}},
+		Required:   []string{"input", "output"},
+	}})
+	if len(outputs) != 1 || outputs[0] != "output" || len(tool.InputSchema.Required) != 1 || tool.InputSchema.Required[0] != "input" {
+		t.Errorf("optionalOutputs = %v, required %v", outputs, tool.InputSchema.Required)
+	}
+	description := tool.InputSchema.Properties["output"].(map[string]interface{})["description"].(string)
+	if !strings.Contains(description, "<input>_trimmed_001") {
+		t.Errorf("description = %q", description)
+	}
+	if _, outputs := optionalOutputs(mcp.Tool{Name: "convert_subtitles", InputSchema: mcp.ToolInputSchema{Required: []string{"input", "output"}}}); outputs != nil {
+		t.Error("convert_subtitles output should stay required")
+	}
+}
+
+// TestToolsRegisterThroughAddTool fails when a tool is added to the MCP
+// server directly, which skips optional outputs and remote and project paths
+func TestToolsRegisterThroughAddTool(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || fn.Name.Name == "addTool" {
+				continue
+			}
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "AddTool" {
+					t.Errorf("%s: %s registers a tool outside addTool", fset.Position(call.Pos()), fn.Name.Name)
+				}
+				return true
+			})
+		}
+	}
+
+	s, testDir := setupServerTest(t)
+	defer cleanup(testDir)
+	for _, tool := range s.GetToolDefinitions() {
+		if tool.Name != "trim_audio" {
+			continue
+		}
+		for _, key := range tool.InputSchema.Required {
+			if key == "output" {
+				t.Error("trim_audio output should be optional")
+			}
+		}
+		return
+	}
+	t.Error("trim_audio is missing from the tool definitions")
+}
+
+func TestWithOutputPaths(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "interview.mov")
+	s := &MCPServer{}
+
+	var got string
+	handler := s.withOutputPaths("trim_video", []string{"output"}, func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		got = arguments["output"].(string)
+		return mcp.NewToolResultText("Trimmed to " + got), nil
+	})
+	result, err := handler(map[string]interface{}{"input": input})
+	if err != nil || result.IsError {
+		t.Fatalf("handler failed: %+v, %v", result, err)
+	}
+	if want := filepath.Join(dir, "interview_trimmed_001.mov"); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	text, _ := mcp.AsTextContent(result.Content[0])
+	if !strings.Contains(text.Text, "OUTPUT PATHS:\n- output: "+got) {
+		t.Errorf("result does not list the output: %s", text.Text)
+	}
+
+	// The first path is still reserved, so the next call gets another
+	handler(map[string]interface{}{"input": input})
+	if filepath.Base(got) != "interview_trimmed_002.mov" {
+		t.Errorf("second output = %q", got)
+	}
+
+	failing := s.withOutputPaths("trim_video", []string{"output"}, func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		got = arguments["output"].(string)
+		os.WriteFile(got, []byte("partial render"), 0644)
+		return mcp.NewToolResultError("ffmpeg failed"), nil
+	})
+	failing(map[string]interface{}{"input": input})
+	if _, err := os.Stat(got); !os.IsNotExist(err) {
+		t.Errorf("reserved path %s was not released after a failure", got)
+	}
+
+	explicit := filepath.Join(dir, "mine.mp4")
+	handler(map[string]interface{}{"input": input, "output": explicit})
+	if got != explicit {
+		t.Errorf("explicit output replaced with %q", got)
+	}
+}
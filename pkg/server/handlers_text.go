@@ -0,0 +1,712 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/elements"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/fonts"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerAddLowerThird registers the add_lower_third MCP tool
+func (s *MCPServer) registerAddLowerThird() {
+	s.addTool(mcp.Tool{
+		Name:        "add_lower_third",
+		Description: "Add an animated lower-third title (name and subtitle on a background plate with an accent color) in a single encode. Sized automatically for the video resolution.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Main line, usually a person's name",
+				},
+				"title": map[string]interface{}{
+					"type":        "string",
+					"description": "Second line, e.g. job title or location (optional)",
+				},
+				"template": map[string]interface{}{
+					"type":        "string",
+					"enum":        text.LowerThirdTemplates,
+					"description": "bar: plate with accent strip; slide: plate slides in with accent underline, then text fades in; minimal: text with accent rule, no plate (default: bar)",
+				},
+				"side": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"left", "right"},
+					"description": "Which side of the frame to place it on (default: left)",
+				},
+				"accentColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Accent color name or #RRGGBB (default: #E63946)",
+				},
+				"textColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Text color (default: white)",
+				},
+				"plateColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Background plate color (default: black)",
+				},
+				"plateOpacity": map[string]interface{}{
+					"type":        "number",
+					"description": "Background plate opacity 0-1 (default: 0.75)",
+				},
+				"fontFile": map[string]interface{}{
+					"type":        "string",
+					"description": "Font file path or installed font name, e.g. 'Open Sans Bold' (optional; see list_fonts)",
+				},
+				"startTime": map[string]interface{}{
+					"type":        "number",
+					"description": "When the lower third appears, in seconds (default: 0)",
+				},
+				"duration": map[string]interface{}{
+					"type":        "number",
+					"description": "How long it stays on screen including animations, in seconds (default: 5)",
+				},
+				"animation": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{text.LowerThirdAnimateSlide, text.LowerThirdAnimateFade, text.LowerThirdAnimateNone},
+					"description": "In and out animation (default: slide)",
+				},
+				"animationDuration": map[string]interface{}{
+					"type":        "number",
+					"description": "Length of the in and out animations in seconds (default: 0.5)",
+				},
+			},
+			Required: []string{"input", "output", "name"},
+		},
+	}, s.handleAddLowerThird)
+}
+
+func (s *MCPServer) handleAddLowerThird(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input             string  `json:"input"`
+		Output            string  `json:"output"`
+		Name              string  `json:"name"`
+		Title             string  `json:"title"`
+		Template          string  `json:"template"`
+		Side              string  `json:"side"`
+		AccentColor       string  `json:"accentColor"`
+		TextColor         string  `json:"textColor"`
+		PlateColor        string  `json:"plateColor"`
+		PlateOpacity      float64 `json:"plateOpacity"`
+		FontFile          string  `json:"fontFile"`
+		StartTime         float64 `json:"startTime"`
+		Duration          float64 `json:"duration"`
+		Animation         string  `json:"animation"`
+		AnimationDuration float64 `json:"animationDuration"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	err := s.textOps.AddLowerThird(context.Background(), text.LowerThirdOptions{
+		Input:             args.Input,
+		Output:            args.Output,
+		Name:              args.Name,
+		Title:             args.Title,
+		Template:          args.Template,
+		Side:              args.Side,
+		AccentColor:       args.AccentColor,
+		TextColor:         args.TextColor,
+		PlateColor:        args.PlateColor,
+		PlateOpacity:      args.PlateOpacity,
+		FontFile:          args.FontFile,
+		StartTime:         args.StartTime,
+		Duration:          args.Duration,
+		Animation:         args.Animation,
+		AnimationDuration: args.AnimationDuration,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to add lower third: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully added lower third to: %s", args.Output)), nil
+}
+
+// registerAddCountdown registers the add_countdown MCP tool
+func (s *MCPServer) registerAddCountdown() {
+	s.addTool(mcp.Tool{
+		Name:        "add_countdown",
+		Description: "Add a countdown timer overlay (MM:SS by default) that counts down to zero between startTime and endTime",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"startTime": map[string]interface{}{
+					"type":        "number",
+					"description": "When the countdown appears, in seconds (default: 0)",
+				},
+				"endTime": map[string]interface{}{
+					"type":        "number",
+					"description": "When the countdown reaches zero and disappears, in seconds",
+				},
+				"from": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds shown when the countdown appears (default: endTime - startTime)",
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{text.CountdownMinutes, text.CountdownHours, text.CountdownSeconds},
+					"description": "Display format (default: mm:ss)",
+				},
+				"position": map[string]interface{}{
+					"type":        "string",
+					"description": "Position: top-left, top-center, top-right, center, bottom-left, bottom-center, bottom-right (default: top-right)",
+				},
+				"fontSize": map[string]interface{}{
+					"type":        "number",
+					"description": "Font size (default: 64)",
+				},
+				"fontColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Font color (default: white)",
+				},
+				"fontFile": map[string]interface{}{
+					"type":        "string",
+					"description": "Font file path or installed font name, e.g. 'Open Sans Bold' (optional; see list_fonts)",
+				},
+				"box": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Draw a background box behind the timer (default: false)",
+				},
+				"boxColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Background box color (default: black)",
+				},
+			},
+			Required: []string{"input", "output", "endTime"},
+		},
+	}, s.handleAddCountdown)
+}
+
+func (s *MCPServer) handleAddCountdown(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input     string   `json:"input"`
+		Output    string   `json:"output"`
+		StartTime *float64 `json:"startTime"`
+		EndTime   *float64 `json:"endTime"`
+		From      *float64 `json:"from"`
+		Format    string   `json:"format"`
+		Position  string   `json:"position"`
+		FontSize  int      `json:"fontSize"`
+		FontColor string   `json:"fontColor"`
+		FontFile  string   `json:"fontFile"`
+		Box       bool     `json:"box"`
+		BoxColor  string   `json:"boxColor"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	err := s.textOps.AddCountdown(context.Background(), text.CountdownOptions{
+		TextOverlayOptions: text.TextOverlayOptions{
+			Input:     args.Input,
+			Output:    args.Output,
+			Position:  text.TextPosition(args.Position),
+			StartTime: args.StartTime,
+			EndTime:   args.EndTime,
+			FontFile:  args.FontFile,
+			FontSize:  args.FontSize,
+			FontColor: args.FontColor,
+			Box:       args.Box,
+			BoxColor:  args.BoxColor,
+		},
+		From:   args.From,
+		Format: args.Format,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to add countdown: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully added countdown to: %s", args.Output)), nil
+}
+
+// registerAddProgressBar registers the add_progress_bar MCP tool
+func (s *MCPServer) registerAddProgressBar() {
+	s.addTool(mcp.Tool{
+		Name:        "add_progress_bar",
+		Description: "Add an animated progress bar along an edge of the frame that fills from startTime to endTime (default: the whole video)",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"edge": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"top", "bottom", "left", "right"},
+					"description": "Edge to draw along; left and right bars fill upward (default: bottom)",
+				},
+				"color": map[string]interface{}{
+					"type":        "string",
+					"description": "Bar color name or #RRGGBB (default: white)",
+				},
+				"trackColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Color of the unfilled track (optional; no track when omitted)",
+				},
+				"trackOpacity": map[string]interface{}{
+					"type":        "number",
+					"description": "Track opacity 0-1 (default: 0.3)",
+				},
+				"thickness": map[string]interface{}{
+					"type":        "number",
+					"description": "Bar thickness in pixels (default: 8)",
+				},
+				"startTime": map[string]interface{}{
+					"type":        "number",
+					"description": "Time the bar starts filling, in seconds (default: 0)",
+				},
+				"endTime": map[string]interface{}{
+					"type":        "number",
+					"description": "Time the bar is full, in seconds (default: end of video)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleAddProgressBar)
+}
+
+func (s *MCPServer) handleAddProgressBar(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input        string   `json:"input"`
+		Output       string   `json:"output"`
+		Edge         string   `json:"edge"`
+		Color        string   `json:"color"`
+		TrackColor   string   `json:"trackColor"`
+		TrackOpacity float64  `json:"trackOpacity"`
+		Thickness    int      `json:"thickness"`
+		StartTime    *float64 `json:"startTime"`
+		EndTime      *float64 `json:"endTime"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	err := s.elements.AddProgressBar(context.Background(), elements.ProgressBarOptions{
+		Input:        args.Input,
+		Output:       args.Output,
+		Edge:         args.Edge,
+		Color:        args.Color,
+		TrackColor:   args.TrackColor,
+		TrackOpacity: args.TrackOpacity,
+		Thickness:    args.Thickness,
+		StartTime:    args.StartTime,
+		EndTime:      args.EndTime,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to add progress bar: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully added progress bar to: %s", args.Output)), nil
+}
+
+// registerListFonts registers the list_fonts MCP tool
+func (s *MCPServer) registerListFonts() {
+	s.addTool(mcp.Tool{
+		Name:        "list_fonts",
+		Description: "List installed fonts by family and style. Any listed name (e.g. 'Open Sans Bold') can be passed as fontFile to text tools instead of a path. Text with characters the chosen font lacks (CJK, emoji) automatically falls back to an installed font that has them.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"filter": map[string]interface{}{
+					"type":        "string",
+					"description": "Only list families containing this text (case-insensitive)",
+				},
+				"showPaths": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Include the file path of each style (default: false)",
+				},
+			},
+		},
+	}, s.handleListFonts)
+}
+
+func (s *MCPServer) handleListFonts(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Filter    string `json:"filter"`
+		ShowPaths bool   `json:"showPaths"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	all, err := fonts.List(context.Background())
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list fonts: %v", err)), nil
+	}
+
+	// Group styles by family, keeping the sorted order
+	var families []string
+	styles := map[string][]fonts.Info{}
+	for _, f := range all {
+		if args.Filter != "" && !strings.Contains(strings.ToLower(f.Family), strings.ToLower(args.Filter)) {
+			continue
+		}
+		if _, ok := styles[f.Family]; !ok {
+			families = append(families, f.Family)
+		}
+		styles[f.Family] = append(styles[f.Family], f)
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("FONTS: %d families\n", len(families)))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	for _, family := range families {
+		var names []string
+		for _, f := range styles[family] {
+			name := f.Style
+			if f.Bundled {
+				name += " (bundled)"
+			}
+			names = append(names, name)
+		}
+		if !args.ShowPaths {
+			out.WriteString(fmt.Sprintf("- %s: %s\n", family, strings.Join(names, ", ")))
+			continue
+		}
+		out.WriteString(fmt.Sprintf("- %s\n", family))
+		for i, f := range styles[family] {
+			out.WriteString(fmt.Sprintf("    %s: %s\n", names[i], f.Path))
+		}
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// registerApplyTemplate registers the apply_template MCP tool
+func (s *MCPServer) registerApplyTemplate() {
+	s.addTool(mcp.Tool{
+		Name: "apply_template",
+		Description: fmt.Sprintf("Stamp a titling template (layered text, rectangles and images with {variable} placeholders) onto a video in a single encode. "+
+			"Built-in templates: %s. Custom templates are JSON files with name, duration, canvas ([width, height], default 1920x1080), "+
+			"variables (defaults; null = required) and elements (type text/rect/image with x, y, align, size, width, height, color, font, path, opacity, start, end, fadeIn, fadeOut in canvas pixels and seconds).",
+			strings.Join(text.TitleTemplateNames(), ", ")),
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"template": map[string]interface{}{
+					"type":        "string",
+					"description": "Built-in template name or path to a template JSON file",
+				},
+				"variables": map[string]interface{}{
+					"type":        "object",
+					"description": "Values for the template's {variables}, e.g. {\"title\": \"Episode 4\", \"accent\": \"#FFB400\"}",
+				},
+				"startTime": map[string]interface{}{
+					"type":        "number",
+					"description": "When the template starts, in seconds (default: 0)",
+				},
+			},
+			Required: []string{"input", "output", "template"},
+		},
+	}, s.handleApplyTemplate)
+}
+
+func (s *MCPServer) handleApplyTemplate(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input     string                 `json:"input"`
+		Output    string                 `json:"output"`
+		Template  string                 `json:"template"`
+		Variables map[string]interface{} `json:"variables"`
+		StartTime float64                `json:"startTime"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	// Accept numbers and booleans as variable values
+	variables := map[string]string{}
+	for name, value := range args.Variables {
+		variables[name] = fmt.Sprint(value)
+	}
+
+	err := s.textOps.ApplyTemplate(context.Background(), text.ApplyTemplateOptions{
+		Input:     args.Input,
+		Output:    args.Output,
+		Template:  args.Template,
+		Variables: variables,
+		StartTime: args.StartTime,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to apply template: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully applied template %s to: %s", args.Template, args.Output)), nil
+}
+
+// registerCreateEndScreen registers the create_end_screen MCP tool
+func (s *MCPServer) registerCreateEndScreen() {
+	s.addTool(mcp.Tool{
+		Name:        "create_end_screen",
+		Description: "Compose an end screen over the last seconds of a video (or after it, holding the last frame): subscribe and next-video placeholders, a heading and an optional picture-in-picture of another video, laid out inside the platform's safe area. Returns placeholder positions for lining up the platform's own end-screen elements.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"platform": map[string]interface{}{
+					"type":        "string",
+					"enum":        text.EndScreenPlatforms,
+					"description": "Platform whose safe area to respect (default: youtube)",
+				},
+				"duration": map[string]interface{}{
+					"type":        "number",
+					"description": "End screen length in seconds (default: 10; YouTube allows 5-20)",
+				},
+				"append": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Add the end screen after the video over its held last frame instead of covering the last seconds (default: false)",
+				},
+				"heading": map[string]interface{}{
+					"type":        "string",
+					"description": "Heading text (default: Thanks for watching)",
+				},
+				"videoSlots": map[string]interface{}{
+					"type":        "number",
+					"description": "Number of next-video placeholders, 0-2 (default: 2)",
+				},
+				"subscribe": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Show the subscribe placeholder (default: true)",
+				},
+				"videoLabel": map[string]interface{}{
+					"type":        "string",
+					"description": "Caption under video placeholders (default: Watch next)",
+				},
+				"subscribeLabel": map[string]interface{}{
+					"type":        "string",
+					"description": "Caption under the subscribe placeholder (default: Subscribe)",
+				},
+				"pip": map[string]interface{}{
+					"type":        "string",
+					"description": "Video to play inside the first video placeholder (optional)",
+				},
+				"logo": map[string]interface{}{
+					"type":        "string",
+					"description": "Image shown inside the subscribe circle, e.g. the channel icon (optional)",
+				},
+				"accentColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Placeholder border and circle color (default: #E63946)",
+				},
+				"textColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Text color (default: white)",
+				},
+				"dim": map[string]interface{}{
+					"type":        "number",
+					"description": "Darkening of the video behind the end screen, 0-1 (default: 0.6)",
+				},
+				"fontFile": map[string]interface{}{
+					"type":        "string",
+					"description": "Font file path or installed font name (optional; see list_fonts)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleCreateEndScreen)
+}
+
+func (s *MCPServer) handleCreateEndScreen(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input          string   `json:"input"`
+		Output         string   `json:"output"`
+		Platform       string   `json:"platform"`
+		Duration       float64  `json:"duration"`
+		Append         bool     `json:"append"`
+		Heading        string   `json:"heading"`
+		VideoSlots     *int     `json:"videoSlots"`
+		Subscribe      *bool    `json:"subscribe"`
+		VideoLabel     string   `json:"videoLabel"`
+		SubscribeLabel string   `json:"subscribeLabel"`
+		PiP            string   `json:"pip"`
+		Logo           string   `json:"logo"`
+		AccentColor    string   `json:"accentColor"`
+		TextColor      string   `json:"textColor"`
+		Dim            *float64 `json:"dim"`
+		FontFile       string   `json:"fontFile"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.textOps.CreateEndScreen(context.Background(), text.EndScreenOptions{
+		Input:          args.Input,
+		Output:         args.Output,
+		Platform:       args.Platform,
+		Duration:       args.Duration,
+		Append:         args.Append,
+		Heading:        args.Heading,
+		VideoSlots:     args.VideoSlots,
+		Subscribe:      args.Subscribe,
+		VideoLabel:     args.VideoLabel,
+		SubscribeLabel: args.SubscribeLabel,
+		PiP:            args.PiP,
+		Logo:           args.Logo,
+		AccentColor:    args.AccentColor,
+		TextColor:      args.TextColor,
+		Dim:            args.Dim,
+		FontFile:       args.FontFile,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create end screen: %v", err)), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Successfully created end screen (%.1fs from %.2fs) in: %s\n", result.Duration, result.Start, args.Output))
+	sb.WriteString("\nPlaceholders (x, y, width x height):\n")
+	for _, slot := range result.Slots {
+		sb.WriteString(fmt.Sprintf("- %s: %d, %d, %dx%d\n", slot.Kind, slot.X, slot.Y, slot.Width, slot.Height))
+	}
+
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+// registerAddWatermark registers the add_watermark MCP tool
+func (s *MCPServer) registerAddWatermark() {
+	s.addTool(mcp.Tool{
+		Name:        "add_watermark",
+		Description: "Watermark a video with an image (e.g. a logo PNG). Size and margins are relative to the frame so the result looks the same at any resolution. Place it once in a corner, or repeat it in a tiled or diagonal grid to deter reuse.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"image": map[string]interface{}{
+					"type":        "string",
+					"description": "Watermark image path (PNG with transparency works best)",
+				},
+				"mode": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{elements.WatermarkSingle, elements.WatermarkTile, elements.WatermarkDiagonal},
+					"description": "single: one mark at position; tile: repeated grid; diagonal: repeated grid rotated across the frame (default: single)",
+				},
+				"position": map[string]interface{}{
+					"type":        "string",
+					"description": "Single mode position: top-left, top-center, top-right, center-left, center, center-right, bottom-left, bottom-center, bottom-right (default: bottom-right)",
+				},
+				"opacityPreset": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"subtle", "light", "medium", "strong"},
+					"description": "subtle 15%, light 30%, medium 50%, strong 80% (default: light)",
+				},
+				"opacity": map[string]interface{}{
+					"type":        "number",
+					"description": "Exact opacity 0-1, overrides opacityPreset",
+				},
+				"scale": map[string]interface{}{
+					"type":        "number",
+					"description": "Watermark width as a fraction of frame width (default: 0.15 single, 0.1 tiled)",
+				},
+				"margin": map[string]interface{}{
+					"type":        "number",
+					"description": "Single mode distance from the edges as a fraction of the shorter frame side (default: 0.03)",
+				},
+				"spacing": map[string]interface{}{
+					"type":        "number",
+					"description": "Tiled modes gap between marks as a fraction of their width (default: 1)",
+				},
+				"angle": map[string]interface{}{
+					"type":        "number",
+					"description": "Diagonal mode rotation in degrees (default: 30)",
+				},
+				"startTime": map[string]interface{}{
+					"type":        "number",
+					"description": "Start time in seconds (default: whole video)",
+				},
+				"duration": map[string]interface{}{
+					"type":        "number",
+					"description": "Duration in seconds",
+				},
+			},
+			Required: []string{"input", "output", "image"},
+		},
+	}, s.handleAddWatermark)
+}
+
+func (s *MCPServer) handleAddWatermark(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input         string   `json:"input"`
+		Output        string   `json:"output"`
+		Image         string   `json:"image"`
+		Mode          string   `json:"mode"`
+		Position      string   `json:"position"`
+		OpacityPreset string   `json:"opacityPreset"`
+		Opacity       *float64 `json:"opacity"`
+		Scale         float64  `json:"scale"`
+		Margin        float64  `json:"margin"`
+		Spacing       float64  `json:"spacing"`
+		Angle         float64  `json:"angle"`
+		StartTime     *float64 `json:"startTime"`
+		Duration      *float64 `json:"duration"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	err := s.elements.AddWatermark(context.Background(), elements.WatermarkOptions{
+		Input:         args.Input,
+		Output:        args.Output,
+		Image:         args.Image,
+		Mode:          args.Mode,
+		Position:      args.Position,
+		OpacityPreset: args.OpacityPreset,
+		Opacity:       args.Opacity,
+		Scale:         args.Scale,
+		Margin:        args.Margin,
+		Spacing:       args.Spacing,
+		Angle:         args.Angle,
+		StartTime:     args.StartTime,
+		Duration:      args.Duration,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to add watermark: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully added watermark to: %s", args.Output)), nil
+}
@@ -0,0 +1,307 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/vision"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/visual"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerTrackingTools registers the track_object and blur_region MCP tools
+func (s *MCPServer) registerTrackingTools() {
+	s.addTool(mcp.Tool{
+		Name:        "track_object",
+		Description: "Track an object described in plain language (e.g. 'the red car', 'laptop screen', 'license plate') across the video with the vision model. Instances found on sampled frames are linked into tracks with a continuous time range and a motion path interpolated between samples. Save the tracks to a JSON file and pass it as trackFile to blur_region, add_shape or reframe_video to blur, highlight or follow the object. Requires a vision provider.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "Object to track",
+				},
+				"interval": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds between sampled frames; lower follows fast movement more closely (default: 1)",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional JSON file to save the boxes and tracks to",
+				},
+			},
+			Required: []string{"input", "query"},
+		},
+	}, s.handleTrackObject)
+
+	s.addTool(mcp.Tool{
+		Name:        "blur_region",
+		Description: "Blur or black out part of the frame, such as a screen, license plate or logo. The region is a fixed box (fractions of the frame) for a time range, tracks from a track_object or detect_faces JSON file, or an object to track now with the vision model. Tracked regions move with the object between samples.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"x": map[string]interface{}{
+					"type":        "number",
+					"description": "Left edge of a fixed region as a fraction of the width (0-1)",
+				},
+				"y": map[string]interface{}{
+					"type":        "number",
+					"description": "Top edge of a fixed region as a fraction of the height (0-1)",
+				},
+				"width": map[string]interface{}{
+					"type":        "number",
+					"description": "Width of a fixed region as a fraction of the width (0-1)",
+				},
+				"height": map[string]interface{}{
+					"type":        "number",
+					"description": "Height of a fixed region as a fraction of the height (0-1)",
+				},
+				"startTime": map[string]interface{}{
+					"type":        "number",
+					"description": "When a fixed region starts in seconds (default: 0)",
+				},
+				"endTime": map[string]interface{}{
+					"type":        "number",
+					"description": "When a fixed region ends in seconds (default: end of video)",
+				},
+				"trackFile": map[string]interface{}{
+					"type":        "string",
+					"description": "JSON file saved by track_object or detect_faces",
+				},
+				"trackIds": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "number"},
+					"description": "Track IDs from trackFile to blur (default: all)",
+				},
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "Object to track and blur with the vision model",
+				},
+				"interval": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds between sampled frames when tracking query (default: 1)",
+				},
+				"mode": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{visual.RedactBlur, visual.RedactBlack},
+					"description": "blur or solid black boxes (default: blur)",
+				},
+				"strength": map[string]interface{}{
+					"type":        "number",
+					"description": "Blur strength 1-10 (default: 8)",
+				},
+				"padding": map[string]interface{}{
+					"type":        "number",
+					"description": "Extra area around each region as a fraction of its size (default: 0.2, or none when only a fixed region is given)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleBlurRegion)
+}
+
+func (s *MCPServer) handleTrackObject(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input    string  `json:"input"`
+		Query    string  `json:"query"`
+		Interval float64 `json:"interval"`
+		Output   string  `json:"output"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	detection, err := s.visionAnalyzer.TrackObject(context.Background(), args.Input, args.Query, args.Interval, nil)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to track object: %v", err)), nil
+	}
+	if args.Output != "" {
+		data, err := json.MarshalIndent(detection, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to encode tracks: %v", err)), nil
+		}
+		if err := os.WriteFile(args.Output, data, 0644); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to write tracks: %v", err)), nil
+		}
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("OBJECT TRACKING: \"%s\" in %s\n", detection.Query, args.Input))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Frames: %d (%d from cache)\n", len(detection.Frames), detection.CachedFrames))
+	out.WriteString(fmt.Sprintf("Tracks: %d\n\n", len(detection.Tracks)))
+
+	if len(detection.Tracks) == 0 {
+		out.WriteString("Not found in any sampled frame.\n")
+	}
+	for _, track := range detection.Tracks {
+		out.WriteString(fmt.Sprintf("%d. [%.2fs - %.2fs] %d keyframe(s)\n", track.ID, track.Start, track.End, len(track.Keyframes)))
+		for i, k := range track.Keyframes {
+			if i == 10 {
+				out.WriteString(fmt.Sprintf("   ... %d more\n", len(track.Keyframes)-i))
+				break
+			}
+			out.WriteString(fmt.Sprintf("   - %.2fs: x=%.2f y=%.2f w=%.2f h=%.2f\n", k.Time, k.X, k.Y, k.W, k.H))
+		}
+	}
+	if args.Output != "" {
+		out.WriteString(fmt.Sprintf("\nSaved to: %s (use as trackFile)\n", args.Output))
+	}
+	if detection.FailedFrames > 0 {
+		out.WriteString(fmt.Sprintf("\nWARNINGS:\n- %d frame(s) could not be analyzed\n", detection.FailedFrames))
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+func (s *MCPServer) handleBlurRegion(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input     string   `json:"input"`
+		Output    string   `json:"output"`
+		X         float64  `json:"x"`
+		Y         float64  `json:"y"`
+		Width     float64  `json:"width"`
+		Height    float64  `json:"height"`
+		StartTime float64  `json:"startTime"`
+		EndTime   float64  `json:"endTime"`
+		TrackFile string   `json:"trackFile"`
+		TrackIDs  []int    `json:"trackIds"`
+		Query     string   `json:"query"`
+		Interval  float64  `json:"interval"`
+		Mode      string   `json:"mode"`
+		Strength  float64  `json:"strength"`
+		Padding   *float64 `json:"padding"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	ctx := context.Background()
+	var regions []visual.MovingRegion
+	var sources []string
+	var warnings []string
+	if args.Width > 0 && args.Height > 0 {
+		end := args.EndTime
+		if end <= 0 {
+			info, err := s.videoOps.GetVideoInfo(ctx, args.Input)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to get video info: %v", err)), nil
+			}
+			end = info.Duration
+		}
+		regions = append(regions, visual.MovingRegion{
+			Start:     args.StartTime,
+			End:       end,
+			Keyframes: []visual.RegionKeyframe{{Time: args.StartTime, X: args.X, Y: args.Y, W: args.Width, H: args.Height}},
+		})
+		sources = append(sources, fmt.Sprintf("fixed region %.2fs - %.2fs", args.StartTime, end))
+	}
+	var tracks []vision.BoxTrack
+	if args.TrackFile != "" {
+		loaded, err := loadTracks(args.TrackFile, args.TrackIDs)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		tracks = append(tracks, loaded...)
+		sources = append(sources, fmt.Sprintf("%d track(s) from %s", len(loaded), args.TrackFile))
+	}
+	if args.Query != "" {
+		detection, err := s.visionAnalyzer.TrackObject(ctx, args.Input, args.Query, args.Interval, nil)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to track object: %v", err)), nil
+		}
+		tracks = append(tracks, detection.Tracks...)
+		sources = append(sources, fmt.Sprintf("%d track(s) of \"%s\"", len(detection.Tracks), detection.Query))
+		if detection.FailedFrames > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d frame(s) could not be analyzed; the object may not be covered there", detection.FailedFrames))
+		}
+	}
+	regions = append(regions, tracksToRegions(tracks, nil)...)
+	if len(sources) == 0 {
+		return mcp.NewToolResultError("Give a region (x, y, width, height), a trackFile or a query"), nil
+	}
+	if len(regions) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("Nothing to blur in %s (%s). No output written.", args.Input, strings.Join(sources, ", "))), nil
+	}
+
+	// Fixed regions are exact; tracked boxes get room for movement
+	padding := args.Padding
+	if padding == nil && len(tracks) == 0 {
+		padding = new(float64)
+	}
+	if err := s.visualFx.BlurRegions(ctx, visual.BlurRegionsOptions{
+		Input:    args.Input,
+		Output:   args.Output,
+		Regions:  regions,
+		Mode:     args.Mode,
+		Strength: args.Strength,
+		Padding:  padding,
+	}); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to blur region: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("REGION BLUR: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Output: %s\n", args.Output))
+	out.WriteString(fmt.Sprintf("Regions redacted: %d\n", len(regions)))
+	out.WriteString("\nSOURCES:\n")
+	for _, source := range sources {
+		out.WriteString(fmt.Sprintf("- %s\n", source))
+	}
+	if len(warnings) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range warnings {
+			out.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// loadTracks reads tracks saved by track_object or detect_faces, keeping
+// only the given IDs when there are any
+func loadTracks(path string, ids []int) ([]vision.BoxTrack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read track file: %w", err)
+	}
+	var saved vision.BoxDetectionResult
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse track file: %w", err)
+	}
+	if len(ids) == 0 {
+		return saved.Tracks, nil
+	}
+
+	byID := map[int]vision.BoxTrack{}
+	for _, track := range saved.Tracks {
+		byID[track.ID] = track
+	}
+	tracks := make([]vision.BoxTrack, 0, len(ids))
+	for _, id := range ids {
+		track, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("track %d not found in %s", id, path)
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks, nil
+}
@@ -0,0 +1,2093 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/visual"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerGenerateThumbnail registers the generate_thumbnail MCP tool
+func (s *MCPServer) registerGenerateThumbnail() {
+	s.addTool(mcp.Tool{
+		Name:        "generate_thumbnail",
+		Description: "Generate a thumbnail image from a video. Picks the most representative frame automatically, or uses an explicit timestamp.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output image file path (jpg, png, webp)",
+				},
+				"timestamp": map[string]interface{}{
+					"type":        "number",
+					"description": "Timestamp in seconds (optional, best frame is picked if omitted)",
+				},
+				"sampleFrames": map[string]interface{}{
+					"type":        "number",
+					"description": "Number of frames analyzed per batch when picking the best frame (default: 100)",
+				},
+				"width": map[string]interface{}{
+					"type":        "number",
+					"description": "Output width in pixels, height keeps aspect ratio (optional)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleGenerateThumbnail)
+}
+
+func (s *MCPServer) handleGenerateThumbnail(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input        string   `json:"input"`
+		Output       string   `json:"output"`
+		Timestamp    *float64 `json:"timestamp"`
+		SampleFrames *int     `json:"sampleFrames"`
+		Width        *int     `json:"width"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	opts := video.ThumbnailOptions{
+		Input:     args.Input,
+		Output:    args.Output,
+		Timestamp: args.Timestamp,
+	}
+	if args.SampleFrames != nil {
+		opts.SampleFrames = *args.SampleFrames
+	}
+	if args.Width != nil {
+		opts.Width = *args.Width
+	}
+
+	if err := s.videoOps.GenerateThumbnail(context.Background(), opts); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to generate thumbnail: %v", err)), nil
+	}
+
+	if args.Timestamp != nil {
+		return mcp.NewToolResultText(fmt.Sprintf("Successfully generated thumbnail at %.2fs: %s", *args.Timestamp, args.Output)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully generated thumbnail (best frame): %s", args.Output)), nil
+}
+
+// registerExportStill registers the export_still MCP tool
+func (s *MCPServer) registerExportStill() {
+	s.addTool(mcp.Tool{
+		Name:        "export_still",
+		Description: "Export the frame at an exact timestamp as a JPEG, PNG, WebP or TIFF image, for poster frames and thumbnails. Give the same filter chain or style preset as the video so the still matches it; time-based filters are rendered as they look at that moment.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output image file path (jpg, png, webp, tiff)",
+				},
+				"timestamp": map[string]interface{}{
+					"type":        "number",
+					"description": "Time of the frame in seconds",
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"jpg", "png", "webp", "tiff"},
+					"description": "Image format (default: the output's extension)",
+				},
+				"quality": map[string]interface{}{
+					"type":        "number",
+					"description": "Quality 1-100 for jpg and webp (default: 92); png and tiff are lossless",
+				},
+				"width": map[string]interface{}{
+					"type":        "number",
+					"description": "Output width in pixels, height keeps aspect ratio (optional)",
+				},
+				"filter": map[string]interface{}{
+					"type":        "string",
+					"description": "FFmpeg filter chain to apply, e.g. the one used on the video (optional)",
+				},
+				"stylePreset": map[string]interface{}{
+					"type":        "string",
+					"enum":        visual.StylePresetNames(),
+					"description": "Style preset look to apply, as with apply_style_preset, before the filter (optional)",
+				},
+			},
+			Required: []string{"input", "output", "timestamp"},
+		},
+	}, s.handleExportStill)
+}
+
+func (s *MCPServer) handleExportStill(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input       string  `json:"input"`
+		Output      string  `json:"output"`
+		Timestamp   float64 `json:"timestamp"`
+		Format      string  `json:"format"`
+		Quality     int     `json:"quality"`
+		Width       int     `json:"width"`
+		Filter      string  `json:"filter"`
+		StylePreset string  `json:"stylePreset"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	var filters []string
+	if args.StylePreset != "" {
+		preset, ok := visual.StylePresets[args.StylePreset]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("Unknown style preset %q (use %s)", args.StylePreset, strings.Join(visual.StylePresetNames(), ", "))), nil
+		}
+		filters = append(filters, preset.Filter)
+	}
+	if args.Filter != "" {
+		filters = append(filters, args.Filter)
+	}
+
+	opts := video.StillOptions{
+		Input:     args.Input,
+		Output:    args.Output,
+		Timestamp: args.Timestamp,
+		Format:    args.Format,
+		Quality:   args.Quality,
+		Width:     args.Width,
+		Filter:    strings.Join(filters, ","),
+	}
+	if err := s.videoOps.ExportStill(context.Background(), opts); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to export still: %v", err)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully exported still at %.3fs: %s", args.Timestamp, args.Output)), nil
+}
+
+// registerGenerateContactSheet registers the generate_contact_sheet MCP tool
+func (s *MCPServer) registerGenerateContactSheet() {
+	s.addTool(mcp.Tool{
+		Name:        "generate_contact_sheet",
+		Description: "Tile evenly spaced frames from a video into a single image with timecodes for quick review",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output image file path (jpg, png)",
+				},
+				"columns": map[string]interface{}{
+					"type":        "number",
+					"description": "Number of tiles per row (default: 4)",
+				},
+				"rows": map[string]interface{}{
+					"type":        "number",
+					"description": "Number of rows (default: 4)",
+				},
+				"tileWidth": map[string]interface{}{
+					"type":        "number",
+					"description": "Width of each tile in pixels (default: 320)",
+				},
+				"showTimecodes": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Draw the timestamp on each tile (default: true)",
+				},
+				"fontColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Timecode color (default: white)",
+				},
+				"background": map[string]interface{}{
+					"type":        "string",
+					"description": "Sheet background color (default: black)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleGenerateContactSheet)
+}
+
+func (s *MCPServer) handleGenerateContactSheet(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input         string `json:"input"`
+		Output        string `json:"output"`
+		Columns       *int   `json:"columns"`
+		Rows          *int   `json:"rows"`
+		TileWidth     *int   `json:"tileWidth"`
+		ShowTimecodes *bool  `json:"showTimecodes"`
+		FontColor     string `json:"fontColor"`
+		Background    string `json:"background"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	opts := video.ContactSheetOptions{
+		Input:         args.Input,
+		Output:        args.Output,
+		ShowTimecodes: true,
+		FontColor:     args.FontColor,
+		Background:    args.Background,
+	}
+	if args.Columns != nil {
+		opts.Columns = *args.Columns
+	}
+	if args.Rows != nil {
+		opts.Rows = *args.Rows
+	}
+	if args.TileWidth != nil {
+		opts.TileWidth = *args.TileWidth
+	}
+	if args.ShowTimecodes != nil {
+		opts.ShowTimecodes = *args.ShowTimecodes
+	}
+
+	sheet, err := s.videoOps.GenerateContactSheet(context.Background(), opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to generate contact sheet: %v", err)), nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Successfully generated contact sheet: %s\n", sheet.Output))
+	result.WriteString(fmt.Sprintf("Frames: %d (one every %.2fs)\n", sheet.FrameCount, sheet.Interval))
+	result.WriteString("Timestamps: ")
+	stamps := make([]string, len(sheet.Timestamps))
+	for i, ts := range sheet.Timestamps {
+		stamps[i] = fmt.Sprintf("%.2fs", ts)
+	}
+	result.WriteString(strings.Join(stamps, ", "))
+
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+// registerSmartSpeed registers the smart_speed MCP tool
+func (s *MCPServer) registerSmartSpeed() {
+	s.addTool(mcp.Tool{
+		Name:        "smart_speed",
+		Description: "Speed up silent (and optionally static) portions of a video more aggressively than speech. Keeps voices at natural pitch - ideal for shortening lectures and meetings.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"speechSpeed": map[string]interface{}{
+					"type":        "number",
+					"description": "Speed multiplier for speech portions (default: 1.0)",
+				},
+				"silenceSpeed": map[string]interface{}{
+					"type":        "number",
+					"description": "Speed multiplier for silent portions (default: 4.0)",
+				},
+				"silenceThreshold": map[string]interface{}{
+					"type":        "number",
+					"description": "Audio level in dB below which audio counts as silence (default: -35)",
+				},
+				"minSilenceDuration": map[string]interface{}{
+					"type":        "number",
+					"description": "Minimum silence length in seconds to speed up (default: 0.5)",
+				},
+				"padding": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds kept at speech speed around each silence (default: 0.1)",
+				},
+				"includeStatic": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Also speed up ranges where the picture is frozen/static (default: false)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleSmartSpeed)
+}
+
+func (s *MCPServer) handleSmartSpeed(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input              string   `json:"input"`
+		Output             string   `json:"output"`
+		SpeechSpeed        *float64 `json:"speechSpeed"`
+		SilenceSpeed       *float64 `json:"silenceSpeed"`
+		SilenceThreshold   *float64 `json:"silenceThreshold"`
+		MinSilenceDuration *float64 `json:"minSilenceDuration"`
+		Padding            *float64 `json:"padding"`
+		IncludeStatic      bool     `json:"includeStatic"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	opts := video.SmartSpeedOptions{
+		Input:         args.Input,
+		Output:        args.Output,
+		IncludeStatic: args.IncludeStatic,
+	}
+	if args.SpeechSpeed != nil {
+		opts.SpeechSpeed = *args.SpeechSpeed
+	}
+	if args.SilenceSpeed != nil {
+		opts.SilenceSpeed = *args.SilenceSpeed
+	}
+	if args.SilenceThreshold != nil {
+		opts.SilenceThreshold = *args.SilenceThreshold
+	}
+	if args.MinSilenceDuration != nil {
+		opts.MinSilenceDuration = *args.MinSilenceDuration
+	}
+	if args.Padding != nil {
+		opts.Padding = *args.Padding
+	}
+
+	result, err := s.videoOps.SmartSpeed(context.Background(), opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to apply smart speed: %v", err)), nil
+	}
+
+	saved := 0.0
+	if result.OriginalDuration > 0 {
+		saved = result.TimeSaved / result.OriginalDuration * 100
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf(`Successfully applied smart speed: %s
+- Original duration: %.2fs
+- New duration: %.2fs
+- Time saved: %.2fs (%.0f%%)
+- Idle ranges sped up: %d`,
+		args.Output,
+		result.OriginalDuration,
+		result.NewDuration,
+		result.TimeSaved,
+		saved,
+		len(result.IdleRanges),
+	)), nil
+}
+
+// registerPrepareScreenRecording registers the prepare_screen_recording MCP tool
+func (s *MCPServer) registerPrepareScreenRecording() {
+	s.addTool(mcp.Tool{
+		Name:        "prepare_screen_recording",
+		Description: "Clean up raw screen recording footage for a tutorial in one pass: convert variable frame rate to constant, crop to a window, downscale a retina capture, highlight the cursor from a recorder's cursor log, and trim dead air and long pauses.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input screen recording file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"fps": map[string]interface{}{
+					"type":        "number",
+					"description": "Constant output frame rate (default: 30)",
+				},
+				"crop": map[string]interface{}{
+					"type":        "object",
+					"description": "Window rectangle to keep, in recording pixels",
+					"properties": map[string]interface{}{
+						"x":      map[string]interface{}{"type": "number"},
+						"y":      map[string]interface{}{"type": "number"},
+						"width":  map[string]interface{}{"type": "number"},
+						"height": map[string]interface{}{"type": "number"},
+					},
+					"required": []string{"x", "y", "width", "height"},
+				},
+				"retina": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Halve the resolution of a 2x (HiDPI/retina) capture (default: false)",
+				},
+				"maxHeight": map[string]interface{}{
+					"type":        "number",
+					"description": "Downscale to at most this height, e.g. 1080 (default: keep)",
+				},
+				"cursor": map[string]interface{}{
+					"type":        "array",
+					"description": "Cursor path to highlight, in recording pixels, as logged by the screen recorder",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"time": map[string]interface{}{"type": "number", "description": "Time in seconds"},
+							"x":    map[string]interface{}{"type": "number"},
+							"y":    map[string]interface{}{"type": "number"},
+						},
+						"required": []string{"time", "x", "y"},
+					},
+				},
+				"cursorSize": map[string]interface{}{
+					"type":        "number",
+					"description": "Cursor highlight diameter in recording pixels (default: 48)",
+				},
+				"trimSilence": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Cut dead air at the start and end (default: false)",
+				},
+				"maxPause": map[string]interface{}{
+					"type":        "number",
+					"description": "Shorten silent pauses longer than this many seconds to this length (default: keep pauses)",
+				},
+				"silenceThreshold": map[string]interface{}{
+					"type":        "number",
+					"description": "Audio level in dB below which audio counts as silence (default: -40)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handlePrepareScreenRecording)
+}
+
+func (s *MCPServer) handlePrepareScreenRecording(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input            string              `json:"input"`
+		Output           string              `json:"output"`
+		FPS              int                 `json:"fps"`
+		Crop             *video.CropRect     `json:"crop"`
+		Retina           bool                `json:"retina"`
+		MaxHeight        int                 `json:"maxHeight"`
+		Cursor           []video.CursorPoint `json:"cursor"`
+		CursorSize       int                 `json:"cursorSize"`
+		TrimSilence      bool                `json:"trimSilence"`
+		MaxPause         float64             `json:"maxPause"`
+		SilenceThreshold float64             `json:"silenceThreshold"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.videoOps.PrepareScreenRecording(context.Background(), video.ScreenRecordingOptions{
+		Input:            args.Input,
+		Output:           args.Output,
+		FPS:              args.FPS,
+		Crop:             args.Crop,
+		Retina:           args.Retina,
+		MaxHeight:        args.MaxHeight,
+		Cursor:           args.Cursor,
+		CursorSize:       args.CursorSize,
+		TrimSilence:      args.TrimSilence,
+		MaxPause:         args.MaxPause,
+		SilenceThreshold: args.SilenceThreshold,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to prepare screen recording: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf(`Successfully prepared screen recording: %s
+- Original duration: %.2fs
+- New duration: %.2fs
+- Silences cut: %d`,
+		args.Output,
+		result.OriginalDuration,
+		result.NewDuration,
+		len(result.Removed),
+	)), nil
+}
+
+// registerGenerateSpriteSheet registers the generate_sprite_sheet MCP tool
+func (s *MCPServer) registerGenerateSpriteSheet() {
+	s.addTool(mcp.Tool{
+		Name:        "generate_sprite_sheet",
+		Description: "Generate a thumbnail sprite sheet and matching WebVTT thumbnails track for video player hover previews",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Sprite image path (jpg, png). Numbered automatically when multiple sheets are needed",
+				},
+				"vttOutput": map[string]interface{}{
+					"type":        "string",
+					"description": "WebVTT output path (default: sprite path with .vtt extension)",
+				},
+				"interval": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds between thumbnails (default: 5)",
+				},
+				"tileWidth": map[string]interface{}{
+					"type":        "number",
+					"description": "Thumbnail width in pixels (default: 160)",
+				},
+				"columns": map[string]interface{}{
+					"type":        "number",
+					"description": "Thumbnails per row (default: 10)",
+				},
+				"maxRows": map[string]interface{}{
+					"type":        "number",
+					"description": "Rows per sheet before starting a new sheet (default: 10)",
+				},
+				"urlPrefix": map[string]interface{}{
+					"type":        "string",
+					"description": "Prefix for sprite URLs in the VTT file, e.g. 'https://cdn.example.com/thumbs/' (default: relative file name)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleGenerateSpriteSheet)
+}
+
+func (s *MCPServer) handleGenerateSpriteSheet(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input     string   `json:"input"`
+		Output    string   `json:"output"`
+		VTTOutput string   `json:"vttOutput"`
+		Interval  *float64 `json:"interval"`
+		TileWidth *int     `json:"tileWidth"`
+		Columns   *int     `json:"columns"`
+		MaxRows   *int     `json:"maxRows"`
+		URLPrefix string   `json:"urlPrefix"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	opts := video.SpriteSheetOptions{
+		Input:     args.Input,
+		Output:    args.Output,
+		VTTOutput: args.VTTOutput,
+		URLPrefix: args.URLPrefix,
+	}
+	if args.Interval != nil {
+		opts.Interval = *args.Interval
+	}
+	if args.TileWidth != nil {
+		opts.TileWidth = *args.TileWidth
+	}
+	if args.Columns != nil {
+		opts.Columns = *args.Columns
+	}
+	if args.MaxRows != nil {
+		opts.MaxRows = *args.MaxRows
+	}
+
+	result, err := s.videoOps.GenerateSpriteSheet(context.Background(), opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to generate sprite sheet: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf(`Successfully generated sprite sheet:
+- Sheets: %s
+- WebVTT track: %s
+- Thumbnails: %d (%dx%d each)`,
+		strings.Join(result.Sheets, ", "),
+		result.VTTFile,
+		result.FrameCount,
+		result.TileWidth,
+		result.TileHeight,
+	)), nil
+}
+
+// registerCompareQuality registers the compare_quality MCP tool
+func (s *MCPServer) registerCompareQuality() {
+	s.addTool(mcp.Tool{
+		Name:        "compare_quality",
+		Description: "Measure encode quality against the source with VMAF, SSIM and PSNR. Returns overall and per-segment scores to tune CRF/profile choices objectively. VMAF requires FFmpeg built with libvmaf.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"reference": map[string]interface{}{
+					"type":        "string",
+					"description": "Source (original) video path",
+				},
+				"distorted": map[string]interface{}{
+					"type":        "string",
+					"description": "Encoded video path to score (scaled to reference size if needed)",
+				},
+				"metrics": map[string]interface{}{
+					"type":        "array",
+					"description": "Metrics to compute (default: vmaf, ssim, psnr)",
+					"items": map[string]interface{}{
+						"type": "string",
+						"enum": []string{"vmaf", "ssim", "psnr"},
+					},
+				},
+				"segmentDuration": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds per reported segment (default: 10)",
+				},
+				"vmafModel": map[string]interface{}{
+					"type":        "string",
+					"description": "libvmaf model option, e.g. 'version=vmaf_4k_v0.6.1' (optional)",
+				},
+				"outputJson": map[string]interface{}{
+					"type":        "string",
+					"description": "Path to save the full per-segment results as JSON (optional)",
+				},
+			},
+			Required: []string{"reference", "distorted"},
+		},
+	}, s.handleCompareQuality)
+}
+
+func (s *MCPServer) handleCompareQuality(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Reference       string   `json:"reference"`
+		Distorted       string   `json:"distorted"`
+		Metrics         []string `json:"metrics"`
+		SegmentDuration float64  `json:"segmentDuration"`
+		VMAFModel       string   `json:"vmafModel"`
+		OutputJSON      string   `json:"outputJson"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.videoOps.CompareQuality(context.Background(), video.CompareQualityOptions{
+		Reference:       args.Reference,
+		Distorted:       args.Distorted,
+		Metrics:         args.Metrics,
+		SegmentDuration: args.SegmentDuration,
+		VMAFModel:       args.VMAFModel,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to compare quality: %v", err)), nil
+	}
+
+	if args.OutputJSON != "" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to encode results: %v", err)), nil
+		}
+		if err := os.WriteFile(args.OutputJSON, data, 0644); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to write results: %v", err)), nil
+		}
+	}
+
+	// Report metrics in a stable order
+	var metrics []string
+	for _, m := range []string{video.MetricVMAF, video.MetricSSIM, video.MetricPSNR} {
+		if _, ok := result.Metrics[m]; ok {
+			metrics = append(metrics, m)
+		}
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("QUALITY: %s vs %s (%d frames)\n\n", args.Distorted, args.Reference, result.FrameCount))
+	out.WriteString("OVERALL:\n")
+	for _, m := range metrics {
+		summary := result.Metrics[m]
+		out.WriteString(fmt.Sprintf("- %s: mean %.3f, min %.3f, max %.3f\n", strings.ToUpper(m), summary.Mean, summary.Min, summary.Max))
+	}
+
+	out.WriteString("\nSEGMENTS (mean / min):\n")
+	for _, seg := range result.Segments {
+		var scores []string
+		for _, m := range metrics {
+			if summary, ok := seg.Metrics[m]; ok {
+				scores = append(scores, fmt.Sprintf("%s %.2f / %.2f", strings.ToUpper(m), summary.Mean, summary.Min))
+			}
+		}
+		out.WriteString(fmt.Sprintf("- %.1fs-%.1fs: %s\n", seg.Start, seg.End, strings.Join(scores, ", ")))
+	}
+
+	if args.OutputJSON != "" {
+		out.WriteString(fmt.Sprintf("\nFull results saved to: %s\n", args.OutputJSON))
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// registerAnalyzeBitrate registers the analyze_bitrate MCP tool
+func (s *MCPServer) registerAnalyzeBitrate() {
+	s.addTool(mcp.Tool{
+		Name:        "analyze_bitrate",
+		Description: "Analyze per-second bitrate, GOP sizes and keyframe positions from packet data. Useful for diagnosing playback stutter and concatenation/stream-copy cut issues. Optionally renders a bitrate chart image.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"stream": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"video", "audio"},
+					"description": "Stream to analyze (default: video)",
+				},
+				"chartOutput": map[string]interface{}{
+					"type":        "string",
+					"description": "Path to save a PNG bitrate chart with keyframe markers (optional)",
+				},
+				"outputJson": map[string]interface{}{
+					"type":        "string",
+					"description": "Path to save the full analysis as JSON (optional)",
+				},
+			},
+			Required: []string{"input"},
+		},
+	}, s.handleAnalyzeBitrate)
+}
+
+func (s *MCPServer) handleAnalyzeBitrate(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input       string `json:"input"`
+		Stream      string `json:"stream"`
+		ChartOutput string `json:"chartOutput"`
+		OutputJSON  string `json:"outputJson"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	analysis, err := s.videoOps.AnalyzeBitrate(context.Background(), video.BitrateOptions{
+		Input:       args.Input,
+		Stream:      args.Stream,
+		ChartOutput: args.ChartOutput,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to analyze bitrate: %v", err)), nil
+	}
+
+	if args.OutputJSON != "" {
+		data, err := json.MarshalIndent(analysis, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to encode analysis: %v", err)), nil
+		}
+		if err := os.WriteFile(args.OutputJSON, data, 0644); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to write analysis: %v", err)), nil
+		}
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("BITRATE ANALYSIS: %s (%s stream)\n\n", args.Input, analysis.Stream))
+	out.WriteString(fmt.Sprintf("Packets: %d over %.2fs\n", analysis.PacketCount, analysis.Duration))
+	out.WriteString(fmt.Sprintf("Bitrate: avg %.0f kbps, min %.0f kbps, peak %.0f kbps\n", analysis.AverageKbps, analysis.MinKbps, analysis.PeakKbps))
+	out.WriteString(fmt.Sprintf("Keyframes: %d\n", len(analysis.Keyframes)))
+	out.WriteString(fmt.Sprintf("GOP: avg %.1f frames, max %d frames, longest %.2fs\n", analysis.AverageGOPSize, analysis.MaxGOPSize, analysis.MaxGOPDuration))
+
+	if len(analysis.Keyframes) > 0 {
+		shown := analysis.Keyframes
+		if len(shown) > 20 {
+			shown = shown[:20]
+		}
+		positions := make([]string, len(shown))
+		for i, kf := range shown {
+			positions[i] = fmt.Sprintf("%.2f", kf)
+		}
+		out.WriteString(fmt.Sprintf("Keyframe positions (s): %s", strings.Join(positions, ", ")))
+		if len(analysis.Keyframes) > len(shown) {
+			out.WriteString(fmt.Sprintf(" ... (+%d more)", len(analysis.Keyframes)-len(shown)))
+		}
+		out.WriteString("\n")
+	}
+
+	if len(analysis.Warnings) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range analysis.Warnings {
+			out.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	if analysis.ChartFile != "" {
+		out.WriteString(fmt.Sprintf("\nChart saved to: %s\n", analysis.ChartFile))
+	}
+	if args.OutputJSON != "" {
+		out.WriteString(fmt.Sprintf("Full analysis saved to: %s\n", args.OutputJSON))
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// registerExportMultiAspect registers the export_multi_aspect MCP tool
+func (s *MCPServer) registerExportMultiAspect() {
+	s.addTool(mcp.Tool{
+		Name:        "export_multi_aspect",
+		Description: "Render 16:9, 9:16 and optionally 1:1 versions of a video in a single job (one decode). Narrower outputs are cropped with auto-reframe to follow the subject; wider outputs get a blurred fill.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"outputDir": map[string]interface{}{
+					"type":        "string",
+					"description": "Directory for the rendered versions",
+				},
+				"baseName": map[string]interface{}{
+					"type":        "string",
+					"description": "Output file name prefix (default: input file name)",
+				},
+				"aspects": map[string]interface{}{
+					"type":        "array",
+					"description": "Aspect ratios to render (default: 16:9 and 9:16)",
+					"items": map[string]interface{}{
+						"type": "string",
+						"enum": []string{"16:9", "9:16", "1:1"},
+					},
+				},
+				"quality": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"high", "medium", "low"},
+					"description": "Encode quality (default: medium)",
+				},
+				"autoReframe": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Follow the subject when cropping instead of a center crop (default: true)",
+				},
+			},
+			Required: []string{"input", "outputDir"},
+		},
+	}, s.handleExportMultiAspect)
+}
+
+func (s *MCPServer) handleExportMultiAspect(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input       string   `json:"input"`
+		OutputDir   string   `json:"outputDir"`
+		BaseName    string   `json:"baseName"`
+		Aspects     []string `json:"aspects"`
+		Quality     string   `json:"quality"`
+		AutoReframe *bool    `json:"autoReframe"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	opts := video.MultiAspectOptions{
+		Input:       args.Input,
+		OutputDir:   args.OutputDir,
+		BaseName:    args.BaseName,
+		Aspects:     args.Aspects,
+		Quality:     args.Quality,
+		AutoReframe: true,
+	}
+	if args.AutoReframe != nil {
+		opts.AutoReframe = *args.AutoReframe
+	}
+
+	outputs, err := s.videoOps.ExportMultiAspect(context.Background(), opts)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to export multi-aspect versions: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("Successfully exported %d versions of %s:\n", len(outputs), args.Input))
+	for _, o := range outputs {
+		line := fmt.Sprintf("- %s (%dx%d, %s): %s", o.Aspect, o.Width, o.Height, o.Method, o.Path)
+		if o.Keyframes > 0 {
+			line += fmt.Sprintf(" [%d camera keyframes]", o.Keyframes)
+		}
+		out.WriteString(line + "\n")
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// registerFindDuplicateMedia registers the find_duplicate_media MCP tool
+func (s *MCPServer) registerFindDuplicateMedia() {
+	s.addTool(mcp.Tool{
+		Name:        "find_duplicate_media",
+		Description: "Scan a directory for duplicate and near-duplicate media using duration, stream hashes and perceptual hashes of sampled frames. Reports groups with a suggested file to keep and reclaimable space; never deletes anything.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"directory": map[string]interface{}{
+					"type":        "string",
+					"description": "Directory to scan",
+				},
+				"recursive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Scan subdirectories (default: false)",
+				},
+				"threshold": map[string]interface{}{
+					"type":        "number",
+					"description": "Minimum visual similarity 0-1 for near-duplicates (default: 0.9)",
+				},
+				"durationTolerance": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum duration difference in seconds between duplicates (default: 1.0)",
+				},
+			},
+			Required: []string{"directory"},
+		},
+	}, s.handleFindDuplicateMedia)
+}
+
+func (s *MCPServer) handleFindDuplicateMedia(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Directory         string  `json:"directory"`
+		Recursive         bool    `json:"recursive"`
+		Threshold         float64 `json:"threshold"`
+		DurationTolerance float64 `json:"durationTolerance"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	report, err := s.videoOps.FindDuplicateMedia(context.Background(), video.DuplicateOptions{
+		Directory:         args.Directory,
+		Recursive:         args.Recursive,
+		Threshold:         args.Threshold,
+		DurationTolerance: args.DurationTolerance,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to find duplicates: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("DUPLICATE SCAN: %s\n", report.Directory))
+	out.WriteString(fmt.Sprintf("Files scanned: %d\n", report.FilesScanned))
+	out.WriteString(fmt.Sprintf("Duplicate groups: %d\n", len(report.Groups)))
+	out.WriteString(fmt.Sprintf("Reclaimable space: %.1f MB\n", float64(report.ReclaimableBytes)/(1024*1024)))
+
+	for i, group := range report.Groups {
+		out.WriteString(fmt.Sprintf("\nGroup %d (%s, %.0f%% similar, %.1f MB reclaimable):\n",
+			i+1, group.Kind, group.Similarity*100, float64(group.Reclaimable)/(1024*1024)))
+		for _, f := range group.Files {
+			marker := "  "
+			if f.Path == group.Keep {
+				marker = "* "
+			}
+			resolution := ""
+			if f.Width > 0 {
+				resolution = fmt.Sprintf(", %dx%d", f.Width, f.Height)
+			}
+			out.WriteString(fmt.Sprintf("%s%s (%.2fs%s, %.1f MB)\n", marker, f.Path, f.Duration, resolution, float64(f.Size)/(1024*1024)))
+		}
+	}
+	if len(report.Groups) > 0 {
+		out.WriteString("\n* = suggested file to keep (highest resolution/bitrate)\n")
+	}
+
+	if len(report.Errors) > 0 {
+		out.WriteString("\nSKIPPED FILES:\n")
+		for _, e := range report.Errors {
+			out.WriteString(fmt.Sprintf("- %s\n", e))
+		}
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// registerValidateMedia registers the validate_media MCP tool
+func (s *MCPServer) registerValidateMedia() {
+	s.addTool(mcp.Tool{
+		Name:        "validate_media",
+		Description: "Quality-check a file before publishing: detects black frames, frozen frames, decode errors/corruption and missing audio in one decoding pass, and returns a pass/fail report with timestamps.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Path to the media file",
+				},
+				"blackMinDuration": map[string]interface{}{
+					"type":        "number",
+					"description": "Minimum black segment length in seconds (default: 0.5)",
+				},
+				"blackPixelThreshold": map[string]interface{}{
+					"type":        "number",
+					"description": "Luminance 0-1 below which a pixel counts as black (default: 0.10)",
+				},
+				"freezeMinDuration": map[string]interface{}{
+					"type":        "number",
+					"description": "Minimum frozen segment length in seconds (default: 2.0)",
+				},
+				"freezeNoise": map[string]interface{}{
+					"type":        "number",
+					"description": "Freeze detection noise tolerance in dB (default: -60)",
+				},
+				"maxDecodeErrors": map[string]interface{}{
+					"type":        "number",
+					"description": "Decode errors tolerated before failing (default: 0)",
+				},
+				"requireAudio": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Fail videos without an audio track (default: true)",
+				},
+			},
+			Required: []string{"input"},
+		},
+	}, s.handleValidateMedia)
+}
+
+func (s *MCPServer) handleValidateMedia(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input               string  `json:"input"`
+		BlackMinDuration    float64 `json:"blackMinDuration"`
+		BlackPixelThreshold float64 `json:"blackPixelThreshold"`
+		FreezeMinDuration   float64 `json:"freezeMinDuration"`
+		FreezeNoise         float64 `json:"freezeNoise"`
+		MaxDecodeErrors     int     `json:"maxDecodeErrors"`
+		RequireAudio        *bool   `json:"requireAudio"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	report, err := s.videoOps.ValidateMedia(context.Background(), video.ValidateOptions{
+		Input:               args.Input,
+		BlackMinDuration:    args.BlackMinDuration,
+		BlackPixelThreshold: args.BlackPixelThreshold,
+		FreezeMinDuration:   args.FreezeMinDuration,
+		FreezeNoiseDB:       args.FreezeNoise,
+		MaxDecodeErrors:     args.MaxDecodeErrors,
+		AllowMissingAudio:   args.RequireAudio != nil && !*args.RequireAudio,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to validate media: %v", err)), nil
+	}
+
+	result := "PASS"
+	if !report.Passed {
+		result = "FAIL"
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("VALIDATION: %s\n", result))
+	out.WriteString(fmt.Sprintf("File: %s\n", report.Input))
+	out.WriteString(fmt.Sprintf("Duration: %.2fs\n", report.Duration))
+	out.WriteString(fmt.Sprintf("Video: %t, Audio: %t\n", report.HasVideo, report.HasAudio))
+	out.WriteString(fmt.Sprintf("Black segments: %d, Frozen segments: %d, Decode errors: %d\n",
+		len(report.BlackSegments), len(report.FrozenSegments), report.DecodeErrors))
+
+	if len(report.Issues) > 0 {
+		out.WriteString("\nISSUES:\n")
+		for _, issue := range report.Issues {
+			if issue.End > 0 {
+				out.WriteString(fmt.Sprintf("- [%s] %.2fs - %.2fs: %s\n", issue.Type, issue.Start, issue.End, issue.Message))
+			} else {
+				out.WriteString(fmt.Sprintf("- [%s] %s\n", issue.Type, issue.Message))
+			}
+		}
+	}
+
+	if len(report.ErrorSamples) > 0 {
+		out.WriteString("\nDECODER MESSAGES:\n")
+		for _, line := range report.ErrorSamples {
+			out.WriteString(fmt.Sprintf("  %s\n", line))
+		}
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// registerMuteVideo registers the mute_video MCP tool
+func (s *MCPServer) registerMuteVideo() {
+	s.addTool(mcp.Tool{
+		Name:        "mute_video",
+		Description: "Remove all audio tracks from a video without re-encoding",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleMuteVideo)
+}
+
+func (s *MCPServer) handleMuteVideo(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input  string `json:"input"`
+		Output string `json:"output"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	if err := s.videoOps.MuteVideo(context.Background(), args.Input, args.Output); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to mute video: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully removed audio: %s", args.Output)), nil
+}
+
+// registerReplaceAudio registers the replace_audio MCP tool
+func (s *MCPServer) registerReplaceAudio() {
+	s.addTool(mcp.Tool{
+		Name:        "replace_audio",
+		Description: "Replace a video's audio with a new audio file. Video is copied without re-encoding.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"audio": map[string]interface{}{
+					"type":        "string",
+					"description": "New audio file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"offset": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds to delay the new audio; negative values skip into the audio (default: 0)",
+				},
+				"shortest": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Trim the output to the shorter of the video and the audio (default: false)",
+				},
+			},
+			Required: []string{"input", "audio", "output"},
+		},
+	}, s.handleReplaceAudio)
+}
+
+func (s *MCPServer) handleReplaceAudio(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input    string  `json:"input"`
+		Audio    string  `json:"audio"`
+		Output   string  `json:"output"`
+		Offset   float64 `json:"offset"`
+		Shortest bool    `json:"shortest"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	if err := s.videoOps.ReplaceAudio(context.Background(), video.ReplaceAudioOptions{
+		Input:    args.Input,
+		Audio:    args.Audio,
+		Output:   args.Output,
+		Offset:   args.Offset,
+		Shortest: args.Shortest,
+	}); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to replace audio: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully replaced audio: %s", args.Output)), nil
+}
+
+// registerSyncAudioVideo registers the sync_audio_video MCP tool
+func (s *MCPServer) registerSyncAudioVideo() {
+	s.addTool(mcp.Tool{
+		Name:        "sync_audio_video",
+		Description: "Line up audio from a separate recorder or mic with a video by cross-correlating it against the camera's own audio, then replace (or mix under) the camera audio with the synced track. Omit output to only report the offset.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Video file with camera (scratch) audio",
+				},
+				"audio": map[string]interface{}{
+					"type":        "string",
+					"description": "Externally recorded audio file",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path (optional; omit to only detect the offset)",
+				},
+				"maxOffset": map[string]interface{}{
+					"type":        "number",
+					"description": "Largest offset to search in seconds, either way (default: 300)",
+				},
+				"cameraVolume": map[string]interface{}{
+					"type":        "number",
+					"description": "Keep the camera audio mixed under the synced track at this volume, e.g. 0.2 (default: 0, replaced)",
+				},
+				"minConfidence": map[string]interface{}{
+					"type":        "number",
+					"description": "Refuse to merge when the match confidence (0-1) is below this (default: 0.2)",
+				},
+			},
+			Required: []string{"input", "audio"},
+		},
+	}, s.handleSyncAudioVideo)
+}
+
+func (s *MCPServer) handleSyncAudioVideo(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input         string   `json:"input"`
+		Audio         string   `json:"audio"`
+		Output        string   `json:"output"`
+		MaxOffset     float64  `json:"maxOffset"`
+		CameraVolume  float64  `json:"cameraVolume"`
+		MinConfidence *float64 `json:"minConfidence"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	ctx := context.Background()
+	result, err := s.audioOps.DetectOffset(ctx, args.Input, args.Audio, args.MaxOffset)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to detect offset: %v", err)), nil
+	}
+
+	direction := "after"
+	if result.Offset < 0 {
+		direction = "before"
+	}
+	summary := fmt.Sprintf("Audio starts %.3fs %s the video (offset %.3fs, confidence %.2f)",
+		math.Abs(result.Offset), direction, result.Offset, result.Confidence)
+	if args.Output == "" {
+		return mcp.NewToolResultText(summary), nil
+	}
+
+	minConfidence := 0.2
+	if args.MinConfidence != nil {
+		minConfidence = *args.MinConfidence
+	}
+	if result.Confidence < minConfidence {
+		return mcp.NewToolResultError(fmt.Sprintf("%s; confidence is below %.2f, so the files may not share audio. Check them or lower minConfidence.",
+			summary, minConfidence)), nil
+	}
+
+	if err := s.videoOps.ReplaceAudio(ctx, video.ReplaceAudioOptions{
+		Input:          args.Input,
+		Audio:          args.Audio,
+		Output:         args.Output,
+		Offset:         result.Offset,
+		Shortest:       true,
+		OriginalVolume: args.CameraVolume,
+	}); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to merge synced audio: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("%s\nSuccessfully synced audio to: %s", summary, args.Output)), nil
+}
+
+// registerFitVoiceover registers the fit_voiceover MCP tool
+func (s *MCPServer) registerFitVoiceover() {
+	s.addTool(mcp.Tool{
+		Name:        "fit_voiceover",
+		Description: "Lay a narration over a video so they end together. Each section between sync points is fitted by nudging the speech tempo, holding the last frame or stretching the video, so narration and visuals stay matched.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"narration": map[string]interface{}{
+					"type":        "string",
+					"description": "Narration audio file path (e.g. from generate_speech)",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"syncPoints": map[string]interface{}{
+					"type":        "array",
+					"description": "Moments that must line up, e.g. [{\"video\": 12.0, \"narration\": 13.4}] to start the second section's narration as the video reaches 12s. Omit to fit the whole narration to the whole video",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"video":     map[string]interface{}{"type": "number", "description": "Video time in seconds"},
+							"narration": map[string]interface{}{"type": "number", "description": "Narration time in seconds"},
+						},
+						"required": []string{"video", "narration"},
+					},
+				},
+				"mode": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{video.FitModeAuto, video.FitModeTempo, video.FitModeVideo},
+					"description": "auto: adjust speech tempo up to maxTempoChange, then the video; tempo: speech tempo only; video: video only (default: auto)",
+				},
+				"videoFit": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{video.VideoFitPad, video.VideoFitStretch},
+					"description": "pad: hold the last frame when narration runs long and leave silence when it runs short; stretch: change the section's playback speed (default: pad)",
+				},
+				"maxTempoChange": map[string]interface{}{
+					"type":        "number",
+					"description": "Largest speech tempo change in auto mode, as a fraction (default: 0.1 for ±10%)",
+				},
+				"originalVolume": map[string]interface{}{
+					"type":        "number",
+					"description": "Keep the video's own audio under the narration at this volume, e.g. 0.2 (default: 0, dropped)",
+				},
+			},
+			Required: []string{"input", "narration", "output"},
+		},
+	}, s.handleFitVoiceover)
+}
+
+func (s *MCPServer) handleFitVoiceover(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input          string            `json:"input"`
+		Narration      string            `json:"narration"`
+		Output         string            `json:"output"`
+		SyncPoints     []video.SyncPoint `json:"syncPoints"`
+		Mode           string            `json:"mode"`
+		VideoFit       string            `json:"videoFit"`
+		MaxTempoChange float64           `json:"maxTempoChange"`
+		OriginalVolume float64           `json:"originalVolume"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.videoOps.FitVoiceover(context.Background(), video.FitVoiceoverOptions{
+		Input:          args.Input,
+		Narration:      args.Narration,
+		Output:         args.Output,
+		SyncPoints:     args.SyncPoints,
+		Mode:           args.Mode,
+		VideoFit:       args.VideoFit,
+		MaxTempoChange: args.MaxTempoChange,
+		OriginalVolume: args.OriginalVolume,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to fit voiceover: %v", err)), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Successfully fitted voiceover (%.2fs): %s\n\nSections:\n", result.Duration, args.Output))
+	for i, section := range result.Sections {
+		b.WriteString(fmt.Sprintf("%d. video %.2f-%.2fs, narration %.2f-%.2fs -> %.2f-%.2fs: tempo %.3fx",
+			i+1, section.VideoStart, section.VideoEnd, section.NarrationStart, section.NarrationEnd,
+			section.OutputStart, section.OutputEnd, section.Tempo))
+		if section.VideoSpeed != 1 {
+			b.WriteString(fmt.Sprintf(", video %.3fx", section.VideoSpeed))
+		}
+		if section.Hold > 0 {
+			b.WriteString(fmt.Sprintf(", hold %.2fs", section.Hold))
+		}
+		if section.Silence > 0 {
+			b.WriteString(fmt.Sprintf(", silence %.2fs", section.Silence))
+		}
+		b.WriteString("\n")
+	}
+	if len(result.Warnings) > 0 {
+		b.WriteString("\nWarnings:\n")
+		for _, warning := range result.Warnings {
+			b.WriteString(fmt.Sprintf("- %s\n", warning))
+		}
+	}
+	return mcp.NewToolResultText(b.String()), nil
+}
+
+// registerAddAudioTrack registers the add_audio_track MCP tool
+func (s *MCPServer) registerAddAudioTrack() {
+	s.addTool(mcp.Tool{
+		Name:        "add_audio_track",
+		Description: "Add an audio file as an additional audio track (e.g. commentary or another language), keeping existing tracks. Video is not re-encoded.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"audio": map[string]interface{}{
+					"type":        "string",
+					"description": "Audio file to add",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"offset": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds to delay the new track; negative values skip into the audio (default: 0)",
+				},
+			},
+			Required: []string{"input", "audio", "output"},
+		},
+	}, s.handleAddAudioTrack)
+}
+
+func (s *MCPServer) handleAddAudioTrack(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input  string  `json:"input"`
+		Audio  string  `json:"audio"`
+		Output string  `json:"output"`
+		Offset float64 `json:"offset"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	if err := s.videoOps.AddAudioTrack(context.Background(), video.AddAudioTrackOptions{
+		Input:  args.Input,
+		Audio:  args.Audio,
+		Output: args.Output,
+		Offset: args.Offset,
+	}); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to add audio track: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully added audio track: %s", args.Output)), nil
+}
+
+// registerMuxAudioTracks registers the mux_audio_tracks MCP tool
+func (s *MCPServer) registerMuxAudioTracks() {
+	s.addTool(mcp.Tool{
+		Name:        "mux_audio_tracks",
+		Description: "Attach multiple audio tracks (e.g. original, dubbed languages, music-only) to one MP4/MOV/MKV with language, title and default/forced flags. Video is not re-encoded.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output file path (.mp4, .m4v, .mov or .mkv)",
+				},
+				"tracks": map[string]interface{}{
+					"type":        "array",
+					"description": "Audio tracks to attach, in order",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"path": map[string]interface{}{
+								"type":        "string",
+								"description": "Audio file path",
+							},
+							"language": map[string]interface{}{
+								"type":        "string",
+								"description": "Language code, e.g. 'eng', 'es', 'spa'",
+							},
+							"title": map[string]interface{}{
+								"type":        "string",
+								"description": "Track name shown in players, e.g. 'Spanish (dub)'",
+							},
+							"default": map[string]interface{}{
+								"type":        "boolean",
+								"description": "Play this track by default (at most one track)",
+							},
+							"forced": map[string]interface{}{
+								"type":        "boolean",
+								"description": "Mark the track as forced",
+							},
+						},
+						"required": []string{"path"},
+					},
+				},
+				"keepOriginal": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Keep the video's existing audio as the first track(s) (default: true)",
+				},
+				"originalLanguage": map[string]interface{}{
+					"type":        "string",
+					"description": "Language code for the existing audio when kept",
+				},
+			},
+			Required: []string{"input", "output", "tracks"},
+		},
+	}, s.handleMuxAudioTracks)
+}
+
+func (s *MCPServer) handleMuxAudioTracks(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input  string `json:"input"`
+		Output string `json:"output"`
+		Tracks []struct {
+			Path     string `json:"path"`
+			Language string `json:"language"`
+			Title    string `json:"title"`
+			Default  bool   `json:"default"`
+			Forced   bool   `json:"forced"`
+		} `json:"tracks"`
+		KeepOriginal     *bool  `json:"keepOriginal"`
+		OriginalLanguage string `json:"originalLanguage"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	opts := video.MuxAudioTracksOptions{
+		Input:            args.Input,
+		Output:           args.Output,
+		KeepOriginal:     args.KeepOriginal == nil || *args.KeepOriginal,
+		OriginalLanguage: args.OriginalLanguage,
+	}
+	for _, t := range args.Tracks {
+		opts.Tracks = append(opts.Tracks, video.AudioTrack{
+			Path:     t.Path,
+			Language: t.Language,
+			Title:    t.Title,
+			Default:  t.Default,
+			Forced:   t.Forced,
+		})
+	}
+
+	if err := s.videoOps.MuxAudioTracks(context.Background(), opts); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to mux audio tracks: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully muxed %d audio track(s) into: %s", len(opts.Tracks), args.Output)), nil
+}
+
+// registerAttachSubtitles registers the attach_subtitles MCP tool
+func (s *MCPServer) registerAttachSubtitles() {
+	s.addTool(mcp.Tool{
+		Name:        "attach_subtitles",
+		Description: "Embed SRT/VTT/ASS files as soft subtitle tracks that viewers can toggle (mov_text in MP4/MOV, WebVTT in WebM, SRT/ASS in MKV), with language tags and default/forced flags. Video and audio are not re-encoded; use burn_subtitles to hard-burn instead.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output file path (.mp4, .m4v, .mov, .mkv or .webm)",
+				},
+				"tracks": map[string]interface{}{
+					"type":        "array",
+					"description": "Subtitle files to attach, in order",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"path": map[string]interface{}{
+								"type":        "string",
+								"description": "Subtitle file path (.srt, .vtt or .ass)",
+							},
+							"language": map[string]interface{}{
+								"type":        "string",
+								"description": "Language code, e.g. 'eng', 'es', 'spa'",
+							},
+							"title": map[string]interface{}{
+								"type":        "string",
+								"description": "Track name shown in players",
+							},
+							"default": map[string]interface{}{
+								"type":        "boolean",
+								"description": "Show this track by default (at most one track)",
+							},
+							"forced": map[string]interface{}{
+								"type":        "boolean",
+								"description": "Mark as forced subtitles",
+							},
+						},
+						"required": []string{"path"},
+					},
+				},
+				"keepExisting": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Keep subtitle tracks already in the input (default: true)",
+				},
+			},
+			Required: []string{"input", "output", "tracks"},
+		},
+	}, s.handleAttachSubtitles)
+}
+
+func (s *MCPServer) handleAttachSubtitles(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input  string `json:"input"`
+		Output string `json:"output"`
+		Tracks []struct {
+			Path     string `json:"path"`
+			Language string `json:"language"`
+			Title    string `json:"title"`
+			Default  bool   `json:"default"`
+			Forced   bool   `json:"forced"`
+		} `json:"tracks"`
+		KeepExisting *bool `json:"keepExisting"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	opts := video.AttachSubtitlesOptions{
+		Input:        args.Input,
+		Output:       args.Output,
+		KeepExisting: args.KeepExisting == nil || *args.KeepExisting,
+	}
+	for _, t := range args.Tracks {
+		opts.Tracks = append(opts.Tracks, video.SubtitleTrack{
+			Path:     t.Path,
+			Language: t.Language,
+			Title:    t.Title,
+			Default:  t.Default,
+			Forced:   t.Forced,
+		})
+	}
+
+	if err := s.videoOps.AttachSubtitles(context.Background(), opts); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to attach subtitles: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully attached %d subtitle track(s) to: %s", len(opts.Tracks), args.Output)), nil
+}
+
+// registerExtractSubtitles registers the extract_subtitles MCP tool
+func (s *MCPServer) registerExtractSubtitles() {
+	s.addTool(mcp.Tool{
+		Name:        "extract_subtitles",
+		Description: "Extract an embedded subtitle track to an SRT, VTT or ASS file. Call without output to list the subtitle tracks in a file.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output subtitle file (.srt, .vtt or .ass). Omit to list tracks.",
+				},
+				"track": map[string]interface{}{
+					"type":        "number",
+					"description": "Subtitle track number, starting at 0 (default: 0)",
+				},
+			},
+			Required: []string{"input"},
+		},
+	}, s.handleExtractSubtitles)
+}
+
+func (s *MCPServer) handleExtractSubtitles(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input  string `json:"input"`
+		Output string `json:"output"`
+		Track  int    `json:"track"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	if args.Output == "" {
+		tracks, err := s.videoOps.ListSubtitleTracks(context.Background(), args.Input)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list subtitles: %v", err)), nil
+		}
+		if len(tracks) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("No subtitle tracks found in %s", args.Input)), nil
+		}
+
+		var out strings.Builder
+		out.WriteString(fmt.Sprintf("SUBTITLE TRACKS: %s\n", args.Input))
+		for _, t := range tracks {
+			out.WriteString(fmt.Sprintf("- Track %d: %s", t.Track, t.Codec))
+			if t.Language != "" {
+				out.WriteString(fmt.Sprintf(", language %s", t.Language))
+			}
+			if t.Title != "" {
+				out.WriteString(fmt.Sprintf(", \"%s\"", t.Title))
+			}
+			if t.Default {
+				out.WriteString(", default")
+			}
+			if t.Forced {
+				out.WriteString(", forced")
+			}
+			out.WriteString("\n")
+		}
+		return mcp.NewToolResultText(out.String()), nil
+	}
+
+	if err := s.videoOps.ExtractSubtitles(context.Background(), args.Input, args.Output, args.Track); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to extract subtitles: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully extracted subtitle track %d to: %s", args.Track, args.Output)), nil
+}
+
+// registerAddIntroOutro registers the add_intro_outro MCP tool
+func (s *MCPServer) registerAddIntroOutro() {
+	s.addTool(mcp.Tool{
+		Name:        "add_intro_outro",
+		Description: "Prepend an intro and/or append an outro bumper to a video. Bumpers are conformed to the main clip's resolution (letterboxed if needed), frame rate and codec, with silence added where a clip has no audio, and an optional crossfade between clips.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Main video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"intro": map[string]interface{}{
+					"type":        "string",
+					"description": "Intro clip to prepend (optional)",
+				},
+				"outro": map[string]interface{}{
+					"type":        "string",
+					"description": "Outro clip to append (optional)",
+				},
+				"crossfade": map[string]interface{}{
+					"type":        "number",
+					"description": "Crossfade duration in seconds between clips (default: 0, hard cut)",
+				},
+				"transition": map[string]interface{}{
+					"type":        "string",
+					"description": "xfade transition for the crossfade: fade, wipeleft, slideup, circleopen, dissolve, etc. (default: fade)",
+				},
+				"quality": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"high", "medium", "low"},
+					"description": "Encode quality (default: high)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleAddIntroOutro)
+}
+
+func (s *MCPServer) handleAddIntroOutro(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input      string  `json:"input"`
+		Output     string  `json:"output"`
+		Intro      string  `json:"intro"`
+		Outro      string  `json:"outro"`
+		Crossfade  float64 `json:"crossfade"`
+		Transition string  `json:"transition"`
+		Quality    string  `json:"quality"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	err := s.videoOps.AddIntroOutro(context.Background(), video.IntroOutroOptions{
+		Input:      args.Input,
+		Output:     args.Output,
+		Intro:      args.Intro,
+		Outro:      args.Outro,
+		Crossfade:  args.Crossfade,
+		Transition: args.Transition,
+		Quality:    args.Quality,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to add intro/outro: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully added intro/outro to: %s", args.Output)), nil
+}
+
+// registerToneMap registers the tone_map MCP tool
+func (s *MCPServer) registerToneMap() {
+	s.addTool(mcp.Tool{
+		Name:        "tone_map",
+		Description: "Convert HDR video (HDR10/PQ or HLG, e.g. iPhone HDR clips) to SDR BT.709 with zscale tone mapping so it looks right on SDR screens and platforms. Wide-gamut SDR input (BT.2020, Display P3) is converted to BT.709 primaries.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"algorithm": map[string]interface{}{
+					"type":        "string",
+					"enum":        video.ToneMapAlgorithms,
+					"description": "Tone curve: hable (filmic, default), mobius (keeps in-range colors closest), reinhard (simple, flatter), clip (hard clip)",
+				},
+				"peak": map[string]interface{}{
+					"type":        "number",
+					"description": "Source peak brightness in nits (default: the file's MaxCLL, else 1000)",
+				},
+				"quality": map[string]interface{}{
+					"type":        "string",
+					"description": "Quality: high, medium, low (default: high)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleToneMap)
+}
+
+func (s *MCPServer) handleToneMap(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input     string  `json:"input"`
+		Output    string  `json:"output"`
+		Algorithm string  `json:"algorithm"`
+		Peak      float64 `json:"peak"`
+		Quality   string  `json:"quality"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.videoOps.ToneMap(context.Background(), video.ToneMapOptions{
+		Input:     args.Input,
+		Output:    args.Output,
+		Algorithm: args.Algorithm,
+		Peak:      args.Peak,
+		Quality:   args.Quality,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to tone map: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("TONE MAP: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80) + "\n\n")
+	out.WriteString(fmt.Sprintf("Source: %s\n", describeColor(result.Source)))
+	if result.ToneMapped {
+		out.WriteString(fmt.Sprintf("Tone mapped with %s from a %.0f nit peak to SDR BT.709\n", result.Algorithm, result.Peak))
+	} else {
+		out.WriteString("Source is SDR: converted color space to BT.709 without tone mapping\n")
+	}
+	out.WriteString(fmt.Sprintf("\nOutput: %s\n", args.Output))
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// describeColor summarizes color signaling, e.g. "HLG, bt2020 primaries, bt2020nc matrix"
+func describeColor(c video.ColorInfo) string {
+	value := func(v string) string {
+		if v == "" {
+			return "unknown"
+		}
+		return v
+	}
+	kind := "SDR"
+	switch c.Transfer {
+	case "smpte2084":
+		kind = "HDR10 (PQ)"
+	case "arib-std-b67":
+		kind = "HLG"
+	}
+	desc := fmt.Sprintf("%s, %s primaries, %s transfer, %s matrix", kind, value(c.Primaries), value(c.Transfer), value(c.Space))
+	if c.MaxCLL > 0 {
+		desc += fmt.Sprintf(", MaxCLL %d nits", c.MaxCLL)
+	}
+	return desc
+}
+
+// registerDetectInterlacing registers the detect_interlacing MCP tool
+func (s *MCPServer) registerDetectInterlacing() {
+	s.addTool(mcp.Tool{
+		Name:        "detect_interlacing",
+		Description: "Analyze a video with FFmpeg's idet filter: progressive, interlaced (with top or bottom field first), telecined (3:2 pulldown film) or mixed. Use before deinterlace_video on archival or broadcast material.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"frames": map[string]interface{}{
+					"type":        "integer",
+					"description": "Frames to analyze from the start (default: 500)",
+				},
+			},
+			Required: []string{"input"},
+		},
+	}, s.handleDetectInterlacing)
+}
+
+func (s *MCPServer) handleDetectInterlacing(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input  string `json:"input"`
+		Frames int    `json:"frames"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	report, err := s.videoOps.DetectInterlacing(context.Background(), args.Input, args.Frames)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to detect interlacing: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("INTERLACE DETECTION: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80) + "\n\n")
+	out.WriteString(interlaceSummary(report))
+	switch report.Scan {
+	case "interlaced":
+		out.WriteString("\nRecommendation: deinterlace_video with method bwdif\n")
+	case "telecine":
+		out.WriteString("\nRecommendation: deinterlace_video with method ivtc to restore the film frames\n")
+	case "mixed":
+		out.WriteString("\nRecommendation: deinterlace_video with onlyFlagged to leave progressive frames alone\n")
+	default:
+		out.WriteString("\nNo deinterlacing needed\n")
+	}
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// interlaceSummary formats an idet report
+func interlaceSummary(r *video.InterlaceReport) string {
+	scan := r.Scan
+	if r.FieldOrder != "" {
+		scan += fmt.Sprintf(" (%s)", strings.ToUpper(r.FieldOrder))
+	}
+	return fmt.Sprintf("Scan: %s\nFrames checked: %d (TFF %d, BFF %d, progressive %d, undetermined %d)\nRepeated fields: %.0f%% of frames\n",
+		scan, r.FramesChecked, r.TFF, r.BFF, r.Progressive, r.Undetermined, r.RepeatedRatio*100)
+}
+
+// registerDeinterlaceVideo registers the deinterlace_video MCP tool
+func (s *MCPServer) registerDeinterlaceVideo() {
+	s.addTool(mcp.Tool{
+		Name:        "deinterlace_video",
+		Description: "Deinterlace video with bwdif or yadif, or undo 3:2 pulldown (inverse telecine) to restore 23.976 fps film frames. By default the scan type and field order are detected first and the method is chosen to match.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"method": map[string]interface{}{
+					"type":        "string",
+					"enum":        video.DeinterlaceMethods,
+					"description": "auto (detect, default), bwdif (best quality), yadif (faster), ivtc (inverse telecine for film transferred to video)",
+				},
+				"fieldOrder": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"auto", "tff", "bff"},
+					"description": "Field order: auto (detect, default), tff (top field first, most HD), bff (bottom field first, DV)",
+				},
+				"doubleRate": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Output one frame per field (e.g. 50i to 50p) for smoother motion (bwdif/yadif)",
+				},
+				"onlyFlagged": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only deinterlace frames flagged as interlaced, for sources mixing progressive and interlaced parts",
+				},
+				"quality": map[string]interface{}{
+					"type":        "string",
+					"description": "Quality: high, medium, low (default: high)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleDeinterlaceVideo)
+}
+
+func (s *MCPServer) handleDeinterlaceVideo(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input       string `json:"input"`
+		Output      string `json:"output"`
+		Method      string `json:"method"`
+		FieldOrder  string `json:"fieldOrder"`
+		DoubleRate  bool   `json:"doubleRate"`
+		OnlyFlagged bool   `json:"onlyFlagged"`
+		Quality     string `json:"quality"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.videoOps.Deinterlace(context.Background(), video.DeinterlaceOptions{
+		Input:       args.Input,
+		Output:      args.Output,
+		Method:      args.Method,
+		FieldOrder:  args.FieldOrder,
+		DoubleRate:  args.DoubleRate,
+		OnlyFlagged: args.OnlyFlagged,
+		Quality:     args.Quality,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to deinterlace: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("DEINTERLACE: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80) + "\n\n")
+	if result.Detection != nil {
+		out.WriteString("DETECTION:\n")
+		out.WriteString(interlaceSummary(result.Detection))
+		out.WriteString("\n")
+	}
+	out.WriteString(fmt.Sprintf("Method: %s\nField order: %s\nFilter: %s\n", result.Method, result.FieldOrder, result.Filter))
+	if result.Detection != nil && result.Detection.Scan == "progressive" && args.Method == "" {
+		out.WriteString("\nWARNINGS:\n- Source looks progressive; only frames flagged as interlaced were processed\n")
+	}
+	out.WriteString(fmt.Sprintf("\nOutput: %s\n", args.Output))
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// registerGenerateScopes registers the generate_scopes MCP tool
+func (s *MCPServer) registerGenerateScopes() {
+	s.addTool(mcp.Tool{
+		Name:        "generate_scopes",
+		Description: "Render video scopes (luma waveform on an IRE scale, vectorscope with skin tone line, RGB histogram) next to the picture, for one frame as an image or over time as a video, to judge exposure and color from data",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output image path (.png/.jpg), or video path (.mp4) with overTime",
+				},
+				"time": map[string]interface{}{
+					"type":        "number",
+					"description": "Frame to analyze in seconds, or where to start with overTime (default: 0)",
+				},
+				"scopes": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string", "enum": video.ScopeTypes},
+					"description": "Scopes to render (default: all)",
+				},
+				"hideSource": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Leave out the picture panel",
+				},
+				"overTime": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Render a video of the scopes as the clip plays instead of one frame",
+				},
+				"duration": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds to render with overTime (default: to the end)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleGenerateScopes)
+}
+
+func (s *MCPServer) handleGenerateScopes(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input      string   `json:"input"`
+		Output     string   `json:"output"`
+		Time       float64  `json:"time"`
+		Scopes     []string `json:"scopes"`
+		HideSource bool     `json:"hideSource"`
+		OverTime   bool     `json:"overTime"`
+		Duration   float64  `json:"duration"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	err := s.videoOps.GenerateScopes(context.Background(), video.ScopesOptions{
+		Input:      args.Input,
+		Output:     args.Output,
+		Time:       args.Time,
+		Scopes:     args.Scopes,
+		HideSource: args.HideSource,
+		OverTime:   args.OverTime,
+		Duration:   args.Duration,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to generate scopes: %v", err)), nil
+	}
+
+	what := fmt.Sprintf("frame at %.2fs", args.Time)
+	if args.OverTime {
+		what = "video over time"
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully rendered scopes (%s) to: %s", what, args.Output)), nil
+}
@@ -0,0 +1,720 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/visual"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerCreateSlideshow registers the create_slideshow MCP tool
+func (s *MCPServer) registerCreateSlideshow() {
+	s.addTool(mcp.Tool{
+		Name:        "create_slideshow",
+		Description: "Build a Ken Burns slideshow from a list or folder of images: each image gets a random or chosen pan/zoom, slides crossfade into each other, and an optional music track can drive slide changes on its beats.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"images": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Image files in order (or use folder)",
+				},
+				"folder": map[string]interface{}{
+					"type":        "string",
+					"description": "Folder of images, used in file name order (or use images)",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"width": map[string]interface{}{
+					"type":        "number",
+					"description": "Output width (default: 1920)",
+				},
+				"height": map[string]interface{}{
+					"type":        "number",
+					"description": "Output height (default: 1080)",
+				},
+				"fps": map[string]interface{}{
+					"type":        "number",
+					"description": "Frame rate (default: 30)",
+				},
+				"slideDuration": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds per slide (default: 4)",
+				},
+				"crossfade": map[string]interface{}{
+					"type":        "number",
+					"description": "Crossfade between slides in seconds (default: 1, 0 for hard cuts)",
+				},
+				"transition": map[string]interface{}{
+					"type":        "string",
+					"description": "xfade transition: fade, dissolve, wipeleft, slideleft, circleopen, etc. (default: fade)",
+				},
+				"motion": map[string]interface{}{
+					"type":        "string",
+					"enum":        visual.SlideshowMotions,
+					"description": "Pan/zoom for every slide (default: random, never repeating the previous slide)",
+				},
+				"motions": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Per-slide motions in image order; empty entries use motion",
+				},
+				"seed": map[string]interface{}{
+					"type":        "number",
+					"description": "Seed for random motions, for repeatable results (optional)",
+				},
+				"music": map[string]interface{}{
+					"type":        "string",
+					"description": "Music track laid under the slideshow, faded out at the end (optional)",
+				},
+				"beatSync": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Change slides on the music's beats (default: false)",
+				},
+				"beatsPerSlide": map[string]interface{}{
+					"type":        "number",
+					"description": "Beats per slide when syncing (default: the count closest to slideDuration)",
+				},
+			},
+			Required: []string{"output"},
+		},
+	}, s.handleCreateSlideshow)
+}
+
+func (s *MCPServer) handleCreateSlideshow(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Images        []string `json:"images"`
+		Folder        string   `json:"folder"`
+		Output        string   `json:"output"`
+		Width         int      `json:"width"`
+		Height        int      `json:"height"`
+		FPS           int      `json:"fps"`
+		SlideDuration float64  `json:"slideDuration"`
+		Crossfade     *float64 `json:"crossfade"`
+		Transition    string   `json:"transition"`
+		Motion        string   `json:"motion"`
+		Motions       []string `json:"motions"`
+		Seed          int64    `json:"seed"`
+		Music         string   `json:"music"`
+		BeatSync      bool     `json:"beatSync"`
+		BeatsPerSlide int      `json:"beatsPerSlide"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	result, err := s.visualFx.CreateSlideshow(context.Background(), visual.SlideshowOptions{
+		Images:        args.Images,
+		Folder:        args.Folder,
+		Output:        args.Output,
+		Width:         args.Width,
+		Height:        args.Height,
+		FPS:           args.FPS,
+		SlideDuration: args.SlideDuration,
+		Crossfade:     args.Crossfade,
+		Transition:    args.Transition,
+		Motion:        args.Motion,
+		Motions:       args.Motions,
+		Seed:          args.Seed,
+		Music:         args.Music,
+		BeatSync:      args.BeatSync,
+		BeatsPerSlide: args.BeatsPerSlide,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create slideshow: %v", err)), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Successfully created slideshow (%d slides, %.1fs) at: %s\n", len(result.Slides), result.Duration, args.Output))
+	if result.Tempo > 0 {
+		sb.WriteString(fmt.Sprintf("Synced to beats at %.1f BPM\n", result.Tempo))
+	}
+	sb.WriteString("\nSlides:\n")
+	for _, slide := range result.Slides {
+		sb.WriteString(fmt.Sprintf("- %.2fs  %-9s  %s\n", slide.Start, slide.Motion, filepath.Base(slide.Image)))
+	}
+
+	return mcp.NewToolResultText(sb.String()), nil
+}
+
+// registerPunchIn registers the punch_in MCP tool
+func (s *MCPServer) registerPunchIn() {
+	s.addTool(mcp.Tool{
+		Name:        "punch_in",
+		Description: "Zoom into a region of a screen recording between two timestamps, easing smoothly in and back out. The region is fitted to the frame's aspect ratio; the rest of the video is untouched.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video file path",
+				},
+				"start": map[string]interface{}{
+					"type":        "number",
+					"description": "When the zoom starts easing in, in seconds",
+				},
+				"end": map[string]interface{}{
+					"type":        "number",
+					"description": "When the zoom has eased back out, in seconds",
+				},
+				"x": map[string]interface{}{
+					"type":        "number",
+					"description": "Region's left edge as a fraction of the frame width (0-1)",
+				},
+				"y": map[string]interface{}{
+					"type":        "number",
+					"description": "Region's top edge as a fraction of the frame height (0-1)",
+				},
+				"width": map[string]interface{}{
+					"type":        "number",
+					"description": "Region width as a fraction of the frame width",
+				},
+				"height": map[string]interface{}{
+					"type":        "number",
+					"description": "Region height as a fraction of the frame height",
+				},
+				"ease": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds to ease in and to ease out (default: 0.6)",
+				},
+			},
+			Required: []string{"input", "output", "start", "end", "x", "y", "width", "height"},
+		},
+	}, s.handlePunchIn)
+}
+
+func (s *MCPServer) handlePunchIn(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input  string  `json:"input"`
+		Output string  `json:"output"`
+		Start  float64 `json:"start"`
+		End    float64 `json:"end"`
+		X      float64 `json:"x"`
+		Y      float64 `json:"y"`
+		Width  float64 `json:"width"`
+		Height float64 `json:"height"`
+		Ease   float64 `json:"ease"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	err := s.visualFx.PunchIn(context.Background(), visual.PunchInOptions{
+		Input:  args.Input,
+		Output: args.Output,
+		Start:  args.Start,
+		End:    args.End,
+		X:      args.X,
+		Y:      args.Y,
+		W:      args.Width,
+		H:      args.Height,
+		Ease:   args.Ease,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to punch in: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully punched in from %.2fs to %.2fs: %s", args.Start, args.End, args.Output)), nil
+}
+
+// registerDenoiseVideo registers the denoise_video MCP tool
+func (s *MCPServer) registerDenoiseVideo() {
+	s.addTool(mcp.Tool{
+		Name:        "denoise_video",
+		Description: "Reduce video noise (low-light sensor noise, compression artifacts) with hqdn3d (fast) or nlmeans (slower, keeps more detail) at light, medium or strong presets",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video path",
+				},
+				"method": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{visual.DenoiseFast, visual.DenoiseQuality},
+					"description": "hqdn3d: fast spatial and temporal smoothing (default); nlmeans: non-local means, several times slower",
+				},
+				"strength": map[string]interface{}{
+					"type":        "string",
+					"enum":        visual.Strengths,
+					"description": "Strength preset (default: medium)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleDenoiseVideo)
+}
+
+func (s *MCPServer) handleDenoiseVideo(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input    string `json:"input"`
+		Output   string `json:"output"`
+		Method   string `json:"method"`
+		Strength string `json:"strength"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	err := s.visualFx.Denoise(context.Background(), visual.DenoiseOptions{
+		Input:    args.Input,
+		Output:   args.Output,
+		Method:   args.Method,
+		Strength: args.Strength,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to denoise video: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully denoised video to: %s", args.Output)), nil
+}
+
+// registerAddFilmGrain registers the add_film_grain MCP tool
+func (s *MCPServer) registerAddFilmGrain() {
+	s.addTool(mcp.Tool{
+		Name:        "add_film_grain",
+		Description: "Add moving film grain for a film look, or to hide banding after denoising or heavy compression",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video path",
+				},
+				"strength": map[string]interface{}{
+					"type":        "string",
+					"enum":        visual.Strengths,
+					"description": "Strength preset (default: medium)",
+				},
+				"intensity": map[string]interface{}{
+					"type":        "number",
+					"description": "Grain intensity 1-100, overrides strength",
+				},
+				"color": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Add grain to the color channels too, like color negative film (default: luma only)",
+				},
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleAddFilmGrain)
+}
+
+func (s *MCPServer) handleAddFilmGrain(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input     string  `json:"input"`
+		Output    string  `json:"output"`
+		Strength  string  `json:"strength"`
+		Intensity float64 `json:"intensity"`
+		Color     bool    `json:"color"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	err := s.visualFx.AddFilmGrain(context.Background(), visual.FilmGrainOptions{
+		Input:     args.Input,
+		Output:    args.Output,
+		Strength:  args.Strength,
+		Intensity: args.Intensity,
+		Color:     args.Color,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to add film grain: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully added film grain to: %s", args.Output)), nil
+}
+
+// registerApplyStylePreset registers the apply_style_preset MCP tool
+func (s *MCPServer) registerApplyStylePreset() {
+	var looks []string
+	for _, name := range visual.StylePresetNames() {
+		looks = append(looks, fmt.Sprintf("%s (%s)", name, visual.StylePresets[name].Description))
+	}
+	s.addTool(mcp.Tool{
+		Name:        "apply_style_preset",
+		Description: "Give a video a curated look in one pass: " + strings.Join(looks, "; "),
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video path",
+				},
+				"preset": map[string]interface{}{
+					"type":        "string",
+					"enum":        visual.StylePresetNames(),
+					"description": "Look to apply",
+				},
+				"mask": effectMaskSchema,
+			},
+			Required: []string{"input", "output", "preset"},
+		},
+	}, s.handleApplyStylePreset)
+}
+
+func (s *MCPServer) handleApplyStylePreset(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input  string          `json:"input"`
+		Output string          `json:"output"`
+		Preset string          `json:"preset"`
+		Mask   *effectMaskArgs `json:"mask"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	err := s.visualFx.ApplyStylePreset(context.Background(), visual.StylePresetOptions{
+		Input:  args.Input,
+		Output: args.Output,
+		Preset: args.Preset,
+		Mask:   args.Mask.toEffectMask(),
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to apply style preset: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully applied %s style to: %s", args.Preset, args.Output)), nil
+}
+
+// registerApplyDuotone registers the apply_duotone MCP tool
+func (s *MCPServer) registerApplyDuotone() {
+	s.addTool(mcp.Tool{
+		Name:        "apply_duotone",
+		Description: "Map the picture's brightness onto a two-color gradient (shadows to highlights), for title cards and music video looks",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video path",
+				},
+				"shadows": map[string]interface{}{
+					"type":        "string",
+					"description": "Hex color for dark tones, #RRGGBB (default: #1B1464)",
+				},
+				"highlights": map[string]interface{}{
+					"type":        "string",
+					"description": "Hex color for bright tones, #RRGGBB (default: #FF6F61)",
+				},
+				"contrast": map[string]interface{}{
+					"type":        "number",
+					"description": "Contrast applied before mapping, -1 to 1 (default: 0)",
+				},
+				"mask": effectMaskSchema,
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleApplyDuotone)
+}
+
+func (s *MCPServer) handleApplyDuotone(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input      string          `json:"input"`
+		Output     string          `json:"output"`
+		Shadows    string          `json:"shadows"`
+		Highlights string          `json:"highlights"`
+		Contrast   float64         `json:"contrast"`
+		Mask       *effectMaskArgs `json:"mask"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	err := s.visualFx.ApplyDuotone(context.Background(), visual.DuotoneOptions{
+		Input:      args.Input,
+		Output:     args.Output,
+		Shadows:    args.Shadows,
+		Highlights: args.Highlights,
+		Contrast:   args.Contrast,
+		Mask:       args.Mask.toEffectMask(),
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to apply duotone: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully applied duotone to: %s", args.Output)), nil
+}
+
+// registerApplySelectiveColor registers the apply_selective_color MCP tool
+func (s *MCPServer) registerApplySelectiveColor() {
+	s.addTool(mcp.Tool{
+		Name:        "apply_selective_color",
+		Description: "Keep one color (e.g. a red coat) and turn the rest of the picture black and white",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video path",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video path",
+				},
+				"color": map[string]interface{}{
+					"type":        "string",
+					"description": "Color to keep, as a name or hex like 0x2060FF (default: red)",
+				},
+				"similarity": map[string]interface{}{
+					"type":        "number",
+					"description": "How close a color must be to be kept, 0.01-1 (default: 0.3)",
+				},
+				"blend": map[string]interface{}{
+					"type":        "number",
+					"description": "Softness of the falloff to gray, 0-1 (default: 0.1)",
+				},
+				"mask": effectMaskSchema,
+			},
+			Required: []string{"input", "output"},
+		},
+	}, s.handleApplySelectiveColor)
+}
+
+func (s *MCPServer) handleApplySelectiveColor(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input      string          `json:"input"`
+		Output     string          `json:"output"`
+		Color      string          `json:"color"`
+		Similarity float64         `json:"similarity"`
+		Blend      float64         `json:"blend"`
+		Mask       *effectMaskArgs `json:"mask"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	err := s.visualFx.ApplySelectiveColor(context.Background(), visual.SelectiveColorOptions{
+		Input:      args.Input,
+		Output:     args.Output,
+		Color:      args.Color,
+		Similarity: args.Similarity,
+		Blend:      args.Blend,
+		Mask:       args.Mask.toEffectMask(),
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to apply selective color: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully applied selective color to: %s", args.Output)), nil
+}
+
+// registerOverlayVideo registers the overlay_video MCP tool
+func (s *MCPServer) registerOverlayVideo() {
+	s.addTool(mcp.Tool{
+		Name:        "overlay_video",
+		Description: "Blend a full-frame overlay video or image (light leaks, smoke, dust, film texture) over a video with a blend mode and opacity. Screen suits light on black, multiply suits dark texture on white.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"mainVideo": map[string]interface{}{
+					"type":        "string",
+					"description": "Main video path",
+				},
+				"overlay": map[string]interface{}{
+					"type":        "string",
+					"description": "Overlay video or image path, scaled to the main frame",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video path",
+				},
+				"mode": map[string]interface{}{
+					"type":        "string",
+					"enum":        visual.BlendModes,
+					"description": "Blend mode (default: screen)",
+				},
+				"opacity": map[string]interface{}{
+					"type":        "number",
+					"description": "Overlay opacity 0-1 (default: 1)",
+				},
+				"start": map[string]interface{}{
+					"type":        "number",
+					"description": "Time in the main video the overlay begins, in seconds (default: 0)",
+				},
+				"loop": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Repeat the overlay until the main video ends (images always cover the whole video)",
+				},
+			},
+			Required: []string{"mainVideo", "overlay", "output"},
+		},
+	}, s.handleOverlayVideo)
+}
+
+func (s *MCPServer) handleOverlayVideo(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		MainVideo string  `json:"mainVideo"`
+		Overlay   string  `json:"overlay"`
+		Output    string  `json:"output"`
+		Mode      string  `json:"mode"`
+		Opacity   float64 `json:"opacity"`
+		Start     float64 `json:"start"`
+		Loop      bool    `json:"loop"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	err := s.composite.OverlayVideo(context.Background(), visual.OverlayVideoOptions{
+		MainVideo: args.MainVideo,
+		Overlay:   args.Overlay,
+		Output:    args.Output,
+		Mode:      args.Mode,
+		Opacity:   args.Opacity,
+		Start:     args.Start,
+		Loop:      args.Loop,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to overlay video: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Successfully blended overlay into: %s", args.Output)), nil
+}
+
+// effectMaskSchema is the mask parameter shared by the single-filter
+// effect tools
+var effectMaskSchema = map[string]interface{}{
+	"type":        "object",
+	"description": "Limit the effect to part of the frame and/or ramp it over time: give a shape box (x, y, width, height), moving keyframes, or a mask image, and optionally amount keyframes to fade the effect in and out",
+	"properties": map[string]interface{}{
+		"shape": map[string]interface{}{
+			"type":        "string",
+			"enum":        visual.MaskShapes,
+			"description": "Shape filling the box (default: rect)",
+		},
+		"x": map[string]interface{}{
+			"type":        "number",
+			"description": "Box left edge as a fraction of the frame width",
+		},
+		"y": map[string]interface{}{
+			"type":        "number",
+			"description": "Box top edge as a fraction of the frame height",
+		},
+		"width": map[string]interface{}{
+			"type":        "number",
+			"description": "Box width as a fraction of the frame width",
+		},
+		"height": map[string]interface{}{
+			"type":        "number",
+			"description": "Box height as a fraction of the frame height",
+		},
+		"keyframes": map[string]interface{}{
+			"type":        "array",
+			"description": "Moving box instead of a fixed one, interpolated linearly between keyframes",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"time":   map[string]interface{}{"type": "number"},
+					"x":      map[string]interface{}{"type": "number"},
+					"y":      map[string]interface{}{"type": "number"},
+					"width":  map[string]interface{}{"type": "number"},
+					"height": map[string]interface{}{"type": "number"},
+				},
+			},
+		},
+		"image": map[string]interface{}{
+			"type":        "string",
+			"description": "Mask image or video instead of a shape, scaled to the frame; white areas get the effect",
+		},
+		"useAlpha": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Read the mask image's transparency instead of its brightness",
+		},
+		"invert": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Apply the effect outside the mask instead",
+		},
+		"feather": map[string]interface{}{
+			"type":        "number",
+			"description": "Edge softness in pixels",
+		},
+		"amount": map[string]interface{}{
+			"type":        "array",
+			"description": "Effect strength over time, 0 (off) to 1 (full), interpolated linearly between keyframes",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"time":  map[string]interface{}{"type": "number"},
+					"value": map[string]interface{}{"type": "number"},
+				},
+			},
+		},
+	},
+}
+
+// effectMaskArgs is the mask parameter as sent by the client
+type effectMaskArgs struct {
+	Shape     string  `json:"shape"`
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+	Width     float64 `json:"width"`
+	Height    float64 `json:"height"`
+	Keyframes []struct {
+		Time   float64 `json:"time"`
+		X      float64 `json:"x"`
+		Y      float64 `json:"y"`
+		Width  float64 `json:"width"`
+		Height float64 `json:"height"`
+	} `json:"keyframes"`
+	Image    string  `json:"image"`
+	UseAlpha bool    `json:"useAlpha"`
+	Invert   bool    `json:"invert"`
+	Feather  float64 `json:"feather"`
+	Amount   []struct {
+		Time  float64 `json:"time"`
+		Value float64 `json:"value"`
+	} `json:"amount"`
+}
+
+// toEffectMask converts mask arguments, returning nil when no mask was given
+func (m *effectMaskArgs) toEffectMask() *visual.EffectMask {
+	if m == nil {
+		return nil
+	}
+	mask := &visual.EffectMask{
+		Shape:    m.Shape,
+		X:        m.X,
+		Y:        m.Y,
+		W:        m.Width,
+		H:        m.Height,
+		Image:    m.Image,
+		UseAlpha: m.UseAlpha,
+		Invert:   m.Invert,
+		Feather:  m.Feather,
+	}
+	for _, k := range m.Keyframes {
+		mask.Keyframes = append(mask.Keyframes, visual.RegionKeyframe{Time: k.Time, X: k.X, Y: k.Y, W: k.Width, H: k.Height})
+	}
+	for _, k := range m.Amount {
+		mask.Amount = append(mask.Amount, visual.AmountKeyframe{Time: k.Time, Value: k.Value})
+	}
+	return mask
+}
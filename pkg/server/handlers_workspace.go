@@ -0,0 +1,187 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/workspace"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// projectPaths explains how tools treat paths while a project is active
+const projectPaths = "While a project is active, relative output paths are written to its outputs/ folder (transcripts to transcripts/), relative inputs are found in the project, assets/ or outputs/, and tools given no output name one from the project's naming template."
+
+// registerWorkspaceTools registers the create_project, set_active_project
+// and list_projects MCP tools
+func (s *MCPServer) registerWorkspaceTools() {
+	s.addTool(mcp.Tool{
+		Name:        "create_project",
+		Description: "Create a project: a directory with assets/, outputs/, temp/ and transcripts/ folders that also holds its timelines, multi-take projects and usage records. " + projectPaths,
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Project name",
+				},
+				"root": map[string]interface{}{
+					"type":        "string",
+					"description": "Project directory (default: <projectsDir>/<name>)",
+				},
+				"naming": map[string]interface{}{
+					"type":        "string",
+					"description": "Template for output file names using {name} (input file name), {tool}, {project}, {date} and {time} (default: {name}_{tool})",
+				},
+				"description": map[string]interface{}{
+					"type":        "string",
+					"description": "What the project is for",
+				},
+				"activate": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Make it the active project (default: true)",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}, s.handleCreateProject)
+
+	s.addTool(mcp.Tool{
+		Name:        "set_active_project",
+		Description: "Switch to a project by name or directory, or leave the active project with an empty name so paths are used as given again. " + projectPaths,
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Project name or directory; empty to leave the active project",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}, s.handleSetActiveProject)
+
+	s.addTool(mcp.Tool{
+		Name:        "list_projects",
+		Description: "List registered projects and show which one is active",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+			Required:   []string{},
+		},
+	}, s.handleListProjects)
+}
+
+func (s *MCPServer) handleCreateProject(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Name        string `json:"name"`
+		Root        string `json:"root"`
+		Naming      string `json:"naming"`
+		Description string `json:"description"`
+		Activate    *bool  `json:"activate"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	activate := args.Activate == nil || *args.Activate
+	project, err := s.workspace.Create(workspace.CreateOptions{
+		Name:        args.Name,
+		Root:        args.Root,
+		Naming:      args.Naming,
+		Description: args.Description,
+		Activate:    activate,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create project: %v", err)), nil
+	}
+	if activate {
+		s.applyProject(project)
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("PROJECT CREATED: %s\n", project.Name))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	writeProject(&out, project)
+	if activate {
+		out.WriteString("\nThis is now the active project.\n")
+	} else {
+		out.WriteString(fmt.Sprintf("\nActivate it with set_active_project name=%q.\n", project.Name))
+	}
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+func (s *MCPServer) handleSetActiveProject(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Name string `json:"name"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	if strings.TrimSpace(args.Name) == "" {
+		if err := s.workspace.Deactivate(); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to leave project: %v", err)), nil
+		}
+		s.applyProject(nil)
+		return mcp.NewToolResultText("No project is active. Paths are used as given."), nil
+	}
+
+	project, err := s.workspace.Activate(args.Name)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to activate project: %v", err)), nil
+	}
+	s.applyProject(project)
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("ACTIVE PROJECT: %s\n", project.Name))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	writeProject(&out, project)
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+func (s *MCPServer) handleListProjects(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	entries := s.workspace.List()
+	if len(entries) == 0 {
+		return mcp.NewToolResultText("No projects yet. Create one with create_project."), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("PROJECTS (%d)\n", len(entries)))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	var warnings []string
+	for _, e := range entries {
+		marker := " "
+		if e.Active {
+			marker = "*"
+		}
+		out.WriteString(fmt.Sprintf("%s %s: %s\n", marker, e.Name, e.Root))
+		if e.Err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", e.Name, e.Err))
+		}
+	}
+	out.WriteString("\n* active project\n")
+
+	if len(warnings) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range warnings {
+			out.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+// writeProject lists a project's directory, folders and naming template
+func writeProject(out *strings.Builder, p *workspace.Project) {
+	out.WriteString(fmt.Sprintf("Directory: %s\n", p.Root))
+	if p.Description != "" {
+		out.WriteString(fmt.Sprintf("Description: %s\n", p.Description))
+	}
+	out.WriteString(fmt.Sprintf("Output names: %s\n", p.Naming))
+	out.WriteString("\nFOLDERS:\n")
+	for _, folder := range workspace.Folders {
+		out.WriteString(fmt.Sprintf("- %s\n", p.Dir(folder)))
+	}
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/chapters"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/youtube"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerUploadToYouTube registers the upload_to_youtube MCP tool
+func (s *MCPServer) registerUploadToYouTube() {
+	s.addTool(mcp.Tool{
+		Name:        "upload_to_youtube",
+		Description: "Publish a finished video to YouTube with title, description, tags, chapters, thumbnail and privacy status. Chapters (from generate_chapters or a list) are added to the description as timestamps. Uploads are resumable, and videos are private unless privacy or publishAt says otherwise. Requires youtubeClientId, youtubeClientSecret and youtubeRefreshToken (OAuth with the youtube.upload scope); each upload uses about 1,600 units of the daily API quota.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Video file to upload",
+				},
+				"title": map[string]interface{}{
+					"type":        "string",
+					"description": "Video title, at most 100 characters",
+				},
+				"description": map[string]interface{}{
+					"type":        "string",
+					"description": "Video description (chapters are appended)",
+				},
+				"tags": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Tags, at most 500 characters in total",
+				},
+				"chapters": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"start": map[string]interface{}{"type": "number", "description": "Start time in seconds"},
+							"title": map[string]interface{}{"type": "string", "description": "Chapter title"},
+						},
+					},
+					"description": "Chapters to list in the description. YouTube shows them when the first starts at 0 and there are at least 3 of 10 seconds or more",
+				},
+				"chaptersFile": map[string]interface{}{
+					"type":        "string",
+					"description": "Chapters JSON or YouTube timestamps file from generate_chapters, instead of chapters",
+				},
+				"thumbnail": map[string]interface{}{
+					"type":        "string",
+					"description": "Custom thumbnail, JPEG or PNG up to 2 MB (needs a verified channel)",
+				},
+				"privacy": map[string]interface{}{
+					"type":        "string",
+					"enum":        youtube.PrivacyStatuses,
+					"description": "Privacy status (default: private)",
+				},
+				"publishAt": map[string]interface{}{
+					"type":        "string",
+					"description": "Schedule a private video to go public at this RFC 3339 time, e.g. 2025-06-01T15:00:00Z",
+				},
+				"categoryId": map[string]interface{}{
+					"type":        "string",
+					"description": "YouTube category ID, e.g. 27 for Education or 28 for Science & Technology (default: 22, People & Blogs)",
+				},
+				"madeForKids": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Declare the video made for kids (default: false)",
+				},
+				"language": map[string]interface{}{
+					"type":        "string",
+					"description": "Language of the title and audio, e.g. en (optional)",
+				},
+			},
+			Required: []string{"input", "title"},
+		},
+	}, s.handleUploadToYouTube)
+}
+
+func (s *MCPServer) handleUploadToYouTube(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	var args struct {
+		Input        string             `json:"input"`
+		Title        string             `json:"title"`
+		Description  string             `json:"description"`
+		Tags         []string           `json:"tags"`
+		Chapters     []chapters.Chapter `json:"chapters"`
+		ChaptersFile string             `json:"chaptersFile"`
+		Thumbnail    string             `json:"thumbnail"`
+		Privacy      string             `json:"privacy"`
+		PublishAt    string             `json:"publishAt"`
+		CategoryID   string             `json:"categoryId"`
+		MadeForKids  bool               `json:"madeForKids"`
+		Language     string             `json:"language"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+	}
+
+	list := args.Chapters
+	if args.ChaptersFile != "" {
+		data, err := os.ReadFile(args.ChaptersFile)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read chapters: %v", err)), nil
+		}
+		if list, err = chapters.Parse(string(data)); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid chapters: %v", err)), nil
+		}
+	}
+
+	result, err := s.youtubeUploader.Upload(context.Background(), youtube.Options{
+		Input:       args.Input,
+		Title:       args.Title,
+		Description: args.Description,
+		Tags:        args.Tags,
+		Chapters:    list,
+		Thumbnail:   args.Thumbnail,
+		Privacy:     args.Privacy,
+		PublishAt:   args.PublishAt,
+		CategoryID:  args.CategoryID,
+		MadeForKids: args.MadeForKids,
+		Language:    args.Language,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to upload to YouTube: %v", err)), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("YOUTUBE UPLOAD: %s\n", args.Input))
+	out.WriteString(strings.Repeat("=", 80))
+	out.WriteString("\n\n")
+	out.WriteString(fmt.Sprintf("Video: %s\n", result.URL))
+	out.WriteString(fmt.Sprintf("Studio: %s\n", result.StudioURL))
+	out.WriteString(fmt.Sprintf("Title: %s\n", result.Title))
+	if result.PublishAt != "" {
+		out.WriteString(fmt.Sprintf("Privacy: %s until %s\n", result.Privacy, result.PublishAt))
+	} else {
+		out.WriteString(fmt.Sprintf("Privacy: %s\n", result.Privacy))
+	}
+	out.WriteString(fmt.Sprintf("Uploaded: %.1f MB\n", float64(result.Bytes)/(1<<20)))
+	if len(result.Tags) > 0 {
+		out.WriteString(fmt.Sprintf("Tags: %s\n", strings.Join(result.Tags, ", ")))
+	}
+	if result.Chapters > 0 {
+		out.WriteString(fmt.Sprintf("Chapters: %d\n", result.Chapters))
+	}
+	if args.Thumbnail != "" && result.ThumbnailSet {
+		out.WriteString(fmt.Sprintf("Thumbnail: %s\n", args.Thumbnail))
+	}
+	if result.Description != "" {
+		out.WriteString("\nDESCRIPTION:\n")
+		out.WriteString(result.Description)
+		out.WriteString("\n")
+	}
+	out.WriteString("\nYouTube processes the video for a few minutes before higher resolutions are available.\n")
+
+	if len(result.Warnings) > 0 {
+		out.WriteString("\nWARNINGS:\n")
+		for _, w := range result.Warnings {
+			out.WriteString(fmt.Sprintf("- %s\n", w))
+		}
+	}
+
+	return mcp.NewToolResultText(out.String()), nil
+}
@@ -0,0 +1,344 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/remote"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/workspace"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// outputKeys are the arguments that name what a tool writes
+var outputKeys = []string{"output", "outputPath", "outputDir"}
+
+// explicitOutputs keep a required output: the path itself is the request
+var explicitOutputs = map[string]bool{
+	"add_to_timeline":   true, // Records an output made elsewhere
+	"convert_subtitles": true, // The extension picks the format
+	"run_ffmpeg":        true, // Likewise, and the options depend on it
+}
+
+// fixedExtensions are the extensions of tools that always write one format
+var fixedExtensions = map[string]string{
+	"extract_transcript":   ".json",
+	"translate_transcript": ".srt",
+}
+
+// outputExtensions are the default output extensions of tools that don't
+// write the same kind of file they read, unless a format says otherwise
+var outputExtensions = map[string]string{
+	"extract_audio":            ".mp3",
+	"generate_thumbnail":       ".jpg",
+	"export_still":             ".jpg",
+	"create_thumbnail":         ".jpg",
+	"generate_contact_sheet":   ".jpg",
+	"generate_sprite_sheet":    ".jpg",
+	"generate_scopes":          ".png",
+	"generate_waveform_image":  ".png",
+	"generate_timeline":        ".png",
+	"generate_flowchart":       ".png",
+	"generate_org_chart":       ".png",
+	"generate_mind_map":        ".png",
+	"generate_speech":          ".mp3",
+	"generate_dialogue":        ".mp3",
+	"generate_silence":         ".wav",
+	"generate_tone":            ".wav",
+	"create_audiogram":         ".mp4",
+	"create_slideshow":         ".mp4",
+	"create_video_from_images": ".mp4",
+	"create_end_screen":        ".mp4",
+	"start_recording":          ".mp4",
+	"export_podcast":           ".mp3",
+}
+
+// outputLabels say what a tool did, for output names like
+// interview_trimmed_001.mp4. Other tools use their name without a leading
+// verb or trailing _video.
+var outputLabels = map[string]string{
+	"trim_video":               "trimmed",
+	"trim_audio":               "trimmed",
+	"trim_to_script":           "trimmed",
+	"resize_video":             "resized",
+	"concatenate_videos":       "joined",
+	"concatenate_audio":        "joined",
+	"convert_video":            "converted",
+	"convert_audio":            "converted",
+	"transcode_video":          "transcoded",
+	"transcode_for_web":        "web",
+	"mute_video":               "muted",
+	"replace_audio":            "new_audio",
+	"normalize_audio":          "normalized",
+	"clean_voice":              "clean",
+	"reverse_audio":            "reversed",
+	"fade_audio":               "faded",
+	"mix_audio":                "mixed",
+	"adjust_speed":             "speed",
+	"adjust_audio_speed":       "speed",
+	"smart_speed":              "speed",
+	"prepare_screen_recording": "prepared",
+	"blur_faces":               "blurred",
+	"blur_region":              "blurred",
+	"burn_subtitles":           "subtitled",
+	"auto_caption":             "captioned",
+	"karaoke_captions":         "captioned",
+	"dub_video":                "dubbed",
+	"remove_by_transcript":     "edited",
+	"replace_spoken_words":     "edited",
+	"remove_audio_section":     "cut",
+	"extract_frames":           "frames",
+	"export_still":             "still",
+	"set_metadata":             "tagged",
+	"embed_chapters":           "chapters",
+	"export_podcast":           "episode",
+	"start_recording":          "recording",
+}
+
+// formatName matches format arguments that are file extensions
+var formatName = regexp.MustCompile(`^[a-z0-9]{2,5}$`)
+
+// inputArguments name a tool's main input, in order of preference
+var inputArguments = []string{"input", "videoPath", "filePath", "mainVideo", "audio", "inputs", "videos", "images"}
+
+// chosenOutput is an output path picked for an argument that was left out
+type chosenOutput struct {
+	key  string
+	path string
+}
+
+// optionalOutputs drops required output arguments from a tool's schema,
+// since a path is chosen when they are left out, and returns their names
+func optionalOutputs(tool mcp.Tool) (mcp.Tool, []string) {
+	if explicitOutputs[tool.Name] {
+		return tool, nil
+	}
+	var outputs, required []string
+	for _, key := range tool.InputSchema.Required {
+		if isOutputKey(key) {
+			outputs = append(outputs, key)
+		} else {
+			required = append(required, key)
+		}
+	}
+	if len(outputs) == 0 {
+		return tool, nil
+	}
+
+	properties := make(map[string]interface{}, len(tool.InputSchema.Properties))
+	for name, property := range tool.InputSchema.Properties {
+		properties[name] = property
+		schema, ok := property.(map[string]interface{})
+		if !ok || !isOutputKey(name) {
+			continue
+		}
+		copied := make(map[string]interface{}, len(schema))
+		for k, v := range schema {
+			copied[k] = v
+		}
+		description, _ := copied["description"].(string)
+		copied["description"] = strings.TrimSpace(description + " (optional: omit for a numbered name like <input>_" + outputLabel(tool.Name) + "_001 next to the input, or in the active project's outputs)")
+		properties[name] = copied
+	}
+	tool.InputSchema.Properties = properties
+	tool.InputSchema.Required = required
+	return tool, outputs
+}
+
+// isOutputKey reports whether an argument is one optionalOutputs fills in
+func isOutputKey(key string) bool {
+	for _, k := range outputKeys {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
+
+// withOutputPaths wraps a tool handler so outputs that were left out get a
+// new numbered path: in the active project's outputs (or transcripts)
+// folder, otherwise next to the input. The paths are reserved before the
+// tool runs, released if it fails and listed in the result.
+func (s *MCPServer) withOutputPaths(tool string, outputs []string, handler func(map[string]interface{}) (*mcp.CallToolResult, error)) func(map[string]interface{}) (*mcp.CallToolResult, error) {
+	return func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		resolved, chosen, err := s.chooseOutputs(tool, outputs, arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		result, err := handler(resolved)
+		finishOutputs(result, err, chosen)
+		return result, err
+	}
+}
+
+// chooseOutputs picks paths for the outputs missing from arguments and
+// returns a copy of the arguments with them filled in
+func (s *MCPServer) chooseOutputs(tool string, outputs []string, arguments map[string]interface{}) (map[string]interface{}, []chosenOutput, error) {
+	if workspaceTools[tool] {
+		return arguments, nil, nil
+	}
+	var project *workspace.Project
+	if s.workspace != nil {
+		var err error
+		if project, err = s.workspace.Active(); err != nil {
+			return nil, nil, fmt.Errorf("active project is unavailable: %v (use set_active_project with an empty name to leave it)", err)
+		}
+	}
+	// The transcript is worth keeping in the project even though it's optional
+	if project != nil && tool == "extract_transcript" {
+		outputs = append(outputs, "outputPath")
+	}
+
+	var missing []string
+	for _, key := range outputs {
+		if v, _ := arguments[key].(string); v == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return arguments, nil, nil
+	}
+
+	input := primaryInput(arguments)
+	resolved := make(map[string]interface{}, len(arguments)+len(missing))
+	for k, v := range arguments {
+		resolved[k] = v
+	}
+	var chosen []chosenOutput
+	for _, key := range missing {
+		ext := ""
+		if key != "outputDir" {
+			ext = outputExtension(tool, arguments, input)
+		}
+		var path string
+		var err error
+		if project != nil {
+			path, err = project.OutputPath(projectFolder(tool), input, outputLabel(tool), ext, time.Now())
+		} else {
+			dir := "."
+			if input != "" && !remote.IsRemote(input) {
+				dir = filepath.Dir(input)
+			}
+			if dir, err = filepath.Abs(dir); err == nil {
+				path, err = workspace.UniquePath(dir, workspace.BaseName(input)+"_"+outputLabel(tool), ext)
+			}
+		}
+		if err != nil {
+			releaseOutputs(chosen)
+			return nil, nil, fmt.Errorf("failed to choose %s: %w", key, err)
+		}
+		resolved[key] = path
+		chosen = append(chosen, chosenOutput{key: key, path: path})
+	}
+	return resolved, chosen, nil
+}
+
+// finishOutputs releases the chosen paths when the tool failed, or lists
+// them in its result
+func finishOutputs(result *mcp.CallToolResult, err error, chosen []chosenOutput) {
+	if len(chosen) == 0 {
+		return
+	}
+	if err != nil || result == nil || result.IsError {
+		releaseOutputs(chosen)
+		return
+	}
+	for i, content := range result.Content {
+		text, ok := mcp.AsTextContent(content)
+		if !ok {
+			continue
+		}
+		var b strings.Builder
+		b.WriteString(strings.TrimRight(text.Text, "\n"))
+		b.WriteString("\n\nOUTPUT PATHS:\n")
+		for _, c := range chosen {
+			b.WriteString(fmt.Sprintf("- %s: %s\n", c.key, c.path))
+		}
+		result.Content[i] = mcp.NewToolResultText(b.String()).Content[0]
+		return
+	}
+}
+
+// releaseOutputs removes the paths reserved for a tool that failed, with
+// anything it wrote there. FFmpeg keeps a failed output that existed
+// before it ran, so a partial render into a reserved path is removed here.
+func releaseOutputs(chosen []chosenOutput) {
+	for _, c := range chosen {
+		os.RemoveAll(c.path)
+	}
+}
+
+// outputPaths maps each chosen output argument to its path
+func outputPaths(chosen []chosenOutput) map[string]string {
+	paths := make(map[string]string, len(chosen))
+	for _, c := range chosen {
+		paths[c.key] = c.path
+	}
+	return paths
+}
+
+// outputLabel says what a tool did
+func outputLabel(tool string) string {
+	if label, ok := outputLabels[tool]; ok {
+		return label
+	}
+	label := tool
+	for _, verb := range []string{"apply_", "add_", "generate_", "create_", "extract_", "adjust_"} {
+		label = strings.TrimPrefix(label, verb)
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(label, "_video"), "_effect")
+}
+
+// primaryInput returns the tool's main input path, if any
+func primaryInput(arguments map[string]interface{}) string {
+	for _, key := range inputArguments {
+		switch v := arguments[key].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case []interface{}:
+			for _, item := range v {
+				if path, ok := item.(string); ok && path != "" {
+					return path
+				}
+				// Lists of clips such as create_video_from_images' images
+				if clip, ok := item.(map[string]interface{}); ok {
+					if path, ok := clip["image"].(string); ok && path != "" {
+						return path
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// outputExtension picks the extension for a chosen output: the tool's only
+// format, then the format argument, then the tool's own kind of output,
+// then the input's extension, then .mp4
+func outputExtension(tool string, arguments map[string]interface{}, input string) string {
+	if ext, ok := fixedExtensions[tool]; ok {
+		return ext
+	}
+	if format, ok := arguments["format"].(string); ok && formatName.MatchString(format) {
+		if format == "text" {
+			return ".txt"
+		}
+		return "." + format
+	}
+	// Scopes over time are a video, not an image
+	if tool == "generate_scopes" && arguments["overTime"] == true {
+		return ".mp4"
+	}
+	if ext, ok := outputExtensions[tool]; ok {
+		return ext
+	}
+	if ext := filepath.Ext(input); fileExtension.MatchString(ext) {
+		return strings.ToLower(ext)
+	}
+	return ".mp4"
+}
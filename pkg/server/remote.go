@@ -0,0 +1,270 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/remote"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// textArguments hold free text that may happen to be a URL
+var textArguments = map[string]bool{
+	"text": true, "title": true, "script": true, "description": true, "prompt": true,
+	"notes": true, "label": true, "heading": true, "markup": true, "query": true,
+	"searchText": true, "textToRemove": true, "subscribeLabel": true, "name": true, "lines": true,
+}
+
+// backgroundOutputTools return while their output is still being written,
+// so it can't be staged and uploaded when they return
+var backgroundOutputTools = map[string]bool{"start_recording": true}
+
+// remoteCall stages the remote paths of one tool call
+type remoteCall struct {
+	tool     string
+	store    *remote.Store
+	tempDir  string
+	staging  string            // Output directory, created on first use
+	inputs   map[string]string // URI -> cached file
+	outputs  []remoteOutput
+	sidecars []remoteOutput // Files written beside a staged output
+}
+
+// remoteOutput is an output argument rendered locally and uploaded after
+type remoteOutput struct {
+	uri   string
+	local string
+	dir   bool
+}
+
+// withRemotePaths wraps a tool handler so path arguments may be http(s)://,
+// s3:// or gs:// URIs. Inputs are downloaded into the remote cache; outputs
+// are written to a staging directory and uploaded, with any sidecar files
+// written beside them, once the tool succeeds.
+// Local paths in the result are replaced with the URIs they stand for.
+func (s *MCPServer) withRemotePaths(tool string, handler func(map[string]interface{}) (*mcp.CallToolResult, error)) func(map[string]interface{}) (*mcp.CallToolResult, error) {
+	return func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		ctx := context.Background()
+		call := &remoteCall{tool: tool, store: s.remoteStore, tempDir: s.config.TempPath(), inputs: map[string]string{}}
+		staged, err := call.stage(ctx, "", arguments)
+		if err != nil {
+			call.cleanup()
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if len(call.inputs) == 0 && len(call.outputs) == 0 {
+			return handler(arguments)
+		}
+
+		result, err := handler(staged.(map[string]interface{}))
+		if err != nil || result == nil || result.IsError {
+			call.cleanup()
+			if result != nil {
+				call.rewrite(result, nil)
+			}
+			return result, err
+		}
+
+		uploaded, err := call.upload(ctx)
+		if err != nil {
+			// Keep the staged outputs so the render isn't lost
+			return mcp.NewToolResultError(fmt.Sprintf("%v (output kept in %s)", err, call.staging)), nil
+		}
+		call.cleanup()
+		call.rewrite(result, uploaded)
+		return result, nil
+	}
+}
+
+// stage replaces remote URIs in an argument value with local paths,
+// returning a copy so the caller's arguments are left alone
+func (c *remoteCall) stage(ctx context.Context, key string, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			staged, err := c.stage(ctx, k, item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = staged
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			staged, err := c.stage(ctx, key, item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = staged
+		}
+		return out, nil
+	case string:
+		if !isPathArgument(key) || !remote.IsRemote(v) {
+			return v, nil
+		}
+		if isOutputArgument(key) {
+			return c.stageOutput(key, v)
+		}
+		if local, ok := c.inputs[v]; ok {
+			return local, nil
+		}
+		local, err := c.store.Fetch(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		c.inputs[v] = local
+		return local, nil
+	}
+	return value, nil
+}
+
+// stageOutput returns a local path for an output URI. Each output gets its
+// own directory so files with the same name don't collide.
+func (c *remoteCall) stageOutput(key, uri string) (string, error) {
+	if backgroundOutputTools[c.tool] {
+		return "", fmt.Errorf("%s writes %s in the background and can't upload it; use a local path and upload the file once it's finished", c.tool, key)
+	}
+	if c.staging == "" {
+		dir, err := os.MkdirTemp(c.tempDir, "mcp-video-upload-")
+		if err != nil {
+			return "", fmt.Errorf("failed to create staging directory: %w", err)
+		}
+		c.staging = dir
+	}
+
+	out := remoteOutput{uri: uri, dir: strings.HasSuffix(key, "Dir") || key == "folder" || strings.HasSuffix(uri, "/")}
+	slot := filepath.Join(c.staging, strconv.Itoa(len(c.outputs)))
+	if out.dir {
+		out.local = slot
+	} else {
+		out.local = filepath.Join(slot, path.Base(uri))
+	}
+	// Tools expect an output's directory to exist
+	if err := os.MkdirAll(slot, 0755); err != nil {
+		return "", err
+	}
+	c.outputs = append(c.outputs, out)
+	return out.local, nil
+}
+
+// upload sends every output the tool wrote to its URI. Outputs the tool
+// didn't write, such as optional files it skipped, are ignored.
+func (c *remoteCall) upload(ctx context.Context) ([]string, error) {
+	var uploaded []string
+	for _, out := range c.outputs {
+		if out.dir {
+			uris, err := c.store.UploadDir(ctx, out.local, out.uri)
+			if err != nil {
+				return nil, err
+			}
+			uploaded = append(uploaded, uris...)
+			continue
+		}
+		if _, err := os.Stat(out.local); err != nil {
+			continue
+		}
+		if err := c.store.Upload(ctx, out.local, out.uri); err != nil {
+			return nil, err
+		}
+		uploaded = append(uploaded, out.uri)
+
+		sidecars, err := c.uploadSidecars(ctx, out)
+		if err != nil {
+			return nil, err
+		}
+		uploaded = append(uploaded, sidecars...)
+	}
+	return uploaded, nil
+}
+
+// uploadSidecars sends the other files a tool wrote beside a file output,
+// such as checksums or caption files, beside its URI. Each output is staged
+// in its own directory, so anything else there came with it.
+func (c *remoteCall) uploadSidecars(ctx context.Context, out remoteOutput) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(out.local))
+	if err != nil {
+		return nil, err
+	}
+	var uploaded []string
+	prefix := strings.TrimSuffix(out.uri, path.Base(out.uri))
+	for _, entry := range entries {
+		local := filepath.Join(filepath.Dir(out.local), entry.Name())
+		if entry.IsDir() || local == out.local {
+			continue
+		}
+		sidecar := remoteOutput{uri: prefix + entry.Name(), local: local}
+		if err := c.store.Upload(ctx, sidecar.local, sidecar.uri); err != nil {
+			return nil, err
+		}
+		c.sidecars = append(c.sidecars, sidecar)
+		uploaded = append(uploaded, sidecar.uri)
+	}
+	return uploaded, nil
+}
+
+// rewrite replaces staged and cached paths in the result text with their
+// URIs and lists what was uploaded
+func (c *remoteCall) rewrite(result *mcp.CallToolResult, uploaded []string) {
+	replacements := map[string]string{}
+	for uri, local := range c.inputs {
+		replacements[local] = uri
+	}
+	for _, outputs := range [][]remoteOutput{c.outputs, c.sidecars} {
+		for _, out := range outputs {
+			replacements[out.local] = out.uri
+		}
+	}
+	// Longest first, so a directory doesn't shadow the files in it
+	locals := make([]string, 0, len(replacements))
+	for local := range replacements {
+		locals = append(locals, local)
+	}
+	sort.Slice(locals, func(i, j int) bool { return len(locals[i]) > len(locals[j]) })
+
+	for i, content := range result.Content {
+		text, ok := mcp.AsTextContent(content)
+		if !ok {
+			continue
+		}
+		rewritten := text.Text
+		for _, local := range locals {
+			rewritten = strings.ReplaceAll(rewritten, local, replacements[local])
+		}
+		if i == 0 && len(uploaded) > 0 {
+			rewritten = strings.TrimRight(rewritten, "\n") + "\n\nUPLOADED:\n- " + strings.Join(uploaded, "\n- ") + "\n"
+		}
+		result.Content[i] = mcp.NewToolResultText(rewritten).Content[0]
+	}
+}
+
+// cleanup removes the staging directory. Cached inputs are kept.
+func (c *remoteCall) cleanup() {
+	if c.staging != "" {
+		os.RemoveAll(c.staging)
+	}
+}
+
+// isPathArgument reports whether an argument may hold a file path, as
+// opposed to free text or a service URL
+func isPathArgument(key string) bool {
+	lower := strings.ToLower(key)
+	for _, suffix := range []string{"url", "prefix", "endpoint"} {
+		if strings.HasSuffix(lower, suffix) {
+			return false
+		}
+	}
+	return !textArguments[key]
+}
+
+// isOutputArgument reports whether an argument names a file or directory
+// the tool writes
+func isOutputArgument(key string) bool {
+	return strings.Contains(strings.ToLower(key), "output") || key == "exportPath" || key == "stemsDir" || key == "folder"
+}
@@ -4,17 +4,39 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/chandler-mayo/mcp-video-editor/pkg/apiclient"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/audio"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/captions"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/capture"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/chapters"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/diagrams"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/dubbing"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/elements"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/highlights"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/imagegen"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/library"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/meeting"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/moderation"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/podcast"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/poster"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/multicam"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/multitake"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/reframe"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/remote"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/shorts"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/stock"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/text"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/timeline"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/translate"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/usage"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/vision"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/workspace"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/youtube"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/visual"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -41,23 +63,58 @@ type MCPServer struct {
 	transitions      *visual.Transitions
 	elements         *elements.Operations
 	transcriptOps    *transcript.Operations
+	translator       *translate.Translator
 	timeline         *timeline.Manager
+	usage            *usage.Tracker
 	multitake        *multitake.Manager
+	multicamOps      *multicam.Operations
 	visionAnalyzer   *vision.Analyzer
 	diagramGen       *diagrams.Generator
 	ttsOps           *audio.TTSOperations
 	audioReplacement *audio.ReplacementOperations
 	audioOps         *audio.Operations
-	tools            []mcp.Tool // Registry of all registered tools
+	meetingPipeline  *meeting.Pipeline
+	captionPipeline  *captions.Pipeline
+	dubPipeline      *dubbing.Pipeline
+	chapterGen       *chapters.Generator
+	highlightFinder  *highlights.Finder
+	shortCreator     *shorts.Creator
+	posterDesigner   *poster.Designer
+	podcastExporter  *podcast.Exporter
+	reframer         *reframe.Reframer
+	moderator        *moderation.Moderator
+	imageGen         *imagegen.Generator
+	stockClient      *stock.Client
+	mediaIndexer     *library.Indexer
+	remoteStore      *remote.Store // Cache and uploads for http(s)://, s3:// and gs:// paths
+	youtubeUploader  *youtube.Uploader
+	workspace        *workspace.Manager
+	recorder         *capture.Recorder
+	streamer         *capture.Streamer
+	tools            []mcp.Tool          // Registry of all registered tools
+	defaultOutputs   map[string][]string // Tool -> output arguments chosen when left out
 }
 
 // NewMCPServer creates a new MCP server instance
 func NewMCPServer(cfg *config.Config) (*MCPServer, error) {
-	// Initialize FFmpeg
-	ffmpegMgr, err := ffmpeg.NewManager(cfg.FFmpegPath, cfg.FFprobePath)
+	// Initialize FFmpeg, downloading a static build first if allowed and needed
+	ffmpegPath, ffprobePath := cfg.FFmpegPath, cfg.FFprobePath
+	if cfg.FFmpegAutoDownload && ffmpegPath == "" && !ffmpeg.OnPath() {
+		bins, err := ffmpeg.Download(context.Background(), cfg.FFmpegDownloadPath(), cfg.FFmpegChecksums)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download FFmpeg: %w", err)
+		}
+		ffmpegPath, ffprobePath = bins.FFmpeg, bins.FFprobe
+	}
+	ffmpegMgr, err := ffmpeg.NewManager(ffmpegPath, ffprobePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize FFmpeg: %w", err)
 	}
+	ffmpegMgr.SetResourceLimits(resourceLimitsFromConfig(cfg))
+	apiclient.Configure(apiPolicyFromConfig(cfg))
+	scratch.SetBase(cfg.TempPath())
+	// Nothing runs yet, so old temp directories were left by a crash
+	scratch.Clean(scratch.StaleAge, false)
 
 	// Create operations handlers
 	videoOps := video.NewOperations(ffmpegMgr)
@@ -67,16 +124,39 @@ func NewMCPServer(cfg *config.Config) (*MCPServer, error) {
 	transitions := visual.NewTransitions(ffmpegMgr)
 	elementsOps := elements.NewOperations(ffmpegMgr)
 	transcriptOps := transcript.NewOperations(cfg.OpenAIKey, ffmpegMgr)
+	translator := translate.NewTranslator(cfg)
 	timelineMgr := timeline.NewManager("")
 	multitakeMgr := multitake.NewManager("")
-	visionAnalyzer := vision.NewAnalyzer(cfg.OpenAIKey, videoOps, ffmpegMgr)
+	visionAnalyzer := vision.NewAnalyzer(cfg, videoOps, ffmpegMgr)
 	diagramGen := diagrams.NewGenerator()
+	imageGen := imagegen.NewGenerator(cfg)
+	stockClient := stock.NewClient(cfg)
+	remoteStore := remote.NewStore(cfg)
+	youtubeUploader := youtube.NewUploader(cfg)
+	workspaceMgr := workspace.NewManager(cfg)
+	recorder := capture.NewRecorder(ffmpegMgr)
+	streamer := capture.NewStreamer(ffmpegMgr)
 
 	// Create audio operations
 	ttsOps := audio.NewTTSOperations(cfg.ElevenLabsKey, cfg)
+	ttsOps.SetFFmpeg(ffmpegMgr)
 	spliceOps := audio.NewSpliceOperations(ffmpegMgr)
 	audioReplacement := audio.NewReplacementOperations(ttsOps, spliceOps, transcriptOps, videoOps)
 	audioOps := audio.NewOperations(ffmpegMgr)
+	multicamOps := multicam.NewOperations(ffmpegMgr, audioOps)
+
+	// Create workflow pipelines
+	meetingPipeline := meeting.NewPipeline(cfg.OpenAIKey, ffmpegMgr, videoOps, transcriptOps)
+	captionPipeline := captions.NewPipeline(videoOps, textOps, transcriptOps)
+	dubPipeline := dubbing.NewPipeline(ffmpegMgr, videoOps, transcriptOps, translator, ttsOps)
+	chapterGen := chapters.NewGenerator(cfg, ffmpegMgr, videoOps, transcriptOps, visionAnalyzer)
+	highlightFinder := highlights.NewFinder(cfg, ffmpegMgr, videoOps, transcriptOps)
+	shortCreator := shorts.NewCreator(ffmpegMgr, videoOps, transcriptOps, visionAnalyzer)
+	posterDesigner := poster.NewDesigner(videoOps, textOps, elementsOps)
+	podcastExporter := podcast.NewExporter(ffmpegMgr, videoOps)
+	reframer := reframe.NewReframer(ffmpegMgr, videoOps, visionAnalyzer)
+	moderator := moderation.NewModerator(videoOps, transcriptOps, visionAnalyzer)
+	mediaIndexer := library.NewIndexer(videoOps, transcriptOps, visionAnalyzer)
 
 	// Create MCP server
 	s := server.NewMCPServer(
@@ -95,13 +175,39 @@ func NewMCPServer(cfg *config.Config) (*MCPServer, error) {
 		transitions:      transitions,
 		elements:         elementsOps,
 		transcriptOps:    transcriptOps,
+		translator:       translator,
 		timeline:         timelineMgr,
+		usage:            usage.Default(),
 		multitake:        multitakeMgr,
+		multicamOps:      multicamOps,
 		visionAnalyzer:   visionAnalyzer,
 		diagramGen:       diagramGen,
 		ttsOps:           ttsOps,
 		audioReplacement: audioReplacement,
 		audioOps:         audioOps,
+		meetingPipeline:  meetingPipeline,
+		captionPipeline:  captionPipeline,
+		dubPipeline:      dubPipeline,
+		chapterGen:       chapterGen,
+		highlightFinder:  highlightFinder,
+		shortCreator:     shortCreator,
+		posterDesigner:   posterDesigner,
+		podcastExporter:  podcastExporter,
+		reframer:         reframer,
+		moderator:        moderator,
+		imageGen:         imageGen,
+		stockClient:      stockClient,
+		mediaIndexer:     mediaIndexer,
+		remoteStore:      remoteStore,
+		youtubeUploader:  youtubeUploader,
+		workspace:        workspaceMgr,
+		recorder:         recorder,
+		streamer:         streamer,
+	}
+
+	// Pick up the project that was active when the server last ran
+	if project, err := workspaceMgr.Active(); err == nil && project != nil {
+		srv.applyProject(project)
 	}
 
 	// Register all tools
@@ -119,23 +225,38 @@ func (s *MCPServer) Start(ctx context.Context) error {
 func (s *MCPServer) registerTools() {
 	// Video operations
 	s.registerGetVideoInfo()
+	s.registerGetMetadata()
+	s.registerSetMetadata()
 	s.registerTrimVideo()
 	s.registerConcatenateVideos()
 	s.registerResizeVideo()
 	s.registerExtractAudio()
+	s.registerMuteVideo()
+	s.registerReplaceAudio()
+	s.registerSyncAudioVideo()
+	s.registerFitVoiceover()
+	s.registerAddAudioTrack()
+	s.registerMuxAudioTracks()
 	s.registerTranscodeVideo()
 
 	// Visual effects
 	s.registerApplyBlur()
 	s.registerApplyColorGrade()
 	s.registerApplyChromaKey()
+	s.registerReplaceBackground()
 	s.registerApplyVignette()
 	s.registerApplySharpen()
+	s.registerDenoiseVideo()
+	s.registerAddFilmGrain()
+	s.registerApplyStylePreset()
+	s.registerApplyDuotone()
+	s.registerApplySelectiveColor()
 
 	// Composite operations
 	s.registerCreatePictureInPicture()
 	s.registerCreateSplitScreen()
 	s.registerCreateSideBySide()
+	s.registerOverlayVideo()
 
 	// Transitions
 	s.registerAddTransition()
@@ -144,7 +265,18 @@ func (s *MCPServer) registerTools() {
 	// Text operations
 	s.registerAddTextOverlay()
 	s.registerAddAnimatedText()
+	s.registerAddLowerThird()
+	s.registerAddCountdown()
+	s.registerAddProgressBar()
+	s.registerListFonts()
+	s.registerApplyTemplate()
+	s.registerCreateEndScreen()
 	s.registerBurnSubtitles()
+	s.registerAttachSubtitles()
+	s.registerExtractSubtitles()
+	s.registerConvertSubtitles()
+	s.registerShiftSubtitles()
+	s.registerScaleSubtitles()
 
 	// Additional video operations
 	s.registerExtractFrames()
@@ -152,6 +284,24 @@ func (s *MCPServer) registerTools() {
 	s.registerConvertVideo()
 	s.registerTranscodeForWeb()
 	s.registerCreateVideoFromImages()
+	s.registerGenerateThumbnail()
+	s.registerExportStill()
+	s.registerCreateThumbnail()
+	s.registerGenerateContactSheet()
+	s.registerGenerateSpriteSheet()
+	s.registerSmartSpeed()
+	s.registerPrepareScreenRecording()
+	s.registerCompareQuality()
+	s.registerAnalyzeBitrate()
+	s.registerExportMultiAspect()
+	s.registerReframeVideo()
+	s.registerFindDuplicateMedia()
+	s.registerValidateMedia()
+	s.registerToneMap()
+	s.registerDetectInterlacing()
+	s.registerDeinterlaceVideo()
+	s.registerGenerateScopes()
+	s.registerAddIntroOutro()
 
 	// Additional audio operations
 	s.registerGetAudioStats()
@@ -159,6 +309,7 @@ func (s *MCPServer) registerTools() {
 	// Audio editing operations
 	s.registerTrimAudio()
 	s.registerConcatenateAudio()
+	s.registerCrossfadeAudio()
 	s.registerAdjustAudioVolume()
 	s.registerNormalizeAudio()
 	s.registerFadeAudio()
@@ -169,35 +320,56 @@ func (s *MCPServer) registerTools() {
 	s.registerSplitAudio()
 	s.registerReverseAudio()
 	s.registerExtractAudioChannel()
+	s.registerGenerateWaveformImage()
+	s.registerCreateAudiogram()
+	s.registerSeparateStems()
+	s.registerPitchShift()
+	s.registerCleanVoice()
+	s.registerExportPodcast()
+	s.registerGenerateSilence()
+	s.registerGenerateTone()
 
 	// Audio word replacement
 	s.registerReplaceSpokenWord()
+	s.registerReplaceSpokenWords()
 	s.registerCloneVoiceFromAudio()
 	s.registerGenerateSpeech()
+	s.registerGenerateDialogue()
 	s.registerGetWordTimestamps()
 
 	// Voice management
 	s.registerListCachedVoices()
 	s.registerClearCachedVoice()
 	s.registerClearAllCachedVoices()
+	s.registerExportVoiceCache()
+	s.registerImportVoiceCache()
 
 	// Config management
 	s.registerGetConfig()
 	s.registerSetConfig()
 	s.registerResetConfig()
+	s.registerGetSystemStatus()
+	s.registerGetFFmpegCapabilities()
+	s.registerRunFFmpeg()
+	s.registerGetAIUsage()
+	s.registerCleanupTemp()
 
 	// Additional visual effects
 	s.registerApplyKenBurns()
+	s.registerCreateSlideshow()
+	s.registerPunchIn()
 
 	// Visual elements
 	s.registerAddImageOverlay()
 	s.registerAddShape()
+	s.registerAddWatermark()
 
 	// Transcript operations
 	s.registerExtractTranscript()
 	s.registerFindInTranscript()
 	s.registerRemoveByTranscript()
 	s.registerTrimToScript()
+	s.registerTranslateTranscript()
 
 	// Timeline operations
 	s.registerCreateTimeline()
@@ -219,25 +391,70 @@ func (s *MCPServer) registerTools() {
 	s.registerCleanupProjectTemp()
 	s.registerExportFinalVideo()
 
+	// Multicam operations
+	s.registerAlignCameras()
+	s.registerSwitchCameras()
+
 	// Video vision analysis
 	s.registerAnalyzeVideoContent()
 	s.registerCompareVideoFrames()
 	s.registerDescribeScene()
+	s.registerClearVisionCache()
 	s.registerFindObjectsInVideo()
 	s.registerSearchVisualContent()
+	s.registerExtractOnscreenText()
+	s.registerFaceTools()
+	s.registerTrackingTools()
 
 	// Diagram generation
 	s.registerGenerateTimeline()
 	s.registerGenerateFlowchart()
 	s.registerGenerateOrgChart()
 	s.registerGenerateMindMap()
+
+	// Image generation
+	s.registerGenerateImage()
+
+	// Stock media
+	s.registerSearchStockMedia()
+
+	// Media library
+	s.registerMediaLibrary()
+
+	// Workflow pipelines
+	s.registerProcessMeetingRecording()
+	s.registerGenerateChapters()
+	s.registerEmbedChapters()
+	s.registerExtractChapters()
+	s.registerFindHighlights()
+	s.registerCreateShort()
+	s.registerModerateVideo()
+	s.registerAutoCaption()
+	s.registerKaraokeCaptions()
+	s.registerDubVideo()
+
+	// Publishing
+	s.registerUploadToYouTube()
+
+	// Workspace
+	s.registerWorkspaceTools()
+
+	// Screen capture and streaming
+	s.registerCaptureTools()
 }
 
 // Tool registration methods
 
 // addTool is a helper that adds a tool to both the MCP server and our internal registry
 func (s *MCPServer) addTool(tool mcp.Tool, handler func(map[string]interface{}) (*mcp.CallToolResult, error)) {
-	s.server.AddTool(tool, handler)
+	tool, outputs := optionalOutputs(tool)
+	if len(outputs) > 0 {
+		if s.defaultOutputs == nil {
+			s.defaultOutputs = map[string][]string{}
+		}
+		s.defaultOutputs[tool.Name] = outputs
+	}
+	s.server.AddTool(tool, s.withOutputPaths(tool.Name, outputs, s.withRemotePaths(tool.Name, s.withProjectPaths(tool.Name, handler))))
 	s.tools = append(s.tools, tool)
 }
 
@@ -376,7 +593,7 @@ func (s *MCPServer) registerExtractAudio() {
 func (s *MCPServer) registerTranscodeVideo() {
 	s.addTool(mcp.Tool{
 		Name:        "transcode_video",
-		Description: "Convert video to different format/codec",
+		Description: "Convert video to different format/codec, by CRF quality or at a target bitrate (two-pass ABR, constrained VBR or CBR) for platforms with strict bitrate rules",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -390,7 +607,32 @@ func (s *MCPServer) registerTranscodeVideo() {
 				},
 				"quality": map[string]interface{}{
 					"type":        "string",
-					"description": "Quality: high, medium, low",
+					"description": "Quality: high, medium, low (crf rate control)",
+				},
+				"rateControl": map[string]interface{}{
+					"type":        "string",
+					"enum":        video.RateControlModes,
+					"description": "crf: constant quality (default); abr: average bitrate; vbr: bitrate capped at maxBitrate; cbr: constant bitrate with HRD signaling. Defaults to abr when bitrate is set",
+				},
+				"bitrate": map[string]interface{}{
+					"type":        "integer",
+					"description": "Target video bitrate in kbps (required for abr, vbr and cbr)",
+				},
+				"maxBitrate": map[string]interface{}{
+					"type":        "integer",
+					"description": "Peak video bitrate in kbps for vbr (default: 1.5x bitrate)",
+				},
+				"bufferSize": map[string]interface{}{
+					"type":        "integer",
+					"description": "Rate control buffer (VBV) in kbps (default: 2x maxBitrate for vbr, bitrate for cbr)",
+				},
+				"audioBitrate": map[string]interface{}{
+					"type":        "integer",
+					"description": "Audio bitrate in kbps",
+				},
+				"twoPass": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Analyze the video in a first pass for more accurate bitrate distribution (needs bitrate; takes about twice as long)",
 				},
 			},
 			Required: []string{"input", "output"},
@@ -421,6 +663,7 @@ func (s *MCPServer) registerApplyBlur() {
 					"type":        "number",
 					"description": "Blur strength 0-10",
 				},
+				"mask": effectMaskSchema,
 			},
 			Required: []string{"input", "output"},
 		},
@@ -454,6 +697,7 @@ func (s *MCPServer) registerApplyColorGrade() {
 					"type":        "number",
 					"description": "Saturation -1 to 1",
 				},
+				"mask": effectMaskSchema,
 			},
 			Required: []string{"input", "output"},
 		},
@@ -463,7 +707,7 @@ func (s *MCPServer) registerApplyColorGrade() {
 func (s *MCPServer) registerApplyChromaKey() {
 	s.addTool(mcp.Tool{
 		Name:        "apply_chroma_key",
-		Description: "Remove green screen (chroma key)",
+		Description: "Remove green screen (chroma key) with optional despill, matte choke and feathering, and composite onto a color, image or video background or export with a transparent alpha channel",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -483,12 +727,102 @@ func (s *MCPServer) registerApplyChromaKey() {
 					"type":        "number",
 					"description": "Color similarity 0-1",
 				},
+				"blend": map[string]interface{}{
+					"type":        "number",
+					"description": "Edge blend 0-1 (default: 0.1)",
+				},
+				"despill": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Remove key color spill from the subject (default: false)",
+				},
+				"choke": map[string]interface{}{
+					"type":        "integer",
+					"description": "Shrink the matte edge by this many pixels to remove fringes, 0-10 (default: 0)",
+				},
+				"feather": map[string]interface{}{
+					"type":        "number",
+					"description": "Soften the matte edge, blur radius in pixels (default: 0)",
+				},
+				"background": map[string]interface{}{
+					"type":        "string",
+					"description": "Image or video to composite the keyed subject onto (videos loop)",
+				},
+				"backgroundColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Solid color to composite the keyed subject onto, e.g. white or 0x202020",
+				},
+				"transparent": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Keep the keyed-out area transparent instead of compositing, for overlays: VP9 with alpha for a .webm or .mkv output, ProRes 4444 for .mov (default: false)",
+				},
 			},
 			Required: []string{"input", "output"},
 		},
 	}, s.handleApplyChromaKey)
 }
 
+func (s *MCPServer) registerReplaceBackground() {
+	s.addTool(mcp.Tool{
+		Name:        "replace_background",
+		Description: "Replace a green screen background with an image or video in one render (chroma key + composite), with background scaling, blur, light wrap and despill options",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Green screen video path",
+				},
+				"background": map[string]interface{}{
+					"type":        "string",
+					"description": "Background image or video path (videos loop to cover the foreground)",
+				},
+				"output": map[string]interface{}{
+					"type":        "string",
+					"description": "Output video path",
+				},
+				"keyColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Color to key out (default: green)",
+				},
+				"similarity": map[string]interface{}{
+					"type":        "number",
+					"description": "Color similarity 0-1 (default: 0.3)",
+				},
+				"blend": map[string]interface{}{
+					"type":        "number",
+					"description": "Edge blend 0-1 (default: 0.1)",
+				},
+				"fit": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"cover", "contain", "stretch"},
+					"description": "How the background is scaled to the frame (default: cover)",
+				},
+				"backgroundBlur": map[string]interface{}{
+					"type":        "number",
+					"description": "Background blur radius, e.g. 10 for a soft depth-of-field look (default: 0)",
+				},
+				"lightWrap": map[string]interface{}{
+					"type":        "number",
+					"description": "Light wrap strength 0-1, bleeds background light over subject edges (default: 0)",
+				},
+				"despill": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Remove key color spill from the subject (default: false)",
+				},
+				"choke": map[string]interface{}{
+					"type":        "integer",
+					"description": "Shrink the matte edge by this many pixels to remove fringes, 0-10 (default: 0)",
+				},
+				"feather": map[string]interface{}{
+					"type":        "number",
+					"description": "Soften the matte edge, blur radius in pixels (default: 0)",
+				},
+			},
+			Required: []string{"input", "background", "output"},
+		},
+	}, s.handleReplaceBackground)
+}
+
 func (s *MCPServer) registerApplyVignette() {
 	s.addTool(mcp.Tool{
 		Name:        "apply_vignette",
@@ -508,6 +842,7 @@ func (s *MCPServer) registerApplyVignette() {
 					"type":        "number",
 					"description": "Intensity 0-1",
 				},
+				"mask": effectMaskSchema,
 			},
 			Required: []string{"input", "output"},
 		},
@@ -542,7 +877,7 @@ func (s *MCPServer) registerApplySharpen() {
 func (s *MCPServer) registerCreatePictureInPicture() {
 	s.addTool(mcp.Tool{
 		Name:        "create_picture_in_picture",
-		Description: "Create picture-in-picture effect",
+		Description: "Create picture-in-picture effect, static or animated with keyframed position, scale and opacity, with optional border and drop shadow",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -562,6 +897,60 @@ func (s *MCPServer) registerCreatePictureInPicture() {
 					"type":        "string",
 					"description": "Position: top-left, top-right, bottom-left, bottom-right, center",
 				},
+				"width": map[string]interface{}{
+					"type":        "integer",
+					"description": "PiP width in pixels, with height (default: 25% of the PiP video's size)",
+				},
+				"height": map[string]interface{}{
+					"type":        "integer",
+					"description": "PiP height in pixels, with width",
+				},
+				"margin": map[string]interface{}{
+					"type":        "integer",
+					"description": "Distance from the frame edge in pixels (default: 20)",
+				},
+				"borderWidth": map[string]interface{}{
+					"type":        "integer",
+					"description": "Border around the PiP in pixels (default: none)",
+				},
+				"borderColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Border color (default: white)",
+				},
+				"shadow": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Add a soft drop shadow",
+				},
+				"keyframes": map[string]interface{}{
+					"type":        "array",
+					"description": "Animate the PiP instead of placing it statically, e.g. slide in at 5s and shrink to a corner at 20s. Values are interpolated linearly and held before the first and after the last keyframe.",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"time": map[string]interface{}{
+								"type":        "number",
+								"description": "Time in seconds",
+							},
+							"x": map[string]interface{}{
+								"type":        "number",
+								"description": "Left edge as a fraction of the frame width (values outside 0-1 are off-frame)",
+							},
+							"y": map[string]interface{}{
+								"type":        "number",
+								"description": "Top edge as a fraction of the frame height",
+							},
+							"scale": map[string]interface{}{
+								"type":        "number",
+								"description": "PiP width as a fraction of the frame width",
+							},
+							"opacity": map[string]interface{}{
+								"type":        "number",
+								"description": "Opacity 0-1 (default: 1)",
+							},
+						},
+						"required": []string{"time", "x", "y", "scale"},
+					},
+				},
 			},
 			Required: []string{"mainVideo", "pipVideo", "output"},
 		},
@@ -571,7 +960,7 @@ func (s *MCPServer) registerCreatePictureInPicture() {
 func (s *MCPServer) registerCreateSplitScreen() {
 	s.addTool(mcp.Tool{
 		Name:        "create_split_screen",
-		Description: "Create split screen layout",
+		Description: "Lay videos out in a grid (fixed layouts or any rows x columns) with optional gaps, per-cell labels and a choice of which audio to keep or mix, e.g. for comparison videos",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -588,10 +977,35 @@ func (s *MCPServer) registerCreateSplitScreen() {
 				},
 				"layout": map[string]interface{}{
 					"type":        "string",
-					"description": "Layout: horizontal, vertical, grid-2x2, grid-3x3",
+					"description": "Layout: horizontal, vertical, grid-2x2, grid-3x3, or any grid-RxC such as grid-2x3 (default: horizontal)",
+				},
+				"rows": map[string]interface{}{
+					"type":        "integer",
+					"description": "Custom grid rows (1-8), with columns; overrides layout",
+				},
+				"columns": map[string]interface{}{
+					"type":        "integer",
+					"description": "Custom grid columns (1-8), with rows",
+				},
+				"gap": map[string]interface{}{
+					"type":        "integer",
+					"description": "Gap between cells and around the edge in pixels (default: 0)",
+				},
+				"borderColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Color of gaps and empty cells (default: black)",
+				},
+				"labels": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Caption for each cell in video order, e.g. [\"Before\", \"After\"]",
+				},
+				"audio": map[string]interface{}{
+					"type":        "string",
+					"description": "Audio to keep: a cell number (1 = first video), \"mix\" to mix every video's audio (all need an audio track), or \"none\" (default: 1)",
 				},
 			},
-			Required: []string{"videos", "output", "layout"},
+			Required: []string{"videos", "output"},
 		},
 	}, s.handleCreateSplitScreen)
 }
@@ -711,6 +1125,23 @@ func (s *MCPServer) registerAddTextOverlay() {
 					"type":        "number",
 					"description": "Duration in seconds",
 				},
+				"fontFile": map[string]interface{}{
+					"type":        "string",
+					"description": "Font file path or installed font name (see list_fonts); also used to measure text for wrapping",
+				},
+				"maxWidth": map[string]interface{}{
+					"type":        "number",
+					"description": "Wrap text onto multiple lines no wider than this many pixels (default: no wrapping)",
+				},
+				"lineSpacing": map[string]interface{}{
+					"type":        "number",
+					"description": "Extra pixels between lines",
+				},
+				"align": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"left", "center", "right"},
+					"description": "Alignment of lines within multi-line text (default: left; center/right need FFmpeg 6.1+)",
+				},
 			},
 			Required: []string{"input", "output", "text"},
 		},
@@ -752,6 +1183,23 @@ func (s *MCPServer) registerAddAnimatedText() {
 					"type":        "string",
 					"description": "Font color",
 				},
+				"fontFile": map[string]interface{}{
+					"type":        "string",
+					"description": "Font file path or installed font name (see list_fonts); also used to measure text for wrapping",
+				},
+				"maxWidth": map[string]interface{}{
+					"type":        "number",
+					"description": "Wrap text onto multiple lines no wider than this many pixels (default: no wrapping)",
+				},
+				"lineSpacing": map[string]interface{}{
+					"type":        "number",
+					"description": "Extra pixels between lines",
+				},
+				"align": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"left", "center", "right"},
+					"description": "Alignment of lines within multi-line text (default: left; center/right need FFmpeg 6.1+)",
+				},
 			},
 			Required: []string{"input", "output", "text", "animation"},
 		},
@@ -796,7 +1244,7 @@ func (s *MCPServer) registerBurnSubtitles() {
 func (s *MCPServer) registerExtractFrames() {
 	s.addTool(mcp.Tool{
 		Name:        "extract_frames",
-		Description: "Extract frames from video as images",
+		Description: "Extract frames from video as an image sequence, with custom zero-padded names (e.g. plate.####), PNG/TIFF/EXR with alpha, and an optional frames.json manifest mapping frame numbers to source timestamps for VFX round-trips",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -814,7 +1262,7 @@ func (s *MCPServer) registerExtractFrames() {
 				},
 				"format": map[string]interface{}{
 					"type":        "string",
-					"description": "Output format: jpg, png (default: jpg)",
+					"description": "Output format: jpg, png, tiff, exr (32-bit float) (default: jpg)",
 				},
 				"startTime": map[string]interface{}{
 					"type":        "number",
@@ -824,6 +1272,22 @@ func (s *MCPServer) registerExtractFrames() {
 					"type":        "number",
 					"description": "Duration in seconds",
 				},
+				"namePattern": map[string]interface{}{
+					"type":        "string",
+					"description": "File name without extension; a run of # or %04d is the zero-padded frame number, e.g. shot010_plate.#### (default: frame_####)",
+				},
+				"startNumber": map[string]interface{}{
+					"type":        "number",
+					"description": "Number of the first frame (default: 1; VFX plates often start at 1001)",
+				},
+				"alpha": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Keep the alpha channel of transparent sources such as ProRes 4444 or WebM alpha; needs png, tiff or exr (default: false)",
+				},
+				"manifest": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Write frames.json mapping each frame number and file to its source timestamp and frame (default: false)",
+				},
 			},
 			Required: []string{"input", "outputDir"},
 		},
@@ -858,7 +1322,7 @@ func (s *MCPServer) registerAdjustSpeed() {
 func (s *MCPServer) registerConvertVideo() {
 	s.addTool(mcp.Tool{
 		Name:        "convert_video",
-		Description: "Convert video to different format with codec and quality options",
+		Description: "Convert video to different format with codec and quality options, including HEVC and AV1 delivery (HDR10/HLG kept), VP9 and ProRes 4444 with alpha, ProRes/DNxHR intermediates for NLE handoff, and lossless FFV1/H.264 archival masters",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -868,7 +1332,7 @@ func (s *MCPServer) registerConvertVideo() {
 				},
 				"output": map[string]interface{}{
 					"type":        "string",
-					"description": "Output video file path",
+					"description": "Output video file path (.mov, .mxf or .mkv for ProRes/DNxHR; .webm for vp9_alpha; .mkv for archival)",
 				},
 				"format": map[string]interface{}{
 					"type":        "string",
@@ -878,9 +1342,14 @@ func (s *MCPServer) registerConvertVideo() {
 					"type":        "string",
 					"description": "Video codec: h264, vp9, mpeg4",
 				},
+				"codecPreset": map[string]interface{}{
+					"type":        "string",
+					"enum":        video.CodecPresetNames(),
+					"description": "Complete encoder setup instead of videoCodec: h264; hevc (libx265) and av1 (SVT-AV1), which keep HDR10/HLG metadata; vp9_alpha (.webm) and prores_4444 (.mov), which keep transparency; prores_proxy to prores_4444 and dnxhr_lb to dnxhr_444 intermediates with PCM audio; ffv1 and x264_lossless archival masters (.mkv, PCM audio, framemd5 and SHA-256 checksum sidecars)",
+				},
 				"quality": map[string]interface{}{
 					"type":        "string",
-					"description": "Quality: high, medium, low",
+					"description": "Quality: high, medium, low, or archival for a lossless FFV1 master in .mkv with checksums",
 				},
 			},
 			Required: []string{"input", "output"},
@@ -954,6 +1423,18 @@ func (s *MCPServer) registerSetConfig() {
 					"type":        "string",
 					"description": "Path to FFprobe binary",
 				},
+				"ffmpegAutoDownload": map[string]interface{}{
+					"type":        "boolean",
+					"description": "At startup, download a pinned static FFmpeg and ffprobe (verified by SHA-256) when ffmpeg isn't on PATH and ffmpegPath is unset (default: false)",
+				},
+				"ffmpegDownloadDir": map[string]interface{}{
+					"type":        "string",
+					"description": "Where the downloaded FFmpeg is installed (default: <user cache>/mcp-video-editor/ffmpeg)",
+				},
+				"ffmpegChecksums": map[string]interface{}{
+					"type":        "object",
+					"description": "SHA-256 sums of FFmpeg download archives by file name, e.g. {\"ffmpeg-6.1-linux-64.zip\": \"...\"}; archives without one are refused",
+				},
 				"defaultQuality": map[string]interface{}{
 					"type":        "string",
 					"description": "Default quality: high, medium, low",
@@ -962,6 +1443,127 @@ func (s *MCPServer) registerSetConfig() {
 					"type":        "string",
 					"description": "Temporary directory path",
 				},
+				"ttsProvider": map[string]interface{}{
+					"type":        "string",
+					"enum":        audio.TTSProviders,
+					"description": "Default text-to-speech provider",
+				},
+				"piperModel": map[string]interface{}{
+					"type":        "string",
+					"description": "Default Piper voice model (.onnx) for local TTS",
+				},
+				"voiceCacheDir": map[string]interface{}{
+					"type":        "string",
+					"description": "Directory for the voice clone cache (voices.json), e.g. a synced folder shared between machines. Default: kept in the config file",
+				},
+				"pronunciations": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": map[string]interface{}{"type": "string"},
+					"description":          "TTS pronunciation dictionary applied before synthesis: word -> respelling (e.g. \"nginx\": \"engine x\") or /ipa/",
+				},
+				"visionProvider": map[string]interface{}{
+					"type":        "string",
+					"enum":        vision.Providers,
+					"description": "Provider for frame analysis tools. Default: the first of OpenAI, Claude or Gemini with an API key; ollama runs local models such as LLaVA",
+				},
+				"visionModel": map[string]interface{}{
+					"type":        "string",
+					"description": "Vision model (defaults: gpt-4o, claude-sonnet-4-5, gemini-2.5-flash, llava)",
+				},
+				"geminiKey": map[string]interface{}{
+					"type":        "string",
+					"description": "Google Gemini API key",
+				},
+				"stabilityKey": map[string]interface{}{
+					"type":        "string",
+					"description": "Stability AI API key for Stable Diffusion images",
+				},
+				"pexelsKey": map[string]interface{}{
+					"type":        "string",
+					"description": "Pexels API key for search_stock_media",
+				},
+				"pixabayKey": map[string]interface{}{
+					"type":        "string",
+					"description": "Pixabay API key for search_stock_media",
+				},
+				"ollamaUrl": map[string]interface{}{
+					"type":        "string",
+					"description": "Ollama server URL (default: http://localhost:11434)",
+				},
+				"visionWorkers": map[string]interface{}{
+					"type":        "number",
+					"description": "Frames the vision tools analyze at once (default: 4). Lower it for local Ollama models or tight rate limits",
+				},
+				"apiMaxRetries": map[string]interface{}{
+					"type":        "number",
+					"description": "Retries for OpenAI, vision and ElevenLabs calls after 429/5xx or network errors (default: 3, -1 disables)",
+				},
+				"apiRetryDelay": map[string]interface{}{
+					"type":        "number",
+					"description": "First retry delay in seconds, doubled per retry with jitter; Retry-After from the API wins when longer (default: 1)",
+				},
+				"apiRateLimits": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": map[string]interface{}{"type": "number"},
+					"description":          "Requests per minute by provider: openai, vision, elevenlabs, stability, pexels, pixabay, youtube (e.g. {\"vision\": 20}). Omitted providers are unlimited",
+				},
+				"apiBudgets": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": map[string]interface{}{"type": "number"},
+					"description":          "Most requests per server session by provider: openai, vision, elevenlabs, stability, pexels, pixabay, youtube. Calls past the budget fail instead of being sent",
+				},
+				"youtubeClientId": map[string]interface{}{
+					"type":        "string",
+					"description": "OAuth client ID for upload_to_youtube (or YOUTUBE_CLIENT_ID)",
+				},
+				"youtubeClientSecret": map[string]interface{}{
+					"type":        "string",
+					"description": "OAuth client secret for upload_to_youtube",
+				},
+				"youtubeRefreshToken": map[string]interface{}{
+					"type":        "string",
+					"description": "OAuth refresh token with the youtube.upload scope (or YOUTUBE_REFRESH_TOKEN)",
+				},
+				"remoteCacheDir": map[string]interface{}{
+					"type":        "string",
+					"description": "Where http(s)://, s3:// and gs:// inputs are downloaded (default: <tempDir>/mcp-video-remote)",
+				},
+				"remoteCacheMb": map[string]interface{}{
+					"type":        "number",
+					"description": "Size limit of the download cache in MB; least recently used files are removed first (default: 10240)",
+				},
+				"s3AccessKeyId": map[string]interface{}{
+					"type":        "string",
+					"description": "AWS access key ID for s3:// paths (or AWS_ACCESS_KEY_ID). Unset for public buckets",
+				},
+				"s3SecretAccessKey": map[string]interface{}{
+					"type":        "string",
+					"description": "AWS secret access key for s3:// paths",
+				},
+				"s3SessionToken": map[string]interface{}{
+					"type":        "string",
+					"description": "Session token for temporary AWS credentials",
+				},
+				"s3Region": map[string]interface{}{
+					"type":        "string",
+					"description": "Region of the S3 buckets (default: us-east-1)",
+				},
+				"s3Endpoint": map[string]interface{}{
+					"type":        "string",
+					"description": "S3-compatible endpoint such as MinIO or Cloudflare R2, addressed path-style (e.g. http://localhost:9000)",
+				},
+				"gcsAccessKeyId": map[string]interface{}{
+					"type":        "string",
+					"description": "Google Cloud Storage HMAC access ID for gs:// paths (or GCS_HMAC_ACCESS_ID). Unset for public buckets",
+				},
+				"gcsSecret": map[string]interface{}{
+					"type":        "string",
+					"description": "Google Cloud Storage HMAC secret",
+				},
+				"projectsDir": map[string]interface{}{
+					"type":        "string",
+					"description": "Directory create_project puts new projects in (default: working directory)",
+				},
 			},
 			Required: []string{},
 		},
@@ -980,6 +1582,39 @@ func (s *MCPServer) registerResetConfig() {
 	}, s.handleResetConfig)
 }
 
+func (s *MCPServer) registerGetSystemStatus() {
+	s.addTool(mcp.Tool{
+		Name:        "get_system_status",
+		Description: "Get current CPU, memory, GPU and disk utilization, running and queued FFmpeg jobs, ffprobe cache reuse, and the configured resource limits. New jobs are deferred while a limit is exceeded.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+			Required:   []string{},
+		},
+	}, s.handleGetSystemStatus)
+}
+
+func (s *MCPServer) registerGetAIUsage() {
+	s.addTool(mcp.Tool{
+		Name:        "get_ai_usage",
+		Description: "Report Whisper, vision, translation, TTS and voice cloning usage (tokens, characters, audio minutes) with estimated cost at list prices. Covers this session by default, or everything recorded for a timeline or multi-take project. Calls are attributed to the timeline or project last worked on.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"timelineId": map[string]interface{}{
+					"type":        "string",
+					"description": "Report stored usage for this timeline",
+				},
+				"projectId": map[string]interface{}{
+					"type":        "string",
+					"description": "Report stored usage for this multi-take project",
+				},
+			},
+			Required: []string{},
+		},
+	}, s.handleGetAIUsage)
+}
+
 // Additional visual effects registrations
 
 func (s *MCPServer) registerApplyKenBurns() {
@@ -1036,7 +1671,7 @@ func (s *MCPServer) registerApplyKenBurns() {
 func (s *MCPServer) registerAddImageOverlay() {
 	s.addTool(mcp.Tool{
 		Name:        "add_image_overlay",
-		Description: "Overlay an image on video with positioning, scaling, and effects",
+		Description: "Overlay an image on video with positioning, scaling, and effects. Animated GIF, APNG and WebM/MOV-with-alpha stickers play from startTime and loop.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -1050,7 +1685,7 @@ func (s *MCPServer) registerAddImageOverlay() {
 				},
 				"image": map[string]interface{}{
 					"type":        "string",
-					"description": "Image file path to overlay",
+					"description": "Image file path to overlay: a still, or an animated GIF, APNG or WebM/MOV with alpha",
 				},
 				"position": map[string]interface{}{
 					"type":        "string",
@@ -1092,6 +1727,14 @@ func (s *MCPServer) registerAddImageOverlay() {
 					"type":        "number",
 					"description": "Duration in seconds",
 				},
+				"animated": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Play the image as an animation (default: detected from the file)",
+				},
+				"loop": map[string]interface{}{
+					"type":        "number",
+					"description": "Extra plays of an animation after the first; -1 loops until the video ends, 0 plays once then disappears (default: -1)",
+				},
 			},
 			Required: []string{"input", "output", "image"},
 		},
@@ -1101,7 +1744,7 @@ func (s *MCPServer) registerAddImageOverlay() {
 func (s *MCPServer) registerAddShape() {
 	s.addTool(mcp.Tool{
 		Name:        "add_shape",
-		Description: "Draw shapes on video (rectangle, circle, line, arrow)",
+		Description: "Draw callout shapes on video: rectangles (optionally rounded), circles, polygons, lines and arrows with real arrowheads, solid or dashed. Shapes can move and scale over time with motion keyframes, and a plain rectangle can follow a tracked object from track_object.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -1115,15 +1758,15 @@ func (s *MCPServer) registerAddShape() {
 				},
 				"shape": map[string]interface{}{
 					"type":        "string",
-					"description": "Shape type: rectangle, circle, line, arrow",
+					"description": "Shape type: rectangle, circle, polygon, line, arrow",
 				},
 				"x": map[string]interface{}{
 					"type":        "number",
-					"description": "X position",
+					"description": "X position: left edge of a rectangle, center of a circle, start of a line or arrow (required unless drawing a polygon or following a track)",
 				},
 				"y": map[string]interface{}{
 					"type":        "number",
-					"description": "Y position",
+					"description": "Y position (required unless following a track)",
 				},
 				"width": map[string]interface{}{
 					"type":        "number",
@@ -1145,13 +1788,55 @@ func (s *MCPServer) registerAddShape() {
 					"type":        "number",
 					"description": "End Y (for line/arrow)",
 				},
+				"points": map[string]interface{}{
+					"type":        "array",
+					"description": "Polygon corners in pixels, in order (at least 3)",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"x": map[string]interface{}{"type": "number"},
+							"y": map[string]interface{}{"type": "number"},
+						},
+						"required": []string{"x", "y"},
+					},
+				},
 				"color": map[string]interface{}{
 					"type":        "string",
 					"description": "Color (e.g., 'red', 'white', '0xFF0000')",
 				},
 				"borderWidth": map[string]interface{}{
 					"type":        "number",
-					"description": "Border width (0 = filled shape)",
+					"description": "Border width (0 = filled shape); line and arrow thickness (default: 2 for lines, 4 for arrows)",
+				},
+				"borderColor": map[string]interface{}{
+					"type":        "string",
+					"description": "Outline color; with borderWidth the shape is filled with color and outlined in borderColor",
+				},
+				"cornerRadius": map[string]interface{}{
+					"type":        "number",
+					"description": "Corner radius in pixels for a rounded rectangle",
+				},
+				"dash": map[string]interface{}{
+					"type":        "number",
+					"description": "Dash length in pixels for outlines, lines and arrow shafts, with equal gaps (default: solid)",
+				},
+				"headSize": map[string]interface{}{
+					"type":        "number",
+					"description": "Arrowhead length in pixels (default: 4x the line width, at least 16)",
+				},
+				"motion": map[string]interface{}{
+					"type":        "array",
+					"description": "Keyframes that move and scale the shape over time, interpolated linearly; not for shapes following a track",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"time":  map[string]interface{}{"type": "number", "description": "Time in seconds"},
+							"x":     map[string]interface{}{"type": "number", "description": "Horizontal offset in pixels from where the shape is drawn"},
+							"y":     map[string]interface{}{"type": "number", "description": "Vertical offset in pixels"},
+							"scale": map[string]interface{}{"type": "number", "description": "Size relative to as drawn, about the shape's center (default: 1)"},
+						},
+						"required": []string{"time"},
+					},
 				},
 				"opacity": map[string]interface{}{
 					"type":        "number",
@@ -1165,8 +1850,16 @@ func (s *MCPServer) registerAddShape() {
 					"type":        "number",
 					"description": "Duration in seconds",
 				},
+				"trackFile": map[string]interface{}{
+					"type":        "string",
+					"description": "JSON file saved by track_object or detect_faces; the rectangle follows a track instead of using x, y, width and height",
+				},
+				"trackId": map[string]interface{}{
+					"type":        "number",
+					"description": "Track ID in trackFile to follow (default: 1)",
+				},
 			},
-			Required: []string{"input", "output", "shape", "x", "y"},
+			Required: []string{"input", "output", "shape"},
 		},
 	}, s.handleAddShape)
 }
@@ -1602,7 +2295,7 @@ func (s *MCPServer) registerExportFinalVideo() {
 func (s *MCPServer) registerAnalyzeVideoContent() {
 	s.addTool(mcp.Tool{
 		Name:        "analyze_video_content",
-		Description: "Analyze visual content of video by extracting and analyzing frames with GPT-4 Vision. Returns frame descriptions, objects detected, and overall summary.",
+		Description: "Analyze visual content of video by extracting and analyzing frames with the configured vision provider (OpenAI, Claude, Gemini or a local Ollama model). Returns frame descriptions, objects detected, and overall summary.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -1652,7 +2345,7 @@ func (s *MCPServer) registerCompareVideoFrames() {
 func (s *MCPServer) registerDescribeScene() {
 	s.addTool(mcp.Tool{
 		Name:        "describe_scene",
-		Description: "Describe a specific scene in the video at a given timestamp using GPT-4 Vision",
+		Description: "Describe a specific scene in the video at a given timestamp using the configured vision provider",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -1674,10 +2367,26 @@ func (s *MCPServer) registerDescribeScene() {
 	}, s.handleDescribeScene)
 }
 
+func (s *MCPServer) registerClearVisionCache() {
+	s.addTool(mcp.Tool{
+		Name:        "clear_vision_cache",
+		Description: "Clear cached frames and vision descriptions. Vision tools reuse frames and answers for the same video, timestamp and prompt, so repeat calls skip extraction and API calls.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Video file to clear the cache for (default: all videos)",
+				},
+			},
+		},
+	}, s.handleClearVisionCache)
+}
+
 func (s *MCPServer) registerFindObjectsInVideo() {
 	s.addTool(mcp.Tool{
 		Name:        "find_objects_in_video",
-		Description: "Find specific objects or elements in video frames using GPT-4 Vision",
+		Description: "Find specific objects or elements in video frames using the configured vision provider",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -1702,7 +2411,7 @@ func (s *MCPServer) registerFindObjectsInVideo() {
 func (s *MCPServer) registerSearchVisualContent() {
 	s.addTool(mcp.Tool{
 		Name:        "search_visual_content",
-		Description: "Search for specific visual content throughout the video and return matching timestamps",
+		Description: "Search for specific visual content throughout the video and return matching timestamps, joined into the continuous time ranges where it is visible",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -1724,6 +2433,45 @@ func (s *MCPServer) registerSearchVisualContent() {
 	}, s.handleSearchVisualContent)
 }
 
+func (s *MCPServer) registerExtractOnscreenText() {
+	s.addTool(mcp.Tool{
+		Name:        "extract_onscreen_text",
+		Description: "Read on-screen text (slides, captions, code, UI) from sampled frames with OCR and return it with timestamps. Consecutive frames with the same text are merged, and query finds e.g. the slide that mentions \"Q3 revenue\". Uses Tesseract when installed, otherwise the configured vision provider.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"input": map[string]interface{}{
+					"type":        "string",
+					"description": "Input video file path",
+				},
+				"interval": map[string]interface{}{
+					"type":        "number",
+					"description": "Interval in seconds between sampled frames (default: 5)",
+				},
+				"timestamps": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "number"},
+					"description": "Specific timestamps to read instead of sampling at an interval",
+				},
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "Only return text containing every word of the query (case-insensitive)",
+				},
+				"engine": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{vision.OCREngineAuto, vision.OCREngineTesseract, vision.OCREngineVision},
+					"description": "OCR engine: auto (default, Tesseract if installed), tesseract, or vision for the configured vision model",
+				},
+				"language": map[string]interface{}{
+					"type":        "string",
+					"description": "Tesseract language codes, e.g. eng+deu (default: eng)",
+				},
+			},
+			Required: []string{"input"},
+		},
+	}, s.handleExtractOnscreenText)
+}
+
 func (s *MCPServer) registerGenerateTimeline() {
 	s.addTool(mcp.Tool{
 		Name:        "generate_timeline",
@@ -1950,13 +2698,13 @@ func (s *MCPServer) registerGenerateMindMap() {
 func (s *MCPServer) registerCreateSideBySide() {
 	s.addTool(mcp.Tool{
 		Name:        "create_side_by_side",
-		Description: "Place two videos side by side horizontally",
+		Description: "Place two videos side by side (or stacked) at a common size, with per-input start offsets to sync them and a choice of audio",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
 				"input1": map[string]interface{}{
 					"type":        "string",
-					"description": "First input video file path",
+					"description": "First input video file path (left, or top when stacked)",
 				},
 				"input2": map[string]interface{}{
 					"type":        "string",
@@ -1966,6 +2714,30 @@ func (s *MCPServer) registerCreateSideBySide() {
 					"type":        "string",
 					"description": "Output video file path",
 				},
+				"offset1": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds to skip at the start of input1, to line it up with input2 (default: 0)",
+				},
+				"offset2": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds to skip at the start of input2 (default: 0)",
+				},
+				"vertical": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Stack input1 above input2 instead of side by side (default: false)",
+				},
+				"height": map[string]interface{}{
+					"type":        "number",
+					"description": "Common height both videos are scaled to side by side (default: input1's height)",
+				},
+				"width": map[string]interface{}{
+					"type":        "number",
+					"description": "Common width both videos are scaled to when stacked (default: input1's width)",
+				},
+				"audio": map[string]interface{}{
+					"type":        "string",
+					"description": "Audio to keep: 1 or 2 for one input, mix for both, or none (default: 1)",
+				},
 			},
 			Required: []string{"input1", "input2", "output"},
 		},
@@ -1975,13 +2747,26 @@ func (s *MCPServer) registerCreateSideBySide() {
 func (s *MCPServer) registerCreateVideoFromImages() {
 	s.addTool(mcp.Tool{
 		Name:        "create_video_from_images",
-		Description: "Create a video from a sequence of image files",
+		Description: "Create a video from images: an ordered list with per-image durations and xfade transitions, or a numbered sequence (frame_%04d.png) or wildcard (shot_*.jpg) at one image per frame. Works the same on Windows, and file names with spaces, quotes or brackets are safe. Images of different sizes are letterboxed into the frame.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
+				"images": map[string]interface{}{
+					"type":        "array",
+					"description": "Images in order, each {image, duration, transition}; duration and transition (into the next image) are optional",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"image":      map[string]interface{}{"type": "string"},
+							"duration":   map[string]interface{}{"type": "number", "description": "Seconds on screen"},
+							"transition": map[string]interface{}{"type": "string", "description": "xfade transition into the next image (fade, dissolve, wipeleft, slideleft, circleopen...) or cut"},
+						},
+						"required": []string{"image"},
+					},
+				},
 				"imagePattern": map[string]interface{}{
 					"type":        "string",
-					"description": "Image file pattern (e.g., 'frame-%03d.png' or 'image*.jpg')",
+					"description": "Image sequence instead of images, one image per frame: 'frame-%03d.png' or 'image*.jpg' (sorted by name)",
 				},
 				"output": map[string]interface{}{
 					"type":        "string",
@@ -1991,8 +2776,28 @@ func (s *MCPServer) registerCreateVideoFromImages() {
 					"type":        "number",
 					"description": "Frames per second (default: 30)",
 				},
+				"duration": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds per listed image without its own duration (default: 3)",
+				},
+				"transition": map[string]interface{}{
+					"type":        "string",
+					"description": "Transition between listed images without their own (default: cut)",
+				},
+				"transitionDuration": map[string]interface{}{
+					"type":        "number",
+					"description": "Transition length in seconds (default: 0.5)",
+				},
+				"width": map[string]interface{}{
+					"type":        "number",
+					"description": "Output width (default: the first image's)",
+				},
+				"height": map[string]interface{}{
+					"type":        "number",
+					"description": "Output height (default: the first image's)",
+				},
 			},
-			Required: []string{"imagePattern", "output"},
+			Required: []string{"output"},
 		},
 	}, s.handleCreateVideoFromImages)
 }
@@ -2000,7 +2805,7 @@ func (s *MCPServer) registerCreateVideoFromImages() {
 func (s *MCPServer) registerGetAudioStats() {
 	s.addTool(mcp.Tool{
 		Name:        "get_audio_stats",
-		Description: "Get audio statistics and analysis from a video or audio file",
+		Description: "Get audio statistics from a video or audio file: codec and bitrate plus measured integrated loudness (LUFS), true peak, loudness range, crest factor, clipping, DC offset and silence percentage",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -2020,32 +2825,78 @@ func (s *MCPServer) ExecuteToolDirect(name string, args map[string]interface{})
 	// Create a map of tool names to handler functions
 	handlers := map[string]func(map[string]interface{}) (*mcp.CallToolResult, error){
 		"get_video_info":              s.handleGetVideoInfo,
+		"get_metadata":                s.handleGetMetadata,
+		"set_metadata":                s.handleSetMetadata,
 		"trim_video":                  s.handleTrimVideo,
 		"concatenate_videos":          s.handleConcatenateVideos,
 		"resize_video":                s.handleResizeVideo,
 		"extract_audio":               s.handleExtractAudio,
+		"mute_video":                  s.handleMuteVideo,
+		"replace_audio":               s.handleReplaceAudio,
+		"sync_audio_video":            s.handleSyncAudioVideo,
+		"fit_voiceover":               s.handleFitVoiceover,
+		"add_audio_track":             s.handleAddAudioTrack,
+		"mux_audio_tracks":            s.handleMuxAudioTracks,
 		"transcode_video":             s.handleTranscodeVideo,
 		"apply_blur_effect":           s.handleApplyBlur,
 		"apply_color_grade":           s.handleApplyColorGrade,
 		"apply_chroma_key":            s.handleApplyChromaKey,
+		"replace_background":          s.handleReplaceBackground,
 		"apply_vignette":              s.handleApplyVignette,
 		"apply_sharpen":               s.handleApplySharpen,
+		"denoise_video":               s.handleDenoiseVideo,
+		"add_film_grain":              s.handleAddFilmGrain,
+		"apply_style_preset":          s.handleApplyStylePreset,
+		"apply_duotone":               s.handleApplyDuotone,
+		"apply_selective_color":       s.handleApplySelectiveColor,
 		"create_picture_in_picture":   s.handleCreatePictureInPicture,
 		"create_split_screen":         s.handleCreateSplitScreen,
 		"create_side_by_side":         s.handleCreateSideBySide,
+		"overlay_video":               s.handleOverlayVideo,
 		"add_transition":              s.handleAddTransition,
 		"crossfade_videos":            s.handleCrossfadeVideos,
 		"add_text_overlay":            s.handleAddTextOverlay,
 		"add_animated_text":           s.handleAddAnimatedText,
+		"add_lower_third":             s.handleAddLowerThird,
+		"add_countdown":               s.handleAddCountdown,
+		"add_progress_bar":            s.handleAddProgressBar,
+		"list_fonts":                  s.handleListFonts,
+		"apply_template":              s.handleApplyTemplate,
+		"create_end_screen":           s.handleCreateEndScreen,
+		"add_watermark":               s.handleAddWatermark,
+		"add_intro_outro":             s.handleAddIntroOutro,
 		"burn_subtitles":              s.handleBurnSubtitles,
+		"attach_subtitles":            s.handleAttachSubtitles,
+		"extract_subtitles":           s.handleExtractSubtitles,
+		"convert_subtitles":           s.handleConvertSubtitles,
+		"shift_subtitles":             s.handleShiftSubtitles,
+		"scale_subtitles":             s.handleScaleSubtitles,
 		"extract_frames":              s.handleExtractFrames,
 		"adjust_speed":                s.handleAdjustSpeed,
 		"convert_video":               s.handleConvertVideo,
 		"transcode_for_web":           s.handleTranscodeForWeb,
 		"create_video_from_images":    s.handleCreateVideoFromImages,
+		"generate_thumbnail":          s.handleGenerateThumbnail,
+		"export_still":                s.handleExportStill,
+		"create_thumbnail":            s.handleCreateThumbnail,
+		"generate_contact_sheet":      s.handleGenerateContactSheet,
+		"generate_sprite_sheet":       s.handleGenerateSpriteSheet,
+		"smart_speed":                 s.handleSmartSpeed,
+		"prepare_screen_recording":    s.handlePrepareScreenRecording,
+		"compare_quality":             s.handleCompareQuality,
+		"analyze_bitrate":             s.handleAnalyzeBitrate,
+		"export_multi_aspect":         s.handleExportMultiAspect,
+		"reframe_video":               s.handleReframeVideo,
+		"find_duplicate_media":        s.handleFindDuplicateMedia,
+		"validate_media":              s.handleValidateMedia,
+		"tone_map":                    s.handleToneMap,
+		"detect_interlacing":          s.handleDetectInterlacing,
+		"deinterlace_video":           s.handleDeinterlaceVideo,
+		"generate_scopes":             s.handleGenerateScopes,
 		"get_audio_stats":             s.handleGetAudioStats,
 		"trim_audio":                  s.handleTrimAudio,
 		"concatenate_audio":           s.handleConcatenateAudio,
+		"crossfade_audio":             s.handleCrossfadeAudio,
 		"adjust_audio_volume":         s.handleAdjustAudioVolume,
 		"normalize_audio":             s.handleNormalizeAudio,
 		"fade_audio":                  s.handleFadeAudio,
@@ -2056,17 +2907,36 @@ func (s *MCPServer) ExecuteToolDirect(name string, args map[string]interface{})
 		"split_audio":                 s.handleSplitAudio,
 		"reverse_audio":               s.handleReverseAudio,
 		"extract_audio_channel":       s.handleExtractAudioChannel,
+		"generate_waveform_image":     s.handleGenerateWaveformImage,
+		"create_audiogram":            s.handleCreateAudiogram,
+		"separate_stems":              s.handleSeparateStems,
+		"pitch_shift":                 s.handlePitchShift,
+		"clean_voice":                 s.handleCleanVoice,
+		"export_podcast":              s.handleExportPodcast,
+		"generate_silence":            s.handleGenerateSilence,
+		"generate_tone":               s.handleGenerateTone,
 		"replace_spoken_word":         s.handleReplaceSpokenWord,
+		"replace_spoken_words":        s.handleReplaceSpokenWords,
 		"clone_voice_from_audio":      s.handleCloneVoiceFromAudio,
 		"generate_speech":             s.handleGenerateSpeech,
+		"generate_dialogue":           s.handleGenerateDialogue,
 		"get_word_timestamps":         s.handleGetWordTimestamps,
 		"list_cached_voices":          s.handleListCachedVoices,
 		"clear_cached_voice":          s.handleClearCachedVoice,
 		"clear_all_cached_voices":     s.handleClearAllCachedVoices,
+		"export_voice_cache":          s.handleExportVoiceCache,
+		"import_voice_cache":          s.handleImportVoiceCache,
 		"get_config":                  s.handleGetConfig,
 		"set_config":                  s.handleSetConfig,
 		"reset_config":                s.handleResetConfig,
+		"get_system_status":           s.handleGetSystemStatus,
+		"get_ffmpeg_capabilities":     s.handleGetFFmpegCapabilities,
+		"run_ffmpeg":                  s.handleRunFFmpeg,
+		"get_ai_usage":                s.handleGetAIUsage,
+		"cleanup_temp":                s.handleCleanupTemp,
 		"apply_ken_burns":             s.handleApplyKenBurns,
+		"create_slideshow":            s.handleCreateSlideshow,
+		"punch_in":                    s.handlePunchIn,
 		"add_image_overlay":           s.handleAddImageOverlay,
 		"add_shape":                   s.handleAddShape,
 		"extract_transcript":          s.handleExtractTranscript,
@@ -2089,15 +2959,48 @@ func (s *MCPServer) ExecuteToolDirect(name string, args map[string]interface{})
 		"list_multi_take_projects":    s.handleListMultiTakeProjects,
 		"cleanup_project_temp":        s.handleCleanupProjectTemp,
 		"export_final_video":          s.handleExportFinalVideo,
+		"align_cameras":               s.handleAlignCameras,
+		"switch_cameras":              s.handleSwitchCameras,
 		"analyze_video_content":       s.handleAnalyzeVideoContent,
 		"compare_video_frames":        s.handleCompareVideoFrames,
 		"describe_scene":              s.handleDescribeScene,
+		"clear_vision_cache":          s.handleClearVisionCache,
+		"extract_onscreen_text":       s.handleExtractOnscreenText,
+		"detect_faces":                s.handleDetectFaces,
+		"blur_faces":                  s.handleBlurFaces,
+		"track_object":                s.handleTrackObject,
+		"blur_region":                 s.handleBlurRegion,
 		"find_objects_in_video":       s.handleFindObjectsInVideo,
 		"search_visual_content":       s.handleSearchVisualContent,
 		"generate_timeline_diagram":   s.handleGenerateTimeline,
 		"generate_flowchart":          s.handleGenerateFlowchart,
 		"generate_org_chart":          s.handleGenerateOrgChart,
 		"generate_mind_map":           s.handleGenerateMindMap,
+		"generate_image":              s.handleGenerateImage,
+		"generate_broll_image":        s.handleGenerateBrollImage,
+		"search_stock_media":          s.handleSearchStockMedia,
+		"index_media":                 s.handleIndexMedia,
+		"search_media":                s.handleSearchMedia,
+		"process_meeting_recording":   s.handleProcessMeetingRecording,
+		"generate_chapters":           s.handleGenerateChapters,
+		"embed_chapters":              s.handleEmbedChapters,
+		"extract_chapters":            s.handleExtractChapters,
+		"find_highlights":             s.handleFindHighlights,
+		"create_short":                s.handleCreateShort,
+		"moderate_video":              s.handleModerateVideo,
+		"auto_caption":                s.handleAutoCaption,
+		"karaoke_captions":            s.handleKaraokeCaptions,
+		"translate_transcript":        s.handleTranslateTranscript,
+		"dub_video":                   s.handleDubVideo,
+		"upload_to_youtube":           s.handleUploadToYouTube,
+		"create_project":              s.handleCreateProject,
+		"set_active_project":          s.handleSetActiveProject,
+		"list_projects":               s.handleListProjects,
+		"start_recording":             s.handleStartRecording,
+		"stop_recording":              s.handleStopRecording,
+		"list_recordings":             s.handleListRecordings,
+		"stream_video":                s.handleStreamVideo,
+		"stop_stream":                 s.handleStopStream,
 	}
 
 	// Look up the handler
@@ -2110,7 +3013,15 @@ func (s *MCPServer) ExecuteToolDirect(name string, args map[string]interface{})
 	}
 
 	// Execute the handler
-	result, err := handler(args)
+	args, chosen, err := s.chooseOutputs(name, s.defaultOutputs[name], args)
+	if err != nil {
+		return &ToolResult{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+	result, err := s.withRemotePaths(name, s.withProjectPaths(name, handler))(args)
+	finishOutputs(result, err, chosen)
 	if err != nil {
 		return &ToolResult{
 			Success: false,
@@ -2141,10 +3052,14 @@ func (s *MCPServer) ExecuteToolDirect(name string, args map[string]interface{})
 		}
 	}
 
-	return &ToolResult{
+	toolResult := &ToolResult{
 		Success: true,
 		Content: contentText,
-	}, nil
+	}
+	if len(chosen) > 0 {
+		toolResult.Data = map[string]interface{}{"outputs": outputPaths(chosen)}
+	}
+	return toolResult, nil
 }
 
 // GetToolDefinitions returns the schemas for all registered tools
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/workspace"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// workspaceTools manage projects and are never rewritten
+var workspaceTools = map[string]bool{"create_project": true, "set_active_project": true, "list_projects": true}
+
+// transcriptTools write to the project's transcripts folder
+var transcriptTools = map[string]bool{"extract_transcript": true, "translate_transcript": true, "extract_subtitles": true}
+
+// fileExtension matches extensions that mark a value as a file path
+var fileExtension = regexp.MustCompile(`^\.[A-Za-z0-9]{1,5}$`)
+
+// withProjectPaths wraps a tool handler so that, while a project is active,
+// relative paths resolve inside it: outputs go to outputs/ (transcripts to
+// transcripts/) and inputs are found in the project root, assets/, outputs/
+// or transcripts/
+func (s *MCPServer) withProjectPaths(tool string, handler func(map[string]interface{}) (*mcp.CallToolResult, error)) func(map[string]interface{}) (*mcp.CallToolResult, error) {
+	return func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+		if workspaceTools[tool] {
+			return handler(arguments)
+		}
+		project, err := s.workspace.Active()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Active project is unavailable: %v (use set_active_project with an empty name to leave it)", err)), nil
+		}
+		if project == nil {
+			return handler(arguments)
+		}
+
+		return handler(projectArguments(project, tool, arguments))
+	}
+}
+
+// projectArguments returns a copy of the arguments with relative paths
+// resolved in the project
+func projectArguments(p *workspace.Project, tool string, arguments map[string]interface{}) map[string]interface{} {
+	folder := projectFolder(tool)
+
+	out := make(map[string]interface{}, len(arguments))
+	for key, value := range arguments {
+		out[key] = value
+		if !isPathArgument(key) {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			out[key] = projectPath(p, folder, key, v)
+		case []interface{}:
+			items := make([]interface{}, len(v))
+			for i, item := range v {
+				items[i] = item
+				if path, ok := item.(string); ok {
+					items[i] = projectPath(p, folder, key, path)
+				}
+			}
+			out[key] = items
+		}
+	}
+
+	return out
+}
+
+// projectFolder returns the project folder a tool writes to
+func projectFolder(tool string) string {
+	if transcriptTools[tool] {
+		return workspace.DirTranscripts
+	}
+	return workspace.DirOutputs
+}
+
+// projectPath resolves one relative path argument. Outputs are placed in
+// folder; inputs are looked up in the project and left alone when absent.
+// Values that don't look like paths are returned unchanged.
+func projectPath(p *workspace.Project, folder, key, value string) string {
+	if value == "" || filepath.IsAbs(value) || strings.Contains(value, "://") {
+		return value
+	}
+	dir := isDirArgument(key)
+	if !dir && !fileExtension.MatchString(filepath.Ext(value)) {
+		return value
+	}
+
+	if isOutputArgument(key) {
+		path := filepath.Join(p.Dir(folder), value)
+		parent := path
+		if !dir {
+			parent = filepath.Dir(path)
+		}
+		// Tools expect an output's directory to exist
+		if err := os.MkdirAll(parent, 0755); err != nil {
+			return value
+		}
+		return path
+	}
+
+	if dir {
+		return value
+	}
+	for _, base := range []string{p.Root, p.Dir(workspace.DirAssets), p.Dir(workspace.DirOutputs), p.Dir(workspace.DirTranscripts)} {
+		path := filepath.Join(base, value)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return value
+}
+
+// isDirArgument reports whether an argument names a directory
+func isDirArgument(key string) bool {
+	return strings.HasSuffix(key, "Dir") || key == "folder"
+}
+
+// applyProject points the stores that keep their own directories at the
+// project, or back at their defaults when p is nil
+func (s *MCPServer) applyProject(p *workspace.Project) {
+	root, takes := "", ""
+	if p != nil {
+		root, takes = p.Root, filepath.Join(p.Root, ".mcp-multi-take-projects")
+	}
+	s.timeline.SetBaseDir(root)
+	s.multitake.SetBaseDir(takes)
+	s.usage.SetBaseDir(root)
+	scratch.SetBase(s.config.TempPath())
+}
@@ -0,0 +1,531 @@
+// Package shorts cuts a vertical, captioned clip about a topic out of a long video
+package shorts
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/captions"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/reframe"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/vision"
+)
+
+// Output size for every platform
+const (
+	shortWidth  = 1080
+	shortHeight = 1920
+)
+
+// visualWeight is how much a vision match counts next to a transcript match
+const visualWeight = 0.5
+
+// Platform holds the export settings for a short-form platform
+type Platform struct {
+	MaxLength    float64 // Longest clip the platform accepts, in seconds
+	CRF          int
+	MaxBitrate   int // kbps
+	AudioBitrate int // kbps
+	FPS          int // 0 keeps the source frame rate
+}
+
+// Platforms maps platform names to export presets
+var Platforms = map[string]Platform{
+	"shorts": {MaxLength: 60, CRF: 20, MaxBitrate: 10000, AudioBitrate: 192},
+	"tiktok": {MaxLength: 180, CRF: 21, MaxBitrate: 6000, AudioBitrate: 128},
+	"reels":  {MaxLength: 90, CRF: 21, MaxBitrate: 5000, AudioBitrate: 128, FPS: 30},
+}
+
+// Options contains parameters for creating a short
+type Options struct {
+	Input          string
+	Query          string // Topic or phrase the short should be about
+	Output         string
+	Platform       string  // shorts (default), tiktok or reels
+	MinLength      float64 // Default: 15
+	MaxLength      float64 // Default: 60, capped by the platform
+	Language       string  // Transcription language hint (optional)
+	TranscriptPath string  // Reuse a saved transcript JSON instead of transcribing
+	SkipVision     bool    // Match on the transcript only
+	VisionInterval float64 // Seconds between searched frames (default: duration/120, at least 5)
+	CenterCrop     bool    // Use a fixed center crop instead of following the subject
+	Tracking       string  // Subject tracking engine: auto (default), vision or motion
+	SkipCaptions   bool
+	Captions       captions.KaraokeOptions // Caption style; Input, Output and TranscriptPath are ignored
+}
+
+// Result describes the exported short
+type Result struct {
+	Output         string   `json:"output"`
+	Platform       string   `json:"platform"`
+	Start          float64  `json:"start"` // In the source video
+	End            float64  `json:"end"`
+	Duration       float64  `json:"duration"`
+	Score          float64  `json:"score"`             // Relevance of the chosen segment
+	Text           string   `json:"text,omitempty"`    // What is said in the short
+	Matched        []string `json:"matched,omitempty"` // Query words found in the transcript
+	VisualMatches  int      `json:"visualMatches"`     // Vision matches inside the short
+	Framing        string   `json:"framing"`           // vision or motion (subject tracked), center or none
+	Keyframes      int      `json:"keyframes,omitempty"`
+	Captions       int      `json:"captions"`
+	CaptionsFile   string   `json:"captionsFile,omitempty"`
+	TranscriptFile string   `json:"transcriptFile,omitempty"`
+	Sources        []string `json:"sources"` // Signals used: transcript, vision
+	Warnings       []string `json:"warnings,omitempty"`
+}
+
+// Creator finds a topic in a long video and exports it as a short
+type Creator struct {
+	ffmpeg        *ffmpeg.Manager
+	videoOps      *video.Operations
+	transcriptOps *transcript.Operations
+	vision        *vision.Analyzer
+	reframer      *reframe.Reframer
+}
+
+// NewCreator creates a short creator
+func NewCreator(mgr *ffmpeg.Manager, videoOps *video.Operations, transcriptOps *transcript.Operations, visionAnalyzer *vision.Analyzer) *Creator {
+	return &Creator{
+		ffmpeg:        mgr,
+		videoOps:      videoOps,
+		transcriptOps: transcriptOps,
+		vision:        visionAnalyzer,
+		reframer:      reframe.NewReframer(mgr, videoOps, visionAnalyzer),
+	}
+}
+
+// Create searches the transcript and frames for the query, cuts the best
+// matching segment, crops it to 9:16 around the subject, burns karaoke
+// captions and encodes it with the platform preset
+func (c *Creator) Create(ctx context.Context, opts Options) (*Result, error) {
+	if strings.TrimSpace(opts.Query) == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if opts.Platform == "" {
+		opts.Platform = "shorts"
+	}
+	platform, ok := Platforms[opts.Platform]
+	if !ok {
+		return nil, fmt.Errorf("unknown platform: %s (use shorts, tiktok or reels)", opts.Platform)
+	}
+	if opts.MinLength <= 0 {
+		opts.MinLength = 15
+	}
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = 60
+	}
+	opts.MaxLength = math.Min(opts.MaxLength, platform.MaxLength)
+	if opts.MinLength > opts.MaxLength {
+		return nil, fmt.Errorf("minimum length %.0fs is longer than the maximum %.0fs", opts.MinLength, opts.MaxLength)
+	}
+	if opts.Output == "" {
+		opts.Output = strings.TrimSuffix(opts.Input, filepath.Ext(opts.Input)) + "_" + opts.Platform + ".mp4"
+	}
+
+	info, err := c.videoOps.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+	if info.Duration <= 0 || info.Width <= 0 || info.Height <= 0 {
+		return nil, fmt.Errorf("could not determine duration or dimensions of %s", opts.Input)
+	}
+
+	result := &Result{Output: opts.Output, Platform: opts.Platform}
+	base := strings.TrimSuffix(opts.Output, filepath.Ext(opts.Output))
+
+	// Transcript and frames are searched independently; either is enough
+	trans, transcriptFile, err := c.loadTranscript(ctx, opts, base, info.HasAudio)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("transcript unavailable: %v", err))
+	} else {
+		result.Sources = append(result.Sources, "transcript")
+		result.TranscriptFile = transcriptFile
+	}
+
+	var visual []vision.VisualSearchMatch
+	if !opts.SkipVision {
+		interval := opts.VisionInterval
+		if interval <= 0 {
+			interval = math.Max(5, info.Duration/120)
+		}
+		search, err := c.vision.SearchVisualContent(ctx, opts.Input, opts.Query, interval)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("visual search unavailable: %v", err))
+		} else {
+			result.Sources = append(result.Sources, "vision")
+			visual = search.Matches
+		}
+	}
+	if len(result.Sources) == 0 {
+		return nil, fmt.Errorf("nothing to search: no transcript and no vision provider")
+	}
+
+	var segments []transcript.Segment
+	if trans != nil {
+		segments = trans.Segments
+	}
+	pick, err := pickSegment(scoreSegments(segments, opts.Query, visual), visual, info.Duration, opts.MinLength, opts.MaxLength)
+	if err != nil {
+		return nil, err
+	}
+	result.Start, result.End = pick.start, pick.end
+	result.Duration = round2(pick.end - pick.start)
+	result.Score = round2(pick.score)
+	result.Text = pick.text
+	result.Matched = pick.matched
+	for _, m := range visual {
+		if m.Timestamp >= pick.start && m.Timestamp < pick.end {
+			result.VisualMatches++
+		}
+	}
+
+	filters := []string{c.frameFilter(ctx, opts, pick, info.Width, info.Height, result)}
+
+	if !opts.SkipCaptions {
+		if trans == nil {
+			result.Warnings = append(result.Warnings, "captions skipped: no transcript")
+		} else if filter, err := writeCaptions(opts.Captions, shiftSegments(trans.Segments, pick.start, pick.end), base+".karaoke.ass", result); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("captions skipped: %v", err))
+		} else {
+			filters = append(filters, filter)
+		}
+	}
+
+	// Seeking before the input starts filter and caption timestamps at the cut
+	args := []string{
+		"-ss", formatSeconds(pick.start),
+		"-i", opts.Input,
+		"-t", formatSeconds(pick.end - pick.start),
+		"-map", "0:v:0", "-map", "0:a?",
+		"-vf", strings.Join(filters, ","),
+	}
+	args = append(args, encodeArgs(platform)...)
+	args = append(args, "-y", opts.Output)
+	if err := c.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, fmt.Errorf("failed to export short: %w", err)
+	}
+	return result, nil
+}
+
+// loadTranscript loads a saved transcript, or transcribes the input and saves
+// the result to base.transcript.json
+func (c *Creator) loadTranscript(ctx context.Context, opts Options, base string, hasAudio bool) (*transcript.Transcript, string, error) {
+	if opts.TranscriptPath != "" {
+		trans, err := c.transcriptOps.LoadTranscript(opts.TranscriptPath)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load transcript: %w", err)
+		}
+		return trans, opts.TranscriptPath, nil
+	}
+	if !hasAudio {
+		return nil, "", fmt.Errorf("video has no audio track to transcribe")
+	}
+	trans, err := c.transcriptOps.ExtractTranscript(ctx, opts.Input, opts.Language)
+	if err != nil {
+		return nil, "", err
+	}
+	transcriptFile := base + ".transcript.json"
+	if err := c.transcriptOps.SaveTranscript(trans, transcriptFile); err != nil {
+		return nil, "", fmt.Errorf("failed to save transcript: %w", err)
+	}
+	return trans, transcriptFile, nil
+}
+
+// frameFilter crops the picked segment to 9:16 and scales it to the output
+// size. The crop follows the subject unless a center crop is requested or
+// tracking fails.
+func (c *Creator) frameFilter(ctx context.Context, opts Options, pick segmentPick, width, height int, result *Result) string {
+	targetAspect := float64(shortWidth) / float64(shortHeight)
+	if float64(width)/float64(height) <= targetAspect+0.01 {
+		// Already vertical: scale and pad rather than crop
+		result.Framing = "none"
+		return fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1",
+			shortWidth, shortHeight, shortWidth, shortHeight)
+	}
+
+	var path []video.ReframeKeyframe
+	result.Framing = "center"
+	if !opts.CenterCrop {
+		track, err := c.reframer.Track(ctx, opts.Input, reframe.TrackOptions{
+			Aspect: targetAspect,
+			Engine: opts.Tracking,
+			Start:  pick.start,
+			End:    pick.end,
+		})
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("subject tracking failed, using a center crop: %v", err))
+		} else {
+			path = track.Path
+			result.Framing = track.Engine
+			result.Keyframes = len(track.Path)
+			result.Warnings = append(result.Warnings, track.Warnings...)
+		}
+	}
+	filter, _ := reframe.CropFilter(path, width, height, shortWidth, shortHeight)
+	return filter
+}
+
+// writeCaptions writes a karaoke ASS script for the short and returns the
+// filter that burns it in
+func writeCaptions(opts captions.KaraokeOptions, segments []transcript.Segment, path string, result *Result) (string, error) {
+	style, err := captions.KaraokeStyle(opts)
+	if err != nil {
+		return "", err
+	}
+	list := captions.BuildCaptions(segments, captions.LevelWord, style.MaxWords)
+	if len(list) == 0 {
+		return "", fmt.Errorf("no word timings in the segment")
+	}
+	if err := os.WriteFile(path, []byte(captions.BuildASS(list, style, shortWidth, shortHeight)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write captions: %w", err)
+	}
+	result.Captions = len(list)
+	result.CaptionsFile = path
+	return fmt.Sprintf("ass='%s'", ffmpeg.EscapeFilterPath(path)), nil
+}
+
+// encodeArgs returns the codec settings for a platform preset
+func encodeArgs(p Platform) []string {
+	args := []string{
+		"-c:v", "libx264",
+		"-crf", strconv.Itoa(p.CRF),
+		"-preset", "medium",
+		"-profile:v", "high",
+		"-pix_fmt", "yuv420p",
+		"-maxrate", fmt.Sprintf("%dk", p.MaxBitrate),
+		"-bufsize", fmt.Sprintf("%dk", p.MaxBitrate*2),
+	}
+	if p.FPS > 0 {
+		args = append(args, "-r", strconv.Itoa(p.FPS))
+	}
+	return append(args,
+		"-c:a", "aac",
+		"-b:a", fmt.Sprintf("%dk", p.AudioBitrate),
+		"-ar", "48000",
+		"-movflags", "+faststart",
+	)
+}
+
+// scoredSegment is a transcript segment with its relevance to the query
+type scoredSegment struct {
+	start, end float64
+	text       string
+	matched    []string
+	score      float64
+}
+
+// stopWords are left out of the query so they don't match every segment
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "about": true, "from": true,
+	"that": true, "this": true, "where": true, "when": true, "what": true, "how": true,
+	"they": true, "talk": true, "talks": true, "part": true, "into": true, "are": true,
+}
+
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}']+`)
+
+// queryTerms returns the query's meaningful lowercase words
+func queryTerms(query string) []string {
+	var terms []string
+	seen := map[string]bool{}
+	for _, w := range wordPattern.FindAllString(strings.ToLower(query), -1) {
+		if len(w) < 3 || stopWords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+// termMatches reports whether a spoken word is a form of a query term,
+// so "prices" matches "pricing" and "launches" matches "launch"
+func termMatches(word, term string) bool {
+	return word == term || stem(word) == stem(term)
+}
+
+// stem strips a common English suffix, keeping at least three letters
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "es", "ed", "s", "e"} {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			return word[:len(word)-len(suffix)]
+		}
+	}
+	return word
+}
+
+// scoreSegments rates each segment by the share of query terms it contains,
+// with a bonus for the exact phrase and for vision matches during it
+func scoreSegments(segments []transcript.Segment, query string, visual []vision.VisualSearchMatch) []scoredSegment {
+	terms := queryTerms(query)
+	phrase := strings.Join(wordPattern.FindAllString(strings.ToLower(query), -1), " ")
+
+	var scored []scoredSegment
+	for _, seg := range segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" || seg.End <= seg.Start {
+			continue
+		}
+		words := wordPattern.FindAllString(strings.ToLower(text), -1)
+		s := scoredSegment{start: seg.Start, end: seg.End, text: text}
+		for _, term := range terms {
+			for _, w := range words {
+				if termMatches(w, term) {
+					s.matched = append(s.matched, term)
+					break
+				}
+			}
+		}
+		if len(terms) > 0 {
+			s.score = float64(len(s.matched)) / float64(len(terms))
+		}
+		if phrase != "" && strings.Contains(" "+strings.Join(words, " ")+" ", " "+phrase+" ") {
+			s.score += 0.5
+		}
+		for _, m := range visual {
+			if m.Timestamp >= seg.Start && m.Timestamp < seg.End {
+				s.score += visualWeight * m.Confidence
+			}
+		}
+		scored = append(scored, s)
+	}
+	return scored
+}
+
+// segmentPick is the span chosen for the short
+type segmentPick struct {
+	start, end float64
+	score      float64
+	text       string
+	matched    []string
+}
+
+// pickSegment anchors on the most relevant segment and grows the span with
+// neighbouring segments: always until minLength, and past it only while the
+// neighbours are relevant too. Without a transcript match it centers on the
+// strongest vision match.
+func pickSegment(segments []scoredSegment, visual []vision.VisualSearchMatch, duration, minLength, maxLength float64) (segmentPick, error) {
+	anchor := -1
+	for i, s := range segments {
+		if s.score > 0 && (anchor < 0 || s.score > segments[anchor].score) {
+			anchor = i
+		}
+	}
+
+	if anchor < 0 {
+		if len(visual) == 0 {
+			return segmentPick{}, fmt.Errorf("no part of the video matches the query")
+		}
+		best := visual[0]
+		for _, m := range visual[1:] {
+			if m.Confidence > best.Confidence {
+				best = m
+			}
+		}
+		start, end := fitLength(best.Timestamp-minLength/2, best.Timestamp+minLength/2, minLength, maxLength, duration)
+		return segmentPick{start: start, end: end, score: visualWeight * best.Confidence}, nil
+	}
+
+	lo, hi := anchor, anchor
+	length := func(a, b int) float64 { return segments[b].end - segments[a].start }
+	for {
+		current := length(lo, hi)
+		canPrev := lo > 0 && length(lo-1, hi) <= maxLength && (current < minLength || segments[lo-1].score > 0)
+		canNext := hi < len(segments)-1 && length(lo, hi+1) <= maxLength && (current < minLength || segments[hi+1].score > 0)
+		if canPrev && canNext {
+			// Prefer what follows the match so the point gets finished
+			if segments[lo-1].score > segments[hi+1].score {
+				canNext = false
+			} else {
+				canPrev = false
+			}
+		}
+		if canNext {
+			hi++
+		} else if canPrev {
+			lo--
+		} else {
+			break
+		}
+	}
+
+	pick := segmentPick{}
+	var texts []string
+	seen := map[string]bool{}
+	for _, s := range segments[lo : hi+1] {
+		pick.score = math.Max(pick.score, s.score)
+		texts = append(texts, s.text)
+		for _, m := range s.matched {
+			if !seen[m] {
+				seen[m] = true
+				pick.matched = append(pick.matched, m)
+			}
+		}
+	}
+	sort.Strings(pick.matched)
+	pick.text = strings.Join(texts, " ")
+	pick.start, pick.end = fitLength(segments[lo].start, segments[hi].end, minLength, maxLength, duration)
+	return pick, nil
+}
+
+// fitLength pads or trims a span to the length limits and keeps it inside
+// the video. Short spans grow after the end first, then before the start.
+func fitLength(start, end, minLength, maxLength, duration float64) (float64, float64) {
+	start = math.Max(0, start)
+	end = math.Min(duration, end)
+	if end-start > maxLength {
+		end = start + maxLength
+	}
+	if end-start < minLength {
+		end = math.Min(duration, start+minLength)
+		start = math.Max(0, end-minLength)
+	}
+	return round2(start), round2(end)
+}
+
+// shiftSegments returns the words spoken between start and end, retimed to
+// the clip
+func shiftSegments(segments []transcript.Segment, start, end float64) []transcript.Segment {
+	var shifted []transcript.Segment
+	for _, seg := range segments {
+		if seg.End <= start || seg.Start >= end {
+			continue
+		}
+		out := transcript.Segment{
+			Text:  seg.Text,
+			Start: math.Max(0, seg.Start-start),
+			End:   math.Min(end, seg.End) - start,
+		}
+		for _, w := range seg.Words {
+			if w.Start < start || w.Start >= end {
+				continue
+			}
+			out.Words = append(out.Words, transcript.Word{
+				Word:  w.Word,
+				Start: w.Start - start,
+				End:   math.Min(end, w.End) - start,
+			})
+		}
+		if len(seg.Words) > 0 && len(out.Words) == 0 {
+			continue
+		}
+		shifted = append(shifted, out)
+	}
+	return shifted
+}
+
+func formatSeconds(s float64) string {
+	return strconv.FormatFloat(s, 'f', 3, 64)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,135 @@
+package shorts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/vision"
+)
+
+func TestQueryTerms(t *testing.T) {
+	if got, want := queryTerms("Where they talk about the new Pricing plan, pricing!"), []string{"new", "pricing", "plan"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("queryTerms() = %v, want %v", got, want)
+	}
+}
+
+func TestTermMatches(t *testing.T) {
+	tests := []struct {
+		word, term string
+		want       bool
+	}{
+		{"pricing", "pricing", true},
+		{"prices", "pricing", true},
+		{"launches", "launch", true},
+		{"planet", "plan", false},
+		{"renewal", "new", false},
+	}
+	for _, tt := range tests {
+		if got := termMatches(tt.word, tt.term); got != tt.want {
+			t.Errorf("termMatches(%q, %q) = %v, want %v", tt.word, tt.term, got, tt.want)
+		}
+	}
+}
+
+func testSegments() []transcript.Segment {
+	return []transcript.Segment{
+		{Start: 0, End: 10, Text: "Welcome back to the channel."},
+		{Start: 10, End: 20, Text: "Today we cover three updates."},
+		{Start: 20, End: 30, Text: "First, the new pricing plan."},
+		{Start: 30, End: 40, Text: "Prices drop for every team."},
+		{Start: 40, End: 50, Text: "Next up is the mobile app."},
+		{Start: 50, End: 60, Text: "Thanks for watching."},
+	}
+}
+
+func TestScoreSegments(t *testing.T) {
+	visual := []vision.VisualSearchMatch{{Timestamp: 45, Confidence: 0.8}}
+	scored := scoreSegments(testSegments(), "new pricing plan", visual)
+
+	if scored[2].score != 1.5 || !reflect.DeepEqual(scored[2].matched, []string{"new", "pricing", "plan"}) {
+		t.Errorf("Expected the exact phrase to score 1.5, got %+v", scored[2])
+	}
+	if scored[3].score <= 0 || scored[3].score >= 1 {
+		t.Errorf("Expected a partial match for a word form, got %+v", scored[3])
+	}
+	if scored[4].score != 0.4 {
+		t.Errorf("Expected the vision match to score the segment, got %+v", scored[4])
+	}
+	if scored[0].score != 0 {
+		t.Errorf("Expected no score for an unrelated segment, got %+v", scored[0])
+	}
+}
+
+func TestPickSegment(t *testing.T) {
+	scored := scoreSegments(testSegments(), "new pricing plan", nil)
+
+	pick, err := pickSegment(scored, nil, 60, 15, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The anchor grows into the relevant follow-up and stops there
+	if pick.start != 20 || pick.end != 40 || !strings.HasPrefix(pick.text, "First, the new") {
+		t.Errorf("pickSegment() = %+v, want 20-40", pick)
+	}
+
+	// Below the minimum, unrelated neighbours are added too
+	pick, _ = pickSegment(scored, nil, 60, 35, 60)
+	if pick.start != 20 || pick.end != 60 {
+		t.Errorf("pickSegment() with a 35s minimum = %v-%v, want 20-60", pick.start, pick.end)
+	}
+
+	// The maximum stops growth
+	pick, _ = pickSegment(scored, nil, 60, 5, 15)
+	if pick.start != 20 || pick.end != 30 {
+		t.Errorf("pickSegment() with a 15s maximum = %v-%v, want 20-30", pick.start, pick.end)
+	}
+
+	if _, err := pickSegment(scoreSegments(testSegments(), "quantum", nil), nil, 60, 15, 60); err == nil {
+		t.Error("Expected an error when nothing matches")
+	}
+}
+
+func TestPickSegmentVisualOnly(t *testing.T) {
+	visual := []vision.VisualSearchMatch{{Timestamp: 20, Confidence: 0.5}, {Timestamp: 95, Confidence: 0.9}}
+	pick, err := pickSegment(nil, visual, 100, 20, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pick.start != 80 || pick.end != 100 {
+		t.Errorf("pickSegment() = %v-%v, want 80-100 kept inside the video", pick.start, pick.end)
+	}
+}
+
+func TestShiftSegments(t *testing.T) {
+	segments := []transcript.Segment{
+		{Start: 5, End: 12, Text: "cut off start", Words: []transcript.Word{{Word: "cut", Start: 5, End: 6}, {Word: "off", Start: 10.5, End: 11}, {Word: "start", Start: 11, End: 12}}},
+		{Start: 12, End: 18, Text: "kept", Words: []transcript.Word{{Word: "kept", Start: 12, End: 18}}},
+		{Start: 18, End: 25, Text: "outside"},
+	}
+
+	got := shiftSegments(segments, 10, 15)
+	if len(got) != 2 {
+		t.Fatalf("shiftSegments() = %+v, want 2 segments", got)
+	}
+	want := []transcript.Word{{Word: "off", Start: 0.5, End: 1}, {Word: "start", Start: 1, End: 2}}
+	if !reflect.DeepEqual(got[0].Words, want) || got[0].Start != 0 {
+		t.Errorf("shiftSegments()[0] = %+v", got[0])
+	}
+	if got[1].End != 5 || got[1].Words[0].End != 5 {
+		t.Errorf("Expected words clipped to the end of the short, got %+v", got[1])
+	}
+}
+
+func TestEncodeArgs(t *testing.T) {
+	args := strings.Join(encodeArgs(Platforms["reels"]), " ")
+	for _, want := range []string{"-crf 21", "-maxrate 5000k", "-bufsize 10000k", "-r 30", "-b:a 128k", "+faststart"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("Expected %q in %q", want, args)
+		}
+	}
+	if strings.Contains(strings.Join(encodeArgs(Platforms["shorts"]), " "), "-r ") {
+		t.Error("Expected shorts to keep the source frame rate")
+	}
+}
@@ -0,0 +1,138 @@
+package stock
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/apiclient"
+)
+
+// pexelsPhoto is a photo in a Pexels search response
+type pexelsPhoto struct {
+	ID              int    `json:"id"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+	URL             string `json:"url"`
+	Photographer    string `json:"photographer"`
+	PhotographerURL string `json:"photographer_url"`
+	Alt             string `json:"alt"`
+	Src             struct {
+		Original string `json:"original"`
+		Large2x  string `json:"large2x"`
+	} `json:"src"`
+}
+
+// pexelsVideo is a video in a Pexels search response
+type pexelsVideo struct {
+	ID       int    `json:"id"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	URL      string `json:"url"`
+	Duration int    `json:"duration"`
+	User     struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"user"`
+	VideoFiles []struct {
+		Quality  string `json:"quality"`
+		FileType string `json:"file_type"`
+		Width    int    `json:"width"`
+		Height   int    `json:"height"`
+		Link     string `json:"link"`
+	} `json:"video_files"`
+}
+
+// searchPexels searches Pexels photos or videos
+func (c *Client) searchPexels(ctx context.Context, opts SearchOptions) ([]Item, error) {
+	params := url.Values{}
+	params.Set("query", opts.Query)
+	params.Set("per_page", strconv.Itoa(opts.Count))
+	if opts.Orientation != "" {
+		params.Set("orientation", opts.Orientation)
+	}
+	header := http.Header{}
+	header.Set("Authorization", c.config.PexelsAPIKey)
+
+	if opts.Type == TypeImage {
+		var resp struct {
+			Photos []pexelsPhoto `json:"photos"`
+		}
+		if err := getJSON(ctx, apiclient.ProviderPexels, queryURL(c.pexelsURL+"/v1/search", params), header, &resp); err != nil {
+			return nil, err
+		}
+		items := make([]Item, 0, len(resp.Photos))
+		for _, p := range resp.Photos {
+			download := p.Src.Large2x
+			if download == "" {
+				download = p.Src.Original
+			}
+			items = append(items, Item{
+				Provider:    ProviderPexels,
+				ID:          strconv.Itoa(p.ID),
+				Type:        TypeImage,
+				PageURL:     p.URL,
+				DownloadURL: download,
+				Width:       p.Width,
+				Height:      p.Height,
+				Author:      p.Photographer,
+				AuthorURL:   p.PhotographerURL,
+				Description: p.Alt,
+			})
+		}
+		return items, nil
+	}
+
+	var resp struct {
+		Videos []pexelsVideo `json:"videos"`
+	}
+	if err := getJSON(ctx, apiclient.ProviderPexels, queryURL(c.pexelsURL+"/videos/search", params), header, &resp); err != nil {
+		return nil, err
+	}
+	items := make([]Item, 0, len(resp.Videos))
+	for _, v := range resp.Videos {
+		// Take the widest MP4 that fits MaxWidth, or the narrowest if none do
+		best := -1
+		for i, f := range v.VideoFiles {
+			if f.Link == "" || (f.FileType != "" && !strings.Contains(f.FileType, "mp4")) {
+				continue
+			}
+			if best < 0 || betterWidth(f.Width, v.VideoFiles[best].Width, opts.MaxWidth) {
+				best = i
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		file := v.VideoFiles[best]
+		items = append(items, Item{
+			Provider:    ProviderPexels,
+			ID:          strconv.Itoa(v.ID),
+			Type:        TypeVideo,
+			PageURL:     v.URL,
+			DownloadURL: file.Link,
+			Width:       file.Width,
+			Height:      file.Height,
+			Duration:    float64(v.Duration),
+			Author:      v.User.Name,
+			AuthorURL:   v.User.URL,
+		})
+	}
+	return items, nil
+}
+
+// betterWidth reports whether width a is a better pick than b: the widest
+// that fits maxWidth, or the narrowest when neither fits
+func betterWidth(a, b, maxWidth int) bool {
+	aFits, bFits := a <= maxWidth, b <= maxWidth
+	if aFits != bFits {
+		return aFits
+	}
+	if aFits {
+		return a > b
+	}
+	return math.Abs(float64(a-maxWidth)) < math.Abs(float64(b-maxWidth))
+}
@@ -0,0 +1,160 @@
+package stock
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/apiclient"
+)
+
+// pixabayImage is an image in a Pixabay search response
+type pixabayImage struct {
+	ID            int    `json:"id"`
+	PageURL       string `json:"pageURL"`
+	Tags          string `json:"tags"`
+	LargeImageURL string `json:"largeImageURL"`
+	ImageWidth    int    `json:"imageWidth"`
+	ImageHeight   int    `json:"imageHeight"`
+	User          string `json:"user"`
+	UserID        int    `json:"user_id"`
+}
+
+// pixabayVideoFile is one rendition of a Pixabay video
+type pixabayVideoFile struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// pixabayVideo is a video in a Pixabay search response
+type pixabayVideo struct {
+	ID       int                         `json:"id"`
+	PageURL  string                      `json:"pageURL"`
+	Tags     string                      `json:"tags"`
+	Duration int                         `json:"duration"`
+	Videos   map[string]pixabayVideoFile `json:"videos"` // large, medium, small, tiny
+	User     string                      `json:"user"`
+	UserID   int                         `json:"user_id"`
+}
+
+// pixabayOrientations maps orientations to Pixabay's, which has no square
+var pixabayOrientations = map[string]string{
+	OrientationLandscape: "horizontal",
+	OrientationPortrait:  "vertical",
+}
+
+// searchPixabay searches Pixabay images or videos
+func (c *Client) searchPixabay(ctx context.Context, opts SearchOptions) ([]Item, error) {
+	params := url.Values{}
+	params.Set("key", c.config.PixabayAPIKey)
+	params.Set("q", opts.Query)
+	params.Set("safesearch", "true")
+	// Pixabay requires at least 3 results per page
+	params.Set("per_page", strconv.Itoa(max(opts.Count, 3)))
+
+	if opts.Type == TypeImage {
+		if o, ok := pixabayOrientations[opts.Orientation]; ok {
+			params.Set("orientation", o)
+		}
+		params.Set("image_type", "photo")
+		var resp struct {
+			Hits []pixabayImage `json:"hits"`
+		}
+		if err := getJSON(ctx, apiclient.ProviderPixabay, queryURL(c.pixabayURL+"/", params), nil, &resp); err != nil {
+			return nil, err
+		}
+		var items []Item
+		for _, h := range resp.Hits {
+			if len(items) == opts.Count {
+				break
+			}
+			if !matchesOrientation(h.ImageWidth, h.ImageHeight, opts.Orientation) {
+				continue
+			}
+			items = append(items, Item{
+				Provider:    ProviderPixabay,
+				ID:          strconv.Itoa(h.ID),
+				Type:        TypeImage,
+				PageURL:     h.PageURL,
+				DownloadURL: h.LargeImageURL,
+				Width:       h.ImageWidth,
+				Height:      h.ImageHeight,
+				Author:      h.User,
+				AuthorURL:   pixabayUserURL(h.User, h.UserID),
+				Tags:        splitTags(h.Tags),
+			})
+		}
+		return items, nil
+	}
+
+	var resp struct {
+		Hits []pixabayVideo `json:"hits"`
+	}
+	if err := getJSON(ctx, apiclient.ProviderPixabay, queryURL(c.pixabayURL+"/videos/", params), nil, &resp); err != nil {
+		return nil, err
+	}
+	var items []Item
+	for _, h := range resp.Hits {
+		if len(items) == opts.Count {
+			break
+		}
+		var file pixabayVideoFile
+		for _, f := range h.Videos {
+			if f.URL != "" && (file.URL == "" || betterWidth(f.Width, file.Width, opts.MaxWidth)) {
+				file = f
+			}
+		}
+		if file.URL == "" || !matchesOrientation(file.Width, file.Height, opts.Orientation) {
+			continue
+		}
+		items = append(items, Item{
+			Provider:    ProviderPixabay,
+			ID:          strconv.Itoa(h.ID),
+			Type:        TypeVideo,
+			PageURL:     h.PageURL,
+			DownloadURL: file.URL,
+			Width:       file.Width,
+			Height:      file.Height,
+			Duration:    float64(h.Duration),
+			Author:      h.User,
+			AuthorURL:   pixabayUserURL(h.User, h.UserID),
+			Tags:        splitTags(h.Tags),
+		})
+	}
+	return items, nil
+}
+
+// matchesOrientation checks a size against an optional orientation. Pixabay
+// cannot filter videos or square images itself.
+func matchesOrientation(width, height int, orientation string) bool {
+	switch orientation {
+	case OrientationLandscape:
+		return width > height
+	case OrientationPortrait:
+		return height > width
+	case OrientationSquare:
+		return width > 0 && height > 0 && float64(width)/float64(height) > 0.9 && float64(width)/float64(height) < 1.1
+	}
+	return true
+}
+
+// pixabayUserURL returns the profile page of a Pixabay user
+func pixabayUserURL(user string, id int) string {
+	if user == "" || id == 0 {
+		return ""
+	}
+	return "https://pixabay.com/users/" + url.PathEscape(user) + "-" + strconv.Itoa(id) + "/"
+}
+
+// splitTags splits Pixabay's comma-separated tag list
+func splitTags(tags string) []string {
+	var out []string
+	for _, t := range strings.Split(tags, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
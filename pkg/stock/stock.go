@@ -0,0 +1,339 @@
+// Package stock searches Pexels and Pixabay for free stock footage and
+// photos and downloads them into the asset library with their license
+package stock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/apiclient"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+)
+
+// Stock media providers
+const (
+	ProviderPexels  = "pexels"
+	ProviderPixabay = "pixabay"
+)
+
+// Providers lists the supported providers in search order
+var Providers = []string{ProviderPexels, ProviderPixabay}
+
+// Media types
+const (
+	TypeVideo = "video"
+	TypeImage = "image"
+)
+
+// Orientations
+const (
+	OrientationLandscape = "landscape"
+	OrientationPortrait  = "portrait"
+	OrientationSquare    = "square"
+)
+
+// Licenses of each provider. Both allow free commercial use and editing
+// without attribution, but not reselling unaltered copies.
+var licenses = map[string]License{
+	ProviderPexels:  {Name: "Pexels License", URL: "https://www.pexels.com/license/"},
+	ProviderPixabay: {Name: "Pixabay Content License", URL: "https://pixabay.com/service/license-summary/"},
+}
+
+// License names the terms an item is used under
+type License struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// SearchOptions contains parameters for a stock media search
+type SearchOptions struct {
+	Query       string
+	Type        string  // video (default) or image
+	Provider    string  // pexels or pixabay (default: every provider with a key)
+	Orientation string  // landscape, portrait or square (optional)
+	Count       int     // Results per provider (default: 5, at most 20)
+	MinDuration float64 // Shortest video in seconds (optional)
+	MaxDuration float64 // Longest video in seconds (optional)
+	MaxWidth    int     // Widest video file to download (default: 1920)
+	Download    bool    // Save results into Folder
+	Folder      string  // Download folder (default: <assetDir>/stock)
+}
+
+// Item is a stock photo or clip
+type Item struct {
+	Provider    string   `json:"provider"`
+	ID          string   `json:"id"`
+	Type        string   `json:"type"`
+	PageURL     string   `json:"pageUrl"`
+	DownloadURL string   `json:"downloadUrl"`
+	Width       int      `json:"width"`
+	Height      int      `json:"height"`
+	Duration    float64  `json:"duration,omitempty"` // Seconds, videos only
+	Author      string   `json:"author"`
+	AuthorURL   string   `json:"authorUrl,omitempty"`
+	Description string   `json:"description,omitempty"`
+	License     License  `json:"license"`
+	Attribution string   `json:"attribution"`       // Credit line for descriptions or end cards
+	Path        string   `json:"path,omitempty"`    // Local file once downloaded
+	Tags        []string `json:"tags,omitempty"`    // Search terms the provider matched
+	Query       string   `json:"query,omitempty"`   // Search that found the item
+	Fetched     string   `json:"fetched,omitempty"` // When it was downloaded (RFC 3339)
+}
+
+// Result lists the items found
+type Result struct {
+	Query    string   `json:"query"`
+	Items    []Item   `json:"items"`
+	Folder   string   `json:"folder,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Client searches and downloads stock media
+type Client struct {
+	config     *config.Config
+	pexelsURL  string
+	pixabayURL string
+}
+
+// NewClient creates a stock media client. Keys are read from cfg on each
+// call so set_config changes apply immediately.
+func NewClient(cfg *config.Config) *Client {
+	return &Client{
+		config:     cfg,
+		pexelsURL:  "https://api.pexels.com",
+		pixabayURL: "https://pixabay.com/api",
+	}
+}
+
+// Folder returns the default download folder
+func (c *Client) Folder() string {
+	assetDir := c.config.AssetPath()
+	if assetDir == "" {
+		cwd, _ := os.Getwd()
+		assetDir = filepath.Join(cwd, ".mcp-video-assets")
+	}
+	return filepath.Join(assetDir, "stock")
+}
+
+// Search queries the chosen provider, or every provider with a key, and
+// optionally downloads the results. A provider that fails is reported as a
+// warning unless none succeed.
+func (c *Client) Search(ctx context.Context, opts SearchOptions) (*Result, error) {
+	opts.Query = strings.TrimSpace(opts.Query)
+	if opts.Query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if opts.Type == "" {
+		opts.Type = TypeVideo
+	}
+	if opts.Type != TypeVideo && opts.Type != TypeImage {
+		return nil, fmt.Errorf("unknown type: %s (use video or image)", opts.Type)
+	}
+	switch opts.Orientation {
+	case "", OrientationLandscape, OrientationPortrait, OrientationSquare:
+	default:
+		return nil, fmt.Errorf("unknown orientation: %s (use landscape, portrait or square)", opts.Orientation)
+	}
+	if opts.Count <= 0 {
+		opts.Count = 5
+	}
+	opts.Count = min(opts.Count, 20)
+	if opts.MaxWidth <= 0 {
+		opts.MaxWidth = 1920
+	}
+
+	providers, err := c.providers(opts.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Query: opts.Query, Items: []Item{}}
+	var lastErr error
+	searched := 0
+	for _, provider := range providers {
+		var items []Item
+		var err error
+		if provider == ProviderPexels {
+			items, err = c.searchPexels(ctx, opts)
+		} else {
+			items, err = c.searchPixabay(ctx, opts)
+		}
+		if err != nil {
+			lastErr = err
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s search failed: %v", provider, err))
+			continue
+		}
+		searched++
+		for _, item := range items {
+			if opts.Type == TypeVideo && !withinDuration(item.Duration, opts.MinDuration, opts.MaxDuration) {
+				continue
+			}
+			item.License = licenses[provider]
+			item.Attribution = attribution(item)
+			item.Query = opts.Query
+			result.Items = append(result.Items, item)
+		}
+	}
+	if searched == 0 {
+		return nil, lastErr
+	}
+
+	if opts.Download {
+		result.Folder = opts.Folder
+		if result.Folder == "" {
+			result.Folder = c.Folder()
+		}
+		if err := os.MkdirAll(result.Folder, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create folder: %w", err)
+		}
+		for i := range result.Items {
+			if err := c.download(ctx, &result.Items[i], result.Folder); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s %s not downloaded: %v", result.Items[i].Provider, result.Items[i].ID, err))
+			}
+		}
+	}
+	return result, nil
+}
+
+// providers returns the named provider, or every provider with a key
+func (c *Client) providers(name string) ([]string, error) {
+	keys := map[string]string{ProviderPexels: c.config.PexelsAPIKey, ProviderPixabay: c.config.PixabayAPIKey}
+	if name != "" {
+		key, ok := keys[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown provider: %s (use pexels or pixabay)", name)
+		}
+		if key == "" {
+			return nil, fmt.Errorf("no %s API key configured. Set %s_API_KEY or %sKey in config", name, strings.ToUpper(name), strings.ToLower(name))
+		}
+		return []string{strings.ToLower(name)}, nil
+	}
+
+	var configured []string
+	for _, p := range Providers {
+		if keys[p] != "" {
+			configured = append(configured, p)
+		}
+	}
+	if len(configured) == 0 {
+		return nil, fmt.Errorf("stock media search not configured. Set PEXELS_API_KEY or PIXABAY_API_KEY (or pexelsKey/pixabayKey in config)")
+	}
+	return configured, nil
+}
+
+// download saves an item into folder as <provider>-<id>.<ext> and writes
+// its license and attribution to a JSON sidecar. Files already downloaded
+// are reused.
+func (c *Client) download(ctx context.Context, item *Item, folder string) error {
+	ext := filepath.Ext(strings.SplitN(item.DownloadURL, "?", 2)[0])
+	if ext == "" || len(ext) > 5 {
+		ext = ".mp4"
+		if item.Type == TypeImage {
+			ext = ".jpg"
+		}
+	}
+	path := filepath.Join(folder, fmt.Sprintf("%s-%s%s", item.Provider, item.ID, ext))
+
+	if _, err := os.Stat(path); err != nil {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, item.DownloadURL, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+
+		// Write to a temporary name so an interrupted download is not reused
+		tmp := path + ".part"
+		file, err := os.Create(tmp)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(file, resp.Body); err != nil {
+			file.Close()
+			os.Remove(tmp)
+			return err
+		}
+		if err := file.Close(); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return err
+		}
+	}
+
+	item.Path = path
+	item.Fetched = time.Now().UTC().Format(time.RFC3339)
+	meta, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path+".json", meta, 0644)
+}
+
+// getJSON fetches a provider API URL through the provider's request policy
+func getJSON(ctx context.Context, provider, rawURL string, header http.Header, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := apiclient.HTTPClient(provider).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 300))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// withinDuration reports whether a clip length is inside the optional bounds
+func withinDuration(duration, minDuration, maxDuration float64) bool {
+	if minDuration > 0 && duration < minDuration {
+		return false
+	}
+	if maxDuration > 0 && duration > maxDuration {
+		return false
+	}
+	return true
+}
+
+// attribution returns a credit line such as "Video by Jane Doe on Pexels"
+func attribution(item Item) string {
+	kind := "Video"
+	if item.Type == TypeImage {
+		kind = "Photo"
+	}
+	site := "Pexels"
+	if item.Provider == ProviderPixabay {
+		site = "Pixabay"
+	}
+	if item.Author == "" {
+		return fmt.Sprintf("%s from %s", kind, site)
+	}
+	return fmt.Sprintf("%s by %s on %s", kind, item.Author, site)
+}
+
+// queryURL joins a base URL and query parameters
+func queryURL(base string, params url.Values) string {
+	return base + "?" + params.Encode()
+}
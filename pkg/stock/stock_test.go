@@ -0,0 +1,166 @@
+package stock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+)
+
+func TestBetterWidth(t *testing.T) {
+	tests := []struct {
+		a, b int
+		want bool
+	}{
+		{1920, 1280, true},  // Wider and both fit
+		{3840, 1920, false}, // Too wide
+		{1280, 3840, true},  // Only a fits
+		{2560, 3840, true},  // Neither fits, a is closer
+	}
+	for _, tt := range tests {
+		if got := betterWidth(tt.a, tt.b, 1920); got != tt.want {
+			t.Errorf("betterWidth(%d, %d) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesOrientation(t *testing.T) {
+	if !matchesOrientation(1920, 1080, OrientationLandscape) || matchesOrientation(1080, 1920, OrientationLandscape) {
+		t.Error("Landscape check failed")
+	}
+	if !matchesOrientation(1080, 1080, OrientationSquare) || matchesOrientation(1920, 1080, OrientationSquare) {
+		t.Error("Square check failed")
+	}
+	if !matchesOrientation(1080, 1920, "") {
+		t.Error("Expected no orientation to match anything")
+	}
+}
+
+func TestProvidersRequireKey(t *testing.T) {
+	c := NewClient(&config.Config{PixabayAPIKey: "key"})
+	got, err := c.providers("")
+	if err != nil || len(got) != 1 || got[0] != ProviderPixabay {
+		t.Errorf("Expected only pixabay, got %v (%v)", got, err)
+	}
+	if _, err := c.providers("pexels"); err == nil {
+		t.Error("Expected error for pexels without a key")
+	}
+	if _, err := c.providers("shutterstock"); err == nil {
+		t.Error("Expected error for unknown provider")
+	}
+	if _, err := NewClient(&config.Config{}).providers(""); err == nil {
+		t.Error("Expected error with no keys")
+	}
+}
+
+func TestSearchAndDownload(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/videos/search":
+			if r.Header.Get("Authorization") != "pexels-key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprintf(w, `{"videos": [
+				{"id": 1, "url": "https://www.pexels.com/video/1/", "duration": 12, "user": {"name": "Jane Doe", "url": "https://www.pexels.com/@jane"},
+				 "video_files": [
+					{"file_type": "video/mp4", "width": 3840, "height": 2160, "link": "%[1]s/files/1-4k.mp4"},
+					{"file_type": "video/mp4", "width": 1920, "height": 1080, "link": "%[1]s/files/1-hd.mp4"},
+					{"file_type": "video/mp4", "width": 640, "height": 360, "link": "%[1]s/files/1-sd.mp4"}]},
+				{"id": 2, "url": "https://www.pexels.com/video/2/", "duration": 90, "user": {"name": "John Roe"},
+				 "video_files": [{"file_type": "video/mp4", "width": 1920, "height": 1080, "link": "%[1]s/files/2.mp4"}]}
+			]}`, server.URL)
+		case "/api/videos/":
+			if r.URL.Query().Get("key") != "pixabay-key" || r.URL.Query().Get("per_page") != "3" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintf(w, `{"hits": [
+				{"id": 7, "pageURL": "https://pixabay.com/videos/7/", "tags": "ocean, waves", "duration": 20, "user": "sea", "user_id": 9,
+				 "videos": {"large": {"url": "%[1]s/files/7.mp4", "width": 1920, "height": 1080}, "tiny": {"url": "%[1]s/files/7-tiny.mp4", "width": 640, "height": 360}}}
+			]}`, server.URL)
+		case "/files/1-hd.mp4", "/files/7.mp4":
+			w.Write([]byte("video"))
+		default:
+			t.Errorf("Unexpected request: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(&config.Config{PexelsAPIKey: "pexels-key", PixabayAPIKey: "pixabay-key"})
+	c.pexelsURL = server.URL
+	c.pixabayURL = server.URL + "/api"
+
+	folder := t.TempDir()
+	result, err := c.Search(context.Background(), SearchOptions{Query: "ocean", Count: 2, MaxDuration: 60, Download: true, Folder: folder})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(result.Warnings) > 0 {
+		t.Errorf("Unexpected warnings: %v", result.Warnings)
+	}
+	// The 90 second Pexels clip is over MaxDuration
+	if len(result.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(result.Items))
+	}
+
+	pexels := result.Items[0]
+	if pexels.Provider != ProviderPexels || pexels.Width != 1920 || !strings.HasSuffix(pexels.DownloadURL, "1-hd.mp4") {
+		t.Errorf("Expected the 1920px Pexels file, got %+v", pexels)
+	}
+	if pexels.License.Name != "Pexels License" || pexels.Attribution != "Video by Jane Doe on Pexels" {
+		t.Errorf("Unexpected license tags: %+v / %q", pexels.License, pexels.Attribution)
+	}
+	if pexels.Path != filepath.Join(folder, "pexels-1.mp4") {
+		t.Errorf("Unexpected path: %s", pexels.Path)
+	}
+
+	pixabay := result.Items[1]
+	if pixabay.Width != 1920 || len(pixabay.Tags) != 2 || pixabay.AuthorURL != "https://pixabay.com/users/sea-9/" {
+		t.Errorf("Unexpected Pixabay item: %+v", pixabay)
+	}
+
+	data, err := os.ReadFile(pixabay.Path + ".json")
+	if err != nil {
+		t.Fatalf("Expected license sidecar: %v", err)
+	}
+	var sidecar Item
+	if err := json.Unmarshal(data, &sidecar); err != nil || sidecar.License.URL != licenses[ProviderPixabay].URL || sidecar.Query != "ocean" {
+		t.Errorf("Unexpected sidecar: %s", data)
+	}
+}
+
+func TestSearchProviderFailureIsWarning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/search" {
+			w.Write([]byte(`{"photos": [{"id": 5, "url": "https://www.pexels.com/photo/5/", "width": 4000, "height": 3000, "photographer": "Ann", "src": {"large2x": "https://images.pexels.com/5.jpeg"}}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	c := NewClient(&config.Config{PexelsAPIKey: "a", PixabayAPIKey: "b"})
+	c.pexelsURL = server.URL
+	c.pixabayURL = server.URL + "/api"
+
+	result, err := c.Search(context.Background(), SearchOptions{Query: "desk", Type: TypeImage})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(result.Items) != 1 || result.Items[0].Attribution != "Photo by Ann on Pexels" {
+		t.Errorf("Unexpected items: %+v", result.Items)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "pixabay") {
+		t.Errorf("Expected a pixabay warning, got %v", result.Warnings)
+	}
+}
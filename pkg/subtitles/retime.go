@@ -0,0 +1,149 @@
+package subtitles
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RetimeFunc maps a cue time in seconds to a new time
+type RetimeFunc func(seconds float64) float64
+
+// Shift returns a RetimeFunc that moves every cue by offset seconds
+func Shift(offset float64) RetimeFunc {
+	return func(t float64) float64 { return t + offset }
+}
+
+// Scale returns a RetimeFunc for a video whose playback speed changed by
+// speed (2.0 means the video now plays twice as fast)
+func Scale(speed float64) RetimeFunc {
+	return func(t float64) float64 { return t / speed }
+}
+
+// Retime applies fn to every cue. Cues that end at or before zero are
+// dropped and cues that now start before zero are clipped.
+func (d *Document) Retime(fn RetimeFunc) {
+	cues := d.Cues[:0]
+	for _, cue := range d.Cues {
+		cue.Start, cue.End = fn(cue.Start), fn(cue.End)
+		if cue.End <= 0 {
+			continue
+		}
+		if cue.Start < 0 {
+			cue.Start = 0
+		}
+		cues = append(cues, cue)
+	}
+	d.Cues = cues
+}
+
+// RetimeFile retimes a subtitle file and writes it in the same format. ASS
+// scripts are edited line by line so styles and positioning survive.
+// It returns the number of cues written.
+func RetimeFile(input, output string, fn RetimeFunc) (int, error) {
+	format, err := FormatFromPath(input)
+	if err != nil {
+		return 0, err
+	}
+	if outFormat, err := FormatFromPath(output); err != nil {
+		return 0, err
+	} else if outFormat != format {
+		return 0, fmt.Errorf("output must use the same format as the input (%s); use convert_subtitles to change format", format)
+	}
+
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read subtitles: %w", err)
+	}
+
+	var content string
+	var count int
+	if format == FormatASS {
+		content, count, err = retimeASS(string(data), fn)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		doc, err := Parse(string(data), format)
+		if err != nil {
+			return 0, err
+		}
+		doc.Retime(fn)
+		if content, err = doc.Render(format); err != nil {
+			return 0, err
+		}
+		count = len(doc.Cues)
+	}
+
+	if err := os.WriteFile(output, []byte(content), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write subtitles: %w", err)
+	}
+	return count, nil
+}
+
+// retimeASS rewrites the Start and End fields of Dialogue lines in place
+func retimeASS(content string, fn RetimeFunc) (string, int, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	startField, endField := 1, 2
+	kept := lines[:0]
+	count := 0
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(trimmed, "Format:"); ok {
+			for i, f := range strings.Split(rest, ",") {
+				switch strings.ToLower(strings.TrimSpace(f)) {
+				case "start":
+					startField = i
+				case "end":
+					endField = i
+				}
+			}
+			kept = append(kept, line)
+			continue
+		}
+
+		rest, ok := strings.CutPrefix(trimmed, "Dialogue:")
+		if !ok {
+			kept = append(kept, line)
+			continue
+		}
+
+		values := strings.SplitN(strings.TrimSpace(rest), ",", max(startField, endField)+2)
+		if len(values) <= max(startField, endField) {
+			return "", 0, fmt.Errorf("invalid Dialogue line: %q", line)
+		}
+		start, err := parseTimestamp(values[startField])
+		if err != nil {
+			return "", 0, err
+		}
+		end, err := parseTimestamp(values[endField])
+		if err != nil {
+			return "", 0, err
+		}
+
+		start, end = fn(start), fn(end)
+		if end <= 0 {
+			continue
+		}
+		values[startField] = formatASSTimestamp(start)
+		values[endField] = formatASSTimestamp(end)
+		kept = append(kept, "Dialogue: "+strings.Join(values, ","))
+		count++
+	}
+
+	return strings.Join(kept, "\n"), count, nil
+}
+
+// ConvertFile converts a subtitle file to the format of the output extension.
+// It returns the number of cues written.
+func ConvertFile(input, output string) (int, error) {
+	doc, err := ReadFile(input)
+	if err != nil {
+		return 0, err
+	}
+	if err := doc.WriteFile(output); err != nil {
+		return 0, fmt.Errorf("failed to write subtitles: %w", err)
+	}
+	return len(doc.Cues), nil
+}
@@ -0,0 +1,301 @@
+package subtitles
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Supported subtitle formats
+const (
+	FormatSRT = "srt"
+	FormatVTT = "vtt"
+	FormatASS = "ass"
+)
+
+// Cue is a single timed subtitle
+type Cue struct {
+	Start    float64 // seconds
+	End      float64 // seconds
+	Text     string  // Lines separated by "\n"; SRT/VTT inline tags are kept
+	Settings string  // WebVTT cue settings, e.g. "align:start line:90%"
+}
+
+// Document is a parsed subtitle file
+type Document struct {
+	Format string
+	Header string // WebVTT blocks before the first cue (STYLE, REGION, NOTE)
+	Cues   []Cue
+}
+
+var (
+	timestampPattern = regexp.MustCompile(`^(?:(\d+):)?(\d{1,2}):(\d{1,2})(?:[,.](\d{1,3}))?$`)
+	cueTimingPattern = regexp.MustCompile(`^\s*(\S+)\s+-->\s+(\S+)\s*(.*)$`)
+	assOverrideBlock = regexp.MustCompile(`\{[^}]*\}`)
+	vttTagPattern    = regexp.MustCompile(`</?(?:c|v|lang|ruby|rt)(?:[.\s][^>]*)?>|<\d[\d:.]*>`)
+	htmlTagPattern   = regexp.MustCompile(`<[^>]+>`)
+)
+
+// FormatFromPath returns the subtitle format for a file extension
+func FormatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".srt":
+		return FormatSRT, nil
+	case ".vtt":
+		return FormatVTT, nil
+	case ".ass", ".ssa":
+		return FormatASS, nil
+	default:
+		return "", fmt.Errorf("unsupported subtitle format: %s. Supported: .srt, .vtt, .ass", filepath.Ext(path))
+	}
+}
+
+// ReadFile parses a subtitle file, detecting the format from its extension
+func ReadFile(path string) (*Document, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read subtitles: %w", err)
+	}
+	return Parse(string(data), format)
+}
+
+// Parse parses subtitle content in the given format
+func Parse(content, format string) (*Document, error) {
+	content = strings.TrimPrefix(content, "\ufeff")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	switch format {
+	case FormatSRT, FormatVTT:
+		return parseBlocks(content, format)
+	case FormatASS:
+		return parseASS(content)
+	default:
+		return nil, fmt.Errorf("unsupported subtitle format: %s", format)
+	}
+}
+
+// parseBlocks parses SRT and WebVTT, which both use blank-line separated
+// blocks with a "start --> end" timing line
+func parseBlocks(content, format string) (*Document, error) {
+	doc := &Document{Format: format}
+	blocks := strings.Split(strings.TrimSpace(content), "\n\n")
+
+	var header []string
+	for i, block := range blocks {
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+		if format == FormatVTT && i == 0 && strings.HasPrefix(lines[0], "WEBVTT") {
+			continue
+		}
+
+		timing := -1
+		for j, line := range lines {
+			if strings.Contains(line, "-->") {
+				timing = j
+				break
+			}
+		}
+		if timing < 0 {
+			// STYLE, REGION and NOTE blocks precede the cues in WebVTT
+			if format == FormatVTT && len(doc.Cues) == 0 {
+				header = append(header, strings.Join(lines, "\n"))
+			}
+			continue
+		}
+
+		m := cueTimingPattern.FindStringSubmatch(lines[timing])
+		if m == nil {
+			return nil, fmt.Errorf("invalid cue timing: %q", lines[timing])
+		}
+		start, err := parseTimestamp(m[1])
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseTimestamp(m[2])
+		if err != nil {
+			return nil, err
+		}
+
+		cue := Cue{Start: start, End: end, Text: strings.Join(lines[timing+1:], "\n")}
+		if format == FormatVTT {
+			cue.Settings = m[3]
+		}
+		doc.Cues = append(doc.Cues, cue)
+	}
+
+	doc.Header = strings.Join(header, "\n\n")
+	return doc, nil
+}
+
+// parseASS reads Dialogue events from an ASS/SSA script
+func parseASS(content string) (*Document, error) {
+	doc := &Document{Format: FormatASS}
+	fields := []string{"layer", "start", "end", "style", "name", "marginl", "marginr", "marginv", "effect", "text"}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(line, "Format:"); ok {
+			fields = nil
+			for _, f := range strings.Split(rest, ",") {
+				fields = append(fields, strings.ToLower(strings.TrimSpace(f)))
+			}
+			continue
+		}
+		rest, ok := strings.CutPrefix(line, "Dialogue:")
+		if !ok {
+			continue
+		}
+
+		values := strings.SplitN(strings.TrimSpace(rest), ",", len(fields))
+		if len(values) < len(fields) {
+			continue
+		}
+		var cue Cue
+		for i, name := range fields {
+			var err error
+			switch name {
+			case "start":
+				cue.Start, err = parseTimestamp(values[i])
+			case "end":
+				cue.End, err = parseTimestamp(values[i])
+			case "text":
+				cue.Text = assToTaggedText(values[i])
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		doc.Cues = append(doc.Cues, cue)
+	}
+
+	return doc, nil
+}
+
+// parseTimestamp parses SRT (00:00:01,500), WebVTT (00:01.500) and ASS (0:00:01.50) times
+func parseTimestamp(s string) (float64, error) {
+	m := timestampPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid timestamp: %q", s)
+	}
+	hours, _ := strconv.Atoi(m[1])
+	mins, _ := strconv.Atoi(m[2])
+	secs, _ := strconv.Atoi(m[3])
+	fraction := 0.0
+	if m[4] != "" {
+		v, _ := strconv.Atoi(m[4])
+		fraction = float64(v) / math.Pow(10, float64(len(m[4])))
+	}
+	return float64(hours*3600+mins*60+secs) + fraction, nil
+}
+
+// Render writes the document in the given format
+func (d *Document) Render(format string) (string, error) {
+	var b strings.Builder
+
+	switch format {
+	case FormatSRT:
+		for i, cue := range d.Cues {
+			fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1,
+				formatTimestamp(cue.Start, ","), formatTimestamp(cue.End, ","), toSRTText(cue.Text, d.Format))
+		}
+
+	case FormatVTT:
+		b.WriteString("WEBVTT\n\n")
+		if d.Format == FormatVTT && d.Header != "" {
+			b.WriteString(d.Header + "\n\n")
+		}
+		for _, cue := range d.Cues {
+			timing := formatTimestamp(cue.Start, ".") + " --> " + formatTimestamp(cue.End, ".")
+			if cue.Settings != "" {
+				timing += " " + cue.Settings
+			}
+			fmt.Fprintf(&b, "%s\n%s\n\n", timing, cue.Text)
+		}
+
+	case FormatASS:
+		b.WriteString(defaultASSHeader)
+		for _, cue := range d.Cues {
+			fmt.Fprintf(&b, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n",
+				formatASSTimestamp(cue.Start), formatASSTimestamp(cue.End), taggedTextToASS(cue.Text))
+		}
+
+	default:
+		return "", fmt.Errorf("unsupported subtitle format: %s", format)
+	}
+
+	return b.String(), nil
+}
+
+// WriteFile renders the document in the format matching the path's extension
+func (d *Document) WriteFile(path string) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+	content, err := d.Render(format)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+const defaultASSHeader = `[Script Info]
+ScriptType: v4.00+
+PlayResX: 1920
+PlayResY: 1080
+WrapStyle: 0
+ScaledBorderAndShadow: yes
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Default,Arial,64,&H00FFFFFF,&H000000FF,&H00000000,&H80000000,0,0,0,0,100,100,0,0,1,3,1,2,60,60,60,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+`
+
+// formatTimestamp formats seconds as HH:MM:SS,mmm (SRT) or HH:MM:SS.mmm (WebVTT)
+func formatTimestamp(seconds float64, sep string) string {
+	ms := int64(math.Round(math.Max(0, seconds) * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// formatASSTimestamp formats seconds as H:MM:SS.cc
+func formatASSTimestamp(seconds float64) string {
+	cs := int64(math.Round(math.Max(0, seconds) * 100))
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
+
+// toSRTText keeps the <i>, <b> and <u> tags SRT players understand
+func toSRTText(text, from string) string {
+	if from == FormatVTT {
+		text = vttTagPattern.ReplaceAllString(text, "")
+	}
+	return text
+}
+
+var (
+	assToTags = strings.NewReplacer(`{\i1}`, "<i>", `{\i0}`, "</i>", `{\b1}`, "<b>", `{\b0}`, "</b>", `{\u1}`, "<u>", `{\u0}`, "</u>", `\N`, "\n", `\n`, "\n", `\h`, " ")
+	tagsToASS = strings.NewReplacer("<i>", `{\i1}`, "</i>", `{\i0}`, "<b>", `{\b1}`, "</b>", `{\b0}`, "<u>", `{\u1}`, "</u>", `{\u0}`, "\n", `\N`)
+)
+
+// assToTaggedText converts ASS event text to SRT-style text, dropping
+// positioning and other override tags that have no SRT equivalent
+func assToTaggedText(text string) string {
+	return assOverrideBlock.ReplaceAllString(assToTags.Replace(text), "")
+}
+
+// taggedTextToASS converts SRT/WebVTT text to ASS event text
+func taggedTextToASS(text string) string {
+	text = vttTagPattern.ReplaceAllString(text, "")
+	text = tagsToASS.Replace(text)
+	// Remove any remaining HTML-style tags (e.g. <font>) but keep ASS override blocks
+	return htmlTagPattern.ReplaceAllString(text, "")
+}
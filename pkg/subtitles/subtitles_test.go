@@ -0,0 +1,133 @@
+package subtitles
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleSRT = `1
+00:00:01,000 --> 00:00:03,500
+Hello <i>world</i>
+
+2
+00:01:02,250 --> 00:01:04,000
+Second line
+continues here
+`
+
+const sampleVTT = `WEBVTT
+
+STYLE
+::cue { color: yellow }
+
+00:01.000 --> 00:03.500 align:start
+<v Alice>Hello</v> there
+
+00:00:05.000 --> 00:00:06.000
+Bye
+`
+
+const sampleASS = `[Script Info]
+ScriptType: v4.00+
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize
+Style: Default,Arial,20
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+Dialogue: 0,0:00:01.00,0:00:03.50,Default,,0,0,0,,{\pos(10,10)}{\i1}Hello{\i0}, world\NNext
+`
+
+func TestParseSRT(t *testing.T) {
+	doc, err := Parse(sampleSRT, FormatSRT)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(doc.Cues) != 2 {
+		t.Fatalf("Expected 2 cues, got %d", len(doc.Cues))
+	}
+	if doc.Cues[1].Start != 62.25 || doc.Cues[1].Text != "Second line\ncontinues here" {
+		t.Errorf("Unexpected cue: %+v", doc.Cues[1])
+	}
+}
+
+func TestParseVTT(t *testing.T) {
+	doc, err := Parse(sampleVTT, FormatVTT)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(doc.Cues) != 2 || doc.Cues[0].Settings != "align:start" || !strings.Contains(doc.Header, "STYLE") {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+
+	srt, _ := doc.Render(FormatSRT)
+	if !strings.Contains(srt, "00:00:01,000 --> 00:00:03,500\nHello there") {
+		t.Errorf("Expected voice tags stripped in SRT output:\n%s", srt)
+	}
+}
+
+func TestConvertASS(t *testing.T) {
+	doc, err := Parse(sampleASS, FormatASS)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(doc.Cues) != 1 || doc.Cues[0].Text != "<i>Hello</i>, world\nNext" {
+		t.Fatalf("Unexpected cues: %+v", doc.Cues)
+	}
+
+	vtt, _ := doc.Render(FormatVTT)
+	if !strings.HasPrefix(vtt, "WEBVTT\n\n00:00:01.000 --> 00:00:03.500\n<i>Hello</i>, world\nNext") {
+		t.Errorf("Unexpected VTT output:\n%s", vtt)
+	}
+
+	srtDoc, _ := Parse(sampleSRT, FormatSRT)
+	ass, _ := srtDoc.Render(FormatASS)
+	if !strings.Contains(ass, `Dialogue: 0,0:00:01.00,0:00:03.50,Default,,0,0,0,,Hello {\i1}world{\i0}`) ||
+		!strings.Contains(ass, `Second line\Ncontinues here`) {
+		t.Errorf("Unexpected ASS output:\n%s", ass)
+	}
+}
+
+func TestRetime(t *testing.T) {
+	doc, _ := Parse(sampleSRT, FormatSRT)
+	doc.Retime(Shift(-2))
+	if len(doc.Cues) != 2 || doc.Cues[0].Start != 0 || doc.Cues[0].End != 1.5 {
+		t.Errorf("Expected first cue clipped at zero, got %+v", doc.Cues[0])
+	}
+
+	doc.Retime(Shift(-10))
+	if len(doc.Cues) != 1 {
+		t.Errorf("Expected cue ending before zero to be dropped, got %+v", doc.Cues)
+	}
+
+	doc, _ = Parse(sampleSRT, FormatSRT)
+	doc.Retime(Scale(2))
+	if doc.Cues[1].Start != 31.125 {
+		t.Errorf("Expected times halved for 2x speed, got %+v", doc.Cues[1])
+	}
+}
+
+func TestRetimeFileASS(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.ass")
+	output := filepath.Join(dir, "out.ass")
+	if err := os.WriteFile(input, []byte(sampleASS), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := RetimeFile(input, output, Shift(1.5))
+	if err != nil {
+		t.Fatalf("RetimeFile failed: %v", err)
+	}
+	data, _ := os.ReadFile(output)
+	if count != 1 || !strings.Contains(string(data), `Dialogue: 0,0:00:02.50,0:00:05.00,Default,,0,0,0,,{\pos(10,10)}{\i1}Hello{\i0}, world\NNext`) {
+		t.Errorf("Expected styling preserved with shifted times, got:\n%s", data)
+	}
+
+	if _, err := RetimeFile(input, filepath.Join(dir, "out.srt"), Shift(1)); err == nil {
+		t.Error("Expected error when retiming into a different format")
+	}
+}
@@ -0,0 +1,108 @@
+package text
+
+import (
+	"context"
+	"fmt"
+)
+
+// Countdown display formats
+const (
+	CountdownMinutes = "mm:ss"
+	CountdownHours   = "hh:mm:ss"
+	CountdownSeconds = "ss"
+)
+
+// CountdownOptions contains options for a countdown timer overlay. Text is
+// ignored; the timer counts down between StartTime and EndTime.
+type CountdownOptions struct {
+	TextOverlayOptions
+	From   *float64 // Seconds shown at StartTime (default: EndTime - StartTime)
+	Format string   // mm:ss (default), hh:mm:ss or ss
+}
+
+// AddCountdown overlays a timer that counts down to zero over a time range
+func (o *Operations) AddCountdown(ctx context.Context, opts CountdownOptions) error {
+	fontFile, err := resolveFont(ctx, opts.FontFile, "")
+	if err != nil {
+		return err
+	}
+	opts.FontFile = fontFile
+	filter, err := o.buildCountdownFilter(opts)
+	if err != nil {
+		return err
+	}
+
+	args := []string{
+		"-i", opts.Input,
+		"-vf", filter,
+		"-c:a", "copy",
+		"-y",
+		opts.Output,
+	}
+
+	return o.ffmpeg.Execute(ctx, args...)
+}
+
+// buildCountdownFilter builds a drawtext filter whose text is evaluated per frame
+func (o *Operations) buildCountdownFilter(opts CountdownOptions) (string, error) {
+	start := 0.0
+	if opts.StartTime != nil {
+		start = *opts.StartTime
+	}
+	end := 0.0
+	switch {
+	case opts.EndTime != nil:
+		end = *opts.EndTime
+	case opts.Duration != nil:
+		end = start + *opts.Duration
+	default:
+		return "", fmt.Errorf("endTime or duration is required")
+	}
+	if end <= start {
+		return "", fmt.Errorf("endTime must be after startTime")
+	}
+
+	from := end - start
+	if opts.From != nil {
+		if *opts.From < 0 {
+			return "", fmt.Errorf("from must not be negative")
+		}
+		from = *opts.From
+	}
+
+	text, err := countdownText(start, from, opts.Format)
+	if err != nil {
+		return "", err
+	}
+
+	base := opts.TextOverlayOptions
+	base.StartTime, base.EndTime, base.Duration = &start, &end, nil
+	if base.FontSize == 0 {
+		base.FontSize = 64
+	}
+	if base.Position == "" && (base.X == "" || base.Y == "") {
+		base.Position = TopRight
+	}
+
+	return o.buildDrawTextFilterWithText(base, text), nil
+}
+
+// countdownText builds drawtext expansions that show the remaining time,
+// rounded up so the timer reads zero only when the range ends
+func countdownText(start, from float64, format string) (string, error) {
+	remaining := fmt.Sprintf("ceil(max(0,%.3f-(t-%.3f)))", from, start)
+	field := func(expr string) string {
+		return fmt.Sprintf(`%%{eif\:%s\:d\:2}`, expr)
+	}
+
+	switch format {
+	case "", CountdownMinutes:
+		return field("trunc("+remaining+"/60)") + `\:` + field("mod("+remaining+",60)"), nil
+	case CountdownHours:
+		return field("trunc("+remaining+"/3600)") + `\:` + field("mod(trunc("+remaining+"/60),60)") + `\:` + field("mod("+remaining+",60)"), nil
+	case CountdownSeconds:
+		return fmt.Sprintf(`%%{eif\:%s\:d}`, remaining), nil
+	default:
+		return "", fmt.Errorf("unknown countdown format: %s (use mm:ss, hh:mm:ss or ss)", format)
+	}
+}
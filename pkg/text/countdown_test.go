@@ -0,0 +1,47 @@
+package text
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountdownText(t *testing.T) {
+	text, err := countdownText(5, 90, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := `%{eif\:trunc(ceil(max(0,90.000-(t-5.000)))/60)\:d\:2}\:%{eif\:mod(ceil(max(0,90.000-(t-5.000))),60)\:d\:2}`
+	if text != want {
+		t.Errorf("countdownText() = %s, want %s", text, want)
+	}
+
+	if _, err := countdownText(0, 10, "days"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestBuildCountdownFilter(t *testing.T) {
+	o := &Operations{}
+	start, end := 10.0, 70.0
+	filter, err := o.buildCountdownFilter(CountdownOptions{
+		TextOverlayOptions: TextOverlayOptions{StartTime: &start, EndTime: &end},
+		Format:             CountdownSeconds,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`text='%{eif\:ceil(max(0,60.000-(t-10.000)))\:d}'`,
+		"x=w-text_w-10",
+		"fontsize=64",
+		"enable='between(t,10.00,70.00)'",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q:\n%s", want, filter)
+		}
+	}
+
+	if _, err := o.buildCountdownFilter(CountdownOptions{}); err == nil {
+		t.Error("Expected error without an end time")
+	}
+}
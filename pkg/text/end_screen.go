@@ -0,0 +1,370 @@
+package text
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// EndScreenPlatforms lists the platforms with end-screen safe areas
+var EndScreenPlatforms = []string{"youtube", "shorts", "tiktok", "reels", "generic"}
+
+// safeArea is the margin on each side of the frame, as fractions of the
+// frame size, that platform UI may cover
+type safeArea struct {
+	top, right, bottom, left float64
+}
+
+var endScreenSafeAreas = map[string]safeArea{
+	// YouTube places end-screen elements inside the frame with a small
+	// border and shows the player controls along the bottom
+	"youtube": {0.05, 0.05, 0.1, 0.05},
+	// Vertical platforms overlay the caption and handle at the bottom and
+	// the like/comment/share buttons down the right edge
+	"shorts":  {0.15, 0.16, 0.25, 0.05},
+	"tiktok":  {0.12, 0.18, 0.28, 0.05},
+	"reels":   {0.14, 0.14, 0.28, 0.05},
+	"generic": {0.05, 0.05, 0.05, 0.05},
+}
+
+// End-screen slot kinds
+const (
+	EndScreenVideo     = "video"
+	EndScreenSubscribe = "subscribe"
+)
+
+// EndScreenOptions contains options for composing an end screen
+type EndScreenOptions struct {
+	Input    string
+	Output   string
+	Platform string  // youtube (default), shorts, tiktok, reels or generic
+	Duration float64 // Seconds of end screen (default: 10; YouTube allows 5-20)
+	Append   bool    // Hold the last frame for Duration instead of covering the end of the video
+
+	// Content
+	Heading        string // Text above the placeholders (default: Thanks for watching)
+	VideoSlots     *int   // Next-video placeholders, 0-2 (default: 2)
+	Subscribe      *bool  // Show the subscribe placeholder (default: true)
+	VideoLabel     string // Caption under video placeholders (default: Watch next)
+	SubscribeLabel string // Caption under the subscribe placeholder (default: Subscribe)
+	PiP            string // Video played inside the first video placeholder (optional)
+	Logo           string // Image shown inside the subscribe circle (optional)
+
+	// Appearance
+	AccentColor string   // default: #E63946
+	TextColor   string   // default: white
+	Dim         *float64 // Darkening of the video behind the end screen, 0-1 (default: 0.6)
+	FontFile    string
+	FadeIn      float64 // seconds (default: 0.5)
+}
+
+// EndScreenSlot is the position of one placeholder in output pixels, for
+// lining up the platform's own end-screen elements
+type EndScreenSlot struct {
+	Kind   string `json:"kind"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// EndScreenResult describes a rendered end screen
+type EndScreenResult struct {
+	Start    float64         `json:"start"`
+	Duration float64         `json:"duration"`
+	Slots    []EndScreenSlot `json:"slots"`
+}
+
+// endScreenLayout holds pixel positions for an end screen
+type endScreenLayout struct {
+	headingX, headingY, headingSize int
+	labelSize, labelGap, border     int
+	slots                           []EndScreenSlot
+}
+
+// CreateEndScreen draws subscribe and next-video placeholders, a heading and
+// an optional picture-in-picture over the last seconds of a video (or over
+// a held last frame with Append), laid out inside the platform's safe area
+func (o *Operations) CreateEndScreen(ctx context.Context, opts EndScreenOptions) (*EndScreenResult, error) {
+	fontFile, err := resolveFont(ctx, opts.FontFile, opts.Heading+opts.VideoLabel+opts.SubscribeLabel+"Thanks for watching▶")
+	if err != nil {
+		return nil, err
+	}
+	opts.FontFile = fontFile
+
+	width, height, err := o.ffmpeg.ProbeDimensions(ctx, opts.Input)
+	if err != nil {
+		return nil, err
+	}
+	videoDuration, err := o.ffmpeg.ProbeDuration(ctx, opts.Input)
+	if err != nil {
+		return nil, err
+	}
+	filter, result, err := buildEndScreenFilter(opts, width, height, videoDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"-i", opts.Input}
+	if opts.PiP != "" {
+		args = append(args, "-i", opts.PiP)
+	}
+	if opts.Logo != "" {
+		args = append(args, "-loop", "1", "-i", opts.Logo)
+	}
+	args = append(args,
+		"-filter_complex", filter,
+		"-map", "[v]",
+		"-map", "0:a?",
+		"-c:a", "copy",
+		"-y",
+		opts.Output,
+	)
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// buildEndScreenFilter builds the filter graph for an end screen on a
+// width x height video lasting videoDuration seconds. The PiP, when set, is
+// input 1 and the logo follows it. The result is labelled [v].
+func buildEndScreenFilter(opts EndScreenOptions, width, height int, videoDuration float64) (string, *EndScreenResult, error) {
+	platform := opts.Platform
+	if platform == "" {
+		platform = "youtube"
+	}
+	area, ok := endScreenSafeAreas[platform]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown platform: %s (use %s)", platform, strings.Join(EndScreenPlatforms, ", "))
+	}
+	duration := opts.Duration
+	if duration <= 0 {
+		duration = 10
+	}
+	if platform == "youtube" && (duration < 5 || duration > 20) {
+		return "", nil, fmt.Errorf("YouTube end screens must last 5-20 seconds, got %.1f", duration)
+	}
+	videoSlots := 2
+	if opts.VideoSlots != nil {
+		videoSlots = *opts.VideoSlots
+	}
+	if videoSlots < 0 || videoSlots > 2 {
+		return "", nil, fmt.Errorf("videoSlots must be between 0 and 2")
+	}
+	if opts.PiP != "" && videoSlots == 0 {
+		return "", nil, fmt.Errorf("pip needs at least one video slot")
+	}
+	subscribe := opts.Subscribe == nil || *opts.Subscribe
+	if opts.Logo != "" && !subscribe {
+		return "", nil, fmt.Errorf("logo needs the subscribe placeholder")
+	}
+
+	start := videoDuration
+	if !opts.Append {
+		if duration > videoDuration {
+			return "", nil, fmt.Errorf("end screen (%.1fs) is longer than the video (%.1fs); use append to add it after the video", duration, videoDuration)
+		}
+		start = videoDuration - duration
+	}
+	end := start + duration
+
+	dim := 0.6
+	if opts.Dim != nil {
+		dim = math.Max(0, math.Min(1, *opts.Dim))
+	}
+	fadeIn := opts.FadeIn
+	if fadeIn <= 0 {
+		fadeIn = 0.5
+	}
+	fadeIn = math.Min(fadeIn, duration/2)
+	accent := colorOr(opts.AccentColor, "#E63946")
+	textColor := colorOr(opts.TextColor, "white")
+	videoLabel := opts.VideoLabel
+	if videoLabel == "" {
+		videoLabel = "Watch next"
+	}
+	subscribeLabel := opts.SubscribeLabel
+	if subscribeLabel == "" {
+		subscribeLabel = "Subscribe"
+	}
+	heading := opts.Heading
+	if heading == "" {
+		heading = "Thanks for watching"
+	}
+
+	l := layoutEndScreen(area, width, height, videoSlots, subscribe)
+	enable := fmt.Sprintf("between(t,%.3f,%.3f)", start, end)
+	fade := fmt.Sprintf(",fade=t=in:st=%.3f:d=%.3f:alpha=1", start, fadeIn)
+
+	var parts []string
+	current := "0:v"
+	if opts.Append {
+		parts = append(parts, fmt.Sprintf("[0:v]tpad=stop_mode=clone:stop_duration=%.3f[es_base]", duration))
+		current = "es_base"
+	}
+	overlay := func(label, source string, x, y int) {
+		parts = append(parts,
+			source+"["+label+"]",
+			fmt.Sprintf("[%s][%s]overlay=x=%d:y=%d:eof_action=pass:enable='%s'[%s_out]", current, label, x, y, enable, label))
+		current = label + "_out"
+	}
+
+	if dim > 0 {
+		overlay("es_dim", fmt.Sprintf("color=c=black@%.2f:s=%dx%d:r=30:d=%.3f,format=rgba%s", dim, width, height, end, fade), 0, 0)
+	}
+
+	pipInput, logoInput := 1, 1
+	if opts.PiP != "" {
+		logoInput = 2
+	}
+	var texts []string
+	drawText := func(text string, size int, x, y string, color string) {
+		params := []string{
+			fmt.Sprintf("text='%s'", escapeText(text)),
+			fmt.Sprintf("x='%s'", x),
+			fmt.Sprintf("y=%s", y),
+			fmt.Sprintf("fontsize=%d", size),
+			fmt.Sprintf("fontcolor=%s", color),
+			fmt.Sprintf("alpha='%s'", templateAlpha(1, start, end, fadeIn, 0)),
+			fmt.Sprintf("enable='%s'", enable),
+		}
+		if opts.FontFile != "" {
+			params = append(params, fmt.Sprintf("fontfile='%s'", opts.FontFile))
+		}
+		texts = append(texts, "drawtext="+strings.Join(params, ":"))
+	}
+
+	videoIndex := 0
+	for i, slot := range l.slots {
+		label := fmt.Sprintf("es_slot%d", i)
+		centerX := strconv.Itoa(slot.X + slot.Width/2)
+		labelY := strconv.Itoa(slot.Y + slot.Height + l.labelGap)
+		innerW, innerH := slot.Width-2*l.border, slot.Height-2*l.border
+
+		switch slot.Kind {
+		case EndScreenVideo:
+			source := fmt.Sprintf("color=c=white@0.12:s=%dx%d:r=30:d=%.3f,format=rgba", innerW, innerH, end)
+			if videoIndex == 0 && opts.PiP != "" {
+				// Fill the slot with the PiP, starting when the end screen does
+				// and holding its last frame if it is shorter
+				source = fmt.Sprintf("[%d:v]scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d,setsar=1,format=rgba,tpad=stop_mode=clone:stop_duration=%.3f,setpts=PTS-STARTPTS+%.3f/TB",
+					pipInput, innerW, innerH, innerW, innerH, duration, start)
+			} else {
+				drawText("▶", max(slot.Height/4, 1), centerX+"-text_w/2", strconv.Itoa(slot.Y+slot.Height/2)+"-text_h/2", textColor+"@0.8")
+			}
+			source += fmt.Sprintf(",pad=%d:%d:%d:%d:color=%s%s", slot.Width, slot.Height, l.border, l.border, accent, fade)
+			overlay(label, source, slot.X, slot.Y)
+			drawText(videoLabel, l.labelSize, centerX+"-text_w/2", labelY, textColor)
+			videoIndex++
+
+		case EndScreenSubscribe:
+			source := fmt.Sprintf("color=c=%s:s=%dx%d:r=30:d=%.3f,format=rgba", accent, slot.Width, slot.Height, end)
+			if opts.Logo != "" {
+				// Ring of accent around the logo, cropped to a circle
+				logo := fmt.Sprintf("es_logo%d", i)
+				parts = append(parts, fmt.Sprintf("[%d:v]scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d,format=rgba[%s]",
+					logoInput, innerW, innerH, innerW, innerH, logo))
+				source = fmt.Sprintf("%s[%s_bg];[%s_bg][%s]overlay=%d:%d:shortest=1", source, logo, logo, logo, l.border, l.border)
+			}
+			// Cut the square to a circle
+			source += ",geq=r='r(X,Y)':g='g(X,Y)':b='b(X,Y)':a='if(lte(hypot(X-W/2,Y-H/2),W/2),alpha(X,Y),0)'" + fade
+			overlay(label, source, slot.X, slot.Y)
+			drawText(subscribeLabel, l.labelSize, centerX+"-text_w/2", labelY, textColor)
+		}
+	}
+
+	drawText(heading, l.headingSize, strconv.Itoa(l.headingX)+"-text_w/2", strconv.Itoa(l.headingY), textColor)
+	parts = append(parts, fmt.Sprintf("[%s]%s[v]", current, strings.Join(texts, ",")))
+
+	return strings.Join(parts, ";"), &EndScreenResult{Start: start, Duration: duration, Slots: l.slots}, nil
+}
+
+// layoutEndScreen places the heading and placeholders inside the safe area.
+// Landscape frames put the placeholders in a row and portrait frames stack
+// them; video placeholders are 16:9 and the subscribe circle matches their
+// height.
+func layoutEndScreen(area safeArea, width, height, videoSlots int, subscribe bool) endScreenLayout {
+	left := int(math.Round(float64(width) * area.left))
+	top := int(math.Round(float64(height) * area.top))
+	safeW := width - left - int(math.Round(float64(width)*area.right))
+	safeH := height - top - int(math.Round(float64(height)*area.bottom))
+	short := float64(min(width, height))
+
+	l := endScreenLayout{
+		headingSize: max(int(math.Round(short*0.07)), 1),
+		labelSize:   max(int(math.Round(short*0.035)), 1),
+		labelGap:    max(int(math.Round(short*0.015)), 1),
+		border:      max(int(math.Round(short*0.005)), 2),
+	}
+	l.headingX = left + safeW/2
+	l.headingY = top
+
+	contentTop := top + l.headingSize*2
+	contentH := top + safeH - contentTop
+	labelH := l.labelGap + l.labelSize
+	gap := int(math.Round(short * 0.05))
+
+	// Widths relative to one video placeholder's height
+	var kinds []string
+	var units []float64
+	for range videoSlots {
+		kinds, units = append(kinds, EndScreenVideo), append(units, 16.0/9)
+	}
+	if subscribe {
+		kinds, units = append(kinds, EndScreenSubscribe), append(units, 1)
+	}
+	if len(kinds) == 0 {
+		return l
+	}
+
+	var size float64 // Placeholder height
+	if width >= height {
+		total := 0.0
+		for _, u := range units {
+			total += u
+		}
+		size = (float64(safeW) - float64(gap*(len(kinds)-1))) / total
+		size = math.Min(size, float64(contentH-labelH))
+		size = math.Floor(math.Min(size, float64(height)*0.3))
+
+		widths := make([]int, len(kinds))
+		rowW := gap * (len(kinds) - 1)
+		for i, u := range units {
+			widths[i] = int(size * u)
+			rowW += widths[i]
+		}
+		x := left + (safeW-rowW)/2
+		y := contentTop + (contentH-int(size)-labelH)/2
+		for i, kind := range kinds {
+			l.slots = append(l.slots, EndScreenSlot{Kind: kind, X: x, Y: y, Width: widths[i], Height: int(size)})
+			x += widths[i] + gap
+		}
+		return l
+	}
+
+	// Portrait: the subscribe circle is drawn smaller than the video slots
+	scales := make([]float64, len(kinds))
+	total := float64(gap*(len(kinds)-1) + labelH*len(kinds))
+	sizeUnits := 0.0
+	for i, kind := range kinds {
+		scales[i] = 1
+		if kind == EndScreenSubscribe {
+			scales[i] = 0.6
+		}
+		sizeUnits += scales[i]
+	}
+	size = math.Min(float64(safeW)*9/16, (float64(contentH)-total)/sizeUnits)
+
+	colH := int(math.Round(size*sizeUnits)) + int(total)
+	y := contentTop + (contentH-colH)/2
+	for i, kind := range kinds {
+		h := int(math.Round(size * scales[i]))
+		w := int(math.Round(float64(h) * units[i]))
+		l.slots = append(l.slots, EndScreenSlot{Kind: kind, X: left + (safeW-w)/2, Y: y, Width: w, Height: h})
+		y += h + labelH + gap
+	}
+	return l
+}
@@ -0,0 +1,111 @@
+package text
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildEndScreenFilterYouTube(t *testing.T) {
+	filter, result, err := buildEndScreenFilter(EndScreenOptions{Duration: 8}, 1920, 1080, 60)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Start != 52 || result.Duration != 8 {
+		t.Errorf("Expected end screen at 52s for 8s, got %.1f for %.1f", result.Start, result.Duration)
+	}
+	if len(result.Slots) != 3 {
+		t.Fatalf("Expected 2 video slots and a subscribe slot, got %+v", result.Slots)
+	}
+
+	for _, want := range []string{
+		"color=c=black@0.60:s=1920x1080",
+		"fade=t=in:st=52.000:d=0.500:alpha=1",
+		"enable='between(t,52.000,60.000)'",
+		"pad=",
+		"geq=r='r(X,Y)'",
+		"text='Thanks for watching'",
+		"text='Watch next'",
+		"text='Subscribe'",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q:\n%s", want, filter)
+		}
+	}
+	if !strings.HasSuffix(filter, "[v]") {
+		t.Errorf("Expected filter to end with [v]: %s", filter)
+	}
+}
+
+func TestBuildEndScreenFilterAppendWithPiP(t *testing.T) {
+	slots := 1
+	filter, result, err := buildEndScreenFilter(EndScreenOptions{
+		Platform:   "generic",
+		Duration:   4,
+		Append:     true,
+		VideoSlots: &slots,
+		PiP:        "next.mp4",
+		Logo:       "logo.png",
+	}, 1280, 720, 30)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Start != 30 {
+		t.Errorf("Expected appended end screen to start at 30s, got %.1f", result.Start)
+	}
+	for _, want := range []string{
+		"[0:v]tpad=stop_mode=clone:stop_duration=4.000[es_base]",
+		"[1:v]scale=",
+		"setpts=PTS-STARTPTS+30.000/TB",
+		"[2:v]scale=",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q:\n%s", want, filter)
+		}
+	}
+	if strings.Contains(filter, "text='▶'") {
+		t.Errorf("Expected no play glyph over the PiP:\n%s", filter)
+	}
+}
+
+func TestBuildEndScreenFilterErrors(t *testing.T) {
+	if _, _, err := buildEndScreenFilter(EndScreenOptions{Platform: "myspace"}, 1920, 1080, 60); err == nil {
+		t.Error("Expected error for unknown platform")
+	}
+	if _, _, err := buildEndScreenFilter(EndScreenOptions{Duration: 30}, 1920, 1080, 60); err == nil {
+		t.Error("Expected error for a YouTube end screen over 20s")
+	}
+	if _, _, err := buildEndScreenFilter(EndScreenOptions{Duration: 10}, 1920, 1080, 6); err == nil {
+		t.Error("Expected error for an end screen longer than the video")
+	}
+	none := 0
+	if _, _, err := buildEndScreenFilter(EndScreenOptions{VideoSlots: &none, PiP: "a.mp4"}, 1920, 1080, 60); err == nil {
+		t.Error("Expected error for a PiP without a video slot")
+	}
+}
+
+func TestLayoutEndScreenStaysInSafeArea(t *testing.T) {
+	for platform, area := range endScreenSafeAreas {
+		for _, size := range [][2]int{{1920, 1080}, {1080, 1920}, {1280, 720}} {
+			width, height := size[0], size[1]
+			l := layoutEndScreen(area, width, height, 2, true)
+			left, top := int(float64(width)*area.left), int(float64(height)*area.top)
+			right := width - int(float64(width)*area.right)
+			bottom := height - int(float64(height)*area.bottom)
+
+			for i, s := range l.slots {
+				if s.X < left || s.Y < top || s.X+s.Width > right || s.Y+s.Height+l.labelGap+l.labelSize > bottom {
+					t.Errorf("%s %dx%d: slot %d %+v outside safe area", platform, width, height, i, s)
+				}
+				if i > 0 {
+					prev := l.slots[i-1]
+					if prev.X+prev.Width > s.X && prev.Y+prev.Height > s.Y {
+						t.Errorf("%s %dx%d: slots %d and %d overlap", platform, width, height, i-1, i)
+					}
+				}
+			}
+			if l.slots[0].Kind != EndScreenVideo || l.slots[2].Kind != EndScreenSubscribe {
+				t.Errorf("Unexpected slot order: %+v", l.slots)
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package text
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/fonts"
+)
+
+// TextAlign aligns the lines of multi-line text within the text block
+type TextAlign string
+
+const (
+	AlignLeft   TextAlign = "left"
+	AlignCenter TextAlign = "center"
+	AlignRight  TextAlign = "right"
+)
+
+// resolveFont turns a font name into a file and switches to a fallback font
+// when text has characters the chosen font cannot draw
+func resolveFont(ctx context.Context, font, text string) (string, error) {
+	path := ""
+	if font != "" {
+		var err error
+		if path, err = fonts.Resolve(ctx, font); err != nil {
+			return "", err
+		}
+	}
+	if !fonts.NeedsFallback(text) {
+		return path, nil
+	}
+	return fonts.ForText(ctx, text, path)
+}
+
+// layoutText returns the text to draw, wrapped to opts.MaxWidth when set
+func layoutText(opts TextOverlayOptions) string {
+	if opts.MaxWidth <= 0 {
+		return opts.Text
+	}
+	fontSize := opts.FontSize
+	if fontSize == 0 {
+		fontSize = 24
+	}
+	measure := textMeasure(opts.FontFile, fontSize)
+	return strings.Join(wrapText(opts.Text, float64(opts.MaxWidth), measure), "\n")
+}
+
+// layoutParams returns drawtext options for line spacing and alignment.
+// Alignment uses text_align, which needs FFmpeg 6.1 or newer.
+func layoutParams(opts TextOverlayOptions) []string {
+	var params []string
+	if opts.LineSpacing != 0 {
+		params = append(params, fmt.Sprintf("line_spacing=%d", opts.LineSpacing))
+	}
+	switch opts.Align {
+	case AlignCenter:
+		params = append(params, "text_align=C")
+	case AlignRight:
+		params = append(params, "text_align=R")
+	}
+	return params
+}
+
+// textMeasure returns a function measuring text width in pixels. It uses the
+// font's metrics when fontFile can be read and an average-width estimate otherwise.
+func textMeasure(fontFile string, fontSize int) func(string) float64 {
+	if fontFile != "" {
+		if f, err := fonts.LoadMetrics(fontFile); err == nil {
+			return func(s string) float64 { return f.Width(s, fontSize) }
+		}
+	}
+	return func(s string) float64 {
+		return float64(utf8.RuneCountInString(s)) * float64(fontSize) * 0.58
+	}
+}
+
+// wrapText breaks text into lines no wider than maxWidth, breaking at spaces
+// where possible. Existing line breaks are kept, and words wider than a whole
+// line (including unspaced CJK text) are broken between characters.
+func wrapText(text string, maxWidth float64, measure func(string) float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if measure(candidate) <= maxWidth {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			line = word
+			if measure(word) > maxWidth {
+				chunks := breakWord(word, maxWidth, measure)
+				lines = append(lines, chunks[:len(chunks)-1]...)
+				line = chunks[len(chunks)-1]
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// breakWord splits a word into pieces that each fit maxWidth, keeping at
+// least one character per piece
+func breakWord(word string, maxWidth float64, measure func(string) float64) []string {
+	var chunks []string
+	chunk := ""
+	for _, r := range word {
+		if chunk != "" && measure(chunk+string(r)) > maxWidth {
+			chunks = append(chunks, chunk)
+			chunk = ""
+		}
+		chunk += string(r)
+	}
+	return append(chunks, chunk)
+}
@@ -0,0 +1,56 @@
+package text
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// runeWidth measures every character as 10 pixels wide
+func runeWidth(s string) float64 {
+	return float64(utf8.RuneCountInString(s) * 10)
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"fits", "short line", []string{"short line"}},
+		{"wraps at spaces", "the quick brown fox jumps", []string{"the quick", "brown fox", "jumps"}},
+		{"keeps breaks", "one\n\ntwo", []string{"one", "", "two"}},
+		{"breaks long words", "a supercalifragilistic b", []string{"a", "supercalif", "ragilistic", "b"}},
+		{"unspaced text", "日本語のテキストを折り返す", []string{"日本語のテキストを折", "り返す"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapText(tt.text, 100, runeWidth)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrapText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildDrawTextFilterLayout(t *testing.T) {
+	o := &Operations{}
+	filter := o.buildDrawTextFilter(TextOverlayOptions{
+		Text:        "hello wide world",
+		FontSize:    20,
+		MaxWidth:    120,
+		LineSpacing: 6,
+		Align:       AlignCenter,
+	})
+	for _, want := range []string{`text='hello wide\nworld'`, "line_spacing=6", "text_align=C"} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q:\n%s", want, filter)
+		}
+	}
+
+	filter = o.buildDrawTextFilter(TextOverlayOptions{Text: "hello wide world"})
+	if strings.Contains(filter, `\n`) || strings.Contains(filter, "text_align") {
+		t.Errorf("Expected no layout options by default:\n%s", filter)
+	}
+}
@@ -0,0 +1,285 @@
+package text
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// Lower-third templates
+const (
+	LowerThirdBar     = "bar"     // Name and title on a plate with an accent strip on the leading edge
+	LowerThirdSlide   = "slide"   // Plate slides in with an accent underline, then the text fades in
+	LowerThirdMinimal = "minimal" // Text only, separated by an accent rule
+)
+
+// LowerThirdTemplates lists the built-in templates in display order
+var LowerThirdTemplates = []string{LowerThirdBar, LowerThirdSlide, LowerThirdMinimal}
+
+// Lower-third animations
+const (
+	LowerThirdAnimateSlide = "slide"
+	LowerThirdAnimateFade  = "fade"
+	LowerThirdAnimateNone  = "none"
+)
+
+// LowerThirdOptions contains options for a lower-third title
+type LowerThirdOptions struct {
+	Input    string
+	Output   string
+	Name     string
+	Title    string // Second line, e.g. job title (optional)
+	Template string // bar (default), slide or minimal
+	Side     string // left (default) or right
+
+	// Colors (names or #RRGGBB)
+	AccentColor  string  // default: #E63946
+	TextColor    string  // default: white
+	PlateColor   string  // default: black
+	PlateOpacity float64 // 0-1, default: 0.75
+
+	FontFile string
+
+	// Timing
+	StartTime         float64 // seconds
+	Duration          float64 // seconds on screen, including animations (default: 5)
+	Animation         string  // slide (default), fade or none; used for both in and out
+	AnimationDuration float64 // seconds (default: 0.5)
+}
+
+// lowerThirdLayout holds pixel sizes for a lower third at the video's resolution
+type lowerThirdLayout struct {
+	x, y, travel          int // Resting position and slide distance (negative slides from the left)
+	width, height         int // Plate size
+	pad, accent           int // Inner padding and accent thickness
+	nameSize, titleSize   int
+	nameY, titleY, ruleY  int // Offsets from the top of the plate
+	start, end, animation float64
+}
+
+// AddLowerThird draws an animated name/title lower third in a single encode
+func (o *Operations) AddLowerThird(ctx context.Context, opts LowerThirdOptions) error {
+	if strings.TrimSpace(opts.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	fontFile, err := resolveFont(ctx, opts.FontFile, opts.Name+opts.Title)
+	if err != nil {
+		return err
+	}
+	opts.FontFile = fontFile
+	width, height, err := o.ffmpeg.ProbeDimensions(ctx, opts.Input)
+	if err != nil {
+		return err
+	}
+	filter, err := buildLowerThirdFilter(opts, width, height)
+	if err != nil {
+		return err
+	}
+
+	return o.ffmpeg.Execute(ctx,
+		"-i", opts.Input,
+		"-filter_complex", filter,
+		"-map", "[v]",
+		"-map", "0:a?",
+		"-c:a", "copy",
+		"-y",
+		opts.Output,
+	)
+}
+
+// buildLowerThirdFilter builds the filter graph for a lower third on a
+// width x height video. The result is labelled [v].
+func buildLowerThirdFilter(opts LowerThirdOptions, width, height int) (string, error) {
+	template := opts.Template
+	if template == "" {
+		template = LowerThirdBar
+	}
+	if template != LowerThirdBar && template != LowerThirdSlide && template != LowerThirdMinimal {
+		return "", fmt.Errorf("unknown lower-third template: %s (use %s)", template, strings.Join(LowerThirdTemplates, ", "))
+	}
+	animation := opts.Animation
+	if animation == "" {
+		animation = LowerThirdAnimateSlide
+	}
+	if animation != LowerThirdAnimateSlide && animation != LowerThirdAnimateFade && animation != LowerThirdAnimateNone {
+		return "", fmt.Errorf("unknown animation: %s (use slide, fade or none)", animation)
+	}
+	if opts.Side != "" && opts.Side != "left" && opts.Side != "right" {
+		return "", fmt.Errorf("unknown side: %s (use left or right)", opts.Side)
+	}
+
+	accent := colorOr(opts.AccentColor, "#E63946")
+	textColor := colorOr(opts.TextColor, "white")
+	plateColor := colorOr(opts.PlateColor, "black")
+	plateOpacity := opts.PlateOpacity
+	if plateOpacity <= 0 || plateOpacity > 1 {
+		plateOpacity = 0.75
+	}
+
+	l := layoutLowerThird(opts, width, height)
+	if animation == LowerThirdAnimateNone {
+		l.animation = 0
+	}
+
+	// Progress runs 0 -> 1 over the intro and back to 0 over the outro
+	progress := progressExpr(l.start, l.end, l.animation, 0)
+	plateX := fmt.Sprintf("%d", l.x)
+	if animation == LowerThirdAnimateSlide || template == LowerThirdSlide {
+		plateX = slideExpr(l.x, l.travel, progress)
+	}
+	textX, textAlpha := plateX, "1"
+	switch {
+	case template == LowerThirdSlide:
+		// Text fades in once the plate has landed
+		textX, textAlpha = fmt.Sprintf("%d", l.x), progressExpr(l.start, l.end, l.animation, l.animation*0.6)
+	case animation == LowerThirdAnimateFade:
+		textAlpha = progress
+	}
+	plateFade := animation == LowerThirdAnimateFade && template != LowerThirdSlide
+
+	enable := fmt.Sprintf("between(t,%.3f,%.3f)", l.start, l.end)
+	var parts []string
+	current := "0:v"
+	overlay := func(label, color string, w, h, yOffset int, xExpr string, xOffset int) {
+		src := fmt.Sprintf("color=c=%s:s=%dx%d:r=30:d=%.3f,format=rgba", color, w, h, l.end)
+		if plateFade && l.animation > 0 {
+			src += fmt.Sprintf(",fade=t=in:st=%.3f:d=%.3f:alpha=1,fade=t=out:st=%.3f:d=%.3f:alpha=1",
+				l.start, l.animation, l.end-l.animation, l.animation)
+		}
+		parts = append(parts, src+"["+label+"]")
+		next := label + "_out"
+		parts = append(parts, fmt.Sprintf("[%s][%s]overlay=x='%s+%d':y=%d:eof_action=pass:enable='%s'[%s]",
+			current, label, xExpr, xOffset, l.y+yOffset, enable, next))
+		current = next
+	}
+
+	switch template {
+	case LowerThirdBar:
+		overlay("lt_plate", fmt.Sprintf("%s@%.2f", plateColor, plateOpacity), l.width, l.height, 0, plateX, 0)
+		accentX := 0
+		if opts.Side == "right" {
+			accentX = l.width - l.accent
+		}
+		overlay("lt_accent", accent, l.accent, l.height, 0, plateX, accentX)
+	case LowerThirdSlide:
+		overlay("lt_plate", fmt.Sprintf("%s@%.2f", plateColor, plateOpacity), l.width, l.height, 0, plateX, 0)
+		overlay("lt_accent", accent, l.width, l.accent, l.height-l.accent, plateX, 0)
+	case LowerThirdMinimal:
+		overlay("lt_rule", accent, l.width-2*l.pad, max(l.accent/2, 2), l.ruleY, plateX, l.pad)
+	}
+
+	textParams := func(text string, size, yOffset int, color string) string {
+		params := []string{
+			fmt.Sprintf("text='%s'", escapeText(text)),
+			fmt.Sprintf("x='%s+%d'", textX, l.pad),
+			fmt.Sprintf("y=%d", l.y+yOffset),
+			fmt.Sprintf("fontsize=%d", size),
+			fmt.Sprintf("fontcolor=%s", color),
+			fmt.Sprintf("alpha='%s'", textAlpha),
+			fmt.Sprintf("enable='%s'", enable),
+		}
+		if opts.FontFile != "" {
+			params = append(params, fmt.Sprintf("fontfile='%s'", opts.FontFile))
+		}
+		if template == LowerThirdMinimal {
+			params = append(params, "shadowx=2", "shadowy=2", "shadowcolor=black@0.6")
+		}
+		return "drawtext=" + strings.Join(params, ":")
+	}
+
+	texts := []string{textParams(opts.Name, l.nameSize, l.nameY, textColor)}
+	if opts.Title != "" {
+		titleColor := textColor + "@0.85"
+		if template == LowerThirdMinimal {
+			titleColor = accent
+		}
+		texts = append(texts, textParams(opts.Title, l.titleSize, l.titleY, titleColor))
+	}
+	parts = append(parts, fmt.Sprintf("[%s]%s[v]", current, strings.Join(texts, ",")))
+
+	return strings.Join(parts, ";"), nil
+}
+
+// layoutLowerThird sizes the lower third for the video. Sizes are defined
+// for 1080p and scaled by the short side; text width is estimated from the
+// character count since drawtext cannot report it ahead of time.
+func layoutLowerThird(opts LowerThirdOptions, width, height int) lowerThirdLayout {
+	scale := float64(min(width, height)) / 1080
+	px := func(v float64) int { return int(math.Round(v * scale)) }
+
+	l := lowerThirdLayout{
+		pad:       px(28),
+		accent:    px(10),
+		nameSize:  px(54),
+		titleSize: px(36),
+		start:     opts.StartTime,
+		animation: opts.AnimationDuration,
+	}
+	duration := opts.Duration
+	if duration <= 0 {
+		duration = 5
+	}
+	l.end = l.start + duration
+	if l.animation <= 0 {
+		l.animation = 0.5
+	}
+	l.animation = math.Min(l.animation, duration/2)
+
+	textWidth := estimateTextWidth(opts.Name, l.nameSize)
+	if opts.Title != "" {
+		textWidth = max(textWidth, estimateTextWidth(opts.Title, l.titleSize))
+	}
+	l.width = min(textWidth+2*l.pad+l.accent, width-2*px(40))
+
+	l.nameY = l.pad
+	l.ruleY = l.nameY + l.nameSize + px(10)
+	l.titleY = l.ruleY + px(10)
+	l.height = l.pad + l.nameSize + l.pad
+	if opts.Title != "" {
+		l.height = l.titleY + l.titleSize + l.pad
+	}
+
+	marginX := int(math.Round(float64(width) * 0.06))
+	l.y = height - int(math.Round(float64(height)*0.12)) - l.height
+	if opts.Side == "right" {
+		l.x = width - marginX - l.width
+		l.travel = width - l.x
+	} else {
+		l.x = marginX
+		l.travel = -(l.x + l.width)
+	}
+	return l
+}
+
+// estimateTextWidth approximates rendered width from the character count
+func estimateTextWidth(text string, fontSize int) int {
+	return int(math.Ceil(float64(utf8.RuneCountInString(text)) * float64(fontSize) * 0.58))
+}
+
+// progressExpr is an FFmpeg expression that eases from 0 to 1 after start+delay
+// and back to 0 before end
+func progressExpr(start, end, animation, delay float64) string {
+	if animation <= 0 {
+		return "1"
+	}
+	p := fmt.Sprintf("min(clip((t-%.3f)/%.3f,0,1),clip((%.3f-t)/%.3f,0,1))", start+delay, animation, end, animation)
+	// Ease-out cubic
+	return fmt.Sprintf("(1-pow(1-%s,3))", p)
+}
+
+// slideExpr offsets x by travel pixels while progress is below 1
+func slideExpr(x, travel int, progress string) string {
+	if progress == "1" {
+		return fmt.Sprintf("%d", x)
+	}
+	return fmt.Sprintf("(%d+(%d)*(1-%s))", x, travel, progress)
+}
+
+func colorOr(color, fallback string) string {
+	if color == "" {
+		return fallback
+	}
+	return color
+}
@@ -0,0 +1,74 @@
+package text
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildLowerThirdFilterBar(t *testing.T) {
+	filter, err := buildLowerThirdFilter(LowerThirdOptions{
+		Name:      "Ada Lovelace",
+		Title:     "Analyst",
+		StartTime: 2,
+		Duration:  4,
+	}, 1920, 1080)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"color=c=black@0.75:s=",
+		"color=c=#E63946:s=10x",
+		"[0:v][lt_plate]overlay=x='(115+(-",
+		"enable='between(t,2.000,6.000)'",
+		"text='Ada Lovelace'",
+		"text='Analyst'",
+		"[lt_accent_out]drawtext=",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q:\n%s", want, filter)
+		}
+	}
+	if !strings.HasSuffix(filter, "[v]") {
+		t.Errorf("Expected filter to end with [v]: %s", filter)
+	}
+}
+
+func TestBuildLowerThirdFilterFadeRight(t *testing.T) {
+	filter, err := buildLowerThirdFilter(LowerThirdOptions{
+		Name:      "Grace Hopper",
+		Side:      "right",
+		Animation: LowerThirdAnimateFade,
+	}, 1280, 720)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(filter, "fade=t=in:st=0.000:d=0.500:alpha=1") {
+		t.Errorf("Expected plate fade:\n%s", filter)
+	}
+	if strings.Contains(filter, "overlay=x='(") {
+		t.Errorf("Expected static position for fade animation:\n%s", filter)
+	}
+}
+
+func TestBuildLowerThirdFilterErrors(t *testing.T) {
+	if _, err := buildLowerThirdFilter(LowerThirdOptions{Name: "A", Template: "ribbon"}, 1920, 1080); err == nil {
+		t.Error("Expected error for unknown template")
+	}
+	if _, err := buildLowerThirdFilter(LowerThirdOptions{Name: "A", Animation: "spin"}, 1920, 1080); err == nil {
+		t.Error("Expected error for unknown animation")
+	}
+}
+
+func TestLayoutLowerThird(t *testing.T) {
+	l := layoutLowerThird(LowerThirdOptions{Name: "Ada", Title: "A much longer title line", Duration: 0.6}, 1920, 1080)
+	if l.width != estimateTextWidth("A much longer title line", 36)+2*28+10 {
+		t.Errorf("Expected plate sized to the longest line, got %d", l.width)
+	}
+	if l.animation != 0.3 {
+		t.Errorf("Expected animation clamped to half the duration, got %v", l.animation)
+	}
+	if l.y+l.height != 1080-130 {
+		t.Errorf("Expected plate above the bottom margin, got y=%d height=%d", l.y, l.height)
+	}
+}
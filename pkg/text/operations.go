@@ -71,6 +71,11 @@ type TextOverlayOptions struct {
 	// Animation
 	FadeIn  *float64 // seconds
 	FadeOut *float64 // seconds
+
+	// Layout
+	MaxWidth    int       // Wrap lines wider than this many pixels (0: no wrapping)
+	LineSpacing int       // Extra pixels between lines
+	Align       TextAlign // Line alignment within the text block: left (default), center, right
 }
 
 // AnimatedTextOptions extends TextOverlayOptions with animation
@@ -113,6 +118,11 @@ func NewOperations(mgr *ffmpeg.Manager) *Operations {
 
 // AddTextOverlay adds text overlay to video
 func (o *Operations) AddTextOverlay(ctx context.Context, opts TextOverlayOptions) error {
+	fontFile, err := resolveFont(ctx, opts.FontFile, opts.Text)
+	if err != nil {
+		return err
+	}
+	opts.FontFile = fontFile
 	filter := o.buildDrawTextFilter(opts)
 
 	args := []string{
@@ -128,6 +138,11 @@ func (o *Operations) AddTextOverlay(ctx context.Context, opts TextOverlayOptions
 
 // AddAnimatedText adds animated text to video
 func (o *Operations) AddAnimatedText(ctx context.Context, opts AnimatedTextOptions) error {
+	fontFile, err := resolveFont(ctx, opts.FontFile, opts.Text)
+	if err != nil {
+		return err
+	}
+	opts.FontFile = fontFile
 	filter := o.buildAnimatedTextFilter(opts)
 
 	args := []string{
@@ -166,10 +181,14 @@ func (o *Operations) BurnSubtitles(ctx context.Context, opts SubtitleOptions) er
 
 // buildDrawTextFilter builds the drawtext filter string
 func (o *Operations) buildDrawTextFilter(opts TextOverlayOptions) string {
-	params := []string{}
-
 	// Escape text for FFmpeg
-	escapedText := escapeText(opts.Text)
+	return o.buildDrawTextFilterWithText(opts, escapeText(layoutText(opts)))
+}
+
+// buildDrawTextFilterWithText builds a drawtext filter for text that is
+// already escaped, such as text containing %{...} expansions
+func (o *Operations) buildDrawTextFilterWithText(opts TextOverlayOptions, escapedText string) string {
+	params := []string{}
 	params = append(params, fmt.Sprintf("text='%s'", escapedText))
 
 	// Position
@@ -192,6 +211,7 @@ func (o *Operations) buildDrawTextFilter(opts TextOverlayOptions) string {
 		fontColor = "white"
 	}
 	params = append(params, fmt.Sprintf("fontcolor=%s", fontColor))
+	params = append(params, layoutParams(opts)...)
 
 	// Border/Outline
 	if opts.BorderWidth > 0 {
@@ -260,7 +280,7 @@ func (o *Operations) buildDrawTextFilter(opts TextOverlayOptions) string {
 func (o *Operations) buildAnimatedTextFilter(opts AnimatedTextOptions) string {
 	params := []string{}
 
-	escapedText := escapeText(opts.Text)
+	escapedText := escapeText(layoutText(opts.TextOverlayOptions))
 	params = append(params, fmt.Sprintf("text='%s'", escapedText))
 
 	// Animation position
@@ -283,6 +303,7 @@ func (o *Operations) buildAnimatedTextFilter(opts AnimatedTextOptions) string {
 		fontColor = "white"
 	}
 	params = append(params, fmt.Sprintf("fontcolor=%s", fontColor))
+	params = append(params, layoutParams(opts.TextOverlayOptions)...)
 
 	// Border
 	if opts.BorderWidth > 0 {
@@ -311,11 +332,7 @@ func (o *Operations) buildAnimatedTextFilter(opts AnimatedTextOptions) string {
 
 // buildSubtitlesFilter builds subtitles filter
 func (o *Operations) buildSubtitlesFilter(opts SubtitleOptions) string {
-	// Escape path for FFmpeg (Windows paths need special handling)
-	escapedPath := strings.ReplaceAll(opts.SubtitleFile, "\\", "/")
-	escapedPath = strings.ReplaceAll(escapedPath, ":", "\\:")
-
-	filter := fmt.Sprintf("subtitles='%s'", escapedPath)
+	filter := fmt.Sprintf("subtitles='%s'", ffmpeg.EscapeFilterPath(opts.SubtitleFile))
 
 	// Add styling if specified
 	styleParams := []string{}
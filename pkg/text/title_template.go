@@ -0,0 +1,349 @@
+package text
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//go:embed templates/*.json
+var builtinTemplates embed.FS
+
+// Template element types
+const (
+	ElementText  = "text"
+	ElementRect  = "rect"
+	ElementImage = "image"
+)
+
+// TitleTemplate is a reusable title layout: layered text, rectangles and
+// images positioned on a design canvas, with {variable} placeholders.
+// Placeholders that are not declared in Variables are required.
+type TitleTemplate struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Canvas      [2]int             `json:"canvas,omitempty"`    // Design size in pixels (default: 1920x1080)
+	Duration    float64            `json:"duration"`            // Seconds on screen
+	Variables   map[string]*string `json:"variables,omitempty"` // Defaults; null marks a required variable
+	Elements    []TemplateElement  `json:"elements"`
+}
+
+// TemplateElement is one layer of a title template. Positions and sizes are
+// in canvas pixels and scaled to the video.
+type TemplateElement struct {
+	Type string `json:"type"` // text, rect or image
+
+	// Position of the anchor point; align picks whether it is the left edge,
+	// center or right edge of the element
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Align string  `json:"align,omitempty"` // left (default), center or right
+
+	// Text
+	Text        string  `json:"text,omitempty"`
+	Font        string  `json:"font,omitempty"` // Font name or path
+	Size        float64 `json:"size,omitempty"` // Font size (default: 48)
+	Color       string  `json:"color,omitempty"`
+	BorderWidth float64 `json:"borderWidth,omitempty"`
+	BorderColor string  `json:"borderColor,omitempty"`
+
+	// Rect and image size; images keep their aspect ratio when height is 0
+	Width  float64 `json:"width,omitempty"`
+	Height float64 `json:"height,omitempty"`
+	Path   string  `json:"path,omitempty"` // Image file
+
+	// Appearance and timing, relative to the start of the template
+	Opacity *float64 `json:"opacity,omitempty"` // 0-1 (default: 1)
+	Start   float64  `json:"start,omitempty"`
+	End     float64  `json:"end,omitempty"` // default: template duration
+	FadeIn  float64  `json:"fadeIn,omitempty"`
+	FadeOut float64  `json:"fadeOut,omitempty"`
+}
+
+// ApplyTemplateOptions contains options for stamping a title template onto a video
+type ApplyTemplateOptions struct {
+	Input     string
+	Output    string
+	Template  string            // Built-in template name or path to a template JSON file
+	Variables map[string]string // Values for {variables}
+	StartTime float64           // When the template starts, in seconds
+}
+
+// TitleTemplateNames lists the built-in templates
+func TitleTemplateNames() []string {
+	entries, _ := builtinTemplates.ReadDir("templates")
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadTitleTemplate loads a built-in template by name or a template JSON file
+func LoadTitleTemplate(nameOrPath string) (*TitleTemplate, error) {
+	data, err := builtinTemplates.ReadFile(path.Join("templates", nameOrPath+".json"))
+	if err != nil {
+		data, err = os.ReadFile(nameOrPath)
+		if err != nil {
+			return nil, fmt.Errorf("template not found: %s (built-in templates: %s)", nameOrPath, strings.Join(TitleTemplateNames(), ", "))
+		}
+	}
+
+	var t TitleTemplate
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", nameOrPath, err)
+	}
+	if t.Duration <= 0 {
+		return nil, fmt.Errorf("template %s must have a positive duration", nameOrPath)
+	}
+	if len(t.Elements) == 0 {
+		return nil, fmt.Errorf("template %s has no elements", nameOrPath)
+	}
+	return &t, nil
+}
+
+// ApplyTemplate renders a title template over the video in a single encode
+func (o *Operations) ApplyTemplate(ctx context.Context, opts ApplyTemplateOptions) error {
+	t, err := LoadTitleTemplate(opts.Template)
+	if err != nil {
+		return err
+	}
+	elements, err := t.expand(opts.Variables)
+	if err != nil {
+		return err
+	}
+	for i, el := range elements {
+		if el.Type != ElementText {
+			continue
+		}
+		if elements[i].Font, err = resolveFont(ctx, el.Font, el.Text); err != nil {
+			return err
+		}
+	}
+
+	width, height, err := o.ffmpeg.ProbeDimensions(ctx, opts.Input)
+	if err != nil {
+		return err
+	}
+	filter, images, err := buildTemplateFilter(t, elements, width, height, opts.StartTime)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"-i", opts.Input}
+	for _, img := range images {
+		args = append(args, "-loop", "1", "-i", img)
+	}
+	args = append(args,
+		"-filter_complex", filter,
+		"-map", "[v]",
+		"-map", "0:a?",
+		"-c:a", "copy",
+		"-y",
+		opts.Output,
+	)
+
+	return o.ffmpeg.Execute(ctx, args...)
+}
+
+var variablePattern = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+
+// expand substitutes variables into the template's elements, dropping text
+// and images whose content is empty after substitution
+func (t *TitleTemplate) expand(values map[string]string) ([]TemplateElement, error) {
+	missing := map[string]bool{}
+	substitute := func(s string) string {
+		return variablePattern.ReplaceAllStringFunc(s, func(m string) string {
+			name := m[1 : len(m)-1]
+			if v, ok := values[name]; ok {
+				return v
+			}
+			if v := t.Variables[name]; v != nil {
+				return *v
+			}
+			missing[name] = true
+			return ""
+		})
+	}
+
+	var elements []TemplateElement
+	for _, el := range t.Elements {
+		el.Text = substitute(el.Text)
+		el.Path = substitute(el.Path)
+		el.Color = substitute(el.Color)
+		el.BorderColor = substitute(el.BorderColor)
+		el.Font = substitute(el.Font)
+		switch el.Type {
+		case ElementText:
+			if strings.TrimSpace(el.Text) == "" {
+				continue
+			}
+		case ElementImage:
+			if el.Path == "" {
+				continue
+			}
+		case ElementRect:
+		default:
+			return nil, fmt.Errorf("unknown template element type: %s (use text, rect or image)", el.Type)
+		}
+		elements = append(elements, el)
+	}
+
+	if len(missing) > 0 {
+		var names []string
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("missing template variables: %s", strings.Join(names, ", "))
+	}
+	return elements, nil
+}
+
+// buildTemplateFilter builds the filter graph for expanded template elements
+// on a width x height video, starting at start seconds. Images are returned
+// in input order (input 1 onwards). The result is labelled [v].
+func buildTemplateFilter(t *TitleTemplate, elements []TemplateElement, width, height int, start float64) (string, []string, error) {
+	canvasW, canvasH := float64(t.Canvas[0]), float64(t.Canvas[1])
+	if canvasW <= 0 || canvasH <= 0 {
+		canvasW, canvasH = 1920, 1080
+	}
+	// Positions scale with each axis; sizes scale uniformly so text and
+	// images keep their proportions on other aspect ratios
+	sx, sy := float64(width)/canvasW, float64(height)/canvasH
+	s := math.Min(sx, sy)
+	px := func(v, scale float64) int { return int(math.Round(v * scale)) }
+
+	var parts []string
+	var images []string
+	current := "0:v"
+	for i, el := range elements {
+		elStart := start + el.Start
+		elEnd := start + t.Duration
+		if el.End > 0 {
+			elEnd = start + el.End
+		}
+		if elEnd <= elStart {
+			return "", nil, fmt.Errorf("element %d ends before it starts", i+1)
+		}
+		opacity := 1.0
+		if el.Opacity != nil {
+			opacity = math.Max(0, math.Min(1, *el.Opacity))
+		}
+		enable := fmt.Sprintf("between(t,%.3f,%.3f)", elStart, elEnd)
+		x, y := px(el.X, sx), px(el.Y, sy)
+		next := fmt.Sprintf("tpl%d", i)
+
+		// Rects and images are overlaid, so their fades are applied to the
+		// source stream's alpha
+		fades := ""
+		if el.FadeIn > 0 {
+			fades += fmt.Sprintf(",fade=t=in:st=%.3f:d=%.3f:alpha=1", elStart, el.FadeIn)
+		}
+		if el.FadeOut > 0 {
+			fades += fmt.Sprintf(",fade=t=out:st=%.3f:d=%.3f:alpha=1", elEnd-el.FadeOut, el.FadeOut)
+		}
+
+		switch el.Type {
+		case ElementText:
+			size := el.Size
+			if size <= 0 {
+				size = 48
+			}
+			xExpr := fmt.Sprintf("%d", x)
+			switch el.Align {
+			case "center":
+				xExpr = fmt.Sprintf("%d-text_w/2", x)
+			case "right":
+				xExpr = fmt.Sprintf("%d-text_w", x)
+			}
+			params := []string{
+				fmt.Sprintf("text='%s'", escapeText(el.Text)),
+				fmt.Sprintf("x='%s'", xExpr),
+				fmt.Sprintf("y=%d", y),
+				fmt.Sprintf("fontsize=%d", max(px(size, s), 1)),
+				fmt.Sprintf("fontcolor=%s", colorOr(el.Color, "white")),
+				fmt.Sprintf("alpha='%s'", templateAlpha(opacity, elStart, elEnd, el.FadeIn, el.FadeOut)),
+				fmt.Sprintf("enable='%s'", enable),
+			}
+			if el.Font != "" {
+				params = append(params, fmt.Sprintf("fontfile='%s'", el.Font))
+			}
+			if el.BorderWidth > 0 {
+				params = append(params,
+					fmt.Sprintf("borderw=%d", max(px(el.BorderWidth, s), 1)),
+					fmt.Sprintf("bordercolor=%s", colorOr(el.BorderColor, "black")))
+			}
+			parts = append(parts, fmt.Sprintf("[%s]drawtext=%s[%s]", current, strings.Join(params, ":"), next))
+
+		case ElementRect:
+			w, h := px(el.Width, sx), px(el.Height, sy)
+			if w <= 0 || h <= 0 {
+				return "", nil, fmt.Errorf("rect element %d needs a width and height", i+1)
+			}
+			x = alignX(x, w, el.Align)
+			parts = append(parts,
+				fmt.Sprintf("color=c=%s@%.2f:s=%dx%d:r=30:d=%.3f,format=rgba%s[%s_src]",
+					colorOr(el.Color, "black"), opacity, w, h, elEnd, fades, next),
+				fmt.Sprintf("[%s][%s_src]overlay=x=%d:y=%d:eof_action=pass:enable='%s'[%s]",
+					current, next, x, y, enable, next))
+
+		case ElementImage:
+			images = append(images, el.Path)
+			w := px(el.Width, s)
+			if w <= 0 {
+				return "", nil, fmt.Errorf("image element %d needs a width", i+1)
+			}
+			h := -1
+			if el.Height > 0 {
+				h = px(el.Height, s)
+			}
+			x = alignX(x, w, el.Align)
+			parts = append(parts,
+				fmt.Sprintf("[%d:v]scale=%d:%d,format=rgba,colorchannelmixer=aa=%.2f%s[%s_src]",
+					len(images), w, h, opacity, fades, next),
+				fmt.Sprintf("[%s][%s_src]overlay=x=%d:y=%d:shortest=1:enable='%s'[%s]",
+					current, next, x, y, enable, next))
+		}
+		current = next
+	}
+
+	if len(parts) == 0 {
+		return "", nil, fmt.Errorf("template has nothing to draw")
+	}
+	// Relabel the last output as [v]
+	last := parts[len(parts)-1]
+	parts[len(parts)-1] = strings.TrimSuffix(last, "["+current+"]") + "[v]"
+	return strings.Join(parts, ";"), images, nil
+}
+
+// alignX moves an anchor x to the left edge of an element w pixels wide
+func alignX(x, w int, align string) int {
+	switch align {
+	case "center":
+		return x - w/2
+	case "right":
+		return x - w
+	}
+	return x
+}
+
+// templateAlpha is a drawtext alpha expression for opacity with optional fades
+func templateAlpha(opacity, start, end, fadeIn, fadeOut float64) string {
+	alpha := fmt.Sprintf("%.2f", opacity)
+	if fadeIn > 0 {
+		alpha += fmt.Sprintf("*clip((t-%.3f)/%.3f,0,1)", start, fadeIn)
+	}
+	if fadeOut > 0 {
+		alpha += fmt.Sprintf("*clip((%.3f-t)/%.3f,0,1)", end, fadeOut)
+	}
+	return alpha
+}
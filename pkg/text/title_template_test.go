@@ -0,0 +1,94 @@
+package text
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuiltinTitleTemplates(t *testing.T) {
+	names := TitleTemplateNames()
+	if len(names) == 0 {
+		t.Fatal("Expected built-in templates")
+	}
+	for _, name := range names {
+		tpl, err := LoadTitleTemplate(name)
+		if err != nil {
+			t.Errorf("LoadTitleTemplate(%s) error: %v", name, err)
+			continue
+		}
+		// Fill every required variable so all layers render
+		values := map[string]string{}
+		for v, def := range tpl.Variables {
+			if def == nil {
+				values[v] = "Example"
+			}
+		}
+		elements, err := tpl.expand(values)
+		if err != nil {
+			t.Errorf("%s: expand error: %v", name, err)
+			continue
+		}
+		if _, _, err := buildTemplateFilter(tpl, elements, 1280, 720, 0); err != nil {
+			t.Errorf("%s: buildTemplateFilter error: %v", name, err)
+		}
+	}
+}
+
+func TestExpandTitleTemplate(t *testing.T) {
+	tpl, err := LoadTitleTemplate("title-card")
+	if err != nil {
+		t.Fatalf("LoadTitleTemplate error: %v", err)
+	}
+
+	if _, err := tpl.expand(nil); err == nil || !strings.Contains(err.Error(), "title") {
+		t.Errorf("Expected missing title error, got %v", err)
+	}
+
+	elements, err := tpl.expand(map[string]string{"title": "Launch Day", "accent": "#00FF00"})
+	if err != nil {
+		t.Fatalf("expand error: %v", err)
+	}
+	// The empty subtitle layer is dropped
+	if len(elements) != 3 {
+		t.Fatalf("Expected 3 elements, got %d", len(elements))
+	}
+	if elements[1].Text != "Launch Day" || elements[2].Color != "#00FF00" {
+		t.Errorf("Unexpected substitution: %+v", elements)
+	}
+}
+
+func TestBuildTemplateFilter(t *testing.T) {
+	tpl := &TitleTemplate{Duration: 4}
+	opacity := 0.5
+	elements := []TemplateElement{
+		{Type: ElementRect, X: 960, Y: 100, Align: "center", Width: 400, Height: 100, Color: "black", Opacity: &opacity, FadeIn: 1},
+		{Type: ElementImage, Path: "logo.png", X: 1880, Y: 40, Align: "right", Width: 200},
+		{Type: ElementText, Text: "Hello: world", X: 960, Y: 500, Align: "center", Size: 60, End: 3},
+	}
+
+	filter, images, err := buildTemplateFilter(tpl, elements, 960, 540, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(images) != 1 || images[0] != "logo.png" {
+		t.Errorf("images = %v", images)
+	}
+	for _, want := range []string{
+		"color=c=black@0.50:s=200x50:r=30:d=14.000,format=rgba,fade=t=in:st=10.000:d=1.000:alpha=1[tpl0_src]",
+		"[0:v][tpl0_src]overlay=x=380:y=50:eof_action=pass:enable='between(t,10.000,14.000)'[tpl0]",
+		"[1:v]scale=100:-1,format=rgba",
+		"overlay=x=840:y=20:shortest=1",
+		`text='Hello\: world'`,
+		"x='480-text_w/2'",
+		"fontsize=30",
+		"enable='between(t,10.000,13.000)'[v]",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q:\n%s", want, filter)
+		}
+	}
+
+	if _, _, err := buildTemplateFilter(tpl, []TemplateElement{{Type: ElementRect, X: 0, Y: 0}}, 960, 540, 0); err == nil {
+		t.Error("Expected error for rect without size")
+	}
+}
@@ -62,6 +62,13 @@ func NewManager(baseDir string) *Manager {
 	}
 }
 
+// SetBaseDir moves later reads and writes to <baseDir>/.mcp-video-timelines,
+// e.g. when another workspace project becomes active. Empty means the
+// working directory.
+func (m *Manager) SetBaseDir(baseDir string) {
+	*m = *NewManager(baseDir)
+}
+
 // Initialize creates the timelines directory
 func (m *Manager) Initialize() error {
 	return os.MkdirAll(m.timelinesDir, 0755)
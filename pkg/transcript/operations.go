@@ -9,7 +9,10 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/chandler-mayo/mcp-video-editor/pkg/apiclient"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/usage"
 	openai "github.com/sashabaranov/go-openai"
 )
 
@@ -67,7 +70,7 @@ const (
 func NewOperations(apiKey string, mgr *ffmpeg.Manager) *Operations {
 	var client *openai.Client
 	if apiKey != "" {
-		client = openai.NewClient(apiKey)
+		client = openai.NewClientWithConfig(apiclient.OpenAIConfig(apiKey, apiclient.ProviderOpenAI))
 	}
 	return &Operations{
 		client:        client,
@@ -84,11 +87,11 @@ func (o *Operations) ExtractTranscript(ctx context.Context, videoPath string, la
 	}
 
 	// Create temp directory
-	tempDir, err := os.MkdirTemp("", "whisper-")
+	tempDir, cleanup, err := scratch.Dir("whisper")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+		return nil, err
 	}
-	defer os.RemoveAll(tempDir)
+	defer cleanup()
 
 	// Extract audio with optimized settings
 	audioPath := filepath.Join(tempDir, "audio.mp3")
@@ -250,6 +253,12 @@ func (o *Operations) transcribeFile(ctx context.Context, audioPath, language str
 	if err != nil {
 		return nil, err
 	}
+	usage.Record(usage.Entry{
+		Provider:     usage.ProviderOpenAI,
+		Feature:      usage.FeatureTranscription,
+		Model:        req.Model,
+		AudioSeconds: resp.Duration,
+	})
 
 	// Parse the response
 	segments := make([]Segment, len(resp.Segments))
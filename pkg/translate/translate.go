@@ -0,0 +1,129 @@
+package translate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/llm"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/usage"
+)
+
+// segmentsPerRequest keeps each translation request well inside the model's output limit
+const segmentsPerRequest = 40
+
+// Options contains options for translating a transcript
+type Options struct {
+	TargetLanguage string // Language name or code, e.g. "Spanish" or "es"
+	SourceLanguage string // Optional; defaults to the transcript's language
+	Notes          string // Optional glossary, tone or audience guidance
+}
+
+// Translator translates transcripts with the configured LLM provider
+type Translator struct {
+	llm *llm.Client
+}
+
+// NewTranslator creates a translator. The provider and model are read from
+// the config on each call so set_config changes apply immediately.
+func NewTranslator(cfg *config.Config) *Translator {
+	return &Translator{llm: llm.NewClient(cfg)}
+}
+
+// Provider returns the LLM provider used for translation ("claude" or "openai")
+func (t *Translator) Provider() string {
+	return t.llm.Provider()
+}
+
+// TranslateTranscript returns a copy of trans with every segment translated.
+// Segment timing is kept; word timings are dropped since they no longer apply.
+func (t *Translator) TranslateTranscript(ctx context.Context, trans *transcript.Transcript, opts Options) (*transcript.Transcript, error) {
+	if opts.TargetLanguage == "" {
+		return nil, fmt.Errorf("target language is required")
+	}
+	source := opts.SourceLanguage
+	if source == "" {
+		source = trans.Language
+	}
+
+	translated := &transcript.Transcript{
+		Language: opts.TargetLanguage,
+		Duration: trans.Duration,
+	}
+
+	var texts []string
+	for start := 0; start < len(trans.Segments); start += segmentsPerRequest {
+		batch := trans.Segments[start:min(start+segmentsPerRequest, len(trans.Segments))]
+		lines, err := t.translateBatch(ctx, batch, source, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i, seg := range batch {
+			text := strings.TrimSpace(lines[i])
+			translated.Segments = append(translated.Segments, transcript.Segment{
+				Start: seg.Start,
+				End:   seg.End,
+				Text:  text,
+			})
+			texts = append(texts, text)
+		}
+	}
+	translated.Text = strings.Join(texts, " ")
+
+	return translated, nil
+}
+
+// translateBatch translates one batch of segments, returning one line per segment
+func (t *Translator) translateBatch(ctx context.Context, segments []transcript.Segment, source string, opts Options) ([]string, error) {
+	content, err := t.llm.Complete(ctx, usage.FeatureTranslation, buildPrompt(segments, source, opts))
+	if err != nil {
+		return nil, fmt.Errorf("translation request failed: %w", err)
+	}
+	lines, err := parseTranslations(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) != len(segments) {
+		return nil, fmt.Errorf("expected %d translated segments, got %d", len(segments), len(lines))
+	}
+	return lines, nil
+}
+
+// buildPrompt asks for a JSON array with one translation per numbered segment
+func buildPrompt(segments []transcript.Segment, source string, opts Options) string {
+	var lines []string
+	for i, seg := range segments {
+		lines = append(lines, fmt.Sprintf("[%d] %s", i, strings.TrimSpace(seg.Text)))
+	}
+
+	from := ""
+	if source != "" {
+		from = " from " + source
+	}
+	notes := ""
+	if opts.Notes != "" {
+		notes = "\nTranslator notes: " + opts.Notes + "\n"
+	}
+
+	return fmt.Sprintf(`Translate these numbered transcript segments%s to %s for subtitles and dubbing.
+Keep each translation about as long as the original so it fits the same time slot when spoken. Keep names, numbers and technical terms accurate. Do not merge or split segments.
+%s
+Return only a JSON object of the form {"translations": ["...", "..."]} with exactly %d strings, one per segment, in order.
+
+Segments:
+%s`, from, opts.TargetLanguage, notes, len(segments), strings.Join(lines, "\n"))
+}
+
+// parseTranslations extracts the translations array from a model response,
+// tolerating surrounding prose or code fences
+func parseTranslations(content string) ([]string, error) {
+	var resp struct {
+		Translations []string `json:"translations"`
+	}
+	if err := llm.DecodeJSON(content, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse translation response: %w", err)
+	}
+	return resp.Translations, nil
+}
@@ -0,0 +1,51 @@
+package translate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/transcript"
+)
+
+func TestParseTranslations(t *testing.T) {
+	content := "Here you go:\n```json\n{\"translations\": [\"Hola a todos.\", \"Bienvenidos\"]}\n```"
+	lines, err := parseTranslations(content)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(lines) != 2 || lines[0] != "Hola a todos." || lines[1] != "Bienvenidos" {
+		t.Errorf("Unexpected translations: %v", lines)
+	}
+
+	if _, err := parseTranslations("no json here"); err == nil {
+		t.Error("Expected error for response without JSON")
+	}
+}
+
+func TestBuildPrompt(t *testing.T) {
+	segments := []transcript.Segment{{Text: " Hello everyone. "}, {Text: "Welcome back"}}
+	prompt := buildPrompt(segments, "english", Options{TargetLanguage: "Spanish", Notes: "Keep 'MCP' untranslated"})
+
+	for _, want := range []string{"from english to Spanish", "[0] Hello everyone.\n[1] Welcome back", "exactly 2 strings", "Keep 'MCP' untranslated"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected prompt to contain %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestProvider(t *testing.T) {
+	cfg := &config.Config{}
+	tr := NewTranslator(cfg)
+	if tr.Provider() != "openai" {
+		t.Errorf("Expected openai without keys, got %s", tr.Provider())
+	}
+	cfg.ClaudeAPIKey = "key"
+	if tr.Provider() != "claude" {
+		t.Errorf("Expected claude with a Claude key, got %s", tr.Provider())
+	}
+	cfg.AgentProvider = "openai"
+	if tr.Provider() != "openai" {
+		t.Errorf("Expected configured provider, got %s", tr.Provider())
+	}
+}
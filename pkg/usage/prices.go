@@ -0,0 +1,64 @@
+package usage
+
+import "strings"
+
+// price is a model's list price in USD
+type price struct {
+	Input      float64 // Per million input tokens
+	Output     float64 // Per million output tokens
+	Characters float64 // Per million characters
+	Minute     float64 // Per minute of audio
+}
+
+// prices are published list prices; a model matches its longest prefix
+// here, so dated versions price like their base model
+var prices = map[string]price{
+	"whisper-1":         {Minute: 0.006},
+	"gpt-4o-transcribe": {Minute: 0.006},
+	"gpt-4o":            {Input: 2.5, Output: 10},
+	"gpt-4o-mini":       {Input: 0.15, Output: 0.6},
+	"gpt-4.1":           {Input: 2, Output: 8},
+	"gpt-4.1-mini":      {Input: 0.4, Output: 1.6},
+	"gpt-4.1-nano":      {Input: 0.1, Output: 0.4},
+	"gpt-4-turbo":       {Input: 10, Output: 30},
+	"gpt-4":             {Input: 30, Output: 60},
+	"tts-1":             {Characters: 15},
+	"tts-1-hd":          {Characters: 30},
+	"gpt-4o-mini-tts":   {Characters: 12},
+	"claude-opus-4":     {Input: 15, Output: 75},
+	"claude-opus-4-5":   {Input: 5, Output: 25},
+	"claude-opus-4-6":   {Input: 5, Output: 25},
+	"claude-sonnet-4":   {Input: 3, Output: 15},
+	"claude-haiku-4":    {Input: 1, Output: 5},
+	"gemini-2.0-flash":  {Input: 0.1, Output: 0.4},
+	"gemini-2.5-flash":  {Input: 0.3, Output: 2.5},
+	"gemini-2.5-pro":    {Input: 1.25, Output: 10},
+	// ElevenLabs bills credits per character; priced at $0.30 per 1,000
+	"eleven":       {Characters: 300},
+	"eleven_flash": {Characters: 150},
+	"eleven_turbo": {Characters: 150},
+}
+
+// EstimateCost prices an entry from the list prices. Unknown models and
+// free calls, such as instant voice clones, cost zero.
+func EstimateCost(e Entry) float64 {
+	model := e.Model
+	if e.Provider == ProviderOllama {
+		return 0
+	}
+	if model == "" && e.Provider == ProviderElevenLabs {
+		model = "eleven"
+	}
+	var best string
+	for name := range prices {
+		if strings.HasPrefix(model, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return 0
+	}
+	p := prices[best]
+	return (float64(e.InputTokens)*p.Input+float64(e.OutputTokens)*p.Output+float64(e.Characters)*p.Characters)/1e6 +
+		e.AudioSeconds/60*p.Minute
+}
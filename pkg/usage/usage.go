@@ -0,0 +1,251 @@
+// Package usage records what AI calls consumed and estimates their cost
+package usage
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Providers
+const (
+	ProviderOpenAI     = "openai"
+	ProviderAnthropic  = "anthropic"
+	ProviderElevenLabs = "elevenlabs"
+	ProviderGemini     = "gemini"
+	ProviderOllama     = "ollama" // Local, so always free
+)
+
+// Features that call a billed API
+const (
+	FeatureTranscription = "transcription"
+	FeatureVision        = "vision"
+	FeatureTranslation   = "translation"
+	FeatureMeeting       = "meeting_analysis"
+	FeatureSpeech        = "speech"
+	FeatureVoiceClone    = "voice_clone"
+	FeatureChapters      = "chapters"
+	FeatureHighlights    = "highlights"
+)
+
+// Entry is one billed AI call
+type Entry struct {
+	Time         time.Time `json:"time"`
+	Provider     string    `json:"provider"`
+	Feature      string    `json:"feature"`
+	Model        string    `json:"model,omitempty"`
+	InputTokens  int       `json:"inputTokens,omitempty"`
+	OutputTokens int       `json:"outputTokens,omitempty"`
+	Characters   int       `json:"characters,omitempty"`   // Text sent to TTS
+	AudioSeconds float64   `json:"audioSeconds,omitempty"` // Audio sent to transcription
+	Cost         float64   `json:"estimatedCost"`          // USD at list prices
+	Scope        string    `json:"scope,omitempty"`        // Timeline or project the call was made for
+}
+
+// Total sums the entries for one provider, feature and model
+type Total struct {
+	Provider     string  `json:"provider"`
+	Feature      string  `json:"feature"`
+	Model        string  `json:"model,omitempty"`
+	Calls        int     `json:"calls"`
+	InputTokens  int     `json:"inputTokens,omitempty"`
+	OutputTokens int     `json:"outputTokens,omitempty"`
+	Characters   int     `json:"characters,omitempty"`
+	AudioSeconds float64 `json:"audioSeconds,omitempty"`
+	Cost         float64 `json:"estimatedCost"`
+}
+
+// Report summarizes usage for a session or scope
+type Report struct {
+	Scope  string    `json:"scope,omitempty"`
+	Since  time.Time `json:"since,omitempty"`
+	Calls  int       `json:"calls"`
+	Cost   float64   `json:"estimatedCost"`
+	Totals []Total   `json:"totals"`
+}
+
+// Summarize totals entries by provider, feature and model, most expensive first
+func Summarize(scope string, entries []Entry) Report {
+	report := Report{Scope: scope, Calls: len(entries), Totals: []Total{}}
+	index := map[[3]string]int{}
+	for _, e := range entries {
+		if report.Since.IsZero() || e.Time.Before(report.Since) {
+			report.Since = e.Time
+		}
+		key := [3]string{e.Provider, e.Feature, e.Model}
+		i, ok := index[key]
+		if !ok {
+			i = len(report.Totals)
+			index[key] = i
+			report.Totals = append(report.Totals, Total{Provider: e.Provider, Feature: e.Feature, Model: e.Model})
+		}
+		total := &report.Totals[i]
+		total.Calls++
+		total.InputTokens += e.InputTokens
+		total.OutputTokens += e.OutputTokens
+		total.Characters += e.Characters
+		total.AudioSeconds += e.AudioSeconds
+		total.Cost += e.Cost
+		report.Cost += e.Cost
+	}
+	slices.SortStableFunc(report.Totals, func(a, b Total) int { return cmp.Compare(b.Cost, a.Cost) })
+	return report
+}
+
+// TimelineScope names the usage scope for a timeline
+func TimelineScope(timelineID string) string {
+	return "timeline-" + timelineID
+}
+
+// ProjectScope names the usage scope for a multi-take project
+func ProjectScope(projectID string) string {
+	return "project-" + projectID
+}
+
+// Tracker collects usage for the session and stores it per scope
+type Tracker struct {
+	mu      sync.Mutex
+	dir     string
+	scope   string
+	started time.Time
+	session []Entry
+	err     error
+}
+
+// NewTracker creates a tracker that stores scoped usage in
+// <baseDir>/.mcp-video-usage (the working directory when baseDir is empty)
+func NewTracker(baseDir string) *Tracker {
+	if baseDir == "" {
+		baseDir, _ = os.Getwd()
+	}
+	return &Tracker{
+		dir:     filepath.Join(baseDir, ".mcp-video-usage"),
+		started: time.Now(),
+	}
+}
+
+var defaultTracker = NewTracker("")
+
+// Default returns the tracker that Record writes to
+func Default() *Tracker {
+	return defaultTracker
+}
+
+// Record adds an entry to the default tracker
+func Record(e Entry) {
+	defaultTracker.Record(e)
+}
+
+// SetBaseDir stores scoped usage in <baseDir>/.mcp-video-usage from now
+// on, e.g. when another workspace project becomes active. Empty means the
+// working directory.
+func (t *Tracker) SetBaseDir(baseDir string) {
+	if baseDir == "" {
+		baseDir, _ = os.Getwd()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.dir = filepath.Join(baseDir, ".mcp-video-usage")
+}
+
+// SetScope attributes later calls to a timeline or project. An empty scope
+// records to the session only.
+func (t *Tracker) SetScope(scope string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.scope = scope
+}
+
+// Scope returns the scope calls are attributed to
+func (t *Tracker) Scope() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.scope
+}
+
+// Record adds an entry to the session and to the current scope's file,
+// estimating its cost when none is given. Storage errors never fail the
+// call being recorded; Err reports the last one.
+func (t *Tracker) Record(e Entry) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.Cost == 0 {
+		e.Cost = EstimateCost(e)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e.Scope = t.scope
+	t.session = append(t.session, e)
+	if t.scope == "" {
+		return
+	}
+	entries, err := t.load(t.scope)
+	if err == nil {
+		err = t.save(t.scope, append(entries, e))
+	}
+	t.err = err
+}
+
+// Session summarizes every call since the server started
+func (t *Tracker) Session() Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	report := Summarize("", t.session)
+	report.Since = t.started
+	return report
+}
+
+// ScopeReport summarizes the stored usage for a timeline or project
+func (t *Tracker) ScopeReport(scope string) (Report, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entries, err := t.load(scope)
+	if err != nil {
+		return Report{}, err
+	}
+	return Summarize(scope, entries), nil
+}
+
+// Err returns the last error storing usage, if any
+func (t *Tracker) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *Tracker) path(scope string) string {
+	return filepath.Join(t.dir, scope+".json")
+}
+
+func (t *Tracker) load(scope string) ([]Entry, error) {
+	data, err := os.ReadFile(t.path(scope))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load usage: %w", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse usage: %w", err)
+	}
+	return entries, nil
+}
+
+func (t *Tracker) save(scope string, entries []Entry) error {
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage: %w", err)
+	}
+	return os.WriteFile(t.path(scope), data, 0644)
+}
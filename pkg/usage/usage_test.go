@@ -0,0 +1,69 @@
+package usage
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateCost(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Entry
+		want  float64
+	}{
+		{"whisper", Entry{Provider: ProviderOpenAI, Model: "whisper-1", AudioSeconds: 600}, 0.06},
+		{"dated model", Entry{Provider: ProviderOpenAI, Model: "gpt-4o-2024-08-06", InputTokens: 1000000, OutputTokens: 100000}, 3.5},
+		{"longest prefix", Entry{Provider: ProviderOpenAI, Model: "gpt-4o-mini", InputTokens: 1000000}, 0.15},
+		{"elevenlabs", Entry{Provider: ProviderElevenLabs, Model: "eleven_multilingual_v2", Characters: 1000}, 0.3},
+		{"voice clone", Entry{Provider: ProviderElevenLabs, Feature: FeatureVoiceClone}, 0},
+		{"unknown model", Entry{Provider: ProviderOpenAI, Model: "mystery", InputTokens: 1000}, 0},
+	}
+	for _, tt := range tests {
+		if got := EstimateCost(tt.entry); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: EstimateCost() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTrackerScopes(t *testing.T) {
+	dir := t.TempDir()
+	tracker := NewTracker(dir)
+
+	tracker.Record(Entry{Provider: ProviderOpenAI, Feature: FeatureTranscription, Model: "whisper-1", AudioSeconds: 60})
+	tracker.SetScope(TimelineScope("abc"))
+	tracker.Record(Entry{Provider: ProviderOpenAI, Feature: FeatureVision, Model: "gpt-4o", InputTokens: 1000, OutputTokens: 100})
+	tracker.Record(Entry{Provider: ProviderOpenAI, Feature: FeatureVision, Model: "gpt-4o", InputTokens: 2000, OutputTokens: 200})
+	tracker.SetScope(ProjectScope("xyz"))
+	tracker.Record(Entry{Provider: ProviderElevenLabs, Feature: FeatureSpeech, Characters: 500})
+	if err := tracker.Err(); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	session := tracker.Session()
+	if session.Calls != 4 || len(session.Totals) != 3 {
+		t.Errorf("Session() = %d calls in %d totals, want 4 in 3", session.Calls, len(session.Totals))
+	}
+
+	timeline, err := tracker.ScopeReport(TimelineScope("abc"))
+	if err != nil {
+		t.Fatalf("ScopeReport() error = %v", err)
+	}
+	if timeline.Calls != 2 || len(timeline.Totals) != 1 {
+		t.Fatalf("Timeline report = %+v, want 2 vision calls", timeline)
+	}
+	vision := timeline.Totals[0]
+	if vision.InputTokens != 3000 || vision.OutputTokens != 300 || math.Abs(timeline.Cost-0.0105) > 1e-9 {
+		t.Errorf("Unexpected vision total: %+v (cost %v)", vision, timeline.Cost)
+	}
+
+	// A fresh tracker reads the stored scope
+	reloaded, err := NewTracker(dir).ScopeReport(ProjectScope("xyz"))
+	if err != nil || reloaded.Calls != 1 || reloaded.Totals[0].Characters != 500 {
+		t.Errorf("Reloaded project report = %+v, %v", reloaded, err)
+	}
+
+	empty, err := tracker.ScopeReport(TimelineScope("none"))
+	if err != nil || empty.Calls != 0 {
+		t.Errorf("Expected an empty report for an unused scope, got %+v, %v", empty, err)
+	}
+}
@@ -0,0 +1,53 @@
+package video
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ArchiveChecksumFiles returns the sidecars written next to an archival
+// master: decoded per-frame MD5s, which still verify after a lossless
+// rewrap, and a SHA-256 of the file in sha256sum format
+func ArchiveChecksumFiles(output string) []string {
+	return []string{output + ".framemd5", output + ".sha256"}
+}
+
+// writeArchiveChecksums writes the checksum sidecars for an archival master
+func (o *Operations) writeArchiveChecksums(ctx context.Context, output string) error {
+	files := ArchiveChecksumFiles(output)
+	if err := o.ffmpeg.Execute(ctx,
+		"-i", output,
+		"-map", "0:v",
+		"-map", "0:a?",
+		"-f", "framemd5",
+		"-y", files[0],
+	); err != nil {
+		return fmt.Errorf("failed to write frame checksums: %w", err)
+	}
+	return writeSHA256Sidecar(output, files[1])
+}
+
+// writeSHA256Sidecar writes "<hash>  <name>" so sha256sum -c can check the
+// file from the directory it's in
+func writeSHA256Sidecar(path, sidecar string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	line := fmt.Sprintf("%s  %s\n", hex.EncodeToString(h.Sum(nil)), filepath.Base(path))
+	if err := os.WriteFile(sidecar, []byte(line), 0644); err != nil {
+		return fmt.Errorf("failed to write checksum: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package video
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArchivalPresets(t *testing.T) {
+	color, _ := parseColorInfo(hdrProbe)
+	opts := ConvertVideoOptions{Input: "master.mov", Output: "master.mkv", Quality: "high"}
+
+	ffv1, ok := GetCodecPreset("ffv1")
+	if !ok || !ffv1.Archival || !ffv1.Alpha {
+		t.Fatalf("Expected an archival FFV1 preset that keeps alpha: %+v", ffv1)
+	}
+	args := strings.Join(buildPresetArgs(opts, ffv1, color), " ")
+	for _, want := range []string{"-c:v ffv1 -level 3 -g 1", "-slicecrc 1", "-color_trc smpte2084", "-c:a pcm_s24le"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("Expected %q in args: %s", want, args)
+		}
+	}
+	// Lossless masters keep the source pixel format and ignore quality
+	if strings.Contains(args, "-pix_fmt") || strings.Contains(args, "-crf") {
+		t.Errorf("Expected no pixel format conversion or CRF: %s", args)
+	}
+
+	x264, _ := GetCodecPreset("x264_lossless")
+	if args := strings.Join(buildPresetArgs(opts, x264, nil), " "); !strings.Contains(args, "-c:v libx264 -qp 0") {
+		t.Errorf("Expected lossless H.264: %s", args)
+	}
+}
+
+func TestWriteSHA256Sidecar(t *testing.T) {
+	dir := t.TempDir()
+	master := filepath.Join(dir, "master.mkv")
+	if err := os.WriteFile(master, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sidecar := ArchiveChecksumFiles(master)[1]
+	if err := writeSHA256Sidecar(master, sidecar); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(sidecar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad  master.mkv\n"
+	if string(data) != want {
+		t.Errorf("Sidecar = %q, want %q", data, want)
+	}
+}
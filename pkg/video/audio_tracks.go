@@ -0,0 +1,288 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ReplaceAudioOptions contains options for swapping a video's soundtrack
+type ReplaceAudioOptions struct {
+	Input    string
+	Audio    string
+	Output   string
+	Offset   float64 // Seconds to delay the new audio; negative values skip into it
+	Shortest bool    // End the output with the shorter of the video and the new audio
+
+	// OriginalVolume mixes the video's own audio under the new audio at this
+	// volume (e.g. 0.2 to keep a little camera ambience); 0 drops it
+	OriginalVolume float64
+}
+
+// AddAudioTrackOptions contains options for adding an extra audio track
+type AddAudioTrackOptions struct {
+	Input  string
+	Audio  string
+	Output string
+	Offset float64 // Seconds to delay the new track; negative values skip into it
+}
+
+// AudioTrack describes one audio stream to mux into a video
+type AudioTrack struct {
+	Path     string
+	Language string // ISO 639 language code, e.g. "eng" or "es"
+	Title    string // Track name shown by players, e.g. "Spanish (dub)"
+	Default  bool   // Selected automatically by players
+	Forced   bool   // Marked as forced (MKV players honor this; MP4 support varies)
+}
+
+// MuxAudioTracksOptions contains options for attaching several audio tracks to a video
+type MuxAudioTracksOptions struct {
+	Input            string
+	Output           string // .mp4, .m4v, .mov or .mkv
+	Tracks           []AudioTrack
+	KeepOriginal     bool   // Keep the video's existing audio tracks ahead of the new ones
+	OriginalLanguage string // Language tag for the existing tracks when kept
+}
+
+// languageCodes maps ISO 639-1 codes and the language names Whisper reports
+// to the ISO 639-2 codes MP4 requires
+var languageCodes = map[string]string{
+	"ar": "ara", "de": "deu", "en": "eng", "es": "spa", "fr": "fra", "hi": "hin", "it": "ita",
+	"ja": "jpn", "ko": "kor", "nl": "nld", "pl": "pol", "pt": "por", "ru": "rus", "sv": "swe",
+	"tr": "tur", "uk": "ukr", "zh": "zho",
+	"arabic": "ara", "german": "deu", "english": "eng", "spanish": "spa", "french": "fra",
+	"hindi": "hin", "italian": "ita", "japanese": "jpn", "korean": "kor", "dutch": "nld",
+	"polish": "pol", "portuguese": "por", "russian": "rus", "swedish": "swe", "turkish": "tur",
+	"ukrainian": "ukr", "chinese": "zho",
+}
+
+// MuteVideo removes every audio track, copying video and other streams untouched
+func (o *Operations) MuteVideo(ctx context.Context, input, output string) error {
+	if err := validateOutputPath(output, input); err != nil {
+		return err
+	}
+
+	return o.ffmpeg.Execute(ctx,
+		"-i", input,
+		"-map", "0",
+		"-map", "-0:a",
+		"-c", "copy",
+		"-y", output,
+	)
+}
+
+// ReplaceAudio replaces all audio in a video with a new file. Video is
+// copied without re-encoding; the new audio is encoded to AAC.
+func (o *Operations) ReplaceAudio(ctx context.Context, opts ReplaceAudioOptions) error {
+	if err := validateOutputPath(opts.Output, opts.Input, opts.Audio); err != nil {
+		return err
+	}
+
+	return o.ffmpeg.Execute(ctx, buildReplaceAudioArgs(opts)...)
+}
+
+// AddAudioTrack appends the new file as an additional audio track, keeping
+// the video and existing audio tracks as they are
+func (o *Operations) AddAudioTrack(ctx context.Context, opts AddAudioTrackOptions) error {
+	if err := validateOutputPath(opts.Output, opts.Input, opts.Audio); err != nil {
+		return err
+	}
+
+	existing, err := o.countStreams(ctx, opts.Input, "a")
+	if err != nil {
+		return err
+	}
+
+	return o.ffmpeg.Execute(ctx, buildAddAudioTrackArgs(opts, existing)...)
+}
+
+// countStreams returns the number of streams of one type ("a", "v" or "s") in a file
+func (o *Operations) countStreams(ctx context.Context, input, streamType string) (int, error) {
+	output, err := o.ffmpeg.Probe(ctx,
+		"-v", "error",
+		"-select_streams", streamType,
+		"-show_entries", "stream=index",
+		"-of", "csv=p=0",
+		input,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to probe streams: %w", err)
+	}
+	return len(strings.Fields(output)), nil
+}
+
+// MuxAudioTracks attaches several audio files to a video as separate tracks
+// with language, title and default/forced flags. Video and kept tracks are
+// copied; new tracks are encoded to AAC.
+func (o *Operations) MuxAudioTracks(ctx context.Context, opts MuxAudioTracksOptions) error {
+	if len(opts.Tracks) == 0 {
+		return fmt.Errorf("at least one audio track is required")
+	}
+	switch strings.ToLower(filepath.Ext(opts.Output)) {
+	case ".mp4", ".m4v", ".mov", ".mkv":
+	default:
+		return fmt.Errorf("output must be .mp4, .m4v, .mov or .mkv to hold multiple audio tracks")
+	}
+
+	inputs := []string{opts.Input}
+	defaults := 0
+	for _, track := range opts.Tracks {
+		inputs = append(inputs, track.Path)
+		if track.Default {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		return fmt.Errorf("only one audio track can be the default")
+	}
+	if err := validateOutputPath(opts.Output, inputs...); err != nil {
+		return err
+	}
+
+	existing := 0
+	if opts.KeepOriginal {
+		var err error
+		if existing, err = o.countStreams(ctx, opts.Input, "a"); err != nil {
+			return err
+		}
+	}
+
+	return o.ffmpeg.Execute(ctx, buildMuxAudioArgs(opts, existing)...)
+}
+
+func buildMuxAudioArgs(opts MuxAudioTracksOptions, existingTracks int) []string {
+	args := []string{"-i", opts.Input}
+	for _, track := range opts.Tracks {
+		args = append(args, "-i", track.Path)
+	}
+
+	args = append(args, "-map", "0:v")
+	if existingTracks > 0 {
+		args = append(args, "-map", "0:a")
+	}
+	for i := range opts.Tracks {
+		args = append(args, "-map", fmt.Sprintf("%d:a:0", i+1))
+	}
+	args = append(args, "-c", "copy")
+
+	hasDefault := false
+	for _, track := range opts.Tracks {
+		hasDefault = hasDefault || track.Default
+	}
+	for i := 0; i < existingTracks; i++ {
+		if opts.OriginalLanguage != "" {
+			args = append(args, fmt.Sprintf("-metadata:s:a:%d", i), "language="+normalizeLanguageCode(opts.OriginalLanguage))
+		}
+		// A new default track takes over from the original ones
+		if hasDefault {
+			args = append(args, fmt.Sprintf("-disposition:a:%d", i), "0")
+		}
+	}
+
+	for i, track := range opts.Tracks {
+		index := strconv.Itoa(existingTracks + i)
+		args = append(args, "-c:a:"+index, "aac", "-b:a:"+index, "192k")
+		if track.Language != "" {
+			args = append(args, "-metadata:s:a:"+index, "language="+normalizeLanguageCode(track.Language))
+		}
+		if track.Title != "" {
+			args = append(args, "-metadata:s:a:"+index, "title="+track.Title)
+		}
+		args = append(args, "-disposition:a:"+index, trackDisposition(track.Default, track.Forced))
+	}
+
+	return append(args, "-y", opts.Output)
+}
+
+// trackDisposition builds an FFmpeg disposition value from track flags
+func trackDisposition(isDefault, forced bool) string {
+	var flags []string
+	if isDefault {
+		flags = append(flags, "default")
+	}
+	if forced {
+		flags = append(flags, "forced")
+	}
+	if len(flags) == 0 {
+		return "0"
+	}
+	return strings.Join(flags, "+")
+}
+
+// normalizeLanguageCode converts two-letter codes and language names to the
+// three-letter form used in MP4 and MKV metadata
+func normalizeLanguageCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if long, ok := languageCodes[code]; ok {
+		return long
+	}
+	return code
+}
+
+func buildReplaceAudioArgs(opts ReplaceAudioOptions) []string {
+	args := []string{"-i", opts.Input}
+	args = append(args, audioInputArgs(opts.Audio, opts.Offset)...)
+	switch {
+	case opts.OriginalVolume > 0:
+		added := "[1:a:0]"
+		if opts.Offset > 0 {
+			added += audioDelayFilter(opts.Offset) + "[added];[added]"
+		}
+		args = append(args,
+			"-filter_complex", fmt.Sprintf("[0:a:0]volume=%.3f[original];%s[original]amix=inputs=2:duration=first:normalize=0[mixed]",
+				opts.OriginalVolume, added),
+			"-map", "0:v",
+			"-map", "[mixed]",
+		)
+	case opts.Offset > 0:
+		args = append(args, "-map", "0:v", "-map", "1:a:0", "-af", audioDelayFilter(opts.Offset))
+	default:
+		args = append(args, "-map", "0:v", "-map", "1:a:0")
+	}
+	args = append(args,
+		"-c:v", "copy",
+		"-c:a", "aac",
+		"-b:a", "192k",
+	)
+	if opts.Shortest {
+		args = append(args, "-shortest")
+	}
+	return append(args, "-y", opts.Output)
+}
+
+func buildAddAudioTrackArgs(opts AddAudioTrackOptions, existingTracks int) []string {
+	args := []string{"-i", opts.Input}
+	args = append(args, audioInputArgs(opts.Audio, opts.Offset)...)
+
+	newTrack := "1:a:0"
+	if opts.Offset > 0 {
+		args = append(args, "-filter_complex", fmt.Sprintf("[1:a:0]%s[added]", audioDelayFilter(opts.Offset)))
+		newTrack = "[added]"
+	}
+
+	track := strconv.Itoa(existingTracks)
+	return append(args,
+		"-map", "0",
+		"-map", newTrack,
+		"-c", "copy",
+		"-c:a:"+track, "aac",
+		"-b:a:"+track, "192k",
+		"-y", opts.Output,
+	)
+}
+
+// audioInputArgs returns input arguments for an audio file, seeking into it for negative offsets
+func audioInputArgs(audio string, offset float64) []string {
+	if offset < 0 {
+		return []string{"-ss", fmt.Sprintf("%.3f", -offset), "-i", audio}
+	}
+	return []string{"-i", audio}
+}
+
+// audioDelayFilter delays every channel by offset seconds
+func audioDelayFilter(offset float64) string {
+	return fmt.Sprintf("adelay=%d:all=1", int(offset*1000))
+}
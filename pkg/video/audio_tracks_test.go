@@ -0,0 +1,82 @@
+package video
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildReplaceAudioArgs(t *testing.T) {
+	args := strings.Join(buildReplaceAudioArgs(ReplaceAudioOptions{
+		Input:    "in.mp4",
+		Audio:    "music.wav",
+		Output:   "out.mp4",
+		Offset:   1.5,
+		Shortest: true,
+	}), " ")
+	for _, want := range []string{"-map 0:v -map 1:a:0", "-af adelay=1500:all=1", "-c:v copy", "-shortest"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("Expected %q in args: %s", want, args)
+		}
+	}
+
+	args = strings.Join(buildReplaceAudioArgs(ReplaceAudioOptions{Input: "in.mp4", Audio: "music.wav", Output: "out.mp4", Offset: -2}), " ")
+	if !strings.Contains(args, "-ss 2.000 -i music.wav") || strings.Contains(args, "adelay") || strings.Contains(args, "-shortest") {
+		t.Errorf("Expected seek into audio for negative offset: %s", args)
+	}
+
+	args = strings.Join(buildReplaceAudioArgs(ReplaceAudioOptions{Input: "in.mp4", Audio: "mic.wav", Output: "out.mp4", Offset: 0.5, OriginalVolume: 0.2}), " ")
+	if !strings.Contains(args, "[0:a:0]volume=0.200[original];[1:a:0]adelay=500:all=1[added];[added][original]amix=inputs=2:duration=first:normalize=0[mixed]") ||
+		!strings.Contains(args, "-map [mixed]") || strings.Contains(args, "-af") {
+		t.Errorf("Expected original audio mixed under the delayed track: %s", args)
+	}
+}
+
+func TestBuildAddAudioTrackArgs(t *testing.T) {
+	args := strings.Join(buildAddAudioTrackArgs(AddAudioTrackOptions{
+		Input:  "in.mp4",
+		Audio:  "commentary.m4a",
+		Output: "out.mp4",
+	}, 1), " ")
+	if !strings.Contains(args, "-map 0 -map 1:a:0 -c copy -c:a:1 aac") {
+		t.Errorf("Expected new track to be the only one encoded: %s", args)
+	}
+
+	args = strings.Join(buildAddAudioTrackArgs(AddAudioTrackOptions{Input: "in.mp4", Audio: "a.wav", Output: "out.mp4", Offset: 0.25}, 0), " ")
+	if !strings.Contains(args, "[1:a:0]adelay=250:all=1[added]") || !strings.Contains(args, "-map [added]") {
+		t.Errorf("Expected delayed track via filter graph: %s", args)
+	}
+}
+
+func TestBuildMuxAudioArgs(t *testing.T) {
+	args := strings.Join(buildMuxAudioArgs(MuxAudioTracksOptions{
+		Input:  "in.mp4",
+		Output: "out.mkv",
+		Tracks: []AudioTrack{
+			{Path: "spanish.wav", Language: "es", Title: "Spanish", Default: true},
+			{Path: "music.wav", Title: "Music only", Forced: true},
+		},
+		KeepOriginal:     true,
+		OriginalLanguage: "en",
+	}, 1), " ")
+
+	for _, want := range []string{
+		"-map 0:v -map 0:a -map 1:a:0 -map 2:a:0 -c copy",
+		"-metadata:s:a:0 language=eng -disposition:a:0 0",
+		"-c:a:1 aac",
+		"-metadata:s:a:1 language=spa -metadata:s:a:1 title=Spanish -disposition:a:1 default",
+		"-disposition:a:2 forced",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("Expected %q in args: %s", want, args)
+		}
+	}
+}
+
+func TestNormalizeLanguageCode(t *testing.T) {
+	tests := map[string]string{"en": "eng", "ES": "spa", "jpn": "jpn", " fr ": "fra", "English": "eng"}
+	for input, want := range tests {
+		if got := normalizeLanguageCode(input); got != want {
+			t.Errorf("normalizeLanguageCode(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
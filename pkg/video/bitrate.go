@@ -0,0 +1,309 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BitrateOptions contains options for bitrate and keyframe analysis
+type BitrateOptions struct {
+	Input       string
+	Stream      string // "video" (default) or "audio"
+	ChartOutput string // Optional PNG chart of per-second bitrate with keyframe markers
+	ChartWidth  int    // Chart width in pixels (default: 1200)
+	ChartHeight int    // Chart height in pixels (default: 400)
+}
+
+// BitratePoint is the bitrate measured over one second of the stream
+type BitratePoint struct {
+	Second int     `json:"second"`
+	Kbps   float64 `json:"kbps"`
+}
+
+// GOPInfo describes one group of pictures starting at a keyframe
+type GOPInfo struct {
+	Start    float64 `json:"start"`
+	Frames   int     `json:"frames"`
+	Duration float64 `json:"duration"`
+}
+
+// BitrateAnalysis contains per-second bitrate, GOP structure and keyframe positions
+type BitrateAnalysis struct {
+	Stream         string         `json:"stream"`
+	PacketCount    int            `json:"packetCount"`
+	StartTime      float64        `json:"startTime"`
+	Duration       float64        `json:"duration"`
+	AverageKbps    float64        `json:"averageKbps"`
+	PeakKbps       float64        `json:"peakKbps"`
+	MinKbps        float64        `json:"minKbps"`
+	PerSecond      []BitratePoint `json:"perSecond"`
+	Keyframes      []float64      `json:"keyframes"`
+	GOPs           []GOPInfo      `json:"gops"`
+	AverageGOPSize float64        `json:"averageGopSize"`
+	MaxGOPSize     int            `json:"maxGopSize"`
+	MaxGOPDuration float64        `json:"maxGopDuration"`
+	Warnings       []string       `json:"warnings,omitempty"`
+	ChartFile      string         `json:"chartFile,omitempty"`
+}
+
+// packetInfo is a single demuxed packet from ffprobe
+type packetInfo struct {
+	Time     float64
+	Size     int
+	Keyframe bool
+}
+
+// AnalyzeBitrate reads packet data with ffprobe and reports per-second
+// bitrate, GOP sizes and keyframe positions for diagnosing stutter and
+// concat problems
+func (o *Operations) AnalyzeBitrate(ctx context.Context, opts BitrateOptions) (*BitrateAnalysis, error) {
+	stream := opts.Stream
+	if stream == "" {
+		stream = "video"
+	}
+	var selector string
+	switch stream {
+	case "video":
+		selector = "v:0"
+	case "audio":
+		selector = "a:0"
+	default:
+		return nil, fmt.Errorf("unsupported stream type: %s (use video or audio)", stream)
+	}
+
+	output, err := o.ffmpeg.Probe(ctx,
+		"-v", "error",
+		"-select_streams", selector,
+		"-show_entries", "packet=pts_time,dts_time,size,flags",
+		"-of", "json",
+		opts.Input,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read packets: %w", err)
+	}
+
+	packets, err := parsePackets(output)
+	if err != nil {
+		return nil, err
+	}
+	if len(packets) == 0 {
+		return nil, fmt.Errorf("no %s packets found in %s", stream, opts.Input)
+	}
+
+	analysis := analyzePackets(packets)
+	analysis.Stream = stream
+
+	if opts.ChartOutput != "" {
+		if err := writeBitrateChart(opts.ChartOutput, analysis, opts.ChartWidth, opts.ChartHeight); err != nil {
+			return nil, err
+		}
+		analysis.ChartFile = opts.ChartOutput
+	}
+
+	return analysis, nil
+}
+
+// parsePackets decodes ffprobe packet JSON, preferring pts and falling back to dts
+func parsePackets(output string) ([]packetInfo, error) {
+	var probe struct {
+		Packets []struct {
+			PTSTime string `json:"pts_time"`
+			DTSTime string `json:"dts_time"`
+			Size    string `json:"size"`
+			Flags   string `json:"flags"`
+		} `json:"packets"`
+	}
+	if err := json.Unmarshal([]byte(output), &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse packet data: %w", err)
+	}
+
+	packets := make([]packetInfo, 0, len(probe.Packets))
+	for _, p := range probe.Packets {
+		t, err := strconv.ParseFloat(p.PTSTime, 64)
+		if err != nil {
+			if t, err = strconv.ParseFloat(p.DTSTime, 64); err != nil {
+				continue
+			}
+		}
+		size, _ := strconv.Atoi(p.Size)
+		packets = append(packets, packetInfo{
+			Time:     t,
+			Size:     size,
+			Keyframe: strings.Contains(p.Flags, "K"),
+		})
+	}
+
+	// Packets arrive in decode order; B-frames make pts non-monotonic
+	sort.SliceStable(packets, func(i, j int) bool {
+		return packets[i].Time < packets[j].Time
+	})
+	return packets, nil
+}
+
+// analyzePackets computes bitrate buckets, GOP structure and warnings
+func analyzePackets(packets []packetInfo) *BitrateAnalysis {
+	analysis := &BitrateAnalysis{PacketCount: len(packets)}
+
+	start := packets[0].Time
+	end := packets[len(packets)-1].Time
+	analysis.StartTime = start
+	analysis.Duration = end - start
+
+	// Per-second bitrate
+	buckets := make([]int, int(math.Floor(end-start))+1)
+	totalBytes := 0
+	for _, p := range packets {
+		buckets[int(math.Floor(p.Time-start))] += p.Size
+		totalBytes += p.Size
+	}
+	analysis.MinKbps = math.Inf(1)
+	for i, bytes := range buckets {
+		kbps := float64(bytes) * 8 / 1000
+		analysis.PerSecond = append(analysis.PerSecond, BitratePoint{Second: i, Kbps: kbps})
+		analysis.PeakKbps = math.Max(analysis.PeakKbps, kbps)
+		// The final bucket is usually partial
+		if i < len(buckets)-1 || len(buckets) == 1 {
+			analysis.MinKbps = math.Min(analysis.MinKbps, kbps)
+		}
+	}
+	if analysis.Duration > 0 {
+		analysis.AverageKbps = float64(totalBytes) * 8 / 1000 / analysis.Duration
+	} else {
+		analysis.AverageKbps = float64(totalBytes) * 8 / 1000
+	}
+
+	// Keyframes and GOPs
+	for _, p := range packets {
+		if !p.Keyframe {
+			if len(analysis.GOPs) > 0 {
+				analysis.GOPs[len(analysis.GOPs)-1].Frames++
+			}
+			continue
+		}
+		analysis.Keyframes = append(analysis.Keyframes, p.Time)
+		if len(analysis.GOPs) > 0 {
+			prev := &analysis.GOPs[len(analysis.GOPs)-1]
+			prev.Duration = p.Time - prev.Start
+		}
+		analysis.GOPs = append(analysis.GOPs, GOPInfo{Start: p.Time, Frames: 1})
+	}
+	if len(analysis.GOPs) > 0 {
+		last := &analysis.GOPs[len(analysis.GOPs)-1]
+		last.Duration = end - last.Start
+	}
+
+	totalFrames := 0
+	for _, gop := range analysis.GOPs {
+		totalFrames += gop.Frames
+		if gop.Frames > analysis.MaxGOPSize {
+			analysis.MaxGOPSize = gop.Frames
+		}
+		analysis.MaxGOPDuration = math.Max(analysis.MaxGOPDuration, gop.Duration)
+	}
+	if len(analysis.GOPs) > 0 {
+		analysis.AverageGOPSize = float64(totalFrames) / float64(len(analysis.GOPs))
+	}
+
+	// Warnings for common stutter and concat causes
+	if !packets[0].Keyframe {
+		analysis.Warnings = append(analysis.Warnings, "Stream does not start on a keyframe; stream-copy cuts and concatenation may show glitches at the start")
+	}
+	if len(analysis.Keyframes) == 0 {
+		analysis.Warnings = append(analysis.Warnings, "No keyframes found; seeking and cutting will be unreliable")
+	}
+	if analysis.MaxGOPDuration > 10 {
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("Longest GOP is %.1fs; seeking will be slow and cuts imprecise (consider a keyframe every 2s)", analysis.MaxGOPDuration))
+	}
+	spikes := 0
+	for _, point := range analysis.PerSecond {
+		if analysis.AverageKbps > 0 && point.Kbps > analysis.AverageKbps*3 {
+			spikes++
+		}
+	}
+	if spikes > 0 {
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("%d second(s) exceed 3x the average bitrate; these spikes can cause playback stutter on constrained connections", spikes))
+	}
+
+	return analysis
+}
+
+// writeBitrateChart renders per-second bitrate bars with keyframe markers as a PNG
+func writeBitrateChart(path string, analysis *BitrateAnalysis, width, height int) error {
+	if width <= 0 {
+		width = 1200
+	}
+	if height <= 0 {
+		height = 400
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	background := color.RGBA{24, 24, 27, 255}
+	barColor := color.RGBA{59, 130, 246, 255}
+	keyframeColor := color.RGBA{250, 204, 21, 255}
+	averageColor := color.RGBA{239, 68, 68, 255}
+
+	fillRect(img, 0, 0, width, height, background)
+
+	seconds := len(analysis.PerSecond)
+	if seconds == 0 || analysis.PeakKbps <= 0 {
+		return savePNG(path, img)
+	}
+
+	const margin = 10
+	plotHeight := height - 2*margin
+	scaleY := float64(plotHeight) / analysis.PeakKbps
+	barWidth := float64(width-2*margin) / float64(seconds)
+
+	for i, point := range analysis.PerSecond {
+		x0 := margin + int(float64(i)*barWidth)
+		x1 := margin + int(float64(i+1)*barWidth) - 1
+		if x1 <= x0 {
+			x1 = x0 + 1
+		}
+		barHeight := int(point.Kbps * scaleY)
+		fillRect(img, x0, height-margin-barHeight, x1, height-margin, barColor)
+	}
+
+	// Keyframe markers along the bottom edge
+	for _, kf := range analysis.Keyframes {
+		x := margin + int((kf-analysis.StartTime)*barWidth)
+		fillRect(img, x, height-margin, x+1, height, keyframeColor)
+	}
+
+	// Average bitrate line
+	y := height - margin - int(analysis.AverageKbps*scaleY)
+	fillRect(img, margin, y, width-margin, y+1, averageColor)
+
+	return savePNG(path, img)
+}
+
+func fillRect(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	bounds := img.Bounds()
+	for y := max(y0, bounds.Min.Y); y < min(y1, bounds.Max.Y); y++ {
+		for x := max(x0, bounds.Min.X); x < min(x1, bounds.Max.X); x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+func savePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create chart: %w", err)
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, img); err != nil {
+		return fmt.Errorf("failed to write chart: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package video
+
+import (
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParsePackets(t *testing.T) {
+	output := `{"packets":[
+		{"pts_time":"0.000000","dts_time":"-0.066667","size":"5000","flags":"K__"},
+		{"pts_time":"0.133333","dts_time":"0.000000","size":"800","flags":"___"},
+		{"pts_time":"0.066667","dts_time":"0.033333","size":"400","flags":"___"},
+		{"dts_time":"0.100000","size":"300","flags":"___"}
+	]}`
+
+	packets, err := parsePackets(output)
+	if err != nil {
+		t.Fatalf("parsePackets failed: %v", err)
+	}
+	if len(packets) != 4 {
+		t.Fatalf("Expected 4 packets, got %d", len(packets))
+	}
+	if !packets[0].Keyframe || packets[1].Time != 0.066667 || packets[2].Time != 0.1 {
+		t.Errorf("Packets not sorted by presentation time: %+v", packets)
+	}
+}
+
+func TestAnalyzePackets(t *testing.T) {
+	// 4 seconds at 2 packets per second, keyframes at 0s and 2s
+	var packets []packetInfo
+	for i := 0; i < 8; i++ {
+		packets = append(packets, packetInfo{
+			Time:     float64(i) * 0.5,
+			Size:     1000,
+			Keyframe: i%4 == 0,
+		})
+	}
+
+	analysis := analyzePackets(packets)
+
+	if len(analysis.Keyframes) != 2 || analysis.Keyframes[1] != 2 {
+		t.Errorf("Unexpected keyframes: %v", analysis.Keyframes)
+	}
+	if len(analysis.GOPs) != 2 || analysis.GOPs[0].Frames != 4 || analysis.GOPs[0].Duration != 2 {
+		t.Errorf("Unexpected GOPs: %+v", analysis.GOPs)
+	}
+	if len(analysis.PerSecond) != 4 || analysis.PerSecond[0].Kbps != 16 {
+		t.Errorf("Unexpected per-second bitrate: %+v", analysis.PerSecond)
+	}
+	if len(analysis.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", analysis.Warnings)
+	}
+
+	// A stream that starts mid-GOP should be flagged
+	packets[0].Keyframe = false
+	analysis = analyzePackets(packets)
+	if len(analysis.Warnings) == 0 {
+		t.Error("Expected a warning for a stream not starting on a keyframe")
+	}
+}
+
+func TestWriteBitrateChart(t *testing.T) {
+	analysis := analyzePackets([]packetInfo{
+		{Time: 0, Size: 4000, Keyframe: true},
+		{Time: 1, Size: 1000},
+		{Time: 2, Size: 2000, Keyframe: true},
+	})
+
+	path := filepath.Join(t.TempDir(), "chart.png")
+	if err := writeBitrateChart(path, analysis, 300, 100); err != nil {
+		t.Fatalf("writeBitrateChart failed: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Chart was not created: %v", err)
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("Chart is not a valid PNG: %v", err)
+	}
+	if img.Bounds().Dx() != 300 || img.Bounds().Dy() != 100 {
+		t.Errorf("Unexpected chart size: %v", img.Bounds())
+	}
+}
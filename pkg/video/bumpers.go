@@ -0,0 +1,202 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// IntroOutroOptions contains options for adding branded bumpers to a video
+type IntroOutroOptions struct {
+	Input      string
+	Output     string
+	Intro      string  // Clip to prepend (optional)
+	Outro      string  // Clip to append (optional)
+	Crossfade  float64 // Seconds of overlap between clips (0: hard cut)
+	Transition string  // xfade transition used when crossfading (default: fade)
+	Quality    string  // high (default), medium or low
+}
+
+// bumperClip describes one clip in the joined sequence
+type bumperClip struct {
+	duration float64
+	hasAudio bool
+}
+
+// AddIntroOutro prepends and/or appends bumpers to a video. The bumpers are
+// scaled, padded and retimed to the main clip's resolution and frame rate so
+// clips from any source join cleanly, and the result is encoded with the
+// main clip's codec family.
+func (o *Operations) AddIntroOutro(ctx context.Context, opts IntroOutroOptions) error {
+	if opts.Intro == "" && opts.Outro == "" {
+		return fmt.Errorf("intro or outro is required")
+	}
+	if err := validateOutputPath(opts.Output, opts.Input, opts.Intro, opts.Outro); err != nil {
+		return err
+	}
+
+	main, err := o.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return fmt.Errorf("failed to get video info: %w", err)
+	}
+	if main.Width <= 0 || main.Height <= 0 {
+		return fmt.Errorf("could not determine video dimensions")
+	}
+	fps := main.FPS
+	if fps <= 0 {
+		fps = 30
+	}
+
+	var inputs []string
+	var clips []bumperClip
+	for _, path := range []string{opts.Intro, opts.Input, opts.Outro} {
+		if path == "" {
+			continue
+		}
+		info := main
+		if path != opts.Input {
+			if info, err = o.GetVideoInfo(ctx, path); err != nil {
+				return fmt.Errorf("failed to get info for %s: %w", path, err)
+			}
+		}
+		if info.Duration <= 0 {
+			return fmt.Errorf("could not determine duration of %s", path)
+		}
+		inputs = append(inputs, path)
+		clips = append(clips, bumperClip{duration: info.Duration, hasAudio: info.HasAudio})
+	}
+
+	filter, hasAudio, err := buildIntroOutroFilter(clips, main.Width, main.Height, fps, opts.Crossfade, opts.Transition)
+	if err != nil {
+		return err
+	}
+
+	quality := opts.Quality
+	if quality == "" {
+		quality = "high"
+	}
+	videoCodec, audioCodec := bumperCodecs(main.VideoCodec, opts.Output)
+
+	var args []string
+	for _, input := range inputs {
+		args = append(args, "-i", input)
+	}
+	args = append(args, "-filter_complex", filter, "-map", "[v]")
+	if hasAudio {
+		args = append(args, "-map", "[a]", "-c:a", audioCodec, "-b:a", "192k")
+	}
+	args = append(args,
+		"-c:v", videoCodec,
+		"-crf", strconv.Itoa(qualityToCRF(quality)),
+		"-pix_fmt", "yuv420p",
+	)
+	if videoCodec == "libvpx-vp9" {
+		// VP9 only honours CRF in constant-quality mode
+		args = append(args, "-b:v", "0")
+	}
+	args = append(args, "-y", opts.Output)
+
+	return o.ffmpeg.Execute(ctx, args...)
+}
+
+// buildIntroOutroFilter conforms every clip to width x height at fps and
+// joins them, with a crossfade when crossfade > 0. Clips without audio get
+// silence so the soundtrack stays aligned; audio is dropped only when no
+// clip has any. The result is labelled [v] (and [a]).
+func buildIntroOutroFilter(clips []bumperClip, width, height int, fps, crossfade float64, transition string) (string, bool, error) {
+	if len(clips) < 2 {
+		return "", false, fmt.Errorf("need at least 2 clips to join")
+	}
+	if crossfade < 0 {
+		return "", false, fmt.Errorf("crossfade must not be negative")
+	}
+	hasAudio := false
+	for _, c := range clips {
+		if crossfade >= c.duration {
+			return "", false, fmt.Errorf("crossfade (%.2fs) must be shorter than every clip (%.2fs)", crossfade, c.duration)
+		}
+		hasAudio = hasAudio || c.hasAudio
+	}
+	if transition == "" {
+		transition = "fade"
+	}
+
+	var parts []string
+	for i, c := range clips {
+		parts = append(parts, fmt.Sprintf(
+			"[%d:v]scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1,fps=%s,format=yuv420p,settb=AVTB[v%d]",
+			i, width, height, width, height, formatFPS(fps), i))
+		if !hasAudio {
+			continue
+		}
+		if c.hasAudio {
+			parts = append(parts, fmt.Sprintf("[%d:a]aformat=sample_rates=48000:channel_layouts=stereo,aresample=async=1[a%d]", i, i))
+		} else {
+			parts = append(parts, fmt.Sprintf("anullsrc=r=48000:cl=stereo,atrim=duration=%.3f[a%d]", c.duration, i))
+		}
+	}
+
+	if crossfade == 0 {
+		var labels strings.Builder
+		for i := range clips {
+			labels.WriteString(fmt.Sprintf("[v%d]", i))
+			if hasAudio {
+				labels.WriteString(fmt.Sprintf("[a%d]", i))
+			}
+		}
+		audio, outs := 0, "[v]"
+		if hasAudio {
+			audio, outs = 1, "[v][a]"
+		}
+		parts = append(parts, fmt.Sprintf("%sconcat=n=%d:v=1:a=%d%s", labels.String(), len(clips), audio, outs))
+		return strings.Join(parts, ";"), hasAudio, nil
+	}
+
+	// Chain crossfades; each one starts crossfade seconds before the end of
+	// everything joined so far
+	video, audio := "v0", "a0"
+	length := clips[0].duration
+	for i := 1; i < len(clips); i++ {
+		nextV, nextA := fmt.Sprintf("vx%d", i), fmt.Sprintf("ax%d", i)
+		if i == len(clips)-1 {
+			nextV, nextA = "v", "a"
+		}
+		parts = append(parts, fmt.Sprintf("[%s][v%d]xfade=transition=%s:duration=%.3f:offset=%.3f[%s]",
+			video, i, transition, crossfade, length-crossfade, nextV))
+		if hasAudio {
+			parts = append(parts, fmt.Sprintf("[%s][a%d]acrossfade=d=%.3f[%s]", audio, i, crossfade, nextA))
+		}
+		video, audio = nextV, nextA
+		length += clips[i].duration - crossfade
+	}
+	return strings.Join(parts, ";"), hasAudio, nil
+}
+
+// bumperCodecs picks encoders in the main clip's codec family that the
+// output container supports
+func bumperCodecs(videoCodec, output string) (string, string) {
+	if strings.EqualFold(filepath.Ext(output), ".webm") {
+		return "libvpx-vp9", "libopus"
+	}
+	switch videoCodec {
+	case "hevc":
+		return "libx265", "aac"
+	case "vp9":
+		return "libvpx-vp9", "aac"
+	default:
+		return "libx264", "aac"
+	}
+}
+
+// formatFPS writes a frame rate, using the exact NTSC fractions where they apply
+func formatFPS(fps float64) string {
+	for _, base := range []float64{24, 30, 60} {
+		ntsc := base * 1000 / 1001
+		if fps > ntsc-0.01 && fps < ntsc+0.01 {
+			return fmt.Sprintf("%d/1001", int(base*1000))
+		}
+	}
+	return strconv.FormatFloat(fps, 'f', -1, 64)
+}
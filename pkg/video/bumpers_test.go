@@ -0,0 +1,60 @@
+package video
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildIntroOutroFilterConcat(t *testing.T) {
+	clips := []bumperClip{{duration: 3}, {duration: 60, hasAudio: true}, {duration: 5, hasAudio: true}}
+	filter, hasAudio, err := buildIntroOutroFilter(clips, 1920, 1080, 30000.0/1001, 0, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !hasAudio {
+		t.Error("Expected audio when any clip has it")
+	}
+	for _, want := range []string{
+		"[0:v]scale=1920:1080:force_original_aspect_ratio=decrease,pad=1920:1080:(ow-iw)/2:(oh-ih)/2,setsar=1,fps=30000/1001,format=yuv420p,settb=AVTB[v0]",
+		"anullsrc=r=48000:cl=stereo,atrim=duration=3.000[a0]",
+		"[1:a]aformat=sample_rates=48000:channel_layouts=stereo,aresample=async=1[a1]",
+		"[v0][a0][v1][a1][v2][a2]concat=n=3:v=1:a=1[v][a]",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q:\n%s", want, filter)
+		}
+	}
+}
+
+func TestBuildIntroOutroFilterCrossfade(t *testing.T) {
+	clips := []bumperClip{{duration: 4}, {duration: 20}, {duration: 6}}
+	filter, hasAudio, err := buildIntroOutroFilter(clips, 1280, 720, 25, 1, "wipeleft")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if hasAudio || strings.Contains(filter, "acrossfade") {
+		t.Error("Expected no audio when no clip has it")
+	}
+	for _, want := range []string{
+		"fps=25,",
+		"[v0][v1]xfade=transition=wipeleft:duration=1.000:offset=3.000[vx1]",
+		"[vx1][v2]xfade=transition=wipeleft:duration=1.000:offset=22.000[v]",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q:\n%s", want, filter)
+		}
+	}
+
+	if _, _, err := buildIntroOutroFilter(clips, 1280, 720, 25, 5, ""); err == nil {
+		t.Error("Expected error when the crossfade is longer than a clip")
+	}
+}
+
+func TestBumperCodecs(t *testing.T) {
+	if v, a := bumperCodecs("hevc", "out.mp4"); v != "libx265" || a != "aac" {
+		t.Errorf("hevc -> %s %s", v, a)
+	}
+	if v, a := bumperCodecs("h264", "out.webm"); v != "libvpx-vp9" || a != "libopus" {
+		t.Errorf("webm -> %s %s", v, a)
+	}
+}
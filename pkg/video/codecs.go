@@ -0,0 +1,397 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CodecPreset is an encoder setup for a delivery codec or an editing intermediate
+type CodecPreset struct {
+	Description  string
+	VideoCodec   string
+	AudioCodec   string
+	Args         []string // Encoder options
+	CRF          bool     // Quality maps to -crf (delivery codecs only)
+	Container    string   // Extension the codec is normally delivered in
+	Intermediate bool     // Large, edit-friendly file for handoff to an NLE
+	KeepsHDR     bool     // HDR10/HLG color metadata is carried through
+	Alpha        bool     // The alpha channel is carried through
+	Archival     bool     // Lossless master in Matroska, with checksum sidecars
+}
+
+// codecPresets are the presets ConvertVideo accepts by name
+var codecPresets = map[string]CodecPreset{
+	"h264": {
+		Description: "H.264 for maximum compatibility",
+		VideoCodec:  "libx264",
+		AudioCodec:  "aac",
+		CRF:         true,
+		Container:   "mp4",
+		Args:        []string{"-preset", "medium", "-pix_fmt", "yuv420p", "-movflags", "+faststart"},
+	},
+	"hevc": {
+		Description: "H.265/HEVC, about half the size of H.264; keeps HDR10/HLG",
+		VideoCodec:  "libx265",
+		AudioCodec:  "aac",
+		CRF:         true,
+		Container:   "mp4",
+		KeepsHDR:    true,
+		// hvc1 tagging is required for playback in Apple players
+		Args: []string{"-preset", "medium", "-tag:v", "hvc1", "-movflags", "+faststart"},
+	},
+	"av1": {
+		Description: "AV1 (SVT-AV1), smallest files, slower to encode; keeps HDR10/HLG",
+		VideoCodec:  "libsvtav1",
+		AudioCodec:  "libopus",
+		CRF:         true,
+		Container:   "mp4",
+		KeepsHDR:    true,
+		Args:        []string{"-preset", "8", "-svtav1-params", "tune=0", "-movflags", "+faststart"},
+	},
+	"vp9_alpha": {
+		Description: "VP9 with alpha in WebM, for transparent overlays on the web and in editors",
+		VideoCodec:  "libvpx-vp9",
+		AudioCodec:  "libopus",
+		CRF:         true,
+		Container:   "webm",
+		Alpha:       true,
+		// -b:v 0 makes -crf constant quality; alt-ref frames don't carry alpha
+		Args: []string{"-pix_fmt", "yuva420p", "-b:v", "0", "-row-mt", "1", "-auto-alt-ref", "0"},
+	},
+	"prores_proxy": proRes("ProRes 422 Proxy, offline editing", 0, "yuv422p10le"),
+	"prores_lt":    proRes("ProRes 422 LT", 1, "yuv422p10le"),
+	"prores":       proRes("ProRes 422", 2, "yuv422p10le"),
+	"prores_hq":    proRes("ProRes 422 HQ, the usual mastering intermediate", 3, "yuv422p10le"),
+	"prores_4444":  proRes("ProRes 4444, keeps alpha", 4, "yuva444p10le"),
+	"dnxhr_lb":     dnxhr("DNxHR LB, offline editing", "dnxhr_lb", "yuv422p"),
+	"dnxhr_sq":     dnxhr("DNxHR SQ", "dnxhr_sq", "yuv422p"),
+	"dnxhr_hq":     dnxhr("DNxHR HQ", "dnxhr_hq", "yuv422p"),
+	"dnxhr_hqx":    dnxhr("DNxHR HQX, 10-bit", "dnxhr_hqx", "yuv422p10le"),
+	"dnxhr_444":    dnxhr("DNxHR 444, 10-bit 4:4:4", "dnxhr_444", "yuv444p10le"),
+	// FFV1 version 3 as recommended for archives: intra-only, with a CRC
+	// per slice so damage can be found and contained
+	"ffv1":          archival("FFV1, lossless archival master; keeps alpha", "ffv1", true, "-level", "3", "-g", "1", "-slices", "16", "-slicecrc", "1", "-context", "1"),
+	"x264_lossless": archival("Lossless H.264, smaller and faster than FFV1", "libx264", false, "-qp", "0", "-preset", "slow"),
+}
+
+func proRes(description string, profile int, pixFmt string) CodecPreset {
+	return CodecPreset{
+		Description:  description,
+		VideoCodec:   "prores_ks",
+		AudioCodec:   "pcm_s24le",
+		Container:    "mov",
+		Intermediate: true,
+		KeepsHDR:     true,
+		Alpha:        strings.HasPrefix(pixFmt, "yuva"),
+		Args:         []string{"-profile:v", strconv.Itoa(profile), "-vendor", "apl0", "-pix_fmt", pixFmt},
+	}
+}
+
+func dnxhr(description, profile, pixFmt string) CodecPreset {
+	return CodecPreset{
+		Description:  description,
+		VideoCodec:   "dnxhd",
+		AudioCodec:   "pcm_s24le",
+		Container:    "mov",
+		Intermediate: true,
+		Args:         []string{"-profile:v", profile, "-pix_fmt", pixFmt},
+	}
+}
+
+// archival returns a lossless preset. The source pixel format is kept, so
+// the master decodes to exactly the source frames.
+func archival(description, codec string, alpha bool, args ...string) CodecPreset {
+	return CodecPreset{
+		Description: description,
+		VideoCodec:  codec,
+		AudioCodec:  "pcm_s24le",
+		Container:   "mkv",
+		KeepsHDR:    true,
+		Alpha:       alpha,
+		Archival:    true,
+		Args:        args,
+	}
+}
+
+// CodecPresetNames returns the codec preset names, sorted
+func CodecPresetNames() []string {
+	names := make([]string, 0, len(codecPresets))
+	for name := range codecPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetCodecPreset returns a codec preset by name
+func GetCodecPreset(name string) (CodecPreset, bool) {
+	preset, ok := codecPresets[strings.ToLower(name)]
+	return preset, ok
+}
+
+// presetCRF maps a quality level to a CRF on the preset's encoder scale
+func presetCRF(codec, quality string) int {
+	crf := qualityToCRF(quality)
+	switch codec {
+	case "libx265":
+		return crf + 5 // x265 CRF 28 looks about like x264 CRF 23
+	case "libsvtav1":
+		return crf + 12
+	case "libvpx-vp9":
+		return crf + 8
+	}
+	return crf
+}
+
+// AlphaPreset returns the codec preset that keeps alpha in an output file:
+// VP9 for WebM and Matroska, ProRes 4444 for QuickTime
+func AlphaPreset(output string) (CodecPreset, error) {
+	switch strings.ToLower(filepath.Ext(output)) {
+	case ".webm", ".mkv":
+		return codecPresets["vp9_alpha"], nil
+	case ".mov":
+		return codecPresets["prores_4444"], nil
+	}
+	return CodecPreset{}, fmt.Errorf("transparent video needs a .webm, .mkv or .mov output, not %s", output)
+}
+
+// alphaDecoder returns the decoder that keeps the alpha channel of a video
+// codec. FFmpeg's native VP8/VP9 decoders drop the alpha of WebM files.
+func alphaDecoder(codec string) string {
+	switch codec {
+	case "vp8":
+		return "libvpx"
+	case "vp9":
+		return "libvpx-vp9"
+	}
+	return ""
+}
+
+// ColorInfo describes the color signaling of a video stream
+type ColorInfo struct {
+	PixelFormat     string
+	Primaries       string
+	Transfer        string
+	Space           string
+	Range           string
+	MasterDisplay   string // x265 master-display string, if the source carries one
+	MaxCLL, MaxFALL int    // Content light levels in nits, if known
+}
+
+// IsHDR reports whether the stream uses a PQ (HDR10) or HLG transfer
+func (c ColorInfo) IsHDR() bool {
+	return c.Transfer == "smpte2084" || c.Transfer == "arib-std-b67"
+}
+
+// ProbeColor reads the color signaling and HDR metadata of the first video stream
+func (o *Operations) ProbeColor(ctx context.Context, input string) (*ColorInfo, error) {
+	output, err := o.ffmpeg.Probe(ctx,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=pix_fmt,color_primaries,color_transfer,color_space,color_range:frame=side_data_list",
+		"-read_intervals", "%+#1",
+		"-of", "json",
+		input,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return parseColorInfo(output)
+}
+
+// parseColorInfo parses ffprobe stream color fields and first-frame side data
+func parseColorInfo(output string) (*ColorInfo, error) {
+	var data struct {
+		Streams []struct {
+			PixFmt         string `json:"pix_fmt"`
+			ColorPrimaries string `json:"color_primaries"`
+			ColorTransfer  string `json:"color_transfer"`
+			ColorSpace     string `json:"color_space"`
+			ColorRange     string `json:"color_range"`
+		} `json:"streams"`
+		Frames []struct {
+			SideData []map[string]interface{} `json:"side_data_list"`
+		} `json:"frames"`
+	}
+	if err := json.Unmarshal([]byte(output), &data); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+	if len(data.Streams) == 0 {
+		return nil, fmt.Errorf("no video stream found")
+	}
+	s := data.Streams[0]
+	info := &ColorInfo{
+		PixelFormat: s.PixFmt,
+		Primaries:   s.ColorPrimaries,
+		Transfer:    s.ColorTransfer,
+		Space:       s.ColorSpace,
+		Range:       s.ColorRange,
+	}
+	for _, frame := range data.Frames {
+		for _, side := range frame.SideData {
+			switch side["side_data_type"] {
+			case "Mastering display metadata":
+				info.MasterDisplay = masterDisplay(side)
+			case "Content light level metadata":
+				info.MaxCLL = int(sideNumber(side["max_content"]))
+				info.MaxFALL = int(sideNumber(side["max_average"]))
+			}
+		}
+	}
+	return info, nil
+}
+
+// masterDisplay formats mastering display side data the way x265 expects:
+// chromaticities in 0.00002 units and luminance in 0.0001 cd/m²
+func masterDisplay(side map[string]interface{}) string {
+	chroma := func(key string) int { return int(math.Round(sideNumber(side[key]) * 50000)) }
+	luma := func(key string) int { return int(math.Round(sideNumber(side[key]) * 10000)) }
+	if luma("max_luminance") == 0 {
+		return ""
+	}
+	return fmt.Sprintf("G(%d,%d)B(%d,%d)R(%d,%d)WP(%d,%d)L(%d,%d)",
+		chroma("green_x"), chroma("green_y"), chroma("blue_x"), chroma("blue_y"),
+		chroma("red_x"), chroma("red_y"), chroma("white_point_x"), chroma("white_point_y"),
+		luma("max_luminance"), luma("min_luminance"))
+}
+
+// sideNumber reads a side data value, which ffprobe writes as a number or a "num/den" string
+func sideNumber(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case string:
+		num, den, found := strings.Cut(n, "/")
+		x, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0
+		}
+		if found {
+			d, err := strconv.ParseFloat(den, 64)
+			if err != nil || d == 0 {
+				return 0
+			}
+			x /= d
+		}
+		return x
+	}
+	return 0
+}
+
+// hdrArgs returns the options that carry HDR color signaling into an encode
+// with the given preset, or nil for SDR sources and presets that drop it
+func hdrArgs(preset CodecPreset, color *ColorInfo) []string {
+	if color == nil || !color.IsHDR() || !preset.KeepsHDR {
+		return nil
+	}
+	var args []string
+	for _, flag := range [][2]string{
+		{"-color_primaries", color.Primaries},
+		{"-color_trc", color.Transfer},
+		{"-colorspace", color.Space},
+	} {
+		if flag[1] != "" && flag[1] != "unknown" {
+			args = append(args, flag[0], flag[1])
+		}
+	}
+	if !preset.Intermediate && !preset.Archival {
+		args = append(args, "-pix_fmt", "yuv420p10le")
+	}
+	if preset.VideoCodec == "libx265" {
+		params := []string{"hdr-opt=1", "repeat-headers=1", "transfer=" + color.Transfer}
+		if color.Primaries != "" {
+			params = append(params, "colorprim="+color.Primaries)
+		}
+		if color.Space != "" {
+			params = append(params, "colormatrix="+color.Space)
+		}
+		if color.MasterDisplay != "" {
+			params = append(params, "master-display="+color.MasterDisplay)
+		}
+		if color.MaxCLL > 0 {
+			params = append(params, fmt.Sprintf("max-cll=%d,%d", color.MaxCLL, color.MaxFALL))
+		}
+		args = append(args, "-x265-params", strings.Join(params, ":"))
+	}
+	return args
+}
+
+// convertWithPreset encodes with a named codec preset, carrying HDR color
+// metadata through when the preset supports it
+func (o *Operations) convertWithPreset(ctx context.Context, opts ConvertVideoOptions) error {
+	preset, ok := GetCodecPreset(opts.CodecPreset)
+	if !ok {
+		return fmt.Errorf("unknown codec preset %q (use %s)", opts.CodecPreset, strings.Join(CodecPresetNames(), ", "))
+	}
+	if preset.Intermediate {
+		switch strings.ToLower(filepath.Ext(opts.Output)) {
+		case ".mov", ".mxf", ".mkv":
+		default:
+			return fmt.Errorf("%s needs a .mov, .mxf or .mkv output", opts.CodecPreset)
+		}
+	}
+	if preset.Archival && !strings.EqualFold(filepath.Ext(opts.Output), ".mkv") {
+		return fmt.Errorf("%s needs a .mkv output", opts.CodecPreset)
+	}
+	if preset.Container == "webm" {
+		switch strings.ToLower(filepath.Ext(opts.Output)) {
+		case ".webm", ".mkv":
+		default:
+			return fmt.Errorf("%s needs a .webm or .mkv output", opts.CodecPreset)
+		}
+	}
+
+	var color *ColorInfo
+	if preset.KeepsHDR {
+		// Best effort: without color info the encode is tagged like an SDR source
+		color, _ = o.ProbeColor(ctx, opts.Input)
+	}
+	args := buildPresetArgs(opts, preset, color)
+	if preset.Alpha {
+		// Best effort: sources that can't be probed have no alpha to keep
+		if info, err := o.GetVideoInfo(ctx, opts.Input); err == nil {
+			if decoder := alphaDecoder(info.VideoCodec); decoder != "" {
+				args = append([]string{"-c:v", decoder}, args...)
+			}
+		}
+	}
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return err
+	}
+	if preset.Archival {
+		return o.writeArchiveChecksums(ctx, opts.Output)
+	}
+	return nil
+}
+
+// buildPresetArgs returns the FFmpeg arguments for a codec preset encode
+func buildPresetArgs(opts ConvertVideoOptions, preset CodecPreset, color *ColorInfo) []string {
+	args := []string{"-i", opts.Input, "-c:v", preset.VideoCodec}
+	args = append(args, preset.Args...)
+	if preset.CRF && opts.Quality != "" {
+		args = append(args, "-crf", strconv.Itoa(presetCRF(preset.VideoCodec, opts.Quality)))
+	}
+	if opts.Bitrate != nil {
+		args = append(args, "-b:v", fmt.Sprintf("%dk", *opts.Bitrate))
+	}
+	args = append(args, hdrArgs(preset, color)...)
+
+	audioCodec := preset.AudioCodec
+	if opts.AudioCodec != "" {
+		audioCodec = opts.AudioCodec
+	}
+	args = append(args, "-c:a", audioCodec)
+	if opts.AudioBitrate != nil {
+		args = append(args, "-b:a", fmt.Sprintf("%dk", *opts.AudioBitrate))
+	}
+	if opts.Format != "" {
+		args = append(args, "-f", opts.Format)
+	}
+	return append(args, "-y", opts.Output)
+}
@@ -0,0 +1,86 @@
+package video
+
+import (
+	"strings"
+	"testing"
+)
+
+const hdrProbe = `{
+  "frames": [{"side_data_list": [
+    {"side_data_type": "Mastering display metadata",
+     "red_x": "34000/50000", "red_y": "16000/50000", "green_x": "13250/50000", "green_y": "34500/50000",
+     "blue_x": "7500/50000", "blue_y": "3000/50000", "white_point_x": "15635/50000", "white_point_y": "16450/50000",
+     "min_luminance": "50/10000", "max_luminance": "10000000/10000"},
+    {"side_data_type": "Content light level metadata", "max_content": 1000, "max_average": 400}
+  ]}],
+  "streams": [{"pix_fmt": "yuv420p10le", "color_primaries": "bt2020", "color_transfer": "smpte2084", "color_space": "bt2020nc", "color_range": "tv"}]
+}`
+
+func TestParseColorInfo(t *testing.T) {
+	info, err := parseColorInfo(hdrProbe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.IsHDR() {
+		t.Error("Expected PQ source to be HDR")
+	}
+	if want := "G(13250,34500)B(7500,3000)R(34000,16000)WP(15635,16450)L(10000000,50)"; info.MasterDisplay != want {
+		t.Errorf("MasterDisplay = %s, want %s", info.MasterDisplay, want)
+	}
+	if info.MaxCLL != 1000 || info.MaxFALL != 400 {
+		t.Errorf("Expected MaxCLL 1000 and MaxFALL 400, got %d and %d", info.MaxCLL, info.MaxFALL)
+	}
+}
+
+func TestBuildPresetArgs(t *testing.T) {
+	color, _ := parseColorInfo(hdrProbe)
+	opts := ConvertVideoOptions{Input: "in.mov", Output: "out.mp4", Quality: "high"}
+
+	hevc, _ := GetCodecPreset("hevc")
+	args := strings.Join(buildPresetArgs(opts, hevc, color), " ")
+	for _, want := range []string{"-c:v libx265", "-tag:v hvc1", "-crf 23", "-color_trc smpte2084", "-pix_fmt yuv420p10le",
+		"master-display=G(13250,34500)", "max-cll=1000,400", "-c:a aac"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("Expected %q in args: %s", want, args)
+		}
+	}
+
+	h264, _ := GetCodecPreset("h264")
+	if args := strings.Join(buildPresetArgs(opts, h264, color), " "); strings.Contains(args, "smpte2084") {
+		t.Errorf("Expected no HDR signaling for H.264: %s", args)
+	}
+
+	opts.Output = "out.mov"
+	prores, _ := GetCodecPreset("prores_hq")
+	args = strings.Join(buildPresetArgs(opts, prores, nil), " ")
+	if !strings.Contains(args, "-c:v prores_ks -profile:v 3") || !strings.Contains(args, "-c:a pcm_s24le") || strings.Contains(args, "-crf") {
+		t.Errorf("Expected ProRes HQ without CRF: %s", args)
+	}
+}
+
+func TestAlphaPresets(t *testing.T) {
+	opts := ConvertVideoOptions{Input: "overlay.mov", Output: "overlay.webm", Quality: "medium"}
+	vp9, _ := GetCodecPreset("vp9_alpha")
+	args := strings.Join(buildPresetArgs(opts, vp9, nil), " ")
+	for _, want := range []string{"-c:v libvpx-vp9", "-pix_fmt yuva420p", "-b:v 0", "-crf 31", "-c:a libopus"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("Expected %q in args: %s", want, args)
+		}
+	}
+
+	for output, want := range map[string]string{"a.webm": "libvpx-vp9", "a.MKV": "libvpx-vp9", "a.mov": "prores_ks"} {
+		preset, err := AlphaPreset(output)
+		if err != nil || !preset.Alpha || preset.VideoCodec != want {
+			t.Errorf("AlphaPreset(%q) = %s, %v; want %s", output, preset.VideoCodec, err, want)
+		}
+	}
+	if _, err := AlphaPreset("a.mp4"); err == nil {
+		t.Error("Expected an error for MP4, which can't carry alpha")
+	}
+	if prores, _ := GetCodecPreset("prores_hq"); prores.Alpha {
+		t.Error("ProRes 422 HQ has no alpha channel")
+	}
+	if alphaDecoder("vp9") != "libvpx-vp9" || alphaDecoder("h264") != "" {
+		t.Error("Unexpected alpha decoders")
+	}
+}
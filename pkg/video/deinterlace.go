@@ -0,0 +1,215 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DeinterlaceMethods lists the Deinterlace methods: auto picks ivtc for
+// telecined sources and bwdif otherwise
+var DeinterlaceMethods = []string{"auto", "bwdif", "yadif", "ivtc"}
+
+// defaultDetectFrames is how many frames idet analyzes
+const defaultDetectFrames = 500
+
+// InterlaceReport is the idet analysis of a video
+type InterlaceReport struct {
+	Scan          string  `json:"scan"`       // progressive, interlaced, telecine or mixed
+	FieldOrder    string  `json:"fieldOrder"` // tff or bff for interlaced material, else ""
+	TFF           int     `json:"tff"`
+	BFF           int     `json:"bff"`
+	Progressive   int     `json:"progressive"`
+	Undetermined  int     `json:"undetermined"`
+	RepeatedRatio float64 `json:"repeatedRatio"` // Share of frames with a repeated field, ~0.4 for 3:2 pulldown
+	FramesChecked int     `json:"framesChecked"`
+}
+
+var (
+	idetMultiPattern    = regexp.MustCompile(`Multi frame detection:\s*TFF:\s*(\d+)\s*BFF:\s*(\d+)\s*Progressive:\s*(\d+)\s*Undetermined:\s*(\d+)`)
+	idetRepeatedPattern = regexp.MustCompile(`Repeated Fields:\s*Neither:\s*(\d+)\s*Top:\s*(\d+)\s*Bottom:\s*(\d+)`)
+)
+
+// DetectInterlacing runs idet over the first frames of a video and
+// classifies its scan type and field order
+func (o *Operations) DetectInterlacing(ctx context.Context, input string, frames int) (*InterlaceReport, error) {
+	if frames <= 0 {
+		frames = defaultDetectFrames
+	}
+	output, err := o.ffmpeg.ExecuteWithOutput(ctx,
+		"-hide_banner",
+		"-nostats",
+		"-i", input,
+		"-map", "0:v:0",
+		"-vf", "idet",
+		"-frames:v", strconv.Itoa(frames),
+		"-an",
+		"-f", "null", "-",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("interlace detection failed: %w", err)
+	}
+	return parseIdet(output)
+}
+
+// parseIdet reads idet's summary lines and classifies the result
+func parseIdet(output string) (*InterlaceReport, error) {
+	multi := idetMultiPattern.FindStringSubmatch(output)
+	if multi == nil {
+		return nil, fmt.Errorf("no idet summary in FFmpeg output")
+	}
+	count := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	report := &InterlaceReport{
+		TFF:          count(multi[1]),
+		BFF:          count(multi[2]),
+		Progressive:  count(multi[3]),
+		Undetermined: count(multi[4]),
+	}
+	report.FramesChecked = report.TFF + report.BFF + report.Progressive + report.Undetermined
+	if repeated := idetRepeatedPattern.FindStringSubmatch(output); repeated != nil {
+		neither, top, bottom := count(repeated[1]), count(repeated[2]), count(repeated[3])
+		if total := neither + top + bottom; total > 0 {
+			report.RepeatedRatio = float64(top+bottom) / float64(total)
+		}
+	}
+
+	interlaced := report.TFF + report.BFF
+	determined := interlaced + report.Progressive
+	share := 0.0
+	if determined > 0 {
+		share = float64(interlaced) / float64(determined)
+	}
+	switch {
+	case report.RepeatedRatio >= 0.15 && share >= 0.1 && share <= 0.7:
+		report.Scan = "telecine"
+	case share >= 0.7:
+		report.Scan = "interlaced"
+	case share >= 0.05:
+		report.Scan = "mixed"
+	default:
+		report.Scan = "progressive"
+	}
+	if report.Scan != "progressive" {
+		report.FieldOrder = "tff"
+		if report.BFF > report.TFF {
+			report.FieldOrder = "bff"
+		}
+	}
+	return report, nil
+}
+
+// DeinterlaceOptions contains options for deinterlacing and inverse telecine
+type DeinterlaceOptions struct {
+	Input       string
+	Output      string
+	Method      string // auto (default), bwdif, yadif or ivtc
+	FieldOrder  string // auto (default), tff or bff
+	DoubleRate  bool   // One frame per field (50i to 50p) instead of per frame
+	OnlyFlagged bool   // Only deinterlace frames flagged as interlaced, for mixed sources
+	Quality     string // Quality: high, medium, low (default: high)
+}
+
+// DeinterlaceResult describes a deinterlace run
+type DeinterlaceResult struct {
+	Detection  *InterlaceReport // Nil when method and field order were both given
+	Method     string
+	FieldOrder string
+	Filter     string
+}
+
+// Deinterlace removes interlacing with bwdif or yadif, or restores the
+// original progressive frames of telecined film with field matching and
+// decimation. With auto settings the source is analyzed with idet first.
+func (o *Operations) Deinterlace(ctx context.Context, opts DeinterlaceOptions) (*DeinterlaceResult, error) {
+	if err := validateOutputPath(opts.Output, opts.Input); err != nil {
+		return nil, err
+	}
+	method := opts.Method
+	if method == "" {
+		method = "auto"
+	}
+	if !slices.Contains(DeinterlaceMethods, method) {
+		return nil, fmt.Errorf("unknown method %q (use %s)", method, strings.Join(DeinterlaceMethods, ", "))
+	}
+	order := opts.FieldOrder
+	if order == "" {
+		order = "auto"
+	}
+	if order != "auto" && order != "tff" && order != "bff" {
+		return nil, fmt.Errorf("unknown field order %q (use auto, tff or bff)", order)
+	}
+
+	result := &DeinterlaceResult{}
+	onlyFlagged := opts.OnlyFlagged
+	if method == "auto" || order == "auto" {
+		report, err := o.DetectInterlacing(ctx, opts.Input, 0)
+		if err != nil {
+			return nil, err
+		}
+		result.Detection = report
+		if method == "auto" {
+			method = "bwdif"
+			switch report.Scan {
+			case "telecine":
+				method = "ivtc"
+			case "progressive", "mixed":
+				// Leave progressive frames untouched
+				onlyFlagged = true
+			}
+		}
+		if order == "auto" && report.FieldOrder != "" {
+			order = report.FieldOrder
+		}
+	}
+	result.Method = method
+	result.FieldOrder = order
+
+	result.Filter = buildDeinterlaceFilter(method, order, opts.DoubleRate, onlyFlagged)
+	quality := opts.Quality
+	if quality == "" {
+		quality = "high"
+	}
+	err := o.ffmpeg.Execute(ctx,
+		"-i", opts.Input,
+		"-vf", result.Filter,
+		"-c:v", "libx264",
+		"-crf", strconv.Itoa(qualityToCRF(quality)),
+		"-preset", "medium",
+		// Archival sources often carry PCM or MP2 audio, which MP4 can't hold
+		"-c:a", "aac",
+		"-b:a", "192k",
+		"-y", opts.Output,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// buildDeinterlaceFilter returns the filter for a method. ivtc matches
+// fields back into film frames, deinterlaces frames that still comb, and
+// drops the duplicate frame of each 3:2 cycle.
+func buildDeinterlaceFilter(method, order string, doubleRate, onlyFlagged bool) string {
+	if method == "ivtc" {
+		return fmt.Sprintf("fieldmatch=order=%s:combmatch=full,yadif=deint=interlaced,decimate", order)
+	}
+	mode := "send_frame"
+	if doubleRate {
+		mode = "send_field"
+	}
+	deint := "all"
+	if onlyFlagged {
+		deint = "interlaced"
+	}
+	parity := order
+	if parity == "" {
+		parity = "auto"
+	}
+	return fmt.Sprintf("%s=mode=%s:parity=%s:deint=%s", method, mode, parity, deint)
+}
@@ -0,0 +1,49 @@
+package video
+
+import "testing"
+
+func TestParseIdet(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		scan   string
+		order  string
+	}{
+		{"interlaced bff", `[Parsed_idet_0 @ 0x1] Repeated Fields: Neither:   498 Top:     1 Bottom:     1
+[Parsed_idet_0 @ 0x1] Single frame detection: TFF:     3 BFF:   420 Progressive:    50 Undetermined:    27
+[Parsed_idet_0 @ 0x1] Multi frame detection: TFF:     0 BFF:   480 Progressive:    12 Undetermined:     8`, "interlaced", "bff"},
+		{"telecine", `[Parsed_idet_0 @ 0x1] Repeated Fields: Neither:   300 Top:   100 Bottom:   100
+[Parsed_idet_0 @ 0x1] Multi frame detection: TFF:   200 BFF:     0 Progressive:   290 Undetermined:    10`, "telecine", "tff"},
+		{"progressive", `[Parsed_idet_0 @ 0x1] Repeated Fields: Neither:   500 Top:     0 Bottom:     0
+[Parsed_idet_0 @ 0x1] Multi frame detection: TFF:     2 BFF:     0 Progressive:   495 Undetermined:     3`, "progressive", ""},
+	}
+	for _, tt := range tests {
+		report, err := parseIdet(tt.output)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if report.Scan != tt.scan || report.FieldOrder != tt.order {
+			t.Errorf("%s: got %s/%s, want %s/%s", tt.name, report.Scan, report.FieldOrder, tt.scan, tt.order)
+		}
+	}
+	if _, err := parseIdet("no summary"); err == nil {
+		t.Error("Expected an error without an idet summary")
+	}
+}
+
+func TestBuildDeinterlaceFilter(t *testing.T) {
+	tests := []struct {
+		method, order           string
+		doubleRate, onlyFlagged bool
+		want                    string
+	}{
+		{"bwdif", "tff", false, false, "bwdif=mode=send_frame:parity=tff:deint=all"},
+		{"yadif", "bff", true, true, "yadif=mode=send_field:parity=bff:deint=interlaced"},
+		{"ivtc", "tff", false, false, "fieldmatch=order=tff:combmatch=full,yadif=deint=interlaced,decimate"},
+	}
+	for _, tt := range tests {
+		if got := buildDeinterlaceFilter(tt.method, tt.order, tt.doubleRate, tt.onlyFlagged); got != tt.want {
+			t.Errorf("got %s, want %s", got, tt.want)
+		}
+	}
+}
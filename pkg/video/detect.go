@@ -0,0 +1,129 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// TimeSpan represents a detected time range within a media file
+type TimeSpan struct {
+	Start    float64 `json:"start"`
+	End      float64 `json:"end"`
+	Duration float64 `json:"duration"`
+}
+
+var (
+	silenceStartPattern = regexp.MustCompile(`silence_start:\s*(-?[0-9.]+)`)
+	silenceEndPattern   = regexp.MustCompile(`silence_end:\s*(-?[0-9.]+)`)
+	freezeStartPattern  = regexp.MustCompile(`freeze_start:\s*(-?[0-9.]+)`)
+	freezeEndPattern    = regexp.MustCompile(`freeze_end:\s*(-?[0-9.]+)`)
+)
+
+// DetectSilence finds ranges where audio stays below noiseDB for at least minDuration seconds
+func (o *Operations) DetectSilence(ctx context.Context, input string, noiseDB, minDuration float64) ([]TimeSpan, error) {
+	if noiseDB == 0 {
+		noiseDB = -35
+	}
+	if minDuration <= 0 {
+		minDuration = 0.5
+	}
+
+	output, err := o.ffmpeg.ExecuteWithOutput(ctx,
+		"-i", input,
+		"-af", fmt.Sprintf("silencedetect=noise=%.1fdB:d=%.2f", noiseDB, minDuration),
+		"-vn",
+		"-f", "null",
+		"-",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("silence detection failed: %w", err)
+	}
+
+	duration := 0.0
+	if info, err := o.GetVideoInfo(ctx, input); err == nil {
+		duration = info.Duration
+	}
+
+	return parseDetectRanges(output, silenceStartPattern, silenceEndPattern, duration), nil
+}
+
+// DetectFreezes finds ranges where the picture stays static for at least minDuration seconds.
+// noise is the freezedetect noise tolerance in dB (default: -60).
+func (o *Operations) DetectFreezes(ctx context.Context, input string, noiseDB, minDuration float64) ([]TimeSpan, error) {
+	if noiseDB == 0 {
+		noiseDB = -60
+	}
+	if minDuration <= 0 {
+		minDuration = 2.0
+	}
+
+	output, err := o.ffmpeg.ExecuteWithOutput(ctx,
+		"-i", input,
+		"-vf", fmt.Sprintf("freezedetect=n=%.1fdB:d=%.2f", noiseDB, minDuration),
+		"-an",
+		"-f", "null",
+		"-",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("freeze detection failed: %w", err)
+	}
+
+	duration := 0.0
+	if info, err := o.GetVideoInfo(ctx, input); err == nil {
+		duration = info.Duration
+	}
+
+	return parseDetectRanges(output, freezeStartPattern, freezeEndPattern, duration), nil
+}
+
+// parseDetectRanges pairs start/end markers from FFmpeg detection filter logs.
+// A trailing start without an end is closed at totalDuration when it is known.
+func parseDetectRanges(output string, startPattern, endPattern *regexp.Regexp, totalDuration float64) []TimeSpan {
+	type marker struct {
+		pos   int
+		value float64
+		start bool
+	}
+
+	var markers []marker
+	for _, m := range startPattern.FindAllStringSubmatchIndex(output, -1) {
+		if v, err := strconv.ParseFloat(output[m[2]:m[3]], 64); err == nil {
+			markers = append(markers, marker{pos: m[0], value: v, start: true})
+		}
+	}
+	for _, m := range endPattern.FindAllStringSubmatchIndex(output, -1) {
+		if v, err := strconv.ParseFloat(output[m[2]:m[3]], 64); err == nil {
+			markers = append(markers, marker{pos: m[0], value: v})
+		}
+	}
+
+	// Restore log order so starts and ends pair up correctly
+	sort.Slice(markers, func(i, j int) bool { return markers[i].pos < markers[j].pos })
+
+	var spans []TimeSpan
+	open := false
+	var start float64
+	for _, m := range markers {
+		if m.start {
+			start = m.value
+			if start < 0 {
+				start = 0
+			}
+			open = true
+			continue
+		}
+		if open {
+			spans = append(spans, TimeSpan{Start: start, End: m.value, Duration: m.value - start})
+			open = false
+		}
+	}
+
+	if open && totalDuration > start {
+		spans = append(spans, TimeSpan{Start: start, End: totalDuration, Duration: totalDuration - start})
+	}
+
+	return spans
+}
@@ -0,0 +1,347 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+)
+
+// mediaExtensions lists file types scanned for duplicates
+var mediaExtensions = map[string]bool{
+	".mp4": true, ".mov": true, ".mkv": true, ".avi": true, ".webm": true, ".m4v": true,
+	".flv": true, ".wmv": true, ".mts": true, ".m2ts": true, ".ts": true, ".mpg": true, ".mpeg": true,
+	".mp3": true, ".wav": true, ".m4a": true, ".aac": true, ".flac": true, ".ogg": true, ".opus": true,
+}
+
+const (
+	fingerprintFrames = 8 // Frames sampled per file for the perceptual hash
+	hashWidth         = 9 // dHash compares horizontally adjacent pixels of a 9x8 thumbnail
+	hashHeight        = 8
+)
+
+// DuplicateOptions contains options for scanning a directory for duplicate media
+type DuplicateOptions struct {
+	Directory         string
+	Recursive         bool
+	Threshold         float64 // Minimum visual similarity 0-1 for near-duplicates (default: 0.9)
+	DurationTolerance float64 // Maximum duration difference in seconds (default: 1.0)
+}
+
+// MediaFingerprint identifies a media file by duration, stream content and sampled frames
+type MediaFingerprint struct {
+	Path         string   `json:"path"`
+	Size         int64    `json:"size"`
+	Duration     float64  `json:"duration"`
+	Width        int      `json:"width,omitempty"`
+	Height       int      `json:"height,omitempty"`
+	Bitrate      int      `json:"bitrate,omitempty"`
+	StreamHashes []string `json:"streamHashes,omitempty"`
+	FrameHashes  []uint64 `json:"-"`
+}
+
+// DuplicateGroup is a set of files that hold the same content
+type DuplicateGroup struct {
+	Kind        string             `json:"kind"` // "exact" (identical streams) or "near" (visually similar)
+	Similarity  float64            `json:"similarity"`
+	Files       []MediaFingerprint `json:"files"`
+	Keep        string             `json:"keep"`        // Suggested file to keep (highest quality)
+	Reclaimable int64              `json:"reclaimable"` // Bytes freed by removing the others
+}
+
+// DuplicateReport summarizes a duplicate scan
+type DuplicateReport struct {
+	Directory        string           `json:"directory"`
+	FilesScanned     int              `json:"filesScanned"`
+	Groups           []DuplicateGroup `json:"groups"`
+	ReclaimableBytes int64            `json:"reclaimableBytes"`
+	Errors           []string         `json:"errors,omitempty"`
+}
+
+// FindDuplicateMedia scans a directory and reports exact and near-duplicate
+// media files. Files are only fingerprinted when another file has a similar
+// duration, so large folders stay fast. Nothing is deleted.
+func (o *Operations) FindDuplicateMedia(ctx context.Context, opts DuplicateOptions) (*DuplicateReport, error) {
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = 0.9
+	}
+	tolerance := opts.DurationTolerance
+	if tolerance <= 0 {
+		tolerance = 1.0
+	}
+
+	paths, err := listMediaFiles(opts.Directory, opts.Recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DuplicateReport{Directory: opts.Directory, FilesScanned: len(paths)}
+
+	// Probe durations first; only files with a duration match are fingerprinted
+	var fingerprints []MediaFingerprint
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		info, err := o.GetVideoInfo(ctx, path)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		stat, err := os.Stat(path)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		fingerprints = append(fingerprints, MediaFingerprint{
+			Path:     path,
+			Size:     stat.Size(),
+			Duration: info.Duration,
+			Width:    info.Width,
+			Height:   info.Height,
+			Bitrate:  info.Bitrate,
+		})
+	}
+
+	sort.Slice(fingerprints, func(i, j int) bool {
+		return fingerprints[i].Duration < fingerprints[j].Duration
+	})
+
+	for i := range fingerprints {
+		if !hasDurationNeighbor(fingerprints, i, tolerance) {
+			continue
+		}
+		fp := &fingerprints[i]
+		if fp.StreamHashes, err = o.streamHashes(ctx, fp.Path); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", fp.Path, err))
+		}
+		if fp.Width > 0 && fp.Duration > 0 {
+			if fp.FrameHashes, err = o.frameHashes(ctx, fp.Path, fp.Duration); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", fp.Path, err))
+			}
+		}
+	}
+
+	report.Groups = groupDuplicates(fingerprints, threshold, tolerance)
+	for _, group := range report.Groups {
+		report.ReclaimableBytes += group.Reclaimable
+	}
+
+	return report, nil
+}
+
+// listMediaFiles returns media files in a directory
+func listMediaFiles(dir string, recursive bool) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && (!recursive || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if mediaExtensions[strings.ToLower(filepath.Ext(path))] {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan directory: %w", err)
+	}
+	return paths, nil
+}
+
+// hasDurationNeighbor reports whether a duration-sorted fingerprint has
+// another file within the tolerance
+func hasDurationNeighbor(fps []MediaFingerprint, i int, tolerance float64) bool {
+	return (i > 0 && fps[i].Duration-fps[i-1].Duration <= tolerance) ||
+		(i < len(fps)-1 && fps[i+1].Duration-fps[i].Duration <= tolerance)
+}
+
+// streamHashes hashes each stream's packets so identical content is found
+// even when it has been remuxed into a different container
+func (o *Operations) streamHashes(ctx context.Context, path string) ([]string, error) {
+	output, err := o.ffmpeg.ExecuteWithOutput(ctx,
+		"-v", "error",
+		"-i", path,
+		"-map", "0:v?",
+		"-map", "0:a?",
+		"-c", "copy",
+		"-f", "streamhash",
+		"-hash", "md5",
+		"-",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash streams: %w", err)
+	}
+
+	var hashes []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "MD5=") {
+			hashes = append(hashes, strings.TrimSpace(line))
+		}
+	}
+	return hashes, nil
+}
+
+// frameHashes computes a difference hash for frames sampled evenly across the video
+func (o *Operations) frameHashes(ctx context.Context, path string, duration float64) ([]uint64, error) {
+	tempDir, cleanup, err := scratch.Dir("fingerprint")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	rawPath := filepath.Join(tempDir, "frames.gray")
+	if err := o.ffmpeg.Execute(ctx,
+		"-i", path,
+		"-vf", fmt.Sprintf("fps=%d/%.6f,scale=%d:%d,format=gray", fingerprintFrames, duration, hashWidth, hashHeight),
+		"-frames:v", fmt.Sprintf("%d", fingerprintFrames),
+		"-f", "rawvideo",
+		"-y", rawPath,
+	); err != nil {
+		return nil, fmt.Errorf("failed to sample frames: %w", err)
+	}
+
+	data, err := os.ReadFile(rawPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sampled frames: %w", err)
+	}
+
+	frameSize := hashWidth * hashHeight
+	var hashes []uint64
+	for offset := 0; offset+frameSize <= len(data); offset += frameSize {
+		hashes = append(hashes, differenceHash(data[offset:offset+frameSize]))
+	}
+	return hashes, nil
+}
+
+// differenceHash computes a 64-bit dHash from a 9x8 grayscale thumbnail
+func differenceHash(pixels []byte) uint64 {
+	var hash uint64
+	for y := 0; y < hashHeight; y++ {
+		for x := 0; x < hashWidth-1; x++ {
+			hash <<= 1
+			if pixels[y*hashWidth+x] < pixels[y*hashWidth+x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// frameSimilarity returns the average bit agreement between two sets of frame hashes
+func frameSimilarity(a, b []uint64) float64 {
+	n := min(len(a), len(b))
+	if n == 0 {
+		return 0
+	}
+	distance := 0
+	for i := 0; i < n; i++ {
+		distance += bits.OnesCount64(a[i] ^ b[i])
+	}
+	return 1 - float64(distance)/float64(n*64)
+}
+
+// sameStreams reports whether two files have identical stream hashes
+func sameStreams(a, b []string) bool {
+	if len(a) == 0 || len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// groupDuplicates clusters fingerprints into exact and near-duplicate groups
+func groupDuplicates(fps []MediaFingerprint, threshold, tolerance float64) []DuplicateGroup {
+	parent := make([]int, len(fps))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range fps {
+		for j := i + 1; j < len(fps); j++ {
+			if math.Abs(fps[j].Duration-fps[i].Duration) > tolerance {
+				continue
+			}
+			if sameStreams(fps[i].StreamHashes, fps[j].StreamHashes) ||
+				frameSimilarity(fps[i].FrameHashes, fps[j].FrameHashes) >= threshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	members := make(map[int][]MediaFingerprint)
+	for i := range fps {
+		root := find(i)
+		members[root] = append(members[root], fps[i])
+	}
+
+	var groups []DuplicateGroup
+	for _, files := range members {
+		if len(files) < 2 {
+			continue
+		}
+
+		// A group is exact only when every pair has identical streams
+		kind, similarity := "exact", 1.0
+		for i := range files {
+			for j := i + 1; j < len(files); j++ {
+				if sameStreams(files[i].StreamHashes, files[j].StreamHashes) {
+					continue
+				}
+				kind = "near"
+				similarity = math.Min(similarity, frameSimilarity(files[i].FrameHashes, files[j].FrameHashes))
+			}
+		}
+
+		// Keep the highest resolution, then highest bitrate, then largest file
+		sort.Slice(files, func(i, j int) bool {
+			pi, pj := files[i].Width*files[i].Height, files[j].Width*files[j].Height
+			if pi != pj {
+				return pi > pj
+			}
+			if files[i].Bitrate != files[j].Bitrate {
+				return files[i].Bitrate > files[j].Bitrate
+			}
+			return files[i].Size > files[j].Size
+		})
+
+		group := DuplicateGroup{
+			Kind:       kind,
+			Similarity: similarity,
+			Files:      files,
+			Keep:       files[0].Path,
+		}
+		for _, f := range files[1:] {
+			group.Reclaimable += f.Size
+		}
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Reclaimable > groups[j].Reclaimable
+	})
+	return groups
+}
@@ -0,0 +1,61 @@
+package video
+
+import "testing"
+
+func TestDifferenceHash(t *testing.T) {
+	gradient := make([]byte, hashWidth*hashHeight)
+	for y := 0; y < hashHeight; y++ {
+		for x := 0; x < hashWidth; x++ {
+			gradient[y*hashWidth+x] = byte(x * 20)
+		}
+	}
+	if hash := differenceHash(gradient); hash != ^uint64(0) {
+		t.Errorf("Expected all bits set for a left-to-right gradient, got %064b", hash)
+	}
+	if hash := differenceHash(make([]byte, hashWidth*hashHeight)); hash != 0 {
+		t.Errorf("Expected zero hash for a flat frame, got %064b", hash)
+	}
+}
+
+func TestFrameSimilarity(t *testing.T) {
+	a := []uint64{0xFFFF0000FFFF0000, 0}
+	if s := frameSimilarity(a, a); s != 1 {
+		t.Errorf("Expected identical hashes to be fully similar, got %.2f", s)
+	}
+	b := []uint64{0xFFFF0000FFFF0000, 0xFF}
+	if s := frameSimilarity(a, b); s != 1-8.0/128 {
+		t.Errorf("Unexpected similarity %.4f", s)
+	}
+	if s := frameSimilarity(nil, a); s != 0 {
+		t.Errorf("Expected 0 similarity without frame hashes, got %.2f", s)
+	}
+}
+
+func TestGroupDuplicates(t *testing.T) {
+	fps := []MediaFingerprint{
+		{Path: "a.mp4", Size: 100, Duration: 10, Width: 1920, Height: 1080, StreamHashes: []string{"0,v,MD5=1"}, FrameHashes: []uint64{1, 2}},
+		{Path: "a-copy.mov", Size: 110, Duration: 10, Width: 1920, Height: 1080, StreamHashes: []string{"0,v,MD5=1"}, FrameHashes: []uint64{1, 2}},
+		{Path: "a-720p.mp4", Size: 40, Duration: 10.2, Width: 1280, Height: 720, StreamHashes: []string{"0,v,MD5=2"}, FrameHashes: []uint64{1, 3}},
+		{Path: "other.mp4", Size: 500, Duration: 10.1, Width: 1920, Height: 1080, StreamHashes: []string{"0,v,MD5=3"}, FrameHashes: []uint64{^uint64(0), ^uint64(0)}},
+		{Path: "long.mp4", Size: 900, Duration: 60, Width: 1920, Height: 1080, StreamHashes: []string{"0,v,MD5=1"}},
+	}
+
+	groups := groupDuplicates(fps, 0.9, 1.0)
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 group, got %d: %+v", len(groups), groups)
+	}
+
+	group := groups[0]
+	if len(group.Files) != 3 {
+		t.Errorf("Expected 3 files in group, got %d", len(group.Files))
+	}
+	if group.Kind != "near" {
+		t.Errorf("Expected near-duplicate group, got %s", group.Kind)
+	}
+	if group.Keep != "a-copy.mov" {
+		t.Errorf("Expected largest full-resolution file to be kept, got %s", group.Keep)
+	}
+	if group.Reclaimable != 140 {
+		t.Errorf("Expected 140 reclaimable bytes, got %d", group.Reclaimable)
+	}
+}
@@ -0,0 +1,181 @@
+package video
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// frameNumber matches the frame number in a name pattern: a run of # as in
+// VFX tools, or a printf-style %0Nd
+var frameNumber = regexp.MustCompile(`#+|%(0\d+)?d`)
+
+// showinfoTime matches the timestamp showinfo logs for each frame
+var showinfoTime = regexp.MustCompile(`\] n:\s*\d+ .*pts_time:(-?[0-9.]+)`)
+
+// alphaPixelFormats are the pixel formats that keep alpha, by image format
+var alphaPixelFormats = map[string]string{
+	"png":  "rgba",
+	"tiff": "rgba",
+	"exr":  "gbrapf32le",
+}
+
+// FrameManifest maps the files of an image sequence to source timestamps,
+// so edits can be laid back onto the video frame for frame
+type FrameManifest struct {
+	Input       string          `json:"input"`
+	Pattern     string          `json:"pattern"` // printf-style file name, e.g. plate.%04d.exr
+	StartNumber int             `json:"startNumber"`
+	FPS         float64         `json:"fps"` // Frame rate of the source
+	Frames      []ManifestFrame `json:"frames"`
+	File        string          `json:"-"` // Path of frames.json, if written
+}
+
+// ManifestFrame is one file of an image sequence
+type ManifestFrame struct {
+	Frame       int     `json:"frame"` // Number in the file name
+	File        string  `json:"file"`
+	Time        float64 `json:"time"`        // Source timestamp in seconds
+	SourceFrame int     `json:"sourceFrame"` // Frame index in the source at its frame rate
+}
+
+// buildExtractFramesArgs returns the FFmpeg arguments for ExtractFrames and
+// the printf-style file name of the frames
+func buildExtractFramesArgs(opts ExtractFramesOptions, decoder string) ([]string, string, error) {
+	format := strings.ToLower(opts.Format)
+	if format == "" {
+		format = "jpg"
+	}
+	if format == "tif" {
+		format = "tiff"
+	}
+	name, err := framePattern(opts.NamePattern)
+	if err != nil {
+		return nil, "", err
+	}
+	pattern := name + "." + format
+
+	var args []string
+	if decoder != "" {
+		args = append(args, "-c:v", decoder)
+	}
+	// Seeking before the input keeps filters off the skipped frames, and
+	// -copyts keeps source timestamps for the manifest
+	if opts.StartTime != nil {
+		args = append(args, "-ss", fmt.Sprintf("%.3f", *opts.StartTime))
+	}
+	if opts.Duration != nil {
+		args = append(args, "-t", fmt.Sprintf("%.3f", *opts.Duration))
+	}
+	args = append(args, "-i", opts.Input, "-copyts", "-map", "0:v:0")
+
+	var filters []string
+	if opts.FrameCount != nil {
+		// Extract specific number of frames
+		filters = append(filters, fmt.Sprintf("select='not(mod(n\\,%d))'", *opts.FrameCount))
+	} else if opts.FPS != nil {
+		// Extract at specific FPS
+		filters = append(filters, fmt.Sprintf("fps=%.2f", *opts.FPS))
+	}
+	filters = append(filters, "showinfo")
+	// Every filtered frame becomes one file, so files and showinfo lines match
+	args = append(args, "-vf", strings.Join(filters, ","), "-vsync", "0")
+
+	if opts.StartNumber != nil {
+		if *opts.StartNumber < 0 {
+			return nil, "", fmt.Errorf("startNumber must be non-negative, got: %d", *opts.StartNumber)
+		}
+		args = append(args, "-start_number", strconv.Itoa(*opts.StartNumber))
+	}
+
+	if opts.Alpha {
+		pixFmt, ok := alphaPixelFormats[format]
+		if !ok {
+			return nil, "", fmt.Errorf("alpha needs png, tiff or exr, not %s", format)
+		}
+		args = append(args, "-pix_fmt", pixFmt)
+	} else if format == "exr" {
+		args = append(args, "-pix_fmt", "gbrpf32le")
+	}
+	switch format {
+	case "exr":
+		args = append(args, "-c:v", "exr", "-compression", "zip1")
+	case "tiff":
+		args = append(args, "-compression_algo", "deflate")
+	}
+
+	return append(args, "-y", filepath.Join(opts.OutputDir, pattern)), pattern, nil
+}
+
+// framePattern turns a name pattern into a printf-style file name. Names
+// without a frame number get _#### appended.
+func framePattern(name string) (string, error) {
+	if name == "" {
+		name = "frame_####"
+	}
+	if strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("name pattern %q must be a file name, not a path", name)
+	}
+	matches := frameNumber.FindAllStringIndex(name, -1)
+	if len(matches) > 1 {
+		return "", fmt.Errorf("name pattern %q has more than one frame number", name)
+	}
+	if len(matches) == 0 {
+		name += "_####"
+		matches = frameNumber.FindAllStringIndex(name, -1)
+	}
+
+	start, end := matches[0][0], matches[0][1]
+	number := name[start:end]
+	if strings.HasPrefix(number, "#") {
+		number = fmt.Sprintf("%%0%dd", len(number))
+	}
+	escape := strings.NewReplacer("%", "%%")
+	return escape.Replace(name[:start]) + number + escape.Replace(name[end:]), nil
+}
+
+// parseShowinfoTimes returns the timestamp of each frame showinfo logged
+func parseShowinfoTimes(output string) []float64 {
+	var times []float64
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.Contains(line, "Parsed_showinfo") {
+			continue
+		}
+		if m := showinfoTime.FindStringSubmatch(line); m != nil {
+			t, err := strconv.ParseFloat(m[1], 64)
+			if err == nil {
+				times = append(times, t)
+			}
+		}
+	}
+	return times
+}
+
+// buildFrameManifest numbers the frames as FFmpeg named them
+func buildFrameManifest(input, pattern string, startNumber *int, fps float64, times []float64) *FrameManifest {
+	manifest := &FrameManifest{
+		Input:       input,
+		Pattern:     pattern,
+		StartNumber: 1,
+		FPS:         fps,
+		Frames:      []ManifestFrame{},
+	}
+	if startNumber != nil {
+		manifest.StartNumber = *startNumber
+	}
+	for i, t := range times {
+		frame := ManifestFrame{
+			Frame: manifest.StartNumber + i,
+			File:  fmt.Sprintf(pattern, manifest.StartNumber+i),
+			Time:  t,
+		}
+		if fps > 0 {
+			frame.SourceFrame = int(math.Round(t * fps))
+		}
+		manifest.Frames = append(manifest.Frames, frame)
+	}
+	return manifest
+}
@@ -0,0 +1,91 @@
+package video
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFramePattern(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"", "frame_%04d"},
+		{"shot010_plate.####", "shot010_plate.%04d"},
+		{"plate.%06d", "plate.%06d"},
+		{"take", "take_%04d"},
+		{"50%_##", "50%%_%02d"},
+	}
+	for _, tt := range tests {
+		got, err := framePattern(tt.name)
+		if err != nil {
+			t.Errorf("framePattern(%q) error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("framePattern(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	for _, name := range []string{"a_##_b_##", "renders/plate.####"} {
+		if _, err := framePattern(name); err == nil {
+			t.Errorf("Expected an error for %q", name)
+		}
+	}
+}
+
+func TestBuildExtractFramesArgs(t *testing.T) {
+	start, duration, startNumber := 2.5, 4.0, 1001
+	args, pattern, err := buildExtractFramesArgs(ExtractFramesOptions{
+		Input:       "key.webm",
+		OutputDir:   "plates",
+		StartTime:   &start,
+		Duration:    &duration,
+		Format:      "exr",
+		NamePattern: "fg.####",
+		StartNumber: &startNumber,
+		Alpha:       true,
+	}, "libvpx-vp9")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pattern != "fg.%04d.exr" {
+		t.Errorf("Pattern = %q", pattern)
+	}
+	joined := strings.Join(args, " ")
+	want := "-c:v libvpx-vp9 -ss 2.500 -t 4.000 -i key.webm -copyts -map 0:v:0 -vf showinfo -vsync 0 -start_number 1001 -pix_fmt gbrapf32le -c:v exr -compression zip1 -y plates/fg.%04d.exr"
+	if joined != want {
+		t.Errorf("Arguments\n got %q\nwant %q", joined, want)
+	}
+
+	fps := 2.0
+	args, _, err = buildExtractFramesArgs(ExtractFramesOptions{Input: "in.mp4", OutputDir: "out", FPS: &fps}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if joined := strings.Join(args, " "); !strings.Contains(joined, "-vf fps=2.00,showinfo") || !strings.HasSuffix(joined, "out/frame_%04d.jpg") {
+		t.Errorf("Default arguments %q", joined)
+	}
+
+	if _, _, err := buildExtractFramesArgs(ExtractFramesOptions{Format: "jpg", Alpha: true}, ""); err == nil {
+		t.Error("Expected an error for JPEG with alpha")
+	}
+}
+
+func TestBuildFrameManifest(t *testing.T) {
+	output := `[Parsed_showinfo_0 @ 0x5] config in time_base: 1/90000, frame_rate: 24000/1001
+[Parsed_showinfo_0 @ 0x5] n:   0 pts: 225225 pts_time:2.50250 duration:3754
+[Parsed_showinfo_0 @ 0x5] color_range:tv color_space:bt709
+[Parsed_showinfo_0 @ 0x5] n:   1 pts: 228979 pts_time:2.54421 duration:3754`
+
+	startNumber := 1001
+	manifest := buildFrameManifest("key.webm", "fg.%04d.exr", &startNumber, 24000.0/1001, parseShowinfoTimes(output))
+	if len(manifest.Frames) != 2 {
+		t.Fatalf("Manifest has %d frames, want 2", len(manifest.Frames))
+	}
+	first, second := manifest.Frames[0], manifest.Frames[1]
+	if first.Frame != 1001 || first.File != "fg.1001.exr" || first.Time != 2.5025 || first.SourceFrame != 60 {
+		t.Errorf("First frame %+v", first)
+	}
+	if second.Frame != 1002 || second.File != "fg.1002.exr" || second.SourceFrame != 61 {
+		t.Errorf("Second frame %+v", second)
+	}
+}
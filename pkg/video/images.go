@@ -0,0 +1,274 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+)
+
+// xfadeName matches xfade transition names such as fade or wipeleft
+var xfadeName = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// ImageClip is one image of a video made from images
+type ImageClip struct {
+	Image      string  `json:"image"`
+	Duration   float64 `json:"duration"`   // Seconds on screen (default: the options' Duration)
+	Transition string  `json:"transition"` // xfade transition into the next image, or cut (default: the options' Transition)
+	overlap    float64 // Length of the transition into the next image
+}
+
+// ImagesToVideoOptions contains options for making a video from images
+type ImagesToVideoOptions struct {
+	Images             []ImageClip // In order; or use Pattern
+	Pattern            string      // printf sequence (frame_%04d.png) or wildcard (shot_*.jpg), one frame per image
+	Output             string
+	FPS                int     // Default: 30
+	Duration           float64 // Seconds per listed image (default: 3)
+	Transition         string  // Default transition between listed images (default: cut)
+	TransitionDuration float64 // Seconds (default: 0.5)
+	Width              int     // Default: the first image's size
+	Height             int
+}
+
+// ImagesToVideoResult describes the video made from images
+type ImagesToVideoResult struct {
+	Images   int     `json:"images"`
+	Duration float64 `json:"duration"`
+	Width    int     `json:"width"`
+	Height   int     `json:"height"`
+}
+
+// CreateVideoFromImages makes a video from an ordered list of images, each
+// with its own duration and transition, or from a numbered sequence.
+// Wildcards are expanded here rather than by FFmpeg's glob pattern type,
+// which isn't available on Windows, and the list is written to a concat
+// script so no file name is interpreted as a pattern.
+func (o *Operations) CreateVideoFromImages(ctx context.Context, opts ImagesToVideoOptions) (*ImagesToVideoResult, error) {
+	if opts.FPS <= 0 {
+		opts.FPS = 30
+	}
+	if len(opts.Images) == 0 && opts.Pattern == "" {
+		return nil, fmt.Errorf("images or pattern is required")
+	}
+
+	// A numbered sequence is read as one input by the image2 demuxer
+	if len(opts.Images) == 0 && !strings.ContainsAny(opts.Pattern, "*?[") {
+		args := []string{"-framerate", fmt.Sprintf("%d", opts.FPS), "-i", opts.Pattern}
+		if opts.Width > 0 && opts.Height > 0 {
+			args = append(args, "-vf", fitFilter(opts.Width, opts.Height, opts.FPS))
+		} else {
+			args = append(args, "-vf", "pad=ceil(iw/2)*2:ceil(ih/2)*2,format=yuv420p")
+		}
+		args = append(args, encodeArgs(opts.Output)...)
+		if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+			return nil, err
+		}
+		return &ImagesToVideoResult{Width: opts.Width, Height: opts.Height}, nil
+	}
+
+	clips := opts.Images
+	if len(clips) == 0 {
+		matches, err := filepath.Glob(opts.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no images match %s", opts.Pattern)
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			clips = append(clips, ImageClip{Image: match, Duration: 1 / float64(opts.FPS)})
+		}
+	}
+	clips, err := resolveClips(clips, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, clip := range clips {
+		if _, err := os.Stat(clip.Image); err != nil {
+			return nil, fmt.Errorf("image not found: %s", clip.Image)
+		}
+		if err := validateOutputPath(opts.Output, clip.Image); err != nil {
+			return nil, err
+		}
+	}
+
+	width, height := opts.Width, opts.Height
+	if width <= 0 || height <= 0 {
+		info, err := o.GetVideoInfo(ctx, clips[0].Image)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", clips[0].Image, err)
+		}
+		width, height = info.Width+info.Width%2, info.Height+info.Height%2
+	}
+	result := &ImagesToVideoResult{Images: len(clips), Width: width, Height: height}
+	for _, clip := range clips {
+		result.Duration += clip.Duration
+	}
+
+	var args []string
+	if hasTransitions(clips) {
+		args = buildTransitionArgs(clips, width, height, opts.FPS)
+	} else {
+		tempDir, cleanup, err := scratch.Dir("images")
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		script := filepath.Join(tempDir, "images.ffconcat")
+		if err := os.WriteFile(script, []byte(buildConcatScript(clips)), 0644); err != nil {
+			return nil, fmt.Errorf("failed to create concat script: %w", err)
+		}
+		args = []string{
+			"-f", "concat",
+			"-safe", "0",
+			"-i", script,
+			"-vf", fitFilter(width, height, opts.FPS),
+			"-t", fmt.Sprintf("%.3f", result.Duration),
+		}
+	}
+	args = append(args, encodeArgs(opts.Output)...)
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// resolveClips fills in default durations and transitions and checks them
+func resolveClips(clips []ImageClip, opts ImagesToVideoOptions) ([]ImageClip, error) {
+	resolved := make([]ImageClip, len(clips))
+	for i, clip := range clips {
+		if clip.Image == "" {
+			return nil, fmt.Errorf("image %d has no path", i+1)
+		}
+		if clip.Duration == 0 {
+			clip.Duration = opts.Duration
+		}
+		if clip.Duration == 0 {
+			clip.Duration = 3
+		}
+		if clip.Duration < 0 {
+			return nil, fmt.Errorf("image %d has a negative duration", i+1)
+		}
+		if clip.Transition == "" {
+			clip.Transition = opts.Transition
+		}
+		if clip.Transition == "" || clip.Transition == "none" || i == len(clips)-1 {
+			clip.Transition = "cut"
+		}
+		if !xfadeName.MatchString(clip.Transition) {
+			return nil, fmt.Errorf("invalid transition %q for image %d", clip.Transition, i+1)
+		}
+		resolved[i] = clip
+	}
+
+	transition := opts.TransitionDuration
+	if transition <= 0 {
+		transition = 0.5
+	}
+	for i := range resolved {
+		if resolved[i].Transition == "cut" {
+			continue
+		}
+		// A transition can't outlast either image
+		if limit := min(resolved[i].Duration, resolved[i+1].Duration); transition > limit {
+			return nil, fmt.Errorf("transition of %.2fs after image %d is longer than an image it joins (%.2fs)", transition, i+1, limit)
+		}
+		resolved[i].overlap = transition
+	}
+	return resolved, nil
+}
+
+// hasTransitions reports whether any clip transitions into the next
+func hasTransitions(clips []ImageClip) bool {
+	for _, clip := range clips {
+		if clip.Transition != "cut" {
+			return true
+		}
+	}
+	return false
+}
+
+// buildConcatScript writes clips as an ffconcat script. Paths are quoted,
+// so spaces, backslashes and wildcard characters are taken literally, and
+// the last image is listed twice so its duration is honoured.
+func buildConcatScript(clips []ImageClip) string {
+	var b strings.Builder
+	b.WriteString("ffconcat version 1.0\n")
+	for _, clip := range clips {
+		fmt.Fprintf(&b, "file %s\nduration %.6f\n", quoteConcatPath(clip.Image), clip.Duration)
+	}
+	fmt.Fprintf(&b, "file %s\n", quoteConcatPath(clips[len(clips)-1].Image))
+	return b.String()
+}
+
+// quoteConcatPath single-quotes an absolute path for a concat script
+func quoteConcatPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return "'" + strings.ReplaceAll(path, "'", `'\''`) + "'"
+}
+
+// buildTransitionArgs loops each image as its own input and joins them with
+// xfade. Each image runs on under the next one's transition, so images stay
+// on screen for their full duration.
+func buildTransitionArgs(clips []ImageClip, width, height, fps int) []string {
+	var args, parts []string
+	for i, clip := range clips {
+		args = append(args,
+			"-loop", "1",
+			"-framerate", fmt.Sprintf("%d", fps),
+			"-t", fmt.Sprintf("%.3f", clip.Duration+clip.overlap),
+			"-i", clip.Image,
+		)
+		parts = append(parts, fmt.Sprintf("[%d:v]%s,settb=AVTB[s%d]", i, fitFilter(width, height, fps), i))
+	}
+
+	current := "s0"
+	offset := 0.0
+	for i := 1; i < len(clips); i++ {
+		prev := clips[i-1]
+		offset += prev.Duration
+		next := fmt.Sprintf("x%d", i)
+		if i == len(clips)-1 {
+			next = "v"
+		}
+		if prev.Transition == "cut" {
+			parts = append(parts, fmt.Sprintf("[%s][s%d]concat=n=2:v=1:a=0[%s]", current, i, next))
+		} else {
+			parts = append(parts, fmt.Sprintf("[%s][s%d]xfade=transition=%s:duration=%.3f:offset=%.3f[%s]",
+				current, i, prev.Transition, prev.overlap, offset, next))
+		}
+		current = next
+	}
+	if len(clips) == 1 {
+		parts[0] = strings.TrimSuffix(parts[0], "[s0]") + "[v]"
+	}
+	return append(args, "-filter_complex", strings.Join(parts, ";"), "-map", "[v]")
+}
+
+// fitFilter letterboxes images of any size into the frame
+func fitFilter(width, height, fps int) string {
+	return fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1,fps=%d,format=yuv420p",
+		width, height, width, height, fps)
+}
+
+// encodeArgs are the H.264 output arguments for videos made from images
+func encodeArgs(output string) []string {
+	return []string{
+		"-c:v", "libx264",
+		"-preset", "medium",
+		"-crf", "23",
+		"-pix_fmt", "yuv420p",
+		"-movflags", "+faststart",
+		"-y",
+		output,
+	}
+}
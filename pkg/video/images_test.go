@@ -0,0 +1,69 @@
+package video
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildConcatScript(t *testing.T) {
+	dir := t.TempDir()
+	clips, err := resolveClips([]ImageClip{
+		{Image: filepath.Join(dir, "it's [final] *.png"), Duration: 2},
+		{Image: filepath.Join(dir, "b.jpg")},
+	}, ImagesToVideoOptions{Duration: 1.5})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "ffconcat version 1.0\n" +
+		"file '" + dir + "/it'\\''s [final] *.png'\nduration 2.000000\n" +
+		"file '" + dir + "/b.jpg'\nduration 1.500000\n" +
+		"file '" + dir + "/b.jpg'\n"
+	if got := buildConcatScript(clips); got != want {
+		t.Errorf("buildConcatScript() =\n%s\nwant\n%s", got, want)
+	}
+	if hasTransitions(clips) {
+		t.Error("Clips without transitions should use the concat script")
+	}
+}
+
+func TestBuildTransitionArgs(t *testing.T) {
+	clips, err := resolveClips([]ImageClip{
+		{Image: "a.png", Duration: 2, Transition: "wipeleft"},
+		{Image: "b.png", Duration: 3, Transition: "cut"},
+		{Image: "c.png", Duration: 1, Transition: "fade"},
+	}, ImagesToVideoOptions{TransitionDuration: 0.5})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if clips[2].Transition != "cut" {
+		t.Errorf("Last image transition = %q, want cut", clips[2].Transition)
+	}
+
+	args := buildTransitionArgs(clips, 1280, 720, 25)
+	joined := strings.Join(args, " ")
+	for _, want := range []string{
+		"-loop 1 -framerate 25 -t 2.500 -i a.png",
+		"-loop 1 -framerate 25 -t 3.000 -i b.png",
+		"-loop 1 -framerate 25 -t 1.000 -i c.png",
+		"[0:v]scale=1280:720:force_original_aspect_ratio=decrease,pad=1280:720:(ow-iw)/2:(oh-ih)/2,setsar=1,fps=25,format=yuv420p,settb=AVTB[s0]",
+		"[s0][s1]xfade=transition=wipeleft:duration=0.500:offset=2.000[x1]",
+		"[x1][s2]concat=n=2:v=1:a=0[v]",
+		"-map [v]",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Arguments %q missing %q", joined, want)
+		}
+	}
+
+	for _, clips := range [][]ImageClip{
+		{{Image: "a.png", Duration: 0.3, Transition: "fade"}, {Image: "b.png"}},
+		{{Image: "a.png", Transition: "fade;drawtext"}, {Image: "b.png"}},
+		{{Image: "a.png", Duration: -1}},
+		{{Duration: 1}},
+	} {
+		if _, err := resolveClips(clips, ImagesToVideoOptions{}); err == nil {
+			t.Errorf("Expected an error for %+v", clips)
+		}
+	}
+}
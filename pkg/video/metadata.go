@@ -0,0 +1,277 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sensitiveTagPatterns match tag keys that reveal where or with what a video
+// was recorded, such as location (QuickTime ©xyz and ISO 6709) and the
+// phone or camera make, model and firmware
+var sensitiveTagPatterns = []string{"location", "gps", "make", "model", "software", "device", "serial", "camera", "lens", "owner"}
+
+// ChapterMarker is a chapter stored in a media file
+type ChapterMarker struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Title string  `json:"title"`
+}
+
+// StreamMetadata contains the tags of one stream
+type StreamMetadata struct {
+	Index    int               `json:"index"`
+	Type     string            `json:"type"` // video, audio, subtitle, data or attachment
+	Codec    string            `json:"codec,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Rotation int               `json:"rotation,omitempty"`
+}
+
+// Metadata contains the container tags, streams and chapters of a file
+type Metadata struct {
+	Format       string            `json:"format"`
+	Duration     float64           `json:"duration"`
+	Tags         map[string]string `json:"tags"`
+	CreationTime string            `json:"creationTime,omitempty"`
+	Rotation     int               `json:"rotation"` // Clockwise degrees players rotate the video by
+	Streams      []StreamMetadata  `json:"streams"`
+	Chapters     []ChapterMarker   `json:"chapters"`
+	Sensitive    []string          `json:"sensitive,omitempty"` // What StripSensitive removes
+}
+
+// MetadataOptions contains the changes to make to a file's metadata
+type MetadataOptions struct {
+	Input          string
+	Output         string
+	Tags           map[string]string // Container tags to set; an empty value removes the tag
+	Rotation       *int              // Clockwise display rotation: 0, 90, 180 or 270
+	CreationTime   *time.Time
+	StripSensitive bool // Remove location, device tags and data tracks such as GPS telemetry
+	RemoveChapters bool
+}
+
+// GetMetadata reads the container tags, stream tags, rotation and chapters
+// of a file
+func (o *Operations) GetMetadata(ctx context.Context, input string) (*Metadata, error) {
+	output, err := o.ffmpeg.Probe(ctx,
+		"-v", "quiet",
+		"-print_format", "json",
+		"-show_format",
+		"-show_streams",
+		"-show_chapters",
+		input,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return parseMetadata(output)
+}
+
+// parseMetadata parses ffprobe's JSON output into Metadata
+func parseMetadata(output string) (*Metadata, error) {
+	var probeData struct {
+		Format struct {
+			FormatName string            `json:"format_name"`
+			Duration   string            `json:"duration"`
+			Tags       map[string]string `json:"tags"`
+		} `json:"format"`
+		Streams []struct {
+			Index        int               `json:"index"`
+			CodecType    string            `json:"codec_type"`
+			CodecName    string            `json:"codec_name"`
+			CodecTag     string            `json:"codec_tag_string"`
+			Tags         map[string]string `json:"tags"`
+			SideDataList []struct {
+				Rotation *float64 `json:"rotation"`
+			} `json:"side_data_list"`
+		} `json:"streams"`
+		Chapters []struct {
+			StartTime string            `json:"start_time"`
+			EndTime   string            `json:"end_time"`
+			Tags      map[string]string `json:"tags"`
+		} `json:"chapters"`
+	}
+	if err := json.Unmarshal([]byte(output), &probeData); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	meta := &Metadata{
+		Format:       probeData.Format.FormatName,
+		Tags:         probeData.Format.Tags,
+		CreationTime: probeData.Format.Tags["creation_time"],
+		Streams:      []StreamMetadata{},
+		Chapters:     []ChapterMarker{},
+	}
+	if meta.Tags == nil {
+		meta.Tags = map[string]string{}
+	}
+	meta.Duration, _ = strconv.ParseFloat(probeData.Format.Duration, 64)
+	for _, key := range slices.Sorted(maps.Keys(meta.Tags)) {
+		if isSensitiveTag(key) {
+			meta.Sensitive = append(meta.Sensitive, key)
+		}
+	}
+
+	rotationSet := false
+	for _, s := range probeData.Streams {
+		stream := StreamMetadata{
+			Index: s.Index,
+			Type:  s.CodecType,
+			Codec: s.CodecName,
+			Tags:  s.Tags,
+		}
+		// The display matrix rotation is counterclockwise; the older
+		// rotate tag is clockwise
+		for _, sd := range s.SideDataList {
+			if sd.Rotation != nil {
+				stream.Rotation = normalizeRotation(-int(math.Round(*sd.Rotation)))
+			}
+		}
+		if rotate, err := strconv.Atoi(s.Tags["rotate"]); err == nil && stream.Rotation == 0 {
+			stream.Rotation = normalizeRotation(rotate)
+		}
+		if s.CodecType == "video" && !rotationSet {
+			meta.Rotation = stream.Rotation
+			rotationSet = true
+		}
+		if meta.CreationTime == "" {
+			meta.CreationTime = s.Tags["creation_time"]
+		}
+		if stream.Codec == "" {
+			stream.Codec = s.CodecTag
+		}
+		meta.Streams = append(meta.Streams, stream)
+
+		if s.CodecType == "data" || s.CodecType == "attachment" {
+			meta.Sensitive = append(meta.Sensitive, fmt.Sprintf("stream %d: %s track", s.Index, s.CodecType))
+			continue
+		}
+		for _, key := range slices.Sorted(maps.Keys(s.Tags)) {
+			if isSensitiveTag(key) {
+				meta.Sensitive = append(meta.Sensitive, fmt.Sprintf("stream %d: %s", s.Index, key))
+			}
+		}
+	}
+
+	for _, ch := range probeData.Chapters {
+		start, _ := strconv.ParseFloat(ch.StartTime, 64)
+		end, _ := strconv.ParseFloat(ch.EndTime, 64)
+		meta.Chapters = append(meta.Chapters, ChapterMarker{Start: start, End: end, Title: ch.Tags["title"]})
+	}
+	return meta, nil
+}
+
+// SetMetadata writes a copy of the input with changed tags, rotation,
+// creation time or chapters, without re-encoding
+func (o *Operations) SetMetadata(ctx context.Context, opts MetadataOptions) error {
+	if err := validateOutputPath(opts.Output, opts.Input); err != nil {
+		return err
+	}
+	current, err := o.GetMetadata(ctx, opts.Input)
+	if err != nil {
+		return fmt.Errorf("failed to read metadata: %w", err)
+	}
+	args, err := buildMetadataArgs(opts, current)
+	if err != nil {
+		return err
+	}
+	return o.ffmpeg.Execute(ctx, args...)
+}
+
+// streamSpecifiers are the stream types kept when sensitive data is
+// stripped, by FFmpeg stream specifier
+var streamSpecifiers = map[string]string{"video": "v", "audio": "a", "subtitle": "s"}
+
+// buildMetadataArgs returns the FFmpeg arguments for SetMetadata
+func buildMetadataArgs(opts MetadataOptions, current *Metadata) ([]string, error) {
+	tags := map[string]string{}
+	var streamTags []string
+	if opts.StripSensitive {
+		// Everything is dropped, then the harmless tags are written back
+		for key, value := range current.Tags {
+			if !isSensitiveTag(key) {
+				tags[key] = value
+			}
+		}
+		// Output streams are renumbered by the maps below, so tags are
+		// addressed by type and position within the type, which the maps keep
+		counts := map[string]int{}
+		for _, s := range current.Streams {
+			spec, ok := streamSpecifiers[s.Type]
+			if !ok {
+				continue
+			}
+			n := counts[spec]
+			counts[spec]++
+			for _, key := range []string{"language", "title", "handler_name"} {
+				if value, ok := s.Tags[key]; ok {
+					streamTags = append(streamTags, fmt.Sprintf("-metadata:s:%s:%d", spec, n), key+"="+value)
+				}
+			}
+		}
+	}
+	for key, value := range opts.Tags {
+		if key == "" || strings.ContainsAny(key, "=\n") {
+			return nil, fmt.Errorf("invalid tag name %q", key)
+		}
+		if value == "" && opts.StripSensitive {
+			delete(tags, key)
+			continue
+		}
+		tags[key] = value
+	}
+	if opts.CreationTime != nil {
+		tags["creation_time"] = opts.CreationTime.UTC().Format("2006-01-02T15:04:05.000000Z")
+	}
+
+	var args []string
+	if opts.Rotation != nil {
+		rotation := *opts.Rotation
+		if rotation%90 != 0 {
+			return nil, fmt.Errorf("rotation must be 0, 90, 180 or 270, got: %d", rotation)
+		}
+		if !slices.ContainsFunc(current.Streams, func(s StreamMetadata) bool { return s.Type == "video" }) {
+			return nil, fmt.Errorf("input has no video stream to rotate")
+		}
+		// The display matrix is counterclockwise and replaces any existing
+		// rotation, so the pixels are never touched
+		args = append(args, "-display_rotation:v:0", strconv.Itoa(-normalizeRotation(rotation)))
+	}
+	args = append(args, "-i", opts.Input)
+
+	if opts.StripSensitive {
+		// Data tracks carry GPS and sensor telemetry, e.g. GoPro and DJI
+		args = append(args,
+			"-map", "0:v?", "-map", "0:a?", "-map", "0:s?",
+			"-map_metadata", "-1",
+		)
+	} else {
+		args = append(args, "-map", "0")
+	}
+	if opts.RemoveChapters {
+		args = append(args, "-map_chapters", "-1")
+	}
+	args = append(args, "-c", "copy")
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		args = append(args, "-metadata", key+"="+tags[key])
+	}
+	args = append(args, streamTags...)
+	return append(args, "-y", opts.Output), nil
+}
+
+// isSensitiveTag reports whether a tag key reveals location or device details
+func isSensitiveTag(key string) bool {
+	key = strings.ToLower(key)
+	return slices.ContainsFunc(sensitiveTagPatterns, func(p string) bool { return strings.Contains(key, p) })
+}
+
+// normalizeRotation maps degrees into 0-359
+func normalizeRotation(degrees int) int {
+	return ((degrees % 360) + 360) % 360
+}
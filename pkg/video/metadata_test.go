@@ -0,0 +1,137 @@
+package video
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const iphoneProbe = `{
+	"streams": [
+		{"index": 0, "codec_type": "video", "codec_name": "hevc",
+		 "tags": {"language": "und", "handler_name": "Core Media Video", "creation_time": "2026-05-01T10:00:00.000000Z"},
+		 "side_data_list": [{"side_data_type": "Display Matrix", "rotation": -90}]},
+		{"index": 1, "codec_type": "audio", "codec_name": "aac", "tags": {"language": "eng"}},
+		{"index": 2, "codec_type": "data", "codec_tag_string": "mebx", "tags": {}}
+	],
+	"chapters": [
+		{"start_time": "0.000000", "end_time": "12.500000", "tags": {"title": "Intro"}}
+	],
+	"format": {
+		"format_name": "mov,mp4,m4a,3gp,3g2,mj2",
+		"duration": "30.000000",
+		"tags": {
+			"major_brand": "qt  ",
+			"title": "Beach",
+			"creation_time": "2026-05-01T10:00:00.000000Z",
+			"com.apple.quicktime.location.ISO6709": "+37.7749-122.4194+010.000/",
+			"com.apple.quicktime.make": "Apple",
+			"com.apple.quicktime.model": "iPhone 15 Pro",
+			"com.apple.quicktime.software": "18.4"
+		}
+	}
+}`
+
+func TestParseMetadata(t *testing.T) {
+	meta, err := parseMetadata(iphoneProbe)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if meta.Rotation != 90 || meta.Streams[0].Rotation != 90 {
+		t.Errorf("Rotation = %d, want 90 clockwise", meta.Rotation)
+	}
+	if meta.Duration != 30 || meta.CreationTime != "2026-05-01T10:00:00.000000Z" || meta.Tags["title"] != "Beach" {
+		t.Errorf("Metadata %+v", meta)
+	}
+	if len(meta.Chapters) != 1 || meta.Chapters[0].End != 12.5 || meta.Chapters[0].Title != "Intro" {
+		t.Errorf("Chapters %+v", meta.Chapters)
+	}
+	want := []string{
+		"com.apple.quicktime.location.ISO6709",
+		"com.apple.quicktime.make",
+		"com.apple.quicktime.model",
+		"com.apple.quicktime.software",
+		"stream 2: data track",
+	}
+	if strings.Join(meta.Sensitive, "|") != strings.Join(want, "|") {
+		t.Errorf("Sensitive = %q, want %q", meta.Sensitive, want)
+	}
+}
+
+func TestBuildMetadataArgs(t *testing.T) {
+	meta, err := parseMetadata(iphoneProbe)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rotation := 180
+	created := time.Date(2026, 6, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	args, err := buildMetadataArgs(MetadataOptions{
+		Input:          "in.mov",
+		Output:         "out.mov",
+		Tags:           map[string]string{"title": "Launch day", "comment": ""},
+		Rotation:       &rotation,
+		CreationTime:   &created,
+		StripSensitive: true,
+	}, meta)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	for _, want := range []string{
+		"-display_rotation:v:0 -180 -i in.mov",
+		"-map 0:v? -map 0:a? -map 0:s? -map_metadata -1 -c copy",
+		"-metadata creation_time=2026-06-01T10:00:00.000000Z -metadata major_brand=qt   -metadata title=Launch day",
+		"-metadata:s:v:0 language=und -metadata:s:v:0 handler_name=Core Media Video -metadata:s:a:0 language=eng -y out.mov",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Arguments %q missing %q", joined, want)
+		}
+	}
+	for _, leaked := range []string{"ISO6709", "iPhone", "comment"} {
+		if strings.Contains(joined, leaked) {
+			t.Errorf("Arguments %q still contain %q", joined, leaked)
+		}
+	}
+
+	args, err = buildMetadataArgs(MetadataOptions{Input: "in.mov", Output: "out.mov", Tags: map[string]string{"comment": ""}, RemoveChapters: true}, meta)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if joined := strings.Join(args, " "); joined != "-i in.mov -map 0 -map_chapters -1 -c copy -metadata comment= -y out.mov" {
+		t.Errorf("Arguments %q", joined)
+	}
+
+	bad := 45
+	for _, opts := range []MetadataOptions{
+		{Rotation: &bad},
+		{Tags: map[string]string{"a=b": "c"}},
+	} {
+		if _, err := buildMetadataArgs(opts, meta); err == nil {
+			t.Errorf("Expected an error for %+v", opts)
+		}
+	}
+}
+
+func TestBuildMetadataArgsStreamOrder(t *testing.T) {
+	// Audio first and a data track before the subtitles: the output puts
+	// video first and drops the data track
+	meta := &Metadata{Streams: []StreamMetadata{
+		{Index: 0, Type: "audio", Tags: map[string]string{"language": "eng"}},
+		{Index: 1, Type: "video", Tags: map[string]string{"handler_name": "Camera"}},
+		{Index: 2, Type: "data", Tags: map[string]string{"handler_name": "GoPro MET"}},
+		{Index: 3, Type: "audio", Tags: map[string]string{"language": "fra"}},
+		{Index: 4, Type: "subtitle", Tags: map[string]string{"title": "English"}},
+	}}
+	args, err := buildMetadataArgs(MetadataOptions{Input: "in.mp4", Output: "out.mp4", StripSensitive: true}, meta)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	want := "-metadata:s:a:0 language=eng -metadata:s:v:0 handler_name=Camera -metadata:s:a:1 language=fra -metadata:s:s:0 title=English -y out.mp4"
+	if !strings.HasSuffix(joined, want) {
+		t.Errorf("Arguments %q, want suffix %q", joined, want)
+	}
+	if strings.Contains(joined, "GoPro") {
+		t.Errorf("Arguments %q kept the data track's tags", joined)
+	}
+}
@@ -0,0 +1,161 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// MultiAspectOptions contains options for rendering one edit in several aspect ratios
+type MultiAspectOptions struct {
+	Input       string
+	OutputDir   string
+	BaseName    string   // Output file name prefix (default: input file name)
+	Aspects     []string // Any of "16:9", "9:16", "1:1" (default: 16:9 and 9:16)
+	Quality     string   // high, medium, low (default: medium)
+	AutoReframe bool     // Follow the subject when cropping instead of a center crop
+}
+
+// AspectOutput describes one rendered aspect ratio
+type AspectOutput struct {
+	Aspect    string `json:"aspect"`
+	Path      string `json:"path"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Method    string `json:"method"` // scale, crop, reframe, or blur-fill
+	Keyframes int    `json:"keyframes,omitempty"`
+}
+
+// aspectTargets maps supported aspect ratios to output resolutions
+var aspectTargets = map[string][2]int{
+	"16:9": {1920, 1080},
+	"9:16": {1080, 1920},
+	"1:1":  {1080, 1080},
+}
+
+// ExportMultiAspect renders horizontal, vertical and square versions of a
+// video in a single FFmpeg job so decoding happens once. Outputs narrower
+// than the source are cropped (following the subject with auto-reframe),
+// and wider outputs are filled with a blurred copy of the source.
+func (o *Operations) ExportMultiAspect(ctx context.Context, opts MultiAspectOptions) ([]AspectOutput, error) {
+	aspects := opts.Aspects
+	if len(aspects) == 0 {
+		aspects = []string{"16:9", "9:16"}
+	}
+	for _, aspect := range aspects {
+		if _, ok := aspectTargets[aspect]; !ok {
+			return nil, fmt.Errorf("unsupported aspect ratio: %s (use 16:9, 9:16 or 1:1)", aspect)
+		}
+	}
+
+	info, err := o.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+	if info.Width <= 0 || info.Height <= 0 {
+		return nil, fmt.Errorf("could not determine video dimensions")
+	}
+
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	baseName := opts.BaseName
+	if baseName == "" {
+		baseName = strings.TrimSuffix(filepath.Base(opts.Input), filepath.Ext(opts.Input))
+	}
+
+	sourceAspect := float64(info.Width) / float64(info.Height)
+	filters := []string{fmt.Sprintf("[0:v]split=%d%s", len(aspects), splitLabels("s", len(aspects)))}
+	outputs := make([]AspectOutput, len(aspects))
+
+	for i, aspect := range aspects {
+		target := aspectTargets[aspect]
+		targetAspect := float64(target[0]) / float64(target[1])
+		out := AspectOutput{
+			Aspect: aspect,
+			Path:   filepath.Join(opts.OutputDir, fmt.Sprintf("%s_%s.mp4", baseName, strings.ReplaceAll(aspect, ":", "x"))),
+			Width:  target[0],
+			Height: target[1],
+		}
+		if err := validateOutputPath(out.Path, opts.Input); err != nil {
+			return nil, err
+		}
+
+		in := fmt.Sprintf("[s%d]", i)
+		label := fmt.Sprintf("[v%d]", i)
+
+		switch {
+		case math.Abs(sourceAspect-targetAspect) < 0.01:
+			out.Method = "scale"
+			filters = append(filters, fmt.Sprintf("%sscale=%d:%d,setsar=1%s", in, target[0], target[1], label))
+
+		case targetAspect < sourceAspect:
+			cropWidth := int(math.Round(float64(info.Height)*targetAspect/2)) * 2
+			cropX := fmt.Sprintf("%d", (info.Width-cropWidth)/2)
+			out.Method = "crop"
+			if opts.AutoReframe {
+				path, err := o.AnalyzeReframe(ctx, opts.Input, targetAspect)
+				if err != nil {
+					return nil, fmt.Errorf("auto-reframe failed for %s: %w", aspect, err)
+				}
+				cropX = BuildReframeCropX(path, info.Width, cropWidth)
+				out.Method = "reframe"
+				out.Keyframes = len(path)
+			}
+			filters = append(filters, fmt.Sprintf("%scrop=%d:%d:x='%s':y=0,scale=%d:%d,setsar=1%s",
+				in, cropWidth, info.Height, cropX, target[0], target[1], label))
+
+		default:
+			// Wider than the source: fit the frame over a blurred fill
+			out.Method = "blur-fill"
+			filters = append(filters,
+				fmt.Sprintf("%ssplit[f%d][b%d]", in, i, i),
+				fmt.Sprintf("[b%d]scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d,boxblur=20:2[bb%d]",
+					i, target[0], target[1], target[0], target[1], i),
+				fmt.Sprintf("[f%d]scale=%d:%d:force_original_aspect_ratio=decrease[ff%d]", i, target[0], target[1], i),
+				fmt.Sprintf("[bb%d][ff%d]overlay=(W-w)/2:(H-h)/2,setsar=1%s", i, i, label),
+			)
+		}
+
+		outputs[i] = out
+	}
+
+	args := []string{
+		"-i", opts.Input,
+		"-filter_complex", strings.Join(filters, ";"),
+	}
+	crf := strconv.Itoa(qualityToCRF(opts.Quality))
+	for i, out := range outputs {
+		args = append(args,
+			"-map", fmt.Sprintf("[v%d]", i),
+			"-map", "0:a?",
+			"-c:v", "libx264",
+			"-crf", crf,
+			"-preset", "medium",
+			"-pix_fmt", "yuv420p",
+			"-c:a", "aac",
+			"-b:a", "192k",
+			"-movflags", "+faststart",
+			"-y", out.Path,
+		)
+	}
+
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, err
+	}
+
+	return outputs, nil
+}
+
+// splitLabels returns output pad labels like [s0][s1]
+func splitLabels(prefix string, n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteString(fmt.Sprintf("[%s%d]", prefix, i))
+	}
+	return b.String()
+}
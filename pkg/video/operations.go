@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
 )
 
 // Operations handles video editing operations
@@ -180,9 +182,14 @@ func (o *Operations) Concatenate(ctx context.Context, opts ConcatenateOptions) e
 		}
 	}
 
-	// Create a temporary concat file
-	concatFile := filepath.Join(os.TempDir(), "concat_list.txt")
-	defer os.Remove(concatFile)
+	// Write the file list in a directory of its own so concurrent calls don't
+	// share it
+	tempDir, cleanup, err := scratch.Dir("concat")
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	concatFile := filepath.Join(tempDir, "concat_list.txt")
 
 	// Write file list
 	var lines []string
@@ -279,42 +286,140 @@ func (o *Operations) ExtractAudio(ctx context.Context, opts ExtractAudioOptions)
 
 // TranscodeOptions contains options for transcoding
 type TranscodeOptions struct {
-	Input       string
-	Output      string
-	VideoCodec  string
-	AudioCodec  string
-	Quality     string
-	Preset      string
-	MaxWidth    int
-	MaxHeight   int
+	Input        string
+	Output       string
+	VideoCodec   string
+	AudioCodec   string
+	Quality      string
+	Preset       string
+	MaxWidth     int
+	MaxHeight    int
+	RateControl  string // crf (default), abr, vbr or cbr; abr when only Bitrate is set
+	Bitrate      int    // Target video bitrate in kbps, required for abr, vbr and cbr
+	MaxBitrate   int    // vbr peak in kbps (default: 1.5x Bitrate)
+	BufferSize   int    // Rate control buffer in kbps (default: 2x the peak for vbr, Bitrate for cbr)
+	AudioBitrate int    // Audio bitrate in kbps
+	TwoPass      bool   // Analyze the video in a first pass, then encode; needs a bitrate mode
 }
 
+// RateControlModes lists the TranscodeOptions.RateControl values
+var RateControlModes = []string{"crf", "abr", "vbr", "cbr"}
+
 // Transcode converts a video to a different format/codec
 func (o *Operations) Transcode(ctx context.Context, opts TranscodeOptions) error {
 	if err := validateOutputPath(opts.Output, opts.Input); err != nil {
 		return err
 	}
 
-	args := []string{"-i", opts.Input}
+	if !opts.TwoPass {
+		args, err := buildTranscodeArgs(opts, 0, "")
+		if err != nil {
+			return err
+		}
+		return o.ffmpeg.Execute(ctx, args...)
+	}
 
-	// Video codec
-	if opts.VideoCodec != "" {
-		args = append(args, "-c:v", opts.VideoCodec)
-	} else {
-		args = append(args, "-c:v", "libx264")
+	tempDir, cleanup, err := scratch.Dir("twopass")
+	if err != nil {
+		return err
 	}
+	defer cleanup()
+	passLog := filepath.Join(tempDir, "pass")
 
-	// Audio codec
-	if opts.AudioCodec != "" {
-		args = append(args, "-c:a", opts.AudioCodec)
-	} else {
-		args = append(args, "-c:a", "aac")
+	for pass := 1; pass <= 2; pass++ {
+		args, err := buildTranscodeArgs(opts, pass, passLog)
+		if err != nil {
+			return err
+		}
+		if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+			return fmt.Errorf("pass %d: %w", pass, err)
+		}
 	}
+	return nil
+}
 
-	// Quality (CRF)
-	if opts.Quality != "" {
-		crf := qualityToCRF(opts.Quality)
-		args = append(args, "-crf", strconv.Itoa(crf))
+// buildTranscodeArgs returns the FFmpeg arguments for a single-pass encode
+// (pass 0) or one pass of a two-pass encode sharing passLog. The first pass
+// only analyzes video and writes nothing but the log.
+func buildTranscodeArgs(opts TranscodeOptions, pass int, passLog string) ([]string, error) {
+	mode := opts.RateControl
+	if mode == "" {
+		mode = "crf"
+		if opts.Bitrate > 0 {
+			mode = "abr"
+		}
+	}
+	if !slices.Contains(RateControlModes, mode) {
+		return nil, fmt.Errorf("unknown rate control %q (use %s)", mode, strings.Join(RateControlModes, ", "))
+	}
+	if mode != "crf" && opts.Bitrate <= 0 {
+		return nil, fmt.Errorf("%s rate control needs a target bitrate", mode)
+	}
+	if mode == "crf" && pass > 0 {
+		return nil, fmt.Errorf("two-pass encoding needs a target bitrate (abr, vbr or cbr)")
+	}
+	if opts.MaxBitrate < 0 || opts.BufferSize < 0 || opts.AudioBitrate < 0 {
+		return nil, fmt.Errorf("bitrates cannot be negative")
+	}
+
+	args := []string{"-i", opts.Input}
+
+	// Video codec
+	videoCodec := opts.VideoCodec
+	if videoCodec == "" {
+		videoCodec = "libx264"
+	}
+	args = append(args, "-c:v", videoCodec)
+
+	// Rate control: CRF quality, or a target bitrate with optional limits
+	var x264Params, x265Params []string
+	switch mode {
+	case "crf":
+		if opts.Quality != "" {
+			crf := qualityToCRF(opts.Quality)
+			args = append(args, "-crf", strconv.Itoa(crf))
+		}
+	case "abr":
+		args = append(args, "-b:v", fmt.Sprintf("%dk", opts.Bitrate))
+	case "vbr":
+		peak := opts.MaxBitrate
+		if peak == 0 {
+			peak = opts.Bitrate * 3 / 2
+		}
+		if peak < opts.Bitrate {
+			return nil, fmt.Errorf("max bitrate %dk is below the target %dk", peak, opts.Bitrate)
+		}
+		buffer := opts.BufferSize
+		if buffer == 0 {
+			buffer = peak * 2
+		}
+		args = append(args, "-b:v", fmt.Sprintf("%dk", opts.Bitrate),
+			"-maxrate", fmt.Sprintf("%dk", peak), "-bufsize", fmt.Sprintf("%dk", buffer))
+	case "cbr":
+		buffer := opts.BufferSize
+		if buffer == 0 {
+			buffer = opts.Bitrate
+		}
+		rate := fmt.Sprintf("%dk", opts.Bitrate)
+		args = append(args, "-b:v", rate, "-minrate", rate, "-maxrate", rate, "-bufsize", fmt.Sprintf("%dk", buffer))
+		// Signal CBR in the stream and pad with filler so the rate holds in quiet scenes
+		x264Params = append(x264Params, "nal-hrd=cbr", "force-cfr=1")
+		x265Params = append(x265Params, "hrd=1", "strict-cbr=1")
+	}
+
+	// Pass
+	if pass > 0 {
+		if videoCodec == "libx265" {
+			x265Params = append(x265Params, fmt.Sprintf("pass=%d", pass), "stats="+passLog)
+		} else {
+			args = append(args, "-pass", strconv.Itoa(pass), "-passlogfile", passLog)
+		}
+	}
+	switch {
+	case videoCodec == "libx264" && len(x264Params) > 0:
+		args = append(args, "-x264-params", strings.Join(x264Params, ":"))
+	case videoCodec == "libx265" && len(x265Params) > 0:
+		args = append(args, "-x265-params", strings.Join(x265Params, ":"))
 	}
 
 	// Preset
@@ -329,9 +434,21 @@ func (o *Operations) Transcode(ctx context.Context, opts TranscodeOptions) error
 		args = append(args, "-vf", scale)
 	}
 
-	args = append(args, "-y", opts.Output)
+	if pass == 1 {
+		return append(args, "-an", "-f", "null", "-y", os.DevNull), nil
+	}
 
-	return o.ffmpeg.Execute(ctx, args...)
+	// Audio codec
+	if opts.AudioCodec != "" {
+		args = append(args, "-c:a", opts.AudioCodec)
+	} else {
+		args = append(args, "-c:a", "aac")
+	}
+	if opts.AudioBitrate > 0 {
+		args = append(args, "-b:a", fmt.Sprintf("%dk", opts.AudioBitrate))
+	}
+
+	return append(args, "-y", opts.Output), nil
 }
 
 // Helper functions
@@ -385,54 +502,56 @@ func qualityToCRF(quality string) int {
 
 // ExtractFramesOptions contains options for extracting frames
 type ExtractFramesOptions struct {
-	Input      string
-	OutputDir  string
-	FPS        *float64 // Frames per second to extract
-	StartTime  *float64 // Start time in seconds
-	Duration   *float64 // Duration in seconds
-	Format     string   // Output format: jpg, png, etc.
-	FrameCount *int     // Extract specific number of frames evenly distributed
+	Input       string
+	OutputDir   string
+	FPS         *float64 // Frames per second to extract
+	StartTime   *float64 // Start time in seconds
+	Duration    *float64 // Duration in seconds
+	Format      string   // Output format: jpg, png, tiff, exr, etc.
+	FrameCount  *int     // Extract specific number of frames evenly distributed
+	NamePattern string   // File name without extension; a run of # or %0Nd is the frame number (default: frame_####)
+	StartNumber *int     // Number of the first file (default: 1; VFX plates often start at 1001)
+	Alpha       bool     // Keep the alpha channel (png, tiff and exr)
+	Manifest    bool     // Write frames.json mapping frame numbers to timestamps
 }
 
-// ExtractFrames extracts frames from video as images
-func (o *Operations) ExtractFrames(ctx context.Context, opts ExtractFramesOptions) error {
+// ExtractFrames extracts frames from video as images and returns the file
+// and source timestamp of each
+func (o *Operations) ExtractFrames(ctx context.Context, opts ExtractFramesOptions) (*FrameManifest, error) {
 	if err := validateOutputPath(opts.OutputDir, opts.Input); err != nil {
-		return err
+		return nil, err
 	}
 
-	args := []string{"-i", opts.Input}
-
-	// Start time
-	if opts.StartTime != nil {
-		args = append(args, "-ss", fmt.Sprintf("%.2f", *opts.StartTime))
+	info, err := o.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
 	}
-
-	// Duration
-	if opts.Duration != nil {
-		args = append(args, "-t", fmt.Sprintf("%.2f", *opts.Duration))
+	decoder := ""
+	if opts.Alpha {
+		decoder = alphaDecoder(info.VideoCodec)
 	}
 
-	// Frame rate or frame count
-	if opts.FrameCount != nil {
-		// Extract specific number of frames
-		args = append(args, "-vf", fmt.Sprintf("select='not(mod(n\\,%d))'", *opts.FrameCount))
-		args = append(args, "-vsync", "0")
-	} else if opts.FPS != nil {
-		// Extract at specific FPS
-		args = append(args, "-vf", fmt.Sprintf("fps=%.2f", *opts.FPS))
+	args, pattern, err := buildExtractFramesArgs(opts, decoder)
+	if err != nil {
+		return nil, err
 	}
-
-	// Output format
-	format := opts.Format
-	if format == "" {
-		format = "jpg"
+	output, err := o.ffmpeg.ExecuteWithOutput(ctx, args...)
+	if err != nil {
+		return nil, err
 	}
 
-	// Output pattern
-	outputPattern := filepath.Join(opts.OutputDir, fmt.Sprintf("frame_%%04d.%s", format))
-	args = append(args, "-y", outputPattern)
-
-	return o.ffmpeg.Execute(ctx, args...)
+	manifest := buildFrameManifest(opts.Input, pattern, opts.StartNumber, info.FPS, parseShowinfoTimes(output))
+	if opts.Manifest {
+		manifest.File = filepath.Join(opts.OutputDir, "frames.json")
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+		}
+		if err := os.WriteFile(manifest.File, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write manifest: %w", err)
+		}
+	}
+	return manifest, nil
 }
 
 // AdjustSpeedOptions contains options for adjusting video speed
@@ -454,25 +573,9 @@ func (o *Operations) AdjustSpeed(ctx context.Context, opts AdjustSpeedOptions) e
 
 	// Calculate PTS and audio tempo
 	pts := 1.0 / opts.Speed
-	atempo := opts.Speed
-
-	// FFmpeg atempo filter only supports 0.5-2.0 range
-	// For values outside this range, chain multiple atempo filters
-	atempoFilters := []string{}
-	remaining := atempo
-
-	for remaining > 2.0 {
-		atempoFilters = append(atempoFilters, "atempo=2.0")
-		remaining /= 2.0
-	}
-	for remaining < 0.5 {
-		atempoFilters = append(atempoFilters, "atempo=0.5")
-		remaining /= 0.5
-	}
-	atempoFilters = append(atempoFilters, fmt.Sprintf("atempo=%.4f", remaining))
 
 	videoFilter := fmt.Sprintf("setpts=%.4f*PTS", pts)
-	audioFilter := strings.Join(atempoFilters, ",")
+	audioFilter := ffmpeg.AtempoChain(opts.Speed)
 
 	args := []string{
 		"-i", opts.Input,
@@ -492,9 +595,10 @@ type ConvertVideoOptions struct {
 	Format       string // Output format: mp4, webm, avi, etc.
 	VideoCodec   string // Video codec: h264, vp9, etc.
 	AudioCodec   string // Audio codec: aac, opus, etc.
-	Quality      string // Quality: high, medium, low
+	Quality      string // Quality: high, medium, low, or archival (lossless FFV1)
 	Bitrate      *int   // Video bitrate in kbps
 	AudioBitrate *int   // Audio bitrate in kbps
+	CodecPreset  string // Named encoder setup (hevc, av1, prores_hq, dnxhr_hq, ...); replaces VideoCodec
 }
 
 // ConvertVideo converts video to different format
@@ -502,6 +606,12 @@ func (o *Operations) ConvertVideo(ctx context.Context, opts ConvertVideoOptions)
 	if err := validateOutputPath(opts.Output, opts.Input); err != nil {
 		return err
 	}
+	if opts.Quality == "archival" && opts.CodecPreset == "" {
+		opts.CodecPreset = "ffv1"
+	}
+	if opts.CodecPreset != "" {
+		return o.convertWithPreset(ctx, opts)
+	}
 
 	args := []string{"-i", opts.Input}
 
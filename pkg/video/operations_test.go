@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
@@ -196,3 +197,64 @@ func TestConcatenate(t *testing.T) {
 		t.Errorf("Expected duration ~%f, got %f", expectedDuration, info.Duration)
 	}
 }
+
+func TestBuildTranscodeArgs(t *testing.T) {
+	base := TranscodeOptions{Input: "in.mp4", Output: "out.mp4"}
+
+	opts := base
+	opts.Quality = "high"
+	args, err := buildTranscodeArgs(opts, 0, "")
+	if err != nil || !strings.Contains(strings.Join(args, " "), "-crf") {
+		t.Errorf("Expected CRF by default: %v %v", args, err)
+	}
+
+	opts = base
+	opts.RateControl = "cbr"
+	opts.Bitrate = 8000
+	args, _ = buildTranscodeArgs(opts, 0, "")
+	joined := strings.Join(args, " ")
+	for _, want := range []string{"-b:v 8000k -minrate 8000k -maxrate 8000k -bufsize 8000k", "-x264-params nal-hrd=cbr:force-cfr=1"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Expected %q in args: %s", want, joined)
+		}
+	}
+
+	opts = base
+	opts.RateControl = "vbr"
+	opts.Bitrate = 4000
+	args, _ = buildTranscodeArgs(opts, 0, "")
+	if joined := strings.Join(args, " "); !strings.Contains(joined, "-b:v 4000k -maxrate 6000k -bufsize 12000k") {
+		t.Errorf("Expected constrained VBR defaults: %s", joined)
+	}
+
+	opts = base
+	opts.Bitrate = 5000
+	opts.AudioBitrate = 192
+	first, _ := buildTranscodeArgs(opts, 1, "/tmp/log")
+	second, _ := buildTranscodeArgs(opts, 2, "/tmp/log")
+	if joined := strings.Join(first, " "); !strings.Contains(joined, "-pass 1 -passlogfile /tmp/log") || !strings.Contains(joined, "-an -f null") || strings.Contains(joined, "out.mp4") {
+		t.Errorf("Expected an analysis-only first pass: %s", joined)
+	}
+	if joined := strings.Join(second, " "); !strings.Contains(joined, "-pass 2") || !strings.Contains(joined, "-b:a 192k") || second[len(second)-1] != "out.mp4" {
+		t.Errorf("Expected the second pass to write the output: %s", joined)
+	}
+
+	opts.VideoCodec = "libx265"
+	args, _ = buildTranscodeArgs(opts, 2, "/tmp/log")
+	if joined := strings.Join(args, " "); !strings.Contains(joined, "-x265-params pass=2:stats=/tmp/log") {
+		t.Errorf("Expected x265 pass parameters: %s", joined)
+	}
+
+	for _, bad := range []TranscodeOptions{
+		{RateControl: "cbr"},
+		{RateControl: "fast", Bitrate: 1000},
+		{RateControl: "vbr", Bitrate: 4000, MaxBitrate: 2000},
+	} {
+		if _, err := buildTranscodeArgs(bad, 0, ""); err == nil {
+			t.Errorf("Expected an error for %+v", bad)
+		}
+	}
+	if _, err := buildTranscodeArgs(base, 1, "/tmp/log"); err == nil {
+		t.Error("Expected two-pass CRF to be rejected")
+	}
+}
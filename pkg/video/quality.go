@@ -0,0 +1,275 @@
+package video
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+)
+
+// Supported quality metrics
+const (
+	MetricVMAF = "vmaf"
+	MetricSSIM = "ssim"
+	MetricPSNR = "psnr"
+)
+
+// CompareQualityOptions contains options for comparing an encode against its source
+type CompareQualityOptions struct {
+	Reference       string   // Source / original video
+	Distorted       string   // Encoded video to score
+	Metrics         []string // Any of vmaf, ssim, psnr (default: all three)
+	SegmentDuration float64  // Seconds per reported segment (default: 10)
+	VMAFModel       string   // Optional libvmaf model (e.g. "version=vmaf_v0.6.1neg")
+}
+
+// MetricSummary holds aggregate scores for one metric
+type MetricSummary struct {
+	Mean float64 `json:"mean"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+}
+
+// QualitySegment holds metric scores for a time range
+type QualitySegment struct {
+	Start   float64                  `json:"start"`
+	End     float64                  `json:"end"`
+	Metrics map[string]MetricSummary `json:"metrics"`
+}
+
+// QualityResult contains overall and per-segment quality scores
+type QualityResult struct {
+	Reference  string                   `json:"reference"`
+	Distorted  string                   `json:"distorted"`
+	FrameCount int                      `json:"frameCount"`
+	Metrics    map[string]MetricSummary `json:"metrics"`
+	Segments   []QualitySegment         `json:"segments"`
+}
+
+// CompareQuality scores an encode against its reference with VMAF, SSIM and
+// PSNR in a single decode pass and reports overall and per-segment results
+func (o *Operations) CompareQuality(ctx context.Context, opts CompareQualityOptions) (*QualityResult, error) {
+	metrics := opts.Metrics
+	if len(metrics) == 0 {
+		metrics = []string{MetricVMAF, MetricSSIM, MetricPSNR}
+	}
+	for _, m := range metrics {
+		if m != MetricVMAF && m != MetricSSIM && m != MetricPSNR {
+			return nil, fmt.Errorf("unsupported metric: %s (use vmaf, ssim or psnr)", m)
+		}
+	}
+	segmentDuration := opts.SegmentDuration
+	if segmentDuration <= 0 {
+		segmentDuration = 10
+	}
+
+	refInfo, err := o.GetVideoInfo(ctx, opts.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reference info: %w", err)
+	}
+	if refInfo.Width <= 0 || refInfo.Height <= 0 {
+		return nil, fmt.Errorf("could not determine reference dimensions")
+	}
+	fps := refInfo.FPS
+	if fps <= 0 {
+		fps = 30
+	}
+
+	tempDir, cleanup, err := scratch.Dir("quality")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	logFiles := make(map[string]string, len(metrics))
+	for _, m := range metrics {
+		ext := ".log"
+		if m == MetricVMAF {
+			ext = ".json"
+		}
+		logFiles[m] = filepath.Join(tempDir, m+ext)
+	}
+
+	filter := buildQualityFilter(metrics, logFiles, refInfo.Width, refInfo.Height, opts.VMAFModel)
+	output, err := o.ffmpeg.ExecuteWithOutput(ctx,
+		"-i", opts.Distorted,
+		"-i", opts.Reference,
+		"-filter_complex", filter,
+		"-f", "null", "-",
+	)
+	if err != nil {
+		if strings.Contains(output, "No such filter: 'libvmaf'") {
+			return nil, fmt.Errorf("this FFmpeg build does not include libvmaf; request only ssim and psnr")
+		}
+		return nil, fmt.Errorf("quality comparison failed: %w", err)
+	}
+
+	frameScores := make(map[string][]float64, len(metrics))
+	for _, m := range metrics {
+		data, err := os.ReadFile(logFiles[m])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s log: %w", m, err)
+		}
+		var scores []float64
+		switch m {
+		case MetricVMAF:
+			scores, err = parseVMAFLog(data)
+		case MetricSSIM:
+			scores = parseStatsLog(string(data), ssimStatPattern)
+		case MetricPSNR:
+			scores = parseStatsLog(string(data), psnrStatPattern)
+		}
+		if err != nil {
+			return nil, err
+		}
+		frameScores[m] = scores
+	}
+
+	result := aggregateQuality(frameScores, fps, segmentDuration)
+	result.Reference = opts.Reference
+	result.Distorted = opts.Distorted
+	return result, nil
+}
+
+// buildQualityFilter scales the distorted stream to the reference size and
+// feeds both through each metric filter. Input 0 is distorted, input 1 is reference.
+func buildQualityFilter(metrics []string, logFiles map[string]string, width, height int, vmafModel string) string {
+	n := len(metrics)
+	var parts []string
+
+	distorted := fmt.Sprintf("[0:v]scale=%d:%d:flags=bicubic,setpts=PTS-STARTPTS", width, height)
+	reference := "[1:v]setpts=PTS-STARTPTS"
+	if n > 1 {
+		distorted += fmt.Sprintf(",split=%d", n)
+		reference += fmt.Sprintf(",split=%d", n)
+	}
+	for i := range metrics {
+		distorted += fmt.Sprintf("[d%d]", i)
+		reference += fmt.Sprintf("[r%d]", i)
+	}
+	parts = append(parts, distorted, reference)
+
+	for i, m := range metrics {
+		logPath := ffmpeg.EscapeFilterPath(logFiles[m])
+		switch m {
+		case MetricVMAF:
+			vmaf := fmt.Sprintf("[d%d][r%d]libvmaf=log_fmt=json:log_path='%s'", i, i, logPath)
+			if vmafModel != "" {
+				vmaf += fmt.Sprintf(":model='%s'", ffmpeg.EscapeFilterPath(vmafModel))
+			}
+			parts = append(parts, vmaf)
+		case MetricSSIM:
+			parts = append(parts, fmt.Sprintf("[d%d][r%d]ssim=stats_file='%s'", i, i, logPath))
+		case MetricPSNR:
+			parts = append(parts, fmt.Sprintf("[d%d][r%d]psnr=stats_file='%s'", i, i, logPath))
+		}
+	}
+
+	return strings.Join(parts, ";")
+}
+
+var (
+	ssimStatPattern = regexp.MustCompile(`All:([0-9.]+)`)
+	psnrStatPattern = regexp.MustCompile(`psnr_avg:([0-9.]+|inf)`)
+)
+
+// parseStatsLog extracts one score per line from an ssim/psnr stats file.
+// Identical frames report PSNR as inf, which is capped at 100 dB.
+func parseStatsLog(data string, pattern *regexp.Regexp) []float64 {
+	var scores []float64
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		match := pattern.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		if match[1] == "inf" {
+			scores = append(scores, 100)
+			continue
+		}
+		if v, err := strconv.ParseFloat(match[1], 64); err == nil {
+			scores = append(scores, v)
+		}
+	}
+	return scores
+}
+
+// parseVMAFLog extracts per-frame VMAF scores from a libvmaf JSON log
+func parseVMAFLog(data []byte) ([]float64, error) {
+	var log struct {
+		Frames []struct {
+			FrameNum int                `json:"frameNum"`
+			Metrics  map[string]float64 `json:"metrics"`
+		} `json:"frames"`
+	}
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("failed to parse VMAF log: %w", err)
+	}
+
+	scores := make([]float64, len(log.Frames))
+	for i, frame := range log.Frames {
+		scores[i] = frame.Metrics["vmaf"]
+	}
+	return scores, nil
+}
+
+// aggregateQuality groups per-frame scores into overall and per-segment summaries
+func aggregateQuality(frameScores map[string][]float64, fps, segmentDuration float64) *QualityResult {
+	result := &QualityResult{Metrics: make(map[string]MetricSummary)}
+
+	frameCount := 0
+	for m, scores := range frameScores {
+		if len(scores) > frameCount {
+			frameCount = len(scores)
+		}
+		if len(scores) > 0 {
+			result.Metrics[m] = summarizeScores(scores)
+		}
+	}
+	result.FrameCount = frameCount
+
+	framesPerSegment := int(math.Round(fps * segmentDuration))
+	if framesPerSegment < 1 {
+		framesPerSegment = 1
+	}
+	totalDuration := float64(frameCount) / fps
+
+	for start := 0; start < frameCount; start += framesPerSegment {
+		end := start + framesPerSegment
+		segment := QualitySegment{
+			Start:   float64(start) / fps,
+			End:     math.Min(float64(end)/fps, totalDuration),
+			Metrics: make(map[string]MetricSummary),
+		}
+		for m, scores := range frameScores {
+			if start >= len(scores) {
+				continue
+			}
+			segment.Metrics[m] = summarizeScores(scores[start:min(end, len(scores))])
+		}
+		result.Segments = append(result.Segments, segment)
+	}
+
+	return result
+}
+
+func summarizeScores(scores []float64) MetricSummary {
+	summary := MetricSummary{Min: math.Inf(1), Max: math.Inf(-1)}
+	total := 0.0
+	for _, s := range scores {
+		total += s
+		summary.Min = math.Min(summary.Min, s)
+		summary.Max = math.Max(summary.Max, s)
+	}
+	summary.Mean = total / float64(len(scores))
+	return summary
+}
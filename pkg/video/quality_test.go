@@ -0,0 +1,65 @@
+package video
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStatsLog(t *testing.T) {
+	psnr := "n:1 mse_avg:0.52 mse_y:0.60 psnr_avg:50.97 psnr_y:50.34\nn:2 mse_avg:0.00 psnr_avg:inf psnr_y:inf\n"
+	scores := parseStatsLog(psnr, psnrStatPattern)
+	if len(scores) != 2 || scores[0] != 50.97 || scores[1] != 100 {
+		t.Errorf("Unexpected PSNR scores: %v", scores)
+	}
+
+	ssim := "n:1 Y:0.995 U:0.990 V:0.991 All:0.993 (21.5)\n"
+	scores = parseStatsLog(ssim, ssimStatPattern)
+	if len(scores) != 1 || scores[0] != 0.993 {
+		t.Errorf("Unexpected SSIM scores: %v", scores)
+	}
+}
+
+func TestParseVMAFLog(t *testing.T) {
+	log := `{"frames":[{"frameNum":0,"metrics":{"vmaf":95.5}},{"frameNum":1,"metrics":{"vmaf":90.5}}]}`
+	scores, err := parseVMAFLog([]byte(log))
+	if err != nil {
+		t.Fatalf("parseVMAFLog failed: %v", err)
+	}
+	if len(scores) != 2 || scores[1] != 90.5 {
+		t.Errorf("Unexpected VMAF scores: %v", scores)
+	}
+}
+
+func TestAggregateQuality(t *testing.T) {
+	// 5 frames at 2 fps with 1 second segments -> 3 segments
+	result := aggregateQuality(map[string][]float64{
+		MetricVMAF: {90, 80, 70, 60, 50},
+	}, 2, 1)
+
+	if result.FrameCount != 5 {
+		t.Errorf("Expected 5 frames, got %d", result.FrameCount)
+	}
+	if len(result.Segments) != 3 {
+		t.Fatalf("Expected 3 segments, got %d", len(result.Segments))
+	}
+	if got := result.Segments[0].Metrics[MetricVMAF].Mean; got != 85 {
+		t.Errorf("Expected first segment mean 85, got %.2f", got)
+	}
+	if result.Segments[2].End != 2.5 {
+		t.Errorf("Expected last segment to end at 2.5s, got %.2f", result.Segments[2].End)
+	}
+	if result.Metrics[MetricVMAF].Min != 50 || result.Metrics[MetricVMAF].Mean != 70 {
+		t.Errorf("Unexpected overall summary: %+v", result.Metrics[MetricVMAF])
+	}
+}
+
+func TestBuildQualityFilter(t *testing.T) {
+	filter := buildQualityFilter([]string{MetricSSIM, MetricPSNR},
+		map[string]string{MetricSSIM: "/tmp/q/ssim.log", MetricPSNR: "/tmp/q/psnr.log"}, 1920, 1080, "")
+
+	for _, want := range []string{"scale=1920:1080", "split=2[d0][d1]", "[d0][r0]ssim=stats_file='/tmp/q/ssim.log'", "[d1][r1]psnr="} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q, got %s", want, filter)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/scratch"
+)
+
+// ReframeKeyframe is the horizontal crop center at a point in time,
+// as a fraction of the source width (0 = left edge, 1 = right edge)
+type ReframeKeyframe struct {
+	Time   float64 `json:"time"`
+	Center float64 `json:"center"`
+}
+
+const (
+	reframeAnalysisWidth = 160  // Width frames are downscaled to for analysis
+	reframeDeadzone      = 0.08 // Minimum center shift (fraction of width) before the virtual camera moves
+	reframeMaxKeyframes  = 60   // Cap on keyframes to keep the crop expression small
+)
+
+// AnalyzeReframe samples frames and finds where the visual interest is
+// (edge detail plus motion) to keep it inside a narrower crop window with
+// the given aspect ratio (width/height). Returns a smoothed camera path.
+func (o *Operations) AnalyzeReframe(ctx context.Context, input string, targetAspect float64) ([]ReframeKeyframe, error) {
+	return o.AnalyzeReframeRange(ctx, input, targetAspect, 0, 0)
+}
+
+// AnalyzeReframeRange is AnalyzeReframe for the section between start and
+// end (0 for the end of the input). Path times are relative to start.
+func (o *Operations) AnalyzeReframeRange(ctx context.Context, input string, targetAspect, start, end float64) ([]ReframeKeyframe, error) {
+	info, err := o.GetVideoInfo(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+	if info.Width <= 0 || info.Height <= 0 || info.Duration <= 0 {
+		return nil, fmt.Errorf("could not determine video dimensions or duration")
+	}
+	if end <= 0 || end > info.Duration {
+		end = info.Duration
+	}
+	if start < 0 || start >= end {
+		return nil, fmt.Errorf("invalid range: %.2fs to %.2fs", start, end)
+	}
+
+	interval := math.Max(1, (end-start)/120)
+	width := reframeAnalysisWidth
+	height := int(math.Round(float64(width)*float64(info.Height)/float64(info.Width)/2)) * 2
+	if height < 2 {
+		height = 2
+	}
+
+	tempDir, cleanup, err := scratch.Dir("reframe")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	rawPath := filepath.Join(tempDir, "frames.gray")
+	if err := o.ffmpeg.Execute(ctx,
+		"-ss", fmt.Sprintf("%.3f", start),
+		"-t", fmt.Sprintf("%.3f", end-start),
+		"-i", input,
+		"-vf", fmt.Sprintf("fps=1/%.6f,scale=%d:%d,format=gray", interval, width, height),
+		"-f", "rawvideo",
+		"-y", rawPath,
+	); err != nil {
+		return nil, fmt.Errorf("failed to sample frames: %w", err)
+	}
+
+	data, err := os.ReadFile(rawPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sampled frames: %w", err)
+	}
+
+	frameSize := width * height
+	var centers []float64
+	var previous []byte
+	for offset := 0; offset+frameSize <= len(data); offset += frameSize {
+		frame := data[offset : offset+frameSize]
+		scores := columnInterest(frame, previous, width, height)
+		centers = append(centers, bestWindowCenter(scores, float64(height)*targetAspect))
+		previous = frame
+	}
+	if len(centers) == 0 {
+		return nil, fmt.Errorf("no frames sampled from %s", input)
+	}
+
+	return BuildReframePath(centers, interval), nil
+}
+
+// columnInterest scores each column by edge detail and, when a previous frame
+// is available, by motion (which usually follows the subject)
+func columnInterest(frame, previous []byte, width, height int) []float64 {
+	scores := make([]float64, width)
+	for y := 1; y < height; y++ {
+		row := y * width
+		for x := 1; x < width; x++ {
+			i := row + x
+			dx := math.Abs(float64(frame[i]) - float64(frame[i-1]))
+			dy := math.Abs(float64(frame[i]) - float64(frame[i-width]))
+			scores[x] += dx + dy
+			if previous != nil {
+				scores[x] += 2 * math.Abs(float64(frame[i])-float64(previous[i]))
+			}
+		}
+	}
+	return scores
+}
+
+// bestWindowCenter slides a window of the given width across the column
+// scores and returns the center of the highest-scoring window as a fraction
+func bestWindowCenter(scores []float64, windowWidth float64) float64 {
+	n := len(scores)
+	window := int(math.Round(windowWidth))
+	if window >= n || window <= 0 {
+		return 0.5
+	}
+
+	sum := 0.0
+	for i := 0; i < window; i++ {
+		sum += scores[i]
+	}
+	best, bestStart := sum, 0
+	// Prefer the center when the frame has no clear subject
+	centerStart := (n - window) / 2
+	for start := 1; start+window <= n; start++ {
+		sum += scores[start+window-1] - scores[start-1]
+		if sum > best*1.05 || (sum >= best && abs(start-centerStart) < abs(bestStart-centerStart)) {
+			best, bestStart = sum, start
+		}
+	}
+	if best == 0 {
+		return 0.5
+	}
+	return (float64(bestStart) + float64(window)/2) / float64(n)
+}
+
+// BuildReframePath smooths per-sample centers and only moves the virtual
+// camera when the subject drifts outside a deadzone, avoiding jitter
+func BuildReframePath(centers []float64, interval float64) []ReframeKeyframe {
+	smoothed := make([]float64, len(centers))
+	for i := range centers {
+		lo, hi := max(0, i-2), min(len(centers), i+3)
+		total := 0.0
+		for _, c := range centers[lo:hi] {
+			total += c
+		}
+		smoothed[i] = total / float64(hi-lo)
+	}
+
+	path := []ReframeKeyframe{{Time: 0, Center: smoothed[0]}}
+	for i := 1; i < len(smoothed); i++ {
+		last := path[len(path)-1]
+		if math.Abs(smoothed[i]-last.Center) < reframeDeadzone {
+			continue
+		}
+		t := float64(i) * interval
+		// Hold the previous position until the move starts
+		if t-interval > last.Time {
+			path = append(path, ReframeKeyframe{Time: t - interval, Center: last.Center})
+		}
+		path = append(path, ReframeKeyframe{Time: t, Center: smoothed[i]})
+	}
+
+	if len(path) > reframeMaxKeyframes {
+		step := float64(len(path)-1) / float64(reframeMaxKeyframes-1)
+		reduced := make([]ReframeKeyframe, 0, reframeMaxKeyframes)
+		for i := 0; i < reframeMaxKeyframes; i++ {
+			reduced = append(reduced, path[int(math.Round(float64(i)*step))])
+		}
+		path = reduced
+	}
+
+	return path
+}
+
+// BuildReframeCropX builds an FFmpeg expression for the crop x offset that
+// follows the camera path with linear interpolation between keyframes.
+// Each keyframe's offset is clamped to the frame, so the moves between
+// them stay inside it too.
+func BuildReframeCropX(path []ReframeKeyframe, sourceWidth, cropWidth int) string {
+	maxX := float64(sourceWidth - cropWidth)
+	if len(path) == 0 {
+		return fmt.Sprintf("%.0f", maxX/2)
+	}
+
+	times := make([]float64, len(path))
+	offsets := make([]float64, len(path))
+	for i, kf := range path {
+		times[i] = kf.Time
+		offsets[i] = math.Max(0, math.Min(maxX, kf.Center*float64(sourceWidth)-float64(cropWidth)/2))
+	}
+	return ffmpeg.LinearExpr(times, offsets)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
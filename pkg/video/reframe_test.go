@@ -0,0 +1,53 @@
+package video
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBestWindowCenter(t *testing.T) {
+	// All detail sits in the right quarter of the frame
+	scores := make([]float64, 100)
+	for i := 75; i < 100; i++ {
+		scores[i] = 10
+	}
+	if center := bestWindowCenter(scores, 30); center < 0.8 {
+		t.Errorf("Expected window to follow detail on the right, got center %.2f", center)
+	}
+
+	// A flat frame stays centered
+	if center := bestWindowCenter(make([]float64, 100), 30); center != 0.5 {
+		t.Errorf("Expected centered window for an empty frame, got %.2f", center)
+	}
+}
+
+func TestBuildReframePath(t *testing.T) {
+	// Small jitter stays inside the deadzone, then the subject moves right
+	centers := []float64{0.5, 0.52, 0.49, 0.5, 0.8, 0.8, 0.8, 0.8, 0.8}
+	path := BuildReframePath(centers, 1)
+
+	if path[0].Time != 0 {
+		t.Fatalf("Expected path to start at 0, got %+v", path[0])
+	}
+	last := path[len(path)-1]
+	if last.Center < 0.7 {
+		t.Errorf("Expected camera to end on the right, got %+v", path)
+	}
+	for i := 1; i < len(path); i++ {
+		if path[i].Time < path[i-1].Time {
+			t.Errorf("Path times are not increasing: %+v", path)
+		}
+	}
+}
+
+func TestBuildReframeCropX(t *testing.T) {
+	expr := BuildReframeCropX([]ReframeKeyframe{{Time: 0, Center: 0.5}}, 1920, 608)
+	if expr != "656.0000" {
+		t.Errorf("Expected static centered crop, got %s", expr)
+	}
+
+	expr = BuildReframeCropX([]ReframeKeyframe{{Time: 0, Center: 0.5}, {Time: 2, Center: 1}}, 1920, 608)
+	if !strings.HasPrefix(expr, "if(lt(t,2.000),656.0000+(656.0000)*(t-0.000)/2.000,1312.0000)") {
+		t.Errorf("Unexpected interpolated crop expression: %s", expr)
+	}
+}
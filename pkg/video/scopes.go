@@ -0,0 +1,121 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ScopeTypes lists the scopes GenerateScopes can render
+var ScopeTypes = []string{"waveform", "vectorscope", "histogram"}
+
+// Scope panel size; panels are laid out two per row
+const (
+	scopePanelWidth  = 640
+	scopePanelHeight = 360
+)
+
+// scopeFilters render each scope from yuv444p input at panel size
+var scopeFilters = map[string]string{
+	// Luma by column on an IRE scale, like a broadcast waveform monitor
+	"waveform": fmt.Sprintf("waveform=mode=column:intensity=0.1:display=overlay:graticule=green:flags=numbers+dots:scale=ire,scale=%d:%d",
+		scopePanelWidth, scopePanelHeight),
+	// Chroma with skin tone and color target markings, kept square
+	"vectorscope": fmt.Sprintf("vectorscope=mode=color3:graticule=green:flags=name,scale=%d:%d,pad=%d:%d:(ow-iw)/2:0",
+		scopePanelHeight, scopePanelHeight, scopePanelWidth, scopePanelHeight),
+	"histogram": fmt.Sprintf("histogram=display_mode=overlay:levels_mode=linear,scale=%d:%d",
+		scopePanelWidth, scopePanelHeight),
+}
+
+// ScopesOptions contains options for rendering video scopes
+type ScopesOptions struct {
+	Input      string
+	Output     string   // Image for one frame, video when OverTime
+	Time       float64  // Frame to analyze in seconds
+	Scopes     []string // Any of ScopeTypes (default: all)
+	HideSource bool     // Leave out the picture panel
+	OverTime   bool     // Render a video of the scopes for the whole clip, or Duration from Time
+	Duration   float64  // Seconds to render when OverTime (default: to the end)
+}
+
+// GenerateScopes renders waveform, vectorscope and histogram panels, with
+// the picture itself, into one image for a frame or a video over time
+func (o *Operations) GenerateScopes(ctx context.Context, opts ScopesOptions) error {
+	if err := validateOutputPath(opts.Output, opts.Input); err != nil {
+		return err
+	}
+	if opts.Time < 0 || opts.Duration < 0 {
+		return fmt.Errorf("time and duration cannot be negative")
+	}
+	filter, err := buildScopesFilter(opts.Scopes, !opts.HideSource)
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	if opts.Time > 0 {
+		args = append(args, "-ss", fmt.Sprintf("%.3f", opts.Time))
+	}
+	args = append(args, "-i", opts.Input, "-filter_complex", filter, "-map", "[out]")
+	if opts.OverTime {
+		if opts.Duration > 0 {
+			args = append(args, "-t", fmt.Sprintf("%.3f", opts.Duration))
+		}
+		args = append(args, "-an", "-c:v", "libx264", "-crf", "20", "-pix_fmt", "yuv420p")
+	} else {
+		args = append(args, "-frames:v", "1", "-update", "1")
+	}
+	args = append(args, "-y", opts.Output)
+
+	return o.ffmpeg.Execute(ctx, args...)
+}
+
+// buildScopesFilter splits the picture into the requested scope panels and
+// stacks them into a grid two panels wide
+func buildScopesFilter(scopes []string, withSource bool) (string, error) {
+	if len(scopes) == 0 {
+		scopes = ScopeTypes
+	}
+	var panels []string
+	for _, scope := range scopes {
+		if !slices.Contains(ScopeTypes, scope) {
+			return "", fmt.Errorf("unknown scope %q (use %s)", scope, strings.Join(ScopeTypes, ", "))
+		}
+		if !slices.Contains(panels, scope) {
+			panels = append(panels, scope)
+		}
+	}
+	if withSource {
+		panels = append([]string{"source"}, panels...)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("[0:v]format=yuv444p")
+	if len(panels) > 1 {
+		sb.WriteString(fmt.Sprintf(",split=%d", len(panels)))
+	}
+	for i := range panels {
+		sb.WriteString(fmt.Sprintf("[in%d]", i))
+	}
+	var labels, layout []string
+	for i, panel := range panels {
+		filter := scopeFilters[panel]
+		if panel == "source" {
+			filter = fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2",
+				scopePanelWidth, scopePanelHeight, scopePanelWidth, scopePanelHeight)
+		}
+		sb.WriteString(fmt.Sprintf(";[in%d]%s,setsar=1[p%d]", i, filter, i))
+		labels = append(labels, fmt.Sprintf("[p%d]", i))
+		layout = append(layout, fmt.Sprintf("%d_%d", i%2*scopePanelWidth, i/2*scopePanelHeight))
+	}
+
+	switch len(panels) {
+	case 1:
+		sb.WriteString(";[p0]format=yuv420p[out]")
+	default:
+		sb.WriteString(fmt.Sprintf(";%sxstack=inputs=%d:layout=%s:fill=black,format=yuv420p[out]",
+			strings.Join(labels, ""), len(panels), strings.Join(layout, "|")))
+	}
+	return sb.String(), nil
+}
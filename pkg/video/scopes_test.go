@@ -0,0 +1,33 @@
+package video
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildScopesFilter(t *testing.T) {
+	filter, err := buildScopesFilter(nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"[0:v]format=yuv444p,split=4[in0][in1][in2][in3]",
+		"[in1]waveform=",
+		"[in2]vectorscope=",
+		"[in3]histogram=",
+		"xstack=inputs=4:layout=0_0|640_0|0_360|640_360",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected %q in filter: %s", want, filter)
+		}
+	}
+
+	filter, _ = buildScopesFilter([]string{"vectorscope"}, false)
+	if strings.Contains(filter, "split") || strings.Contains(filter, "xstack") || !strings.HasSuffix(filter, "[p0]format=yuv420p[out]") {
+		t.Errorf("Expected a single panel without split or stack: %s", filter)
+	}
+
+	if _, err := buildScopesFilter([]string{"parade"}, true); err == nil {
+		t.Error("Expected an error for an unknown scope")
+	}
+}
@@ -0,0 +1,232 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+)
+
+// silenceLeadIn is how much of a trimmed silence is kept next to speech so
+// cuts don't clip the first or last syllable
+const silenceLeadIn = 0.25
+
+// CropRect is a window's rectangle in recording pixels
+type CropRect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"width"`
+	H int `json:"height"`
+}
+
+// CursorPoint is the cursor position at a point in time, in recording
+// pixels, as logged by the screen recorder
+type CursorPoint struct {
+	Time float64 `json:"time"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+}
+
+// ScreenRecordingOptions contains options for preparing a screen recording
+type ScreenRecordingOptions struct {
+	Input            string
+	Output           string
+	FPS              int           // Constant output frame rate (default: 30)
+	Crop             *CropRect     // Window to keep, in recording pixels
+	Retina           bool          // Halve the resolution of a 2x (HiDPI) capture
+	MaxHeight        int           // Downscale to at most this height, never up
+	Cursor           []CursorPoint // Cursor path to highlight, interpolated linearly
+	CursorSize       int           // Highlight diameter in recording pixels (default: 48)
+	TrimSilence      bool          // Cut dead air at the start and end
+	MaxPause         float64       // Shorten silent pauses longer than this many seconds to it (0 = keep)
+	SilenceThreshold float64       // Silence threshold in dB (default: -40)
+}
+
+// ScreenRecordingResult summarizes a prepared screen recording
+type ScreenRecordingResult struct {
+	OriginalDuration float64    `json:"originalDuration"`
+	NewDuration      float64    `json:"newDuration"`
+	Removed          []TimeSpan `json:"removed"`
+}
+
+// PrepareScreenRecording applies the usual fixes to raw tutorial footage
+// in one pass: variable frame rate to constant, crop to the window,
+// downscale a retina capture, highlight the cursor and cut silences. The
+// cursor is drawn before cropping, so its path is in recording pixels.
+func (o *Operations) PrepareScreenRecording(ctx context.Context, opts ScreenRecordingOptions) (*ScreenRecordingResult, error) {
+	if err := validateOutputPath(opts.Output, opts.Input); err != nil {
+		return nil, err
+	}
+	info, err := o.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+	if info.Duration <= 0 {
+		return nil, fmt.Errorf("could not determine video duration")
+	}
+	if opts.Crop != nil {
+		c := opts.Crop
+		if c.W < 16 || c.H < 16 || c.X < 0 || c.Y < 0 || c.X+c.W > info.Width || c.Y+c.H > info.Height {
+			return nil, fmt.Errorf("crop %dx%d at %d,%d doesn't fit the %dx%d recording", c.W, c.H, c.X, c.Y, info.Width, info.Height)
+		}
+	}
+
+	var cuts []TimeSpan
+	if opts.TrimSilence || opts.MaxPause > 0 {
+		if !info.HasAudio {
+			return nil, fmt.Errorf("recording has no audio track to find silences in")
+		}
+		minDuration := 1.0
+		if opts.MaxPause > 0 {
+			minDuration = opts.MaxPause
+		}
+		threshold := opts.SilenceThreshold
+		if threshold == 0 {
+			threshold = -40
+		}
+		silences, err := o.DetectSilence(ctx, opts.Input, threshold, minDuration)
+		if err != nil {
+			return nil, err
+		}
+		cuts = silenceCuts(silences, info.Duration, opts.TrimSilence, opts.MaxPause)
+	}
+
+	videoFilter, audioFilter, err := buildScreenRecordingFilter(opts, cuts)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{
+		"-i", opts.Input,
+		"-filter_complex", videoFilter,
+		"-map", "[vout]",
+		"-c:v", "libx264",
+		"-preset", "medium",
+		"-crf", "20",
+	}
+	if info.HasAudio {
+		args = append(args, "-map", "0:a:0")
+		if audioFilter != "" {
+			args = append(args, "-af", audioFilter, "-c:a", "aac", "-b:a", "160k")
+		} else {
+			args = append(args, "-c:a", "copy")
+		}
+	}
+	args = append(args, "-movflags", "+faststart", "-y", opts.Output)
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, err
+	}
+
+	removed := 0.0
+	for _, cut := range cuts {
+		removed += cut.Duration
+	}
+	return &ScreenRecordingResult{
+		OriginalDuration: info.Duration,
+		NewDuration:      info.Duration - removed,
+		Removed:          cuts,
+	}, nil
+}
+
+// silenceCuts returns the ranges to remove: silences touching the start or
+// end when trimming, and the middle of pauses longer than maxPause, leaving
+// maxPause of each. A little of every trimmed silence is kept next to
+// speech.
+func silenceCuts(silences []TimeSpan, duration float64, trimEnds bool, maxPause float64) []TimeSpan {
+	var cuts []TimeSpan
+	add := func(start, end float64) {
+		if end-start > 0.05 {
+			cuts = append(cuts, TimeSpan{Start: start, End: end, Duration: end - start})
+		}
+	}
+	for _, s := range silences {
+		switch {
+		case trimEnds && s.Start <= 0.05 && s.End >= duration-0.05:
+			// Silent throughout: there is no speech to trim to
+		case trimEnds && s.Start <= 0.05:
+			add(0, s.End-silenceLeadIn)
+		case trimEnds && s.End >= duration-0.05:
+			add(s.Start+silenceLeadIn, duration)
+		case maxPause > 0 && s.Duration > maxPause:
+			add(s.Start+maxPause/2, s.End-maxPause/2)
+		}
+	}
+	return cuts
+}
+
+// buildScreenRecordingFilter returns the video graph, ending in [vout], and
+// the audio filter ("" to copy the audio) for a screen recording
+func buildScreenRecordingFilter(opts ScreenRecordingOptions, cuts []TimeSpan) (string, string, error) {
+	fps := opts.FPS
+	if fps == 0 {
+		fps = 30
+	}
+	if fps < 1 || fps > 120 {
+		return "", "", fmt.Errorf("fps must be between 1 and 120")
+	}
+
+	var graph []string
+	chain := []string{fmt.Sprintf("fps=%d", fps)}
+	source := "[0:v]"
+	if len(opts.Cursor) > 0 {
+		graph = append(graph, fmt.Sprintf("[0:v]fps=%d[rec]", fps), cursorHighlight(opts, fps))
+		chain = []string{fmt.Sprintf("[rec][cursor]overlay=x='%s':y='%s':shortest=1", cursorExpr(opts, "x"), cursorExpr(opts, "y"))}
+		source = ""
+	}
+	if c := opts.Crop; c != nil {
+		chain = append(chain, fmt.Sprintf("crop=%d:%d:%d:%d", c.W/2*2, c.H/2*2, c.X, c.Y))
+	}
+	if opts.Retina {
+		chain = append(chain, "scale=trunc(iw/4)*2:trunc(ih/4)*2:flags=lanczos")
+	}
+	if opts.MaxHeight > 0 {
+		chain = append(chain, fmt.Sprintf("scale=-2:'2*trunc(min(ih,%d)/2)':flags=lanczos", opts.MaxHeight))
+	}
+
+	audio := ""
+	if len(cuts) > 0 {
+		var ranges []string
+		for _, cut := range cuts {
+			ranges = append(ranges, fmt.Sprintf("between(t,%.3f,%.3f)", cut.Start, cut.End))
+		}
+		keep := fmt.Sprintf("not(%s)", strings.Join(ranges, "+"))
+		chain = append(chain, fmt.Sprintf("select='%s',setpts=N/(%d*TB)", keep, fps))
+		audio = fmt.Sprintf("aselect='%s',asetpts=N/SR/TB", keep)
+	}
+	chain = append(chain, "setsar=1", "format=yuv420p")
+	graph = append(graph, source+strings.Join(chain, ",")+"[vout]")
+	return strings.Join(graph, ";"), audio, nil
+}
+
+// cursorHighlight returns a source of translucent yellow discs, with a one
+// pixel soft edge, labeled [cursor]
+func cursorHighlight(opts ScreenRecordingOptions, fps int) string {
+	size := opts.CursorSize
+	if size <= 0 {
+		size = 48
+	}
+	r := float64(size) / 2
+	return fmt.Sprintf("color=c=black@0:s=%dx%d:r=%d,format=rgba,geq=r='255':g='220':b='0':a='110*clip(%.1f-hypot(X+0.5-%.1f,Y+0.5-%.1f),0,1)'[cursor]",
+		size, size, fps, r, r, r)
+}
+
+// cursorExpr returns the overlay position that centers the highlight on
+// the cursor along one axis ("x" or "y")
+func cursorExpr(opts ScreenRecordingOptions, axis string) string {
+	points := append([]CursorPoint(nil), opts.Cursor...)
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Time < points[j].Time })
+	times := make([]float64, len(points))
+	values := make([]float64, len(points))
+	for i, p := range points {
+		times[i], values[i] = p.Time, p.X
+		if axis == "y" {
+			values[i] = p.Y
+		}
+	}
+	size := "w"
+	if axis == "y" {
+		size = "h"
+	}
+	return fmt.Sprintf("%s-%s/2", ffmpeg.LinearExpr(times, values), size)
+}
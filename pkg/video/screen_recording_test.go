@@ -0,0 +1,70 @@
+package video
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSilenceCuts(t *testing.T) {
+	silences := []TimeSpan{
+		{Start: 0, End: 3, Duration: 3},
+		{Start: 10, End: 16, Duration: 6},
+		{Start: 20, End: 21, Duration: 1},
+		{Start: 55, End: 60, Duration: 5},
+	}
+	cuts := silenceCuts(silences, 60, true, 2)
+	want := []TimeSpan{
+		{Start: 0, End: 2.75, Duration: 2.75},
+		{Start: 11, End: 15, Duration: 4},
+		{Start: 55.25, End: 60, Duration: 4.75},
+	}
+	if len(cuts) != len(want) {
+		t.Fatalf("silenceCuts = %+v, want %+v", cuts, want)
+	}
+	for i := range want {
+		if cuts[i] != want[i] {
+			t.Errorf("cut %d = %+v, want %+v", i, cuts[i], want[i])
+		}
+	}
+
+	// Without trimming, silences at the ends are pauses like any other
+	if cuts := silenceCuts(silences, 60, false, 0); len(cuts) != 0 {
+		t.Errorf("Expected no cuts without trimming or a max pause, got %+v", cuts)
+	}
+}
+
+func TestBuildScreenRecordingFilter(t *testing.T) {
+	opts := ScreenRecordingOptions{
+		Crop:      &CropRect{X: 100, Y: 50, W: 1281, H: 721},
+		Retina:    true,
+		MaxHeight: 720,
+		Cursor:    []CursorPoint{{Time: 2, X: 300, Y: 200}, {Time: 0, X: 100, Y: 100}},
+	}
+	video, audio, err := buildScreenRecordingFilter(opts, []TimeSpan{{Start: 0, End: 2.75}, {Start: 11, End: 15}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"[0:v]fps=30[rec]",
+		"color=c=black@0:s=48x48:r=30",
+		"[rec][cursor]overlay=x='if(lt(t,2.000),100.0000+(200.0000)*(t-0.000)/2.000,300.0000)-w/2'",
+		"crop=1280:720:100:50",
+		"scale=trunc(iw/4)*2:trunc(ih/4)*2",
+		"scale=-2:'2*trunc(min(ih,720)/2)'",
+		"select='not(between(t,0.000,2.750)+between(t,11.000,15.000))',setpts=N/(30*TB)",
+		"format=yuv420p[vout]",
+	} {
+		if !strings.Contains(video, want) {
+			t.Errorf("Video filter %q missing %q", video, want)
+		}
+	}
+	if audio != "aselect='not(between(t,0.000,2.750)+between(t,11.000,15.000))',asetpts=N/SR/TB" {
+		t.Errorf("Audio filter = %q", audio)
+	}
+
+	// Just frame rate normalization, with the audio copied
+	video, audio, err = buildScreenRecordingFilter(ScreenRecordingOptions{FPS: 60}, nil)
+	if err != nil || video != "[0:v]fps=60,setsar=1,format=yuv420p[vout]" || audio != "" {
+		t.Errorf("Plain filter = %q, %q, %v", video, audio, err)
+	}
+}
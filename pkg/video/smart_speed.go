@@ -0,0 +1,222 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+)
+
+// SmartSpeedOptions contains options for silence-aware speed-up
+type SmartSpeedOptions struct {
+	Input              string
+	Output             string
+	SpeechSpeed        float64 // Speed for speech portions (default: 1.0)
+	SilenceSpeed       float64 // Speed for silent portions (default: 4.0)
+	SilenceThreshold   float64 // Silence threshold in dB (default: -35)
+	MinSilenceDuration float64 // Minimum silence length in seconds to speed up (default: 0.5)
+	Padding            float64 // Seconds kept at speech speed around each silence (default: 0.1)
+	IncludeStatic      bool    // Also treat frozen/static picture ranges as idle
+	MinStaticDuration  float64 // Minimum static length in seconds (default: 2.0)
+}
+
+// SmartSpeedResult summarizes a smart speed render
+type SmartSpeedResult struct {
+	OriginalDuration float64    `json:"originalDuration"`
+	NewDuration      float64    `json:"newDuration"`
+	TimeSaved        float64    `json:"timeSaved"`
+	IdleRanges       []TimeSpan `json:"idleRanges"`
+}
+
+// speedSegment is a contiguous range rendered at a single speed
+type speedSegment struct {
+	Start float64
+	End   float64
+	Speed float64
+}
+
+// SmartSpeed speeds up silent (and optionally static) portions of a video more
+// aggressively than speech. Audio tempo is changed with atempo so voices keep
+// their pitch.
+func (o *Operations) SmartSpeed(ctx context.Context, opts SmartSpeedOptions) (*SmartSpeedResult, error) {
+	if err := validateOutputPath(opts.Output, opts.Input); err != nil {
+		return nil, err
+	}
+
+	speechSpeed := opts.SpeechSpeed
+	if speechSpeed == 0 {
+		speechSpeed = 1.0
+	}
+	silenceSpeed := opts.SilenceSpeed
+	if silenceSpeed == 0 {
+		silenceSpeed = 4.0
+	}
+	if speechSpeed <= 0 || silenceSpeed <= 0 {
+		return nil, fmt.Errorf("speeds must be positive")
+	}
+	padding := opts.Padding
+	if padding == 0 {
+		padding = 0.1
+	}
+
+	info, err := o.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+	if info.Duration <= 0 {
+		return nil, fmt.Errorf("could not determine video duration")
+	}
+	if !info.HasAudio && !opts.IncludeStatic {
+		return nil, fmt.Errorf("video has no audio track; enable includeStatic to speed up static portions instead")
+	}
+
+	var idle []TimeSpan
+	if info.HasAudio {
+		silences, err := o.DetectSilence(ctx, opts.Input, opts.SilenceThreshold, opts.MinSilenceDuration)
+		if err != nil {
+			return nil, err
+		}
+		idle = append(idle, silences...)
+	}
+	if opts.IncludeStatic {
+		freezes, err := o.DetectFreezes(ctx, opts.Input, 0, opts.MinStaticDuration)
+		if err != nil {
+			return nil, err
+		}
+		idle = append(idle, freezes...)
+	}
+
+	idle = mergeTimeSpans(shrinkTimeSpans(idle, padding))
+	segments := buildSpeedSegments(idle, info.Duration, speechSpeed, silenceSpeed)
+
+	filter := buildSmartSpeedFilter(segments, info.HasAudio)
+	args := []string{
+		"-i", opts.Input,
+		"-filter_complex", filter,
+		"-map", "[outv]",
+	}
+	if info.HasAudio {
+		args = append(args, "-map", "[outa]")
+	}
+	args = append(args,
+		"-c:v", "libx264",
+		"-preset", "medium",
+		"-crf", "23",
+		"-pix_fmt", "yuv420p",
+	)
+	if info.HasAudio {
+		args = append(args, "-c:a", "aac")
+	}
+	args = append(args, "-y", opts.Output)
+
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, err
+	}
+
+	newDuration := 0.0
+	for _, seg := range segments {
+		newDuration += (seg.End - seg.Start) / seg.Speed
+	}
+
+	return &SmartSpeedResult{
+		OriginalDuration: info.Duration,
+		NewDuration:      newDuration,
+		TimeSaved:        info.Duration - newDuration,
+		IdleRanges:       idle,
+	}, nil
+}
+
+// shrinkTimeSpans trims padding from both ends of each span, dropping spans that vanish
+func shrinkTimeSpans(spans []TimeSpan, padding float64) []TimeSpan {
+	var result []TimeSpan
+	for _, span := range spans {
+		start := span.Start + padding
+		end := span.End - padding
+		if end-start <= 0.05 {
+			continue
+		}
+		result = append(result, TimeSpan{Start: start, End: end, Duration: end - start})
+	}
+	return result
+}
+
+// mergeTimeSpans sorts spans and merges any that overlap
+func mergeTimeSpans(spans []TimeSpan) []TimeSpan {
+	if len(spans) == 0 {
+		return spans
+	}
+
+	sorted := make([]TimeSpan, len(spans))
+	copy(sorted, spans)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	merged := []TimeSpan{sorted[0]}
+	for _, span := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if span.Start <= last.End {
+			if span.End > last.End {
+				last.End = span.End
+				last.Duration = last.End - last.Start
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
+
+// buildSpeedSegments covers [0, duration] with alternating speech and idle segments
+func buildSpeedSegments(idle []TimeSpan, duration, speechSpeed, idleSpeed float64) []speedSegment {
+	var segments []speedSegment
+	cursor := 0.0
+
+	for _, span := range idle {
+		start := span.Start
+		end := span.End
+		if end > duration {
+			end = duration
+		}
+		if start >= end {
+			continue
+		}
+		if start > cursor {
+			segments = append(segments, speedSegment{Start: cursor, End: start, Speed: speechSpeed})
+		}
+		segments = append(segments, speedSegment{Start: start, End: end, Speed: idleSpeed})
+		cursor = end
+	}
+
+	if cursor < duration {
+		segments = append(segments, speedSegment{Start: cursor, End: duration, Speed: speechSpeed})
+	}
+
+	return segments
+}
+
+// buildSmartSpeedFilter builds a filter_complex that retimes each segment and concatenates them
+func buildSmartSpeedFilter(segments []speedSegment, hasAudio bool) string {
+	var parts []string
+	var concatInputs strings.Builder
+
+	for i, seg := range segments {
+		parts = append(parts, fmt.Sprintf("[0:v]trim=start=%.3f:end=%.3f,setpts=(PTS-STARTPTS)/%.4f[v%d]",
+			seg.Start, seg.End, seg.Speed, i))
+		concatInputs.WriteString(fmt.Sprintf("[v%d]", i))
+
+		if hasAudio {
+			parts = append(parts, fmt.Sprintf("[0:a]atrim=start=%.3f:end=%.3f,asetpts=PTS-STARTPTS,%s[a%d]",
+				seg.Start, seg.End, ffmpeg.AtempoChain(seg.Speed), i))
+			concatInputs.WriteString(fmt.Sprintf("[a%d]", i))
+		}
+	}
+
+	if hasAudio {
+		parts = append(parts, fmt.Sprintf("%sconcat=n=%d:v=1:a=1[outv][outa]", concatInputs.String(), len(segments)))
+	} else {
+		parts = append(parts, fmt.Sprintf("%sconcat=n=%d:v=1:a=0[outv]", concatInputs.String(), len(segments)))
+	}
+
+	return strings.Join(parts, ";")
+}
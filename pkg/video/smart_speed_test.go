@@ -0,0 +1,50 @@
+package video
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseDetectRanges(t *testing.T) {
+	output := `[silencedetect @ 0x1] silence_start: 1.5
+[silencedetect @ 0x1] silence_end: 3.25 | silence_duration: 1.75
+[silencedetect @ 0x1] silence_start: 8
+`
+	spans := parseDetectRanges(output, silenceStartPattern, silenceEndPattern, 10)
+
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Start != 1.5 || spans[0].End != 3.25 {
+		t.Errorf("Unexpected first span: %+v", spans[0])
+	}
+	// Trailing silence is closed at the total duration
+	if spans[1].Start != 8 || spans[1].End != 10 {
+		t.Errorf("Unexpected trailing span: %+v", spans[1])
+	}
+}
+
+func TestBuildSpeedSegments(t *testing.T) {
+	idle := mergeTimeSpans([]TimeSpan{
+		{Start: 6, End: 8},
+		{Start: 2, End: 4},
+		{Start: 3, End: 5},
+	})
+	if len(idle) != 2 {
+		t.Fatalf("Expected overlapping spans to merge into 2, got %d", len(idle))
+	}
+
+	segments := buildSpeedSegments(idle, 10, 1.0, 4.0)
+	if len(segments) != 5 {
+		t.Fatalf("Expected 5 segments, got %d", len(segments))
+	}
+
+	total := 0.0
+	for _, seg := range segments {
+		total += (seg.End - seg.Start) / seg.Speed
+	}
+	// 5s of speech at 1x plus 5s of silence at 4x
+	if math.Abs(total-6.25) > 0.001 {
+		t.Errorf("Expected output duration 6.25, got %f", total)
+	}
+}
@@ -0,0 +1,169 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SpriteSheetOptions contains options for generating hover-preview sprites
+type SpriteSheetOptions struct {
+	Input     string
+	Output    string  // Sprite image path (jpg, png); numbered when more than one sheet is needed
+	VTTOutput string  // WebVTT thumbnails track path (default: Output with .vtt extension)
+	Interval  float64 // Seconds between thumbnails (default: 5)
+	TileWidth int     // Width of each thumbnail in pixels (default: 160)
+	Columns   int     // Thumbnails per row (default: 10)
+	MaxRows   int     // Rows per sheet before starting a new sheet (default: 10)
+	URLPrefix string  // Prefix for sprite URLs in the VTT file (default: relative file name)
+}
+
+// SpriteSheetResult describes generated sprite sheets and their WebVTT track
+type SpriteSheetResult struct {
+	Sheets     []string `json:"sheets"`
+	VTTFile    string   `json:"vttFile"`
+	FrameCount int      `json:"frameCount"`
+	TileWidth  int      `json:"tileWidth"`
+	TileHeight int      `json:"tileHeight"`
+}
+
+// GenerateSpriteSheet extracts small frames at a fixed interval, tiles them
+// into sprite sheets and writes a WebVTT thumbnails track that maps each time
+// range to its tile (using #xywh media fragments) for player hover previews.
+func (o *Operations) GenerateSpriteSheet(ctx context.Context, opts SpriteSheetOptions) (*SpriteSheetResult, error) {
+	if err := validateOutputPath(opts.Output, opts.Input); err != nil {
+		return nil, err
+	}
+
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = 5
+	}
+	tileWidth := opts.TileWidth
+	if tileWidth <= 0 {
+		tileWidth = 160
+	}
+	columns := opts.Columns
+	if columns <= 0 {
+		columns = 10
+	}
+	maxRows := opts.MaxRows
+	if maxRows <= 0 {
+		maxRows = 10
+	}
+	vttOutput := opts.VTTOutput
+	if vttOutput == "" {
+		vttOutput = strings.TrimSuffix(opts.Output, filepath.Ext(opts.Output)) + ".vtt"
+	}
+
+	info, err := o.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+	if info.Duration <= 0 || info.Width <= 0 || info.Height <= 0 {
+		return nil, fmt.Errorf("could not determine video duration or dimensions")
+	}
+
+	// Even tile height that keeps the source aspect ratio
+	tileHeight := int(math.Round(float64(tileWidth)*float64(info.Height)/float64(info.Width)/2)) * 2
+	if tileHeight < 2 {
+		tileHeight = 2
+	}
+
+	frameCount := int(math.Ceil(info.Duration / interval))
+	rows := int(math.Ceil(float64(frameCount) / float64(columns)))
+	if rows > maxRows {
+		rows = maxRows
+	}
+	perSheet := columns * rows
+	sheetCount := int(math.Ceil(float64(frameCount) / float64(perSheet)))
+
+	// Multiple sheets are written with a numbered pattern
+	pattern := opts.Output
+	if sheetCount > 1 {
+		ext := filepath.Ext(opts.Output)
+		pattern = strings.TrimSuffix(opts.Output, ext) + "_%03d" + ext
+	}
+
+	filter := fmt.Sprintf("fps=1/%.6f,scale=%d:%d,tile=%dx%d", interval, tileWidth, tileHeight, columns, rows)
+	args := []string{
+		"-i", opts.Input,
+		"-vf", filter,
+		"-frames:v", fmt.Sprintf("%d", sheetCount),
+	}
+	if sheetCount > 1 {
+		args = append(args, "-start_number", "1")
+	} else {
+		args = append(args, "-update", "1")
+	}
+	args = append(args, "-y", pattern)
+
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, err
+	}
+
+	sheets := make([]string, sheetCount)
+	for i := range sheets {
+		if sheetCount > 1 {
+			sheets[i] = fmt.Sprintf(pattern, i+1)
+		} else {
+			sheets[i] = opts.Output
+		}
+	}
+
+	urls := make([]string, sheetCount)
+	for i, sheet := range sheets {
+		urls[i] = opts.URLPrefix + filepath.Base(sheet)
+	}
+
+	vtt := BuildThumbnailVTT(urls, frameCount, columns, rows, tileWidth, tileHeight, interval, info.Duration)
+	if err := os.WriteFile(vttOutput, []byte(vtt), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write VTT file: %w", err)
+	}
+
+	return &SpriteSheetResult{
+		Sheets:     sheets,
+		VTTFile:    vttOutput,
+		FrameCount: frameCount,
+		TileWidth:  tileWidth,
+		TileHeight: tileHeight,
+	}, nil
+}
+
+// BuildThumbnailVTT builds a WebVTT thumbnails track that points each cue at
+// a tile within a sprite sheet using #xywh media fragments
+func BuildThumbnailVTT(sheetURLs []string, frameCount, columns, rows, tileWidth, tileHeight int, interval, duration float64) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+
+	perSheet := columns * rows
+	for i := 0; i < frameCount; i++ {
+		sheet := i / perSheet
+		if sheet >= len(sheetURLs) {
+			break
+		}
+		index := i % perSheet
+		x := (index % columns) * tileWidth
+		y := (index / columns) * tileHeight
+
+		start := float64(i) * interval
+		end := start + interval
+		if end > duration {
+			end = duration
+		}
+
+		b.WriteString(fmt.Sprintf("\n%s --> %s\n%s#xywh=%d,%d,%d,%d\n",
+			formatVTTTime(start), formatVTTTime(end), sheetURLs[sheet], x, y, tileWidth, tileHeight))
+	}
+
+	return b.String()
+}
+
+// formatVTTTime formats seconds as a WebVTT timestamp (HH:MM:SS.mmm)
+func formatVTTTime(seconds float64) string {
+	ms := int(math.Round(seconds * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, (ms/60000)%60, (ms/1000)%60, ms%1000)
+}
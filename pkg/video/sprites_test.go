@@ -0,0 +1,33 @@
+package video
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildThumbnailVTT(t *testing.T) {
+	// 5 frames, 2x2 grid -> second sheet holds the last frame
+	vtt := BuildThumbnailVTT([]string{"s_001.jpg", "s_002.jpg"}, 5, 2, 2, 160, 90, 5, 23)
+
+	if !strings.HasPrefix(vtt, "WEBVTT\n") {
+		t.Fatal("Expected WEBVTT header")
+	}
+
+	expected := []string{
+		"00:00:00.000 --> 00:00:05.000\ns_001.jpg#xywh=0,0,160,90",
+		"00:00:05.000 --> 00:00:10.000\ns_001.jpg#xywh=160,0,160,90",
+		"00:00:15.000 --> 00:00:20.000\ns_001.jpg#xywh=160,90,160,90",
+		"00:00:20.000 --> 00:00:23.000\ns_002.jpg#xywh=0,0,160,90",
+	}
+	for _, cue := range expected {
+		if !strings.Contains(vtt, cue) {
+			t.Errorf("Missing cue %q in:\n%s", cue, vtt)
+		}
+	}
+}
+
+func TestFormatVTTTime(t *testing.T) {
+	if got := formatVTTTime(3725.5); got != "01:02:05.500" {
+		t.Errorf("Expected 01:02:05.500, got %s", got)
+	}
+}
@@ -0,0 +1,123 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+)
+
+// stillFormats are the image formats export_still writes, by extension
+var stillFormats = map[string]string{
+	"jpg":  "mjpeg",
+	"jpeg": "mjpeg",
+	"png":  "png",
+	"webp": "libwebp",
+	"tiff": "tiff",
+}
+
+// StillOptions contains options for exporting a still frame
+type StillOptions struct {
+	Input     string
+	Output    string
+	Timestamp float64 // Time of the frame in seconds
+	Format    string  // jpg, png, webp or tiff (default: the output's extension)
+	Quality   int     // 1-100 for jpg and webp (default: 92); png and tiff are lossless
+	Width     int     // Output width in pixels, height keeps aspect ratio (0 = source width)
+	Filter    string  // Filter chain to apply first, such as the one used on the video
+}
+
+// ExportStill writes the frame at an exact timestamp as an image, styled
+// with the same filters as the video if given. Source timestamps are kept,
+// so filters that change over time look as they do at that point.
+func (o *Operations) ExportStill(ctx context.Context, opts StillOptions) error {
+	if err := validateOutputPath(opts.Output, opts.Input); err != nil {
+		return err
+	}
+	if opts.Filter != "" {
+		dir, err := filepath.Abs(filepath.Dir(opts.Output))
+		if err != nil {
+			return err
+		}
+		if err := ffmpeg.ValidateFilterGraph(opts.Filter, dir); err != nil {
+			return fmt.Errorf("invalid filter: %w", err)
+		}
+	}
+	info, err := o.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return fmt.Errorf("failed to get video info: %w", err)
+	}
+	if info.Duration > 0 && opts.Timestamp >= info.Duration {
+		return fmt.Errorf("timestamp %.3fs is past the end of the video (%.3fs)", opts.Timestamp, info.Duration)
+	}
+
+	args, err := buildStillArgs(opts)
+	if err != nil {
+		return err
+	}
+	return o.ffmpeg.Execute(ctx, args...)
+}
+
+// buildStillArgs returns the FFmpeg arguments for a still frame
+func buildStillArgs(opts StillOptions) ([]string, error) {
+	if opts.Timestamp < 0 {
+		return nil, fmt.Errorf("timestamp must be non-negative, got: %.3f", opts.Timestamp)
+	}
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(opts.Output), "."))
+	format := strings.ToLower(opts.Format)
+	if format == "" {
+		format = ext
+	}
+	codec, ok := stillFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported still format %q (use jpg, png, webp or tiff)", format)
+	}
+	if ext != format && !(codec == "mjpeg" && stillFormats[ext] == "mjpeg") {
+		return nil, fmt.Errorf("output extension .%s doesn't match format %s", ext, format)
+	}
+	quality := opts.Quality
+	if quality == 0 {
+		quality = 92
+	}
+	if quality < 1 || quality > 100 {
+		return nil, fmt.Errorf("quality must be between 1 and 100")
+	}
+
+	var filters []string
+	if opts.Filter != "" {
+		filters = append(filters, opts.Filter)
+	}
+	if opts.Width > 0 {
+		filters = append(filters, fmt.Sprintf("scale=%d:-2:flags=lanczos", opts.Width))
+	}
+
+	// Seeking before the input lands on the exact frame (FFmpeg decodes
+	// from the previous keyframe), and -copyts keeps its real timestamp
+	args := []string{
+		"-ss", fmt.Sprintf("%.3f", opts.Timestamp),
+		"-i", opts.Input,
+		"-copyts",
+		"-map", "0:v:0",
+	}
+	switch codec {
+	case "mjpeg":
+		// Full range 4:4:4 avoids the soft chroma of video-range 4:2:0
+		filters = append(filters, "format=yuvj444p")
+		args = append(args, "-c:v", "mjpeg", "-q:v", fmt.Sprintf("%d", jpegQScale(quality)))
+	case "libwebp":
+		args = append(args, "-c:v", "libwebp", "-quality", fmt.Sprintf("%d", quality))
+	default:
+		args = append(args, "-c:v", codec)
+	}
+	if len(filters) > 0 {
+		args = append(args, "-vf", strings.Join(filters, ","))
+	}
+	return append(args, "-frames:v", "1", "-update", "1", "-y", opts.Output), nil
+}
+
+// jpegQScale maps a 1-100 quality to MJPEG's qscale, 2 (best) to 31
+func jpegQScale(quality int) int {
+	return 2 + (100-quality)*29/99
+}
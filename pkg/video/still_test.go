@@ -0,0 +1,55 @@
+package video
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildStillArgs(t *testing.T) {
+	args, err := buildStillArgs(StillOptions{
+		Input:     "talk.mp4",
+		Output:    "poster.jpg",
+		Timestamp: 12.345,
+		Width:     1280,
+		Filter:    "eq=contrast=1.1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	for _, want := range []string{
+		"-ss 12.345 -i talk.mp4 -copyts",
+		"-c:v mjpeg -q:v 4",
+		"-vf eq=contrast=1.1,scale=1280:-2:flags=lanczos,format=yuvj444p",
+		"-frames:v 1 -update 1 -y poster.jpg",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Arguments %q missing %q", joined, want)
+		}
+	}
+
+	args, err = buildStillArgs(StillOptions{Input: "talk.mp4", Output: "poster.webp", Quality: 80})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if joined := strings.Join(args, " "); !strings.Contains(joined, "-c:v libwebp -quality 80") || strings.Contains(joined, "-vf") {
+		t.Errorf("WebP arguments %q", joined)
+	}
+
+	for _, opts := range []StillOptions{
+		{Output: "poster.gif"},
+		{Output: "poster.png", Format: "jpg"},
+		{Output: "poster.jpg", Timestamp: -1},
+		{Output: "poster.jpg", Quality: 101},
+	} {
+		if _, err := buildStillArgs(opts); err == nil {
+			t.Errorf("Expected an error for %+v", opts)
+		}
+	}
+	if _, err := buildStillArgs(StillOptions{Output: "poster.jpeg", Format: "jpg"}); err != nil {
+		t.Errorf("Unexpected error for .jpeg with format jpg: %v", err)
+	}
+	if jpegQScale(100) != 2 || jpegQScale(1) != 31 {
+		t.Errorf("jpegQScale(100) = %d, jpegQScale(1) = %d", jpegQScale(100), jpegQScale(1))
+	}
+}
@@ -0,0 +1,244 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SubtitleTrack describes one subtitle file to attach as a soft track
+type SubtitleTrack struct {
+	Path     string // .srt, .vtt or .ass file
+	Language string // ISO 639 language code, e.g. "eng" or "es"
+	Title    string // Track name shown by players
+	Default  bool   // Shown automatically by players
+	Forced   bool   // Forced subtitles (e.g. translations of foreign dialogue only)
+}
+
+// AttachSubtitlesOptions contains options for embedding toggleable subtitle tracks
+type AttachSubtitlesOptions struct {
+	Input        string
+	Output       string // .mp4/.m4v/.mov use mov_text, .mkv keeps SRT/ASS, .webm uses WebVTT
+	Tracks       []SubtitleTrack
+	KeepExisting bool // Keep subtitle tracks already in the input
+}
+
+// SubtitleStreamInfo describes a subtitle stream found in a media file
+type SubtitleStreamInfo struct {
+	Track    int    `json:"track"` // Position among subtitle streams (0-based)
+	Codec    string `json:"codec"`
+	Language string `json:"language,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Default  bool   `json:"default"`
+	Forced   bool   `json:"forced"`
+}
+
+// bitmapSubtitleCodecs are image-based formats that cannot be converted to text
+var bitmapSubtitleCodecs = map[string]bool{
+	"hdmv_pgs_subtitle": true,
+	"dvd_subtitle":      true,
+	"dvb_subtitle":      true,
+	"xsub":              true,
+}
+
+// AttachSubtitles embeds subtitle files as soft tracks that viewers can turn
+// on and off. Video and audio are copied without re-encoding.
+func (o *Operations) AttachSubtitles(ctx context.Context, opts AttachSubtitlesOptions) error {
+	if len(opts.Tracks) == 0 {
+		return fmt.Errorf("at least one subtitle track is required")
+	}
+
+	inputs := []string{opts.Input}
+	defaults := 0
+	for _, track := range opts.Tracks {
+		if _, err := subtitleCodecFor(opts.Output, track.Path); err != nil {
+			return err
+		}
+		inputs = append(inputs, track.Path)
+		if track.Default {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		return fmt.Errorf("only one subtitle track can be the default")
+	}
+	if err := validateOutputPath(opts.Output, inputs...); err != nil {
+		return err
+	}
+
+	existing := 0
+	if opts.KeepExisting {
+		var err error
+		if existing, err = o.countStreams(ctx, opts.Input, "s"); err != nil {
+			return err
+		}
+	}
+
+	return o.ffmpeg.Execute(ctx, buildAttachSubtitlesArgs(opts, existing)...)
+}
+
+// ListSubtitleTracks returns the subtitle streams in a media file
+func (o *Operations) ListSubtitleTracks(ctx context.Context, input string) ([]SubtitleStreamInfo, error) {
+	output, err := o.ffmpeg.Probe(ctx,
+		"-v", "error",
+		"-select_streams", "s",
+		"-show_entries", "stream=codec_name:stream_tags=language,title:stream_disposition=default,forced",
+		"-of", "json",
+		input,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe subtitle streams: %w", err)
+	}
+	return parseSubtitleStreams(output)
+}
+
+// ExtractSubtitles writes one embedded text subtitle track to a file. The
+// output format follows the extension: .srt, .vtt or .ass.
+func (o *Operations) ExtractSubtitles(ctx context.Context, input, output string, track int) error {
+	if err := validateOutputPath(output, input); err != nil {
+		return err
+	}
+
+	format, err := subtitleFormatForExt(output)
+	if err != nil {
+		return err
+	}
+
+	tracks, err := o.ListSubtitleTracks(ctx, input)
+	if err != nil {
+		return err
+	}
+	if len(tracks) == 0 {
+		return fmt.Errorf("no subtitle tracks found in %s", input)
+	}
+	if track < 0 || track >= len(tracks) {
+		return fmt.Errorf("subtitle track %d not found (file has %d track(s), numbered from 0)", track, len(tracks))
+	}
+	if bitmapSubtitleCodecs[tracks[track].Codec] {
+		return fmt.Errorf("subtitle track %d is image-based (%s) and cannot be converted to text", track, tracks[track].Codec)
+	}
+
+	return o.ffmpeg.Execute(ctx,
+		"-i", input,
+		"-map", fmt.Sprintf("0:s:%d", track),
+		"-c:s", format,
+		"-y", output,
+	)
+}
+
+func buildAttachSubtitlesArgs(opts AttachSubtitlesOptions, existingTracks int) []string {
+	args := []string{"-i", opts.Input}
+	for _, track := range opts.Tracks {
+		args = append(args, "-i", track.Path)
+	}
+
+	args = append(args, "-map", "0:v?", "-map", "0:a?")
+	if existingTracks > 0 {
+		args = append(args, "-map", "0:s")
+	}
+	for i := range opts.Tracks {
+		args = append(args, "-map", fmt.Sprintf("%d:0", i+1))
+	}
+	args = append(args, "-c", "copy")
+
+	// MP4 and WebM only accept one text codec, so existing tracks are converted;
+	// MKV holds anything and keeps them as they are
+	if outputCodec, _ := subtitleCodecFor(opts.Output, ".srt"); outputCodec != "srt" {
+		for i := 0; i < existingTracks; i++ {
+			args = append(args, fmt.Sprintf("-c:s:%d", i), outputCodec)
+		}
+	}
+
+	hasDefault := false
+	for _, track := range opts.Tracks {
+		hasDefault = hasDefault || track.Default
+	}
+	if hasDefault {
+		for i := 0; i < existingTracks; i++ {
+			args = append(args, fmt.Sprintf("-disposition:s:%d", i), "0")
+		}
+	}
+
+	for i, track := range opts.Tracks {
+		index := strconv.Itoa(existingTracks + i)
+		codec, _ := subtitleCodecFor(opts.Output, track.Path)
+		args = append(args, "-c:s:"+index, codec)
+		if track.Language != "" {
+			args = append(args, "-metadata:s:s:"+index, "language="+normalizeLanguageCode(track.Language))
+		}
+		if track.Title != "" {
+			args = append(args, "-metadata:s:s:"+index, "title="+track.Title)
+		}
+		args = append(args, "-disposition:s:"+index, trackDisposition(track.Default, track.Forced))
+	}
+
+	return append(args, "-y", opts.Output)
+}
+
+// subtitleCodecFor picks the subtitle codec for a subtitle file in the output container
+func subtitleCodecFor(output, subtitlePath string) (string, error) {
+	subExt := strings.ToLower(filepath.Ext(subtitlePath))
+	switch subExt {
+	case ".srt", ".vtt", ".ass", ".ssa":
+	default:
+		return "", fmt.Errorf("unsupported subtitle format: %s. Supported: .srt, .vtt, .ass", subExt)
+	}
+
+	switch strings.ToLower(filepath.Ext(output)) {
+	case ".mp4", ".m4v", ".mov":
+		return "mov_text", nil
+	case ".webm":
+		return "webvtt", nil
+	case ".mkv":
+		if subExt == ".ass" || subExt == ".ssa" {
+			return "ass", nil
+		}
+		return "srt", nil
+	default:
+		return "", fmt.Errorf("output must be .mp4, .m4v, .mov, .mkv or .webm to hold subtitle tracks")
+	}
+}
+
+// subtitleFormatForExt returns the FFmpeg subtitle encoder for an output file
+func subtitleFormatForExt(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".srt":
+		return "srt", nil
+	case ".vtt":
+		return "webvtt", nil
+	case ".ass", ".ssa":
+		return "ass", nil
+	default:
+		return "", fmt.Errorf("unsupported subtitle output: %s. Supported: .srt, .vtt, .ass", filepath.Ext(path))
+	}
+}
+
+// parseSubtitleStreams parses ffprobe JSON for subtitle streams
+func parseSubtitleStreams(output string) ([]SubtitleStreamInfo, error) {
+	var probe struct {
+		Streams []struct {
+			CodecName   string            `json:"codec_name"`
+			Tags        map[string]string `json:"tags"`
+			Disposition map[string]int    `json:"disposition"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal([]byte(output), &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	tracks := make([]SubtitleStreamInfo, 0, len(probe.Streams))
+	for i, stream := range probe.Streams {
+		tracks = append(tracks, SubtitleStreamInfo{
+			Track:    i,
+			Codec:    stream.CodecName,
+			Language: stream.Tags["language"],
+			Title:    stream.Tags["title"],
+			Default:  stream.Disposition["default"] == 1,
+			Forced:   stream.Disposition["forced"] == 1,
+		})
+	}
+	return tracks, nil
+}
@@ -0,0 +1,66 @@
+package video
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSubtitleCodecFor(t *testing.T) {
+	tests := []struct {
+		output, subtitle, want string
+	}{
+		{"out.mp4", "captions.srt", "mov_text"},
+		{"out.mov", "captions.vtt", "mov_text"},
+		{"out.webm", "captions.srt", "webvtt"},
+		{"out.mkv", "captions.srt", "srt"},
+		{"out.mkv", "styled.ass", "ass"},
+	}
+	for _, tt := range tests {
+		got, err := subtitleCodecFor(tt.output, tt.subtitle)
+		if err != nil || got != tt.want {
+			t.Errorf("subtitleCodecFor(%q, %q) = %q, %v; want %q", tt.output, tt.subtitle, got, err, tt.want)
+		}
+	}
+
+	if _, err := subtitleCodecFor("out.avi", "captions.srt"); err == nil {
+		t.Error("Expected error for container without subtitle support")
+	}
+	if _, err := subtitleCodecFor("out.mp4", "captions.txt"); err == nil {
+		t.Error("Expected error for unsupported subtitle file")
+	}
+}
+
+func TestBuildAttachSubtitlesArgs(t *testing.T) {
+	args := strings.Join(buildAttachSubtitlesArgs(AttachSubtitlesOptions{
+		Input:  "in.mp4",
+		Output: "out.mp4",
+		Tracks: []SubtitleTrack{
+			{Path: "en.srt", Language: "en", Default: true},
+			{Path: "es.vtt", Language: "spa", Title: "Español"},
+		},
+	}, 0), " ")
+
+	for _, want := range []string{
+		"-map 0:v? -map 0:a? -map 1:0 -map 2:0 -c copy",
+		"-c:s:0 mov_text -metadata:s:s:0 language=eng -disposition:s:0 default",
+		"-c:s:1 mov_text -metadata:s:s:1 language=spa -metadata:s:s:1 title=Español -disposition:s:1 0",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("Expected %q in args: %s", want, args)
+		}
+	}
+}
+
+func TestParseSubtitleStreams(t *testing.T) {
+	output := `{"streams": [
+		{"codec_name": "mov_text", "tags": {"language": "eng"}, "disposition": {"default": 1, "forced": 0}},
+		{"codec_name": "hdmv_pgs_subtitle", "disposition": {"default": 0, "forced": 1}}
+	]}`
+	tracks, err := parseSubtitleStreams(output)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tracks) != 2 || tracks[0].Language != "eng" || !tracks[0].Default || !tracks[1].Forced || tracks[1].Track != 1 {
+		t.Errorf("Unexpected tracks: %+v", tracks)
+	}
+}
@@ -0,0 +1,173 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ThumbnailOptions contains options for generating a single thumbnail
+type ThumbnailOptions struct {
+	Input        string
+	Output       string   // Image path (jpg, png, webp)
+	Timestamp    *float64 // Explicit timestamp in seconds; if nil the best frame is picked
+	SampleFrames int      // Frames per batch analyzed by the thumbnail filter (default: 100)
+	Width        int      // Output width in pixels (0 = source width)
+}
+
+// GenerateThumbnail extracts a representative frame from a video.
+// With an explicit timestamp the frame at that time is used, otherwise
+// FFmpeg's thumbnail filter picks the most representative frame.
+func (o *Operations) GenerateThumbnail(ctx context.Context, opts ThumbnailOptions) error {
+	if err := validateOutputPath(opts.Output, opts.Input); err != nil {
+		return err
+	}
+
+	filters := []string{}
+	args := []string{}
+
+	if opts.Timestamp != nil {
+		if *opts.Timestamp < 0 {
+			return fmt.Errorf("timestamp must be non-negative, got: %.2f", *opts.Timestamp)
+		}
+		// Seek before input for fast, accurate single-frame extraction
+		args = append(args, "-ss", fmt.Sprintf("%.3f", *opts.Timestamp))
+	} else {
+		sampleFrames := opts.SampleFrames
+		if sampleFrames <= 0 {
+			sampleFrames = 100
+		}
+		filters = append(filters, fmt.Sprintf("thumbnail=%d", sampleFrames))
+	}
+
+	if opts.Width > 0 {
+		filters = append(filters, fmt.Sprintf("scale=%d:-2", opts.Width))
+	}
+
+	args = append(args, "-i", opts.Input)
+	if len(filters) > 0 {
+		args = append(args, "-vf", strings.Join(filters, ","))
+	}
+	args = append(args,
+		"-frames:v", "1",
+		"-y",
+		opts.Output,
+	)
+
+	return o.ffmpeg.Execute(ctx, args...)
+}
+
+// ContactSheetOptions contains options for generating a contact sheet
+type ContactSheetOptions struct {
+	Input         string
+	Output        string // Image path (jpg, png)
+	Columns       int    // Tiles per row (default: 4)
+	Rows          int    // Tiles per column (default: 4)
+	TileWidth     int    // Width of each tile in pixels (default: 320)
+	ShowTimecodes bool   // Draw the frame timestamp on each tile
+	FontColor     string // Timecode color (default: white)
+	Padding       int    // Pixels between tiles (default: 4)
+	Background    string // Sheet background color (default: black)
+}
+
+// ContactSheetResult describes a generated contact sheet
+type ContactSheetResult struct {
+	Output     string    `json:"output"`
+	FrameCount int       `json:"frameCount"`
+	Interval   float64   `json:"interval"`
+	Timestamps []float64 `json:"timestamps"`
+}
+
+// GenerateContactSheet tiles evenly spaced frames from a video into a single
+// image for quick visual review
+func (o *Operations) GenerateContactSheet(ctx context.Context, opts ContactSheetOptions) (*ContactSheetResult, error) {
+	if err := validateOutputPath(opts.Output, opts.Input); err != nil {
+		return nil, err
+	}
+
+	columns := opts.Columns
+	if columns <= 0 {
+		columns = 4
+	}
+	rows := opts.Rows
+	if rows <= 0 {
+		rows = 4
+	}
+	tileWidth := opts.TileWidth
+	if tileWidth <= 0 {
+		tileWidth = 320
+	}
+	padding := opts.Padding
+	if padding <= 0 {
+		padding = 4
+	}
+	background := opts.Background
+	if background == "" {
+		background = "black"
+	}
+
+	info, err := o.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+	if info.Duration <= 0 {
+		return nil, fmt.Errorf("could not determine video duration")
+	}
+
+	frameCount := columns * rows
+	interval := info.Duration / float64(frameCount)
+
+	// Sample from the middle of each interval so the first tile isn't a black lead-in frame
+	offset := interval / 2
+	timestamps := make([]float64, frameCount)
+	for i := range timestamps {
+		timestamps[i] = math.Round((offset+float64(i)*interval)*1000) / 1000
+	}
+
+	filters := []string{
+		fmt.Sprintf("fps=1/%.6f", interval),
+		fmt.Sprintf("scale=%d:-2", tileWidth),
+	}
+	if opts.ShowTimecodes {
+		fontColor := opts.FontColor
+		if fontColor == "" {
+			fontColor = "white"
+		}
+		// pts is relative to the seek offset, so add it back for source timecodes
+		filters = append(filters, fmt.Sprintf(
+			"drawtext=text='%%{pts\\:hms\\:%.3f}':x=w-text_w-6:y=h-text_h-6:fontsize=%d:fontcolor=%s:box=1:boxcolor=black@0.5:boxborderw=3",
+			offset, tileFontSize(tileWidth), fontColor))
+	}
+	filters = append(filters, fmt.Sprintf("tile=%dx%d:padding=%d:margin=%d:color=%s",
+		columns, rows, padding, padding, background))
+
+	args := []string{
+		"-ss", fmt.Sprintf("%.3f", offset),
+		"-i", opts.Input,
+		"-vf", strings.Join(filters, ","),
+		"-frames:v", "1",
+		"-y",
+		opts.Output,
+	}
+
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, err
+	}
+
+	return &ContactSheetResult{
+		Output:     opts.Output,
+		FrameCount: frameCount,
+		Interval:   interval,
+		Timestamps: timestamps,
+	}, nil
+}
+
+// tileFontSize scales timecode text with the tile width
+func tileFontSize(tileWidth int) int {
+	size := tileWidth / 14
+	if size < 10 {
+		size = 10
+	}
+	return size
+}
@@ -0,0 +1,76 @@
+package video
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateThumbnail(t *testing.T) {
+	ops, testDir := setupTest(t)
+	defer cleanup(testDir)
+
+	testVideo := filepath.Join(testDir, "test.mp4")
+	createTestVideo(t, testVideo)
+
+	ctx := context.Background()
+
+	bestFrame := filepath.Join(testDir, "best.jpg")
+	if err := ops.GenerateThumbnail(ctx, ThumbnailOptions{
+		Input:  testVideo,
+		Output: bestFrame,
+	}); err != nil {
+		t.Fatalf("GenerateThumbnail (best frame) failed: %v", err)
+	}
+	if _, err := os.Stat(bestFrame); os.IsNotExist(err) {
+		t.Error("Best-frame thumbnail was not created")
+	}
+
+	timestamp := 2.5
+	atTime := filepath.Join(testDir, "at_time.png")
+	if err := ops.GenerateThumbnail(ctx, ThumbnailOptions{
+		Input:     testVideo,
+		Output:    atTime,
+		Timestamp: &timestamp,
+		Width:     320,
+	}); err != nil {
+		t.Fatalf("GenerateThumbnail (timestamp) failed: %v", err)
+	}
+	if _, err := os.Stat(atTime); os.IsNotExist(err) {
+		t.Error("Timestamp thumbnail was not created")
+	}
+}
+
+func TestGenerateContactSheet(t *testing.T) {
+	ops, testDir := setupTest(t)
+	defer cleanup(testDir)
+
+	testVideo := filepath.Join(testDir, "test.mp4")
+	createTestVideo(t, testVideo)
+
+	outputPath := filepath.Join(testDir, "sheet.jpg")
+	ctx := context.Background()
+
+	result, err := ops.GenerateContactSheet(ctx, ContactSheetOptions{
+		Input:         testVideo,
+		Output:        outputPath,
+		Columns:       3,
+		Rows:          2,
+		TileWidth:     160,
+		ShowTimecodes: true,
+	})
+	if err != nil {
+		t.Fatalf("GenerateContactSheet failed: %v", err)
+	}
+
+	if result.FrameCount != 6 {
+		t.Errorf("Expected 6 frames, got %d", result.FrameCount)
+	}
+	if len(result.Timestamps) != 6 {
+		t.Errorf("Expected 6 timestamps, got %d", len(result.Timestamps))
+	}
+	if _, err := os.Stat(outputPath); os.IsNotExist(err) {
+		t.Error("Contact sheet was not created")
+	}
+}
@@ -0,0 +1,141 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ToneMapAlgorithms lists the tone curves ToneMap accepts
+var ToneMapAlgorithms = []string{"hable", "mobius", "reinhard", "clip"}
+
+// defaultHDRPeak is the assumed source peak in nits when the file carries no
+// content light level metadata; phones and most HDR10 masters stay below it
+const defaultHDRPeak = 1000
+
+// ToneMapOptions contains options for converting HDR or wide-gamut video to SDR BT.709
+type ToneMapOptions struct {
+	Input     string
+	Output    string
+	Algorithm string  // hable (default), mobius, reinhard or clip
+	Peak      float64 // Source peak brightness in nits (default: MaxCLL metadata, else 1000)
+	Quality   string  // Quality: high, medium, low (default: high)
+}
+
+// ToneMapResult describes a tone mapping run
+type ToneMapResult struct {
+	Source     ColorInfo
+	ToneMapped bool // False when only the color space was converted (SDR source)
+	Algorithm  string
+	Peak       float64 // Source peak used, in nits
+}
+
+// ToneMap converts HDR10/HLG video to SDR BT.709 with zscale and tonemap,
+// and wide-gamut SDR video to BT.709 primaries. Output is tagged BT.709.
+func (o *Operations) ToneMap(ctx context.Context, opts ToneMapOptions) (*ToneMapResult, error) {
+	if err := validateOutputPath(opts.Output, opts.Input); err != nil {
+		return nil, err
+	}
+	algorithm := opts.Algorithm
+	if algorithm == "" {
+		algorithm = "hable"
+	}
+	if !slices.Contains(ToneMapAlgorithms, algorithm) {
+		return nil, fmt.Errorf("unknown tone mapping algorithm %q (use %s)", algorithm, strings.Join(ToneMapAlgorithms, ", "))
+	}
+	if opts.Peak < 0 {
+		return nil, fmt.Errorf("peak cannot be negative")
+	}
+	for _, filter := range []string{"zscale", "tonemap"} {
+		if !o.ffmpeg.HasFilter(filter) {
+			return nil, fmt.Errorf("this FFmpeg build has no %s filter (zscale needs FFmpeg built with libzimg)", filter)
+		}
+	}
+
+	color, err := o.ProbeColor(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read color information: %w", err)
+	}
+	result := &ToneMapResult{Source: *color, ToneMapped: color.IsHDR(), Algorithm: algorithm}
+	if !result.ToneMapped && (color.Primaries == "bt709" || color.Primaries == "" || color.Primaries == "unknown") {
+		return nil, fmt.Errorf("input is already SDR BT.709 (or untagged); nothing to convert")
+	}
+	if result.ToneMapped {
+		result.Peak = opts.Peak
+		if result.Peak == 0 && color.MaxCLL > 0 {
+			result.Peak = float64(color.MaxCLL)
+		}
+		if result.Peak == 0 {
+			result.Peak = defaultHDRPeak
+		}
+	}
+
+	quality := opts.Quality
+	if quality == "" {
+		quality = "high"
+	}
+	args := []string{
+		"-i", opts.Input,
+		"-vf", buildToneMapFilter(color, algorithm, result.Peak),
+		"-c:v", "libx264",
+		"-crf", strconv.Itoa(qualityToCRF(quality)),
+		"-preset", "medium",
+		"-color_primaries", "bt709",
+		"-color_trc", "bt709",
+		"-colorspace", "bt709",
+		"-c:a", "copy",
+		"-y", opts.Output,
+	}
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// zscaleNames are the probed color values zscale accepts under the same name
+var zscaleNames = map[string]bool{
+	"bt709": true, "bt2020": true, "smpte170m": true, "bt470bg": true, "smpte432": true,
+	"bt2020nc": true, "bt2020c": true,
+	"smpte2084": true, "arib-std-b67": true, "bt2020-10": true, "bt2020-12": true, "linear": true,
+	"limited": true, "full": true,
+}
+
+// buildToneMapFilter converts to BT.709: HDR sources go to linear light,
+// are tone mapped from peak (in nits) and then encoded with the BT.709
+// transfer; SDR sources only get their primaries and matrix converted.
+// Input properties come from the probe when zscale knows them, so frames
+// without color tags still convert.
+func buildToneMapFilter(color *ColorInfo, algorithm string, peak float64) string {
+	var in []string
+	for _, p := range [][2]string{{"tin", color.Transfer}, {"pin", color.Primaries}, {"min", color.Space}, {"rin", zscaleRange(color.Range)}} {
+		if zscaleNames[p[1]] {
+			in = append(in, p[0]+"="+p[1])
+		}
+	}
+	input := strings.Join(in, ":")
+	if input != "" {
+		input += ":"
+	}
+
+	if !color.IsHDR() {
+		return fmt.Sprintf("zscale=%sp=bt709:t=bt709:m=bt709:r=tv,format=yuv420p", input)
+	}
+	// npl=100 makes 1.0 in linear light SDR reference white, so the
+	// tonemap peak is the source peak relative to 100 nits
+	return fmt.Sprintf("zscale=%st=linear:npl=100,format=gbrpf32le,zscale=p=bt709,"+
+		"tonemap=tonemap=%s:desat=0:peak=%s,zscale=t=bt709:m=bt709:r=tv,format=yuv420p",
+		input, algorithm, strconv.FormatFloat(peak/100, 'f', -1, 64))
+}
+
+// zscaleRange maps an ffprobe color range to zscale's names
+func zscaleRange(r string) string {
+	switch r {
+	case "tv":
+		return "limited"
+	case "pc":
+		return "full"
+	}
+	return ""
+}
@@ -0,0 +1,27 @@
+package video
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildToneMapFilter(t *testing.T) {
+	hlg := &ColorInfo{Primaries: "bt2020", Transfer: "arib-std-b67", Space: "bt2020nc", Range: "tv"}
+	filter := buildToneMapFilter(hlg, "mobius", 1000)
+	for _, want := range []string{
+		"zscale=tin=arib-std-b67:pin=bt2020:min=bt2020nc:rin=limited:t=linear:npl=100",
+		"tonemap=tonemap=mobius:desat=0:peak=10,",
+		"zscale=t=bt709:m=bt709:r=tv,format=yuv420p",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected %q in filter: %s", want, filter)
+		}
+	}
+
+	// Unknown names are left for zscale to read from the frames
+	p3 := &ColorInfo{Primaries: "smpte432", Transfer: "iec61966-2-1", Space: "bt709"}
+	filter = buildToneMapFilter(p3, "hable", 0)
+	if filter != "zscale=pin=smpte432:min=bt709:p=bt709:t=bt709:m=bt709:r=tv,format=yuv420p" {
+		t.Errorf("Unexpected SDR conversion: %s", filter)
+	}
+}
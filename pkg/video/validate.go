@@ -0,0 +1,209 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ValidateOptions contains thresholds for media quality checks
+type ValidateOptions struct {
+	Input               string
+	BlackMinDuration    float64 // Minimum black segment length in seconds (default: 0.5)
+	BlackPixelThreshold float64 // Luminance 0-1 below which a pixel counts as black (default: 0.10)
+	FreezeMinDuration   float64 // Minimum frozen segment length in seconds (default: 2.0)
+	FreezeNoiseDB       float64 // freezedetect noise tolerance in dB (default: -60)
+	MaxDecodeErrors     int     // Decode errors tolerated before failing (default: 0)
+	AllowMissingAudio   bool    // Don't fail videos without an audio track
+}
+
+// ValidationIssue is a single QC problem, with a time range when one applies
+type ValidationIssue struct {
+	Type    string  `json:"type"` // black, freeze, decode_error, missing_audio, missing_video
+	Start   float64 `json:"start,omitempty"`
+	End     float64 `json:"end,omitempty"`
+	Message string  `json:"message"`
+}
+
+// ValidationReport is the pass/fail result of ValidateMedia
+type ValidationReport struct {
+	Input          string            `json:"input"`
+	Passed         bool              `json:"passed"`
+	Duration       float64           `json:"duration"`
+	HasVideo       bool              `json:"hasVideo"`
+	HasAudio       bool              `json:"hasAudio"`
+	BlackSegments  []TimeSpan        `json:"blackSegments,omitempty"`
+	FrozenSegments []TimeSpan        `json:"frozenSegments,omitempty"`
+	DecodeErrors   int               `json:"decodeErrors"`
+	ErrorSamples   []string          `json:"errorSamples,omitempty"`
+	Issues         []ValidationIssue `json:"issues,omitempty"`
+}
+
+const maxErrorSamples = 10
+
+var (
+	blackStartPattern   = regexp.MustCompile(`black_start:\s*(-?[0-9.]+)`)
+	blackEndPattern     = regexp.MustCompile(`black_end:\s*(-?[0-9.]+)`)
+	logLinePattern      = regexp.MustCompile(`^\[([^\]@]+?) @ 0x[0-9a-f]+\]\s*(.*)$`)
+	decodeErrorPattern  = regexp.MustCompile(`(?i)error|corrupt|invalid|concealing|missing|non-existing|illegal|partial file`)
+	repeatedLinePattern = regexp.MustCompile(`Last message repeated (\d+) times`)
+)
+
+// ValidateMedia decodes every video and audio frame once, running blackdetect
+// and freezedetect on the picture and counting decoder errors, and reports
+// whether the file is fit to publish
+func (o *Operations) ValidateMedia(ctx context.Context, opts ValidateOptions) (*ValidationReport, error) {
+	blackMin := opts.BlackMinDuration
+	if blackMin <= 0 {
+		blackMin = 0.5
+	}
+	blackPixel := opts.BlackPixelThreshold
+	if blackPixel <= 0 {
+		blackPixel = 0.10
+	}
+	freezeMin := opts.FreezeMinDuration
+	if freezeMin <= 0 {
+		freezeMin = 2.0
+	}
+	freezeNoise := opts.FreezeNoiseDB
+	if freezeNoise == 0 {
+		freezeNoise = -60
+	}
+
+	info, err := o.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read media: %w", err)
+	}
+
+	report := &ValidationReport{
+		Input:    opts.Input,
+		Duration: info.Duration,
+		HasVideo: info.Width > 0,
+		HasAudio: info.HasAudio,
+	}
+
+	args := []string{
+		"-hide_banner",
+		"-nostats",
+		"-err_detect", "crccheck+bitstream+buffer",
+		"-i", opts.Input,
+		"-map", "0:v:0?",
+		"-map", "0:a?",
+	}
+	if report.HasVideo {
+		args = append(args, "-vf", fmt.Sprintf("blackdetect=d=%.2f:pix_th=%.2f,freezedetect=n=%.1fdB:d=%.2f",
+			blackMin, blackPixel, freezeNoise, freezeMin))
+	}
+	args = append(args, "-f", "null", "-")
+
+	output, runErr := o.ffmpeg.ExecuteWithOutput(ctx, args...)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	report.BlackSegments = parseDetectRanges(output, blackStartPattern, blackEndPattern, info.Duration)
+	report.FrozenSegments = parseDetectRanges(output, freezeStartPattern, freezeEndPattern, info.Duration)
+	report.DecodeErrors, report.ErrorSamples = countDecodeErrors(output)
+
+	if runErr != nil {
+		report.Issues = append(report.Issues, ValidationIssue{
+			Type:    "decode_error",
+			Message: fmt.Sprintf("decoding stopped early: %s", lastLogLine(output)),
+		})
+	}
+	report.Issues = append(report.Issues, collectIssues(report, opts)...)
+	report.Passed = len(report.Issues) == 0
+
+	return report, nil
+}
+
+// collectIssues turns detections into issues according to the options
+func collectIssues(report *ValidationReport, opts ValidateOptions) []ValidationIssue {
+	var issues []ValidationIssue
+
+	if !report.HasVideo {
+		issues = append(issues, ValidationIssue{Type: "missing_video", Message: "no video stream"})
+	}
+	if report.HasVideo && !report.HasAudio && !opts.AllowMissingAudio {
+		issues = append(issues, ValidationIssue{Type: "missing_audio", Message: "no audio stream"})
+	}
+	for _, span := range report.BlackSegments {
+		issues = append(issues, ValidationIssue{
+			Type:    "black",
+			Start:   span.Start,
+			End:     span.End,
+			Message: fmt.Sprintf("black frames for %.2fs", span.Duration),
+		})
+	}
+	for _, span := range report.FrozenSegments {
+		issues = append(issues, ValidationIssue{
+			Type:    "freeze",
+			Start:   span.Start,
+			End:     span.End,
+			Message: fmt.Sprintf("frozen picture for %.2fs", span.Duration),
+		})
+	}
+	if report.DecodeErrors > opts.MaxDecodeErrors {
+		issues = append(issues, ValidationIssue{
+			Type:    "decode_error",
+			Message: fmt.Sprintf("%d decode errors (allowed: %d)", report.DecodeErrors, opts.MaxDecodeErrors),
+		})
+	}
+
+	return issues
+}
+
+// countDecodeErrors counts demuxer and decoder error lines in FFmpeg's log,
+// including repeats FFmpeg collapses into "Last message repeated N times"
+func countDecodeErrors(output string) (int, []string) {
+	count := 0
+	var samples []string
+	lastWasError := false
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if m := repeatedLinePattern.FindStringSubmatch(line); m != nil {
+			if lastWasError {
+				n, _ := strconv.Atoi(m[1])
+				count += n
+			}
+			continue
+		}
+
+		lastWasError = false
+		message := line
+		if m := logLinePattern.FindStringSubmatch(line); m != nil {
+			source := m[1]
+			// Detection filters report through the same log
+			if strings.HasPrefix(source, "Parsed_") || strings.HasSuffix(source, "detect") {
+				continue
+			}
+			message = m[2]
+		} else if !strings.HasPrefix(line, "Error while decoding") {
+			continue
+		}
+		if !decodeErrorPattern.MatchString(message) {
+			continue
+		}
+
+		count++
+		lastWasError = true
+		if len(samples) < maxErrorSamples {
+			samples = append(samples, line)
+		}
+	}
+
+	return count, samples
+}
+
+// lastLogLine returns the final non-empty line of FFmpeg output
+func lastLogLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
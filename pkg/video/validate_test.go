@@ -0,0 +1,45 @@
+package video
+
+import "testing"
+
+func TestCountDecodeErrors(t *testing.T) {
+	output := `Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'in.mp4':
+[h264 @ 0x55d4c8e0a1c0] error while decoding MB 12 34, bytestream -7
+[h264 @ 0x55d4c8e0a1c0] concealing 1200 DC, 1200 AC, 1200 MV errors in P frame
+    Last message repeated 2 times
+[Parsed_blackdetect_0 @ 0x55d4c8f00000] black_start:0 black_end:1.5 black_duration:1.5
+[freezedetect @ 0x55d4c8f00100] lavfi.freezedetect.freeze_start: 4
+[aac @ 0x55d4c8e0b000] Reserved bit set.
+Error while decoding stream #0:1: Invalid data found when processing input
+`
+	count, samples := countDecodeErrors(output)
+	if count != 5 {
+		t.Errorf("Expected 5 decode errors, got %d", count)
+	}
+	if len(samples) != 3 {
+		t.Errorf("Expected 3 error samples, got %d: %v", len(samples), samples)
+	}
+}
+
+func TestCollectIssues(t *testing.T) {
+	report := &ValidationReport{
+		HasVideo:       true,
+		BlackSegments:  []TimeSpan{{Start: 0, End: 1.5, Duration: 1.5}},
+		FrozenSegments: []TimeSpan{{Start: 4, End: 7, Duration: 3}},
+		DecodeErrors:   2,
+	}
+
+	issues := collectIssues(report, ValidateOptions{})
+	types := map[string]int{}
+	for _, issue := range issues {
+		types[issue.Type]++
+	}
+	if types["missing_audio"] != 1 || types["black"] != 1 || types["freeze"] != 1 || types["decode_error"] != 1 {
+		t.Errorf("Unexpected issues: %+v", issues)
+	}
+
+	issues = collectIssues(report, ValidateOptions{AllowMissingAudio: true, MaxDecodeErrors: 5})
+	if len(issues) != 2 {
+		t.Errorf("Expected only black and freeze issues, got %+v", issues)
+	}
+}
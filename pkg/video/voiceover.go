@@ -0,0 +1,245 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+)
+
+// Voiceover fit modes
+const (
+	FitModeAuto  = "auto"  // Narration tempo within MaxTempoChange, then the video for the rest
+	FitModeTempo = "tempo" // Narration tempo only
+	FitModeVideo = "video" // Video only
+)
+
+// Video fit methods
+const (
+	VideoFitPad     = "pad"     // Hold the last frame when narration runs long; leave silence when it runs short
+	VideoFitStretch = "stretch" // Change the section's playback speed
+)
+
+// SyncPoint pairs a moment in the video with the moment in the narration
+// that should play over it
+type SyncPoint struct {
+	Video     float64 `json:"video"`
+	Narration float64 `json:"narration"`
+}
+
+// FitVoiceoverOptions contains options for fitting narration to a video
+type FitVoiceoverOptions struct {
+	Input          string
+	Narration      string
+	Output         string
+	SyncPoints     []SyncPoint // Split both into sections fitted one by one; none fits the whole
+	Mode           string      // auto (default), tempo or video
+	VideoFit       string      // pad (default) or stretch
+	MaxTempoChange float64     // Largest narration tempo change in auto mode (default: 0.1, i.e. ±10%)
+
+	// OriginalVolume mixes the video's own audio, retimed with the
+	// picture, under the narration at this volume; 0 drops it
+	OriginalVolume float64
+}
+
+// FitSection describes how one section was fitted
+type FitSection struct {
+	VideoStart     float64 `json:"videoStart"`
+	VideoEnd       float64 `json:"videoEnd"`
+	NarrationStart float64 `json:"narrationStart"`
+	NarrationEnd   float64 `json:"narrationEnd"`
+	Tempo          float64 `json:"tempo"`      // Narration speed factor
+	VideoSpeed     float64 `json:"videoSpeed"` // Video playback speed factor
+	Hold           float64 `json:"hold"`       // Seconds the last frame is held
+	Silence        float64 `json:"silence"`    // Seconds of silence after the narration
+	OutputStart    float64 `json:"outputStart"`
+	OutputEnd      float64 `json:"outputEnd"`
+}
+
+// FitVoiceoverResult summarizes a voiceover fit
+type FitVoiceoverResult struct {
+	Duration float64      `json:"duration"`
+	Sections []FitSection `json:"sections"`
+	Warnings []string     `json:"warnings,omitempty"`
+}
+
+// FitVoiceover lays narration over a video so each section, and the whole,
+// ends together. Each section is fitted by changing the narration tempo,
+// the video, or both.
+func (o *Operations) FitVoiceover(ctx context.Context, opts FitVoiceoverOptions) (*FitVoiceoverResult, error) {
+	if err := validateOutputPath(opts.Output, opts.Input, opts.Narration); err != nil {
+		return nil, err
+	}
+
+	info, err := o.GetVideoInfo(ctx, opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+	narration, err := o.GetVideoInfo(ctx, opts.Narration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get narration info: %w", err)
+	}
+	if info.Duration <= 0 || narration.Duration <= 0 {
+		return nil, fmt.Errorf("could not determine video and narration durations")
+	}
+
+	sections, warnings, err := planFitSections(opts, info.Duration, narration.Duration)
+	if err != nil {
+		return nil, err
+	}
+	original := opts.OriginalVolume > 0 && info.HasAudio
+	if opts.OriginalVolume > 0 && !info.HasAudio {
+		warnings = append(warnings, "video has no audio to keep under the narration")
+	}
+
+	args := []string{
+		"-i", opts.Input,
+		"-i", opts.Narration,
+		"-filter_complex", buildFitVoiceoverFilter(sections, original, opts.OriginalVolume),
+		"-map", "[outv]",
+		"-map", "[outa]",
+		"-c:v", "libx264",
+		"-preset", "medium",
+		"-crf", "23",
+		"-pix_fmt", "yuv420p",
+		"-c:a", "aac",
+		"-y", opts.Output,
+	}
+	if err := o.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, err
+	}
+
+	return &FitVoiceoverResult{
+		Duration: sections[len(sections)-1].OutputEnd,
+		Sections: sections,
+		Warnings: warnings,
+	}, nil
+}
+
+// planFitSections splits the video and narration at the sync points and
+// works out the tempo, speed, hold and silence for each section
+func planFitSections(opts FitVoiceoverOptions, videoDuration, narrationDuration float64) ([]FitSection, []string, error) {
+	mode := opts.Mode
+	if mode == "" {
+		mode = FitModeAuto
+	}
+	if mode != FitModeAuto && mode != FitModeTempo && mode != FitModeVideo {
+		return nil, nil, fmt.Errorf("unknown mode: %s (use auto, tempo or video)", mode)
+	}
+	videoFit := opts.VideoFit
+	if videoFit == "" {
+		videoFit = VideoFitPad
+	}
+	if videoFit != VideoFitPad && videoFit != VideoFitStretch {
+		return nil, nil, fmt.Errorf("unknown videoFit: %s (use pad or stretch)", videoFit)
+	}
+	maxTempo := opts.MaxTempoChange
+	if maxTempo == 0 {
+		maxTempo = 0.1
+	}
+	if maxTempo < 0 || maxTempo >= 1 {
+		return nil, nil, fmt.Errorf("maxTempoChange must be between 0 and 1")
+	}
+
+	points := append([]SyncPoint{{}}, opts.SyncPoints...)
+	points = append(points, SyncPoint{Video: videoDuration, Narration: narrationDuration})
+	for i := 1; i < len(points); i++ {
+		if points[i].Video <= points[i-1].Video || points[i].Narration <= points[i-1].Narration {
+			return nil, nil, fmt.Errorf("sync points must increase in both video and narration time and lie inside both (%.2fs video, %.2fs narration)",
+				videoDuration, narrationDuration)
+		}
+	}
+
+	var sections []FitSection
+	var warnings []string
+	cursor := 0.0
+	for i := 1; i < len(points); i++ {
+		section := FitSection{
+			VideoStart:     points[i-1].Video,
+			VideoEnd:       points[i].Video,
+			NarrationStart: points[i-1].Narration,
+			NarrationEnd:   points[i].Narration,
+			Tempo:          1,
+			VideoSpeed:     1,
+		}
+		videoLength := section.VideoEnd - section.VideoStart
+		narrationLength := section.NarrationEnd - section.NarrationStart
+
+		switch mode {
+		case FitModeTempo:
+			section.Tempo = narrationLength / videoLength
+			if math.Abs(section.Tempo-1) > maxTempo {
+				warnings = append(warnings, fmt.Sprintf("section %d: narration tempo changed by %+.0f%%", i, (section.Tempo-1)*100))
+			}
+		case FitModeAuto:
+			section.Tempo = min(max(narrationLength/videoLength, 1-maxTempo), 1+maxTempo)
+		}
+
+		fitted := narrationLength / section.Tempo
+		length := videoLength
+		if mode != FitModeTempo && math.Abs(fitted-videoLength) > 0.001 {
+			switch {
+			case videoFit == VideoFitStretch:
+				section.VideoSpeed = videoLength / fitted
+				length = fitted
+			case fitted > videoLength:
+				section.Hold = fitted - videoLength
+				length = fitted
+			default:
+				section.Silence = videoLength - fitted
+			}
+		}
+
+		section.OutputStart = cursor
+		section.OutputEnd = cursor + length
+		cursor = section.OutputEnd
+		sections = append(sections, section)
+	}
+	return sections, warnings, nil
+}
+
+// buildFitVoiceoverFilter retimes each section of the video (input 0) and
+// narration (input 1) and concatenates them into [outv] and [outa]
+func buildFitVoiceoverFilter(sections []FitSection, original bool, originalVolume float64) string {
+	var parts []string
+	var concatInputs strings.Builder
+
+	for i, section := range sections {
+		length := section.OutputEnd - section.OutputStart
+
+		videoChain := fmt.Sprintf("[0:v]trim=start=%.3f:end=%.3f,setpts=PTS-STARTPTS", section.VideoStart, section.VideoEnd)
+		if section.VideoSpeed != 1 {
+			videoChain += fmt.Sprintf(",setpts=PTS/%.4f", section.VideoSpeed)
+		}
+		if section.Hold > 0 {
+			videoChain += fmt.Sprintf(",tpad=stop_mode=clone:stop_duration=%.3f", section.Hold)
+		}
+		parts = append(parts, fmt.Sprintf("%s[v%d]", videoChain, i))
+
+		narrationChain := fmt.Sprintf("[1:a]atrim=start=%.3f:end=%.3f,asetpts=PTS-STARTPTS", section.NarrationStart, section.NarrationEnd)
+		if section.Tempo != 1 {
+			narrationChain += "," + ffmpeg.AtempoChain(section.Tempo)
+		}
+		narrationChain += fmt.Sprintf(",aformat=sample_rates=48000:channel_layouts=stereo,apad=whole_dur=%.3f,atrim=duration=%.3f", length, length)
+
+		if original {
+			parts = append(parts, fmt.Sprintf("%s[n%d]", narrationChain, i))
+			originalChain := fmt.Sprintf("[0:a]atrim=start=%.3f:end=%.3f,asetpts=PTS-STARTPTS", section.VideoStart, section.VideoEnd)
+			if section.VideoSpeed != 1 {
+				originalChain += "," + ffmpeg.AtempoChain(section.VideoSpeed)
+			}
+			originalChain += fmt.Sprintf(",aformat=sample_rates=48000:channel_layouts=stereo,apad=whole_dur=%.3f,atrim=duration=%.3f,volume=%.3f",
+				length, length, originalVolume)
+			parts = append(parts, fmt.Sprintf("%s[o%d]", originalChain, i))
+			parts = append(parts, fmt.Sprintf("[n%d][o%d]amix=inputs=2:duration=first:normalize=0[a%d]", i, i, i))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s[a%d]", narrationChain, i))
+		}
+		concatInputs.WriteString(fmt.Sprintf("[v%d][a%d]", i, i))
+	}
+
+	parts = append(parts, fmt.Sprintf("%sconcat=n=%d:v=1:a=1[outv][outa]", concatInputs.String(), len(sections)))
+	return strings.Join(parts, ";")
+}
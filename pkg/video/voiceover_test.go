@@ -0,0 +1,102 @@
+package video
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPlanFitSectionsAuto(t *testing.T) {
+	// 20s video, 23s narration; the first section needs 5% faster speech,
+	// the second 20%, which is past the 10% limit
+	opts := FitVoiceoverOptions{SyncPoints: []SyncPoint{{Video: 10, Narration: 10.5}}}
+	sections, warnings, err := planFitSections(opts, 20, 23)
+	if err != nil {
+		t.Fatalf("planFitSections() error = %v", err)
+	}
+	if len(sections) != 2 || len(warnings) != 0 {
+		t.Fatalf("Expected 2 sections and no warnings, got %d and %v", len(sections), warnings)
+	}
+
+	first := sections[0]
+	if math.Abs(first.Tempo-1.05) > 1e-9 || first.Hold != 0 || first.Silence != 0 || first.OutputEnd != 10 {
+		t.Errorf("Unexpected first section: %+v", first)
+	}
+
+	// 12.5s of narration at 1.1x is 11.36s: hold the last frame 1.36s
+	second := sections[1]
+	if second.Tempo != 1.1 || math.Abs(second.Hold-(12.5/1.1-10)) > 1e-9 {
+		t.Errorf("Unexpected second section: %+v", second)
+	}
+	if math.Abs(second.OutputEnd-(10+12.5/1.1)) > 1e-9 {
+		t.Errorf("Expected narration and video to end together, got %+v", second)
+	}
+}
+
+func TestPlanFitSectionsModes(t *testing.T) {
+	// Narration shorter than the video
+	sections, _, err := planFitSections(FitVoiceoverOptions{Mode: FitModeVideo}, 10, 8)
+	if err != nil {
+		t.Fatalf("planFitSections() error = %v", err)
+	}
+	if s := sections[0]; s.Tempo != 1 || s.Silence != 2 || s.OutputEnd != 10 {
+		t.Errorf("Expected 2s of silence after the narration, got %+v", s)
+	}
+
+	sections, _, _ = planFitSections(FitVoiceoverOptions{Mode: FitModeVideo, VideoFit: VideoFitStretch}, 10, 8)
+	if s := sections[0]; s.VideoSpeed != 1.25 || s.OutputEnd != 8 {
+		t.Errorf("Expected video sped up to 1.25x, got %+v", s)
+	}
+
+	sections, warnings, _ := planFitSections(FitVoiceoverOptions{Mode: FitModeTempo}, 10, 8)
+	if s := sections[0]; s.Tempo != 0.8 || s.VideoSpeed != 1 || s.OutputEnd != 10 {
+		t.Errorf("Expected narration slowed to 0.8x, got %+v", s)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "-20%") {
+		t.Errorf("Expected a tempo warning, got %v", warnings)
+	}
+
+	for name, opts := range map[string]FitVoiceoverOptions{
+		"unknown mode":      {Mode: "warp"},
+		"unknown video fit": {VideoFit: "crop"},
+		"tempo limit":       {MaxTempoChange: 1},
+		"unordered points":  {SyncPoints: []SyncPoint{{Video: 5, Narration: 5}, {Video: 4, Narration: 6}}},
+		"point past end":    {SyncPoints: []SyncPoint{{Video: 5, Narration: 9}}},
+	} {
+		if _, _, err := planFitSections(opts, 10, 8); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestBuildFitVoiceoverFilter(t *testing.T) {
+	sections := []FitSection{
+		{VideoStart: 0, VideoEnd: 10, NarrationStart: 0, NarrationEnd: 10.5, Tempo: 1.05, VideoSpeed: 1, OutputEnd: 10},
+		{VideoStart: 10, VideoEnd: 20, NarrationStart: 10.5, NarrationEnd: 22.5, Tempo: 1.1, VideoSpeed: 1, Hold: 0.909, OutputStart: 10, OutputEnd: 20.909},
+	}
+
+	filter := buildFitVoiceoverFilter(sections, false, 0)
+	for _, want := range []string{
+		"[0:v]trim=start=0.000:end=10.000,setpts=PTS-STARTPTS[v0]",
+		"[1:a]atrim=start=0.000:end=10.500,asetpts=PTS-STARTPTS,atempo=1.0500,aformat=sample_rates=48000:channel_layouts=stereo,apad=whole_dur=10.000,atrim=duration=10.000[a0]",
+		"[0:v]trim=start=10.000:end=20.000,setpts=PTS-STARTPTS,tpad=stop_mode=clone:stop_duration=0.909[v1]",
+		"[v0][a0][v1][a1]concat=n=2:v=1:a=1[outv][outa]",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Filter missing %q:\n%s", want, filter)
+		}
+	}
+
+	sections = []FitSection{{VideoEnd: 10, NarrationEnd: 8, Tempo: 1, VideoSpeed: 1.25, OutputEnd: 8}}
+	filter = buildFitVoiceoverFilter(sections, true, 0.2)
+	for _, want := range []string{
+		"setpts=PTS-STARTPTS,setpts=PTS/1.2500[v0]",
+		"[0:a]atrim=start=0.000:end=10.000,asetpts=PTS-STARTPTS,atempo=1.2500,",
+		"volume=0.200[o0]",
+		"[n0][o0]amix=inputs=2:duration=first:normalize=0[a0]",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Filter missing %q:\n%s", want, filter)
+		}
+	}
+}
@@ -2,15 +2,17 @@ package vision
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
 	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
-	openai "github.com/sashabaranov/go-openai"
 )
 
 // FrameAnalysis represents analysis of a single frame
@@ -27,10 +29,11 @@ type FrameAnalysis struct {
 
 // VideoSceneAnalysis represents complete video analysis
 type VideoSceneAnalysis struct {
-	VideoPath string          `json:"videoPath"`
-	Duration  float64         `json:"duration"`
-	Frames    []FrameAnalysis `json:"frames"`
-	Summary   string          `json:"summary"`
+	VideoPath    string          `json:"videoPath"`
+	Duration     float64         `json:"duration"`
+	Frames       []FrameAnalysis `json:"frames"`
+	Summary      string          `json:"summary"`
+	CachedFrames int             `json:"cachedFrames"` // Frames answered from the cache
 }
 
 // VisualSearchMatch represents a search match
@@ -43,88 +46,253 @@ type VisualSearchMatch struct {
 
 // VisualSearchResult represents search results
 type VisualSearchResult struct {
-	Found   bool                `json:"found"`
-	Matches []VisualSearchMatch `json:"matches"`
+	Found        bool                `json:"found"`
+	Matches      []VisualSearchMatch `json:"matches"`
+	Ranges       []VisualSearchRange `json:"ranges"`       // Consecutive matches joined into continuous spans
+	CachedFrames int                 `json:"cachedFrames"` // Frames answered from the cache
 }
 
 // Analyzer handles video vision analysis
 type Analyzer struct {
-	client   *openai.Client
+	config   *config.Config
 	videoOps *video.Operations
 	ffmpeg   *ffmpeg.Manager
-	tempDir  string
+	cache    *frameCache
 }
 
-// NewAnalyzer creates a new vision analyzer
-func NewAnalyzer(apiKey string, videoOps *video.Operations, ffmpegMgr *ffmpeg.Manager) *Analyzer {
-	var client *openai.Client
-	if apiKey != "" {
-		client = openai.NewClient(apiKey)
-	}
-
-	tempDir := filepath.Join(os.TempDir(), ".mcp-video-vision-temp")
-	os.MkdirAll(tempDir, 0755)
-
+// NewAnalyzer creates a new vision analyzer. The provider is read from cfg
+// on each call, so config changes apply without a restart.
+func NewAnalyzer(cfg *config.Config, videoOps *video.Operations, ffmpegMgr *ffmpeg.Manager) *Analyzer {
 	return &Analyzer{
-		client:   client,
+		config:   cfg,
 		videoOps: videoOps,
 		ffmpeg:   ffmpegMgr,
-		tempDir:  tempDir,
+		cache:    newFrameCache(defaultCacheDir()),
 	}
 }
 
-// AnalyzeFrame analyzes a single frame with GPT-4 Vision
+// DefaultWorkers is how many frames are analyzed at once unless
+// visionWorkers is configured
+const DefaultWorkers = 4
+
+// defaultFramePrompt asks for a general description of a frame
+const defaultFramePrompt = "Describe what you see in this video frame in detail. Include any visible objects, people, text, actions, and the overall scene."
+
+// Provider returns the configured vision provider
+func (a *Analyzer) Provider() (VisionProvider, error) {
+	return NewProvider(ProviderSettings{
+		Provider:     a.config.VisionProvider,
+		Model:        a.config.VisionModel,
+		OpenAIKey:    a.config.OpenAIKey,
+		ClaudeAPIKey: a.config.ClaudeAPIKey,
+		GeminiAPIKey: a.config.GeminiAPIKey,
+		OllamaURL:    a.config.OllamaURL,
+	})
+}
+
+// AnalyzeFrame analyzes a single frame with the configured vision provider
 func (a *Analyzer) AnalyzeFrame(ctx context.Context, imagePath string, prompt string) (string, error) {
-	if a.client == nil {
-		return "", fmt.Errorf("OpenAI API key not configured")
+	provider, err := a.Provider()
+	if err != nil {
+		return "", err
 	}
 
-	// Read image and encode to base64
 	imageData, err := os.ReadFile(imagePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to read image: %w", err)
 	}
 
-	base64Image := base64.StdEncoding.EncodeToString(imageData)
-	imageURL := fmt.Sprintf("data:image/jpeg;base64,%s", base64Image)
-
 	// Default prompt if not provided
 	if prompt == "" {
-		prompt = "Describe what you see in this video frame in detail. Include any visible objects, people, text, actions, and the overall scene."
-	}
-
-	// Call GPT-4 Vision API
-	resp, err := a.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model: openai.GPT4o,
-		Messages: []openai.ChatCompletionMessage{
-			{
-				Role: openai.ChatMessageRoleUser,
-				MultiContent: []openai.ChatMessagePart{
-					{
-						Type: openai.ChatMessagePartTypeText,
-						Text: prompt,
-					},
-					{
-						Type: openai.ChatMessagePartTypeImageURL,
-						ImageURL: &openai.ChatMessageImageURL{
-							URL: imageURL,
-						},
-					},
-				},
-			},
-		},
-		MaxTokens: 500,
-	})
+		prompt = defaultFramePrompt
+	}
 
+	description, err := provider.Describe(ctx, prompt, [][]byte{imageData}, 500)
 	if err != nil {
 		return "", fmt.Errorf("failed to analyze frame: %w", err)
 	}
+	if description == "" {
+		return "No description available", nil
+	}
+	return description, nil
+}
+
+// DescribeScene describes the frame at timestamp, reusing a cached answer
+// for the same video, timestamp and prompt
+func (a *Analyzer) DescribeScene(ctx context.Context, videoPath string, timestamp float64, prompt string) (string, error) {
+	provider, err := a.Provider()
+	if err != nil {
+		return "", err
+	}
+	hash, err := a.cache.fileHash(videoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read video: %w", err)
+	}
+	if prompt == "" {
+		prompt = defaultFramePrompt
+	}
 
-	if len(resp.Choices) == 0 {
+	description, _, err := a.describe(ctx, provider, videoPath, hash, prompt, []float64{timestamp}, 500)
+	if err != nil {
+		return "", err
+	}
+	if description == "" {
 		return "No description available", nil
 	}
+	return description, nil
+}
+
+// ClearCache removes cached frames and descriptions for one video, or for
+// all videos when videoPath is empty
+func (a *Analyzer) ClearCache(videoPath string) (CacheStats, error) {
+	if videoPath == "" {
+		return a.cache.clear("")
+	}
+	hash, err := a.cache.fileHash(videoPath)
+	if err != nil {
+		return CacheStats{}, fmt.Errorf("failed to read video: %w", err)
+	}
+	return a.cache.clear(hash)
+}
+
+// DescribeFrames answers prompt about each frame at timestamps, in order,
+// reusing cached answers and analyzing frames concurrently
+func (a *Analyzer) DescribeFrames(ctx context.Context, videoPath string, timestamps []float64, prompt string) ([]string, error) {
+	provider, err := a.Provider()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := a.cache.fileHash(videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read video: %w", err)
+	}
+
+	results := a.describeFrames(ctx, provider, videoPath, hash, prompt, timestamps, true)
+	descriptions := make([]string, len(results))
+	for i, result := range results {
+		if result.err != nil {
+			return nil, fmt.Errorf("failed to analyze frame at %.2fs: %w", timestamps[i], result.err)
+		}
+		descriptions[i] = result.description
+	}
+	return descriptions, nil
+}
+
+// frame returns the cached frame at timestamp, extracting it on first use
+func (a *Analyzer) frame(ctx context.Context, videoPath, hash string, timestamp float64) (string, error) {
+	path := a.cache.framePath(hash, timestamp)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	// Extract beside the final name so an interrupted run leaves no partial
+	// frame, under a name of its own so concurrent calls for the same frame
+	// don't write into each other's file
+	f, err := os.CreateTemp(filepath.Dir(path), strings.TrimSuffix(filepath.Base(path), ".jpg")+"-*.partial.jpg")
+	if err != nil {
+		return "", err
+	}
+	partial := f.Name()
+	f.Close()
+	if err := a.extractFrameAtTimestamp(ctx, videoPath, timestamp, partial); err != nil {
+		os.Remove(partial)
+		return "", fmt.Errorf("failed to extract frame at %.2fs: %w", timestamp, err)
+	}
+	return path, os.Rename(partial, path)
+}
+
+// describe answers prompt about the frames at timestamps, reusing a cached
+// answer from the same provider and model. It reports whether the answer
+// came from the cache.
+func (a *Analyzer) describe(ctx context.Context, provider VisionProvider, videoPath, hash, prompt string, timestamps []float64, maxTokens int) (string, bool, error) {
+	key := a.cache.descriptionPath(hash, provider.Name(), provider.Model(), prompt, timestamps)
+	if description, ok := a.cache.description(key); ok {
+		return description, true, nil
+	}
+
+	var images [][]byte
+	for _, timestamp := range timestamps {
+		path, err := a.frame(ctx, videoPath, hash, timestamp)
+		if err != nil {
+			return "", false, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read frame: %w", err)
+		}
+		images = append(images, data)
+	}
+
+	description, err := provider.Describe(ctx, prompt, images, maxTokens)
+	if err != nil {
+		return "", false, err
+	}
+	if description != "" {
+		// A failed write only costs a future cache miss
+		a.cache.storeDescription(key, cachedDescription{
+			Provider:    provider.Name(),
+			Model:       provider.Model(),
+			Prompt:      prompt,
+			Timestamps:  timestamps,
+			Description: description,
+			Created:     time.Now(),
+		})
+	}
+	return description, false, nil
+}
+
+// frameResult is one frame's answer from describeFrames
+type frameResult struct {
+	description string
+	cached      bool
+	err         error
+}
 
-	return resp.Choices[0].Message.Content, nil
+// workers returns how many frames may be analyzed at once
+func (a *Analyzer) workers() int {
+	if a.config.VisionWorkers > 0 {
+		return a.config.VisionWorkers
+	}
+	return DefaultWorkers
+}
+
+// describeFrames answers prompt about each frame at timestamps, running up
+// to the configured number of workers at once. Results are in timestamp
+// order. With stopOnError, frames not yet started after a failure are
+// skipped and report the context error.
+func (a *Analyzer) describeFrames(ctx context.Context, provider VisionProvider, videoPath, hash, prompt string, timestamps []float64, stopOnError bool) []frameResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]frameResult, len(timestamps))
+	slots := make(chan struct{}, a.workers())
+	var wg sync.WaitGroup
+	for i, timestamp := range timestamps {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			results[i].err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, timestamp float64) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if err := ctx.Err(); err != nil {
+				results[i].err = err
+				return
+			}
+			description, cached, err := a.describe(ctx, provider, videoPath, hash, prompt, []float64{timestamp}, 500)
+			results[i] = frameResult{description: description, cached: cached, err: err}
+			if err != nil && stopOnError {
+				cancel()
+			}
+		}(i, timestamp)
+	}
+	wg.Wait()
+	return results
 }
 
 // extractFrameAtTimestamp extracts a single frame at a specific timestamp
@@ -142,8 +310,9 @@ func (a *Analyzer) extractFrameAtTimestamp(ctx context.Context, videoPath string
 
 // AnalyzeVideo analyzes multiple frames from a video
 func (a *Analyzer) AnalyzeVideo(ctx context.Context, videoPath string, interval float64, count *int) (*VideoSceneAnalysis, error) {
-	if a.client == nil {
-		return nil, fmt.Errorf("OpenAI API key not configured")
+	provider, err := a.Provider()
+	if err != nil {
+		return nil, err
 	}
 
 	// Get video info
@@ -170,47 +339,58 @@ func (a *Analyzer) AnalyzeVideo(ctx context.Context, videoPath string, interval
 		}
 	}
 
-	// Extract and analyze each frame
+	hash, err := a.cache.fileHash(videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read video: %w", err)
+	}
+
+	// Extract and analyze the frames concurrently, reusing cached frames and
+	// answers. The first failure stops frames that have not started.
+	results := a.describeFrames(ctx, provider, videoPath, hash, defaultFramePrompt, timestamps, true)
+
 	var frames []FrameAnalysis
+	cachedFrames := 0
 	for i, timestamp := range timestamps {
-		framePath := filepath.Join(a.tempDir, fmt.Sprintf("frame-%d.jpg", i+1))
-
-		// Extract frame at specific timestamp
-		if err := a.extractFrameAtTimestamp(ctx, videoPath, timestamp, framePath); err != nil {
-			return nil, fmt.Errorf("failed to extract frame %d: %w", i, err)
+		result := results[i]
+		if result.err != nil {
+			return nil, fmt.Errorf("failed to analyze frame %d: %w", i, result.err)
 		}
-
-		description, err := a.AnalyzeFrame(ctx, framePath, "")
-		if err != nil {
-			return nil, fmt.Errorf("failed to analyze frame %d: %w", i, err)
+		if result.cached {
+			cachedFrames++
+		}
+		description := result.description
+		if description == "" {
+			description = "No description available"
 		}
 
 		frames = append(frames, FrameAnalysis{
 			Timestamp:   timestamp,
 			FrameNumber: i,
-			ImagePath:   framePath,
+			ImagePath:   a.cache.framePath(hash, timestamp),
 			Description: description,
 		})
 	}
 
 	// Generate summary
-	summary, err := a.generateSummary(ctx, frames)
+	summary, err := a.generateSummary(ctx, provider, frames)
 	if err != nil {
 		summary = "Summary unavailable"
 	}
 
 	return &VideoSceneAnalysis{
-		VideoPath: videoPath,
-		Duration:  info.Duration,
-		Frames:    frames,
-		Summary:   summary,
+		VideoPath:    videoPath,
+		Duration:     info.Duration,
+		Frames:       frames,
+		Summary:      summary,
+		CachedFrames: cachedFrames,
 	}, nil
 }
 
 // SearchVisualContent searches for specific content in video
 func (a *Analyzer) SearchVisualContent(ctx context.Context, videoPath string, query string, interval float64) (*VisualSearchResult, error) {
-	if a.client == nil {
-		return nil, fmt.Errorf("OpenAI API key not configured")
+	provider, err := a.Provider()
+	if err != nil {
+		return nil, err
 	}
 
 	// Get video info
@@ -238,19 +418,24 @@ Respond in this exact JSON format:
   "description": "brief description of what matches or why it doesn't match"
 }`, query)
 
+	hash, err := a.cache.fileHash(videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read video: %w", err)
+	}
+
+	// Frames that fail are skipped rather than failing the search
+	results := a.describeFrames(ctx, provider, videoPath, hash, searchPrompt, timestamps, false)
+
 	var matches []VisualSearchMatch
+	cachedFrames := 0
 	for i, timestamp := range timestamps {
-		framePath := filepath.Join(a.tempDir, fmt.Sprintf("search-frame-%d.jpg", i+1))
-
-		// Extract frame at specific timestamp
-		if err := a.extractFrameAtTimestamp(ctx, videoPath, timestamp, framePath); err != nil {
+		if results[i].err != nil {
 			continue
 		}
-
-		response, err := a.AnalyzeFrame(ctx, framePath, searchPrompt)
-		if err != nil {
-			continue
+		if results[i].cached {
+			cachedFrames++
 		}
+		response := results[i].description
 
 		// Parse JSON response
 		var result struct {
@@ -292,85 +477,40 @@ Respond in this exact JSON format:
 	}
 
 	return &VisualSearchResult{
-		Found:   len(matches) > 0,
-		Matches: matches,
+		Found:        len(matches) > 0,
+		Matches:      matches,
+		Ranges:       MatchRanges(matches, interval, info.Duration),
+		CachedFrames: cachedFrames,
 	}, nil
 }
 
 // CompareFrames compares two video frames
 func (a *Analyzer) CompareFrames(ctx context.Context, videoPath string, timestamp1, timestamp2 float64) (string, error) {
-	if a.client == nil {
-		return "", fmt.Errorf("OpenAI API key not configured")
-	}
-
-	// Extract both frames
-	frame1Path := filepath.Join(a.tempDir, "compare-frame-1.jpg")
-	frame2Path := filepath.Join(a.tempDir, "compare-frame-2.jpg")
-
-	if err := a.extractFrameAtTimestamp(ctx, videoPath, timestamp1, frame1Path); err != nil {
-		return "", fmt.Errorf("failed to extract first frame: %w", err)
-	}
-	if err := a.extractFrameAtTimestamp(ctx, videoPath, timestamp2, frame2Path); err != nil {
-		return "", fmt.Errorf("failed to extract second frame: %w", err)
-	}
-
-	// Read and encode both images
-	imageData1, err := os.ReadFile(frame1Path)
+	provider, err := a.Provider()
 	if err != nil {
-		return "", fmt.Errorf("failed to read first frame: %w", err)
+		return "", err
 	}
-	imageData2, err := os.ReadFile(frame2Path)
+
+	hash, err := a.cache.fileHash(videoPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to read second frame: %w", err)
+		return "", fmt.Errorf("failed to read video: %w", err)
 	}
 
-	base64Image1 := base64.StdEncoding.EncodeToString(imageData1)
-	base64Image2 := base64.StdEncoding.EncodeToString(imageData2)
-
-	// Compare frames using GPT-4 Vision
+	// Compare frames with the vision provider
 	prompt := "Compare these two video frames. Describe the differences, similarities, and any notable changes between them."
 
-	resp, err := a.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model: openai.GPT4o,
-		Messages: []openai.ChatCompletionMessage{
-			{
-				Role: openai.ChatMessageRoleUser,
-				MultiContent: []openai.ChatMessagePart{
-					{
-						Type: openai.ChatMessagePartTypeText,
-						Text: prompt,
-					},
-					{
-						Type: openai.ChatMessagePartTypeImageURL,
-						ImageURL: &openai.ChatMessageImageURL{
-							URL: fmt.Sprintf("data:image/jpeg;base64,%s", base64Image1),
-						},
-					},
-					{
-						Type: openai.ChatMessagePartTypeImageURL,
-						ImageURL: &openai.ChatMessageImageURL{
-							URL: fmt.Sprintf("data:image/jpeg;base64,%s", base64Image2),
-						},
-					},
-				},
-			},
-		},
-		MaxTokens: 500,
-	})
-
+	comparison, _, err := a.describe(ctx, provider, videoPath, hash, prompt, []float64{timestamp1, timestamp2}, 500)
 	if err != nil {
 		return "", fmt.Errorf("failed to compare frames: %w", err)
 	}
-
-	if len(resp.Choices) == 0 {
+	if comparison == "" {
 		return "No comparison available", nil
 	}
-
-	return resp.Choices[0].Message.Content, nil
+	return comparison, nil
 }
 
 // generateSummary generates an overall summary from frame analyses
-func (a *Analyzer) generateSummary(ctx context.Context, frames []FrameAnalysis) (string, error) {
+func (a *Analyzer) generateSummary(ctx context.Context, provider VisionProvider, frames []FrameAnalysis) (string, error) {
 	if len(frames) == 0 {
 		return "No frames analyzed", nil
 	}
@@ -389,29 +529,12 @@ func (a *Analyzer) generateSummary(ctx context.Context, frames []FrameAnalysis)
 		}
 	}
 
-	resp, err := a.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model: openai.GPT4o,
-		Messages: []openai.ChatCompletionMessage{
-			{
-				Role:    openai.ChatMessageRoleUser,
-				Content: prompt,
-			},
-		},
-		MaxTokens: 300,
-	})
-
+	summary, err := provider.Describe(ctx, prompt, nil, 300)
 	if err != nil {
 		return "", err
 	}
-
-	if len(resp.Choices) == 0 {
+	if summary == "" {
 		return "No summary available", nil
 	}
-
-	return resp.Choices[0].Message.Content, nil
-}
-
-// Cleanup removes temporary files
-func (a *Analyzer) Cleanup() error {
-	return os.RemoveAll(a.tempDir)
+	return summary, nil
 }
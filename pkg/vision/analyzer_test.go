@@ -0,0 +1,103 @@
+package vision
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+)
+
+// fakeProvider answers with the image it was given and tracks concurrency
+type fakeProvider struct {
+	mu      sync.Mutex
+	active  int
+	peak    int
+	calls   int
+	failOn  string
+	latency time.Duration
+}
+
+func (p *fakeProvider) Name() string  { return "fake" }
+func (p *fakeProvider) Model() string { return "fake-1" }
+
+func (p *fakeProvider) Describe(ctx context.Context, prompt string, images [][]byte, maxTokens int) (string, error) {
+	p.mu.Lock()
+	p.active++
+	p.calls++
+	p.peak = max(p.peak, p.active)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.active--
+		p.mu.Unlock()
+	}()
+
+	time.Sleep(p.latency)
+	answer := string(images[0])
+	if answer == p.failOn {
+		return "", errors.New("model unavailable")
+	}
+	return answer, nil
+}
+
+// newTestAnalyzer returns an analyzer whose cache already holds a frame for
+// each timestamp, so no FFmpeg is needed
+func newTestAnalyzer(t *testing.T, workers int, timestamps []float64) (*Analyzer, string) {
+	a := &Analyzer{
+		config: &config.Config{VisionWorkers: workers},
+		cache:  newFrameCache(t.TempDir()),
+	}
+	for _, timestamp := range timestamps {
+		path := a.cache.framePath("video", timestamp)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(fmt.Sprintf("frame %.0f", timestamp)), 0644)
+	}
+	return a, "video"
+}
+
+func TestDescribeFramesKeepsOrder(t *testing.T) {
+	timestamps := []float64{0, 5, 10, 15, 20, 25, 30, 35}
+	a, hash := newTestAnalyzer(t, 3, timestamps)
+	provider := &fakeProvider{latency: 10 * time.Millisecond}
+
+	results := a.describeFrames(context.Background(), provider, "clip.mp4", hash, "describe", timestamps, true)
+	for i, timestamp := range timestamps {
+		if want := fmt.Sprintf("frame %.0f", timestamp); results[i].description != want || results[i].err != nil {
+			t.Errorf("results[%d] = %+v, want %q", i, results[i], want)
+		}
+	}
+	if provider.peak < 2 || provider.peak > 3 {
+		t.Errorf("Expected up to 3 frames at once, peak was %d", provider.peak)
+	}
+
+	results = a.describeFrames(context.Background(), provider, "clip.mp4", hash, "describe", timestamps, true)
+	if provider.calls != len(timestamps) || !results[0].cached {
+		t.Errorf("Expected the second run to come from the cache, got %d calls", provider.calls)
+	}
+}
+
+func TestDescribeFramesStopsOnError(t *testing.T) {
+	timestamps := []float64{0, 5, 10, 15, 20, 25, 30, 35}
+	a, hash := newTestAnalyzer(t, 1, timestamps)
+	provider := &fakeProvider{failOn: "frame 5"}
+
+	results := a.describeFrames(context.Background(), provider, "clip.mp4", hash, "describe", timestamps, true)
+	if results[1].err == nil || results[len(results)-1].err == nil {
+		t.Errorf("Expected the failure and the frames after it to report errors, got %+v", results)
+	}
+	if provider.calls != 2 {
+		t.Errorf("Expected frames after a failure to be skipped, got %d calls", provider.calls)
+	}
+
+	provider = &fakeProvider{failOn: "frame 5"}
+	results = a.describeFrames(context.Background(), provider, "clip.mp4", hash, "search", timestamps, false)
+	if results[1].err == nil || results[2].err != nil || provider.calls != len(timestamps) {
+		t.Errorf("Expected a search to skip only the failed frame, got %d calls", provider.calls)
+	}
+}
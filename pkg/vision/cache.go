@@ -0,0 +1,155 @@
+package vision
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// frameCache keeps extracted frames and model descriptions on disk, one
+// directory per source file content hash
+type frameCache struct {
+	dir    string
+	mu     sync.Mutex
+	hashes map[string]fileStamp // Path -> hash, while size and mtime match
+}
+
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+	hash    string
+}
+
+// cachedDescription is a stored model answer
+type cachedDescription struct {
+	Provider    string    `json:"provider"`
+	Model       string    `json:"model"`
+	Prompt      string    `json:"prompt"`
+	Timestamps  []float64 `json:"timestamps"`
+	Description string    `json:"description"`
+	Created     time.Time `json:"created"`
+}
+
+// CacheStats describes what a cache clear removed
+type CacheStats struct {
+	Files int   `json:"files"`
+	Bytes int64 `json:"bytes"`
+}
+
+// defaultCacheDir is under the user cache directory so it survives restarts
+func defaultCacheDir() string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "mcp-video-editor", "vision")
+}
+
+func newFrameCache(dir string) *frameCache {
+	return &frameCache{dir: dir, hashes: make(map[string]fileStamp)}
+}
+
+// fileHash returns the SHA256 of a file's content. Hashes are remembered
+// per path until the file's size or modification time changes.
+func (c *frameCache) fileHash(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	stamp, ok := c.hashes[path]
+	c.mu.Unlock()
+	if ok && stamp.size == info.Size() && stamp.modTime.Equal(info.ModTime()) {
+		return stamp.hash, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	c.mu.Lock()
+	c.hashes[path] = fileStamp{size: info.Size(), modTime: info.ModTime(), hash: hash}
+	c.mu.Unlock()
+	return hash, nil
+}
+
+// framePath is where the frame at timestamp is kept, to the millisecond
+func (c *frameCache) framePath(hash string, timestamp float64) string {
+	return filepath.Join(c.dir, hash, fmt.Sprintf("frame-%d.jpg", int64(timestamp*1000+0.5)))
+}
+
+// descriptionPath is where the answer to prompt about the frames at
+// timestamps is kept. Provider and model are part of the key, since
+// another model gives another answer.
+func (c *frameCache) descriptionPath(hash, provider, model, prompt string, timestamps []float64) string {
+	stamps := make([]string, len(timestamps))
+	for i, t := range timestamps {
+		stamps[i] = fmt.Sprintf("%d", int64(t*1000+0.5))
+	}
+	key := sha256.Sum256([]byte(strings.Join([]string{provider, model, strings.Join(stamps, ","), prompt}, "\x00")))
+	return filepath.Join(c.dir, hash, "desc-"+hex.EncodeToString(key[:12])+".json")
+}
+
+func (c *frameCache) description(path string) (string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	var entry cachedDescription
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return "", false
+	}
+	return entry.Description, true
+}
+
+func (c *frameCache) storeDescription(path string, entry cachedDescription) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// clear removes the cache for one source hash, or everything when hash is
+// empty
+func (c *frameCache) clear(hash string) (CacheStats, error) {
+	dir := c.dir
+	if hash != "" {
+		dir = filepath.Join(c.dir, hash)
+	}
+	var stats CacheStats
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			stats.Files++
+			stats.Bytes += info.Size()
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, err
+	}
+	return stats, os.RemoveAll(dir)
+}
@@ -0,0 +1,92 @@
+package vision
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileHashFollowsContent(t *testing.T) {
+	dir := t.TempDir()
+	video := filepath.Join(dir, "clip.mp4")
+	os.WriteFile(video, []byte("first"), 0644)
+
+	cache := newFrameCache(filepath.Join(dir, "cache"))
+	first, err := cache.fileHash(video)
+	if err != nil {
+		t.Fatalf("fileHash() error = %v", err)
+	}
+	if again, _ := cache.fileHash(video); again != first {
+		t.Errorf("Expected a stable hash, got %q then %q", first, again)
+	}
+
+	os.WriteFile(video, []byte("second take"), 0644)
+	os.Chtimes(video, time.Now(), time.Now().Add(time.Minute))
+	changed, _ := cache.fileHash(video)
+	if changed == first {
+		t.Error("Expected an edited file to hash differently")
+	}
+
+	copied := filepath.Join(dir, "copy.mp4")
+	os.WriteFile(copied, []byte("second take"), 0644)
+	if hash, _ := cache.fileHash(copied); hash != changed {
+		t.Error("Expected identical content to share a hash across paths")
+	}
+}
+
+func TestDescriptionKeys(t *testing.T) {
+	cache := newFrameCache(t.TempDir())
+	base := cache.descriptionPath("abc", ProviderOpenAI, "gpt-4o", "describe", []float64{1.5})
+
+	if cache.descriptionPath("abc", ProviderOpenAI, "gpt-4o", "describe", []float64{1.5000001}) != base {
+		t.Error("Expected timestamps to match to the millisecond")
+	}
+	for name, other := range map[string]string{
+		"timestamp": cache.descriptionPath("abc", ProviderOpenAI, "gpt-4o", "describe", []float64{2}),
+		"prompt":    cache.descriptionPath("abc", ProviderOpenAI, "gpt-4o", "find a cat", []float64{1.5}),
+		"model":     cache.descriptionPath("abc", ProviderOpenAI, "gpt-4o-mini", "describe", []float64{1.5}),
+		"provider":  cache.descriptionPath("abc", ProviderClaude, "gpt-4o", "describe", []float64{1.5}),
+		"video":     cache.descriptionPath("def", ProviderOpenAI, "gpt-4o", "describe", []float64{1.5}),
+	} {
+		if other == base {
+			t.Errorf("Expected a different %s to use a different key", name)
+		}
+	}
+
+	if _, ok := cache.description(base); ok {
+		t.Error("Expected a miss before storing")
+	}
+	if err := cache.storeDescription(base, cachedDescription{Description: "A cat."}); err != nil {
+		t.Fatalf("storeDescription() error = %v", err)
+	}
+	if got, ok := cache.description(base); !ok || got != "A cat." {
+		t.Errorf("description() = %q, %v", got, ok)
+	}
+}
+
+func TestClearCache(t *testing.T) {
+	cache := newFrameCache(t.TempDir())
+	for _, hash := range []string{"abc", "def"} {
+		path := cache.framePath(hash, 1)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte("jpeg"), 0644)
+		cache.storeDescription(cache.descriptionPath(hash, ProviderOpenAI, "gpt-4o", "describe", []float64{1}), cachedDescription{Description: "x"})
+	}
+
+	stats, err := cache.clear("abc")
+	if err != nil || stats.Files != 2 || stats.Bytes == 0 {
+		t.Errorf("clear(abc) = %+v, %v; want 2 files", stats, err)
+	}
+	if _, err := os.Stat(cache.framePath("def", 1)); err != nil {
+		t.Error("Expected other videos to stay cached")
+	}
+
+	stats, err = cache.clear("")
+	if err != nil || stats.Files != 2 {
+		t.Errorf("clear() = %+v, %v; want 2 files", stats, err)
+	}
+	if stats, err := cache.clear("missing"); err != nil || stats.Files != 0 {
+		t.Errorf("Expected clearing an uncached video to be a no-op, got %+v, %v", stats, err)
+	}
+}
@@ -0,0 +1,127 @@
+package vision
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/llm"
+)
+
+// facePrompt asks for every visible face as a normalized bounding box
+const facePrompt = `Find every human face visible in this frame, including small, partly hidden and turned-away faces.
+
+Give each face's bounding box as fractions of the frame size: x and y are the top-left corner (0 = left/top edge, 1 = right/bottom edge), w and h are the width and height.
+
+Respond in this exact JSON format:
+{
+  "boxes": [{"x": 0.0-1.0, "y": 0.0-1.0, "w": 0.0-1.0, "h": 0.0-1.0}]
+}
+
+Use an empty list when there are no faces.`
+
+// BoxDetectionResult lists boxes per sampled frame and the same subjects
+// linked into tracks over time
+type BoxDetectionResult struct {
+	VideoPath    string       `json:"videoPath"`
+	Query        string       `json:"query,omitempty"` // Object searched for; empty for faces
+	Interval     float64      `json:"interval"`
+	Frames       []FrameBoxes `json:"frames"`
+	Tracks       []BoxTrack   `json:"tracks"`
+	FailedFrames int          `json:"failedFrames,omitempty"` // Frames the provider could not analyze
+	CachedFrames int          `json:"cachedFrames"`           // Frames answered from the cache
+}
+
+// DetectFaces finds faces in frames sampled every interval seconds (default:
+// 1), or at the given timestamps, and links them into tracks that cover the
+// time between samples. Frames that fail are counted rather than failing
+// the detection, unless every frame fails.
+func (a *Analyzer) DetectFaces(ctx context.Context, videoPath string, interval float64, timestamps []float64) (*BoxDetectionResult, error) {
+	return a.detectBoxes(ctx, videoPath, facePrompt, interval, timestamps)
+}
+
+// detectBoxes asks the prompt of each sampled frame, reads the boxes from
+// the answers and links them into tracks
+func (a *Analyzer) detectBoxes(ctx context.Context, videoPath, prompt string, interval float64, timestamps []float64) (*BoxDetectionResult, error) {
+	provider, err := a.Provider()
+	if err != nil {
+		return nil, err
+	}
+	info, err := a.videoOps.GetVideoInfo(ctx, videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+
+	if interval <= 0 {
+		interval = 1
+	}
+	if len(timestamps) == 0 {
+		for t := 0.0; t < info.Duration; t += interval {
+			timestamps = append(timestamps, t)
+		}
+	}
+	if len(timestamps) == 0 {
+		return nil, fmt.Errorf("no frames to analyze")
+	}
+
+	hash, err := a.cache.fileHash(videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read video: %w", err)
+	}
+
+	results := a.describeFrames(ctx, provider, videoPath, hash, prompt, timestamps, false)
+	detection := &BoxDetectionResult{VideoPath: videoPath, Interval: interval}
+	var lastErr error
+	for i, result := range results {
+		if result.err != nil {
+			lastErr = result.err
+			detection.FailedFrames++
+			continue
+		}
+		if result.cached {
+			detection.CachedFrames++
+		}
+		detection.Frames = append(detection.Frames, FrameBoxes{Timestamp: timestamps[i], Boxes: parseBoxes(result.description)})
+	}
+	if len(detection.Frames) == 0 {
+		return nil, fmt.Errorf("failed to analyze frames: %w", lastErr)
+	}
+
+	// A subject may be missed in a frame or two without starting a new track
+	detection.Tracks = LinkTracks(detection.Frames, 3*interval, interval, info.Duration)
+	return detection, nil
+}
+
+// parseBoxes reads the model's JSON answer, dropping empty boxes and
+// clipping the rest to the frame
+func parseBoxes(answer string) []Box {
+	var raw struct {
+		Boxes []Box `json:"boxes"`
+	}
+	if err := llm.DecodeJSON(answer, &raw); err != nil {
+		return nil
+	}
+
+	var boxes []Box
+	for _, b := range raw.Boxes {
+		if box, ok := clipBox(b); ok {
+			boxes = append(boxes, box)
+		}
+	}
+	return boxes
+}
+
+// clipBox limits a box to the frame. Boxes with no area left are dropped.
+func clipBox(b Box) (Box, bool) {
+	if b.X < 0 {
+		b.W, b.X = b.W+b.X, 0
+	}
+	if b.Y < 0 {
+		b.H, b.Y = b.H+b.Y, 0
+	}
+	b.W, b.H = math.Min(b.W, 1-b.X), math.Min(b.H, 1-b.Y)
+	if b.W <= 0.001 || b.H <= 0.001 {
+		return Box{}, false
+	}
+	return b, true
+}
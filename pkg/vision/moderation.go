@@ -0,0 +1,107 @@
+package vision
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/llm"
+)
+
+// moderationPrompt asks for content that needs review before publishing
+const moderationPrompt = `Review this video frame for brand safety before publishing. Flag only what is actually visible:
+- "nudity": nudity or sexually suggestive content
+- "violence": violence, weapons used against people, blood or gore
+- "logo": a clearly visible brand logo or trademark
+
+Rate each flag's severity: "low" (incidental, small or mild), "medium" (noticeable, may need review), "high" (explicit, graphic or prominent).
+
+Respond in this exact JSON format:
+{
+  "flags": [{"category": "nudity|violence|logo", "severity": "low|medium|high", "detail": "short description", "brand": "brand name for logos"}]
+}
+
+Use an empty list when the frame is safe.`
+
+// ContentFlag is something in a frame that needs review
+type ContentFlag struct {
+	Category string `json:"category"` // nudity, violence or logo
+	Severity string `json:"severity"` // low, medium or high
+	Detail   string `json:"detail"`
+	Brand    string `json:"brand,omitempty"`
+}
+
+// FrameModeration lists the flags raised for one sampled frame
+type FrameModeration struct {
+	Timestamp float64       `json:"timestamp"`
+	Flags     []ContentFlag `json:"flags"`
+}
+
+// ModerationScan is the result of screening sampled frames
+type ModerationScan struct {
+	Frames       []FrameModeration `json:"frames"`
+	FailedFrames int               `json:"failedFrames,omitempty"` // Frames the provider could not analyze
+	CachedFrames int               `json:"cachedFrames"`           // Frames answered from the cache
+}
+
+// ModerateFrames screens the frames at timestamps for nudity, violence and
+// brand logos. Frames that fail are counted rather than failing the scan,
+// unless every frame fails.
+func (a *Analyzer) ModerateFrames(ctx context.Context, videoPath string, timestamps []float64) (*ModerationScan, error) {
+	provider, err := a.Provider()
+	if err != nil {
+		return nil, err
+	}
+	if len(timestamps) == 0 {
+		return nil, fmt.Errorf("no frames to analyze")
+	}
+	hash, err := a.cache.fileHash(videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read video: %w", err)
+	}
+
+	results := a.describeFrames(ctx, provider, videoPath, hash, moderationPrompt, timestamps, false)
+	scan := &ModerationScan{}
+	var lastErr error
+	for i, result := range results {
+		if result.err != nil {
+			lastErr = result.err
+			scan.FailedFrames++
+			continue
+		}
+		if result.cached {
+			scan.CachedFrames++
+		}
+		scan.Frames = append(scan.Frames, FrameModeration{Timestamp: timestamps[i], Flags: parseContentFlags(result.description)})
+	}
+	if len(scan.Frames) == 0 {
+		return nil, fmt.Errorf("failed to analyze frames: %w", lastErr)
+	}
+	return scan, nil
+}
+
+// parseContentFlags reads the model's JSON answer, keeping flags with a
+// known category and severity
+func parseContentFlags(answer string) []ContentFlag {
+	var raw struct {
+		Flags []ContentFlag `json:"flags"`
+	}
+	if err := llm.DecodeJSON(answer, &raw); err != nil {
+		return nil
+	}
+
+	var flags []ContentFlag
+	for _, f := range raw.Flags {
+		f.Category = strings.ToLower(strings.TrimSpace(f.Category))
+		f.Severity = strings.ToLower(strings.TrimSpace(f.Severity))
+		if f.Category != "nudity" && f.Category != "violence" && f.Category != "logo" {
+			continue
+		}
+		if f.Severity != "low" && f.Severity != "medium" && f.Severity != "high" {
+			continue
+		}
+		f.Detail, f.Brand = strings.TrimSpace(f.Detail), strings.TrimSpace(f.Brand)
+		flags = append(flags, f)
+	}
+	return flags
+}
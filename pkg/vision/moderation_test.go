@@ -0,0 +1,20 @@
+package vision
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseContentFlags(t *testing.T) {
+	answer := "Here is the review:\n```json\n{\"flags\": [{\"category\": \"Logo\", \"severity\": \"medium\", \"detail\": \"soda can on desk\", \"brand\": \" Coca-Cola \"}, {\"category\": \"violence\", \"severity\": \"none\", \"detail\": \"\"}, {\"category\": \"drugs\", \"severity\": \"high\"}]}\n```"
+	want := []ContentFlag{{Category: "logo", Severity: "medium", Detail: "soda can on desk", Brand: "Coca-Cola"}}
+	if got := parseContentFlags(answer); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseContentFlags() = %+v, want %+v", got, want)
+	}
+	if got := parseContentFlags(`{"flags": []}`); got != nil {
+		t.Errorf("Expected no flags for a safe frame, got %+v", got)
+	}
+	if got := parseContentFlags("The frame looks fine."); got != nil {
+		t.Errorf("Expected no flags for prose, got %+v", got)
+	}
+}
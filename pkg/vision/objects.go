@@ -0,0 +1,77 @@
+package vision
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// objectPromptTemplate asks for every instance of an object as a normalized
+// bounding box
+const objectPromptTemplate = `Find every instance of this in the frame: %s
+
+Give each one's bounding box as fractions of the frame size: x and y are the top-left corner (0 = left/top edge, 1 = right/bottom edge), w and h are the width and height. Only include clear matches.
+
+Respond in this exact JSON format:
+{
+  "boxes": [{"x": 0.0-1.0, "y": 0.0-1.0, "w": 0.0-1.0, "h": 0.0-1.0}]
+}
+
+Use an empty list when it is not visible.`
+
+// VisualSearchRange is a continuous span in which a searched object was seen
+type VisualSearchRange struct {
+	Start      float64 `json:"start"`
+	End        float64 `json:"end"`
+	Matches    int     `json:"matches"`    // Sampled frames that matched within the span
+	Confidence float64 `json:"confidence"` // Highest confidence within the span
+}
+
+// TrackObject finds an object described in plain language in frames sampled
+// every interval seconds (default: 1), or at the given timestamps, and links
+// each instance into a track with a continuous time range and a motion path
+// between samples.
+func (a *Analyzer) TrackObject(ctx context.Context, videoPath, query string, interval float64, timestamps []float64) (*BoxDetectionResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	detection, err := a.detectBoxes(ctx, videoPath, fmt.Sprintf(objectPromptTemplate, query), interval, timestamps)
+	if err != nil {
+		return nil, err
+	}
+	detection.Query = query
+	return detection, nil
+}
+
+// MatchRanges joins matches from adjacent samples into continuous spans.
+// Matches no more than 1.5 intervals apart belong to the same span, and each
+// span reaches half an interval past its first and last match, clamped to
+// duration when it is known.
+func MatchRanges(matches []VisualSearchMatch, interval, duration float64) []VisualSearchRange {
+	if len(matches) == 0 || interval <= 0 {
+		return nil
+	}
+	var ranges []VisualSearchRange
+	var last float64
+	for _, m := range matches {
+		if len(ranges) > 0 && m.Timestamp-last <= 1.5*interval {
+			r := &ranges[len(ranges)-1]
+			r.Matches++
+			r.Confidence = math.Max(r.Confidence, m.Confidence)
+		} else {
+			ranges = append(ranges, VisualSearchRange{Start: m.Timestamp, Matches: 1, Confidence: m.Confidence})
+		}
+		ranges[len(ranges)-1].End = m.Timestamp
+		last = m.Timestamp
+	}
+	for i := range ranges {
+		ranges[i].Start = math.Max(0, ranges[i].Start-interval/2)
+		ranges[i].End += interval / 2
+		if duration > 0 {
+			ranges[i].End = math.Min(duration, ranges[i].End)
+		}
+	}
+	return ranges
+}
@@ -0,0 +1,212 @@
+package vision
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// OCR engines
+const (
+	OCREngineAuto      = "auto"      // Tesseract when installed, otherwise the vision provider
+	OCREngineTesseract = "tesseract" // Local Tesseract OCR, free and offline
+	OCREngineVision    = "vision"    // The configured vision model
+)
+
+// ocrPrompt asks a vision model for a plain transcription
+const ocrPrompt = "Transcribe all text visible in this video frame exactly as written, such as slide text, captions, labels and code, preserving line breaks. Reply with only the text, or NONE if there is no readable text."
+
+// OCROptions contains parameters for extracting on-screen text
+type OCROptions struct {
+	Input      string
+	Interval   float64   // Seconds between sampled frames (default: 5)
+	Timestamps []float64 // Sample these instead of every Interval
+	Engine     string    // auto (default), tesseract or vision
+	Language   string    // Tesseract languages, e.g. eng+deu (default: eng)
+	Query      string    // Keep only text containing every word of the query
+}
+
+// TextSegment is on-screen text that stays the same across consecutive
+// sampled frames, such as one slide
+type TextSegment struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"` // Timestamp of the last frame showing the text
+	Text  string  `json:"text"`
+}
+
+// OnscreenTextResult lists the text found in a video
+type OnscreenTextResult struct {
+	Input         string        `json:"input"`
+	Engine        string        `json:"engine"`
+	FramesScanned int           `json:"framesScanned"`
+	CachedFrames  int           `json:"cachedFrames"`
+	Segments      []TextSegment `json:"segments"`
+}
+
+// tesseractProvider runs Tesseract OCR as a VisionProvider, so OCR shares
+// frame caching and workers with the vision tools. The prompt is ignored.
+type tesseractProvider struct {
+	path     string
+	language string
+}
+
+func (p *tesseractProvider) Name() string {
+	return OCREngineTesseract
+}
+
+func (p *tesseractProvider) Model() string {
+	return p.language
+}
+
+func (p *tesseractProvider) Describe(ctx context.Context, prompt string, images [][]byte, maxTokens int) (string, error) {
+	var texts []string
+	for _, image := range images {
+		cmd := exec.CommandContext(ctx, p.path, "stdin", "stdout", "-l", p.language)
+		cmd.Stdin = bytes.NewReader(image)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("tesseract failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		texts = append(texts, string(output))
+	}
+	return strings.Join(texts, "\n"), nil
+}
+
+// resolveOCREngine picks the engine to use. lookPath is exec.LookPath
+// outside tests; hasProvider reports whether a vision provider is set up.
+func resolveOCREngine(engine string, lookPath func(string) (string, error), hasProvider bool) (string, error) {
+	switch strings.ToLower(engine) {
+	case "", OCREngineAuto:
+		if _, err := lookPath("tesseract"); err == nil {
+			return OCREngineTesseract, nil
+		}
+		if hasProvider {
+			return OCREngineVision, nil
+		}
+		return "", fmt.Errorf("OCR needs tesseract installed or a vision provider configured")
+	case OCREngineTesseract:
+		if _, err := lookPath("tesseract"); err != nil {
+			return "", fmt.Errorf("tesseract is not installed or not on PATH")
+		}
+		return OCREngineTesseract, nil
+	case OCREngineVision:
+		return OCREngineVision, nil
+	default:
+		return "", fmt.Errorf("unknown OCR engine: %s (use auto, tesseract or vision)", engine)
+	}
+}
+
+// ExtractText reads on-screen text from sampled frames. Consecutive frames
+// showing the same text are merged into one segment, so a slide shown for a
+// minute appears once with its start and end.
+func (a *Analyzer) ExtractText(ctx context.Context, opts OCROptions) (*OnscreenTextResult, error) {
+	provider, providerErr := a.Provider()
+	engine, err := resolveOCREngine(opts.Engine, exec.LookPath, providerErr == nil)
+	if err != nil {
+		return nil, err
+	}
+	if engine == OCREngineTesseract {
+		path, _ := exec.LookPath("tesseract")
+		language := opts.Language
+		if language == "" {
+			language = "eng"
+		}
+		provider = &tesseractProvider{path: path, language: language}
+	} else if providerErr != nil {
+		return nil, providerErr
+	}
+
+	timestamps := opts.Timestamps
+	if len(timestamps) == 0 {
+		info, err := a.videoOps.GetVideoInfo(ctx, opts.Input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get video info: %w", err)
+		}
+		interval := opts.Interval
+		if interval <= 0 {
+			interval = 5.0
+		}
+		for t := 0.0; t < info.Duration; t += interval {
+			timestamps = append(timestamps, t)
+		}
+	}
+
+	hash, err := a.cache.fileHash(opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read video: %w", err)
+	}
+
+	results := a.describeFrames(ctx, provider, opts.Input, hash, ocrPrompt, timestamps, true)
+	result := &OnscreenTextResult{Input: opts.Input, Engine: engine, FramesScanned: len(timestamps), Segments: []TextSegment{}}
+	texts := make([]string, len(timestamps))
+	for i := range timestamps {
+		if results[i].err != nil {
+			return nil, fmt.Errorf("failed to read text at %.2fs: %w", timestamps[i], results[i].err)
+		}
+		if results[i].cached {
+			result.CachedFrames++
+		}
+		texts[i] = cleanOCRText(results[i].description)
+	}
+
+	for _, segment := range mergeTextSegments(timestamps, texts) {
+		if matchesTextQuery(segment.Text, opts.Query) {
+			result.Segments = append(result.Segments, segment)
+		}
+	}
+	return result, nil
+}
+
+// cleanOCRText trims blank lines and the model's no-text answer
+func cleanOCRText(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	text = strings.Join(lines, "\n")
+	if strings.EqualFold(strings.Trim(text, ".` "), "none") {
+		return ""
+	}
+	return text
+}
+
+// mergeTextSegments joins consecutive frames with the same text. Frames
+// without text end a segment.
+func mergeTextSegments(timestamps []float64, texts []string) []TextSegment {
+	var segments []TextSegment
+	for i, text := range texts {
+		if text == "" {
+			continue
+		}
+		if n := len(segments); n > 0 && i > 0 && texts[i-1] != "" && sameText(segments[n-1].Text, text) {
+			segments[n-1].End = timestamps[i]
+			continue
+		}
+		segments = append(segments, TextSegment{Start: timestamps[i], End: timestamps[i], Text: text})
+	}
+	return segments
+}
+
+// sameText compares text ignoring case and whitespace, since OCR of the
+// same slide varies slightly between frames
+func sameText(a, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
+
+// matchesTextQuery reports whether text contains every word of query,
+// ignoring case. An empty query matches everything.
+func matchesTextQuery(text, query string) bool {
+	text = strings.ToLower(text)
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
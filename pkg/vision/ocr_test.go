@@ -0,0 +1,82 @@
+package vision
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolveOCREngine(t *testing.T) {
+	installed := func(string) (string, error) { return "/usr/bin/tesseract", nil }
+	missing := func(string) (string, error) { return "", errors.New("not found") }
+
+	tests := []struct {
+		name        string
+		engine      string
+		lookPath    func(string) (string, error)
+		hasProvider bool
+		want        string
+		wantErr     string
+	}{
+		{name: "auto prefers tesseract", lookPath: installed, hasProvider: true, want: OCREngineTesseract},
+		{name: "auto falls back to vision", engine: "auto", lookPath: missing, hasProvider: true, want: OCREngineVision},
+		{name: "auto with nothing set up", lookPath: missing, wantErr: "needs tesseract installed"},
+		{name: "vision requested", engine: "Vision", lookPath: installed, hasProvider: true, want: OCREngineVision},
+		{name: "tesseract missing", engine: "tesseract", lookPath: missing, hasProvider: true, wantErr: "not installed"},
+		{name: "unknown engine", engine: "easyocr", lookPath: installed, wantErr: "unknown OCR engine"},
+	}
+	for _, tt := range tests {
+		got, err := resolveOCREngine(tt.engine, tt.lookPath, tt.hasProvider)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: resolveOCREngine() = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestCleanOCRText(t *testing.T) {
+	if got := cleanOCRText("  Q3 Revenue \n\n\n  Up 12%  \n"); got != "Q3 Revenue\nUp 12%" {
+		t.Errorf("cleanOCRText() = %q", got)
+	}
+	for _, none := range []string{"NONE", "None.", "`none`", "\n"} {
+		if got := cleanOCRText(none); got != "" {
+			t.Errorf("cleanOCRText(%q) = %q, want empty", none, got)
+		}
+	}
+}
+
+func TestMergeTextSegments(t *testing.T) {
+	timestamps := []float64{0, 5, 10, 15, 20, 25}
+	texts := []string{"Agenda", "AGENDA ", "Q3 revenue\nUp 12%", "", "Q3 revenue\nUp 12%", "Thanks"}
+
+	segments := mergeTextSegments(timestamps, texts)
+	want := []TextSegment{
+		{Start: 0, End: 5, Text: "Agenda"},
+		{Start: 10, End: 10, Text: "Q3 revenue\nUp 12%"},
+		{Start: 20, End: 20, Text: "Q3 revenue\nUp 12%"},
+		{Start: 25, End: 25, Text: "Thanks"},
+	}
+	if len(segments) != len(want) {
+		t.Fatalf("mergeTextSegments() = %+v, want %+v", segments, want)
+	}
+	for i := range want {
+		if segments[i] != want[i] {
+			t.Errorf("segments[%d] = %+v, want %+v", i, segments[i], want[i])
+		}
+	}
+}
+
+func TestMatchesTextQuery(t *testing.T) {
+	text := "Quarterly results\nQ3 Revenue up 12%"
+	if !matchesTextQuery(text, "q3 REVENUE") || !matchesTextQuery(text, "") {
+		t.Error("Expected a case-insensitive match on every word")
+	}
+	if matchesTextQuery(text, "Q4 revenue") {
+		t.Error("Expected every query word to be required")
+	}
+}
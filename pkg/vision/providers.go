@@ -0,0 +1,374 @@
+package vision
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/apiclient"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/usage"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Vision providers
+const (
+	ProviderOpenAI = "openai"
+	ProviderClaude = "claude"
+	ProviderGemini = "gemini"
+	ProviderOllama = "ollama" // Local models such as LLaVA
+)
+
+// Providers lists the supported vision providers
+var Providers = []string{ProviderOpenAI, ProviderClaude, ProviderGemini, ProviderOllama}
+
+// defaultModels is the model each provider uses when none is configured
+var defaultModels = map[string]string{
+	ProviderOpenAI: openai.GPT4o,
+	ProviderClaude: "claude-sonnet-4-5",
+	ProviderGemini: "gemini-2.5-flash",
+	ProviderOllama: "llava",
+}
+
+// DefaultOllamaURL is the address of a local Ollama server
+const DefaultOllamaURL = "http://localhost:11434"
+
+// VisionProvider answers a prompt about JPEG images. With no images it is
+// a plain text completion.
+type VisionProvider interface {
+	Name() string
+	Model() string
+	Describe(ctx context.Context, prompt string, images [][]byte, maxTokens int) (string, error)
+}
+
+// openAIProvider uses GPT-4 class models with image input
+type openAIProvider struct {
+	client *openai.Client
+	model  string
+}
+
+func (p *openAIProvider) Name() string {
+	return ProviderOpenAI
+}
+
+func (p *openAIProvider) Model() string {
+	return p.model
+}
+
+func (p *openAIProvider) Describe(ctx context.Context, prompt string, images [][]byte, maxTokens int) (string, error) {
+	message := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser}
+	if len(images) == 0 {
+		message.Content = prompt
+	} else {
+		message.MultiContent = []openai.ChatMessagePart{{Type: openai.ChatMessagePartTypeText, Text: prompt}}
+		for _, image := range images {
+			message.MultiContent = append(message.MultiContent, openai.ChatMessagePart{
+				Type:     openai.ChatMessagePartTypeImageURL,
+				ImageURL: &openai.ChatMessageImageURL{URL: "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(image)},
+			})
+		}
+	}
+
+	resp, err := p.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model:     p.model,
+		Messages:  []openai.ChatCompletionMessage{message},
+		MaxTokens: maxTokens,
+	})
+	if err != nil {
+		return "", err
+	}
+	usage.Record(usage.Entry{
+		Provider:     usage.ProviderOpenAI,
+		Feature:      usage.FeatureVision,
+		Model:        p.model,
+		InputTokens:  resp.Usage.PromptTokens,
+		OutputTokens: resp.Usage.CompletionTokens,
+	})
+	if len(resp.Choices) == 0 {
+		return "", nil
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// claudeProvider uses Anthropic models with image input
+type claudeProvider struct {
+	client anthropic.Client
+	model  string
+}
+
+func (p *claudeProvider) Name() string {
+	return ProviderClaude
+}
+
+func (p *claudeProvider) Model() string {
+	return p.model
+}
+
+func (p *claudeProvider) Describe(ctx context.Context, prompt string, images [][]byte, maxTokens int) (string, error) {
+	var blocks []anthropic.ContentBlockParamUnion
+	for _, image := range images {
+		blocks = append(blocks, anthropic.NewImageBlockBase64("image/jpeg", base64.StdEncoding.EncodeToString(image)))
+	}
+	blocks = append(blocks, anthropic.NewTextBlock(prompt))
+
+	resp, err := p.client.Messages.New(ctx, anthropic.MessageNewParams{
+		Model:     anthropic.Model(p.model),
+		MaxTokens: int64(maxTokens),
+		Messages:  []anthropic.MessageParam{anthropic.NewUserMessage(blocks...)},
+	})
+	if err != nil {
+		return "", err
+	}
+	usage.Record(usage.Entry{
+		Provider:     usage.ProviderAnthropic,
+		Feature:      usage.FeatureVision,
+		Model:        p.model,
+		InputTokens:  int(resp.Usage.InputTokens),
+		OutputTokens: int(resp.Usage.OutputTokens),
+	})
+	var out strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			out.WriteString(block.Text)
+		}
+	}
+	return out.String(), nil
+}
+
+// geminiProvider calls the Gemini generateContent REST API
+type geminiProvider struct {
+	client  *http.Client
+	apiKey  string
+	model   string
+	baseURL string
+}
+
+func (p *geminiProvider) Name() string {
+	return ProviderGemini
+}
+
+func (p *geminiProvider) Model() string {
+	return p.model
+}
+
+func (p *geminiProvider) Describe(ctx context.Context, prompt string, images [][]byte, maxTokens int) (string, error) {
+	type inlineData struct {
+		MimeType string `json:"mime_type"`
+		Data     string `json:"data"`
+	}
+	type part struct {
+		Text       string      `json:"text,omitempty"`
+		InlineData *inlineData `json:"inline_data,omitempty"`
+	}
+	parts := []part{{Text: prompt}}
+	for _, image := range images {
+		parts = append(parts, part{InlineData: &inlineData{MimeType: "image/jpeg", Data: base64.StdEncoding.EncodeToString(image)}})
+	}
+	// Thinking models spend output tokens on reasoning, so maxTokens is
+	// not sent as a cap
+	body := map[string]interface{}{
+		"contents": []map[string]interface{}{{"parts": parts}},
+	}
+
+	var resp struct {
+		Candidates []struct {
+			Content struct {
+				Parts []part `json:"parts"`
+			} `json:"content"`
+		} `json:"candidates"`
+		UsageMetadata struct {
+			PromptTokenCount     int `json:"promptTokenCount"`
+			CandidatesTokenCount int `json:"candidatesTokenCount"`
+		} `json:"usageMetadata"`
+	}
+	url := fmt.Sprintf("%s/v1beta/models/%s:generateContent", p.baseURL, p.model)
+	if err := postJSON(ctx, p.client, url, map[string]string{"x-goog-api-key": p.apiKey}, body, &resp); err != nil {
+		return "", err
+	}
+	usage.Record(usage.Entry{
+		Provider:     usage.ProviderGemini,
+		Feature:      usage.FeatureVision,
+		Model:        p.model,
+		InputTokens:  resp.UsageMetadata.PromptTokenCount,
+		OutputTokens: resp.UsageMetadata.CandidatesTokenCount,
+	})
+	if len(resp.Candidates) == 0 {
+		return "", nil
+	}
+	var out strings.Builder
+	for _, p := range resp.Candidates[0].Content.Parts {
+		out.WriteString(p.Text)
+	}
+	return out.String(), nil
+}
+
+// ollamaProvider calls a local Ollama server, for offline models such as
+// LLaVA
+type ollamaProvider struct {
+	client  *http.Client
+	baseURL string
+	model   string
+}
+
+func (p *ollamaProvider) Name() string {
+	return ProviderOllama
+}
+
+func (p *ollamaProvider) Model() string {
+	return p.model
+}
+
+func (p *ollamaProvider) Describe(ctx context.Context, prompt string, images [][]byte, maxTokens int) (string, error) {
+	message := map[string]interface{}{"role": "user", "content": prompt}
+	if len(images) > 0 {
+		encoded := make([]string, len(images))
+		for i, image := range images {
+			encoded[i] = base64.StdEncoding.EncodeToString(image)
+		}
+		message["images"] = encoded
+	}
+	body := map[string]interface{}{
+		"model":    p.model,
+		"messages": []interface{}{message},
+		"stream":   false,
+		"options":  map[string]interface{}{"num_predict": maxTokens},
+	}
+
+	var resp struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		PromptEvalCount int `json:"prompt_eval_count"`
+		EvalCount       int `json:"eval_count"`
+	}
+	if err := postJSON(ctx, p.client, strings.TrimRight(p.baseURL, "/")+"/api/chat", nil, body, &resp); err != nil {
+		return "", fmt.Errorf("%w (is Ollama running at %s with %s pulled?)", err, p.baseURL, p.model)
+	}
+	usage.Record(usage.Entry{
+		Provider:     usage.ProviderOllama,
+		Feature:      usage.FeatureVision,
+		Model:        p.model,
+		InputTokens:  resp.PromptEvalCount,
+		OutputTokens: resp.EvalCount,
+	})
+	return resp.Message.Content, nil
+}
+
+// postJSON sends body as JSON and decodes a JSON response into out
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(respData)))
+	}
+	return json.Unmarshal(respData, out)
+}
+
+// ProviderSettings selects and configures a vision provider
+type ProviderSettings struct {
+	Provider     string // openai, claude, gemini or ollama (default: first with an API key)
+	Model        string // Default depends on the provider
+	OpenAIKey    string
+	ClaudeAPIKey string
+	GeminiAPIKey string
+	OllamaURL    string
+}
+
+// resolveProvider picks the provider to use. Without one configured it
+// takes the first hosted provider with a key; Ollama is never chosen
+// implicitly because its availability cannot be checked without a call.
+func resolveProvider(settings ProviderSettings) (string, error) {
+	name := strings.ToLower(settings.Provider)
+	switch name {
+	case ProviderOpenAI:
+		if settings.OpenAIKey == "" {
+			return "", fmt.Errorf("OpenAI API key not configured. Set OPENAI_API_KEY environment variable or update config")
+		}
+	case ProviderClaude:
+		if settings.ClaudeAPIKey == "" {
+			return "", fmt.Errorf("Claude API key not configured. Set CLAUDE_API_KEY environment variable or update config")
+		}
+	case ProviderGemini:
+		if settings.GeminiAPIKey == "" {
+			return "", fmt.Errorf("Gemini API key not configured. Set GEMINI_API_KEY environment variable or update config")
+		}
+	case ProviderOllama:
+	case "":
+		switch {
+		case settings.OpenAIKey != "":
+			return ProviderOpenAI, nil
+		case settings.ClaudeAPIKey != "":
+			return ProviderClaude, nil
+		case settings.GeminiAPIKey != "":
+			return ProviderGemini, nil
+		}
+		return "", fmt.Errorf("no vision provider available. Set an OpenAI, Claude or Gemini API key, or set visionProvider to ollama for a local model")
+	default:
+		return "", fmt.Errorf("unknown vision provider: %s (use %s)", name, strings.Join(Providers, ", "))
+	}
+	return name, nil
+}
+
+// NewProvider creates the vision provider the settings select
+func NewProvider(settings ProviderSettings) (VisionProvider, error) {
+	name, err := resolveProvider(settings)
+	if err != nil {
+		return nil, err
+	}
+	model := settings.Model
+	if model == "" {
+		model = defaultModels[name]
+	}
+
+	switch name {
+	case ProviderOpenAI:
+		client := openai.NewClientWithConfig(apiclient.OpenAIConfig(settings.OpenAIKey, apiclient.ProviderVision))
+		return &openAIProvider{client: client, model: model}, nil
+	case ProviderClaude:
+		client := anthropic.NewClient(
+			option.WithAPIKey(settings.ClaudeAPIKey),
+			option.WithHTTPClient(apiclient.HTTPClient(apiclient.ProviderVision)),
+			option.WithMaxRetries(0), // Retries come from apiclient
+		)
+		return &claudeProvider{client: client, model: model}, nil
+	case ProviderGemini:
+		return &geminiProvider{
+			client:  apiclient.HTTPClient(apiclient.ProviderVision),
+			apiKey:  settings.GeminiAPIKey,
+			model:   model,
+			baseURL: "https://generativelanguage.googleapis.com",
+		}, nil
+	default:
+		baseURL := settings.OllamaURL
+		if baseURL == "" {
+			baseURL = DefaultOllamaURL
+		}
+		return &ollamaProvider{client: http.DefaultClient, baseURL: baseURL, model: model}, nil
+	}
+}
@@ -0,0 +1,95 @@
+package vision
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResolveProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings ProviderSettings
+		want     string
+		wantErr  string
+	}{
+		{name: "OpenAI preferred when set up", settings: ProviderSettings{OpenAIKey: "sk", ClaudeAPIKey: "ck"}, want: ProviderOpenAI},
+		{name: "Claude without OpenAI", settings: ProviderSettings{ClaudeAPIKey: "ck", GeminiAPIKey: "gk"}, want: ProviderClaude},
+		{name: "Gemini last", settings: ProviderSettings{GeminiAPIKey: "gk"}, want: ProviderGemini},
+		{name: "nothing set up", wantErr: "no vision provider available"},
+		{name: "config overrides the fallback order", settings: ProviderSettings{Provider: "Gemini", OpenAIKey: "sk", GeminiAPIKey: "gk"}, want: ProviderGemini},
+		{name: "Ollama needs no key", settings: ProviderSettings{Provider: "ollama"}, want: ProviderOllama},
+		{name: "configured provider needs its key", settings: ProviderSettings{Provider: "claude", OpenAIKey: "sk"}, wantErr: "Claude API key not configured"},
+		{name: "unknown provider", settings: ProviderSettings{Provider: "bard"}, wantErr: "unknown vision provider"},
+	}
+	for _, tt := range tests {
+		got, err := resolveProvider(tt.settings)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: resolveProvider() = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestGeminiProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1beta/models/gemini-2.5-flash:generateContent" || r.Header.Get("x-goog-api-key") != "gk" {
+			t.Errorf("Unexpected request %s with key %q", r.URL.Path, r.Header.Get("x-goog-api-key"))
+		}
+		var body struct {
+			Contents []struct {
+				Parts []map[string]interface{} `json:"parts"`
+			} `json:"contents"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Contents) != 1 || len(body.Contents[0].Parts) != 3 || body.Contents[0].Parts[0]["text"] != "compare" {
+			t.Errorf("Expected the prompt and two images, got %+v", body)
+		}
+		w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"A cat "},{"text":"appears."}]}}],"usageMetadata":{"promptTokenCount":500,"candidatesTokenCount":4}}`))
+	}))
+	defer server.Close()
+
+	provider := &geminiProvider{client: server.Client(), apiKey: "gk", model: "gemini-2.5-flash", baseURL: server.URL}
+	got, err := provider.Describe(context.Background(), "compare", [][]byte{{0xff}, {0xd8}}, 500)
+	if err != nil || got != "A cat appears." {
+		t.Errorf("Describe() = %q, %v", got, err)
+	}
+}
+
+func TestOllamaProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Model    string `json:"model"`
+			Stream   bool   `json:"stream"`
+			Messages []struct {
+				Content string   `json:"content"`
+				Images  []string `json:"images"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/api/chat" || body.Model != "llava" || body.Stream || len(body.Messages) != 1 || len(body.Messages[0].Images) != 1 {
+			t.Errorf("Unexpected request to %s: %+v", r.URL.Path, body)
+		}
+		w.Write([]byte(`{"message":{"role":"assistant","content":"A whiteboard."},"prompt_eval_count":600,"eval_count":5}`))
+	}))
+	defer server.Close()
+
+	provider := &ollamaProvider{client: server.Client(), baseURL: server.URL + "/", model: "llava"}
+	got, err := provider.Describe(context.Background(), "describe", [][]byte{{0xff}}, 500)
+	if err != nil || got != "A whiteboard." {
+		t.Errorf("Describe() = %q, %v", got, err)
+	}
+
+	server.Close()
+	if _, err := provider.Describe(context.Background(), "describe", nil, 10); err == nil || !strings.Contains(err.Error(), "is Ollama running") {
+		t.Errorf("Expected a hint when Ollama is down, got %v", err)
+	}
+}
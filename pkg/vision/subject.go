@@ -0,0 +1,94 @@
+package vision
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/llm"
+)
+
+// subjectPrompt asks for the horizontal extent of the main subject. Only
+// horizontal positions are needed to steer a crop from landscape to portrait.
+const subjectPrompt = `Locate the main subject of this frame: the person speaking or, if nobody is speaking, the most prominent person. If there is no person, use the most important object.
+
+Give horizontal positions as fractions of the frame width, from 0 (left edge) to 1 (right edge).
+
+Respond in this exact JSON format:
+{
+  "found": true/false,
+  "face": 0.0-1.0,
+  "left": 0.0-1.0,
+  "right": 0.0-1.0
+}
+
+"face" is the center of the person's face (or the object's center), "left" and "right" are the edges of their head and shoulders.`
+
+// SubjectPosition is where the main subject is in one frame, as fractions
+// of the frame width
+type SubjectPosition struct {
+	Timestamp float64 `json:"timestamp"`
+	Found     bool    `json:"found"`
+	Face      float64 `json:"face"`  // Center of the face or object
+	Left      float64 `json:"left"`  // Left edge of head and shoulders
+	Right     float64 `json:"right"` // Right edge of head and shoulders
+}
+
+// LocateSubject finds the main subject in the frames at timestamps, in
+// order. Frames that fail or show no subject are returned with Found unset;
+// an error is returned only when every frame fails.
+func (a *Analyzer) LocateSubject(ctx context.Context, videoPath string, timestamps []float64) ([]SubjectPosition, error) {
+	provider, err := a.Provider()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := a.cache.fileHash(videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read video: %w", err)
+	}
+
+	results := a.describeFrames(ctx, provider, videoPath, hash, subjectPrompt, timestamps, false)
+	positions := make([]SubjectPosition, len(timestamps))
+	var lastErr error
+	failed := 0
+	for i, result := range results {
+		positions[i].Timestamp = timestamps[i]
+		if result.err != nil {
+			lastErr = result.err
+			failed++
+			continue
+		}
+		if position, ok := parseSubjectPosition(result.description); ok {
+			position.Timestamp = timestamps[i]
+			positions[i] = position
+		}
+	}
+	if failed > 0 && failed == len(results) {
+		return nil, fmt.Errorf("failed to analyze frames: %w", lastErr)
+	}
+	return positions, nil
+}
+
+// parseSubjectPosition reads the model's JSON answer. Positions are clamped
+// to the frame and the edges default to the face when missing.
+func parseSubjectPosition(answer string) (SubjectPosition, bool) {
+	var raw struct {
+		Found bool     `json:"found"`
+		Face  *float64 `json:"face"`
+		Left  *float64 `json:"left"`
+		Right *float64 `json:"right"`
+	}
+	if err := llm.DecodeJSON(answer, &raw); err != nil || !raw.Found || raw.Face == nil {
+		return SubjectPosition{}, false
+	}
+
+	clamp := func(v float64) float64 { return math.Max(0, math.Min(1, v)) }
+	position := SubjectPosition{Found: true, Face: clamp(*raw.Face), Left: clamp(*raw.Face), Right: clamp(*raw.Face)}
+	if raw.Left != nil {
+		position.Left = math.Min(clamp(*raw.Left), position.Face)
+	}
+	if raw.Right != nil {
+		position.Right = math.Max(clamp(*raw.Right), position.Face)
+	}
+	return position, true
+}
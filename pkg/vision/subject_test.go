@@ -0,0 +1,25 @@
+package vision
+
+import "testing"
+
+func TestParseSubjectPosition(t *testing.T) {
+	tests := []struct {
+		answer string
+		want   SubjectPosition
+		ok     bool
+	}{
+		{"```json\n{\"found\": true, \"face\": 0.62, \"left\": 0.5, \"right\": 0.75}\n```", SubjectPosition{Found: true, Face: 0.62, Left: 0.5, Right: 0.75}, true},
+		// Out-of-frame values are clamped and edges never cross the face
+		{`{"found": true, "face": 1.2, "left": 1.3, "right": 1.5}`, SubjectPosition{Found: true, Face: 1, Left: 1, Right: 1}, true},
+		{`{"found": true, "face": 0.3}`, SubjectPosition{Found: true, Face: 0.3, Left: 0.3, Right: 0.3}, true},
+		{`{"found": false, "face": 0.5}`, SubjectPosition{}, false},
+		{`{"found": true}`, SubjectPosition{}, false},
+		{"No person is visible.", SubjectPosition{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseSubjectPosition(tt.answer)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseSubjectPosition(%q) = %+v, %v; want %+v, %v", tt.answer, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package vision
+
+import (
+	"math"
+	"sort"
+)
+
+// maxTrackKeyframes caps keyframes per track to keep filter expressions small
+const maxTrackKeyframes = 120
+
+// Box is a region of a frame as fractions of its width and height, measured
+// from the top-left corner
+type Box struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	W float64 `json:"w"`
+	H float64 `json:"h"`
+}
+
+func (b Box) centerX() float64 { return b.X + b.W/2 }
+func (b Box) centerY() float64 { return b.Y + b.H/2 }
+
+// FrameBoxes lists the boxes found in one sampled frame
+type FrameBoxes struct {
+	Timestamp float64 `json:"timestamp"`
+	Boxes     []Box   `json:"boxes"`
+}
+
+// BoxKeyframe is a tracked box at a sampled time
+type BoxKeyframe struct {
+	Time float64 `json:"time"`
+	Box
+}
+
+// BoxTrack follows one subject across sampled frames. Between keyframes the
+// box moves linearly; Start and End extend past the first and last sample
+// so the subject stays covered between samples.
+type BoxTrack struct {
+	ID        int           `json:"id"`
+	Start     float64       `json:"start"`
+	End       float64       `json:"end"`
+	Keyframes []BoxKeyframe `json:"keyframes"`
+}
+
+// LinkTracks joins boxes in consecutive frames into tracks. A box continues
+// the track whose last box is nearest, when that box was seen within maxGap
+// seconds and is no further away than its own size; otherwise it starts a
+// new track. Tracks are extended by margin seconds on each side, clamped to
+// duration when it is known.
+func LinkTracks(frames []FrameBoxes, maxGap, margin, duration float64) []BoxTrack {
+	sorted := append([]FrameBoxes(nil), frames...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp < sorted[j].Timestamp })
+
+	var tracks []BoxTrack
+	for _, frame := range sorted {
+		type pair struct {
+			track, box int
+			distance   float64
+		}
+		var pairs []pair
+		for ti, track := range tracks {
+			last := track.Keyframes[len(track.Keyframes)-1]
+			if frame.Timestamp-last.Time > maxGap {
+				continue
+			}
+			for bi, box := range frame.Boxes {
+				if d := boxDistance(last.Box, box); d <= 1 {
+					pairs = append(pairs, pair{ti, bi, d})
+				}
+			}
+		}
+		sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].distance < pairs[j].distance })
+
+		usedTrack := map[int]bool{}
+		usedBox := map[int]bool{}
+		for _, p := range pairs {
+			if usedTrack[p.track] || usedBox[p.box] {
+				continue
+			}
+			usedTrack[p.track], usedBox[p.box] = true, true
+			tracks[p.track].Keyframes = append(tracks[p.track].Keyframes, BoxKeyframe{Time: frame.Timestamp, Box: frame.Boxes[p.box]})
+		}
+		for bi, box := range frame.Boxes {
+			if !usedBox[bi] {
+				tracks = append(tracks, BoxTrack{ID: len(tracks) + 1, Keyframes: []BoxKeyframe{{Time: frame.Timestamp, Box: box}}})
+			}
+		}
+	}
+
+	for i := range tracks {
+		keyframes := tracks[i].Keyframes
+		tracks[i].Start = math.Max(0, keyframes[0].Time-margin)
+		tracks[i].End = keyframes[len(keyframes)-1].Time + margin
+		if duration > 0 {
+			tracks[i].End = math.Min(duration, tracks[i].End)
+		}
+		tracks[i].Keyframes = thinKeyframes(keyframes, maxTrackKeyframes)
+	}
+	return tracks
+}
+
+// boxDistance is the distance between box centers relative to the boxes'
+// average size, so 1 means one box-width apart
+func boxDistance(a, b Box) float64 {
+	size := math.Max((a.W+b.W)/2, (a.H+b.H)/2)
+	if size <= 0 {
+		return math.Inf(1)
+	}
+	return math.Hypot(a.centerX()-b.centerX(), a.centerY()-b.centerY()) / size
+}
+
+// thinKeyframes keeps evenly spaced keyframes, always including the ends
+func thinKeyframes(keyframes []BoxKeyframe, limit int) []BoxKeyframe {
+	if len(keyframes) <= limit {
+		return keyframes
+	}
+	step := float64(len(keyframes)-1) / float64(limit-1)
+	thinned := make([]BoxKeyframe, 0, limit)
+	for i := 0; i < limit; i++ {
+		thinned = append(thinned, keyframes[int(math.Round(float64(i)*step))])
+	}
+	return thinned
+}
@@ -0,0 +1,97 @@
+package vision
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLinkTracks(t *testing.T) {
+	left := func(x float64) Box { return Box{X: x, Y: 0.2, W: 0.1, H: 0.15} }
+	frames := []FrameBoxes{
+		{Timestamp: 1, Boxes: []Box{left(0.12), {X: 0.7, Y: 0.3, W: 0.1, H: 0.15}}},
+		{Timestamp: 0, Boxes: []Box{left(0.1)}},
+		// The right face is missed once but stays within the gap
+		{Timestamp: 2, Boxes: []Box{left(0.15)}},
+		{Timestamp: 3, Boxes: []Box{{X: 0.72, Y: 0.3, W: 0.1, H: 0.15}, left(0.17)}},
+		// Too far from any track to continue one
+		{Timestamp: 4, Boxes: []Box{{X: 0.4, Y: 0.7, W: 0.05, H: 0.08}}},
+	}
+
+	tracks := LinkTracks(frames, 3, 1, 4.5)
+	if len(tracks) != 3 {
+		t.Fatalf("LinkTracks() = %d tracks, want 3: %+v", len(tracks), tracks)
+	}
+
+	var times []float64
+	for _, k := range tracks[0].Keyframes {
+		times = append(times, k.Time)
+	}
+	if !reflect.DeepEqual(times, []float64{0, 1, 2, 3}) || tracks[0].Start != 0 || tracks[0].End != 4 {
+		t.Errorf("Expected the left face tracked 0-3 and extended to 4, got %+v", tracks[0])
+	}
+	if len(tracks[1].Keyframes) != 2 || tracks[1].Start != 0 || tracks[1].End != 4 {
+		t.Errorf("Expected the right face linked across the missed frame, got %+v", tracks[1])
+	}
+	if tracks[2].ID != 3 || tracks[2].Start != 3 || tracks[2].End != 4.5 {
+		t.Errorf("Expected a new track clamped to the duration, got %+v", tracks[2])
+	}
+
+	// Past the gap the same position starts a new track
+	if got := LinkTracks([]FrameBoxes{{Timestamp: 0, Boxes: []Box{left(0.1)}}, {Timestamp: 10, Boxes: []Box{left(0.1)}}}, 3, 0, 0); len(got) != 2 {
+		t.Errorf("Expected 2 tracks across a long gap, got %d", len(got))
+	}
+}
+
+func TestThinKeyframes(t *testing.T) {
+	var keyframes []BoxKeyframe
+	for i := 0; i < 10; i++ {
+		keyframes = append(keyframes, BoxKeyframe{Time: float64(i)})
+	}
+	thinned := thinKeyframes(keyframes, 4)
+	if len(thinned) != 4 || thinned[0].Time != 0 || thinned[3].Time != 9 {
+		t.Errorf("thinKeyframes() = %+v", thinned)
+	}
+}
+
+func TestParseBoxes(t *testing.T) {
+	answer := "Found two faces:\n```json\n{\"boxes\": [{\"x\": 0.1, \"y\": 0.2, \"w\": 0.1, \"h\": 0.15}, {\"x\": 0.95, \"y\": -0.05, \"w\": 0.1, \"h\": 0.2}, {\"x\": 0.5, \"y\": 0.5, \"w\": 0, \"h\": 0.1}]}\n```"
+	boxes := parseBoxes(answer)
+	if len(boxes) != 2 {
+		t.Fatalf("parseBoxes() = %+v, want 2 boxes", boxes)
+	}
+	if boxes[0] != (Box{X: 0.1, Y: 0.2, W: 0.1, H: 0.15}) {
+		t.Errorf("boxes[0] = %+v", boxes[0])
+	}
+	// Clipped to the frame
+	if b := boxes[1]; b.Y != 0 || b.X != 0.95 || b.W < 0.0499 || b.W > 0.0501 || b.H < 0.1499 || b.H > 0.1501 {
+		t.Errorf("boxes[1] = %+v", b)
+	}
+	if got := parseBoxes(`{"boxes": []}`); got != nil {
+		t.Errorf("Expected no boxes, got %+v", got)
+	}
+	if got := parseBoxes("I can't see any faces."); got != nil {
+		t.Errorf("Expected no boxes for prose, got %+v", got)
+	}
+}
+
+func TestMatchRanges(t *testing.T) {
+	matches := []VisualSearchMatch{
+		{Timestamp: 0, Confidence: 0.6},
+		{Timestamp: 5, Confidence: 0.9},
+		// Too far from the previous match to share its span
+		{Timestamp: 20, Confidence: 0.7},
+		{Timestamp: 58, Confidence: 0.8},
+	}
+	got := MatchRanges(matches, 5, 60)
+	want := []VisualSearchRange{
+		{Start: 0, End: 7.5, Matches: 2, Confidence: 0.9},
+		{Start: 17.5, End: 22.5, Matches: 1, Confidence: 0.7},
+		{Start: 55.5, End: 60, Matches: 1, Confidence: 0.8},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MatchRanges() = %+v, want %+v", got, want)
+	}
+	if got := MatchRanges(nil, 5, 60); got != nil {
+		t.Errorf("Expected no ranges without matches, got %+v", got)
+	}
+}
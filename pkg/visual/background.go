@@ -0,0 +1,174 @@
+package visual
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+)
+
+// ReplaceBackgroundOptions contains options for green-screen background replacement
+type ReplaceBackgroundOptions struct {
+	Input          string // Foreground (green screen) video
+	Background     string // Background image or video
+	Output         string
+	KeyColor       string  // Color to key out (default: 0x00FF00)
+	Similarity     float64 // 0-1 (default: 0.3)
+	Blend          float64 // 0-1 (default: 0.1)
+	Fit            string  // Background scaling: "cover" (default), "contain", "stretch"
+	BackgroundBlur float64 // Blur radius applied to the background (0 = none)
+	LightWrap      float64 // 0-1, how much background light bleeds over subject edges (0 = none)
+	Despill        bool    // Remove key color spill from the subject
+	Choke          int     // Shrink the matte edge by this many pixels, 0-10 (default: 0)
+	Feather        float64 // Soften the matte edge, blur sigma in pixels (default: 0)
+}
+
+// ReplaceBackground keys the subject out of a green screen recording and
+// composites it over a new image or video background in a single render
+func (e *Effects) ReplaceBackground(ctx context.Context, opts ReplaceBackgroundOptions) error {
+	width, height, err := e.ffmpeg.ProbeDimensions(ctx, opts.Input)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"-i", opts.Input}
+	if isImageFile(opts.Background) {
+		args = append(args, "-loop", "1", "-i", opts.Background)
+	} else {
+		// Loop short background clips to cover the full foreground
+		args = append(args, "-stream_loop", "-1", "-i", opts.Background)
+	}
+
+	args = append(args,
+		"-filter_complex", buildReplaceBackgroundFilter(opts, width, height),
+		"-map", "[out]",
+		"-map", "0:a?",
+		"-c:a", "copy",
+		"-shortest",
+		"-y", opts.Output,
+	)
+
+	return e.ffmpeg.Execute(ctx, args...)
+}
+
+// buildReplaceBackgroundFilter builds the key, background and light wrap graph.
+// Input 0 is the foreground and input 1 is the background.
+func buildReplaceBackgroundFilter(opts ReplaceBackgroundOptions, width, height int) string {
+	keyColor := opts.KeyColor
+	if keyColor == "" {
+		keyColor = "0x00FF00"
+	}
+	similarity := opts.Similarity
+	if similarity == 0 {
+		similarity = 0.3
+	}
+	blend := opts.Blend
+	if blend == 0 {
+		blend = 0.1
+	}
+
+	var parts []string
+
+	// Foreground: key out the screen color
+	parts = append(parts, "[0:v]"+buildKeyFilter(keyColor, similarity, blend, opts.Despill, opts.Choke, opts.Feather)+"[fg]")
+
+	// Background: fit to the foreground frame
+	var bg string
+	switch opts.Fit {
+	case "contain":
+		bg = fmt.Sprintf("[1:v]scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2",
+			width, height, width, height)
+	case "stretch":
+		bg = fmt.Sprintf("[1:v]scale=%d:%d", width, height)
+	default:
+		bg = fmt.Sprintf("[1:v]scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d",
+			width, height, width, height)
+	}
+	bg += ",setsar=1"
+	if opts.BackgroundBlur > 0 {
+		bg += fmt.Sprintf(",boxblur=%.0f:1", opts.BackgroundBlur)
+	}
+
+	if opts.LightWrap <= 0 {
+		parts = append(parts, bg+"[bg]", "[bg][fg]overlay=shortest=1,format=yuv420p[out]")
+		return strings.Join(parts, ";")
+	}
+
+	// Light wrap: blend a blurred copy of the background over a thin band
+	// just inside the subject's edge so it sits naturally in the new scene
+	strength := opts.LightWrap
+	if strength > 1 {
+		strength = 1
+	}
+	parts = append(parts,
+		bg+",split[bg][bgwrap]",
+		"[fg]split[fgmain][fgedge]",
+		"[fgedge]alphaextract,split[alpha][alphablur]",
+		"[alphablur]boxblur=8:1,negate[outside]",
+		fmt.Sprintf("[alpha][outside]blend=all_mode=multiply,lut=y=val*%.2f[edge]", strength*2),
+		"[bgwrap]boxblur=20:1,format=yuv420p[bgblur]",
+		"[bgblur][edge]alphamerge[wrap]",
+		"[bg][fgmain]overlay=shortest=1[comp]",
+		"[comp][wrap]overlay=format=auto,format=yuv420p[out]",
+	)
+	return strings.Join(parts, ";")
+}
+
+// maxChoke caps matte choking; each pixel is one erosion pass
+const maxChoke = 10
+
+// buildKeyFilter keys out keyColor and refines the matte: despill removes
+// the screen color reflected onto the subject, choke erodes the alpha edge
+// to drop fringes, and feather blurs only the alpha plane so the edge
+// blends instead of stair-stepping. The result is yuva420p.
+func buildKeyFilter(keyColor string, similarity, blend float64, despill bool, choke int, feather float64) string {
+	filter := fmt.Sprintf("chromakey=color=%s:similarity=%.2f:blend=%.2f", keyColor, similarity, blend)
+	if despill {
+		despillType := "green"
+		if strings.EqualFold(keyColor, "blue") || strings.EqualFold(keyColor, "0x0000FF") {
+			despillType = "blue"
+		}
+		filter += ",despill=type=" + despillType
+	}
+	filter += ",format=yuva420p"
+	// Zero thresholds leave the Y, U and V planes untouched
+	for i := 0; i < min(choke, maxChoke); i++ {
+		filter += ",erosion=threshold0=0:threshold1=0:threshold2=0"
+	}
+	if feather > 0 {
+		filter += fmt.Sprintf(",gblur=sigma=%.1f:planes=8", feather)
+	}
+	return filter
+}
+
+// probeFrameRate returns the frame rate of a file's first video stream as
+// FFmpeg's fraction, or 30 when the file doesn't say
+func probeFrameRate(ctx context.Context, mgr *ffmpeg.Manager, input string) (string, error) {
+	output, err := mgr.Probe(ctx,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=r_frame_rate",
+		"-of", "csv=p=0",
+		input,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to probe frame rate: %w", err)
+	}
+
+	fps := strings.TrimSpace(output)
+	if fps == "" || strings.HasPrefix(fps, "0/") {
+		fps = "30"
+	}
+	return fps, nil
+}
+
+// isImageFile reports whether a path looks like a still image
+func isImageFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg", ".png", ".bmp", ".webp", ".tif", ".tiff":
+		return true
+	}
+	return false
+}
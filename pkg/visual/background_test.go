@@ -0,0 +1,44 @@
+package visual
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildReplaceBackgroundFilter(t *testing.T) {
+	filter := buildReplaceBackgroundFilter(ReplaceBackgroundOptions{}, 1280, 720)
+	if !strings.Contains(filter, "chromakey=color=0x00FF00:similarity=0.30:blend=0.10") {
+		t.Errorf("Expected default chroma key settings, got %s", filter)
+	}
+	if !strings.Contains(filter, "force_original_aspect_ratio=increase,crop=1280:720") {
+		t.Errorf("Expected background to cover the frame, got %s", filter)
+	}
+	if strings.Contains(filter, "alphamerge") {
+		t.Errorf("Expected no light wrap by default, got %s", filter)
+	}
+
+	filter = buildReplaceBackgroundFilter(ReplaceBackgroundOptions{
+		Fit:            "contain",
+		BackgroundBlur: 10,
+		LightWrap:      0.5,
+		Despill:        true,
+	}, 1920, 1080)
+	for _, want := range []string{"pad=1920:1080", "boxblur=10:1", "despill=type=green", "alphamerge[wrap]", "[out]"} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q, got %s", want, filter)
+		}
+	}
+}
+
+func TestBuildKeyFilter(t *testing.T) {
+	filter := buildKeyFilter("blue", 0.3, 0.1, true, 2, 1.5)
+	want := "chromakey=color=blue:similarity=0.30:blend=0.10,despill=type=blue,format=yuva420p," +
+		"erosion=threshold0=0:threshold1=0:threshold2=0,erosion=threshold0=0:threshold1=0:threshold2=0," +
+		"gblur=sigma=1.5:planes=8"
+	if filter != want {
+		t.Errorf("buildKeyFilter() = %q, want %q", filter, want)
+	}
+	if filter := buildKeyFilter("0x00FF00", 0.3, 0.1, false, 50, 0); strings.Count(filter, "erosion") != maxChoke {
+		t.Errorf("Expected choke capped at %d passes, got %s", maxChoke, filter)
+	}
+}
@@ -0,0 +1,108 @@
+package visual
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// blendModes maps overlay_video blend modes to FFmpeg blend filter modes
+var blendModes = map[string]string{
+	"normal":     "normal",
+	"screen":     "screen",
+	"multiply":   "multiply",
+	"overlay":    "overlay",
+	"add":        "addition",
+	"softlight":  "softlight",
+	"lighten":    "lighten",
+	"darken":     "darken",
+	"difference": "difference",
+}
+
+// BlendModes lists the blend modes OverlayVideo accepts
+var BlendModes = []string{"normal", "screen", "multiply", "overlay", "add", "softlight", "lighten", "darken", "difference"}
+
+// OverlayVideoOptions contains options for blending one video over another
+type OverlayVideoOptions struct {
+	MainVideo string
+	Overlay   string // Video or still image, scaled to the main frame
+	Output    string
+	Mode      string  // One of BlendModes (default: screen)
+	Opacity   float64 // 0-1 (default: 1)
+	Start     float64 // Main video time in seconds the overlay begins at
+	Loop      bool    // Repeat the overlay until the main video ends
+}
+
+// OverlayVideo blends a full-frame overlay, such as a light leak, smoke or
+// film texture, over the main video. Screen suits light on black, multiply
+// suits dark texture on white. The output keeps the main video's length
+// and audio; without Loop the main video shows alone once the overlay ends.
+func (c *Composite) OverlayVideo(ctx context.Context, opts OverlayVideoOptions) error {
+	width, height, err := c.ffmpeg.ProbeDimensions(ctx, opts.MainVideo)
+	if err != nil {
+		return err
+	}
+	duration, err := c.ffmpeg.ProbeDuration(ctx, opts.MainVideo)
+	if err != nil {
+		return err
+	}
+	loop := opts.Loop || isImageFile(opts.Overlay)
+	filter, err := buildBlendFilter(opts, width, height, loop)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"-i", opts.MainVideo}
+	switch {
+	case isImageFile(opts.Overlay):
+		args = append(args, "-loop", "1")
+	case loop:
+		args = append(args, "-stream_loop", "-1")
+	}
+	args = append(args,
+		"-i", opts.Overlay,
+		"-filter_complex", filter,
+		"-map", "[vout]",
+		"-map", "0:a?",
+		"-c:a", "copy",
+		"-t", fmt.Sprintf("%.3f", duration),
+		"-y", opts.Output,
+	)
+	return c.ffmpeg.Execute(ctx, args...)
+}
+
+// buildBlendFilter scales the overlay to the main frame and blends the two
+// in planar RGB, where the blend modes behave as in image editors
+func buildBlendFilter(opts OverlayVideoOptions, width, height int, loop bool) (string, error) {
+	name := opts.Mode
+	if name == "" {
+		name = "screen"
+	}
+	mode, ok := blendModes[name]
+	if !ok {
+		return "", fmt.Errorf("unknown blend mode %q (use %s)", name, strings.Join(BlendModes, ", "))
+	}
+	opacity := opts.Opacity
+	if opacity == 0 {
+		opacity = 1
+	}
+	if opacity < 0 || opacity > 1 {
+		return "", fmt.Errorf("opacity must be between 0 and 1")
+	}
+	if opts.Start < 0 {
+		return "", fmt.Errorf("start cannot be negative")
+	}
+
+	overlay := fmt.Sprintf("[1:v]scale=%d:%d,setsar=1,format=gbrp", width, height)
+	if opts.Start > 0 {
+		overlay += fmt.Sprintf(",setpts=PTS-STARTPTS+%.3f/TB", opts.Start)
+	}
+	// Main frames pass through unblended before the overlay starts and,
+	// unless it loops, after it ends
+	eof := ""
+	if !loop {
+		eof = ":eof_action=pass"
+	}
+	return fmt.Sprintf("%s[ov];[0:v]format=gbrp[base];[base][ov]blend=all_mode=%s:all_opacity=%.2f%s,format=yuv420p[vout]",
+		overlay, mode, opacity, eof), nil
+}
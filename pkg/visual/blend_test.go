@@ -0,0 +1,39 @@
+package visual
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildBlendFilter(t *testing.T) {
+	filter, err := buildBlendFilter(OverlayVideoOptions{}, 1920, 1080, false)
+	want := "[1:v]scale=1920:1080,setsar=1,format=gbrp[ov];[0:v]format=gbrp[base];[base][ov]blend=all_mode=screen:all_opacity=1.00:eof_action=pass,format=yuv420p[vout]"
+	if err != nil || filter != want {
+		t.Errorf("buildBlendFilter() = %q, %v; want %q", filter, err, want)
+	}
+
+	filter, err = buildBlendFilter(OverlayVideoOptions{Mode: "add", Opacity: 0.4, Start: 2.5}, 1280, 720, true)
+	if err != nil {
+		t.Fatalf("buildBlendFilter() error = %v", err)
+	}
+	for _, want := range []string{"setpts=PTS-STARTPTS+2.500/TB", "all_mode=addition:all_opacity=0.40,"} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q, got %s", want, filter)
+		}
+	}
+	if strings.Contains(filter, "eof_action") {
+		t.Errorf("Expected a looping overlay to keep blending, got %s", filter)
+	}
+
+	for _, mode := range BlendModes {
+		if _, ok := blendModes[mode]; !ok {
+			t.Errorf("BlendModes lists %q without a filter mode", mode)
+		}
+	}
+	if _, err := buildBlendFilter(OverlayVideoOptions{Mode: "dodge"}, 1280, 720, false); err == nil {
+		t.Error("Expected an error for an unknown blend mode")
+	}
+	if _, err := buildBlendFilter(OverlayVideoOptions{Opacity: 1.5}, 1280, 720, false); err == nil {
+		t.Error("Expected an error for opacity above 1")
+	}
+}
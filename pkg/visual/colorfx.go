@@ -0,0 +1,127 @@
+package visual
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DuotoneOptions contains options for the duotone effect
+type DuotoneOptions struct {
+	Input      string
+	Output     string
+	Shadows    string      // Hex color dark tones map to (default: #1B1464, deep indigo)
+	Highlights string      // Hex color bright tones map to (default: #FF6F61, coral)
+	Contrast   float64     // Contrast applied to the luma first, -1 to 1 (default: 0)
+	Mask       *EffectMask // Limit the effect to part of the frame or ramp it over time
+}
+
+// ApplyDuotone maps the picture's brightness onto a gradient between two
+// colors, for title cards and poster-style music video looks
+func (e *Effects) ApplyDuotone(ctx context.Context, opts DuotoneOptions) error {
+	filter, err := buildDuotoneFilter(opts)
+	if err != nil {
+		return err
+	}
+	return e.applyFilter(ctx, opts.Input, opts.Output, filter, opts.Mask)
+}
+
+// buildDuotoneFilter turns the picture gray, then maps each gray level to
+// the shadows-to-highlights gradient per RGB channel
+func buildDuotoneFilter(opts DuotoneOptions) (string, error) {
+	shadows, err := parseHexColor(colorOrDefault(opts.Shadows, "#1B1464"))
+	if err != nil {
+		return "", fmt.Errorf("invalid shadows color: %w", err)
+	}
+	highlights, err := parseHexColor(colorOrDefault(opts.Highlights, "#FF6F61"))
+	if err != nil {
+		return "", fmt.Errorf("invalid highlights color: %w", err)
+	}
+	if opts.Contrast < -1 || opts.Contrast > 1 {
+		return "", fmt.Errorf("contrast must be between -1 and 1")
+	}
+
+	filters := []string{"format=gray"}
+	if opts.Contrast != 0 {
+		filters = append(filters, fmt.Sprintf("eq=contrast=%.2f", opts.Contrast+1))
+	}
+	var channels []string
+	for i, name := range []string{"r", "g", "b"} {
+		channels = append(channels, fmt.Sprintf("%s='%d+(%d)*val/255'", name, shadows[i], highlights[i]-shadows[i]))
+	}
+	filters = append(filters, "format=rgb24", "lutrgb="+strings.Join(channels, ":"))
+	return strings.Join(filters, ","), nil
+}
+
+// SelectiveColorOptions contains options for the selective color effect
+type SelectiveColorOptions struct {
+	Input      string
+	Output     string
+	Color      string      // Color to keep, as a name or hex (default: red)
+	Similarity float64     // How close a color must be to keep it, 0.01-1 (default: 0.3)
+	Blend      float64     // Softness of the falloff to gray, 0-1 (default: 0.1)
+	Mask       *EffectMask // Limit the effect to part of the frame or ramp it over time
+}
+
+// ApplySelectiveColor keeps one color, such as a red coat, and turns the
+// rest of the picture black and white
+func (e *Effects) ApplySelectiveColor(ctx context.Context, opts SelectiveColorOptions) error {
+	filter, err := buildSelectiveColorFilter(opts)
+	if err != nil {
+		return err
+	}
+	return e.applyFilter(ctx, opts.Input, opts.Output, filter, opts.Mask)
+}
+
+// buildSelectiveColorFilter returns a colorhold filter for the color to keep
+func buildSelectiveColorFilter(opts SelectiveColorOptions) (string, error) {
+	color := colorOrDefault(opts.Color, "red")
+	if err := checkFilterColor(color); err != nil {
+		return "", err
+	}
+	similarity := opts.Similarity
+	if similarity == 0 {
+		similarity = 0.3
+	}
+	blend := opts.Blend
+	if blend == 0 {
+		blend = 0.1
+	}
+	if similarity < 0.01 || similarity > 1 || blend < 0 || blend > 1 {
+		return "", fmt.Errorf("similarity must be between 0.01 and 1 and blend between 0 and 1")
+	}
+	return fmt.Sprintf("colorhold=color=%s:similarity=%.2f:blend=%.2f", color, similarity, blend), nil
+}
+
+// parseHexColor reads #RRGGBB or 0xRRGGBB into red, green and blue
+func parseHexColor(s string) ([3]int, error) {
+	hex := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "#"), "0x")
+	if len(hex) != 6 {
+		return [3]int{}, fmt.Errorf("%q is not a #RRGGBB color", s)
+	}
+	var rgb [3]int
+	for i := range rgb {
+		v, err := strconv.ParseUint(hex[i*2:i*2+2], 16, 8)
+		if err != nil {
+			return [3]int{}, fmt.Errorf("%q is not a #RRGGBB color", s)
+		}
+		rgb[i] = int(v)
+	}
+	return rgb, nil
+}
+
+// checkFilterColor rejects colors that would break out of a filter option
+func checkFilterColor(color string) error {
+	if color == "" || strings.ContainsAny(color, ":,;'[]=\\ ") {
+		return fmt.Errorf("invalid color %q", color)
+	}
+	return nil
+}
+
+func colorOrDefault(color, fallback string) string {
+	if color == "" {
+		return fallback
+	}
+	return color
+}
@@ -0,0 +1,29 @@
+package visual
+
+import "testing"
+
+func TestBuildDuotoneFilter(t *testing.T) {
+	got, err := buildDuotoneFilter(DuotoneOptions{Shadows: "#000000", Highlights: "0xFF8000", Contrast: 0.2})
+	want := "format=gray,eq=contrast=1.20,format=rgb24,lutrgb=r='0+(255)*val/255':g='0+(128)*val/255':b='0+(0)*val/255'"
+	if err != nil || got != want {
+		t.Errorf("buildDuotoneFilter() = %q, %v; want %q", got, err, want)
+	}
+	if _, err := buildDuotoneFilter(DuotoneOptions{Shadows: "navy"}); err == nil {
+		t.Error("Expected an error for a non-hex color")
+	}
+	if _, err := buildDuotoneFilter(DuotoneOptions{Contrast: 2}); err == nil {
+		t.Error("Expected an error for contrast out of range")
+	}
+}
+
+func TestBuildSelectiveColorFilter(t *testing.T) {
+	if got, err := buildSelectiveColorFilter(SelectiveColorOptions{}); err != nil || got != "colorhold=color=red:similarity=0.30:blend=0.10" {
+		t.Errorf("default = %q, %v", got, err)
+	}
+	if got, err := buildSelectiveColorFilter(SelectiveColorOptions{Color: "0x2060FF", Similarity: 0.2, Blend: 0.05}); err != nil || got != "colorhold=color=0x2060FF:similarity=0.20:blend=0.05" {
+		t.Errorf("hex = %q, %v", got, err)
+	}
+	if _, err := buildSelectiveColorFilter(SelectiveColorOptions{Color: "red:similarity=1"}); err == nil {
+		t.Error("Expected an error for a color with filter syntax")
+	}
+}
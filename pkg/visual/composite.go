@@ -22,16 +22,22 @@ type PictureInPictureOptions struct {
 	MainVideo   string
 	PipVideo    string
 	Output      string
-	Position    string  // top-left, top-right, bottom-left, bottom-right, center, etc.
+	Position    string // top-left, top-right, bottom-left, bottom-right, center, etc.
 	Width       *int
 	Height      *int
 	Margin      int
-	BorderWidth int
-	BorderColor string
+	BorderWidth int           // Border around the PiP in pixels (0 = none)
+	BorderColor string        // Border color (default: white)
+	Shadow      bool          // Soft drop shadow below and right of the PiP
+	Keyframes   []PipKeyframe // Animate position, scale and opacity; overrides Position, Width and Height
 }
 
 // CreatePictureInPicture creates picture-in-picture composition
 func (c *Composite) CreatePictureInPicture(ctx context.Context, opts PictureInPictureOptions) error {
+	if len(opts.Keyframes) > 0 {
+		return c.createAnimatedPictureInPicture(ctx, opts)
+	}
+
 	// Set defaults
 	position := opts.Position
 	if position == "" {
@@ -66,16 +72,25 @@ func (c *Composite) CreatePictureInPicture(ctx context.Context, opts PictureInPi
 		yExpr = fmt.Sprintf("main_h-overlay_h-%d", margin)
 	}
 
+	// Border and shadow are added after scaling, in output pixels
+	card, err := pipCardFilter(opts.BorderWidth, opts.BorderColor, opts.Shadow)
+	if err != nil {
+		return err
+	}
+	if card != "" {
+		card = "," + card
+	}
+
 	// Build filter complex
 	var filterComplex string
 	if opts.Width != nil && opts.Height != nil {
 		// Scale PiP video
-		filterComplex = fmt.Sprintf("[1:v]scale=%d:%d[pip];[0:v][pip]overlay=%s:%s",
-			*opts.Width, *opts.Height, xExpr, yExpr)
+		filterComplex = fmt.Sprintf("[1:v]scale=%d:%d%s[pip];[0:v][pip]overlay=%s:%s",
+			*opts.Width, *opts.Height, card, xExpr, yExpr)
 	} else {
 		// Use original size (scaled to 25% of main)
-		filterComplex = fmt.Sprintf("[1:v]scale=iw*0.25:ih*0.25[pip];[0:v][pip]overlay=%s:%s",
-			xExpr, yExpr)
+		filterComplex = fmt.Sprintf("[1:v]scale=iw*0.25:ih*0.25%s[pip];[0:v][pip]overlay=%s:%s",
+			card, xExpr, yExpr)
 	}
 
 	args := []string{
@@ -93,61 +108,33 @@ func (c *Composite) CreatePictureInPicture(ctx context.Context, opts PictureInPi
 type SplitScreenOptions struct {
 	Videos      []string
 	Output      string
-	Layout      string // horizontal, vertical, grid-2x2, grid-3x3
-	BorderWidth int
-	BorderColor string
+	Layout      string   // horizontal, vertical, grid-2x2, grid-3x3, or any grid-RxC
+	Rows        int      // Custom grid rows, with Columns; overrides Layout
+	Columns     int      // Custom grid columns
+	BorderWidth int      // Gap between cells and around the edge, in pixels
+	BorderColor string   // Gap and empty cell color (default: black)
+	Labels      []string // Caption for each cell, in video order ("" for none)
+	Audio       string   // Cell number whose audio to keep, "mix" or "none" (default: 1)
 }
 
-// CreateSplitScreen creates split screen layout
+// CreateSplitScreen lays the videos out in a grid, row by row. Each cell is
+// a share of the first video's frame; videos are fitted into their cell
+// without distortion.
 func (c *Composite) CreateSplitScreen(ctx context.Context, opts SplitScreenOptions) error {
 	if len(opts.Videos) < 2 {
 		return fmt.Errorf("need at least 2 videos for split screen")
 	}
-
-	var filterComplex string
-
-	switch opts.Layout {
-	case "horizontal":
-		// 2-way horizontal split
-		filterComplex = "[0:v]scale=iw/2:ih[left];[1:v]scale=iw/2:ih[right];[left][right]hstack=inputs=2"
-
-	case "vertical":
-		// 2-way vertical split
-		filterComplex = "[0:v]scale=iw:ih/2[top];[1:v]scale=iw:ih/2[bottom];[top][bottom]vstack=inputs=2"
-
-	case "grid-2x2":
-		// 4-way grid
-		if len(opts.Videos) < 4 {
-			return fmt.Errorf("grid-2x2 requires 4 videos")
-		}
-		filterComplex = `
-			[0:v]scale=iw/2:ih/2[v0];
-			[1:v]scale=iw/2:ih/2[v1];
-			[2:v]scale=iw/2:ih/2[v2];
-			[3:v]scale=iw/2:ih/2[v3];
-			[v0][v1]hstack=inputs=2[top];
-			[v2][v3]hstack=inputs=2[bottom];
-			[top][bottom]vstack=inputs=2
-		`
-
-	case "grid-3x3":
-		// 9-way grid
-		if len(opts.Videos) < 9 {
-			return fmt.Errorf("grid-3x3 requires 9 videos")
-		}
-		filterComplex = `
-			[0:v]scale=iw/3:ih/3[v0];[1:v]scale=iw/3:ih/3[v1];[2:v]scale=iw/3:ih/3[v2];
-			[3:v]scale=iw/3:ih/3[v3];[4:v]scale=iw/3:ih/3[v4];[5:v]scale=iw/3:ih/3[v5];
-			[6:v]scale=iw/3:ih/3[v6];[7:v]scale=iw/3:ih/3[v7];[8:v]scale=iw/3:ih/3[v8];
-			[v0][v1][v2]hstack=inputs=3[row1];
-			[v3][v4][v5]hstack=inputs=3[row2];
-			[v6][v7][v8]hstack=inputs=3[row3];
-			[row1][row2][row3]vstack=inputs=3
-		`
-
-	default:
-		// Default to horizontal
-		filterComplex = "[0:v]scale=iw/2:ih[left];[1:v]scale=iw/2:ih[right];[left][right]hstack=inputs=2"
+	rows, columns, err := gridShape(opts)
+	if err != nil {
+		return err
+	}
+	width, height, err := c.ffmpeg.ProbeDimensions(ctx, opts.Videos[0])
+	if err != nil {
+		return err
+	}
+	filter, audioMaps, err := buildGridFilter(opts, rows, columns, width, height)
+	if err != nil {
+		return err
 	}
 
 	// Build input arguments
@@ -156,10 +143,9 @@ func (c *Composite) CreateSplitScreen(ctx context.Context, opts SplitScreenOptio
 		args = append(args, "-i", video)
 	}
 
-	args = append(args,
-		"-filter_complex", filterComplex,
-		"-y", opts.Output,
-	)
+	args = append(args, "-filter_complex", filter, "-map", "[vout]")
+	args = append(args, audioMaps...)
+	args = append(args, "-y", opts.Output)
 
 	return c.ffmpeg.Execute(ctx, args...)
 }
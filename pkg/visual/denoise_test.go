@@ -0,0 +1,37 @@
+package visual
+
+import "testing"
+
+func TestBuildDenoiseFilter(t *testing.T) {
+	if filter, err := buildDenoiseFilter("", ""); err != nil || filter != "hqdn3d=4:3:6:4.5" {
+		t.Errorf("default = %q, %v", filter, err)
+	}
+	if filter, err := buildDenoiseFilter(DenoiseQuality, "strong"); err != nil || filter != "nlmeans=s=6:p=7:r=15" {
+		t.Errorf("nlmeans strong = %q, %v", filter, err)
+	}
+	if _, err := buildDenoiseFilter("median", "light"); err == nil {
+		t.Error("Expected an error for an unknown method")
+	}
+	if _, err := buildDenoiseFilter(DenoiseFast, "extreme"); err == nil {
+		t.Error("Expected an error for an unknown strength")
+	}
+}
+
+func TestBuildFilmGrainFilter(t *testing.T) {
+	tests := []struct {
+		opts FilmGrainOptions
+		want string
+	}{
+		{FilmGrainOptions{}, "noise=c0s=15:c0f=t+u"},
+		{FilmGrainOptions{Strength: "strong", Color: true}, "noise=c0s=28:c0f=t+u:c1s=14:c1f=t+u:c2s=14:c2f=t+u"},
+		{FilmGrainOptions{Strength: "light", Intensity: 40}, "noise=c0s=40:c0f=t+u"},
+	}
+	for _, tt := range tests {
+		if got, err := buildFilmGrainFilter(tt.opts); err != nil || got != tt.want {
+			t.Errorf("%+v: got %q, %v; want %q", tt.opts, got, err, tt.want)
+		}
+	}
+	if _, err := buildFilmGrainFilter(FilmGrainOptions{Intensity: 150}); err == nil {
+		t.Error("Expected an error for intensity above 100")
+	}
+}
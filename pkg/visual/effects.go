@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/video"
 )
 
 // Effects handles visual effects operations
@@ -26,6 +27,7 @@ type BlurOptions struct {
 	Angle     float64 // For motion blur
 	StartTime *float64
 	Duration  *float64
+	Mask      *EffectMask // Limit the blur to part of the frame or ramp it over time
 }
 
 // ApplyBlur applies blur effect to video
@@ -54,27 +56,21 @@ func (e *Effects) ApplyBlur(ctx context.Context, opts BlurOptions) error {
 		filter = fmt.Sprintf("%s:enable='%s'", filter, enable)
 	}
 
-	args := []string{
-		"-i", opts.Input,
-		"-vf", filter,
-		"-c:a", "copy",
-		"-y", opts.Output,
-	}
-
-	return e.ffmpeg.Execute(ctx, args...)
+	return e.applyFilter(ctx, opts.Input, opts.Output, filter, opts.Mask)
 }
 
 // ColorGradeOptions contains options for color grading
 type ColorGradeOptions struct {
 	Input       string
 	Output      string
-	Brightness  *float64 // -1 to 1
-	Contrast    *float64 // -1 to 1
-	Saturation  *float64 // -1 to 1
-	Gamma       *float64 // 0.1 to 10
-	Hue         *float64 // Degrees
-	Temperature *float64 // -100 to 100
-	Tint        *float64 // -100 to 100
+	Brightness  *float64    // -1 to 1
+	Contrast    *float64    // -1 to 1
+	Saturation  *float64    // -1 to 1
+	Gamma       *float64    // 0.1 to 10
+	Hue         *float64    // Degrees
+	Temperature *float64    // -100 to 100
+	Tint        *float64    // -100 to 100
+	Mask        *EffectMask // Limit the grade to part of the frame or ramp it over time
 }
 
 // ApplyColorGrade applies color grading to video
@@ -137,14 +133,7 @@ func (e *Effects) ApplyColorGrade(ctx context.Context, opts ColorGradeOptions) e
 
 	filterComplex := joinParams(filters, ",")
 
-	args := []string{
-		"-i", opts.Input,
-		"-vf", filterComplex,
-		"-c:a", "copy",
-		"-y", opts.Output,
-	}
-
-	return e.ffmpeg.Execute(ctx, args...)
+	return e.applyFilter(ctx, opts.Input, opts.Output, filterComplex, opts.Mask)
 }
 
 // ChromaKeyOptions contains options for chroma key (green screen)
@@ -154,8 +143,12 @@ type ChromaKeyOptions struct {
 	KeyColor        string  // Color to key out
 	Similarity      float64 // 0-1
 	Blend           float64 // 0-1
-	BackgroundImage *string
-	BackgroundColor *string
+	BackgroundImage *string // Image or video to composite the subject onto
+	BackgroundColor *string // Solid color to composite the subject onto
+	Despill         bool    // Remove key color spill from the subject
+	Choke           int     // Shrink the matte edge by this many pixels, 0-10
+	Feather         float64 // Soften the matte edge, blur sigma in pixels
+	Transparent     bool    // Keep the keyed-out area transparent (.webm, .mkv or .mov output)
 }
 
 // ApplyChromaKey removes green screen from video
@@ -175,35 +168,70 @@ func (e *Effects) ApplyChromaKey(ctx context.Context, opts ChromaKeyOptions) err
 		blend = 0.1
 	}
 
-	filter := fmt.Sprintf("chromakey=color=%s:similarity=%.2f:blend=%.2f", keyColor, similarity, blend)
+	filter := buildKeyFilter(keyColor, similarity, blend, opts.Despill, opts.Choke, opts.Feather)
 
-	args := []string{}
+	if opts.Transparent {
+		if opts.BackgroundImage != nil || opts.BackgroundColor != nil {
+			return fmt.Errorf("transparent output can't be combined with a background")
+		}
+		args, err := buildTransparentKeyArgs(opts.Input, opts.Output, filter)
+		if err != nil {
+			return err
+		}
+		return e.ffmpeg.Execute(ctx, args...)
+	}
 
-	// If background image is specified, use overlay
+	// Composite onto a background in the same render if one is given
 	if opts.BackgroundImage != nil {
-		args = []string{
-			"-i", *opts.BackgroundImage,
-			"-i", opts.Input,
-			"-filter_complex", fmt.Sprintf("[1:v]%s[keyed];[0:v][keyed]overlay", filter),
-			"-y", opts.Output,
+		return e.ReplaceBackground(ctx, ReplaceBackgroundOptions{
+			Input:      opts.Input,
+			Background: *opts.BackgroundImage,
+			Output:     opts.Output,
+			KeyColor:   keyColor,
+			Similarity: similarity,
+			Blend:      blend,
+			Despill:    opts.Despill,
+			Choke:      opts.Choke,
+			Feather:    opts.Feather,
+		})
+	}
+
+	args := []string{"-i", opts.Input}
+	if opts.BackgroundColor != nil {
+		if err := checkFilterColor(*opts.BackgroundColor); err != nil {
+			return err
 		}
+		// The color plate is drawn over a copy of the input, so it has the
+		// input's size and frame rate
+		graph := fmt.Sprintf("[0:v]split[key][plate];[plate]drawbox=c=%s:t=fill[bg];[key]%s[fg];[bg][fg]overlay=format=auto,format=yuv420p[out]",
+			*opts.BackgroundColor, filter)
+		args = append(args, "-filter_complex", graph, "-map", "[out]", "-map", "0:a?")
 	} else {
-		args = []string{
-			"-i", opts.Input,
-			"-vf", filter,
-			"-c:a", "copy",
-			"-y", opts.Output,
-		}
+		args = append(args, "-vf", filter)
 	}
+	args = append(args, "-c:a", "copy", "-y", opts.Output)
 
 	return e.ffmpeg.Execute(ctx, args...)
 }
 
+// buildTransparentKeyArgs encodes the keyed subject with the matte as its
+// alpha channel, in VP9 for WebM and Matroska or ProRes 4444 for QuickTime
+func buildTransparentKeyArgs(input, output, filter string) ([]string, error) {
+	preset, err := video.AlphaPreset(output)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"-i", input, "-vf", filter, "-c:v", preset.VideoCodec}
+	args = append(args, preset.Args...)
+	return append(args, "-c:a", preset.AudioCodec, "-y", output), nil
+}
+
 // VignetteOptions contains options for vignette effect
 type VignetteOptions struct {
 	Input     string
 	Output    string
-	Intensity float64 // 0-1
+	Intensity float64     // 0-1
+	Mask      *EffectMask // Limit the vignette to part of the frame or ramp it over time
 }
 
 // ApplyVignette applies vignette effect (darkened edges)
@@ -215,14 +243,7 @@ func (e *Effects) ApplyVignette(ctx context.Context, opts VignetteOptions) error
 
 	filter := "vignette"
 
-	args := []string{
-		"-i", opts.Input,
-		"-vf", filter,
-		"-c:a", "copy",
-		"-y", opts.Output,
-	}
-
-	return e.ffmpeg.Execute(ctx, args...)
+	return e.applyFilter(ctx, opts.Input, opts.Output, filter, opts.Mask)
 }
 
 // SharpenOptions contains options for sharpen effect
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
@@ -184,3 +185,30 @@ func TestApplyChromaKey(t *testing.T) {
 		t.Error("Output file was not created")
 	}
 }
+
+func TestBuildTransparentKeyArgs(t *testing.T) {
+	filter := buildKeyFilter("0x00FF00", 0.3, 0.1, true, 0, 0)
+
+	args, err := buildTransparentKeyArgs("green.mp4", "keyed.webm", filter)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	for _, want := range []string{"-c:v libvpx-vp9", "-pix_fmt yuva420p", "-auto-alt-ref 0", "-c:a libopus"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Expected %q in args: %s", want, joined)
+		}
+	}
+
+	args, err = buildTransparentKeyArgs("green.mp4", "keyed.mov", filter)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if joined := strings.Join(args, " "); !strings.Contains(joined, "-c:v prores_ks -profile:v 4") || !strings.Contains(joined, "-pix_fmt yuva444p10le") {
+		t.Errorf("Expected ProRes 4444 with alpha: %s", joined)
+	}
+
+	if _, err := buildTransparentKeyArgs("green.mp4", "keyed.mp4", filter); err == nil {
+		t.Error("Expected an error for an MP4 output, which can't carry alpha")
+	}
+}
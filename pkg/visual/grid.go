@@ -0,0 +1,121 @@
+package visual
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// gridLayouts are the named split screen layouts as rows and columns
+var gridLayouts = map[string][2]int{
+	"horizontal": {1, 2},
+	"vertical":   {2, 1},
+	"grid-2x2":   {2, 2},
+	"grid-3x3":   {3, 3},
+}
+
+// gridShape returns the rows and columns of a split screen: Rows and
+// Columns when set, else the named layout or a grid-RxC layout
+func gridShape(opts SplitScreenOptions) (int, int, error) {
+	rows, columns := opts.Rows, opts.Columns
+	if rows == 0 && columns == 0 {
+		layout := opts.Layout
+		if layout == "" {
+			layout = "horizontal"
+		}
+		if shape, ok := gridLayouts[layout]; ok {
+			rows, columns = shape[0], shape[1]
+		} else if _, err := fmt.Sscanf(layout, "grid-%dx%d", &rows, &columns); err != nil {
+			return 0, 0, fmt.Errorf("unknown layout %q (use horizontal, vertical or grid-RxC, e.g. grid-2x3)", layout)
+		}
+	}
+	if rows < 1 || columns < 1 || rows > 8 || columns > 8 {
+		return 0, 0, fmt.Errorf("grid must have 1-8 rows and columns, got %dx%d", rows, columns)
+	}
+	if len(opts.Videos) > rows*columns {
+		return 0, 0, fmt.Errorf("%d videos don't fit a %dx%d grid", len(opts.Videos), rows, columns)
+	}
+	return rows, columns, nil
+}
+
+// buildGridFilter fits each video into a cell of a width x height frame,
+// labels it, and stacks the cells with gaps. It returns the graph and the
+// output options that pick the audio.
+func buildGridFilter(opts SplitScreenOptions, rows, columns, width, height int) (string, []string, error) {
+	gap := max(0, opts.BorderWidth)
+	color := opts.BorderColor
+	if color == "" {
+		color = "black"
+	}
+	if err := checkFilterColor(color); err != nil {
+		return "", nil, err
+	}
+	if len(opts.Labels) > len(opts.Videos) {
+		return "", nil, fmt.Errorf("%d labels for %d videos", len(opts.Labels), len(opts.Videos))
+	}
+
+	// Cells share the frame after the gaps, rounded down to even sizes
+	cellW := (width - gap*(columns+1)) / columns / 2 * 2
+	cellH := (height - gap*(rows+1)) / rows / 2 * 2
+	if cellW < 16 || cellH < 16 {
+		return "", nil, fmt.Errorf("a %dx%d grid with %dpx gaps leaves no room in a %dx%d frame", rows, columns, gap, width, height)
+	}
+
+	var parts, labels, layout []string
+	for i := range opts.Videos {
+		cell := fmt.Sprintf("[%d:v]scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2:color=%s,setsar=1",
+			i, cellW, cellH, cellW, cellH, color)
+		if i < len(opts.Labels) && strings.TrimSpace(opts.Labels[i]) != "" {
+			cell += "," + gridLabel(opts.Labels[i], cellH)
+		}
+		parts = append(parts, fmt.Sprintf("%s[c%d]", cell, i))
+		labels = append(labels, fmt.Sprintf("[c%d]", i))
+		layout = append(layout, fmt.Sprintf("%d_%d", gap+(i%columns)*(cellW+gap), gap+(i/columns)*(cellH+gap)))
+	}
+	// xstack sizes the canvas to the last cell, so the bottom row and right
+	// column (even when empty) are padded in afterwards
+	canvasW, canvasH := gap+columns*(cellW+gap), gap+rows*(cellH+gap)
+	parts = append(parts, fmt.Sprintf("%sxstack=inputs=%d:layout=%s:fill=%s,pad=%d:%d:0:0:color=%s,format=yuv420p[vout]",
+		strings.Join(labels, ""), len(opts.Videos), strings.Join(layout, "|"), color, canvasW, canvasH, color))
+
+	mix, audio, err := audioSelection(opts.Audio, len(opts.Videos))
+	if err != nil {
+		return "", nil, err
+	}
+	if mix != "" {
+		parts = append(parts, mix)
+	}
+	return strings.Join(parts, ";"), audio, nil
+}
+
+// audioSelection returns the graph part and output options that keep one
+// input's audio ("1" to inputs, default 1), mix every input's audio ("mix")
+// or drop it ("none"). The graph part is "" unless mixing.
+func audioSelection(choice string, inputs int) (string, []string, error) {
+	switch choice {
+	case "none":
+		return "", []string{"-an"}, nil
+	case "mix":
+		var labels strings.Builder
+		for i := 0; i < inputs; i++ {
+			labels.WriteString(fmt.Sprintf("[%d:a]", i))
+		}
+		return fmt.Sprintf("%samix=inputs=%d:duration=longest:normalize=0[aout]", labels.String(), inputs), []string{"-map", "[aout]"}, nil
+	}
+	input := 1
+	if choice != "" {
+		n, err := strconv.Atoi(choice)
+		if err != nil || n < 1 || n > inputs {
+			return "", nil, fmt.Errorf("audio must be an input number from 1 to %d, mix or none", inputs)
+		}
+		input = n
+	}
+	return "", []string{"-map", fmt.Sprintf("%d:a?", input-1)}, nil
+}
+
+// gridLabel returns a drawtext caption centered at the bottom of a cell
+func gridLabel(label string, cellH int) string {
+	text := strings.NewReplacer(`\`, `\\`, `'`, "’", ":", `\:`, "%", `\%`, "\n", " ").Replace(label)
+	return fmt.Sprintf("drawtext=text='%s':x=(w-text_w)/2:y=h-text_h-%d:fontsize=%d:fontcolor=white:box=1:boxcolor=black@0.5:boxborderw=%d",
+		text, cellH/20, max(14, cellH/14), max(4, cellH/60))
+}
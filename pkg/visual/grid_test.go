@@ -0,0 +1,68 @@
+package visual
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGridShape(t *testing.T) {
+	videos := []string{"a.mp4", "b.mp4", "c.mp4"}
+	tests := []struct {
+		opts       SplitScreenOptions
+		rows, cols int
+	}{
+		{SplitScreenOptions{Videos: videos[:2]}, 1, 2},
+		{SplitScreenOptions{Videos: videos, Layout: "grid-2x2"}, 2, 2},
+		{SplitScreenOptions{Videos: videos, Layout: "grid-1x3"}, 1, 3},
+		{SplitScreenOptions{Videos: videos, Layout: "vertical", Rows: 3, Columns: 1}, 3, 1},
+	}
+	for _, tt := range tests {
+		rows, cols, err := gridShape(tt.opts)
+		if err != nil || rows != tt.rows || cols != tt.cols {
+			t.Errorf("gridShape(%+v) = %d, %d, %v; want %d, %d", tt.opts, rows, cols, err, tt.rows, tt.cols)
+		}
+	}
+	if _, _, err := gridShape(SplitScreenOptions{Videos: videos, Layout: "vertical"}); err == nil {
+		t.Error("Expected an error for 3 videos in a 2x1 grid")
+	}
+	if _, _, err := gridShape(SplitScreenOptions{Videos: videos, Layout: "diagonal"}); err == nil {
+		t.Error("Expected an error for an unknown layout")
+	}
+}
+
+func TestBuildGridFilter(t *testing.T) {
+	opts := SplitScreenOptions{
+		Videos:      []string{"a.mp4", "b.mp4", "c.mp4"},
+		BorderWidth: 10,
+		BorderColor: "white",
+		Labels:      []string{"Before", "", "It's 50%: after"},
+		Audio:       "mix",
+	}
+	filter, audio, err := buildGridFilter(opts, 2, 2, 1920, 1080)
+	if err != nil {
+		t.Fatalf("buildGridFilter() error = %v", err)
+	}
+	for _, want := range []string{
+		"[0:v]scale=944:524:force_original_aspect_ratio=decrease,pad=944:524:(ow-iw)/2:(oh-ih)/2:color=white,setsar=1,drawtext=text='Before'",
+		"setsar=1[c1]",
+		"text='It’s 50\\%\\: after'",
+		"xstack=inputs=3:layout=10_10|964_10|10_544:fill=white,pad=1918:1078:0:0:color=white",
+		"[0:a][1:a][2:a]amix=inputs=3:duration=longest:normalize=0[aout]",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q, got %s", want, filter)
+		}
+	}
+	if strings.Join(audio, " ") != "-map [aout]" {
+		t.Errorf("audio = %v, want the mix", audio)
+	}
+
+	opts.Audio = "2"
+	if _, audio, err := buildGridFilter(opts, 2, 2, 1920, 1080); err != nil || strings.Join(audio, " ") != "-map 1:a?" {
+		t.Errorf("audio = %v, %v; want the second video's audio", audio, err)
+	}
+	opts.Audio = "4"
+	if _, _, err := buildGridFilter(opts, 2, 2, 1920, 1080); err == nil {
+		t.Error("Expected an error for audio from an empty cell")
+	}
+}
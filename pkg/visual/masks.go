@@ -0,0 +1,203 @@
+package visual
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+)
+
+// Mask shapes
+const (
+	MaskRect    = "rect"
+	MaskEllipse = "ellipse"
+)
+
+// MaskShapes lists the shapes an EffectMask accepts
+var MaskShapes = []string{MaskRect, MaskEllipse}
+
+// AmountKeyframe is an effect's strength at a point in time, from 0 (off)
+// to 1 (full)
+type AmountKeyframe struct {
+	Time  float64
+	Value float64
+}
+
+// EffectMask limits an effect to part of the frame and ramps its strength
+// over time. The area is a shape, fixed or following keyframes, or the
+// brightness or alpha of an image or video; without either the effect
+// covers the whole frame and only Amount applies.
+type EffectMask struct {
+	Shape     string           // rect (default) or ellipse
+	X, Y      float64          // Fixed shape box, as fractions of the frame from the top-left corner
+	W, H      float64          // Fixed shape box size, as fractions of the frame
+	Keyframes []RegionKeyframe // Moving shape box, interpolated linearly between keyframes
+	Image     string           // Mask image or video, scaled to the frame; white applies the effect
+	UseAlpha  bool             // Read Image's alpha channel instead of its brightness
+	Invert    bool             // Apply the effect outside the area instead
+	Feather   float64          // Edge softness (blur sigma) in pixels
+	Amount    []AmountKeyframe // Strength over time, interpolated linearly (default: full)
+}
+
+// applyFilter runs a single-input video filter over input. With a mask the
+// filtered picture is blended over the original through it; without one
+// the filter applies to the whole frame as before.
+func (e *Effects) applyFilter(ctx context.Context, input, output, filter string, mask *EffectMask) error {
+	if mask == nil {
+		return e.ffmpeg.Execute(ctx,
+			"-i", input,
+			"-vf", filter,
+			"-c:a", "copy",
+			"-y", output,
+		)
+	}
+
+	graph, err := buildMaskedFilter(filter, mask)
+	if err != nil {
+		return err
+	}
+	args := []string{"-i", input}
+	if mask.Image != "" {
+		args = append(args, "-i", mask.Image)
+	}
+	args = append(args,
+		"-filter_complex", graph,
+		"-map", "[vout]",
+		"-map", "0:a?",
+		"-c:v", "libx264",
+		"-crf", "18",
+		"-preset", "medium",
+		"-pix_fmt", "yuv420p",
+		"-c:a", "copy",
+		"-y", output,
+	)
+	return e.ffmpeg.Execute(ctx, args...)
+}
+
+// buildMaskedFilter builds a graph that applies effect to a copy of the
+// frame, sets its alpha from the mask and strength ramp, and overlays it on
+// the original. The mask image, if any, is input 1 and a single frame is
+// held for the whole video.
+func buildMaskedFilter(effect string, mask *EffectMask) (string, error) {
+	shape, err := maskShapeExpr(mask)
+	if err != nil {
+		return "", err
+	}
+	amount := amountExpr(mask.Amount)
+	if shape == "" && mask.Image == "" {
+		if amount == "" {
+			return "", fmt.Errorf("mask needs a shape box, keyframes, an image or amount keyframes")
+		}
+		if mask.Invert {
+			return "", fmt.Errorf("invert needs a mask shape or image")
+		}
+	}
+
+	var filters []string
+	var matte string
+	switch {
+	case mask.Image != "":
+		matte = "[mimg]format=gray"
+		if mask.UseAlpha {
+			matte = "[mimg]format=rgba,alphaextract"
+		}
+		if mask.Invert {
+			matte += ",negate"
+		}
+		filters = append(filters, "[1:v][0:v]scale2ref[mimg][main]", "[main]split[base][fx]")
+	case shape != "":
+		if mask.Invert {
+			shape = "1-" + shape
+		}
+		matte = fmt.Sprintf("[m]format=gray,geq=lum='255*(%s)'", shape)
+		filters = append(filters, "[0:v]split=3[base][fx][m]")
+	default:
+		filters = append(filters, "[0:v]split[base][fx]")
+	}
+	if matte != "" && mask.Feather > 0 {
+		matte += fmt.Sprintf(",gblur=sigma=%.1f", mask.Feather)
+	}
+
+	filters = append(filters, fmt.Sprintf("[fx]%s[fxd]", effect))
+	switch {
+	case matte != "" && amount != "":
+		filters = append(filters, matte+"[mask]",
+			fmt.Sprintf("[fxd][mask]alphamerge,geq=lum='lum(X,Y)':cb='cb(X,Y)':cr='cr(X,Y)':a='alpha(X,Y)*(%s)'[fxa]", amount))
+	case matte != "":
+		filters = append(filters, matte+"[mask]", "[fxd][mask]alphamerge[fxa]")
+	default:
+		filters = append(filters,
+			fmt.Sprintf("[fxd]format=yuva420p,geq=lum='lum(X,Y)':cb='cb(X,Y)':cr='cr(X,Y)':a='255*(%s)'[fxa]", amount))
+	}
+	filters = append(filters, "[base][fxa]overlay=format=auto,format=yuv420p[vout]")
+	return strings.Join(filters, ";"), nil
+}
+
+// maskShapeExpr returns a geq expression that is 1 inside the mask shape and
+// 0 outside, or "" when the mask has no box or keyframes
+func maskShapeExpr(mask *EffectMask) (string, error) {
+	shape := mask.Shape
+	if shape == "" {
+		shape = MaskRect
+	}
+	if !slices.Contains(MaskShapes, shape) {
+		return "", fmt.Errorf("unknown mask shape %q (use %s)", shape, strings.Join(MaskShapes, ", "))
+	}
+
+	var x, y, w, h string
+	switch {
+	case len(mask.Keyframes) > 0:
+		if mask.Image != "" {
+			return "", fmt.Errorf("use either a mask image or shape keyframes, not both")
+		}
+		keyframes := slices.Clone(mask.Keyframes)
+		slices.SortFunc(keyframes, func(a, b RegionKeyframe) int { return cmp.Compare(a.Time, b.Time) })
+		times := make([]float64, len(keyframes))
+		xs, ys := make([]float64, len(keyframes)), make([]float64, len(keyframes))
+		ws, hs := make([]float64, len(keyframes)), make([]float64, len(keyframes))
+		for i, k := range keyframes {
+			if k.W <= 0 || k.H <= 0 {
+				return "", fmt.Errorf("mask keyframe at %.2fs needs a width and height", k.Time)
+			}
+			times[i], xs[i], ys[i], ws[i], hs[i] = k.Time, k.X, k.Y, k.W, k.H
+		}
+		x, y = ffmpeg.LinearExprIn("T", times, xs), ffmpeg.LinearExprIn("T", times, ys)
+		w, h = ffmpeg.LinearExprIn("T", times, ws), ffmpeg.LinearExprIn("T", times, hs)
+	case mask.W > 0 && mask.H > 0:
+		if mask.Image != "" {
+			return "", fmt.Errorf("use either a mask image or a shape box, not both")
+		}
+		x, y = fmt.Sprintf("%.4f", mask.X), fmt.Sprintf("%.4f", mask.Y)
+		w, h = fmt.Sprintf("%.4f", mask.W), fmt.Sprintf("%.4f", mask.H)
+	case mask.W != 0 || mask.H != 0:
+		return "", fmt.Errorf("mask box needs a positive width and height")
+	default:
+		return "", nil
+	}
+
+	if shape == MaskEllipse {
+		return fmt.Sprintf("lte(pow((X/W-(%s)-(%s)/2)/((%s)/2),2)+pow((Y/H-(%s)-(%s)/2)/((%s)/2),2),1)",
+			x, w, w, y, h, h), nil
+	}
+	return fmt.Sprintf("between(X/W,%s,(%s)+(%s))*between(Y/H,%s,(%s)+(%s))", x, x, w, y, y, h), nil
+}
+
+// amountExpr returns the strength ramp as an expression in geq's T, or ""
+// for full strength throughout
+func amountExpr(keyframes []AmountKeyframe) string {
+	if len(keyframes) == 0 {
+		return ""
+	}
+	keyframes = slices.Clone(keyframes)
+	slices.SortFunc(keyframes, func(a, b AmountKeyframe) int { return cmp.Compare(a.Time, b.Time) })
+	times := make([]float64, len(keyframes))
+	values := make([]float64, len(keyframes))
+	for i, k := range keyframes {
+		times[i], values[i] = k.Time, math.Max(0, math.Min(1, k.Value))
+	}
+	return ffmpeg.LinearExprIn("T", times, values)
+}
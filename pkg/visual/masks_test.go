@@ -0,0 +1,70 @@
+package visual
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildMaskedFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		mask    EffectMask
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "fixed ellipse, inverted and feathered",
+			mask: EffectMask{Shape: MaskEllipse, X: 0.25, Y: 0.25, W: 0.5, H: 0.5, Invert: true, Feather: 20},
+			want: []string{
+				"[0:v]split=3[base][fx][m]",
+				"[m]format=gray,geq=lum='255*(1-lte(pow((X/W-(0.2500)-(0.5000)/2)/((0.5000)/2),2)",
+				"gblur=sigma=20.0[mask]",
+				"[fx]gblur=sigma=5.0[fxd]",
+				"[fxd][mask]alphamerge[fxa]",
+				"[base][fxa]overlay=format=auto,format=yuv420p[vout]",
+			},
+		},
+		{
+			name: "moving rect",
+			mask: EffectMask{Keyframes: []RegionKeyframe{{Time: 2, X: 0.5, Y: 0, W: 0.2, H: 0.2}, {Time: 0, X: 0.1, Y: 0, W: 0.2, H: 0.2}}},
+			want: []string{"between(X/W,if(lt(T,2.000),0.1000+(0.4000)*(T-0.000)/2.000,0.5000)"},
+		},
+		{
+			name: "image alpha with ramp",
+			mask: EffectMask{Image: "matte.png", UseAlpha: true, Amount: []AmountKeyframe{{Time: 0, Value: 0}, {Time: 1, Value: 2}}},
+			want: []string{
+				"[1:v][0:v]scale2ref[mimg][main];[main]split[base][fx]",
+				"[mimg]format=rgba,alphaextract[mask]",
+				"a='alpha(X,Y)*(if(lt(T,1.000),0.0000+(1.0000)*(T-0.000)/1.000,1.0000))'[fxa]",
+			},
+		},
+		{
+			name: "ramp only",
+			mask: EffectMask{Amount: []AmountKeyframe{{Time: 3, Value: 0.5}}},
+			want: []string{"[0:v]split[base][fx]", "[fxd]format=yuva420p,geq=lum='lum(X,Y)':cb='cb(X,Y)':cr='cr(X,Y)':a='255*(0.5000)'[fxa]"},
+		},
+		{name: "empty", mask: EffectMask{}, wantErr: true},
+		{name: "unknown shape", mask: EffectMask{Shape: "star", W: 0.1, H: 0.1}, wantErr: true},
+		{name: "image and box", mask: EffectMask{Image: "matte.png", W: 0.1, H: 0.1}, wantErr: true},
+		{name: "zero-size keyframe", mask: EffectMask{Keyframes: []RegionKeyframe{{Time: 0, X: 0.1}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildMaskedFilter("gblur=sigma=5.0", &tt.mask)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("buildMaskedFilter() = %q, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildMaskedFilter() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("buildMaskedFilter() = %q, missing %q", got, want)
+				}
+			}
+		})
+	}
+}
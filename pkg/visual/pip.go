@@ -0,0 +1,137 @@
+package visual
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+)
+
+// pipShadowMargin is the room around a PiP for its drop shadow, as a
+// fraction of the PiP width; the shadow is offset by half of it
+const pipShadowMargin = 0.05
+
+// PipKeyframe is the PiP's placement at a point in time. Values are
+// interpolated linearly between keyframes and held before the first and
+// after the last, so a PiP can slide in from off-frame or shrink to a
+// corner.
+type PipKeyframe struct {
+	Time    float64
+	X       float64  // Left edge as a fraction of the main frame width
+	Y       float64  // Top edge as a fraction of the main frame height
+	Scale   float64  // PiP width, border included, as a fraction of the main frame width
+	Opacity *float64 // 0-1 (default: 1)
+}
+
+// createAnimatedPictureInPicture overlays the PiP following its keyframes
+func (c *Composite) createAnimatedPictureInPicture(ctx context.Context, opts PictureInPictureOptions) error {
+	width, _, err := c.ffmpeg.ProbeDimensions(ctx, opts.MainVideo)
+	if err != nil {
+		return err
+	}
+	filter, err := buildAnimatedPipFilter(opts, width)
+	if err != nil {
+		return err
+	}
+
+	args := []string{
+		"-i", opts.MainVideo,
+		"-i", opts.PipVideo,
+		"-filter_complex", filter,
+		"-map", "[vout]",
+		"-map", "0:a?",
+		"-c:a", "copy",
+		"-y", opts.Output,
+	}
+	return c.ffmpeg.Execute(ctx, args...)
+}
+
+// buildAnimatedPipFilter builds the PiP card (border, shadow, opacity) at
+// the PiP's own size, scales it every frame to the keyframed width of a
+// mainWidth-wide frame, and overlays it at the keyframed position
+func buildAnimatedPipFilter(opts PictureInPictureOptions, mainWidth int) (string, error) {
+	keyframes := slices.Clone(opts.Keyframes)
+	slices.SortFunc(keyframes, func(a, b PipKeyframe) int { return cmp.Compare(a.Time, b.Time) })
+
+	n := len(keyframes)
+	times, xs, ys := make([]float64, n), make([]float64, n), make([]float64, n)
+	scales, opacities := make([]float64, n), make([]float64, n)
+	fading := false
+	for i, k := range keyframes {
+		if k.Scale <= 0 || k.Scale > 1 {
+			return "", fmt.Errorf("keyframe at %.2fs: scale must be between 0 and 1", k.Time)
+		}
+		opacity := 1.0
+		if k.Opacity != nil {
+			if *k.Opacity < 0 || *k.Opacity > 1 {
+				return "", fmt.Errorf("keyframe at %.2fs: opacity must be between 0 and 1", k.Time)
+			}
+			opacity = *k.Opacity
+		}
+		times[i], xs[i], ys[i], scales[i], opacities[i] = k.Time, k.X, k.Y, k.Scale, opacity
+		fading = fading || opacity != opacities[0]
+	}
+
+	card, err := pipCardFilter(opts.BorderWidth, opts.BorderColor, opts.Shadow)
+	if err != nil {
+		return "", err
+	}
+	pip := "[1:v]"
+	if card != "" {
+		pip += card + ","
+	}
+	pip += "format=rgba"
+	switch {
+	case fading:
+		pip += fmt.Sprintf(",geq=r='r(X,Y)':g='g(X,Y)':b='b(X,Y)':a='alpha(X,Y)*(%s)'",
+			ffmpeg.LinearExprIn("T", times, opacities))
+	case opacities[0] < 1:
+		pip += fmt.Sprintf(",colorchannelmixer=aa=%.3f", opacities[0])
+	}
+
+	// With a shadow the card is wider than the PiP and the PiP sits inside it
+	margin := 0.0
+	if opts.Shadow {
+		margin = pipShadowMargin
+	}
+	scale := ffmpeg.LinearExpr(times, scales)
+	pip += fmt.Sprintf(",scale=w='max(2,trunc(%d*(%s)*%.4f/2)*2)':h=-2:eval=frame[pip]", mainWidth, scale, 1+2*margin)
+
+	x := fmt.Sprintf("W*(%s)", ffmpeg.LinearExpr(times, xs))
+	y := fmt.Sprintf("H*(%s)", ffmpeg.LinearExpr(times, ys))
+	if margin > 0 {
+		x += fmt.Sprintf("-W*(%s)*%.4f", scale, margin)
+		y += fmt.Sprintf("-W*(%s)*%.4f", scale, margin)
+	}
+	return fmt.Sprintf("%s;[0:v][pip]overlay=x='%s':y='%s':format=auto,format=yuv420p[vout]", pip, x, y), nil
+}
+
+// pipCardFilter returns the chain that frames the PiP with a border and a
+// drop shadow, or "" for neither. With a shadow the output is RGBA and
+// the PiP sits pipShadowMargin of its width in from the top-left corner.
+func pipCardFilter(borderWidth int, borderColor string, shadow bool) (string, error) {
+	var chain string
+	if borderWidth > 0 {
+		if borderColor == "" {
+			borderColor = "white"
+		}
+		if err := checkFilterColor(borderColor); err != nil {
+			return "", err
+		}
+		chain = fmt.Sprintf("pad=iw+%d:ih+%d:%d:%d:color=%s", 2*borderWidth, 2*borderWidth, borderWidth, borderWidth, borderColor)
+	}
+	if !shadow {
+		return chain, nil
+	}
+	if chain != "" {
+		chain += ","
+	}
+	m := pipShadowMargin
+	chain += fmt.Sprintf("format=rgba,split[pipc][pips];"+
+		"[pips]colorchannelmixer=rr=0:gg=0:bb=0:aa=0.5,pad=iw*%.4f:ih+iw*%.4f:iw*%.4f:iw*%.4f:color=black@0,gblur=sigma=10[pipsb];"+
+		"[pipsb][pipc]overlay=x='W*%.4f':y='W*%.4f':format=rgb",
+		1+2*m, 2*m, 1.5*m, 1.5*m, m/(1+2*m), m/(1+2*m))
+	return chain, nil
+}
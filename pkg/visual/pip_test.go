@@ -0,0 +1,54 @@
+package visual
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildAnimatedPipFilter(t *testing.T) {
+	half := 0.5
+	opts := PictureInPictureOptions{
+		Shadow: true,
+		Keyframes: []PipKeyframe{
+			{Time: 20, X: 0.7, Y: 0.7, Scale: 0.25},
+			{Time: 5, X: 1, Y: 0.1, Scale: 0.5, Opacity: &half},
+		},
+	}
+	filter, err := buildAnimatedPipFilter(opts, 1920)
+	if err != nil {
+		t.Fatalf("buildAnimatedPipFilter() error = %v", err)
+	}
+	for _, want := range []string{
+		"[1:v]format=rgba,split[pipc][pips]",
+		"overlay=x='W*0.0455':y='W*0.0455':format=rgb,format=rgba,geq=",
+		"a='alpha(X,Y)*(if(lt(T,20.000),0.5000+(0.5000)*(T-5.000)/15.000,1.0000))'",
+		",scale=w='max(2,trunc(1920*(if(lt(t,20.000),0.5000-(0.2500)*(t-5.000)/15.000,0.2500))*1.1000/2)*2)':h=-2:eval=frame[pip]",
+		"overlay=x='W*(if(lt(t,20.000),1.0000-(0.3000)*(t-5.000)/15.000,0.7000))-W*(",
+		"format=yuv420p[vout]",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q, got %s", want, filter)
+		}
+	}
+
+	filter, err = buildAnimatedPipFilter(PictureInPictureOptions{Keyframes: []PipKeyframe{{X: 0.1, Y: 0.1, Scale: 0.3, Opacity: &half}}}, 1280)
+	if err != nil || !strings.Contains(filter, "colorchannelmixer=aa=0.500") || strings.Contains(filter, "geq") {
+		t.Errorf("Expected a constant opacity without geq, got %s, %v", filter, err)
+	}
+
+	if _, err := buildAnimatedPipFilter(PictureInPictureOptions{Keyframes: []PipKeyframe{{Scale: 0}}}, 1280); err == nil {
+		t.Error("Expected an error for a zero scale")
+	}
+}
+
+func TestPipCardFilter(t *testing.T) {
+	if card, err := pipCardFilter(0, "", false); err != nil || card != "" {
+		t.Errorf("Expected no card, got %q, %v", card, err)
+	}
+	if card, err := pipCardFilter(4, "", false); err != nil || card != "pad=iw+8:ih+8:4:4:color=white" {
+		t.Errorf("Expected a white border, got %q, %v", card, err)
+	}
+	if _, err := pipCardFilter(4, "red:x=0", false); err == nil {
+		t.Error("Expected an error for an invalid border color")
+	}
+}
@@ -0,0 +1,135 @@
+package visual
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// PunchInOptions contains options for a punch-in zoom
+type PunchInOptions struct {
+	Input  string
+	Output string
+	Start  float64 // When the zoom starts easing in, in seconds
+	End    float64 // When the zoom has eased back out
+	X, Y   float64 // Region's top-left corner, as fractions of the frame
+	W, H   float64 // Region size, as fractions of the frame
+	Ease   float64 // Seconds to ease in and to ease out (default: 0.6)
+}
+
+// PunchIn zooms into a region of the frame between Start and End, easing
+// in and back out, the staple of screen recording tutorials. The region
+// is widened to the frame's aspect ratio so the whole of it stays in view.
+// Video outside the zoom is passed through at full quality.
+func (e *Effects) PunchIn(ctx context.Context, opts PunchInOptions) error {
+	width, height, err := e.ffmpeg.ProbeDimensions(ctx, opts.Input)
+	if err != nil {
+		return err
+	}
+	fps, err := probeFrameRate(ctx, e.ffmpeg, opts.Input)
+	if err != nil {
+		return err
+	}
+	duration, err := e.ffmpeg.ProbeDuration(ctx, opts.Input)
+	if err != nil {
+		return err
+	}
+	filter, err := buildPunchInFilter(opts, width, height, fps, duration)
+	if err != nil {
+		return err
+	}
+
+	args := []string{
+		"-i", opts.Input,
+		"-filter_complex", filter,
+		"-map", "[vout]",
+		"-map", "0:a?",
+		"-c:v", "libx264",
+		"-crf", "18",
+		"-preset", "medium",
+		"-c:a", "copy",
+		"-y", opts.Output,
+	}
+	return e.ffmpeg.Execute(ctx, args...)
+}
+
+// buildPunchInFilter splits the video at Start and End, zooms the middle
+// part with zoompan and joins the parts back together
+func buildPunchInFilter(opts PunchInOptions, width, height int, fps string, duration float64) (string, error) {
+	if opts.Start < 0 || opts.End <= opts.Start {
+		return "", fmt.Errorf("end must be after start")
+	}
+	end := math.Min(opts.End, duration)
+	if end <= opts.Start {
+		return "", fmt.Errorf("start %.2fs is past the end of the video (%.2fs)", opts.Start, duration)
+	}
+	zoom, anchorX, anchorY, err := punchInTarget(opts)
+	if err != nil {
+		return "", err
+	}
+
+	// Ease in and out over at most half the zoom each, smoothstepped
+	span := end - opts.Start
+	ease := opts.Ease
+	if ease <= 0 {
+		ease = 0.6
+	}
+	ease = math.Min(ease, span/2)
+	p := fmt.Sprintf("min(clip(it/%.3f,0,1),clip((%.3f-it)/%.3f,0,1))", ease, span, ease)
+	progress := fmt.Sprintf("(3*pow(%s,2)-2*pow(%s,3))", p, p)
+
+	// Upscale before zoompan so its whole-pixel steps are too small to
+	// show as jitter
+	zoomed := fmt.Sprintf("scale=%d:%d,zoompan=z='1+(%.4f)*%s':x='(iw-iw/zoom)*%.4f':y='(ih-ih/zoom)*%.4f':d=1:s=%dx%d:fps=%s",
+		width*2, height*2, zoom-1, progress, anchorX, anchorY, width, height, fps)
+
+	type part struct {
+		trim, filter string
+	}
+	var parts []part
+	if opts.Start > 0 {
+		parts = append(parts, part{fmt.Sprintf("trim=end=%.3f", opts.Start), ""})
+	}
+	parts = append(parts, part{fmt.Sprintf("trim=start=%.3f:end=%.3f", opts.Start, end), zoomed + ","})
+	if end < duration {
+		parts = append(parts, part{fmt.Sprintf("trim=start=%.3f", end), ""})
+	}
+
+	var graph []string
+	split := "[0:v]null[p0]"
+	if len(parts) > 1 {
+		var labels strings.Builder
+		for i := range parts {
+			labels.WriteString(fmt.Sprintf("[p%d]", i))
+		}
+		split = fmt.Sprintf("[0:v]split=%d%s", len(parts), labels.String())
+	}
+	graph = append(graph, split)
+	var joined strings.Builder
+	for i, pt := range parts {
+		graph = append(graph, fmt.Sprintf("[p%d]%s,setpts=PTS-STARTPTS,%ssetsar=1,format=yuv420p[s%d]", i, pt.trim, pt.filter, i))
+		joined.WriteString(fmt.Sprintf("[s%d]", i))
+	}
+	graph = append(graph, fmt.Sprintf("%sconcat=n=%d:v=1:a=0[vout]", joined.String(), len(parts)))
+	return strings.Join(graph, ";"), nil
+}
+
+// punchInTarget returns the zoom that fits the region to the frame and the
+// anchor, as fractions of the zoom's free travel, that centers it
+func punchInTarget(opts PunchInOptions) (zoom, anchorX, anchorY float64, err error) {
+	if opts.W <= 0 || opts.H <= 0 || opts.X < 0 || opts.Y < 0 || opts.X+opts.W > 1.0001 || opts.Y+opts.H > 1.0001 {
+		return 0, 0, 0, fmt.Errorf("region must lie within the frame (x, y, width and height are fractions of it)")
+	}
+	zoom = math.Min(1/opts.W, 1/opts.H)
+	if zoom < 1.05 {
+		return 0, 0, 0, fmt.Errorf("region covers nearly the whole frame; choose a smaller one to zoom into")
+	}
+	zoom = math.Min(zoom, 10)
+
+	// Center the view on the region, kept inside the frame
+	view := 1 / zoom
+	left := math.Max(0, math.Min(1-view, opts.X+opts.W/2-view/2))
+	top := math.Max(0, math.Min(1-view, opts.Y+opts.H/2-view/2))
+	return zoom, left / (1 - view), top / (1 - view), nil
+}
@@ -0,0 +1,54 @@
+package visual
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPunchInTarget(t *testing.T) {
+	// A quarter-size region in the bottom-right corner
+	zoom, ax, ay, err := punchInTarget(PunchInOptions{X: 0.75, Y: 0.75, W: 0.25, H: 0.25})
+	if err != nil || zoom != 4 || ax != 1 || ay != 1 {
+		t.Errorf("punchInTarget = %v, %v, %v, %v; want 4, 1, 1", zoom, ax, ay, err)
+	}
+	// A wide region is fitted by its width and centered vertically
+	zoom, ax, ay, err = punchInTarget(PunchInOptions{X: 0.25, Y: 0.4, W: 0.5, H: 0.1})
+	if err != nil || zoom != 2 || ax != 0.5 || math.Abs(ay-0.4) > 1e-9 {
+		t.Errorf("punchInTarget = %v, %v, %v, %v; want 2, 0.5, 0.4", zoom, ax, ay, err)
+	}
+	if _, _, _, err := punchInTarget(PunchInOptions{X: 0.8, W: 0.5, H: 0.5}); err == nil {
+		t.Error("Expected an error for a region outside the frame")
+	}
+}
+
+func TestBuildPunchInFilter(t *testing.T) {
+	opts := PunchInOptions{Start: 2, End: 6, X: 0.5, Y: 0.5, W: 0.5, H: 0.5, Ease: 5}
+	filter, err := buildPunchInFilter(opts, 1920, 1080, "30000/1001", 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"[0:v]split=3[p0][p1][p2]",
+		"[p0]trim=end=2.000,setpts=PTS-STARTPTS,setsar=1",
+		"[p1]trim=start=2.000:end=6.000,setpts=PTS-STARTPTS,scale=3840:2160,zoompan=z='1+(1.0000)*",
+		"clip(it/2.000,0,1),clip((4.000-it)/2.000,0,1)",
+		"d=1:s=1920x1080:fps=30000/1001",
+		"[p2]trim=start=6.000,",
+		"[s0][s1][s2]concat=n=3:v=1:a=0[vout]",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Filter %q missing %q", filter, want)
+		}
+	}
+
+	// A zoom from the first frame to the end has no passthrough parts
+	opts.Start, opts.End = 0, 12
+	filter, err = buildPunchInFilter(opts, 1920, 1080, "30", 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(filter, "[0:v]null[p0];[p0]trim=start=0.000:end=10.000") || !strings.Contains(filter, "concat=n=1") {
+		t.Errorf("Whole-video filter %q", filter)
+	}
+}
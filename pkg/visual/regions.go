@@ -0,0 +1,159 @@
+package visual
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/ffmpeg"
+)
+
+// Region redaction modes
+const (
+	RedactBlur  = "blur"  // Heavy box blur (default)
+	RedactBlack = "black" // Solid black fill
+)
+
+// RegionKeyframe is a region's position at a point in time, as fractions of
+// the frame width and height from the top-left corner
+type RegionKeyframe struct {
+	Time float64
+	X    float64
+	Y    float64
+	W    float64
+	H    float64
+}
+
+// MovingRegion is an area to redact between Start and End. Its position is
+// interpolated linearly between keyframes and held before the first and
+// after the last.
+type MovingRegion struct {
+	Start     float64
+	End       float64
+	Keyframes []RegionKeyframe
+}
+
+// BlurRegionsOptions contains options for redacting moving regions
+type BlurRegionsOptions struct {
+	Input    string
+	Output   string
+	Regions  []MovingRegion
+	Mode     string   // blur (default) or black
+	Strength float64  // Blur strength 1-10 (default: 8)
+	Padding  *float64 // Extra size on each side as a fraction of the region (default: 0.2)
+}
+
+// BlurRegions blurs or blacks out regions that move over time, such as
+// tracked faces. Each region is cropped at a fixed size (its largest box)
+// that follows the keyframes, redacted, and laid back over the frame while
+// the region is active.
+func (e *Effects) BlurRegions(ctx context.Context, opts BlurRegionsOptions) error {
+	if len(opts.Regions) == 0 {
+		return fmt.Errorf("no regions to redact")
+	}
+	filter, err := buildRegionsFilter(opts)
+	if err != nil {
+		return err
+	}
+
+	args := []string{
+		"-i", opts.Input,
+		"-filter_complex", filter,
+		"-map", "[vout]",
+		"-map", "0:a?",
+		"-c:v", "libx264",
+		"-crf", "18",
+		"-preset", "medium",
+		"-pix_fmt", "yuv420p",
+		"-c:a", "copy",
+		"-y", opts.Output,
+	}
+	return e.ffmpeg.Execute(ctx, args...)
+}
+
+// buildRegionsFilter builds the filter graph that redacts every region
+func buildRegionsFilter(opts BlurRegionsOptions) (string, error) {
+	mode := opts.Mode
+	if mode == "" {
+		mode = RedactBlur
+	}
+	var redact string
+	switch mode {
+	case RedactBlur:
+		strength := opts.Strength
+		if strength <= 0 {
+			strength = 8
+		}
+		// Radius scales with the region; boxblur allows at most half its size
+		radius := math.Min(10, strength) / 40
+		redact = fmt.Sprintf("boxblur=luma_radius='min(w,h)*%.3f':luma_power=3:chroma_radius='min(cw,ch)*%.3f':chroma_power=3", radius, radius)
+	case RedactBlack:
+		redact = "drawbox=c=black:t=fill"
+	default:
+		return "", fmt.Errorf("unknown mode: %s (use blur or black)", mode)
+	}
+	padding := 0.2
+	if opts.Padding != nil {
+		padding = math.Max(0, *opts.Padding)
+	}
+
+	var regions []MovingRegion
+	for _, r := range opts.Regions {
+		if len(r.Keyframes) > 0 && r.End > r.Start {
+			regions = append(regions, r)
+		}
+	}
+	if len(regions) == 0 {
+		return "", fmt.Errorf("no regions with keyframes and a time range")
+	}
+
+	filters := []string{fmt.Sprintf("[0:v]split=%d[base]%s", len(regions)+1, splitLabels("r", len(regions)))}
+	previous := "[base]"
+	for i, r := range regions {
+		w, h, xs, ys := regionPath(r.Keyframes, padding)
+		times := make([]float64, len(r.Keyframes))
+		for j, k := range r.Keyframes {
+			times[j] = k.Time
+		}
+		x, y := ffmpeg.LinearExpr(times, xs), ffmpeg.LinearExpr(times, ys)
+
+		out := fmt.Sprintf("[o%d]", i)
+		if i == len(regions)-1 {
+			out = "[vout]"
+		}
+		filters = append(filters,
+			fmt.Sprintf("[r%d]crop=w=iw*%.4f:h=ih*%.4f:x='iw*(%s)':y='ih*(%s)',%s[p%d]", i, w, h, x, y, redact, i),
+			fmt.Sprintf("%s[p%d]overlay=x='W*(%s)':y='H*(%s)':enable='between(t,%.3f,%.3f)'%s", previous, i, x, y, r.Start, r.End, out),
+		)
+		previous = out
+	}
+	return strings.Join(filters, ";"), nil
+}
+
+// regionPath returns a fixed padded size covering the region's largest box
+// and the top-left corner at each keyframe, keeping the box on the frame
+func regionPath(keyframes []RegionKeyframe, padding float64) (float64, float64, []float64, []float64) {
+	w, h := 0.0, 0.0
+	for _, k := range keyframes {
+		w, h = math.Max(w, k.W), math.Max(h, k.H)
+	}
+	w, h = math.Min(1, w*(1+2*padding)), math.Min(1, h*(1+2*padding))
+
+	xs := make([]float64, len(keyframes))
+	ys := make([]float64, len(keyframes))
+	for i, k := range keyframes {
+		xs[i] = math.Max(0, math.Min(1-w, k.X+k.W/2-w/2))
+		ys[i] = math.Max(0, math.Min(1-h, k.Y+k.H/2-h/2))
+	}
+	return w, h, xs, ys
+}
+
+// splitLabels returns output pad labels like [r0][r1]
+func splitLabels(prefix string, n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteString(fmt.Sprintf("[%s%d]", prefix, i))
+	}
+	return b.String()
+}
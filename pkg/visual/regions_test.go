@@ -0,0 +1,62 @@
+package visual
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegionPath(t *testing.T) {
+	keyframes := []RegionKeyframe{
+		{Time: 0, X: 0.4, Y: 0.4, W: 0.1, H: 0.1},
+		{Time: 1, X: 0.95, Y: 0.0, W: 0.05, H: 0.2},
+	}
+	w, h, xs, ys := regionPath(keyframes, 0.25)
+	if w != 0.15000000000000002 && w != 0.15 {
+		t.Errorf("w = %v, want 0.15", w)
+	}
+	if h != 0.30000000000000004 && h != 0.3 {
+		t.Errorf("h = %v, want 0.3", h)
+	}
+	// Centered on the box, then kept on the frame
+	if xs[0] < 0.374 || xs[0] > 0.376 || ys[0] < 0.299 || ys[0] > 0.301 {
+		t.Errorf("first corner = %v, %v", xs[0], ys[0])
+	}
+	if xs[1] < 0.849 || xs[1] > 0.851 || ys[1] != 0 {
+		t.Errorf("edge corner = %v, %v", xs[1], ys[1])
+	}
+}
+
+func TestBuildRegionsFilter(t *testing.T) {
+	regions := []MovingRegion{
+		{Start: 0, End: 2, Keyframes: []RegionKeyframe{{Time: 0, X: 0.1, Y: 0.1, W: 0.1, H: 0.1}}},
+		{Start: 1, End: 1, Keyframes: []RegionKeyframe{{Time: 1, X: 0.5, Y: 0.5, W: 0.1, H: 0.1}}}, // Empty range
+		{Start: 3, End: 5, Keyframes: []RegionKeyframe{{Time: 3, X: 0.5, Y: 0.5, W: 0.1, H: 0.1}, {Time: 4, X: 0.6, Y: 0.5, W: 0.1, H: 0.1}}},
+	}
+
+	filter, err := buildRegionsFilter(BlurRegionsOptions{Regions: regions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"[0:v]split=3[base][r0][r1]",
+		"[r0]crop=w=iw*0.1400:h=ih*0.1400:x='iw*(0.0800)':y='ih*(0.0800)',boxblur=luma_radius='min(w,h)*0.200'",
+		"[base][p0]overlay=x='W*(0.0800)':y='H*(0.0800)':enable='between(t,0.000,2.000)'[o0]",
+		"[o0][p1]overlay=",
+		"enable='between(t,3.000,5.000)'[vout]",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q:\n%s", want, filter)
+		}
+	}
+
+	filter, _ = buildRegionsFilter(BlurRegionsOptions{Regions: regions[:1], Mode: RedactBlack})
+	if !strings.Contains(filter, "drawbox=c=black:t=fill[p0]") || !strings.Contains(filter, "[vout]") {
+		t.Errorf("Expected a black fill, got %s", filter)
+	}
+	if _, err := buildRegionsFilter(BlurRegionsOptions{Regions: regions, Mode: "pixelate"}); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+	if _, err := buildRegionsFilter(BlurRegionsOptions{Regions: regions[1:2]}); err == nil {
+		t.Error("Expected an error when no region has a time range")
+	}
+}
@@ -0,0 +1,41 @@
+package visual
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildSideBySideFilter(t *testing.T) {
+	filter, audio, err := buildSideBySideFilter(SideBySideOptions{Audio: "mix"}, 721)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"[0:v]scale=-2:720,setsar=1[s0]",
+		"[1:v]scale=-2:720,setsar=1[s1]",
+		"[s0][s1]hstack=inputs=2",
+		"[0:a][1:a]amix=inputs=2",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Filter %q missing %q", filter, want)
+		}
+	}
+	if strings.Join(audio, " ") != "-map [aout]" {
+		t.Errorf("Audio options = %v", audio)
+	}
+
+	filter, audio, err = buildSideBySideFilter(SideBySideOptions{Vertical: true, Audio: "2"}, 1280)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(filter, "scale=1280:-2") || !strings.Contains(filter, "vstack=inputs=2") {
+		t.Errorf("Stacked filter %q", filter)
+	}
+	if strings.Join(audio, " ") != "-map 1:a?" {
+		t.Errorf("Audio options = %v", audio)
+	}
+
+	if _, _, err := buildSideBySideFilter(SideBySideOptions{Audio: "3"}, 720); err == nil {
+		t.Error("Expected an error for audio from a third input")
+	}
+}
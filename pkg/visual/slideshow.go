@@ -0,0 +1,352 @@
+package visual
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/audio"
+)
+
+// Ken Burns motions for slideshow images
+const (
+	MotionRandom   = "random"
+	MotionZoomIn   = "zoom-in"
+	MotionZoomOut  = "zoom-out"
+	MotionPanLeft  = "pan-left"
+	MotionPanRight = "pan-right"
+	MotionPanUp    = "pan-up"
+	MotionPanDown  = "pan-down"
+	MotionNone     = "none"
+)
+
+const (
+	slideshowZoom      = 1.25 // Zoom at the tight end of zooms and throughout pans
+	slideshowMusicFade = 2.0  // Music fade-out length in seconds
+)
+
+// SlideshowMotions lists the motions a slide can use
+var SlideshowMotions = []string{MotionRandom, MotionZoomIn, MotionZoomOut, MotionPanLeft, MotionPanRight, MotionPanUp, MotionPanDown, MotionNone}
+
+var slideshowImageExts = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".webp": true, ".bmp": true, ".tif": true, ".tiff": true,
+}
+
+// SlideshowOptions contains options for building a slideshow from images
+type SlideshowOptions struct {
+	Images []string // Image files in order
+	Folder string   // Or a folder whose images are used in name order
+	Output string
+
+	Width  int // default: 1920
+	Height int // default: 1080
+	FPS    int // default: 30
+
+	SlideDuration float64  // Seconds per slide (default: 4)
+	Crossfade     *float64 // Seconds of overlap between slides (default: 1, 0 for hard cuts)
+	Transition    string   // xfade transition (default: fade)
+	Motion        string   // Motion for every slide (default: random)
+	Motions       []string // Per-slide motions, overriding Motion where set
+	Seed          int64    // Seed for random motions (default: time-based)
+
+	Music         string // Audio track laid under the slideshow (optional)
+	BeatSync      bool   // Change slides on the music's beats
+	BeatsPerSlide int    // Beats per slide when syncing (default: nearest to SlideDuration)
+}
+
+// Slide describes one slide in a rendered slideshow
+type Slide struct {
+	Image    string  `json:"image"`
+	Start    float64 `json:"start"`
+	Duration float64 `json:"duration"`
+	Motion   string  `json:"motion"`
+}
+
+// SlideshowResult describes a rendered slideshow
+type SlideshowResult struct {
+	Duration  float64 `json:"duration"`
+	Tempo     float64 `json:"tempo,omitempty"` // Detected BPM when beat-synced
+	Crossfade float64 `json:"crossfade"`
+	Slides    []Slide `json:"slides"`
+}
+
+// kenBurnsMove is a zoom and a pan of the view centre, with the centre given
+// as a fraction of the room left for panning at that zoom
+type kenBurnsMove struct {
+	startZoom, endZoom float64
+	startX, startY     float64
+	endX, endY         float64
+}
+
+// CreateSlideshow renders images as a Ken Burns slideshow with crossfades,
+// optionally over a music track with slide changes on its beats
+func (e *Effects) CreateSlideshow(ctx context.Context, opts SlideshowOptions) (*SlideshowResult, error) {
+	images, err := slideshowImages(opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.BeatSync && opts.Music == "" {
+		return nil, fmt.Errorf("beat sync needs a music track")
+	}
+
+	var beats *audio.BeatAnalysis
+	if opts.BeatSync {
+		if beats, err = audio.NewOperations(e.ffmpeg).DetectBeats(ctx, opts.Music); err != nil {
+			return nil, fmt.Errorf("failed to detect beats: %w", err)
+		}
+	}
+
+	result, err := planSlideshow(images, opts, beats)
+	if err != nil {
+		return nil, err
+	}
+	filter := buildSlideshowFilter(result, opts, rand.New(rand.NewSource(slideshowSeed(opts.Seed))))
+
+	var args []string
+	for _, img := range images {
+		args = append(args, "-i", img)
+	}
+	if opts.Music != "" {
+		args = append(args, "-i", opts.Music)
+	}
+	args = append(args, "-filter_complex", filter, "-map", "[v]")
+	if opts.Music != "" {
+		args = append(args, "-map", "[a]", "-c:a", "aac", "-b:a", "192k")
+	}
+	args = append(args,
+		"-c:v", "libx264",
+		"-pix_fmt", "yuv420p",
+		"-movflags", "+faststart",
+		"-y",
+		opts.Output,
+	)
+	if err := e.ffmpeg.Execute(ctx, args...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// slideshowImages returns the explicit image list, or the images in the folder
+// sorted by name
+func slideshowImages(opts SlideshowOptions) ([]string, error) {
+	if len(opts.Images) > 0 && opts.Folder != "" {
+		return nil, fmt.Errorf("use images or folder, not both")
+	}
+	images := opts.Images
+	if opts.Folder != "" {
+		entries, err := os.ReadDir(opts.Folder)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read folder: %w", err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && slideshowImageExts[strings.ToLower(filepath.Ext(entry.Name()))] {
+				images = append(images, filepath.Join(opts.Folder, entry.Name()))
+			}
+		}
+		sort.Strings(images)
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no images found")
+	}
+	return images, nil
+}
+
+// planSlideshow times each slide. Without beats every slide lasts
+// SlideDuration; with beats each change lands on a beat. Slide times are when
+// each transition starts, so the crossfade finishes just after the beat.
+func planSlideshow(images []string, opts SlideshowOptions, beats *audio.BeatAnalysis) (*SlideshowResult, error) {
+	slideDuration := opts.SlideDuration
+	if slideDuration <= 0 {
+		slideDuration = 4
+	}
+	crossfade := 1.0
+	if opts.Crossfade != nil {
+		if crossfade = *opts.Crossfade; crossfade < 0 {
+			return nil, fmt.Errorf("crossfade must not be negative")
+		}
+	}
+	if len(opts.Motions) > len(images) {
+		return nil, fmt.Errorf("%d motions given for %d images", len(opts.Motions), len(images))
+	}
+	for _, m := range append([]string{opts.Motion}, opts.Motions...) {
+		if m != "" && kenBurnsMoves[m] == nil && m != MotionRandom {
+			return nil, fmt.Errorf("unknown motion: %s (use %s)", m, strings.Join(SlideshowMotions, ", "))
+		}
+	}
+
+	// Times at which each slide starts, plus the end
+	changes := make([]float64, len(images)+1)
+	result := &SlideshowResult{}
+	if beats != nil {
+		period := beats.Period()
+		perSlide := opts.BeatsPerSlide
+		if perSlide <= 0 {
+			perSlide = max(1, int(math.Round(slideDuration/period)))
+		}
+		result.Tempo = math.Round(beats.Tempo*10) / 10
+		// The beat grid is steady, so it extends past the end of the music
+		for i := 1; i <= len(images); i++ {
+			changes[i] = beats.Offset + float64(i*perSlide)*period
+		}
+	} else {
+		for i := 1; i <= len(images); i++ {
+			changes[i] = float64(i) * slideDuration
+		}
+	}
+
+	shortest := math.Inf(1)
+	for i := range images {
+		shortest = math.Min(shortest, changes[i+1]-changes[i])
+	}
+	// Leave at least half of every slide unobstructed
+	result.Crossfade = math.Min(crossfade, shortest/2)
+	if len(images) == 1 {
+		result.Crossfade = 0
+	}
+
+	for i, img := range images {
+		motion := opts.Motion
+		if i < len(opts.Motions) && opts.Motions[i] != "" {
+			motion = opts.Motions[i]
+		}
+		if motion == "" {
+			motion = MotionRandom
+		}
+		result.Slides = append(result.Slides, Slide{
+			Image:    img,
+			Start:    changes[i],
+			Duration: changes[i+1] - changes[i],
+			Motion:   motion,
+		})
+	}
+	result.Duration = changes[len(images)]
+	return result, nil
+}
+
+// buildSlideshowFilter animates each image with zoompan and joins the slides
+// with xfade. Random motions are resolved with rng, never repeating the
+// previous slide's motion. Images are inputs 0..n-1 and the music, if any,
+// input n. The result is labelled [v] (and [a]).
+func buildSlideshowFilter(result *SlideshowResult, opts SlideshowOptions, rng *rand.Rand) string {
+	width, height, fps := opts.Width, opts.Height, opts.FPS
+	if width <= 0 || height <= 0 {
+		width, height = 1920, 1080
+	}
+	if fps <= 0 {
+		fps = 30
+	}
+	transition := opts.Transition
+	if transition == "" {
+		transition = "fade"
+	}
+	crossfade := result.Crossfade
+
+	var parts []string
+	previous := ""
+	for i := range result.Slides {
+		slide := &result.Slides[i]
+		if slide.Motion == MotionRandom {
+			slide.Motion = randomMotion(rng, previous)
+		}
+		previous = slide.Motion
+
+		// Every slide but the last runs on under the next one's crossfade
+		length := slide.Duration
+		if i < len(result.Slides)-1 {
+			length += crossfade
+		}
+		frames := max(int(math.Round(length*float64(fps))), 1)
+		move := kenBurnsMoves[slide.Motion]
+		if slide.Motion == MotionZoomIn || slide.Motion == MotionZoomOut {
+			// Zoom towards a point near the centre so zooms vary
+			move = &kenBurnsMove{
+				startZoom: move.startZoom, endZoom: move.endZoom,
+				startX: 0.5, startY: 0.5,
+				endX: 0.3 + rng.Float64()*0.4, endY: 0.3 + rng.Float64()*0.4,
+			}
+			if slide.Motion == MotionZoomOut {
+				move.startX, move.startY, move.endX, move.endY = move.endX, move.endY, 0.5, 0.5
+			}
+		}
+
+		// Upscale before zoompan so its whole-pixel steps are too small to
+		// see as jitter
+		parts = append(parts, fmt.Sprintf(
+			"[%d:v]scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d,setsar=1,%s,format=yuv420p,settb=AVTB[s%d]",
+			i, width*2, height*2, width*2, height*2, zoompanFilter(move, frames, width, height, fps), i))
+	}
+
+	if len(result.Slides) == 1 {
+		parts[0] = strings.TrimSuffix(parts[0], "[s0]") + "[v]"
+	} else {
+		current := "s0"
+		for i := 1; i < len(result.Slides); i++ {
+			next := fmt.Sprintf("x%d", i)
+			if i == len(result.Slides)-1 {
+				next = "v"
+			}
+			if crossfade > 0 {
+				parts = append(parts, fmt.Sprintf("[%s][s%d]xfade=transition=%s:duration=%.3f:offset=%.3f[%s]",
+					current, i, transition, crossfade, result.Slides[i].Start, next))
+			} else {
+				parts = append(parts, fmt.Sprintf("[%s][s%d]concat=n=2:v=1:a=0[%s]", current, i, next))
+			}
+			current = next
+		}
+	}
+
+	if opts.Music != "" {
+		fade := math.Min(slideshowMusicFade, result.Duration/2)
+		parts = append(parts, fmt.Sprintf("[%d:a]atrim=0:%.3f,asetpts=PTS-STARTPTS,afade=t=out:st=%.3f:d=%.3f[a]",
+			len(result.Slides), result.Duration, result.Duration-fade, fade))
+	}
+	return strings.Join(parts, ";")
+}
+
+var kenBurnsMoves = map[string]*kenBurnsMove{
+	MotionZoomIn:   {1, slideshowZoom, 0.5, 0.5, 0.5, 0.5},
+	MotionZoomOut:  {slideshowZoom, 1, 0.5, 0.5, 0.5, 0.5},
+	MotionPanLeft:  {slideshowZoom, slideshowZoom, 1, 0.5, 0, 0.5},
+	MotionPanRight: {slideshowZoom, slideshowZoom, 0, 0.5, 1, 0.5},
+	MotionPanUp:    {slideshowZoom, slideshowZoom, 0.5, 1, 0.5, 0},
+	MotionPanDown:  {slideshowZoom, slideshowZoom, 0.5, 0, 0.5, 1},
+	MotionNone:     {1, 1, 0.5, 0.5, 0.5, 0.5},
+}
+
+// randomMotion picks a motion other than previous
+func randomMotion(rng *rand.Rand, previous string) string {
+	choices := []string{MotionZoomIn, MotionZoomOut, MotionPanLeft, MotionPanRight, MotionPanUp, MotionPanDown}
+	for {
+		if m := choices[rng.Intn(len(choices))]; m != previous {
+			return m
+		}
+	}
+}
+
+// zoompanFilter eases a move over frames output frames of width x height
+func zoompanFilter(move *kenBurnsMove, frames, width, height, fps int) string {
+	// Smoothstep progress so moves start and end gently
+	p := fmt.Sprintf("(3*pow(on/%d,2)-2*pow(on/%d,3))", max(frames-1, 1), max(frames-1, 1))
+	lerp := func(a, b float64) string {
+		if a == b {
+			return fmt.Sprintf("%.4f", a)
+		}
+		return fmt.Sprintf("(%.4f+(%.4f)*%s)", a, b-a, p)
+	}
+	return fmt.Sprintf("zoompan=z='%s':x='(iw-iw/zoom)*%s':y='(ih-ih/zoom)*%s':d=%d:s=%dx%d:fps=%d",
+		lerp(move.startZoom, move.endZoom), lerp(move.startX, move.endX), lerp(move.startY, move.endY),
+		frames, width, height, fps)
+}
+
+func slideshowSeed(seed int64) int64 {
+	if seed == 0 {
+		return time.Now().UnixNano()
+	}
+	return seed
+}
@@ -0,0 +1,104 @@
+package visual
+
+import (
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/audio"
+)
+
+func TestPlanSlideshowFixedDuration(t *testing.T) {
+	result, err := planSlideshow([]string{"a.jpg", "b.jpg", "c.jpg"}, SlideshowOptions{SlideDuration: 3}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Duration != 9 || result.Crossfade != 1 {
+		t.Errorf("Expected 9s with 1s crossfades, got %.1fs and %.1fs", result.Duration, result.Crossfade)
+	}
+	if result.Slides[2].Start != 6 || result.Slides[2].Motion != MotionRandom {
+		t.Errorf("Unexpected third slide: %+v", result.Slides[2])
+	}
+}
+
+func TestPlanSlideshowBeatSync(t *testing.T) {
+	beats := &audio.BeatAnalysis{Tempo: 120, Offset: 0.2}
+	result, err := planSlideshow([]string{"a.jpg", "b.jpg", "c.jpg"}, SlideshowOptions{
+		SlideDuration: 2.1,
+		Motions:       []string{MotionPanLeft},
+	}, beats)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// 2.1s rounds to 4 beats of 0.5s
+	for i, want := range []float64{0, 2.2, 4.2} {
+		if math.Abs(result.Slides[i].Start-want) > 1e-9 {
+			t.Errorf("Slide %d: expected start %.1f, got %.3f", i, want, result.Slides[i].Start)
+		}
+	}
+	if math.Abs(result.Duration-6.2) > 1e-9 || result.Tempo != 120 {
+		t.Errorf("Unexpected duration %.3f or tempo %.1f", result.Duration, result.Tempo)
+	}
+	if result.Slides[0].Motion != MotionPanLeft {
+		t.Errorf("Expected per-slide motion, got %s", result.Slides[0].Motion)
+	}
+
+	// Crossfades are capped to half the shortest slide
+	crossfade := 3.0
+	result, _ = planSlideshow([]string{"a.jpg", "b.jpg"}, SlideshowOptions{BeatsPerSlide: 1, Crossfade: &crossfade}, beats)
+	if result.Crossfade != 0.25 {
+		t.Errorf("Expected crossfade capped at 0.25s, got %.3f", result.Crossfade)
+	}
+}
+
+func TestPlanSlideshowErrors(t *testing.T) {
+	if _, err := planSlideshow([]string{"a.jpg"}, SlideshowOptions{Motion: "spin"}, nil); err == nil {
+		t.Error("Expected error for unknown motion")
+	}
+	if _, err := planSlideshow([]string{"a.jpg"}, SlideshowOptions{Motions: []string{"", ""}}, nil); err == nil {
+		t.Error("Expected error for more motions than images")
+	}
+}
+
+func TestBuildSlideshowFilter(t *testing.T) {
+	result, _ := planSlideshow([]string{"a.jpg", "b.jpg", "c.jpg"}, SlideshowOptions{}, nil)
+	filter := buildSlideshowFilter(result, SlideshowOptions{Music: "song.mp3", Transition: "slideleft"}, rand.New(rand.NewSource(1)))
+
+	for _, want := range []string{
+		"[0:v]scale=3840:2160:force_original_aspect_ratio=increase,crop=3840:2160",
+		"zoompan=z=",
+		":d=150:s=1920x1080:fps=30",
+		"[s0][s1]xfade=transition=slideleft:duration=1.000:offset=4.000[x1]",
+		"[x1][s2]xfade=transition=slideleft:duration=1.000:offset=8.000[v]",
+		"[3:a]atrim=0:12.000,asetpts=PTS-STARTPTS,afade=t=out:st=10.000:d=2.000[a]",
+	} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("Expected filter to contain %q:\n%s", want, filter)
+		}
+	}
+	for i := 1; i < len(result.Slides); i++ {
+		if result.Slides[i].Motion == result.Slides[i-1].Motion || result.Slides[i].Motion == MotionRandom {
+			t.Errorf("Expected distinct resolved motions, got %+v", result.Slides)
+		}
+	}
+}
+
+func TestSlideshowImagesFromFolder(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"02.png", "01.JPG", "notes.txt", "03.webp"} {
+		os.WriteFile(filepath.Join(dir, name), nil, 0644)
+	}
+	images, err := slideshowImages(SlideshowOptions{Folder: dir})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(images) != 3 || filepath.Base(images[0]) != "01.JPG" || filepath.Base(images[2]) != "03.webp" {
+		t.Errorf("Unexpected images: %v", images)
+	}
+	if _, err := slideshowImages(SlideshowOptions{Folder: dir, Images: []string{"a.jpg"}}); err == nil {
+		t.Error("Expected error for both images and folder")
+	}
+}
@@ -0,0 +1,80 @@
+package visual
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StylePreset is a curated look built from one fused filter chain
+type StylePreset struct {
+	Description string
+	Filter      string
+}
+
+// StylePresets are the looks ApplyStylePreset knows, by name
+var StylePresets = map[string]StylePreset{
+	"vhs": {
+		Description: "Worn VHS tape: soft half-resolution picture, color bleed, scanlines, tape noise and boosted saturation",
+		Filter: "scale=iw/2:ih/2,scale=iw*2:ih*2:flags=bicubic," +
+			"chromashift=cbh=-4:crh=4," +
+			"eq=contrast=1.1:saturation=1.35:gamma=1.05," +
+			"noise=c0s=12:c0f=t+u," +
+			"drawgrid=w=iw:h=4:t=1:c=black@0.25," +
+			"vignette=PI/6",
+	},
+	"film_noir": {
+		Description: "High-contrast black and white with crushed shadows, a heavy vignette and fine grain",
+		Filter: "hue=s=0," +
+			"eq=contrast=1.4:brightness=-0.04:gamma=0.9," +
+			"vignette=PI/4," +
+			"noise=c0s=7:c0f=t+u",
+	},
+	"teal_orange": {
+		Description: "Blockbuster grade: teal shadows, warm skin tones and highlights, a touch more contrast",
+		Filter: "colorbalance=rs=-0.15:gs=0.05:bs=0.2:rm=0.05:bm=-0.05:rh=0.15:gh=0.03:bh=-0.15," +
+			"eq=contrast=1.1:saturation=1.15",
+	},
+	"glitch": {
+		Description: "Digital glitch: RGB channel split, heavy noise and short hue-shift flashes",
+		Filter: "rgbashift=rh=-8:bh=8:gv=2," +
+			"noise=c0s=20:c0f=t+u," +
+			"eq=contrast=1.15:saturation=1.3," +
+			"hue=h='if(lt(mod(t,1.7),0.08),120,0)':s='if(lt(mod(t,1.7),0.08),2,1)'",
+	},
+	"dreamy_glow": {
+		Description: "Soft dreamy glow: a blurred copy screened over the picture, lifted and slightly warm",
+		Filter: "format=gbrp,split[glow0][glow1];[glow1]gblur=sigma=18[glow2];" +
+			"[glow0][glow2]blend=all_mode=screen:all_opacity=0.45,format=yuv420p," +
+			"eq=brightness=0.03:saturation=1.1," +
+			"colorbalance=rh=0.05:bh=-0.03",
+	},
+}
+
+// StylePresetNames returns the style preset names in sorted order
+func StylePresetNames() []string {
+	names := make([]string, 0, len(StylePresets))
+	for name := range StylePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StylePresetOptions contains options for applying a style preset
+type StylePresetOptions struct {
+	Input  string
+	Output string
+	Preset string      // One of StylePresetNames
+	Mask   *EffectMask // Limit the look to part of the frame or ramp it over time
+}
+
+// ApplyStylePreset gives a video one of the curated looks in a single pass
+func (e *Effects) ApplyStylePreset(ctx context.Context, opts StylePresetOptions) error {
+	preset, ok := StylePresets[opts.Preset]
+	if !ok {
+		return fmt.Errorf("unknown style preset %q (use %s)", opts.Preset, strings.Join(StylePresetNames(), ", "))
+	}
+	return e.applyFilter(ctx, opts.Input, opts.Output, preset.Filter, opts.Mask)
+}
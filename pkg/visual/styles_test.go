@@ -0,0 +1,42 @@
+package visual
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStylePresets(t *testing.T) {
+	names := StylePresetNames()
+	for _, want := range []string{"vhs", "film_noir", "teal_orange", "glitch", "dreamy_glow"} {
+		if _, ok := StylePresets[want]; !ok {
+			t.Errorf("missing style preset %q", want)
+		}
+	}
+	for i, name := range names {
+		preset := StylePresets[name]
+		if preset.Description == "" || preset.Filter == "" {
+			t.Errorf("%s: empty description or filter", name)
+		}
+		if i > 0 && names[i-1] > name {
+			t.Errorf("StylePresetNames() not sorted: %v", names)
+		}
+	}
+}
+
+func TestApplyStylePresetUnknown(t *testing.T) {
+	err := NewEffects(nil).ApplyStylePreset(context.Background(), StylePresetOptions{Input: "in.mp4", Output: "out.mp4", Preset: "sepia"})
+	if err == nil || !strings.Contains(err.Error(), "unknown style preset") {
+		t.Errorf("ApplyStylePreset() error = %v, want unknown style preset", err)
+	}
+}
+
+func TestBuildMaskedFilterWithGraphPreset(t *testing.T) {
+	got, err := buildMaskedFilter(StylePresets["dreamy_glow"].Filter, &EffectMask{W: 0.5, H: 0.5})
+	if err != nil {
+		t.Fatalf("buildMaskedFilter() error = %v", err)
+	}
+	if !strings.Contains(got, "[fx]format=gbrp,split[glow0][glow1];") || !strings.Contains(got, "colorbalance=rh=0.05:bh=-0.03[fxd]") {
+		t.Errorf("buildMaskedFilter() = %q", got)
+	}
+}
@@ -0,0 +1,337 @@
+// Package workspace groups a video's files into a project with fixed
+// folders for assets, outputs, temporary files and transcripts, so tools
+// can default their paths instead of asking for absolute ones
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+)
+
+// Project folders
+const (
+	DirAssets      = "assets"      // Source footage, images and music
+	DirOutputs     = "outputs"     // Rendered and exported files
+	DirTemp        = "temp"        // Intermediate files; safe to delete
+	DirTranscripts = "transcripts" // Transcripts and subtitles
+)
+
+// Folders lists the folders created in every project
+var Folders = []string{DirAssets, DirOutputs, DirTemp, DirTranscripts}
+
+// DefaultNaming names outputs after their input and what the tool did
+const DefaultNaming = "{name}_{tool}"
+
+// projectFile holds a project's settings in its root
+const projectFile = ".mcp-video-project.json"
+
+// Project is a directory holding one video's files
+type Project struct {
+	Name        string    `json:"name"`
+	Root        string    `json:"-"`
+	Naming      string    `json:"naming"` // Output name template; see OutputPath
+	Description string    `json:"description,omitempty"`
+	Created     time.Time `json:"created"`
+}
+
+// Dir returns the path of one of the project's folders
+func (p *Project) Dir(folder string) string {
+	return filepath.Join(p.Root, folder)
+}
+
+// OutputPath reserves a path in folder for a file made from input, named
+// by the project's template. The template may use {name} (the input file
+// name without extension), {tool} (what the tool did, e.g. trimmed),
+// {project}, {date} and {time}. See UniquePath for the numbering.
+func (p *Project) OutputPath(folder, input, label, ext string, now time.Time) (string, error) {
+	naming := p.Naming
+	if naming == "" {
+		naming = DefaultNaming
+	}
+	base := strings.NewReplacer(
+		"{name}", BaseName(input),
+		"{tool}", label,
+		"{project}", slug(p.Name),
+		"{date}", now.Format("2006-01-02"),
+		"{time}", now.Format("150405"),
+	).Replace(naming)
+	return UniquePath(p.Dir(folder), base, ext)
+}
+
+// BaseName returns a file name without its directory and extension, or
+// "output" when there is none
+func BaseName(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if path == "" || name == "." || name == string(filepath.Separator) {
+		return "output"
+	}
+	return name
+}
+
+// UniquePath reserves the first free path dir/base_001.ext, _002 and so on,
+// by creating an empty file (or, without ext, a directory) there, so
+// concurrent calls never get the same name and nothing is overwritten.
+// Characters that would leave dir are replaced in base.
+func UniquePath(dir, base, ext string) (string, error) {
+	base = strings.Trim(strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' {
+			return '_'
+		}
+		return r
+	}, base), "_ ")
+	if base == "" {
+		base = "output"
+	}
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	for i := 1; ; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("%s_%03d%s", base, i, ext))
+		var err error
+		if ext == "" {
+			err = os.Mkdir(path, 0755)
+		} else {
+			var f *os.File
+			if f, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644); err == nil {
+				f.Close()
+			}
+		}
+		if err == nil {
+			return path, nil
+		}
+		if !os.IsExist(err) {
+			return "", fmt.Errorf("failed to reserve %s: %w", path, err)
+		}
+	}
+}
+
+// ReservePath reserves path by creating it empty, or, if a file is already
+// there, the first free path_001.ext beside it, so files written next to an
+// output never overwrite existing ones
+func ReservePath(path string) (string, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err == nil {
+		f.Close()
+		return path, nil
+	}
+	if !os.IsExist(err) {
+		return "", fmt.Errorf("failed to reserve %s: %w", path, err)
+	}
+	ext := filepath.Ext(path)
+	return UniquePath(filepath.Dir(path), strings.TrimSuffix(filepath.Base(path), ext), ext)
+}
+
+// CreateOptions contains parameters for a new project
+type CreateOptions struct {
+	Name        string
+	Root        string // Directory to create (default: <projectsDir>/<name>)
+	Naming      string // Output name template (default: {name}_{tool})
+	Description string
+	Activate    bool // Make it the active project
+}
+
+// Manager creates projects and tracks the active one in the configuration
+type Manager struct {
+	config *config.Config
+}
+
+// NewManager creates a new project manager
+func NewManager(cfg *config.Config) *Manager {
+	return &Manager{config: cfg}
+}
+
+// Create makes the project's folders and settings file and registers it by
+// name. An existing project directory is opened rather than overwritten.
+func (m *Manager) Create(opts CreateOptions) (*Project, error) {
+	name := strings.TrimSpace(opts.Name)
+	if name == "" {
+		return nil, fmt.Errorf("project name is required")
+	}
+	if root, ok := m.config.Projects[name]; ok && opts.Root == "" {
+		return nil, fmt.Errorf("project %q already exists at %s", name, root)
+	}
+
+	root := opts.Root
+	if root == "" {
+		parent := m.config.ProjectsDir
+		if parent == "" {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get working directory: %w", err)
+			}
+			parent = cwd
+		}
+		root = filepath.Join(parent, slug(name))
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project directory: %w", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, projectFile)); err == nil {
+		return nil, fmt.Errorf("%s already holds a project; use set_active_project to open it", root)
+	}
+
+	project := &Project{
+		Name:        name,
+		Root:        root,
+		Naming:      opts.Naming,
+		Description: opts.Description,
+		Created:     time.Now().UTC().Truncate(time.Second),
+	}
+	if project.Naming == "" {
+		project.Naming = DefaultNaming
+	}
+	for _, folder := range Folders {
+		if err := os.MkdirAll(project.Dir(folder), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", folder, err)
+		}
+	}
+	data, err := json.MarshalIndent(project, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(root, projectFile), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write project file: %w", err)
+	}
+
+	if m.config.Projects == nil {
+		m.config.Projects = map[string]string{}
+	}
+	m.config.Projects[name] = root
+	if opts.Activate {
+		m.activate(project)
+	}
+	if err := m.config.Save(); err != nil {
+		return nil, fmt.Errorf("failed to save config: %w", err)
+	}
+	return project, nil
+}
+
+// Open reads the project in root
+func Open(root string) (*Project, error) {
+	data, err := os.ReadFile(filepath.Join(root, projectFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no project in %s", root)
+		}
+		return nil, fmt.Errorf("failed to read project: %w", err)
+	}
+	var project Project
+	if err := json.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("invalid project file in %s: %w", root, err)
+	}
+	project.Root = root
+	return &project, nil
+}
+
+// Activate makes the project with the given name, or in the given
+// directory, the active one. Opening a directory registers the project.
+func (m *Manager) Activate(nameOrRoot string) (*Project, error) {
+	root, ok := m.config.Projects[nameOrRoot]
+	if !ok {
+		abs, err := filepath.Abs(nameOrRoot)
+		if err != nil {
+			return nil, fmt.Errorf("invalid project directory: %w", err)
+		}
+		root = abs
+	}
+	project, err := Open(root)
+	if err != nil {
+		if !ok && !strings.ContainsRune(nameOrRoot, filepath.Separator) {
+			return nil, fmt.Errorf("unknown project: %s", nameOrRoot)
+		}
+		return nil, err
+	}
+	if m.config.Projects == nil {
+		m.config.Projects = map[string]string{}
+	}
+	m.config.Projects[project.Name] = root
+	m.activate(project)
+	if err := m.config.Save(); err != nil {
+		return nil, fmt.Errorf("failed to save config: %w", err)
+	}
+	return project, nil
+}
+
+// Deactivate leaves the active project, so paths are used as given again
+func (m *Manager) Deactivate() error {
+	m.config.ActiveProject = ""
+	m.config.ProjectAssetDir = ""
+	m.config.ProjectTempDir = ""
+	return m.config.Save()
+}
+
+// Active returns the active project, or nil when there is none
+func (m *Manager) Active() (*Project, error) {
+	if m.config.ActiveProject == "" {
+		return nil, nil
+	}
+	project, err := Open(m.config.ActiveProject)
+	if err != nil {
+		return nil, err
+	}
+	m.activate(project)
+	return project, nil
+}
+
+// Entry is a registered project as listed by List
+type Entry struct {
+	Name   string
+	Root   string
+	Active bool
+	Err    error // Set when the project can no longer be opened
+}
+
+// List returns the registered projects sorted by name
+func (m *Manager) List() []Entry {
+	entries := make([]Entry, 0, len(m.config.Projects))
+	for name, root := range m.config.Projects {
+		entry := Entry{Name: name, Root: root, Active: root == m.config.ActiveProject}
+		if _, err := Open(root); err != nil {
+			entry.Err = err
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// activate points the configuration at the project's folders
+func (m *Manager) activate(p *Project) {
+	m.config.ActiveProject = p.Root
+	m.config.ProjectAssetDir = p.Dir(DirAssets)
+	m.config.ProjectTempDir = p.Dir(DirTemp)
+}
+
+// slug turns a project name into a directory name
+func slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	s := strings.TrimSuffix(b.String(), "-")
+	if s == "" {
+		return "project"
+	}
+	return s
+}
@@ -0,0 +1,102 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+)
+
+func TestCreateAndActivate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	cfg := &config.Config{ProjectsDir: dir}
+	m := NewManager(cfg)
+
+	project, err := m.Create(CreateOptions{Name: "Launch Video!", Activate: true})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if want := filepath.Join(dir, "launch-video"); project.Root != want {
+		t.Errorf("Root = %q, want %q", project.Root, want)
+	}
+	for _, folder := range Folders {
+		if info, err := os.Stat(project.Dir(folder)); err != nil || !info.IsDir() {
+			t.Errorf("folder %s was not created", folder)
+		}
+	}
+	if cfg.ActiveProject != project.Root || cfg.ProjectAssetDir != project.Dir(DirAssets) || cfg.TempPath() != project.Dir(DirTemp) {
+		t.Errorf("config not pointed at project: %+v", cfg)
+	}
+
+	if _, err := m.Create(CreateOptions{Name: "Launch Video!"}); err == nil {
+		t.Error("expected an error creating a project twice")
+	}
+
+	if err := m.Deactivate(); err != nil {
+		t.Fatalf("Deactivate failed: %v", err)
+	}
+	if cfg.ActiveProject != "" || cfg.AssetPath() != "" {
+		t.Errorf("project still active after Deactivate: %+v", cfg)
+	}
+
+	// A fresh config finds the project by directory and registers it
+	other := NewManager(&config.Config{})
+	opened, err := other.Activate(project.Root)
+	if err != nil {
+		t.Fatalf("Activate by directory failed: %v", err)
+	}
+	if opened.Name != "Launch Video!" || opened.Naming != DefaultNaming {
+		t.Errorf("opened project = %+v", opened)
+	}
+	if entries := other.List(); len(entries) != 1 || !entries[0].Active || entries[0].Err != nil {
+		t.Errorf("List = %+v", entries)
+	}
+	if _, err := other.Activate("missing"); err == nil {
+		t.Error("expected an error for an unknown project")
+	}
+}
+
+func TestOutputPath(t *testing.T) {
+	p := &Project{Name: "Demo Reel", Root: t.TempDir()}
+	now := time.Date(2024, 3, 9, 14, 5, 6, 0, time.UTC)
+
+	first, err := p.OutputPath(DirOutputs, "/footage/interview.mov", "trimmed", ".mp4", now)
+	if err != nil {
+		t.Fatalf("OutputPath failed: %v", err)
+	}
+	if want := filepath.Join(p.Root, DirOutputs, "interview_trimmed_001.mp4"); first != want {
+		t.Errorf("OutputPath = %q, want %q", first, want)
+	}
+	if _, err := os.Stat(first); err != nil {
+		t.Errorf("path was not reserved: %v", err)
+	}
+	if second, _ := p.OutputPath(DirOutputs, "/footage/interview.mov", "trimmed", "mp4", now); filepath.Base(second) != "interview_trimmed_002.mp4" {
+		t.Errorf("reserved file not skipped: %q", second)
+	}
+
+	p.Naming = "{project}/{date}_{time}_{name}"
+	if got, _ := p.OutputPath(DirOutputs, "", "x", ".png", now); filepath.Base(got) != "demo-reel_2024-03-09_140506_output_001.png" {
+		t.Errorf("template output = %q", got)
+	}
+	p.Naming = DefaultNaming
+	if dir, _ := p.OutputPath(DirOutputs, "talk.mp4", "frames", "", now); filepath.Base(dir) != "talk_frames_001" {
+		t.Errorf("directory output = %q", dir)
+	} else if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Error("directory was not reserved")
+	}
+}
+
+func TestReservePath(t *testing.T) {
+	dir := t.TempDir()
+	want := filepath.Join(dir, "talk.captions.srt")
+	if got, err := ReservePath(want); err != nil || got != want {
+		t.Errorf("ReservePath() = %q, %v; want %q", got, err, want)
+	}
+	// The first path is taken now, so the next call must not reuse it
+	if got, err := ReservePath(want); err != nil || got != filepath.Join(dir, "talk.captions_001.srt") {
+		t.Errorf("ReservePath() of a taken path = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,525 @@
+// Package youtube publishes finished videos to YouTube with their title,
+// description, tags, chapters and thumbnail through the Data API v3
+package youtube
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/apiclient"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/chapters"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+)
+
+// Privacy statuses
+const (
+	PrivacyPrivate  = "private"
+	PrivacyUnlisted = "unlisted"
+	PrivacyPublic   = "public"
+)
+
+// PrivacyStatuses lists the accepted privacy statuses
+var PrivacyStatuses = []string{PrivacyPrivate, PrivacyUnlisted, PrivacyPublic}
+
+// Limits YouTube enforces on metadata
+const (
+	maxTitleLength      = 100
+	maxDescriptionBytes = 5000
+	maxTagsLength       = 500
+	maxThumbnailBytes   = 2 << 20
+	minChapters         = 3
+	minChapterLength    = 10.0
+)
+
+const (
+	defaultCategoryID      = "22" // People & Blogs
+	maxUploadAttempts      = 5    // Tries before an interrupted upload fails
+	uploadStatusIncomplete = 308  // Resumable upload status while bytes are missing
+)
+
+// Options contains parameters for an upload
+type Options struct {
+	Input       string
+	Title       string             // Required, at most 100 characters
+	Description string             // Optional
+	Tags        []string           // Optional, 500 characters in total
+	Chapters    []chapters.Chapter // Added to the description as timestamps
+	Thumbnail   string             // JPEG or PNG, at most 2 MB (optional)
+	Privacy     string             // private (default), unlisted or public
+	PublishAt   string             // RFC 3339 time a private video goes public (optional)
+	CategoryID  string             // YouTube category (default: 22, People & Blogs)
+	MadeForKids bool               // Self-declared made for kids
+	Language    string             // Title and audio language, e.g. en (optional)
+}
+
+// Result describes the published video
+type Result struct {
+	VideoID      string   `json:"videoId"`
+	URL          string   `json:"url"`
+	StudioURL    string   `json:"studioUrl"`
+	Title        string   `json:"title"`
+	Privacy      string   `json:"privacy"`
+	PublishAt    string   `json:"publishAt,omitempty"`
+	Description  string   `json:"description"`
+	Tags         []string `json:"tags,omitempty"`
+	Chapters     int      `json:"chapters"`
+	ThumbnailSet bool     `json:"thumbnailSet"`
+	Bytes        int64    `json:"bytes"`
+	Warnings     []string `json:"warnings,omitempty"`
+}
+
+// Uploader uploads videos with OAuth credentials from the config
+type Uploader struct {
+	config       *config.Config
+	client       *http.Client // Uploads, which can't be replayed by the API policy
+	tokenURL     string
+	uploadURL    string
+	thumbnailURL string
+	retryDelay   time.Duration // First wait before resuming, doubled per attempt
+}
+
+// NewUploader creates an uploader. Credentials are read from cfg on each
+// call so set_config changes apply immediately.
+func NewUploader(cfg *config.Config) *Uploader {
+	return &Uploader{
+		config:       cfg,
+		client:       &http.Client{},
+		tokenURL:     "https://oauth2.googleapis.com/token",
+		uploadURL:    "https://www.googleapis.com/upload/youtube/v3/videos",
+		thumbnailURL: "https://www.googleapis.com/upload/youtube/v3/thumbnails/set",
+		retryDelay:   time.Second,
+	}
+}
+
+// Upload validates the metadata, uploads the video with a resumable upload
+// and sets the thumbnail. A thumbnail YouTube refuses, such as on accounts
+// without custom thumbnails enabled, is a warning rather than a failure.
+func (u *Uploader) Upload(ctx context.Context, opts Options) (*Result, error) {
+	metadata, result, err := buildMetadata(opts)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(opts.Input)
+	if err != nil {
+		return nil, fmt.Errorf("input not found: %w", err)
+	}
+	if opts.Thumbnail != "" {
+		thumb, err := os.Stat(opts.Thumbnail)
+		if err != nil {
+			return nil, fmt.Errorf("thumbnail not found: %w", err)
+		}
+		if thumb.Size() > maxThumbnailBytes {
+			return nil, fmt.Errorf("thumbnail is %.1f MB; YouTube allows 2 MB", float64(thumb.Size())/(1<<20))
+		}
+	}
+
+	token, err := u.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := u.startUpload(ctx, token, metadata, info.Size(), contentType(opts.Input, "video/*"))
+	if err != nil {
+		return nil, err
+	}
+	videoID, err := u.sendFile(ctx, token, session, opts.Input, info.Size())
+	if err != nil {
+		return nil, err
+	}
+	result.VideoID = videoID
+	result.URL = "https://www.youtube.com/watch?v=" + videoID
+	result.StudioURL = "https://studio.youtube.com/video/" + videoID + "/edit"
+	result.Bytes = info.Size()
+
+	if opts.Thumbnail != "" {
+		if err := u.setThumbnail(ctx, token, videoID, opts.Thumbnail); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("thumbnail not set: %v", err))
+		} else {
+			result.ThumbnailSet = true
+		}
+	}
+	return result, nil
+}
+
+// videoResource is the snippet and status sent with an upload
+type videoResource struct {
+	Snippet struct {
+		Title                string   `json:"title"`
+		Description          string   `json:"description"`
+		Tags                 []string `json:"tags,omitempty"`
+		CategoryID           string   `json:"categoryId"`
+		DefaultLanguage      string   `json:"defaultLanguage,omitempty"`
+		DefaultAudioLanguage string   `json:"defaultAudioLanguage,omitempty"`
+	} `json:"snippet"`
+	Status struct {
+		PrivacyStatus           string `json:"privacyStatus"`
+		PublishAt               string `json:"publishAt,omitempty"`
+		SelfDeclaredMadeForKids bool   `json:"selfDeclaredMadeForKids"`
+	} `json:"status"`
+}
+
+// buildMetadata checks the options against YouTube's limits and returns the
+// video resource to upload. Problems YouTube would reject are errors; tags
+// past the limit are dropped and chapters YouTube won't show are warnings.
+func buildMetadata(opts Options) (*videoResource, *Result, error) {
+	result := &Result{}
+	var v videoResource
+
+	title := strings.TrimSpace(opts.Title)
+	if title == "" {
+		return nil, nil, fmt.Errorf("title is required")
+	}
+	if n := len([]rune(title)); n > maxTitleLength {
+		return nil, nil, fmt.Errorf("title is %d characters; YouTube allows %d", n, maxTitleLength)
+	}
+	if strings.ContainsAny(title, "<>") {
+		return nil, nil, fmt.Errorf("title cannot contain < or >")
+	}
+
+	description := strings.TrimSpace(opts.Description)
+	if len(opts.Chapters) > 0 {
+		result.Chapters = len(opts.Chapters)
+		result.Warnings = append(result.Warnings, checkChapters(opts.Chapters)...)
+		last := opts.Chapters[len(opts.Chapters)-1].Start
+		timestamps := strings.TrimSpace(chapters.FormatYouTube(opts.Chapters, last))
+		if description != "" {
+			description += "\n\n"
+		}
+		description += "Chapters:\n" + timestamps
+	}
+	if strings.ContainsAny(description, "<>") {
+		return nil, nil, fmt.Errorf("description cannot contain < or >")
+	}
+	if len(description) > maxDescriptionBytes {
+		return nil, nil, fmt.Errorf("description is %d bytes with chapters; YouTube allows %d", len(description), maxDescriptionBytes)
+	}
+
+	// Tags with spaces count with their quotes, and commas between tags count
+	length := 0
+	for _, tag := range opts.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		size := len([]rune(tag))
+		if strings.Contains(tag, " ") {
+			size += 2
+		}
+		if len(v.Snippet.Tags) > 0 {
+			size++
+		}
+		if length+size > maxTagsLength {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("dropped tags from %q on; tags are limited to %d characters", tag, maxTagsLength))
+			break
+		}
+		length += size
+		v.Snippet.Tags = append(v.Snippet.Tags, tag)
+	}
+
+	privacy := opts.Privacy
+	if privacy == "" {
+		privacy = PrivacyPrivate
+	}
+	switch privacy {
+	case PrivacyPrivate, PrivacyUnlisted, PrivacyPublic:
+	default:
+		return nil, nil, fmt.Errorf("unknown privacy: %s (use private, unlisted or public)", privacy)
+	}
+	if opts.PublishAt != "" {
+		at, err := time.Parse(time.RFC3339, opts.PublishAt)
+		if err != nil {
+			return nil, nil, fmt.Errorf("publishAt must be an RFC 3339 time such as 2025-06-01T15:00:00Z: %w", err)
+		}
+		if !at.After(time.Now()) {
+			return nil, nil, fmt.Errorf("publishAt must be in the future")
+		}
+		// Scheduled videos stay private until they go public
+		if opts.Privacy != "" && opts.Privacy != PrivacyPrivate {
+			return nil, nil, fmt.Errorf("scheduled videos must be private until publishAt")
+		}
+		v.Status.PublishAt = at.UTC().Format(time.RFC3339)
+	}
+
+	v.Snippet.Title = title
+	v.Snippet.Description = description
+	v.Snippet.CategoryID = opts.CategoryID
+	if v.Snippet.CategoryID == "" {
+		v.Snippet.CategoryID = defaultCategoryID
+	}
+	v.Snippet.DefaultLanguage = opts.Language
+	v.Snippet.DefaultAudioLanguage = opts.Language
+	v.Status.PrivacyStatus = privacy
+	v.Status.SelfDeclaredMadeForKids = opts.MadeForKids
+
+	result.Title = title
+	result.Description = description
+	result.Tags = v.Snippet.Tags
+	result.Privacy = privacy
+	result.PublishAt = v.Status.PublishAt
+	return &v, result, nil
+}
+
+// checkChapters returns the reasons YouTube won't show chapters: the first
+// must start at 0:00 and there must be at least three of 10 seconds or more
+func checkChapters(list []chapters.Chapter) []string {
+	var warnings []string
+	if list[0].Start != 0 {
+		warnings = append(warnings, "YouTube only shows chapters when the first starts at 0:00")
+	}
+	if len(list) < minChapters {
+		warnings = append(warnings, fmt.Sprintf("YouTube needs at least %d chapters to show them (got %d)", minChapters, len(list)))
+	}
+	for i := 1; i < len(list); i++ {
+		if list[i].Start-list[i-1].Start < minChapterLength {
+			warnings = append(warnings, fmt.Sprintf("chapter %q is shorter than %.0f seconds; YouTube may not show chapters", list[i-1].Title, minChapterLength))
+			break
+		}
+	}
+	return warnings
+}
+
+// accessToken exchanges the refresh token for an access token
+func (u *Uploader) accessToken(ctx context.Context) (string, error) {
+	cfg := u.config
+	if cfg.YouTubeClientID == "" || cfg.YouTubeClientSecret == "" || cfg.YouTubeRefreshToken == "" {
+		return "", fmt.Errorf("YouTube not configured. Set youtubeClientId, youtubeClientSecret and youtubeRefreshToken (or YOUTUBE_CLIENT_ID, YOUTUBE_CLIENT_SECRET and YOUTUBE_REFRESH_TOKEN)")
+	}
+	form := url.Values{
+		"client_id":     {cfg.YouTubeClientID},
+		"client_secret": {cfg.YouTubeClientSecret},
+		"refresh_token": {cfg.YouTubeRefreshToken},
+		"grant_type":    {"refresh_token"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := apiclient.HTTPClient(apiclient.ProviderYouTube).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh YouTube token: %w", err)
+	}
+	defer resp.Body.Close()
+	var token struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to refresh YouTube token: HTTP %d", resp.StatusCode)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("failed to refresh YouTube token: %s %s", token.Error, token.ErrorDescription)
+	}
+	return token.AccessToken, nil
+}
+
+// startUpload creates a resumable upload session and returns its URL
+func (u *Uploader) startUpload(ctx context.Context, token string, metadata *videoResource, size int64, mediaType string) (string, error) {
+	body, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.uploadURL+"?uploadType=resumable&part=snippet,status", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("X-Upload-Content-Length", strconv.FormatInt(size, 10))
+	req.Header.Set("X-Upload-Content-Type", mediaType)
+
+	resp, err := apiclient.HTTPClient(apiclient.ProviderYouTube).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to start upload: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to start upload: %s", apiError(resp))
+	}
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return "", fmt.Errorf("failed to start upload: no upload URL returned")
+	}
+	return session, nil
+}
+
+// sendFile uploads the video to the session. After a network error or
+// server error it asks how much arrived and resumes from there.
+func (u *Uploader) sendFile(ctx context.Context, token, session, path string, size int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var offset int64
+	var lastErr error
+	for attempt := 0; attempt < maxUploadAttempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, u.retryDelay<<(attempt-1)); err != nil {
+				return "", err
+			}
+			id, next, err := u.uploadStatus(ctx, token, session, size)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if id != "" {
+				return id, nil
+			}
+			offset = next
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, session, io.NewSectionReader(file, offset, size-offset))
+		if err != nil {
+			return "", err
+		}
+		req.ContentLength = size - offset
+		req.Header.Set("Authorization", "Bearer "+token)
+		if size > 0 {
+			req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, size-1, size))
+		}
+
+		resp, err := u.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		id, done, err := uploadResponse(resp)
+		if done {
+			return id, err
+		}
+		lastErr = err
+	}
+	return "", fmt.Errorf("upload failed after %d attempts: %w", maxUploadAttempts, lastErr)
+}
+
+// uploadStatus asks how much of the upload arrived. It returns the video
+// ID when the upload turned out to be complete, or the offset to resume at.
+func (u *Uploader) uploadStatus(ctx context.Context, token, session string, size int64) (string, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, session, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	if resp.StatusCode == uploadStatusIncomplete {
+		resp.Body.Close()
+		// Range is "bytes=0-N" for the bytes received, absent for none
+		var end int64 = -1
+		if r := resp.Header.Get("Range"); r != "" {
+			if i := strings.LastIndex(r, "-"); i >= 0 {
+				if n, err := strconv.ParseInt(r[i+1:], 10, 64); err == nil {
+					end = n
+				}
+			}
+		}
+		return "", end + 1, nil
+	}
+	id, done, err := uploadResponse(resp)
+	if done && err == nil {
+		return id, 0, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("unexpected upload status HTTP %d", resp.StatusCode)
+	}
+	return "", 0, err
+}
+
+// uploadResponse reads the response to an upload request. done is set when
+// the upload finished or failed for good; otherwise it may be resumed.
+func uploadResponse(resp *http.Response) (id string, done bool, err error) {
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated:
+		var video struct {
+			ID string `json:"id"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&video); err != nil || video.ID == "" {
+			return "", true, fmt.Errorf("upload finished without a video ID")
+		}
+		return video.ID, true, nil
+	case resp.StatusCode == uploadStatusIncomplete || resp.StatusCode >= 500:
+		return "", false, fmt.Errorf("HTTP %d", resp.StatusCode)
+	default:
+		return "", true, fmt.Errorf("upload rejected: %s", apiError(resp))
+	}
+}
+
+// setThumbnail uploads a custom thumbnail for the video
+func (u *Uploader) setThumbnail(ctx context.Context, token, videoID, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.thumbnailURL+"?videoId="+url.QueryEscape(videoID), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", contentType(path, "image/jpeg"))
+
+	resp, err := apiclient.HTTPClient(apiclient.ProviderYouTube).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s", apiError(resp))
+	}
+	return nil
+}
+
+// apiError returns the message from a Google API error response
+func apiError(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var parsed struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &parsed) == nil && parsed.Error.Message != "" {
+		return fmt.Sprintf("HTTP %d: %s", resp.StatusCode, parsed.Error.Message)
+	}
+	if msg := strings.TrimSpace(string(body)); msg != "" && len(msg) <= 300 {
+		return fmt.Sprintf("HTTP %d: %s", resp.StatusCode, msg)
+	}
+	return fmt.Sprintf("HTTP %d", resp.StatusCode)
+}
+
+// contentType guesses a MIME type from the file extension
+func contentType(path, fallback string) string {
+	if t := mime.TypeByExtension(strings.ToLower(filepath.Ext(path))); t != "" {
+		return t
+	}
+	return fallback
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
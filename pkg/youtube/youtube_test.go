@@ -0,0 +1,155 @@
+package youtube
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chandler-mayo/mcp-video-editor/pkg/chapters"
+	"github.com/chandler-mayo/mcp-video-editor/pkg/config"
+)
+
+func TestBuildMetadata(t *testing.T) {
+	v, result, err := buildMetadata(Options{
+		Title:       "Launch day",
+		Description: "Everything we shipped.",
+		Tags:        []string{"launch", "product demo", " "},
+		Chapters: []chapters.Chapter{
+			{Start: 0, Title: "Intro"},
+			{Start: 45, Title: "Demo"},
+			{Start: 125, Title: "Q&A"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("buildMetadata failed: %v", err)
+	}
+	want := "Everything we shipped.\n\nChapters:\n0:00 Intro\n0:45 Demo\n2:05 Q&A"
+	if v.Snippet.Description != want {
+		t.Errorf("Description =\n%s\nwant\n%s", v.Snippet.Description, want)
+	}
+	if v.Status.PrivacyStatus != PrivacyPrivate || v.Snippet.CategoryID != "22" {
+		t.Errorf("Unexpected defaults: %+v", v.Status)
+	}
+	if len(v.Snippet.Tags) != 2 || len(result.Warnings) != 0 {
+		t.Errorf("Unexpected tags %v or warnings %v", v.Snippet.Tags, result.Warnings)
+	}
+}
+
+func TestBuildMetadataLimits(t *testing.T) {
+	tests := []Options{
+		{Title: ""},
+		{Title: strings.Repeat("a", 101)},
+		{Title: "<script>"},
+		{Title: "ok", Description: strings.Repeat("a", 5001)},
+		{Title: "ok", Privacy: "friends"},
+		{Title: "ok", PublishAt: "tomorrow"},
+		{Title: "ok", PublishAt: time.Now().Add(time.Hour).Format(time.RFC3339), Privacy: PrivacyPublic},
+	}
+	for _, opts := range tests {
+		if _, _, err := buildMetadata(opts); err == nil {
+			t.Errorf("Expected error for %+v", opts)
+		}
+	}
+
+	// Tags past 500 characters are dropped with a warning
+	tags := []string{strings.Repeat("a", 300), strings.Repeat("b", 198), "c"}
+	v, result, err := buildMetadata(Options{Title: "ok", Tags: tags})
+	if err != nil || len(v.Snippet.Tags) != 2 || len(result.Warnings) != 1 {
+		t.Errorf("Expected 2 tags and a warning, got %d and %v (%v)", len(v.Snippet.Tags), result.Warnings, err)
+	}
+}
+
+func TestCheckChapters(t *testing.T) {
+	if w := checkChapters([]chapters.Chapter{{Start: 0}, {Start: 30}, {Start: 60}}); len(w) != 0 {
+		t.Errorf("Unexpected warnings: %v", w)
+	}
+	if w := checkChapters([]chapters.Chapter{{Start: 5}, {Start: 8}}); len(w) != 3 {
+		t.Errorf("Expected start, count and length warnings, got %v", w)
+	}
+}
+
+func TestUploadResumes(t *testing.T) {
+	video := filepath.Join(t.TempDir(), "final.mp4")
+	os.WriteFile(video, []byte("0123456789"), 0644)
+	thumb := filepath.Join(t.TempDir(), "thumb.png")
+	os.WriteFile(thumb, []byte("png"), 0644)
+
+	var server *httptest.Server
+	var received []byte
+	puts := 0
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			if r.FormValue("refresh_token") != "refresh" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"access_token": "access"}`))
+		case r.URL.Path == "/videos" && r.Method == http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("Authorization") != "Bearer access" || !strings.Contains(string(body), `"title":"Launch"`) || r.Header.Get("X-Upload-Content-Length") != "10" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Location", server.URL+"/session")
+		case r.URL.Path == "/session" && r.Header.Get("Content-Range") == "bytes */10":
+			w.Header().Set("Range", "bytes=0-3")
+			w.WriteHeader(uploadStatusIncomplete)
+		case r.URL.Path == "/session":
+			puts++
+			body, _ := io.ReadAll(r.Body)
+			if puts == 1 {
+				// Only the first 4 bytes arrive
+				received = append(received, body[:4]...)
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if r.Header.Get("Content-Range") != "bytes 4-9/10" {
+				t.Errorf("Unexpected resume range: %s", r.Header.Get("Content-Range"))
+			}
+			received = append(received, body...)
+			w.Write([]byte(`{"id": "abc123"}`))
+		case r.URL.Path == "/thumbnails":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": {"message": "The authenticated user doesn't have permissions to upload and set custom video thumbnails."}}`))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	u := NewUploader(&config.Config{YouTubeClientID: "id", YouTubeClientSecret: "secret", YouTubeRefreshToken: "refresh"})
+	u.tokenURL = server.URL + "/token"
+	u.uploadURL = server.URL + "/videos"
+	u.thumbnailURL = server.URL + "/thumbnails"
+	u.retryDelay = time.Millisecond
+
+	result, err := u.Upload(context.Background(), Options{Input: video, Title: "Launch", Thumbnail: thumb})
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if result.VideoID != "abc123" || result.URL != "https://www.youtube.com/watch?v=abc123" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if string(received) != "0123456789" {
+		t.Errorf("Server received %q", received)
+	}
+	if result.ThumbnailSet || len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "custom video thumbnails") {
+		t.Errorf("Expected a thumbnail warning, got %v", result.Warnings)
+	}
+}
+
+func TestUploadRequiresCredentials(t *testing.T) {
+	video := filepath.Join(t.TempDir(), "final.mp4")
+	os.WriteFile(video, []byte("0"), 0644)
+	_, err := NewUploader(&config.Config{}).Upload(context.Background(), Options{Input: video, Title: "Launch"})
+	if err == nil || !strings.Contains(err.Error(), "youtubeRefreshToken") {
+		t.Errorf("Expected a configuration error, got %v", err)
+	}
+}